@@ -0,0 +1,256 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActionTemplateSpec defines a reusable healing action and the parameters
+// policies referencing it may set
+type ActionTemplateSpec struct {
+	// Type of action
+	// +kubebuilder:validation:Enum=restart;scale;patch;delete;finalizer;hibernate;custom
+	Type string `json:"type"`
+
+	// Description for logging/auditing
+	Description string `json:"description,omitempty"`
+
+	// RestartAction parameters used when the policy does not set them
+	RestartAction *RestartAction `json:"restartAction,omitempty"`
+
+	// ScaleAction parameters used when the policy does not set them
+	ScaleAction *ScaleAction `json:"scaleAction,omitempty"`
+
+	// PatchAction parameters used when the policy does not set them
+	PatchAction *PatchAction `json:"patchAction,omitempty"`
+
+	// DeleteAction parameters used when the policy does not set them
+	DeleteAction *DeleteAction `json:"deleteAction,omitempty"`
+
+	// FinalizerAction parameters used when the policy does not set them
+	FinalizerAction *FinalizerAction `json:"finalizerAction,omitempty"`
+
+	// HibernateAction parameters used when the policy does not set them
+	HibernateAction *HibernateAction `json:"hibernateAction,omitempty"`
+
+	// Constraints on the parameters of referencing policies
+	Constraints ActionConstraints `json:"constraints,omitempty"`
+}
+
+// ActionConstraints bound the parameters a policy may use with a template
+type ActionConstraints struct {
+	// Scale bounds the replica counts of scale actions
+	Scale *ScaleBounds `json:"scale,omitempty"`
+
+	// Patch limits the fields patch actions may modify
+	Patch *PatchConstraints `json:"patch,omitempty"`
+
+	// RequireApproval forces approval for actions using the template
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// ScaleBounds limits the replica counts a scale action may produce
+// +kubebuilder:validation:XValidation:rule="self.minReplicas <= self.maxReplicas",message="minReplicas must not exceed maxReplicas"
+type ScaleBounds struct {
+	// MinReplicas a scale action may scale to
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas int32 `json:"minReplicas"`
+
+	// MaxReplicas a scale action may scale to
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+}
+
+// PatchConstraints limits the fields patch actions may modify
+type PatchConstraints struct {
+	// AllowedPaths are dot-separated field paths such as
+	// "spec.template.spec.containers"; a path allows every field below it
+	// +kubebuilder:validation:MinItems=1
+	AllowedPaths []string `json:"allowedPaths"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=at
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ActionTemplate is the Schema for the actiontemplates API. Templates are
+// cluster-scoped so platform teams can constrain the actions of policies in
+// application namespaces.
+type ActionTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ActionTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ActionTemplateList contains a list of ActionTemplate
+type ActionTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActionTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActionTemplate{}, &ActionTemplateList{})
+}
+
+// Apply merges a policy action with the template. Parameters set on the
+// policy action take precedence over the template's; the result is checked
+// against the template constraints.
+func (t *ActionTemplate) Apply(action *HealingActionTemplate) (*HealingActionTemplate, error) {
+	if action.Type != "" && action.Type != t.Spec.Type {
+		return nil, fmt.Errorf("action %s has type %s but template %s has type %s",
+			action.Name, action.Type, t.Name, t.Spec.Type)
+	}
+
+	merged := action.DeepCopy()
+	merged.Type = t.Spec.Type
+	if merged.Description == "" {
+		merged.Description = t.Spec.Description
+	}
+	if merged.RestartAction == nil && t.Spec.RestartAction != nil {
+		merged.RestartAction = t.Spec.RestartAction.DeepCopy()
+	}
+	if merged.ScaleAction == nil && t.Spec.ScaleAction != nil {
+		merged.ScaleAction = t.Spec.ScaleAction.DeepCopy()
+	}
+	if merged.PatchAction == nil && t.Spec.PatchAction != nil {
+		merged.PatchAction = t.Spec.PatchAction.DeepCopy()
+	}
+	if merged.DeleteAction == nil && t.Spec.DeleteAction != nil {
+		merged.DeleteAction = t.Spec.DeleteAction.DeepCopy()
+	}
+	if merged.FinalizerAction == nil && t.Spec.FinalizerAction != nil {
+		merged.FinalizerAction = t.Spec.FinalizerAction.DeepCopy()
+	}
+	if merged.HibernateAction == nil && t.Spec.HibernateAction != nil {
+		merged.HibernateAction = t.Spec.HibernateAction.DeepCopy()
+	}
+	if t.Spec.Constraints.RequireApproval {
+		merged.RequiresApproval = true
+	}
+
+	if bounds := t.Spec.Constraints.Scale; bounds != nil && merged.ScaleAction != nil {
+		// Bounds tighten the action's own limits so computed replica counts
+		// stay within them
+		scale := merged.ScaleAction
+		if scale.MinReplicas < bounds.MinReplicas {
+			scale.MinReplicas = bounds.MinReplicas
+		}
+		if scale.MaxReplicas == 0 || scale.MaxReplicas > bounds.MaxReplicas {
+			scale.MaxReplicas = bounds.MaxReplicas
+		}
+	}
+
+	if err := t.Validate(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// Validate checks an action's parameters against the template constraints.
+// Patch content that still contains template expressions is not checked.
+func (t *ActionTemplate) Validate(action *HealingActionTemplate) error {
+	if scale := action.ScaleAction; scale != nil {
+		if scale.MaxReplicas > 0 && scale.MinReplicas > scale.MaxReplicas {
+			return fmt.Errorf("action %s: minReplicas %d exceeds maxReplicas %d",
+				action.Name, scale.MinReplicas, scale.MaxReplicas)
+		}
+		if bounds := t.Spec.Constraints.Scale; bounds != nil {
+			if scale.MinReplicas < bounds.MinReplicas || scale.MaxReplicas > bounds.MaxReplicas {
+				return fmt.Errorf("action %s: replicas %d-%d outside template %s bounds %d-%d",
+					action.Name, scale.MinReplicas, scale.MaxReplicas, t.Name, bounds.MinReplicas, bounds.MaxReplicas)
+			}
+			if scale.Direction == "absolute" && scale.ReplicasTemplate == "" &&
+				(scale.Replicas < bounds.MinReplicas || scale.Replicas > bounds.MaxReplicas) {
+				return fmt.Errorf("action %s: %d replicas outside template %s bounds %d-%d",
+					action.Name, scale.Replicas, t.Name, bounds.MinReplicas, bounds.MaxReplicas)
+			}
+		}
+	}
+
+	if constraints := t.Spec.Constraints.Patch; constraints != nil && action.PatchAction != nil {
+		paths, err := patchPaths(action.PatchAction)
+		if err != nil {
+			return fmt.Errorf("action %s: %w", action.Name, err)
+		}
+		for _, path := range paths {
+			if !pathAllowed(path, constraints.AllowedPaths) {
+				return fmt.Errorf("action %s: patch of %s is not allowed by template %s",
+					action.Name, path, t.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// patchPaths lists the dot-separated field paths modified by a patch action
+func patchPaths(patch *PatchAction) ([]string, error) {
+	var paths []string
+	for _, op := range patch.Patches {
+		paths = append(paths, strings.Join(op.Path, "."))
+	}
+
+	if patch.Patch == "" || strings.Contains(patch.Patch, "{{") {
+		return paths, nil
+	}
+
+	if patch.Type == "json" {
+		var ops []struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal([]byte(patch.Patch), &ops); err != nil {
+			return nil, fmt.Errorf("invalid JSON patch: %w", err)
+		}
+		for _, op := range ops {
+			segments := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+			for i, segment := range segments {
+				segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+			}
+			paths = append(paths, strings.Join(segments, "."))
+		}
+		return paths, nil
+	}
+
+	var content map[string]interface{}
+	if err := json.Unmarshal([]byte(patch.Patch), &content); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	return appendLeafPaths(paths, "", content), nil
+}
+
+// appendLeafPaths appends the paths of the leaf fields of a patch document
+func appendLeafPaths(paths []string, prefix string, content map[string]interface{}) []string {
+	if len(content) == 0 && prefix != "" {
+		return append(paths, prefix)
+	}
+	for key, value := range content {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			paths = appendLeafPaths(paths, path, nested)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// pathAllowed reports whether path equals or is below an allowed path
+func pathAllowed(path string, allowed []string) bool {
+	for _, prefix := range allowed {
+		if path == prefix || strings.HasPrefix(path, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
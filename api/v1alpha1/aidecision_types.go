@@ -0,0 +1,123 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AIDecisionSpec records an AI recommendation and the HealingAction it
+// approved
+type AIDecisionSpec struct {
+	// HealingActionRef is the name of the HealingAction created from the
+	// decision, in the decision's namespace
+	HealingActionRef string `json:"healingActionRef"`
+
+	// PolicyRef references the HealingPolicy that requested the analysis
+	PolicyRef PolicyReference `json:"policyRef"`
+
+	// TargetResource the recommended action applies to
+	TargetResource TargetResource `json:"targetResource"`
+
+	// ActionType recommended by the AI
+	ActionType string `json:"actionType"`
+
+	// Model that produced the recommendation
+	Model string `json:"model,omitempty"`
+
+	// PromptHash identifies the prompt the model was given
+	PromptHash string `json:"promptHash,omitempty"`
+
+	// Confidence of the recommendation, between 0 and 1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	Confidence float64 `json:"confidence"`
+
+	// Risk level the AI assigned to the action
+	Risk string `json:"risk,omitempty"`
+
+	// Reason given for the recommendation
+	Reason string `json:"reason,omitempty"`
+
+	// ReasoningSteps taken to reach the decision
+	ReasoningSteps []string `json:"reasoningSteps,omitempty"`
+
+	// Alternatives the AI considered
+	Alternatives []DecisionAlternative `json:"alternatives,omitempty"`
+
+	// RiskAssessment summarizes the decision logic
+	RiskAssessment string `json:"riskAssessment,omitempty"`
+
+	// ExpectedOutcome of the action
+	ExpectedOutcome string `json:"expectedOutcome,omitempty"`
+
+	// TTL is how long the decision is kept once its outcome is known
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	TTL metav1.Duration `json:"ttl,omitempty"`
+}
+
+// DecisionAlternative is an action the AI considered
+type DecisionAlternative struct {
+	// Action considered
+	Action string `json:"action"`
+
+	// Rejected is true if the AI decided against the action
+	Rejected bool `json:"rejected,omitempty"`
+
+	// Reason the action was chosen or rejected
+	Reason string `json:"reason,omitempty"`
+}
+
+// AIDecisionStatus defines the observed state of AIDecision
+type AIDecisionStatus struct {
+	// Phase of the decision's HealingAction
+	// +kubebuilder:validation:Enum=Pending;Completed;Failed;Cancelled
+	Phase string `json:"phase,omitempty"`
+
+	// Success is set once the HealingAction finished
+	Success *bool `json:"success,omitempty"`
+
+	// ActualOutcome reported by the HealingAction
+	ActualOutcome string `json:"actualOutcome,omitempty"`
+
+	// CompletionTime of the HealingAction
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aid
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.actionType"
+// +kubebuilder:printcolumn:name="Confidence",type="number",JSONPath=".spec.confidence"
+// +kubebuilder:printcolumn:name="HealingAction",type="string",JSONPath=".spec.healingActionRef"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Success",type="boolean",JSONPath=".status.success"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AIDecision is the Schema for the aidecisions API
+type AIDecision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AIDecisionSpec   `json:"spec,omitempty"`
+	Status AIDecisionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AIDecisionList contains a list of AIDecision
+type AIDecisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AIDecision `json:"items"`
+}
+
+// AIDecision phase constants
+const (
+	AIDecisionPhasePending   = "Pending"
+	AIDecisionPhaseCompleted = "Completed"
+	AIDecisionPhaseFailed    = "Failed"
+	AIDecisionPhaseCancelled = "Cancelled"
+)
+
+func init() {
+	SchemeBuilder.Register(&AIDecision{}, &AIDecisionList{})
+}
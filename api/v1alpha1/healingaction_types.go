@@ -24,10 +24,20 @@ type HealingActionSpec struct {
 
 	// Timeout for the action
 	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	Timeout metav1.Duration `json:"timeout,omitempty"`
 
+	// AttemptTimeout bounds each execution attempt. The executor is
+	// cancelled once the attempt or the action's Timeout runs out; zero
+	// uses the manager's default.
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	AttemptTimeout metav1.Duration `json:"attemptTimeout,omitempty"`
+
 	// RetryPolicy for failed actions
 	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// BlastRadiusLimits copied from the policy safety rules
+	BlastRadiusLimits *BlastRadiusLimits `json:"blastRadiusLimits,omitempty"`
 }
 
 // PolicyReference links to the source HealingPolicy
@@ -60,25 +70,40 @@ type TargetResource struct {
 	UID string `json:"uid,omitempty"`
 }
 
+// ActionReference identifies another HealingAction
+type ActionReference struct {
+	// Name of the HealingAction
+	Name string `json:"name"`
+
+	// Namespace of the HealingAction
+	Namespace string `json:"namespace"`
+
+	// Priority of the referenced action
+	Priority int32 `json:"priority,omitempty"`
+}
+
 // RetryPolicy defines retry behavior
 type RetryPolicy struct {
 	// MaxAttempts before giving up
 	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
 	MaxAttempts int32 `json:"maxAttempts,omitempty"`
 
 	// BackoffDelay between attempts
 	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	BackoffDelay metav1.Duration `json:"backoffDelay,omitempty"`
 
 	// BackoffMultiplier for exponential backoff
 	// +kubebuilder:default=2.0
+	// +kubebuilder:validation:Minimum=1
 	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty"`
 }
 
 // HealingActionStatus defines the observed state of HealingAction
 type HealingActionStatus struct {
 	// Phase of the action
-	// +kubebuilder:validation:Enum=Pending;Approved;InProgress;Succeeded;Failed;Cancelled
+	// +kubebuilder:validation:Enum=Pending;Approved;InProgress;Verifying;Succeeded;Failed;Cancelled
 	Phase string `json:"phase,omitempty"`
 
 	// StartTime when execution began
@@ -96,9 +121,41 @@ type HealingActionStatus struct {
 	// Result of the action
 	Result *ActionResult `json:"result,omitempty"`
 
+	// Verification tracks the success criteria of an executed action
+	Verification *VerificationStatus `json:"verification,omitempty"`
+
+	// PriorityDecision explains the priority of an action blended from
+	// its rule priority and AI confidence
+	PriorityDecision *PriorityDecision `json:"priorityDecision,omitempty"`
+
+	// Hibernation tracks a hibernated workload until it is restored
+	Hibernation *HibernationStatus `json:"hibernation,omitempty"`
+
+	// NodeTaint tracks a node tainted by a taint action until it is
+	// untainted or escalated
+	NodeTaint *NodeTaintStatus `json:"nodeTaint,omitempty"`
+
+	// DryRunObservation tracks what happened to the target of a dry-run
+	// action without the action, for comparing dry runs against healing
+	DryRunObservation *DryRunObservation `json:"dryRunObservation,omitempty"`
+
+	// Severity of the trigger that caused the action
+	Severity string `json:"severity,omitempty"`
+
 	// Approval information
 	Approval *ApprovalStatus `json:"approval,omitempty"`
 
+	// BlastRadius is the simulated downstream impact of the action
+	BlastRadius *BlastRadiusReport `json:"blastRadius,omitempty"`
+
+	// TargetGeneration is the target's metadata.generation after the action
+	// completed, used to detect later out-of-band changes
+	TargetGeneration int64 `json:"targetGeneration,omitempty"`
+
+	// PreemptedBy references the higher-priority action that cancelled this
+	// action before it completed
+	PreemptedBy *ActionReference `json:"preemptedBy,omitempty"`
+
 	// Conditions of the action
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
@@ -117,13 +174,244 @@ type ActionResult struct {
 	// Error if the action failed
 	Error string `json:"error,omitempty"`
 
+	// FailureReason classifies the error of a failed action
+	// +kubebuilder:validation:Enum=RBACDenied;Timeout;TargetNotFound;Conflict;ExecutorError;ValidationFailed;CapacityBlocked;BlastRadiusBlocked;VerificationFailed
+	FailureReason string `json:"failureReason,omitempty"`
+
 	// Metrics captured during execution
 	Metrics map[string]string `json:"metrics,omitempty"`
 
 	// Changes made to the target resource
 	Changes []ResourceChange `json:"changes,omitempty"`
+
+	// Partial is set when the attempt was cancelled after it changed the
+	// target; Changes lists what was done before it stopped
+	Partial bool `json:"partial,omitempty"`
+
+	// Diagnostics captured by pre-action hooks
+	Diagnostics []DiagnosticCapture `json:"diagnostics,omitempty"`
+
+	// Steps are the results of the steps of chain actions
+	Steps []StepResult `json:"steps,omitempty"`
+}
+
+// Step phases of chain actions
+const (
+	StepPhaseSucceeded       = "Succeeded"
+	StepPhaseFailed          = "Failed"
+	StepPhaseSkipped         = "Skipped"
+	StepPhaseRolledBack      = "RolledBack"
+	StepPhaseRollbackFailed  = "RollbackFailed"
+	StepPhaseRollbackSkipped = "RollbackSkipped"
+)
+
+// StepResult is the outcome of one step of a chain action
+type StepResult struct {
+	// Name of the step
+	Name string `json:"name"`
+
+	// Type of the step
+	Type string `json:"type"`
+
+	// Phase of the step; completed steps are RolledBack when a later step
+	// failed, or RollbackSkipped if the fields they changed were changed
+	// again by someone else
+	// +kubebuilder:validation:Enum=Succeeded;Failed;Skipped;RolledBack;RollbackFailed;RollbackSkipped
+	Phase string `json:"phase"`
+
+	// Message describing the outcome
+	Message string `json:"message,omitempty"`
+
+	// Outputs of the step available to the following steps, truncated to
+	// a bounded size
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// Changes made by the step
+	Changes []ResourceChange `json:"changes,omitempty"`
+
+	// StartTime of the step
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime of the step
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// VerificationStatus is the progress of an action's success criteria
+type VerificationStatus struct {
+	// StartTime when verification began
+	StartTime metav1.Time `json:"startTime"`
+
+	// LastCheckTime of the most recent check
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Checks made so far
+	Checks int32 `json:"checks,omitempty"`
+
+	// Message describing the outcome of the last check
+	Message string `json:"message,omitempty"`
+}
+
+// DiagnosticCapture holds the output of a pre-action hook
+type DiagnosticCapture struct {
+	// Type of hook that produced the capture
+	Type string `json:"type"`
+
+	// Source pod and container of the capture
+	Source string `json:"source,omitempty"`
+
+	// Output captured, truncated to a bounded size
+	Output string `json:"output,omitempty"`
+
+	// Truncated indicates the output was cut short
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Error if the capture failed
+	Error string `json:"error,omitempty"`
+
+	// CapturedAt timestamp
+	CapturedAt metav1.Time `json:"capturedAt,omitempty"`
 }
 
+// BlastRadiusReport describes the simulated impact of a destructive action
+type BlastRadiusReport struct {
+	// AffectedPods that would be removed by the action
+	AffectedPods []string `json:"affectedPods,omitempty"`
+
+	// ServicesLosingEndpoints would be left without any ready endpoints
+	ServicesLosingEndpoints []string `json:"servicesLosingEndpoints,omitempty"`
+
+	// ViolatedPDBs are PodDisruptionBudgets that would be exceeded
+	ViolatedPDBs []string `json:"violatedPDBs,omitempty"`
+
+	// RemainingReplicas is the lowest number of ready sibling replicas left
+	// for any affected workload
+	RemainingReplicas int32 `json:"remainingReplicas"`
+
+	// Blocked indicates the action was refused because of its impact
+	Blocked bool `json:"blocked,omitempty"`
+
+	// Reason the action was blocked
+	Reason string `json:"reason,omitempty"`
+
+	// SimulatedAt is when the simulation ran
+	SimulatedAt *metav1.Time `json:"simulatedAt,omitempty"`
+}
+
+// HibernationStatus tracks a workload scaled to zero by a hibernate action
+type HibernationStatus struct {
+	// PreviousReplicas restored when the workload resumes
+	PreviousReplicas int32 `json:"previousReplicas"`
+
+	// ResumeAt is when the workload is restored at the latest
+	ResumeAt *metav1.Time `json:"resumeAt,omitempty"`
+
+	// ResumedAt is when the workload was restored
+	ResumedAt *metav1.Time `json:"resumedAt,omitempty"`
+
+	// ResumeReason explains why the workload was restored
+	ResumeReason string `json:"resumeReason,omitempty"`
+}
+
+// Outcomes observed for the targets of dry-run actions
+const (
+	// DryRunOutcomeRecovered means the policy's triggers stopped firing for
+	// the target without anyone acting on it
+	DryRunOutcomeRecovered = "Recovered"
+
+	// DryRunOutcomePersisted means the triggers still fired for the target
+	// at the end of the observation window
+	DryRunOutcomePersisted = "Persisted"
+
+	// DryRunOutcomeIntervened means someone else changed the target while
+	// it was observed
+	DryRunOutcomeIntervened = "Intervened"
+
+	// DryRunOutcomeInconclusive means the target or policy was deleted
+	// while the target was observed
+	DryRunOutcomeInconclusive = "Inconclusive"
+)
+
+// DryRunObservation is what happened to the target of a dry-run action
+// while it was observed
+type DryRunObservation struct {
+	// ObserveUntil is when the observation window ends
+	ObserveUntil metav1.Time `json:"observeUntil"`
+
+	// ObservedAt is when the outcome was determined
+	ObservedAt *metav1.Time `json:"observedAt,omitempty"`
+
+	// Outcome observed for the target
+	// +kubebuilder:validation:Enum=Recovered;Persisted;Intervened;Inconclusive
+	Outcome string `json:"outcome,omitempty"`
+
+	// Manager is the field manager that changed an Intervened target
+	Manager string `json:"manager,omitempty"`
+
+	// Message describing the outcome
+	Message string `json:"message,omitempty"`
+}
+
+// PriorityDecision explains how the blended priority of an action was
+// computed: RuleWeight * RulePriority + AIWeight * AIConfidence * 100
+type PriorityDecision struct {
+	// RulePriority of the policy's action
+	RulePriority int32 `json:"rulePriority"`
+
+	// AIConfidence of the matching AI recommendation, zero without one
+	AIConfidence float64 `json:"aiConfidence"`
+
+	// RuleWeight the rule priority was weighed with
+	RuleWeight float64 `json:"ruleWeight"`
+
+	// AIWeight the AI confidence was weighed with
+	AIWeight float64 `json:"aiWeight"`
+
+	// Score is the blended priority
+	Score float64 `json:"score"`
+
+	// Rank of the action among the actions blended in its evaluation,
+	// starting at 1
+	Rank int32 `json:"rank"`
+
+	// TieBreak explains the order among actions with the same score
+	TieBreak string `json:"tieBreak,omitempty"`
+
+	// Explanation summarizes the decision
+	Explanation string `json:"explanation"`
+}
+
+// NodeTaintStatus tracks a node tainted by a taint action
+type NodeTaintStatus struct {
+	// Key of the taint
+	Key string `json:"key"`
+
+	// Effect of the taint
+	Effect string `json:"effect"`
+
+	// WindowEndsAt is when the node is checked and resolved
+	WindowEndsAt metav1.Time `json:"windowEndsAt"`
+
+	// Outcome once resolved: Untainted, DrainRequested or Kept
+	// +kubebuilder:validation:Enum=Untainted;DrainRequested;Kept
+	Outcome string `json:"outcome,omitempty"`
+
+	// DrainAction is the drain action created to escalate the node
+	DrainAction string `json:"drainAction,omitempty"`
+
+	// ResolvedAt is when the node was untainted or escalated
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+
+	// Message describes the node's health when it was resolved
+	Message string `json:"message,omitempty"`
+}
+
+// Outcomes of a node taint
+const (
+	NodeTaintUntainted      = "Untainted"
+	NodeTaintDrainRequested = "DrainRequested"
+	NodeTaintKept           = "Kept"
+)
+
 // ResourceChange describes a modification made
 type ResourceChange struct {
 	// ResourceRef identifies the resource (Kind/Namespace/Name)
@@ -169,6 +457,8 @@ type ApprovalStatus struct {
 // +kubebuilder:resource:shortName=ha
 // +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetResource.kind"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Dry Run",type="boolean",JSONPath=".spec.dryRun"
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=".status.severity"
 // +kubebuilder:printcolumn:name="Success",type="boolean",JSONPath=".status.result.success"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
@@ -195,16 +485,66 @@ const (
 	HealingActionPhasePending    = "Pending"
 	HealingActionPhaseApproved   = "Approved"
 	HealingActionPhaseInProgress = "InProgress"
+	HealingActionPhaseVerifying  = "Verifying"
 	HealingActionPhaseSucceeded  = "Succeeded"
 	HealingActionPhaseFailed     = "Failed"
 	HealingActionPhaseCancelled  = "Cancelled"
 )
 
+// Failure reasons recorded on failed actions
+const (
+	FailureReasonRBACDenied         = "RBACDenied"
+	FailureReasonTimeout            = "Timeout"
+	FailureReasonTargetNotFound     = "TargetNotFound"
+	FailureReasonConflict           = "Conflict"
+	FailureReasonExecutorError      = "ExecutorError"
+	FailureReasonValidationFailed   = "ValidationFailed"
+	FailureReasonCapacityBlocked    = "CapacityBlocked"
+	FailureReasonBlastRadiusBlocked = "BlastRadiusBlocked"
+	FailureReasonVerificationFailed = "VerificationFailed"
+)
+
 // Condition types
 const (
 	ConditionTypeReady     = "Ready"
 	ConditionTypeApproved  = "Approved"
 	ConditionTypeCompleted = "Completed"
+
+	// ConditionTypeOverrideDetected is set on a policy when a target was
+	// changed manually after KubeSkippy acted on it
+	ConditionTypeOverrideDetected = "OverrideDetected"
+
+	// ConditionTypeTriggerFlapping is set on a policy when one of its
+	// triggers keeps turning on and off
+	ConditionTypeTriggerFlapping = "TriggerFlapping"
+
+	// ConditionTypeRestartStorm is set on a policy while a restart storm
+	// trigger is firing
+	ConditionTypeRestartStorm = "RestartStorm"
+
+	// ConditionTypePaused is set on a policy while spec.paused is true, and
+	// on actions held by a paused policy
+	ConditionTypePaused = "Paused"
+
+	// ConditionTypeDefaultsDrifted is set on a policy when the defaults
+	// recorded at admission differ from the running operator's defaults
+	ConditionTypeDefaultsDrifted = "DefaultsDrifted"
+
+	// ConditionTypeHibernating is set on an action while its target is
+	// scaled to zero by a hibernate action
+	ConditionTypeHibernating = "Hibernating"
+
+	// ConditionTypeNodeTainted is set on an action while the node it
+	// tainted waits for its window
+	ConditionTypeNodeTainted = "NodeTainted"
+
+	// ConditionTypeDependenciesSettled is set on a policy with dependsOn,
+	// false while it waits for the policies it depends on
+	ConditionTypeDependenciesSettled = "DependenciesSettled"
+
+	// ConditionTypePolicyDegraded is set on a policy while healing is
+	// ineffective for one of its targets
+	ConditionTypePolicyDegraded = "PolicyDegraded"
 )
 
 func init() {
@@ -0,0 +1,6 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the version other HealingPolicy versions convert
+// through. It is also the storage version, so clusters without the
+// conversion webhook keep reading their policies unchanged.
+func (*HealingPolicy) Hub() {}
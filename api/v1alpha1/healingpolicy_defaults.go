@@ -0,0 +1,153 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationAppliedDefaults records, as a JSON object, the defaults filled
+// into a policy at admission so changes between operator versions can be
+// detected
+const AnnotationAppliedDefaults = "kubeskippy.io/applied-defaults"
+
+// Behavior-affecting policy defaults of this operator version
+const (
+	DefaultMode                   = "monitor"
+	DefaultCooldownPeriod         = 5 * time.Minute
+	DefaultClearAfterEvaluations  = 3
+	DefaultMaxActionsPerHour      = 10
+	DefaultHealthCheckTimeout     = 5 * time.Minute
+	DefaultActionTimeout          = 10 * time.Minute
+	DefaultRetryMaxAttempts       = 3
+	DefaultRetryBackoffDelay      = 30 * time.Second
+	DefaultRetryBackoffMultiplier = 2.0
+)
+
+// Keys of the defaults recorded in AnnotationAppliedDefaults
+const (
+	DefaultKeyMode                  = "mode"
+	DefaultKeyCooldownPeriod        = "triggers.cooldownPeriod"
+	DefaultKeyClearAfterEvaluations = "triggers.clearAfterEvaluations"
+	DefaultKeyMaxActionsPerHour     = "safetyRules.maxActionsPerHour"
+	DefaultKeyHealthCheckTimeout    = "safetyRules.healthCheckTimeout"
+	DefaultKeyActionTimeout         = "actionTimeout"
+	DefaultKeyRetryPolicy           = "retryPolicy"
+)
+
+// PolicyDefaults returns the current default for each recorded key
+func PolicyDefaults() map[string]string {
+	return map[string]string{
+		DefaultKeyMode:                  DefaultMode,
+		DefaultKeyCooldownPeriod:        DefaultCooldownPeriod.String(),
+		DefaultKeyClearAfterEvaluations: strconv.Itoa(DefaultClearAfterEvaluations),
+		DefaultKeyMaxActionsPerHour:     strconv.Itoa(DefaultMaxActionsPerHour),
+		DefaultKeyHealthCheckTimeout:    DefaultHealthCheckTimeout.String(),
+		DefaultKeyActionTimeout:         DefaultActionTimeout.String(),
+		DefaultKeyRetryPolicy:           retryPolicyString(defaultRetryPolicy()),
+	}
+}
+
+// defaultRetryPolicy is the retry policy for policies that do not set one
+func defaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:       DefaultRetryMaxAttempts,
+		BackoffDelay:      metav1.Duration{Duration: DefaultRetryBackoffDelay},
+		BackoffMultiplier: DefaultRetryBackoffMultiplier,
+	}
+}
+
+// retryPolicyString summarizes a retry policy for recording
+func retryPolicyString(r *RetryPolicy) string {
+	return strconv.Itoa(int(r.MaxAttempts)) + "x" + r.BackoffDelay.Duration.String() +
+		"*" + strconv.FormatFloat(r.BackoffMultiplier, 'g', -1, 64)
+}
+
+// ApplyDefaults fills unset behavior-affecting fields and returns the
+// defaults it applied, keyed like PolicyDefaults
+func (p *HealingPolicy) ApplyDefaults() map[string]string {
+	defaults := PolicyDefaults()
+	applied := make(map[string]string)
+
+	if p.Spec.Mode == "" {
+		p.Spec.Mode = DefaultMode
+		applied[DefaultKeyMode] = defaults[DefaultKeyMode]
+	}
+
+	for i := range p.Spec.Triggers {
+		trigger := &p.Spec.Triggers[i]
+		if trigger.CooldownPeriod.Duration == 0 {
+			trigger.CooldownPeriod = metav1.Duration{Duration: DefaultCooldownPeriod}
+			applied[DefaultKeyCooldownPeriod] = defaults[DefaultKeyCooldownPeriod]
+		}
+		if trigger.ClearAfterEvaluations == 0 {
+			trigger.ClearAfterEvaluations = DefaultClearAfterEvaluations
+			applied[DefaultKeyClearAfterEvaluations] = defaults[DefaultKeyClearAfterEvaluations]
+		}
+	}
+
+	if p.Spec.SafetyRules.MaxActionsPerHour == 0 {
+		p.Spec.SafetyRules.MaxActionsPerHour = DefaultMaxActionsPerHour
+		applied[DefaultKeyMaxActionsPerHour] = defaults[DefaultKeyMaxActionsPerHour]
+	}
+	if p.Spec.SafetyRules.HealthCheckTimeout.Duration == 0 {
+		p.Spec.SafetyRules.HealthCheckTimeout = metav1.Duration{Duration: DefaultHealthCheckTimeout}
+		applied[DefaultKeyHealthCheckTimeout] = defaults[DefaultKeyHealthCheckTimeout]
+	}
+
+	if p.Spec.ActionTimeout == nil {
+		p.Spec.ActionTimeout = &metav1.Duration{Duration: DefaultActionTimeout}
+		applied[DefaultKeyActionTimeout] = defaults[DefaultKeyActionTimeout]
+	}
+	if p.Spec.RetryPolicy == nil {
+		p.Spec.RetryPolicy = defaultRetryPolicy()
+		applied[DefaultKeyRetryPolicy] = defaults[DefaultKeyRetryPolicy]
+	}
+
+	return applied
+}
+
+// RecordAppliedDefaults merges applied defaults into the policy's
+// AnnotationAppliedDefaults. Keys recorded earlier keep their value, since
+// the policy still carries the default of that time.
+func (p *HealingPolicy) RecordAppliedDefaults(applied map[string]string) error {
+	if len(applied) == 0 {
+		return nil
+	}
+
+	recorded := p.AppliedDefaults()
+	if recorded == nil {
+		recorded = make(map[string]string, len(applied))
+	}
+	for key, value := range applied {
+		if _, ok := recorded[key]; !ok {
+			recorded[key] = value
+		}
+	}
+
+	data, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	if p.Annotations == nil {
+		p.Annotations = make(map[string]string)
+	}
+	p.Annotations[AnnotationAppliedDefaults] = string(data)
+	return nil
+}
+
+// AppliedDefaults returns the defaults recorded on the policy, or nil if
+// none were recorded or the annotation cannot be parsed
+func (p *HealingPolicy) AppliedDefaults() map[string]string {
+	data, ok := p.Annotations[AnnotationAppliedDefaults]
+	if !ok {
+		return nil
+	}
+	var recorded map[string]string
+	if err := json.Unmarshal([]byte(data), &recorded); err != nil {
+		return nil
+	}
+	return recorded
+}
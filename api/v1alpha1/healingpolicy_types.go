@@ -1,6 +1,9 @@
 package v1alpha1
 
 import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -22,6 +25,97 @@ type HealingPolicySpec struct {
 	// +kubebuilder:validation:Enum=monitor;dryrun;automatic;manual
 	// +kubebuilder:default=monitor
 	Mode string `json:"mode,omitempty"`
+
+	// RolloutPercentage limits automatic mode to this percentage of the
+	// matched targets, chosen by a stable hash of the target; actions on the
+	// other targets run as dry-runs. Defaults to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	RolloutPercentage *int32 `json:"rolloutPercentage,omitempty"`
+
+	// Paused stops trigger evaluation and holds the policy's pending actions
+	// without deleting the policy
+	Paused bool `json:"paused,omitempty"`
+
+	// ActionTimeout for the actions created by the policy
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	ActionTimeout *metav1.Duration `json:"actionTimeout,omitempty"`
+
+	// AttemptTimeout bounds each execution attempt of the actions created
+	// by the policy, see HealingActionSpec.AttemptTimeout
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	AttemptTimeout *metav1.Duration `json:"attemptTimeout,omitempty"`
+
+	// RetryPolicy for the actions created by the policy
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// AIProfile controls how much work the AI subsystem does for the policy
+	AIProfile *AIProfile `json:"aiProfile,omitempty"`
+
+	// AIAnalysisInterval is the minimum time between fresh AI analyses.
+	// Triggers firing in between are filtered with the most recent
+	// analysis. Unset runs an analysis whenever triggers fire.
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	AIAnalysisInterval *metav1.Duration `json:"aiAnalysisInterval,omitempty"`
+
+	// SeverityMapping assigns severities to triggers that do not set one.
+	// The first matching entry wins; unmatched triggers are warnings.
+	SeverityMapping []SeverityMapping `json:"severityMapping,omitempty"`
+
+	// DependsOn lists policies that must be evaluated and settled before
+	// this one each cycle, for example node-level policies ahead of the
+	// pod-level policies of the same workloads
+	DependsOn []PolicyDependency `json:"dependsOn,omitempty"`
+
+	// RecordTriggerValues exports the value each metric trigger is
+	// evaluated against, and the threshold it is compared with, as the
+	// kubeskippy_trigger_value and kubeskippy_trigger_threshold gauges
+	RecordTriggerValues bool `json:"recordTriggerValues,omitempty"`
+}
+
+// PolicyDependency references a HealingPolicy that is evaluated first
+type PolicyDependency struct {
+	// Name of the HealingPolicy
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the HealingPolicy, defaults to the dependent policy's
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SeverityMapping matches triggers by type and metric query
+type SeverityMapping struct {
+	// TriggerType to match (empty matches every type)
+	// +kubebuilder:validation:Enum=metric;event;condition;log;restartStorm;schedule;stuckTerminating;plugin
+	TriggerType string `json:"triggerType,omitempty"`
+
+	// QueryPattern is a regular expression matched against the query of
+	// metric triggers (empty matches every trigger)
+	QueryPattern string `json:"queryPattern,omitempty"`
+
+	// Severity of matching triggers
+	// +kubebuilder:validation:Enum=info;warning;critical
+	Severity string `json:"severity"`
+}
+
+// AIProfile bounds the cost of advanced metrics, pattern detection and AI
+// analysis so small or edge clusters can run them
+type AIProfile struct {
+	// Mode is full, which analyzes every evaluation, or lite, which analyzes
+	// at most once per AnalysisInterval on a sample of at most MaxPods pods
+	// +kubebuilder:validation:Enum=full;lite
+	// +kubebuilder:default=full
+	Mode string `json:"mode,omitempty"`
+
+	// AnalysisInterval is the minimum time between analyses in lite mode.
+	// Defaults to 10m.
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	AnalysisInterval *metav1.Duration `json:"analysisInterval,omitempty"`
+
+	// MaxPods is the number of pods sampled per analysis in lite mode.
+	// Defaults to 50.
+	// +kubebuilder:validation:Minimum=1
+	MaxPods int32 `json:"maxPods,omitempty"`
 }
 
 // ResourceSelector defines how to select resources for healing
@@ -33,7 +127,18 @@ type ResourceSelector struct {
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 
 	// Resource types to monitor
+	// +kubebuilder:validation:MinItems=1
 	Resources []ResourceFilter `json:"resources"`
+
+	// ExcludeNamespaces to ignore; entries may be glob patterns such as "kube-*"
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// ExcludeLabelSelector ignores resources whose labels match
+	ExcludeLabelSelector *metav1.LabelSelector `json:"excludeLabelSelector,omitempty"`
+
+	// ExcludeNames to ignore across all resource types; entries may be glob
+	// patterns such as "*-canary"
+	ExcludeNames []string `json:"excludeNames,omitempty"`
 }
 
 // ResourceFilter defines a specific resource type to monitor
@@ -42,19 +147,21 @@ type ResourceFilter struct {
 	APIVersion string `json:"apiVersion"`
 
 	// Kind of the resource
+	// +kubebuilder:validation:MinLength=1
 	Kind string `json:"kind"`
 
-	// ExcludeNames to ignore specific resource names
+	// ExcludeNames to ignore specific resource names; entries may be glob patterns
 	ExcludeNames []string `json:"excludeNames,omitempty"`
 }
 
 // HealingTrigger defines when to initiate healing
 type HealingTrigger struct {
 	// Name of this trigger
+	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 
 	// Type of trigger
-	// +kubebuilder:validation:Enum=metric;event;condition
+	// +kubebuilder:validation:Enum=metric;event;condition;log;restartStorm;schedule;stuckTerminating;plugin
 	Type string `json:"type"`
 
 	// MetricTrigger for Prometheus-based triggers
@@ -66,16 +173,51 @@ type HealingTrigger struct {
 	// ConditionTrigger for resource condition-based triggers
 	ConditionTrigger *ConditionTrigger `json:"conditionTrigger,omitempty"`
 
+	// LogTrigger for pod log pattern-based triggers
+	LogTrigger *LogTrigger `json:"logTrigger,omitempty"`
+
+	// RestartStormTrigger for namespace-wide container restart storms
+	RestartStormTrigger *RestartStormTrigger `json:"restartStormTrigger,omitempty"`
+
+	// ScheduleTrigger for proactive actions run on a cron schedule
+	ScheduleTrigger *ScheduleTrigger `json:"scheduleTrigger,omitempty"`
+
+	// StuckTerminatingTrigger for resources stuck in Terminating on their
+	// finalizers
+	StuckTerminatingTrigger *StuckTerminatingTrigger `json:"stuckTerminatingTrigger,omitempty"`
+
+	// PluginTrigger for triggers evaluated by a registered plugin
+	PluginTrigger *PluginTrigger `json:"pluginTrigger,omitempty"`
+
 	// CooldownPeriod prevents trigger from firing too frequently
 	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	CooldownPeriod metav1.Duration `json:"cooldownPeriod,omitempty"`
+
+	// ClearAfterEvaluations is the number of consecutive evaluations the
+	// trigger must stay quiet before its state is cleared from the status
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	ClearAfterEvaluations int32 `json:"clearAfterEvaluations,omitempty"`
+
+	// Severity of the trigger, overriding the policy's severity mapping
+	// +kubebuilder:validation:Enum=info;warning;critical
+	Severity string `json:"severity,omitempty"`
 }
 
 // MetricTrigger defines Prometheus metric-based triggers
 type MetricTrigger struct {
 	// Query is the PromQL query
+	// +kubebuilder:validation:MinLength=1
 	Query string `json:"query"`
 
+	// Datasource answering the query. Empty infers it from the query:
+	// "custom:" queries are pushed metrics, "pattern:" queries are detected
+	// patterns, PromQL goes to Prometheus and other queries are builtin
+	// metric names.
+	// +kubebuilder:validation:Enum=prometheus;builtin;pushed;pattern
+	Datasource string `json:"datasource,omitempty"`
+
 	// Threshold for the metric
 	Threshold float64 `json:"threshold"`
 
@@ -85,7 +227,70 @@ type MetricTrigger struct {
 
 	// Duration the condition must be true
 	// +kubebuilder:default="2m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// Baseline makes the threshold a multiple of the query's average over
+	// the same hour of the previous days, e.g. threshold 2 with operator ">"
+	// fires when the value exceeds twice that average. Requires the
+	// prometheus datasource.
+	// +optional
+	Baseline *MetricBaseline `json:"baseline,omitempty"`
+}
+
+// MetricBaseline averages a query over the same clock hour of the previous
+// days, read from Prometheus
+type MetricBaseline struct {
+	// Days of history averaged. Days without samples are skipped.
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=28
+	Days int32 `json:"days,omitempty"`
+
+	// Floor is the smallest baseline the threshold multiplies, so that
+	// quiet hours averaging close to zero do not fire on any value
+	// +optional
+	Floor float64 `json:"floor,omitempty"`
+}
+
+// DefaultBaselineDays is used when a baseline does not set its days
+const DefaultBaselineDays = 7
+
+// Metric trigger datasources
+const (
+	DatasourcePrometheus = "prometheus"
+	DatasourceBuiltin    = "builtin"
+	DatasourcePushed     = "pushed"
+	DatasourcePattern    = "pattern"
+)
+
+// Query prefixes selecting the pushed and pattern datasources
+const (
+	PushedQueryPrefix  = "custom:"
+	PatternQueryPrefix = "pattern:"
+)
+
+// EffectiveDatasource returns the explicit datasource of the trigger, or the
+// one inferred from its query
+func (t *MetricTrigger) EffectiveDatasource() string {
+	if t.Datasource != "" {
+		return t.Datasource
+	}
+	return InferDatasource(t.Query)
+}
+
+// InferDatasource returns the datasource of a query without an explicit one
+func InferDatasource(query string) string {
+	switch {
+	case strings.HasPrefix(query, PushedQueryPrefix):
+		return DatasourcePushed
+	case strings.HasPrefix(query, PatternQueryPrefix):
+		return DatasourcePattern
+	case strings.ContainsAny(query, "({["):
+		return DatasourcePrometheus
+	default:
+		return DatasourceBuiltin
+	}
 }
 
 // EventTrigger defines Kubernetes event-based triggers
@@ -94,41 +299,141 @@ type EventTrigger struct {
 	Reason string `json:"reason,omitempty"`
 
 	// Type of event (Normal, Warning)
+	// +kubebuilder:validation:Enum=Normal;Warning
 	Type string `json:"type,omitempty"`
 
 	// Count threshold
+	// +kubebuilder:validation:Minimum=0
 	Count int32 `json:"count,omitempty"`
 
 	// Window to count events in
 	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	Window metav1.Duration `json:"window,omitempty"`
 }
 
+// LogTrigger defines pod log pattern-based triggers
+type LogTrigger struct {
+	// Pattern is the regular expression to match log lines against
+	// +kubebuilder:validation:MinLength=1
+	Pattern string `json:"pattern"`
+
+	// Container to sample (defaults to all containers)
+	Container string `json:"container,omitempty"`
+
+	// Count of matching lines required to fire
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count,omitempty"`
+
+	// Window to count matches in
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Window metav1.Duration `json:"window,omitempty"`
+
+	// MaxLines sampled per container and evaluation
+	// +kubebuilder:default=500
+	// +kubebuilder:validation:Minimum=1
+	MaxLines int64 `json:"maxLines,omitempty"`
+
+	// MaxBytes sampled per container and evaluation
+	// +kubebuilder:default=65536
+	// +kubebuilder:validation:Minimum=1
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+}
+
+// RestartStormTrigger fires when many pods of a namespace restart within a
+// short window. While a storm is active, per-pod restart and delete actions
+// are skipped in favor of workload-level actions.
+type RestartStormTrigger struct {
+	// MinRestarts across the namespace within the window
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	MinRestarts int32 `json:"minRestarts,omitempty"`
+
+	// MinPods is the number of distinct restarting pods required
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	MinPods int32 `json:"minPods,omitempty"`
+
+	// Window to count restarts in
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Window metav1.Duration `json:"window,omitempty"`
+}
+
+// ScheduleTrigger fires at the times of a cron schedule, for proactive
+// actions such as a nightly restart of a leaky deployment. Scheduled actions
+// go through the same safety checks and approvals as reactive ones. A run
+// missed by more than an hour, e.g. while the operator was down, is skipped.
+type ScheduleTrigger struct {
+	// Schedule in cron format ("minute hour day-of-month month day-of-week")
+	// or a macro such as "@daily"
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// TimeZone of the schedule as an IANA name, e.g. "Europe/Berlin"
+	// +kubebuilder:default="UTC"
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// StuckTerminatingTrigger fires when matched resources have been deleted
+// but are still held by finalizers after the threshold, typically because
+// the controller owning a finalizer is gone. Only the stuck resources are
+// targeted by the policy's actions.
+type StuckTerminatingTrigger struct {
+	// Threshold a resource must be terminating for
+	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Threshold metav1.Duration `json:"threshold,omitempty"`
+}
+
+// PluginTrigger is evaluated by a trigger evaluator or pattern detector
+// loaded into the operator as a plugin. Exactly one of Evaluator and
+// Detector must be set; a detector fires when it finds any pattern.
+type PluginTrigger struct {
+	// Evaluator is the name of a registered trigger evaluator
+	Evaluator string `json:"evaluator,omitempty"`
+
+	// Detector is the name of a registered pattern detector
+	Detector string `json:"detector,omitempty"`
+
+	// Config is passed to the plugin as is
+	Config map[string]string `json:"config,omitempty"`
+}
+
 // ConditionTrigger defines resource condition-based triggers
 type ConditionTrigger struct {
 	// Type of condition
 	Type string `json:"type"`
 
 	// Status to match
+	// +kubebuilder:validation:Enum=True;False;Unknown
 	Status string `json:"status"`
 
 	// Duration the condition must exist
 	// +kubebuilder:default="2m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	Duration metav1.Duration `json:"duration,omitempty"`
 }
 
 // HealingActionTemplate defines a healing action to take
 type HealingActionTemplate struct {
 	// Name of this action
+	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 
 	// Type of action
-	// +kubebuilder:validation:Enum=restart;scale;patch;delete;custom
+	// +kubebuilder:validation:Enum=restart;scale;patch;delete;finalizer;hibernate;exec;resize;taint;drain;custom;chain
 	Type string `json:"type"`
 
 	// Description for logging/auditing
 	Description string `json:"description,omitempty"`
 
+	// TemplateRef names a cluster-scoped ActionTemplate providing defaults
+	// for the action's parameters and constraining them
+	TemplateRef string `json:"templateRef,omitempty"`
+
 	// RestartAction for pod restarts
 	RestartAction *RestartAction `json:"restartAction,omitempty"`
 
@@ -141,12 +446,224 @@ type HealingActionTemplate struct {
 	// DeleteAction for resource deletion
 	DeleteAction *DeleteAction `json:"deleteAction,omitempty"`
 
+	// FinalizerAction for resources stuck in Terminating
+	FinalizerAction *FinalizerAction `json:"finalizerAction,omitempty"`
+
+	// HibernateAction for workloads to stop for a while
+	HibernateAction *HibernateAction `json:"hibernateAction,omitempty"`
+
+	// ExecAction for commands run inside containers
+	ExecAction *ExecAction `json:"execAction,omitempty"`
+
+	// ResizeAction for container resource changes
+	ResizeAction *ResizeAction `json:"resizeAction,omitempty"`
+
+	// TaintAction for nodes suspected of a problem
+	TaintAction *TaintAction `json:"taintAction,omitempty"`
+
 	// Priority of this action (higher executes first)
 	// +kubebuilder:default=50
+	// +kubebuilder:validation:Minimum=0
 	Priority int32 `json:"priority,omitempty"`
 
 	// RequiresApproval overrides policy mode
 	RequiresApproval bool `json:"requiresApproval,omitempty"`
+
+	// FreezeRollout pauses a target Deployment, or holds the rolling update
+	// partition of a target StatefulSet, while the action changes it, so no
+	// other rollout interleaves with the healing. The workload is resumed
+	// afterwards and its rollout watched until healthy; the fields the
+	// action changed are reverted if the action failed or the rollout did
+	// not become healthy. A workload resumed by someone else meanwhile is
+	// left to them.
+	FreezeRollout bool `json:"freezeRollout,omitempty"`
+
+	// PreActionHooks capture diagnostics before the target is mutated
+	PreActionHooks []PreActionHook `json:"preActionHooks,omitempty"`
+
+	// Execution runs the commands of exec actions and exec hooks in helper
+	// pods instead of the target's containers
+	Execution *ExecutionEnvironment `json:"execution,omitempty"`
+
+	// SuccessCriteria verify that the action solved the problem. An
+	// executed action is Verifying until its criteria are met, and fails
+	// if they are not met within their timeout.
+	SuccessCriteria *SuccessCriteria `json:"successCriteria,omitempty"`
+
+	// Steps of chain actions, run in order on the target. The string
+	// parameters of a step may use the outputs of the steps before it as
+	// {{.Steps.<step>.<output>}}, and the target as {{.Target.Name}}; the
+	// replicasTemplate of scale steps is rendered the same way. When a step
+	// fails, the steps that completed are rolled back in reverse order
+	// where their changes can be undone.
+	// +kubebuilder:validation:MaxItems=10
+	Steps []ActionStep `json:"steps,omitempty"`
+}
+
+// ActionStep is one step of a chain action. Besides the output of its
+// diagnostics, a step outputs the metrics of its result, e.g.
+// previous_replicas of scale steps.
+type ActionStep struct {
+	// Name of the step, unique within the chain
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Type of the step: an action type, diagnostics to capture Hooks, or
+	// verify to wait for Condition on the target
+	// +kubebuilder:validation:Enum=restart;scale;patch;delete;finalizer;exec;resize;diagnostics;verify
+	Type string `json:"type"`
+
+	// RestartAction for restart steps
+	RestartAction *RestartAction `json:"restartAction,omitempty"`
+
+	// ScaleAction for scale steps
+	ScaleAction *ScaleAction `json:"scaleAction,omitempty"`
+
+	// PatchAction for patch steps
+	PatchAction *PatchAction `json:"patchAction,omitempty"`
+
+	// DeleteAction for delete steps
+	DeleteAction *DeleteAction `json:"deleteAction,omitempty"`
+
+	// FinalizerAction for finalizer steps
+	FinalizerAction *FinalizerAction `json:"finalizerAction,omitempty"`
+
+	// ExecAction for exec steps
+	ExecAction *ExecAction `json:"execAction,omitempty"`
+
+	// ResizeAction for resize steps
+	ResizeAction *ResizeAction `json:"resizeAction,omitempty"`
+
+	// Hooks captured by diagnostics steps
+	Hooks []PreActionHook `json:"hooks,omitempty"`
+
+	// Condition verify steps wait for the target to report
+	Condition *TargetCondition `json:"condition,omitempty"`
+
+	// Timeout of verify steps
+	// +kubebuilder:default="2m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ActionTemplate returns the action the executor of the step's type runs,
+// with the chain's execution environment
+func (s *ActionStep) ActionTemplate(chain *HealingActionTemplate) *HealingActionTemplate {
+	step := s.DeepCopy()
+	return &HealingActionTemplate{
+		Name:            step.Name,
+		Type:            step.Type,
+		RestartAction:   step.RestartAction,
+		ScaleAction:     step.ScaleAction,
+		PatchAction:     step.PatchAction,
+		DeleteAction:    step.DeleteAction,
+		FinalizerAction: step.FinalizerAction,
+		ExecAction:      step.ExecAction,
+		ResizeAction:    step.ResizeAction,
+		Execution:       chain.Execution.DeepCopy(),
+	}
+}
+
+// SuccessCriteria define when an executed action counts as successful. All
+// set criteria must be met.
+type SuccessCriteria struct {
+	// Metric that must meet its threshold
+	Metric *MetricCriterion `json:"metric,omitempty"`
+
+	// Condition the target must report
+	Condition *TargetCondition `json:"condition,omitempty"`
+
+	// Timeout for the criteria to be met after execution
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// Interval between checks of the criteria
+	// +kubebuilder:default="15s"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// MetricCriterion compares a metric query against a threshold
+type MetricCriterion struct {
+	// Query is the PromQL query, which may use the target variables of
+	// templated trigger queries such as {{.Namespace}} and {{.Owner}}
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+
+	// Threshold for the metric
+	Threshold float64 `json:"threshold"`
+
+	// Operator for comparison
+	// +kubebuilder:validation:Enum=">";"<";">=";"<="
+	Operator string `json:"operator"`
+}
+
+// TargetCondition is a status condition the target must report
+type TargetCondition struct {
+	// Type of the condition, such as Ready for pods or Available for
+	// deployments
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// Status the condition must have
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	// +kubebuilder:default="True"
+	Status string `json:"status,omitempty"`
+}
+
+// PreActionHook captures evidence from the target before an action runs
+type PreActionHook struct {
+	// Type of capture
+	// +kubebuilder:validation:Enum=logs;exec;events
+	Type string `json:"type"`
+
+	// Container to capture from (defaults to the first container)
+	Container string `json:"container,omitempty"`
+
+	// TailLines of logs to capture
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	TailLines int64 `json:"tailLines,omitempty"`
+
+	// Command to run for exec hooks, e.g. a thread or heap dump
+	Command []string `json:"command,omitempty"`
+
+	// Timeout for the capture
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ExecutionEnvironment configures the helper pods commands run in, e.g. for
+// heap dumps with tools missing from the target's image. One helper pod is
+// started per target pod, in its namespace, and deleted once the command
+// finished; its logs are the command's output.
+type ExecutionEnvironment struct {
+	// Image of the helper container
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// ServiceAccountName the helper pod runs as. Without it, no service
+	// account token is mounted.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// TargetNode runs the helper pod on the node of the target pod, e.g. to
+	// read node-local data. It cannot be combined with NodeSelector and
+	// Affinity.
+	TargetNode bool `json:"targetNode,omitempty"`
+
+	// NodeSelector constrains the nodes the helper pod runs on
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity of the helper pod
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations of the helper pod
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Resources of the helper container
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // RestartAction defines pod restart parameters
@@ -158,11 +675,67 @@ type RestartAction struct {
 
 	// MaxConcurrent pods to restart at once
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
 	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
 
 	// GracePeriodSeconds for graceful shutdown
 	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=0
 	GracePeriodSeconds int32 `json:"gracePeriodSeconds,omitempty"`
+
+	// Mesh makes pod restarts aware of Istio and Linkerd sidecars
+	Mesh *MeshRestart `json:"mesh,omitempty"`
+
+	// Containers restricts the restart to the named containers of each
+	// target pod, which are restarted in place instead of recreating the
+	// pod, by pinning their image to the digest they run
+	Containers []string `json:"containers,omitempty"`
+}
+
+// ExecAction runs a command in containers of the target's pods, e.g. to
+// reload configuration or flush a cache
+type ExecAction struct {
+	// Command to run
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Containers to run the command in (defaults to the first container)
+	Containers []string `json:"containers,omitempty"`
+
+	// Timeout for each command
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ResizeAction changes the resources of named containers. Pods are resized
+// in place; workloads get the resources in their pod template, which rolls
+// out new pods.
+type ResizeAction struct {
+	// Containers to resize; the other containers are left unchanged
+	// +kubebuilder:validation:MinItems=1
+	Containers []string `json:"containers"`
+
+	// Resources to set on the containers. Only the listed requests and
+	// limits are changed.
+	Resources corev1.ResourceRequirements `json:"resources"`
+}
+
+// MeshRestart configures restarts of pods with a service mesh sidecar.
+// Before a meshed pod is deleted, the sidecars of its peers must be ready and
+// the pod is taken out of load balancing through its readiness gate so the
+// mesh drains its connections.
+type MeshRestart struct {
+	// ReadinessGate is the pod readiness gate condition set to False to
+	// drain the pod. Pods without the gate are deleted without draining.
+	// +kubebuilder:default="kubeskippy.io/serving"
+	ReadinessGate string `json:"readinessGate,omitempty"`
+
+	// DrainSeconds to wait after draining before deleting the pod
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=300
+	// +kubebuilder:default=10
+	DrainSeconds int32 `json:"drainSeconds,omitempty"`
 }
 
 // ScaleAction defines scaling parameters
@@ -172,14 +745,26 @@ type ScaleAction struct {
 	Direction string `json:"direction"`
 
 	// Replicas to scale by or to
+	// +kubebuilder:validation:Minimum=0
 	Replicas int32 `json:"replicas"`
 
+	// ReplicasTemplate computes Replicas from the trigger context when the
+	// action is created, e.g. "{{ mul .CurrentReplicas 1.5 | ceil }}"
+	ReplicasTemplate string `json:"replicasTemplate,omitempty"`
+
+	// AllowScaleToZero lets ReplicasTemplate render 0 replicas. Without it a
+	// template rendering 0 fails the action.
+	// +optional
+	AllowScaleToZero bool `json:"allowScaleToZero,omitempty"`
+
 	// MinReplicas constraint
 	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
 	MinReplicas int32 `json:"minReplicas,omitempty"`
 
 	// MaxReplicas constraint
 	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
 	MaxReplicas int32 `json:"maxReplicas,omitempty"`
 }
 
@@ -189,7 +774,7 @@ type PatchAction struct {
 	// +kubebuilder:validation:Enum=strategic;merge;json
 	Type string `json:"type"`
 
-	// Patch content
+	// Patch content, may reference the trigger context as a template
 	Patch string `json:"patch"`
 
 	// Patches for structured patching
@@ -199,9 +784,10 @@ type PatchAction struct {
 // PatchOperation defines a single patch operation
 type PatchOperation struct {
 	// Path to the field to patch
+	// +kubebuilder:validation:MinItems=1
 	Path []string `json:"path"`
 
-	// Value to set
+	// Value to set, may reference the trigger context as a template
 	Value string `json:"value"`
 }
 
@@ -209,6 +795,7 @@ type PatchOperation struct {
 type DeleteAction struct {
 	// GracePeriodSeconds before force deletion
 	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=0
 	GracePeriodSeconds int32 `json:"gracePeriodSeconds,omitempty"`
 
 	// Force deletion even with finalizers
@@ -219,12 +806,93 @@ type DeleteAction struct {
 	PropagationPolicy string `json:"propagationPolicy,omitempty"`
 }
 
+// FinalizerAction releases a resource stuck in Terminating. Nothing is
+// removed unless opted in: finalizers listed in RemoveFinalizers are
+// removed, and ForceDelete escalates to removing every finalizer. To remove
+// safe finalizers automatically and escalate otherwise, use two actions.
+type FinalizerAction struct {
+	// RemoveFinalizers are known-safe finalizers that may be removed, e.g.
+	// those of an uninstalled controller
+	RemoveFinalizers []string `json:"removeFinalizers,omitempty"`
+
+	// ForceDelete removes every finalizer of the resource. Such actions
+	// always require approval.
+	ForceDelete bool `json:"forceDelete,omitempty"`
+}
+
+// HibernateAction scales a workload to zero, e.g. to stop a leaking or
+// runaway job, and restores its previous replica count after Duration or
+// as soon as ResumeWhen is met, whichever comes first
+type HibernateAction struct {
+	// Duration to keep the workload at zero replicas
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// ResumeWhen restores the workload once a resource reports a condition
+	ResumeWhen *ResumeCondition `json:"resumeWhen,omitempty"`
+}
+
+// TaintAction taints a node suspected of a problem instead of draining it
+// right away. After Window the node and the pods on it are checked: the
+// taint is removed if they are healthy, and the node is escalated otherwise.
+type TaintAction struct {
+	// Key of the taint
+	// +kubebuilder:default="kubeskippy.io/suspected-problem"
+	Key string `json:"key,omitempty"`
+
+	// Effect of the taint
+	// +kubebuilder:default="PreferNoSchedule"
+	// +kubebuilder:validation:Enum=PreferNoSchedule;NoSchedule
+	Effect string `json:"effect,omitempty"`
+
+	// Window to wait for the node's workloads to recover before the taint
+	// is removed or the node escalated
+	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Window metav1.Duration `json:"window,omitempty"`
+
+	// Escalation for a node still unhealthy after the window: Keep leaves
+	// the taint for an operator, Drain creates a drain action, which needs
+	// approval and passes the same safety checks as any other action, to
+	// cordon the node and evict its pods
+	// +kubebuilder:default="Keep"
+	// +kubebuilder:validation:Enum=Drain;Keep
+	Escalation string `json:"escalation,omitempty"`
+}
+
+// ResumeCondition is a status condition of a resource in the action's
+// namespace
+type ResumeCondition struct {
+	// APIVersion of the resource
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Type of condition
+	Type string `json:"type"`
+
+	// Status to match
+	// +kubebuilder:default="True"
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status string `json:"status,omitempty"`
+}
+
 // SafetyRules define constraints on healing actions
 type SafetyRules struct {
 	// MaxActionsPerHour limits action frequency
 	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
 	MaxActionsPerHour int32 `json:"maxActionsPerHour,omitempty"`
 
+	// OffHoursMaxActionsPerHour limits action frequency outside the
+	// operator's business hours, at nights and on weekends
+	// +kubebuilder:validation:Minimum=0
+	OffHoursMaxActionsPerHour int32 `json:"offHoursMaxActionsPerHour,omitempty"`
+
 	// ProtectedResources that should never be modified
 	ProtectedResources []ResourceFilter `json:"protectedResources,omitempty"`
 
@@ -233,7 +901,64 @@ type SafetyRules struct {
 
 	// HealthCheckTimeout for post-action validation
 	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
 	HealthCheckTimeout metav1.Duration `json:"healthCheckTimeout,omitempty"`
+
+	// BlastRadius limits the simulated impact of destructive actions
+	BlastRadius *BlastRadiusLimits `json:"blastRadius,omitempty"`
+
+	// OverridePausePeriod pauses automatic actions on a target after an
+	// out-of-band change by someone other than KubeSkippy, e.g. 1h. Override
+	// detection is off when it is unset or 0s.
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	OverridePausePeriod *metav1.Duration `json:"overridePausePeriod,omitempty"`
+
+	// SeverityRules tighten or escalate actions by the severity of the
+	// trigger that caused them
+	SeverityRules []SeverityRule `json:"severityRules,omitempty"`
+
+	// EscalateAfterFailures is the number of consecutive failed actions on
+	// a target that still triggers after which healing is reported as
+	// ineffective. Defaults to 3; set to 0 to disable escalation.
+	// +kubebuilder:validation:Minimum=0
+	EscalateAfterFailures *int32 `json:"escalateAfterFailures,omitempty"`
+}
+
+// SeverityRule applies to the actions of triggers of one severity
+type SeverityRule struct {
+	// Severity the rule applies to
+	// +kubebuilder:validation:Enum=info;warning;critical
+	Severity string `json:"severity"`
+
+	// RequireApproval for actions of this severity
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// AllowedActions restricts the action types of this severity (empty
+	// allows every type)
+	// +kubebuilder:validation:items:Enum=restart;scale;patch;delete;finalizer;hibernate;exec;resize;taint;drain;custom;chain
+	AllowedActions []string `json:"allowedActions,omitempty"`
+
+	// MinPriority escalates the priority of actions of this severity to at
+	// least this value
+	// +kubebuilder:validation:Minimum=0
+	MinPriority int32 `json:"minPriority,omitempty"`
+}
+
+// BlastRadiusLimits bounds the downstream impact a destructive action may have
+type BlastRadiusLimits struct {
+	// MaxServicesWithoutEndpoints is the number of Services allowed to lose
+	// all ready endpoints as a result of the action
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	MaxServicesWithoutEndpoints int32 `json:"maxServicesWithoutEndpoints,omitempty"`
+
+	// AllowPDBViolation permits actions that would exceed a PodDisruptionBudget
+	AllowPDBViolation bool `json:"allowPDBViolation,omitempty"`
+
+	// MinRemainingReplicas is the number of ready sibling replicas that must
+	// survive the action
+	// +kubebuilder:validation:Minimum=0
+	MinRemainingReplicas int32 `json:"minRemainingReplicas,omitempty"`
 }
 
 // HealingPolicyStatus defines the observed state of HealingPolicy
@@ -241,15 +966,48 @@ type HealingPolicyStatus struct {
 	// LastEvaluated timestamp
 	LastEvaluated metav1.Time `json:"lastEvaluated,omitempty"`
 
+	// LastAIAnalysis is when advanced metrics or AI analysis last ran
+	LastAIAnalysis *metav1.Time `json:"lastAIAnalysis,omitempty"`
+
+	// AIAnalysis reports the progress of the latest AI analysis, updated
+	// while the provider streams its response
+	AIAnalysis *AIAnalysisStatus `json:"aiAnalysis,omitempty"`
+
 	// ActiveTriggers currently firing
 	ActiveTriggers []string `json:"activeTriggers,omitempty"`
 
+	// TriggerStates tracks recently active triggers until they have been
+	// quiet for ClearAfterEvaluations evaluations
+	TriggerStates []TriggerState `json:"triggerStates,omitempty"`
+
+	// TriggerHistory holds the most recent evaluated values of each metric
+	// trigger, newest last
+	TriggerHistory []TriggerHistory `json:"triggerHistory,omitempty"`
+
 	// ActionsTaken in the current period
 	ActionsTaken int32 `json:"actionsTaken,omitempty"`
 
 	// LastActionTime of the most recent action
 	LastActionTime metav1.Time `json:"lastActionTime,omitempty"`
 
+	// ActionCreation reports the progress of the latest batched action creation
+	ActionCreation *ActionCreationProgress `json:"actionCreation,omitempty"`
+
+	// Incidents are the targets triggers are currently firing for
+	Incidents []Incident `json:"incidents,omitempty"`
+
+	// Recovery summarizes the time from detection to resolution of the
+	// incidents the policy acted on
+	Recovery *RecoveryStats `json:"recovery,omitempty"`
+
+	// FailingTargets are the still triggering targets whose actions keep
+	// failing or are blocked by an open circuit breaker
+	FailingTargets []FailingTarget `json:"failingTargets,omitempty"`
+
+	// SelfInducedChurn are the pods replaced by the policy's own restarts,
+	// left out of trigger evaluation while they settle
+	SelfInducedChurn []SelfInducedChurn `json:"selfInducedChurn,omitempty"`
+
 	// Conditions of the policy
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
@@ -257,12 +1015,208 @@ type HealingPolicyStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// Incident is a trigger firing for a target
+type Incident struct {
+	// Trigger that fired
+	Trigger string `json:"trigger"`
+
+	// Target the trigger fired for (Kind/Namespace/Name)
+	Target string `json:"target"`
+
+	// DetectedAt is when the trigger first fired for the target
+	DetectedAt metav1.Time `json:"detectedAt"`
+
+	// ActedAt is when the policy first created an action for the incident
+	ActedAt *metav1.Time `json:"actedAt,omitempty"`
+}
+
+// FailingTarget tracks the unsuccessful healing of a target across
+// evaluations
+type FailingTarget struct {
+	// Target (Kind/Namespace/Name)
+	Target string `json:"target"`
+
+	// ConsecutiveFailures of the policy's actions on the target since its
+	// last successful action
+	ConsecutiveFailures int32 `json:"consecutiveFailures"`
+
+	// CircuitOpen is true while the circuit breaker blocks actions on the
+	// target
+	CircuitOpen bool `json:"circuitOpen,omitempty"`
+
+	// FirstFailure is when the first of the consecutive failures completed
+	FirstFailure *metav1.Time `json:"firstFailure,omitempty"`
+
+	// LastFailure is when the most recent failure completed
+	LastFailure *metav1.Time `json:"lastFailure,omitempty"`
+
+	// LastMessage of the most recent failed action
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// EscalatedAt is when healing of the target was reported as ineffective
+	EscalatedAt *metav1.Time `json:"escalatedAt,omitempty"`
+}
+
+// SelfInducedChurn records the pods a restart or delete action of the
+// policy replaced, so their startup restarts do not fire the triggers again
+type SelfInducedChurn struct {
+	// Action that replaced the pods
+	Action string `json:"action"`
+
+	// Target of the action (Kind/Namespace/Name)
+	Target string `json:"target"`
+
+	// StartedAt is when the action began executing; pods of the target
+	// created since are self-induced
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// Until is the end of the settling window
+	Until metav1.Time `json:"until"`
+
+	// TemplateHash of the replacement pods, from their pod-template-hash or
+	// controller-revision-hash label
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// PodUIDs of the replacement pods seen so far
+	PodUIDs []string `json:"podUIDs,omitempty"`
+}
+
+// RecoveryStats measures the time from a trigger first firing on a target
+// to the trigger no longer firing after an action
+type RecoveryStats struct {
+	// Recoveries counted
+	Recoveries int64 `json:"recoveries"`
+
+	// MeanTimeToRecovery over all counted recoveries
+	MeanTimeToRecovery metav1.Duration `json:"meanTimeToRecovery"`
+
+	// LastTimeToRecovery of the most recent recovery
+	LastTimeToRecovery metav1.Duration `json:"lastTimeToRecovery,omitempty"`
+
+	// LastRecovered is when the most recent recovery was observed
+	LastRecovered *metav1.Time `json:"lastRecovered,omitempty"`
+}
+
+// AI analysis phases
+const (
+	AIAnalysisPhaseRunning   = "Running"
+	AIAnalysisPhaseCompleted = "Completed"
+	AIAnalysisPhaseFailed    = "Failed"
+)
+
+// AIAnalysisStatus is the progress of an AI analysis of the policy's
+// triggered actions
+type AIAnalysisStatus struct {
+	// Phase is Running while the response streams in, then Completed or
+	// Failed
+	// +kubebuilder:validation:Enum=Running;Completed;Failed
+	Phase string `json:"phase"`
+
+	// StartedAt is when the analysis was requested
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// UpdatedAt is when progress was last reported
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// StepsCompleted are the reasoning steps the AI has produced so far
+	StepsCompleted int32 `json:"stepsCompleted,omitempty"`
+
+	// CurrentStep describes the latest reasoning step
+	CurrentStep string `json:"currentStep,omitempty"`
+
+	// Confidence the AI has stated so far, between 0 and 1
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// Message explains a failed analysis
+	Message string `json:"message,omitempty"`
+}
+
+// TriggerState tracks the activity of a trigger across evaluations
+type TriggerState struct {
+	// Name of the trigger
+	Name string `json:"name"`
+
+	// FirstActive is when the trigger started firing
+	FirstActive metav1.Time `json:"firstActive"`
+
+	// LastActive is when the trigger last fired
+	LastActive metav1.Time `json:"lastActive"`
+
+	// MissedEvaluations is the number of consecutive evaluations in which
+	// the trigger did not fire
+	MissedEvaluations int32 `json:"missedEvaluations,omitempty"`
+
+	// Reactivations counts how often the trigger fired again after missing
+	// evaluations
+	Reactivations int32 `json:"reactivations,omitempty"`
+
+	// Flapping is set when the trigger keeps turning on and off
+	Flapping bool `json:"flapping,omitempty"`
+	// LastNotified is when the trigger's activation or last "still firing"
+	// heartbeat was reported
+	LastNotified metav1.Time `json:"lastNotified,omitempty"`
+}
+
+// TriggerHistory holds the recent evaluations of a metric trigger
+type TriggerHistory struct {
+	// Name of the trigger
+	Name string `json:"name"`
+
+	// Samples of the trigger, oldest first
+	Samples []TriggerSample `json:"samples,omitempty"`
+}
+
+// TriggerSample is a single evaluation of a metric trigger
+type TriggerSample struct {
+	// Time of the evaluation
+	Time metav1.Time `json:"time"`
+
+	// Value the query returned
+	Value float64 `json:"value"`
+
+	// Threshold the value was compared against
+	Threshold float64 `json:"threshold"`
+
+	// Operator used for the comparison
+	Operator string `json:"operator,omitempty"`
+
+	// Triggered is set when the comparison fired the trigger
+	Triggered bool `json:"triggered,omitempty"`
+}
+
+// ActionCreationProgress reports batched creation of healing actions
+type ActionCreationProgress struct {
+	// Planned is the number of actions to create in this evaluation
+	Planned int32 `json:"planned"`
+
+	// Created is the number of actions created so far
+	Created int32 `json:"created"`
+
+	// Failed is the number of actions that could not be created
+	Failed int32 `json:"failed"`
+
+	// Batch is the last completed batch
+	Batch int32 `json:"batch"`
+
+	// Batches is the total number of batches
+	Batches int32 `json:"batches"`
+
+	// LastError is the most recent creation error
+	LastError string `json:"lastError,omitempty"`
+
+	// UpdatedAt is when the progress was last updated
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:shortName=hp
 // +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode"
 // +kubebuilder:printcolumn:name="Actions Taken",type="integer",JSONPath=".status.actionsTaken"
 // +kubebuilder:printcolumn:name="Last Action",type="date",JSONPath=".status.lastActionTime"
+// +kubebuilder:printcolumn:name="AI Analysis",type="string",JSONPath=".status.aiAnalysis.phase",priority=1
+// +kubebuilder:printcolumn:name="MTTR",type="string",JSONPath=".status.recovery.meanTimeToRecovery",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // HealingPolicy is the Schema for the healingpolicies API
@@ -0,0 +1,249 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kubeskippy/kubeskippy/pkg/cron"
+)
+
+// SetupWebhookWithManager registers the HealingPolicy defaulting and
+// validating webhooks
+func (r *HealingPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&HealingPolicyDefaulter{}).
+		WithValidator(&HealingPolicyValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-kubeskippy-io-v1alpha1-healingpolicy,mutating=true,failurePolicy=fail,sideEffects=None,groups=kubeskippy.io,resources=healingpolicies,verbs=create;update,versions=v1alpha1,name=mhealingpolicy.kb.io,admissionReviewVersions=v1
+
+// HealingPolicyDefaulter fills behavior-affecting defaults at admission
+// and records them on the policy
+type HealingPolicyDefaulter struct{}
+
+var _ admission.CustomDefaulter = &HealingPolicyDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *HealingPolicyDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	policy, ok := obj.(*HealingPolicy)
+	if !ok {
+		return fmt.Errorf("expected a HealingPolicy but got %T", obj)
+	}
+
+	applied := policy.ApplyDefaults()
+	if len(applied) > 0 {
+		logf.FromContext(ctx).V(1).Info("Applied policy defaults", "policy", policy.Name, "defaults", applied)
+	}
+	return policy.RecordAppliedDefaults(applied)
+}
+
+// +kubebuilder:webhook:path=/validate-kubeskippy-io-v1alpha1-healingpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=kubeskippy.io,resources=healingpolicies,verbs=create;update,versions=v1alpha1,name=vhealingpolicy.kb.io,admissionReviewVersions=v1
+
+// HealingPolicyValidator rejects inconsistent policies at admission
+type HealingPolicyValidator struct{}
+
+var _ admission.CustomValidator = &HealingPolicyValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *HealingPolicyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *HealingPolicyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *HealingPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *HealingPolicyValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	policy, ok := obj.(*HealingPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a HealingPolicy but got %T", obj)
+	}
+
+	var warnings admission.Warnings
+	errs := policy.Validate()
+	if len(policy.Spec.Actions) == 0 && policy.Spec.Mode != "monitor" {
+		warnings = append(warnings, "policy has no actions and will only record triggers")
+	}
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("HealingPolicy").GroupKind(), policy.Name, errs)
+	}
+	return warnings, nil
+}
+
+// Validate checks the consistency of the policy spec
+func (p *HealingPolicy) Validate() field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	triggerNames := make(map[string]bool)
+	for i, trigger := range p.Spec.Triggers {
+		path := specPath.Child("triggers").Index(i)
+		if triggerNames[trigger.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), trigger.Name))
+		}
+		triggerNames[trigger.Name] = true
+
+		if missing := missingTriggerConfig(&trigger); missing != "" {
+			errs = append(errs, field.Required(path.Child(missing), fmt.Sprintf("required for %s triggers", trigger.Type)))
+		}
+		if schedule := trigger.ScheduleTrigger; trigger.Type == "schedule" && schedule != nil {
+			if _, err := cron.Parse(schedule.Schedule); err != nil {
+				errs = append(errs, field.Invalid(path.Child("scheduleTrigger", "schedule"), schedule.Schedule, err.Error()))
+			}
+			if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+				errs = append(errs, field.Invalid(path.Child("scheduleTrigger", "timeZone"), schedule.TimeZone, "unknown time zone"))
+			}
+		}
+		if metric := trigger.MetricTrigger; trigger.Type == "metric" && metric != nil && metric.Baseline != nil {
+			if datasource := metric.EffectiveDatasource(); datasource != DatasourcePrometheus {
+				errs = append(errs, field.Invalid(path.Child("metricTrigger", "baseline"), datasource, "baselines require the prometheus datasource"))
+			}
+			if metric.Baseline.Floor < 0 {
+				errs = append(errs, field.Invalid(path.Child("metricTrigger", "baseline", "floor"), metric.Baseline.Floor, "must not be negative"))
+			}
+		}
+		if plugin := trigger.PluginTrigger; trigger.Type == "plugin" && plugin != nil && (plugin.Evaluator == "") == (plugin.Detector == "") {
+			errs = append(errs, field.Invalid(path.Child("pluginTrigger"), plugin.Evaluator+plugin.Detector, "exactly one of evaluator and detector must be set"))
+		}
+		if trigger.CooldownPeriod.Duration < 0 {
+			errs = append(errs, field.Invalid(path.Child("cooldownPeriod"), trigger.CooldownPeriod.Duration.String(), "must not be negative"))
+		}
+	}
+
+	actionNames := make(map[string]bool)
+	for i, action := range p.Spec.Actions {
+		path := specPath.Child("actions").Index(i)
+		if actionNames[action.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), action.Name))
+		}
+		actionNames[action.Name] = true
+
+		if env := action.Execution; env != nil && env.TargetNode && (len(env.NodeSelector) > 0 || env.Affinity != nil) {
+			errs = append(errs, field.Invalid(path.Child("execution", "targetNode"), true, "cannot be combined with nodeSelector or affinity"))
+		}
+
+		if action.TemplateRef != "" {
+			continue
+		}
+		switch {
+		case action.Type == "scale" && action.ScaleAction == nil:
+			errs = append(errs, field.Required(path.Child("scaleAction"), "required for scale actions"))
+		case action.Type == "patch" && action.PatchAction == nil:
+			errs = append(errs, field.Required(path.Child("patchAction"), "required for patch actions"))
+		case action.Type == "hibernate" && action.HibernateAction == nil:
+			errs = append(errs, field.Required(path.Child("hibernateAction"), "required for hibernate actions"))
+		case action.Type == "hibernate" && action.HibernateAction.Duration.Duration <= 0 && action.HibernateAction.ResumeWhen == nil:
+			errs = append(errs, field.Required(path.Child("hibernateAction", "duration"), "hibernated workloads need a duration or resumeWhen condition"))
+		case action.Type == "exec" && action.ExecAction == nil:
+			errs = append(errs, field.Required(path.Child("execAction"), "required for exec actions"))
+		case action.Type == "resize" && action.ResizeAction == nil:
+			errs = append(errs, field.Required(path.Child("resizeAction"), "required for resize actions"))
+		case action.Type == "taint" && action.TaintAction == nil:
+			errs = append(errs, field.Required(path.Child("taintAction"), "required for taint actions"))
+		case action.Type == "drain" && !action.RequiresApproval:
+			errs = append(errs, field.Required(path.Child("requiresApproval"), "drain actions need approval"))
+		case action.Type == "chain" && len(action.Steps) == 0:
+			errs = append(errs, field.Required(path.Child("steps"), "required for chain actions"))
+		}
+		errs = append(errs, validateSteps(path.Child("steps"), action.Steps)...)
+	}
+
+	dependencies := make(map[string]bool)
+	for i, dep := range p.Spec.DependsOn {
+		path := specPath.Child("dependsOn").Index(i)
+		namespace := dep.Namespace
+		if namespace == "" {
+			namespace = p.Namespace
+		}
+		key := namespace + "/" + dep.Name
+		switch {
+		case dep.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "dependency name is required"))
+		case dep.Name == p.Name && namespace == p.Namespace:
+			errs = append(errs, field.Invalid(path.Child("name"), dep.Name, "a policy cannot depend on itself"))
+		case dependencies[key]:
+			errs = append(errs, field.Duplicate(path, key))
+		}
+		dependencies[key] = true
+	}
+
+	if p.Spec.ActionTimeout != nil && p.Spec.ActionTimeout.Duration <= 0 {
+		errs = append(errs, field.Invalid(specPath.Child("actionTimeout"), p.Spec.ActionTimeout.Duration.String(), "must be positive"))
+	}
+	if retry := p.Spec.RetryPolicy; retry != nil {
+		if retry.MaxAttempts < 0 {
+			errs = append(errs, field.Invalid(specPath.Child("retryPolicy", "maxAttempts"), retry.MaxAttempts, "must not be negative"))
+		}
+		if retry.BackoffMultiplier != 0 && retry.BackoffMultiplier < 1 {
+			errs = append(errs, field.Invalid(specPath.Child("retryPolicy", "backoffMultiplier"), retry.BackoffMultiplier, "must be at least 1"))
+		}
+	}
+
+	return errs
+}
+
+// validateSteps checks that the steps of a chain action have unique names
+// and the configuration their type requires
+func validateSteps(path *field.Path, steps []ActionStep) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]bool)
+	for i, step := range steps {
+		path := path.Index(i)
+		if names[step.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), step.Name))
+		}
+		names[step.Name] = true
+
+		switch {
+		case step.Type == "scale" && step.ScaleAction == nil:
+			errs = append(errs, field.Required(path.Child("scaleAction"), "required for scale steps"))
+		case step.Type == "patch" && step.PatchAction == nil:
+			errs = append(errs, field.Required(path.Child("patchAction"), "required for patch steps"))
+		case step.Type == "exec" && step.ExecAction == nil:
+			errs = append(errs, field.Required(path.Child("execAction"), "required for exec steps"))
+		case step.Type == "resize" && step.ResizeAction == nil:
+			errs = append(errs, field.Required(path.Child("resizeAction"), "required for resize steps"))
+		case step.Type == "diagnostics" && len(step.Hooks) == 0:
+			errs = append(errs, field.Required(path.Child("hooks"), "required for diagnostics steps"))
+		case step.Type == "verify" && step.Condition == nil:
+			errs = append(errs, field.Required(path.Child("condition"), "required for verify steps"))
+		}
+	}
+	return errs
+}
+
+// missingTriggerConfig returns the field a trigger of its type requires
+// but does not set
+func missingTriggerConfig(trigger *HealingTrigger) string {
+	switch {
+	case trigger.Type == "metric" && trigger.MetricTrigger == nil:
+		return "metricTrigger"
+	case trigger.Type == "event" && trigger.EventTrigger == nil:
+		return "eventTrigger"
+	case trigger.Type == "condition" && trigger.ConditionTrigger == nil:
+		return "conditionTrigger"
+	case trigger.Type == "log" && trigger.LogTrigger == nil:
+		return "logTrigger"
+	case trigger.Type == "schedule" && trigger.ScheduleTrigger == nil:
+		return "scheduleTrigger"
+	case trigger.Type == "plugin" && trigger.PluginTrigger == nil:
+		return "pluginTrigger"
+	}
+	return ""
+}
@@ -0,0 +1,221 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHealingPolicyDefaulter(t *testing.T) {
+	policy := &HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "default"},
+		Spec: HealingPolicySpec{
+			Triggers: []HealingTrigger{
+				{Name: "restarts", Type: "restartStorm", CooldownPeriod: metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+
+	require.NoError(t, (&HealingPolicyDefaulter{}).Default(context.Background(), policy))
+
+	assert.Equal(t, DefaultMode, policy.Spec.Mode)
+	assert.Equal(t, time.Minute, policy.Spec.Triggers[0].CooldownPeriod.Duration)
+	assert.Equal(t, int32(DefaultClearAfterEvaluations), policy.Spec.Triggers[0].ClearAfterEvaluations)
+	require.NotNil(t, policy.Spec.ActionTimeout)
+	assert.Equal(t, DefaultActionTimeout, policy.Spec.ActionTimeout.Duration)
+	require.NotNil(t, policy.Spec.RetryPolicy)
+	assert.Equal(t, int32(DefaultRetryMaxAttempts), policy.Spec.RetryPolicy.MaxAttempts)
+
+	recorded := policy.AppliedDefaults()
+	assert.Equal(t, PolicyDefaults()[DefaultKeyActionTimeout], recorded[DefaultKeyActionTimeout])
+	assert.NotContains(t, recorded, DefaultKeyCooldownPeriod, "user-set fields are not recorded")
+
+	// Recorded values are kept when defaults are applied again
+	policy.Annotations[AnnotationAppliedDefaults] = `{"actionTimeout":"5m0s"}`
+	policy.Spec.Triggers = append(policy.Spec.Triggers, HealingTrigger{Name: "other", Type: "restartStorm"})
+	require.NoError(t, (&HealingPolicyDefaulter{}).Default(context.Background(), policy))
+
+	recorded = policy.AppliedDefaults()
+	assert.Equal(t, "5m0s", recorded[DefaultKeyActionTimeout])
+	assert.Equal(t, PolicyDefaults()[DefaultKeyCooldownPeriod], recorded[DefaultKeyCooldownPeriod])
+}
+
+func TestHealingPolicyValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        HealingPolicySpec
+		expectError []string
+	}{
+		{
+			name: "valid policy",
+			spec: HealingPolicySpec{
+				Mode:     "automatic",
+				Triggers: []HealingTrigger{{Name: "cpu", Type: "metric", MetricTrigger: &MetricTrigger{Query: "up", Operator: ">"}}},
+				Actions:  []HealingActionTemplate{{Name: "restart", Type: "restart"}},
+			},
+		},
+		{
+			name: "duplicate names",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{
+					{Name: "storm", Type: "restartStorm"},
+					{Name: "storm", Type: "restartStorm"},
+				},
+				Actions: []HealingActionTemplate{
+					{Name: "restart", Type: "restart"},
+					{Name: "restart", Type: "restart"},
+				},
+			},
+			expectError: []string{"spec.triggers[1].name", "spec.actions[1].name"},
+		},
+		{
+			name: "missing type specific config",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{{Name: "cpu", Type: "metric"}},
+				Actions:  []HealingActionTemplate{{Name: "scale", Type: "scale"}},
+			},
+			expectError: []string{"spec.triggers[0].metricTrigger", "spec.actions[0].scaleAction"},
+		},
+		{
+			name: "baseline outside prometheus",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{
+					{Name: "errors", Type: "metric", MetricTrigger: &MetricTrigger{Query: "rate(errors_total[5m])", Operator: ">", Threshold: 2, Baseline: &MetricBaseline{Days: 7}}},
+					{Name: "queue", Type: "metric", MetricTrigger: &MetricTrigger{Query: "custom:queue_depth", Operator: ">", Baseline: &MetricBaseline{}}},
+					{Name: "latency", Type: "metric", MetricTrigger: &MetricTrigger{Query: "rate(latency_sum[5m])", Operator: ">", Baseline: &MetricBaseline{Floor: -1}}},
+				},
+			},
+			expectError: []string{"spec.triggers[1].metricTrigger.baseline", "spec.triggers[2].metricTrigger.baseline.floor"},
+		},
+		{
+			name: "hibernate without resume",
+			spec: HealingPolicySpec{
+				Actions: []HealingActionTemplate{
+					{Name: "stop", Type: "hibernate"},
+					{Name: "pause", Type: "hibernate", HibernateAction: &HibernateAction{}},
+					{Name: "nap", Type: "hibernate", HibernateAction: &HibernateAction{Duration: metav1.Duration{Duration: time.Hour}}},
+				},
+			},
+			expectError: []string{"spec.actions[0].hibernateAction", "spec.actions[1].hibernateAction.duration"},
+		},
+		{
+			name: "taint without configuration",
+			spec: HealingPolicySpec{
+				Actions: []HealingActionTemplate{
+					{Name: "suspect", Type: "taint"},
+					{Name: "isolate", Type: "taint", TaintAction: &TaintAction{Effect: "NoSchedule"}},
+				},
+			},
+			expectError: []string{"spec.actions[0].taintAction"},
+		},
+		{
+			name: "chain without steps",
+			spec: HealingPolicySpec{
+				Actions: []HealingActionTemplate{
+					{Name: "recover", Type: "chain"},
+					{Name: "restart-and-verify", Type: "chain", Steps: []ActionStep{
+						{Name: "restart", Type: "restart"},
+						{Name: "restart", Type: "scale"},
+						{Name: "verify", Type: "verify"},
+					}},
+				},
+			},
+			expectError: []string{"spec.actions[0].steps", "spec.actions[1].steps[1].name", "spec.actions[1].steps[1].scaleAction", "spec.actions[1].steps[2].condition"},
+		},
+		{
+			name: "invalid dependencies",
+			spec: HealingPolicySpec{
+				DependsOn: []PolicyDependency{
+					{Name: "test-policy"},
+					{Name: "node-health"},
+					{Name: "node-health", Namespace: "default"},
+					{Name: "test-policy", Namespace: "other"},
+					{},
+				},
+			},
+			expectError: []string{"spec.dependsOn[0].name", "spec.dependsOn[2]", "spec.dependsOn[4].name"},
+		},
+		{
+			name: "template provides action config",
+			spec: HealingPolicySpec{
+				Actions: []HealingActionTemplate{{Name: "scale", Type: "scale", TemplateRef: "bounded-scale"}},
+			},
+		},
+		{
+			name: "invalid schedule",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{
+					{Name: "nightly", Type: "schedule", ScheduleTrigger: &ScheduleTrigger{Schedule: "0 25 * * *", TimeZone: "Mars/Olympus"}},
+					{Name: "weekly", Type: "schedule"},
+				},
+			},
+			expectError: []string{"spec.triggers[0].scheduleTrigger.schedule", "spec.triggers[0].scheduleTrigger.timeZone", "spec.triggers[1].scheduleTrigger"},
+		},
+		{
+			name: "valid schedule",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{{Name: "nightly", Type: "schedule", ScheduleTrigger: &ScheduleTrigger{Schedule: "@daily", TimeZone: "Europe/Berlin"}}},
+			},
+		},
+		{
+			name: "invalid plugin triggers",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{
+					{Name: "missing", Type: "plugin"},
+					{Name: "none", Type: "plugin", PluginTrigger: &PluginTrigger{}},
+					{Name: "both", Type: "plugin", PluginTrigger: &PluginTrigger{Evaluator: "queue-depth", Detector: "gc-thrash"}},
+				},
+			},
+			expectError: []string{"spec.triggers[0].pluginTrigger", "spec.triggers[1].pluginTrigger", "spec.triggers[2].pluginTrigger"},
+		},
+		{
+			name: "valid plugin trigger",
+			spec: HealingPolicySpec{
+				Triggers: []HealingTrigger{{Name: "queue", Type: "plugin", PluginTrigger: &PluginTrigger{Evaluator: "queue-depth", Config: map[string]string{"max": "100"}}}},
+			},
+		},
+		{
+			name: "invalid timeout and retry policy",
+			spec: HealingPolicySpec{
+				ActionTimeout: &metav1.Duration{},
+				RetryPolicy:   &RetryPolicy{MaxAttempts: -1, BackoffMultiplier: 0.5},
+			},
+			expectError: []string{"spec.actionTimeout", "spec.retryPolicy.maxAttempts", "spec.retryPolicy.backoffMultiplier"},
+		},
+		{
+			name: "helper pods pinned to the target node and a selector",
+			spec: HealingPolicySpec{
+				Actions: []HealingActionTemplate{
+					{Name: "dump", Type: "exec", ExecAction: &ExecAction{Command: []string{"jcmd"}},
+						Execution: &ExecutionEnvironment{Image: "jdk", TargetNode: true, NodeSelector: map[string]string{"pool": "debug"}}},
+					{Name: "trace", Type: "exec", ExecAction: &ExecAction{Command: []string{"perf"}},
+						Execution: &ExecutionEnvironment{Image: "perf", TargetNode: true}},
+				},
+			},
+			expectError: []string{"spec.actions[0].execution.targetNode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &HealingPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			_, err := (&HealingPolicyValidator{}).ValidateCreate(context.Background(), policy)
+			if len(tt.expectError) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, fieldPath := range tt.expectError {
+				assert.Contains(t, err.Error(), fieldPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// HealingPolicyBundleSpec defines the desired state of HealingPolicyBundle
+type HealingPolicyBundleSpec struct {
+	// Variables shared by the bundled policies. A policy references a
+	// variable as $(name); a string that is only a reference takes the
+	// variable's value as JSON when it parses, so numbers and lists can be
+	// shared too.
+	Variables []BundleVariable `json:"variables,omitempty"`
+
+	// Policies of the bundle, in order. Each policy depends on the one
+	// before it, so they are evaluated in this order every cycle.
+	// +kubebuilder:validation:MinItems=1
+	Policies []BundledPolicy `json:"policies"`
+}
+
+// BundleVariable is a value shared by the policies of a bundle
+type BundleVariable struct {
+	// Name of the variable
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Value of the variable
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom reads the value when the bundle is reconciled
+	ValueFrom *BundleVariableSource `json:"valueFrom,omitempty"`
+}
+
+// BundleVariableSource is where a variable's value is read from
+type BundleVariableSource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap in the bundle's namespace
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// BundledPolicy is a HealingPolicy managed by a bundle
+type BundledPolicy struct {
+	// Name of the policy within the bundle; the HealingPolicy is named
+	// <bundle>-<name>
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Labels added to the HealingPolicy
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Spec of the HealingPolicy, which may reference the bundle's variables
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Spec runtime.RawExtension `json:"spec"`
+}
+
+// HealingPolicyBundleStatus defines the observed state of HealingPolicyBundle
+type HealingPolicyBundleStatus struct {
+	// Policies are the HealingPolicies of the bundle, in order
+	Policies []string `json:"policies,omitempty"`
+
+	// LastResolved is when the variables were last resolved and the
+	// policies updated
+	LastResolved *metav1.Time `json:"lastResolved,omitempty"`
+
+	// Conditions of the bundle
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration for tracking updates
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=hpb
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HealingPolicyBundle is the Schema for the healingpolicybundles API
+type HealingPolicyBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HealingPolicyBundleSpec   `json:"spec,omitempty"`
+	Status HealingPolicyBundleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HealingPolicyBundleList contains a list of HealingPolicyBundle
+type HealingPolicyBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HealingPolicyBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HealingPolicyBundle{}, &HealingPolicyBundleList{})
+}
@@ -0,0 +1,232 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealingReportSpec defines the desired state of HealingReport
+type HealingReportSpec struct {
+	// PolicyNames limits the report to these policies (empty means all
+	// policies in the report's namespace)
+	PolicyNames []string `json:"policyNames,omitempty"`
+
+	// Period covered by each report; the report is regenerated every period
+	// +kubebuilder:default="168h"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Period metav1.Duration `json:"period,omitempty"`
+
+	// TopIssues is the number of recurring issues listed per policy
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=0
+	TopIssues int32 `json:"topIssues,omitempty"`
+
+	// Export writes the report to a ConfigMap
+	Export *ReportExport `json:"export,omitempty"`
+
+	// AITuning asks the AI analyzer for further tuning suggestions from the
+	// firing history of each policy's triggers
+	AITuning bool `json:"aiTuning,omitempty"`
+}
+
+// ReportExport configures exporting a report for ops reviews
+type ReportExport struct {
+	// Formats to export
+	// +kubebuilder:validation:items:Enum=markdown;json
+	// +kubebuilder:validation:MinItems=1
+	Formats []string `json:"formats"`
+
+	// ConfigMapName receiving the export; defaults to "<report>-export"
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// HealingReportStatus defines the observed state of HealingReport
+type HealingReportStatus struct {
+	// PeriodStart of the latest report
+	PeriodStart *metav1.Time `json:"periodStart,omitempty"`
+
+	// PeriodEnd of the latest report
+	PeriodEnd *metav1.Time `json:"periodEnd,omitempty"`
+
+	// NextGeneration is when the report will be regenerated
+	NextGeneration *metav1.Time `json:"nextGeneration,omitempty"`
+
+	// Summary across all reported policies
+	Summary PolicyReport `json:"summary,omitempty"`
+
+	// Policies contains the per-policy breakdown
+	Policies []PolicyReport `json:"policies,omitempty"`
+
+	// ExportedTo is the ConfigMap holding the latest export
+	ExportedTo string `json:"exportedTo,omitempty"`
+
+	// Conditions of the report
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration for tracking updates
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// PolicyReport summarizes healing effectiveness for a policy
+type PolicyReport struct {
+	// Name of the policy; empty for the summary
+	Name string `json:"name,omitempty"`
+
+	// TriggersFired counts the actions created per trigger
+	TriggersFired map[string]int32 `json:"triggersFired,omitempty"`
+
+	// ActionsByType counts the actions per action type
+	ActionsByType map[string]int32 `json:"actionsByType,omitempty"`
+
+	// ActionsTotal is the number of actions in the period
+	ActionsTotal int32 `json:"actionsTotal"`
+
+	// Succeeded actions
+	Succeeded int32 `json:"succeeded"`
+
+	// Failed actions
+	Failed int32 `json:"failed"`
+
+	// DryRun actions, which are excluded from the success rate
+	DryRun int32 `json:"dryRun,omitempty"`
+
+	// SuccessRatePercent of completed, non-dry-run actions
+	SuccessRatePercent int32 `json:"successRatePercent"`
+
+	// MTTR is the mean time from action creation to successful completion
+	MTTR metav1.Duration `json:"mttr,omitempty"`
+
+	// AIDriven actions recommended by AI analysis
+	AIDriven int32 `json:"aiDriven"`
+
+	// Traditional actions created by threshold triggers
+	Traditional int32 `json:"traditional"`
+
+	// TopIssues are the most frequently healed trigger/target pairs
+	TopIssues []RecurringIssue `json:"topIssues,omitempty"`
+
+	// Suggestions for tuning the policy's triggers, based on their firing
+	// history
+	Suggestions []TuningSuggestion `json:"suggestions,omitempty"`
+
+	// DryRunComparison compares the dry-run actions against what happened
+	// to their targets without them
+	DryRunComparison *DryRunComparison `json:"dryRunComparison,omitempty"`
+}
+
+// Recommendations on enabling automatic mode for a dry-run policy
+const (
+	DryRunRecommendationEnableAutomatic  = "EnableAutomatic"
+	DryRunRecommendationKeepDryRun       = "KeepDryRun"
+	DryRunRecommendationInsufficientData = "InsufficientData"
+)
+
+// DryRunComparison is the "healing would have helped/hurt" breakdown of
+// the observed dry-run actions in a report period
+type DryRunComparison struct {
+	// Outcomes counts the observed dry-run actions per outcome
+	Outcomes map[string]int32 `json:"outcomes,omitempty"`
+
+	// WouldHaveHelped counts targets that kept failing or needed someone
+	// to intervene
+	WouldHaveHelped int32 `json:"wouldHaveHelped"`
+
+	// WouldHaveHurt counts targets that recovered on their own, which the
+	// action would only have disrupted
+	WouldHaveHurt int32 `json:"wouldHaveHurt"`
+
+	// Inconclusive counts targets or policies deleted while observed
+	Inconclusive int32 `json:"inconclusive,omitempty"`
+
+	// HelpedPercent of the conclusive outcomes that healing would have
+	// helped
+	HelpedPercent int32 `json:"helpedPercent"`
+
+	// Recommendation on enabling automatic mode
+	// +kubebuilder:validation:Enum=EnableAutomatic;KeepDryRun;InsufficientData
+	Recommendation string `json:"recommendation"`
+
+	// Reason for the recommendation
+	Reason string `json:"reason,omitempty"`
+}
+
+// RecurringIssue is a trigger repeatedly firing for the same target
+type RecurringIssue struct {
+	// Trigger that fired
+	Trigger string `json:"trigger"`
+
+	// Target as Kind/Namespace/Name
+	Target string `json:"target"`
+
+	// Count of actions for this trigger and target
+	Count int32 `json:"count"`
+}
+
+// Firing patterns tuning suggestions are made for
+const (
+	TuningPatternAlwaysFiring = "AlwaysFiring"
+	TuningPatternNeverFiring  = "NeverFiring"
+	TuningPatternFlapping     = "Flapping"
+)
+
+// Sources of tuning suggestions
+const (
+	TuningSourceRules = "rules"
+	TuningSourceAI    = "ai"
+)
+
+// TuningSuggestion proposes a change to a trigger based on how it fired
+type TuningSuggestion struct {
+	// Trigger the suggestion is for; empty for AI suggestions about the
+	// whole policy
+	Trigger string `json:"trigger,omitempty"`
+
+	// Pattern observed in the trigger's firing history
+	// +kubebuilder:validation:Enum=AlwaysFiring;NeverFiring;Flapping
+	Pattern string `json:"pattern,omitempty"`
+
+	// Field of the trigger to change, e.g. metricTrigger.threshold
+	Field string `json:"field,omitempty"`
+
+	// Current value of the field
+	Current string `json:"current,omitempty"`
+
+	// Suggested value of the field
+	Suggested string `json:"suggested,omitempty"`
+
+	// Reason for the suggestion, with the evidence it is based on
+	Reason string `json:"reason"`
+
+	// Source of the suggestion
+	// +kubebuilder:validation:Enum=rules;ai
+	Source string `json:"source"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=hr
+// +kubebuilder:printcolumn:name="Actions",type="integer",JSONPath=".status.summary.actionsTotal"
+// +kubebuilder:printcolumn:name="Success %",type="integer",JSONPath=".status.summary.successRatePercent"
+// +kubebuilder:printcolumn:name="Period End",type="date",JSONPath=".status.periodEnd"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HealingReport is the Schema for the healingreports API
+type HealingReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HealingReportSpec   `json:"spec,omitempty"`
+	Status HealingReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HealingReportList contains a list of HealingReport
+type HealingReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HealingReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HealingReport{}, &HealingReportList{})
+}
@@ -0,0 +1,102 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorHealthSpec defines the desired state of OperatorHealth
+type OperatorHealthSpec struct {
+	// ForceSafeMode keeps the operator in safe mode regardless of its health
+	ForceSafeMode bool `json:"forceSafeMode,omitempty"`
+}
+
+// OperatorHealthStatus is the operator's view of its own health, maintained
+// by the watchdog
+type OperatorHealthStatus struct {
+	// SafeMode is true while new healing actions are forced to dry-run
+	SafeMode bool `json:"safeMode"`
+
+	// SafeModeSince is when safe mode was entered
+	SafeModeSince *metav1.Time `json:"safeModeSince,omitempty"`
+
+	// SafeModeReason explains why safe mode was entered
+	SafeModeReason string `json:"safeModeReason,omitempty"`
+
+	// Checks are the results of the latest health checks
+	Checks []HealthCheckStatus `json:"checks,omitempty"`
+
+	// Remediations are the most recent self-remediations, newest last
+	Remediations []SelfRemediation `json:"remediations,omitempty"`
+
+	// LastCheckTime is when the checks last ran
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Conditions of the operator
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// HealthCheckStatus is the result of one watchdog health check
+type HealthCheckStatus struct {
+	// Name of the check
+	Name string `json:"name"`
+
+	// Healthy is false while the check fails
+	Healthy bool `json:"healthy"`
+
+	// Message describing the result
+	Message string `json:"message,omitempty"`
+
+	// ConsecutiveFailures of the check
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// LastTransitionTime is when Healthy last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// SelfRemediation is a corrective action the watchdog took on the operator
+type SelfRemediation struct {
+	// Time of the remediation
+	Time metav1.Time `json:"time"`
+
+	// Check that failed
+	Check string `json:"check"`
+
+	// Action taken, such as "reset-ai-coordinator" or "enter-safe-mode"
+	Action string `json:"action"`
+
+	// Succeeded is false when the action returned an error
+	Succeeded bool `json:"succeeded"`
+
+	// Message describing the outcome
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=oh
+// +kubebuilder:printcolumn:name="Safe Mode",type="boolean",JSONPath=".status.safeMode"
+// +kubebuilder:printcolumn:name="Last Check",type="date",JSONPath=".status.lastCheckTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OperatorHealth is the Schema for the operatorhealths API. The watchdog
+// maintains a single OperatorHealth in the operator's namespace.
+type OperatorHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorHealthSpec   `json:"spec,omitempty"`
+	Status OperatorHealthStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorHealthList contains a list of OperatorHealth
+type OperatorHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorHealth `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorHealth{}, &OperatorHealthList{})
+}
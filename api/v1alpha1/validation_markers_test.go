@@ -0,0 +1,314 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldMarkers are the validation markers of a struct field
+type fieldMarkers struct {
+	durationField bool
+	enum          []string
+	itemsEnum     []string
+	pattern       string
+	minimum       *float64
+	maximum       *float64
+	minLength     *int
+	minItems      *int
+}
+
+// parseValidationMarkers reads the kubebuilder validation markers of every
+// struct field in the package's type files, keyed by type and field name
+func parseValidationMarkers(t *testing.T) map[string]map[string]fieldMarkers {
+	t.Helper()
+
+	files, err := filepath.Glob("*_types.go")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	markers := map[string]map[string]fieldMarkers{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		require.NoError(t, err)
+
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			structType, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			fields := map[string]fieldMarkers{}
+			for _, field := range structType.Fields.List {
+				if len(field.Names) == 0 {
+					continue
+				}
+				m := fieldMarkers{durationField: isDurationType(field.Type)}
+				if field.Doc != nil {
+					for _, comment := range field.Doc.List {
+						parseMarker(t, &m, strings.TrimSpace(strings.TrimPrefix(comment.Text, "//")))
+					}
+				}
+				fields[field.Names[0].Name] = m
+			}
+			markers[spec.Name.Name] = fields
+			return false
+		})
+	}
+	return markers
+}
+
+// isDurationType reports whether the field is a metav1.Duration or a
+// pointer to one
+func isDurationType(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "metav1" && selector.Sel.Name == "Duration"
+}
+
+// parseMarker records a single +kubebuilder:validation marker
+func parseMarker(t *testing.T, m *fieldMarkers, line string) {
+	name, value, ok := strings.Cut(strings.TrimPrefix(line, "+kubebuilder:validation:"), "=")
+	if !ok || !strings.HasPrefix(line, "+kubebuilder:validation:") {
+		return
+	}
+
+	number := func() *float64 {
+		n, err := strconv.ParseFloat(value, 64)
+		require.NoError(t, err, line)
+		return &n
+	}
+	integer := func() *int {
+		n, err := strconv.Atoi(value)
+		require.NoError(t, err, line)
+		return &n
+	}
+	switch name {
+	case "Enum":
+		m.enum = parseEnum(value)
+	case "items:Enum":
+		m.itemsEnum = parseEnum(value)
+	case "Pattern":
+		m.pattern = strings.Trim(value, "`")
+	case "Minimum":
+		m.minimum = number()
+	case "Maximum":
+		m.maximum = number()
+	case "MinLength":
+		m.minLength = integer()
+	case "MinItems":
+		m.minItems = integer()
+	}
+}
+
+// parseEnum splits enum values, which may be quoted
+func parseEnum(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ";") {
+		values = append(values, strings.Trim(v, `"`))
+	}
+	return values
+}
+
+func TestValidationMarkers_Durations(t *testing.T) {
+	markers := parseValidationMarkers(t)
+
+	// Status durations are written by the operator only, so only the types
+	// of spec fields are checked
+	specTypes := map[string]bool{}
+	for _, spec := range []interface{}{HealingPolicySpec{}, HealingActionSpec{}, AIDecisionSpec{}, HealingReportSpec{}} {
+		collectStructTypes(reflect.TypeOf(spec), specTypes)
+	}
+
+	var pattern string
+	for typeName, fields := range markers {
+		if !specTypes[typeName] {
+			continue
+		}
+		for fieldName, m := range fields {
+			if !m.durationField {
+				continue
+			}
+			if assert.NotEmpty(t, m.pattern, "%s.%s has no duration pattern", typeName, fieldName) {
+				if pattern == "" {
+					pattern = m.pattern
+				}
+				assert.Equal(t, pattern, m.pattern, "%s.%s uses a different duration pattern", typeName, fieldName)
+			}
+		}
+	}
+	require.NotEmpty(t, pattern)
+
+	durations := regexp.MustCompile(pattern)
+	for _, value := range []string{"0", "0s", "30s", "5m", "1h30m", "1.5h", "300ms", "10µs", "2h45m30.5s"} {
+		_, err := time.ParseDuration(value)
+		require.NoError(t, err, value)
+		assert.True(t, durations.MatchString(value), "%q should be accepted", value)
+	}
+	for _, value := range []string{"", "5", "-1m", "1d", "5 m", "five minutes", "1h-30m", "m"} {
+		assert.False(t, durations.MatchString(value), "%q should be rejected", value)
+	}
+}
+
+// collectStructTypes records the names of the struct types reachable from t
+func collectStructTypes(t reflect.Type, types map[string]bool) {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		collectStructTypes(t.Elem(), types)
+	case reflect.Struct:
+		if types[t.Name()] || t.PkgPath() != reflect.TypeOf(HealingPolicy{}).PkgPath() {
+			return
+		}
+		types[t.Name()] = true
+		for i := 0; i < t.NumField(); i++ {
+			collectStructTypes(t.Field(i).Type, types)
+		}
+	}
+}
+
+func TestValidationMarkers_Enums(t *testing.T) {
+	markers := parseValidationMarkers(t)
+
+	// Fields listing the same values must stay in sync
+	assert.Equal(t, markers["HealingTrigger"]["Type"].enum, markers["SeverityMapping"]["TriggerType"].enum)
+	assert.Equal(t, markers["HealingActionTemplate"]["Type"].enum, markers["SeverityRule"]["AllowedActions"].itemsEnum)
+	assert.Equal(t, markers["ConditionTrigger"]["Status"].enum, markers["ResumeCondition"]["Status"].enum)
+	assert.Equal(t, []string{"True", "False", "Unknown"}, markers["ConditionTrigger"]["Status"].enum)
+	assert.Equal(t, []string{"Normal", "Warning"}, markers["EventTrigger"]["Type"].enum)
+	assert.Equal(t, []string{">", "<", ">=", "<="}, markers["MetricTrigger"]["Operator"].enum)
+	assert.Equal(t, markers["MetricTrigger"]["Operator"].enum, markers["MetricCriterion"]["Operator"].enum)
+	assert.Equal(t, markers["ConditionTrigger"]["Status"].enum, markers["TargetCondition"]["Status"].enum)
+
+	bounds := markers["AIDecisionSpec"]["Confidence"]
+	require.NotNil(t, bounds.minimum)
+	require.NotNil(t, bounds.maximum)
+	assert.Equal(t, 0.0, *bounds.minimum)
+	assert.Equal(t, 1.0, *bounds.maximum)
+}
+
+// validateMarkers checks the set fields of v against the markers of their
+// struct type, recursing into nested structs, pointers, slices and maps
+func validateMarkers(t *testing.T, markers map[string]map[string]fieldMarkers, path string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			validateMarkers(t, markers, path, v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			validateMarkers(t, markers, path+"["+strconv.Itoa(i)+"]", v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			validateMarkers(t, markers, path+"["+key.String()+"]", v.MapIndex(key))
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(metav1.Duration{}) || v.Type() == reflect.TypeOf(metav1.Time{}) {
+			return
+		}
+		fields := markers[v.Type().Name()]
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			value := v.Field(i)
+			fieldPath := path + "." + field.Name
+			if !value.IsZero() {
+				checkField(t, fieldPath, fields[field.Name], value)
+			}
+			validateMarkers(t, markers, fieldPath, value)
+		}
+	}
+}
+
+// checkField checks a set field against its markers
+func checkField(t *testing.T, path string, m fieldMarkers, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		if m.enum != nil {
+			assert.Contains(t, m.enum, value.String(), "%s is not one of the allowed values", path)
+		}
+		if m.minLength != nil {
+			assert.GreaterOrEqual(t, len(value.String()), *m.minLength, path)
+		}
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		var n float64
+		if value.CanInt() {
+			n = float64(value.Int())
+		} else {
+			n = value.Float()
+		}
+		if m.minimum != nil {
+			assert.GreaterOrEqual(t, n, *m.minimum, path)
+		}
+		if m.maximum != nil {
+			assert.LessOrEqual(t, n, *m.maximum, path)
+		}
+	case reflect.Slice:
+		if m.minItems != nil {
+			assert.GreaterOrEqual(t, value.Len(), *m.minItems, path)
+		}
+		if m.itemsEnum != nil && value.Type().Elem().Kind() == reflect.String {
+			for i := 0; i < value.Len(); i++ {
+				assert.Contains(t, m.itemsEnum, value.Index(i).String(), "%s[%d] is not one of the allowed values", path, i)
+			}
+		}
+	}
+}
+
+func TestValidationMarkers_SamplePolicies(t *testing.T) {
+	markers := parseValidationMarkers(t)
+
+	files, err := filepath.Glob("../../config/samples/*.yaml")
+	require.NoError(t, err)
+	demos, err := filepath.Glob("../../demo/policies/*.yaml")
+	require.NoError(t, err)
+	files = append(files, demos...)
+	require.NotEmpty(t, files)
+
+	validated := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		for i, doc := range bytes.Split(data, []byte("\n---")) {
+			var meta metav1.TypeMeta
+			if err := yaml.Unmarshal(doc, &meta); err != nil || meta.Kind != "HealingPolicy" {
+				continue
+			}
+			policy := &HealingPolicy{}
+			if err := yaml.UnmarshalStrict(doc, policy); err != nil {
+				t.Logf("%s document %d: %v", file, i, err)
+				continue
+			}
+			validateMarkers(t, markers, filepath.Base(file)+":"+policy.Name, reflect.ValueOf(policy.Spec))
+			validated++
+		}
+	}
+	assert.NotZero(t, validated)
+}
@@ -21,126 +21,347 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ActionResult) DeepCopyInto(out *ActionResult) {
+func (in *AIAnalysisStatus) DeepCopyInto(out *AIAnalysisStatus) {
 	*out = *in
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.Changes != nil {
-		in, out := &in.Changes, &out.Changes
-		*out = make([]ResourceChange, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIAnalysisStatus.
+func (in *AIAnalysisStatus) DeepCopy() *AIAnalysisStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AIAnalysisStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIDecision) DeepCopyInto(out *AIDecision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIDecision.
+func (in *AIDecision) DeepCopy() *AIDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(AIDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AIDecision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIDecisionList) DeepCopyInto(out *AIDecisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AIDecision, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionResult.
-func (in *ActionResult) DeepCopy() *ActionResult {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIDecisionList.
+func (in *AIDecisionList) DeepCopy() *AIDecisionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ActionResult)
+	out := new(AIDecisionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AIDecisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApprovalStatus) DeepCopyInto(out *ApprovalStatus) {
+func (in *AIDecisionSpec) DeepCopyInto(out *AIDecisionSpec) {
 	*out = *in
-	if in.ApprovedAt != nil {
-		in, out := &in.ApprovedAt, &out.ApprovedAt
+	out.PolicyRef = in.PolicyRef
+	out.TargetResource = in.TargetResource
+	if in.ReasoningSteps != nil {
+		in, out := &in.ReasoningSteps, &out.ReasoningSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Alternatives != nil {
+		in, out := &in.Alternatives, &out.Alternatives
+		*out = make([]DecisionAlternative, len(*in))
+		copy(*out, *in)
+	}
+	out.TTL = in.TTL
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIDecisionSpec.
+func (in *AIDecisionSpec) DeepCopy() *AIDecisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AIDecisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AIDecisionStatus) DeepCopyInto(out *AIDecisionStatus) {
+	*out = *in
+	if in.Success != nil {
+		in, out := &in.Success, &out.Success
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalStatus.
-func (in *ApprovalStatus) DeepCopy() *ApprovalStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIDecisionStatus.
+func (in *AIDecisionStatus) DeepCopy() *AIDecisionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ApprovalStatus)
+	out := new(AIDecisionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConditionTrigger) DeepCopyInto(out *ConditionTrigger) {
+func (in *AIProfile) DeepCopyInto(out *AIProfile) {
 	*out = *in
-	out.Duration = in.Duration
+	if in.AnalysisInterval != nil {
+		in, out := &in.AnalysisInterval, &out.AnalysisInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionTrigger.
-func (in *ConditionTrigger) DeepCopy() *ConditionTrigger {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AIProfile.
+func (in *AIProfile) DeepCopy() *AIProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(ConditionTrigger)
+	out := new(AIProfile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeleteAction) DeepCopyInto(out *DeleteAction) {
+func (in *ActionConstraints) DeepCopyInto(out *ActionConstraints) {
+	*out = *in
+	if in.Scale != nil {
+		in, out := &in.Scale, &out.Scale
+		*out = new(ScaleBounds)
+		**out = **in
+	}
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = new(PatchConstraints)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionConstraints.
+func (in *ActionConstraints) DeepCopy() *ActionConstraints {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionConstraints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionCreationProgress) DeepCopyInto(out *ActionCreationProgress) {
 	*out = *in
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteAction.
-func (in *DeleteAction) DeepCopy() *DeleteAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionCreationProgress.
+func (in *ActionCreationProgress) DeepCopy() *ActionCreationProgress {
 	if in == nil {
 		return nil
 	}
-	out := new(DeleteAction)
+	out := new(ActionCreationProgress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EventTrigger) DeepCopyInto(out *EventTrigger) {
+func (in *ActionReference) DeepCopyInto(out *ActionReference) {
 	*out = *in
-	out.Window = in.Window
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTrigger.
-func (in *EventTrigger) DeepCopy() *EventTrigger {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionReference.
+func (in *ActionReference) DeepCopy() *ActionReference {
 	if in == nil {
 		return nil
 	}
-	out := new(EventTrigger)
+	out := new(ActionReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingAction) DeepCopyInto(out *HealingAction) {
+func (in *ActionResult) DeepCopyInto(out *ActionResult) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]ResourceChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = make([]DiagnosticCapture, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]StepResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionResult.
+func (in *ActionResult) DeepCopy() *ActionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionStep) DeepCopyInto(out *ActionStep) {
+	*out = *in
+	if in.RestartAction != nil {
+		in, out := &in.RestartAction, &out.RestartAction
+		*out = new(RestartAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleAction != nil {
+		in, out := &in.ScaleAction, &out.ScaleAction
+		*out = new(ScaleAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PatchAction != nil {
+		in, out := &in.PatchAction, &out.PatchAction
+		*out = new(PatchAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeleteAction != nil {
+		in, out := &in.DeleteAction, &out.DeleteAction
+		*out = new(DeleteAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FinalizerAction != nil {
+		in, out := &in.FinalizerAction, &out.FinalizerAction
+		*out = new(FinalizerAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecAction != nil {
+		in, out := &in.ExecAction, &out.ExecAction
+		*out = new(ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResizeAction != nil {
+		in, out := &in.ResizeAction, &out.ResizeAction
+		*out = new(ResizeAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]PreActionHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(TargetCondition)
+		**out = **in
+	}
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionStep.
+func (in *ActionStep) DeepCopy() *ActionStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionTemplate) DeepCopyInto(out *ActionTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingAction.
-func (in *HealingAction) DeepCopy() *HealingAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionTemplate.
+func (in *ActionTemplate) DeepCopy() *ActionTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingAction)
+	out := new(ActionTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealingAction) DeepCopyObject() runtime.Object {
+func (in *ActionTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -148,31 +369,31 @@ func (in *HealingAction) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingActionList) DeepCopyInto(out *HealingActionList) {
+func (in *ActionTemplateList) DeepCopyInto(out *ActionTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HealingAction, len(*in))
+		*out = make([]ActionTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionList.
-func (in *HealingActionList) DeepCopy() *HealingActionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionTemplateList.
+func (in *ActionTemplateList) DeepCopy() *ActionTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingActionList)
+	out := new(ActionTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealingActionList) DeepCopyObject() runtime.Object {
+func (in *ActionTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -180,482 +401,2281 @@ func (in *HealingActionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingActionSpec) DeepCopyInto(out *HealingActionSpec) {
+func (in *ActionTemplateSpec) DeepCopyInto(out *ActionTemplateSpec) {
 	*out = *in
-	out.PolicyRef = in.PolicyRef
-	out.TargetResource = in.TargetResource
-	in.Action.DeepCopyInto(&out.Action)
-	out.Timeout = in.Timeout
-	if in.RetryPolicy != nil {
-		in, out := &in.RetryPolicy, &out.RetryPolicy
-		*out = new(RetryPolicy)
+	if in.RestartAction != nil {
+		in, out := &in.RestartAction, &out.RestartAction
+		*out = new(RestartAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleAction != nil {
+		in, out := &in.ScaleAction, &out.ScaleAction
+		*out = new(ScaleAction)
+		**out = **in
+	}
+	if in.PatchAction != nil {
+		in, out := &in.PatchAction, &out.PatchAction
+		*out = new(PatchAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeleteAction != nil {
+		in, out := &in.DeleteAction, &out.DeleteAction
+		*out = new(DeleteAction)
 		**out = **in
 	}
+	if in.FinalizerAction != nil {
+		in, out := &in.FinalizerAction, &out.FinalizerAction
+		*out = new(FinalizerAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HibernateAction != nil {
+		in, out := &in.HibernateAction, &out.HibernateAction
+		*out = new(HibernateAction)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Constraints.DeepCopyInto(&out.Constraints)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionSpec.
-func (in *HealingActionSpec) DeepCopy() *HealingActionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionTemplateSpec.
+func (in *ActionTemplateSpec) DeepCopy() *ActionTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingActionSpec)
+	out := new(ActionTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingActionStatus) DeepCopyInto(out *HealingActionStatus) {
+func (in *ApprovalStatus) DeepCopyInto(out *ApprovalStatus) {
 	*out = *in
-	if in.StartTime != nil {
-		in, out := &in.StartTime, &out.StartTime
-		*out = (*in).DeepCopy()
-	}
-	if in.CompletionTime != nil {
-		in, out := &in.CompletionTime, &out.CompletionTime
+	if in.ApprovedAt != nil {
+		in, out := &in.ApprovedAt, &out.ApprovedAt
 		*out = (*in).DeepCopy()
 	}
-	if in.LastAttemptTime != nil {
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalStatus.
+func (in *ApprovalStatus) DeepCopy() *ApprovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlastRadiusLimits) DeepCopyInto(out *BlastRadiusLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlastRadiusLimits.
+func (in *BlastRadiusLimits) DeepCopy() *BlastRadiusLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(BlastRadiusLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlastRadiusReport) DeepCopyInto(out *BlastRadiusReport) {
+	*out = *in
+	if in.AffectedPods != nil {
+		in, out := &in.AffectedPods, &out.AffectedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServicesLosingEndpoints != nil {
+		in, out := &in.ServicesLosingEndpoints, &out.ServicesLosingEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ViolatedPDBs != nil {
+		in, out := &in.ViolatedPDBs, &out.ViolatedPDBs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SimulatedAt != nil {
+		in, out := &in.SimulatedAt, &out.SimulatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlastRadiusReport.
+func (in *BlastRadiusReport) DeepCopy() *BlastRadiusReport {
+	if in == nil {
+		return nil
+	}
+	out := new(BlastRadiusReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleVariable) DeepCopyInto(out *BundleVariable) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(BundleVariableSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleVariable.
+func (in *BundleVariable) DeepCopy() *BundleVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleVariableSource) DeepCopyInto(out *BundleVariableSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleVariableSource.
+func (in *BundleVariableSource) DeepCopy() *BundleVariableSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleVariableSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundledPolicy) DeepCopyInto(out *BundledPolicy) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundledPolicy.
+func (in *BundledPolicy) DeepCopy() *BundledPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BundledPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionTrigger) DeepCopyInto(out *ConditionTrigger) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionTrigger.
+func (in *ConditionTrigger) DeepCopy() *ConditionTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecisionAlternative) DeepCopyInto(out *DecisionAlternative) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecisionAlternative.
+func (in *DecisionAlternative) DeepCopy() *DecisionAlternative {
+	if in == nil {
+		return nil
+	}
+	out := new(DecisionAlternative)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeleteAction) DeepCopyInto(out *DeleteAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeleteAction.
+func (in *DeleteAction) DeepCopy() *DeleteAction {
+	if in == nil {
+		return nil
+	}
+	out := new(DeleteAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticCapture) DeepCopyInto(out *DiagnosticCapture) {
+	*out = *in
+	in.CapturedAt.DeepCopyInto(&out.CapturedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticCapture.
+func (in *DiagnosticCapture) DeepCopy() *DiagnosticCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunComparison) DeepCopyInto(out *DryRunComparison) {
+	*out = *in
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunComparison.
+func (in *DryRunComparison) DeepCopy() *DryRunComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunObservation) DeepCopyInto(out *DryRunObservation) {
+	*out = *in
+	in.ObserveUntil.DeepCopyInto(&out.ObserveUntil)
+	if in.ObservedAt != nil {
+		in, out := &in.ObservedAt, &out.ObservedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunObservation.
+func (in *DryRunObservation) DeepCopy() *DryRunObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTrigger) DeepCopyInto(out *EventTrigger) {
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTrigger.
+func (in *EventTrigger) DeepCopy() *EventTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecAction) DeepCopyInto(out *ExecAction) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecAction.
+func (in *ExecAction) DeepCopy() *ExecAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionEnvironment) DeepCopyInto(out *ExecutionEnvironment) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionEnvironment.
+func (in *ExecutionEnvironment) DeepCopy() *ExecutionEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailingTarget) DeepCopyInto(out *FailingTarget) {
+	*out = *in
+	if in.FirstFailure != nil {
+		in, out := &in.FirstFailure, &out.FirstFailure
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailure != nil {
+		in, out := &in.LastFailure, &out.LastFailure
+		*out = (*in).DeepCopy()
+	}
+	if in.EscalatedAt != nil {
+		in, out := &in.EscalatedAt, &out.EscalatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailingTarget.
+func (in *FailingTarget) DeepCopy() *FailingTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(FailingTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalizerAction) DeepCopyInto(out *FinalizerAction) {
+	*out = *in
+	if in.RemoveFinalizers != nil {
+		in, out := &in.RemoveFinalizers, &out.RemoveFinalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalizerAction.
+func (in *FinalizerAction) DeepCopy() *FinalizerAction {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalizerAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingAction) DeepCopyInto(out *HealingAction) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingAction.
+func (in *HealingAction) DeepCopy() *HealingAction {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingAction) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingActionList) DeepCopyInto(out *HealingActionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealingAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionList.
+func (in *HealingActionList) DeepCopy() *HealingActionList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingActionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingActionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingActionSpec) DeepCopyInto(out *HealingActionSpec) {
+	*out = *in
+	out.PolicyRef = in.PolicyRef
+	out.TargetResource = in.TargetResource
+	in.Action.DeepCopyInto(&out.Action)
+	out.Timeout = in.Timeout
+	out.AttemptTimeout = in.AttemptTimeout
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		**out = **in
+	}
+	if in.BlastRadiusLimits != nil {
+		in, out := &in.BlastRadiusLimits, &out.BlastRadiusLimits
+		*out = new(BlastRadiusLimits)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionSpec.
+func (in *HealingActionSpec) DeepCopy() *HealingActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingActionStatus) DeepCopyInto(out *HealingActionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAttemptTime != nil {
 		in, out := &in.LastAttemptTime, &out.LastAttemptTime
 		*out = (*in).DeepCopy()
 	}
-	if in.Result != nil {
-		in, out := &in.Result, &out.Result
-		*out = new(ActionResult)
+	if in.Result != nil {
+		in, out := &in.Result, &out.Result
+		*out = new(ActionResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(VerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityDecision != nil {
+		in, out := &in.PriorityDecision, &out.PriorityDecision
+		*out = new(PriorityDecision)
+		**out = **in
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(HibernationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeTaint != nil {
+		in, out := &in.NodeTaint, &out.NodeTaint
+		*out = new(NodeTaintStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunObservation != nil {
+		in, out := &in.DryRunObservation, &out.DryRunObservation
+		*out = new(DryRunObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ApprovalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlastRadius != nil {
+		in, out := &in.BlastRadius, &out.BlastRadius
+		*out = new(BlastRadiusReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreemptedBy != nil {
+		in, out := &in.PreemptedBy, &out.PreemptedBy
+		*out = new(ActionReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionStatus.
+func (in *HealingActionStatus) DeepCopy() *HealingActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingActionTemplate) DeepCopyInto(out *HealingActionTemplate) {
+	*out = *in
+	if in.RestartAction != nil {
+		in, out := &in.RestartAction, &out.RestartAction
+		*out = new(RestartAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleAction != nil {
+		in, out := &in.ScaleAction, &out.ScaleAction
+		*out = new(ScaleAction)
+		**out = **in
+	}
+	if in.PatchAction != nil {
+		in, out := &in.PatchAction, &out.PatchAction
+		*out = new(PatchAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeleteAction != nil {
+		in, out := &in.DeleteAction, &out.DeleteAction
+		*out = new(DeleteAction)
+		**out = **in
+	}
+	if in.PreActionHooks != nil {
+		in, out := &in.PreActionHooks, &out.PreActionHooks
+		*out = make([]PreActionHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Execution != nil {
+		in, out := &in.Execution, &out.Execution
+		*out = new(ExecutionEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuccessCriteria != nil {
+		in, out := &in.SuccessCriteria, &out.SuccessCriteria
+		*out = new(SuccessCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ActionStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FinalizerAction != nil {
+		in, out := &in.FinalizerAction, &out.FinalizerAction
+		*out = new(FinalizerAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HibernateAction != nil {
+		in, out := &in.HibernateAction, &out.HibernateAction
+		*out = new(HibernateAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecAction != nil {
+		in, out := &in.ExecAction, &out.ExecAction
+		*out = new(ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResizeAction != nil {
+		in, out := &in.ResizeAction, &out.ResizeAction
+		*out = new(ResizeAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaintAction != nil {
+		in, out := &in.TaintAction, &out.TaintAction
+		*out = new(TaintAction)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionTemplate.
+func (in *HealingActionTemplate) DeepCopy() *HealingActionTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingActionTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicy) DeepCopyInto(out *HealingPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicy.
+func (in *HealingPolicy) DeepCopy() *HealingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicyBundle) DeepCopyInto(out *HealingPolicyBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyBundle.
+func (in *HealingPolicyBundle) DeepCopy() *HealingPolicyBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicyBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingPolicyBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicyBundleList) DeepCopyInto(out *HealingPolicyBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealingPolicyBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyBundleList.
+func (in *HealingPolicyBundleList) DeepCopy() *HealingPolicyBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicyBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingPolicyBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicyBundleSpec) DeepCopyInto(out *HealingPolicyBundleSpec) {
+	*out = *in
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]BundleVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]BundledPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyBundleSpec.
+func (in *HealingPolicyBundleSpec) DeepCopy() *HealingPolicyBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicyBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicyBundleStatus) DeepCopyInto(out *HealingPolicyBundleStatus) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastResolved != nil {
+		in, out := &in.LastResolved, &out.LastResolved
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyBundleStatus.
+func (in *HealingPolicyBundleStatus) DeepCopy() *HealingPolicyBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicyBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicyList) DeepCopyInto(out *HealingPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealingPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyList.
+func (in *HealingPolicyList) DeepCopy() *HealingPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicySpec) DeepCopyInto(out *HealingPolicySpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]HealingTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]HealingActionTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.SafetyRules.DeepCopyInto(&out.SafetyRules)
+	if in.RolloutPercentage != nil {
+		in, out := &in.RolloutPercentage, &out.RolloutPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActionTimeout != nil {
+		in, out := &in.ActionTimeout, &out.ActionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AttemptTimeout != nil {
+		in, out := &in.AttemptTimeout, &out.AttemptTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		**out = **in
+	}
+	if in.AIProfile != nil {
+		in, out := &in.AIProfile, &out.AIProfile
+		*out = new(AIProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AIAnalysisInterval != nil {
+		in, out := &in.AIAnalysisInterval, &out.AIAnalysisInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SeverityMapping != nil {
+		in, out := &in.SeverityMapping, &out.SeverityMapping
+		*out = make([]SeverityMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]PolicyDependency, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicySpec.
+func (in *HealingPolicySpec) DeepCopy() *HealingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingPolicyStatus) DeepCopyInto(out *HealingPolicyStatus) {
+	*out = *in
+	in.LastEvaluated.DeepCopyInto(&out.LastEvaluated)
+	if in.LastAIAnalysis != nil {
+		in, out := &in.LastAIAnalysis, &out.LastAIAnalysis
+		*out = (*in).DeepCopy()
+	}
+	if in.AIAnalysis != nil {
+		in, out := &in.AIAnalysis, &out.AIAnalysis
+		*out = new(AIAnalysisStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveTriggers != nil {
+		in, out := &in.ActiveTriggers, &out.ActiveTriggers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TriggerStates != nil {
+		in, out := &in.TriggerStates, &out.TriggerStates
+		*out = make([]TriggerState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TriggerHistory != nil {
+		in, out := &in.TriggerHistory, &out.TriggerHistory
+		*out = make([]TriggerHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastActionTime.DeepCopyInto(&out.LastActionTime)
+	if in.ActionCreation != nil {
+		in, out := &in.ActionCreation, &out.ActionCreation
+		*out = new(ActionCreationProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Incidents != nil {
+		in, out := &in.Incidents, &out.Incidents
+		*out = make([]Incident, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(RecoveryStats)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailingTargets != nil {
+		in, out := &in.FailingTargets, &out.FailingTargets
+		*out = make([]FailingTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SelfInducedChurn != nil {
+		in, out := &in.SelfInducedChurn, &out.SelfInducedChurn
+		*out = make([]SelfInducedChurn, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyStatus.
+func (in *HealingPolicyStatus) DeepCopy() *HealingPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingReport) DeepCopyInto(out *HealingReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingReport.
+func (in *HealingReport) DeepCopy() *HealingReport {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingReportList) DeepCopyInto(out *HealingReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealingReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingReportList.
+func (in *HealingReportList) DeepCopy() *HealingReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealingReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingReportSpec) DeepCopyInto(out *HealingReportSpec) {
+	*out = *in
+	if in.PolicyNames != nil {
+		in, out := &in.PolicyNames, &out.PolicyNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Period = in.Period
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ReportExport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingReportSpec.
+func (in *HealingReportSpec) DeepCopy() *HealingReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingReportStatus) DeepCopyInto(out *HealingReportStatus) {
+	*out = *in
+	if in.PeriodStart != nil {
+		in, out := &in.PeriodStart, &out.PeriodStart
+		*out = (*in).DeepCopy()
+	}
+	if in.PeriodEnd != nil {
+		in, out := &in.PeriodEnd, &out.PeriodEnd
+		*out = (*in).DeepCopy()
+	}
+	if in.NextGeneration != nil {
+		in, out := &in.NextGeneration, &out.NextGeneration
+		*out = (*in).DeepCopy()
+	}
+	in.Summary.DeepCopyInto(&out.Summary)
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]PolicyReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingReportStatus.
+func (in *HealingReportStatus) DeepCopy() *HealingReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealingTrigger) DeepCopyInto(out *HealingTrigger) {
+	*out = *in
+	if in.MetricTrigger != nil {
+		in, out := &in.MetricTrigger, &out.MetricTrigger
+		*out = new(MetricTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTrigger != nil {
+		in, out := &in.EventTrigger, &out.EventTrigger
+		*out = new(EventTrigger)
+		**out = **in
+	}
+	if in.ConditionTrigger != nil {
+		in, out := &in.ConditionTrigger, &out.ConditionTrigger
+		*out = new(ConditionTrigger)
+		**out = **in
+	}
+	if in.LogTrigger != nil {
+		in, out := &in.LogTrigger, &out.LogTrigger
+		*out = new(LogTrigger)
+		**out = **in
+	}
+	if in.RestartStormTrigger != nil {
+		in, out := &in.RestartStormTrigger, &out.RestartStormTrigger
+		*out = new(RestartStormTrigger)
+		**out = **in
+	}
+	if in.ScheduleTrigger != nil {
+		in, out := &in.ScheduleTrigger, &out.ScheduleTrigger
+		*out = new(ScheduleTrigger)
+		**out = **in
+	}
+	out.CooldownPeriod = in.CooldownPeriod
+	if in.StuckTerminatingTrigger != nil {
+		in, out := &in.StuckTerminatingTrigger, &out.StuckTerminatingTrigger
+		*out = new(StuckTerminatingTrigger)
+		**out = **in
+	}
+	if in.PluginTrigger != nil {
+		in, out := &in.PluginTrigger, &out.PluginTrigger
+		*out = new(PluginTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingTrigger.
+func (in *HealingTrigger) DeepCopy() *HealingTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(HealingTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckStatus) DeepCopyInto(out *HealthCheckStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckStatus.
+func (in *HealthCheckStatus) DeepCopy() *HealthCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HibernateAction) DeepCopyInto(out *HibernateAction) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.ResumeWhen != nil {
+		in, out := &in.ResumeWhen, &out.ResumeWhen
+		*out = new(ResumeCondition)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HibernateAction.
+func (in *HibernateAction) DeepCopy() *HibernateAction {
+	if in == nil {
+		return nil
+	}
+	out := new(HibernateAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HibernationStatus) DeepCopyInto(out *HibernationStatus) {
+	*out = *in
+	if in.ResumeAt != nil {
+		in, out := &in.ResumeAt, &out.ResumeAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ResumedAt != nil {
+		in, out := &in.ResumedAt, &out.ResumedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HibernationStatus.
+func (in *HibernationStatus) DeepCopy() *HibernationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HibernationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Incident) DeepCopyInto(out *Incident) {
+	*out = *in
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
+	if in.ActedAt != nil {
+		in, out := &in.ActedAt, &out.ActedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Incident.
+func (in *Incident) DeepCopy() *Incident {
+	if in == nil {
+		return nil
+	}
+	out := new(Incident)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogTrigger) DeepCopyInto(out *LogTrigger) {
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogTrigger.
+func (in *LogTrigger) DeepCopy() *LogTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(LogTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshRestart) DeepCopyInto(out *MeshRestart) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshRestart.
+func (in *MeshRestart) DeepCopy() *MeshRestart {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshRestart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricCriterion) DeepCopyInto(out *MetricCriterion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricCriterion.
+func (in *MetricCriterion) DeepCopy() *MetricCriterion {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricCriterion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricBaseline) DeepCopyInto(out *MetricBaseline) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricBaseline.
+func (in *MetricBaseline) DeepCopy() *MetricBaseline {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricBaseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricTrigger) DeepCopyInto(out *MetricTrigger) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(MetricBaseline)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricTrigger.
+func (in *MetricTrigger) DeepCopy() *MetricTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTaintStatus) DeepCopyInto(out *NodeTaintStatus) {
+	*out = *in
+	in.WindowEndsAt.DeepCopyInto(&out.WindowEndsAt)
+	if in.ResolvedAt != nil {
+		in, out := &in.ResolvedAt, &out.ResolvedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTaintStatus.
+func (in *NodeTaintStatus) DeepCopy() *NodeTaintStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTaintStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorHealth) DeepCopyInto(out *OperatorHealth) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorHealth.
+func (in *OperatorHealth) DeepCopy() *OperatorHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorHealth) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorHealthList) DeepCopyInto(out *OperatorHealthList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorHealthList.
+func (in *OperatorHealthList) DeepCopy() *OperatorHealthList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorHealthList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorHealthList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorHealthSpec) DeepCopyInto(out *OperatorHealthSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorHealthSpec.
+func (in *OperatorHealthSpec) DeepCopy() *OperatorHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorHealthStatus) DeepCopyInto(out *OperatorHealthStatus) {
+	*out = *in
+	if in.SafeModeSince != nil {
+		in, out := &in.SafeModeSince, &out.SafeModeSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]HealthCheckStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Remediations != nil {
+		in, out := &in.Remediations, &out.Remediations
+		*out = make([]SelfRemediation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorHealthStatus.
+func (in *OperatorHealthStatus) DeepCopy() *OperatorHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchAction) DeepCopyInto(out *PatchAction) {
+	*out = *in
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]PatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchAction.
+func (in *PatchAction) DeepCopy() *PatchAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchConstraints) DeepCopyInto(out *PatchConstraints) {
+	*out = *in
+	if in.AllowedPaths != nil {
+		in, out := &in.AllowedPaths, &out.AllowedPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchConstraints.
+func (in *PatchConstraints) DeepCopy() *PatchConstraints {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchConstraints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchOperation) DeepCopyInto(out *PatchOperation) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchOperation.
+func (in *PatchOperation) DeepCopy() *PatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginTrigger) DeepCopyInto(out *PluginTrigger) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginTrigger.
+func (in *PluginTrigger) DeepCopy() *PluginTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyDependency) DeepCopyInto(out *PolicyDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyDependency.
+func (in *PolicyDependency) DeepCopy() *PolicyDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyReference) DeepCopyInto(out *PolicyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyReference.
+func (in *PolicyReference) DeepCopy() *PolicyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyReport) DeepCopyInto(out *PolicyReport) {
+	*out = *in
+	if in.TriggersFired != nil {
+		in, out := &in.TriggersFired, &out.TriggersFired
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActionsByType != nil {
+		in, out := &in.ActionsByType, &out.ActionsByType
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.MTTR = in.MTTR
+	if in.TopIssues != nil {
+		in, out := &in.TopIssues, &out.TopIssues
+		*out = make([]RecurringIssue, len(*in))
+		copy(*out, *in)
+	}
+	if in.Suggestions != nil {
+		in, out := &in.Suggestions, &out.Suggestions
+		*out = make([]TuningSuggestion, len(*in))
+		copy(*out, *in)
+	}
+	if in.DryRunComparison != nil {
+		in, out := &in.DryRunComparison, &out.DryRunComparison
+		*out = new(DryRunComparison)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyReport.
+func (in *PolicyReport) DeepCopy() *PolicyReport {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreActionHook) DeepCopyInto(out *PreActionHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreActionHook.
+func (in *PreActionHook) DeepCopy() *PreActionHook {
+	if in == nil {
+		return nil
+	}
+	out := new(PreActionHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityDecision) DeepCopyInto(out *PriorityDecision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityDecision.
+func (in *PriorityDecision) DeepCopy() *PriorityDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryStats) DeepCopyInto(out *RecoveryStats) {
+	*out = *in
+	if in.LastRecovered != nil {
+		in, out := &in.LastRecovered, &out.LastRecovered
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryStats.
+func (in *RecoveryStats) DeepCopy() *RecoveryStats {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecurringIssue) DeepCopyInto(out *RecurringIssue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecurringIssue.
+func (in *RecurringIssue) DeepCopy() *RecurringIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(RecurringIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportExport) DeepCopyInto(out *ReportExport) {
+	*out = *in
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportExport.
+func (in *ReportExport) DeepCopy() *ReportExport {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResizeAction) DeepCopyInto(out *ResizeAction) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResizeAction.
+func (in *ResizeAction) DeepCopy() *ResizeAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ResizeAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceChange) DeepCopyInto(out *ResourceChange) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceChange.
+func (in *ResourceChange) DeepCopy() *ResourceChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFilter) DeepCopyInto(out *ResourceFilter) {
+	*out = *in
+	if in.ExcludeNames != nil {
+		in, out := &in.ExcludeNames, &out.ExcludeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilter.
+func (in *ResourceFilter) DeepCopy() *ResourceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Approval != nil {
-		in, out := &in.Approval, &out.Approval
-		*out = new(ApprovalStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeLabelSelector != nil {
+		in, out := &in.ExcludeLabelSelector, &out.ExcludeLabelSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.ExcludeNames != nil {
+		in, out := &in.ExcludeNames, &out.ExcludeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
+func (in *ResourceSelector) DeepCopy() *ResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartAction) DeepCopyInto(out *RestartAction) {
+	*out = *in
+	if in.Mesh != nil {
+		in, out := &in.Mesh, &out.Mesh
+		*out = new(MeshRestart)
+		**out = **in
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartAction.
+func (in *RestartAction) DeepCopy() *RestartAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartStormTrigger) DeepCopyInto(out *RestartStormTrigger) {
+	*out = *in
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartStormTrigger.
+func (in *RestartStormTrigger) DeepCopy() *RestartStormTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartStormTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResumeCondition) DeepCopyInto(out *ResumeCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResumeCondition.
+func (in *ResumeCondition) DeepCopy() *ResumeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ResumeCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	out.BackoffDelay = in.BackoffDelay
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SafetyRules) DeepCopyInto(out *SafetyRules) {
+	*out = *in
+	if in.ProtectedResources != nil {
+		in, out := &in.ProtectedResources, &out.ProtectedResources
+		*out = make([]ResourceFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.HealthCheckTimeout = in.HealthCheckTimeout
+	if in.BlastRadius != nil {
+		in, out := &in.BlastRadius, &out.BlastRadius
+		*out = new(BlastRadiusLimits)
+		**out = **in
+	}
+	if in.OverridePausePeriod != nil {
+		in, out := &in.OverridePausePeriod, &out.OverridePausePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SeverityRules != nil {
+		in, out := &in.SeverityRules, &out.SeverityRules
+		*out = make([]SeverityRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EscalateAfterFailures != nil {
+		in, out := &in.EscalateAfterFailures, &out.EscalateAfterFailures
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionStatus.
-func (in *HealingActionStatus) DeepCopy() *HealingActionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafetyRules.
+func (in *SafetyRules) DeepCopy() *SafetyRules {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingActionStatus)
+	out := new(SafetyRules)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingActionTemplate) DeepCopyInto(out *HealingActionTemplate) {
+func (in *ScaleAction) DeepCopyInto(out *ScaleAction) {
 	*out = *in
-	if in.RestartAction != nil {
-		in, out := &in.RestartAction, &out.RestartAction
-		*out = new(RestartAction)
-		**out = **in
-	}
-	if in.ScaleAction != nil {
-		in, out := &in.ScaleAction, &out.ScaleAction
-		*out = new(ScaleAction)
-		**out = **in
-	}
-	if in.PatchAction != nil {
-		in, out := &in.PatchAction, &out.PatchAction
-		*out = new(PatchAction)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DeleteAction != nil {
-		in, out := &in.DeleteAction, &out.DeleteAction
-		*out = new(DeleteAction)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingActionTemplate.
-func (in *HealingActionTemplate) DeepCopy() *HealingActionTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleAction.
+func (in *ScaleAction) DeepCopy() *ScaleAction {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingActionTemplate)
+	out := new(ScaleAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingPolicy) DeepCopyInto(out *HealingPolicy) {
+func (in *ScaleBounds) DeepCopyInto(out *ScaleBounds) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicy.
-func (in *HealingPolicy) DeepCopy() *HealingPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleBounds.
+func (in *ScaleBounds) DeepCopy() *ScaleBounds {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingPolicy)
+	out := new(ScaleBounds)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealingPolicy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingPolicyList) DeepCopyInto(out *HealingPolicyList) {
+func (in *ScheduleTrigger) DeepCopyInto(out *ScheduleTrigger) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]HealingPolicy, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyList.
-func (in *HealingPolicyList) DeepCopy() *HealingPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleTrigger.
+func (in *ScheduleTrigger) DeepCopy() *ScheduleTrigger {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingPolicyList)
+	out := new(ScheduleTrigger)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealingPolicyList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingPolicySpec) DeepCopyInto(out *HealingPolicySpec) {
+func (in *SelfInducedChurn) DeepCopyInto(out *SelfInducedChurn) {
 	*out = *in
-	in.Selector.DeepCopyInto(&out.Selector)
-	if in.Triggers != nil {
-		in, out := &in.Triggers, &out.Triggers
-		*out = make([]HealingTrigger, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Actions != nil {
-		in, out := &in.Actions, &out.Actions
-		*out = make([]HealingActionTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	in.Until.DeepCopyInto(&out.Until)
+	if in.PodUIDs != nil {
+		in, out := &in.PodUIDs, &out.PodUIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.SafetyRules.DeepCopyInto(&out.SafetyRules)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicySpec.
-func (in *HealingPolicySpec) DeepCopy() *HealingPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfInducedChurn.
+func (in *SelfInducedChurn) DeepCopy() *SelfInducedChurn {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingPolicySpec)
+	out := new(SelfInducedChurn)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingPolicyStatus) DeepCopyInto(out *HealingPolicyStatus) {
+func (in *SelfRemediation) DeepCopyInto(out *SelfRemediation) {
 	*out = *in
-	in.LastEvaluated.DeepCopyInto(&out.LastEvaluated)
-	if in.ActiveTriggers != nil {
-		in, out := &in.ActiveTriggers, &out.ActiveTriggers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.LastActionTime.DeepCopyInto(&out.LastActionTime)
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingPolicyStatus.
-func (in *HealingPolicyStatus) DeepCopy() *HealingPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfRemediation.
+func (in *SelfRemediation) DeepCopy() *SelfRemediation {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingPolicyStatus)
+	out := new(SelfRemediation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealingTrigger) DeepCopyInto(out *HealingTrigger) {
+func (in *SeverityMapping) DeepCopyInto(out *SeverityMapping) {
 	*out = *in
-	if in.MetricTrigger != nil {
-		in, out := &in.MetricTrigger, &out.MetricTrigger
-		*out = new(MetricTrigger)
-		**out = **in
-	}
-	if in.EventTrigger != nil {
-		in, out := &in.EventTrigger, &out.EventTrigger
-		*out = new(EventTrigger)
-		**out = **in
-	}
-	if in.ConditionTrigger != nil {
-		in, out := &in.ConditionTrigger, &out.ConditionTrigger
-		*out = new(ConditionTrigger)
-		**out = **in
-	}
-	out.CooldownPeriod = in.CooldownPeriod
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealingTrigger.
-func (in *HealingTrigger) DeepCopy() *HealingTrigger {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityMapping.
+func (in *SeverityMapping) DeepCopy() *SeverityMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(HealingTrigger)
+	out := new(SeverityMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricTrigger) DeepCopyInto(out *MetricTrigger) {
+func (in *SeverityRule) DeepCopyInto(out *SeverityRule) {
 	*out = *in
-	out.Duration = in.Duration
+	if in.AllowedActions != nil {
+		in, out := &in.AllowedActions, &out.AllowedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricTrigger.
-func (in *MetricTrigger) DeepCopy() *MetricTrigger {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityRule.
+func (in *SeverityRule) DeepCopy() *SeverityRule {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricTrigger)
+	out := new(SeverityRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PatchAction) DeepCopyInto(out *PatchAction) {
+func (in *StepResult) DeepCopyInto(out *StepResult) {
 	*out = *in
-	if in.Patches != nil {
-		in, out := &in.Patches, &out.Patches
-		*out = make([]PatchOperation, len(*in))
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]ResourceChange, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchAction.
-func (in *PatchAction) DeepCopy() *PatchAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepResult.
+func (in *StepResult) DeepCopy() *StepResult {
 	if in == nil {
 		return nil
 	}
-	out := new(PatchAction)
+	out := new(StepResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PatchOperation) DeepCopyInto(out *PatchOperation) {
+func (in *StuckTerminatingTrigger) DeepCopyInto(out *StuckTerminatingTrigger) {
 	*out = *in
-	if in.Path != nil {
-		in, out := &in.Path, &out.Path
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.Threshold = in.Threshold
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchOperation.
-func (in *PatchOperation) DeepCopy() *PatchOperation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckTerminatingTrigger.
+func (in *StuckTerminatingTrigger) DeepCopy() *StuckTerminatingTrigger {
 	if in == nil {
 		return nil
 	}
-	out := new(PatchOperation)
+	out := new(StuckTerminatingTrigger)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PolicyReference) DeepCopyInto(out *PolicyReference) {
+func (in *SuccessCriteria) DeepCopyInto(out *SuccessCriteria) {
 	*out = *in
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(MetricCriterion)
+		**out = **in
+	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(TargetCondition)
+		**out = **in
+	}
+	out.Timeout = in.Timeout
+	out.Interval = in.Interval
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyReference.
-func (in *PolicyReference) DeepCopy() *PolicyReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuccessCriteria.
+func (in *SuccessCriteria) DeepCopy() *SuccessCriteria {
 	if in == nil {
 		return nil
 	}
-	out := new(PolicyReference)
+	out := new(SuccessCriteria)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceChange) DeepCopyInto(out *ResourceChange) {
+func (in *TaintAction) DeepCopyInto(out *TaintAction) {
 	*out = *in
-	if in.Timestamp != nil {
-		in, out := &in.Timestamp, &out.Timestamp
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceChange.
-func (in *ResourceChange) DeepCopy() *ResourceChange {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaintAction.
+func (in *TaintAction) DeepCopy() *TaintAction {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceChange)
+	out := new(TaintAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceFilter) DeepCopyInto(out *ResourceFilter) {
+func (in *TargetCondition) DeepCopyInto(out *TargetCondition) {
 	*out = *in
-	if in.ExcludeNames != nil {
-		in, out := &in.ExcludeNames, &out.ExcludeNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilter.
-func (in *ResourceFilter) DeepCopy() *ResourceFilter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetCondition.
+func (in *TargetCondition) DeepCopy() *TargetCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceFilter)
+	out := new(TargetCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
+func (in *TargetResource) DeepCopyInto(out *TargetResource) {
 	*out = *in
-	if in.Namespaces != nil {
-		in, out := &in.Namespaces, &out.Namespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.LabelSelector != nil {
-		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]ResourceFilter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
-func (in *ResourceSelector) DeepCopy() *ResourceSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetResource.
+func (in *TargetResource) DeepCopy() *TargetResource {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSelector)
+	out := new(TargetResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestartAction) DeepCopyInto(out *RestartAction) {
+func (in *TriggerHistory) DeepCopyInto(out *TriggerHistory) {
 	*out = *in
+	if in.Samples != nil {
+		in, out := &in.Samples, &out.Samples
+		*out = make([]TriggerSample, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartAction.
-func (in *RestartAction) DeepCopy() *RestartAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerHistory.
+func (in *TriggerHistory) DeepCopy() *TriggerHistory {
 	if in == nil {
 		return nil
 	}
-	out := new(RestartAction)
+	out := new(TriggerHistory)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+func (in *TriggerSample) DeepCopyInto(out *TriggerSample) {
 	*out = *in
-	out.BackoffDelay = in.BackoffDelay
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
-func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerSample.
+func (in *TriggerSample) DeepCopy() *TriggerSample {
 	if in == nil {
 		return nil
 	}
-	out := new(RetryPolicy)
+	out := new(TriggerSample)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SafetyRules) DeepCopyInto(out *SafetyRules) {
+func (in *TriggerState) DeepCopyInto(out *TriggerState) {
 	*out = *in
-	if in.ProtectedResources != nil {
-		in, out := &in.ProtectedResources, &out.ProtectedResources
-		*out = make([]ResourceFilter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	out.HealthCheckTimeout = in.HealthCheckTimeout
+	in.FirstActive.DeepCopyInto(&out.FirstActive)
+	in.LastActive.DeepCopyInto(&out.LastActive)
+	in.LastNotified.DeepCopyInto(&out.LastNotified)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafetyRules.
-func (in *SafetyRules) DeepCopy() *SafetyRules {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerState.
+func (in *TriggerState) DeepCopy() *TriggerState {
 	if in == nil {
 		return nil
 	}
-	out := new(SafetyRules)
+	out := new(TriggerState)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScaleAction) DeepCopyInto(out *ScaleAction) {
+func (in *TuningSuggestion) DeepCopyInto(out *TuningSuggestion) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleAction.
-func (in *ScaleAction) DeepCopy() *ScaleAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningSuggestion.
+func (in *TuningSuggestion) DeepCopy() *TuningSuggestion {
 	if in == nil {
 		return nil
 	}
-	out := new(ScaleAction)
+	out := new(TuningSuggestion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TargetResource) DeepCopyInto(out *TargetResource) {
+func (in *VerificationStatus) DeepCopyInto(out *VerificationStatus) {
 	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetResource.
-func (in *TargetResource) DeepCopy() *TargetResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerificationStatus.
+func (in *VerificationStatus) DeepCopy() *VerificationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TargetResource)
+	out := new(VerificationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,213 @@
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &HealingPolicy{}
+
+// ConvertTo converts this HealingPolicy to the hub version v1alpha1
+func (src *HealingPolicy) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.HealingPolicy)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 HealingPolicy but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.HealingPolicySpec{
+		Selector:            src.Spec.Selector,
+		Actions:             src.Spec.Actions,
+		SafetyRules:         src.Spec.SafetyRules,
+		Mode:                src.Spec.Mode,
+		RolloutPercentage:   src.Spec.RolloutPercentage,
+		Paused:              src.Spec.Paused,
+		ActionTimeout:       src.Spec.ActionTimeout,
+		RetryPolicy:         src.Spec.RetryPolicy,
+		SeverityMapping:     src.Spec.SeverityMapping,
+		DependsOn:           src.Spec.DependsOn,
+		RecordTriggerValues: src.Spec.RecordTriggerValues,
+	}
+	if src.Spec.Triggers != nil {
+		dst.Spec.Triggers = make([]v1alpha1.HealingTrigger, len(src.Spec.Triggers))
+		for i := range src.Spec.Triggers {
+			dst.Spec.Triggers[i] = src.Spec.Triggers[i].toHub()
+		}
+	}
+	if ai := src.Spec.AI; ai != nil {
+		dst.Spec.AIAnalysisInterval = ai.MinInterval
+		if ai.Mode != "" || ai.Lite != nil {
+			dst.Spec.AIProfile = &v1alpha1.AIProfile{Mode: ai.Mode}
+			if ai.Lite != nil {
+				dst.Spec.AIProfile.AnalysisInterval = ai.Lite.AnalysisInterval
+				dst.Spec.AIProfile.MaxPods = ai.Lite.MaxPods
+			}
+		}
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts the hub version v1alpha1 to this HealingPolicy
+func (dst *HealingPolicy) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.HealingPolicy)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 HealingPolicy but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = HealingPolicySpec{
+		Selector:            src.Spec.Selector,
+		Actions:             src.Spec.Actions,
+		SafetyRules:         src.Spec.SafetyRules,
+		Mode:                src.Spec.Mode,
+		RolloutPercentage:   src.Spec.RolloutPercentage,
+		Paused:              src.Spec.Paused,
+		ActionTimeout:       src.Spec.ActionTimeout,
+		RetryPolicy:         src.Spec.RetryPolicy,
+		SeverityMapping:     src.Spec.SeverityMapping,
+		DependsOn:           src.Spec.DependsOn,
+		RecordTriggerValues: src.Spec.RecordTriggerValues,
+	}
+	if src.Spec.Triggers != nil {
+		dst.Spec.Triggers = make([]HealingTrigger, len(src.Spec.Triggers))
+		for i := range src.Spec.Triggers {
+			dst.Spec.Triggers[i] = triggerFromHub(&src.Spec.Triggers[i])
+		}
+	}
+	if src.Spec.AIProfile != nil || src.Spec.AIAnalysisInterval != nil {
+		dst.Spec.AI = &AISettings{MinInterval: src.Spec.AIAnalysisInterval}
+		if profile := src.Spec.AIProfile; profile != nil {
+			dst.Spec.AI.Mode = profile.Mode
+			if profile.AnalysisInterval != nil || profile.MaxPods != 0 {
+				dst.Spec.AI.Lite = &LiteAISettings{
+					AnalysisInterval: profile.AnalysisInterval,
+					MaxPods:          profile.MaxPods,
+				}
+			}
+		}
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// toHub converts a trigger to v1alpha1, whose type is named by the set field
+func (t *HealingTrigger) toHub() v1alpha1.HealingTrigger {
+	out := v1alpha1.HealingTrigger{
+		Name:                    t.Name,
+		EventTrigger:            t.Event,
+		ConditionTrigger:        t.Condition,
+		LogTrigger:              t.Log,
+		RestartStormTrigger:     t.RestartStorm,
+		ScheduleTrigger:         t.Schedule,
+		StuckTerminatingTrigger: t.StuckTerminating,
+		PluginTrigger:           t.Plugin,
+		CooldownPeriod:          t.CooldownPeriod,
+		ClearAfterEvaluations:   t.ClearAfterEvaluations,
+		Severity:                t.Severity,
+	}
+	if t.Metric != nil {
+		out.MetricTrigger = t.Metric.toHub()
+	}
+
+	switch {
+	case t.Metric != nil:
+		out.Type = "metric"
+	case t.Event != nil:
+		out.Type = "event"
+	case t.Condition != nil:
+		out.Type = "condition"
+	case t.Log != nil:
+		out.Type = "log"
+	case t.RestartStorm != nil:
+		out.Type = "restartStorm"
+	case t.Schedule != nil:
+		out.Type = "schedule"
+	case t.StuckTerminating != nil:
+		out.Type = "stuckTerminating"
+	case t.Plugin != nil:
+		out.Type = "plugin"
+	}
+	return out
+}
+
+// triggerFromHub converts a v1alpha1 trigger, keeping only the field of its
+// type
+func triggerFromHub(t *v1alpha1.HealingTrigger) HealingTrigger {
+	out := HealingTrigger{
+		Name:                  t.Name,
+		CooldownPeriod:        t.CooldownPeriod,
+		ClearAfterEvaluations: t.ClearAfterEvaluations,
+		Severity:              t.Severity,
+	}
+	switch t.Type {
+	case "metric":
+		if t.MetricTrigger != nil {
+			out.Metric = metricTriggerFromHub(t.MetricTrigger)
+		}
+	case "event":
+		out.Event = t.EventTrigger
+	case "condition":
+		out.Condition = t.ConditionTrigger
+	case "log":
+		out.Log = t.LogTrigger
+	case "restartStorm":
+		out.RestartStorm = t.RestartStormTrigger
+	case "schedule":
+		out.Schedule = t.ScheduleTrigger
+	case "stuckTerminating":
+		out.StuckTerminating = t.StuckTerminatingTrigger
+	case "plugin":
+		out.Plugin = t.PluginTrigger
+	}
+	return out
+}
+
+// toHub converts a metric trigger to v1alpha1, where pushed and pattern
+// queries carry their prefix and the datasource is only set when it differs
+// from the one inferred from the query
+func (m *MetricTrigger) toHub() *v1alpha1.MetricTrigger {
+	out := &v1alpha1.MetricTrigger{
+		Query:     m.Query,
+		Threshold: m.Threshold,
+		Operator:  m.Operator,
+		Duration:  m.Duration,
+		Baseline:  m.Baseline,
+	}
+	if prefix := queryPrefix(m.Datasource); prefix != "" && !strings.HasPrefix(m.Query, prefix) {
+		out.Query = prefix + m.Query
+	}
+	if m.Datasource != v1alpha1.InferDatasource(out.Query) {
+		out.Datasource = m.Datasource
+	}
+	return out
+}
+
+// metricTriggerFromHub converts a v1alpha1 metric trigger, making its
+// datasource explicit and dropping the query prefix it implies
+func metricTriggerFromHub(m *v1alpha1.MetricTrigger) *MetricTrigger {
+	datasource := m.EffectiveDatasource()
+	return &MetricTrigger{
+		Datasource: datasource,
+		Query:      strings.TrimPrefix(m.Query, queryPrefix(datasource)),
+		Threshold:  m.Threshold,
+		Operator:   m.Operator,
+		Duration:   m.Duration,
+		Baseline:   m.Baseline,
+	}
+}
+
+// queryPrefix returns the v1alpha1 query prefix of a datasource, if any
+func queryPrefix(datasource string) string {
+	switch datasource {
+	case v1alpha1.DatasourcePushed:
+		return v1alpha1.PushedQueryPrefix
+	case v1alpha1.DatasourcePattern:
+		return v1alpha1.PatternQueryPrefix
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func duration(d time.Duration) *metav1.Duration {
+	return &metav1.Duration{Duration: d}
+}
+
+func alphaPolicy() *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"team": "web"}},
+		Spec: v1alpha1.HealingPolicySpec{
+			Selector: v1alpha1.ResourceSelector{
+				Namespaces: []string{"default"},
+				Resources:  []v1alpha1.ResourceFilter{{APIVersion: "apps/v1", Kind: "Deployment"}},
+			},
+			Triggers: []v1alpha1.HealingTrigger{
+				{Name: "errors", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+					Query: `rate(http_errors_total[5m])`, Threshold: 0.1, Operator: ">",
+				}, CooldownPeriod: metav1.Duration{Duration: 5 * time.Minute}},
+				{Name: "restarts", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+					Query: "restart_count", Threshold: 5, Operator: ">",
+				}},
+				{Name: "queue", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+					Query: "custom:queue_depth", Threshold: 100, Operator: ">=",
+				}},
+				{Name: "leaks", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+					Query: "pattern:memory_leak", Threshold: 0, Operator: ">",
+				}},
+				{Name: "up", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+					Query: "up", Threshold: 1, Operator: "<", Datasource: v1alpha1.DatasourcePrometheus,
+				}},
+				{Name: "oom", Type: "event", EventTrigger: &v1alpha1.EventTrigger{Reason: "OOMKilling", Count: 1}, Severity: "critical"},
+				{Name: "nightly", Type: "schedule", ScheduleTrigger: &v1alpha1.ScheduleTrigger{Schedule: "@daily"}},
+			},
+			Actions:             []v1alpha1.HealingActionTemplate{{Name: "restart", Type: "restart"}},
+			Mode:                "automatic",
+			AIProfile:           &v1alpha1.AIProfile{Mode: "lite", AnalysisInterval: duration(10 * time.Minute), MaxPods: 20},
+			AIAnalysisInterval:  duration(time.Minute),
+			DependsOn:           []v1alpha1.PolicyDependency{{Name: "nodes"}},
+			RecordTriggerValues: true,
+		},
+		Status: v1alpha1.HealingPolicyStatus{ActionsTaken: 3, ActiveTriggers: []string{"errors"}},
+	}
+}
+
+func TestHealingPolicy_IsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, AddToScheme(scheme))
+
+	convertible, err := conversion.IsConvertible(scheme, &HealingPolicy{})
+	require.NoError(t, err)
+	assert.True(t, convertible)
+}
+
+func TestHealingPolicy_ConvertFrom(t *testing.T) {
+	policy := &HealingPolicy{}
+	require.NoError(t, policy.ConvertFrom(alphaPolicy()))
+
+	assert.Equal(t, "web", policy.Name)
+	assert.Equal(t, int32(3), policy.Status.ActionsTaken)
+	assert.Equal(t, &AISettings{
+		Mode:        "lite",
+		MinInterval: duration(time.Minute),
+		Lite:        &LiteAISettings{AnalysisInterval: duration(10 * time.Minute), MaxPods: 20},
+	}, policy.Spec.AI)
+
+	triggers := policy.Spec.Triggers
+	require.Len(t, triggers, 7)
+	tests := []struct {
+		datasource string
+		query      string
+	}{
+		{v1alpha1.DatasourcePrometheus, `rate(http_errors_total[5m])`},
+		{v1alpha1.DatasourceBuiltin, "restart_count"},
+		{v1alpha1.DatasourcePushed, "queue_depth"},
+		{v1alpha1.DatasourcePattern, "memory_leak"},
+		{v1alpha1.DatasourcePrometheus, "up"},
+	}
+	for i, tt := range tests {
+		require.NotNil(t, triggers[i].Metric, triggers[i].Name)
+		assert.Equal(t, tt.datasource, triggers[i].Metric.Datasource, triggers[i].Name)
+		assert.Equal(t, tt.query, triggers[i].Metric.Query, triggers[i].Name)
+	}
+	assert.Equal(t, "OOMKilling", triggers[5].Event.Reason)
+	assert.Nil(t, triggers[5].Metric)
+	assert.Equal(t, "@daily", triggers[6].Schedule.Schedule)
+}
+
+func TestHealingPolicy_ConvertTo(t *testing.T) {
+	policy := &HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: HealingPolicySpec{
+			Triggers: []HealingTrigger{
+				{Name: "queue", Metric: &MetricTrigger{Datasource: v1alpha1.DatasourcePushed, Query: "queue_depth", Threshold: 100, Operator: ">"}},
+				{Name: "rate", Metric: &MetricTrigger{Datasource: v1alpha1.DatasourceBuiltin, Query: "error_rate", Threshold: 1, Operator: ">"}},
+				{Name: "storm", RestartStorm: &v1alpha1.RestartStormTrigger{MinRestarts: 10}},
+			},
+			AI: &AISettings{MinInterval: duration(time.Minute)},
+		},
+	}
+
+	hub := &v1alpha1.HealingPolicy{}
+	require.NoError(t, policy.ConvertTo(hub))
+
+	triggers := hub.Spec.Triggers
+	require.Len(t, triggers, 3)
+	assert.Equal(t, "metric", triggers[0].Type)
+	assert.Equal(t, "custom:queue_depth", triggers[0].MetricTrigger.Query)
+	assert.Empty(t, triggers[0].MetricTrigger.Datasource, "inferred datasources are left implicit")
+	assert.Equal(t, "error_rate", triggers[1].MetricTrigger.Query)
+	assert.Empty(t, triggers[1].MetricTrigger.Datasource)
+	assert.Equal(t, "restartStorm", triggers[2].Type)
+	assert.Equal(t, int32(10), triggers[2].RestartStormTrigger.MinRestarts)
+	assert.Equal(t, duration(time.Minute), hub.Spec.AIAnalysisInterval)
+	assert.Nil(t, hub.Spec.AIProfile)
+}
+
+func TestHealingPolicy_RoundTrip(t *testing.T) {
+	original := alphaPolicy()
+
+	policy := &HealingPolicy{}
+	require.NoError(t, policy.ConvertFrom(original.DeepCopy()))
+	hub := &v1alpha1.HealingPolicy{}
+	require.NoError(t, policy.ConvertTo(hub))
+	assert.Equal(t, original, hub)
+
+	again := &HealingPolicy{}
+	require.NoError(t, again.ConvertFrom(hub))
+	assert.Equal(t, policy, again)
+}
@@ -0,0 +1,203 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// HealingPolicySpec defines the desired state of HealingPolicy
+type HealingPolicySpec struct {
+	// Selector defines which resources this policy applies to
+	Selector v1alpha1.ResourceSelector `json:"selector"`
+
+	// Triggers define conditions that activate healing
+	Triggers []HealingTrigger `json:"triggers"`
+
+	// Actions define what healing actions to take
+	Actions []v1alpha1.HealingActionTemplate `json:"actions"`
+
+	// SafetyRules define constraints on healing actions
+	SafetyRules v1alpha1.SafetyRules `json:"safetyRules,omitempty"`
+
+	// Mode defines whether actions are automatic or require approval
+	// +kubebuilder:validation:Enum=monitor;dryrun;automatic;manual
+	// +kubebuilder:default=monitor
+	Mode string `json:"mode,omitempty"`
+
+	// RolloutPercentage limits automatic mode to this percentage of the
+	// matched targets, chosen by a stable hash of the target; actions on the
+	// other targets run as dry-runs. Defaults to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	RolloutPercentage *int32 `json:"rolloutPercentage,omitempty"`
+
+	// Paused stops trigger evaluation and holds the policy's pending actions
+	// without deleting the policy
+	Paused bool `json:"paused,omitempty"`
+
+	// ActionTimeout for the actions created by the policy
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	ActionTimeout *metav1.Duration `json:"actionTimeout,omitempty"`
+
+	// RetryPolicy for the actions created by the policy
+	RetryPolicy *v1alpha1.RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// AI controls when and how much the AI subsystem analyzes for the policy
+	AI *AISettings `json:"ai,omitempty"`
+
+	// SeverityMapping assigns severities to triggers that do not set one.
+	// The first matching entry wins; unmatched triggers are warnings.
+	SeverityMapping []v1alpha1.SeverityMapping `json:"severityMapping,omitempty"`
+
+	// DependsOn lists policies that must be evaluated and settled before
+	// this one each cycle
+	DependsOn []v1alpha1.PolicyDependency `json:"dependsOn,omitempty"`
+
+	// RecordTriggerValues exports the value each metric trigger is
+	// evaluated against, and the threshold it is compared with, as
+	// Prometheus gauges
+	RecordTriggerValues bool `json:"recordTriggerValues,omitempty"`
+}
+
+// AISettings groups the AI analysis settings of a policy
+type AISettings struct {
+	// Mode is full, which analyzes every evaluation, or lite, which bounds
+	// the cost of analyses as configured in Lite
+	// +kubebuilder:validation:Enum=full;lite
+	// +kubebuilder:default=full
+	Mode string `json:"mode,omitempty"`
+
+	// MinInterval is the minimum time between fresh AI analyses. Triggers
+	// firing in between are filtered with the most recent analysis. Unset
+	// runs an analysis whenever triggers fire.
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+
+	// Lite bounds the analyses in lite mode
+	Lite *LiteAISettings `json:"lite,omitempty"`
+}
+
+// LiteAISettings bound the analyses of lite mode
+type LiteAISettings struct {
+	// AnalysisInterval is the minimum time between analyses. Defaults to 10m.
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	AnalysisInterval *metav1.Duration `json:"analysisInterval,omitempty"`
+
+	// MaxPods is the number of pods sampled per analysis. Defaults to 50.
+	// +kubebuilder:validation:Minimum=1
+	MaxPods int32 `json:"maxPods,omitempty"`
+}
+
+// HealingTrigger defines when to initiate healing. Exactly one kind of
+// trigger must be set; its field names the trigger type.
+// +kubebuilder:validation:XValidation:rule="[has(self.metric), has(self.event), has(self.condition), has(self.log), has(self.restartStorm), has(self.schedule), has(self.stuckTerminating), has(self.plugin)].filter(x, x).size() == 1",message="exactly one of metric, event, condition, log, restartStorm, schedule, stuckTerminating and plugin must be set"
+type HealingTrigger struct {
+	// Name of this trigger
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Metric fires on the value of a metric query
+	Metric *MetricTrigger `json:"metric,omitempty"`
+
+	// Event fires on Kubernetes events
+	Event *v1alpha1.EventTrigger `json:"event,omitempty"`
+
+	// Condition fires on resource conditions
+	Condition *v1alpha1.ConditionTrigger `json:"condition,omitempty"`
+
+	// Log fires on pod log patterns
+	Log *v1alpha1.LogTrigger `json:"log,omitempty"`
+
+	// RestartStorm fires on namespace-wide container restart storms
+	RestartStorm *v1alpha1.RestartStormTrigger `json:"restartStorm,omitempty"`
+
+	// Schedule fires on a cron schedule for proactive actions
+	Schedule *v1alpha1.ScheduleTrigger `json:"schedule,omitempty"`
+
+	// StuckTerminating fires on resources stuck in Terminating on their
+	// finalizers
+	StuckTerminating *v1alpha1.StuckTerminatingTrigger `json:"stuckTerminating,omitempty"`
+
+	// Plugin is evaluated by a registered plugin
+	Plugin *v1alpha1.PluginTrigger `json:"plugin,omitempty"`
+
+	// CooldownPeriod prevents trigger from firing too frequently
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	CooldownPeriod metav1.Duration `json:"cooldownPeriod,omitempty"`
+
+	// ClearAfterEvaluations is the number of consecutive evaluations the
+	// trigger must stay quiet before its state is cleared from the status
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	ClearAfterEvaluations int32 `json:"clearAfterEvaluations,omitempty"`
+
+	// Severity of the trigger, overriding the policy's severity mapping
+	// +kubebuilder:validation:Enum=info;warning;critical
+	Severity string `json:"severity,omitempty"`
+}
+
+// MetricTrigger compares the value of a query against a threshold
+type MetricTrigger struct {
+	// Datasource answering the query: prometheus for PromQL, builtin for
+	// the operator's basic metric names, pushed for metrics pushed to the
+	// receiver and pattern for the patterns of a detector
+	// +kubebuilder:validation:Enum=prometheus;builtin;pushed;pattern
+	Datasource string `json:"datasource"`
+
+	// Query in the datasource: PromQL, a builtin metric name, a pushed
+	// metric name or a detector name
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+
+	// Threshold for the metric
+	Threshold float64 `json:"threshold"`
+
+	// Operator for comparison
+	// +kubebuilder:validation:Enum=">";"<";">=";"<="
+	Operator string `json:"operator"`
+
+	// Duration the condition must be true
+	// +kubebuilder:default="2m"
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// Baseline makes the threshold a multiple of the query's average over
+	// the same hour of the previous days. Requires the prometheus
+	// datasource.
+	// +optional
+	Baseline *v1alpha1.MetricBaseline `json:"baseline,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=hp
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="Actions Taken",type="integer",JSONPath=".status.actionsTaken"
+// +kubebuilder:printcolumn:name="Last Action",type="date",JSONPath=".status.lastActionTime"
+// +kubebuilder:printcolumn:name="AI Analysis",type="string",JSONPath=".status.aiAnalysis.phase",priority=1
+// +kubebuilder:printcolumn:name="MTTR",type="string",JSONPath=".status.recovery.meanTimeToRecovery",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HealingPolicy is the Schema for the healingpolicies API
+type HealingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HealingPolicySpec            `json:"spec,omitempty"`
+	Status v1alpha1.HealingPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HealingPolicyList contains a list of HealingPolicy
+type HealingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HealingPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HealingPolicy{}, &HealingPolicyList{})
+}
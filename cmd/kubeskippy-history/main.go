@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The KubeSkippy Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubeskippy-history lists every HealingAction taken on a workload, from
+// policies in any namespace, oldest first. It only reads from the cluster.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeskippyv1alpha1 "github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/controller"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(kubeskippyv1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	var kind string
+	var namespace string
+	var name string
+	var output string
+	var timeout time.Duration
+	flag.StringVar(&kind, "kind", "Deployment", "Kind of the workload")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace of the workload")
+	flag.StringVar(&name, "name", "", "Name of the workload")
+	flag.StringVar(&output, "o", "text", "Output format: text or json")
+	flag.DurationVar(&timeout, "timeout", time.Minute, "Time allowed for listing the actions")
+	flag.Parse()
+
+	if err := run(kind, namespace, name, output, timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(kind, namespace, name, output string, timeout time.Duration) error {
+	if name == "" {
+		return errors.New("a workload name is required (-name)")
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+
+	kubeConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	c, err := client.New(kubeConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// The API server cannot select on the target, so filter client-side;
+	// the operator uses its cache index for the same query
+	actionList := &kubeskippyv1alpha1.HealingActionList{}
+	if err := c.List(ctx, actionList); err != nil {
+		return fmt.Errorf("failed to list healing actions: %w", err)
+	}
+	actions := controller.FilterActionsForTarget(actionList.Items, kind, namespace, name)
+
+	if output == "json" {
+		if actions == nil {
+			actions = []kubeskippyv1alpha1.HealingAction{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(actions)
+	}
+	return render(os.Stdout, controller.ActionTargetKey(kind, namespace, name), actions)
+}
+
+// render writes the actions as a table
+func render(w io.Writer, target string, actions []kubeskippyv1alpha1.HealingAction) error {
+	if len(actions) == 0 {
+		_, err := fmt.Fprintf(w, "No healing actions on %s\n", target)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CREATED\tACTION\tPOLICY\tTYPE\tPHASE\tDRY RUN\tMESSAGE")
+	for i := range actions {
+		action := &actions[i]
+		message := ""
+		if action.Status.Result != nil {
+			message = action.Status.Result.Message
+		}
+		fmt.Fprintf(tw, "%s\t%s/%s\t%s/%s\t%s\t%s\t%t\t%s\n",
+			action.CreationTimestamp.Format(time.RFC3339),
+			action.Namespace, action.Name,
+			action.Spec.PolicyRef.Namespace, action.Spec.PolicyRef.Name,
+			action.Spec.Action.Type, action.Status.Phase, action.Spec.DryRun, message)
+	}
+	return tw.Flush()
+}
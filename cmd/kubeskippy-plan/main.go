@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The KubeSkippy Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubeskippy-plan previews what a proposed HealingPolicy would do against
+// the live cluster: the resources it matches, which triggers are currently
+// true and the actions it would generate. It never writes to the cluster.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	kubeskippyv1alpha1 "github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/controller"
+	kubemetrics "github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/safety"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(kubeskippyv1alpha1.AddToScheme(scheme))
+}
+
+// errReadOnly is returned for any write attempted while planning
+var errReadOnly = errors.New("kubeskippy-plan is read-only")
+
+func main() {
+	var policyFile string
+	var namespace string
+	var output string
+	var prometheusURL string
+	var timeout time.Duration
+	flag.StringVar(&policyFile, "f", "", "The HealingPolicy YAML file to evaluate")
+	flag.StringVar(&namespace, "namespace", "", "Namespace for the policy if the file does not set one")
+	flag.StringVar(&output, "o", "text", "Output format: text or json")
+	flag.StringVar(&prometheusURL, "prometheus-url", "", "Prometheus URL for prometheus triggers")
+	flag.DurationVar(&timeout, "timeout", time.Minute, "Time allowed for evaluating the policy")
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts), zap.WriteTo(os.Stderr)))
+
+	if err := run(policyFile, namespace, output, prometheusURL, timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(policyFile, namespace, output, prometheusURL string, timeout time.Duration) error {
+	if policyFile == "" {
+		return errors.New("a policy file is required (-f)")
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+
+	policy, err := loadPolicy(policyFile)
+	if err != nil {
+		return err
+	}
+	if policy.Namespace == "" {
+		policy.Namespace = namespace
+	}
+	if policy.Namespace == "" {
+		policy.Namespace = "default"
+	}
+
+	kubeConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	c, err := newReadOnlyClient(kubeConfig)
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+	// The metrics server is optional, resource triggers report errors without it
+	metricsClientset, _ := metricsclient.NewForConfig(kubeConfig)
+
+	cfg := config.NewDefaultConfig()
+	metricsCollector := kubemetrics.NewCollector(c, clientset, metricsClientset)
+	if prometheusURL != "" {
+		if err := metricsCollector.WithPrometheus(prometheusURL); err != nil {
+			return fmt.Errorf("failed to configure Prometheus: %w", err)
+		}
+	}
+
+	reconciler := &controller.HealingPolicyReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		Config:           cfg,
+		MetricsCollector: metricsCollector,
+		SafetyController: safety.NewController(c, cfg.Safety, safety.NewInMemoryActionStore(), nil),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	plan, err := reconciler.PlanPolicy(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+	plan.Render(os.Stdout)
+	return nil
+}
+
+// loadPolicy decodes a HealingPolicy from a YAML or JSON file
+func loadPolicy(path string) (*kubeskippyv1alpha1.HealingPolicy, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policy file: %w", err)
+	}
+	defer file.Close()
+
+	policy := &kubeskippyv1alpha1.HealingPolicy{}
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(policy); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %w", err)
+	}
+	if policy.Kind != "" && policy.Kind != "HealingPolicy" {
+		return nil, fmt.Errorf("expected a HealingPolicy, got %s", policy.Kind)
+	}
+	return policy, nil
+}
+
+// newReadOnlyClient returns a client that rejects every write so planning
+// cannot change the cluster
+func newReadOnlyClient(kubeConfig *rest.Config) (client.Client, error) {
+	c, err := client.NewWithWatch(kubeConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return interceptor.NewClient(c, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			return errReadOnly
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			return errReadOnly
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return errReadOnly
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			return errReadOnly
+		},
+		DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+			return errReadOnly
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, subResourceObj client.Object, opts ...client.SubResourceCreateOption) error {
+			return errReadOnly
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			return errReadOnly
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			return errReadOnly
+		},
+	}), nil
+}
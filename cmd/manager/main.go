@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 	"time"
@@ -25,22 +26,38 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
-	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	kubeskippyv1alpha1 "github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubeskippyv1beta1 "github.com/kubeskippy/kubeskippy/api/v1beta1"
+	"github.com/kubeskippy/kubeskippy/internal/admission"
 	"github.com/kubeskippy/kubeskippy/internal/ai"
+	"github.com/kubeskippy/kubeskippy/internal/archive"
 	"github.com/kubeskippy/kubeskippy/internal/controller"
+	"github.com/kubeskippy/kubeskippy/internal/debug"
+	"github.com/kubeskippy/kubeskippy/internal/events"
+	"github.com/kubeskippy/kubeskippy/internal/faults"
 	kubemetrics "github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/migration"
+	"github.com/kubeskippy/kubeskippy/internal/notify"
+	"github.com/kubeskippy/kubeskippy/internal/recipes"
 	"github.com/kubeskippy/kubeskippy/internal/remediation"
 	"github.com/kubeskippy/kubeskippy/internal/safety"
+	"github.com/kubeskippy/kubeskippy/internal/serving"
+	"github.com/kubeskippy/kubeskippy/internal/telemetry"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/internal/watchdog"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
+	"github.com/kubeskippy/kubeskippy/pkg/plugins"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/kubernetes"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
@@ -55,6 +72,8 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(kubeskippyv1alpha1.AddToScheme(scheme))
+	// v1beta1 makes the webhook server convert HealingPolicies
+	utilruntime.Must(kubeskippyv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -65,6 +84,12 @@ func main() {
 	var probeAddr string
 	var watchNamespace string
 	var dryRun bool
+	var enableWebhooks bool
+	var secureMetrics bool
+	var certDir string
+	var probeTLS bool
+	var migrateStorage bool
+	var telemetryPreview bool
 
 	flag.StringVar(&configFile, "config", "", "The controller config file")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -74,6 +99,15 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&watchNamespace, "namespace", "", "Namespace to watch (empty means all namespaces)")
 	flag.BoolVar(&dryRun, "dry-run", false, "Run in dry-run mode (no actual healing actions)")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Serve the admission webhooks")
+	flag.BoolVar(&secureMetrics, "metrics-secure", false,
+		"Serve metrics over HTTPS to callers authorized through TokenReview and SubjectAccessReview")
+	flag.StringVar(&certDir, "tls-cert-dir", "", "Directory holding the serving certificate (tls.crt) and key (tls.key)")
+	flag.BoolVar(&probeTLS, "health-probe-tls", false, "Serve the health probes over HTTPS; requires --tls-cert-dir")
+	flag.BoolVar(&migrateStorage, "migrate-storage-version", false,
+		"Rewrite the stored kubeskippy.io resources in their CRDs' storage version and exit")
+	flag.BoolVar(&telemetryPreview, "telemetry-preview", false,
+		"Print the telemetry report that would be sent now and exit, whether or not telemetry is enabled")
 
 	opts := zap.Options{
 		Development: true,
@@ -83,22 +117,50 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// Run as the migration Job after upgrading the CRDs
+	if migrateStorage {
+		if err := migrateStorageVersion(ctrl.SetupSignalHandler()); err != nil {
+			setupLog.Error(err, "storage version migration failed")
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg := config.NewDefaultConfig()
 	if configFile != "" {
-		// TODO: Load config from file
 		setupLog.Info("Loading config from file", "path", configFile)
+		var err error
+		if cfg, err = config.LoadFile(configFile); err != nil {
+			setupLog.Error(err, "unable to load config file")
+			os.Exit(1)
+		}
 	}
 
-	// Override with command line flags
-	if metricsAddr != "" {
+	// Override with the command line flags that were set, so that their
+	// defaults do not replace the config file
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["metrics-bind-address"] {
 		cfg.MetricsAddr = metricsAddr
 	}
-	if probeAddr != "" {
+	if set["health-probe-bind-address"] {
 		cfg.ProbeAddr = probeAddr
 	}
-	cfg.EnableLeaderElection = enableLeaderElection
-	cfg.WatchNamespace = watchNamespace
+	if set["leader-elect"] {
+		cfg.EnableLeaderElection = enableLeaderElection
+	}
+	if set["namespace"] {
+		cfg.WatchNamespace = watchNamespace
+	}
+	if set["enable-webhooks"] {
+		cfg.EnableWebhooks = enableWebhooks
+	}
+	cfg.Serving.SecureMetrics = cfg.Serving.SecureMetrics || secureMetrics
+	cfg.Serving.ProbeTLS = cfg.Serving.ProbeTLS || probeTLS
+	if certDir != "" {
+		cfg.Serving.CertDir = certDir
+	}
 	if dryRun {
 		cfg.Safety.DryRunMode = true
 	}
@@ -109,11 +171,35 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create manager options
+	// Show what telemetry would send before opting in
+	if telemetryPreview {
+		if err := previewTelemetry(ctrl.SetupSignalHandler(), cfg); err != nil {
+			setupLog.Error(err, "telemetry preview failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Load the serving certificate, reloaded when it is rotated
+	var servingCert *serving.Certificate
+	if cfg.Serving.CertDir != "" {
+		cert, err := serving.LoadCertificate(cfg.Serving)
+		if err != nil {
+			setupLog.Error(err, "unable to load serving certificate")
+			os.Exit(1)
+		}
+		servingCert = cert
+	}
+
+	// Create manager options; TLS probes replace the manager's endpoint
+	probeBindAddress := cfg.ProbeAddr
+	if cfg.Serving.ProbeTLS {
+		probeBindAddress = "0"
+	}
 	mgrOpts := ctrl.Options{
 		Scheme:                 scheme,
-		Metrics:                server.Options{BindAddress: cfg.MetricsAddr},
-		HealthProbeBindAddress: cfg.ProbeAddr,
+		Metrics:                serving.MetricsOptions(cfg.MetricsAddr, cfg.Serving, servingCert),
+		HealthProbeBindAddress: probeBindAddress,
 		LeaderElection:         cfg.EnableLeaderElection,
 		LeaderElectionID:       "kubeskippy.io",
 	}
@@ -125,17 +211,64 @@ func main() {
 		os.Exit(1)
 	}
 
+	if servingCert != nil {
+		if err := mgr.Add(servingCert); err != nil {
+			setupLog.Error(err, "unable to watch serving certificate")
+			os.Exit(1)
+		}
+	}
+	setupLog.Info("Serving endpoints", "secureMetrics", cfg.Serving.SecureMetrics,
+		"probeTLS", cfg.Serving.ProbeTLS, "debugTLS", cfg.Serving.DebugTLS)
+
 	// Initialize components
 	setupLog.Info("Initializing components")
 
-	// Create safety controller with in-memory store
-	safetyStore := safety.NewInMemoryActionStore()
+	ctx := ctrl.SetupSignalHandler()
+
+	// Create safety controller with the configured action store, in memory
+	// unless managers share a Redis store
+	var safetyStore safety.ActionStore = safety.NewInMemoryActionStore()
+	if cfg.Safety.ActionStore.Backend == config.ActionStoreRedis {
+		redisStore, err := safety.LoadRedisActionStore(ctx, mgr.GetAPIReader(), cfg.Safety.ActionStore.Redis)
+		if err != nil {
+			setupLog.Error(err, "unable to connect to the redis action store")
+			os.Exit(1)
+		}
+		defer redisStore.Close()
+		safetyStore = redisStore
+		setupLog.Info("Using redis action store", "secret",
+			cfg.Safety.ActionStore.Redis.SecretNamespace+"/"+cfg.Safety.ActionStore.Redis.SecretName)
+	}
 	safetyController := safety.NewController(mgr.GetClient(), cfg.Safety, safetyStore, nil)
 
 	// Start cleanup loop for old action records
-	ctx := ctrl.SetupSignalHandler()
 	safetyController.StartCleanupLoop(ctx, 24*time.Hour)
 
+	// Sign audit records if a signing key is configured
+	if cfg.Safety.AuditLog.Signing.Enabled {
+		signer, err := safety.LoadAuditSigner(ctx, mgr.GetAPIReader(), cfg.Safety.AuditLog.Signing)
+		if err != nil {
+			setupLog.Error(err, "unable to load audit signing key")
+			os.Exit(1)
+		}
+		safetyController.SetAuditSigner(signer)
+		setupLog.Info("Audit record signing enabled", "keyID", signer.KeyID())
+	}
+
+	// Apply business hours and release freezes if configured
+	var releaseCalendar controller.ReleaseCalendar
+	if cfg.Safety.Calendar.Enabled {
+		calendar, err := safety.NewCalendar(mgr.GetClient(), cfg.Safety.Calendar)
+		if err != nil {
+			setupLog.Error(err, "invalid safety calendar")
+			os.Exit(1)
+		}
+		safetyController.SetCalendar(calendar)
+		releaseCalendar = calendar
+		setupLog.Info("Safety calendar enabled", "timeZone", cfg.Safety.Calendar.TimeZone,
+			"blackoutConfigMap", cfg.Safety.Calendar.BlackoutConfigMap)
+	}
+
 	// Create Kubernetes clients for metrics collector
 	kubeConfig := ctrl.GetConfigOrDie()
 	clientset, err := kubernetes.NewForConfig(kubeConfig)
@@ -162,14 +295,149 @@ func main() {
 			// Continue without Prometheus - it's optional
 		} else {
 			setupLog.Info("Prometheus integration enabled successfully")
+			metricsCollector.WithQueryCache(cfg.Metrics.QueryCacheTTL)
+		}
+	}
+
+	// Accept metrics pushed by applications if enabled
+	if cfg.Metrics.PushReceiver.Enabled {
+		pushReceiver := kubemetrics.NewPushReceiver(cfg.Metrics.PushReceiver.StatsDAddr,
+			cfg.Metrics.PushReceiver.OTLPAddr, cfg.Metrics.PushReceiver.TTL)
+		if err := mgr.Add(pushReceiver); err != nil {
+			setupLog.Error(err, "unable to add metrics push receiver")
+			os.Exit(1)
 		}
+		metricsCollector.WithPushReceiver(pushReceiver)
+	}
+
+	// Load the plugins providing custom trigger evaluators and detectors
+	pluginRegistry := plugins.NewRegistry()
+	if cfg.Plugins.Directory != "" {
+		loaded, err := pluginRegistry.LoadDirectory(cfg.Plugins.Directory)
+		if err != nil {
+			setupLog.Error(err, "unable to load plugins")
+			os.Exit(1)
+		}
+		evaluators, detectors := pluginRegistry.Names()
+		setupLog.Info("Loaded plugins", "files", loaded, "evaluators", evaluators, "detectors", detectors,
+			"seriesDetectors", pluginRegistry.DetectorNames())
+	}
+	metricsCollector.WithPlugins(pluginRegistry)
+	metricsCollector.WithLowSignalSampling(cfg.Metrics.MaxLowSignalPods)
+	if cfg.Metrics.CapacitySignals.Enabled {
+		if err := metricsCollector.WithCapacitySignals(cfg.Metrics.CapacitySignals.ClusterAutoscalerStatus); err != nil {
+			setupLog.Error(err, "invalid capacity signals configuration")
+			os.Exit(1)
+		}
+	}
+
+	// Run the enabled detectors over the time series of the advanced
+	// collector
+	var policyMetricsCollector controller.MetricsCollector = metricsCollector
+	if len(cfg.Metrics.PatternDetectors) > 0 {
+		advancedCollector := kubemetrics.NewAdvancedCollector(metricsCollector)
+		if err := advancedCollector.WithDetectors(pluginRegistry, cfg.Metrics.PatternDetectors); err != nil {
+			setupLog.Error(err, "unable to enable pattern detectors")
+			os.Exit(1)
+		}
+		policyMetricsCollector = advancedCollector
+		setupLog.Info("Pattern detectors enabled", "detectors", cfg.Metrics.PatternDetectors)
+	}
+
+	// Record collection timings for the profiling endpoints if enabled
+	var collectorStats *kubemetrics.CollectorStats
+	if cfg.Profiling.Enabled {
+		collectorStats = kubemetrics.NewCollectorStats()
+		metricsCollector.WithStats(collectorStats)
 	}
 
 	// Create remediation engine with action recorder
 	actionRecorder := remediation.NewInMemoryActionRecorder(24 * time.Hour)
 	actionRecorder.StartCleanupLoop(ctx, 1*time.Hour)
-	remediationEngine := remediation.NewEngine(mgr.GetClient(), actionRecorder)
+	// Changes made by the engine are attributed to KubeSkippy so manual
+	// overrides can be detected from managedFields
+	engineClient := client.WithFieldOwner(mgr.GetClient(), kubetypes.FieldManager)
+	if cfg.Safety.DryRunMode {
+		// Reject writes from executors at the client as well, in case one
+		// runs despite dry-run mode
+		engineClient = remediation.NewReadOnlyClient(engineClient)
+		setupLog.Info("Read-only client enabled for remediation executors")
+	}
+	// Set up below, after the AI coordinator the watchdog watches
+	var operatorWatchdog controller.Watchdog
+	if cfg.Watchdog.Enabled {
+		// Stop the writes of actions already running when the operator
+		// enters safe mode, not only new actions
+		engineClient = remediation.NewSafeModeClient(engineClient, func() bool {
+			return operatorWatchdog != nil && operatorWatchdog.SafeMode()
+		})
+	}
+	// Inject failures into the engine and the AI analyzer for e2e tests
+	// and chaos drills
+	faultInjector, err := faults.New(cfg.FaultInjection)
+	if err != nil {
+		setupLog.Error(err, "unable to enable fault injection")
+		os.Exit(1)
+	}
+	if faultInjector != nil {
+		setupLog.Info("Fault injection enabled", "executorFailureRate", cfg.FaultInjection.ExecutorFailureRate,
+			"aiDelayRate", cfg.FaultInjection.AIDelayRate, "aiErrorRate", cfg.FaultInjection.AIErrorRate,
+			"apiErrorRate", cfg.FaultInjection.APIErrorRate)
+	}
+	engineClient = faults.NewClient(engineClient, faultInjector)
+	remediationEngine := remediation.NewEngine(engineClient, actionRecorder)
+	remediationEngine.SetFaultInjector(faultInjector)
+	// Read-only engines can't annotate, and dry runs change nothing
+	remediationEngine.SetChangeCauseAnnotations(cfg.Remediation.ChangeCauseAnnotations && !cfg.Safety.DryRunMode)
+	podExecutor := remediation.NewPodExecutor(kubeConfig, clientset)
+	if !cfg.Safety.DryRunMode {
+		// Commands run in containers can't be rejected as dry runs, and
+		// hooks and helper pods create pods
+		helperPods := remediation.NewHelperPodRunner(engineClient, clientset)
+		hookRunner := remediation.NewHookRunner(engineClient, clientset, podExecutor)
+		hookRunner.SetHelperPodRunner(helperPods)
+		remediationEngine.SetHookRunner(hookRunner)
+		remediationEngine.SetPodExecutor(podExecutor)
+		remediationEngine.SetHelperPodRunner(helperPods)
+	}
+	if cfg.Remediation.RBACPreflight {
+		remediationEngine.SetRBACPreflight(remediation.NewRBACPreflight(mgr.GetClient()))
+	}
+	if cfg.Remediation.ServerDryRun.Enabled {
+		dryRunClient, err := newServerDryRunClient(mgr, cfg.Remediation.ServerDryRun)
+		if err != nil {
+			setupLog.Error(err, "unable to create server-side dry-run client")
+			os.Exit(1)
+		}
+		remediationEngine.SetServerDryRun(client.WithFieldOwner(dryRunClient, kubetypes.FieldManager))
+		setupLog.Info("Server-side dry runs enabled", "impersonateUser", cfg.Remediation.ServerDryRun.ImpersonateUser)
+	}
+	if cfg.Remediation.Capacity.Enabled {
+		capacity, err := remediation.NewCapacityChecker(mgr.GetClient(),
+			cfg.Remediation.Capacity.ClusterAutoscalerStatus, cfg.Remediation.Capacity.RecommendNodeScaling)
+		if err != nil {
+			setupLog.Error(err, "invalid capacity check configuration")
+			os.Exit(1)
+		}
+		remediationEngine.SetCapacityChecker(capacity)
+	}
 	remediationEngine.StartCleanupRoutine(ctx)
+	// Nothing is left to clean up in dry-run mode
+	if cfg.Remediation.Janitor.Enabled && !cfg.Safety.DryRunMode {
+		janitor := remediation.NewJanitor(engineClient, cfg.Remediation.Janitor.Interval, cfg.Remediation.Janitor.GracePeriod)
+		if err := mgr.Add(janitor); err != nil {
+			setupLog.Error(err, "unable to add janitor")
+			os.Exit(1)
+		}
+	}
+	if cfg.Telemetry.Enabled && !telemetry.Disabled() {
+		reporter := telemetry.NewReporter(mgr.GetClient(), cfg.Telemetry, cfg.AI.Provider)
+		if err := mgr.Add(reporter); err != nil {
+			setupLog.Error(err, "unable to add telemetry reporter")
+			os.Exit(1)
+		}
+		setupLog.Info("Anonymous telemetry enabled", "endpoint", cfg.Telemetry.Endpoint, "interval", cfg.Telemetry.Interval)
+	}
 
 	// Initialize AI analyzer with fallback
 	var aiAnalyzer controller.AIAnalyzer
@@ -179,13 +447,42 @@ func main() {
 			setupLog.Error(err, "Failed to create AI analyzer, disabling AI features")
 			aiAnalyzer = &ai.NoOpAnalyzer{}
 		} else {
+			analyzer.WithNamespaceReader(mgr.GetClient())
+			analyzer.WithFaultInjection(faultInjector)
 			aiAnalyzer = analyzer
-			setupLog.Info("AI analyzer initialized successfully", "provider", cfg.AI.Provider)
+			setupLog.Info("AI analyzer initialized successfully", "provider", cfg.AI.Provider,
+				"external", ai.IsExternalProvider(cfg.AI.Provider))
 		}
 	} else {
 		aiAnalyzer = &ai.NoOpAnalyzer{}
 		setupLog.Info("AI features disabled - no provider configured")
 	}
+	// Reports ask for tuning advice directly, outside the shared analyses
+	tuningAnalyzer := aiAnalyzer
+	var coordinator *ai.Coordinator
+	if cfg.AI.Provider != "" && cfg.AI.CoordinationInterval > 0 {
+		coordinator = ai.NewCoordinator(aiAnalyzer, cfg.AI.CoordinationInterval)
+		aiAnalyzer = coordinator
+		setupLog.Info("Sharing AI analyses between policies", "interval", cfg.AI.CoordinationInterval)
+	}
+
+	// Monitor the operator's own health if enabled
+	if cfg.Watchdog.Enabled {
+		wd := watchdog.NewWatchdog(mgr.GetClient(), cfg.Watchdog)
+		if coordinator != nil {
+			wd.SetAIQueue(coordinator.Waiting)
+			wd.AddRemediation(watchdog.CheckAIQueue, watchdog.Remediation{
+				Name: "reset-ai-coordinator",
+				Run:  func(context.Context) error { return coordinator.Reset() },
+			})
+		}
+		if err := mgr.Add(wd); err != nil {
+			setupLog.Error(err, "unable to add watchdog")
+			os.Exit(1)
+		}
+		operatorWatchdog = wd
+		setupLog.Info("Operator watchdog enabled", "operatorHealth", cfg.Watchdog.Namespace+"/"+cfg.Watchdog.Name)
+	}
 
 	// Initialize global AI metrics
 	kubemetrics.InitializeGlobalAIMetrics()
@@ -193,43 +490,192 @@ func main() {
 
 	setupLog.Info("Safety controller, metrics collector, and remediation engine initialized")
 
+	// Post incident summaries to the issue tracker if configured
+	var notifier controller.ActionNotifier
+	var triggerNotifier controller.TriggerNotifier
+	var escalationNotifier controller.EscalationNotifier
+	if cfg.IssueTracker.Enabled {
+		issueTracker, err := notify.LoadIssueTrackerNotifier(ctx, mgr.GetAPIReader(), cfg.IssueTracker)
+		if err != nil {
+			setupLog.Error(err, "unable to configure issue tracker notifications")
+			os.Exit(1)
+		}
+		notifier = issueTracker
+		triggerNotifier = issueTracker
+		escalationNotifier = issueTracker
+		setupLog.Info("Issue tracker notifications enabled", "provider", cfg.IssueTracker.Provider)
+	}
+
 	// Setup controllers
-	if err = (&controller.HealingPolicyReconciler{
+	policyReconciler := &controller.HealingPolicyReconciler{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		Config:           cfg,
-		MetricsCollector: metricsCollector,
+		MetricsCollector: policyMetricsCollector,
 		SafetyController: safetyController,
 		AIAnalyzer:       aiAnalyzer,
-	}).SetupWithManager(mgr); err != nil {
+		Events:           events.NewAggregator(mgr.GetEventRecorderFor("healingpolicy-controller"), cfg.Events),
+		Notifier:         triggerNotifier,
+		Escalations:      escalationNotifier,
+		Watchdog:         operatorWatchdog,
+		Calendar:         releaseCalendar,
+		Stats:            collectorStats,
+	}
+	if err = policyReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HealingPolicy")
 		os.Exit(1)
 	}
 
+	// Serve the policy testing endpoint if configured
+	if cfg.PolicyTesting.Enabled {
+		testServer, err := controller.LoadPolicyTestServer(ctx, mgr.GetAPIReader(), policyReconciler, cfg.PolicyTesting)
+		if err != nil {
+			setupLog.Error(err, "unable to configure policy testing endpoint")
+			os.Exit(1)
+		}
+		if cfg.Serving.DebugTLS {
+			testServer.WithTLS(servingCert.TLSConfig())
+		}
+		if err := mgr.Add(testServer); err != nil {
+			setupLog.Error(err, "unable to add policy testing endpoint")
+			os.Exit(1)
+		}
+		setupLog.Info("Policy testing endpoint enabled", "address", cfg.PolicyTesting.BindAddress)
+	}
+
+	// Serve the profiling endpoints if configured
+	if cfg.Profiling.Enabled {
+		debugServer, err := debug.LoadServer(ctx, mgr.GetAPIReader(), collectorStats, cfg.Profiling)
+		if err != nil {
+			setupLog.Error(err, "unable to configure profiling endpoints")
+			os.Exit(1)
+		}
+		if cfg.Serving.DebugTLS {
+			debugServer.WithTLS(servingCert.TLSConfig())
+		}
+		if err := mgr.Add(debugServer); err != nil {
+			setupLog.Error(err, "unable to add profiling endpoints")
+			os.Exit(1)
+		}
+		setupLog.Info("Profiling endpoints enabled", "address", cfg.Profiling.BindAddress, "pprof", cfg.Profiling.EnablePprof)
+	}
+
 	if err = (&controller.HealingActionReconciler{
 		Client:            mgr.GetClient(),
 		Scheme:            mgr.GetScheme(),
 		Config:            cfg,
 		RemediationEngine: remediationEngine,
 		SafetyController:  safetyController,
+		Notifier:          notifier,
+		Events:            events.NewAggregator(mgr.GetEventRecorderFor("healingaction-controller"), cfg.Events),
+		Watchdog:          operatorWatchdog,
+		Calendar:          releaseCalendar,
+		MetricsCollector:  metricsCollector,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HealingAction")
 		os.Exit(1)
 	}
+
+	if err = (&controller.HealingReportReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		AIAnalyzer: tuningAnalyzer,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HealingReport")
+		os.Exit(1)
+	}
+
+	if err = (&controller.AIDecisionReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AIDecision")
+		os.Exit(1)
+	}
+
+	if err = (&controller.HealingPolicyBundleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HealingPolicyBundle")
+		os.Exit(1)
+	}
+	if cfg.EnableWebhooks {
+		if err = (&kubeskippyv1alpha1.HealingPolicy{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HealingPolicy")
+			os.Exit(1)
+		}
+		actionWebhook := &admission.HealingActionWebhook{
+			Client:    mgr.GetClient(),
+			Safety:    safetyController,
+			Simulator: remediation.NewCascadeSimulator(mgr.GetClient()),
+		}
+		if err = actionWebhook.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HealingAction")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	// Install the built-in recipe policies if enabled
+	if cfg.Recipes.Enabled {
+		installer, err := recipes.NewInstaller(mgr.GetClient(), cfg.Recipes)
+		if err != nil {
+			setupLog.Error(err, "invalid recipes configuration")
+			os.Exit(1)
+		}
+		if err := mgr.Add(installer); err != nil {
+			setupLog.Error(err, "unable to add recipe installer")
+			os.Exit(1)
+		}
+	}
+
+	// Export action history to object storage if enabled
+	if cfg.Archive.Enabled {
+		store, err := archive.LoadObjectStore(ctx, mgr.GetAPIReader(), cfg.Archive)
+		if err != nil {
+			setupLog.Error(err, "invalid archive configuration")
+			os.Exit(1)
+		}
+		exporter := archive.NewExporter(store, cfg.Archive,
+			&archive.ActionSource{Client: mgr.GetClient()},
+			&archive.AuditSource{Store: safetyStore},
+			&archive.AIDecisionSource{Client: mgr.GetClient()})
+		if cfg.Archive.CheckpointConfigMap != "" {
+			exporter.WithCheckpoint(archive.NewConfigMapCheckpoint(mgr.GetClient(),
+				cfg.Archive.TokenSecretNamespace, cfg.Archive.CheckpointConfigMap))
+		}
+		if err := mgr.Add(exporter); err != nil {
+			setupLog.Error(err, "unable to add archive exporter")
+			os.Exit(1)
+		}
+		setupLog.Info("Action history export enabled", "provider", cfg.Archive.Provider, "format", cfg.Archive.Format, "interval", cfg.Archive.Interval)
+	}
+
 	// Add health checks
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+	var probes healthChecks = mgr
+	if cfg.Serving.ProbeTLS {
+		probeServer := serving.NewProbeServer(cfg.ProbeAddr, servingCert.TLSConfig())
+		if err := mgr.Add(probeServer); err != nil {
+			setupLog.Error(err, "unable to add health probe server")
+			os.Exit(1)
+		}
+		probes = probeServer
+	}
+	if err := probes.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	if err := probes.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
 
 	// Register custom Prometheus metrics
 	registerMetrics()
+	if cfg.Metrics.StateMetrics.Enabled {
+		metrics.Registry.MustRegister(kubemetrics.NewStateCollector(mgr.GetClient()))
+	}
 
 	// Start manager
 	setupLog.Info("Starting manager", "version", "v0.1.0", "dry-run", cfg.Safety.DryRunMode)
@@ -239,18 +685,75 @@ func main() {
 	}
 }
 
+// healthChecks registers liveness and readiness checks, on the manager or
+// on the TLS probe server
+type healthChecks interface {
+	AddHealthzCheck(name string, check healthz.Checker) error
+	AddReadyzCheck(name string, check healthz.Checker) error
+}
+
+// newServerDryRunClient returns the client server-side dry runs are sent
+// through: the manager's client, or a client impersonating the configured
+// user. Impersonated reads bypass the cache.
+func newServerDryRunClient(mgr ctrl.Manager, cfg config.ServerDryRunConfig) (client.Client, error) {
+	if cfg.ImpersonateUser == "" {
+		return mgr.GetClient(), nil
+	}
+	restConfig := rest.CopyConfig(mgr.GetConfig())
+	restConfig.Impersonate = rest.ImpersonationConfig{UserName: cfg.ImpersonateUser, Groups: cfg.ImpersonateGroups}
+	return client.New(restConfig, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+}
+
+// migrateStorageVersion rewrites the kubeskippy.io resources stored in
+// older versions, so the versions can be dropped from the CRDs
+func migrateStorageVersion(ctx context.Context) error {
+	migrationScheme := runtime.NewScheme()
+	if err := apiextensionsv1.AddToScheme(migrationScheme); err != nil {
+		return err
+	}
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: migrationScheme})
+	if err != nil {
+		return err
+	}
+	results, err := migration.NewStorageVersionMigrator(c).MigrateGroup(ctx, kubeskippyv1alpha1.GroupVersion.Group)
+	if err != nil {
+		return err
+	}
+	setupLog.Info("Storage version migration complete", "crds", len(results))
+	return nil
+}
+
+// previewTelemetry prints the telemetry report of the cluster to stdout
+func previewTelemetry(ctx context.Context, cfg *config.Config) error {
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	return telemetry.NewReporter(c, cfg.Telemetry, cfg.AI.Provider).Preview(ctx, os.Stdout)
+}
+
 // registerMetrics registers custom Prometheus metrics
 func registerMetrics() {
 	// Register healing action metrics (with trigger_type label for compatibility)
 	healingActionsTotal := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "kubeskippy_healing_actions_total",
-			Help: "Total number of healing actions taken",
+			Help: "Total number of healing actions taken; reason classifies failures",
 		},
-		[]string{"action_type", "namespace", "status", "trigger_type"},
+		[]string{"action_type", "namespace", "status", "trigger_type", "reason", "dry_run"},
 	)
 	metrics.Registry.MustRegister(healingActionsTotal)
 
+	// Register the success rate of real, non-dry-run actions
+	actionSuccessRate := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubeskippy_healing_action_success_rate",
+			Help: "Ratio of succeeded to finished non-dry-run healing actions since the operator started",
+		},
+		[]string{"action_type", "namespace"},
+	)
+	metrics.Registry.MustRegister(actionSuccessRate)
+
 	// Register policy evaluation metrics
 	policyEvaluationsTotal := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -261,6 +764,45 @@ func registerMetrics() {
 	)
 	metrics.Registry.MustRegister(policyEvaluationsTotal)
 
+	// Register trigger state transition metrics
+	triggerTransitionsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_trigger_transitions_total",
+			Help: "Total number of trigger state transitions",
+		},
+		[]string{"policy", "namespace", "trigger", "transition"},
+	)
+	metrics.Registry.MustRegister(triggerTransitionsTotal)
+
+	// Register the trigger values of policies that record them
+	triggerValue := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubeskippy_trigger_value",
+			Help: "Value a metric trigger was last evaluated against, for policies with recordTriggerValues",
+		},
+		[]string{"policy", "namespace", "trigger"},
+	)
+	metrics.Registry.MustRegister(triggerValue)
+	triggerThreshold := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubeskippy_trigger_threshold",
+			Help: "Threshold a metric trigger's value was last compared with, including baselines, for policies with recordTriggerValues",
+		},
+		[]string{"policy", "namespace", "trigger"},
+	)
+	metrics.Registry.MustRegister(triggerThreshold)
+
+	// Register policy time to recovery metrics
+	policyRecoverySeconds := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubeskippy_policy_recovery_seconds",
+			Help:    "Time from a trigger first firing on a target to it no longer firing after a healing action",
+			Buckets: []float64{30, 60, 120, 300, 600, 1200, 1800, 3600, 7200, 14400},
+		},
+		[]string{"policy", "namespace"},
+	)
+	metrics.Registry.MustRegister(policyRecoverySeconds)
+
 	// Register AI analysis metrics
 	aiAnalysisLatency := prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -320,9 +862,103 @@ func registerMetrics() {
 	)
 	metrics.Registry.MustRegister(aiConfidenceFactors)
 
+	aiPatchDenials := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_ai_patch_denials_total",
+			Help: "Total number of AI-proposed patches denied by the patch safety rules",
+		},
+		[]string{"kind", "rule"},
+	)
+	metrics.Registry.MustRegister(aiPatchDenials)
+
+	aiEgressPrompts := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_ai_egress_prompts_total",
+			Help: "Total number of prompts with cluster data sent to each AI provider",
+		},
+		[]string{"provider", "purpose"},
+	)
+	metrics.Registry.MustRegister(aiEgressPrompts)
+
+	aiEgressBytes := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_ai_egress_bytes_total",
+			Help: "Total size in bytes of the prompts sent to each AI provider",
+		},
+		[]string{"provider"},
+	)
+	metrics.Registry.MustRegister(aiEgressBytes)
+
+	aiEgressItems := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_ai_egress_items_total",
+			Help: "Total number of namespaces, objects, metric names, event messages, log lines and issues sent to each AI provider",
+		},
+		[]string{"provider", "kind"},
+	)
+	metrics.Registry.MustRegister(aiEgressItems)
+
+	readOnlyViolations := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_readonly_violations_total",
+			Help: "Total number of writes rejected by the read-only client in dry-run mode",
+		},
+		[]string{"verb", "kind"},
+	)
+	metrics.Registry.MustRegister(readOnlyViolations)
+
+	prometheusQueryCache := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_prometheus_query_cache_requests_total",
+			Help: "Total number of Prometheus queries by cache result; result is hit, miss or shared",
+		},
+		[]string{"result"},
+	)
+	metrics.Registry.MustRegister(prometheusQueryCache)
+
+	injectedFaults := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_injected_faults_total",
+			Help: "Total number of faults injected by kind; kind is executor, ai_delay, ai_error or api_error",
+		},
+		[]string{"kind"},
+	)
+	metrics.Registry.MustRegister(injectedFaults)
+
+	statusPatchesTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_status_patches_total",
+			Help: "Total number of status patches by controller; result is patched, unchanged, conflict or failed",
+		},
+		[]string{"controller", "result"},
+	)
+	metrics.Registry.MustRegister(statusPatchesTotal)
+
+	reconcileConflictsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubeskippy_reconcile_conflicts_total",
+			Help: "Total number of reconciles that failed on a conflicting write and were retried",
+		},
+		[]string{"controller"},
+	)
+	metrics.Registry.MustRegister(reconcileConflictsTotal)
+
 	// Set AI metrics references for the metrics package
 	kubemetrics.SetAIMetrics(aiReasoningStepsTotal, aiAlternativesConsidered, aiConfidenceFactors, aiDecisionConfidence)
+	kubemetrics.SetAIPatchDenialsMetric(aiPatchDenials)
+	kubemetrics.SetQueryCacheMetric(prometheusQueryCache)
+	ai.SetEgressMetrics(aiEgressPrompts, aiEgressBytes, aiEgressItems)
 
 	// Set healing actions metric for the controller package
 	controller.SetHealingActionsMetric(healingActionsTotal)
+	controller.SetActionSuccessRateMetric(actionSuccessRate)
+	controller.SetTriggerTransitionsMetric(triggerTransitionsTotal)
+	controller.SetTriggerValueMetrics(triggerValue, triggerThreshold)
+	controller.SetPolicyRecoveryMetric(policyRecoverySeconds)
+	controller.SetStatusPatchMetrics(statusPatchesTotal, reconcileConflictsTotal)
+
+	faults.SetInjectedFaultsMetric(injectedFaults)
+
+	// Set read-only violations metric for the remediation package
+	remediation.SetReadOnlyViolationsMetric(readOnlyViolations)
 }
@@ -0,0 +1,193 @@
+// Package admission serves the HealingAction admission webhooks, which
+// simulate the safety rules when an action is created
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// Risk levels recorded on admitted actions
+const (
+	RiskLow      = "low"
+	RiskMedium   = "medium"
+	RiskHigh     = "high"
+	RiskCritical = "critical"
+)
+
+// ActionValidator runs the safety checks that apply at admission
+type ActionValidator interface {
+	ValidateAdmission(ctx context.Context, action *v1alpha1.HealingAction) (*types.ValidationResult, error)
+}
+
+// BlastRadiusSimulator estimates the pods, services and disruption budgets
+// affected by disrupting a target
+type BlastRadiusSimulator interface {
+	Simulate(ctx context.Context, target client.Object) (*v1alpha1.BlastRadiusReport, error)
+}
+
+// disruptiveActionTypes remove or restart the target's pods and get a
+// blast radius estimate
+var disruptiveActionTypes = map[string]bool{
+	"restart":   true,
+	"delete":    true,
+	"finalizer": true,
+	"hibernate": true,
+}
+
+// HealingActionWebhook rejects HealingActions that break the safety rules
+// and annotates the others with their risk level and blast radius
+type HealingActionWebhook struct {
+	// Client reads action targets
+	Client client.Reader
+
+	// Safety validates actions
+	Safety ActionValidator
+
+	// Simulator optionally estimates the blast radius of disruptive actions
+	Simulator BlastRadiusSimulator
+}
+
+var (
+	_ admission.CustomDefaulter = &HealingActionWebhook{}
+	_ admission.CustomValidator = &HealingActionWebhook{}
+)
+
+// SetupWithManager registers the HealingAction webhooks
+func (w *HealingActionWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.HealingAction{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// The webhooks only see creates and fail open: the controller validates
+// every action again before executing it, so an unavailable webhook must
+// not stop healing.
+
+// +kubebuilder:webhook:path=/mutate-kubeskippy-io-v1alpha1-healingaction,mutating=true,failurePolicy=ignore,sideEffects=None,groups=kubeskippy.io,resources=healingactions,verbs=create,versions=v1alpha1,name=mhealingaction.kb.io,admissionReviewVersions=v1
+
+// Default implements admission.CustomDefaulter
+func (w *HealingActionWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	action, ok := obj.(*v1alpha1.HealingAction)
+	if !ok {
+		return fmt.Errorf("expected a HealingAction but got %T", obj)
+	}
+
+	report := w.estimateBlastRadius(ctx, action)
+	if action.Annotations == nil {
+		action.Annotations = make(map[string]string)
+	}
+	action.Annotations[types.AnnotationRiskLevel] = RiskLevel(action, report)
+	if report != nil {
+		action.Annotations[types.AnnotationBlastRadius] = FormatBlastRadius(report)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-kubeskippy-io-v1alpha1-healingaction,mutating=false,failurePolicy=ignore,sideEffects=None,groups=kubeskippy.io,resources=healingactions,verbs=create,versions=v1alpha1,name=vhealingaction.kb.io,admissionReviewVersions=v1
+
+// ValidateCreate implements admission.CustomValidator
+func (w *HealingActionWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	action, ok := obj.(*v1alpha1.HealingAction)
+	if !ok {
+		return nil, fmt.Errorf("expected a HealingAction but got %T", obj)
+	}
+
+	result, err := w.Safety.ValidateAdmission(ctx, action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate action: %w", err)
+	}
+	if !result.Valid {
+		return result.Warnings, apierrors.NewForbidden(
+			v1alpha1.GroupVersion.WithResource("healingactions").GroupResource(), action.Name, errors.New(result.Reason))
+	}
+	return result.Warnings, nil
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (w *HealingActionWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (w *HealingActionWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// estimateBlastRadius simulates disruptive actions on their target.
+// Failures are logged and leave the action without an estimate.
+func (w *HealingActionWebhook) estimateBlastRadius(ctx context.Context, action *v1alpha1.HealingAction) *v1alpha1.BlastRadiusReport {
+	if w.Simulator == nil || w.Client == nil || !disruptiveActionTypes[action.Spec.Action.Type] {
+		return nil
+	}
+
+	ref := action.Spec.TargetResource
+	target := &unstructured.Unstructured{}
+	target.SetAPIVersion(ref.APIVersion)
+	target.SetKind(ref.Kind)
+	if err := w.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, target); err != nil {
+		logf.FromContext(ctx).V(1).Info("Skipping blast radius estimate", "target", ref.Name, "error", err.Error())
+		return nil
+	}
+
+	report, err := w.Simulator.Simulate(ctx, target)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to estimate blast radius", "target", ref.Name)
+		return nil
+	}
+	return report
+}
+
+// RiskLevel rates an action by its type, escalated when the blast radius
+// leaves services without endpoints, violates disruption budgets or takes
+// down every replica. Dry-runs are always low risk.
+func RiskLevel(action *v1alpha1.HealingAction, report *v1alpha1.BlastRadiusReport) string {
+	if action.Spec.DryRun {
+		return RiskLow
+	}
+
+	risk := RiskMedium
+	switch action.Spec.Action.Type {
+	case "scale":
+		if scale := action.Spec.Action.ScaleAction; scale != nil && scale.Direction == "up" {
+			risk = RiskLow
+		}
+	case "delete", "hibernate":
+		risk = RiskHigh
+	case "finalizer":
+		risk = RiskHigh
+		if finalizer := action.Spec.Action.FinalizerAction; finalizer != nil && finalizer.ForceDelete {
+			risk = RiskCritical
+		}
+	}
+
+	if report != nil {
+		switch {
+		case len(report.ServicesLosingEndpoints) > 0 || len(report.ViolatedPDBs) > 0:
+			risk = RiskCritical
+		case len(report.AffectedPods) > 0 && report.RemainingReplicas == 0 && risk != RiskCritical:
+			risk = RiskHigh
+		}
+	}
+	return risk
+}
+
+// FormatBlastRadius summarizes a blast radius report for the annotation
+func FormatBlastRadius(report *v1alpha1.BlastRadiusReport) string {
+	return fmt.Sprintf("pods=%d,servicesLosingEndpoints=%d,violatedPDBs=%d,remainingReplicas=%d",
+		len(report.AffectedPods), len(report.ServicesLosingEndpoints), len(report.ViolatedPDBs), report.RemainingReplicas)
+}
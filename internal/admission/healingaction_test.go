@@ -0,0 +1,169 @@
+package admission
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+type fakeValidator struct {
+	result *types.ValidationResult
+}
+
+func (f *fakeValidator) ValidateAdmission(ctx context.Context, action *v1alpha1.HealingAction) (*types.ValidationResult, error) {
+	return f.result, nil
+}
+
+type fakeSimulator struct {
+	report  *v1alpha1.BlastRadiusReport
+	targets []string
+}
+
+func (f *fakeSimulator) Simulate(ctx context.Context, target client.Object) (*v1alpha1.BlastRadiusReport, error) {
+	f.targets = append(f.targets, target.GetObjectKind().GroupVersionKind().Kind+"/"+target.GetName())
+	return f.report, nil
+}
+
+func testAction(actionType string) *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-restart-x7k2p", Namespace: "apps"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "web-1", Namespace: "apps"},
+			Action:         v1alpha1.HealingActionTemplate{Name: actionType, Type: actionType},
+		},
+	}
+}
+
+func TestRiskLevel(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func() *v1alpha1.HealingAction
+		report *v1alpha1.BlastRadiusReport
+		want   string
+	}{
+		{
+			name:   "restart",
+			action: func() *v1alpha1.HealingAction { return testAction("restart") },
+			report: &v1alpha1.BlastRadiusReport{AffectedPods: []string{"web-1"}, RemainingReplicas: 2},
+			want:   RiskMedium,
+		},
+		{
+			name: "scale up",
+			action: func() *v1alpha1.HealingAction {
+				a := testAction("scale")
+				a.Spec.Action.ScaleAction = &v1alpha1.ScaleAction{Direction: "up", Replicas: 1}
+				return a
+			},
+			want: RiskLow,
+		},
+		{
+			name:   "delete",
+			action: func() *v1alpha1.HealingAction { return testAction("delete") },
+			want:   RiskHigh,
+		},
+		{
+			name:   "hibernate",
+			action: func() *v1alpha1.HealingAction { return testAction("hibernate") },
+			want:   RiskHigh,
+		},
+		{
+			name: "force delete finalizers",
+			action: func() *v1alpha1.HealingAction {
+				a := testAction("finalizer")
+				a.Spec.Action.FinalizerAction = &v1alpha1.FinalizerAction{ForceDelete: true}
+				return a
+			},
+			want: RiskCritical,
+		},
+		{
+			name:   "last replica",
+			action: func() *v1alpha1.HealingAction { return testAction("restart") },
+			report: &v1alpha1.BlastRadiusReport{AffectedPods: []string{"web-1"}},
+			want:   RiskHigh,
+		},
+		{
+			name:   "service loses endpoints",
+			action: func() *v1alpha1.HealingAction { return testAction("restart") },
+			report: &v1alpha1.BlastRadiusReport{AffectedPods: []string{"web-1"}, ServicesLosingEndpoints: []string{"web"}},
+			want:   RiskCritical,
+		},
+		{
+			name: "dry-run",
+			action: func() *v1alpha1.HealingAction {
+				a := testAction("delete")
+				a.Spec.DryRun = true
+				return a
+			},
+			want: RiskLow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RiskLevel(tt.action(), tt.report))
+		})
+	}
+}
+
+func TestDefault_AnnotatesRiskAndBlastRadius(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"}}
+	simulator := &fakeSimulator{report: &v1alpha1.BlastRadiusReport{AffectedPods: []string{"web-1"}, RemainingReplicas: 2}}
+	w := &HealingActionWebhook{
+		Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build(),
+		Simulator: simulator,
+	}
+
+	action := testAction("restart")
+	require.NoError(t, w.Default(context.Background(), action))
+	assert.Equal(t, []string{"Pod/web-1"}, simulator.targets)
+	assert.Equal(t, RiskMedium, action.Annotations[types.AnnotationRiskLevel])
+	assert.Equal(t, "pods=1,servicesLosingEndpoints=0,violatedPDBs=0,remainingReplicas=2", action.Annotations[types.AnnotationBlastRadius])
+
+	// Non-disruptive actions are not simulated
+	scale := testAction("scale")
+	scale.Spec.Action.ScaleAction = &v1alpha1.ScaleAction{Direction: "up", Replicas: 1}
+	require.NoError(t, w.Default(context.Background(), scale))
+	assert.Len(t, simulator.targets, 1)
+	assert.Equal(t, RiskLow, scale.Annotations[types.AnnotationRiskLevel])
+	assert.NotContains(t, scale.Annotations, types.AnnotationBlastRadius)
+
+	// Missing targets leave the action without an estimate
+	missing := testAction("delete")
+	missing.Spec.TargetResource.Name = "gone"
+	require.NoError(t, w.Default(context.Background(), missing))
+	assert.Equal(t, RiskHigh, missing.Annotations[types.AnnotationRiskLevel])
+	assert.NotContains(t, missing.Annotations, types.AnnotationBlastRadius)
+}
+
+func TestValidateCreate(t *testing.T) {
+	w := &HealingActionWebhook{Safety: &fakeValidator{result: &types.ValidationResult{
+		Valid:    false,
+		Reason:   "Resource is protected: namespace kube-system is protected",
+		Warnings: []string{},
+	}}}
+	_, err := w.ValidateCreate(context.Background(), testAction("restart"))
+	require.Error(t, err)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Contains(t, err.Error(), "namespace kube-system is protected")
+
+	w.Safety = &fakeValidator{result: &types.ValidationResult{
+		Valid:    true,
+		Warnings: []string{"Delete operations are potentially destructive"},
+	}}
+	warnings, err := w.ValidateCreate(context.Background(), testAction("delete"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Delete operations are potentially destructive"}, []string(warnings))
+}
@@ -0,0 +1,123 @@
+// Package aitest provides a mock Ollama server returning canned analyses so
+// the AI-enabled healing flow can be exercised without a model or GPU.
+package aitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// validationPromptPrefix identifies recommendation validation prompts
+const validationPromptPrefix = "Validate the safety"
+
+// batchItemPattern matches the numbered actions of a batch validation prompt
+var batchItemPattern = regexp.MustCompile(`(?m)^(\d+)\. ACTION:`)
+
+// OllamaServer is a mock Ollama API serving /api/tags and /api/generate
+type OllamaServer struct {
+	*httptest.Server
+
+	// Model advertised by /api/tags
+	Model string
+
+	mu       sync.Mutex
+	analysis *types.AIAnalysis
+	prompts  []string
+}
+
+// NewOllamaServer starts a mock Ollama server that answers analysis prompts
+// with the given analysis as structured JSON
+func NewOllamaServer(model string, analysis *types.AIAnalysis) *OllamaServer {
+	s := &OllamaServer{Model: model, analysis: analysis}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/tags", s.handleTags)
+	mux.HandleFunc("/api/generate", s.handleGenerate)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetAnalysis replaces the canned analysis
+func (s *OllamaServer) SetAnalysis(analysis *types.AIAnalysis) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.analysis = analysis
+}
+
+// Prompts returns the prompts received so far
+func (s *OllamaServer) Prompts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.prompts...)
+}
+
+func (s *OllamaServer) handleTags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"models": []map[string]interface{}{
+			{"name": s.Model + ":latest", "modified_at": time.Now(), "size": 1},
+		},
+	})
+}
+
+func (s *OllamaServer) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.prompts = append(s.prompts, req.Prompt)
+	analysis := s.analysis
+	s.mu.Unlock()
+
+	var response string
+	if strings.HasPrefix(req.Prompt, validationPromptPrefix) {
+		response = "SAFE: the action is reversible and scoped to a single workload"
+		if items := batchItemPattern.FindAllStringSubmatch(req.Prompt, -1); len(items) > 0 {
+			lines := make([]string, len(items))
+			for i, item := range items {
+				lines[i] = fmt.Sprintf("%s. SAFE - the action is reversible", item[1])
+			}
+			response = strings.Join(lines, "\n")
+		}
+	} else {
+		if analysis == nil {
+			analysis = &types.AIAnalysis{Summary: "No issues detected"}
+		}
+		data, err := json.Marshal(analysis)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response = string(data)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"model":      req.Model,
+		"created_at": time.Now(),
+		"response":   response,
+		"done":       true,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
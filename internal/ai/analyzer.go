@@ -2,13 +2,17 @@ package ai
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
 	"github.com/kubeskippy/kubeskippy/internal/types"
 	"github.com/kubeskippy/kubeskippy/internal/metrics"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
@@ -21,6 +25,9 @@ type Analyzer struct {
 	prompts         *PromptTemplates
 	validate        bool
 	metricsRecorder *metrics.AIMetricsRecorder
+
+	// namespaceReader looks up namespace data policies; nil disables them
+	namespaceReader client.Reader
 }
 
 // AIClient defines the interface for AI backend implementations
@@ -53,49 +60,91 @@ func NewNoOpAnalyzer() *NoOpAnalyzer {
 
 // NewAnalyzer creates a new AI analyzer
 func NewAnalyzer(config config.AIConfig) (*Analyzer, error) {
-	var client AIClient
-	var err error
+	client, err := newRecordingClient(config, func() (AIClient, error) {
+		return newProviderClient(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize prompt templates
+	prompts := &PromptTemplates{
+		ClusterAnalysis:   defaultClusterAnalysisPrompt,
+		IssueAnalysis:     defaultIssueAnalysisPrompt,
+		ActionValidation:  defaultActionValidationPrompt,
+		RootCauseAnalysis: defaultRootCausePrompt,
+	}
 
-	// Create appropriate client based on provider
+	return &Analyzer{
+		config:          config,
+		client:          client,
+		prompts:         prompts,
+		validate:        true,
+		metricsRecorder: metrics.NewAIMetricsRecorder(),
+	}, nil
+}
+
+// newProviderClient creates the client of the configured provider
+func newProviderClient(config config.AIConfig) (AIClient, error) {
 	switch config.Provider {
 	case "ollama":
-		client, err = NewOllamaClient(config.Endpoint, config.Model, config.Timeout)
+		client, err := NewOllamaClient(config.Endpoint, config.Model, config.Timeout)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Ollama client: %w", err)
 		}
+		return client, nil
 
 	case "openai":
 		if config.APIKey == "" {
 			return nil, fmt.Errorf("OpenAI API key is required")
 		}
-		client, err = NewOpenAIClient(config.APIKey, config.Model, config.Timeout)
+		client, err := NewOpenAIClient(config.APIKey, config.Model, config.Timeout)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create OpenAI client: %w", err)
 		}
+		return client, nil
+
+	case "grpc":
+		client, err := NewGRPCClient(config.Endpoint, config.Model, config.Timeout, config.GRPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+		}
+		return client, nil
+
+	case "bedrock":
+		client, err := NewBedrockClient(config.Model, config.Bedrock.Region, config.MaxTokens, config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Bedrock client: %w", err)
+		}
+		return client, nil
+
+	case "vertex":
+		client, err := NewVertexClient(config.Model, config.Vertex.Project, config.Vertex.Location, config.MaxTokens, config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+		}
+		return client, nil
+
+	case "azure-openai":
+		client, err := NewAzureOpenAIClient(config.Endpoint, config.Model, config.AzureOpenAI.APIVersion, config.MaxTokens, config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Azure OpenAI client: %w", err)
+		}
+		return client, nil
 
 	default:
 		return nil, fmt.Errorf("unsupported AI provider: %s", config.Provider)
 	}
-
-	// Initialize prompt templates
-	prompts := &PromptTemplates{
-		ClusterAnalysis:   defaultClusterAnalysisPrompt,
-		IssueAnalysis:     defaultIssueAnalysisPrompt,
-		ActionValidation:  defaultActionValidationPrompt,
-		RootCauseAnalysis: defaultRootCausePrompt,
-	}
-
-	return &Analyzer{
-		config:          config,
-		client:          client,
-		prompts:         prompts,
-		validate:        true,
-		metricsRecorder: metrics.NewAIMetricsRecorder(),
-	}, nil
 }
 
 // AnalyzeClusterState analyzes the cluster state and provides recommendations
 func (a *Analyzer) AnalyzeClusterState(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue) (*types.AIAnalysis, error) {
+	return a.analyze(ctx, metrics, issues, nil)
+}
+
+// analyze runs a cluster analysis, reporting its progress if progress is
+// set and the response is streamed
+func (a *Analyzer) analyze(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error) {
 	log := log.FromContext(ctx)
 	log.Info("Analyzing cluster state with AI", "provider", a.config.Provider, "model", a.client.GetModel())
 
@@ -104,6 +153,26 @@ func (a *Analyzer) AnalyzeClusterState(ctx context.Context, metrics *types.Clust
 		return nil, fmt.Errorf("AI service is not available")
 	}
 
+	// Withhold data from restricted namespaces before it leaves the cluster
+	restricted, err := a.restrictedNamespaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot enforce AI data policy: %w", err)
+	}
+	metrics, issues, report := redactRestricted(metrics, issues, restricted)
+	if !report.empty() {
+		a.auditRedaction(ctx, report)
+	}
+	if len(issues) == 0 && report.Issues > 0 {
+		return &types.AIAnalysis{
+			Timestamp:            time.Now(),
+			Summary:              "AI analysis skipped: all issues are in restricted namespaces",
+			Issues:               []types.AIIssue{},
+			Recommendations:      []types.AIRecommendation{},
+			ModelVersion:         a.client.GetModel(),
+			RestrictedNamespaces: report.Namespaces,
+		}, nil
+	}
+
 	// Prepare the analysis prompt
 	prompt, err := a.buildClusterAnalysisPrompt(metrics, issues)
 	if err != nil {
@@ -111,7 +180,9 @@ func (a *Analyzer) AnalyzeClusterState(ctx context.Context, metrics *types.Clust
 	}
 
 	// Query the AI
-	response, err := a.client.Query(ctx, prompt, a.config.Temperature)
+	a.auditEgress(ctx, egressPurposeAnalysis, prompt, analysisEgress(metrics, issues))
+	response, err := a.query(ctx, prompt, progress)
+	traceFrom(ctx).recordExchange(egressPurposeAnalysis, prompt, response, err)
 	if err != nil {
 		return nil, fmt.Errorf("AI query failed: %w", err)
 	}
@@ -125,6 +196,9 @@ func (a *Analyzer) AnalyzeClusterState(ctx context.Context, metrics *types.Clust
 	// Add metadata
 	analysis.Timestamp = time.Now()
 	analysis.ModelVersion = a.client.GetModel()
+	analysis.PromptHash = hashPrompt(prompt)
+	analysis.RestrictedNamespaces = report.Namespaces
+	traceFrom(ctx).recordParsed(analysis)
 
 	// Validate recommendations if enabled
 	if a.validate {
@@ -144,45 +218,16 @@ func (a *Analyzer) AnalyzeClusterState(ctx context.Context, metrics *types.Clust
 	return analysis, nil
 }
 
-// ValidateRecommendation validates an AI recommendation for safety
+// ValidateRecommendation validates an AI recommendation for safety. Local
+// rules are checked first; the AI is only queried if they pass.
 func (a *Analyzer) ValidateRecommendation(ctx context.Context, recommendation *types.AIRecommendation) error {
-	log := log.FromContext(ctx)
-
-	// Basic validation
-	if recommendation.Action == "" {
-		return fmt.Errorf("recommendation has no action specified")
-	}
-
-	if recommendation.Target == "" {
-		return fmt.Errorf("recommendation has no target specified")
-	}
-
-	// Check against known unsafe actions
-	unsafeActions := []string{"delete-namespace", "delete-node", "delete-pv", "delete-crd"}
-	for _, unsafe := range unsafeActions {
-		if strings.Contains(strings.ToLower(recommendation.Action), unsafe) {
-			return fmt.Errorf("unsafe action detected: %s", unsafe)
-		}
-	}
-
-	// Validate confidence threshold
-	if recommendation.Confidence < float64(a.config.MinConfidence) {
-		return fmt.Errorf("recommendation confidence %.2f is below threshold %.2f",
-			recommendation.Confidence, a.config.MinConfidence)
+	if err := a.validateLocally(recommendation); err != nil {
+		return err
 	}
 
 	// Additional validation using AI if configured
-	if a.config.ValidateResponses {
-		prompt := a.buildValidationPrompt(recommendation)
-		response, err := a.client.Query(ctx, prompt, 0.1) // Low temperature for validation
-		if err != nil {
-			log.Error(err, "Failed to validate recommendation with AI")
-			return fmt.Errorf("validation query failed: %w", err)
-		}
-
-		if !strings.Contains(strings.ToLower(response), "safe") {
-			return fmt.Errorf("AI validation failed: %s", response)
-		}
+	if a.needsAIValidation(recommendation) {
+		return a.validateWithAI(ctx, recommendation)
 	}
 
 	return nil
@@ -193,6 +238,13 @@ func (a *Analyzer) GetModel() string {
 	return a.client.GetModel()
 }
 
+// hashPrompt returns the SHA-256 of a prompt so decisions can be traced to
+// their input without storing it
+func hashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
 // buildClusterAnalysisPrompt creates the prompt for cluster analysis
 func (a *Analyzer) buildClusterAnalysisPrompt(metrics *types.ClusterMetrics, issues []types.Issue) (string, error) {
 	// Convert metrics to JSON for structured input
@@ -267,16 +319,24 @@ func (a *Analyzer) validateAnalysis(ctx context.Context, analysis *types.AIAnaly
 	log := log.FromContext(ctx)
 
 	// Filter recommendations below confidence threshold
-	validRecs := []types.AIRecommendation{}
+	candidates := []types.AIRecommendation{}
 	for _, rec := range analysis.Recommendations {
 		if rec.Confidence >= float64(a.config.MinConfidence) {
-			// Additional safety checks
-			if err := a.ValidateRecommendation(ctx, &rec); err != nil {
-				log.Info("Filtered out recommendation", "action", rec.Action, "reason", err.Error())
-				continue
-			}
-			validRecs = append(validRecs, rec)
+			candidates = append(candidates, rec)
+		} else {
+			traceFrom(ctx).recordRejected(rec, fmt.Sprintf("confidence %.2f below %.2f", rec.Confidence, a.config.MinConfidence))
+		}
+	}
+
+	// Additional safety checks
+	validRecs := []types.AIRecommendation{}
+	for i, err := range a.validateRecommendations(ctx, candidates) {
+		if err != nil {
+			log.Info("Filtered out recommendation", "action", candidates[i].Action, "reason", err.Error())
+			traceFrom(ctx).recordRejected(candidates[i], err.Error())
+			continue
 		}
+		validRecs = append(validRecs, candidates[i])
 	}
 	analysis.Recommendations = validRecs
 
@@ -446,8 +506,14 @@ func parseRecommendationsWithReasoning(text string) []types.AIRecommendation {
 			if inReasoning {
 				reasoningSection += line + "\n"
 			} else {
-				// Parse basic attributes
-				if strings.Contains(trimmedLine, "Target:") {
+				// Parse basic attributes. Patches are JSON and may contain
+				// the other markers, so they are matched first.
+				if patch, ok := strings.CutPrefix(trimmedLine, "Patch:"); ok {
+					var patches []v1alpha1.PatchOperation
+					if err := json.Unmarshal([]byte(strings.TrimSpace(patch)), &patches); err == nil {
+						currentRec.Patches = patches
+					}
+				} else if strings.Contains(trimmedLine, "Target:") {
 					currentRec.Target = strings.TrimSpace(strings.Split(trimmedLine, ":")[1])
 				} else if strings.Contains(trimmedLine, "Reason:") {
 					currentRec.Reason = strings.TrimSpace(strings.Split(trimmedLine, ":")[1])
@@ -483,6 +549,8 @@ DETECTED ISSUES:
 
 Current Time: %s
 
+When Capacity reports pending unschedulable pods or FailedScheduling events, check whether the cluster is out of capacity before blaming the workloads: restarting or scaling up pods that cannot be scheduled does not help, while node groups at their maximum size or in backoff call for capacity changes instead.
+
 Please provide your analysis in the following structured format:
 
 SUMMARY:
@@ -514,6 +582,7 @@ RECOMMENDATIONS:
    Reason: [Why this action will help]
    Risk: [Any risks associated]
    Confidence: [0.0-1.0]
+   Patch: [Patch actions only: a JSON list of {"path": [field names], "value": "JSON value"} operations on one line]
    
    REASONING:
    Observations: [What patterns/symptoms led to this recommendation]
@@ -538,6 +607,8 @@ RECOMMENDATIONS:
 
 END
 
+Pods with InitFailures are stuck before their app containers start, so restarting or scaling them only helps when an init container waits on a dependency that has since recovered; for image, configuration or command errors recommend fixing the init container instead. Pods with EphemeralContainers are being debugged and should not be restarted or deleted.
+
 Focus on practical, safe actions that can be automated. Provide transparent reasoning for each decision to build trust and enable learning.`
 
 const defaultIssueAnalysisPrompt = `Analyze the following Kubernetes issue and provide root cause analysis:
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
 	"github.com/kubeskippy/kubeskippy/internal/types"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
 )
@@ -318,6 +319,27 @@ END`,
 				assert.Len(t, analysis.Recommendations, 1)
 			},
 		},
+		{
+			name: "patch recommendation",
+			response: `SUMMARY:
+Slow rollouts
+
+RECOMMENDATIONS:
+1. patch
+   Target: deployment/web
+   Patch: [{"path": ["spec", "minReadySeconds"], "value": "30"}]
+   Reason: Pods report ready too early
+   Confidence: 0.9
+
+END`,
+			validate: func(t *testing.T, analysis *types.AIAnalysis) {
+				require.Len(t, analysis.Recommendations, 1)
+				rec := analysis.Recommendations[0]
+				assert.Equal(t, "deployment/web", rec.Target)
+				assert.Equal(t, "Pods report ready too early", rec.Reason)
+				assert.Equal(t, []v1alpha1.PatchOperation{{Path: []string{"spec", "minReadySeconds"}, Value: "30"}}, rec.Patches)
+			},
+		},
 	}
 
 	for _, tt := range tests {
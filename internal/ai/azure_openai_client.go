@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AzureOpenAIClient implements the AIClient interface for a deployment of
+// an Azure OpenAI resource. Requests carry Azure AD tokens of the workload
+// identity of the manager, so no API key is stored.
+type AzureOpenAIClient struct {
+	deployment string
+	endpoint   string
+	maxTokens  int
+	tokens     *credentialCache[string]
+	httpClient *http.Client
+}
+
+// NewAzureOpenAIClient creates a new Azure OpenAI client for the deployment
+// of the resource at endpoint
+func NewAzureOpenAIClient(endpoint, deployment, apiVersion string, maxTokens int, timeout time.Duration) (*AzureOpenAIClient, error) {
+	if endpoint == "" || deployment == "" {
+		return nil, fmt.Errorf("an Azure OpenAI endpoint and deployment are required")
+	}
+	if apiVersion == "" {
+		return nil, fmt.Errorf("an Azure OpenAI API version is required")
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	tokens, err := newAzureTokenSource(httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &AzureOpenAIClient{
+		deployment: deployment,
+		endpoint: fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			strings.TrimSuffix(endpoint, "/"), url.PathEscape(deployment), url.QueryEscape(apiVersion)),
+		maxTokens:  maxTokens,
+		tokens:     tokens,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Query sends a prompt to the deployment and returns the response
+func (a *AzureOpenAIClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	log := log.FromContext(ctx)
+	log.V(1).Info("Querying Azure OpenAI", "deployment", a.deployment, "prompt_length", len(prompt))
+
+	// The deployment selects the model
+	request := OpenAIRequest{
+		Model: a.deployment,
+		Messages: []Message{
+			{Role: "system", Content: assistantSystemPrompt},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: temperature,
+		MaxTokens:   a.maxTokens,
+	}
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	token, err := a.tokens.get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Azure AD token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiError OpenAIError
+		if err := json.Unmarshal(body, &apiError); err == nil && apiError.Error.Message != "" {
+			return "", fmt.Errorf("Azure OpenAI API error: %s (code: %s)", apiError.Error.Message, apiError.Error.Code)
+		}
+		return "", fmt.Errorf("Azure OpenAI returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var openAIResp OpenAIResponse
+	if err := json.Unmarshal(body, &openAIResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(openAIResp.Choices) == 0 {
+		return "", fmt.Errorf("no response choices returned")
+	}
+	response := openAIResp.Choices[0].Message.Content
+
+	log.V(1).Info("Azure OpenAI query completed",
+		"response_length", len(response),
+		"total_tokens", openAIResp.Usage.TotalTokens,
+		"finish_reason", openAIResp.Choices[0].FinishReason)
+
+	return response, nil
+}
+
+// GetModel returns the model identifier
+func (a *AzureOpenAIClient) GetModel() string {
+	return fmt.Sprintf("azure-openai/%s", a.deployment)
+}
+
+// IsAvailable checks that an Azure AD token can be obtained
+func (a *AzureOpenAIClient) IsAvailable(ctx context.Context) bool {
+	_, err := a.tokens.get(ctx)
+	return err == nil
+}
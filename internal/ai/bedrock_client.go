@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// BedrockClient implements the AIClient interface for AWS Bedrock using
+// the Converse API. Requests are signed with the credentials of the
+// manager's IRSA role or EKS Pod Identity, so no API key is stored.
+type BedrockClient struct {
+	model       string
+	region      string
+	endpoint    string
+	maxTokens   int
+	credentials *credentialCache[awsCredentials]
+	httpClient  *http.Client
+}
+
+// bedrockContent is a text block of a Converse message
+type bedrockContent struct {
+	Text string `json:"text"`
+}
+
+// bedrockMessage is a Converse message
+type bedrockMessage struct {
+	Role    string           `json:"role"`
+	Content []bedrockContent `json:"content"`
+}
+
+// bedrockRequest is a Converse request
+type bedrockRequest struct {
+	System          []bedrockContent `json:"system,omitempty"`
+	Messages        []bedrockMessage `json:"messages"`
+	InferenceConfig struct {
+		Temperature float32 `json:"temperature"`
+		MaxTokens   int     `json:"maxTokens,omitempty"`
+	} `json:"inferenceConfig"`
+}
+
+// bedrockResponse is a Converse response
+type bedrockResponse struct {
+	Output struct {
+		Message bedrockMessage `json:"message"`
+	} `json:"output"`
+	StopReason string `json:"stopReason"`
+	Usage      struct {
+		TotalTokens int `json:"totalTokens"`
+	} `json:"usage"`
+	Message string `json:"message"`
+}
+
+// NewBedrockClient creates a new Bedrock client for a model ID. The region
+// defaults to AWS_REGION.
+func NewBedrockClient(model, region string, maxTokens int, timeout time.Duration) (*BedrockClient, error) {
+	if model == "" {
+		return nil, fmt.Errorf("a Bedrock model ID is required")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("a Bedrock region is required: set ai.bedrock.region or AWS_REGION")
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	credentials, err := newAWSCredentialSource(httpClient, region)
+	if err != nil {
+		return nil, err
+	}
+	return &BedrockClient{
+		model:       model,
+		region:      region,
+		endpoint:    awsEndpoint("BEDROCK_RUNTIME", fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)),
+		maxTokens:   maxTokens,
+		credentials: credentials,
+		httpClient:  httpClient,
+	}, nil
+}
+
+// Query sends a prompt to Bedrock and returns the response
+func (b *BedrockClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	log := log.FromContext(ctx)
+	log.V(1).Info("Querying Bedrock", "model", b.model, "region", b.region, "prompt_length", len(prompt))
+
+	request := bedrockRequest{
+		System:   []bedrockContent{{Text: assistantSystemPrompt}},
+		Messages: []bedrockMessage{{Role: "user", Content: []bedrockContent{{Text: prompt}}}},
+	}
+	request.InferenceConfig.Temperature = temperature
+	request.InferenceConfig.MaxTokens = b.maxTokens
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	credentials, err := b.credentials.get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	// Model IDs hold colons, which the path must carry escaped
+	escaped := "/model/" + awsURIEncode(b.model) + "/converse"
+	req.URL.Path, req.URL.RawPath = "/model/"+b.model+"/converse", escaped
+	req.Header.Set("Content-Type", "application/json")
+	signSigV4(req, requestBody, credentials, b.region, "bedrock", time.Now())
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var bedrockResp bedrockResponse
+	if err := json.Unmarshal(body, &bedrockResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("Bedrock returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Bedrock API error (status %d): %s", resp.StatusCode, bedrockResp.Message)
+	}
+
+	var response strings.Builder
+	for _, content := range bedrockResp.Output.Message.Content {
+		response.WriteString(content.Text)
+	}
+	if response.Len() == 0 {
+		return "", fmt.Errorf("no response content returned")
+	}
+
+	log.V(1).Info("Bedrock query completed",
+		"response_length", response.Len(),
+		"total_tokens", bedrockResp.Usage.TotalTokens,
+		"stop_reason", bedrockResp.StopReason)
+
+	return response.String(), nil
+}
+
+// GetModel returns the model identifier
+func (b *BedrockClient) GetModel() string {
+	return fmt.Sprintf("bedrock/%s", b.model)
+}
+
+// IsAvailable checks that AWS credentials can be obtained
+func (b *BedrockClient) IsAvailable(ctx context.Context) bool {
+	_, err := b.credentials.get(ctx)
+	return err == nil
+}
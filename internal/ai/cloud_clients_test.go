@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestBedrockClient(t *testing.T) {
+	clearCloudEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/model/anthropic.claude-3-haiku-20240307-v1%3A0/converse", r.URL.EscapedPath())
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/bedrock/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,")
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+
+		var req bedrockRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Analyze the cluster", req.Messages[0].Content[0].Text)
+		assert.Equal(t, 2048, req.InferenceConfig.MaxTokens)
+
+		if req.InferenceConfig.Temperature > 0.5 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "not authorized to invoke the model"}`))
+			return
+		}
+		w.Write([]byte(`{"output": {"message": {"role": "assistant", "content": [{"text": "SUMMARY: "}, {"text": "all good"}]}}, "stopReason": "end_turn"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL_BEDROCK_RUNTIME", server.URL)
+	client, err := NewBedrockClient("anthropic.claude-3-haiku-20240307-v1:0", "", 2048, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "bedrock/anthropic.claude-3-haiku-20240307-v1:0", client.GetModel())
+	assert.True(t, client.IsAvailable(context.Background()))
+
+	response, err := client.Query(context.Background(), "Analyze the cluster", 0.2)
+	require.NoError(t, err)
+	assert.Equal(t, "SUMMARY: all good", response)
+
+	_, err = client.Query(context.Background(), "Analyze the cluster", 0.7)
+	assert.ErrorContains(t, err, "not authorized to invoke the model")
+}
+
+func TestVertexClient(t *testing.T) {
+	clearCloudEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/computeMetadata/v1/instance/service-accounts/default/token" {
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			w.Write([]byte(`{"access_token": "gcp-token", "expires_in": 3599, "token_type": "Bearer"}`))
+			return
+		}
+		assert.Equal(t, "/v1/projects/acme/locations/europe-west4/publishers/google/models/gemini-1.5-pro:generateContent", r.URL.Path)
+		assert.Equal(t, "Bearer gcp-token", r.Header.Get("Authorization"))
+		var req vertexRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Analyze the cluster", req.Contents[0].Parts[0].Text)
+		assert.Equal(t, assistantSystemPrompt, req.SystemInstruction.Parts[0].Text)
+		w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "SUMMARY: all good"}]}, "finishReason": "STOP"}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+	client, err := NewVertexClient("gemini-1.5-pro", "acme", "europe-west4", 2048, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "https://europe-west4-aiplatform.googleapis.com/v1/projects/acme/locations/europe-west4/publishers/google/models/gemini-1.5-pro:generateContent", client.endpoint)
+	client.endpoint = server.URL + "/v1/projects/acme/locations/europe-west4/publishers/google/models/gemini-1.5-pro:generateContent"
+	assert.Equal(t, "vertex/gemini-1.5-pro", client.GetModel())
+	assert.True(t, client.IsAvailable(context.Background()))
+
+	response, err := client.Query(context.Background(), "Analyze the cluster", 0.2)
+	require.NoError(t, err)
+	assert.Equal(t, "SUMMARY: all good", response)
+}
+
+func TestAzureOpenAIClient(t *testing.T) {
+	clearCloudEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+			w.Write([]byte(`{"token_type": "Bearer", "expires_in": 3599, "access_token": "azure-token"}`))
+			return
+		}
+		assert.Equal(t, "/openai/deployments/gpt-4o/chat/completions", r.URL.Path)
+		assert.Equal(t, "2024-06-01", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "Bearer azure-token", r.Header.Get("Authorization"))
+		var req OpenAIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Analyze the cluster", req.Messages[1].Content)
+		json.NewEncoder(w).Encode(OpenAIResponse{Choices: []Choice{{Message: Message{Role: "assistant", Content: "SUMMARY: all good"}}}})
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", writeTokenFile(t, "federated-token"))
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+	client, err := NewAzureOpenAIClient(server.URL+"/", "gpt-4o", "2024-06-01", 2048, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "azure-openai/gpt-4o", client.GetModel())
+	assert.True(t, client.IsAvailable(context.Background()))
+
+	response, err := client.Query(context.Background(), "Analyze the cluster", 0.2)
+	require.NoError(t, err)
+	assert.Equal(t, "SUMMARY: all good", response)
+}
+
+// Without workload identity the providers fail at startup, not on the
+// first analysis
+func TestNewProviderClient_CloudCredentials(t *testing.T) {
+	clearCloudEnv(t)
+
+	_, err := newProviderClient(config.AIConfig{Provider: "bedrock", Model: "amazon.titan-text-express-v1", Bedrock: config.BedrockConfig{Region: "us-east-1"}})
+	assert.ErrorContains(t, err, "no AWS credentials")
+	_, err = newProviderClient(config.AIConfig{Provider: "bedrock", Model: "amazon.titan-text-express-v1"})
+	assert.ErrorContains(t, err, "region is required")
+	_, err = newProviderClient(config.AIConfig{Provider: "azure-openai", Endpoint: "https://acme.openai.azure.com", Model: "gpt-4o", AzureOpenAI: config.AzureOpenAIConfig{APIVersion: "2024-06-01"}})
+	assert.ErrorContains(t, err, "no Azure workload identity")
+	_, err = newProviderClient(config.AIConfig{Provider: "vertex", Model: "gemini-1.5-pro"})
+	assert.ErrorContains(t, err, "project and location are required")
+}
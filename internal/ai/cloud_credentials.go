@@ -0,0 +1,303 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialRefreshMargin is how long before they expire cached credentials
+// are refreshed
+const credentialRefreshMargin = 5 * time.Minute
+
+// credentialCache caches short-lived credentials until shortly before they
+// expire. Credentials without expiry are fetched once.
+type credentialCache[T any] struct {
+	fetch func(ctx context.Context) (T, time.Time, error)
+
+	mu      sync.Mutex
+	value   T
+	expires time.Time
+	fetched bool
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func newCredentialCache[T any](fetch func(ctx context.Context) (T, time.Time, error)) *credentialCache[T] {
+	return &credentialCache[T]{fetch: fetch, now: time.Now}
+}
+
+// get returns the cached credentials, fetching them when missing or about
+// to expire
+func (c *credentialCache[T]) get(ctx context.Context) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fetched && (c.expires.IsZero() || c.now().Add(credentialRefreshMargin).Before(c.expires)) {
+		return c.value, nil
+	}
+	value, expires, err := c.fetch(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	c.value, c.expires, c.fetched = value, expires, true
+	return value, nil
+}
+
+// awsCredentials sign requests to AWS
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// newAWSCredentialSource returns the credentials of the environment, in the
+// order of the AWS SDKs: static keys, IRSA web identity, then EKS Pod
+// Identity
+func newAWSCredentialSource(httpClient *http.Client, region string) (*credentialCache[awsCredentials], error) {
+	if key := os.Getenv("AWS_ACCESS_KEY_ID"); key != "" {
+		static := awsCredentials{
+			AccessKeyID:     key,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		return newCredentialCache(func(ctx context.Context) (awsCredentials, time.Time, error) {
+			return static, time.Time{}, nil
+		}), nil
+	}
+
+	if role, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"); role != "" && tokenFile != "" {
+		sts := &webIdentityCredentials{
+			httpClient:  httpClient,
+			endpoint:    awsEndpoint("STS", fmt.Sprintf("https://sts.%s.amazonaws.com/", region)),
+			roleARN:     role,
+			tokenFile:   tokenFile,
+			sessionName: os.Getenv("AWS_ROLE_SESSION_NAME"),
+		}
+		if sts.sessionName == "" {
+			sts.sessionName = "kubeskippy"
+		}
+		return newCredentialCache(sts.fetch), nil
+	}
+
+	if endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); endpoint != "" {
+		container := &containerCredentials{
+			httpClient: httpClient,
+			endpoint:   endpoint,
+			tokenFile:  os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"),
+		}
+		return newCredentialCache(container.fetch), nil
+	}
+
+	return nil, fmt.Errorf("no AWS credentials: set up IRSA or EKS Pod Identity for the manager's service account")
+}
+
+// awsEndpoint returns the endpoint of a service, overridden by the
+// AWS_ENDPOINT_URL_<SERVICE> variable of the AWS SDKs
+func awsEndpoint(service, endpoint string) string {
+	if override := os.Getenv("AWS_ENDPOINT_URL_" + service); override != "" {
+		return override
+	}
+	return endpoint
+}
+
+// webIdentityCredentials exchanges the projected service account token of
+// IRSA for credentials of a role
+type webIdentityCredentials struct {
+	httpClient  *http.Client
+	endpoint    string
+	roleARN     string
+	tokenFile   string
+	sessionName string
+}
+
+// assumeRoleWithWebIdentityResponse is the STS response
+type assumeRoleWithWebIdentityResponse struct {
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+}
+
+func (w *webIdentityCredentials) fetch(ctx context.Context) (awsCredentials, time.Time, error) {
+	// The token is rotated by the kubelet, so it is read on every refresh
+	token, err := os.ReadFile(w.tokenFile)
+	if err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {w.roleARN},
+		"RoleSessionName":  {w.sessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := doCredentialRequest(w.httpClient, req)
+	if err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to assume role %s: %w", w.roleARN, err)
+	}
+	var resp assumeRoleWithWebIdentityResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to decode STS response: %w", err)
+	}
+	c := resp.Credentials
+	return awsCredentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}, c.Expiration, nil
+}
+
+// containerCredentials gets credentials from the EKS Pod Identity agent
+type containerCredentials struct {
+	httpClient *http.Client
+	endpoint   string
+	tokenFile  string
+}
+
+func (c *containerCredentials) fetch(ctx context.Context) (awsCredentials, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint, nil)
+	if err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return awsCredentials{}, time.Time{}, fmt.Errorf("failed to read container authorization token: %w", err)
+		}
+		req.Header.Set("Authorization", strings.TrimSpace(string(token)))
+	}
+
+	body, err := doCredentialRequest(c.httpClient, req)
+	if err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to get container credentials: %w", err)
+	}
+	var resp struct {
+		AccessKeyID     string    `json:"AccessKeyId"`
+		SecretAccessKey string    `json:"SecretAccessKey"`
+		Token           string    `json:"Token"`
+		Expiration      time.Time `json:"Expiration"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return awsCredentials{}, time.Time{}, fmt.Errorf("failed to decode container credentials: %w", err)
+	}
+	return awsCredentials{AccessKeyID: resp.AccessKeyID, SecretAccessKey: resp.SecretAccessKey, SessionToken: resp.Token}, resp.Expiration, nil
+}
+
+// oauthTokenResponse is the token response of the GCE metadata server and
+// Azure AD
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// newGCPTokenSource returns access tokens of the service account of the
+// GKE workload identity from the metadata server
+func newGCPTokenSource(httpClient *http.Client) *credentialCache[string] {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	endpoint := "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token"
+	return newCredentialCache(func(ctx context.Context) (string, time.Time, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		token, expires, err := fetchOAuthToken(httpClient, req)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to get workload identity token from the metadata server: %w", err)
+		}
+		return token, expires, nil
+	})
+}
+
+// azureCognitiveServicesScope is the scope of Azure OpenAI tokens
+const azureCognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+// newAzureTokenSource returns Azure AD tokens for the Azure OpenAI scope,
+// exchanging the federated token of the Azure workload identity
+func newAzureTokenSource(httpClient *http.Client) (*credentialCache[string], error) {
+	clientID, tenantID, tokenFile := os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	if clientID == "" || tenantID == "" || tokenFile == "" {
+		return nil, fmt.Errorf("no Azure workload identity: AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE must be set")
+	}
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = "https://login.microsoftonline.com/"
+	}
+	endpoint := strings.TrimSuffix(authority, "/") + "/" + tenantID + "/oauth2/v2.0/token"
+
+	return newCredentialCache(func(ctx context.Context) (string, time.Time, error) {
+		// The token is rotated by the kubelet, so it is read on every refresh
+		assertion, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to read federated token: %w", err)
+		}
+		form := url.Values{
+			"client_id":             {clientID},
+			"scope":                 {azureCognitiveServicesScope},
+			"grant_type":            {"client_credentials"},
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {strings.TrimSpace(string(assertion))},
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		token, expires, err := fetchOAuthToken(httpClient, req)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to get Azure AD token: %w", err)
+		}
+		return token, expires, nil
+	}), nil
+}
+
+// fetchOAuthToken sends a token request
+func fetchOAuthToken(httpClient *http.Client, req *http.Request) (string, time.Time, error) {
+	body, err := doCredentialRequest(httpClient, req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var resp oauthTokenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("no access token in the response")
+	}
+	return resp.AccessToken, time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second), nil
+}
+
+// doCredentialRequest sends a request for credentials and returns the body
+// of a successful response
+func doCredentialRequest(httpClient *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
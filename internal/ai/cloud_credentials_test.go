@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearCloudEnv unsets the credential variables of every cloud
+func clearCloudEnv(t *testing.T) {
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_ROLE_ARN", "AWS_WEB_IDENTITY_TOKEN_FILE",
+		"AWS_ROLE_SESSION_NAME", "AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE",
+		"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_ENDPOINT_URL_STS", "AWS_ENDPOINT_URL_BEDROCK_RUNTIME", "GCE_METADATA_HOST",
+		"AZURE_CLIENT_ID", "AZURE_TENANT_ID", "AZURE_FEDERATED_TOKEN_FILE", "AZURE_AUTHORITY_HOST",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+// writeTokenFile writes a projected service account token
+func writeTokenFile(t *testing.T, token string) string {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0o600))
+	return path
+}
+
+func TestCredentialCache(t *testing.T) {
+	now := time.Now()
+	fetches := 0
+	cache := newCredentialCache(func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return "token", now.Add(time.Hour), nil
+	})
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		token, err := cache.get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+	}
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(56 * time.Minute)
+	_, err := cache.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetches, "refreshed before expiry")
+}
+
+func TestWebIdentityCredentials(t *testing.T) {
+	clearCloudEnv(t)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/kubeskippy", r.PostForm.Get("RoleArn"))
+		assert.Equal(t, "projected-token", r.PostForm.Get("WebIdentityToken"))
+		assert.Equal(t, "kubeskippy", r.PostForm.Get("RoleSessionName"))
+		w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/kubeskippy")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", writeTokenFile(t, "projected-token"))
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+	source, err := newAWSCredentialSource(server.Client(), "us-east-1")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		creds, err := source.get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, awsCredentials{AccessKeyID: "ASIAEXAMPLE", SecretAccessKey: "secret", SessionToken: "session"}, creds)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "credentials are cached until they expire")
+}
+
+func TestContainerCredentials(t *testing.T) {
+	clearCloudEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "pod-identity-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"AccessKeyId": "ASIAPOD", "SecretAccessKey": "secret", "Token": "session", "Expiration": "` +
+			time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL)
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", writeTokenFile(t, "pod-identity-token"))
+	source, err := newAWSCredentialSource(server.Client(), "us-east-1")
+	require.NoError(t, err)
+
+	creds, err := source.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAPOD", creds.AccessKeyID)
+	assert.Equal(t, "session", creds.SessionToken)
+}
+
+func TestNewAWSCredentialSource(t *testing.T) {
+	clearCloudEnv(t)
+	_, err := newAWSCredentialSource(http.DefaultClient, "us-east-1")
+	assert.ErrorContains(t, err, "no AWS credentials")
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	source, err := newAWSCredentialSource(http.DefaultClient, "us-east-1")
+	require.NoError(t, err)
+	creds, err := source.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, awsCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, creds)
+}
+
+func TestAzureTokenSource(t *testing.T) {
+	clearCloudEnv(t)
+	_, err := newAzureTokenSource(http.DefaultClient)
+	assert.ErrorContains(t, err, "no Azure workload identity")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Equal(t, azureCognitiveServicesScope, r.PostForm.Get("scope"))
+		assert.Equal(t, "federated-token", r.PostForm.Get("client_assertion"))
+		w.Write([]byte(`{"token_type": "Bearer", "expires_in": 3599, "access_token": "azure-token"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", writeTokenFile(t, "federated-token"))
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL+"/")
+	source, err := newAzureTokenSource(server.Client())
+	require.NoError(t, err)
+	token, err := source.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "azure-token", token)
+}
@@ -0,0 +1,252 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// ClusterAnalyzer is the analyzer the coordinator shares between policies
+type ClusterAnalyzer interface {
+	AnalyzeClusterState(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue) (*types.AIAnalysis, error)
+	ValidateRecommendation(ctx context.Context, recommendation *types.AIRecommendation) error
+	GetModel() string
+}
+
+// StreamingAnalyzer is implemented by analyzers that report the progress of
+// an analysis while it runs
+type StreamingAnalyzer interface {
+	AnalyzeClusterStateStreaming(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error)
+}
+
+// Coordinator deduplicates AI analyses across policies. It makes at most one
+// AI call per interval for the whole cluster, covering the issues of every
+// policy that asked since the previous call, and hands each policy the
+// recommendations relevant to its own issues. Issues raised while the last
+// analysis is still fresh are queued for the next call.
+type Coordinator struct {
+	analyzer ClusterAnalyzer
+	interval time.Duration
+
+	mu      sync.Mutex
+	last    *types.AIAnalysis
+	lastAt  time.Time
+	batch   []types.Issue
+	pending map[string]types.Issue
+	metrics *types.ClusterMetrics
+
+	// waiting counts the callers waiting for the shared analysis
+	waiting atomic.Int32
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// NewCoordinator shares analyzer between policies, calling it at most once
+// per interval
+func NewCoordinator(analyzer ClusterAnalyzer, interval time.Duration) *Coordinator {
+	return &Coordinator{
+		analyzer: analyzer,
+		interval: interval,
+		pending:  make(map[string]types.Issue),
+		now:      time.Now,
+	}
+}
+
+// AnalyzeClusterState returns the part of the shared analysis relevant to
+// issues, running a new analysis when the last one is older than the interval
+func (c *Coordinator) AnalyzeClusterState(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue) (*types.AIAnalysis, error) {
+	return c.analyze(ctx, metrics, issues, nil)
+}
+
+// AnalyzeClusterStateStreaming is AnalyzeClusterState reporting the progress
+// of the shared analysis if this caller runs it. Callers waiting for or
+// reusing the analysis of another policy get no progress.
+func (c *Coordinator) AnalyzeClusterStateStreaming(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error) {
+	return c.analyze(ctx, metrics, issues, progress)
+}
+
+// analyze implements AnalyzeClusterState and AnalyzeClusterStateStreaming
+func (c *Coordinator) analyze(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error) {
+	log := log.FromContext(ctx)
+
+	c.waiting.Add(1)
+	c.mu.Lock()
+	c.waiting.Add(-1)
+	defer c.mu.Unlock()
+
+	for _, issue := range issues {
+		c.pending[issue.ID] = issue
+	}
+	c.metrics = mergeClusterMetrics(c.metrics, metrics)
+
+	if c.last != nil && c.now().Sub(c.lastAt) < c.interval {
+		log.V(1).Info("Reusing shared AI analysis", "age", c.now().Sub(c.lastAt), "queued_issues", len(c.pending))
+		return c.fanOut(issues), nil
+	}
+
+	batch := make([]types.Issue, 0, len(c.pending))
+	for _, issue := range c.pending {
+		batch = append(batch, issue)
+	}
+	log.Info("Running shared AI analysis", "issues", len(batch), "requested", len(issues))
+
+	var analysis *types.AIAnalysis
+	var err error
+	if streaming, ok := c.analyzer.(StreamingAnalyzer); ok && progress != nil {
+		analysis, err = streaming.AnalyzeClusterStateStreaming(ctx, c.metrics, batch, progress)
+	} else {
+		analysis, err = c.analyzer.AnalyzeClusterState(ctx, c.metrics, batch)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.last = analysis
+	c.lastAt = c.now()
+	c.batch = batch
+	c.pending = make(map[string]types.Issue)
+	c.metrics = nil
+	return c.fanOut(issues), nil
+}
+
+// Waiting returns the number of callers waiting for the shared analysis
+func (c *Coordinator) Waiting() int {
+	return int(c.waiting.Load())
+}
+
+// Reset drops the shared analysis and the queued issues, so the next caller
+// runs a fresh analysis. It fails instead of waiting while an analysis runs.
+func (c *Coordinator) Reset() error {
+	if !c.mu.TryLock() {
+		return fmt.Errorf("shared AI analysis in progress")
+	}
+	defer c.mu.Unlock()
+
+	c.last = nil
+	c.batch = nil
+	c.pending = make(map[string]types.Issue)
+	c.metrics = nil
+	return nil
+}
+
+// ValidateRecommendation delegates to the shared analyzer
+func (c *Coordinator) ValidateRecommendation(ctx context.Context, recommendation *types.AIRecommendation) error {
+	return c.analyzer.ValidateRecommendation(ctx, recommendation)
+}
+
+// GetModel returns the shared analyzer's model
+func (c *Coordinator) GetModel() string {
+	return c.analyzer.GetModel()
+}
+
+// fanOut returns a copy of the last analysis holding the AI issues and
+// recommendations relevant to issues. A recommendation is relevant when its
+// target names a resource of one of the issues, or when it names no
+// resource of the analyzed batch at all. Callers hold mu.
+func (c *Coordinator) fanOut(issues []types.Issue) *types.AIAnalysis {
+	analysis := *c.last
+	analysis.Issues = nil
+	analysis.Recommendations = nil
+
+	ids := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		ids[issue.ID] = true
+	}
+	for _, issue := range c.last.Issues {
+		if ids[issue.ID] {
+			analysis.Issues = append(analysis.Issues, issue)
+		}
+	}
+
+	for _, recommendation := range c.last.Recommendations {
+		if recommendationTargets(recommendation, c.batch) == 0 || recommendationTargets(recommendation, issues) > 0 {
+			analysis.Recommendations = append(analysis.Recommendations, recommendation)
+		}
+	}
+	return &analysis
+}
+
+// recommendationTargets counts the issues whose resource the
+// recommendation's target names
+func recommendationTargets(recommendation types.AIRecommendation, issues []types.Issue) int {
+	if recommendation.Target == "" {
+		return 0
+	}
+	count := 0
+	for _, issue := range issues {
+		name := issue.Resource
+		if i := strings.LastIndexAny(name, "|/"); i >= 0 {
+			name = name[i+1:]
+		}
+		if name != "" && strings.Contains(recommendation.Target, name) {
+			count++
+		}
+	}
+	return count
+}
+
+// mergeClusterMetrics combines the metrics collected by different policies,
+// keeping one entry per pod and node
+func mergeClusterMetrics(base, add *types.ClusterMetrics) *types.ClusterMetrics {
+	if base == nil {
+		if add == nil {
+			return nil
+		}
+		merged := *add
+		merged.Nodes = append([]types.NodeMetrics(nil), add.Nodes...)
+		merged.Pods = append([]types.PodMetrics(nil), add.Pods...)
+		merged.Events = append([]types.EventMetrics(nil), add.Events...)
+		merged.LogMatches = append([]types.LogMatch(nil), add.LogMatches...)
+		merged.Custom = make(map[string]float64, len(add.Custom))
+		for k, v := range add.Custom {
+			merged.Custom[k] = v
+		}
+		return &merged
+	}
+	if add == nil {
+		return base
+	}
+
+	if add.Timestamp.After(base.Timestamp) {
+		base.Timestamp = add.Timestamp
+	}
+	nodes := make(map[string]bool, len(base.Nodes))
+	for _, node := range base.Nodes {
+		nodes[node.Name] = true
+	}
+	for _, node := range add.Nodes {
+		if !nodes[node.Name] {
+			base.Nodes = append(base.Nodes, node)
+		}
+	}
+	pods := make(map[string]bool, len(base.Pods))
+	for _, pod := range base.Pods {
+		pods[pod.Namespace+"/"+pod.Name] = true
+	}
+	for _, pod := range add.Pods {
+		if !pods[pod.Namespace+"/"+pod.Name] {
+			base.Pods = append(base.Pods, pod)
+		}
+	}
+	base.Events = append(base.Events, add.Events...)
+	base.LogMatches = append(base.LogMatches, add.LogMatches...)
+	if base.Custom == nil {
+		base.Custom = make(map[string]float64, len(add.Custom))
+	}
+	for k, v := range add.Custom {
+		base.Custom[k] = v
+	}
+	// Capacity signals are cluster-wide, the same for every policy
+	if base.Capacity == nil {
+		base.Capacity = add.Capacity
+	}
+	return base
+}
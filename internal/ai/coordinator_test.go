@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+type countingAnalyzer struct {
+	calls   int
+	issues  [][]types.Issue
+	metrics []*types.ClusterMetrics
+	err     error
+}
+
+func (a *countingAnalyzer) AnalyzeClusterState(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue) (*types.AIAnalysis, error) {
+	a.calls++
+	a.issues = append(a.issues, issues)
+	a.metrics = append(a.metrics, metrics)
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &types.AIAnalysis{
+		Summary: "shared",
+		Issues: []types.AIIssue{
+			{ID: "restarts-web-1"},
+			{ID: "memory-api-1"},
+		},
+		Recommendations: []types.AIRecommendation{
+			{ID: "rec-1", Action: "restart", Target: "Pod/web-1"},
+			{ID: "rec-2", Action: "scale", Target: "Deployment/api-1"},
+			{ID: "rec-3", Action: "patch", Target: "cluster"},
+		},
+	}, nil
+}
+
+func (a *countingAnalyzer) ValidateRecommendation(ctx context.Context, recommendation *types.AIRecommendation) error {
+	return nil
+}
+
+func (a *countingAnalyzer) GetModel() string {
+	return "test-model"
+}
+
+func TestCoordinator(t *testing.T) {
+	backend := &countingAnalyzer{}
+	coordinator := NewCoordinator(backend, 5*time.Minute)
+	now := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	coordinator.now = func() time.Time { return now }
+
+	webIssues := []types.Issue{{ID: "restarts-web-1", Resource: ":v1:Pod|default|web-1"}}
+	apiIssues := []types.Issue{{ID: "memory-api-1", Resource: "apps:v1:Deployment|default|api-1"}}
+	webMetrics := &types.ClusterMetrics{Pods: []types.PodMetrics{{Name: "web-1", Namespace: "default"}}}
+	apiMetrics := &types.ClusterMetrics{Pods: []types.PodMetrics{
+		{Name: "web-1", Namespace: "default"},
+		{Name: "api-1", Namespace: "default"},
+	}}
+
+	// The first policy triggers an analysis
+	web, err := coordinator.AnalyzeClusterState(context.Background(), webMetrics, webIssues)
+	require.NoError(t, err)
+	assert.Equal(t, 1, backend.calls)
+
+	// Within the interval other policies share it
+	now = now.Add(time.Minute)
+	api, err := coordinator.AnalyzeClusterState(context.Background(), apiMetrics, apiIssues)
+	require.NoError(t, err)
+	assert.Equal(t, 1, backend.calls)
+
+	// Each policy gets its own recommendations plus the general ones
+	recommendationIDs := func(analysis *types.AIAnalysis) []string {
+		var ids []string
+		for _, rec := range analysis.Recommendations {
+			ids = append(ids, rec.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"rec-1", "rec-2", "rec-3"}, recommendationIDs(web),
+		"api-1 was not part of the first batch, so rec-2 is general to it")
+	assert.Equal(t, []string{"rec-2", "rec-3"}, recommendationIDs(api), "rec-1 targets the other policy's pod")
+	assert.Equal(t, []types.AIIssue{{ID: "memory-api-1"}}, api.Issues)
+
+	// The next analysis covers the issues queued since the last one, over
+	// the merged metrics
+	now = now.Add(5 * time.Minute)
+	web, err = coordinator.AnalyzeClusterState(context.Background(), webMetrics, webIssues)
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.calls)
+	assert.ElementsMatch(t, append(webIssues, apiIssues...), backend.issues[1])
+	assert.Len(t, backend.metrics[1].Pods, 2)
+	assert.Equal(t, []string{"rec-1", "rec-3"}, recommendationIDs(web))
+	assert.Equal(t, []types.AIIssue{{ID: "restarts-web-1"}}, web.Issues)
+	assert.Equal(t, "test-model", coordinator.GetModel())
+}
+
+func TestCoordinator_Error(t *testing.T) {
+	backend := &countingAnalyzer{err: errors.New("provider unavailable")}
+	coordinator := NewCoordinator(backend, 5*time.Minute)
+
+	_, err := coordinator.AnalyzeClusterState(context.Background(), nil, []types.Issue{{ID: "a"}})
+	assert.Error(t, err)
+
+	// Failed analyses are not cached and keep their issues queued
+	backend.err = nil
+	_, err = coordinator.AnalyzeClusterState(context.Background(), nil, []types.Issue{{ID: "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.calls)
+	assert.ElementsMatch(t, []types.Issue{{ID: "a"}, {ID: "b"}}, backend.issues[1])
+}
+
+func TestCoordinator_Reset(t *testing.T) {
+	backend := &countingAnalyzer{}
+	coordinator := NewCoordinator(backend, time.Hour)
+
+	_, err := coordinator.AnalyzeClusterState(context.Background(), &types.ClusterMetrics{}, []types.Issue{{ID: "restarts-web-1"}})
+	require.NoError(t, err)
+	require.NoError(t, coordinator.Reset())
+
+	// The cached analysis is dropped, so the next caller analyzes again
+	_, err = coordinator.AnalyzeClusterState(context.Background(), &types.ClusterMetrics{}, []types.Issue{{ID: "memory-api-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.calls)
+	assert.Equal(t, []types.Issue{{ID: "memory-api-1"}}, backend.issues[1])
+
+	// An analysis in progress is not interrupted
+	coordinator.mu.Lock()
+	assert.ErrorContains(t, coordinator.Reset(), "in progress")
+	coordinator.mu.Unlock()
+	assert.Zero(t, coordinator.Waiting())
+}
+
+// streamingCountingAnalyzer reports one progress update per analysis
+type streamingCountingAnalyzer struct {
+	countingAnalyzer
+}
+
+func (a *streamingCountingAnalyzer) AnalyzeClusterStateStreaming(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error) {
+	progress(types.AIProgress{StepsCompleted: 1})
+	return a.AnalyzeClusterState(ctx, metrics, issues)
+}
+
+func TestCoordinator_Streaming(t *testing.T) {
+	backend := &streamingCountingAnalyzer{}
+	coordinator := NewCoordinator(backend, 5*time.Minute)
+	issues := []types.Issue{{ID: "restarts-web-1", Resource: ":v1:Pod|default|web-1"}}
+
+	var reported int
+	progress := func(types.AIProgress) { reported++ }
+	_, err := coordinator.AnalyzeClusterStateStreaming(context.Background(), &types.ClusterMetrics{}, issues, progress)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reported)
+
+	// Callers reusing the shared analysis get no progress
+	_, err = coordinator.AnalyzeClusterStateStreaming(context.Background(), &types.ClusterMetrics{}, issues, progress)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reported)
+	assert.Equal(t, 1, backend.calls)
+}
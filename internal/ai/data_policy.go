@@ -0,0 +1,228 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// localProviders are AI providers that run inside the cluster boundary and
+// may receive data from restricted namespaces
+var localProviders = map[string]bool{
+	"ollama": true,
+}
+
+// IsExternalProvider reports whether a provider sends data outside the
+// cluster
+func IsExternalProvider(provider string) bool {
+	return !localProviders[provider]
+}
+
+// WithNamespaceReader enables the namespace data policy. Namespaces labeled
+// kubeskippy.io/ai-data-policy=restricted are redacted from every payload
+// sent to an external provider.
+func (a *Analyzer) WithNamespaceReader(reader client.Reader) {
+	a.namespaceReader = reader
+}
+
+// restrictedNamespaces returns the namespaces whose data must not leave the
+// cluster. It returns nil when the provider is local or no reader is set.
+func (a *Analyzer) restrictedNamespaces(ctx context.Context) (map[string]bool, error) {
+	if a.namespaceReader == nil || !IsExternalProvider(a.config.Provider) {
+		return nil, nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := a.namespaceReader.List(ctx, namespaces,
+		client.MatchingLabels{types.LabelAIDataPolicy: types.AIDataPolicyRestricted}); err != nil {
+		return nil, fmt.Errorf("failed to list restricted namespaces: %w", err)
+	}
+
+	restricted := make(map[string]bool, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		restricted[ns.Name] = true
+	}
+	return restricted, nil
+}
+
+// redactionReport counts what was withheld from a payload
+type redactionReport struct {
+	Namespaces []string
+	Pods       int
+	Events     int
+	LogMatches int
+	Custom     int
+	Patterns   int
+	Issues     int
+}
+
+func (r redactionReport) empty() bool {
+	return r.Pods+r.Events+r.LogMatches+r.Custom+r.Patterns+r.Issues == 0
+}
+
+// redactRestricted returns copies of the metrics and issues without any data
+// from restricted namespaces. The inputs are not modified.
+func redactRestricted(metrics *types.ClusterMetrics, issues []types.Issue, restricted map[string]bool) (*types.ClusterMetrics, []types.Issue, redactionReport) {
+	report := redactionReport{}
+	if len(restricted) == 0 {
+		return metrics, issues, report
+	}
+
+	seen := map[string]bool{}
+	note := func(ns string) {
+		if !seen[ns] {
+			seen[ns] = true
+			report.Namespaces = append(report.Namespaces, ns)
+		}
+	}
+
+	var redacted *types.ClusterMetrics
+	if metrics != nil {
+		copied := *metrics
+		redacted = &copied
+
+		redacted.Pods = nil
+		for _, pod := range metrics.Pods {
+			if restricted[pod.Namespace] {
+				report.Pods++
+				note(pod.Namespace)
+				continue
+			}
+			redacted.Pods = append(redacted.Pods, pod)
+		}
+
+		redacted.Events = nil
+		for _, event := range metrics.Events {
+			if ns := eventNamespace(event); restricted[ns] {
+				report.Events++
+				note(ns)
+				continue
+			}
+			redacted.Events = append(redacted.Events, event)
+		}
+
+		redacted.LogMatches = nil
+		for _, match := range metrics.LogMatches {
+			if restricted[match.Namespace] {
+				report.LogMatches++
+				note(match.Namespace)
+				continue
+			}
+			redacted.LogMatches = append(redacted.LogMatches, match)
+		}
+
+		redacted.Patterns = nil
+		for _, pattern := range metrics.Patterns {
+			if ns := patternNamespace(pattern); restricted[ns] {
+				report.Patterns++
+				note(ns)
+				continue
+			}
+			redacted.Patterns = append(redacted.Patterns, pattern)
+		}
+
+		if metrics.Custom != nil {
+			// Aggregates may be computed from restricted samples, so they
+			// are withheld along with the per-workload values
+			tainted := map[string]bool{}
+			for key := range metrics.Custom {
+				if name, ns := splitCustomMetricKey(key); restricted[ns] {
+					tainted[name] = true
+					note(ns)
+				}
+			}
+			redacted.Custom = make(map[string]float64, len(metrics.Custom))
+			for key, value := range metrics.Custom {
+				if name, _ := splitCustomMetricKey(key); tainted[name] {
+					report.Custom++
+					continue
+				}
+				redacted.Custom[key] = value
+			}
+		}
+
+		// The counts stay, only the restricted pods are withheld
+		if metrics.Capacity != nil {
+			capacity := *metrics.Capacity
+			capacity.UnschedulablePods = nil
+			for _, pod := range metrics.Capacity.UnschedulablePods {
+				if restricted[pod.Namespace] {
+					report.Pods++
+					note(pod.Namespace)
+					continue
+				}
+				capacity.UnschedulablePods = append(capacity.UnschedulablePods, pod)
+			}
+			redacted.Capacity = &capacity
+		}
+	}
+
+	var kept []types.Issue
+	for _, issue := range issues {
+		if restricted[issue.Namespace] {
+			report.Issues++
+			note(issue.Namespace)
+			continue
+		}
+		kept = append(kept, issue)
+	}
+
+	sort.Strings(report.Namespaces)
+	return redacted, kept, report
+}
+
+// eventNamespace extracts the namespace from an event object reference of
+// the form Kind/namespace/name
+func eventNamespace(event types.EventMetrics) string {
+	parts := strings.SplitN(event.Object, "/", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+// patternNamespace extracts the namespace from a pattern target of the
+// form namespace/name
+func patternNamespace(pattern pkgtypes.Pattern) string {
+	ns, _, found := strings.Cut(pattern.Target, "/")
+	if !found {
+		return ""
+	}
+	return ns
+}
+
+// splitCustomMetricKey splits a custom metric key into its metric name and,
+// for per-workload keys of the form custom:name:namespace/workload, the
+// namespace
+func splitCustomMetricKey(key string) (string, string) {
+	i := strings.LastIndex(key, ":")
+	if i < 0 {
+		return key, ""
+	}
+	ns, _, found := strings.Cut(key[i+1:], "/")
+	if !found {
+		return key, ""
+	}
+	return key[:i], ns
+}
+
+// auditRedaction records that restricted data was withheld from a provider
+func (a *Analyzer) auditRedaction(ctx context.Context, report redactionReport) {
+	log.FromContext(ctx).Info("Audit: AI data policy enforced",
+		"provider", a.config.Provider,
+		"namespaces", report.Namespaces,
+		"pods", report.Pods,
+		"events", report.Events,
+		"logMatches", report.LogMatches,
+		"customMetrics", report.Custom,
+		"patterns", report.Patterns,
+		"issues", report.Issues)
+}
@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+func newDataPolicyReader(t *testing.T, funcs *interceptor.Funcs) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "payments",
+			Labels: map[string]string{types.LabelAIDataPolicy: types.AIDataPolicyRestricted},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+	)
+	if funcs != nil {
+		builder = builder.WithInterceptorFuncs(*funcs)
+	}
+	return builder.Build()
+}
+
+func dataPolicyMetrics() *types.ClusterMetrics {
+	return &types.ClusterMetrics{
+		Timestamp: time.Now(),
+		Pods: []types.PodMetrics{
+			{Name: "web-7d9f", Namespace: "web", RestartCount: 4},
+			{Name: "ledger-5c2a", Namespace: "payments", RestartCount: 9},
+		},
+		Events: []types.EventMetrics{
+			{Type: "Warning", Reason: "BackOff", Object: "Pod/web/web-7d9f"},
+			{Type: "Warning", Reason: "BackOff", Object: "Pod/payments/ledger-5c2a"},
+		},
+		Custom: map[string]float64{
+			"custom:queue_depth":                 120,
+			"custom:queue_depth:payments/ledger": 120,
+			"custom:queue_depth:web/frontend":    3,
+			"custom:latency_ms:web/frontend":     40,
+		},
+		LogMatches: []types.LogMatch{
+			{Pod: "ledger-5c2a", Namespace: "payments", Line: "card declined"},
+		},
+		Patterns: []pkgtypes.Pattern{
+			{Name: "slow-leak", Target: "payments/ledger-5c2a", Description: "memory grows 2% per minute", Detector: "leaks"},
+			{Name: "flapping", Target: "web/web-7d9f", Description: "CPU oscillates every 30s", Detector: "flapping"},
+		},
+	}
+}
+
+func TestAnalyzer_DataPolicy(t *testing.T) {
+	issues := []types.Issue{
+		{ID: "restarts-web", Namespace: "web", Description: "web-7d9f restarting"},
+		{ID: "restarts-ledger", Namespace: "payments", Description: "ledger-5c2a restarting"},
+	}
+
+	newAnalyzer := func(provider string, reader client.Reader, prompt *string, queried *bool) *Analyzer {
+		a := &Analyzer{
+			config: config.AIConfig{Provider: provider},
+			client: &MockAIClient{
+				Available: true,
+				QueryFunc: func(ctx context.Context, p string, temperature float32) (string, error) {
+					*prompt = p
+					*queried = true
+					return defaultMockResponse, nil
+				},
+			},
+			prompts: &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+		}
+		a.WithNamespaceReader(reader)
+		return a
+	}
+
+	t.Run("external provider never sees restricted data", func(t *testing.T) {
+		var prompt string
+		var queried bool
+		a := newAnalyzer("openai", newDataPolicyReader(t, nil), &prompt, &queried)
+
+		analysis, err := a.AnalyzeClusterState(context.Background(), dataPolicyMetrics(), issues)
+		require.NoError(t, err)
+		require.True(t, queried)
+
+		assert.Contains(t, prompt, "web-7d9f")
+		assert.Contains(t, prompt, "custom:latency_ms:web/frontend")
+		assert.Contains(t, prompt, "CPU oscillates every 30s", "detected patterns are part of the analysis")
+		for _, leaked := range []string{"payments", "ledger", "card declined", "queue_depth"} {
+			assert.NotContains(t, prompt, leaked)
+		}
+		assert.Equal(t, []string{"payments"}, analysis.RestrictedNamespaces)
+	})
+
+	t.Run("all issues restricted skips the provider", func(t *testing.T) {
+		var prompt string
+		var queried bool
+		a := newAnalyzer("grpc", newDataPolicyReader(t, nil), &prompt, &queried)
+
+		analysis, err := a.AnalyzeClusterState(context.Background(), dataPolicyMetrics(), issues[1:])
+		require.NoError(t, err)
+		assert.False(t, queried)
+		assert.Empty(t, analysis.Recommendations)
+		assert.Equal(t, []string{"payments"}, analysis.RestrictedNamespaces)
+	})
+
+	t.Run("local provider receives everything", func(t *testing.T) {
+		var prompt string
+		var queried bool
+		a := newAnalyzer("ollama", newDataPolicyReader(t, nil), &prompt, &queried)
+
+		analysis, err := a.AnalyzeClusterState(context.Background(), dataPolicyMetrics(), issues)
+		require.NoError(t, err)
+		assert.Contains(t, prompt, "ledger-5c2a")
+		assert.Empty(t, analysis.RestrictedNamespaces)
+	})
+
+	t.Run("fails closed when namespaces cannot be read", func(t *testing.T) {
+		var prompt string
+		var queried bool
+		reader := newDataPolicyReader(t, &interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return errors.New("forbidden")
+			},
+		})
+		a := newAnalyzer("openai", reader, &prompt, &queried)
+
+		_, err := a.AnalyzeClusterState(context.Background(), dataPolicyMetrics(), issues)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AI data policy")
+		assert.False(t, queried)
+	})
+}
+
+func TestRedactRestricted_DoesNotModifyInput(t *testing.T) {
+	metrics := dataPolicyMetrics()
+	metrics.Capacity = &pkgtypes.CapacityMetrics{
+		PendingUnschedulable: 2,
+		UnschedulablePods: []pkgtypes.UnschedulablePod{
+			{Name: "ledger-6b1c", Namespace: "payments", Message: "0/3 nodes are available: 3 Insufficient cpu."},
+			{Name: "web-8e0a", Namespace: "web", Message: "0/3 nodes are available: 3 Insufficient cpu."},
+		},
+	}
+	issues := []types.Issue{{ID: "a", Namespace: "payments"}}
+
+	redacted, kept, report := redactRestricted(metrics, issues, map[string]bool{"payments": true})
+
+	assert.Len(t, metrics.Pods, 2)
+	assert.Len(t, metrics.Capacity.UnschedulablePods, 2)
+	assert.Equal(t, 2, redacted.Capacity.PendingUnschedulable)
+	require.Len(t, redacted.Capacity.UnschedulablePods, 1)
+	assert.Equal(t, "web-8e0a", redacted.Capacity.UnschedulablePods[0].Name)
+	assert.Len(t, metrics.Custom, 4)
+	assert.Len(t, redacted.Pods, 1)
+	assert.Len(t, redacted.Events, 1)
+	assert.Empty(t, redacted.LogMatches)
+	assert.Len(t, metrics.Patterns, 2)
+	assert.Equal(t, "flapping", redacted.Patterns[0].Name)
+	assert.Len(t, redacted.Patterns, 1)
+	assert.Equal(t, map[string]float64{"custom:latency_ms:web/frontend": 40}, redacted.Custom)
+	assert.Empty(t, kept)
+	assert.Equal(t, redactionReport{
+		Namespaces: []string{"payments"},
+		Pods:       2,
+		Events:     1,
+		LogMatches: 1,
+		Custom:     3,
+		Patterns:   1,
+		Issues:     1,
+	}, report)
+}
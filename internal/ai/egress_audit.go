@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+var (
+	aiEgressPrompts *prometheus.CounterVec
+	aiEgressBytes   *prometheus.CounterVec
+	aiEgressItems   *prometheus.CounterVec
+)
+
+// SetEgressMetrics sets the AI data egress metrics from main.go
+func SetEgressMetrics(prompts, bytes, items *prometheus.CounterVec) {
+	aiEgressPrompts = prompts
+	aiEgressBytes = bytes
+	aiEgressItems = items
+}
+
+// Prompt purposes recorded with each egress
+const (
+	egressPurposeAnalysis   = "analysis"
+	egressPurposeValidation = "validation"
+)
+
+// egressManifest lists the cluster data included in one outbound prompt.
+// Object, namespace and metric names are listed as they are, while event
+// messages and log lines are only listed as hashes.
+type egressManifest struct {
+	Namespaces    []string
+	Objects       []string
+	MetricNames   []string
+	EventMessages []string
+	LogLines      []string
+	Issues        int
+}
+
+// analysisEgress returns the manifest of a cluster analysis prompt built
+// from the (already redacted) metrics and issues
+func analysisEgress(metrics *types.ClusterMetrics, issues []types.Issue) egressManifest {
+	namespaces, objects, metricNames := nameSet{}, nameSet{}, nameSet{}
+	var manifest egressManifest
+
+	if metrics != nil {
+		for _, node := range metrics.Nodes {
+			objects.add("Node/" + node.Name)
+		}
+		for _, pod := range metrics.Pods {
+			namespaces.add(pod.Namespace)
+			objects.add("Pod/" + pod.Namespace + "/" + pod.Name)
+		}
+		for _, event := range metrics.Events {
+			namespaces.add(eventNamespace(event))
+			objects.add(event.Object)
+			manifest.EventMessages = append(manifest.EventMessages, hashEgressText(event.Message))
+		}
+		for _, match := range metrics.LogMatches {
+			namespaces.add(match.Namespace)
+			objects.add("Pod/" + match.Namespace + "/" + match.Pod)
+			manifest.LogLines = append(manifest.LogLines, hashEgressText(match.Line))
+		}
+		for _, pattern := range metrics.Patterns {
+			namespaces.add(patternNamespace(pattern))
+			objects.add(pattern.Target)
+		}
+		for key := range metrics.Custom {
+			name, ns := splitCustomMetricKey(key)
+			namespaces.add(ns)
+			metricNames.add(name)
+		}
+		for key := range metrics.Resources {
+			metricNames.add(key)
+		}
+	}
+
+	for _, issue := range issues {
+		namespaces.add(issue.Namespace)
+		objects.add(issue.Resource)
+	}
+	manifest.Issues = len(issues)
+
+	manifest.Namespaces = namespaces.sorted()
+	manifest.Objects = objects.sorted()
+	manifest.MetricNames = metricNames.sorted()
+	return manifest
+}
+
+// validationEgress returns the manifest of a validation prompt, which only
+// names the targets of the recommendations
+func validationEgress(recs []*types.AIRecommendation) egressManifest {
+	objects := nameSet{}
+	for _, rec := range recs {
+		objects.add(rec.Target)
+	}
+	return egressManifest{Objects: objects.sorted()}
+}
+
+// auditEgress records the cluster data included in a prompt before it is
+// sent to the provider, so that what left the cluster can be audited
+func (a *Analyzer) auditEgress(ctx context.Context, purpose, prompt string, manifest egressManifest) {
+	log.FromContext(ctx).Info("Audit: AI data egress",
+		"provider", a.config.Provider,
+		"model", a.client.GetModel(),
+		"purpose", purpose,
+		"promptHash", hashPrompt(prompt),
+		"promptBytes", len(prompt),
+		"namespaces", manifest.Namespaces,
+		"objects", manifest.Objects,
+		"metricNames", manifest.MetricNames,
+		"eventMessages", len(manifest.EventMessages),
+		"eventMessageHashes", manifest.EventMessages,
+		"logLines", len(manifest.LogLines),
+		"logLineHashes", manifest.LogLines,
+		"issues", manifest.Issues)
+
+	provider := a.config.Provider
+	if aiEgressPrompts != nil {
+		aiEgressPrompts.WithLabelValues(provider, purpose).Inc()
+	}
+	if aiEgressBytes != nil {
+		aiEgressBytes.WithLabelValues(provider).Add(float64(len(prompt)))
+	}
+	if aiEgressItems != nil {
+		for kind, count := range map[string]int{
+			"namespaces":     len(manifest.Namespaces),
+			"objects":        len(manifest.Objects),
+			"metric_names":   len(manifest.MetricNames),
+			"event_messages": len(manifest.EventMessages),
+			"log_lines":      len(manifest.LogLines),
+			"issues":         manifest.Issues,
+		} {
+			aiEgressItems.WithLabelValues(provider, kind).Add(float64(count))
+		}
+	}
+}
+
+// hashEgressText returns a short SHA-256 of text sent to a provider, enough
+// to match it against the source without logging it
+func hashEgressText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:6])
+}
+
+// nameSet collects distinct non-empty names
+type nameSet map[string]bool
+
+func (s nameSet) add(name string) {
+	if name = strings.TrimSpace(name); name != "" {
+		s[name] = true
+	}
+}
+
+func (s nameSet) sorted() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// useEgressMetrics installs fresh egress metrics for a test
+func useEgressMetrics(t *testing.T) (prompts, bytes, items *prometheus.CounterVec) {
+	prompts = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_ai_egress_prompts_total"},
+		[]string{"provider", "purpose"})
+	bytes = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_ai_egress_bytes_total"},
+		[]string{"provider"})
+	items = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_ai_egress_items_total"},
+		[]string{"provider", "kind"})
+	previousPrompts, previousBytes, previousItems := aiEgressPrompts, aiEgressBytes, aiEgressItems
+	SetEgressMetrics(prompts, bytes, items)
+	t.Cleanup(func() { SetEgressMetrics(previousPrompts, previousBytes, previousItems) })
+	return prompts, bytes, items
+}
+
+func TestAnalysisEgress(t *testing.T) {
+	metrics := dataPolicyMetrics()
+	metrics.Events[0].Message = "Back-off restarting failed container"
+	issues := []types.Issue{{ID: "restarts-web", Resource: "Pod/web/web-7d9f", Namespace: "web"}}
+
+	manifest := analysisEgress(metrics, issues)
+
+	assert.Equal(t, []string{"payments", "web"}, manifest.Namespaces)
+	assert.Equal(t, []string{
+		"Pod/payments/ledger-5c2a",
+		"Pod/web/web-7d9f",
+		"payments/ledger-5c2a",
+		"web/web-7d9f",
+	}, manifest.Objects)
+	assert.Equal(t, []string{"custom:latency_ms", "custom:queue_depth"}, manifest.MetricNames)
+	require.Len(t, manifest.EventMessages, 2)
+	assert.Equal(t, hashEgressText("Back-off restarting failed container"), manifest.EventMessages[0])
+	assert.Len(t, manifest.EventMessages[0], 12)
+	assert.Equal(t, []string{hashEgressText("card declined")}, manifest.LogLines)
+	assert.Equal(t, 1, manifest.Issues)
+
+	assert.Equal(t, egressManifest{Namespaces: []string{}, Objects: []string{}, MetricNames: []string{}},
+		analysisEgress(nil, nil))
+}
+
+func TestAnalyzer_AuditsEgress(t *testing.T) {
+	prompts, bytes, items := useEgressMetrics(t)
+	var sent []string
+	a := &Analyzer{
+		config: config.AIConfig{Provider: "openai"},
+		client: &MockAIClient{
+			Available: true,
+			QueryFunc: func(ctx context.Context, p string, temperature float32) (string, error) {
+				sent = append(sent, p)
+				return defaultMockResponse, nil
+			},
+		},
+		prompts: &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+	}
+	a.WithNamespaceReader(newDataPolicyReader(t, nil))
+	issues := []types.Issue{{ID: "restarts-web", Namespace: "web"}}
+
+	_, err := a.AnalyzeClusterState(context.Background(), dataPolicyMetrics(), issues)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+
+	// Only what is left after redaction is counted as sent
+	assert.Equal(t, 1.0, testutil.ToFloat64(prompts.WithLabelValues("openai", "analysis")))
+	assert.Equal(t, float64(len(sent[0])), testutil.ToFloat64(bytes.WithLabelValues("openai")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(items.WithLabelValues("openai", "namespaces")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(items.WithLabelValues("openai", "objects")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(items.WithLabelValues("openai", "metric_names")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(items.WithLabelValues("openai", "event_messages")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(items.WithLabelValues("openai", "log_lines")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(items.WithLabelValues("openai", "issues")))
+
+	recs := []*types.AIRecommendation{{Action: "restart", Target: "web/web-7d9f"}, {Action: "scale", Target: "web/api"}}
+	a.validateBatchWithAI(context.Background(), recs)
+	assert.Equal(t, 1.0, testutil.ToFloat64(prompts.WithLabelValues("openai", "validation")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(items.WithLabelValues("openai", "objects")))
+	assert.Equal(t, []string{"web/api", "web/web-7d9f"}, validationEgress(recs).Objects)
+}
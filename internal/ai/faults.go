@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/kubeskippy/kubeskippy/internal/faults"
+)
+
+// WithFaultInjection delays and fails the analyzer's AI queries at the
+// injector's rates
+func (a *Analyzer) WithFaultInjection(injector *faults.Injector) {
+	if injector != nil {
+		a.client = &faultyClient{AIClient: a.client, faults: injector}
+	}
+}
+
+// faultyClient injects delays and failures into the queries of an AI client
+type faultyClient struct {
+	AIClient
+	faults *faults.Injector
+}
+
+// Query queries the client unless a failure is injected
+func (f *faultyClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	if err := f.faults.AIFault(ctx); err != nil {
+		return "", err
+	}
+	return f.AIClient.Query(ctx, prompt, temperature)
+}
+
+// StreamQuery streams the client's response unless a failure is injected
+func (f *faultyClient) StreamQuery(ctx context.Context, prompt string, temperature float32, callback func(chunk string) error) error {
+	if err := f.faults.AIFault(ctx); err != nil {
+		return err
+	}
+	streaming, ok := f.AIClient.(StreamingClient)
+	if !ok {
+		response, err := f.AIClient.Query(ctx, prompt, temperature)
+		if err != nil {
+			return err
+		}
+		return callback(response)
+	}
+	return streaming.StreamQuery(ctx, prompt, temperature, callback)
+}
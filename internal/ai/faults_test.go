@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/faults"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestAnalyzer_WithFaultInjection(t *testing.T) {
+	queried := 0
+	a := &Analyzer{
+		config: config.AIConfig{Provider: "ollama"},
+		client: &MockAIClient{
+			Available: true,
+			QueryFunc: func(ctx context.Context, prompt string, temperature float32) (string, error) {
+				queried++
+				return defaultMockResponse, nil
+			},
+		},
+		prompts: &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+	}
+
+	a.WithFaultInjection(nil)
+	_, err := a.AnalyzeClusterState(context.Background(), &types.ClusterMetrics{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queried)
+
+	a.WithFaultInjection(faults.NewInjector(config.FaultInjectionConfig{AIErrorRate: 1}, 1))
+	_, err = a.AnalyzeClusterState(context.Background(), &types.ClusterMetrics{}, nil)
+	assert.ErrorIs(t, err, faults.ErrInjected)
+	assert.Equal(t, 1, queried, "failed queries do not reach the provider")
+	assert.Equal(t, "mock/test-model", a.GetModel(), "the wrapped client answers everything else")
+}
@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/internal/ai/inference"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// maxGRPCMessageSize bounds response messages
+const maxGRPCMessageSize = 16 * 1024 * 1024
+
+// GRPCClient implements the AIClient interface for in-cluster inference
+// gateways speaking the KServe v2 gRPC protocol (KServe, Triton). Requests
+// are spread over a small pool of connections.
+type GRPCClient struct {
+	model        string
+	timeout      time.Duration
+	inputTensor  string
+	outputTensor string
+
+	conns   []*grpc.ClientConn
+	clients []inference.GRPCInferenceServiceClient
+	next    atomic.Uint64
+}
+
+// NewGRPCClient creates a new gRPC inference client
+func NewGRPCClient(endpoint, model string, timeout time.Duration, cfg config.GRPCConfig) (*GRPCClient, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("gRPC endpoint is required")
+	}
+	if model == "" {
+		return nil, fmt.Errorf("gRPC model is required")
+	}
+	// Endpoints may be given as URLs
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		endpoint = strings.TrimSuffix(endpoint[i+3:], "/")
+	}
+
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		tlsConfig, err := grpcTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	size := cfg.MaxConnections
+	if size <= 0 {
+		size = 4
+	}
+	client := &GRPCClient{
+		model:        model,
+		timeout:      timeout,
+		inputTensor:  cfg.InputTensor,
+		outputTensor: cfg.OutputTensor,
+	}
+	if client.inputTensor == "" {
+		client.inputTensor = "text_input"
+	}
+	if client.outputTensor == "" {
+		client.outputTensor = "text_output"
+	}
+
+	for i := 0; i < size; i++ {
+		conn, err := grpc.NewClient(endpoint,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxGRPCMessageSize)))
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("invalid gRPC endpoint: %w", err)
+		}
+		client.conns = append(client.conns, conn)
+		client.clients = append(client.clients, inference.NewGRPCInferenceServiceClient(conn))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if !client.IsAvailable(ctx) {
+		client.Close()
+		return nil, fmt.Errorf("gRPC inference service is not available at %s", endpoint)
+	}
+
+	return client, nil
+}
+
+// Query sends a prompt to the inference service and returns the response
+func (g *GRPCClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	log := log.FromContext(ctx)
+	log.V(1).Info("Querying gRPC inference service", "model", g.model, "prompt_length", len(prompt))
+
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	response, err := g.client().ModelInfer(ctx, newModelInferRequest(g.model, g.inputTensor, g.outputTensor, prompt, temperature))
+	if err != nil {
+		return "", grpcError("ModelInfer", err)
+	}
+
+	text, err := modelInferText(response, g.outputTensor)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.V(1).Info("gRPC query completed", "response_length", len(text))
+	return text, nil
+}
+
+// GetModel returns the model identifier
+func (g *GRPCClient) GetModel() string {
+	return fmt.Sprintf("grpc/%s", g.model)
+}
+
+// IsAvailable checks that the model is ready
+func (g *GRPCClient) IsAvailable(ctx context.Context) bool {
+	response, err := g.client().ModelReady(ctx, &inference.ModelReadyRequest{Name: g.model})
+	return err == nil && response.GetReady()
+}
+
+// Close closes the pooled connections
+func (g *GRPCClient) Close() error {
+	var firstErr error
+	for _, conn := range g.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// client returns the next client of the pool
+func (g *GRPCClient) client() inference.GRPCInferenceServiceClient {
+	return g.clients[g.next.Add(1)%uint64(len(g.clients))]
+}
+
+// grpcError describes a failed call with its status code
+func grpcError(method string, err error) error {
+	if s, ok := status.FromError(err); ok {
+		return fmt.Errorf("gRPC call %s failed with code %d: %s", method, s.Code(), s.Message())
+	}
+	return fmt.Errorf("gRPC call %s failed: %w", method, err)
+}
+
+// grpcTLSConfig builds the TLS configuration, including client certificates for mTLS
+func grpcTLSConfig(cfg config.GRPCConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// newModelInferRequest builds a ModelInferRequest with a single BYTES input
+// tensor holding the prompt
+func newModelInferRequest(model, inputTensor, outputTensor, prompt string, temperature float32) *inference.ModelInferRequest {
+	return &inference.ModelInferRequest{
+		ModelName: model,
+		Parameters: map[string]*inference.InferParameter{
+			"temperature": {ParameterChoice: &inference.InferParameter_DoubleParam{DoubleParam: float64(temperature)}},
+		},
+		Inputs: []*inference.ModelInferRequest_InferInputTensor{{
+			Name:     inputTensor,
+			Datatype: "BYTES",
+			Shape:    []int64{1},
+			Contents: &inference.InferTensorContents{BytesContents: [][]byte{[]byte(prompt)}},
+		}},
+		Outputs: []*inference.ModelInferRequest_InferRequestedOutputTensor{{Name: outputTensor}},
+	}
+}
+
+// modelInferText extracts the text of the named output tensor, from either
+// typed contents or raw_output_contents
+func modelInferText(response *inference.ModelInferResponse, outputTensor string) (string, error) {
+	raw := response.GetRawOutputContents()
+	for i, output := range response.GetOutputs() {
+		if output.GetName() != outputTensor {
+			continue
+		}
+		if contents := output.GetContents().GetBytesContents(); len(contents) > 0 {
+			return string(contents[0]), nil
+		}
+
+		// BYTES elements in raw contents are prefixed with a 4-byte little-endian length
+		if i < len(raw) && len(raw[i]) >= 4 {
+			length := binary.LittleEndian.Uint32(raw[i][:4])
+			if int(length) <= len(raw[i])-4 {
+				return string(raw[i][4 : 4+length]), nil
+			}
+		}
+		return "", fmt.Errorf("output tensor %q has no contents", outputTensor)
+	}
+
+	return "", fmt.Errorf("output tensor %q not found in response", outputTensor)
+}
@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubeskippy/kubeskippy/internal/ai/inference"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// inferenceServer emulates the KServe v2 gRPC inference service
+type inferenceServer struct {
+	inference.UnimplementedGRPCInferenceServiceServer
+	t *testing.T
+}
+
+func (s *inferenceServer) ModelReady(ctx context.Context, req *inference.ModelReadyRequest) (*inference.ModelReadyResponse, error) {
+	return &inference.ModelReadyResponse{Ready: req.GetName() == "llama"}, nil
+}
+
+func (s *inferenceServer) ModelInfer(ctx context.Context, req *inference.ModelInferRequest) (*inference.ModelInferResponse, error) {
+	_, ok := ctx.Deadline()
+	assert.True(s.t, ok, "deadline should be propagated")
+	assert.InDelta(s.t, 0.2, req.GetParameters()["temperature"].GetDoubleParam(), 1e-6)
+
+	prompt := string(req.GetInputs()[0].GetContents().GetBytesContents()[0])
+	if prompt == "fail" {
+		return nil, status.Error(codes.ResourceExhausted, "resource exhausted")
+	}
+	return inferResponse(req.GetOutputs()[0].GetName(), "echo: "+prompt), nil
+}
+
+// newInferenceServer starts a plaintext gRPC inference server
+func newInferenceServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	inference.RegisterGRPCInferenceServiceServer(server, &inferenceServer{t: t})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// inferResponse builds a ModelInferResponse with a BYTES output in raw_output_contents
+func inferResponse(output, text string) *inference.ModelInferResponse {
+	raw := make([]byte, 4, 4+len(text))
+	binary.LittleEndian.PutUint32(raw, uint32(len(text)))
+	raw = append(raw, text...)
+
+	return &inference.ModelInferResponse{
+		ModelName:         "llama",
+		Outputs:           []*inference.ModelInferResponse_InferOutputTensor{{Name: output, Datatype: "BYTES"}},
+		RawOutputContents: [][]byte{raw},
+	}
+}
+
+func TestGRPCClient(t *testing.T) {
+	endpoint := newInferenceServer(t)
+
+	cfg := config.GRPCConfig{Insecure: true, MaxConnections: 2}
+	client, err := NewGRPCClient(endpoint, "llama", 5*time.Second, cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, "grpc/llama", client.GetModel())
+	assert.Len(t, client.conns, 2)
+
+	t.Run("query", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			response, err := client.Query(context.Background(), "hello", 0.2)
+			require.NoError(t, err)
+			assert.Equal(t, "echo: hello", response)
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		_, err := client.Query(context.Background(), "fail", 0.2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "code 8: resource exhausted")
+	})
+
+	t.Run("model not ready", func(t *testing.T) {
+		_, err := NewGRPCClient("http://"+endpoint, "mistral", time.Second, cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewGRPCClient_Validation(t *testing.T) {
+	_, err := NewGRPCClient("", "llama", time.Second, config.GRPCConfig{Insecure: true})
+	assert.Error(t, err)
+
+	_, err = NewGRPCClient("localhost:1", "", time.Second, config.GRPCConfig{Insecure: true})
+	assert.Error(t, err)
+
+	_, err = NewGRPCClient("localhost:1", "llama", time.Second, config.GRPCConfig{CAFile: "/nonexistent/ca.pem"})
+	assert.Error(t, err)
+}
+
+func TestModelInferText(t *testing.T) {
+	text, err := modelInferText(inferResponse("text_output", "ok"), "text_output")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", text)
+
+	typed := &inference.ModelInferResponse{Outputs: []*inference.ModelInferResponse_InferOutputTensor{{
+		Name:     "text_output",
+		Contents: &inference.InferTensorContents{BytesContents: [][]byte{[]byte("typed")}},
+	}}}
+	text, err = modelInferText(typed, "text_output")
+	require.NoError(t, err)
+	assert.Equal(t, "typed", text)
+
+	_, err = modelInferText(inferResponse("other", "ok"), "text_output")
+	assert.Error(t, err)
+}
@@ -12,6 +12,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// assistantSystemPrompt is the system prompt of chat providers
+const assistantSystemPrompt = "You are a Kubernetes cluster healing expert assistant. Provide detailed, actionable recommendations for cluster issues."
+
 // OpenAIClient implements the AIClient interface for OpenAI
 type OpenAIClient struct {
 	apiKey     string
@@ -108,7 +111,7 @@ func (o *OpenAIClient) Query(ctx context.Context, prompt string, temperature flo
 		Messages: []Message{
 			{
 				Role:    "system",
-				Content: "You are a Kubernetes cluster healing expert assistant. Provide detailed, actionable recommendations for cluster issues.",
+				Content: assistantSystemPrompt,
 			},
 			{
 				Role:    "user",
@@ -228,7 +231,7 @@ func (o *OpenAIClient) StreamQuery(ctx context.Context, prompt string, temperatu
 		"messages": []Message{
 			{
 				Role:    "system",
-				Content: "You are a Kubernetes cluster healing expert assistant. Provide detailed, actionable recommendations for cluster issues.",
+				Content: assistantSystemPrompt,
 			},
 			{
 				Role:    "user",
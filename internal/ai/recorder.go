@@ -0,0 +1,195 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Recording modes
+const (
+	// RecordingModeRecord persists every response of the provider
+	RecordingModeRecord = "record"
+
+	// RecordingModeReplay serves recorded responses without a provider
+	RecordingModeReplay = "replay"
+)
+
+// promptTimestampPattern matches the RFC 3339 timestamps in prompts, such
+// as the analysis time and the collection time of the metrics
+var promptTimestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// recordedResponse is a prompt and response pair as stored on disk
+type recordedResponse struct {
+	Model    string `json:"model"`
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
+}
+
+// RecordingClient records the responses of an AI client to a directory,
+// one JSON file per model and prompt, or replays them from it. Prompts are
+// matched ignoring their timestamps. Replay is
+// deterministic and never contacts a provider, so tests and CI can run the
+// parsing and filtering logic against real model output.
+type RecordingClient struct {
+	client AIClient
+	model  string
+	dir    string
+	replay bool
+}
+
+// NewRecordingClient wraps client to record its responses to dir
+func NewRecordingClient(client AIClient, dir string) (*RecordingClient, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	return &RecordingClient{client: client, model: client.GetModel(), dir: dir}, nil
+}
+
+// NewReplayClient creates a client serving the responses recorded for
+// model in dir
+func NewReplayClient(model, dir string) (*RecordingClient, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("recording path %s is not a directory", dir)
+	}
+	return &RecordingClient{model: model, dir: dir, replay: true}, nil
+}
+
+// newRecordingClient creates the AI client of the recording configuration.
+// The provider's client is not created for replays.
+func newRecordingClient(cfg config.AIConfig, provider func() (AIClient, error)) (AIClient, error) {
+	switch cfg.Recording.Mode {
+	case "":
+		return provider()
+	case RecordingModeRecord:
+		client, err := provider()
+		if err != nil {
+			return nil, err
+		}
+		return NewRecordingClient(client, cfg.Recording.Dir)
+	case RecordingModeReplay:
+		return NewReplayClient(cfg.Model, cfg.Recording.Dir)
+	default:
+		return nil, fmt.Errorf("unsupported AI recording mode: %s", cfg.Recording.Mode)
+	}
+}
+
+// Query returns the recorded response to the prompt when replaying, or
+// queries the client and records its response
+func (r *RecordingClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	if r.replay {
+		return r.load(prompt)
+	}
+
+	response, err := r.client.Query(ctx, prompt, temperature)
+	if err != nil {
+		return "", err
+	}
+	return response, r.save(prompt, response)
+}
+
+// StreamQuery replays the recorded response as a single chunk, or streams
+// the client's response and records it once complete
+func (r *RecordingClient) StreamQuery(ctx context.Context, prompt string, temperature float32, callback func(chunk string) error) error {
+	if r.replay {
+		response, err := r.load(prompt)
+		if err != nil {
+			return err
+		}
+		return callback(response)
+	}
+
+	streaming, ok := r.client.(StreamingClient)
+	if !ok {
+		response, err := r.Query(ctx, prompt, temperature)
+		if err != nil {
+			return err
+		}
+		return callback(response)
+	}
+
+	var response strings.Builder
+	err := streaming.StreamQuery(ctx, prompt, temperature, func(chunk string) error {
+		response.WriteString(chunk)
+		return callback(chunk)
+	})
+	if err != nil {
+		return err
+	}
+	return r.save(prompt, response.String())
+}
+
+// GetModel returns the model identifier
+func (r *RecordingClient) GetModel() string {
+	return r.model
+}
+
+// IsAvailable checks the client; replays are always available
+func (r *RecordingClient) IsAvailable(ctx context.Context) bool {
+	return r.replay || r.client.IsAvailable(ctx)
+}
+
+// path returns the file of the response to prompt. Timestamps are masked
+// so prompts built from the same data at different times share a response.
+func (r *RecordingClient) path(prompt string) string {
+	prompt = promptTimestampPattern.ReplaceAllString(prompt, "<time>")
+	sum := sha256.Sum256([]byte(r.model + "\x00" + prompt))
+	return filepath.Join(r.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// load reads the recorded response to prompt
+func (r *RecordingClient) load(prompt string) (string, error) {
+	path := r.path(prompt)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no recorded response for the prompt in %s", path)
+		}
+		return "", fmt.Errorf("failed to read recorded response: %w", err)
+	}
+
+	var recorded recordedResponse
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return "", fmt.Errorf("invalid recorded response %s: %w", path, err)
+	}
+	return recorded.Response, nil
+}
+
+// save records the response to prompt, replacing an earlier recording.
+// The file is renamed into place so replays never read partial files.
+func (r *RecordingClient) save(prompt, response string) error {
+	data, err := json.MarshalIndent(recordedResponse{Model: r.model, Prompt: prompt, Response: response}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := r.path(prompt)
+	tmp, err := os.CreateTemp(r.dir, ".recording-*")
+	if err != nil {
+		return fmt.Errorf("failed to record response: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to record response: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to record response: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to record response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestRecordingClient(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recordings")
+
+	queries := 0
+	provider := &MockAIClient{
+		Model:     "mock/llama",
+		Available: true,
+		QueryFunc: func(ctx context.Context, prompt string, temperature float32) (string, error) {
+			queries++
+			return "response to " + prompt[:5], nil
+		},
+	}
+	recorder, err := NewRecordingClient(provider, dir)
+	require.NoError(t, err)
+
+	prompt := "Analyze at 2024-05-15T10:00:00Z"
+	response, err := recorder.Query(context.Background(), prompt, 0.2)
+	require.NoError(t, err)
+	assert.Equal(t, "response to Analy", response)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	replay, err := NewReplayClient("mock/llama", dir)
+	require.NoError(t, err)
+	assert.True(t, replay.IsAvailable(context.Background()))
+
+	t.Run("replays ignoring timestamps", func(t *testing.T) {
+		response, err := replay.Query(context.Background(), "Analyze at 2025-01-02T03:04:05.123+01:00", 0.2)
+		require.NoError(t, err)
+		assert.Equal(t, "response to Analy", response)
+		assert.Equal(t, 1, queries, "replays never reach the provider")
+	})
+
+	t.Run("streams the recorded response", func(t *testing.T) {
+		var chunks []string
+		err := replay.StreamQuery(context.Background(), prompt, 0.2, func(chunk string) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"response to Analy"}, chunks)
+	})
+
+	t.Run("unknown prompt", func(t *testing.T) {
+		_, err := replay.Query(context.Background(), "Validate the safety", 0.2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no recorded response")
+	})
+
+	t.Run("other model", func(t *testing.T) {
+		other, err := NewReplayClient("mock/mistral", dir)
+		require.NoError(t, err)
+		_, err = other.Query(context.Background(), prompt, 0.2)
+		assert.Error(t, err)
+	})
+}
+
+func TestRecordingClient_RecordsStreams(t *testing.T) {
+	dir := t.TempDir()
+	provider := &streamingMockClient{MockAIClient: MockAIClient{Model: "mock/llama", Available: true}, chunks: []string{"SUMMARY:\n", "ok\n", "END"}}
+	recorder, err := NewRecordingClient(provider, dir)
+	require.NoError(t, err)
+
+	var streamed strings.Builder
+	require.NoError(t, recorder.StreamQuery(context.Background(), "prompt", 0.2, func(chunk string) error {
+		streamed.WriteString(chunk)
+		return nil
+	}))
+	assert.False(t, provider.queried)
+
+	replay, err := NewReplayClient("mock/llama", dir)
+	require.NoError(t, err)
+	response, err := replay.Query(context.Background(), "prompt", 0.2)
+	require.NoError(t, err)
+	assert.Equal(t, streamed.String(), response)
+}
+
+func TestNewAnalyzer_Replay(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.AIConfig{
+		Provider:      "ollama",
+		Model:         "llama2:7b",
+		MinConfidence: 0.7,
+		Recording:     config.AIRecordingConfig{Mode: RecordingModeReplay, Dir: dir},
+	}
+
+	metrics := &types.ClusterMetrics{Timestamp: time.Now()}
+	issues := []types.Issue{{ID: "issue-1", Severity: "High", Description: "Node CPU usage above 80%"}}
+
+	// Record the analysis of a mock provider, as a real model would answer
+	recording := cfg
+	recording.Recording.Mode = RecordingModeRecord
+	recorder, err := newRecordingClient(recording, func() (AIClient, error) {
+		return &MockAIClient{Model: cfg.Model, Available: true}, nil
+	})
+	require.NoError(t, err)
+	analyzer := &Analyzer{
+		config:  recording,
+		client:  recorder,
+		prompts: &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+	}
+	_, err = analyzer.AnalyzeClusterState(context.Background(), metrics, issues)
+	require.NoError(t, err)
+
+	// The replaying analyzer needs no reachable provider
+	replaying, err := NewAnalyzer(cfg)
+	require.NoError(t, err)
+	metrics.Timestamp = time.Now().Add(time.Minute)
+	analysis, err := replaying.AnalyzeClusterState(context.Background(), metrics, issues)
+	require.NoError(t, err)
+	assert.Len(t, analysis.Recommendations, 2)
+
+	cfg.Recording.Mode = "rewind"
+	_, err = NewAnalyzer(cfg)
+	assert.Error(t, err)
+}
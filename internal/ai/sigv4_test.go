@@ -0,0 +1,29 @@
+package ai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The get-vanilla case of the AWS Signature Version 4 test suite
+func TestSignSigV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+	signSigV4(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestSigV4CanonicalURI(t *testing.T) {
+	assert.Equal(t, "/", sigV4CanonicalURI(""))
+	assert.Equal(t, "/model/anthropic.claude-v2%253A1/converse", sigV4CanonicalURI("/model/anthropic.claude-v2%3A1/converse"))
+	assert.Equal(t, "a=1&a=2&a-b=3", sigV4CanonicalQuery(map[string][]string{"a-b": {"3"}, "a": {"2", "1"}}))
+}
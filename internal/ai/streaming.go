@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// StreamingClient is implemented by AI clients that can stream their
+// response as it is generated
+type StreamingClient interface {
+	// StreamQuery sends a prompt to the AI and passes each chunk of the
+	// response to callback
+	StreamQuery(ctx context.Context, prompt string, temperature float32, callback func(chunk string) error) error
+}
+
+// AnalyzeClusterStateStreaming analyzes the cluster state like
+// AnalyzeClusterState, streaming the response from providers that support
+// it and reporting the reasoning steps to progress as they arrive
+func (a *Analyzer) AnalyzeClusterStateStreaming(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error) {
+	return a.analyze(ctx, metrics, issues, progress)
+}
+
+// query sends the prompt to the AI, streaming the response when progress
+// is wanted and the client supports it
+func (a *Analyzer) query(ctx context.Context, prompt string, progress func(types.AIProgress)) (string, error) {
+	streaming, ok := a.client.(StreamingClient)
+	if progress == nil || !ok || a.config.ProgressInterval <= 0 {
+		return a.client.Query(ctx, prompt, a.config.Temperature)
+	}
+
+	tracker := &progressTracker{report: progress}
+	err := streaming.StreamQuery(ctx, prompt, a.config.Temperature, func(chunk string) error {
+		tracker.add(chunk)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return tracker.response.String(), nil
+}
+
+// progressTracker accumulates a streamed response and reports the
+// reasoning steps found in its complete lines whenever they change
+type progressTracker struct {
+	report   func(types.AIProgress)
+	response strings.Builder
+	parsed   int
+	last     types.AIProgress
+}
+
+// add appends a chunk of the response
+func (t *progressTracker) add(chunk string) {
+	t.response.WriteString(chunk)
+	if !strings.Contains(chunk, "\n") {
+		return
+	}
+
+	text := t.response.String()
+	complete := strings.LastIndex(text, "\n")
+	if complete <= t.parsed {
+		return
+	}
+	t.parsed = complete
+
+	current := streamedProgress(text[:complete])
+	if current != t.last {
+		t.last = current
+		t.report(current)
+	}
+}
+
+// streamedProgress reads the progress of a partial analysis response. A
+// reasoning step is completed once the next step or the ISSUES section
+// starts; the confidence is that of the latest completed step.
+func streamedProgress(text string) types.AIProgress {
+	if !strings.Contains(text, "REASONING_STEPS") {
+		return types.AIProgress{}
+	}
+	steps := parseReasoningSteps(extractSection(text, "REASONING_STEPS", "ISSUES"))
+	if len(steps) == 0 {
+		return types.AIProgress{}
+	}
+
+	completed := steps[:len(steps)-1]
+	if strings.Contains(text[strings.Index(text, "REASONING_STEPS"):], "ISSUES") {
+		completed = steps
+	}
+	progress := types.AIProgress{
+		StepsCompleted: len(completed),
+		CurrentStep:    steps[len(steps)-1].Description,
+	}
+	if len(completed) > 0 {
+		progress.Confidence = completed[len(completed)-1].Confidence
+	}
+	return progress
+}
@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// streamingMockClient streams its response in chunks
+type streamingMockClient struct {
+	MockAIClient
+	chunks  []string
+	queried bool
+}
+
+func (m *streamingMockClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	m.queried = true
+	return strings.Join(m.chunks, ""), nil
+}
+
+func (m *streamingMockClient) StreamQuery(ctx context.Context, prompt string, temperature float32, callback func(chunk string) error) error {
+	for _, chunk := range m.chunks {
+		if err := callback(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const streamedResponse = `SUMMARY:
+Pods of web are crash looping.
+
+REASONING_STEPS:
+Step 1: Inspect restarts
+  Evidence: 12 restarts in 10 minutes
+  Confidence: 0.6
+Step 2: Correlate with memory
+  Evidence: OOMKilled
+  Confidence: 0.85
+
+ISSUES:
+- Crash loop
+  Severity: High
+
+RECOMMENDATIONS:
+1. Increase memory limits
+   Target: deployment/web
+   Confidence: 0.9
+
+END`
+
+func TestAnalyzer_AnalyzeClusterStateStreaming(t *testing.T) {
+	// Stream the response a few characters at a time
+	var chunks []string
+	for i := 0; i < len(streamedResponse); i += 7 {
+		chunks = append(chunks, streamedResponse[i:min(i+7, len(streamedResponse))])
+	}
+	issues := []types.Issue{{ID: "crash-web", Description: "crash loop"}}
+
+	t.Run("reports reasoning steps as they arrive", func(t *testing.T) {
+		client := &streamingMockClient{MockAIClient: MockAIClient{Available: true}, chunks: chunks}
+		analyzer := &Analyzer{
+			config:  config.AIConfig{ProgressInterval: 1},
+			client:  client,
+			prompts: &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+		}
+
+		var reported []types.AIProgress
+		analysis, err := analyzer.AnalyzeClusterStateStreaming(context.Background(), &types.ClusterMetrics{}, issues,
+			func(progress types.AIProgress) { reported = append(reported, progress) })
+		require.NoError(t, err)
+		assert.False(t, client.queried)
+		assert.Len(t, analysis.ReasoningSteps, 2)
+		assert.Len(t, analysis.Recommendations, 1)
+
+		assert.Equal(t, []types.AIProgress{
+			{StepsCompleted: 0, CurrentStep: "Inspect restarts"},
+			{StepsCompleted: 1, CurrentStep: "Correlate with memory", Confidence: 0.6},
+			{StepsCompleted: 2, CurrentStep: "Correlate with memory", Confidence: 0.85},
+		}, reported)
+	})
+
+	t.Run("queries without streaming when disabled", func(t *testing.T) {
+		client := &streamingMockClient{MockAIClient: MockAIClient{Available: true}, chunks: chunks}
+		analyzer := &Analyzer{
+			client:  client,
+			prompts: &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+		}
+
+		var reported int
+		analysis, err := analyzer.AnalyzeClusterStateStreaming(context.Background(), &types.ClusterMetrics{}, issues,
+			func(types.AIProgress) { reported++ })
+		require.NoError(t, err)
+		assert.True(t, client.queried)
+		assert.Zero(t, reported)
+		assert.Len(t, analysis.ReasoningSteps, 2)
+	})
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// Exchange is one query sent to the AI provider and its raw response
+type Exchange struct {
+	// Purpose is "analysis" or "validation"
+	Purpose  string `json:"purpose"`
+	Prompt   string `json:"prompt"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RejectedRecommendation is a recommendation dropped by the validation of
+// an analysis
+type RejectedRecommendation struct {
+	Recommendation types.AIRecommendation `json:"recommendation"`
+	Reason         string                 `json:"reason"`
+}
+
+// Trace records what the analyses of a context sent to and received from
+// the AI provider, for tuning prompts and thresholds. Attach it with
+// WithTrace.
+type Trace struct {
+	mu sync.Mutex
+
+	Exchanges []Exchange `json:"exchanges"`
+
+	// Parsed is the last analysis as parsed from the response, before its
+	// recommendations were validated
+	Parsed *types.AIAnalysis `json:"parsedAnalysis,omitempty"`
+
+	// Rejected are the recommendations validation dropped
+	Rejected []RejectedRecommendation `json:"rejectedRecommendations,omitempty"`
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context whose analyses are recorded in trace
+func WithTrace(ctx context.Context, trace *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// traceFrom returns the trace of a context, or nil
+func traceFrom(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// recordExchange records a query. A nil trace records nothing.
+func (t *Trace) recordExchange(purpose, prompt, response string, err error) {
+	if t == nil {
+		return
+	}
+	exchange := Exchange{Purpose: purpose, Prompt: prompt, Response: response}
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Exchanges = append(t.Exchanges, exchange)
+}
+
+// recordParsed records a copy of an analysis before validation
+func (t *Trace) recordParsed(analysis *types.AIAnalysis) {
+	if t == nil {
+		return
+	}
+	parsed := *analysis
+	parsed.Recommendations = append([]types.AIRecommendation(nil), analysis.Recommendations...)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Parsed = &parsed
+}
+
+// recordRejected records a recommendation validation dropped
+func (t *Trace) recordRejected(recommendation types.AIRecommendation, reason string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Rejected = append(t.Rejected, RejectedRecommendation{Recommendation: recommendation, Reason: reason})
+}
@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestTrace(t *testing.T) {
+	analyzer := &Analyzer{
+		config:   config.AIConfig{Provider: "mock", MinConfidence: 0.88},
+		client:   &MockAIClient{Available: true},
+		prompts:  &PromptTemplates{ClusterAnalysis: defaultClusterAnalysisPrompt},
+		validate: true,
+	}
+	metrics := &types.ClusterMetrics{Timestamp: time.Now()}
+	issues := []types.Issue{{ID: "issue-1", Severity: "High", Description: "Node CPU usage above 80%"}}
+
+	trace := &Trace{}
+	analysis, err := analyzer.AnalyzeClusterState(WithTrace(context.Background(), trace), metrics, issues)
+	require.NoError(t, err)
+
+	require.Len(t, trace.Exchanges, 1)
+	assert.Equal(t, egressPurposeAnalysis, trace.Exchanges[0].Purpose)
+	assert.Contains(t, trace.Exchanges[0].Prompt, "Node CPU usage above 80%")
+	assert.Equal(t, defaultMockResponse, trace.Exchanges[0].Response)
+
+	require.NotNil(t, trace.Parsed)
+	assert.Len(t, trace.Parsed.Recommendations, 2, "parsed before validation")
+	require.NotEmpty(t, trace.Rejected)
+	assert.Equal(t, 0.85, trace.Rejected[0].Recommendation.Confidence)
+	assert.Equal(t, "confidence 0.85 below 0.88", trace.Rejected[0].Reason)
+	assert.Len(t, analysis.Recommendations, 2-len(trace.Rejected))
+
+	// Failed queries are recorded with their error
+	analyzer.client = &MockAIClient{Available: true, QueryFunc: func(ctx context.Context, prompt string, temperature float32) (string, error) {
+		return "", errors.New("connection refused")
+	}}
+	trace = &Trace{}
+	_, err = analyzer.AnalyzeClusterState(WithTrace(context.Background(), trace), metrics, issues)
+	require.Error(t, err)
+	require.Len(t, trace.Exchanges, 1)
+	assert.Equal(t, "connection refused", trace.Exchanges[0].Error)
+	assert.Nil(t, trace.Parsed)
+
+	// Without a trace nothing is recorded
+	_, err = analyzer.AnalyzeClusterState(context.Background(), metrics, issues)
+	require.Error(t, err)
+}
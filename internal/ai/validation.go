@@ -0,0 +1,271 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// ValidationModeIndividual sends one validation query per recommendation
+	ValidationModeIndividual = "individual"
+
+	// ValidationModeBatch validates all recommendations in a single query
+	ValidationModeBatch = "batch"
+)
+
+// localRule rejects recommendations without querying the AI. check returns
+// the reason for rejecting the recommendation, or "" to accept it.
+type localRule struct {
+	name  string
+	check func(rec *types.AIRecommendation) string
+}
+
+// localRules is the first validation tier, evaluated before any AI query
+var localRules = []localRule{
+	{
+		name: "unsafe-action",
+		check: func(rec *types.AIRecommendation) string {
+			action := strings.ToLower(rec.Action)
+			for _, unsafe := range []string{"delete-namespace", "delete-node", "delete-pv", "delete-crd"} {
+				if strings.Contains(action, unsafe) {
+					return fmt.Sprintf("unsafe action detected: %s", unsafe)
+				}
+			}
+			return ""
+		},
+	},
+	{
+		name: "wildcard-target",
+		check: func(rec *types.AIRecommendation) string {
+			target := strings.ToLower(strings.TrimSpace(rec.Target))
+			if strings.Contains(target, "*") || target == "all" {
+				return fmt.Sprintf("unsafe target detected: %s", rec.Target)
+			}
+			return ""
+		},
+	},
+	{
+		name: "system-namespace-delete",
+		check: func(rec *types.AIRecommendation) string {
+			if strings.Contains(strings.ToLower(rec.Action), "delete") &&
+				strings.Contains(strings.ToLower(rec.Target), "kube-system") {
+				return "unsafe action detected: delete in kube-system"
+			}
+			return ""
+		},
+	},
+}
+
+// riskLevels orders the risk levels accepted by ValidateMinRisk
+var riskLevels = []string{"low", "medium", "high", "critical"}
+
+// riskLevel maps a free-form risk description to its level index. Unknown
+// risks are treated as high so they are still validated.
+func riskLevel(risk string) int {
+	risk = strings.ToLower(risk)
+	if strings.Contains(risk, "minimal") || strings.Contains(risk, "none") {
+		return 0
+	}
+	level := -1
+	for i, name := range riskLevels {
+		if strings.Contains(risk, name) {
+			level = i
+		}
+	}
+	if level < 0 {
+		return 2
+	}
+	return level
+}
+
+// validateLocally checks a recommendation against the basic requirements and
+// the local rules
+func (a *Analyzer) validateLocally(rec *types.AIRecommendation) error {
+	if rec.Action == "" {
+		return fmt.Errorf("recommendation has no action specified")
+	}
+
+	if rec.Target == "" {
+		return fmt.Errorf("recommendation has no target specified")
+	}
+
+	for _, rule := range localRules {
+		if reason := rule.check(rec); reason != "" {
+			return fmt.Errorf("%s", reason)
+		}
+	}
+
+	if rec.Confidence < float64(a.config.MinConfidence) {
+		return fmt.Errorf("recommendation confidence %.2f is below threshold %.2f",
+			rec.Confidence, a.config.MinConfidence)
+	}
+
+	return nil
+}
+
+// needsAIValidation reports whether a recommendation should also be
+// validated by the AI
+func (a *Analyzer) needsAIValidation(rec *types.AIRecommendation) bool {
+	if !a.config.ValidateResponses {
+		return false
+	}
+	if a.config.ValidateMinRisk == "" {
+		return true
+	}
+	return riskLevel(rec.Risk) >= riskLevel(a.config.ValidateMinRisk)
+}
+
+// validateRecommendations validates the recommendations and returns one
+// error per recommendation (nil if valid). Recommendations needing AI
+// validation are checked in a single query in batch mode.
+func (a *Analyzer) validateRecommendations(ctx context.Context, recs []types.AIRecommendation) []error {
+	errs := make([]error, len(recs))
+	var pending []int
+	for i := range recs {
+		if err := a.validateLocally(&recs[i]); err != nil {
+			errs[i] = err
+			continue
+		}
+		if a.needsAIValidation(&recs[i]) {
+			pending = append(pending, i)
+		}
+	}
+
+	if len(pending) == 0 {
+		return errs
+	}
+
+	if a.config.ValidationMode != ValidationModeBatch || len(pending) == 1 {
+		for _, i := range pending {
+			errs[i] = a.validateWithAI(ctx, &recs[i])
+		}
+		return errs
+	}
+
+	batch := make([]*types.AIRecommendation, len(pending))
+	for j, i := range pending {
+		batch[j] = &recs[i]
+	}
+	for j, err := range a.validateBatchWithAI(ctx, batch) {
+		errs[pending[j]] = err
+	}
+	return errs
+}
+
+// validateWithAI validates a single recommendation with an AI query
+func (a *Analyzer) validateWithAI(ctx context.Context, rec *types.AIRecommendation) error {
+	prompt := a.buildValidationPrompt(rec)
+	a.auditEgress(ctx, egressPurposeValidation, prompt, validationEgress([]*types.AIRecommendation{rec}))
+	response, err := a.client.Query(ctx, prompt, 0.1) // Low temperature for validation
+	traceFrom(ctx).recordExchange(egressPurposeValidation, prompt, response, err)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to validate recommendation with AI")
+		return fmt.Errorf("validation query failed: %w", err)
+	}
+
+	if safe, found := parseVerdict(response); !found || !safe {
+		return fmt.Errorf("AI validation failed: %s", response)
+	}
+	return nil
+}
+
+// validateBatchWithAI validates several recommendations with one AI query.
+// Recommendations without a verdict in the response are rejected.
+func (a *Analyzer) validateBatchWithAI(ctx context.Context, recs []*types.AIRecommendation) []error {
+	errs := make([]error, len(recs))
+
+	prompt := buildBatchValidationPrompt(recs)
+	a.auditEgress(ctx, egressPurposeValidation, prompt, validationEgress(recs))
+	response, err := a.client.Query(ctx, prompt, 0.1)
+	traceFrom(ctx).recordExchange(egressPurposeValidation, prompt, response, err)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to batch validate recommendations with AI")
+		for i := range errs {
+			errs[i] = fmt.Errorf("validation query failed: %w", err)
+		}
+		return errs
+	}
+
+	verdicts := parseBatchVerdicts(response)
+	for i := range recs {
+		verdict, found := verdicts[i+1]
+		switch {
+		case !found:
+			errs[i] = fmt.Errorf("AI validation returned no verdict for recommendation %d", i+1)
+		case !verdict.safe:
+			errs[i] = fmt.Errorf("AI validation failed: %s", verdict.text)
+		}
+	}
+	return errs
+}
+
+// buildBatchValidationPrompt lists the recommendations in one prompt
+func buildBatchValidationPrompt(recs []*types.AIRecommendation) string {
+	var items strings.Builder
+	for i, rec := range recs {
+		fmt.Fprintf(&items, "%d. ACTION: %s\n   TARGET: %s\n   REASON: %s\n   RISK: %s\n\n",
+			i+1, rec.Action, rec.Target, rec.Reason, rec.Risk)
+	}
+	return fmt.Sprintf(defaultBatchValidationPrompt, items.String())
+}
+
+// batchVerdict is the verdict for one recommendation of a batch
+type batchVerdict struct {
+	safe bool
+	text string
+}
+
+// batchVerdictPattern matches "<number>. <verdict>" response lines
+var batchVerdictPattern = regexp.MustCompile(`^\s*(\d+)[.):]\s*(.+)$`)
+
+// parseBatchVerdicts extracts the numbered verdicts from a batch response
+func parseBatchVerdicts(response string) map[int]batchVerdict {
+	verdicts := make(map[int]batchVerdict)
+	for _, line := range strings.Split(response, "\n") {
+		m := batchVerdictPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		if _, seen := verdicts[n]; seen {
+			continue
+		}
+		if safe, found := parseVerdict(m[2]); found {
+			verdicts[n] = batchVerdict{safe: safe, text: strings.TrimSpace(m[2])}
+		}
+	}
+	return verdicts
+}
+
+// parseVerdict reads a SAFE or UNSAFE verdict from a response
+func parseVerdict(text string) (safe bool, found bool) {
+	upper := strings.ToUpper(text)
+	if strings.Contains(upper, "UNSAFE") {
+		return false, true
+	}
+	if strings.Contains(upper, "SAFE") {
+		return true, true
+	}
+	return false, false
+}
+
+const defaultBatchValidationPrompt = `Validate the safety of the following Kubernetes healing actions:
+
+%s
+Is each action safe to execute automatically? Consider:
+- Potential for data loss
+- Impact on application availability
+- Cluster stability
+- Security implications
+
+Respond with one line per action in the form "<number>. SAFE" or "<number>. UNSAFE" followed by an explanation.`
@@ -0,0 +1,157 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestAnalyzer_validateRecommendations(t *testing.T) {
+	recs := func() []types.AIRecommendation {
+		return []types.AIRecommendation{
+			{Action: "restart", Target: "deployment/web", Risk: "Low", Confidence: 0.9},
+			{Action: "scale up", Target: "deployment/api", Risk: "High, may exhaust capacity", Confidence: 0.9},
+			{Action: "delete-node", Target: "node/worker-1", Risk: "Critical", Confidence: 0.9},
+			{Action: "patch", Target: "deployment/worker", Risk: "Medium", Confidence: 0.9},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		mode            string
+		minRisk         string
+		response        string
+		expectedQueries int
+		expectValid     []bool
+	}{
+		{
+			name:            "individual mode queries per recommendation",
+			mode:            ValidationModeIndividual,
+			response:        "SAFE",
+			expectedQueries: 3,
+			expectValid:     []bool{true, true, false, true},
+		},
+		{
+			name:            "batch mode queries once",
+			mode:            ValidationModeBatch,
+			response:        "1. SAFE\n2. UNSAFE - capacity\n3. SAFE",
+			expectedQueries: 1,
+			expectValid:     []bool{true, false, false, true},
+		},
+		{
+			name:            "batch mode rejects missing verdicts",
+			mode:            ValidationModeBatch,
+			response:        "1. SAFE",
+			expectedQueries: 1,
+			expectValid:     []bool{true, false, false, false},
+		},
+		{
+			name:            "only risky recommendations are validated",
+			mode:            ValidationModeIndividual,
+			minRisk:         "high",
+			response:        "UNSAFE",
+			expectedQueries: 1,
+			expectValid:     []bool{true, false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompts []string
+			analyzer := &Analyzer{
+				config: config.AIConfig{
+					MinConfidence:     0.7,
+					ValidateResponses: true,
+					ValidationMode:    tt.mode,
+					ValidateMinRisk:   tt.minRisk,
+				},
+				client: &MockAIClient{
+					Available: true,
+					QueryFunc: func(ctx context.Context, prompt string, temperature float32) (string, error) {
+						prompts = append(prompts, prompt)
+						return tt.response, nil
+					},
+				},
+				prompts: &PromptTemplates{ActionValidation: defaultActionValidationPrompt},
+			}
+
+			errs := analyzer.validateRecommendations(context.Background(), recs())
+			assert.Len(t, prompts, tt.expectedQueries)
+			require.Len(t, errs, len(tt.expectValid))
+			for i, valid := range tt.expectValid {
+				assert.Equal(t, valid, errs[i] == nil, "recommendation %d: %v", i+1, errs[i])
+			}
+			assert.ErrorContains(t, errs[2], "unsafe action detected: delete-node",
+				"local rules reject before any AI query")
+		})
+	}
+}
+
+func TestAnalyzer_validateBatchWithAI_QueryError(t *testing.T) {
+	analyzer := &Analyzer{
+		client: &MockAIClient{
+			QueryFunc: func(ctx context.Context, prompt string, temperature float32) (string, error) {
+				return "", errors.New("timeout")
+			},
+		},
+	}
+
+	errs := analyzer.validateBatchWithAI(context.Background(), []*types.AIRecommendation{
+		{Action: "restart", Target: "deployment/web"},
+		{Action: "scale", Target: "deployment/api"},
+	})
+	for _, err := range errs {
+		assert.ErrorContains(t, err, "validation query failed")
+	}
+}
+
+func TestBuildBatchValidationPrompt(t *testing.T) {
+	prompt := buildBatchValidationPrompt([]*types.AIRecommendation{
+		{Action: "restart", Target: "deployment/web", Risk: "Low"},
+		{Action: "scale", Target: "deployment/api", Risk: "Medium"},
+	})
+	assert.True(t, strings.HasPrefix(prompt, "Validate the safety"))
+	assert.Contains(t, prompt, "1. ACTION: restart\n   TARGET: deployment/web")
+	assert.Contains(t, prompt, "2. ACTION: scale")
+}
+
+func TestRiskLevel(t *testing.T) {
+	tests := []struct {
+		risk     string
+		expected int
+	}{
+		{"Low", 0},
+		{"Minimal, may increase resource costs", 0},
+		{"Medium - brief disruption", 1},
+		{"HIGH", 2},
+		{"critical", 3},
+		{"", 2},
+		{"unclear", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.risk, func(t *testing.T) {
+			assert.Equal(t, tt.expected, riskLevel(tt.risk))
+		})
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	safe, found := parseVerdict("UNSAFE: may delete data")
+	assert.True(t, found)
+	assert.False(t, safe, "UNSAFE must not be read as SAFE")
+
+	safe, found = parseVerdict("Safe to run")
+	assert.True(t, found)
+	assert.True(t, safe)
+
+	_, found = parseVerdict("I am not sure")
+	assert.False(t, found)
+}
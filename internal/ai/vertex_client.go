@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// VertexClient implements the AIClient interface for Gemini models on
+// Vertex AI. Requests carry OAuth tokens of the GKE workload identity of
+// the manager, so no API key is stored.
+type VertexClient struct {
+	model      string
+	endpoint   string
+	maxTokens  int
+	tokens     *credentialCache[string]
+	httpClient *http.Client
+}
+
+// vertexPart is a text part of Vertex content
+type vertexPart struct {
+	Text string `json:"text"`
+}
+
+// vertexContent is a turn of a Vertex conversation
+type vertexContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []vertexPart `json:"parts"`
+}
+
+// vertexRequest is a generateContent request
+type vertexRequest struct {
+	SystemInstruction *vertexContent  `json:"systemInstruction,omitempty"`
+	Contents          []vertexContent `json:"contents"`
+	GenerationConfig  struct {
+		Temperature     float32 `json:"temperature"`
+		MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	} `json:"generationConfig"`
+}
+
+// vertexResponse is a generateContent response
+type vertexResponse struct {
+	Candidates []struct {
+		Content      vertexContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		TotalTokenCount int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+	Error struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// NewVertexClient creates a new Vertex AI client for a publisher model
+func NewVertexClient(model, project, location string, maxTokens int, timeout time.Duration) (*VertexClient, error) {
+	if model == "" || project == "" || location == "" {
+		return nil, fmt.Errorf("a Vertex AI model, project and location are required")
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	return &VertexClient{
+		model: model,
+		endpoint: fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:generateContent",
+			location, project, location, model),
+		maxTokens:  maxTokens,
+		tokens:     newGCPTokenSource(httpClient),
+		httpClient: httpClient,
+	}, nil
+}
+
+// Query sends a prompt to Vertex AI and returns the response
+func (v *VertexClient) Query(ctx context.Context, prompt string, temperature float32) (string, error) {
+	log := log.FromContext(ctx)
+	log.V(1).Info("Querying Vertex AI", "model", v.model, "prompt_length", len(prompt))
+
+	request := vertexRequest{
+		SystemInstruction: &vertexContent{Parts: []vertexPart{{Text: assistantSystemPrompt}}},
+		Contents:          []vertexContent{{Role: "user", Parts: []vertexPart{{Text: prompt}}}},
+	}
+	request.GenerationConfig.Temperature = temperature
+	request.GenerationConfig.MaxOutputTokens = v.maxTokens
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	token, err := v.tokens.get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google Cloud token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var vertexResp vertexResponse
+	if err := json.Unmarshal(body, &vertexResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("Vertex AI returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Vertex AI API error: %s (status: %s)", vertexResp.Error.Message, vertexResp.Error.Status)
+	}
+	if len(vertexResp.Candidates) == 0 {
+		return "", fmt.Errorf("no response candidates returned")
+	}
+
+	candidate := vertexResp.Candidates[0]
+	var response strings.Builder
+	for _, part := range candidate.Content.Parts {
+		response.WriteString(part.Text)
+	}
+
+	log.V(1).Info("Vertex AI query completed",
+		"response_length", response.Len(),
+		"total_tokens", vertexResp.UsageMetadata.TotalTokenCount,
+		"finish_reason", candidate.FinishReason)
+
+	return response.String(), nil
+}
+
+// GetModel returns the model identifier
+func (v *VertexClient) GetModel() string {
+	return fmt.Sprintf("vertex/%s", v.model)
+}
+
+// IsAvailable checks that a workload identity token can be obtained
+func (v *VertexClient) IsAvailable(ctx context.Context) bool {
+	_, err := v.tokens.get(ctx)
+	return err == nil
+}
@@ -0,0 +1,211 @@
+// Package alerting generates Prometheus alerting rules from the thresholds
+// of HealingPolicy triggers, so conditions that healing fails to resolve in
+// time page a human.
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+)
+
+// PrometheusRuleGVK is the prometheus-operator PrometheusRule kind
+var PrometheusRuleGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// Labels set on generated alerts and rules
+const (
+	LabelPolicy    = "kubeskippy_policy"
+	LabelNamespace = "kubeskippy_namespace"
+	LabelTrigger   = "kubeskippy_trigger"
+)
+
+// minAlertFor is the shortest time a condition must hold before paging, so
+// healing gets a chance to resolve it first
+const minAlertFor = 5 * time.Minute
+
+// Options customises generated rules
+type Options struct {
+	// Severity is the severity label of the alerts
+	Severity string
+
+	// Labels are added to the PrometheusRule, e.g. to match the
+	// Prometheus ruleSelector
+	Labels map[string]string
+}
+
+// RuleName returns the name of the PrometheusRule generated for a policy
+func RuleName(policy *v1alpha1.HealingPolicy) string {
+	return "kubeskippy-" + policy.Name
+}
+
+// PrometheusTriggers returns the metric triggers of a policy that query
+// Prometheus. Templated queries are evaluated per target and have no
+// single alert expression, so they are left out.
+func PrometheusTriggers(policy *v1alpha1.HealingPolicy) []v1alpha1.HealingTrigger {
+	var out []v1alpha1.HealingTrigger
+	for _, trigger := range policy.Spec.Triggers {
+		if trigger.Type == "metric" && trigger.MetricTrigger != nil &&
+			trigger.MetricTrigger.EffectiveDatasource() == v1alpha1.DatasourcePrometheus &&
+			!strings.Contains(trigger.MetricTrigger.Query, "{{") {
+			out = append(out, trigger)
+		}
+	}
+	return out
+}
+
+// GenerateRule builds a PrometheusRule with one alert per Prometheus metric
+// trigger of the policy. An alert fires when the trigger's threshold has
+// held for its duration plus its cooldown, i.e. healing had a chance to act
+// and the condition persists. It returns nil if the policy has no
+// Prometheus triggers.
+func GenerateRule(policy *v1alpha1.HealingPolicy, opts Options) (*unstructured.Unstructured, error) {
+	promTriggers := PrometheusTriggers(policy)
+	if len(promTriggers) == 0 {
+		return nil, nil
+	}
+
+	severity := opts.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+
+	rules := make([]interface{}, 0, len(promTriggers))
+	for _, trigger := range promTriggers {
+		mt := trigger.MetricTrigger
+		if !validOperator(mt.Operator) {
+			return nil, fmt.Errorf("trigger %s: unsupported operator %q", trigger.Name, mt.Operator)
+		}
+
+		rules = append(rules, map[string]interface{}{
+			"alert": alertName(policy.Name, trigger.Name),
+			"expr":  fmt.Sprintf("(%s) %s %s", mt.Query, mt.Operator, thresholdExpr(mt)),
+			"for":   formatDuration(alertFor(trigger)),
+			"labels": map[string]interface{}{
+				"severity":     severity,
+				LabelPolicy:    policy.Name,
+				LabelNamespace: policy.Namespace,
+				LabelTrigger:   trigger.Name,
+			},
+			"annotations": map[string]interface{}{
+				"summary": fmt.Sprintf("HealingPolicy %s/%s trigger %s is still firing", policy.Namespace, policy.Name, trigger.Name),
+				"description": fmt.Sprintf("%s %s %s has held for %s. KubeSkippy may be rate-limited, paused or failing to heal it.",
+					mt.Query, mt.Operator, thresholdExpr(mt), formatDuration(alertFor(trigger))),
+			},
+		})
+	}
+
+	labels := map[string]interface{}{
+		"app.kubernetes.io/managed-by": "kubeskippy",
+		LabelPolicy:                    policy.Name,
+	}
+	keys := make([]string, 0, len(opts.Labels))
+	for key := range opts.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		labels[key] = opts.Labels[key]
+	}
+
+	rule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      RuleName(policy),
+			"namespace": policy.Namespace,
+			"labels":    labels,
+		},
+		"spec": map[string]interface{}{
+			"groups": []interface{}{
+				map[string]interface{}{
+					"name":  fmt.Sprintf("kubeskippy.%s.%s", policy.Namespace, policy.Name),
+					"rules": rules,
+				},
+			},
+		},
+	}}
+	rule.SetGroupVersionKind(PrometheusRuleGVK)
+	return rule, nil
+}
+
+// alertFor is how long the condition must hold before the alert fires
+func alertFor(trigger v1alpha1.HealingTrigger) time.Duration {
+	d := trigger.MetricTrigger.Duration.Duration + trigger.CooldownPeriod.Duration
+	if d < minAlertFor {
+		d = minAlertFor
+	}
+	return d
+}
+
+// alertName builds a CamelCase alert name from the policy and trigger names
+func alertName(policy, trigger string) string {
+	var b strings.Builder
+	b.WriteString("KubeSkippy")
+	for _, part := range strings.FieldsFunc(policy+"-"+trigger, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// formatDuration formats a duration in Prometheus notation, e.g. 1h30m
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	var b strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}} {
+		if n := d / unit.size; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+			d -= n * unit.size
+		}
+	}
+	if b.Len() == 0 {
+		return "0s"
+	}
+	return b.String()
+}
+
+// thresholdExpr returns the threshold of a trigger as PromQL. A baseline
+// becomes the average of the query over the hour before the same time of
+// the previous days, which is exact while every day has samples.
+func thresholdExpr(mt *v1alpha1.MetricTrigger) string {
+	if mt.Baseline == nil {
+		return formatThreshold(mt.Threshold)
+	}
+	days := int(mt.Baseline.Days)
+	if days <= 0 {
+		days = v1alpha1.DefaultBaselineDays
+	}
+	terms := make([]string, days)
+	for day := 1; day <= days; day++ {
+		terms[day-1] = fmt.Sprintf("avg_over_time((%s)[1h:] offset %dd)", mt.Query, day)
+	}
+	return fmt.Sprintf("%s * clamp_min((%s) / %d, %s)", formatThreshold(mt.Threshold),
+		strings.Join(terms, " + "), days, formatThreshold(mt.Baseline.Floor))
+}
+
+func formatThreshold(threshold float64) string {
+	return fmt.Sprintf("%g", threshold)
+}
+
+func validOperator(operator string) bool {
+	for _, op := range triggers.Operators {
+		if op == operator {
+			return true
+		}
+	}
+	return false
+}
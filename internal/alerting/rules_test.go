@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func testPolicy() *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-latency", Namespace: "shop"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{
+				{
+					Name: "p99-latency",
+					Type: "metric",
+					MetricTrigger: &v1alpha1.MetricTrigger{
+						Query:     `histogram_quantile(0.99, rate(http_request_duration_seconds_bucket{app="web"}[5m]))`,
+						Threshold: 0.5,
+						Operator:  ">",
+						Duration:  metav1.Duration{Duration: 2 * time.Minute},
+					},
+					CooldownPeriod: metav1.Duration{Duration: 10 * time.Minute},
+				},
+				{
+					Name:          "restarts",
+					Type:          "metric",
+					MetricTrigger: &v1alpha1.MetricTrigger{Query: "restart_count", Threshold: 3, Operator: ">"},
+				},
+				{
+					Name:         "backoff",
+					Type:         "event",
+					EventTrigger: &v1alpha1.EventTrigger{Reason: "BackOff"},
+				},
+				{
+					Name:          "error-ratio",
+					Type:          "metric",
+					MetricTrigger: &v1alpha1.MetricTrigger{Query: `sum(rate(errors_total[1m]))`, Threshold: 10, Operator: ">="},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateRule(t *testing.T) {
+	rule, err := GenerateRule(testPolicy(), Options{Severity: "critical", Labels: map[string]string{"release": "prometheus"}})
+	require.NoError(t, err)
+	require.NotNil(t, rule)
+
+	assert.Equal(t, PrometheusRuleGVK, rule.GroupVersionKind())
+	assert.Equal(t, "kubeskippy-web-latency", rule.GetName())
+	assert.Equal(t, "shop", rule.GetNamespace())
+	assert.Equal(t, "prometheus", rule.GetLabels()["release"])
+	assert.Equal(t, "web-latency", rule.GetLabels()[LabelPolicy])
+
+	groups, _, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+	require.Len(t, rules, 2, "only Prometheus metric triggers become alerts")
+
+	latency := rules[0].(map[string]interface{})
+	assert.Equal(t, "KubeSkippyWebLatencyP99Latency", latency["alert"])
+	assert.Equal(t, `(histogram_quantile(0.99, rate(http_request_duration_seconds_bucket{app="web"}[5m]))) > 0.5`, latency["expr"])
+	assert.Equal(t, "12m", latency["for"])
+	assert.Equal(t, map[string]interface{}{
+		"severity":     "critical",
+		LabelPolicy:    "web-latency",
+		LabelNamespace: "shop",
+		LabelTrigger:   "p99-latency",
+	}, latency["labels"])
+
+	errorRatio := rules[1].(map[string]interface{})
+	assert.Equal(t, "(sum(rate(errors_total[1m]))) >= 10", errorRatio["expr"])
+	assert.Equal(t, "5m", errorRatio["for"], "short triggers still give healing time to act")
+}
+
+func TestGenerateRule_Baseline(t *testing.T) {
+	policy := testPolicy()
+	policy.Spec.Triggers[0].MetricTrigger = &v1alpha1.MetricTrigger{
+		Query: "sum(rate(errors_total[5m]))", Operator: ">", Threshold: 2,
+		Baseline: &v1alpha1.MetricBaseline{Days: 2, Floor: 0.1},
+	}
+
+	rule, err := GenerateRule(policy, Options{})
+	require.NoError(t, err)
+	groups, _, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	require.NoError(t, err)
+	errors := groups[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "(sum(rate(errors_total[5m]))) > 2 * clamp_min(("+
+		"avg_over_time((sum(rate(errors_total[5m])))[1h:] offset 1d) + "+
+		"avg_over_time((sum(rate(errors_total[5m])))[1h:] offset 2d)) / 2, 0.1)", errors["expr"])
+}
+
+func TestGenerateRule_NoPrometheusTriggers(t *testing.T) {
+	policy := testPolicy()
+	policy.Spec.Triggers = policy.Spec.Triggers[1:2]
+
+	rule, err := GenerateRule(policy, Options{})
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestGenerateRule_InvalidOperator(t *testing.T) {
+	policy := testPolicy()
+	policy.Spec.Triggers[0].MetricTrigger.Operator = "=>"
+
+	_, err := GenerateRule(policy, Options{})
+	assert.ErrorContains(t, err, "unsupported operator")
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "5m", formatDuration(5*time.Minute))
+	assert.Equal(t, "1h30m", formatDuration(90*time.Minute))
+	assert.Equal(t, "2m15s", formatDuration(135*time.Second))
+	assert.Equal(t, "0s", formatDuration(0))
+}
+
+func TestPrometheusTriggers_SkipsTemplatedQueries(t *testing.T) {
+	policy := testPolicy()
+	policy.Spec.Triggers[0].MetricTrigger.Query = `rate(http_requests_total{pod="{{.PodName}}"}[5m])`
+
+	found := PrometheusTriggers(policy)
+	require.Len(t, found, 1)
+	assert.Equal(t, "error-ratio", found[0].Name)
+}
@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkpointKey holds the end of the last exported window in the
+// checkpoint ConfigMap
+const checkpointKey = "lastExport"
+
+// Checkpoint persists the end of the last exported window, so a restarted
+// or newly elected exporter continues where the previous one stopped
+type Checkpoint interface {
+	// Load returns the end of the last exported window, or false if no
+	// export was recorded
+	Load(ctx context.Context) (time.Time, bool, error)
+
+	// Save records the end of the last exported window
+	Save(ctx context.Context, last time.Time) error
+}
+
+// ConfigMapCheckpoint keeps the checkpoint in a ConfigMap
+type ConfigMapCheckpoint struct {
+	client client.Client
+	name   types.NamespacedName
+}
+
+// NewConfigMapCheckpoint creates a checkpoint stored in the named ConfigMap
+func NewConfigMapCheckpoint(c client.Client, namespace, name string) *ConfigMapCheckpoint {
+	return &ConfigMapCheckpoint{
+		client: c,
+		name:   types.NamespacedName{Namespace: namespace, Name: name},
+	}
+}
+
+// Load reads the checkpoint
+func (c *ConfigMapCheckpoint) Load(ctx context.Context) (time.Time, bool, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.name, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("failed to get archive checkpoint %s: %w", c.name, err)
+	}
+	value, ok := cm.Data[checkpointKey]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	last, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid archive checkpoint %q in %s: %w", value, c.name, err)
+	}
+	return last, true, nil
+}
+
+// Save writes the checkpoint, creating the ConfigMap if needed
+func (c *ConfigMapCheckpoint) Save(ctx context.Context, last time.Time) error {
+	value := last.UTC().Format(time.RFC3339Nano)
+
+	cm := &corev1.ConfigMap{}
+	err := c.client.Get(ctx, c.name, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.name.Name,
+				Namespace: c.name.Namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "kubeskippy"},
+			},
+			Data: map[string]string{checkpointKey: value},
+		}
+		if err := c.client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create archive checkpoint %s: %w", c.name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get archive checkpoint %s: %w", c.name, err)
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[checkpointKey] = value
+	if err := c.client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update archive checkpoint %s: %w", c.name, err)
+	}
+	return nil
+}
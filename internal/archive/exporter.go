@@ -0,0 +1,212 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// unknownPolicy partitions records that do not belong to a policy
+const unknownPolicy = "none"
+
+// Object formats
+const (
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// Exporter periodically writes the records completed since its previous
+// export to an object store. Each export writes one object per kind, date
+// and policy:
+//
+//	<prefix>/<kind>/date=2006-01-02/policy=<namespace>_<name>/<window start>.<format>
+type Exporter struct {
+	store    ObjectStore
+	sources  []Source
+	prefix   string
+	format   string
+	interval time.Duration
+
+	// checkpoint persists last across restarts and leader changes
+	checkpoint Checkpoint
+
+	// last is the end of the previous export window
+	last time.Time
+
+	now func() time.Time
+}
+
+// NewExporter creates a new exporter. The first export continues from the
+// checkpoint if one is set and recorded, and otherwise covers the interval
+// before the exporter started.
+func NewExporter(store ObjectStore, cfg config.ArchiveConfig, sources ...Source) *Exporter {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	format := cfg.Format
+	if format == "" {
+		format = FormatJSONL
+	}
+	return &Exporter{
+		store:    store,
+		sources:  sources,
+		prefix:   strings.Trim(cfg.Prefix, "/"),
+		format:   format,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// WithCheckpoint persists the end of each exported window
+func (e *Exporter) WithCheckpoint(checkpoint Checkpoint) *Exporter {
+	e.checkpoint = checkpoint
+	return e
+}
+
+// NeedLeaderElection is true so only the leader exports records
+func (e *Exporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start exports records every interval until the context is done, with a
+// final export on shutdown
+func (e *Exporter) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("archive")
+	e.last = e.now().Add(-e.interval)
+	if e.checkpoint != nil {
+		last, ok, err := e.checkpoint.Load(ctx)
+		if err != nil {
+			// Exporting the last interval again is better than not at all
+			log.Error(err, "Failed to load export checkpoint, starting from the last interval")
+		} else if ok {
+			e.last = last
+			log.Info("Continuing export from checkpoint", "since", last)
+		}
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Use a fresh context so the final window is not lost
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			e.exportAndLog(shutdownCtx, log)
+			cancel()
+			return nil
+		case <-ticker.C:
+			e.exportAndLog(ctx, log)
+		}
+	}
+}
+
+func (e *Exporter) exportAndLog(ctx context.Context, log logr.Logger) {
+	written, err := e.Export(ctx)
+	if err != nil {
+		log.Error(err, "Failed to export action history, will retry the window")
+		return
+	}
+	if written > 0 {
+		log.Info("Exported action history", "objects", written)
+	}
+}
+
+// Export writes the records completed since the previous export and
+// returns the number of objects written. The window only advances when
+// every object was written, so a failed export is retried with the next.
+func (e *Exporter) Export(ctx context.Context) (int, error) {
+	since, until := e.last, e.now()
+
+	var records []Record
+	for _, source := range e.sources {
+		collected, err := source.Collect(ctx, since, until)
+		if err != nil {
+			return 0, err
+		}
+		records = append(records, collected...)
+	}
+
+	partitions := make(map[string][]Record)
+	for _, record := range records {
+		key := e.objectKey(record, since)
+		partitions[key] = append(partitions[key], record)
+	}
+
+	keys := make([]string, 0, len(partitions))
+	for key := range partitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		data, err := e.encode(partitions[key])
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		if err := e.store.Put(ctx, key, data); err != nil {
+			return 0, err
+		}
+	}
+
+	// A lost checkpoint only makes the next exporter write the window again
+	if e.checkpoint != nil {
+		if err := e.checkpoint.Save(ctx, until); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to save export checkpoint")
+		}
+	}
+	e.last = until
+	return len(keys), nil
+}
+
+// encode encodes records in the configured format
+func (e *Exporter) encode(records []Record) ([]byte, error) {
+	if e.format == FormatParquet {
+		return encodeParquet(records)
+	}
+	return encodeJSONL(records)
+}
+
+// objectKey partitions a record by kind, date and policy. Objects are named
+// after the window start so retried windows overwrite their earlier objects.
+func (e *Exporter) objectKey(record Record, since time.Time) string {
+	policy := record.Policy
+	if policy == "" {
+		policy = unknownPolicy
+	}
+	key := fmt.Sprintf("%s/date=%s/policy=%s/%s.%s",
+		record.Kind,
+		record.Time.UTC().Format("2006-01-02"),
+		strings.ReplaceAll(policy, "/", "_"),
+		since.UTC().Format("20060102T150405Z"),
+		e.format)
+	if e.prefix != "" {
+		key = e.prefix + "/" + key
+	}
+	return key
+}
+
+// encodeJSONL encodes records as newline-delimited JSON, oldest first
+func encodeJSONL(records []Record) ([]byte, error) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,242 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/safety"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// memoryStore keeps written objects, failing while err is set
+type memoryStore struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (s *memoryStore) Put(_ context.Context, key string, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func decodeRecords(t *testing.T, data []byte) []map[string]interface{} {
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestExporter_Export(t *testing.T) {
+	start := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	completedAction := func(name string, completed time.Time) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       v1alpha1.HealingActionSpec{PolicyRef: v1alpha1.PolicyReference{Name: "web", Namespace: "apps"}},
+			Status:     v1alpha1.HealingActionStatus{CompletionTime: &metav1.Time{Time: completed}},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		completedAction("before", start.Add(-time.Minute)),
+		completedAction("first", start.Add(10*time.Minute)),
+		completedAction("second", start.Add(40*time.Minute)),
+		&v1alpha1.HealingAction{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "apps"}},
+	).Build()
+
+	actionStore := safety.NewInMemoryActionStore()
+	require.NoError(t, actionStore.RecordAction(context.Background(), safety.ActionRecord{
+		PolicyKey: "apps/web", ActionType: "restart", Timestamp: start.Add(20 * time.Minute),
+	}))
+
+	store := &memoryStore{objects: map[string][]byte{}}
+	exporter := NewExporter(store, config.ArchiveConfig{Prefix: "/history/", Interval: time.Hour},
+		&ActionSource{Client: c}, &AuditSource{Store: actionStore})
+	exporter.last = start
+	exporter.now = func() time.Time { return start.Add(time.Hour) }
+
+	written, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, written)
+
+	// Records are partitioned by the date they completed
+	actions := decodeRecords(t, store.objects["history/actions/date=2024-05-01/policy=apps_web/20240501T233000Z.jsonl"])
+	require.Len(t, actions, 1)
+	assert.Equal(t, "first", actions[0]["data"].(map[string]interface{})["metadata"].(map[string]interface{})["name"])
+
+	actions = decodeRecords(t, store.objects["history/actions/date=2024-05-02/policy=apps_web/20240501T233000Z.jsonl"])
+	require.Len(t, actions, 1)
+	assert.Equal(t, "second", actions[0]["data"].(map[string]interface{})["metadata"].(map[string]interface{})["name"])
+
+	audit := decodeRecords(t, store.objects["history/audit/date=2024-05-01/policy=apps_web/20240501T233000Z.jsonl"])
+	require.Len(t, audit, 1)
+	assert.Equal(t, "restart", audit[0]["data"].(map[string]interface{})["ActionType"])
+
+	// The next window starts where this one ended
+	written, err = exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, written)
+}
+
+func TestExporter_RetriesFailedWindow(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	actionStore := safety.NewInMemoryActionStore()
+	require.NoError(t, actionStore.RecordAction(context.Background(), safety.ActionRecord{
+		PolicyKey: "apps/web", Timestamp: start.Add(time.Minute),
+	}))
+
+	store := &memoryStore{objects: map[string][]byte{}, err: errors.New("bucket unavailable")}
+	exporter := NewExporter(store, config.ArchiveConfig{}, &AuditSource{Store: actionStore})
+	exporter.last = start
+	now := start.Add(time.Hour)
+	exporter.now = func() time.Time { return now }
+
+	_, err := exporter.Export(context.Background())
+	assert.ErrorContains(t, err, "bucket unavailable")
+	assert.Equal(t, start, exporter.last)
+
+	store.err = nil
+	now = start.Add(2 * time.Hour)
+	written, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+	assert.Contains(t, store.objects, "audit/date=2024-05-01/policy=apps_web/20240501T120000Z.jsonl")
+}
+
+func TestExporter_Checkpoint(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	checkpoint := NewConfigMapCheckpoint(fake.NewClientBuilder().WithScheme(scheme).Build(), "kubeskippy-system", "archive-checkpoint")
+
+	_, ok, err := checkpoint.Load(context.Background())
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	store := &memoryStore{objects: map[string][]byte{}}
+	exporter := NewExporter(store, config.ArchiveConfig{}).WithCheckpoint(checkpoint)
+	exporter.last = start
+	exporter.now = func() time.Time { return start.Add(time.Hour) }
+	_, err = exporter.Export(context.Background())
+	require.NoError(t, err)
+
+	// A new exporter continues from the saved window end
+	last, ok, err := checkpoint.Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, start.Add(time.Hour).Equal(last))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	next := NewExporter(store, config.ArchiveConfig{}).WithCheckpoint(checkpoint)
+	next.now = func() time.Time { return start.Add(3 * time.Hour) }
+	cancel()
+	require.NoError(t, next.Start(ctx))
+	last, _, err = checkpoint.Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, start.Add(3*time.Hour).Equal(last))
+}
+
+func TestExporter_Parquet(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	actionStore := safety.NewInMemoryActionStore()
+	require.NoError(t, actionStore.RecordAction(context.Background(), safety.ActionRecord{
+		PolicyKey: "apps/web", ActionType: "restart", Timestamp: start.Add(time.Minute),
+	}))
+
+	store := &memoryStore{objects: map[string][]byte{}}
+	exporter := NewExporter(store, config.ArchiveConfig{Format: FormatParquet}, &AuditSource{Store: actionStore})
+	exporter.last = start
+	exporter.now = func() time.Time { return start.Add(time.Hour) }
+	written, err := exporter.Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+
+	data := store.objects["audit/date=2024-05-01/policy=apps_web/20240501T120000Z.parquet"]
+	require.NotEmpty(t, data)
+	rows, err := parquet.Read[parquetRecord](bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, KindAudit, rows[0].Kind)
+	assert.Equal(t, "apps/web", rows[0].Policy)
+	assert.True(t, start.Add(time.Minute).Equal(rows[0].Time))
+	assert.Contains(t, rows[0].Data, `"ActionType":"restart"`)
+}
+
+func TestFileStore_Put(t *testing.T) {
+	root := t.TempDir()
+	store := NewFileStore(root)
+
+	require.NoError(t, store.Put(context.Background(), "audit/date=2024-05-01/a.jsonl", []byte("{}\n")))
+	data, err := os.ReadFile(filepath.Join(root, "audit", "date=2024-05-01", "a.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}\n", string(data))
+}
+
+func TestHTTPStore_Put(t *testing.T) {
+	var req *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = io.ReadAll(r.Body)
+		if r.URL.Query().Get("sig") != "abc" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	store := NewHTTPStore(server.URL+"/container/?sig=abc", "secret-token", time.Second)
+	require.NoError(t, store.Put(context.Background(), "audit/a.jsonl", []byte("{}\n")))
+	assert.Equal(t, http.MethodPut, req.Method)
+	assert.Equal(t, "/container/audit/a.jsonl", req.URL.Path)
+	assert.Equal(t, "Bearer secret-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "{}\n", string(body))
+
+	store = NewHTTPStore(server.URL, "", time.Second)
+	assert.ErrorContains(t, store.Put(context.Background(), "audit/a.jsonl", nil), "status 403")
+}
+
+func TestLoadObjectStore(t *testing.T) {
+	_, err := LoadObjectStore(context.Background(), nil, config.ArchiveConfig{Provider: ProviderFile})
+	assert.ErrorContains(t, err, "path is required")
+
+	_, err = LoadObjectStore(context.Background(), nil, config.ArchiveConfig{Provider: "azure"})
+	assert.ErrorContains(t, err, "unsupported archive provider")
+
+	_, err = LoadObjectStore(context.Background(), nil, config.ArchiveConfig{Provider: ProviderS3})
+	assert.ErrorContains(t, err, "bucket is required")
+
+	store, err := LoadObjectStore(context.Background(), nil, config.ArchiveConfig{Provider: ProviderS3, Bucket: "history", Region: "eu-west-1"})
+	require.NoError(t, err)
+	assert.IsType(t, &S3Store{}, store)
+
+	store, err = LoadObjectStore(context.Background(), nil, config.ArchiveConfig{Provider: ProviderHTTP, URL: "https://storage.example.com/bucket"})
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPStore{}, store)
+}
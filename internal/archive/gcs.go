@@ -0,0 +1,153 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	// gcsScope allows object uploads
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+	// gcsMetadataTokenURL serves access tokens of the workload identity
+	gcsMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCSStore uploads objects to a Google Cloud Storage bucket with the JSON
+// API, authenticating with OAuth2 access tokens that are refreshed before
+// they expire
+type GCSStore struct {
+	endpoint   string
+	bucket     string
+	tokens     oauth2.TokenSource
+	httpClient *http.Client
+}
+
+// NewGCSStore creates a new GCS store. An empty endpoint selects
+// storage.googleapis.com.
+func NewGCSStore(endpoint, bucket string, tokens oauth2.TokenSource, timeout time.Duration) *GCSStore {
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	return &GCSStore{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		bucket:     bucket,
+		tokens:     tokens,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Put uploads the object with a simple media upload
+func (s *GCSStore) Put(ctx context.Context, key string, data []byte) error {
+	token, err := s.tokens.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get GCS access token: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		s.endpoint, url.PathEscape(s.bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(key))
+	token.SetAuthHeader(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GCS returned status %d for %s: %s", resp.StatusCode, key, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// gcsKeyTokenSource returns refreshing tokens for a service account key
+// in the JSON format of the Cloud console
+func gcsKeyTokenSource(key []byte) (oauth2.TokenSource, error) {
+	var account struct {
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(key, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("service account key needs client_email and private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	config := &jwt.Config{
+		Email:        account.ClientEmail,
+		PrivateKey:   []byte(account.PrivateKey),
+		PrivateKeyID: account.PrivateKeyID,
+		Scopes:       []string{gcsScope},
+		TokenURL:     account.TokenURI,
+	}
+	return config.TokenSource(context.Background()), nil
+}
+
+// metadataTokenSource fetches access tokens of the workload identity from
+// the GKE metadata server
+type metadataTokenSource struct {
+	url        string
+	httpClient *http.Client
+}
+
+// gcsMetadataTokenSource returns refreshing tokens of the workload identity
+func gcsMetadataTokenSource(timeout time.Duration) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &metadataTokenSource{
+		url:        gcsMetadataTokenURL,
+		httpClient: &http.Client{Timeout: timeout},
+	})
+}
+
+// Token fetches a new access token
+func (m *metadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, m.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata server returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: body.AccessToken,
+		TokenType:   body.TokenType,
+		Expiry:      time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,59 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestGCSStore_Put(t *testing.T) {
+	var req *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	tokens := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "ya29.token", TokenType: "Bearer"})
+	store := NewGCSStore(server.URL, "history", tokens, time.Second)
+	require.NoError(t, store.Put(context.Background(), "audit/date=2024-05-01/a.jsonl", []byte("{}\n")))
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/upload/storage/v1/b/history/o", req.URL.Path)
+	assert.Equal(t, "media", req.URL.Query().Get("uploadType"))
+	assert.Equal(t, "audit/date=2024-05-01/a.jsonl", req.URL.Query().Get("name"))
+	assert.Equal(t, "Bearer ya29.token", req.Header.Get("Authorization"))
+	assert.Equal(t, "{}\n", string(body))
+}
+
+func TestMetadataTokenSource(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		_, _ = io.WriteString(w, `{"access_token":"ya29.workload","expires_in":3599,"token_type":"Bearer"}`)
+	}))
+	defer server.Close()
+
+	tokens := oauth2.ReuseTokenSource(nil, &metadataTokenSource{url: server.URL, httpClient: server.Client()})
+	token, err := tokens.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "ya29.workload", token.AccessToken)
+
+	// Tokens are reused until they expire
+	_, err = tokens.Token()
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGCSKeyTokenSource(t *testing.T) {
+	_, err := gcsKeyTokenSource([]byte(`{"client_email":"archive@project.iam.gserviceaccount.com"}`))
+	assert.ErrorContains(t, err, "needs client_email and private_key")
+}
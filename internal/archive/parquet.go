@@ -0,0 +1,47 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRecord is the Parquet row of a record. The entry is kept as a
+// JSON string since its schema differs between kinds.
+type parquetRecord struct {
+	Kind   string    `parquet:"kind,dict"`
+	Time   time.Time `parquet:"time,timestamp(millisecond)"`
+	Policy string    `parquet:"policy,dict,optional"`
+	Data   string    `parquet:"data,json"`
+}
+
+// encodeParquet encodes records as a Snappy compressed Parquet file,
+// oldest first
+func encodeParquet(records []Record) ([]byte, error) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	rows := make([]parquetRecord, len(records))
+	for i, record := range records {
+		data, err := json.Marshal(record.Data)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = parquetRecord{
+			Kind:   record.Kind,
+			Time:   record.Time.UTC(),
+			Policy: record.Policy,
+			Data:   string(data),
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows, parquet.Compression(&parquet.Snappy)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,323 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// awsCredentials sign S3 requests
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expires is when temporary credentials stop working, zero if never
+	Expires time.Time
+}
+
+// awsCredentialsProvider returns current credentials, refreshing them as
+// needed
+type awsCredentialsProvider interface {
+	Retrieve(ctx context.Context) (awsCredentials, error)
+}
+
+// secretCredentials reads static credentials from a Secret on every
+// request, so rotated keys are picked up without a restart
+type secretCredentials struct {
+	reader client.Reader
+	name   types.NamespacedName
+}
+
+// Retrieve reads the accessKeyID, secretAccessKey and optional sessionToken
+// keys of the Secret
+func (s *secretCredentials) Retrieve(ctx context.Context) (awsCredentials, error) {
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, s.name, secret); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to get archive credentials secret %s: %w", s.name, err)
+	}
+	creds := awsCredentials{
+		AccessKeyID:     strings.TrimSpace(string(secret.Data["accessKeyID"])),
+		SecretAccessKey: strings.TrimSpace(string(secret.Data["secretAccessKey"])),
+		SessionToken:    strings.TrimSpace(string(secret.Data["sessionToken"])),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("archive credentials secret %s needs accessKeyID and secretAccessKey", s.name)
+	}
+	return creds, nil
+}
+
+// webIdentityCredentials exchange the projected service account token for
+// temporary credentials of an IAM role (IRSA), refreshing them before they
+// expire
+type webIdentityCredentials struct {
+	roleARN    string
+	tokenFile  string
+	stsURL     string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	cached awsCredentials
+	now    func() time.Time
+}
+
+// newWebIdentityCredentials reads the role and token file set by the EKS
+// pod identity webhook
+func newWebIdentityCredentials(region string, timeout time.Duration) *webIdentityCredentials {
+	stsURL := "https://sts.amazonaws.com"
+	if region != "" {
+		stsURL = fmt.Sprintf("https://sts.%s.amazonaws.com", region)
+	}
+	return &webIdentityCredentials{
+		roleARN:    os.Getenv("AWS_ROLE_ARN"),
+		tokenFile:  os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"),
+		stsURL:     stsURL,
+		httpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}
+}
+
+// Retrieve returns the cached credentials, assuming the role again when
+// they expire within five minutes
+func (w *webIdentityCredentials) Retrieve(ctx context.Context) (awsCredentials, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cached.AccessKeyID != "" && w.now().Add(5*time.Minute).Before(w.cached.Expires) {
+		return w.cached, nil
+	}
+	if w.roleARN == "" || w.tokenFile == "" {
+		return awsCredentials{}, fmt.Errorf("no archive credentials secret and no web identity (AWS_ROLE_ARN, AWS_WEB_IDENTITY_TOKEN_FILE) configured")
+	}
+
+	// The token is rotated by the kubelet, so it is read on every refresh
+	token, err := os.ReadFile(w.tokenFile)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {w.roleARN},
+		"RoleSessionName":  {"kubeskippy-archive"},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.stsURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to create STS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to assume role %s: %w", w.roleARN, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode != http.StatusOK {
+		return awsCredentials{}, fmt.Errorf("STS returned status %d assuming role %s: %s", resp.StatusCode, w.roleARN, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to parse STS response: %w", err)
+	}
+	w.cached = awsCredentials{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+		Expires:         result.Credentials.Expiration,
+	}
+	return w.cached, nil
+}
+
+// S3Store uploads objects to an S3 bucket, or a bucket of an S3 compatible
+// store such as MinIO, with requests signed with AWS Signature Version 4
+type S3Store struct {
+	endpoint    string
+	bucket      string
+	region      string
+	pathStyle   bool
+	credentials awsCredentialsProvider
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// NewS3Store creates a new S3 store. An empty endpoint selects AWS S3 in
+// the region; path-style addressing is used with custom endpoints.
+func NewS3Store(endpoint, bucket, region string, credentials awsCredentialsProvider, timeout time.Duration) *S3Store {
+	if region == "" {
+		region = "us-east-1"
+	}
+	pathStyle := endpoint != ""
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	return &S3Store{
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		bucket:      bucket,
+		region:      region,
+		pathStyle:   pathStyle,
+		credentials: credentials,
+		httpClient:  &http.Client{Timeout: timeout},
+		now:         time.Now,
+	}
+}
+
+// Put uploads the object with PutObject
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := url.Parse(s.endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid archive endpoint %s: %w", s.endpoint, err)
+	}
+	if s.pathStyle {
+		endpoint.Path += "/" + s.bucket + "/" + key
+	} else {
+		endpoint.Host = s.bucket + "." + endpoint.Host
+		endpoint.Path += "/" + key
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(key))
+	payloadHash := sha256.Sum256(data)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	signV4(req, creds, s.region, "s3", s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("S3 returned status %d for %s: %s", resp.StatusCode, key, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// signV4 signs the request with AWS Signature Version 4, signing the host
+// and every x-amz-* header. The payload hash is taken from the
+// X-Amz-Content-Sha256 header, or is the hash of an empty payload.
+func signV4(req *http.Request, creds awsCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		empty := sha256.Sum256(nil)
+		payloadHash = hex.EncodeToString(empty[:])
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query sorted by key as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes everything but unreserved characters
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,106 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSignV4(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestS3Store_Put(t *testing.T) {
+	var req *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "archive", Namespace: "kubeskippy-system"},
+		Data:       map[string][]byte{"accessKeyID": []byte("AKID"), "secretAccessKey": []byte("secret"), "sessionToken": []byte("session")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	credentials := &secretCredentials{reader: c, name: types.NamespacedName{Name: "archive", Namespace: "kubeskippy-system"}}
+
+	store := NewS3Store(server.URL, "history", "eu-west-1", credentials, time.Second)
+	require.NoError(t, store.Put(context.Background(), "audit/a.parquet", []byte("PAR1")))
+	assert.Equal(t, http.MethodPut, req.Method)
+	assert.Equal(t, "/history/audit/a.parquet", req.URL.Path)
+	assert.Equal(t, "application/vnd.apache.parquet", req.Header.Get("Content-Type"))
+	assert.Equal(t, "session", req.Header.Get("X-Amz-Security-Token"))
+	assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.Contains(t, req.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request")
+	assert.Equal(t, "PAR1", string(body))
+
+	// Rotated keys are used for the next upload
+	secret.Data["accessKeyID"] = []byte("ROTATED")
+	require.NoError(t, c.Update(context.Background(), secret))
+	require.NoError(t, store.Put(context.Background(), "audit/b.jsonl", nil))
+	assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=ROTATED/"))
+
+	// AWS endpoints use virtual-hosted style
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com", NewS3Store("", "history", "eu-west-1", credentials, time.Second).endpoint)
+}
+
+func TestWebIdentityCredentials(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		assert.Equal(t, "projected-token", r.Form.Get("WebIdentityToken"))
+		_, _ = io.WriteString(w, `<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>
+<AccessKeyId>ASIA</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>session</SessionToken>
+<Expiration>2024-05-01T13:00:00Z</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)
+	}))
+	defer server.Close()
+
+	tokenFile := t.TempDir() + "/token"
+	require.NoError(t, os.WriteFile(tokenFile, []byte("projected-token\n"), 0o600))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := &webIdentityCredentials{
+		roleARN: "arn:aws:iam::123456789012:role/archive", tokenFile: tokenFile, stsURL: server.URL,
+		httpClient: server.Client(), now: func() time.Time { return now },
+	}
+
+	creds, err := w.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA", creds.AccessKeyID)
+	assert.Equal(t, "session", creds.SessionToken)
+
+	// Cached until they are about to expire
+	_, err = w.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	now = now.Add(58 * time.Minute)
+	_, err = w.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
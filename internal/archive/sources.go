@@ -0,0 +1,122 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/safety"
+)
+
+// Record kinds
+const (
+	KindAction     = "actions"
+	KindAudit      = "audit"
+	KindAIDecision = "ai-decisions"
+)
+
+// Record is one exported entry
+type Record struct {
+	// Kind of the record
+	Kind string `json:"kind"`
+
+	// Time the record completed, which selects its date partition
+	Time time.Time `json:"time"`
+
+	// Policy the record belongs to as namespace/name, if known
+	Policy string `json:"policy,omitempty"`
+
+	// Data is the exported entry
+	Data interface{} `json:"data"`
+}
+
+// Source provides the records completed in a time window
+type Source interface {
+	// Collect returns the records completed after since and up to until
+	Collect(ctx context.Context, since, until time.Time) ([]Record, error)
+}
+
+// ActionSource exports completed HealingActions
+type ActionSource struct {
+	Client client.Reader
+}
+
+// Collect returns the actions that completed in the window
+func (s *ActionSource) Collect(ctx context.Context, since, until time.Time) ([]Record, error) {
+	actions := &v1alpha1.HealingActionList{}
+	if err := s.Client.List(ctx, actions); err != nil {
+		return nil, fmt.Errorf("failed to list healing actions: %w", err)
+	}
+
+	var records []Record
+	for i := range actions.Items {
+		action := &actions.Items[i]
+		completed := action.Status.CompletionTime
+		if completed == nil || !completed.After(since) || completed.After(until) {
+			continue
+		}
+		records = append(records, Record{
+			Kind:   KindAction,
+			Time:   completed.Time,
+			Policy: action.Spec.PolicyRef.Namespace + "/" + action.Spec.PolicyRef.Name,
+			Data:   action,
+		})
+	}
+	return records, nil
+}
+
+// AuditSource exports the action records of the safety controller
+type AuditSource struct {
+	Store safety.ActionStore
+}
+
+// Collect returns the action records recorded in the window
+func (s *AuditSource) Collect(ctx context.Context, since, until time.Time) ([]Record, error) {
+	actions, err := s.Store.GetActionsBetween(ctx, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action records: %w", err)
+	}
+
+	records := make([]Record, 0, len(actions))
+	for _, action := range actions {
+		records = append(records, Record{
+			Kind:   KindAudit,
+			Time:   action.Timestamp,
+			Policy: action.PolicyKey,
+			Data:   action,
+		})
+	}
+	return records, nil
+}
+
+// AIDecisionSource exports completed AI decisions
+type AIDecisionSource struct {
+	Client client.Reader
+}
+
+// Collect returns the AI decisions completed in the window
+func (s *AIDecisionSource) Collect(ctx context.Context, since, until time.Time) ([]Record, error) {
+	decisions := &v1alpha1.AIDecisionList{}
+	if err := s.Client.List(ctx, decisions); err != nil {
+		return nil, fmt.Errorf("failed to list AI decisions: %w", err)
+	}
+
+	var records []Record
+	for i := range decisions.Items {
+		decision := &decisions.Items[i]
+		completed := decision.Status.CompletionTime
+		if completed == nil || !completed.After(since) || completed.After(until) {
+			continue
+		}
+		records = append(records, Record{
+			Kind:   KindAIDecision,
+			Time:   completed.Time,
+			Policy: decision.Spec.PolicyRef.Namespace + "/" + decision.Spec.PolicyRef.Name,
+			Data:   decision,
+		})
+	}
+	return records, nil
+}
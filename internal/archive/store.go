@@ -0,0 +1,193 @@
+// Package archive exports action history to object storage for retention
+// beyond what the cluster keeps. Completed actions, audit records and AI
+// decisions are written periodically as JSONL or Parquet objects
+// partitioned by kind, date and policy.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Object store providers
+const (
+	ProviderFile = "file"
+	ProviderHTTP = "http"
+	ProviderS3   = "s3"
+	ProviderGCS  = "gcs"
+)
+
+// ObjectStore writes objects to a storage backend
+type ObjectStore interface {
+	// Put writes an object under key, replacing any existing object
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// FileStore writes objects below a directory, such as a bucket mounted
+// through a CSI driver
+type FileStore struct {
+	root string
+}
+
+// NewFileStore creates a new file store
+func NewFileStore(root string) *FileStore {
+	return &FileStore{root: root}
+}
+
+// Put writes the object to a temporary file and renames it into place
+func (s *FileStore) Put(ctx context.Context, key string, data []byte) error {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", key, err)
+	}
+	return nil
+}
+
+// HTTPStore uploads each object with a PUT below a base URL. The query of
+// the base URL, such as an Azure SAS token, is kept on every request.
+type HTTPStore struct {
+	base       string
+	query      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewHTTPStore creates a new HTTP store
+func NewHTTPStore(baseURL, token string, timeout time.Duration) *HTTPStore {
+	base, query, _ := strings.Cut(baseURL, "?")
+	return &HTTPStore{
+		base:       strings.TrimSuffix(base, "/"),
+		query:      query,
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Put uploads the object
+func (s *HTTPStore) Put(ctx context.Context, key string, data []byte) error {
+	endpoint := s.base + "/" + key
+	if s.query != "" {
+		endpoint += "?" + s.query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(key))
+	// Azure Blob Storage requires the blob type on uploads
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("object store returned status %d for %s: %s", resp.StatusCode, key, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// contentType returns the media type of an object by its extension
+func contentType(key string) string {
+	if strings.HasSuffix(key, "."+FormatParquet) {
+		return "application/vnd.apache.parquet"
+	}
+	return "application/x-ndjson"
+}
+
+// LoadObjectStore creates the configured object store, reading the upload
+// token or credentials from their Secret if one is configured
+func LoadObjectStore(ctx context.Context, reader client.Reader, cfg config.ArchiveConfig) (ObjectStore, error) {
+	switch cfg.Provider {
+	case ProviderFile:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("archive path is required for the file provider")
+		}
+		return NewFileStore(cfg.Path), nil
+
+	case ProviderHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("archive URL is required for the http provider")
+		}
+		var token string
+		if cfg.TokenSecretName != "" {
+			secret := &corev1.Secret{}
+			name := types.NamespacedName{Name: cfg.TokenSecretName, Namespace: cfg.TokenSecretNamespace}
+			if err := reader.Get(ctx, name, secret); err != nil {
+				return nil, fmt.Errorf("failed to get archive token secret %s: %w", name, err)
+			}
+			data, ok := secret.Data[cfg.TokenSecretKey]
+			if !ok {
+				return nil, fmt.Errorf("archive token secret %s has no key %q", name, cfg.TokenSecretKey)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+		return NewHTTPStore(cfg.URL, token, cfg.Timeout), nil
+
+	case ProviderS3:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("archive bucket is required for the s3 provider")
+		}
+		// Credentials are read per upload, so rotated keys and expiring
+		// role credentials are picked up
+		var credentials awsCredentialsProvider = newWebIdentityCredentials(cfg.Region, cfg.Timeout)
+		if cfg.CredentialsSecretName != "" {
+			credentials = &secretCredentials{
+				reader: reader,
+				name:   types.NamespacedName{Name: cfg.CredentialsSecretName, Namespace: cfg.TokenSecretNamespace},
+			}
+		}
+		return NewS3Store(cfg.URL, cfg.Bucket, cfg.Region, credentials, cfg.Timeout), nil
+
+	case ProviderGCS:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("archive bucket is required for the gcs provider")
+		}
+		tokens := gcsMetadataTokenSource(cfg.Timeout)
+		if cfg.CredentialsSecretName != "" {
+			secret := &corev1.Secret{}
+			name := types.NamespacedName{Name: cfg.CredentialsSecretName, Namespace: cfg.TokenSecretNamespace}
+			if err := reader.Get(ctx, name, secret); err != nil {
+				return nil, fmt.Errorf("failed to get archive credentials secret %s: %w", name, err)
+			}
+			key, ok := secret.Data["key.json"]
+			if !ok {
+				return nil, fmt.Errorf("archive credentials secret %s has no key %q", name, "key.json")
+			}
+			var err error
+			if tokens, err = gcsKeyTokenSource(key); err != nil {
+				return nil, err
+			}
+		}
+		return NewGCSStore(cfg.URL, cfg.Bucket, tokens, cfg.Timeout), nil
+	}
+	return nil, fmt.Errorf("unsupported archive provider: %q", cfg.Provider)
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// IndexActionPolicy indexes HealingActions by the name of their policy
+	IndexActionPolicy = "spec.policyRef.name"
+
+	// IndexActionTarget indexes HealingActions by their target resource as
+	// kind/namespace/name
+	IndexActionTarget = "spec.targetResource"
+)
+
+// ActionTargetKey is the IndexActionTarget value of a target resource
+func ActionTargetKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// indexActionTarget extracts the IndexActionTarget value of a HealingAction
+func indexActionTarget(obj client.Object) []string {
+	action := obj.(*v1alpha1.HealingAction)
+	ref := action.Spec.TargetResource
+	return []string{ActionTargetKey(ref.Kind, ref.Namespace, ref.Name)}
+}
+
+// indexActionPolicy extracts the IndexActionPolicy value of a HealingAction
+func indexActionPolicy(obj client.Object) []string {
+	action := obj.(*v1alpha1.HealingAction)
+	return []string{action.Spec.PolicyRef.Name}
+}
+
+// ListActionsForTarget returns every HealingAction on the target, from
+// policies in any namespace, oldest first. The reader must serve the
+// IndexActionTarget index, as the manager's cache does.
+func ListActionsForTarget(ctx context.Context, reader client.Reader, kind, namespace, name string) ([]v1alpha1.HealingAction, error) {
+	actionList := &v1alpha1.HealingActionList{}
+	if err := reader.List(ctx, actionList,
+		client.MatchingFields{IndexActionTarget: ActionTargetKey(kind, namespace, name)}); err != nil {
+		return nil, fmt.Errorf("failed to list healing actions for %s: %w", ActionTargetKey(kind, namespace, name), err)
+	}
+	SortActionsByCreation(actionList.Items)
+	return actionList.Items, nil
+}
+
+// FilterActionsForTarget returns the actions on the target, for readers
+// without the IndexActionTarget index such as a direct API client
+func FilterActionsForTarget(actions []v1alpha1.HealingAction, kind, namespace, name string) []v1alpha1.HealingAction {
+	key := ActionTargetKey(kind, namespace, name)
+	var matched []v1alpha1.HealingAction
+	for i := range actions {
+		if indexActionTarget(&actions[i])[0] == key {
+			matched = append(matched, actions[i])
+		}
+	}
+	SortActionsByCreation(matched)
+	return matched
+}
+
+// SortActionsByCreation orders actions oldest first
+func SortActionsByCreation(actions []v1alpha1.HealingAction) {
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].CreationTimestamp.Before(&actions[j].CreationTimestamp)
+	})
+}
+
+// pendingDuplicate returns an unfinished action of the policy that runs the
+// same action template on the target, if any
+func pendingDuplicate(actions []v1alpha1.HealingAction, policy *v1alpha1.HealingPolicy, actionName string) *v1alpha1.HealingAction {
+	for i := range actions {
+		action := &actions[i]
+		if !isPolicyAction(action, policy) || action.IsComplete() {
+			continue
+		}
+		if action.Spec.Action.Name == actionName {
+			return action
+		}
+	}
+	return nil
+}
+
+// targetCooldownRemaining returns how long the target stays in cooldown for
+// the policy after its last successful action on it. Unlike the safety
+// controller's in-memory cooldown, it is derived from the actions' history
+// and survives operator restarts.
+func targetCooldownRemaining(actions []v1alpha1.HealingAction, policy *v1alpha1.HealingPolicy, cooldown time.Duration, now time.Time) time.Duration {
+	if cooldown <= 0 {
+		return 0
+	}
+	var last time.Time
+	for i := range actions {
+		action := &actions[i]
+		if !isPolicyAction(action, policy) || action.Spec.DryRun ||
+			action.Status.Phase != v1alpha1.HealingActionPhaseSucceeded || action.Status.CompletionTime == nil {
+			continue
+		}
+		if action.Status.CompletionTime.After(last) {
+			last = action.Status.CompletionTime.Time
+		}
+	}
+	if last.IsZero() {
+		return 0
+	}
+	return last.Add(cooldown).Sub(now)
+}
+
+// isPolicyAction reports whether the action was created by the policy
+func isPolicyAction(action *v1alpha1.HealingAction, policy *v1alpha1.HealingPolicy) bool {
+	return action.Spec.PolicyRef.Name == policy.Name && action.Spec.PolicyRef.Namespace == policy.Namespace
+}
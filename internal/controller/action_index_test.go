@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func indexedAction(name, policy, targetKind, targetName string, created time.Time) *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: policy, Namespace: "default"},
+			TargetResource: v1alpha1.TargetResource{Kind: targetKind, Namespace: "apps", Name: targetName},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+		},
+	}
+}
+
+func TestListActionsForTarget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	now := time.Now()
+	objects := []client.Object{
+		indexedAction("web-2", "web-policy", "Deployment", "web", now),
+		indexedAction("web-1", "other-policy", "Deployment", "web", now.Add(-time.Hour)),
+		indexedAction("api-1", "web-policy", "Deployment", "api", now),
+		indexedAction("web-pod-1", "web-policy", "Pod", "web", now),
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget).
+		WithObjects(objects...).
+		Build()
+
+	actions, err := ListActionsForTarget(context.Background(), c, "Deployment", "apps", "web")
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	assert.Equal(t, "web-1", actions[0].Name)
+	assert.Equal(t, "web-2", actions[1].Name)
+
+	actions, err = ListActionsForTarget(context.Background(), c, "StatefulSet", "apps", "web")
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+
+	var items []v1alpha1.HealingAction
+	for _, obj := range objects {
+		items = append(items, *obj.(*v1alpha1.HealingAction))
+	}
+	filtered := FilterActionsForTarget(items, "Deployment", "apps", "web")
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "web-1", filtered[0].Name)
+}
+
+func TestPendingDuplicate(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"}}
+	now := time.Now()
+
+	pending := indexedAction("web-1", "web-policy", "Deployment", "web", now)
+	pending.Status.Phase = v1alpha1.HealingActionPhaseInProgress
+	finished := indexedAction("web-0", "web-policy", "Deployment", "web", now.Add(-time.Hour))
+	finished.Status.Phase = v1alpha1.HealingActionPhaseSucceeded
+	foreign := indexedAction("web-2", "other-policy", "Deployment", "web", now)
+	foreign.Spec.Action.Name = "scale"
+
+	tests := []struct {
+		name       string
+		actions    []v1alpha1.HealingAction
+		actionName string
+		expected   string
+	}{
+		{name: "pending action of the policy", actions: []v1alpha1.HealingAction{*finished, *pending}, actionName: "restart", expected: "web-1"},
+		{name: "finished actions are not duplicates", actions: []v1alpha1.HealingAction{*finished}, actionName: "restart"},
+		{name: "other action of the policy", actions: []v1alpha1.HealingAction{*pending}, actionName: "scale"},
+		{name: "other policies are ignored", actions: []v1alpha1.HealingAction{*foreign}, actionName: "scale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duplicate := pendingDuplicate(tt.actions, policy, tt.actionName)
+			if tt.expected == "" {
+				assert.Nil(t, duplicate)
+				return
+			}
+			require.NotNil(t, duplicate)
+			assert.Equal(t, tt.expected, duplicate.Name)
+		})
+	}
+}
+
+func TestTargetCooldownRemaining(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"}}
+	now := time.Now()
+
+	succeeded := func(name, policyName string, age time.Duration, dryRun bool) v1alpha1.HealingAction {
+		action := indexedAction(name, policyName, "Deployment", "web", now.Add(-age))
+		action.Spec.DryRun = dryRun
+		completed := metav1.NewTime(now.Add(-age))
+		action.Status.Phase = v1alpha1.HealingActionPhaseSucceeded
+		action.Status.CompletionTime = &completed
+		return *action
+	}
+	failed := indexedAction("web-failed", "web-policy", "Deployment", "web", now)
+	failed.Status.Phase = v1alpha1.HealingActionPhaseFailed
+	failed.Status.CompletionTime = &metav1.Time{Time: now}
+
+	tests := []struct {
+		name     string
+		actions  []v1alpha1.HealingAction
+		cooldown time.Duration
+		expected time.Duration
+	}{
+		{name: "recent success", actions: []v1alpha1.HealingAction{succeeded("a", "web-policy", 10*time.Minute, false), succeeded("b", "web-policy", 2*time.Minute, false)}, cooldown: 5 * time.Minute, expected: 3 * time.Minute},
+		{name: "cooldown elapsed", actions: []v1alpha1.HealingAction{succeeded("a", "web-policy", 10*time.Minute, false)}, cooldown: 5 * time.Minute, expected: -5 * time.Minute},
+		{name: "dry runs do not count", actions: []v1alpha1.HealingAction{succeeded("a", "web-policy", time.Minute, true)}, cooldown: 5 * time.Minute},
+		{name: "failures do not count", actions: []v1alpha1.HealingAction{*failed}, cooldown: 5 * time.Minute},
+		{name: "other policies do not count", actions: []v1alpha1.HealingAction{succeeded("a", "other-policy", time.Minute, false)}, cooldown: 5 * time.Minute},
+		{name: "disabled", actions: []v1alpha1.HealingAction{succeeded("a", "web-policy", time.Minute, false)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, targetCooldownRemaining(tt.actions, policy, tt.cooldown, now))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+var (
+	actionSuccessRate *prometheus.GaugeVec
+	actionOutcomes    = newOutcomeTracker()
+)
+
+// SetActionSuccessRateMetric sets the real action success rate metric from main.go
+func SetActionSuccessRateMetric(metric *prometheus.GaugeVec) {
+	actionSuccessRate = metric
+}
+
+// outcomeKey identifies a success rate series
+type outcomeKey struct {
+	actionType string
+	namespace  string
+}
+
+// outcomeTracker counts the outcomes of real actions completed by this
+// process to derive their success rate
+type outcomeTracker struct {
+	mu        sync.Mutex
+	succeeded map[outcomeKey]int
+	finished  map[outcomeKey]int
+}
+
+func newOutcomeTracker() *outcomeTracker {
+	return &outcomeTracker{
+		succeeded: make(map[outcomeKey]int),
+		finished:  make(map[outcomeKey]int),
+	}
+}
+
+// record adds an outcome and returns the resulting success rate
+func (t *outcomeTracker) record(key outcomeKey, succeeded bool) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.finished[key]++
+	if succeeded {
+		t.succeeded[key]++
+	}
+	return float64(t.succeeded[key]) / float64(t.finished[key])
+}
+
+// recordActionMetrics counts a completed action, labelled with whether it
+// was a dry-run, and updates the success rate of real actions. Dry-runs and
+// cancelled actions never affect the success rate.
+func recordActionMetrics(action *v1alpha1.HealingAction, status, triggerType string) {
+	if healingActionsTotal != nil {
+		healingActionsTotal.WithLabelValues(
+			action.Spec.Action.Type,
+			action.Namespace,
+			status,
+			triggerType,
+			failureReason(action),
+			strconv.FormatBool(action.Spec.DryRun),
+		).Inc()
+	}
+
+	if action.Spec.DryRun || action.Status.Phase == v1alpha1.HealingActionPhaseCancelled {
+		return
+	}
+	rate := actionOutcomes.record(outcomeKey{actionType: action.Spec.Action.Type, namespace: action.Namespace},
+		action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded)
+	if actionSuccessRate != nil {
+		actionSuccessRate.WithLabelValues(action.Spec.Action.Type, action.Namespace).Set(rate)
+	}
+}
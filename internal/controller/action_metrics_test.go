@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestRecordActionMetrics(t *testing.T) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_healing_actions_total"},
+		[]string{"action_type", "namespace", "status", "trigger_type", "reason", "dry_run"})
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_healing_action_success_rate"},
+		[]string{"action_type", "namespace"})
+	previousCounter, previousGauge, previousOutcomes := healingActionsTotal, actionSuccessRate, actionOutcomes
+	SetHealingActionsMetric(counter)
+	SetActionSuccessRateMetric(gauge)
+	actionOutcomes = newOutcomeTracker()
+	defer func() {
+		SetHealingActionsMetric(previousCounter)
+		SetActionSuccessRateMetric(previousGauge)
+		actionOutcomes = previousOutcomes
+	}()
+
+	action := func(phase string, dryRun bool) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "restart", Namespace: "default"},
+			Spec: v1alpha1.HealingActionSpec{
+				Action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+				DryRun: dryRun,
+			},
+			Status: v1alpha1.HealingActionStatus{Phase: phase},
+		}
+	}
+
+	recordActionMetrics(action(v1alpha1.HealingActionPhaseSucceeded, true), "completed", "manual")
+	recordActionMetrics(action(v1alpha1.HealingActionPhaseSucceeded, true), "completed", "manual")
+	recordActionMetrics(action(v1alpha1.HealingActionPhaseSucceeded, false), "completed", "manual")
+	recordActionMetrics(action(v1alpha1.HealingActionPhaseFailed, false), "failed", "manual")
+	recordActionMetrics(action(v1alpha1.HealingActionPhaseCancelled, false), "cancelled", "manual")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(counter.WithLabelValues("restart", "default", "completed", "manual", "", "true")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(counter.WithLabelValues("restart", "default", "completed", "manual", "", "false")))
+
+	// Dry-runs and cancellations are excluded from the success rate
+	assert.Equal(t, 0.5, testutil.ToFloat64(gauge.WithLabelValues("restart", "default")))
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// ReasonInvalidActionTemplate is set when a policy action does not satisfy
+// the ActionTemplate it references
+const ReasonInvalidActionTemplate = "InvalidActionTemplate"
+
+// resolveActionTemplate merges an action with the ActionTemplate it
+// references. The template is returned so rendered parameters can be
+// checked against it again; it is nil for actions without a TemplateRef.
+func resolveActionTemplate(ctx context.Context, c client.Reader, action *v1alpha1.HealingActionTemplate) (*v1alpha1.HealingActionTemplate, *v1alpha1.ActionTemplate, error) {
+	if action.TemplateRef == "" {
+		return action, nil, nil
+	}
+
+	template := &v1alpha1.ActionTemplate{}
+	if err := c.Get(ctx, types.NamespacedName{Name: action.TemplateRef}, template); err != nil {
+		return nil, nil, fmt.Errorf("failed to get ActionTemplate %s for action %s: %w", action.TemplateRef, action.Name, err)
+	}
+
+	merged, err := template.Apply(action)
+	if err != nil {
+		return nil, nil, err
+	}
+	return merged, template, nil
+}
+
+// validatePolicyActions checks every action of a policy against its
+// ActionTemplate. With requireTemplates, actions must reference a template.
+func validatePolicyActions(ctx context.Context, c client.Reader, policy *v1alpha1.HealingPolicy, requireTemplates bool) error {
+	for i := range policy.Spec.Actions {
+		action := &policy.Spec.Actions[i]
+		if requireTemplates && action.TemplateRef == "" {
+			return fmt.Errorf("action %s must reference an ActionTemplate", action.Name)
+		}
+		if _, _, err := resolveActionTemplate(ctx, c, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func actionTemplates() []*v1alpha1.ActionTemplate {
+	return []*v1alpha1.ActionTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bounded-scale"},
+			Spec: v1alpha1.ActionTemplateSpec{
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: 1},
+				Constraints: v1alpha1.ActionConstraints{
+					Scale: &v1alpha1.ScaleBounds{MinReplicas: 2, MaxReplicas: 10},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "resources-patch"},
+			Spec: v1alpha1.ActionTemplateSpec{
+				Type: "patch",
+				Constraints: v1alpha1.ActionConstraints{
+					Patch:           &v1alpha1.PatchConstraints{AllowedPaths: []string{"spec.template.spec.containers"}},
+					RequireApproval: true,
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "release-finalizers"},
+			Spec: v1alpha1.ActionTemplateSpec{
+				Type:            "finalizer",
+				FinalizerAction: &v1alpha1.FinalizerAction{RemoveFinalizers: []string{"example.com/cleanup"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "overnight-hibernate"},
+			Spec: v1alpha1.ActionTemplateSpec{
+				Type:            "hibernate",
+				HibernateAction: &v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: 8 * time.Hour}},
+			},
+		},
+	}
+}
+
+func TestResolveActionTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, template := range actionTemplates() {
+		builder = builder.WithObjects(template)
+	}
+	c := builder.Build()
+
+	tests := []struct {
+		name        string
+		action      v1alpha1.HealingActionTemplate
+		expectError string
+		check       func(t *testing.T, resolved *v1alpha1.HealingActionTemplate)
+	}{
+		{
+			name:   "no template reference",
+			action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			check: func(t *testing.T, resolved *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, "restart", resolved.Type)
+			},
+		},
+		{
+			name:   "template parameters and bounds",
+			action: v1alpha1.HealingActionTemplate{Name: "scale", Type: "scale", TemplateRef: "bounded-scale"},
+			check: func(t *testing.T, resolved *v1alpha1.HealingActionTemplate) {
+				require.NotNil(t, resolved.ScaleAction)
+				assert.Equal(t, "up", resolved.ScaleAction.Direction)
+				assert.Equal(t, int32(2), resolved.ScaleAction.MinReplicas)
+				assert.Equal(t, int32(10), resolved.ScaleAction.MaxReplicas)
+			},
+		},
+		{
+			name: "policy limits are tightened to the bounds",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "scale", Type: "scale", TemplateRef: "bounded-scale",
+				ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: 2, MaxReplicas: 100},
+			},
+			check: func(t *testing.T, resolved *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, int32(10), resolved.ScaleAction.MaxReplicas)
+			},
+		},
+		{
+			name: "absolute replicas outside bounds",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "scale", Type: "scale", TemplateRef: "bounded-scale",
+				ScaleAction: &v1alpha1.ScaleAction{Direction: "absolute", Replicas: 50},
+			},
+			expectError: "outside template bounded-scale bounds",
+		},
+		{
+			name: "min above max",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "scale", Type: "scale", TemplateRef: "bounded-scale",
+				ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: 1, MinReplicas: 8, MaxReplicas: 5},
+			},
+			expectError: "exceeds maxReplicas",
+		},
+		{
+			name:        "type mismatch",
+			action:      v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart", TemplateRef: "bounded-scale"},
+			expectError: "has type restart but template bounded-scale has type scale",
+		},
+		{
+			name:        "missing template",
+			action:      v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart", TemplateRef: "missing"},
+			expectError: "failed to get ActionTemplate missing",
+		},
+		{
+			name: "allowed patch",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "patch", Type: "patch", TemplateRef: "resources-patch",
+				PatchAction: &v1alpha1.PatchAction{
+					Type:  "strategic",
+					Patch: `{"spec":{"template":{"spec":{"containers":[{"name":"app"}]}}}}`,
+				},
+			},
+			check: func(t *testing.T, resolved *v1alpha1.HealingActionTemplate) {
+				assert.True(t, resolved.RequiresApproval)
+			},
+		},
+		{
+			name:   "finalizer template parameters",
+			action: v1alpha1.HealingActionTemplate{Name: "release", Type: "finalizer", TemplateRef: "release-finalizers"},
+			check: func(t *testing.T, resolved *v1alpha1.HealingActionTemplate) {
+				require.NotNil(t, resolved.FinalizerAction)
+				assert.Equal(t, []string{"example.com/cleanup"}, resolved.FinalizerAction.RemoveFinalizers)
+			},
+		},
+		{
+			name:   "hibernate template parameters",
+			action: v1alpha1.HealingActionTemplate{Name: "sleep", TemplateRef: "overnight-hibernate"},
+			check: func(t *testing.T, resolved *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, "hibernate", resolved.Type)
+				require.NotNil(t, resolved.HibernateAction)
+				assert.Equal(t, 8*time.Hour, resolved.HibernateAction.Duration.Duration)
+			},
+		},
+		{
+			name: "disallowed merge patch field",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "patch", Type: "patch", TemplateRef: "resources-patch",
+				PatchAction: &v1alpha1.PatchAction{Type: "merge", Patch: `{"spec":{"replicas":0}}`},
+			},
+			expectError: "patch of spec.replicas is not allowed",
+		},
+		{
+			name: "disallowed JSON patch path",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "patch", Type: "patch", TemplateRef: "resources-patch",
+				PatchAction: &v1alpha1.PatchAction{Type: "json", Patch: `[{"op":"replace","path":"/metadata/labels/app","value":"x"}]`},
+			},
+			expectError: "patch of metadata.labels.app is not allowed",
+		},
+		{
+			name: "disallowed structured patch",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "patch", Type: "patch", TemplateRef: "resources-patch",
+				PatchAction: &v1alpha1.PatchAction{
+					Type:    "strategic",
+					Patches: []v1alpha1.PatchOperation{{Path: []string{"spec", "serviceAccountName"}, Value: "admin"}},
+				},
+			},
+			expectError: "patch of spec.serviceAccountName is not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, _, err := resolveActionTemplate(context.Background(), c, &tt.action)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.check != nil {
+				tt.check(t, resolved)
+			}
+		})
+	}
+}
+
+func TestValidatePolicyActions_RequireTemplates(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(actionTemplates()[0]).Build()
+
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Actions: []v1alpha1.HealingActionTemplate{
+				{Name: "scale", Type: "scale", TemplateRef: "bounded-scale"},
+				{Name: "restart", Type: "restart"},
+			},
+		},
+	}
+
+	assert.NoError(t, validatePolicyActions(context.Background(), c, policy, false))
+
+	err := validatePolicyActions(context.Background(), c, policy, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "action restart must reference an ActionTemplate")
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+const (
+	// AIDecisionModeFilter keeps only the actions the AI recommends
+	AIDecisionModeFilter = "filter"
+
+	// AIDecisionModeBlend orders every action by its rule priority blended
+	// with the AI confidence
+	AIDecisionModeBlend = "blend"
+
+	// blendTieBreak explains the order of actions with the same score
+	blendTieBreak = "ordered by AI confidence, then rule priority, then trigger order"
+)
+
+// defaultBlendWeights are used when no blend weights are configured
+var defaultBlendWeights = config.AIBlendWeights{Rule: 0.5, AI: 0.5}
+
+// aiDecisionMode returns how AI recommendations decide on actions
+func (r *HealingPolicyReconciler) aiDecisionMode() string {
+	if r.Config != nil && r.Config.AI.DecisionMode == AIDecisionModeBlend {
+		return AIDecisionModeBlend
+	}
+	return AIDecisionModeFilter
+}
+
+// blendWeights returns the configured blend weights
+func (r *HealingPolicyReconciler) blendWeights() config.AIBlendWeights {
+	if r.Config == nil || (r.Config.AI.BlendWeights.Rule == 0 && r.Config.AI.BlendWeights.AI == 0) {
+		return defaultBlendWeights
+	}
+	return r.Config.AI.BlendWeights
+}
+
+// bestRecommendation returns the most confident recommendation matching
+// the action, or nil if none matches
+func (r *HealingPolicyReconciler) bestRecommendation(action TriggeredAction, recommendations []types.AIRecommendation) *types.AIRecommendation {
+	var best *types.AIRecommendation
+	for i := range recommendations {
+		if !r.matchesAIRecommendation(action, recommendations[i]) {
+			continue
+		}
+		if best == nil || recommendations[i].Confidence > best.Confidence {
+			best = &recommendations[i]
+		}
+	}
+	return best
+}
+
+// blendDecision scores an action from its rule priority and the confidence
+// of its matching recommendation
+func blendDecision(action TriggeredAction, recommendation *types.AIRecommendation, weights config.AIBlendWeights) *v1alpha1.PriorityDecision {
+	decision := &v1alpha1.PriorityDecision{
+		RulePriority: action.Action.Priority,
+		RuleWeight:   weights.Rule,
+		AIWeight:     weights.AI,
+	}
+	if recommendation != nil {
+		decision.AIConfidence = recommendation.Confidence
+	}
+	decision.Score = weights.Rule*float64(decision.RulePriority) + weights.AI*decision.AIConfidence*100
+
+	decision.Explanation = fmt.Sprintf("%.2f x rule priority %d + %.2f x AI confidence %.0f%% = %.1f",
+		weights.Rule, decision.RulePriority, weights.AI, decision.AIConfidence*100, decision.Score)
+	if recommendation == nil {
+		decision.Explanation += " (no matching AI recommendation)"
+	} else {
+		decision.Explanation += fmt.Sprintf(" (AI recommended %s)", recommendation.Action)
+	}
+	return decision
+}
+
+// blendActionsWithAI keeps every action and orders them by the blended
+// score, which also becomes their priority. Actions matching a confident
+// recommendation are AI-driven; each action records its decision.
+func (r *HealingPolicyReconciler) blendActionsWithAI(actions []TriggeredAction, aiResult *types.AIAnalysis) []TriggeredAction {
+	weights := r.blendWeights()
+	blended := make([]TriggeredAction, len(actions))
+	for i, action := range actions {
+		recommendation := r.bestRecommendation(action, aiResult.Recommendations)
+		action.AIRecommendation = recommendation
+		action.IsAIBased = recommendation != nil && recommendation.Confidence >= minAIConfidence
+		action.PriorityDecision = blendDecision(action, recommendation, weights)
+		blended[i] = action
+	}
+
+	sort.SliceStable(blended, func(i, j int) bool {
+		a, b := blended[i].PriorityDecision, blended[j].PriorityDecision
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.AIConfidence != b.AIConfidence {
+			return a.AIConfidence > b.AIConfidence
+		}
+		return a.RulePriority > b.RulePriority
+	})
+
+	for i := range blended {
+		decision := blended[i].PriorityDecision
+		decision.Rank = int32(i + 1)
+		tied := 0
+		for j := range blended {
+			if j != i && blended[j].PriorityDecision.Score == decision.Score {
+				tied++
+			}
+		}
+		if tied > 0 {
+			decision.TieBreak = fmt.Sprintf("Score tied with %d other actions, %s", tied, blendTieBreak)
+		}
+		blended[i].Action.Priority = int32(math.Round(decision.Score))
+	}
+
+	log.Log.Info("Blended actions with AI recommendations",
+		"actions", len(blended),
+		"ai_driven", countAIDrivenActions(blended),
+		"rule_weight", weights.Rule,
+		"ai_weight", weights.AI)
+	return blended
+}
+
+// priorityDecisionAnnotation encodes a decision for the action annotation
+// that carries it into the action's status
+func priorityDecisionAnnotation(decision *v1alpha1.PriorityDecision) (string, error) {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// priorityDecisionFromAnnotations decodes the decision recorded on an
+// action at creation, or returns nil if there is none
+func priorityDecisionFromAnnotations(annotations map[string]string) *v1alpha1.PriorityDecision {
+	value, ok := annotations[types.AnnotationPriorityDecision]
+	if !ok {
+		return nil
+	}
+	decision := &v1alpha1.PriorityDecision{}
+	if err := json.Unmarshal([]byte(value), decision); err != nil {
+		return nil
+	}
+	return decision
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func blendReconciler(weights config.AIBlendWeights) *HealingPolicyReconciler {
+	cfg := config.NewDefaultConfig()
+	cfg.AI.DecisionMode = AIDecisionModeBlend
+	cfg.AI.BlendWeights = weights
+	return &HealingPolicyReconciler{Config: cfg}
+}
+
+func blendAction(name, actionType string, priority int32) TriggeredAction {
+	return TriggeredAction{
+		Trigger: name,
+		Action:  v1alpha1.HealingActionTemplate{Name: name, Type: actionType, Priority: priority},
+	}
+}
+
+func TestBlendActionsWithAI(t *testing.T) {
+	analysis := &types.AIAnalysis{Recommendations: []types.AIRecommendation{
+		{Action: "scale_up", Confidence: 0.9},
+		{Action: "restart", Confidence: 0.4},
+		{Action: "restart", Confidence: 0.6},
+	}}
+	actions := []TriggeredAction{
+		blendAction("restart-pod", "restart", 80),
+		blendAction("scale-up", "scale", 40),
+		blendAction("delete-pod", "delete", 90),
+	}
+
+	tests := []struct {
+		name      string
+		weights   config.AIBlendWeights
+		wantOrder []string
+		wantScore []float64
+		wantAI    []bool
+	}{
+		{
+			name:      "equal weights",
+			weights:   config.AIBlendWeights{Rule: 0.5, AI: 0.5},
+			wantOrder: []string{"restart-pod", "scale-up", "delete-pod"},
+			wantScore: []float64{70, 65, 45},
+			wantAI:    []bool{false, true, false},
+		},
+		{
+			name:      "rules only",
+			weights:   config.AIBlendWeights{Rule: 1},
+			wantOrder: []string{"delete-pod", "restart-pod", "scale-up"},
+			wantScore: []float64{90, 80, 40},
+			wantAI:    []bool{false, false, true},
+		},
+		{
+			name:      "AI only",
+			weights:   config.AIBlendWeights{AI: 1},
+			wantOrder: []string{"scale-up", "restart-pod", "delete-pod"},
+			wantScore: []float64{90, 60, 0},
+			wantAI:    []bool{true, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blended := blendReconciler(tt.weights).blendActionsWithAI(actions, analysis)
+			require.Len(t, blended, len(actions), "blending keeps every action")
+			for i, ta := range blended {
+				assert.Equal(t, tt.wantOrder[i], ta.Action.Name)
+				require.NotNil(t, ta.PriorityDecision)
+				assert.InDelta(t, tt.wantScore[i], ta.PriorityDecision.Score, 0.001)
+				assert.Equal(t, int32(i+1), ta.PriorityDecision.Rank)
+				assert.Equal(t, int32(tt.wantScore[i]), ta.Action.Priority, "the score becomes the priority")
+				assert.Equal(t, tt.wantAI[i], ta.IsAIBased)
+				assert.NotEmpty(t, ta.PriorityDecision.Explanation)
+			}
+		})
+	}
+
+	t.Run("the most confident recommendation counts", func(t *testing.T) {
+		blended := blendReconciler(config.AIBlendWeights{Rule: 0.5, AI: 0.5}).blendActionsWithAI(actions[:1], analysis)
+		assert.Equal(t, 0.6, blended[0].PriorityDecision.AIConfidence)
+		assert.Equal(t, int32(80), blended[0].PriorityDecision.RulePriority)
+		assert.Equal(t, "0.50 x rule priority 80 + 0.50 x AI confidence 60% = 70.0 (AI recommended restart)",
+			blended[0].PriorityDecision.Explanation)
+	})
+
+	t.Run("ties broken by AI confidence", func(t *testing.T) {
+		tied := []TriggeredAction{
+			blendAction("delete-pod", "delete", 100),
+			blendAction("scale-up", "scale", 10),
+		}
+		blended := blendReconciler(config.AIBlendWeights{Rule: 0.5, AI: 0.5}).blendActionsWithAI(tied, analysis)
+		assert.Equal(t, "scale-up", blended[0].Action.Name)
+		assert.Equal(t, blended[0].PriorityDecision.Score, blended[1].PriorityDecision.Score)
+		assert.Contains(t, blended[0].PriorityDecision.TieBreak, "tied with 1 other actions")
+		assert.Contains(t, blended[1].PriorityDecision.Explanation, "no matching AI recommendation")
+	})
+}
+
+func TestPriorityDecisionCarriedIntoStatus(t *testing.T) {
+	decision := blendDecision(blendAction("restart-pod", "restart", 80),
+		&types.AIRecommendation{Action: "restart", Confidence: 0.6}, config.AIBlendWeights{Rule: 0.5, AI: 0.5})
+	decision.Rank = 1
+	annotation, err := priorityDecisionAnnotation(decision)
+	require.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-restart",
+			Namespace:   "apps",
+			Finalizers:  []string{FinalizerName},
+			Annotations: map[string]string{types.AnnotationPriorityDecision: annotation},
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource:   v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+			Action:           v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			ApprovalRequired: true,
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(action).WithStatusSubresource(action).Build()
+	r := &HealingActionReconciler{
+		Client:            c,
+		Scheme:            scheme,
+		Config:            config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{},
+		SafetyController:  &MockSafetyController{},
+	}
+
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+	require.NoError(t, err)
+
+	got := &v1alpha1.HealingAction{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+	assert.Equal(t, decision, got.Status.PriorityDecision)
+
+	assert.Nil(t, priorityDecisionFromAnnotations(map[string]string{types.AnnotationPriorityDecision: "{"}))
+}
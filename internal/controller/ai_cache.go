@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// cachedAnalysis is the most recent AI analysis of a policy
+type cachedAnalysis struct {
+	analysis *types.AIAnalysis
+	at       time.Time
+}
+
+// aiAnalysisCache keeps the latest AI analysis per policy so policies with
+// an aiAnalysisInterval reuse it between fresh analyses. The zero value is
+// ready to use.
+type aiAnalysisCache struct {
+	mu      sync.Mutex
+	entries map[k8stypes.NamespacedName]cachedAnalysis
+}
+
+// get returns the policy's analysis if it is younger than maxAge
+func (c *aiAnalysisCache) get(key k8stypes.NamespacedName, maxAge time.Duration, now time.Time) (cachedAnalysis, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.Sub(entry.at) >= maxAge {
+		return cachedAnalysis{}, false
+	}
+	return entry, true
+}
+
+func (c *aiAnalysisCache) put(key k8stypes.NamespacedName, analysis *types.AIAnalysis, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[k8stypes.NamespacedName]cachedAnalysis)
+	}
+	c.entries[key] = cachedAnalysis{analysis: analysis, at: now}
+}
+
+// size returns the number of cached analyses
+func (c *aiAnalysisCache) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *aiAnalysisCache) forget(key k8stypes.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// aiAnalysisInterval returns the policy's minimum time between fresh AI
+// analyses, 0 when every evaluation with triggered actions is analyzed
+func aiAnalysisInterval(policy *v1alpha1.HealingPolicy) time.Duration {
+	if policy.Spec.AIAnalysisInterval == nil {
+		return 0
+	}
+	return policy.Spec.AIAnalysisInterval.Duration
+}
+
+// analyzeActions returns AI recommendations for the triggered actions. With
+// an aiAnalysisInterval the cached analysis is reused until it expires;
+// cached reports whether it was. Failed analyses are not cached.
+func (r *HealingPolicyReconciler) analyzeActions(ctx context.Context, policy *v1alpha1.HealingPolicy, clusterMetrics *types.ClusterMetrics, actions []TriggeredAction, now time.Time) (analysis *types.AIAnalysis, cached bool, err error) {
+	interval := aiAnalysisInterval(policy)
+	key := client.ObjectKeyFromObject(policy)
+	if interval > 0 {
+		entry, ok := r.aiCache.get(key, interval, now)
+		r.Stats.RecordCacheLookup("aiAnalysis", ok)
+		if ok {
+			return entry.analysis, true, nil
+		}
+	}
+
+	analysis, err = r.getAIRecommendations(ctx, policy, clusterMetrics, actions)
+	if err != nil {
+		return nil, false, err
+	}
+	if interval > 0 {
+		r.aiCache.put(key, analysis, now)
+	}
+	return analysis, false, nil
+}
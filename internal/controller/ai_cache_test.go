@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestAnalyzeActions(t *testing.T) {
+	now := time.Now()
+	metrics := &ktypes.ClusterMetrics{}
+
+	tests := []struct {
+		name     string
+		interval *metav1.Duration
+		times    []time.Time
+		calls    int
+		cached   []bool
+	}{
+		{
+			name:   "no interval analyzes every time",
+			times:  []time.Time{now, now.Add(time.Second)},
+			calls:  2,
+			cached: []bool{false, false},
+		},
+		{
+			name:     "cached analysis reused within the interval",
+			interval: &metav1.Duration{Duration: 5 * time.Minute},
+			times:    []time.Time{now, now.Add(time.Minute), now.Add(4 * time.Minute)},
+			calls:    1,
+			cached:   []bool{false, true, true},
+		},
+		{
+			name:     "fresh analysis once the interval elapsed",
+			interval: &metav1.Duration{Duration: 5 * time.Minute},
+			times:    []time.Time{now, now.Add(time.Minute), now.Add(5 * time.Minute), now.Add(6 * time.Minute)},
+			calls:    2,
+			cached:   []bool{false, true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &recordingAnalyzer{}
+			r := &HealingPolicyReconciler{AIAnalyzer: analyzer}
+			policy := &v1alpha1.HealingPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       v1alpha1.HealingPolicySpec{AIAnalysisInterval: tt.interval},
+			}
+
+			for i, at := range tt.times {
+				analysis, cached, err := r.analyzeActions(context.Background(), policy, metrics, nil, at)
+				require.NoError(t, err)
+				assert.NotNil(t, analysis)
+				assert.Equal(t, tt.cached[i], cached, "evaluation %d", i)
+			}
+			assert.Len(t, analyzer.calls, tt.calls)
+		})
+	}
+}
+
+func TestAIAnalysisCacheForget(t *testing.T) {
+	var cache aiAnalysisCache
+	key := client.ObjectKey{Namespace: "default", Name: "web"}
+	now := time.Now()
+
+	cache.put(key, &ktypes.AIAnalysis{Summary: "leak"}, now)
+	entry, ok := cache.get(key, time.Minute, now)
+	require.True(t, ok)
+	assert.Equal(t, "leak", entry.analysis.Summary)
+
+	cache.forget(key)
+	_, ok = cache.get(key, time.Minute, now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// LabelHealingAction links an AIDecision to its HealingAction
+	LabelHealingAction = "kubeskippy.io/healing-action"
+
+	// defaultDecisionTTL is used when no decision TTL is configured
+	defaultDecisionTTL = 7 * 24 * time.Hour
+)
+
+// newAIDecision builds the decision record of an AI-driven action. The
+// decision shares the action's name so it can be found from the action.
+func newAIDecision(action *v1alpha1.HealingAction, recommendation *types.AIRecommendation, ttl time.Duration) *v1alpha1.AIDecision {
+	alternatives := make([]v1alpha1.DecisionAlternative, 0, len(recommendation.Reasoning.Alternatives))
+	for _, alt := range recommendation.Reasoning.Alternatives {
+		alternatives = append(alternatives, v1alpha1.DecisionAlternative{
+			Action:   alt.Action,
+			Rejected: alt.Rejected,
+			Reason:   alt.Reason,
+		})
+	}
+
+	return &v1alpha1.AIDecision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      action.Name,
+			Namespace: action.Namespace,
+			Labels: map[string]string{
+				LabelManagedBy:     "kubeskippy",
+				LabelPolicyName:    action.Spec.PolicyRef.Name,
+				LabelHealingAction: action.Name,
+			},
+		},
+		Spec: v1alpha1.AIDecisionSpec{
+			HealingActionRef: action.Name,
+			PolicyRef:        action.Spec.PolicyRef,
+			TargetResource:   action.Spec.TargetResource,
+			ActionType:       action.Spec.Action.Type,
+			Model:            action.Annotations[types.AnnotationAIModel],
+			PromptHash:       action.Annotations[types.AnnotationAIPromptHash],
+			Confidence:       recommendation.Confidence,
+			Risk:             recommendation.Risk,
+			Reason:           recommendation.Reason,
+			ReasoningSteps:   extractReasoningSteps(*recommendation),
+			Alternatives:     alternatives,
+			RiskAssessment:   recommendation.Reasoning.DecisionLogic,
+			ExpectedOutcome: fmt.Sprintf("AI-driven %s with %.1f%% confidence",
+				recommendation.Action, recommendation.Confidence*100),
+			TTL: metav1.Duration{Duration: ttl},
+		},
+	}
+}
+
+// recordAIDecision stores the AI recommendation behind a created action
+func (r *HealingPolicyReconciler) recordAIDecision(ctx context.Context, action *v1alpha1.HealingAction, recommendation *types.AIRecommendation) error {
+	ttl := defaultDecisionTTL
+	if r.Config != nil && r.Config.AI.DecisionTTL > 0 {
+		ttl = r.Config.AI.DecisionTTL
+	}
+
+	decision := newAIDecision(action, recommendation, ttl)
+	if err := r.Create(ctx, decision); err != nil {
+		return fmt.Errorf("failed to create AIDecision: %w", err)
+	}
+	decision.Status.Phase = v1alpha1.AIDecisionPhasePending
+	if err := r.Status().Update(ctx, decision); err != nil {
+		return fmt.Errorf("failed to update AIDecision status: %w", err)
+	}
+
+	if metrics.GlobalAIMetrics != nil {
+		metrics.GlobalAIMetrics.StartAIDecision(ctx, &metrics.AIDecision{
+			ID:              decision.Name,
+			PolicyName:      action.Spec.PolicyRef.Name,
+			TriggerType:     "ai",
+			ActionType:      recommendation.Action,
+			Confidence:      recommendation.Confidence,
+			ReasoningSteps:  decision.Spec.ReasoningSteps,
+			Alternatives:    extractAlternatives(*recommendation),
+			RiskAssessment:  decision.Spec.RiskAssessment,
+			ExpectedOutcome: decision.Spec.ExpectedOutcome,
+		})
+	}
+	return nil
+}
+
+// decisionPhase maps a finished action's phase to its decision's phase
+func decisionPhase(actionPhase string) string {
+	switch actionPhase {
+	case v1alpha1.HealingActionPhaseSucceeded:
+		return v1alpha1.AIDecisionPhaseCompleted
+	case v1alpha1.HealingActionPhaseCancelled:
+		return v1alpha1.AIDecisionPhaseCancelled
+	default:
+		return v1alpha1.AIDecisionPhaseFailed
+	}
+}
+
+// completeAIDecision records a finished action's outcome on its decision
+func (r *HealingActionReconciler) completeAIDecision(ctx context.Context, action *v1alpha1.HealingAction) error {
+	if action.Labels[LabelAIDriven] != "true" {
+		return nil
+	}
+
+	decision := &v1alpha1.AIDecision{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(action), decision); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get AIDecision: %w", err)
+	}
+	if decision.Status.CompletionTime != nil {
+		return nil
+	}
+
+	success := action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded
+	outcome := ""
+	if action.Status.Result != nil {
+		outcome = action.Status.Result.Message
+		if action.Status.Result.Error != "" {
+			outcome = action.Status.Result.Error
+		}
+	}
+	completed := metav1.Now()
+	if action.Status.CompletionTime != nil {
+		completed = *action.Status.CompletionTime
+	}
+
+	decision.Status.Phase = decisionPhase(action.Status.Phase)
+	decision.Status.Success = &success
+	decision.Status.ActualOutcome = outcome
+	decision.Status.CompletionTime = &completed
+	if err := r.Status().Update(ctx, decision); err != nil {
+		return fmt.Errorf("failed to update AIDecision status: %w", err)
+	}
+
+	if metrics.GlobalAIMetrics != nil {
+		metrics.GlobalAIMetrics.CompleteAIDecision(ctx, metrics.AIDecision{
+			ID:            decision.Name,
+			Timestamp:     decision.CreationTimestamp.Time,
+			PolicyName:    decision.Spec.PolicyRef.Name,
+			TriggerType:   "ai",
+			ActionType:    decision.Spec.ActionType,
+			Confidence:    decision.Spec.Confidence,
+			ActualOutcome: outcome,
+		}, success, completed.Sub(decision.CreationTimestamp.Time))
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func aiDrivenAction() *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-restart-x7k2p",
+			Namespace:   "apps",
+			Labels:      map[string]string{LabelAIDriven: "true"},
+			Annotations: map[string]string{types.AnnotationAIModel: "llama2:7b"},
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "web", Namespace: "apps"},
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "web-1", Namespace: "apps"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+		},
+	}
+}
+
+func aiDecisionScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestRecordAIDecision(t *testing.T) {
+	scheme := aiDecisionScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.AIDecision{}).Build()
+	cfg := config.NewDefaultConfig()
+	cfg.AI.DecisionTTL = 48 * time.Hour
+	r := &HealingPolicyReconciler{Client: c, Scheme: scheme, Config: cfg}
+
+	recommendation := &types.AIRecommendation{
+		Action:     "restart",
+		Reason:     "memory leak",
+		Risk:       "low",
+		Confidence: 0.85,
+		Reasoning: types.DecisionReasoning{
+			DecisionLogic: "restart clears the leaked memory",
+			Alternatives:  []types.Alternative{{Action: "scale", Rejected: true, Reason: "does not fix the leak"}},
+		},
+	}
+	require.NoError(t, r.recordAIDecision(context.Background(), aiDrivenAction(), recommendation))
+
+	decision := &v1alpha1.AIDecision{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web-restart-x7k2p"}, decision))
+	assert.Equal(t, "web-restart-x7k2p", decision.Spec.HealingActionRef)
+	assert.Equal(t, "web-restart-x7k2p", decision.Labels[LabelHealingAction])
+	assert.Equal(t, "web", decision.Spec.PolicyRef.Name)
+	assert.Equal(t, "web-1", decision.Spec.TargetResource.Name)
+	assert.Equal(t, "restart", decision.Spec.ActionType)
+	assert.Equal(t, "llama2:7b", decision.Spec.Model)
+	assert.Equal(t, 0.85, decision.Spec.Confidence)
+	assert.Equal(t, "restart clears the leaked memory", decision.Spec.RiskAssessment)
+	assert.Equal(t, []v1alpha1.DecisionAlternative{{Action: "scale", Rejected: true, Reason: "does not fix the leak"}}, decision.Spec.Alternatives)
+	assert.Contains(t, decision.Spec.ReasoningSteps, "rejected-scale")
+	assert.Equal(t, 48*time.Hour, decision.Spec.TTL.Duration)
+	assert.Equal(t, v1alpha1.AIDecisionPhasePending, decision.Status.Phase)
+}
+
+func TestCompleteAIDecision(t *testing.T) {
+	tests := []struct {
+		name        string
+		phase       string
+		result      *v1alpha1.ActionResult
+		wantPhase   string
+		wantSuccess bool
+		wantOutcome string
+	}{
+		{
+			name:        "succeeded",
+			phase:       v1alpha1.HealingActionPhaseSucceeded,
+			result:      &v1alpha1.ActionResult{Success: true, Message: "pod restarted"},
+			wantPhase:   v1alpha1.AIDecisionPhaseCompleted,
+			wantSuccess: true,
+			wantOutcome: "pod restarted",
+		},
+		{
+			name:        "failed",
+			phase:       v1alpha1.HealingActionPhaseFailed,
+			result:      &v1alpha1.ActionResult{Message: "restart failed", Error: "forbidden"},
+			wantPhase:   v1alpha1.AIDecisionPhaseFailed,
+			wantOutcome: "forbidden",
+		},
+		{
+			name:      "cancelled",
+			phase:     v1alpha1.HealingActionPhaseCancelled,
+			wantPhase: v1alpha1.AIDecisionPhaseCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := aiDecisionScheme(t)
+			action := aiDrivenAction()
+			action.Status.Phase = tt.phase
+			action.Status.Result = tt.result
+			decision := newAIDecision(action, &types.AIRecommendation{Action: "restart", Confidence: 0.9}, time.Hour)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(decision).WithStatusSubresource(decision).Build()
+			r := &HealingActionReconciler{Client: c, Scheme: scheme}
+
+			require.NoError(t, r.completeAIDecision(context.Background(), action))
+
+			got := &v1alpha1.AIDecision{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(decision), got))
+			assert.Equal(t, tt.wantPhase, got.Status.Phase)
+			require.NotNil(t, got.Status.Success)
+			assert.Equal(t, tt.wantSuccess, *got.Status.Success)
+			assert.Equal(t, tt.wantOutcome, got.Status.ActualOutcome)
+			assert.NotNil(t, got.Status.CompletionTime)
+		})
+	}
+}
+
+func TestCompleteAIDecision_TraditionalAction(t *testing.T) {
+	scheme := aiDecisionScheme(t)
+	r := &HealingActionReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+
+	action := aiDrivenAction()
+	delete(action.Labels, LabelAIDriven)
+	assert.NoError(t, r.completeAIDecision(context.Background(), action))
+}
+
+func TestAIDecisionReconciler(t *testing.T) {
+	finished := func(name string, completedAgo time.Duration) *v1alpha1.AIDecision {
+		completed := metav1.NewTime(time.Now().Add(-completedAgo))
+		return &v1alpha1.AIDecision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       v1alpha1.AIDecisionSpec{HealingActionRef: name, TTL: metav1.Duration{Duration: 24 * time.Hour}},
+			Status:     v1alpha1.AIDecisionStatus{Phase: v1alpha1.AIDecisionPhaseCompleted, CompletionTime: &completed},
+		}
+	}
+	pending := func(name string) *v1alpha1.AIDecision {
+		return &v1alpha1.AIDecision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       v1alpha1.AIDecisionSpec{HealingActionRef: name, TTL: metav1.Duration{Duration: 24 * time.Hour}},
+			Status:     v1alpha1.AIDecisionStatus{Phase: v1alpha1.AIDecisionPhasePending},
+		}
+	}
+
+	scheme := aiDecisionScheme(t)
+	running := &v1alpha1.HealingAction{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "apps"}}
+	decisions := []client.Object{finished("expired", 25*time.Hour), finished("recent", time.Hour), pending("running"), pending("orphaned")}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(append(decisions, running)...).
+		WithStatusSubresource(&v1alpha1.AIDecision{}).
+		Build()
+	r := &AIDecisionReconciler{Client: c, Scheme: scheme}
+
+	reconcile := func(name string) ctrl.Result {
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "apps", Name: name}})
+		require.NoError(t, err)
+		return result
+	}
+	get := func(name string) (*v1alpha1.AIDecision, error) {
+		decision := &v1alpha1.AIDecision{}
+		return decision, c.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: name}, decision)
+	}
+
+	// Decisions past their TTL are deleted
+	assert.Zero(t, reconcile("expired"))
+	_, err := get("expired")
+	assert.True(t, errors.IsNotFound(err))
+
+	// Decisions within their TTL are kept until it passes
+	result := reconcile("recent")
+	assert.InDelta(t, (23 * time.Hour).Seconds(), result.RequeueAfter.Seconds(), 5)
+	_, err = get("recent")
+	assert.NoError(t, err)
+
+	// Pending decisions wait for their action
+	assert.Equal(t, decisionRecheckInterval, reconcile("running").RequeueAfter)
+
+	// Decisions whose action was deleted are closed and then expire
+	result = reconcile("orphaned")
+	assert.InDelta(t, (24 * time.Hour).Seconds(), result.RequeueAfter.Seconds(), 5)
+	orphaned, err := get("orphaned")
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.AIDecisionPhaseCancelled, orphaned.Status.Phase)
+	assert.NotNil(t, orphaned.Status.CompletionTime)
+}
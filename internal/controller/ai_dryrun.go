@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/ai"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+)
+
+// AIDryRun is what the AI analysis of a policy says right now: the policy
+// evaluated against live metrics with the AI verdict of every action, and
+// the prompts, raw responses, parsed analysis and rejected recommendations
+// of the analysis
+type AIDryRun struct {
+	*PolicyTestResult
+
+	// Approved are the actions that would be created, as "action target"
+	Approved []string `json:"approvedActions"`
+
+	AI *ai.Trace `json:"ai"`
+}
+
+// DryRunAI runs the AI analysis path of a policy against live metrics,
+// bypassing the analysis interval and cache, without creating actions. The
+// prompt is built as the controller would build it, including lite
+// sampling.
+func (r *HealingPolicyReconciler) DryRunAI(ctx context.Context, policy *v1alpha1.HealingPolicy) (*AIDryRun, error) {
+	if r.AIAnalyzer == nil || r.Config.AI.Provider == "" {
+		return nil, fmt.Errorf("no AI provider is configured")
+	}
+
+	clusterMetrics, err := r.MetricsCollector.CollectMetrics(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect metrics: %w", err)
+	}
+	if advancedCollector, ok := r.MetricsCollector.(*metrics.AdvancedCollector); ok {
+		if advanced, err := advancedCollector.CollectSampledAdvancedMetrics(ctx, policy, aiBudgetFor(policy).maxPods); err == nil {
+			clusterMetrics.Patterns = advanced.Patterns
+		}
+	}
+
+	trace := &ai.Trace{}
+	result, err := r.TestPolicy(ai.WithTrace(ctx, trace), policy, &PolicyTestRequest{
+		Policy:  policy.Namespace + "/" + policy.Name,
+		Metrics: clusterMetrics,
+		RunAI:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := &AIDryRun{PolicyTestResult: result, Approved: []string{}, AI: trace}
+	analyzed := false
+	for _, action := range result.Actions {
+		// Actions skipped before the AI filtering have no AI verdict
+		analyzed = analyzed || action.AI != "" || action.Skipped == ""
+		if action.Skipped == "" {
+			dryRun.Approved = append(dryRun.Approved, action.Action+" "+action.Target)
+		}
+	}
+	if !analyzed {
+		result.Warnings = append(result.Warnings, "no action would be created, the AI was not queried")
+	}
+	return dryRun, nil
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// recommendingAnalyzer recommends a rolling restart
+type recommendingAnalyzer struct {
+	recordingAnalyzer
+}
+
+func (a *recommendingAnalyzer) AnalyzeClusterState(ctx context.Context, metrics *ktypes.ClusterMetrics, issues []ktypes.Issue) (*ktypes.AIAnalysis, error) {
+	a.calls = append(a.calls, metrics)
+	return &ktypes.AIAnalysis{
+		Summary:         "memory leak",
+		Recommendations: []ktypes.AIRecommendation{{Action: "rolling_restart", Confidence: 0.9}},
+	}, nil
+}
+
+func newAIDryRunReconciler(t *testing.T, snapshot *ktypes.ClusterMetrics) (*HealingPolicyReconciler, *v1alpha1.HealingPolicy, *recommendingAnalyzer) {
+	r, policy := newPolicyTestReconciler(t, nil)
+	r.MetricsCollector.(*MockMetricsCollector).CollectMetricsFunc = func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error) {
+		return snapshot, nil
+	}
+	r.Config.AI.Provider = "ollama"
+	analyzer := &recommendingAnalyzer{}
+	r.AIAnalyzer = analyzer
+	return r, policy, analyzer
+}
+
+func TestDryRunAI(t *testing.T) {
+	r, policy, analyzer := newAIDryRunReconciler(t, hotSnapshot())
+
+	result, err := r.DryRunAI(context.Background(), policy)
+	require.NoError(t, err)
+	require.Len(t, analyzer.calls, 1, "the AI is queried with live metrics")
+	assert.Equal(t, "memory leak", result.AISummary)
+	assert.Equal(t, []string{"restart Pod/default/web-1"}, result.Approved)
+	assert.Empty(t, result.Warnings)
+	require.NotNil(t, result.AI)
+
+	byName := plannedByName(result.PolicyTestResult)
+	assert.Equal(t, "filtered out by AI analysis", byName["patch"].Skipped)
+
+	// Dry runs never write to the cluster
+	actions := &v1alpha1.HealingActionList{}
+	require.NoError(t, r.List(context.Background(), actions))
+	assert.Empty(t, actions.Items)
+}
+
+func TestDryRunAI_LiteSampling(t *testing.T) {
+	snapshot := hotSnapshot()
+	for _, name := range []string{"web-1", "web-2", "web-3"} {
+		snapshot.Pods = append(snapshot.Pods, ktypes.PodMetrics{Name: name, Namespace: "default"})
+	}
+	r, policy, analyzer := newAIDryRunReconciler(t, snapshot)
+	policy.Spec.AIProfile = &v1alpha1.AIProfile{Mode: "lite", MaxPods: 1}
+
+	_, err := r.DryRunAI(context.Background(), policy)
+	require.NoError(t, err)
+	require.Len(t, analyzer.calls, 1)
+	require.Len(t, analyzer.calls[0].Pods, 1, "the prompt is sampled as the controller samples it")
+	assert.Equal(t, "web-1", analyzer.calls[0].Pods[0].Name, "targets are kept")
+}
+
+func TestDryRunAI_NothingTriggered(t *testing.T) {
+	r, policy, analyzer := newAIDryRunReconciler(t, &ktypes.ClusterMetrics{Nodes: []ktypes.NodeMetrics{{Name: "cool-node"}}})
+
+	result, err := r.DryRunAI(context.Background(), policy)
+	require.NoError(t, err)
+	assert.Empty(t, analyzer.calls)
+	assert.Empty(t, result.Approved)
+	assert.Contains(t, result.Warnings, "no action would be created, the AI was not queried")
+}
+
+func TestDryRunAI_RequiresProvider(t *testing.T) {
+	r, policy := newPolicyTestReconciler(t, nil)
+	_, err := r.DryRunAI(context.Background(), policy)
+	assert.ErrorContains(t, err, "no AI provider is configured")
+}
+
+func TestPolicyTestServer_AIDryRun(t *testing.T) {
+	r, _, _ := newAIDryRunReconciler(t, hotSnapshot())
+	server, err := NewPolicyTestServer(r, ":0", "s3cret")
+	require.NoError(t, err)
+
+	post := func(token string, body any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, AIDryRunPath, bytes.NewReader(data))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, post("", AIDryRunRequest{Policy: "default/web-memory"}).Code)
+	assert.Equal(t, http.StatusBadRequest, post("s3cret", AIDryRunRequest{Policy: "web-memory"}).Code)
+	assert.Equal(t, http.StatusNotFound, post("s3cret", AIDryRunRequest{Policy: "default/missing"}).Code)
+
+	rec := post("s3cret", AIDryRunRequest{Policy: "default/web-memory"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result struct {
+		Approved []string       `json:"approvedActions"`
+		AI       map[string]any `json:"ai"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, []string{"restart Pod/default/web-1"}, result.Approved)
+	assert.Contains(t, result.AI, "exchanges")
+}
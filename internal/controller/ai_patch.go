@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/safety"
+)
+
+// AnnotationAIPatch marks actions whose patch was proposed by the AI
+const AnnotationAIPatch = "kubeskippy.io/ai-patch"
+
+// applyAIPatch replaces the patch of a patch action with the patch the AI
+// recommendation behind it proposes, once that patch passes the target
+// kind's mutable field allowlist. A denied patch fails the action rather
+// than falling back to the policy's patch, since the AI matched the action
+// for its own patch. It reports whether the AI patch was applied.
+func applyAIPatch(actionTemplate *v1alpha1.HealingActionTemplate, ta TriggeredAction) (bool, error) {
+	if !ta.IsAIBased || ta.AIRecommendation == nil || len(ta.AIRecommendation.Patches) == 0 || actionTemplate.Type != "patch" {
+		return false, nil
+	}
+
+	kind := ta.Resource.GetObjectKind().GroupVersionKind().Kind
+	if violation := safety.ValidatePatch(kind, ta.AIRecommendation.Patches); violation != nil {
+		metrics.RecordAIPatchDenial(kind, violation.Rule)
+		return false, fmt.Errorf("AI-proposed patch denied: %w", violation)
+	}
+
+	patchType := "merge"
+	if actionTemplate.PatchAction != nil && actionTemplate.PatchAction.Type != "" {
+		patchType = actionTemplate.PatchAction.Type
+	}
+	patches := make([]v1alpha1.PatchOperation, len(ta.AIRecommendation.Patches))
+	for i := range ta.AIRecommendation.Patches {
+		ta.AIRecommendation.Patches[i].DeepCopyInto(&patches[i])
+	}
+	actionTemplate.PatchAction = &v1alpha1.PatchAction{Type: patchType, Patches: patches}
+	return true, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/safety"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestApplyAIPatch(t *testing.T) {
+	denials := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_ai_patch_denials_total"}, []string{"kind", "rule"})
+	metrics.SetAIPatchDenialsMetric(denials)
+	defer metrics.SetAIPatchDenialsMetric(nil)
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+	}
+	policyPatch := func() *v1alpha1.HealingActionTemplate {
+		return &v1alpha1.HealingActionTemplate{
+			Name: "tune",
+			Type: "patch",
+			PatchAction: &v1alpha1.PatchAction{
+				Type:    "strategic",
+				Patches: []v1alpha1.PatchOperation{{Path: []string{"spec", "replicas"}, Value: "3"}},
+			},
+		}
+	}
+	aiAction := func(patches ...v1alpha1.PatchOperation) TriggeredAction {
+		return TriggeredAction{
+			Resource:         deployment,
+			IsAIBased:        true,
+			AIRecommendation: &types.AIRecommendation{Action: "patch", Patches: patches},
+		}
+	}
+
+	// Allowed patches replace the policy's patch and keep its type
+	actionTemplate := policyPatch()
+	minReady := v1alpha1.PatchOperation{Path: []string{"spec", "minReadySeconds"}, Value: "30"}
+	applied, err := applyAIPatch(actionTemplate, aiAction(minReady))
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, "strategic", actionTemplate.PatchAction.Type)
+	assert.Equal(t, []v1alpha1.PatchOperation{minReady}, actionTemplate.PatchAction.Patches)
+
+	// Denied patches fail the action and are counted
+	actionTemplate = policyPatch()
+	_, err = applyAIPatch(actionTemplate, aiAction(v1alpha1.PatchOperation{Path: []string{"spec", "selector"}, Value: "{}"}))
+	assert.ErrorContains(t, err, "AI-proposed patch denied: spec.selector")
+	assert.Equal(t, policyPatch(), actionTemplate)
+	assert.Equal(t, 1.0, testutil.ToFloat64(denials.WithLabelValues("Deployment", safety.PatchRuleSelector)))
+
+	// Actions without an AI patch keep the policy's patch
+	for _, ta := range []TriggeredAction{aiAction(), {Resource: deployment}} {
+		actionTemplate = policyPatch()
+		applied, err = applyAIPatch(actionTemplate, ta)
+		require.NoError(t, err)
+		assert.False(t, applied)
+		assert.Equal(t, policyPatch(), actionTemplate)
+	}
+
+	// Only patch actions take AI patches
+	restart := &v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"}
+	applied, err = applyAIPatch(restart, aiAction(minReady))
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Nil(t, restart.PatchAction)
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// aiProfileLite runs the AI subsystem at reduced frequency on sampled pods
+	aiProfileLite = "lite"
+
+	defaultLiteAnalysisInterval = 10 * time.Minute
+	defaultLiteMaxPods          = 50
+)
+
+// aiBudget is the effective AI profile of a policy
+type aiBudget struct {
+	lite     bool
+	interval time.Duration
+	maxPods  int
+}
+
+// aiBudgetFor resolves a policy's AI profile, applying the lite defaults
+func aiBudgetFor(policy *v1alpha1.HealingPolicy) aiBudget {
+	profile := policy.Spec.AIProfile
+	if profile == nil || profile.Mode != aiProfileLite {
+		return aiBudget{}
+	}
+
+	budget := aiBudget{
+		lite:     true,
+		interval: defaultLiteAnalysisInterval,
+		maxPods:  defaultLiteMaxPods,
+	}
+	if profile.AnalysisInterval != nil && profile.AnalysisInterval.Duration > 0 {
+		budget.interval = profile.AnalysisInterval.Duration
+	}
+	if profile.MaxPods > 0 {
+		budget.maxPods = int(profile.MaxPods)
+	}
+	return budget
+}
+
+// due reports whether analysis may run now given when it last ran. Full
+// profiles are always due.
+func (b aiBudget) due(last *time.Time, now time.Time) bool {
+	if !b.lite || last == nil || last.IsZero() {
+		return true
+	}
+	return now.Sub(*last) >= b.interval
+}
+
+// lastAIAnalysis returns when the policy's AI analysis last ran, if ever
+func lastAIAnalysis(policy *v1alpha1.HealingPolicy) *time.Time {
+	if policy.Status.LastAIAnalysis == nil {
+		return nil
+	}
+	return &policy.Status.LastAIAnalysis.Time
+}
+
+// aiSampleKeys returns the pods targeted by triggered actions so sampling
+// keeps them in the analysis
+func aiSampleKeys(actions []TriggeredAction) map[string]bool {
+	keys := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		keys[action.Resource.GetNamespace()+"/"+action.Resource.GetName()] = true
+	}
+	return keys
+}
+
+// markAIAnalysis records that AI analysis ran for the policy
+func markAIAnalysis(policy *v1alpha1.HealingPolicy) {
+	now := metav1.Now()
+	policy.Status.LastAIAnalysis = &now
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// recordingAnalyzer records the metrics each AI analysis received
+type recordingAnalyzer struct {
+	calls []*ktypes.ClusterMetrics
+}
+
+func (a *recordingAnalyzer) AnalyzeClusterState(ctx context.Context, metrics *ktypes.ClusterMetrics, issues []ktypes.Issue) (*ktypes.AIAnalysis, error) {
+	a.calls = append(a.calls, metrics)
+	return &ktypes.AIAnalysis{}, nil
+}
+
+func (a *recordingAnalyzer) ValidateRecommendation(ctx context.Context, recommendation *ktypes.AIRecommendation) error {
+	return nil
+}
+
+func (a *recordingAnalyzer) GetModel() string { return "test" }
+
+func TestAIBudgetFor(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-2 * time.Minute)
+	stale := now.Add(-15 * time.Minute)
+
+	tests := []struct {
+		name     string
+		profile  *v1alpha1.AIProfile
+		expected aiBudget
+		last     *time.Time
+		due      bool
+	}{
+		{name: "no profile", expected: aiBudget{}, last: &recent, due: true},
+		{name: "full", profile: &v1alpha1.AIProfile{Mode: "full"}, expected: aiBudget{}, last: &recent, due: true},
+		{
+			name:     "lite defaults",
+			profile:  &v1alpha1.AIProfile{Mode: "lite"},
+			expected: aiBudget{lite: true, interval: 10 * time.Minute, maxPods: 50},
+			last:     &recent,
+			due:      false,
+		},
+		{
+			name:     "lite never analyzed",
+			profile:  &v1alpha1.AIProfile{Mode: "lite"},
+			expected: aiBudget{lite: true, interval: 10 * time.Minute, maxPods: 50},
+			due:      true,
+		},
+		{
+			name: "lite custom",
+			profile: &v1alpha1.AIProfile{
+				Mode:             "lite",
+				AnalysisInterval: &metav1.Duration{Duration: time.Minute},
+				MaxPods:          10,
+			},
+			expected: aiBudget{lite: true, interval: time.Minute, maxPods: 10},
+			last:     &recent,
+			due:      true,
+		},
+		{
+			name:     "lite interval elapsed",
+			profile:  &v1alpha1.AIProfile{Mode: "lite"},
+			expected: aiBudget{lite: true, interval: 10 * time.Minute, maxPods: 50},
+			last:     &stale,
+			due:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &v1alpha1.HealingPolicy{Spec: v1alpha1.HealingPolicySpec{AIProfile: tt.profile}}
+			budget := aiBudgetFor(policy)
+			assert.Equal(t, tt.expected, budget)
+			assert.Equal(t, tt.due, budget.due(tt.last, now))
+		})
+	}
+}
+
+func TestEvaluatePolicy_LiteAIProfile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	var objs []client.Object
+	var podMetrics []ktypes.PodMetrics
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("web-%d", i)
+		objs = append(objs, &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+		})
+		podMetrics = append(podMetrics, ktypes.PodMetrics{Name: name, Namespace: "default", Status: "Running"})
+	}
+
+	newPolicy := func() *v1alpha1.HealingPolicy {
+		return &v1alpha1.HealingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: v1alpha1.HealingPolicySpec{
+				Mode: "dryrun",
+				Selector: v1alpha1.ResourceSelector{
+					Namespaces:    []string{"default"},
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Resources:     []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+				},
+				Triggers:  []v1alpha1.HealingTrigger{{Name: "always", Type: "metric"}},
+				Actions:   []v1alpha1.HealingActionTemplate{{Name: "restart", Type: "restart"}},
+				AIProfile: &v1alpha1.AIProfile{Mode: "lite", MaxPods: 2},
+			},
+		}
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.AI.Provider = "ollama"
+	analyzer := &recordingAnalyzer{}
+	r := &HealingPolicyReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme: scheme,
+		Config: cfg,
+		MetricsCollector: &MockMetricsCollector{
+			CollectMetricsFunc: func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error) {
+				return &ktypes.ClusterMetrics{Timestamp: time.Now(), Pods: podMetrics}, nil
+			},
+			EvaluateTriggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
+				return true, "always", nil
+			},
+		},
+		SafetyController: &MockSafetyController{},
+		AIAnalyzer:       analyzer,
+	}
+
+	// First evaluation analyzes a sample of the pods
+	policy := newPolicy()
+	_, err := r.evaluatePolicy(context.Background(), log.Log, policy)
+	require.NoError(t, err)
+	require.Len(t, analyzer.calls, 1)
+	assert.Len(t, analyzer.calls[0].Pods, 2)
+	require.NotNil(t, policy.Status.LastAIAnalysis)
+
+	// Within the interval the policy keeps healing without AI
+	_, err = r.evaluatePolicy(context.Background(), log.Log, policy)
+	require.NoError(t, err)
+	assert.Len(t, analyzer.calls, 1)
+
+	// Once the interval has passed analysis runs again
+	stale := metav1.NewTime(time.Now().Add(-11 * time.Minute))
+	policy.Status.LastAIAnalysis = &stale
+	_, err = r.evaluatePolicy(context.Background(), log.Log, policy)
+	require.NoError(t, err)
+	assert.Len(t, analyzer.calls, 2)
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// aiProgressInterval returns the minimum time between status updates of a
+// streamed AI analysis, 0 when streaming is disabled
+func (r *HealingPolicyReconciler) aiProgressInterval() time.Duration {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.AI.ProgressInterval
+}
+
+// reportAIProgress marks an AI analysis of the policy as running. The
+// returned progress callback patches status.aiAnalysis at most once per
+// progress interval, so the analysis can be watched while the provider
+// streams its response; finish records the outcome on the in-memory
+// status, which the reconcile persists with the rest of the evaluation.
+//
+// Each progress patch is conditional on the resource version the previous
+// one produced, starting from the version the reconcile read, so progress
+// stops being reported as soon as anyone else writes the policy. Only if
+// every write since the read was a progress patch does finish carry the
+// last patched version over to the policy. The reconcile's status patch
+// only carries the fields it changed, so it never overwrites writes it has
+// not seen.
+func (r *HealingPolicyReconciler) reportAIProgress(ctx context.Context, policy *v1alpha1.HealingPolicy) (progress func(types.AIProgress), finish func(*types.AIAnalysis, error)) {
+	logger := log.FromContext(ctx)
+	interval := r.aiProgressInterval()
+	status := &v1alpha1.AIAnalysisStatus{
+		Phase:     v1alpha1.AIAnalysisPhaseRunning,
+		StartedAt: metav1.Now(),
+	}
+	policy.Status.AIAnalysis = status
+
+	resourceVersion := policy.ResourceVersion
+	var patched time.Time
+	var stale bool
+	progress = func(update types.AIProgress) {
+		now := metav1.Now()
+		status.StepsCompleted = int32(update.StepsCompleted)
+		status.CurrentStep = update.CurrentStep
+		status.Confidence = update.Confidence
+		status.UpdatedAt = &now
+
+		if stale || (!patched.IsZero() && now.Sub(patched) < interval) {
+			return
+		}
+		patched = now.Time
+		newVersion, err := r.patchAIAnalysis(ctx, policy, resourceVersion)
+		switch {
+		case errors.IsConflict(err):
+			// Someone else wrote the policy; the outcome is still recorded
+			// by the reconcile's status patch
+			stale = true
+			logger.V(1).Info("Policy changed during AI analysis, no longer reporting progress")
+		case err != nil:
+			logger.V(1).Info("Failed to report AI analysis progress", "error", err.Error())
+		default:
+			resourceVersion = newVersion
+		}
+	}
+
+	finish = func(analysis *types.AIAnalysis, err error) {
+		if !stale {
+			policy.ResourceVersion = resourceVersion
+		}
+		now := metav1.Now()
+		status.UpdatedAt = &now
+		if err != nil {
+			status.Phase = v1alpha1.AIAnalysisPhaseFailed
+			status.Message = err.Error()
+			return
+		}
+		status.Phase = v1alpha1.AIAnalysisPhaseCompleted
+		status.Confidence = analysis.Confidence
+		if steps := analysis.ReasoningSteps; len(steps) > 0 {
+			status.StepsCompleted = int32(len(steps))
+			status.CurrentStep = steps[len(steps)-1].Description
+		}
+	}
+	return progress, finish
+}
+
+// patchAIAnalysis writes the policy's status.aiAnalysis without the rest of
+// its in-memory status, if the stored policy is still at resourceVersion.
+// It returns the resource version of the patched policy.
+func (r *HealingPolicyReconciler) patchAIAnalysis(ctx context.Context, policy *v1alpha1.HealingPolicy, resourceVersion string) (string, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"resourceVersion": resourceVersion},
+		"status":   map[string]interface{}{"aiAnalysis": policy.Status.AIAnalysis},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	patched := policy.DeepCopy()
+	if err := r.Status().Patch(ctx, patched, client.RawPatch(k8stypes.MergePatchType, data)); err != nil {
+		return "", err
+	}
+	return patched.ResourceVersion, nil
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// streamingAnalyzer reports canned progress before returning its analysis,
+// reading the policy's stored status after each update
+type streamingAnalyzer struct {
+	recordingAnalyzer
+	progress []ktypes.AIProgress
+	analysis *ktypes.AIAnalysis
+	err      error
+
+	read   func() *v1alpha1.AIAnalysisStatus
+	stored []*v1alpha1.AIAnalysisStatus
+}
+
+func (a *streamingAnalyzer) AnalyzeClusterStateStreaming(ctx context.Context, metrics *ktypes.ClusterMetrics, issues []ktypes.Issue, progress func(ktypes.AIProgress)) (*ktypes.AIAnalysis, error) {
+	for _, update := range a.progress {
+		progress(update)
+		a.stored = append(a.stored, a.read())
+	}
+	return a.analysis, a.err
+}
+
+func TestGetAIRecommendationsReportsProgress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).WithStatusSubresource(policy).Build()
+	read := func() *v1alpha1.AIAnalysisStatus {
+		stored := &v1alpha1.HealingPolicy{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(policy), stored))
+		return stored.Status.AIAnalysis
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.AI.ProgressInterval = time.Hour
+
+	t.Run("completed", func(t *testing.T) {
+		analyzer := &streamingAnalyzer{
+			progress: []ktypes.AIProgress{
+				{CurrentStep: "Inspect restarts"},
+				{StepsCompleted: 1, CurrentStep: "Correlate with memory", Confidence: 0.6},
+			},
+			analysis: &ktypes.AIAnalysis{
+				Confidence:     0.9,
+				ReasoningSteps: []ktypes.ReasoningStep{{Description: "Inspect restarts"}, {Description: "Correlate with memory"}},
+			},
+			read: read,
+		}
+		r := &HealingPolicyReconciler{Client: c, Config: cfg, AIAnalyzer: analyzer}
+
+		_, err := r.getAIRecommendations(context.Background(), policy, &ktypes.ClusterMetrics{}, nil)
+		require.NoError(t, err)
+
+		// The first update is stored right away, later ones once per interval
+		require.Len(t, analyzer.stored, 2)
+		require.NotNil(t, analyzer.stored[0])
+		assert.Equal(t, v1alpha1.AIAnalysisPhaseRunning, analyzer.stored[0].Phase)
+		assert.Equal(t, "Inspect restarts", analyzer.stored[0].CurrentStep)
+		assert.Equal(t, analyzer.stored[0], analyzer.stored[1])
+
+		status := policy.Status.AIAnalysis
+		assert.Equal(t, v1alpha1.AIAnalysisPhaseCompleted, status.Phase)
+		assert.Equal(t, int32(2), status.StepsCompleted)
+		assert.Equal(t, 0.9, status.Confidence)
+
+		// The reconcile's status update does not conflict with the patches
+		require.NoError(t, c.Status().Update(context.Background(), policy))
+		assert.Equal(t, v1alpha1.AIAnalysisPhaseCompleted, read().Phase)
+	})
+
+	t.Run("concurrent write", func(t *testing.T) {
+		current := &v1alpha1.HealingPolicy{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(policy), current))
+		analyzer := &streamingAnalyzer{
+			progress: []ktypes.AIProgress{{CurrentStep: "Inspect restarts"}},
+			analysis: &ktypes.AIAnalysis{Confidence: 0.9},
+			read:     read,
+		}
+		r := &HealingPolicyReconciler{Client: c, Config: cfg, AIAnalyzer: analyzer}
+
+		// Someone else updates the policy after the reconcile read it
+		other := current.DeepCopy()
+		other.Spec.Paused = true
+		require.NoError(t, c.Update(context.Background(), other))
+
+		_, err := r.getAIRecommendations(context.Background(), current, &ktypes.ClusterMetrics{}, nil)
+		require.NoError(t, err)
+
+		// Progress is not written over the change, and the reconcile's
+		// update conflicts instead of overwriting it
+		assert.NotEqual(t, v1alpha1.AIAnalysisPhaseRunning, analyzer.stored[0].Phase)
+		err = c.Status().Update(context.Background(), current)
+		assert.True(t, apierrors.IsConflict(err))
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		analyzer := &streamingAnalyzer{err: errors.New("model unavailable"), read: read}
+		r := &HealingPolicyReconciler{Client: c, Config: cfg, AIAnalyzer: analyzer}
+
+		_, err := r.getAIRecommendations(context.Background(), policy, &ktypes.ClusterMetrics{}, nil)
+		require.Error(t, err)
+		assert.Equal(t, v1alpha1.AIAnalysisPhaseFailed, policy.Status.AIAnalysis.Phase)
+		assert.Equal(t, "model unavailable", policy.Status.AIAnalysis.Message)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := config.NewDefaultConfig()
+		disabled.AI.ProgressInterval = 0
+		analyzer := &streamingAnalyzer{read: read}
+		r := &HealingPolicyReconciler{Client: c, Config: disabled, AIAnalyzer: analyzer}
+		fresh := policy.DeepCopy()
+		fresh.Status.AIAnalysis = nil
+
+		_, err := r.getAIRecommendations(context.Background(), fresh, &ktypes.ClusterMetrics{}, nil)
+		require.NoError(t, err)
+		assert.Len(t, analyzer.calls, 1)
+		assert.Nil(t, fresh.Status.AIAnalysis)
+	})
+}
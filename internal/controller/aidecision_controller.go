@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// decisionRecheckInterval is how often pending decisions check that their
+// HealingAction still exists
+const decisionRecheckInterval = time.Hour
+
+// AIDecisionReconciler deletes AIDecisions once their TTL has passed
+type AIDecisionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=aidecisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=aidecisions/status,verbs=get;update;patch
+
+// Reconcile expires finished decisions and closes decisions whose
+// HealingAction was deleted before it finished
+func (r *AIDecisionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	decision := &v1alpha1.AIDecision{}
+	if err := r.Get(ctx, req.NamespacedName, decision); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	patcher := newStatusPatcher(r.Client, "aidecision", decision)
+
+	if decision.Status.CompletionTime == nil {
+		action := &v1alpha1.HealingAction{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: decision.Namespace, Name: decision.Spec.HealingActionRef}, action)
+		if err == nil {
+			return ctrl.Result{RequeueAfter: decisionRecheckInterval}, nil
+		}
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		// The action is gone, so its outcome will never be reported
+		now := metav1.Now()
+		decision.Status.Phase = v1alpha1.AIDecisionPhaseCancelled
+		decision.Status.ActualOutcome = "HealingAction was deleted before it finished"
+		decision.Status.CompletionTime = &now
+		if err := patcher.Patch(ctx, decision); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	ttl := decision.Spec.TTL.Duration
+	if ttl <= 0 {
+		ttl = defaultDecisionTTL
+	}
+	if wait := time.Until(decision.Status.CompletionTime.Add(ttl)); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	log.Info("Deleting expired AIDecision", "ttl", ttl)
+	if err := r.Delete(ctx, decision); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *AIDecisionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AIDecision{}).
+		Complete(r)
+}
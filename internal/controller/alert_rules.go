@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/alerting"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+
+// syncAlertRules keeps the policy's PrometheusRule in line with its
+// Prometheus metric triggers, deleting it once there are none. It does
+// nothing unless alert rule generation is enabled.
+func (r *HealingPolicyReconciler) syncAlertRules(ctx context.Context, policy *v1alpha1.HealingPolicy) error {
+	if r.Config == nil || !r.Config.Metrics.AlertRules.Enabled {
+		return nil
+	}
+
+	desired, err := alerting.GenerateRule(policy, alerting.Options{
+		Severity: r.Config.Metrics.AlertRules.Severity,
+		Labels:   r.Config.Metrics.AlertRules.Labels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate alert rules: %w", err)
+	}
+
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(alerting.PrometheusRuleGVK)
+	rule.SetName(alerting.RuleName(policy))
+	rule.SetNamespace(policy.Namespace)
+
+	if desired == nil {
+		err := r.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete PrometheusRule %s: %w", rule.GetName(), err)
+		}
+		return nil
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		rule.SetLabels(desired.GetLabels())
+		rule.Object["spec"] = desired.Object["spec"]
+		return controllerutil.SetControllerReference(policy, rule, r.Scheme)
+	}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return fmt.Errorf("PrometheusRule CRD is not installed: %w", err)
+		}
+		return fmt.Errorf("failed to apply PrometheusRule %s: %w", client.ObjectKeyFromObject(rule), err)
+	}
+	return nil
+}
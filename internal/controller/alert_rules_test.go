@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/alerting"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestSyncAlertRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	scheme.AddKnownTypeWithName(alerting.PrometheusRuleGVK, &unstructured.Unstructured{})
+	listGVK := alerting.PrometheusRuleGVK
+	listGVK.Kind += "List"
+	scheme.AddKnownTypeWithName(listGVK, &unstructured.UnstructuredList{})
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "policy-uid"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{{
+				Name: "errors",
+				Type: "metric",
+				MetricTrigger: &v1alpha1.MetricTrigger{
+					Query: `sum(rate(errors_total{app="web"}[1m]))`, Threshold: 5, Operator: ">",
+				},
+			}},
+		},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Metrics.AlertRules.Enabled = true
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+	r := &HealingPolicyReconciler{Client: c, Scheme: scheme, Config: cfg}
+
+	getRule := func() (*unstructured.Unstructured, error) {
+		rule := &unstructured.Unstructured{}
+		rule.SetGroupVersionKind(alerting.PrometheusRuleGVK)
+		err := c.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: "kubeskippy-web"}, rule)
+		return rule, err
+	}
+
+	require.NoError(t, r.syncAlertRules(context.Background(), policy))
+	rule, err := getRule()
+	require.NoError(t, err)
+	require.Len(t, rule.GetOwnerReferences(), 1)
+	assert.Equal(t, "web", rule.GetOwnerReferences()[0].Name)
+	expr, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	assert.Contains(t, expr[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})["expr"], "> 5")
+
+	// Thresholds changes are applied to the existing rule
+	policy.Spec.Triggers[0].MetricTrigger.Threshold = 8
+	require.NoError(t, r.syncAlertRules(context.Background(), policy))
+	rule, err = getRule()
+	require.NoError(t, err)
+	expr, _, _ = unstructured.NestedSlice(rule.Object, "spec", "groups")
+	assert.Contains(t, expr[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})["expr"], "> 8")
+
+	// Removing the last Prometheus trigger removes the rule
+	policy.Spec.Triggers = nil
+	require.NoError(t, r.syncAlertRules(context.Background(), policy))
+	_, err = getRule()
+	assert.True(t, errors.IsNotFound(err))
+
+	// Disabled by default
+	r.Config = config.NewDefaultConfig()
+	assert.NoError(t, r.syncAlertRules(context.Background(), &v1alpha1.HealingPolicy{}))
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// defaultAttemptTimeout is used when neither the action nor the manager
+// configuration bound execution attempts
+const defaultAttemptTimeout = 5 * time.Minute
+
+// attemptDeadline returns when the executor of the next attempt is
+// cancelled: when the attempt runs out, or the action's timeout passes if
+// that is earlier
+func (r *HealingActionReconciler) attemptDeadline(action *v1alpha1.HealingAction) time.Time {
+	timeout := action.Spec.AttemptTimeout.Duration
+	if timeout <= 0 && r.Config != nil {
+		timeout = r.Config.Remediation.AttemptTimeout
+	}
+	if timeout <= 0 {
+		timeout = defaultAttemptTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	if action.Status.StartTime != nil && action.Spec.Timeout.Duration > 0 {
+		if end := action.Status.StartTime.Add(action.Spec.Timeout.Duration); end.Before(deadline) {
+			deadline = end
+		}
+	}
+	return deadline
+}
+
+// actionTimedOut reports whether the action ran past its timeout
+func actionTimedOut(action *v1alpha1.HealingAction) bool {
+	return action.Status.StartTime != nil && time.Since(action.Status.StartTime.Time) > action.Spec.Timeout.Duration
+}
+
+// failTimedOut fails an action that ran past its timeout, keeping the
+// changes of an attempt it stopped
+func (r *HealingActionReconciler) failTimedOut(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction, result *types.ActionResult) (ctrl.Result, error) {
+	action.SetPhase(v1alpha1.HealingActionPhaseFailed, "Timeout", "Action execution timed out")
+	action.Status.Result = &v1alpha1.ActionResult{
+		Success:       false,
+		Message:       "Action timed out",
+		Error:         fmt.Sprintf("Exceeded timeout of %v", action.Spec.Timeout.Duration),
+		FailureReason: v1alpha1.FailureReasonTimeout,
+	}
+	if result != nil {
+		action.Status.Result.Changes = result.Changes
+		action.Status.Result.Partial = result.Partial
+		action.Status.Result.Diagnostics = result.Diagnostics
+		action.Status.Result.Steps = result.Steps
+		if result.Partial {
+			action.Status.Result.Message = fmt.Sprintf("Action timed out after %d changes", len(result.Changes))
+		}
+	}
+	return r.completeAction(ctx, log, action)
+}
+
+// attemptCancelled reports whether an attempt's executor was cancelled
+// while the reconcile carried on, which happens when the action is
+// preempted during its execution
+func attemptCancelled(ctx context.Context, err error) bool {
+	return errors.Is(err, context.Canceled) && ctx.Err() == nil
+}
+
+// recordCancelledAttempt adds the changes made by a cancelled attempt to
+// the action that cancelled it, instead of overwriting its outcome
+func (r *HealingActionReconciler) recordCancelledAttempt(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction, result *types.ActionResult) (ctrl.Result, error) {
+	latest := &v1alpha1.HealingAction{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(action), latest); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !latest.IsComplete() {
+		// Cancelled by someone else, run the action again
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if result == nil || len(result.Changes) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Recording changes of the cancelled attempt", "changes", len(result.Changes))
+	if latest.Status.Result == nil {
+		latest.Status.Result = &v1alpha1.ActionResult{}
+	}
+	latest.Status.Result.Changes = result.Changes
+	latest.Status.Result.Partial = result.Partial
+	if err := r.Status().Update(ctx, latest); err != nil {
+		log.Error(err, "Failed to record changes of the cancelled attempt")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// partialResult records the progress of an attempt that stopped before it
+// completed and will be retried
+func partialResult(result *types.ActionResult, err error) *v1alpha1.ActionResult {
+	return &v1alpha1.ActionResult{
+		Success:       false,
+		Message:       result.Message,
+		Error:         err.Error(),
+		FailureReason: classifyFailure(err),
+		Changes:       result.Changes,
+		Partial:       true,
+		Diagnostics:   result.Diagnostics,
+		Steps:         result.Steps,
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestAttemptDeadline(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Remediation.AttemptTimeout = 2 * time.Minute
+	r := &HealingActionReconciler{Config: cfg}
+	started := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	action := &v1alpha1.HealingAction{
+		Spec:   v1alpha1.HealingActionSpec{Timeout: metav1.Duration{Duration: 10 * time.Minute}},
+		Status: v1alpha1.HealingActionStatus{StartTime: &started},
+	}
+
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), r.attemptDeadline(action), time.Second, "manager default")
+
+	action.Spec.AttemptTimeout = metav1.Duration{Duration: time.Minute}
+	assert.WithinDuration(t, time.Now().Add(time.Minute), r.attemptDeadline(action), time.Second, "action's own attempt timeout")
+
+	action.Spec.AttemptTimeout = metav1.Duration{Duration: time.Hour}
+	assert.WithinDuration(t, started.Add(10*time.Minute), r.attemptDeadline(action), time.Second, "capped by the action timeout")
+
+	assert.WithinDuration(t, time.Now().Add(defaultAttemptTimeout), (&HealingActionReconciler{}).attemptDeadline(&v1alpha1.HealingAction{}), time.Second)
+}
+
+func TestHealingActionReconciler_AttemptTimeout(t *testing.T) {
+	// The executor scales in steps and stops at the attempt's deadline
+	partialScale := func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+		<-ctx.Done()
+		return &ktypes.ActionResult{
+			Message: "Attempt stopped after 1 changes",
+			Changes: []v1alpha1.ResourceChange{{ResourceRef: "Deployment/apps/web", ChangeType: "scale", Field: "spec.replicas", OldValue: "2", NewValue: "3"}},
+			Partial: true,
+		}, fmt.Errorf("failed to scale: %w", ctx.Err())
+	}
+
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		started     time.Duration
+		maxAttempts int32
+		wantPhase   string
+		wantMessage string
+	}{
+		{
+			name:        "attempt timeout is retried",
+			timeout:     10 * time.Minute,
+			maxAttempts: 3,
+			wantPhase:   v1alpha1.HealingActionPhaseInProgress,
+			wantMessage: "Attempt stopped after 1 changes",
+		},
+		{
+			name:        "attempt timeout without retries left",
+			timeout:     10 * time.Minute,
+			maxAttempts: 1,
+			wantPhase:   v1alpha1.HealingActionPhaseFailed,
+			wantMessage: "Attempt stopped after 1 changes",
+		},
+		{
+			name:        "action timeout fails the action",
+			timeout:     time.Minute + 2*time.Second,
+			started:     time.Minute,
+			maxAttempts: 3,
+			wantPhase:   v1alpha1.HealingActionPhaseFailed,
+			wantMessage: "Action timed out after 1 changes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+			// Status times are stored with second precision
+			started := metav1.NewTime(time.Now().Truncate(time.Second).Add(-tt.started))
+			action := &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{Name: "scale-web", Namespace: "apps", Finalizers: []string{FinalizerName}},
+				Spec: v1alpha1.HealingActionSpec{
+					TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+					Action:         v1alpha1.HealingActionTemplate{Name: "scale", Type: "scale"},
+					Timeout:        metav1.Duration{Duration: tt.timeout},
+					AttemptTimeout: metav1.Duration{Duration: 50 * time.Millisecond},
+					RetryPolicy:    &v1alpha1.RetryPolicy{MaxAttempts: tt.maxAttempts, BackoffDelay: metav1.Duration{Duration: time.Second}, BackoffMultiplier: 2},
+				},
+				Status: v1alpha1.HealingActionStatus{Phase: v1alpha1.HealingActionPhaseInProgress, StartTime: &started},
+			}
+			if tt.started > 0 {
+				action.Spec.AttemptTimeout = metav1.Duration{Duration: time.Hour}
+			}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(action).WithStatusSubresource(action).Build()
+			r := &HealingActionReconciler{
+				Client:            c,
+				Scheme:            scheme,
+				Config:            config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{ExecuteActionFunc: partialScale},
+				SafetyController:  &MockSafetyController{},
+			}
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+			require.NoError(t, err)
+
+			got := &v1alpha1.HealingAction{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+			assert.Equal(t, tt.wantPhase, got.Status.Phase)
+			require.NotNil(t, got.Status.Result, "partial progress is recorded")
+			assert.True(t, got.Status.Result.Partial)
+			assert.Len(t, got.Status.Result.Changes, 1)
+			assert.Equal(t, tt.wantMessage, got.Status.Result.Message)
+			assert.Equal(t, v1alpha1.FailureReasonTimeout, got.Status.Result.FailureReason)
+		})
+	}
+}
+
+// A preempted action keeps the outcome set by the action preempting it,
+// plus the changes its cancelled executor made
+func TestHealingActionReconciler_CancelledAttempt(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	started := metav1.Now()
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart-web", Namespace: "apps", Finalizers: []string{FinalizerName}},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			Timeout:        metav1.Duration{Duration: 10 * time.Minute},
+			RetryPolicy:    &v1alpha1.RetryPolicy{MaxAttempts: 3, BackoffDelay: metav1.Duration{Duration: time.Second}, BackoffMultiplier: 2},
+		},
+		Status: v1alpha1.HealingActionStatus{Phase: v1alpha1.HealingActionPhaseInProgress, StartTime: &started},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(action).WithStatusSubresource(action).Build()
+
+	preempt := func(ctx context.Context, running *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+		victim := &v1alpha1.HealingAction{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(running), victim))
+		victim.SetPhase(v1alpha1.HealingActionPhaseCancelled, ReasonActionPreempted, "Preempted by kubeskippy/node-not-ready")
+		victim.Status.Result = &v1alpha1.ActionResult{Message: "Cancelled in favour of higher-priority action kubeskippy/node-not-ready"}
+		require.NoError(t, c.Status().Update(ctx, victim))
+
+		return &ktypes.ActionResult{
+			Changes: []v1alpha1.ResourceChange{{ResourceRef: "Pod/apps/web-1", ChangeType: "update", Field: "containers[web].image"}},
+			Partial: true,
+		}, fmt.Errorf("failed to restart containers: %w", context.Canceled)
+	}
+	r := &HealingActionReconciler{
+		Client:            c,
+		Scheme:            scheme,
+		Config:            config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{ExecuteActionFunc: preempt},
+		SafetyController:  &MockSafetyController{},
+	}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+	require.NoError(t, err)
+	assert.Zero(t, result, "the cancelled action is not retried")
+
+	got := &v1alpha1.HealingAction{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+	assert.Equal(t, v1alpha1.HealingActionPhaseCancelled, got.Status.Phase)
+	assert.Equal(t, "Cancelled in favour of higher-priority action kubeskippy/node-not-ready", got.Status.Result.Message)
+	assert.True(t, got.Status.Result.Partial)
+	assert.Len(t, got.Status.Result.Changes, 1)
+}
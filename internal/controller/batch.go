@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// defaultCreateBatchSize is used when no batch size is configured
+	defaultCreateBatchSize = 10
+
+	// defaultMaxActionsPerEvaluation is used when no per-evaluation limit
+	// is configured
+	defaultMaxActionsPerEvaluation = 5
+
+	// defaultCreateQPS is used when no creation QPS is configured
+	defaultCreateQPS = 5
+
+	// defaultCreateBurst is used when no creation burst is configured
+	defaultCreateBurst = 10
+)
+
+// BatchProgress reports the progress of a batched creation
+type BatchProgress struct {
+	Batch   int
+	Batches int
+	Total   int
+	Created int
+	Failed  int
+}
+
+// BatchCreator creates objects in rate-limited batches so a trigger matching
+// many targets does not flood the API server
+type BatchCreator struct {
+	client    client.Client
+	limiter   flowcontrol.RateLimiter
+	batchSize int
+}
+
+// NewBatchCreator creates a new batch creator. Zero values use defaults.
+func NewBatchCreator(c client.Client, qps float32, burst, batchSize int) *BatchCreator {
+	if qps <= 0 {
+		qps = defaultCreateQPS
+	}
+	if burst <= 0 {
+		burst = defaultCreateBurst
+	}
+	if batchSize <= 0 {
+		batchSize = defaultCreateBatchSize
+	}
+	return &BatchCreator{
+		client:    c,
+		limiter:   flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		batchSize: batchSize,
+	}
+}
+
+// Create creates the objects and returns one error per object (nil on
+// success). The first object of each batch is checked with a server-side
+// dry-run; if the check fails, the whole batch is skipped since its objects
+// share a template. Objects within a batch are created concurrently subject
+// to the QPS limit, and progress is called after each batch.
+func (b *BatchCreator) Create(ctx context.Context, objs []client.Object, progress func(BatchProgress)) []error {
+	errs := make([]error, len(objs))
+	batches := (len(objs) + b.batchSize - 1) / b.batchSize
+	state := BatchProgress{Batches: batches, Total: len(objs)}
+
+	for start := 0; start < len(objs); start += b.batchSize {
+		end := start + b.batchSize
+		if end > len(objs) {
+			end = len(objs)
+		}
+		batch := objs[start:end]
+		state.Batch++
+
+		if err := b.precheck(ctx, batch[0]); err != nil {
+			for i := start; i < end; i++ {
+				errs[i] = err
+			}
+		} else {
+			var wg sync.WaitGroup
+			for i, obj := range batch {
+				wg.Add(1)
+				go func(i int, obj client.Object) {
+					defer wg.Done()
+					if err := b.limiter.Wait(ctx); err != nil {
+						errs[i] = fmt.Errorf("rate limiter: %w", err)
+						return
+					}
+					errs[i] = b.client.Create(ctx, obj)
+				}(start+i, obj)
+			}
+			wg.Wait()
+		}
+
+		for i := start; i < end; i++ {
+			if errs[i] != nil {
+				state.Failed++
+			} else {
+				state.Created++
+			}
+		}
+		if progress != nil {
+			progress(state)
+		}
+
+		if ctx.Err() != nil {
+			for i := end; i < len(objs); i++ {
+				errs[i] = ctx.Err()
+			}
+			break
+		}
+	}
+
+	return errs
+}
+
+// precheck validates an object with a server-side dry-run create
+func (b *BatchCreator) precheck(ctx context.Context, obj client.Object) error {
+	if err := b.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter: %w", err)
+	}
+	probe, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
+	}
+	if err := b.client.Create(ctx, probe, client.DryRunAll); err != nil {
+		return fmt.Errorf("dry-run pre-check failed: %w", err)
+	}
+	return nil
+}
+
+// createActions creates the planned actions in batches and reports progress
+// in the policy status. Progress is persisted between batches so long
+// creations are visible while they run; the final progress is written by
+// the caller with the rest of the evaluation result.
+func (r *HealingPolicyReconciler) createActions(ctx context.Context, log logr.Logger, policy *v1alpha1.HealingPolicy, actions []client.Object) []error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	r.creatorOnce.Do(func() {
+		if r.Config != nil {
+			cfg := r.Config.Remediation
+			r.creator = NewBatchCreator(r.Client, cfg.CreateQPS, cfg.CreateBurst, cfg.CreateBatchSize)
+		} else {
+			r.creator = NewBatchCreator(r.Client, 0, 0, 0)
+		}
+	})
+
+	progress := &v1alpha1.ActionCreationProgress{Planned: int32(len(actions))}
+
+	errs := r.creator.Create(ctx, actions, func(p BatchProgress) {
+		now := metav1.Now()
+		progress.Created = int32(p.Created)
+		progress.Failed = int32(p.Failed)
+		progress.Batch = int32(p.Batch)
+		progress.Batches = int32(p.Batches)
+		progress.UpdatedAt = &now
+
+		log.V(1).Info("Action creation progress", "batch", p.Batch, "batches", p.Batches,
+			"created", p.Created, "failed", p.Failed)
+
+		if p.Batch < p.Batches {
+			if err := r.patchActionCreation(ctx, policy, progress); err != nil {
+				log.V(1).Info("Failed to report action creation progress", "error", err.Error())
+			}
+		}
+	})
+
+	for i := len(errs) - 1; i >= 0; i-- {
+		if errs[i] != nil {
+			progress.LastError = errs[i].Error()
+			break
+		}
+	}
+	policy.Status.ActionCreation = progress
+	return errs
+}
+
+// patchActionCreation persists the action creation progress alone. It
+// patches a copy of the policy so the status changes of the evaluation,
+// which are written when it ends, are kept.
+func (r *HealingPolicyReconciler) patchActionCreation(ctx context.Context, policy *v1alpha1.HealingPolicy, progress *v1alpha1.ActionCreationProgress) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"actionCreation": progress},
+	})
+	if err != nil {
+		return err
+	}
+	return r.Status().Patch(ctx, policy.DeepCopy(), client.RawPatch(k8stypes.MergePatchType, data))
+}
+
+// actionBudget returns how many actions the evaluation may create: at most
+// maxActions, and no more than the policy's remaining hourly rate limit less
+// its actions that are not yet recorded against the limit. The rate limit
+// is checked once before the evaluation, so without the budget one
+// evaluation could create a whole batch past it.
+func (r *HealingPolicyReconciler) actionBudget(ctx context.Context, policy *v1alpha1.HealingPolicy, maxActions int) (int, error) {
+	budget, ok := r.SafetyController.(RateLimitBudget)
+	if !ok {
+		return maxActions, nil
+	}
+	remaining, err := budget.RemainingActions(ctx, policy)
+	if err != nil {
+		return 0, err
+	}
+
+	// Actions are recorded against the limit when they are executed
+	actions := &v1alpha1.HealingActionList{}
+	if err := r.List(ctx, actions, client.InNamespace(policy.Namespace),
+		client.MatchingLabels{LabelPolicyName: policy.Name}); err != nil {
+		return 0, fmt.Errorf("failed to list healing actions: %w", err)
+	}
+	for i := range actions.Items {
+		switch actions.Items[i].Status.Phase {
+		case "", v1alpha1.HealingActionPhasePending, v1alpha1.HealingActionPhaseApproved, v1alpha1.HealingActionPhaseInProgress:
+			remaining--
+		}
+	}
+
+	if remaining < 0 {
+		remaining = 0
+	}
+	if remaining < maxActions {
+		return remaining, nil
+	}
+	return maxActions, nil
+}
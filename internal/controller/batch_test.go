@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func testConfigMaps(prefix string, count int) []client.Object {
+	objs := make([]client.Object, count)
+	for i := range objs {
+		objs[i] = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", prefix, i),
+				Namespace: "default",
+				Labels:    map[string]string{"batch": prefix},
+			},
+		}
+	}
+	return objs
+}
+
+func TestBatchCreator_Create(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	var dryRuns int
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				createOpts := &client.CreateOptions{}
+				createOpts.ApplyOptions(opts)
+				if len(createOpts.DryRun) > 0 {
+					dryRuns++
+					if obj.GetLabels()["batch"] == "invalid" {
+						return errors.New("admission denied")
+					}
+					return nil
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	creator := NewBatchCreator(fakeClient, 1000, 1000, 10)
+
+	t.Run("creates in batches", func(t *testing.T) {
+		dryRuns = 0
+		var reports []BatchProgress
+		errs := creator.Create(context.Background(), testConfigMaps("valid", 25), func(p BatchProgress) {
+			reports = append(reports, p)
+		})
+
+		require.Len(t, errs, 25)
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 3, dryRuns, "one pre-check per batch")
+		require.Len(t, reports, 3)
+		assert.Equal(t, BatchProgress{Batch: 3, Batches: 3, Total: 25, Created: 25}, reports[2])
+
+		list := &corev1.ConfigMapList{}
+		require.NoError(t, fakeClient.List(context.Background(), list, client.MatchingLabels{"batch": "valid"}))
+		assert.Len(t, list.Items, 25)
+	})
+
+	t.Run("failed pre-check skips batch", func(t *testing.T) {
+		objs := append(testConfigMaps("invalid", 3), testConfigMaps("other", 2)...)
+		creator := NewBatchCreator(fakeClient, 1000, 1000, 3)
+
+		errs := creator.Create(context.Background(), objs, nil)
+		for i := 0; i < 3; i++ {
+			assert.ErrorContains(t, errs[i], "dry-run pre-check failed")
+		}
+		assert.NoError(t, errs[3])
+		assert.NoError(t, errs[4])
+
+		list := &corev1.ConfigMapList{}
+		require.NoError(t, fakeClient.List(context.Background(), list, client.MatchingLabels{"batch": "invalid"}))
+		assert.Empty(t, list.Items)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		errs := creator.Create(ctx, testConfigMaps("canceled", 12), nil)
+		for _, err := range errs {
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestHealingPolicyReconciler_createActions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(policy).
+		WithStatusSubresource(&v1alpha1.HealingPolicy{}).
+		Build()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Remediation.CreateQPS = 1000
+	cfg.Remediation.CreateBurst = 1000
+	cfg.Remediation.CreateBatchSize = 4
+	r := &HealingPolicyReconciler{Client: fakeClient, Scheme: scheme, Config: cfg}
+
+	stored := &v1alpha1.HealingPolicy{}
+	require.NoError(t, fakeClient.Get(context.Background(), NamespacedName(policy), stored))
+
+	errs := r.createActions(context.Background(), logr.Discard(), stored, testConfigMaps("action", 10))
+	require.Len(t, errs, 10)
+
+	progress := stored.Status.ActionCreation
+	require.NotNil(t, progress)
+	assert.Equal(t, int32(10), progress.Planned)
+	assert.Equal(t, int32(10), progress.Created)
+	assert.Equal(t, int32(3), progress.Batch)
+	assert.Equal(t, int32(3), progress.Batches)
+	assert.Empty(t, progress.LastError)
+
+	// Intermediate progress was persisted while batches were running
+	persisted := &v1alpha1.HealingPolicy{}
+	require.NoError(t, fakeClient.Get(context.Background(), NamespacedName(policy), persisted))
+	require.NotNil(t, persisted.Status.ActionCreation)
+	assert.Equal(t, int32(2), persisted.Status.ActionCreation.Batch)
+}
+
+// budgetSafetyController reports a fixed remaining hourly budget
+type budgetSafetyController struct {
+	MockSafetyController
+	remaining int
+}
+
+func (b *budgetSafetyController) RemainingActions(ctx context.Context, policy *v1alpha1.HealingPolicy) (int, error) {
+	return b.remaining, nil
+}
+
+func TestHealingPolicyReconciler_actionBudget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"}}
+	action := func(name, phase string) client.Object {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{LabelPolicyName: "policy"}},
+			Status:     v1alpha1.HealingActionStatus{Phase: phase},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		action("pending", v1alpha1.HealingActionPhasePending),
+		action("running", v1alpha1.HealingActionPhaseInProgress),
+		// Already recorded against the limit
+		action("verifying", v1alpha1.HealingActionPhaseVerifying),
+		action("done", v1alpha1.HealingActionPhaseSucceeded),
+	).Build()
+
+	tests := []struct {
+		name       string
+		safety     SafetyController
+		maxActions int
+		want       int
+	}{
+		{name: "no budget reported", safety: &MockSafetyController{}, maxActions: 5, want: 5},
+		{name: "per-evaluation limit", safety: &budgetSafetyController{remaining: 10}, maxActions: 5, want: 5},
+		{name: "hourly budget less unrecorded actions", safety: &budgetSafetyController{remaining: 5}, maxActions: 5, want: 3},
+		{name: "exhausted", safety: &budgetSafetyController{remaining: 1}, maxActions: 5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &HealingPolicyReconciler{Client: fakeClient, Scheme: scheme, SafetyController: tt.safety}
+			got, err := r.actionBudget(context.Background(), policy, tt.maxActions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
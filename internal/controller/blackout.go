@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// AnnotationBlackout names the release freeze an automatic action was
+	// turned into a dry-run by
+	AnnotationBlackout = "kubeskippy.io/blackout"
+
+	// ReasonBlackout is set on automatic actions held during a release
+	// freeze
+	ReasonBlackout = "Blackout"
+
+	// blackoutUnknown stands for the release freeze when the calendar
+	// cannot be read
+	blackoutUnknown = "unknown"
+)
+
+// activeBlackout returns the release freeze the calendar has now, if any.
+// When the calendar cannot be read a freeze is assumed, so automatic
+// healing does not run through a freeze it failed to see.
+func activeBlackout(ctx context.Context, calendar ReleaseCalendar) string {
+	if calendar == nil {
+		return ""
+	}
+	name, active, err := calendar.Blackout(ctx, time.Now())
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to look up release freezes, assuming one")
+		return blackoutUnknown
+	}
+	if !active {
+		return ""
+	}
+	return name
+}
+
+// activeBlackout returns the release freeze holding back the automatic
+// actions of the policy, if any
+func (r *HealingPolicyReconciler) activeBlackout(ctx context.Context, policy *v1alpha1.HealingPolicy) string {
+	if policy.Spec.Mode != "automatic" {
+		return ""
+	}
+	return activeBlackout(ctx, r.Calendar)
+}
+
+// heldByBlackout returns the release freeze holding back an action that
+// was created before the freeze started, if any. Only actions that run
+// without approval are held; an operator approving an action during a
+// freeze decided to run it.
+func (r *HealingActionReconciler) heldByBlackout(ctx context.Context, action *v1alpha1.HealingAction) string {
+	if action.Spec.DryRun || action.Spec.ApprovalRequired {
+		return ""
+	}
+	return activeBlackout(ctx, r.Calendar)
+}
+
+// holdBlackout keeps an automatic action that has not started pending
+// during a release freeze
+func (r *HealingActionReconciler) holdBlackout(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction, blackout string) (ctrl.Result, error) {
+	log.Info("Release freeze active, holding action", "blackout", blackout)
+
+	if cond := GetCondition(action.Status.Conditions, v1alpha1.ConditionTypeReady); cond == nil || cond.Reason != ReasonBlackout {
+		action.SetPhase(v1alpha1.HealingActionPhasePending, ReasonBlackout,
+			fmt.Sprintf("Action is held during release freeze %s", blackout))
+		if err := patchStatus(ctx, r.Client, action); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// fakeCalendar reports a fixed release freeze, or fails to
+type fakeCalendar struct {
+	blackout string
+	err      error
+}
+
+func (c *fakeCalendar) Blackout(ctx context.Context, t time.Time) (string, bool, error) {
+	return c.blackout, c.blackout != "", c.err
+}
+
+func TestActiveBlackout(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, activeBlackout(ctx, nil))
+	assert.Empty(t, activeBlackout(ctx, &fakeCalendar{}))
+	assert.Equal(t, "q4-release", activeBlackout(ctx, &fakeCalendar{blackout: "q4-release"}))
+	assert.Equal(t, blackoutUnknown, activeBlackout(ctx, &fakeCalendar{err: errors.New("forbidden")}),
+		"an unreadable calendar fails closed")
+
+	r := &HealingPolicyReconciler{Calendar: &fakeCalendar{err: errors.New("forbidden")}}
+	automatic := &v1alpha1.HealingPolicy{Spec: v1alpha1.HealingPolicySpec{Mode: "automatic"}}
+	manual := &v1alpha1.HealingPolicy{Spec: v1alpha1.HealingPolicySpec{Mode: "manual"}}
+	assert.Equal(t, blackoutUnknown, r.activeBlackout(ctx, automatic))
+	assert.Empty(t, r.activeBlackout(ctx, manual))
+}
+
+func TestHealingActionReconciler_BlackoutHold(t *testing.T) {
+	tests := []struct {
+		name             string
+		phase            string
+		dryRun           bool
+		approvalRequired bool
+		calendar         *fakeCalendar
+		expectedPhase    string
+		expectedReason   string
+	}{
+		{name: "pending action is held", phase: v1alpha1.HealingActionPhasePending,
+			calendar: &fakeCalendar{blackout: "q4-release"}, expectedPhase: v1alpha1.HealingActionPhasePending, expectedReason: ReasonBlackout},
+		{name: "approved action is held", phase: v1alpha1.HealingActionPhaseApproved,
+			calendar: &fakeCalendar{blackout: "q4-release"}, expectedPhase: v1alpha1.HealingActionPhasePending, expectedReason: ReasonBlackout},
+		{name: "unreadable calendar holds", phase: v1alpha1.HealingActionPhasePending,
+			calendar: &fakeCalendar{err: errors.New("forbidden")}, expectedPhase: v1alpha1.HealingActionPhasePending, expectedReason: ReasonBlackout},
+		{name: "dry-run action proceeds", phase: v1alpha1.HealingActionPhasePending, dryRun: true,
+			calendar: &fakeCalendar{blackout: "q4-release"}, expectedPhase: v1alpha1.HealingActionPhaseApproved},
+		{name: "action needing approval is not held", phase: v1alpha1.HealingActionPhasePending, approvalRequired: true,
+			calendar: &fakeCalendar{blackout: "q4-release"}, expectedPhase: v1alpha1.HealingActionPhasePending},
+		{name: "no freeze", phase: v1alpha1.HealingActionPhasePending,
+			calendar: &fakeCalendar{}, expectedPhase: v1alpha1.HealingActionPhaseApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+			policy := pausedPolicy(false)
+			action := &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-action",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef:        v1alpha1.PolicyReference{Name: policy.Name, Namespace: policy.Namespace},
+					Action:           v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+					DryRun:           tt.dryRun,
+					ApprovalRequired: tt.approvalRequired,
+				},
+				Status: v1alpha1.HealingActionStatus{Phase: tt.phase},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(policy, action).
+				WithStatusSubresource(policy, action).
+				Build()
+
+			executed := false
+			r := &HealingActionReconciler{
+				Client: fakeClient,
+				Scheme: scheme,
+				Config: config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{
+					ExecuteActionFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+						executed = true
+						return &ktypes.ActionResult{Success: true}, nil
+					},
+				},
+				SafetyController: &MockSafetyController{},
+				Calendar:         tt.calendar,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: action.Name, Namespace: action.Namespace}}
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			updated := &v1alpha1.HealingAction{}
+			require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, updated))
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			if tt.expectedReason != "" {
+				assert.False(t, executed)
+				cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeReady)
+				require.NotNil(t, cond)
+				assert.Equal(t, tt.expectedReason, cond.Reason)
+			}
+		})
+	}
+}
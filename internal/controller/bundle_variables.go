@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// bundleVariableRef matches a $(name) variable reference
+var bundleVariableRef = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// resolveBundleVariables returns the values of the bundle's variables,
+// reading ConfigMap values as of now
+func resolveBundleVariables(ctx context.Context, c client.Reader, bundle *v1alpha1.HealingPolicyBundle) (map[string]string, error) {
+	values := make(map[string]string, len(bundle.Spec.Variables))
+	for _, variable := range bundle.Spec.Variables {
+		if _, duplicate := values[variable.Name]; duplicate {
+			return nil, fmt.Errorf("variable %q is defined more than once", variable.Name)
+		}
+		if variable.ValueFrom == nil {
+			values[variable.Name] = variable.Value
+			continue
+		}
+		if variable.Value != "" {
+			return nil, fmt.Errorf("variable %q sets both value and valueFrom", variable.Name)
+		}
+
+		ref := variable.ValueFrom.ConfigMapKeyRef
+		if ref == nil {
+			return nil, fmt.Errorf("variable %q has no value source", variable.Name)
+		}
+		optional := ref.Optional != nil && *ref.Optional
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: bundle.Namespace, Name: ref.Name}, cm); err != nil {
+			if errors.IsNotFound(err) && optional {
+				values[variable.Name] = ""
+				continue
+			}
+			return nil, fmt.Errorf("failed to read variable %q from ConfigMap %s: %w", variable.Name, ref.Name, err)
+		}
+		value, ok := cm.Data[ref.Key]
+		if !ok && !optional {
+			return nil, fmt.Errorf("variable %q: ConfigMap %s has no key %q", variable.Name, ref.Name, ref.Key)
+		}
+		values[variable.Name] = value
+	}
+	return values, nil
+}
+
+// renderBundledPolicy substitutes the variables into a bundled policy's
+// spec. A string that is only a reference takes the value as JSON when it
+// parses, so numbers, booleans and lists keep their type.
+func renderBundledPolicy(policy *v1alpha1.BundledPolicy, variables map[string]string) (*v1alpha1.HealingPolicySpec, error) {
+	var doc interface{}
+	if err := json.Unmarshal(policy.Spec.Raw, &doc); err != nil {
+		return nil, fmt.Errorf("policy %q: invalid spec: %w", policy.Name, err)
+	}
+
+	undefined := make(map[string]bool)
+	doc = substituteVariables(doc, variables, undefined)
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("policy %q references undefined variables: %s", policy.Name, strings.Join(names, ", "))
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("policy %q: %w", policy.Name, err)
+	}
+	spec := &v1alpha1.HealingPolicySpec{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, fmt.Errorf("policy %q: invalid spec after substituting variables: %w", policy.Name, err)
+	}
+	return spec, nil
+}
+
+// substituteVariables replaces the variable references in a decoded JSON
+// document, recording the undefined ones
+func substituteVariables(value interface{}, variables map[string]string, undefined map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = substituteVariables(item, variables, undefined)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = substituteVariables(item, variables, undefined)
+		}
+	case string:
+		if match := bundleVariableRef.FindStringSubmatch(v); match != nil && match[0] == v {
+			resolved, ok := variables[match[1]]
+			if !ok {
+				undefined[match[1]] = true
+				return v
+			}
+			var typed interface{}
+			if err := json.Unmarshal([]byte(resolved), &typed); err == nil {
+				return typed
+			}
+			return resolved
+		}
+		return bundleVariableRef.ReplaceAllStringFunc(v, func(ref string) string {
+			name := bundleVariableRef.FindStringSubmatch(ref)[1]
+			resolved, ok := variables[name]
+			if !ok {
+				undefined[name] = true
+				return ref
+			}
+			return resolved
+		})
+	}
+	return value
+}
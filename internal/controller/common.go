@@ -3,11 +3,14 @@ package controller
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -28,6 +31,8 @@ const (
 	LabelActionName  = "kubeskippy.io/action-name"
 	LabelActionType  = "kubeskippy.io/action-type"
 	LabelActionPhase = "kubeskippy.io/action-phase"
+	LabelAIDriven    = "kubeskippy.io/ai-driven"
+	LabelTriggerName = "trigger-type"
 
 	// Finalizer
 	FinalizerName = "kubeskippy.io/finalizer"
@@ -56,8 +61,8 @@ func NewPolicyMatcher(policy *v1alpha1.HealingPolicy) *PolicyMatcher {
 
 // Matches checks if a resource matches the policy selector
 func (pm *PolicyMatcher) Matches(obj client.Object) (bool, error) {
-	// Check namespace
-	if len(pm.policy.Spec.Selector.Namespaces) > 0 {
+	// Check namespace, which cluster-scoped resources like nodes do not have
+	if len(pm.policy.Spec.Selector.Namespaces) > 0 && obj.GetNamespace() != "" {
 		found := false
 		for _, ns := range pm.policy.Spec.Selector.Namespaces {
 			if obj.GetNamespace() == ns {
@@ -70,6 +75,11 @@ func (pm *PolicyMatcher) Matches(obj client.Object) (bool, error) {
 		}
 	}
 
+	// Check exclusions before the more expensive label selectors
+	if excluded, err := pm.isExcluded(obj); err != nil || excluded {
+		return false, err
+	}
+
 	// Check labels
 	if pm.policy.Spec.Selector.LabelSelector != nil {
 		selector, err := metav1.LabelSelectorAsSelector(pm.policy.Spec.Selector.LabelSelector)
@@ -89,10 +99,8 @@ func (pm *PolicyMatcher) Matches(obj client.Object) (bool, error) {
 	for _, rf := range pm.policy.Spec.Selector.Resources {
 		if rf.APIVersion == apiVersion && rf.Kind == kind {
 			// Check exclude names
-			for _, exclude := range rf.ExcludeNames {
-				if obj.GetName() == exclude {
-					return false, nil
-				}
+			if excluded, err := matchesAnyGlob(rf.ExcludeNames, obj.GetName()); err != nil || excluded {
+				return false, err
 			}
 			found = true
 			break
@@ -102,6 +110,47 @@ func (pm *PolicyMatcher) Matches(obj client.Object) (bool, error) {
 	return found, nil
 }
 
+// isExcluded checks the selector's namespace, name and label exclusions
+func (pm *PolicyMatcher) isExcluded(obj client.Object) (bool, error) {
+	selector := pm.policy.Spec.Selector
+
+	if excluded, err := matchesAnyGlob(selector.ExcludeNamespaces, obj.GetNamespace()); err != nil || excluded {
+		return excluded, err
+	}
+
+	if excluded, err := matchesAnyGlob(selector.ExcludeNames, obj.GetName()); err != nil || excluded {
+		return excluded, err
+	}
+
+	if selector.ExcludeLabelSelector != nil {
+		exclude, err := metav1.LabelSelectorAsSelector(selector.ExcludeLabelSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid exclude label selector: %w", err)
+		}
+		// An empty selector matches everything; ignore it rather than
+		// excluding every resource
+		if !exclude.Empty() && exclude.Matches(labels.Set(obj.GetLabels())) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// matchesAnyGlob reports whether value matches any of the glob patterns
+func matchesAnyGlob(patterns []string, value string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, value)
+		if err != nil {
+			return false, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ResourceKey generates a unique key for a resource
 func ResourceKey(obj client.Object) string {
 	gvk := obj.GetObjectKind().GroupVersionKind()
@@ -189,10 +238,11 @@ func CreateHealingAction(
 				LabelActionName:  actionTemplate.Name,
 				LabelActionType:  actionTemplate.Type,
 				LabelActionPhase: v1alpha1.HealingActionPhasePending,
-				"trigger-type":   triggerType,
+				LabelTriggerName: triggerType,
 			},
 			Annotations: map[string]string{
-				AnnotationLastApplied: now.Format(time.RFC3339),
+				AnnotationLastApplied:                now.Format(time.RFC3339),
+				kubetypes.AnnotationPolicyGeneration: strconv.FormatInt(policy.Generation, 10),
 			},
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -220,12 +270,13 @@ func CreateHealingAction(
 			Action:           *actionTemplate,
 			ApprovalRequired: actionTemplate.RequiresApproval || policy.Spec.Mode == "manual",
 			DryRun:           dryRun || policy.Spec.Mode == "dryrun",
-			Timeout:          metav1.Duration{Duration: 10 * time.Minute},
+			Timeout:          metav1.Duration{Duration: v1alpha1.DefaultActionTimeout},
 			RetryPolicy: &v1alpha1.RetryPolicy{
-				MaxAttempts:       3,
-				BackoffDelay:      metav1.Duration{Duration: 30 * time.Second},
-				BackoffMultiplier: 2.0,
+				MaxAttempts:       v1alpha1.DefaultRetryMaxAttempts,
+				BackoffDelay:      metav1.Duration{Duration: v1alpha1.DefaultRetryBackoffDelay},
+				BackoffMultiplier: v1alpha1.DefaultRetryBackoffMultiplier,
 			},
+			BlastRadiusLimits: policy.Spec.SafetyRules.BlastRadius.DeepCopy(),
 		},
 		Status: v1alpha1.HealingActionStatus{
 			Phase:              v1alpha1.HealingActionPhasePending,
@@ -233,6 +284,27 @@ func CreateHealingAction(
 		},
 	}
 
+	// Policies may override the action and attempt timeouts and retry policy
+	if policy.Spec.ActionTimeout != nil {
+		action.Spec.Timeout = *policy.Spec.ActionTimeout
+	}
+	if policy.Spec.AttemptTimeout != nil {
+		action.Spec.AttemptTimeout = *policy.Spec.AttemptTimeout
+	}
+	if policy.Spec.RetryPolicy != nil {
+		action.Spec.RetryPolicy = policy.Spec.RetryPolicy.DeepCopy()
+	}
+
+	// Carry the related issue so completion summaries can reference it
+	if issue := policy.Annotations[kubetypes.AnnotationIssue]; issue != "" {
+		action.Annotations[kubetypes.AnnotationIssue] = issue
+	}
+
+	// Carry the cooldown group the safety controller holds related actions by
+	if group := policy.Labels[kubetypes.LabelCooldownGroup]; group != "" {
+		action.Labels[kubetypes.LabelCooldownGroup] = group
+	}
+
 	// Initialize approval status if required
 	if action.Spec.ApprovalRequired {
 		action.Status.Approval = &v1alpha1.ApprovalStatus{
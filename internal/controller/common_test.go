@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
 )
 
 func TestPolicyMatcher_Matches(t *testing.T) {
@@ -74,6 +75,32 @@ func TestPolicyMatcher_Matches(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "namespaces do not filter nodes",
+			policy: &v1alpha1.HealingPolicy{
+				Spec: v1alpha1.HealingPolicySpec{
+					Selector: v1alpha1.ResourceSelector{
+						Namespaces: []string{"production"},
+						Resources: []v1alpha1.ResourceFilter{
+							{
+								APIVersion: "v1",
+								Kind:       "Node",
+							},
+						},
+					},
+				},
+			},
+			object: &corev1.Node{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Node",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "worker-1",
+				},
+			},
+			expected: true,
+		},
 		{
 			name: "matches label selector",
 			policy: &v1alpha1.HealingPolicy{
@@ -147,6 +174,93 @@ func TestPolicyMatcher_Matches(t *testing.T) {
 	}
 }
 
+func TestPolicyMatcher_Exclusions(t *testing.T) {
+	pod := func(namespace, name string, labels map[string]string) client.Object {
+		return &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		}
+	}
+
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Selector: v1alpha1.ResourceSelector{
+				Resources: []v1alpha1.ResourceFilter{
+					{APIVersion: "v1", Kind: "Pod", ExcludeNames: []string{"debug-*"}},
+				},
+				ExcludeNamespaces: []string{"kube-*", "cert-manager"},
+				ExcludeNames:      []string{"*-canary"},
+				ExcludeLabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "workload-type", Operator: metav1.LabelSelectorOpIn, Values: []string{"batch"}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		object   client.Object
+		expected bool
+	}{
+		{
+			name:     "included",
+			object:   pod("default", "web-1", map[string]string{"app": "web"}),
+			expected: true,
+		},
+		{
+			name:     "excluded namespace glob",
+			object:   pod("kube-system", "coredns-1", nil),
+			expected: false,
+		},
+		{
+			name:     "excluded namespace",
+			object:   pod("cert-manager", "cert-manager-1", nil),
+			expected: false,
+		},
+		{
+			name:     "excluded name glob",
+			object:   pod("default", "web-canary", nil),
+			expected: false,
+		},
+		{
+			name:     "excluded resource filter name glob",
+			object:   pod("default", "debug-shell", nil),
+			expected: false,
+		},
+		{
+			name:     "excluded labels",
+			object:   pod("default", "report-1", map[string]string{"workload-type": "batch"}),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher := NewPolicyMatcher(policy)
+			result, err := matcher.Matches(tt.object)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		invalid := policy.DeepCopy()
+		invalid.Spec.Selector.ExcludeNames = []string{"[web"}
+		_, err := NewPolicyMatcher(invalid).Matches(pod("default", "web-1", nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty exclude label selector excludes nothing", func(t *testing.T) {
+		empty := policy.DeepCopy()
+		empty.Spec.Selector.ExcludeLabelSelector = &metav1.LabelSelector{}
+		result, err := NewPolicyMatcher(empty).Matches(pod("default", "web-1", nil))
+		require.NoError(t, err)
+		assert.True(t, result)
+	})
+}
+
 func TestIsProtectedResource(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -371,6 +485,12 @@ func TestCreateHealingAction(t *testing.T) {
 	assert.False(t, action.Spec.DryRun)
 	assert.NotNil(t, action.Spec.RetryPolicy)
 	assert.Equal(t, int32(3), action.Spec.RetryPolicy.MaxAttempts)
+	assert.NotContains(t, action.Labels, kubetypes.LabelCooldownGroup)
+
+	// The policy's cooldown group is carried to its actions
+	policy.Labels = map[string]string{kubetypes.LabelCooldownGroup: "shop"}
+	action = CreateHealingAction(policy, target, actionTemplate, false, "test-trigger")
+	assert.Equal(t, "shop", action.Labels[kubetypes.LabelCooldownGroup])
 }
 
 func TestHealingActionHelpers(t *testing.T) {
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// ReasonDefaultsChanged is set when defaults recorded at admission differ
+	// from the defaults of the running operator
+	ReasonDefaultsChanged = "DefaultsChanged"
+
+	// ReasonDefaultsCurrent clears a previous DefaultsDrifted condition
+	ReasonDefaultsCurrent = "DefaultsCurrent"
+)
+
+// defaultsDrift lists the recorded defaults that differ from the current
+// ones as "key: recorded -> current"
+func defaultsDrift(policy *v1alpha1.HealingPolicy) []string {
+	current := v1alpha1.PolicyDefaults()
+	var drift []string
+	for key, recorded := range policy.AppliedDefaults() {
+		if value, ok := current[key]; ok && value != recorded {
+			drift = append(drift, fmt.Sprintf("%s: %s -> %s", key, recorded, value))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// setDefaultsDriftCondition warns when behavior-affecting defaults changed
+// since the policy was admitted. The policy keeps the recorded values until
+// the fields are updated.
+func setDefaultsDriftCondition(policy *v1alpha1.HealingPolicy) {
+	drift := defaultsDrift(policy)
+	if len(drift) == 0 {
+		if cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeDefaultsDrifted); cond != nil && cond.Status == metav1.ConditionTrue {
+			SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeDefaultsDrifted,
+				metav1.ConditionFalse, ReasonDefaultsCurrent, "Recorded defaults match the operator defaults")
+		}
+		return
+	}
+
+	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeDefaultsDrifted,
+		metav1.ConditionTrue, ReasonDefaultsChanged,
+		fmt.Sprintf("Operator defaults changed since admission, the policy keeps its recorded values: %s",
+			strings.Join(drift, "; ")))
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestSetDefaultsDriftCondition(t *testing.T) {
+	current := v1alpha1.PolicyDefaults()
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				v1alpha1.AnnotationAppliedDefaults: `{"actionTimeout":"` + current[v1alpha1.DefaultKeyActionTimeout] + `"}`,
+			},
+		},
+	}
+
+	// Recorded defaults match the operator defaults
+	setDefaultsDriftCondition(policy)
+	assert.Nil(t, GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeDefaultsDrifted))
+
+	// A default recorded by an older operator version
+	policy.Annotations[v1alpha1.AnnotationAppliedDefaults] = `{"actionTimeout":"20m0s","triggers.cooldownPeriod":"` +
+		current[v1alpha1.DefaultKeyCooldownPeriod] + `","removedKey":"x"}`
+	setDefaultsDriftCondition(policy)
+
+	cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeDefaultsDrifted)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReasonDefaultsChanged, cond.Reason)
+	assert.Contains(t, cond.Message, "actionTimeout: 20m0s -> "+current[v1alpha1.DefaultKeyActionTimeout])
+	assert.NotContains(t, cond.Message, "cooldownPeriod")
+	assert.NotContains(t, cond.Message, "removedKey")
+
+	// Updating the recorded defaults clears the condition
+	policy.Annotations[v1alpha1.AnnotationAppliedDefaults] = `{}`
+	setDefaultsDriftCondition(policy)
+	cond = GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeDefaultsDrifted)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// ReasonDependenciesPending is set while a policy waits for the
+	// policies it depends on
+	ReasonDependenciesPending = "DependenciesPending"
+
+	// ReasonDependenciesSettled is set once the policies a policy depends
+	// on were evaluated and their actions finished
+	ReasonDependenciesSettled = "DependenciesSettled"
+
+	// ReasonDependencyCycle is set when dependsOn leads back to the policy
+	ReasonDependencyCycle = "DependencyCycle"
+
+	// dependencyRecheckInterval is how often a waiting policy checks its
+	// dependencies again
+	dependencyRecheckInterval = 15 * time.Second
+
+	// maxDependencyWait bounds how long a policy is held by dependencies
+	// that do not settle, so a failing dependency cannot stop healing
+	maxDependencyWait = 10 * time.Minute
+)
+
+// dependencyKey returns the namespaced name of a dependency
+func dependencyKey(policy *v1alpha1.HealingPolicy, dep v1alpha1.PolicyDependency) k8stypes.NamespacedName {
+	namespace := dep.Namespace
+	if namespace == "" {
+		namespace = policy.Namespace
+	}
+	return k8stypes.NamespacedName{Namespace: namespace, Name: dep.Name}
+}
+
+// findDependencyCycle follows dependsOn from the policy and returns the
+// path back to it, or nil if there is none. Missing policies end a path.
+func findDependencyCycle(ctx context.Context, c client.Reader, policy *v1alpha1.HealingPolicy) ([]string, error) {
+	start := client.ObjectKeyFromObject(policy)
+	visited := map[k8stypes.NamespacedName]bool{start: true}
+
+	var walk func(current *v1alpha1.HealingPolicy, path []string) ([]string, error)
+	walk = func(current *v1alpha1.HealingPolicy, path []string) ([]string, error) {
+		for _, dep := range current.Spec.DependsOn {
+			key := dependencyKey(current, dep)
+			next := append(path[:len(path):len(path)], key.String())
+			if key == start {
+				return next, nil
+			}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			dependency := &v1alpha1.HealingPolicy{}
+			if err := c.Get(ctx, key, dependency); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get policy %s: %w", key, err)
+			}
+			if cycle, err := walk(dependency, next); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return walk(policy, []string{start.String()})
+}
+
+// unsettledDependencies describes each dependency that has not been
+// evaluated since the policy's last evaluation or still has actions
+// running. Missing and paused dependencies do not hold the policy.
+func (r *HealingPolicyReconciler) unsettledDependencies(ctx context.Context, policy *v1alpha1.HealingPolicy) ([]string, error) {
+	var unsettled []string
+	for _, dep := range policy.Spec.DependsOn {
+		key := dependencyKey(policy, dep)
+		dependency := &v1alpha1.HealingPolicy{}
+		if err := r.Get(ctx, key, dependency); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get policy %s: %w", key, err)
+		}
+		if dependency.Spec.Paused {
+			continue
+		}
+
+		if dependency.Status.LastEvaluated.IsZero() || dependency.Status.LastEvaluated.Before(&policy.Status.LastEvaluated) {
+			unsettled = append(unsettled, fmt.Sprintf("%s has not been evaluated", key))
+			continue
+		}
+
+		running, err := runningActions(ctx, r.Client, dependency)
+		if err != nil {
+			return nil, err
+		}
+		if running > 0 {
+			unsettled = append(unsettled, fmt.Sprintf("%s has %d running actions", key, running))
+		}
+	}
+	return unsettled, nil
+}
+
+// runningActions counts the policy's actions that are executing or will
+// execute without further approval
+func runningActions(ctx context.Context, c client.Reader, policy *v1alpha1.HealingPolicy) (int, error) {
+	actions := &v1alpha1.HealingActionList{}
+	if err := c.List(ctx, actions, client.InNamespace(policy.Namespace),
+		client.MatchingLabels{LabelPolicyName: policy.Name}); err != nil {
+		return 0, fmt.Errorf("failed to list actions of policy %s/%s: %w", policy.Namespace, policy.Name, err)
+	}
+
+	running := 0
+	for _, action := range actions.Items {
+		switch action.Status.Phase {
+		case v1alpha1.HealingActionPhaseApproved, v1alpha1.HealingActionPhaseInProgress, v1alpha1.HealingActionPhaseVerifying:
+			running++
+		case "", v1alpha1.HealingActionPhasePending:
+			// Actions waiting for a human would hold dependents indefinitely
+			if !action.Spec.ApprovalRequired {
+				running++
+			}
+		}
+	}
+	return running, nil
+}
+
+// waitForDependencies reports whether the policy must wait for the
+// policies it depends on before it is evaluated, and sets the
+// DependenciesSettled condition accordingly
+func (r *HealingPolicyReconciler) waitForDependencies(ctx context.Context, log logr.Logger, policy *v1alpha1.HealingPolicy) (bool, error) {
+	if len(policy.Spec.DependsOn) == 0 {
+		return false, nil
+	}
+
+	unsettled, err := r.unsettledDependencies(ctx, policy)
+	if err != nil {
+		return false, err
+	}
+	if len(unsettled) == 0 {
+		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeDependenciesSettled,
+			metav1.ConditionTrue, ReasonDependenciesSettled, "All dependencies settled")
+		return false, nil
+	}
+
+	message := strings.Join(unsettled, "; ")
+	waitingSince := policy.Status.LastEvaluated
+	if waitingSince.IsZero() {
+		waitingSince = policy.CreationTimestamp
+	}
+	if time.Since(waitingSince.Time) > maxDependencyWait {
+		log.Info("Dependencies did not settle in time, evaluating anyway", "dependencies", message)
+		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeDependenciesSettled,
+			metav1.ConditionFalse, ReasonDependenciesPending,
+			fmt.Sprintf("Evaluated without waiting after %s: %s", maxDependencyWait, message))
+		return false, nil
+	}
+
+	log.Info("Waiting for dependencies", "dependencies", message)
+	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeDependenciesSettled,
+		metav1.ConditionFalse, ReasonDependenciesPending, message)
+	return true, nil
+}
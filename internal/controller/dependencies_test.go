@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func dependentPolicy(name string, lastEvaluated time.Time, dependsOn ...string) *v1alpha1.HealingPolicy {
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "default",
+			Finalizers: []string{FinalizerName},
+		},
+		Spec:   v1alpha1.HealingPolicySpec{Mode: "automatic"},
+		Status: v1alpha1.HealingPolicyStatus{LastEvaluated: metav1.NewTime(lastEvaluated)},
+	}
+	for _, dep := range dependsOn {
+		policy.Spec.DependsOn = append(policy.Spec.DependsOn, v1alpha1.PolicyDependency{Name: dep})
+	}
+	return policy
+}
+
+func policyAction(name, policy, phase string, approvalRequired bool) *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{LabelPolicyName: policy},
+		},
+		Spec:   v1alpha1.HealingActionSpec{ApprovalRequired: approvalRequired},
+		Status: v1alpha1.HealingActionStatus{Phase: phase},
+	}
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		policies []client.Object
+		want     []string
+	}{
+		{
+			name: "chain",
+			policies: []client.Object{
+				dependentPolicy("pods", now, "nodes"),
+				dependentPolicy("nodes", now, "storage"),
+				dependentPolicy("storage", now),
+			},
+		},
+		{
+			name:     "missing dependency",
+			policies: []client.Object{dependentPolicy("pods", now, "nodes")},
+		},
+		{
+			name: "cycle",
+			policies: []client.Object{
+				dependentPolicy("pods", now, "nodes"),
+				dependentPolicy("nodes", now, "storage"),
+				dependentPolicy("storage", now, "pods"),
+			},
+			want: []string{"default/pods", "default/nodes", "default/storage", "default/pods"},
+		},
+		{
+			name: "cycle not involving the policy",
+			policies: []client.Object{
+				dependentPolicy("pods", now, "nodes"),
+				dependentPolicy("nodes", now, "storage"),
+				dependentPolicy("storage", now, "nodes"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.policies...).Build()
+			cycle, err := findDependencyCycle(context.Background(), c, tt.policies[0].(*v1alpha1.HealingPolicy))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cycle)
+		})
+	}
+}
+
+func TestWaitForDependencies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	lastEvaluated := time.Now().Add(-time.Minute)
+
+	paused := dependentPolicy("nodes", lastEvaluated.Add(-time.Minute))
+	paused.Spec.Paused = true
+
+	tests := []struct {
+		name          string
+		lastEvaluated time.Time
+		objects       []client.Object
+		wantWait      bool
+		wantMessage   string
+	}{
+		{
+			name:        "dependency settled",
+			objects:     []client.Object{dependentPolicy("nodes", lastEvaluated.Add(30*time.Second))},
+			wantMessage: "All dependencies settled",
+		},
+		{
+			name:        "dependency not evaluated since",
+			objects:     []client.Object{dependentPolicy("nodes", lastEvaluated.Add(-time.Minute))},
+			wantWait:    true,
+			wantMessage: "default/nodes has not been evaluated",
+		},
+		{
+			name: "dependency actions running",
+			objects: []client.Object{
+				dependentPolicy("nodes", lastEvaluated.Add(30*time.Second)),
+				policyAction("drain", "nodes", v1alpha1.HealingActionPhaseInProgress, false),
+				policyAction("cordon", "nodes", v1alpha1.HealingActionPhasePending, false),
+				policyAction("old", "nodes", v1alpha1.HealingActionPhaseSucceeded, false),
+			},
+			wantWait:    true,
+			wantMessage: "default/nodes has 2 running actions",
+		},
+		{
+			name: "dependency actions awaiting approval",
+			objects: []client.Object{
+				dependentPolicy("nodes", lastEvaluated.Add(30*time.Second)),
+				policyAction("drain", "nodes", v1alpha1.HealingActionPhasePending, true),
+			},
+			wantMessage: "All dependencies settled",
+		},
+		{
+			name:        "paused dependency",
+			objects:     []client.Object{paused},
+			wantMessage: "All dependencies settled",
+		},
+		{
+			name:        "missing dependency",
+			wantMessage: "All dependencies settled",
+		},
+		{
+			name:          "waited too long",
+			lastEvaluated: time.Now().Add(-maxDependencyWait - time.Minute),
+			objects:       []client.Object{dependentPolicy("nodes", time.Now().Add(-time.Hour))},
+			wantMessage:   "Evaluated without waiting after 10m0s: default/nodes has not been evaluated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated := lastEvaluated
+			if !tt.lastEvaluated.IsZero() {
+				evaluated = tt.lastEvaluated
+			}
+			policy := dependentPolicy("pods", evaluated, "nodes")
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			r := &HealingPolicyReconciler{Client: c, Scheme: scheme}
+
+			wait, err := r.waitForDependencies(context.Background(), logr.Discard(), policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWait, wait)
+			cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeDependenciesSettled)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantMessage, cond.Message)
+		})
+	}
+}
+
+func TestHealingPolicyReconciler_DependsOn(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	lastEvaluated := time.Now().Add(-time.Minute)
+	nodes := dependentPolicy("nodes", lastEvaluated.Add(-time.Minute))
+	pods := dependentPolicy("pods", lastEvaluated, "nodes")
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(nodes, pods).
+		WithStatusSubresource(nodes, pods).
+		Build()
+
+	collected := map[string]int{}
+	r := &HealingPolicyReconciler{
+		Client: c,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		MetricsCollector: &MockMetricsCollector{
+			CollectMetricsFunc: func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error) {
+				collected[policy.Name]++
+				return &ktypes.ClusterMetrics{}, nil
+			},
+		},
+		SafetyController: &MockSafetyController{},
+	}
+	reconcilePolicy := func(policy *v1alpha1.HealingPolicy) reconcile.Result {
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+		require.NoError(t, err)
+		return result
+	}
+
+	// The pod-level policy waits for the node-level one
+	assert.Equal(t, dependencyRecheckInterval, reconcilePolicy(pods).RequeueAfter)
+	assert.Zero(t, collected["pods"])
+
+	// Once the node-level policy was evaluated it runs
+	reconcilePolicy(nodes)
+	assert.Equal(t, 1, collected["nodes"])
+	reconcilePolicy(pods)
+	assert.Equal(t, 1, collected["pods"])
+
+	updated := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pods), updated))
+	cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeDependenciesSettled)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+}
+
+func TestHealingPolicyReconciler_DependencyCycle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	pods := dependentPolicy("pods", time.Now(), "nodes")
+	nodes := dependentPolicy("nodes", time.Now(), "pods")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pods, nodes).WithStatusSubresource(pods, nodes).Build()
+	r := &HealingPolicyReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		Config:           config.NewDefaultConfig(),
+		MetricsCollector: &MockMetricsCollector{},
+		SafetyController: &MockSafetyController{},
+	}
+
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pods)})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, result.RequeueAfter)
+
+	updated := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pods), updated))
+	cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, ReasonDependencyCycle, cond.Reason)
+	assert.Equal(t, "Dependency cycle: default/pods -> default/nodes -> default/pods", cond.Message)
+}
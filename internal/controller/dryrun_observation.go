@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// ReasonDryRunObserved is recorded once the outcome for the target of a
+	// dry-run action was determined
+	ReasonDryRunObserved = "DryRunObserved"
+
+	// dryRunObservationPollInterval is how often the target of a dry-run
+	// action is checked
+	dryRunObservationPollInterval = time.Minute
+)
+
+// dryRunObservationWindow returns how long the targets of dry-run actions
+// are observed, 0 when observation is disabled
+func (r *HealingActionReconciler) dryRunObservationWindow() time.Duration {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.Remediation.DryRunObservationWindow
+}
+
+// startDryRunObservation starts watching the target of a completed dry run
+// for what happens to it without the action
+func startDryRunObservation(action *v1alpha1.HealingAction, window time.Duration) {
+	if !action.Spec.DryRun || window <= 0 {
+		return
+	}
+	action.Status.DryRunObservation = &v1alpha1.DryRunObservation{
+		ObserveUntil: metav1.NewTime(time.Now().Add(window)),
+	}
+}
+
+// isObservingDryRun reports whether a dry-run action's target is still
+// observed
+func isObservingDryRun(action *v1alpha1.HealingAction) bool {
+	return action.Status.DryRunObservation != nil && action.Status.DryRunObservation.ObservedAt == nil
+}
+
+// handleDryRunObservation checks what happened to the target of a dry-run
+// action: changed by someone else, recovered as the policy's triggers
+// stopped firing for it, or still failing once the window ends
+func (r *HealingActionReconciler) handleDryRunObservation(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	target, err := r.getTarget(ctx, action)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if target == nil {
+		return r.resolveDryRunObservation(ctx, log, action, v1alpha1.DryRunOutcomeInconclusive, "",
+			"the target was deleted")
+	}
+
+	if manager, changedAt, ok := lastForeignUpdate(target, action.CreationTimestamp.Time); ok {
+		return r.resolveDryRunObservation(ctx, log, action, v1alpha1.DryRunOutcomeIntervened, manager,
+			fmt.Sprintf("%s changed the target at %s", manager, changedAt.UTC().Format(time.RFC3339)))
+	}
+
+	policy := &v1alpha1.HealingPolicy{}
+	ref := action.Spec.PolicyRef
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, policy); err != nil {
+		if errors.IsNotFound(err) {
+			return r.resolveDryRunObservation(ctx, log, action, v1alpha1.DryRunOutcomeInconclusive, "",
+				"the policy was deleted")
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get policy %s: %w", ref.Name, err)
+	}
+	if !hasOpenIncident(policy, actionTarget(action)) {
+		return r.resolveDryRunObservation(ctx, log, action, v1alpha1.DryRunOutcomeRecovered, "",
+			"the policy's triggers stopped firing for the target without an action")
+	}
+
+	observation := action.Status.DryRunObservation
+	remaining := time.Until(observation.ObserveUntil.Time)
+	if remaining <= 0 {
+		return r.resolveDryRunObservation(ctx, log, action, v1alpha1.DryRunOutcomePersisted, "",
+			fmt.Sprintf("the policy's triggers still fire for the target %s after the dry run",
+				observation.ObserveUntil.Sub(action.CreationTimestamp.Time).Round(time.Second)))
+	}
+	if remaining > dryRunObservationPollInterval {
+		remaining = dryRunObservationPollInterval
+	}
+	return ctrl.Result{RequeueAfter: remaining}, nil
+}
+
+// resolveDryRunObservation records the outcome observed for the target
+func (r *HealingActionReconciler) resolveDryRunObservation(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction, outcome, manager, message string) (ctrl.Result, error) {
+	log.Info("Observed dry-run target", "outcome", outcome, "reason", message)
+
+	now := metav1.Now()
+	observation := action.Status.DryRunObservation
+	observation.ObservedAt = &now
+	observation.Outcome = outcome
+	observation.Manager = manager
+	observation.Message = message
+	if err := patchStatus(ctx, r.Client, action); err != nil {
+		log.Error(err, "Failed to update dry-run observation")
+		return ctrl.Result{}, err
+	}
+
+	r.recordEvent(action, corev1.EventTypeNormal, ReasonDryRunObserved,
+		fmt.Sprintf("Dry-run target %s: %s", outcome, message))
+	return ctrl.Result{}, nil
+}
+
+// actionTarget identifies an action's target like incidentTarget
+func actionTarget(action *v1alpha1.HealingAction) string {
+	ref := action.Spec.TargetResource
+	return fmt.Sprintf("%s/%s/%s", ref.Kind, ref.Namespace, ref.Name)
+}
+
+// hasOpenIncident reports whether any of the policy's triggers still fires
+// for the target
+func hasOpenIncident(policy *v1alpha1.HealingPolicy, target string) bool {
+	for _, incident := range policy.Status.Incidents {
+		if incident.Target == target {
+			return true
+		}
+	}
+	return false
+}
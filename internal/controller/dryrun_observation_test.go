@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestStartDryRunObservation(t *testing.T) {
+	action := &v1alpha1.HealingAction{Spec: v1alpha1.HealingActionSpec{DryRun: true}}
+	startDryRunObservation(action, 30*time.Minute)
+	require.NotNil(t, action.Status.DryRunObservation)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), action.Status.DryRunObservation.ObserveUntil.Time, 5*time.Second)
+	assert.True(t, isObservingDryRun(action))
+
+	// Executed actions changed their target, and observation can be disabled
+	executed := &v1alpha1.HealingAction{}
+	startDryRunObservation(executed, 30*time.Minute)
+	assert.Nil(t, executed.Status.DryRunObservation)
+	disabled := &v1alpha1.HealingAction{Spec: v1alpha1.HealingActionSpec{DryRun: true}}
+	startDryRunObservation(disabled, 0)
+	assert.Nil(t, disabled.Status.DryRunObservation)
+}
+
+func TestHealingActionReconciler_DryRunObservation(t *testing.T) {
+	created := time.Now().Add(-10 * time.Minute)
+	changed := metav1.NewTime(created.Add(5 * time.Minute))
+	openIncident := []v1alpha1.Incident{{Trigger: "high-cpu", Target: "Deployment/apps/web", DetectedAt: metav1.NewTime(created)}}
+
+	tests := []struct {
+		name          string
+		observeUntil  time.Duration
+		incidents     []v1alpha1.Incident
+		managedFields []metav1.ManagedFieldsEntry
+		noTarget      bool
+		noPolicy      bool
+		wantOutcome   string
+		wantManager   string
+		wantRequeue   time.Duration
+	}{
+		{
+			name:         "still firing within the window",
+			observeUntil: 20 * time.Minute,
+			incidents:    openIncident,
+			wantRequeue:  dryRunObservationPollInterval,
+		},
+		{
+			name:         "still firing after the window",
+			observeUntil: -time.Minute,
+			incidents:    openIncident,
+			wantOutcome:  v1alpha1.DryRunOutcomePersisted,
+		},
+		{
+			name:         "recovered on its own",
+			observeUntil: 20 * time.Minute,
+			wantOutcome:  v1alpha1.DryRunOutcomeRecovered,
+		},
+		{
+			name:         "changed by someone else",
+			observeUntil: 20 * time.Minute,
+			incidents:    openIncident,
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &changed},
+				{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &changed},
+			},
+			wantOutcome: v1alpha1.DryRunOutcomeIntervened,
+			wantManager: "kubectl-edit",
+		},
+		{
+			name:         "changed by KubeSkippy only",
+			observeUntil: 20 * time.Minute,
+			incidents:    openIncident,
+			managedFields: []metav1.ManagedFieldsEntry{
+				{Manager: types.FieldManager, Operation: metav1.ManagedFieldsOperationUpdate, Time: &changed},
+			},
+			wantRequeue: dryRunObservationPollInterval,
+		},
+		{
+			name:         "target deleted",
+			observeUntil: 20 * time.Minute,
+			noTarget:     true,
+			wantOutcome:  v1alpha1.DryRunOutcomeInconclusive,
+		},
+		{
+			name:         "policy deleted",
+			observeUntil: 20 * time.Minute,
+			noPolicy:     true,
+			wantOutcome:  v1alpha1.DryRunOutcomeInconclusive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			require.NoError(t, appsv1.AddToScheme(scheme))
+
+			action := &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "web-restart",
+					Namespace:         "apps",
+					CreationTimestamp: metav1.NewTime(created),
+					Finalizers:        []string{FinalizerName},
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef:      v1alpha1.PolicyReference{Name: "web-policy", Namespace: "apps"},
+					TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+					Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+					DryRun:         true,
+				},
+				Status: v1alpha1.HealingActionStatus{
+					Phase:             v1alpha1.HealingActionPhaseSucceeded,
+					DryRunObservation: &v1alpha1.DryRunObservation{ObserveUntil: metav1.NewTime(time.Now().Add(tt.observeUntil))},
+				},
+			}
+			objects := []client.Object{action}
+			if !tt.noTarget {
+				objects = append(objects, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+					Name: "web", Namespace: "apps", ManagedFields: tt.managedFields,
+				}})
+			}
+			if !tt.noPolicy {
+				objects = append(objects, &v1alpha1.HealingPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "apps"},
+					Status:     v1alpha1.HealingPolicyStatus{Incidents: tt.incidents},
+				})
+			}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(action).Build()
+			r := &HealingActionReconciler{
+				Client:            c,
+				Scheme:            scheme,
+				Config:            config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{},
+				SafetyController:  &MockSafetyController{},
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+			require.NoError(t, err)
+
+			got := &v1alpha1.HealingAction{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+			observation := got.Status.DryRunObservation
+			if tt.wantOutcome == "" {
+				assert.Nil(t, observation.ObservedAt)
+				assert.InDelta(t, tt.wantRequeue.Seconds(), result.RequeueAfter.Seconds(), 5)
+				return
+			}
+
+			assert.Zero(t, result)
+			require.NotNil(t, observation.ObservedAt)
+			assert.Equal(t, tt.wantOutcome, observation.Outcome)
+			assert.Equal(t, tt.wantManager, observation.Manager)
+			assert.NotEmpty(t, observation.Message)
+			assert.False(t, isObservingDryRun(got))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/events"
+)
+
+const (
+	// defaultEscalateAfterFailures is used when a policy does not set one
+	defaultEscalateAfterFailures = 3
+
+	// ReasonHealingIneffective is set when actions keep failing for a
+	// target that still triggers
+	ReasonHealingIneffective = "HealingIneffective"
+
+	// ReasonHealingEffective clears a previous HealingIneffective condition
+	ReasonHealingEffective = "HealingEffective"
+)
+
+// escalateAfterFailures returns the policy's escalation threshold
+func escalateAfterFailures(policy *v1alpha1.HealingPolicy) int32 {
+	if policy.Spec.SafetyRules.EscalateAfterFailures != nil {
+		return *policy.Spec.SafetyRules.EscalateAfterFailures
+	}
+	return defaultEscalateAfterFailures
+}
+
+// escalateFailingTargets tracks the failing targets of the policy and
+// escalates those healing is ineffective for: a Warning event per target,
+// the PolicyDegraded condition and one aggregated notification.
+// circuitOpen holds the targets whose actions the circuit breaker rejected
+// in this evaluation.
+func (r *HealingPolicyReconciler) escalateFailingTargets(ctx context.Context, log logr.Logger, policy *v1alpha1.HealingPolicy, circuitOpen map[string]bool, now metav1.Time) {
+	histories := make(map[string][]v1alpha1.HealingAction)
+	if escalateAfterFailures(policy) > 0 {
+		for _, incident := range policy.Status.Incidents {
+			if _, fetched := histories[incident.Target]; fetched {
+				continue
+			}
+			kind, namespace, name := splitTarget(incident.Target)
+			actions, err := ListActionsForTarget(ctx, r, kind, namespace, name)
+			if err != nil {
+				log.Error(err, "Failed to list actions on target", "target", incident.Target)
+			}
+			histories[incident.Target] = actions
+		}
+	}
+
+	escalated := trackFailingTargets(policy, histories, circuitOpen, now)
+	setDegradedCondition(policy)
+	if len(escalated) == 0 {
+		return
+	}
+
+	for _, target := range escalated {
+		message := describeFailingTarget(target)
+		log.Info("Healing is ineffective for target", "target", target.Target,
+			"consecutiveFailures", target.ConsecutiveFailures, "circuitOpen", target.CircuitOpen)
+		if r.Events != nil {
+			r.Events.Event(policy, events.Key(policy.Namespace+"/"+policy.Name, target.Target, ReasonHealingIneffective),
+				corev1.EventTypeWarning, ReasonHealingIneffective, message)
+		}
+	}
+
+	if r.Escalations != nil {
+		if err := r.Escalations.NotifyEscalation(ctx, policy, escalated); err != nil {
+			log.Error(err, "Failed to notify escalation", "targets", len(escalated))
+		}
+	}
+}
+
+// trackFailingTargets updates the policy's failing targets from the actions
+// completed on targets with an open incident. Failures are counted from the
+// later of the incident's detection and the last counted failure, and a
+// successful action resets the count, so the state survives the actions
+// being deleted. Targets whose incidents closed are forgotten. It returns
+// the targets escalated by this update.
+func trackFailingTargets(policy *v1alpha1.HealingPolicy, histories map[string][]v1alpha1.HealingAction, circuitOpen map[string]bool, now metav1.Time) []v1alpha1.FailingTarget {
+	threshold := escalateAfterFailures(policy)
+	if threshold <= 0 {
+		policy.Status.FailingTargets = nil
+		return nil
+	}
+
+	previous := make(map[string]v1alpha1.FailingTarget, len(policy.Status.FailingTargets))
+	for _, state := range policy.Status.FailingTargets {
+		previous[state.Target] = state
+	}
+	detected := make(map[string]time.Time)
+	for _, incident := range policy.Status.Incidents {
+		if at, ok := detected[incident.Target]; !ok || incident.DetectedAt.Time.Before(at) {
+			detected[incident.Target] = incident.DetectedAt.Time
+		}
+	}
+	targets := make([]string, 0, len(detected))
+	for target := range detected {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var failing, escalated []v1alpha1.FailingTarget
+	for _, target := range targets {
+		state, tracked := previous[target]
+		if !tracked {
+			state = v1alpha1.FailingTarget{Target: target}
+		}
+		since := detected[target]
+		if state.LastFailure != nil && state.LastFailure.After(since) {
+			since = state.LastFailure.Time
+		}
+
+		for _, action := range completedPolicyActions(histories[target], policy, since) {
+			if action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded {
+				state = v1alpha1.FailingTarget{Target: target}
+				continue
+			}
+			state.ConsecutiveFailures++
+			completed := *action.Status.CompletionTime
+			if state.FirstFailure == nil {
+				state.FirstFailure = &completed
+			}
+			state.LastFailure = &completed
+			if action.Status.Result != nil {
+				state.LastMessage = action.Status.Result.Message
+			}
+		}
+
+		state.CircuitOpen = circuitOpen[target]
+		if state.ConsecutiveFailures == 0 && !state.CircuitOpen {
+			continue
+		}
+		if state.EscalatedAt == nil && (state.ConsecutiveFailures >= threshold || state.CircuitOpen) {
+			escalatedAt := now
+			state.EscalatedAt = &escalatedAt
+			escalated = append(escalated, state)
+		}
+		failing = append(failing, state)
+	}
+
+	policy.Status.FailingTargets = failing
+	return escalated
+}
+
+// completedPolicyActions returns the policy's actions that succeeded or
+// failed after since, in completion order. Dry runs and cancelled actions
+// say nothing about whether healing works.
+func completedPolicyActions(actions []v1alpha1.HealingAction, policy *v1alpha1.HealingPolicy, since time.Time) []v1alpha1.HealingAction {
+	var completed []v1alpha1.HealingAction
+	for i := range actions {
+		action := &actions[i]
+		if !isPolicyAction(action, policy) || action.Spec.DryRun || action.Status.CompletionTime == nil ||
+			!action.Status.CompletionTime.After(since) {
+			continue
+		}
+		if action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded || action.Status.Phase == v1alpha1.HealingActionPhaseFailed {
+			completed = append(completed, *action)
+		}
+	}
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].Status.CompletionTime.Before(completed[j].Status.CompletionTime)
+	})
+	return completed
+}
+
+// setDegradedCondition reflects the escalated targets on the policy status
+func setDegradedCondition(policy *v1alpha1.HealingPolicy) {
+	var messages []string
+	for _, target := range policy.Status.FailingTargets {
+		if target.EscalatedAt != nil {
+			messages = append(messages, describeFailingTarget(target))
+		}
+	}
+
+	if len(messages) == 0 {
+		if cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypePolicyDegraded); cond != nil && cond.Status == metav1.ConditionTrue {
+			SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypePolicyDegraded,
+				metav1.ConditionFalse, ReasonHealingEffective, "Healing is effective for all targets")
+		}
+		return
+	}
+	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypePolicyDegraded,
+		metav1.ConditionTrue, ReasonHealingIneffective, strings.Join(messages, "; "))
+}
+
+// describeFailingTarget summarizes why healing is ineffective for a target
+func describeFailingTarget(target v1alpha1.FailingTarget) string {
+	var causes []string
+	if target.ConsecutiveFailures > 0 {
+		causes = append(causes, fmt.Sprintf("%d consecutive failed actions", target.ConsecutiveFailures))
+	}
+	if target.CircuitOpen {
+		causes = append(causes, "circuit breaker open")
+	}
+	message := fmt.Sprintf("Healing ineffective for %s: %s", target.Target, strings.Join(causes, ", "))
+	if target.LastMessage != "" {
+		message += fmt.Sprintf(" (last failure: %s)", target.LastMessage)
+	}
+	return message
+}
+
+// splitTarget splits a Kind/Namespace/Name target
+func splitTarget(target string) (kind, namespace, name string) {
+	parts := strings.SplitN(target, "/", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	return parts[0], parts[1], parts[2]
+}
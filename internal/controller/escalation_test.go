@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/events"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func finishedAction(name, phase string, completed time.Time) *v1alpha1.HealingAction {
+	action := indexedAction(name, "web-policy", "Deployment", "web", completed.Add(-time.Minute))
+	completedAt := metav1.NewTime(completed)
+	action.Status.Phase = phase
+	action.Status.CompletionTime = &completedAt
+	action.Status.Result = &v1alpha1.ActionResult{Success: phase == v1alpha1.HealingActionPhaseSucceeded, Message: name + " done"}
+	return action
+}
+
+func failingPolicy(detected time.Time) *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"},
+		Status: v1alpha1.HealingPolicyStatus{
+			Incidents: []v1alpha1.Incident{{Trigger: "crashloop", Target: "Deployment/apps/web", DetectedAt: metav1.NewTime(detected)}},
+		},
+	}
+}
+
+func TestTrackFailingTargets(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	target := "Deployment/apps/web"
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	now := metav1.NewTime(at(30))
+
+	t.Run("escalates after consecutive failures", func(t *testing.T) {
+		policy := failingPolicy(start)
+		history := []v1alpha1.HealingAction{
+			*finishedAction("a1", v1alpha1.HealingActionPhaseFailed, at(1)),
+			*finishedAction("a2", v1alpha1.HealingActionPhaseFailed, at(2)),
+		}
+		escalated := trackFailingTargets(policy, map[string][]v1alpha1.HealingAction{target: history}, nil, now)
+		assert.Empty(t, escalated)
+		require.Len(t, policy.Status.FailingTargets, 1)
+		assert.Equal(t, int32(2), policy.Status.FailingTargets[0].ConsecutiveFailures)
+
+		// Only failures after the last counted one are added
+		history = append(history, *finishedAction("a3", v1alpha1.HealingActionPhaseFailed, at(3)))
+		escalated = trackFailingTargets(policy, map[string][]v1alpha1.HealingAction{target: history}, nil, now)
+		require.Len(t, escalated, 1)
+		assert.Equal(t, int32(3), escalated[0].ConsecutiveFailures)
+		assert.Equal(t, at(1).Unix(), escalated[0].FirstFailure.Unix())
+		assert.Equal(t, "a3 done", escalated[0].LastMessage)
+		require.NotNil(t, policy.Status.FailingTargets[0].EscalatedAt)
+
+		// The failures are kept once their actions are gone, and a target
+		// is escalated only once
+		escalated = trackFailingTargets(policy, map[string][]v1alpha1.HealingAction{}, nil, now)
+		assert.Empty(t, escalated)
+		require.Len(t, policy.Status.FailingTargets, 1)
+		assert.Equal(t, int32(3), policy.Status.FailingTargets[0].ConsecutiveFailures)
+
+		// A success resets the target
+		history = append(history, *finishedAction("a4", v1alpha1.HealingActionPhaseSucceeded, at(4)))
+		trackFailingTargets(policy, map[string][]v1alpha1.HealingAction{target: history}, nil, now)
+		assert.Empty(t, policy.Status.FailingTargets)
+	})
+
+	t.Run("ignores actions before detection, dry runs and cancellations", func(t *testing.T) {
+		policy := failingPolicy(at(10))
+		dryRun := finishedAction("dry", v1alpha1.HealingActionPhaseFailed, at(11))
+		dryRun.Spec.DryRun = true
+		history := []v1alpha1.HealingAction{
+			*finishedAction("old", v1alpha1.HealingActionPhaseFailed, at(5)),
+			*dryRun,
+			*finishedAction("cancelled", v1alpha1.HealingActionPhaseCancelled, at(12)),
+			*finishedAction("failed", v1alpha1.HealingActionPhaseFailed, at(13)),
+		}
+		trackFailingTargets(policy, map[string][]v1alpha1.HealingAction{target: history}, nil, now)
+		require.Len(t, policy.Status.FailingTargets, 1)
+		assert.Equal(t, int32(1), policy.Status.FailingTargets[0].ConsecutiveFailures)
+	})
+
+	t.Run("escalates an open circuit breaker", func(t *testing.T) {
+		policy := failingPolicy(start)
+		escalated := trackFailingTargets(policy, nil, map[string]bool{target: true}, now)
+		require.Len(t, escalated, 1)
+		assert.True(t, escalated[0].CircuitOpen)
+		assert.Zero(t, escalated[0].ConsecutiveFailures)
+	})
+
+	t.Run("forgets targets whose incident closed", func(t *testing.T) {
+		policy := failingPolicy(start)
+		trackFailingTargets(policy, nil, map[string]bool{target: true}, now)
+		policy.Status.Incidents = nil
+		trackFailingTargets(policy, nil, nil, now)
+		assert.Empty(t, policy.Status.FailingTargets)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		policy := failingPolicy(start)
+		disabled := int32(0)
+		policy.Spec.SafetyRules.EscalateAfterFailures = &disabled
+		assert.Empty(t, trackFailingTargets(policy, nil, map[string]bool{target: true}, now))
+		assert.Empty(t, policy.Status.FailingTargets)
+	})
+}
+
+type recordingEscalationNotifier struct {
+	reports [][]v1alpha1.FailingTarget
+}
+
+func (n *recordingEscalationNotifier) NotifyEscalation(_ context.Context, _ *v1alpha1.HealingPolicy, targets []v1alpha1.FailingTarget) error {
+	n.reports = append(n.reports, targets)
+	return nil
+}
+
+func TestEscalateFailingTargets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	start := time.Now().Add(-time.Hour)
+	objects := []client.Object{
+		finishedAction("a1", v1alpha1.HealingActionPhaseFailed, start.Add(time.Minute)),
+		finishedAction("a2", v1alpha1.HealingActionPhaseFailed, start.Add(2*time.Minute)),
+		finishedAction("a3", v1alpha1.HealingActionPhaseFailed, start.Add(3*time.Minute)),
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget).
+		WithObjects(objects...).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	notifier := &recordingEscalationNotifier{}
+	r := &HealingPolicyReconciler{
+		Client:      c,
+		Events:      events.NewAggregator(recorder, config.EventsConfig{AggregationWindow: time.Minute, EmitEvery: 10}),
+		Escalations: notifier,
+	}
+	policy := failingPolicy(start)
+
+	r.escalateFailingTargets(context.Background(), logr.Discard(), policy, nil, metav1.Now())
+	require.Len(t, notifier.reports, 1)
+	assert.Equal(t, "Deployment/apps/web", notifier.reports[0][0].Target)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning HealingIneffective Healing ineffective for Deployment/apps/web: 3 consecutive failed actions (last failure: a3 done)", <-recorder.Events)
+	cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypePolicyDegraded)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, ReasonHealingIneffective, cond.Reason)
+
+	// Escalated targets are not reported again
+	r.escalateFailingTargets(context.Background(), logr.Discard(), policy, nil, metav1.Now())
+	assert.Len(t, notifier.reports, 1)
+	assert.Empty(t, recorder.Events)
+
+	// The policy recovers once the target is healed
+	require.NoError(t, c.Create(context.Background(), finishedAction("a4", v1alpha1.HealingActionPhaseSucceeded, start.Add(4*time.Minute))))
+	r.escalateFailingTargets(context.Background(), logr.Discard(), policy, nil, metav1.Now())
+	assert.Empty(t, policy.Status.FailingTargets)
+	cond = GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypePolicyDegraded)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+}
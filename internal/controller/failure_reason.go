@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/remediation"
+)
+
+// ReasonCapacityBlocked is the event reason of scale-ups blocked because
+// the nodes have no room for the new replicas
+const ReasonCapacityBlocked = "CapacityBlocked"
+
+// ReasonBlastRadiusBlocked is the event reason of actions blocked because
+// their simulated impact exceeds the policy's blast radius limits
+const ReasonBlastRadiusBlocked = "BlastRadiusBlocked"
+
+// classifyFailure maps an execution error to a failure reason for status
+// and metrics
+func classifyFailure(err error) string {
+	var rbacErr *remediation.MissingRBACError
+	var capacityErr *remediation.CapacityBlockedError
+	var blastErr *remediation.BlastRadiusBlockedError
+	switch {
+	case err == nil:
+		return v1alpha1.FailureReasonExecutorError
+	case errors.As(err, &capacityErr):
+		return v1alpha1.FailureReasonCapacityBlocked
+	case errors.As(err, &blastErr):
+		return v1alpha1.FailureReasonBlastRadiusBlocked
+	case errors.As(err, &rbacErr), apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return v1alpha1.FailureReasonRBACDenied
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return v1alpha1.FailureReasonTimeout
+	case apierrors.IsNotFound(err), apierrors.IsGone(err):
+		return v1alpha1.FailureReasonTargetNotFound
+	case apierrors.IsConflict(err):
+		return v1alpha1.FailureReasonConflict
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return v1alpha1.FailureReasonValidationFailed
+	default:
+		return v1alpha1.FailureReasonExecutorError
+	}
+}
+
+// isTerminalFailure reports whether retrying the action cannot succeed, so
+// the retry policy is skipped
+func isTerminalFailure(err error) bool {
+	var blastErr *remediation.BlastRadiusBlockedError
+	return errors.As(err, &blastErr)
+}
+
+// failureReason returns the metric label for a completed action, empty for
+// actions that did not fail
+func failureReason(action *v1alpha1.HealingAction) string {
+	if action.Status.Phase != v1alpha1.HealingActionPhaseFailed {
+		return ""
+	}
+	if action.Status.Result != nil && action.Status.Result.FailureReason != "" {
+		return action.Status.Result.FailureReason
+	}
+	return v1alpha1.FailureReasonExecutorError
+}
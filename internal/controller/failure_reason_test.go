@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/remediation"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"missing RBAC from preflight", &remediation.MissingRBACError{Verb: "update", Resource: "deployments/scale", Namespace: "default"}, v1alpha1.FailureReasonRBACDenied},
+		{"capacity blocked", &remediation.CapacityBlockedError{Requested: 3, Schedulable: 1}, v1alpha1.FailureReasonCapacityBlocked},
+		{"blast radius blocked", &remediation.BlastRadiusBlockedError{Reason: "would violate PodDisruptionBudgets: web"}, v1alpha1.FailureReasonBlastRadiusBlocked},
+		{"forbidden", apierrors.NewForbidden(gr, "web", errors.New("denied")), v1alpha1.FailureReasonRBACDenied},
+		{"wrapped not found", fmt.Errorf("failed to get resource: %w", apierrors.NewNotFound(gr, "web")), v1alpha1.FailureReasonTargetNotFound},
+		{"conflict", apierrors.NewConflict(gr, "web", errors.New("modified")), v1alpha1.FailureReasonConflict},
+		{"deadline", fmt.Errorf("scale: %w", context.DeadlineExceeded), v1alpha1.FailureReasonTimeout},
+		{"server timeout", apierrors.NewTimeoutError("slow", 1), v1alpha1.FailureReasonTimeout},
+		{"invalid", apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", nil), v1alpha1.FailureReasonValidationFailed},
+		{"anything else", errors.New("exec failed"), v1alpha1.FailureReasonExecutorError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyFailure(tt.err))
+		})
+	}
+}
+
+func TestHealingActionReconciler_FailureReasonMetric(t *testing.T) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_healing_actions_total"},
+		[]string{"action_type", "namespace", "status", "trigger_type", "reason", "dry_run"})
+	previous := healingActionsTotal
+	SetHealingActionsMetric(counter)
+	defer SetHealingActionsMetric(previous)
+
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "denied-action", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			Action:  v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			Timeout: metav1.Duration{Duration: 10 * time.Minute},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Phase:     v1alpha1.HealingActionPhaseInProgress,
+			StartTime: &metav1.Time{Time: time.Now()},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(action).
+		WithStatusSubresource(action).
+		Build()
+
+	r := &HealingActionReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{
+			ExecuteActionFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+				return nil, &remediation.MissingRBACError{Verb: "delete", Resource: "pods", Namespace: "default"}
+			},
+		},
+		SafetyController: &MockSafetyController{},
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: action.Name, Namespace: action.Namespace}}
+	finalAction, err := reconcileUntilPhase(t, r, req, v1alpha1.HealingActionPhaseFailed, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, v1alpha1.FailureReasonRBACDenied, finalAction.Status.Result.FailureReason)
+	assert.Equal(t, 1.0, testutil.ToFloat64(counter.WithLabelValues("restart", "default", "failed", "manual", v1alpha1.FailureReasonRBACDenied, "false")))
+}
+
+func TestHealingActionReconciler_BlastRadiusBlockedIsTerminal(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "blocked-action", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			Action:      v1alpha1.HealingActionTemplate{Name: "delete", Type: "delete"},
+			Timeout:     metav1.Duration{Duration: 10 * time.Minute},
+			RetryPolicy: &v1alpha1.RetryPolicy{MaxAttempts: 3, BackoffDelay: metav1.Duration{Duration: time.Second}, BackoffMultiplier: 2},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Phase:     v1alpha1.HealingActionPhaseInProgress,
+			StartTime: &metav1.Time{Time: time.Now()},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(action).
+		WithStatusSubresource(action).
+		Build()
+
+	report := &v1alpha1.BlastRadiusReport{Blocked: true, Reason: "would violate PodDisruptionBudgets: web"}
+	r := &HealingActionReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{
+			ExecuteActionFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+				err := &remediation.BlastRadiusBlockedError{Reason: report.Reason}
+				return &ktypes.ActionResult{Success: false, Error: err, BlastRadius: report}, err
+			},
+		},
+		SafetyController: &MockSafetyController{},
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: action.Name, Namespace: action.Namespace}}
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter, "blocked actions are not retried")
+
+	final := &v1alpha1.HealingAction{}
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, final))
+	assert.Equal(t, v1alpha1.HealingActionPhaseFailed, final.Status.Phase)
+	assert.Equal(t, int32(1), final.Status.Attempts)
+	assert.Equal(t, v1alpha1.FailureReasonBlastRadiusBlocked, final.Status.Result.FailureReason)
+	assert.True(t, final.Status.BlastRadius.Blocked)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -17,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/events"
 	"github.com/kubeskippy/kubeskippy/internal/types"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
 )
@@ -37,18 +39,47 @@ type HealingActionReconciler struct {
 	Config            *config.Config
 	RemediationEngine RemediationEngine
 	SafetyController  SafetyController
+
+	// Notifier optionally reports completed actions
+	Notifier ActionNotifier
+
+	// Events optionally records Kubernetes events for actions
+	Events *events.Aggregator
+
+	// Watchdog optionally tracks reconciles and enforces safe mode
+	Watchdog Watchdog
+
+	// Calendar optionally holds automatic actions back during release
+	// freezes
+	Calendar ReleaseCalendar
+
+	// MetricsCollector evaluates the metric success criteria of actions
+	MetricsCollector MetricsCollector
 }
 
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingactions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingactions/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingactions/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=patch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=pods/resize,verbs=patch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets;replicasets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments/scale;statefulsets/scale;replicasets/scale,verbs=get;update;patch
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services;persistentvolumeclaims,verbs=update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
-func (r *HealingActionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *HealingActionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	done := trackReconcile(r.Watchdog, "healingaction")
+	defer func() { done(err) }()
+
 	log := log.FromContext(ctx)
 	log.Info("Reconciling HealingAction")
 
@@ -78,13 +109,40 @@ func (r *HealingActionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return r.handleDeletion(ctx, log, action)
 	}
 
-	// Update observed generation
-	if action.Status.ObservedGeneration != action.Generation {
-		action.Status.ObservedGeneration = action.Generation
-		if err := r.Status().Update(ctx, action); err != nil {
-			log.Error(err, "Failed to update observed generation")
+	// Handlers write status changes as patches; whatever they leave
+	// unwritten is patched once the reconcile is done
+	patcher := newStatusPatcher(r.Client, "healingaction", action)
+	ctx = withStatusPatcher(ctx, patcher)
+	defer func() {
+		if patchErr := patcher.Patch(ctx, action); patchErr != nil && !errors.IsNotFound(patchErr) {
+			log.Error(patchErr, "Failed to update status")
+			if err == nil {
+				result, err = ctrl.Result{}, patchErr
+			}
+		}
+	}()
+	action.Status.ObservedGeneration = action.Generation
+
+	// Hold actions that have not started while their policy is paused
+	switch action.Status.Phase {
+	case "", v1alpha1.HealingActionPhasePending, v1alpha1.HealingActionPhaseApproved:
+		paused, err := isPolicyPaused(ctx, r.Client, action)
+		if err != nil {
+			log.Error(err, "Failed to check whether policy is paused")
+			return ctrl.Result{}, err
+		}
+		if paused {
+			return r.holdPaused(ctx, log, action)
+		}
+		if err := r.releasePaused(ctx, log, action); err != nil {
 			return ctrl.Result{}, err
 		}
+		if !action.Spec.DryRun && inSafeMode(r.Watchdog) {
+			return r.holdSafeMode(ctx, log, action)
+		}
+		if blackout := r.heldByBlackout(ctx, action); blackout != "" {
+			return r.holdBlackout(ctx, log, action, blackout)
+		}
 	}
 
 	// Process based on phase
@@ -95,8 +153,20 @@ func (r *HealingActionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return r.handleApproved(ctx, log, action)
 	case v1alpha1.HealingActionPhaseInProgress:
 		return r.handleInProgress(ctx, log, action)
+	case v1alpha1.HealingActionPhaseVerifying:
+		return r.handleVerifying(ctx, log, action)
 	case v1alpha1.HealingActionPhaseSucceeded, v1alpha1.HealingActionPhaseFailed, v1alpha1.HealingActionPhaseCancelled:
-		// Terminal states - nothing to do
+		// Terminal states - only hibernated targets, tainted nodes and the
+		// targets of dry runs are still tracked
+		if isHibernating(action) {
+			return r.handleHibernation(ctx, log, action)
+		}
+		if isNodeTainted(action) {
+			return r.handleNodeTaint(ctx, log, action)
+		}
+		if isObservingDryRun(action) {
+			return r.handleDryRunObservation(ctx, log, action)
+		}
 		return ctrl.Result{}, nil
 	default:
 		log.Error(nil, "Unknown phase", "phase", action.Status.Phase)
@@ -114,6 +184,14 @@ func (r *HealingActionReconciler) handlePending(ctx context.Context, log logr.Lo
 	}
 	action.Labels[LabelActionPhase] = v1alpha1.HealingActionPhasePending
 
+	// Status set at creation is dropped, so carry the severity over
+	if action.Status.Severity == "" {
+		action.Status.Severity = action.Labels[LabelSeverity]
+	}
+	if action.Status.PriorityDecision == nil {
+		action.Status.PriorityDecision = priorityDecisionFromAnnotations(action.Annotations)
+	}
+
 	// Check if approval is required
 	if action.Spec.ApprovalRequired {
 		log.Info("Action requires approval")
@@ -132,7 +210,7 @@ func (r *HealingActionReconciler) handlePending(ctx context.Context, log logr.Lo
 				"Action is waiting for manual approval")
 
 			// Update status first
-			if err := r.Status().Update(ctx, action); err != nil {
+			if err := patchStatus(ctx, r.Client, action); err != nil {
 				log.Error(err, "Failed to update status")
 				return ctrl.Result{}, err
 			}
@@ -157,7 +235,7 @@ func (r *HealingActionReconciler) handlePending(ctx context.Context, log logr.Lo
 	}
 
 	// Update status first
-	if err := r.Status().Update(ctx, action); err != nil {
+	if err := patchStatus(ctx, r.Client, action); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
@@ -175,6 +253,42 @@ func (r *HealingActionReconciler) handlePending(ctx context.Context, log logr.Lo
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// holdPaused holds an action in its current phase while its policy is
+// paused. The hold is reported with a Paused condition, so approved actions
+// stay approved and run once the policy is resumed.
+func (r *HealingActionReconciler) holdPaused(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	log.Info("Policy is paused, holding action")
+
+	if cond := GetCondition(action.Status.Conditions, v1alpha1.ConditionTypePaused); cond == nil || cond.Status != metav1.ConditionTrue {
+		SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypePaused,
+			metav1.ConditionTrue, ReasonPolicyPaused, "Action is held because its policy is paused")
+		if err := patchStatus(ctx, r.Client, action); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
+}
+
+// releasePaused clears the Paused condition of an action whose policy was
+// resumed
+func (r *HealingActionReconciler) releasePaused(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) error {
+	cond := GetCondition(action.Status.Conditions, v1alpha1.ConditionTypePaused)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return nil
+	}
+
+	log.Info("Policy was resumed, releasing action")
+	SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypePaused,
+		metav1.ConditionFalse, ReasonPolicyResumed, "Policy was resumed")
+	if err := patchStatus(ctx, r.Client, action); err != nil {
+		log.Error(err, "Failed to update status")
+		return err
+	}
+	return nil
+}
+
 // handleApproved handles actions that have been approved
 func (r *HealingActionReconciler) handleApproved(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
 	log.Info("Handling approved action")
@@ -184,24 +298,54 @@ func (r *HealingActionReconciler) handleApproved(ctx context.Context, log logr.L
 	if err != nil {
 		log.Error(err, "Failed to validate action")
 		action.SetPhase(v1alpha1.HealingActionPhaseFailed, ReasonValidationError, err.Error())
-		if err := r.Status().Update(ctx, action); err != nil {
-			log.Error(err, "Failed to update status")
+		action.Status.Result = &v1alpha1.ActionResult{
+			Success:       false,
+			Error:         err.Error(),
+			FailureReason: v1alpha1.FailureReasonValidationFailed,
 		}
-		return ctrl.Result{}, nil
+		return r.completeAction(ctx, log, action)
 	}
 
 	if !validation.Valid {
 		log.Info("Action validation failed", "reason", validation.Reason)
 		action.SetPhase(v1alpha1.HealingActionPhaseFailed, ReasonValidationError, validation.Reason)
 		action.Status.Result = &v1alpha1.ActionResult{
-			Success: false,
-			Message: validation.Reason,
-			Error:   "Validation failed",
+			Success:       false,
+			Message:       validation.Reason,
+			Error:         "Validation failed",
+			FailureReason: v1alpha1.FailureReasonValidationFailed,
 		}
-		if err := r.Status().Update(ctx, action); err != nil {
-			log.Error(err, "Failed to update status")
+		return r.completeAction(ctx, log, action)
+	}
+
+	// Cancel lower-priority actions still pending or running on the target
+	preempted, err := r.preemptConflicting(ctx, log, action)
+	if err != nil {
+		log.Error(err, "Failed to preempt conflicting actions")
+		return ctrl.Result{}, err
+	}
+	if len(preempted) > 0 {
+		r.recordEvent(action, corev1.EventTypeNormal, ReasonActionPreempted,
+			fmt.Sprintf("Preempted lower-priority actions: %s", strings.Join(preempted, ", ")))
+	}
+
+	// Take the action's place in the rate limit last, so that the check and
+	// the count cannot be split by another manager
+	if reserver, ok := r.SafetyController.(ActionReserver); ok {
+		allowed, err := reserver.ReserveAction(ctx, action)
+		if err != nil {
+			log.Error(err, "Failed to reserve action against the rate limit")
+			return ctrl.Result{}, err
+		}
+		if !allowed {
+			log.Info("Rate limit reached, holding action")
+			action.SetPhase(action.Status.Phase, ReasonRateLimited, "Action is held until the policy's rate limit allows it")
+			if err := patchStatus(ctx, r.Client, action); err != nil {
+				log.Error(err, "Failed to update status")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
 		}
-		return ctrl.Result{}, nil
 	}
 
 	// Move to in-progress
@@ -210,7 +354,7 @@ func (r *HealingActionReconciler) handleApproved(ctx context.Context, log logr.L
 	action.Status.Attempts = 0
 
 	// Update status first
-	if err := r.Status().Update(ctx, action); err != nil {
+	if err := patchStatus(ctx, r.Client, action); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
@@ -233,40 +377,46 @@ func (r *HealingActionReconciler) handleInProgress(ctx context.Context, log logr
 	log.Info("Handling in-progress action", "attempts", action.Status.Attempts)
 
 	// Check timeout
-	if action.Status.StartTime != nil {
-		elapsed := time.Since(action.Status.StartTime.Time)
-		if elapsed > action.Spec.Timeout.Duration {
-			log.Info("Action timed out")
-			action.SetPhase(v1alpha1.HealingActionPhaseFailed, "Timeout", "Action execution timed out")
-			action.Status.Result = &v1alpha1.ActionResult{
-				Success: false,
-				Message: "Action timed out",
-				Error:   fmt.Sprintf("Exceeded timeout of %v", action.Spec.Timeout.Duration),
-			}
-			return r.completeAction(ctx, log, action)
-		}
+	if actionTimedOut(action) {
+		log.Info("Action timed out")
+		return r.failTimedOut(ctx, log, action, nil)
 	}
 
-	// Execute the action
+	// Execute the action, cancelling the executor once the attempt or the
+	// action runs out of time
 	action.Status.Attempts++
 	action.Status.LastAttemptTime = &metav1.Time{Time: time.Now()}
+	attemptCtx, cancel := context.WithDeadline(ctx, r.attemptDeadline(action))
+	defer cancel()
 
 	var result *types.ActionResult
 	var err error
 
 	if action.Spec.DryRun {
 		log.Info("Executing dry-run")
-		result, err = r.RemediationEngine.DryRun(ctx, action)
+		result, err = r.RemediationEngine.DryRun(attemptCtx, action)
 	} else {
 		log.Info("Executing action")
-		result, err = r.RemediationEngine.ExecuteAction(ctx, action)
+		result, err = r.RemediationEngine.ExecuteAction(attemptCtx, action)
+	}
+
+	if result != nil && result.BlastRadius != nil {
+		action.Status.BlastRadius = result.BlastRadius
 	}
 
 	if err != nil {
 		log.Error(err, "Action execution failed")
 
+		// A preempted action was completed while its executor ran
+		if attemptCancelled(ctx, err) {
+			return r.recordCancelledAttempt(ctx, log, action, result)
+		}
+		if actionTimedOut(action) {
+			return r.failTimedOut(ctx, log, action, result)
+		}
+
 		// Check if we should retry
-		if action.Spec.RetryPolicy != nil && action.Status.Attempts < action.Spec.RetryPolicy.MaxAttempts {
+		if action.Spec.RetryPolicy != nil && action.Status.Attempts < action.Spec.RetryPolicy.MaxAttempts && !isTerminalFailure(err) {
 			backoff := CalculateBackoff(
 				action.Status.Attempts,
 				action.Spec.RetryPolicy.BackoffDelay.Duration,
@@ -277,8 +427,11 @@ func (r *HealingActionReconciler) handleInProgress(ctx context.Context, log logr
 
 			SetCondition(&action.Status.Conditions, "Retrying", metav1.ConditionTrue,
 				"RetryScheduled", fmt.Sprintf("Will retry after %v", backoff))
+			if result != nil && result.Partial {
+				action.Status.Result = partialResult(result, err)
+			}
 
-			if err := r.Status().Update(ctx, action); err != nil {
+			if err := patchStatus(ctx, r.Client, action); err != nil {
 				log.Error(err, "Failed to update status")
 			}
 
@@ -291,16 +444,21 @@ func (r *HealingActionReconciler) handleInProgress(ctx context.Context, log logr
 
 		if result != nil {
 			action.Status.Result = &v1alpha1.ActionResult{
-				Success: result.Success,
-				Message: result.Message,
-				Error:   err.Error(),
-				Metrics: result.Metrics,
-				Changes: result.Changes,
+				Success:       result.Success,
+				Message:       result.Message,
+				Error:         err.Error(),
+				FailureReason: classifyFailure(err),
+				Metrics:       result.Metrics,
+				Changes:       result.Changes,
+				Partial:       result.Partial,
+				Diagnostics:   result.Diagnostics,
+				Steps:         result.Steps,
 			}
 		} else {
 			action.Status.Result = &v1alpha1.ActionResult{
-				Success: false,
-				Error:   err.Error(),
+				Success:       false,
+				Error:         err.Error(),
+				FailureReason: classifyFailure(err),
 			}
 		}
 
@@ -309,20 +467,45 @@ func (r *HealingActionReconciler) handleInProgress(ctx context.Context, log logr
 
 	// Action succeeded
 	log.Info("Action executed successfully")
-	action.SetPhase(v1alpha1.HealingActionPhaseSucceeded, ReasonActionSucceeded,
-		"Action completed successfully")
-
 	action.Status.Result = &v1alpha1.ActionResult{
-		Success: result.Success,
-		Message: result.Message,
-		Metrics: result.Metrics,
-		Changes: result.Changes,
+		Success:     result.Success,
+		Message:     result.Message,
+		Metrics:     result.Metrics,
+		Changes:     result.Changes,
+		Diagnostics: result.Diagnostics,
+		Steps:       result.Steps,
 	}
+	action.Status.TargetGeneration = result.TargetGeneration
 
 	// Record the action with safety controller
 	r.SafetyController.RecordAction(ctx, action, result)
 
-	return r.completeAction(ctx, log, action)
+	// A tainted node is resolved after its window, whatever the verification
+	startNodeTaint(action)
+
+	// The target was changed, but the problem may not be solved yet
+	if needsVerification(action) {
+		return r.startVerification(ctx, log, action)
+	}
+
+	action.SetPhase(v1alpha1.HealingActionPhaseSucceeded, ReasonActionSucceeded,
+		"Action completed successfully")
+	startHibernation(action)
+	startDryRunObservation(action, r.dryRunObservationWindow())
+
+	if _, err := r.completeAction(ctx, log, action); err != nil {
+		return ctrl.Result{}, err
+	}
+	if isHibernating(action) {
+		return r.handleHibernation(ctx, log, action)
+	}
+	if isNodeTainted(action) {
+		return r.handleNodeTaint(ctx, log, action)
+	}
+	if isObservingDryRun(action) {
+		return ctrl.Result{RequeueAfter: dryRunObservationPollInterval}, nil
+	}
+	return ctrl.Result{}, nil
 }
 
 // completeAction updates the action to its final state
@@ -339,40 +522,53 @@ func (r *HealingActionReconciler) completeAction(ctx context.Context, log logr.L
 	// Record metrics
 	triggerType := "manual"
 	if action.Labels != nil {
-		if tt, ok := action.Labels["trigger-type"]; ok {
+		if tt, ok := action.Labels[LabelTriggerName]; ok {
 			triggerType = tt
 		}
 	}
 
 	status := "completed"
-	if action.Status.Phase == v1alpha1.HealingActionPhaseFailed {
+	switch action.Status.Phase {
+	case v1alpha1.HealingActionPhaseFailed:
 		status = "failed"
+	case v1alpha1.HealingActionPhaseCancelled:
+		status = "cancelled"
 	}
 
-	healingActionsTotal.WithLabelValues(
-		action.Spec.Action.Type,
-		action.Namespace,
-		status,
-		triggerType,
-	).Inc()
+	recordActionMetrics(action, status, triggerType)
 
 	// Create an event
 	eventType := corev1.EventTypeNormal
 	reason := ReasonActionSucceeded
 	message := fmt.Sprintf("Healing action %s completed successfully", action.Spec.Action.Type)
 
-	if action.Status.Phase == v1alpha1.HealingActionPhaseFailed {
+	switch action.Status.Phase {
+	case v1alpha1.HealingActionPhaseFailed:
 		eventType = corev1.EventTypeWarning
 		reason = ReasonActionFailed
 		message = fmt.Sprintf("Healing action %s failed: %s",
 			action.Spec.Action.Type,
 			action.Status.Result.Error)
+		switch action.Status.Result.FailureReason {
+		case v1alpha1.FailureReasonCapacityBlocked:
+			reason = ReasonCapacityBlocked
+		case v1alpha1.FailureReasonBlastRadiusBlocked:
+			reason = ReasonBlastRadiusBlocked
+		case v1alpha1.FailureReasonVerificationFailed:
+			reason = ReasonVerificationFailed
+		}
+	case v1alpha1.HealingActionPhaseCancelled:
+		eventType = corev1.EventTypeWarning
+		reason = ReasonActionPreempted
+		message = fmt.Sprintf("Healing action %s cancelled: %s",
+			action.Spec.Action.Type,
+			action.Status.Result.Message)
 	}
 
 	r.recordEvent(action, eventType, reason, message)
 
 	// Update status first (contains phase and completion time)
-	if err := r.Status().Update(ctx, action); err != nil {
+	if err := patchStatus(ctx, r.Client, action); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
@@ -383,6 +579,18 @@ func (r *HealingActionReconciler) completeAction(ctx context.Context, log logr.L
 		return ctrl.Result{}, err
 	}
 
+	// Decision records are best effort as well
+	if err := r.completeAIDecision(ctx, action); err != nil {
+		log.Error(err, "Failed to record AI decision outcome")
+	}
+
+	// Notifications are best effort and never fail the action
+	if r.Notifier != nil {
+		if err := r.Notifier.NotifyCompletion(ctx, action); err != nil {
+			log.Error(err, "Failed to send completion notification")
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -400,6 +608,24 @@ func (r *HealingActionReconciler) handleDeletion(ctx context.Context, log logr.L
 		}
 	}
 
+	// Never leave a hibernated workload at zero replicas
+	if isHibernating(action) {
+		log.Info("Resuming hibernated workload before deletion")
+		if err := r.RemediationEngine.Rollback(ctx, action); err != nil {
+			log.Error(err, "Failed to resume hibernated workload")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Never leave a node tainted without its action resolving it
+	if isNodeTainted(action) {
+		log.Info("Removing node taint before deletion")
+		if err := r.RemediationEngine.Rollback(ctx, action); err != nil {
+			log.Error(err, "Failed to remove node taint")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(action, FinalizerName)
 	if err := r.Update(ctx, action); err != nil {
@@ -410,16 +636,27 @@ func (r *HealingActionReconciler) handleDeletion(ctx context.Context, log logr.L
 	return ctrl.Result{}, nil
 }
 
-// recordEvent records a Kubernetes event
+// recordEvent records a Kubernetes event, aggregating repeats for the same
+// policy, target and reason
 func (r *HealingActionReconciler) recordEvent(action *v1alpha1.HealingAction, eventType, reason, message string) {
-	// In a real implementation, this would use the event recorder
-	// For now, we'll just log it
 	log := log.FromContext(context.Background())
 	log.Info("Recording event",
 		"type", eventType,
 		"reason", reason,
 		"message", message,
 		"action", action.Name)
+
+	if r.Events == nil {
+		return
+	}
+	target := action.Spec.TargetResource
+	key := events.Key(
+		action.Spec.PolicyRef.Namespace+"/"+action.Spec.PolicyRef.Name,
+		fmt.Sprintf("%s/%s/%s", target.Kind, target.Namespace, target.Name),
+		reason)
+	if !r.Events.Event(action, key, eventType, reason, message) {
+		log.V(1).Info("Event aggregated", "reason", reason, "action", action.Name)
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager
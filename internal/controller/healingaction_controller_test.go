@@ -15,29 +15,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
 )
 
 // MockRemediationEngine implements RemediationEngine interface for testing
 type MockRemediationEngine struct {
-	ExecuteActionFunc     func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error)
-	DryRunFunc            func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error)
+	ExecuteActionFunc     func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error)
+	DryRunFunc            func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error)
 	RollbackFunc          func(ctx context.Context, action *v1alpha1.HealingAction) error
-	GetActionExecutorFunc func(actionType string) (ActionExecutor, error)
+	GetActionExecutorFunc func(actionType string) (ktypes.ActionExecutor, error)
+	CancelActionFunc      func(actionName string) error
 }
 
-func (m *MockRemediationEngine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
+func (m *MockRemediationEngine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
 	if m.ExecuteActionFunc != nil {
 		return m.ExecuteActionFunc(ctx, action)
 	}
-	return &ActionResult{Success: true, Message: "Mock success"}, nil
+	return &ktypes.ActionResult{Success: true, Message: "Mock success"}, nil
 }
 
-func (m *MockRemediationEngine) DryRun(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
+func (m *MockRemediationEngine) DryRun(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
 	if m.DryRunFunc != nil {
 		return m.DryRunFunc(ctx, action)
 	}
-	return &ActionResult{Success: true, Message: "Mock dry-run success"}, nil
+	return &ktypes.ActionResult{Success: true, Message: "Mock dry-run success"}, nil
 }
 
 func (m *MockRemediationEngine) Rollback(ctx context.Context, action *v1alpha1.HealingAction) error {
@@ -47,13 +49,20 @@ func (m *MockRemediationEngine) Rollback(ctx context.Context, action *v1alpha1.H
 	return nil
 }
 
-func (m *MockRemediationEngine) GetActionExecutor(actionType string) (ActionExecutor, error) {
+func (m *MockRemediationEngine) GetActionExecutor(actionType string) (ktypes.ActionExecutor, error) {
 	if m.GetActionExecutorFunc != nil {
 		return m.GetActionExecutorFunc(actionType)
 	}
 	return nil, nil
 }
 
+func (m *MockRemediationEngine) CancelAction(actionName string) error {
+	if m.CancelActionFunc != nil {
+		return m.CancelActionFunc(actionName)
+	}
+	return nil
+}
+
 // reconcileUntilPhase simulates multiple reconciliations until the action reaches the expected phase or a terminal state
 func reconcileUntilPhase(t *testing.T, r *HealingActionReconciler, req reconcile.Request, expectedPhase string, maxIterations int) (*v1alpha1.HealingAction, error) {
 	var lastPhase string
@@ -119,8 +128,8 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 	tests := []struct {
 		name            string
 		action          *v1alpha1.HealingAction
-		remediationFunc func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error)
-		validateFunc    func(ctx context.Context, action *v1alpha1.HealingAction) (*ValidationResult, error)
+		remediationFunc func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error)
+		validateFunc    func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error)
 		expectedPhase   string
 		maxReconciles   int
 		setupFunc       func(t *testing.T, action *v1alpha1.HealingAction)
@@ -144,8 +153,8 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 					// Start with empty phase for new actions
 				},
 			},
-			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
-				return &ActionResult{
+			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+				return &ktypes.ActionResult{
 					Success: true,
 					Message: "Action completed successfully",
 				}, nil
@@ -191,8 +200,8 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 					Phase: v1alpha1.HealingActionPhaseApproved,
 				},
 			},
-			validateFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ValidationResult, error) {
-				return &ValidationResult{
+			validateFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error) {
+				return &ktypes.ValidationResult{
 					Valid:  false,
 					Reason: "Resource is protected",
 				}, nil
@@ -219,8 +228,8 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 					StartTime: &metav1.Time{Time: time.Now()},
 				},
 			},
-			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
-				return &ActionResult{
+			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+				return &ktypes.ActionResult{
 					Success: true,
 					Message: "Action completed successfully",
 				}, nil
@@ -253,12 +262,12 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 					Attempts:  0,
 				},
 			},
-			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
+			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
 				// Fail first 2 attempts, succeed on third
 				if action.Status.Attempts < 2 {
 					return nil, errors.New("temporary failure")
 				}
-				return &ActionResult{
+				return &ktypes.ActionResult{
 					Success: true,
 					Message: "Action completed successfully",
 				}, nil
@@ -291,7 +300,7 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 					Attempts:  0,
 				},
 			},
-			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
+			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
 				return nil, errors.New("permanent failure")
 			},
 			expectedPhase: v1alpha1.HealingActionPhaseFailed,
@@ -317,8 +326,8 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 					StartTime: &metav1.Time{Time: time.Now()},
 				},
 			},
-			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ActionResult, error) {
-				return &ActionResult{
+			remediationFunc: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ActionResult, error) {
+				return &ktypes.ActionResult{
 					Success: true,
 					Message: "Dry-run completed successfully",
 				}, nil
@@ -379,6 +388,8 @@ func TestHealingActionReconciler_Reconcile(t *testing.T) {
 				assert.NotNil(t, finalAction.Status.Result)
 				assert.True(t, finalAction.Status.Result.Success)
 				assert.NotNil(t, finalAction.Status.CompletionTime)
+				// Only the targets of dry runs are observed afterwards
+				assert.Equal(t, tt.action.Spec.DryRun, finalAction.Status.DryRunObservation != nil)
 			case v1alpha1.HealingActionPhaseFailed:
 				assert.NotNil(t, finalAction.Status.Result)
 				assert.False(t, finalAction.Status.Result.Success)
@@ -447,5 +458,128 @@ func TestHealingActionReconciler_handleTimeout(t *testing.T) {
 	assert.NotNil(t, finalAction.Status.Result)
 	assert.False(t, finalAction.Status.Result.Success)
 	assert.Contains(t, finalAction.Status.Result.Message, "timed out")
+	assert.Equal(t, v1alpha1.FailureReasonTimeout, finalAction.Status.Result.FailureReason)
 	assert.NotNil(t, finalAction.Status.CompletionTime)
 }
+
+// recordingNotifier records the actions it was notified about
+type recordingNotifier struct {
+	notified []string
+	err      error
+}
+
+func (n *recordingNotifier) NotifyCompletion(ctx context.Context, action *v1alpha1.HealingAction) error {
+	n.notified = append(n.notified, action.Name+"="+action.Status.Phase)
+	return n.err
+}
+
+func TestHealingActionReconciler_NotifiesOnCompletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "timeout-action",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			Action:  v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			Timeout: metav1.Duration{Duration: time.Minute},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Phase:     v1alpha1.HealingActionPhaseInProgress,
+			StartTime: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(action).
+		WithStatusSubresource(action).
+		Build()
+
+	// Notification failures must not fail the reconcile
+	notifier := &recordingNotifier{err: errors.New("tracker unavailable")}
+	r := &HealingActionReconciler{
+		Client:            fakeClient,
+		Scheme:            scheme,
+		Config:            config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{},
+		SafetyController:  &MockSafetyController{},
+		Notifier:          notifier,
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: action.Name, Namespace: action.Namespace}}
+	finalAction, err := reconcileUntilPhase(t, r, req, v1alpha1.HealingActionPhaseFailed, 5)
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.HealingActionPhaseFailed, finalAction.Status.Phase)
+	assert.Equal(t, []string{"timeout-action=Failed"}, notifier.notified)
+}
+
+// reservingSafetyController allows a fixed number of action reservations
+type reservingSafetyController struct {
+	MockSafetyController
+	remaining int
+}
+
+func (r *reservingSafetyController) ReserveAction(ctx context.Context, action *v1alpha1.HealingAction) (bool, error) {
+	if r.remaining == 0 {
+		return false, nil
+	}
+	r.remaining--
+	return true, nil
+}
+
+func TestHealingActionReconciler_RateLimitReservation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	policy := pausedPolicy(false)
+	newAction := func(name string) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Finalizers: []string{FinalizerName}},
+			Spec: v1alpha1.HealingActionSpec{
+				PolicyRef: v1alpha1.PolicyReference{Name: policy.Name, Namespace: policy.Namespace},
+				Action:    v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			},
+			Status: v1alpha1.HealingActionStatus{Phase: v1alpha1.HealingActionPhaseApproved},
+		}
+	}
+	first, second := newAction("first"), newAction("second")
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(policy, first, second).
+		WithStatusSubresource(policy, first, second).
+		Build()
+	r := &HealingActionReconciler{
+		Client:            fakeClient,
+		Scheme:            scheme,
+		Config:            config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{},
+		SafetyController:  &reservingSafetyController{remaining: 1},
+	}
+
+	for _, tt := range []struct {
+		action         *v1alpha1.HealingAction
+		expectedPhase  string
+		expectedReason string
+	}{
+		{action: first, expectedPhase: v1alpha1.HealingActionPhaseInProgress, expectedReason: "Executing"},
+		{action: second, expectedPhase: v1alpha1.HealingActionPhaseApproved, expectedReason: ReasonRateLimited},
+	} {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: tt.action.Name, Namespace: tt.action.Namespace}}
+		result, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		updated := &v1alpha1.HealingAction{}
+		require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, updated))
+		assert.Equal(t, tt.expectedPhase, updated.Status.Phase, tt.action.Name)
+		cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeReady)
+		require.NotNil(t, cond)
+		assert.Equal(t, tt.expectedReason, cond.Reason, tt.action.Name)
+		if tt.expectedReason == ReasonRateLimited {
+			assert.Equal(t, time.Minute, result.RequeueAfter)
+		}
+	}
+}
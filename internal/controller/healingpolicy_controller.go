@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,9 +21,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/events"
 	"github.com/kubeskippy/kubeskippy/internal/metrics"
 	"github.com/kubeskippy/kubeskippy/internal/types"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
 )
 
 // HealingPolicyReconciler reconciles a HealingPolicy object
@@ -32,6 +36,30 @@ type HealingPolicyReconciler struct {
 	MetricsCollector MetricsCollector
 	SafetyController SafetyController
 	AIAnalyzer       AIAnalyzer
+
+	// Events optionally records trigger transitions as Kubernetes events
+	Events *events.Aggregator
+
+	// Notifier optionally reports trigger transitions
+	Notifier TriggerNotifier
+
+	// Escalations optionally reports targets healing is ineffective for
+	Escalations EscalationNotifier
+
+	// Watchdog optionally tracks reconciles and enforces safe mode
+	Watchdog Watchdog
+
+	// Calendar optionally holds automatic actions back during release
+	// freezes
+	Calendar ReleaseCalendar
+
+	// Stats optionally records AI analysis cache hits
+	Stats *metrics.CollectorStats
+
+	creator     *BatchCreator
+	creatorOnce sync.Once
+
+	aiCache aiAnalysisCache
 }
 
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingpolicies,verbs=get;list;watch;create;update;patch;delete
@@ -39,12 +67,16 @@ type HealingPolicyReconciler struct {
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingpolicies/finalizers,verbs=update
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingactions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kubeskippy.io,resources=healingactions/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups="",resources=pods;services;nodes;persistentvolumeclaims;configmaps;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=actiontemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods;services;nodes;persistentvolumeclaims;configmaps;secrets;namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets;replicasets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop
-func (r *HealingPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *HealingPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	done := trackReconcile(r.Watchdog, "healingpolicy")
+	defer func() { done(err) }()
+
 	log := log.FromContext(ctx)
 	log.Info("Reconciling HealingPolicy")
 
@@ -53,6 +85,7 @@ func (r *HealingPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("HealingPolicy not found, likely deleted")
+			triggerValues.prune(req.NamespacedName, nil)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get HealingPolicy")
@@ -74,24 +107,69 @@ func (r *HealingPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return r.handleDeletion(ctx, log, policy)
 	}
 
-	// Update status observed generation
-	if policy.Status.ObservedGeneration != policy.Generation {
-		policy.Status.ObservedGeneration = policy.Generation
-		if err := r.Status().Update(ctx, policy); err != nil {
-			log.Error(err, "Failed to update observed generation")
-			return ctrl.Result{}, err
+	// Status changes of the whole reconcile are written as one patch
+	patcher := newStatusPatcher(r.Client, "healingpolicy", policy)
+	defer func() {
+		if patchErr := patcher.Patch(ctx, policy); patchErr != nil {
+			log.Error(patchErr, "Failed to update status")
+			if err == nil {
+				result, err = ctrl.Result{}, patchErr
+			}
 		}
+	}()
+	policy.Status.ObservedGeneration = policy.Generation
+
+	// Warn when defaults filled in at admission are no longer current
+	setDefaultsDriftCondition(policy)
+
+	// Alerts cover the policy even while it is paused or rate-limited
+	if err := r.syncAlertRules(ctx, policy); err != nil {
+		log.Error(err, "Failed to sync alert rules")
+	}
+
+	// Paused policies keep their status but skip evaluation
+	setPausedCondition(policy)
+	if policy.Spec.Paused {
+		log.Info("Policy is paused, skipping evaluation")
+		return ctrl.Result{}, nil
+	}
+
+	// Reject actions that break the constraints of their ActionTemplate
+	if err := validatePolicyActions(ctx, r.Client, policy, r.Config.Safety.RequireActionTemplates); err != nil {
+		log.Info("Policy actions are invalid", "reason", err.Error())
+		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeReady,
+			metav1.ConditionFalse, ReasonInvalidActionTemplate, err.Error())
+		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
+
+	// Policies with dependsOn are evaluated once their dependencies settled
+	cycle, err := findDependencyCycle(ctx, r.Client, policy)
+	if err != nil {
+		log.Error(err, "Failed to check policy dependencies")
+		return ctrl.Result{}, err
+	}
+	if cycle != nil {
+		message := "Dependency cycle: " + strings.Join(cycle, " -> ")
+		log.Info("Policy dependencies are cyclic", "cycle", message)
+		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeReady,
+			metav1.ConditionFalse, ReasonDependencyCycle, message)
+		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
+	wait, err := r.waitForDependencies(ctx, log, policy)
+	if err != nil {
+		log.Error(err, "Failed to check policy dependencies")
+		return ctrl.Result{}, err
+	}
+	if wait {
+		return ctrl.Result{RequeueAfter: dependencyRecheckInterval}, nil
 	}
 
 	// Evaluate the policy
-	_, err := r.evaluatePolicy(ctx, log, policy)
+	_, err = r.evaluatePolicy(ctx, log, policy)
 	if err != nil {
 		log.Error(err, "Failed to evaluate policy")
 		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeReady,
 			metav1.ConditionFalse, ReasonValidationError, err.Error())
-		if err := r.Status().Update(ctx, policy); err != nil {
-			log.Error(err, "Failed to update status")
-		}
 		return ctrl.Result{RequeueAfter: 5 * time.Minute}, err
 	}
 
@@ -100,17 +178,19 @@ func (r *HealingPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeReady,
 		metav1.ConditionTrue, ReasonPolicyUpdated, "Policy evaluated successfully")
 
-	if err := r.Status().Update(ctx, policy); err != nil {
-		log.Error(err, "Failed to update status")
-		return ctrl.Result{}, err
-	}
-
 	// Requeue based on policy mode and evaluation interval
 	requeueAfter := 1 * time.Minute
 	if policy.Spec.Mode == "monitor" {
 		requeueAfter = 5 * time.Minute
 	}
 
+	// Wake up for the next scheduled run
+	if next, ok := nextScheduledRun(policy, time.Now()); ok {
+		if wait := time.Until(next) + time.Second; wait < requeueAfter {
+			requeueAfter = wait
+		}
+	}
+
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
@@ -130,15 +210,37 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 		return nil, fmt.Errorf("failed to collect metrics: %w", err)
 	}
 	
+	// Lite AI profiles analyze at most once per interval on sampled pods
+	budget := aiBudgetFor(policy)
+	aiDue := budget.due(lastAIAnalysis(policy), time.Now())
+	if !aiDue {
+		log.V(1).Info("Lite AI profile, skipping AI analysis until the next interval", "interval", budget.interval)
+	}
+
 	// Collect advanced metrics for AI analysis if available
 	var advancedMetrics interface{}
-	if advancedCollector, ok := r.MetricsCollector.(*metrics.AdvancedCollector); ok {
-		advancedMetrics, err = advancedCollector.CollectAdvancedMetrics(ctx, policy)
+	if advancedCollector, ok := r.MetricsCollector.(*metrics.AdvancedCollector); ok && aiDue {
+		advanced, err := advancedCollector.CollectSampledAdvancedMetrics(ctx, policy, budget.maxPods)
 		if err != nil {
 			log.Error(err, "Failed to collect advanced metrics, continuing with basic metrics")
+		} else {
+			advancedMetrics = advanced
+			markAIAnalysis(policy)
+			// Detected patterns fire pattern queries and reach the AI
+			clusterMetrics.Patterns = advanced.Patterns
 		}
 	}
 
+	// Pods replaced by the policy's own restarts would fire the triggers
+	// again while they start
+	triggerMetrics, selfInduced, err := r.excludeSelfInducedChurn(ctx, policy, clusterMetrics, time.Now())
+	if err != nil {
+		log.Error(err, "Failed to list actions for self-induced churn")
+	}
+	if selfInduced > 0 {
+		log.V(1).Info("Excluding self-induced churn from trigger evaluation", "pods", selfInduced)
+	}
+
 	// Check rate limits
 	if allowed, err := r.SafetyController.CheckRateLimit(ctx, policy); err != nil {
 		return nil, fmt.Errorf("failed to check rate limit: %w", err)
@@ -150,6 +252,10 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 	// Evaluate triggers
 	activeTriggers := []string{}
 	triggeredActions := []TriggeredAction{}
+	evaluated := make(map[string]bool)
+	reasons := make(map[string]string)
+	firing := make(map[string]map[string]bool)
+	var storms []string
 
 	for _, trigger := range policy.Spec.Triggers {
 		// Check cooldown
@@ -158,54 +264,66 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 			continue
 		}
 
-		// Evaluate trigger using advanced metrics if available for AI policies
-		var triggered bool
-		var reason string
-		var err error
-		
-		// Check if this is an AI-enabled policy and we have advanced metrics
-		isAIPolicy := policy.Annotations["kubeskippy.io/ai-enabled"] == "true"
-		if isAIPolicy && advancedMetrics != nil {
-			if advancedCollector, ok := r.MetricsCollector.(*metrics.AdvancedCollector); ok {
-				if advMetrics, ok := advancedMetrics.(*metrics.AdvancedMetrics); ok {
-					triggered, reason, err = advancedCollector.EvaluateAdvancedTrigger(ctx, &trigger, advMetrics)
-				} else {
-					triggered, reason, err = r.MetricsCollector.EvaluateTrigger(ctx, &trigger, clusterMetrics)
-				}
-			} else {
-				triggered, reason, err = r.MetricsCollector.EvaluateTrigger(ctx, &trigger, clusterMetrics)
-			}
+		// Templated queries are evaluated for every target
+		var result types.TriggerResult
+		var matches []targetMatch
+		if isTemplatedQuery(&trigger) {
+			result.Triggered, result.Reason, matches, err = r.evaluatePerTarget(ctx, policy, &trigger, triggerMetrics, advancedMetrics)
 		} else {
-			triggered, reason, err = r.MetricsCollector.EvaluateTrigger(ctx, &trigger, clusterMetrics)
+			result, err = r.evaluateTrigger(ctx, policy, &trigger, triggerMetrics, advancedMetrics)
 		}
-		
+		triggered, reason := result.Triggered, result.Reason
 		if err != nil {
 			log.Error(err, "Failed to evaluate trigger", "trigger", trigger.Name)
 			continue
 		}
 
-		log.Info("Trigger evaluation result", "trigger", trigger.Name, "type", trigger.Type, "triggered", triggered, "reason", reason)
+		log.V(1).Info("Trigger evaluation result", "trigger", trigger.Name, "type", trigger.Type, "triggered", triggered, "reason", reason)
+		evaluated[trigger.Name] = triggered
+		firing[trigger.Name] = make(map[string]bool)
+		if trigger.Type == "metric" {
+			recordTriggerSample(policy, &trigger, result, metav1.Now(), r.triggerHistorySize())
+			if triggerValues.record(policy, &trigger, result, r.triggerValueSeriesLimit()) {
+				log.Info("Trigger value series limit reached, not exporting some trigger values",
+					"trigger", trigger.Name, "limit", r.triggerValueSeriesLimit())
+			}
+		}
 
 		if triggered {
-			log.Info("Trigger activated", "trigger", trigger.Name, "reason", reason)
+			log.V(1).Info("Trigger active", "trigger", trigger.Name, "reason", reason)
+			reasons[trigger.Name] = reason
 			activeTriggers = append(activeTriggers, trigger.Name)
+			if trigger.Type == "restartStorm" {
+				storms = append(storms, reason)
+			}
 
 			// Find matching resources
-			resources, err := r.findMatchingResources(ctx, policy)
-			if err != nil {
-				log.Error(err, "Failed to find matching resources")
-				continue
+			if matches == nil {
+				resources, err := r.findMatchingResources(ctx, policy)
+				if err != nil {
+					log.Error(err, "Failed to find matching resources")
+					delete(firing, trigger.Name)
+					continue
+				}
+				resources = filterPodStateTargets(&trigger, resources, time.Now())
+				resources = filterStuckTargets(&trigger, resources, time.Now())
+				matches = targetMatches(&trigger, result, resources)
 			}
 
 			// Create triggered actions
-			for _, resource := range resources {
+			for _, match := range matches {
+				firing[trigger.Name][incidentTarget(match.resource)] = true
+				templateContext := NewTemplateContext(match.trigger, match.result, match.resource)
 				for _, actionTemplate := range policy.Spec.Actions {
-					triggeredActions = append(triggeredActions, TriggeredAction{
-						Trigger:  trigger.Name,
-						Resource: resource,
-						Action:   actionTemplate,
-						Reason:   reason,
-					})
+					ta := TriggeredAction{
+						Trigger:         trigger.Name,
+						Resource:        match.resource,
+						Action:          actionTemplate,
+						Reason:          match.result.Reason,
+						TemplateContext: templateContext,
+					}
+					applySeverity(policy, &trigger, &ta)
+					triggeredActions = append(triggeredActions, ta)
 				}
 			}
 		}
@@ -213,38 +331,145 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 
 	// Update active triggers in status
 	policy.Status.ActiveTriggers = activeTriggers
+	previousStates := append([]v1alpha1.TriggerState(nil), policy.Status.TriggerStates...)
+	now := metav1.Now()
+	updateTriggerStates(policy, evaluated, now)
+	r.reportTriggerTransitions(ctx, log, policy,
+		detectTriggerTransitions(policy, previousStates, reasons, r.triggerHeartbeat(), now))
+	pruneTriggerHistory(policy, r.triggerHistorySize())
+	triggerValues.prune(client.ObjectKeyFromObject(policy), policy)
 
 	// Process triggered actions
+	overrides := make(map[string]*ManualOverride)
+	targetHistory := make(map[string][]v1alpha1.HealingAction)
+	circuitOpen := make(map[string]bool)
+	acted := make(map[string]bool)
 	if len(triggeredActions) > 0 {
 		// Get AI recommendations if configured
-		if r.AIAnalyzer != nil && r.Config.AI.Provider != "" {
-			aiResult, err := r.getAIRecommendations(ctx, clusterMetrics, triggeredActions)
+		var aiResult *types.AIAnalysis
+		if r.AIAnalyzer != nil && r.Config.AI.Provider != "" && aiDue {
+			aiMetrics := clusterMetrics
+			if budget.lite {
+				aiMetrics = metrics.SamplePods(clusterMetrics, budget.maxPods, aiSampleKeys(triggeredActions))
+			}
+			var cached bool
+			aiResult, cached, err = r.analyzeActions(ctx, policy, aiMetrics, triggeredActions, time.Now())
+			if cached {
+				log.V(1).Info("Using cached AI analysis until the next analysis interval", "interval", aiAnalysisInterval(policy))
+			} else {
+				markAIAnalysis(policy)
+			}
 			if err != nil {
 				log.Error(err, "Failed to get AI recommendations")
+				aiResult = nil
 			} else {
-				triggeredActions = r.filterActionsWithAI(triggeredActions, aiResult)
+				// Restricted namespaces were never shown to the AI, so their
+				// actions keep the rule-based path
+				analyzed, withheld := partitionRestrictedActions(triggeredActions, aiResult.RestrictedNamespaces)
+				switch {
+				case len(analyzed) == 0:
+				case r.aiDecisionMode() == AIDecisionModeBlend:
+					analyzed = r.blendActionsWithAI(analyzed, aiResult)
+				default:
+					analyzed = r.filterActionsWithAI(analyzed, aiResult)
+				}
+				triggeredActions = append(analyzed, withheld...)
 			}
 		}
 
-		// Sort actions by priority
-		sort.Slice(triggeredActions, func(i, j int) bool {
+		// Prefer cluster-level responses over per-pod restarts during a storm
+		if len(storms) > 0 {
+			var skipped int
+			triggeredActions, skipped = preferClusterLevelActions(triggeredActions)
+			if skipped > 0 {
+				log.Info("Restart storm active, skipping per-pod actions", "skipped", skipped)
+			}
+		}
+
+		// Sort actions by priority, keeping the order of blended actions
+		// with the same priority
+		sort.SliceStable(triggeredActions, func(i, j int) bool {
 			return triggeredActions[i].Action.Priority > triggeredActions[j].Action.Priority
 		})
 
-		// Create healing actions
-		createdCount := 0
+		// Build and validate healing actions
+		maxActions := defaultMaxActionsPerEvaluation
+		if r.Config != nil && r.Config.Remediation.MaxActionsPerEvaluation > 0 {
+			maxActions = r.Config.Remediation.MaxActionsPerEvaluation
+		}
+		maxActions, err = r.actionBudget(ctx, policy, maxActions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rate limit: %w", err)
+		}
+		if maxActions < len(triggeredActions) {
+			log.V(1).Info("Limiting actions to the evaluation's budget", "triggered", len(triggeredActions), "budget", maxActions)
+		}
+
+		var planned []client.Object
+		var plannedTriggers []TriggeredAction
 		for _, ta := range triggeredActions {
-			if createdCount >= 5 { // Limit actions per evaluation
+			if len(planned) >= maxActions { // Limit actions per evaluation and hourly budget
 				break
 			}
 
-			action := CreateHealingAction(
-				policy,
-				ta.Resource,
-				&ta.Action,
-				policy.Spec.Mode == "dryrun",
-				ta.Trigger,
-			)
+			// Leave targets alone while a manual override is in effect
+			targetKey := fmt.Sprintf("%s/%s/%s", ta.Resource.GetObjectKind().GroupVersionKind().Kind,
+				ta.Resource.GetNamespace(), ta.Resource.GetName())
+			override, checked := overrides[targetKey]
+			if !checked {
+				override, err = r.detectManualOverride(ctx, policy, ta.Resource)
+				if err != nil {
+					log.Error(err, "Failed to check for manual overrides", "target", targetKey)
+				}
+				overrides[targetKey] = override
+			}
+			if override != nil {
+				log.Info("Skipping action on manually overridden target",
+					"target", targetKey, "manager", override.Manager, "pausedUntil", override.PausedUntil)
+				continue
+			}
+
+			// Don't duplicate pending actions or act during the target cooldown
+			history, fetched := targetHistory[targetKey]
+			if !fetched {
+				history, err = ListActionsForTarget(ctx, r, ta.Resource.GetObjectKind().GroupVersionKind().Kind,
+					ta.Resource.GetNamespace(), ta.Resource.GetName())
+				if err != nil {
+					log.Error(err, "Failed to list actions on target", "target", targetKey)
+				}
+				targetHistory[targetKey] = history
+			}
+			if pending := pendingDuplicate(history, policy, ta.Action.Name); pending != nil {
+				log.V(1).Info("Skipping action already pending on target",
+					"action", ta.Action.Name, "target", targetKey, "pending", pending.Name)
+				continue
+			}
+			if remaining := targetCooldownRemaining(history, policy, r.targetCooldown(), time.Now()); remaining > 0 {
+				log.V(1).Info("Skipping action on target in cooldown",
+					"action", ta.Action.Name, "target", targetKey, "remaining", remaining.Round(time.Second))
+				continue
+			}
+
+			action, err := r.buildHealingAction(ctx, policy, ta)
+			if err != nil {
+				log.Info("Skipping action", "action", ta.Action.Name, "reason", err.Error())
+				continue
+			}
+			if ta.IsAIBased {
+				action.Labels[LabelAIDriven] = "true"
+				if ta.AIRecommendation != nil {
+					action.Annotations[types.AnnotationAIReasoning] = aiReasoningSummary(ta.AIRecommendation)
+				}
+				if aiResult != nil {
+					action.Annotations[types.AnnotationAIModel] = aiResult.ModelVersion
+					action.Annotations[types.AnnotationAIPromptHash] = aiResult.PromptHash
+				}
+			}
+			if ta.PriorityDecision != nil {
+				if decision, err := priorityDecisionAnnotation(ta.PriorityDecision); err == nil {
+					action.Annotations[types.AnnotationPriorityDecision] = decision
+				}
+			}
 
 			// Validate action with safety controller
 			validation, err := r.SafetyController.ValidateAction(ctx, action)
@@ -256,28 +481,47 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 			if !validation.Valid {
 				log.Info("Action validation failed", "reason", validation.Reason,
 					"warnings", validation.Warnings)
+				if validation.CircuitOpen {
+					circuitOpen[targetKey] = true
+				}
 				continue
 			}
 
-			// Create the action
-			if err := r.Create(ctx, action); err != nil {
-				log.Error(err, "Failed to create healing action")
+			planned = append(planned, action)
+			plannedTriggers = append(plannedTriggers, ta)
+		}
+
+		// Create the actions in rate-limited batches
+		errs := r.createActions(ctx, log, policy, planned)
+		for i, obj := range planned {
+			action := obj.(*v1alpha1.HealingAction)
+			ta := plannedTriggers[i]
+			if errs[i] != nil {
+				log.Error(errs[i], "Failed to create healing action")
 				continue
 			}
 
+			acted[incidentKey(ta.Trigger, incidentTarget(ta.Resource))] = true
 			log.Info("Created healing action",
 				"action", action.Name,
 				"type", action.Spec.Action.Type,
 				"target", fmt.Sprintf("%s/%s", action.Spec.TargetResource.Kind, action.Spec.TargetResource.Name),
 				"ai_driven", ta.IsAIBased)
 
+			// Keep the AI's reasoning as an AIDecision linked to the action
+			if ta.IsAIBased && ta.AIRecommendation != nil {
+				if err := r.recordAIDecision(ctx, action, ta.AIRecommendation); err != nil {
+					log.Error(err, "Failed to record AI decision", "action", action.Name)
+				}
+			}
+
 			// Record healing action metrics
 			if metrics.GlobalAIMetrics != nil {
 				triggerType := "traditional"
 				if ta.IsAIBased {
 					triggerType = "ai"
 				}
-				
+
 				metrics.GlobalAIMetrics.RecordHealingAction(
 					ctx,
 					policy.Name,
@@ -289,12 +533,21 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 				)
 			}
 
-			createdCount++
 			policy.Status.ActionsTaken++
 			policy.Status.LastActionTime = metav1.Now()
 		}
 	}
 
+	for key, override := range overrides {
+		if override == nil {
+			delete(overrides, key)
+		}
+	}
+	setOverrideCondition(policy, overrides)
+	setRestartStormCondition(policy, storms)
+	recordRecoveries(policy, updateIncidents(policy, firing, acted, now), now)
+	r.escalateFailingTargets(ctx, log, policy, circuitOpen, now)
+
 	return &EvaluationResult{
 		ActiveTriggers:   activeTriggers,
 		ActionsCreated:   len(triggeredActions),
@@ -302,6 +555,119 @@ func (r *HealingPolicyReconciler) evaluatePolicy(ctx context.Context, log logr.L
 	}, nil
 }
 
+// evaluateTrigger evaluates a single trigger against the collected metrics
+func (r *HealingPolicyReconciler) evaluateTrigger(ctx context.Context, policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger, clusterMetrics *types.ClusterMetrics, advancedMetrics interface{}) (types.TriggerResult, error) {
+	// Evaluate trigger using advanced metrics if available for AI policies;
+	// other queries are answered by the cluster metrics
+	isAIPolicy := policy.Annotations["kubeskippy.io/ai-enabled"] == "true"
+	if isAIPolicy && advancedMetrics != nil && trigger.Type == "metric" &&
+		trigger.MetricTrigger != nil && metrics.IsAdvancedQuery(trigger.MetricTrigger.Query) {
+		if advancedCollector, ok := r.MetricsCollector.(*metrics.AdvancedCollector); ok {
+			if advMetrics, ok := advancedMetrics.(*metrics.AdvancedMetrics); ok {
+				return advancedCollector.EvaluateAdvancedTrigger(ctx, trigger, advMetrics)
+			}
+		}
+		return r.evaluateCollectorTrigger(ctx, trigger, clusterMetrics)
+	}
+
+	var triggered bool
+	var reason string
+	var err error
+	switch trigger.Type {
+	case "schedule":
+		if trigger.ScheduleTrigger == nil {
+			return types.TriggerResult{}, fmt.Errorf("schedule trigger configuration missing")
+		}
+		triggered, reason, err = triggers.EvaluateSchedule(trigger.ScheduleTrigger, policy.Status.LastEvaluated.Time, time.Now())
+	case "stuckTerminating":
+		triggered, reason, err = r.evaluateStuckTerminating(ctx, policy, trigger, time.Now())
+	default:
+		return r.evaluateCollectorTrigger(ctx, trigger, clusterMetrics)
+	}
+	return types.TriggerResult{Triggered: triggered, Reason: reason}, err
+}
+
+// evaluateCollectorTrigger evaluates a trigger with the metrics collector,
+// keeping the observed value when the collector reports it
+func (r *HealingPolicyReconciler) evaluateCollectorTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, clusterMetrics *types.ClusterMetrics) (types.TriggerResult, error) {
+	if evaluator, ok := r.MetricsCollector.(TriggerResultEvaluator); ok {
+		return evaluator.EvaluateTriggerResult(ctx, trigger, clusterMetrics)
+	}
+	triggered, reason, err := r.MetricsCollector.EvaluateTrigger(ctx, trigger, clusterMetrics)
+	return types.TriggerResult{Triggered: triggered, Reason: reason}, err
+}
+
+// buildHealingAction resolves, renders and constrains the action template of
+// a triggered action and returns the HealingAction it would create
+func (r *HealingPolicyReconciler) buildHealingAction(ctx context.Context, policy *v1alpha1.HealingPolicy, ta TriggeredAction) (*v1alpha1.HealingAction, error) {
+	// Apply the referenced ActionTemplate
+	resolved, constraints, err := resolveActionTemplate(ctx, r.Client, &ta.Action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ActionTemplate: %w", err)
+	}
+
+	// Evaluate templated parameters so the action stores concrete values
+	actionTemplate, templatedFields, err := RenderActionTemplate(resolved, ta.TemplateContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render action template: %w", err)
+	}
+	if constraints != nil {
+		if err := constraints.Validate(actionTemplate); err != nil {
+			return nil, fmt.Errorf("rendered action violates its ActionTemplate: %w", err)
+		}
+	}
+	aiPatched, err := applyAIPatch(actionTemplate, ta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Targets outside a progressive rollout keep running as dry-runs, and
+	// so does every action while the operator is in safe mode and every
+	// automatic action during a release freeze
+	excluded := policy.Spec.Mode == "automatic" && !inRollout(policy, ta.Resource)
+	safeMode := policy.Spec.Mode != "dryrun" && inSafeMode(r.Watchdog)
+	blackout := r.activeBlackout(ctx, policy)
+	action := CreateHealingAction(
+		policy,
+		ta.Resource,
+		actionTemplate,
+		policy.Spec.Mode == "dryrun" || excluded || safeMode || blackout != "",
+		ta.Trigger,
+	)
+	if excluded {
+		action.Annotations[AnnotationRolloutExcluded] = "true"
+	}
+	if safeMode {
+		action.Annotations[AnnotationSafeMode] = "true"
+	}
+	if blackout != "" {
+		action.Annotations[AnnotationBlackout] = blackout
+	}
+	// Removing finalizers that are not known to be safe is never automatic
+	if ta.Severity != "" {
+		action.Labels[LabelSeverity] = ta.Severity
+		action.Status.Severity = ta.Severity
+		if err := checkSeverityRule(policy, ta.Severity, action); err != nil {
+			return nil, err
+		}
+	}
+	if isForceDelete(actionTemplate) {
+		action.Spec.ApprovalRequired = true
+		action.Status.Approval = &v1alpha1.ApprovalStatus{Required: true}
+		action.Annotations[AnnotationForceDelete] = "true"
+	}
+	if aiPatched {
+		action.Annotations[AnnotationAIPatch] = "true"
+	}
+	if len(templatedFields) > 0 {
+		action.Annotations[AnnotationTemplatedFields] = strings.Join(templatedFields, ",")
+	}
+	if ta.Reason != "" {
+		action.Annotations[types.AnnotationTriggerReason] = ta.Reason
+	}
+	return action, nil
+}
+
 // findMatchingResources finds resources that match the policy selector
 func (r *HealingPolicyReconciler) findMatchingResources(ctx context.Context, policy *v1alpha1.HealingPolicy) ([]client.Object, error) {
 	matcher := NewPolicyMatcher(policy)
@@ -323,6 +689,8 @@ func (r *HealingPolicyReconciler) findMatchingResources(ctx context.Context, pol
 			list = &corev1.ServiceList{}
 		case "PersistentVolumeClaim":
 			list = &corev1.PersistentVolumeClaimList{}
+		case "Node":
+			list = &corev1.NodeList{}
 		default:
 			// Skip unknown resource types for now
 			continue
@@ -330,7 +698,7 @@ func (r *HealingPolicyReconciler) findMatchingResources(ctx context.Context, pol
 
 		// List resources
 		listOpts := []client.ListOption{}
-		if len(policy.Spec.Selector.Namespaces) > 0 {
+		if len(policy.Spec.Selector.Namespaces) > 0 && rf.Kind != "Node" {
 			// List in specific namespaces
 			for _, ns := range policy.Spec.Selector.Namespaces {
 				nsListOpts := append(listOpts, client.InNamespace(ns))
@@ -379,6 +747,14 @@ func (r *HealingPolicyReconciler) findMatchingResources(ctx context.Context, pol
 	return resources, nil
 }
 
+// targetCooldown returns the configured per-target cooldown
+func (r *HealingPolicyReconciler) targetCooldown() time.Duration {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.Safety.TargetCooldown
+}
+
 // checkCooldown checks if a trigger is in cooldown
 func (r *HealingPolicyReconciler) checkCooldown(policy *v1alpha1.HealingPolicy, triggerName string, cooldown time.Duration) bool {
 	// Check last action time for this trigger
@@ -392,25 +768,56 @@ func (r *HealingPolicyReconciler) checkCooldown(policy *v1alpha1.HealingPolicy,
 	return true
 }
 
-// getAIRecommendations gets AI recommendations for triggered actions
-func (r *HealingPolicyReconciler) getAIRecommendations(ctx context.Context, clusterMetrics *types.ClusterMetrics, actions []TriggeredAction) (*types.AIAnalysis, error) {
+// getAIRecommendations gets AI recommendations for triggered actions. If
+// policy is set, the progress of a streamed analysis is reported on its
+// status.
+func (r *HealingPolicyReconciler) getAIRecommendations(ctx context.Context, policy *v1alpha1.HealingPolicy, clusterMetrics *types.ClusterMetrics, actions []TriggeredAction) (*types.AIAnalysis, error) {
 	// Convert triggered actions to issues
 	issues := make([]types.Issue, len(actions))
 	for i, action := range actions {
 		issues[i] = types.Issue{
 			ID:          fmt.Sprintf("%s-%s", action.Trigger, action.Resource.GetName()),
-			Severity:    "medium",
+			Severity:    aiIssueSeverity(action.Severity),
 			Type:        action.Trigger,
 			Resource:    ResourceKey(action.Resource),
+			Namespace:   action.Resource.GetNamespace(),
 			Description: action.Reason,
 			DetectedAt:  time.Now(),
 		}
 	}
 
-	// Get AI analysis
+	// Get AI analysis, streaming its progress if the analyzer supports it
+	if streaming, ok := r.AIAnalyzer.(StreamingAIAnalyzer); ok && policy != nil && r.aiProgressInterval() > 0 {
+		progress, finish := r.reportAIProgress(ctx, policy)
+		analysis, err := streaming.AnalyzeClusterStateStreaming(ctx, clusterMetrics, issues, progress)
+		finish(analysis, err)
+		return analysis, err
+	}
 	return r.AIAnalyzer.AnalyzeClusterState(ctx, clusterMetrics, issues)
 }
 
+// partitionRestrictedActions splits actions into those the AI analyzed and
+// those in namespaces withheld by the AI data policy
+func partitionRestrictedActions(actions []TriggeredAction, restricted []string) ([]TriggeredAction, []TriggeredAction) {
+	if len(restricted) == 0 {
+		return actions, nil
+	}
+	withheldNS := make(map[string]bool, len(restricted))
+	for _, ns := range restricted {
+		withheldNS[ns] = true
+	}
+
+	var analyzed, withheld []TriggeredAction
+	for _, action := range actions {
+		if withheldNS[action.Resource.GetNamespace()] {
+			withheld = append(withheld, action)
+			continue
+		}
+		analyzed = append(analyzed, action)
+	}
+	return analyzed, withheld
+}
+
 // filterActionsWithAI filters actions based on AI recommendations
 func (r *HealingPolicyReconciler) filterActionsWithAI(actions []TriggeredAction, aiResult *types.AIAnalysis) []TriggeredAction {
 	if aiResult == nil || len(aiResult.Recommendations) == 0 {
@@ -427,7 +834,7 @@ func (r *HealingPolicyReconciler) filterActionsWithAI(actions []TriggeredAction,
 	// Process each AI recommendation
 	for _, recommendation := range aiResult.Recommendations {
 		// Only proceed with high-confidence recommendations
-		minConfidence := 0.7 // 70% confidence threshold
+		minConfidence := minAIConfidence
 		if recommendation.Confidence < minConfidence {
 			log.Log.Info("Skipping low confidence AI recommendation", 
 				"action", recommendation.Action, 
@@ -436,25 +843,6 @@ func (r *HealingPolicyReconciler) filterActionsWithAI(actions []TriggeredAction,
 			continue
 		}
 
-		// Record AI decision start
-		if metrics.GlobalAIMetrics != nil {
-			decision := &metrics.AIDecision{
-				ID:             fmt.Sprintf("ai-%d", time.Now().Unix()),
-				PolicyName:     actions[0].Resource.GetName(), // Use first action's resource as policy context
-				TriggerType:    "ai",
-				ActionType:     recommendation.Action,
-				Confidence:     recommendation.Confidence,
-				ReasoningSteps: extractReasoningSteps(recommendation),
-				Alternatives:   extractAlternatives(recommendation),
-				RiskAssessment: recommendation.Reasoning.DecisionLogic,
-				ExpectedOutcome: fmt.Sprintf("AI-driven %s with %.1f%% confidence", 
-					recommendation.Action, recommendation.Confidence*100),
-			}
-			
-			ctx := context.Background()
-			metrics.GlobalAIMetrics.StartAIDecision(ctx, decision)
-		}
-
 		// Find matching triggered actions for this AI recommendation
 		for _, action := range actions {
 			if r.matchesAIRecommendation(action, recommendation) {
@@ -481,7 +869,7 @@ func (r *HealingPolicyReconciler) filterActionsWithAI(actions []TriggeredAction,
 			return actions[i].Action.Priority > actions[j].Action.Priority
 		})
 		
-		maxFallback := 2
+		maxFallback := maxAIFallbackActions
 		if len(actions) < maxFallback {
 			maxFallback = len(actions)
 		}
@@ -502,6 +890,20 @@ func (r *HealingPolicyReconciler) filterActionsWithAI(actions []TriggeredAction,
 
 // Helper functions for AI decision processing
 
+// aiReasoningSummary condenses a recommendation's reasoning for the action
+// annotations
+func aiReasoningSummary(recommendation *types.AIRecommendation) string {
+	parts := []string{}
+	if recommendation.Reason != "" {
+		parts = append(parts, recommendation.Reason)
+	}
+	if recommendation.Reasoning.DecisionLogic != "" {
+		parts = append(parts, recommendation.Reasoning.DecisionLogic)
+	}
+	parts = append(parts, fmt.Sprintf("confidence %.0f%%", recommendation.Confidence*100))
+	return strings.Join(parts, "; ")
+}
+
 func extractReasoningSteps(recommendation types.AIRecommendation) []string {
 	steps := []string{}
 	
@@ -604,6 +1006,9 @@ func (r *HealingPolicyReconciler) handleDeletion(ctx context.Context, log logr.L
 		}
 	}
 
+	r.aiCache.forget(client.ObjectKeyFromObject(policy))
+	triggerValues.prune(client.ObjectKeyFromObject(policy), nil)
+
 	// Remove finalizer
 	controllerutil.RemoveFinalizer(policy, FinalizerName)
 	if err := r.Update(ctx, policy); err != nil {
@@ -617,12 +1022,13 @@ func (r *HealingPolicyReconciler) handleDeletion(ctx context.Context, log logr.L
 // SetupWithManager sets up the controller with the Manager
 func (r *HealingPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Create indices for efficient lookups
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.HealingAction{}, "spec.policyRef.name", func(rawObj client.Object) []string {
-		action := rawObj.(*v1alpha1.HealingAction)
-		return []string{action.Spec.PolicyRef.Name}
-	}); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.HealingAction{}, IndexActionPolicy, indexActionPolicy); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget); err != nil {
 		return err
 	}
+	r.Stats.RegisterStore("aiAnalysisCache.entries", r.aiCache.size)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.HealingPolicy{}).
@@ -647,4 +1053,7 @@ type TriggeredAction struct {
 	Reason           string
 	IsAIBased        bool
 	AIRecommendation *types.AIRecommendation
+	PriorityDecision *v1alpha1.PriorityDecision
+	TemplateContext  TemplateContext
+	Severity         string
 }
@@ -16,50 +16,53 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
 	"github.com/kubeskippy/kubeskippy/pkg/config"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
 )
 
 // MockMetricsCollector implements MetricsCollector interface for testing
 type MockMetricsCollector struct {
-	CollectMetricsFunc     func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ClusterMetrics, error)
-	EvaluateTriggerFunc    func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ClusterMetrics) (bool, string, error)
-	GetResourceMetricsFunc func(ctx context.Context, resource *v1alpha1.TargetResource) (*ResourceMetrics, error)
+	CollectMetricsFunc     func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error)
+	EvaluateTriggerFunc    func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error)
+	GetResourceMetricsFunc func(ctx context.Context, resource *v1alpha1.TargetResource) (*ktypes.ResourceMetrics, error)
 }
 
-func (m *MockMetricsCollector) CollectMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ClusterMetrics, error) {
+func (m *MockMetricsCollector) CollectMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error) {
 	if m.CollectMetricsFunc != nil {
 		return m.CollectMetricsFunc(ctx, policy)
 	}
-	return &ClusterMetrics{Timestamp: time.Now()}, nil
+	return &ktypes.ClusterMetrics{Timestamp: time.Now()}, nil
 }
 
-func (m *MockMetricsCollector) EvaluateTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ClusterMetrics) (bool, string, error) {
+func (m *MockMetricsCollector) EvaluateTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
 	if m.EvaluateTriggerFunc != nil {
 		return m.EvaluateTriggerFunc(ctx, trigger, metrics)
 	}
 	return false, "", nil
 }
 
-func (m *MockMetricsCollector) GetResourceMetrics(ctx context.Context, resource *v1alpha1.TargetResource) (*ResourceMetrics, error) {
+func (m *MockMetricsCollector) GetResourceMetrics(ctx context.Context, resource *v1alpha1.TargetResource) (*ktypes.ResourceMetrics, error) {
 	if m.GetResourceMetricsFunc != nil {
 		return m.GetResourceMetricsFunc(ctx, resource)
 	}
-	return &ResourceMetrics{}, nil
+	return &ktypes.ResourceMetrics{}, nil
 }
 
 // MockSafetyController implements SafetyController interface for testing
 type MockSafetyController struct {
-	ValidateActionFunc      func(ctx context.Context, action *v1alpha1.HealingAction) (*ValidationResult, error)
+	ValidateActionFunc      func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error)
 	CheckRateLimitFunc      func(ctx context.Context, policy *v1alpha1.HealingPolicy) (bool, error)
 	IsProtectedResourceFunc func(resource runtime.Object) (bool, string)
-	RecordActionFunc        func(ctx context.Context, action *v1alpha1.HealingAction, result *ActionResult)
+	RecordActionFunc        func(ctx context.Context, action *v1alpha1.HealingAction, result *ktypes.ActionResult)
 }
 
-func (m *MockSafetyController) ValidateAction(ctx context.Context, action *v1alpha1.HealingAction) (*ValidationResult, error) {
+func (m *MockSafetyController) ValidateAction(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error) {
 	if m.ValidateActionFunc != nil {
 		return m.ValidateActionFunc(ctx, action)
 	}
-	return &ValidationResult{Valid: true}, nil
+	return &ktypes.ValidationResult{Valid: true}, nil
 }
 
 func (m *MockSafetyController) CheckRateLimit(ctx context.Context, policy *v1alpha1.HealingPolicy) (bool, error) {
@@ -76,7 +79,7 @@ func (m *MockSafetyController) IsProtectedResource(resource runtime.Object) (boo
 	return false, ""
 }
 
-func (m *MockSafetyController) RecordAction(ctx context.Context, action *v1alpha1.HealingAction, result *ActionResult) {
+func (m *MockSafetyController) RecordAction(ctx context.Context, action *v1alpha1.HealingAction, result *ktypes.ActionResult) {
 	if m.RecordActionFunc != nil {
 		m.RecordActionFunc(ctx, action, result)
 	}
@@ -92,10 +95,10 @@ func TestHealingPolicyReconciler_Reconcile(t *testing.T) {
 		name           string
 		policy         *v1alpha1.HealingPolicy
 		existingObjs   []client.Object
-		metricsFunc    func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ClusterMetrics, error)
-		triggerFunc    func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ClusterMetrics) (bool, string, error)
+		metricsFunc    func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error)
+		triggerFunc    func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error)
 		rateLimitFunc  func(ctx context.Context, policy *v1alpha1.HealingPolicy) (bool, error)
-		validateFunc   func(ctx context.Context, action *v1alpha1.HealingAction) (*ValidationResult, error)
+		validateFunc   func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error)
 		expectedResult reconcile.Result
 		expectedError  bool
 		checkFunc      func(t *testing.T, client client.Client)
@@ -143,8 +146,9 @@ func TestHealingPolicyReconciler_Reconcile(t *testing.T) {
 			existingObjs: []client.Object{
 				&v1alpha1.HealingPolicy{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-policy",
-						Namespace: "default",
+						Name:       "test-policy",
+						Namespace:  "default",
+						Finalizers: []string{FinalizerName},
 					},
 					Spec: v1alpha1.HealingPolicySpec{
 						Mode: "monitor",
@@ -197,8 +201,9 @@ func TestHealingPolicyReconciler_Reconcile(t *testing.T) {
 			existingObjs: []client.Object{
 				&v1alpha1.HealingPolicy{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-policy",
-						Namespace: "default",
+						Name:       "test-policy",
+						Namespace:  "default",
+						Finalizers: []string{FinalizerName},
 					},
 					Spec: v1alpha1.HealingPolicySpec{
 						Mode: "automatic",
@@ -261,8 +266,9 @@ func TestHealingPolicyReconciler_Reconcile(t *testing.T) {
 			existingObjs: []client.Object{
 				&v1alpha1.HealingPolicy{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-policy",
-						Namespace: "default",
+						Name:       "test-policy",
+						Namespace:  "default",
+						Finalizers: []string{FinalizerName},
 					},
 					Spec: v1alpha1.HealingPolicySpec{
 						Mode: "automatic",
@@ -294,7 +300,7 @@ func TestHealingPolicyReconciler_Reconcile(t *testing.T) {
 					},
 				},
 			},
-			triggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ClusterMetrics) (bool, string, error) {
+			triggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
 				return true, "High restart count detected", nil
 			},
 			expectedResult: reconcile.Result{RequeueAfter: 1 * time.Minute},
@@ -320,6 +326,7 @@ func TestHealingPolicyReconciler_Reconcile(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().
 				WithScheme(scheme).
 				WithObjects(tt.existingObjs...).
+				WithStatusSubresource(&v1alpha1.HealingPolicy{}, &v1alpha1.HealingAction{}).
 				Build()
 
 			// Create mocks
@@ -432,3 +439,58 @@ func TestHealingPolicyReconciler_findMatchingResources(t *testing.T) {
 	require.Len(t, resources, 1)
 	assert.Equal(t, "pod1", resources[0].GetName())
 }
+
+func TestPartitionRestrictedActions(t *testing.T) {
+	pod := func(ns, name string) TriggeredAction {
+		return TriggeredAction{Resource: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}}
+	}
+	actions := []TriggeredAction{pod("web", "a"), pod("payments", "b"), pod("web", "c")}
+
+	analyzed, withheld := partitionRestrictedActions(actions, nil)
+	assert.Equal(t, actions, analyzed)
+	assert.Empty(t, withheld)
+
+	analyzed, withheld = partitionRestrictedActions(actions, []string{"payments"})
+	require.Len(t, analyzed, 2)
+	require.Len(t, withheld, 1)
+	assert.Equal(t, "b", withheld[0].Resource.GetName())
+}
+
+func TestEvaluateTrigger_AdvancedQueries(t *testing.T) {
+	r := &HealingPolicyReconciler{MetricsCollector: metrics.NewAdvancedCollector(metrics.NewCollector(nil, nil, nil))}
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{
+		Name:        "web",
+		Annotations: map[string]string{"kubeskippy.io/ai-enabled": "true"},
+	}}
+	patterns := []pkgtypes.Pattern{{Name: "leak", Target: "apps/web-1", Description: "memory leak", Detector: "leaks"}}
+	clusterMetrics := &ktypes.ClusterMetrics{
+		Pods:     []ktypes.PodMetrics{{Name: "web-1", Namespace: "apps", RestartCount: 7}},
+		Patterns: patterns,
+	}
+	advanced := &metrics.AdvancedMetrics{SystemHealthScore: 40, Patterns: patterns}
+
+	tests := []struct {
+		query     string
+		threshold float64
+		wantValue float64
+	}{
+		{query: "system_health_score", threshold: 50, wantValue: 40},
+		{query: "pattern:leaks", threshold: 2, wantValue: 1},
+		{query: "pod_restarts", threshold: 10, wantValue: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			trigger := &v1alpha1.HealingTrigger{
+				Name:          "check",
+				Type:          "metric",
+				MetricTrigger: &v1alpha1.MetricTrigger{Query: tt.query, Threshold: tt.threshold, Operator: "<"},
+			}
+			result, err := r.evaluateTrigger(context.Background(), policy, trigger, clusterMetrics, advanced)
+			require.NoError(t, err)
+			assert.True(t, result.Observed)
+			assert.Equal(t, tt.wantValue, result.Value)
+			assert.True(t, result.Triggered)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// LabelBundle marks policies managed by a HealingPolicyBundle
+	LabelBundle = "kubeskippy.io/bundle"
+
+	// ReasonBundleApplied is set when the bundle's policies are up to date
+	ReasonBundleApplied = "BundleApplied"
+
+	// ReasonBundleInvalid is set when the bundle's variables or policies
+	// cannot be resolved
+	ReasonBundleInvalid = "BundleInvalid"
+
+	// bundleResyncInterval is how often bundles resolve their variables
+	// again, picking up changed ConfigMap values
+	bundleResyncInterval = 5 * time.Minute
+)
+
+// HealingPolicyBundleReconciler manages the policies of a HealingPolicyBundle
+type HealingPolicyBundleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingpolicybundles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingpolicybundles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Reconcile resolves the bundle's variables and creates, updates and
+// deletes its policies. Nothing is changed unless every policy resolves.
+func (r *HealingPolicyBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	bundle := &v1alpha1.HealingPolicyBundle{}
+	if err := r.Get(ctx, req.NamespacedName, bundle); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	patcher := newStatusPatcher(r.Client, "healingpolicybundle", bundle)
+
+	policies, err := r.renderBundle(ctx, bundle)
+	if err != nil {
+		log.Info("Bundle is invalid", "reason", err.Error())
+		SetCondition(&bundle.Status.Conditions, v1alpha1.ConditionTypeReady, metav1.ConditionFalse,
+			ReasonBundleInvalid, err.Error())
+		bundle.Status.ObservedGeneration = bundle.Generation
+		if err := patcher.Patch(ctx, bundle); err != nil {
+			log.Error(err, "Failed to update bundle status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: bundleResyncInterval}, nil
+	}
+
+	names := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		if err := r.applyBundledPolicy(ctx, bundle, policy); err != nil {
+			log.Error(err, "Failed to apply bundled policy", "policy", policy.Name)
+			return ctrl.Result{}, err
+		}
+		names = append(names, policy.Name)
+	}
+	if err := r.pruneBundledPolicies(ctx, bundle, names); err != nil {
+		log.Error(err, "Failed to delete policies removed from the bundle")
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	bundle.Status.Policies = names
+	bundle.Status.LastResolved = &now
+	bundle.Status.ObservedGeneration = bundle.Generation
+	SetCondition(&bundle.Status.Conditions, v1alpha1.ConditionTypeReady, metav1.ConditionTrue,
+		ReasonBundleApplied, fmt.Sprintf("Applied %d policies", len(names)))
+	if err := patcher.Patch(ctx, bundle); err != nil {
+		log.Error(err, "Failed to update bundle status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: bundleResyncInterval}, nil
+}
+
+// renderBundle returns the bundle's policies with the variables resolved,
+// defaults applied and each policy depending on the one before it
+func (r *HealingPolicyBundleReconciler) renderBundle(ctx context.Context, bundle *v1alpha1.HealingPolicyBundle) ([]*v1alpha1.HealingPolicy, error) {
+	variables, err := resolveBundleVariables(ctx, r.Client, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(bundle.Spec.Policies))
+	policies := make([]*v1alpha1.HealingPolicy, 0, len(bundle.Spec.Policies))
+	for i := range bundle.Spec.Policies {
+		bundled := &bundle.Spec.Policies[i]
+		if seen[bundled.Name] {
+			return nil, fmt.Errorf("policy %q is defined more than once", bundled.Name)
+		}
+		seen[bundled.Name] = true
+
+		spec, err := renderBundledPolicy(bundled, variables)
+		if err != nil {
+			return nil, err
+		}
+		policy := &v1alpha1.HealingPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bundledPolicyName(bundle, bundled),
+				Namespace: bundle.Namespace,
+				Labels:    map[string]string{LabelBundle: bundle.Name},
+			},
+			Spec: *spec,
+		}
+		for key, value := range bundled.Labels {
+			policy.Labels[key] = value
+		}
+		if i > 0 {
+			addDependency(policy, policies[i-1].Name)
+		}
+		if err := policy.RecordAppliedDefaults(policy.ApplyDefaults()); err != nil {
+			return nil, fmt.Errorf("policy %q: failed to record defaults: %w", bundled.Name, err)
+		}
+		if errs := policy.Validate(); len(errs) > 0 {
+			return nil, fmt.Errorf("policy %q: %s", bundled.Name, errs.ToAggregate().Error())
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// bundledPolicyName returns the name of the HealingPolicy of a bundled policy
+func bundledPolicyName(bundle *v1alpha1.HealingPolicyBundle, bundled *v1alpha1.BundledPolicy) string {
+	return bundle.Name + "-" + bundled.Name
+}
+
+// addDependency makes the policy depend on a policy in its namespace
+func addDependency(policy *v1alpha1.HealingPolicy, name string) {
+	for _, dep := range policy.Spec.DependsOn {
+		if dep.Name == name && (dep.Namespace == "" || dep.Namespace == policy.Namespace) {
+			return
+		}
+	}
+	policy.Spec.DependsOn = append(policy.Spec.DependsOn, v1alpha1.PolicyDependency{Name: name})
+}
+
+// applyBundledPolicy creates or updates a policy of the bundle. Policies
+// not created by the bundle are left alone.
+func (r *HealingPolicyBundleReconciler) applyBundledPolicy(ctx context.Context, bundle *v1alpha1.HealingPolicyBundle, desired *v1alpha1.HealingPolicy) error {
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		if policy.ResourceVersion != "" && !metav1.IsControlledBy(policy, bundle) {
+			return fmt.Errorf("policy %s already exists and is not managed by the bundle", policy.Name)
+		}
+		if policy.Labels == nil {
+			policy.Labels = make(map[string]string, len(desired.Labels))
+		}
+		for key, value := range desired.Labels {
+			policy.Labels[key] = value
+		}
+		// Keep the defaults recorded when the policy was created
+		if err := policy.RecordAppliedDefaults(desired.AppliedDefaults()); err != nil {
+			return err
+		}
+		policy.Spec = desired.Spec
+		return controllerutil.SetControllerReference(bundle, policy, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply policy %s: %w", desired.Name, err)
+	}
+	return nil
+}
+
+// pruneBundledPolicies deletes the bundle's policies that are no longer part
+// of it
+func (r *HealingPolicyBundleReconciler) pruneBundledPolicies(ctx context.Context, bundle *v1alpha1.HealingPolicyBundle, keep []string) error {
+	policies := &v1alpha1.HealingPolicyList{}
+	if err := r.List(ctx, policies, client.InNamespace(bundle.Namespace),
+		client.MatchingLabels{LabelBundle: bundle.Name}); err != nil {
+		return fmt.Errorf("failed to list policies of bundle %s: %w", bundle.Name, err)
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[name] = true
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if kept[policy.Name] || !metav1.IsControlledBy(policy, bundle) {
+			continue
+		}
+		if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete policy %s: %w", policy.Name, err)
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *HealingPolicyBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.HealingPolicyBundle{}).
+		Owns(&v1alpha1.HealingPolicy{}).
+		Complete(r)
+}
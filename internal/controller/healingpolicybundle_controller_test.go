@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func bundleScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func testBundle(t *testing.T, variables []v1alpha1.BundleVariable, specs ...string) *v1alpha1.HealingPolicyBundle {
+	bundle := &v1alpha1.HealingPolicyBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", UID: "bundle-uid"},
+		Spec:       v1alpha1.HealingPolicyBundleSpec{Variables: variables},
+	}
+	names := []string{"nodes", "pods", "workloads"}
+	for i, spec := range specs {
+		data, err := yaml.YAMLToJSON([]byte(spec))
+		require.NoError(t, err)
+		bundle.Spec.Policies = append(bundle.Spec.Policies, v1alpha1.BundledPolicy{
+			Name: names[i],
+			Spec: runtime.RawExtension{Raw: data},
+		})
+	}
+	return bundle
+}
+
+const bundledPolicySpec = `
+mode: $(mode)
+selector:
+  namespaces: $(namespaces)
+  resources:
+  - apiVersion: v1
+    kind: Pod
+triggers:
+- name: restarts
+  type: metric
+  metricTrigger:
+    query: pod_restarts
+    threshold: $(threshold)
+    operator: ">"
+actions:
+- name: restart-$(mode)
+  type: restart
+`
+
+func reconcileBundle(t *testing.T, c client.Client, bundle *v1alpha1.HealingPolicyBundle) (*v1alpha1.HealingPolicyBundle, error) {
+	r := &HealingPolicyBundleReconciler{Client: c, Scheme: c.Scheme()}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(bundle)})
+	updated := &v1alpha1.HealingPolicyBundle{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(bundle), updated))
+	return updated, err
+}
+
+func TestHealingPolicyBundleReconcile(t *testing.T) {
+	variables := []v1alpha1.BundleVariable{
+		{Name: "mode", Value: "dryrun"},
+		{Name: "namespaces", Value: `["shop", "payments"]`},
+		{Name: "threshold", ValueFrom: &v1alpha1.BundleVariableSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "shop-healing"},
+			Key:                  "threshold",
+		}}},
+	}
+	bundle := testBundle(t, variables, bundledPolicySpec, bundledPolicySpec, bundledPolicySpec)
+	tuning := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop-healing", Namespace: "default"},
+		Data:       map[string]string{"threshold": "5"},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(bundleScheme(t)).
+		WithObjects(bundle, tuning).
+		WithStatusSubresource(bundle).
+		Build()
+
+	updated, err := reconcileBundle(t, c, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop-nodes", "shop-pods", "shop-workloads"}, updated.Status.Policies)
+	cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	policy := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "shop-pods"}, policy))
+	assert.Equal(t, "dryrun", policy.Spec.Mode)
+	assert.Equal(t, []string{"shop", "payments"}, policy.Spec.Selector.Namespaces, "list variables keep their type")
+	assert.Equal(t, 5.0, policy.Spec.Triggers[0].MetricTrigger.Threshold, "number variables keep their type")
+	assert.Equal(t, "restart-dryrun", policy.Spec.Actions[0].Name, "variables are substituted within strings")
+	assert.Equal(t, []v1alpha1.PolicyDependency{{Name: "shop-nodes"}}, policy.Spec.DependsOn, "policies depend on the one before them")
+	assert.Equal(t, "shop", policy.Labels[LabelBundle])
+	assert.True(t, metav1.IsControlledBy(policy, bundle))
+	assert.Equal(t, v1alpha1.DefaultCooldownPeriod, policy.Spec.Triggers[0].CooldownPeriod.Duration, "defaults are applied")
+
+	// Variables are resolved again on every reconcile
+	tuning.Data["threshold"] = "8"
+	require.NoError(t, c.Update(context.Background(), tuning))
+	// and removed policies are deleted
+	updated.Spec.Policies = updated.Spec.Policies[:2]
+	require.NoError(t, c.Update(context.Background(), updated))
+
+	updated, err = reconcileBundle(t, c, updated)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop-nodes", "shop-pods"}, updated.Status.Policies)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "shop-pods"}, policy))
+	assert.Equal(t, 8.0, policy.Spec.Triggers[0].MetricTrigger.Threshold)
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "shop-workloads"}, policy)
+	assert.True(t, client.IgnoreNotFound(err) == nil && err != nil, "policies removed from the bundle are deleted")
+}
+
+func TestHealingPolicyBundleReconcile_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []v1alpha1.BundleVariable
+		spec      string
+		wantErr   string
+	}{
+		{
+			name:    "undefined variable",
+			spec:    bundledPolicySpec,
+			wantErr: `policy "nodes" references undefined variables: mode, namespaces, threshold`,
+		},
+		{
+			name:      "duplicate variable",
+			variables: []v1alpha1.BundleVariable{{Name: "mode", Value: "dryrun"}, {Name: "mode", Value: "automatic"}},
+			spec:      bundledPolicySpec,
+			wantErr:   `variable "mode" is defined more than once`,
+		},
+		{
+			name: "missing ConfigMap",
+			variables: []v1alpha1.BundleVariable{{Name: "mode", ValueFrom: &v1alpha1.BundleVariableSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+				Key:                  "mode",
+			}}}},
+			spec:    bundledPolicySpec,
+			wantErr: `failed to read variable "mode" from ConfigMap missing`,
+		},
+		{
+			name:    "unknown field",
+			spec:    "selector: {}\ntriggers: []\nactions: []\nmod: dryrun\n",
+			wantErr: `policy "nodes": invalid spec after substituting variables: json: unknown field "mod"`,
+		},
+		{
+			name:    "invalid policy",
+			spec:    "selector: {}\ntriggers:\n- name: a\n  type: metric\n- name: a\n  type: metric\nactions: []\n",
+			wantErr: `spec.triggers[1].name: Duplicate value: "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := testBundle(t, tt.variables, tt.spec)
+			c := fake.NewClientBuilder().
+				WithScheme(bundleScheme(t)).
+				WithObjects(bundle).
+				WithStatusSubresource(bundle).
+				Build()
+
+			updated, err := reconcileBundle(t, c, bundle)
+			require.NoError(t, err)
+			cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeReady)
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, ReasonBundleInvalid, cond.Reason)
+			assert.Contains(t, cond.Message, tt.wantErr)
+
+			policies := &v1alpha1.HealingPolicyList{}
+			require.NoError(t, c.List(context.Background(), policies))
+			assert.Empty(t, policies.Items, "invalid bundles change no policies")
+		})
+	}
+}
+
+func TestHealingPolicyBundleReconcile_UnmanagedPolicy(t *testing.T) {
+	bundle := testBundle(t, nil, "selector: {}\ntriggers: []\nactions: []\n")
+	existing := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "shop-nodes", Namespace: "default"}}
+	c := fake.NewClientBuilder().
+		WithScheme(bundleScheme(t)).
+		WithObjects(bundle, existing).
+		WithStatusSubresource(bundle).
+		Build()
+
+	_, err := reconcileBundle(t, c, bundle)
+	assert.ErrorContains(t, err, "policy shop-nodes already exists and is not managed by the bundle")
+}
+
+func TestHealingPolicyBundleSample(t *testing.T) {
+	data, err := os.ReadFile("../../config/samples/kubeskippy_v1alpha1_healingpolicybundle.yaml")
+	require.NoError(t, err)
+	bundle := &v1alpha1.HealingPolicyBundle{}
+	require.NoError(t, yaml.UnmarshalStrict(data, bundle))
+
+	c := fake.NewClientBuilder().
+		WithScheme(bundleScheme(t)).
+		WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout-healing", Namespace: bundle.Namespace},
+			Data:       map[string]string{"restartThreshold": "5"},
+		}).
+		Build()
+	r := &HealingPolicyBundleReconciler{Client: c, Scheme: c.Scheme()}
+	policies, err := r.renderBundle(context.Background(), bundle)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, "checkout-restarts", policies[1].Name)
+	assert.Equal(t, []string{"checkout", "payments"}, policies[1].Spec.Selector.Namespaces)
+}
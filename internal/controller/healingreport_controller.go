@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// defaultReportPeriod is used when a report does not set a period
+	defaultReportPeriod = 7 * 24 * time.Hour
+
+	// ReasonReportGenerated is set when a report was generated
+	ReasonReportGenerated = "ReportGenerated"
+
+	// ReasonReportFailed is set when a report could not be generated
+	ReasonReportFailed = "ReportFailed"
+
+	// Report export keys
+	reportMarkdownKey = "report.md"
+	reportJSONKey     = "report.json"
+)
+
+// HealingReportReconciler reconciles a HealingReport object
+type HealingReportReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// AIAnalyzer optionally suggests trigger tuning for reports with
+	// AITuning set
+	AIAnalyzer AIAnalyzer
+}
+
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingreports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingreports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingpolicies,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=healingactions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+
+// Reconcile generates the report once per period
+func (r *HealingReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	report := &v1alpha1.HealingReport{}
+	if err := r.Get(ctx, req.NamespacedName, report); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get HealingReport")
+		return ctrl.Result{}, err
+	}
+	patcher := newStatusPatcher(r.Client, "healingreport", report)
+
+	period := report.Spec.Period.Duration
+	if period <= 0 {
+		period = defaultReportPeriod
+	}
+
+	// Only regenerate when the period has elapsed or the spec changed
+	now := time.Now()
+	if report.Status.ObservedGeneration == report.Generation && report.Status.NextGeneration != nil {
+		if wait := report.Status.NextGeneration.Sub(now); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	log.Info("Generating HealingReport", "period", period)
+
+	if err := r.generate(ctx, report, now.Add(-period), now); err != nil {
+		log.Error(err, "Failed to generate report")
+		SetCondition(&report.Status.Conditions, v1alpha1.ConditionTypeReady, metav1.ConditionFalse,
+			ReasonReportFailed, err.Error())
+		if statusErr := patcher.Patch(ctx, report); statusErr != nil {
+			log.Error(statusErr, "Failed to update report status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	next := metav1.NewTime(now.Add(period))
+	report.Status.NextGeneration = &next
+	report.Status.ObservedGeneration = report.Generation
+	SetCondition(&report.Status.Conditions, v1alpha1.ConditionTypeReady, metav1.ConditionTrue,
+		ReasonReportGenerated, fmt.Sprintf("Report covers %d actions across %d policies",
+			report.Status.Summary.ActionsTotal, len(report.Status.Policies)))
+
+	if err := patcher.Patch(ctx, report); err != nil {
+		log.Error(err, "Failed to update report status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: period}, nil
+}
+
+// generate fills the report status for the period and exports it
+func (r *HealingReportReconciler) generate(ctx context.Context, report *v1alpha1.HealingReport, start, end time.Time) error {
+	topIssues := int(report.Spec.TopIssues)
+	if topIssues <= 0 {
+		topIssues = defaultReportTopIssues
+	}
+
+	policies := &v1alpha1.HealingPolicyList{}
+	if err := r.List(ctx, policies, client.InNamespace(report.Namespace)); err != nil {
+		return fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	actions := &v1alpha1.HealingActionList{}
+	if err := r.List(ctx, actions, client.InNamespace(report.Namespace)); err != nil {
+		return fmt.Errorf("failed to list actions: %w", err)
+	}
+
+	included := make(map[string]bool, len(report.Spec.PolicyNames))
+	for _, name := range report.Spec.PolicyNames {
+		included[name] = true
+	}
+
+	byPolicy := make(map[string][]v1alpha1.HealingAction)
+	var all []v1alpha1.HealingAction
+	for _, action := range actions.Items {
+		name := action.Labels[LabelPolicyName]
+		if len(included) > 0 && !included[name] {
+			continue
+		}
+		byPolicy[name] = append(byPolicy[name], action)
+		all = append(all, action)
+	}
+
+	report.Status.Policies = nil
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if len(included) > 0 && !included[policy.Name] {
+			continue
+		}
+		policyReport := BuildPolicyReport(policy.Name, byPolicy[policy.Name], start, end, topIssues)
+
+		// Tuning suggestions are advisory and never fail the report
+		policyReport.Suggestions = SuggestTuning(policy, policyReport.TriggersFired, start)
+		if report.Spec.AITuning && r.AIAnalyzer != nil {
+			policyReport.Suggestions = append(policyReport.Suggestions,
+				r.suggestAITuning(ctx, policy, policyReport.TriggersFired)...)
+		}
+		if err := r.annotateTuningSuggestions(ctx, policy, policyReport.Suggestions); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to annotate policy with tuning suggestions", "policy", policy.Name)
+		}
+
+		report.Status.Policies = append(report.Status.Policies, policyReport)
+	}
+	report.Status.Summary = BuildPolicyReport("", all, start, end, topIssues)
+
+	periodStart := metav1.NewTime(start)
+	periodEnd := metav1.NewTime(end)
+	report.Status.PeriodStart = &periodStart
+	report.Status.PeriodEnd = &periodEnd
+
+	if report.Spec.Export == nil {
+		report.Status.ExportedTo = ""
+		return nil
+	}
+	return r.export(ctx, report)
+}
+
+// export writes the rendered report into a ConfigMap owned by the report
+func (r *HealingReportReconciler) export(ctx context.Context, report *v1alpha1.HealingReport) error {
+	name := report.Spec.Export.ConfigMapName
+	if name == "" {
+		name = report.Name + "-export"
+	}
+
+	data := make(map[string]string)
+	for _, format := range report.Spec.Export.Formats {
+		switch format {
+		case "markdown":
+			data[reportMarkdownKey] = RenderReportMarkdown(report)
+		case "json":
+			rendered, err := RenderReportJSON(report)
+			if err != nil {
+				return err
+			}
+			data[reportJSONKey] = rendered
+		default:
+			return fmt.Errorf("unsupported export format: %s", format)
+		}
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: report.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = data
+		return controllerutil.SetControllerReference(report, cm, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to export report to ConfigMap %s: %w", name, err)
+	}
+
+	report.Status.ExportedTo = name
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *HealingReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.HealingReport{}).
+		Owns(&corev1.ConfigMap{}).
+		Complete(r)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestHealingReportReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	day := time.Now().Add(-24 * time.Hour)
+	web := testReportAction("restart-web", "crash-loop", "web", "restart", v1alpha1.HealingActionPhaseSucceeded, day, 0)
+	other := testReportAction("restart-api", "crash-loop", "api", "restart", v1alpha1.HealingActionPhaseSucceeded, day, 0)
+	other.Labels[LabelPolicyName] = "api-policy"
+
+	report := &v1alpha1.HealingReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "weekly", Namespace: "default", Generation: 1},
+		Spec: v1alpha1.HealingReportSpec{
+			PolicyNames: []string{"web-policy"},
+			Period:      metav1.Duration{Duration: 7 * 24 * time.Hour},
+			Export:      &v1alpha1.ReportExport{Formats: []string{"markdown", "json"}},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			report,
+			&v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"}},
+			&v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "api-policy", Namespace: "default"}},
+			&web, &other,
+		).
+		WithStatusSubresource(&v1alpha1.HealingReport{}).
+		Build()
+
+	r := &HealingReportReconciler{Client: fakeClient, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: NamespacedName(report)}
+
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, result.RequeueAfter)
+
+	stored := &v1alpha1.HealingReport{}
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, stored))
+	require.Len(t, stored.Status.Policies, 1)
+	assert.Equal(t, "web-policy", stored.Status.Policies[0].Name)
+	assert.Equal(t, int32(1), stored.Status.Summary.ActionsTotal)
+	assert.Equal(t, "weekly-export", stored.Status.ExportedTo)
+	require.NotNil(t, stored.Status.NextGeneration)
+
+	cond := GetCondition(stored.Status.Conditions, v1alpha1.ConditionTypeReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, fakeClient.Get(context.Background(), NamespacedName(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "weekly-export", Namespace: "default"},
+	}), cm))
+	assert.Contains(t, cm.Data[reportMarkdownKey], "## Policy: web-policy")
+	var exported v1alpha1.HealingReportStatus
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[reportJSONKey]), &exported))
+	assert.Equal(t, int32(1), exported.Summary.ActionsTotal)
+	require.Len(t, cm.OwnerReferences, 1)
+	assert.Equal(t, "weekly", cm.OwnerReferences[0].Name)
+
+	// A second reconcile within the period waits for the next generation
+	result, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, 6*24*time.Hour)
+	assert.LessOrEqual(t, result.RequeueAfter, 7*24*time.Hour)
+}
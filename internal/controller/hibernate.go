@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/remediation"
+)
+
+const (
+	// ReasonHibernated is set while a hibernated target is at zero replicas
+	ReasonHibernated = "Hibernated"
+
+	// ReasonResumed is set once a hibernated target was restored
+	ReasonResumed = "Resumed"
+
+	// resumeConditionPollInterval is how often a resumeWhen condition is
+	// checked
+	resumeConditionPollInterval = 30 * time.Second
+)
+
+// startHibernation records the replica count to restore once a hibernate
+// action scaled its target to zero
+func startHibernation(action *v1alpha1.HealingAction) {
+	config := action.Spec.Action.HibernateAction
+	if action.Spec.Action.Type != "hibernate" || action.Spec.DryRun || config == nil {
+		return
+	}
+	replicas, ok := remediation.PreviousReplicas(action)
+	if !ok {
+		return
+	}
+
+	hibernation := &v1alpha1.HibernationStatus{PreviousReplicas: replicas}
+	message := fmt.Sprintf("Scaled to zero, %d replicas are restored", replicas)
+	if config.Duration.Duration > 0 {
+		resumeAt := metav1.NewTime(time.Now().Add(config.Duration.Duration))
+		hibernation.ResumeAt = &resumeAt
+		message += " at " + resumeAt.UTC().Format(time.RFC3339)
+	}
+	if cond := config.ResumeWhen; cond != nil {
+		message += fmt.Sprintf(" when %s %s reports %s=%s", cond.Kind, cond.Name, cond.Type, resumeStatus(cond))
+	}
+	action.Status.Hibernation = hibernation
+	SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeHibernating,
+		metav1.ConditionTrue, ReasonHibernated, message)
+}
+
+// isHibernating reports whether an action's target is still scaled to zero
+func isHibernating(action *v1alpha1.HealingAction) bool {
+	return action.Status.Hibernation != nil && action.Status.Hibernation.ResumedAt == nil
+}
+
+// handleHibernation restores a hibernated target once its duration passed
+// or its resume condition is met, and otherwise requeues until then
+func (r *HealingActionReconciler) handleHibernation(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	hibernation := action.Status.Hibernation
+	config := action.Spec.Action.HibernateAction
+
+	if hibernation.ResumeAt != nil && !time.Now().Before(hibernation.ResumeAt.Time) {
+		return r.resumeHibernation(ctx, log, action, "hibernation duration elapsed")
+	}
+
+	var requeue time.Duration
+	if config != nil && config.ResumeWhen != nil {
+		met, err := r.resumeConditionMet(ctx, action.Namespace, config.ResumeWhen)
+		if err != nil {
+			log.Error(err, "Failed to check resume condition")
+		}
+		if met {
+			return r.resumeHibernation(ctx, log, action, fmt.Sprintf("%s %s reports %s=%s",
+				config.ResumeWhen.Kind, config.ResumeWhen.Name, config.ResumeWhen.Type, resumeStatus(config.ResumeWhen)))
+		}
+		requeue = resumeConditionPollInterval
+	}
+	if hibernation.ResumeAt != nil {
+		if remaining := time.Until(hibernation.ResumeAt.Time); requeue == 0 || remaining < requeue {
+			requeue = remaining
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// resumeHibernation restores the target's previous replica count
+func (r *HealingActionReconciler) resumeHibernation(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction, reason string) (ctrl.Result, error) {
+	log.Info("Resuming hibernated workload", "reason", reason, "replicas", action.Status.Hibernation.PreviousReplicas)
+	if err := r.RemediationEngine.Rollback(ctx, action); err != nil {
+		r.recordEvent(action, corev1.EventTypeWarning, ReasonActionFailed,
+			fmt.Sprintf("Failed to resume hibernated workload: %v", err))
+		return ctrl.Result{}, fmt.Errorf("failed to resume hibernated workload: %w", err)
+	}
+
+	now := metav1.Now()
+	action.Status.Hibernation.ResumedAt = &now
+	action.Status.Hibernation.ResumeReason = reason
+	SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeHibernating,
+		metav1.ConditionFalse, ReasonResumed, "Restored previous replica count: "+reason)
+	if err := patchStatus(ctx, r.Client, action); err != nil {
+		log.Error(err, "Failed to update hibernation status")
+		return ctrl.Result{}, err
+	}
+
+	r.recordEvent(action, corev1.EventTypeNormal, ReasonResumed,
+		fmt.Sprintf("Restored %d replicas: %s", action.Status.Hibernation.PreviousReplicas, reason))
+	return ctrl.Result{}, nil
+}
+
+// resumeConditionMet checks whether the resource named by the condition
+// reports it. A missing resource does not meet the condition.
+func (r *HealingActionReconciler) resumeConditionMet(ctx context.Context, namespace string, cond *v1alpha1.ResumeCondition) (bool, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(cond.APIVersion)
+	obj.SetKind(cond.Kind)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: cond.Name}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get %s %s: %w", cond.Kind, cond.Name, err)
+	}
+
+	return hasCondition(obj, cond.Type, resumeStatus(cond))
+}
+
+// hasCondition reports whether an object's status lists the condition
+// with the given status
+func hasCondition(obj *unstructured.Unstructured, conditionType, status string) (bool, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, fmt.Errorf("failed to read conditions of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		return condition["status"] == status, nil
+	}
+	return false, nil
+}
+
+// resumeStatus returns the condition status to wait for
+func resumeStatus(cond *v1alpha1.ResumeCondition) string {
+	if cond.Status == "" {
+		return string(metav1.ConditionTrue)
+	}
+	return cond.Status
+}
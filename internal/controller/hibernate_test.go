@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func hibernateAction(config *v1alpha1.HibernateAction) *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "web-hibernate",
+			Namespace:  "apps",
+			Finalizers: []string{FinalizerName},
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "hibernate", Type: "hibernate", HibernateAction: config},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Phase: v1alpha1.HealingActionPhaseSucceeded,
+			Result: &v1alpha1.ActionResult{
+				Success: true,
+				Changes: []v1alpha1.ResourceChange{{
+					ResourceRef: "Deployment/apps/web",
+					ChangeType:  "scale",
+					Field:       "spec.replicas",
+					OldValue:    "3",
+					NewValue:    "0",
+				}},
+			},
+		},
+	}
+}
+
+func TestStartHibernation(t *testing.T) {
+	action := hibernateAction(&v1alpha1.HibernateAction{
+		Duration:   metav1.Duration{Duration: 2 * time.Hour},
+		ResumeWhen: &v1alpha1.ResumeCondition{APIVersion: "apps/v1", Kind: "Deployment", Name: "db", Type: "Available"},
+	})
+	startHibernation(action)
+
+	require.NotNil(t, action.Status.Hibernation)
+	assert.Equal(t, int32(3), action.Status.Hibernation.PreviousReplicas)
+	require.NotNil(t, action.Status.Hibernation.ResumeAt)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), action.Status.Hibernation.ResumeAt.Time, 5*time.Second)
+	cond := GetCondition(action.Status.Conditions, v1alpha1.ConditionTypeHibernating)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Contains(t, cond.Message, "when Deployment db reports Available=True")
+	assert.True(t, isHibernating(action))
+
+	// Dry-runs leave the target untouched
+	dryRun := hibernateAction(&v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}})
+	dryRun.Spec.DryRun = true
+	startHibernation(dryRun)
+	assert.Nil(t, dryRun.Status.Hibernation)
+}
+
+func TestHealingActionReconciler_Hibernation(t *testing.T) {
+	dbDeployment := func(available corev1.ConditionStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: available},
+			}},
+		}
+	}
+	resumeWhenDB := &v1alpha1.ResumeCondition{APIVersion: "apps/v1", Kind: "Deployment", Name: "db", Type: "Available"}
+
+	tests := []struct {
+		name        string
+		config      *v1alpha1.HibernateAction
+		resumeAt    time.Duration
+		objects     []client.Object
+		wantResumed bool
+		wantReason  string
+		wantRequeue time.Duration
+	}{
+		{
+			name:        "duration elapsed",
+			config:      &v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}},
+			resumeAt:    -time.Minute,
+			wantResumed: true,
+			wantReason:  "hibernation duration elapsed",
+		},
+		{
+			name:        "duration pending",
+			config:      &v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}},
+			resumeAt:    10 * time.Minute,
+			wantRequeue: 10 * time.Minute,
+		},
+		{
+			name:        "resume condition met",
+			config:      &v1alpha1.HibernateAction{ResumeWhen: resumeWhenDB},
+			objects:     []client.Object{dbDeployment(corev1.ConditionTrue)},
+			wantResumed: true,
+			wantReason:  "Deployment db reports Available=True",
+		},
+		{
+			name:        "resume condition not met",
+			config:      &v1alpha1.HibernateAction{ResumeWhen: resumeWhenDB},
+			objects:     []client.Object{dbDeployment(corev1.ConditionFalse)},
+			wantRequeue: resumeConditionPollInterval,
+		},
+		{
+			name:        "resume condition resource missing",
+			config:      &v1alpha1.HibernateAction{ResumeWhen: resumeWhenDB},
+			wantRequeue: resumeConditionPollInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			require.NoError(t, appsv1.AddToScheme(scheme))
+
+			action := hibernateAction(tt.config)
+			action.Status.Hibernation = &v1alpha1.HibernationStatus{PreviousReplicas: 3}
+			if tt.resumeAt != 0 {
+				resumeAt := metav1.NewTime(time.Now().Add(tt.resumeAt))
+				action.Status.Hibernation.ResumeAt = &resumeAt
+			}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(tt.objects, action)...).
+				WithStatusSubresource(action).
+				Build()
+			var rolledBack []string
+			r := &HealingActionReconciler{
+				Client: c,
+				Scheme: scheme,
+				Config: config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{RollbackFunc: func(ctx context.Context, action *v1alpha1.HealingAction) error {
+					rolledBack = append(rolledBack, action.Name)
+					return nil
+				}},
+				SafetyController: &MockSafetyController{},
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+			require.NoError(t, err)
+
+			got := &v1alpha1.HealingAction{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+			if tt.wantResumed {
+				assert.Equal(t, []string{"web-hibernate"}, rolledBack)
+				assert.Zero(t, result)
+				require.NotNil(t, got.Status.Hibernation.ResumedAt)
+				assert.Equal(t, tt.wantReason, got.Status.Hibernation.ResumeReason)
+				cond := GetCondition(got.Status.Conditions, v1alpha1.ConditionTypeHibernating)
+				require.NotNil(t, cond)
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				return
+			}
+
+			assert.Empty(t, rolledBack)
+			assert.Nil(t, got.Status.Hibernation.ResumedAt)
+			assert.InDelta(t, tt.wantRequeue.Seconds(), result.RequeueAfter.Seconds(), 5)
+		})
+	}
+}
+
+func TestHealingActionReconciler_HibernationResumedOnDeletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	action := hibernateAction(&v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}})
+	resumeAt := metav1.NewTime(time.Now().Add(time.Hour))
+	action.Status.Hibernation = &v1alpha1.HibernationStatus{PreviousReplicas: 3, ResumeAt: &resumeAt}
+	now := metav1.Now()
+	action.DeletionTimestamp = &now
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(action).WithStatusSubresource(action).Build()
+	var rolledBack []string
+	r := &HealingActionReconciler{
+		Client: c,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{RollbackFunc: func(ctx context.Context, action *v1alpha1.HealingAction) error {
+			rolledBack = append(rolledBack, action.Name)
+			return nil
+		}},
+		SafetyController: &MockSafetyController{},
+	}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web-hibernate"}, rolledBack)
+
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(action), &v1alpha1.HealingAction{})
+	assert.True(t, errors.IsNotFound(err))
+}
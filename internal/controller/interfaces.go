@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
 	"github.com/kubeskippy/kubeskippy/internal/types"
@@ -21,6 +22,25 @@ type MetricsCollector interface {
 	GetResourceMetrics(ctx context.Context, resource *v1alpha1.TargetResource) (*types.ResourceMetrics, error)
 }
 
+// TriggerResultEvaluator is implemented by metrics collectors that also
+// report the value a metric trigger was evaluated against
+type TriggerResultEvaluator interface {
+	EvaluateTriggerResult(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *types.ClusterMetrics) (types.TriggerResult, error)
+}
+
+// RateLimitBudget is implemented by safety controllers that report how many
+// more actions a policy may take before reaching its hourly rate limit
+type RateLimitBudget interface {
+	RemainingActions(ctx context.Context, policy *v1alpha1.HealingPolicy) (int, error)
+}
+
+// ActionReserver is implemented by safety controllers that count an action
+// against the rate limit of its policy as they check it, right before the
+// action runs
+type ActionReserver interface {
+	ReserveAction(ctx context.Context, action *v1alpha1.HealingAction) (bool, error)
+}
+
 // SafetyController validates and enforces safety rules
 type SafetyController interface {
 	// ValidateAction checks if an action is safe to execute
@@ -49,6 +69,9 @@ type RemediationEngine interface {
 
 	// GetActionExecutor returns the executor for a specific action type
 	GetActionExecutor(actionType string) (types.ActionExecutor, error)
+
+	// CancelAction cancels the executor of an action's running attempt
+	CancelAction(actionName string) error
 }
 
 // ActionExecutor defines the interface for specific action implementations
@@ -75,3 +98,47 @@ type AIAnalyzer interface {
 	GetModel() string
 }
 
+// StreamingAIAnalyzer is implemented by AI analyzers that report the
+// progress of an analysis while the provider streams its response
+type StreamingAIAnalyzer interface {
+	// AnalyzeClusterStateStreaming is AnalyzeClusterState passing the
+	// reasoning steps received so far to progress
+	AnalyzeClusterStateStreaming(ctx context.Context, metrics *types.ClusterMetrics, issues []types.Issue, progress func(types.AIProgress)) (*types.AIAnalysis, error)
+}
+
+
+// ActionNotifier reports completed healing actions to external systems
+type ActionNotifier interface {
+	// NotifyCompletion reports the outcome of a completed action
+	NotifyCompletion(ctx context.Context, action *v1alpha1.HealingAction) error
+}
+
+// TriggerNotifier reports trigger state transitions to external systems
+type TriggerNotifier interface {
+	// NotifyTrigger reports a transition of one of the policy's triggers
+	NotifyTrigger(ctx context.Context, policy *v1alpha1.HealingPolicy, trigger, transition, reason string) error
+}
+
+// EscalationNotifier reports targets healing is ineffective for to
+// external systems
+type EscalationNotifier interface {
+	// NotifyEscalation reports the policy's newly escalated targets
+	NotifyEscalation(ctx context.Context, policy *v1alpha1.HealingPolicy, targets []v1alpha1.FailingTarget) error
+}
+
+// ReleaseCalendar knows the release freezes during which automatic
+// healing only runs dry-runs
+type ReleaseCalendar interface {
+	// Blackout returns the name of the release freeze t falls in, if any
+	Blackout(ctx context.Context, t time.Time) (string, bool, error)
+}
+
+// Watchdog observes the operator's own health
+type Watchdog interface {
+	// ReconcileStarted records the start of a reconcile; the returned
+	// function records its end
+	ReconcileStarted(controller string) func(err error)
+
+	// SafeMode reports whether new healing actions must be dry-runs
+	SafeMode() bool
+}
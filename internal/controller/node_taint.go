@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/remediation"
+)
+
+const (
+	// ReasonNodeTainted is set while a tainted node waits for its window
+	ReasonNodeTainted = "NodeTainted"
+
+	// ReasonNodeUntainted is set once a node recovered and was untainted
+	ReasonNodeUntainted = "NodeUntainted"
+
+	// ReasonNodeDrainRequested is set once a drain action was created for a
+	// node still unhealthy after its window
+	ReasonNodeDrainRequested = "NodeDrainRequested"
+
+	// ReasonNodeTaintKept is set once a node still unhealthy after its
+	// window was left tainted for an operator
+	ReasonNodeTaintKept = "NodeTaintKept"
+
+	// defaultTaintWindow is used when a taint action does not set a window
+	defaultTaintWindow = 10 * time.Minute
+)
+
+// nodePressureConditions are node conditions reporting a problem when True
+var nodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// startNodeTaint records the window of a taint action that tainted its node
+func startNodeTaint(action *v1alpha1.HealingAction) {
+	if action.Spec.Action.Type != "taint" || action.Spec.DryRun {
+		return
+	}
+	config := action.Spec.Action.TaintAction
+	window := defaultTaintWindow
+	if config != nil && config.Window.Duration > 0 {
+		window = config.Window.Duration
+	}
+
+	taint := remediation.NodeTaint(config)
+	endsAt := metav1.NewTime(time.Now().Add(window))
+	action.Status.NodeTaint = &v1alpha1.NodeTaintStatus{
+		Key:          taint.Key,
+		Effect:       string(taint.Effect),
+		WindowEndsAt: endsAt,
+	}
+	SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeNodeTainted,
+		metav1.ConditionTrue, ReasonNodeTainted,
+		fmt.Sprintf("Tainted with %s, the node is checked at %s", taint.ToString(), endsAt.UTC().Format(time.RFC3339)))
+}
+
+// isNodeTainted reports whether an action's node waits for its window
+func isNodeTainted(action *v1alpha1.HealingAction) bool {
+	return action.Status.NodeTaint != nil && action.Status.NodeTaint.ResolvedAt == nil
+}
+
+// handleNodeTaint resolves a tainted node once its window passed: a healthy
+// node is untainted, an unhealthy one is kept tainted or gets a drain action
+// as the action's escalation says
+func (r *HealingActionReconciler) handleNodeTaint(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	nodeTaint := action.Status.NodeTaint
+	if remaining := time.Until(nodeTaint.WindowEndsAt.Time); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	node := action.Spec.TargetResource.Name
+	healthy, message, err := r.nodeHealth(ctx, node)
+	if err != nil {
+		log.Error(err, "Failed to check node health", "node", node)
+		return ctrl.Result{}, err
+	}
+
+	eventType := corev1.EventTypeWarning
+	var reason string
+	switch {
+	case healthy:
+		log.Info("Node recovered, removing taint", "node", node, "taint", nodeTaint.Key)
+		if err := r.RemediationEngine.Rollback(ctx, action); err != nil {
+			r.recordEvent(action, corev1.EventTypeWarning, ReasonActionFailed,
+				fmt.Sprintf("Failed to untaint node %s: %v", node, err))
+			return ctrl.Result{}, fmt.Errorf("failed to untaint node: %w", err)
+		}
+		nodeTaint.Outcome = v1alpha1.NodeTaintUntainted
+		eventType, reason = corev1.EventTypeNormal, ReasonNodeUntainted
+		SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeNodeTainted,
+			metav1.ConditionFalse, reason, "Taint removed: "+message)
+
+	case taintEscalation(action) == "Keep":
+		log.Info("Node still unhealthy, keeping taint", "node", node, "reason", message)
+		nodeTaint.Outcome = v1alpha1.NodeTaintKept
+		reason = ReasonNodeTaintKept
+		SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeNodeTainted,
+			metav1.ConditionTrue, reason, "Taint kept for an operator: "+message)
+
+	default:
+		drain, err := r.requestDrain(ctx, action)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				log.Error(err, "Failed to create drain action", "node", node)
+				return ctrl.Result{}, err
+			}
+			// Without its policy there is nothing to own the drain action
+			log.Info("Policy gone, keeping taint", "node", node, "reason", message)
+			nodeTaint.Outcome = v1alpha1.NodeTaintKept
+			reason = ReasonNodeTaintKept
+			message = fmt.Sprintf("%s; policy %s no longer exists, no drain requested", message, action.Spec.PolicyRef.Name)
+			SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeNodeTainted,
+				metav1.ConditionTrue, reason, "Taint kept for an operator: "+message)
+			break
+		}
+		log.Info("Node still unhealthy, requested drain", "node", node, "drainAction", drain, "reason", message)
+		message = fmt.Sprintf("%s; drain action %s awaits approval", message, drain)
+		nodeTaint.Outcome = v1alpha1.NodeTaintDrainRequested
+		nodeTaint.DrainAction = drain
+		reason = ReasonNodeDrainRequested
+		SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypeNodeTainted,
+			metav1.ConditionTrue, reason, "Drain requested: "+message)
+	}
+
+	now := metav1.Now()
+	nodeTaint.ResolvedAt = &now
+	nodeTaint.Message = message
+	if err := patchStatus(ctx, r.Client, action); err != nil {
+		log.Error(err, "Failed to update node taint status")
+		return ctrl.Result{}, err
+	}
+
+	r.recordEvent(action, eventType, reason, message)
+	return ctrl.Result{}, nil
+}
+
+// taintEscalation returns what happens to a node still unhealthy after
+// the window
+func taintEscalation(action *v1alpha1.HealingAction) string {
+	if config := action.Spec.Action.TaintAction; config != nil && config.Escalation != "" {
+		return config.Escalation
+	}
+	return "Keep"
+}
+
+// requestDrain creates the drain action escalating a taint action and
+// returns its name. Draining evicts every pod of the node, so the drain is
+// a HealingAction of its own: it needs approval and passes the safety
+// checks of any other action before it runs. The name is derived from the
+// taint action, so a retried escalation does not create a second drain.
+func (r *HealingActionReconciler) requestDrain(ctx context.Context, action *v1alpha1.HealingAction) (string, error) {
+	policy := &v1alpha1.HealingPolicy{}
+	key := client.ObjectKey{Name: action.Spec.PolicyRef.Name, Namespace: action.Spec.PolicyRef.Namespace}
+	if err := r.Get(ctx, key, policy); err != nil {
+		return "", err
+	}
+	policy.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind("HealingPolicy"))
+
+	node := &corev1.Node{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: action.Spec.TargetResource.Name,
+			UID:  types.UID(action.Spec.TargetResource.UID),
+		},
+	}
+	template := &v1alpha1.HealingActionTemplate{
+		Name:             action.Spec.Action.Name + "-drain",
+		Type:             "drain",
+		Description:      fmt.Sprintf("Drain node %s, still unhealthy after taint action %s", node.Name, action.Name),
+		RequiresApproval: true,
+	}
+	drain := CreateHealingAction(policy, node, template, action.Spec.DryRun, action.Labels[LabelTriggerName])
+	drain.GenerateName = ""
+	drain.Name = action.Name + "-drain"
+	drain.Spec.ApprovalRequired = true
+	if err := r.Create(ctx, drain); err != nil && !errors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create drain action: %w", err)
+	}
+	return drain.Name, nil
+}
+
+// nodeHealth checks that the node is Ready without pressure conditions.
+// Only the node's own conditions count: pods that are not ready may fail
+// for reasons of their own and are left to the policies watching them. A
+// node that no longer exists has nothing left to escalate and counts as
+// healthy.
+func (r *HealingActionReconciler) nodeHealth(ctx context.Context, name string) (bool, string, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return true, fmt.Sprintf("Node %s no longer exists", name), nil
+		}
+		return false, "", fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+
+	var problems []string
+	if status := nodeConditionStatus(node, corev1.NodeReady); status != corev1.ConditionTrue {
+		problems = append(problems, fmt.Sprintf("%s=%s", corev1.NodeReady, status))
+	}
+	for _, conditionType := range nodePressureConditions {
+		if nodeConditionStatus(node, conditionType) == corev1.ConditionTrue {
+			problems = append(problems, fmt.Sprintf("%s=True", conditionType))
+		}
+	}
+
+	if len(problems) > 0 {
+		return false, fmt.Sprintf("Node %s is unhealthy: %s", name, strings.Join(problems, ", ")), nil
+	}
+	return true, fmt.Sprintf("Node %s is healthy", name), nil
+}
+
+// nodeConditionStatus returns the status of a node condition, Unknown if
+// the node does not report it
+func nodeConditionStatus(node *corev1.Node, conditionType corev1.NodeConditionType) corev1.ConditionStatus {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return corev1.ConditionUnknown
+}
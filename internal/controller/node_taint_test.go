@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func taintAction(phase string, config *v1alpha1.TaintAction) *v1alpha1.HealingAction {
+	now := metav1.Now()
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1-taint", Namespace: "kubeskippy-system", Finalizers: []string{FinalizerName}},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "nodes", Namespace: "kubeskippy-system"},
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Node", Name: "worker-1"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "suspect", Type: "taint", TaintAction: config},
+			Timeout:        metav1.Duration{Duration: 5 * time.Minute},
+		},
+		Status: v1alpha1.HealingActionStatus{Phase: phase, StartTime: &now},
+	}
+}
+
+func taintTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestHealingActionReconciler_StartsNodeTaint(t *testing.T) {
+	scheme := taintTestScheme(t)
+	action := taintAction(v1alpha1.HealingActionPhaseInProgress, &v1alpha1.TaintAction{Window: metav1.Duration{Duration: 15 * time.Minute}})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(action).WithStatusSubresource(action).Build()
+	r := &HealingActionReconciler{
+		Client:            c,
+		Scheme:            scheme,
+		Config:            config.NewDefaultConfig(),
+		RemediationEngine: &MockRemediationEngine{},
+		SafetyController:  &MockSafetyController{},
+	}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+	require.NoError(t, err)
+	assert.InDelta(t, 15*time.Minute, result.RequeueAfter, float64(time.Minute), "requeued for the end of the window")
+
+	got := &v1alpha1.HealingAction{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+	assert.Equal(t, v1alpha1.HealingActionPhaseSucceeded, got.Status.Phase)
+	require.NotNil(t, got.Status.NodeTaint)
+	assert.Equal(t, "kubeskippy.io/suspected-problem", got.Status.NodeTaint.Key)
+	assert.Equal(t, "PreferNoSchedule", got.Status.NodeTaint.Effect)
+	assert.Nil(t, got.Status.NodeTaint.ResolvedAt)
+	cond := GetCondition(got.Status.Conditions, v1alpha1.ConditionTypeNodeTainted)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+}
+
+func TestHealingActionReconciler_ResolvesNodeTaint(t *testing.T) {
+	node := func(ready corev1.ConditionStatus, pressure ...corev1.NodeConditionType) *corev1.Node {
+		n := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}},
+		}
+		for _, p := range pressure {
+			n.Status.Conditions = append(n.Status.Conditions, corev1.NodeCondition{Type: p, Status: corev1.ConditionTrue})
+		}
+		return n
+	}
+	pod := func(ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"},
+			Spec:       corev1.PodSpec{NodeName: "worker-1"},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "nodes", Namespace: "kubeskippy-system"}}
+
+	tests := []struct {
+		name          string
+		windowLeft    time.Duration
+		escalation    string
+		objects       []client.Object
+		wantOutcome   string
+		wantCondition metav1.ConditionStatus
+		wantMessage   string
+		wantRequeue   bool
+	}{
+		{
+			name:        "window not over",
+			windowLeft:  5 * time.Minute,
+			objects:     []client.Object{node(corev1.ConditionFalse)},
+			wantRequeue: true,
+		},
+		{
+			name:          "node recovered",
+			objects:       []client.Object{node(corev1.ConditionTrue), pod(corev1.ConditionTrue)},
+			wantOutcome:   v1alpha1.NodeTaintUntainted,
+			wantCondition: metav1.ConditionFalse,
+			wantMessage:   "Node worker-1 is healthy",
+		},
+		{
+			name:          "node removed",
+			wantOutcome:   v1alpha1.NodeTaintUntainted,
+			wantCondition: metav1.ConditionFalse,
+			wantMessage:   "Node worker-1 no longer exists",
+		},
+		{
+			name:          "a pod not ready is not a node fault",
+			objects:       []client.Object{node(corev1.ConditionTrue), pod(corev1.ConditionFalse)},
+			wantOutcome:   v1alpha1.NodeTaintUntainted,
+			wantCondition: metav1.ConditionFalse,
+			wantMessage:   "Node worker-1 is healthy",
+		},
+		{
+			name:          "pressure kept for an operator by default",
+			objects:       []client.Object{node(corev1.ConditionTrue, corev1.NodeDiskPressure)},
+			wantOutcome:   v1alpha1.NodeTaintKept,
+			wantCondition: metav1.ConditionTrue,
+			wantMessage:   "Node worker-1 is unhealthy: DiskPressure=True",
+		},
+		{
+			name:          "not ready escalated to a drain action",
+			escalation:    "Drain",
+			objects:       []client.Object{node(corev1.ConditionFalse), policy},
+			wantOutcome:   v1alpha1.NodeTaintDrainRequested,
+			wantCondition: metav1.ConditionTrue,
+			wantMessage:   "drain action worker-1-taint-drain awaits approval",
+		},
+		{
+			name:          "drain without a policy keeps the taint",
+			escalation:    "Drain",
+			objects:       []client.Object{node(corev1.ConditionFalse)},
+			wantOutcome:   v1alpha1.NodeTaintKept,
+			wantCondition: metav1.ConditionTrue,
+			wantMessage:   "policy nodes no longer exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := taintTestScheme(t)
+			action := taintAction(v1alpha1.HealingActionPhaseSucceeded, &v1alpha1.TaintAction{Escalation: tt.escalation})
+			action.Status.NodeTaint = &v1alpha1.NodeTaintStatus{
+				Key:          "kubeskippy.io/suspected-problem",
+				Effect:       "PreferNoSchedule",
+				WindowEndsAt: metav1.NewTime(time.Now().Add(tt.windowLeft)),
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(tt.objects, action)...).
+				WithStatusSubresource(action).
+				Build()
+
+			rolledBack := 0
+			r := &HealingActionReconciler{
+				Client: c,
+				Scheme: scheme,
+				Config: config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{
+					RollbackFunc: func(ctx context.Context, action *v1alpha1.HealingAction) error {
+						rolledBack++
+						return nil
+					},
+				},
+				SafetyController: &MockSafetyController{},
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+			require.NoError(t, err)
+
+			got := &v1alpha1.HealingAction{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+			if tt.wantRequeue {
+				assert.Greater(t, result.RequeueAfter, time.Duration(0))
+				assert.Nil(t, got.Status.NodeTaint.ResolvedAt)
+				assert.Zero(t, rolledBack)
+				return
+			}
+
+			assert.Equal(t, tt.wantOutcome, got.Status.NodeTaint.Outcome)
+			assert.NotNil(t, got.Status.NodeTaint.ResolvedAt)
+			assert.Contains(t, got.Status.NodeTaint.Message, tt.wantMessage)
+			cond := GetCondition(got.Status.Conditions, v1alpha1.ConditionTypeNodeTainted)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantCondition, cond.Status)
+			assert.Equal(t, tt.wantOutcome == v1alpha1.NodeTaintUntainted, rolledBack == 1)
+
+			drain := &v1alpha1.HealingAction{}
+			err = c.Get(context.Background(), client.ObjectKey{Namespace: action.Namespace, Name: "worker-1-taint-drain"}, drain)
+			if tt.wantOutcome != v1alpha1.NodeTaintDrainRequested {
+				assert.True(t, errors.IsNotFound(err), "no drain action")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, drain.Name, got.Status.NodeTaint.DrainAction)
+			assert.Equal(t, "drain", drain.Spec.Action.Type)
+			assert.True(t, drain.Spec.ApprovalRequired, "drains need approval")
+			assert.Equal(t, v1alpha1.TargetResource{APIVersion: "v1", Kind: "Node", Name: "worker-1"}, drain.Spec.TargetResource)
+			assert.Equal(t, "nodes", drain.Spec.PolicyRef.Name)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// ReasonOverrideDetected is set when a target was changed manually
+	ReasonOverrideDetected = "OverrideDetected"
+
+	// ReasonNoOverride clears a previous OverrideDetected condition
+	ReasonNoOverride = "NoOverride"
+)
+
+// ManualOverride describes an out-of-band change to a target made after
+// KubeSkippy last acted on it
+type ManualOverride struct {
+	// Manager is the field manager that made the change, if known
+	Manager string
+	// ChangedAt is when the change was made
+	ChangedAt time.Time
+	// PausedUntil is when automatic actions on the target resume
+	PausedUntil time.Time
+	// Action is the last KubeSkippy action on the target
+	Action string
+}
+
+// detectManualOverride checks whether the target was changed by someone
+// else since the last successful action on it and is still within the
+// policy's pause period. Detection is off for policies without one.
+func (r *HealingPolicyReconciler) detectManualOverride(ctx context.Context, policy *v1alpha1.HealingPolicy, target client.Object) (*ManualOverride, error) {
+	if policy.Spec.SafetyRules.OverridePausePeriod == nil || policy.Spec.SafetyRules.OverridePausePeriod.Duration <= 0 {
+		return nil, nil
+	}
+	pause := policy.Spec.SafetyRules.OverridePausePeriod.Duration
+
+	actions, err := ListActionsForTarget(ctx, r, target.GetObjectKind().GroupVersionKind().Kind,
+		target.GetNamespace(), target.GetName())
+	if err != nil {
+		return nil, err
+	}
+	var policyActions []v1alpha1.HealingAction
+	for i := range actions {
+		if isPolicyAction(&actions[i], policy) {
+			policyActions = append(policyActions, actions[i])
+		}
+	}
+
+	last := lastActionOnTarget(policyActions, target)
+	if last == nil || target.GetGeneration() <= last.Status.TargetGeneration {
+		return nil, nil
+	}
+
+	override := &ManualOverride{
+		Manager:   "unknown",
+		ChangedAt: last.Status.CompletionTime.Time,
+		Action:    last.Name,
+	}
+	if manager, changedAt, ok := lastForeignUpdate(target, last.Status.CompletionTime.Time); ok {
+		override.Manager = manager
+		override.ChangedAt = changedAt
+	} else if hasManagedFieldsSince(target, last.Status.CompletionTime.Time) {
+		// Only KubeSkippy changed the target since, e.g. another action
+		return nil, nil
+	}
+
+	override.PausedUntil = override.ChangedAt.Add(pause)
+	if time.Now().After(override.PausedUntil) {
+		return nil, nil
+	}
+	return override, nil
+}
+
+// lastActionOnTarget returns the most recently completed successful action
+// that recorded the target's generation
+func lastActionOnTarget(actions []v1alpha1.HealingAction, target client.Object) *v1alpha1.HealingAction {
+	var last *v1alpha1.HealingAction
+	for i := range actions {
+		action := &actions[i]
+		if action.Spec.DryRun ||
+			action.Status.Phase != v1alpha1.HealingActionPhaseSucceeded ||
+			action.Status.TargetGeneration == 0 ||
+			action.Status.CompletionTime == nil {
+			continue
+		}
+		ref := action.Spec.TargetResource
+		if ref.Name != target.GetName() || ref.Namespace != target.GetNamespace() {
+			continue
+		}
+		if ref.UID != "" && target.GetUID() != "" && ref.UID != string(target.GetUID()) {
+			continue
+		}
+		if last == nil || action.Status.CompletionTime.After(last.Status.CompletionTime.Time) {
+			last = action
+		}
+	}
+	return last
+}
+
+// lastForeignUpdate returns the latest spec/metadata update made after since
+// by a field manager other than KubeSkippy
+func lastForeignUpdate(target client.Object, since time.Time) (string, time.Time, bool) {
+	var manager string
+	var changedAt time.Time
+	for _, entry := range target.GetManagedFields() {
+		if !isSpecUpdateSince(entry, since) || entry.Manager == types.FieldManager {
+			continue
+		}
+		if entry.Time.After(changedAt) {
+			manager = entry.Manager
+			changedAt = entry.Time.Time
+		}
+	}
+	return manager, changedAt, manager != ""
+}
+
+// hasManagedFieldsSince reports whether any spec/metadata update after since
+// was recorded in managedFields
+func hasManagedFieldsSince(target client.Object, since time.Time) bool {
+	for _, entry := range target.GetManagedFields() {
+		if isSpecUpdateSince(entry, since) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpecUpdateSince ignores status subresource updates made by controllers
+func isSpecUpdateSince(entry metav1.ManagedFieldsEntry, since time.Time) bool {
+	return entry.Subresource == "" && entry.Time != nil && entry.Time.After(since)
+}
+
+// setOverrideCondition reflects detected overrides on the policy status
+func setOverrideCondition(policy *v1alpha1.HealingPolicy, overrides map[string]*ManualOverride) {
+	if len(overrides) == 0 {
+		if cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeOverrideDetected); cond != nil && cond.Status == metav1.ConditionTrue {
+			SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeOverrideDetected,
+				metav1.ConditionFalse, ReasonNoOverride, "No manual overrides on targets")
+		}
+		return
+	}
+
+	var target string
+	var override *ManualOverride
+	for name, o := range overrides {
+		if override == nil || o.ChangedAt.After(override.ChangedAt) {
+			target, override = name, o
+		}
+	}
+
+	message := fmt.Sprintf("%s was changed by %s at %s after action %s; automatic actions paused until %s",
+		target, override.Manager, override.ChangedAt.Format(time.RFC3339), override.Action,
+		override.PausedUntil.Format(time.RFC3339))
+	if len(overrides) > 1 {
+		message = fmt.Sprintf("%s (%d targets overridden)", message, len(overrides))
+	}
+	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeOverrideDetected,
+		metav1.ConditionTrue, ReasonOverrideDetected, message)
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestHealingPolicyReconciler_detectManualOverride(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	now := time.Now()
+	completed := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	lastAction := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "scale-web-abc",
+			Namespace: "default",
+			Labels:    map[string]string{LabelPolicyName: "web-policy"},
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "web-policy", Namespace: "default"},
+			TargetResource: v1alpha1.TargetResource{Kind: "Deployment", Name: "web", Namespace: "apps", UID: "uid-web"},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Phase:            v1alpha1.HealingActionPhaseSucceeded,
+			CompletionTime:   &completed,
+			TargetGeneration: 3,
+		},
+	}
+
+	managedFields := func(manager string, age time.Duration, subresource string) []metav1.ManagedFieldsEntry {
+		changed := metav1.NewTime(now.Add(-age))
+		return []metav1.ManagedFieldsEntry{
+			{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &changed},
+			{Manager: manager, Operation: metav1.ManagedFieldsOperationUpdate, Subresource: subresource, Time: &changed},
+		}
+	}
+
+	hour := &metav1.Duration{Duration: time.Hour}
+
+	tests := []struct {
+		name            string
+		generation      int64
+		managedFields   []metav1.ManagedFieldsEntry
+		pause           *metav1.Duration
+		expectOverride  bool
+		expectedManager string
+	}{
+		{
+			name:       "unchanged since last action",
+			generation: 3,
+			pause:      hour,
+		},
+		{
+			name:            "changed by kubectl",
+			generation:      4,
+			managedFields:   managedFields("kubectl-edit", 5*time.Minute, ""),
+			pause:           hour,
+			expectOverride:  true,
+			expectedManager: "kubectl-edit",
+		},
+		{
+			name:          "changed by kubeskippy",
+			generation:    4,
+			managedFields: managedFields(ktypes.FieldManager, 5*time.Minute, ""),
+			pause:         hour,
+		},
+		{
+			name:          "status updates are ignored",
+			generation:    4,
+			managedFields: managedFields("argocd", 5*time.Minute, "status"),
+			pause:         hour,
+			// Generation moved without a recorded spec update, so the
+			// change is attributed to an unknown manager
+			expectOverride:  true,
+			expectedManager: "unknown",
+		},
+		{
+			name:          "pause period elapsed",
+			generation:    4,
+			managedFields: managedFields("kubectl-edit", 5*time.Minute, ""),
+			pause:         &metav1.Duration{Duration: time.Minute},
+		},
+		{
+			name:          "detection is off by default",
+			generation:    4,
+			managedFields: managedFields("kubectl-edit", 5*time.Minute, ""),
+		},
+		{
+			name:          "detection disabled",
+			generation:    4,
+			managedFields: managedFields("kubectl-edit", 5*time.Minute, ""),
+			pause:         &metav1.Duration{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget).
+				WithObjects(lastAction.DeepCopy()).
+				WithStatusSubresource(&v1alpha1.HealingAction{}).
+				Build()
+			// The fake client drops status on create, so set it explicitly
+			stored := &v1alpha1.HealingAction{}
+			require.NoError(t, fakeClient.Get(context.Background(), NamespacedName(lastAction), stored))
+			stored.Status = lastAction.Status
+			require.NoError(t, fakeClient.Status().Update(context.Background(), stored))
+
+			r := &HealingPolicyReconciler{Client: fakeClient, Scheme: scheme}
+
+			policy := &v1alpha1.HealingPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"},
+				Spec: v1alpha1.HealingPolicySpec{
+					SafetyRules: v1alpha1.SafetyRules{OverridePausePeriod: tt.pause},
+				},
+			}
+			target := &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:          "web",
+					Namespace:     "apps",
+					UID:           "uid-web",
+					Generation:    tt.generation,
+					ManagedFields: tt.managedFields,
+				},
+			}
+
+			override, err := r.detectManualOverride(context.Background(), policy, target)
+			require.NoError(t, err)
+			if !tt.expectOverride {
+				assert.Nil(t, override)
+				return
+			}
+			require.NotNil(t, override)
+			assert.Equal(t, tt.expectedManager, override.Manager)
+			assert.Equal(t, "scale-web-abc", override.Action)
+			assert.True(t, override.PausedUntil.After(now))
+		})
+	}
+}
+
+func TestSetOverrideCondition(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{}
+
+	setOverrideCondition(policy, map[string]*ManualOverride{
+		"Deployment/apps/web": {
+			Manager:     "kubectl-edit",
+			ChangedAt:   time.Now(),
+			PausedUntil: time.Now().Add(time.Hour),
+			Action:      "scale-web-abc",
+		},
+	})
+	cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeOverrideDetected)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Contains(t, cond.Message, "Deployment/apps/web was changed by kubectl-edit")
+
+	setOverrideCondition(policy, nil)
+	cond = GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeOverrideDetected)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// ReasonPolicyPaused is set while a policy is paused
+	ReasonPolicyPaused = "PolicyPaused"
+
+	// ReasonPolicyResumed clears a previous Paused condition
+	ReasonPolicyResumed = "PolicyResumed"
+)
+
+// setPausedCondition reports whether the policy is paused
+func setPausedCondition(policy *v1alpha1.HealingPolicy) {
+	if policy.Spec.Paused {
+		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypePaused,
+			metav1.ConditionTrue, ReasonPolicyPaused, "Policy is paused, triggers are not evaluated")
+		return
+	}
+
+	if cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypePaused); cond != nil && cond.Status == metav1.ConditionTrue {
+		SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypePaused,
+			metav1.ConditionFalse, ReasonPolicyResumed, "Policy was resumed")
+	}
+}
+
+// isPolicyPaused reports whether the policy that created an action is
+// paused. Actions whose policy no longer exists are not held.
+func isPolicyPaused(ctx context.Context, c client.Reader, action *v1alpha1.HealingAction) (bool, error) {
+	ref := action.Spec.PolicyRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = action.Namespace
+	}
+
+	policy := &v1alpha1.HealingPolicy{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, policy); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get policy %s/%s: %w", namespace, ref.Name, err)
+	}
+	return policy.Spec.Paused, nil
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func pausedPolicy(paused bool) *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-policy",
+			Namespace:  "default",
+			Finalizers: []string{FinalizerName},
+		},
+		Spec: v1alpha1.HealingPolicySpec{
+			Mode:   "automatic",
+			Paused: paused,
+		},
+	}
+}
+
+func TestHealingPolicyReconciler_Paused(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	policy := pausedPolicy(true)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(policy).
+		WithStatusSubresource(policy).
+		Build()
+
+	collected := false
+	r := &HealingPolicyReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		MetricsCollector: &MockMetricsCollector{
+			CollectMetricsFunc: func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error) {
+				collected = true
+				return &ktypes.ClusterMetrics{}, nil
+			},
+		},
+		SafetyController: &MockSafetyController{},
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, collected, "paused policy should not be evaluated")
+
+	updated := &v1alpha1.HealingPolicy{}
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, updated))
+	cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypePaused)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	// Resuming evaluates the policy again and clears the condition
+	updated.Spec.Paused = false
+	require.NoError(t, fakeClient.Update(context.Background(), updated))
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, collected)
+
+	require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, updated))
+	cond = GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypePaused)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, ReasonPolicyResumed, cond.Reason)
+}
+
+func TestHealingActionReconciler_HoldsActionsOfPausedPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		paused        bool
+		phase         string
+		held          bool
+		expectedPhase string
+	}{
+		{name: "paused policy holds action", paused: true, phase: v1alpha1.HealingActionPhasePending, expectedPhase: v1alpha1.HealingActionPhasePending},
+		{name: "paused policy keeps approved action approved", paused: true, phase: v1alpha1.HealingActionPhaseApproved, expectedPhase: v1alpha1.HealingActionPhaseApproved},
+		{name: "active policy approves action", paused: false, phase: v1alpha1.HealingActionPhasePending, expectedPhase: v1alpha1.HealingActionPhaseApproved},
+		{name: "resumed policy releases held action", paused: false, phase: v1alpha1.HealingActionPhasePending, held: true, expectedPhase: v1alpha1.HealingActionPhaseApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+			policy := pausedPolicy(tt.paused)
+			action := &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-action",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef: v1alpha1.PolicyReference{Name: policy.Name, Namespace: policy.Namespace},
+					Action:    v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+				},
+				Status: v1alpha1.HealingActionStatus{Phase: tt.phase},
+			}
+			if tt.held {
+				SetCondition(&action.Status.Conditions, v1alpha1.ConditionTypePaused,
+					metav1.ConditionTrue, ReasonPolicyPaused, "held")
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(policy, action).
+				WithStatusSubresource(policy, action).
+				Build()
+
+			r := &HealingActionReconciler{
+				Client:            fakeClient,
+				Scheme:            scheme,
+				Config:            config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{},
+				SafetyController:  &MockSafetyController{},
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: action.Name, Namespace: action.Namespace}}
+			result, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			updated := &v1alpha1.HealingAction{}
+			require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, updated))
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+
+			cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypePaused)
+			switch {
+			case tt.paused:
+				assert.Equal(t, time.Minute, result.RequeueAfter)
+				require.NotNil(t, cond)
+				assert.Equal(t, metav1.ConditionTrue, cond.Status)
+				assert.Equal(t, ReasonPolicyPaused, cond.Reason)
+			case tt.held:
+				require.NotNil(t, cond)
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.Equal(t, ReasonPolicyResumed, cond.Reason)
+			default:
+				assert.Nil(t, cond)
+			}
+		})
+	}
+}
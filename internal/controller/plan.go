@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// PolicyPlan previews what a policy would do against the live cluster
+// without changing anything
+type PolicyPlan struct {
+	Policy    string          `json:"policy"`
+	Namespace string          `json:"namespace"`
+	Mode      string          `json:"mode"`
+	Resources []string        `json:"matchedResources"`
+	Triggers  []TriggerPlan   `json:"triggers"`
+	Actions   []PlannedAction `json:"actions"`
+	Warnings  []string        `json:"warnings,omitempty"`
+}
+
+// TriggerPlan is the current state of one trigger
+type TriggerPlan struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Triggered bool   `json:"triggered"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PlannedAction is an action the policy would generate. Skipped holds why
+// the action would not be created.
+type PlannedAction struct {
+	Trigger  string `json:"trigger"`
+	Action   string `json:"action"`
+	Type     string `json:"type"`
+	Target   string `json:"target"`
+	DryRun   bool   `json:"dryRun"`
+	Severity string `json:"severity,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Skipped  string `json:"skipped,omitempty"`
+
+	// AI and Safety are the AI filtering and safety verdicts, set when a
+	// policy is tested against a metrics snapshot
+	AI       string   `json:"ai,omitempty"`
+	Safety   string   `json:"safety,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PlanPolicy evaluates a policy, which need not exist in the cluster, in
+// read-only mode. Cooldowns, rate limits and AI filtering are not applied
+// since they depend on the policy's history; safety validation beyond
+// protected resources runs when the actions execute.
+func (r *HealingPolicyReconciler) PlanPolicy(ctx context.Context, policy *v1alpha1.HealingPolicy) (*PolicyPlan, error) {
+	plan, _, err := r.planPolicy(ctx, policy, nil)
+	return plan, err
+}
+
+// planEntry is a triggered action of a plan and the action it would create,
+// nil when skipped
+type planEntry struct {
+	triggered TriggeredAction
+	action    *v1alpha1.HealingAction
+}
+
+// planPolicy plans a policy against clusterMetrics, collecting live metrics
+// when it is nil. It also returns an entry for each of plan.Actions.
+func (r *HealingPolicyReconciler) planPolicy(ctx context.Context, policy *v1alpha1.HealingPolicy, clusterMetrics *types.ClusterMetrics) (*PolicyPlan, []planEntry, error) {
+	plan := &PolicyPlan{
+		Policy:    policy.Name,
+		Namespace: policy.Namespace,
+		Mode:      policy.Spec.Mode,
+		Resources: []string{},
+		Triggers:  []TriggerPlan{},
+		Actions:   []PlannedAction{},
+	}
+
+	if err := validatePolicyActions(ctx, r.Client, policy, r.Config.Safety.RequireActionTemplates); err != nil {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("policy actions are invalid: %v", err))
+	}
+	if policy.Spec.Mode == "monitor" {
+		plan.Warnings = append(plan.Warnings, "policy is in monitor mode, no actions would be created")
+	}
+	if policy.Spec.Paused {
+		plan.Warnings = append(plan.Warnings, "policy is paused, no actions would be created")
+	}
+
+	resources, err := r.findMatchingResources(ctx, policy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find matching resources: %w", err)
+	}
+	for _, resource := range resources {
+		plan.Resources = append(plan.Resources, planTarget(resource.GetObjectKind().GroupVersionKind().Kind,
+			resource.GetNamespace(), resource.GetName()))
+	}
+	sort.Strings(plan.Resources)
+
+	var advancedMetrics interface{}
+	if clusterMetrics == nil {
+		clusterMetrics, err = r.MetricsCollector.CollectMetrics(ctx, policy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to collect metrics: %w", err)
+		}
+		if advancedCollector, ok := r.MetricsCollector.(*metrics.AdvancedCollector); ok {
+			if advanced, err := advancedCollector.CollectAdvancedMetrics(ctx, policy); err == nil {
+				advancedMetrics = advanced
+			}
+		}
+	}
+
+	now := time.Now()
+	var entries []planEntry
+	for i := range policy.Spec.Triggers {
+		trigger := &policy.Spec.Triggers[i]
+		var result types.TriggerResult
+		var matches []targetMatch
+		var err error
+		if isTemplatedQuery(trigger) {
+			result.Triggered, result.Reason, matches, err = r.evaluatePerTarget(ctx, policy, trigger, clusterMetrics, advancedMetrics)
+		} else {
+			result, err = r.evaluateTrigger(ctx, policy, trigger, clusterMetrics, advancedMetrics)
+		}
+		tp := TriggerPlan{Name: trigger.Name, Type: trigger.Type, Triggered: result.Triggered, Reason: result.Reason}
+		if err != nil {
+			tp.Triggered = false
+			tp.Error = err.Error()
+		}
+		plan.Triggers = append(plan.Triggers, tp)
+		if !tp.Triggered {
+			continue
+		}
+
+		if matches == nil {
+			matches = targetMatches(trigger, result,
+				filterStuckTargets(trigger, filterPodStateTargets(trigger, resources, now), now))
+		}
+		for _, match := range matches {
+			templateContext := NewTemplateContext(match.trigger, match.result, match.resource)
+			for _, actionTemplate := range policy.Spec.Actions {
+				ta := TriggeredAction{
+					Trigger:         trigger.Name,
+					Resource:        match.resource,
+					Action:          actionTemplate,
+					Reason:          match.result.Reason,
+					TemplateContext: templateContext,
+				}
+				applySeverity(policy, trigger, &ta)
+				planned, action := r.planAction(ctx, policy, ta)
+				plan.Actions = append(plan.Actions, planned)
+				entries = append(entries, planEntry{triggered: ta, action: action})
+			}
+		}
+	}
+
+	sort.Stable(plannedActions{plan.Actions, entries})
+	return plan, entries, nil
+}
+
+// plannedActions sorts planned actions with their entries, putting actions
+// that would be created first
+type plannedActions struct {
+	planned []PlannedAction
+	entries []planEntry
+}
+
+func (p plannedActions) Len() int { return len(p.planned) }
+func (p plannedActions) Less(i, j int) bool {
+	return p.planned[i].Skipped == "" && p.planned[j].Skipped != ""
+}
+func (p plannedActions) Swap(i, j int) {
+	p.planned[i], p.planned[j] = p.planned[j], p.planned[i]
+	p.entries[i], p.entries[j] = p.entries[j], p.entries[i]
+}
+
+// planAction builds the action a triggered action would create and notes
+// why it would be skipped
+func (r *HealingPolicyReconciler) planAction(ctx context.Context, policy *v1alpha1.HealingPolicy, ta TriggeredAction) (PlannedAction, *v1alpha1.HealingAction) {
+	planned := PlannedAction{
+		Trigger: ta.Trigger,
+		Action:  ta.Action.Name,
+		Type:    ta.Action.Type,
+		Target: planTarget(ta.Resource.GetObjectKind().GroupVersionKind().Kind,
+			ta.Resource.GetNamespace(), ta.Resource.GetName()),
+		DryRun:   policy.Spec.Mode == "dryrun",
+		Severity: ta.Severity,
+		Reason:   ta.Reason,
+	}
+
+	if protected, reason := r.SafetyController.IsProtectedResource(ta.Resource); protected {
+		planned.Skipped = fmt.Sprintf("protected resource: %s", reason)
+		return planned, nil
+	}
+
+	override, err := r.detectManualOverride(ctx, policy, ta.Resource)
+	if err != nil {
+		planned.Skipped = fmt.Sprintf("failed to check for manual overrides: %v", err)
+		return planned, nil
+	}
+	if override != nil {
+		planned.Skipped = fmt.Sprintf("manually overridden by %s until %s",
+			override.Manager, override.PausedUntil.Format(time.RFC3339))
+		return planned, nil
+	}
+
+	action, err := r.buildHealingAction(ctx, policy, ta)
+	if err != nil {
+		planned.Skipped = err.Error()
+		return planned, nil
+	}
+	planned.Type = action.Spec.Action.Type
+	planned.DryRun = action.Spec.DryRun
+	return planned, action
+}
+
+func planTarget(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", kind, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// Render writes the plan in a human readable form
+func (p *PolicyPlan) Render(w io.Writer) {
+	fmt.Fprintf(w, "Policy %s/%s (mode: %s)\n", p.Namespace, p.Policy, p.Mode)
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(w, "  ! %s\n", warning)
+	}
+
+	fmt.Fprintf(w, "\nMatched resources (%d):\n", len(p.Resources))
+	for _, resource := range p.Resources {
+		fmt.Fprintf(w, "  %s\n", resource)
+	}
+
+	fmt.Fprintf(w, "\nTriggers:\n")
+	for _, trigger := range p.Triggers {
+		state := "not triggered"
+		if trigger.Triggered {
+			state = "TRIGGERED"
+		}
+		detail := trigger.Reason
+		if trigger.Error != "" {
+			state = "error"
+			detail = trigger.Error
+		}
+		fmt.Fprintf(w, "  %-24s %-14s %-13s %s\n", trigger.Name, trigger.Type, state, detail)
+	}
+
+	create, skip := 0, 0
+	var lines []string
+	for _, action := range p.Actions {
+		mode := ""
+		if action.DryRun {
+			mode = " (dry-run)"
+		}
+		if action.Skipped != "" {
+			skip++
+			lines = append(lines, fmt.Sprintf("  ~ %s %s on %s: skipped, %s", action.Action, action.Type, action.Target, action.Skipped))
+			continue
+		}
+		create++
+		lines = append(lines, fmt.Sprintf("  + %s %s on %s%s [%s]", action.Action, action.Type, action.Target, mode, action.Trigger))
+	}
+
+	fmt.Fprintf(w, "\nActions:\n")
+	if len(lines) > 0 {
+		fmt.Fprintln(w, strings.Join(lines, "\n"))
+	}
+	fmt.Fprintf(w, "\nPlan: %d to create, %d skipped.\n", create, skip)
+}
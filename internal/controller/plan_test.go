@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestPlanPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web"},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget).
+		WithObjects(pod("web-1"), pod("web-2")).
+		Build()
+
+	// The policy is only proposed, it does not exist in the cluster
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-restarts", Namespace: "default"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Mode: "dryrun",
+			Selector: v1alpha1.ResourceSelector{
+				Namespaces:    []string{"default"},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Resources:     []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+			},
+			Triggers: []v1alpha1.HealingTrigger{
+				{Name: "high-restarts", Type: "metric"},
+				{Name: "high-memory", Type: "metric"},
+			},
+			Actions: []v1alpha1.HealingActionTemplate{
+				{Name: "restart", Type: "restart"},
+			},
+		},
+	}
+
+	r := &HealingPolicyReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		MetricsCollector: &MockMetricsCollector{
+			EvaluateTriggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
+				if trigger.Name == "high-restarts" {
+					return true, "restarts above threshold", nil
+				}
+				return false, "", nil
+			},
+		},
+		SafetyController: &MockSafetyController{
+			IsProtectedResourceFunc: func(resource runtime.Object) (bool, string) {
+				if obj, ok := resource.(client.Object); ok && obj.GetName() == "web-2" {
+					return true, "protected by label"
+				}
+				return false, ""
+			},
+		},
+	}
+
+	plan, err := r.PlanPolicy(context.Background(), policy)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Pod/default/web-1", "Pod/default/web-2"}, plan.Resources)
+	assert.Equal(t, []TriggerPlan{
+		{Name: "high-restarts", Type: "metric", Triggered: true, Reason: "restarts above threshold"},
+		{Name: "high-memory", Type: "metric"},
+	}, plan.Triggers)
+
+	require.Len(t, plan.Actions, 2)
+	assert.Equal(t, "Pod/default/web-1", plan.Actions[0].Target)
+	assert.True(t, plan.Actions[0].DryRun)
+	assert.Empty(t, plan.Actions[0].Skipped)
+	assert.Equal(t, "Pod/default/web-2", plan.Actions[1].Target)
+	assert.Contains(t, plan.Actions[1].Skipped, "protected resource")
+
+	// Planning never writes to the cluster
+	actions := &v1alpha1.HealingActionList{}
+	require.NoError(t, fakeClient.List(context.Background(), actions))
+	assert.Empty(t, actions.Items)
+
+	var out bytes.Buffer
+	plan.Render(&out)
+	assert.Contains(t, out.String(), "+ restart restart on Pod/default/web-1 (dry-run)")
+	assert.Contains(t, out.String(), "Plan: 1 to create, 1 skipped.")
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+)
+
+// filterPodStateTargets narrows the targets of a condition trigger on a pod
+// state to the pods that have been in that state for the trigger's
+// duration, so a single stuck pod does not act on every matching pod.
+// Other triggers and non-pod targets are returned unchanged.
+func filterPodStateTargets(trigger *v1alpha1.HealingTrigger, resources []client.Object, now time.Time) []client.Object {
+	if trigger.Type != "condition" || trigger.ConditionTrigger == nil || !metrics.IsPodState(trigger.ConditionTrigger.Type) {
+		return resources
+	}
+
+	filtered := make([]client.Object, 0, len(resources))
+	for _, resource := range resources {
+		pod, ok := resource.(*corev1.Pod)
+		if !ok {
+			filtered = append(filtered, resource)
+			continue
+		}
+		if metrics.PodInState(pod, trigger.ConditionTrigger.Type, trigger.ConditionTrigger.Duration.Duration, now) {
+			filtered = append(filtered, resource)
+		}
+	}
+	return filtered
+}
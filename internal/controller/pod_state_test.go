@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestFilterPodStateTargets(t *testing.T) {
+	now := time.Now()
+	old := metav1.NewTime(now.Add(-2 * time.Hour))
+	recent := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	evictedOld := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "evicted-old"},
+		Status:     corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", StartTime: &old},
+	}
+	evictedRecent := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "evicted-recent"},
+		Status:     corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", StartTime: &recent},
+	}
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "running"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+	resources := []client.Object{evictedOld, evictedRecent, running, deployment}
+
+	names := func(objs []client.Object) []string {
+		var out []string
+		for _, obj := range objs {
+			out = append(out, obj.GetName())
+		}
+		return out
+	}
+
+	evicted := &v1alpha1.HealingTrigger{
+		Type: "condition",
+		ConditionTrigger: &v1alpha1.ConditionTrigger{
+			Type: "Evicted", Status: "True", Duration: metav1.Duration{Duration: time.Hour},
+		},
+	}
+	assert.Equal(t, []string{"evicted-old", "web"}, names(filterPodStateTargets(evicted, resources, now)))
+
+	// Other condition triggers keep all targets
+	ready := &v1alpha1.HealingTrigger{
+		Type:             "condition",
+		ConditionTrigger: &v1alpha1.ConditionTrigger{Type: "Ready", Status: "False"},
+	}
+	assert.Len(t, filterPodStateTargets(ready, resources, now), 4)
+
+	metric := &v1alpha1.HealingTrigger{Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{Query: "cpu"}}
+	assert.Len(t, filterPodStateTargets(metric, resources, now), 4)
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// minAIConfidence is the confidence an AI recommendation needs to
+	// select actions
+	minAIConfidence = 0.7
+
+	// maxAIFallbackActions is the number of rule-based actions kept when
+	// no action matches an AI recommendation
+	maxAIFallbackActions = 2
+)
+
+// PolicyTestRequest asks how a policy would handle a metrics snapshot
+type PolicyTestRequest struct {
+	// Policy as namespace/name
+	Policy string `json:"policy"`
+
+	// Metrics snapshot the triggers are evaluated against
+	Metrics *types.ClusterMetrics `json:"metrics"`
+
+	// AIAnalysis to filter actions with instead of calling the AI
+	AIAnalysis *types.AIAnalysis `json:"aiAnalysis,omitempty"`
+
+	// RunAI calls the configured AI analyzer when no analysis is given
+	RunAI bool `json:"runAI,omitempty"`
+}
+
+// PolicyTestResult is the evaluation of a policy against a snapshot. Each
+// planned action carries its AI filtering and safety verdicts.
+type PolicyTestResult struct {
+	*PolicyPlan
+
+	// AISummary of the analysis used for filtering
+	AISummary string `json:"aiSummary,omitempty"`
+}
+
+// TestPolicy evaluates a policy against a metrics snapshot without changing
+// anything. Targets are matched against the live cluster; AI filtering runs
+// when an analysis is given or requested, and every action that would be
+// created is validated by the safety controller.
+func (r *HealingPolicyReconciler) TestPolicy(ctx context.Context, policy *v1alpha1.HealingPolicy, req *PolicyTestRequest) (*PolicyTestResult, error) {
+	if req.Metrics == nil {
+		return nil, fmt.Errorf("a metrics snapshot is required")
+	}
+
+	plan, entries, err := r.planPolicy(ctx, policy, req.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	result := &PolicyTestResult{PolicyPlan: plan}
+
+	analysis := req.AIAnalysis
+	if analysis == nil && req.RunAI && r.AIAnalyzer != nil {
+		var triggered []TriggeredAction
+		for _, entry := range entries {
+			if entry.action != nil {
+				triggered = append(triggered, entry.triggered)
+			}
+		}
+		if len(triggered) > 0 {
+			// Lite profiles show the AI the pods the controller would sample
+			aiMetrics := req.Metrics
+			if budget := aiBudgetFor(policy); budget.lite {
+				aiMetrics = metrics.SamplePods(req.Metrics, budget.maxPods, aiSampleKeys(triggered))
+			}
+			analysis, err = r.getAIRecommendations(ctx, nil, aiMetrics, triggered)
+			if err != nil {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("AI analysis failed, actions are not filtered: %v", err))
+			}
+		}
+	}
+	if analysis != nil {
+		result.AISummary = analysis.Summary
+		r.applyAIVerdicts(plan.Actions, entries, analysis)
+	}
+
+	for i, entry := range entries {
+		if entry.action == nil || plan.Actions[i].Skipped != "" {
+			continue
+		}
+		validation, err := r.SafetyController.ValidateAction(ctx, entry.action)
+		switch {
+		case err != nil:
+			plan.Actions[i].Safety = fmt.Sprintf("validation failed: %v", err)
+		case !validation.Valid:
+			plan.Actions[i].Safety = "rejected: " + validation.Reason
+			plan.Actions[i].Skipped = "rejected by safety controller: " + validation.Reason
+		default:
+			plan.Actions[i].Safety = "valid"
+			plan.Actions[i].Warnings = validation.Warnings
+		}
+	}
+
+	sort.Stable(plannedActions{plan.Actions, entries})
+	return result, nil
+}
+
+// applyAIVerdicts records which actions the AI filtering of an evaluation
+// keeps: those matching a confident recommendation or, when none matches,
+// the highest priority rule-based actions. In blend mode every action is
+// kept with its blended priority.
+func (r *HealingPolicyReconciler) applyAIVerdicts(planned []PlannedAction, entries []planEntry, analysis *types.AIAnalysis) {
+	var candidates []int
+	for i, entry := range entries {
+		if entry.action != nil && planned[i].Skipped == "" {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(analysis.Recommendations) == 0 {
+		for _, i := range candidates {
+			planned[i].AI = "no AI recommendations, kept"
+		}
+		return
+	}
+
+	// Blending keeps every action, ordered by its blended priority
+	if r.aiDecisionMode() == AIDecisionModeBlend {
+		weights := r.blendWeights()
+		for _, i := range candidates {
+			recommendation := r.bestRecommendation(entries[i].triggered, analysis.Recommendations)
+			planned[i].AI = "blended priority: " + blendDecision(entries[i].triggered, recommendation, weights).Explanation
+		}
+		return
+	}
+
+	matched := false
+	for _, i := range candidates {
+		for _, recommendation := range analysis.Recommendations {
+			if recommendation.Confidence >= minAIConfidence && r.matchesAIRecommendation(entries[i].triggered, recommendation) {
+				planned[i].AI = fmt.Sprintf("recommended by AI: %s (%.0f%% confidence)",
+					recommendation.Action, recommendation.Confidence*100)
+				matched = true
+				break
+			}
+		}
+	}
+
+	// Without any match the highest priority actions are kept
+	if !matched {
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return entries[candidates[a]].triggered.Action.Priority > entries[candidates[b]].triggered.Action.Priority
+		})
+		for n, i := range candidates {
+			if n < maxAIFallbackActions {
+				planned[i].AI = "no action matched the AI recommendations, kept as rule-based fallback"
+			}
+		}
+	}
+
+	for _, i := range candidates {
+		if planned[i].AI == "" {
+			planned[i].AI = "not recommended by AI"
+			planned[i].Skipped = "filtered out by AI analysis"
+		}
+	}
+}
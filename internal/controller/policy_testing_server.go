@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/serving"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+const (
+	// PolicyTestPath is the path of the policy testing endpoint
+	PolicyTestPath = "/debug/policies/test"
+
+	// AIDryRunPath is the path of the AI dry-run endpoint
+	AIDryRunPath = "/debug/policies/ai"
+
+	// maxPolicyTestBodyBytes bounds policy test requests
+	maxPolicyTestBodyBytes = 8 << 20
+)
+
+// PolicyTestServer serves the policy testing debug endpoints: a POSTed
+// PolicyTestRequest is answered with the PolicyTestResult of the named
+// policy, and a POSTed AIDryRunRequest with the AIDryRun of the named
+// policy. Every request must present the configured bearer token.
+type PolicyTestServer struct {
+	reconciler *HealingPolicyReconciler
+	addr       string
+	token      string
+	tlsConfig  *tls.Config
+}
+
+// AIDryRunRequest asks what the AI analysis of a policy says right now
+type AIDryRunRequest struct {
+	// Policy as namespace/name
+	Policy string `json:"policy"`
+}
+
+// NewPolicyTestServer creates a new policy test server
+func NewPolicyTestServer(reconciler *HealingPolicyReconciler, addr, token string) (*PolicyTestServer, error) {
+	if token == "" {
+		return nil, fmt.Errorf("a bearer token is required for the policy testing endpoint")
+	}
+	return &PolicyTestServer{reconciler: reconciler, addr: addr, token: token}, nil
+}
+
+// LoadPolicyTestServer creates a policy test server using the token stored
+// in the configured Secret
+func LoadPolicyTestServer(ctx context.Context, reader client.Reader, reconciler *HealingPolicyReconciler, cfg config.PolicyTestingConfig) (*PolicyTestServer, error) {
+	if cfg.TokenSecretName == "" {
+		return nil, fmt.Errorf("policyTesting.tokenSecretName is required")
+	}
+	secret := &corev1.Secret{}
+	name := k8stypes.NamespacedName{Name: cfg.TokenSecretName, Namespace: cfg.TokenSecretNamespace}
+	if err := reader.Get(ctx, name, secret); err != nil {
+		return nil, fmt.Errorf("failed to get policy testing token secret %s: %w", name, err)
+	}
+	data, ok := secret.Data[cfg.TokenSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("policy testing token secret %s has no key %q", name, cfg.TokenSecretKey)
+	}
+	return NewPolicyTestServer(reconciler, cfg.BindAddress, strings.TrimSpace(string(data)))
+}
+
+// WithTLS serves the endpoint over HTTPS
+func (s *PolicyTestServer) WithTLS(tlsConfig *tls.Config) *PolicyTestServer {
+	s.tlsConfig = tlsConfig
+	return s
+}
+
+// NeedLeaderElection is false so every replica serves the endpoint
+func (s *PolicyTestServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the endpoint until the context is cancelled
+func (s *PolicyTestServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(PolicyTestPath, s)
+	mux.Handle(AIDryRunPath, s)
+	server := &http.Server{Addr: s.addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).WithName("policy-testing").Info("Serving policy testing endpoints", "address", s.addr, "paths", []string{PolicyTestPath, AIDryRunPath}, "tls", s.tlsConfig != nil)
+	if err := serving.ListenAndServe(server, s.tlsConfig); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("policy testing endpoint failed: %w", err)
+	}
+	return nil
+}
+
+// ServeHTTP evaluates the requested policy against the posted snapshot, or
+// dry-runs its AI analysis
+func (s *PolicyTestServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPolicyTestBodyBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.URL.Path == AIDryRunPath {
+		s.serveAIDryRun(req.Context(), w, body)
+		return
+	}
+
+	var testReq PolicyTestRequest
+	if err := json.Unmarshal(body, &testReq); err != nil {
+		http.Error(w, fmt.Sprintf("invalid policy test request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if testReq.Metrics == nil {
+		http.Error(w, "a metrics snapshot is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Context()
+	policy, ok := s.getPolicy(ctx, w, testReq.Policy)
+	if !ok {
+		return
+	}
+
+	result, err := s.reconciler.TestPolicy(ctx, policy, &testReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(ctx, w, result, "Failed to write policy test result")
+}
+
+// serveAIDryRun answers an AIDryRunRequest
+func (s *PolicyTestServer) serveAIDryRun(ctx context.Context, w http.ResponseWriter, body []byte) {
+	var dryRunReq AIDryRunRequest
+	if err := json.Unmarshal(body, &dryRunReq); err != nil {
+		http.Error(w, fmt.Sprintf("invalid AI dry-run request: %v", err), http.StatusBadRequest)
+		return
+	}
+	policy, ok := s.getPolicy(ctx, w, dryRunReq.Policy)
+	if !ok {
+		return
+	}
+
+	result, err := s.reconciler.DryRunAI(ctx, policy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(ctx, w, result, "Failed to write AI dry-run result")
+}
+
+// getPolicy gets the policy named namespace/name, writing the error
+// response when it cannot
+func (s *PolicyTestServer) getPolicy(ctx context.Context, w http.ResponseWriter, key string) (*v1alpha1.HealingPolicy, bool) {
+	namespace, name, ok := strings.Cut(key, "/")
+	if !ok || namespace == "" || name == "" {
+		http.Error(w, "policy must be given as namespace/name", http.StatusBadRequest)
+		return nil, false
+	}
+
+	policy := &v1alpha1.HealingPolicy{}
+	if err := s.reconciler.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: name}, policy); err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, fmt.Sprintf("failed to get policy %s: %v", key, err), status)
+		return nil, false
+	}
+	return policy, true
+}
+
+// writeJSON writes a JSON response
+func writeJSON(ctx context.Context, w http.ResponseWriter, v any, failure string) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.FromContext(ctx).Error(err, failure)
+	}
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func newPolicyTestReconciler(t *testing.T, validate func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error)) (*HealingPolicyReconciler, *v1alpha1.HealingPolicy) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+		},
+	}
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-memory", Namespace: "default"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Mode: "automatic",
+			Selector: v1alpha1.ResourceSelector{
+				Namespaces:    []string{"default"},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Resources:     []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+			},
+			Triggers: []v1alpha1.HealingTrigger{
+				{Name: "high-memory", Type: "metric"},
+			},
+			Actions: []v1alpha1.HealingActionTemplate{
+				{Name: "restart", Type: "restart", Priority: 10},
+				{Name: "patch", Type: "patch", Priority: 5},
+				{Name: "delete", Type: "delete", Priority: 1},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget).
+		WithObjects(pod, policy).
+		Build()
+
+	r := &HealingPolicyReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Config: config.NewDefaultConfig(),
+		MetricsCollector: &MockMetricsCollector{
+			EvaluateTriggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
+				// The trigger only fires for the posted snapshot
+				if metrics.Nodes[0].Name == "hot-node" {
+					return true, "memory above threshold", nil
+				}
+				return false, "", nil
+			},
+		},
+		SafetyController: &MockSafetyController{ValidateActionFunc: validate},
+	}
+	return r, policy
+}
+
+func hotSnapshot() *ktypes.ClusterMetrics {
+	return &ktypes.ClusterMetrics{Nodes: []ktypes.NodeMetrics{{Name: "hot-node"}}}
+}
+
+func plannedByName(plan *PolicyTestResult) map[string]PlannedAction {
+	byName := make(map[string]PlannedAction)
+	for _, action := range plan.Actions {
+		byName[action.Action] = action
+	}
+	return byName
+}
+
+func TestTestPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis *ktypes.AIAnalysis
+		validate func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error)
+		skipped  map[string]string
+		ai       map[string]string
+		safety   map[string]string
+	}{
+		{
+			name:    "no AI analysis keeps all triggered actions",
+			skipped: map[string]string{"restart": "", "patch": "", "delete": ""},
+			safety:  map[string]string{"restart": "valid", "patch": "valid", "delete": "valid"},
+		},
+		{
+			name: "confident AI recommendation selects matching action",
+			analysis: &ktypes.AIAnalysis{
+				Summary:         "memory leak",
+				Recommendations: []ktypes.AIRecommendation{{Action: "rolling_restart", Confidence: 0.9}},
+			},
+			skipped: map[string]string{"restart": "", "patch": "filtered out by AI analysis", "delete": "filtered out by AI analysis"},
+			ai:      map[string]string{"restart": "recommended by AI: rolling_restart (90% confidence)", "patch": "not recommended by AI"},
+			safety:  map[string]string{"restart": "valid", "patch": ""},
+		},
+		{
+			name: "unmatched recommendations fall back to highest priority actions",
+			analysis: &ktypes.AIAnalysis{
+				Recommendations: []ktypes.AIRecommendation{{Action: "restart", Confidence: 0.5}},
+			},
+			skipped: map[string]string{"restart": "", "patch": "", "delete": "filtered out by AI analysis"},
+			ai:      map[string]string{"restart": "no action matched the AI recommendations, kept as rule-based fallback"},
+		},
+		{
+			name: "safety rejection skips the action",
+			validate: func(ctx context.Context, action *v1alpha1.HealingAction) (*ktypes.ValidationResult, error) {
+				if action.Spec.Action.Type == "delete" {
+					return &ktypes.ValidationResult{Valid: false, Reason: "deletes are blocked"}, nil
+				}
+				return &ktypes.ValidationResult{Valid: true}, nil
+			},
+			skipped: map[string]string{"restart": "", "delete": "rejected by safety controller: deletes are blocked"},
+			safety:  map[string]string{"restart": "valid", "delete": "rejected: deletes are blocked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, policy := newPolicyTestReconciler(t, tt.validate)
+
+			result, err := r.TestPolicy(context.Background(), policy, &PolicyTestRequest{
+				Policy:     "default/web-memory",
+				Metrics:    hotSnapshot(),
+				AIAnalysis: tt.analysis,
+			})
+			require.NoError(t, err)
+			require.Len(t, result.Triggers, 1)
+			assert.True(t, result.Triggers[0].Triggered)
+			require.Len(t, result.Actions, 3)
+
+			byName := plannedByName(result)
+			for name, skipped := range tt.skipped {
+				assert.Equal(t, skipped, byName[name].Skipped, name)
+			}
+			for name, verdict := range tt.ai {
+				assert.Equal(t, verdict, byName[name].AI, name)
+			}
+			for name, verdict := range tt.safety {
+				assert.Equal(t, verdict, byName[name].Safety, name)
+			}
+
+			// Testing never writes to the cluster
+			actions := &v1alpha1.HealingActionList{}
+			require.NoError(t, r.List(context.Background(), actions))
+			assert.Empty(t, actions.Items)
+		})
+	}
+}
+
+func TestTestPolicyRequiresSnapshot(t *testing.T) {
+	r, policy := newPolicyTestReconciler(t, nil)
+	_, err := r.TestPolicy(context.Background(), policy, &PolicyTestRequest{Policy: "default/web-memory"})
+	assert.Error(t, err)
+}
+
+func TestPolicyTestServer(t *testing.T) {
+	r, _ := newPolicyTestReconciler(t, nil)
+	server, err := NewPolicyTestServer(r, ":0", "s3cret")
+	require.NoError(t, err)
+
+	_, err = NewPolicyTestServer(r, ":0", "")
+	assert.Error(t, err)
+
+	post := func(token string, body any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, PolicyTestPath, bytes.NewReader(data))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	valid := PolicyTestRequest{Policy: "default/web-memory", Metrics: hotSnapshot()}
+
+	assert.Equal(t, http.StatusUnauthorized, post("", valid).Code)
+	assert.Equal(t, http.StatusUnauthorized, post("wrong", valid).Code)
+	assert.Equal(t, http.StatusBadRequest, post("s3cret", PolicyTestRequest{Policy: "web-memory", Metrics: hotSnapshot()}).Code)
+	assert.Equal(t, http.StatusBadRequest, post("s3cret", PolicyTestRequest{Policy: "default/web-memory"}).Code)
+	assert.Equal(t, http.StatusNotFound, post("s3cret", PolicyTestRequest{Policy: "default/missing", Metrics: hotSnapshot()}).Code)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PolicyTestPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = post("s3cret", valid)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var result PolicyTestResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "web-memory", result.Policy)
+	require.Len(t, result.Actions, 3)
+	assert.Equal(t, "valid", result.Actions[0].Safety)
+}
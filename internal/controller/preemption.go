@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// ReasonActionPreempted is set on actions cancelled by a higher-priority
+// action on the same target
+const ReasonActionPreempted = "Preempted"
+
+// preemptConflicting cancels unfinished actions on the same target whose
+// priority is lower than the action's, provided the action's priority
+// reaches the configured preemption priority. Dry-run actions never preempt.
+// It returns the names of the cancelled actions.
+func (r *HealingActionReconciler) preemptConflicting(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) ([]string, error) {
+	threshold := int32(0)
+	if r.Config != nil {
+		threshold = r.Config.Remediation.PreemptionPriority
+	}
+	if threshold <= 0 || action.Spec.DryRun || action.Spec.Action.Priority < threshold {
+		return nil, nil
+	}
+
+	// Actions on a target may come from policies in any namespace
+	target := action.Spec.TargetResource
+	candidates, err := ListActionsForTarget(ctx, r, target.Kind, target.Namespace, target.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &v1alpha1.ActionReference{
+		Name:      action.Name,
+		Namespace: action.Namespace,
+		Priority:  action.Spec.Action.Priority,
+	}
+
+	var preempted []string
+	for i := range candidates {
+		victim := &candidates[i]
+		if !preemptible(action, victim) {
+			continue
+		}
+
+		log.Info("Preempting lower-priority action", "preempted", victim.Name,
+			"namespace", victim.Namespace, "priority", victim.Spec.Action.Priority)
+
+		running := victim.Status.Phase == v1alpha1.HealingActionPhaseInProgress
+		victim.SetPhase(v1alpha1.HealingActionPhaseCancelled, ReasonActionPreempted,
+			fmt.Sprintf("Preempted by %s/%s with priority %d", action.Namespace, action.Name, action.Spec.Action.Priority))
+		victim.Status.PreemptedBy = ref
+		victim.Status.Result = &v1alpha1.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Cancelled in favour of higher-priority action %s/%s", action.Namespace, action.Name),
+		}
+		if _, err := r.completeAction(ctx, log.WithValues("preempted", victim.Name), victim); err != nil {
+			return preempted, fmt.Errorf("failed to preempt action %s/%s: %w", victim.Namespace, victim.Name, err)
+		}
+		preempted = append(preempted, victim.Namespace+"/"+victim.Name)
+
+		// Stop the executor of a running victim; its attempt records the
+		// changes it made before stopping
+		if running {
+			if err := r.RemediationEngine.CancelAction(victim.Name); err == nil {
+				log.Info("Cancelled the executor of the preempted action", "preempted", victim.Name)
+			}
+		}
+	}
+
+	return preempted, nil
+}
+
+// preemptible reports whether candidate is an unfinished, lower-priority
+// action on the same target as action
+func preemptible(action, candidate *v1alpha1.HealingAction) bool {
+	if candidate.Namespace == action.Namespace && candidate.Name == action.Name {
+		return false
+	}
+	if candidate.IsComplete() || candidate.Spec.Action.Priority >= action.Spec.Action.Priority {
+		return false
+	}
+	return sameTarget(action.Spec.TargetResource, candidate.Spec.TargetResource)
+}
+
+// sameTarget reports whether two target references name the same resource
+func sameTarget(a, b v1alpha1.TargetResource) bool {
+	if a.Kind != b.Kind || a.Namespace != b.Namespace || a.Name != b.Name {
+		return false
+	}
+	return a.UID == "" || b.UID == "" || a.UID == b.UID
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestHealingActionReconciler_Preemption(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	target := v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "web-1", Namespace: "web"}
+	newAction := func(name, namespace string, priority int32, phase string, target v1alpha1.TargetResource) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Finalizers: []string{FinalizerName}},
+			Spec: v1alpha1.HealingActionSpec{
+				TargetResource: target,
+				Action:         v1alpha1.HealingActionTemplate{Name: name, Type: "restart", Priority: priority},
+			},
+			Status: v1alpha1.HealingActionStatus{Phase: phase},
+		}
+	}
+
+	critical := newAction("node-not-ready", "kubeskippy", 100, v1alpha1.HealingActionPhaseApproved, target)
+	objs := []client.Object{
+		critical,
+		newAction("routine-running", "web", 50, v1alpha1.HealingActionPhaseInProgress, target),
+		newAction("routine-pending", "web", 50, v1alpha1.HealingActionPhasePending, target),
+		newAction("routine-done", "web", 50, v1alpha1.HealingActionPhaseSucceeded, target),
+		newAction("other-target", "web", 50, v1alpha1.HealingActionPhaseInProgress,
+			v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "web-2", Namespace: "web"}),
+		newAction("equal-priority", "web", 100, v1alpha1.HealingActionPhaseInProgress, target),
+	}
+
+	newReconciler := func(preemptionPriority int32) (*HealingActionReconciler, client.Client) {
+		var copies []client.Object
+		for _, obj := range objs {
+			copies = append(copies, obj.DeepCopyObject().(client.Object))
+		}
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithIndex(&v1alpha1.HealingAction{}, IndexActionTarget, indexActionTarget).
+			WithObjects(copies...).
+			WithStatusSubresource(&v1alpha1.HealingAction{}).
+			Build()
+		cfg := config.NewDefaultConfig()
+		cfg.Remediation.PreemptionPriority = preemptionPriority
+		return &HealingActionReconciler{
+			Client:            c,
+			Scheme:            scheme,
+			Config:            cfg,
+			RemediationEngine: &MockRemediationEngine{},
+			SafetyController:  &MockSafetyController{},
+		}, c
+	}
+
+	phaseOf := func(t *testing.T, c client.Client, namespace, name string) *v1alpha1.HealingAction {
+		action := &v1alpha1.HealingAction{}
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, action))
+		return action
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: critical.Namespace, Name: critical.Name}}
+
+	t.Run("cancels lower-priority actions on the same target", func(t *testing.T) {
+		r, c := newReconciler(100)
+		var cancelled []string
+		r.RemediationEngine.(*MockRemediationEngine).CancelActionFunc = func(actionName string) error {
+			cancelled = append(cancelled, actionName)
+			return nil
+		}
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"routine-running"}, cancelled, "only running executors are cancelled")
+
+		assert.Equal(t, v1alpha1.HealingActionPhaseInProgress, phaseOf(t, c, "kubeskippy", "node-not-ready").Status.Phase)
+		for _, name := range []string{"routine-running", "routine-pending"} {
+			preempted := phaseOf(t, c, "web", name)
+			assert.Equal(t, v1alpha1.HealingActionPhaseCancelled, preempted.Status.Phase, name)
+			assert.Equal(t, &v1alpha1.ActionReference{Name: "node-not-ready", Namespace: "kubeskippy", Priority: 100},
+				preempted.Status.PreemptedBy)
+			assert.NotNil(t, preempted.Status.CompletionTime)
+		}
+		assert.Equal(t, v1alpha1.HealingActionPhaseSucceeded, phaseOf(t, c, "web", "routine-done").Status.Phase)
+		assert.Equal(t, v1alpha1.HealingActionPhaseInProgress, phaseOf(t, c, "web", "other-target").Status.Phase)
+		assert.Equal(t, v1alpha1.HealingActionPhaseInProgress, phaseOf(t, c, "web", "equal-priority").Status.Phase)
+	})
+
+	t.Run("below the preemption priority", func(t *testing.T) {
+		r, c := newReconciler(200)
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.HealingActionPhaseInProgress, phaseOf(t, c, "web", "routine-running").Status.Phase)
+		assert.Nil(t, phaseOf(t, c, "web", "routine-running").Status.PreemptedBy)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		r, c := newReconciler(0)
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.HealingActionPhasePending, phaseOf(t, c, "web", "routine-pending").Status.Phase)
+	})
+}
+
+func TestSameTarget(t *testing.T) {
+	pod := v1alpha1.TargetResource{Kind: "Pod", Namespace: "web", Name: "web-1", UID: "a"}
+
+	assert.True(t, sameTarget(pod, v1alpha1.TargetResource{Kind: "Pod", Namespace: "web", Name: "web-1"}))
+	assert.False(t, sameTarget(pod, v1alpha1.TargetResource{Kind: "Pod", Namespace: "web", Name: "web-1", UID: "b"}))
+	assert.False(t, sameTarget(pod, v1alpha1.TargetResource{Kind: "Deployment", Namespace: "web", Name: "web-1"}))
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// maxTargetReasons bounds the per-target reasons listed in a trigger reason
+const maxTargetReasons = 5
+
+// QueryVars are the variables available to templated trigger queries
+type QueryVars struct {
+	// Namespace of the target
+	Namespace string
+	// Name of the target
+	Name string
+	// Kind of the target
+	Kind string
+	// PodName is the target's name if it is a pod
+	PodName string
+	// Owner is the workload controlling the target, resolving pods of a
+	// ReplicaSet to its Deployment, or the target itself
+	Owner string
+}
+
+// targetMatch is a target a trigger fired for, with the trigger as
+// evaluated for it
+type targetMatch struct {
+	resource client.Object
+	trigger  *v1alpha1.HealingTrigger
+	result   types.TriggerResult
+}
+
+// isTemplatedQuery reports whether a metric trigger's query references
+// target variables and so is evaluated per matched resource
+func isTemplatedQuery(trigger *v1alpha1.HealingTrigger) bool {
+	return trigger.Type == "metric" && trigger.MetricTrigger != nil && strings.Contains(trigger.MetricTrigger.Query, "{{")
+}
+
+// RenderQuery substitutes target variables into a trigger query
+func RenderQuery(query string, vars QueryVars) (string, error) {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid query template: %w", err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("failed to render query template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// queryVars returns the query variables of a target
+func queryVars(ctx context.Context, c client.Reader, target client.Object) QueryVars {
+	vars := QueryVars{
+		Namespace: target.GetNamespace(),
+		Name:      target.GetName(),
+		Kind:      target.GetObjectKind().GroupVersionKind().Kind,
+		Owner:     target.GetName(),
+	}
+	if _, ok := target.(*corev1.Pod); ok || vars.Kind == "Pod" {
+		vars.PodName = target.GetName()
+	}
+
+	owner := metav1.GetControllerOf(target)
+	if owner == nil {
+		return vars
+	}
+	vars.Owner = owner.Name
+	if owner.Kind == "ReplicaSet" {
+		rs := &appsv1.ReplicaSet{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: target.GetNamespace(), Name: owner.Name}, rs); err == nil {
+			if deployment := metav1.GetControllerOf(rs); deployment != nil {
+				vars.Owner = deployment.Name
+			}
+		}
+	}
+	return vars
+}
+
+// evaluatePerTarget evaluates a templated metric trigger once for every
+// resource matched by the policy, with the query rendered for the resource,
+// and returns the resources it fired for
+func (r *HealingPolicyReconciler) evaluatePerTarget(ctx context.Context, policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger, clusterMetrics *types.ClusterMetrics, advancedMetrics interface{}) (bool, string, []targetMatch, error) {
+	resources, err := r.findMatchingResources(ctx, policy)
+	if err != nil {
+		return false, "", nil, fmt.Errorf("failed to find matching resources: %w", err)
+	}
+
+	var matches []targetMatch
+	var reasons []string
+	for _, resource := range resources {
+		query, err := RenderQuery(trigger.MetricTrigger.Query, queryVars(ctx, r.Client, resource))
+		if err != nil {
+			return false, "", nil, err
+		}
+		rendered := trigger.DeepCopy()
+		rendered.MetricTrigger.Query = query
+
+		result, err := r.evaluateTrigger(ctx, policy, rendered, clusterMetrics, advancedMetrics)
+		if err != nil {
+			return false, "", nil, fmt.Errorf("failed to evaluate trigger for %s/%s: %w", resource.GetNamespace(), resource.GetName(), err)
+		}
+		if result.Triggered {
+			matches = append(matches, targetMatch{resource: resource, trigger: rendered, result: result})
+			reasons = append(reasons, fmt.Sprintf("%s/%s: %s", resource.GetNamespace(), resource.GetName(), result.Reason))
+		}
+	}
+
+	if len(matches) == 0 {
+		return false, fmt.Sprintf("fired for none of %d targets", len(resources)), nil, nil
+	}
+	if len(reasons) > maxTargetReasons {
+		reasons = append(reasons[:maxTargetReasons], fmt.Sprintf("and %d more", len(reasons)-maxTargetReasons))
+	}
+	return true, fmt.Sprintf("fired for %d of %d targets: %s", len(matches), len(resources), strings.Join(reasons, "; ")), matches, nil
+}
+
+// targetMatches pairs every resource with the trigger that fired for all
+// of them
+func targetMatches(trigger *v1alpha1.HealingTrigger, result types.TriggerResult, resources []client.Object) []targetMatch {
+	matches := make([]targetMatch, len(resources))
+	for i, resource := range resources {
+		matches[i] = targetMatch{resource: resource, trigger: trigger, result: result}
+	}
+	return matches
+}
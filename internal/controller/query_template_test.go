@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestRenderQuery(t *testing.T) {
+	vars := QueryVars{Namespace: "apps", Name: "web-1", Kind: "Pod", PodName: "web-1", Owner: "web"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "pod and namespace",
+			query: `rate(container_cpu_usage_seconds_total{namespace="{{.Namespace}}",pod="{{.PodName}}"}[5m])`,
+			want:  `rate(container_cpu_usage_seconds_total{namespace="apps",pod="web-1"}[5m])`,
+		},
+		{
+			name:  "owner",
+			query: `kube_deployment_status_replicas_unavailable{deployment="{{.Owner}}"}`,
+			want:  `kube_deployment_status_replicas_unavailable{deployment="web"}`,
+		},
+		{
+			name:  "no variables",
+			query: `up`,
+			want:  `up`,
+		},
+		{
+			name:    "unknown variable",
+			query:   `up{node="{{.Node}}"}`,
+			wantErr: "failed to render query template",
+		},
+		{
+			name:    "malformed template",
+			query:   `up{pod="{{.PodName"}`,
+			wantErr: "invalid query template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderQuery(tt.query, vars)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func queryTemplateScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	return scheme
+}
+
+func webPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "apps",
+			Labels:          map[string]string{"app": "web"},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f", Controller: boolPtr(true)}},
+		},
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestQueryVars_ResolvesDeployment(t *testing.T) {
+	scheme := queryTemplateScheme(t)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-7d9f",
+			Namespace:       "apps",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: boolPtr(true)}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rs).Build()
+
+	vars := queryVars(context.Background(), c, webPod("web-1"))
+	assert.Equal(t, QueryVars{Namespace: "apps", Name: "web-1", Kind: "Pod", PodName: "web-1", Owner: "web"}, vars)
+
+	// Without a controller the target is its own owner
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+	}
+	vars = queryVars(context.Background(), c, deployment)
+	assert.Equal(t, QueryVars{Namespace: "apps", Name: "web", Kind: "Deployment", Owner: "web"}, vars)
+}
+
+func TestEvaluatePerTarget(t *testing.T) {
+	scheme := queryTemplateScheme(t)
+	var queries []string
+	r := &HealingPolicyReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(webPod("web-1"), webPod("web-2")).Build(),
+		Scheme: scheme,
+		MetricsCollector: &MockMetricsCollector{
+			EvaluateTriggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
+				queries = append(queries, trigger.MetricTrigger.Query)
+				if strings.Contains(trigger.MetricTrigger.Query, "web-1") {
+					return true, "restarts 5 > 3", nil
+				}
+				return false, "restarts 0 <= 3", nil
+			},
+		},
+	}
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-restarts", Namespace: "apps"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Selector: v1alpha1.ResourceSelector{
+				Namespaces:    []string{"apps"},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Resources:     []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+			},
+		},
+	}
+	trigger := &v1alpha1.HealingTrigger{
+		Name:          "restarts",
+		Type:          "metric",
+		MetricTrigger: &v1alpha1.MetricTrigger{Query: `restarts{pod="{{.PodName}}"}`, Threshold: 3, Operator: ">"},
+	}
+	require.True(t, isTemplatedQuery(trigger))
+
+	triggered, reason, matches, err := r.evaluatePerTarget(context.Background(), policy, trigger, &ktypes.ClusterMetrics{}, nil)
+	require.NoError(t, err)
+	assert.True(t, triggered)
+	assert.Equal(t, "fired for 1 of 2 targets: apps/web-1: restarts 5 > 3", reason)
+	assert.ElementsMatch(t, []string{`restarts{pod="web-1"}`, `restarts{pod="web-2"}`}, queries)
+
+	require.Len(t, matches, 1)
+	assert.Equal(t, "web-1", matches[0].resource.GetName())
+	assert.Equal(t, `restarts{pod="web-1"}`, matches[0].trigger.MetricTrigger.Query)
+	assert.Equal(t, `restarts{pod="{{.PodName}}"}`, trigger.MetricTrigger.Query, "the policy trigger is left untouched")
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// maxIncidents bounds the incidents tracked in a policy's status
+const maxIncidents = 200
+
+var (
+	policyRecoverySeconds *prometheus.HistogramVec
+)
+
+// SetPolicyRecoveryMetric sets the time to recovery metric from main.go
+func SetPolicyRecoveryMetric(metric *prometheus.HistogramVec) {
+	policyRecoverySeconds = metric
+}
+
+// incidentTarget identifies a target in incidents
+func incidentTarget(obj client.Object) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName())
+}
+
+// updateIncidents opens an incident for every target a trigger fired for,
+// marks the incidents that got an action and closes the incidents of
+// targets their trigger no longer fires for. firing maps each evaluated
+// trigger to the targets it fired for; triggers that were not evaluated
+// keep their incidents. acted holds the trigger and target of every created
+// action, keyed by incidentKey. The detection-to-resolution times of closed
+// incidents that were acted on are returned.
+func updateIncidents(policy *v1alpha1.HealingPolicy, firing map[string]map[string]bool, acted map[string]bool, now metav1.Time) []time.Duration {
+	configured := make(map[string]bool, len(policy.Spec.Triggers))
+	for _, trigger := range policy.Spec.Triggers {
+		configured[trigger.Name] = true
+	}
+
+	var recoveries []time.Duration
+	open := make(map[string]bool, len(policy.Status.Incidents))
+	incidents := policy.Status.Incidents[:0:0]
+	for _, incident := range policy.Status.Incidents {
+		if !configured[incident.Trigger] {
+			continue
+		}
+		if targets, evaluated := firing[incident.Trigger]; evaluated && !targets[incident.Target] {
+			// Incidents that cleared without an action are not recoveries
+			if incident.ActedAt != nil {
+				recoveries = append(recoveries, now.Sub(incident.DetectedAt.Time))
+			}
+			continue
+		}
+		if incident.ActedAt == nil && acted[incidentKey(incident.Trigger, incident.Target)] {
+			actedAt := now
+			incident.ActedAt = &actedAt
+		}
+		open[incidentKey(incident.Trigger, incident.Target)] = true
+		incidents = append(incidents, incident)
+	}
+
+	for _, trigger := range policy.Spec.Triggers {
+		targets := make([]string, 0, len(firing[trigger.Name]))
+		for target := range firing[trigger.Name] {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			key := incidentKey(trigger.Name, target)
+			if open[key] || len(incidents) >= maxIncidents {
+				continue
+			}
+			incident := v1alpha1.Incident{Trigger: trigger.Name, Target: target, DetectedAt: now}
+			if acted[key] {
+				actedAt := now
+				incident.ActedAt = &actedAt
+			}
+			open[key] = true
+			incidents = append(incidents, incident)
+		}
+	}
+
+	policy.Status.Incidents = incidents
+	return recoveries
+}
+
+// incidentKey identifies the incident of a trigger on a target
+func incidentKey(trigger, target string) string {
+	return trigger + "|" + target
+}
+
+// recordRecoveries adds recoveries to the policy's time to recovery and
+// the recovery histogram
+func recordRecoveries(policy *v1alpha1.HealingPolicy, recoveries []time.Duration, now metav1.Time) {
+	if len(recoveries) == 0 {
+		return
+	}
+	stats := policy.Status.Recovery
+	if stats == nil {
+		stats = &v1alpha1.RecoveryStats{}
+		policy.Status.Recovery = stats
+	}
+
+	for _, recovery := range recoveries {
+		total := stats.MeanTimeToRecovery.Duration*time.Duration(stats.Recoveries) + recovery
+		stats.Recoveries++
+		stats.MeanTimeToRecovery = metav1.Duration{Duration: total / time.Duration(stats.Recoveries)}
+		stats.LastTimeToRecovery = metav1.Duration{Duration: recovery}
+
+		if policyRecoverySeconds != nil {
+			policyRecoverySeconds.WithLabelValues(policy.Name, policy.Namespace).Observe(recovery.Seconds())
+		}
+	}
+	stats.LastRecovered = &now
+}
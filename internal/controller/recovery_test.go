@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestUpdateIncidents(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{{Name: "restarts"}, {Name: "memory"}},
+		},
+	}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(start.Add(d)) }
+	web1, web2 := "Pod/apps/web-1", "Pod/apps/web-2"
+
+	// Both pods restart; only web-1 gets an action
+	recoveries := updateIncidents(policy,
+		map[string]map[string]bool{"restarts": {web1: true, web2: true}, "memory": {}},
+		map[string]bool{incidentKey("restarts", web1): true}, at(0))
+	assert.Empty(t, recoveries)
+	require.Len(t, policy.Status.Incidents, 2)
+
+	// restarts is in cooldown and not evaluated, so its incidents stay open
+	recoveries = updateIncidents(policy, map[string]map[string]bool{"memory": {}}, nil, at(time.Minute))
+	assert.Empty(t, recoveries)
+	assert.Len(t, policy.Status.Incidents, 2)
+
+	// web-2 is acted on later and keeps its detection time
+	recoveries = updateIncidents(policy,
+		map[string]map[string]bool{"restarts": {web1: true, web2: true}},
+		map[string]bool{incidentKey("restarts", web2): true}, at(2*time.Minute))
+	assert.Empty(t, recoveries)
+	for _, incident := range policy.Status.Incidents {
+		require.NotNil(t, incident.ActedAt, incident.Target)
+		assert.Equal(t, at(0), incident.DetectedAt)
+	}
+	assert.Equal(t, at(0), *policy.Status.Incidents[0].ActedAt)
+	assert.Equal(t, at(2*time.Minute), *policy.Status.Incidents[1].ActedAt)
+
+	// web-1 recovers
+	recoveries = updateIncidents(policy, map[string]map[string]bool{"restarts": {web2: true}}, nil, at(5*time.Minute))
+	assert.Equal(t, []time.Duration{5 * time.Minute}, recoveries)
+	require.Len(t, policy.Status.Incidents, 1)
+	assert.Equal(t, web2, policy.Status.Incidents[0].Target)
+
+	// Incidents that clear without an action are not recoveries
+	updateIncidents(policy, map[string]map[string]bool{"memory": {web1: true}}, nil, at(6*time.Minute))
+	recoveries = updateIncidents(policy, map[string]map[string]bool{"memory": {}}, nil, at(7*time.Minute))
+	assert.Empty(t, recoveries)
+
+	// Incidents of removed triggers are dropped
+	policy.Spec.Triggers = policy.Spec.Triggers[1:]
+	recoveries = updateIncidents(policy, map[string]map[string]bool{}, nil, at(8*time.Minute))
+	assert.Empty(t, recoveries)
+	assert.Empty(t, policy.Status.Incidents)
+}
+
+func TestRecordRecoveries(t *testing.T) {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_policy_recovery_seconds"}, []string{"policy", "namespace"})
+	SetPolicyRecoveryMetric(histogram)
+	defer SetPolicyRecoveryMetric(nil)
+
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}}
+	now := metav1.Now()
+	recordRecoveries(policy, nil, now)
+	assert.Nil(t, policy.Status.Recovery)
+
+	recordRecoveries(policy, []time.Duration{2 * time.Minute, 4 * time.Minute}, now)
+	recordRecoveries(policy, []time.Duration{9 * time.Minute}, now)
+
+	stats := policy.Status.Recovery
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(3), stats.Recoveries)
+	assert.Equal(t, 5*time.Minute, stats.MeanTimeToRecovery.Duration)
+	assert.Equal(t, 9*time.Minute, stats.LastTimeToRecovery.Duration)
+	assert.Equal(t, &now, stats.LastRecovered)
+	assert.Equal(t, 1, testutil.CollectAndCount(histogram))
+}
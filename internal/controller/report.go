@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// defaultReportTopIssues is used when a report does not set TopIssues
+	defaultReportTopIssues = 5
+
+	// minDryRunComparisons is the number of conclusive dry-run outcomes
+	// needed to recommend for or against automatic mode
+	minDryRunComparisons = 5
+
+	// enableAutomaticHelpedPercent is the share of conclusive dry-run
+	// outcomes healing must have helped to recommend automatic mode
+	enableAutomaticHelpedPercent = 80
+)
+
+// BuildPolicyReport aggregates the actions of a policy created within
+// [start, end). Pass an empty name to build a summary across policies.
+func BuildPolicyReport(name string, actions []v1alpha1.HealingAction, start, end time.Time, topIssues int) v1alpha1.PolicyReport {
+	report := v1alpha1.PolicyReport{
+		Name:          name,
+		TriggersFired: make(map[string]int32),
+		ActionsByType: make(map[string]int32),
+	}
+
+	issues := make(map[v1alpha1.RecurringIssue]int32)
+	outcomes := make(map[string]int32)
+	var recoveryTotal time.Duration
+	var recovered int32
+
+	for i := range actions {
+		action := &actions[i]
+		created := action.CreationTimestamp.Time
+		if created.Before(start) || !created.Before(end) {
+			continue
+		}
+
+		report.ActionsTotal++
+		report.ActionsByType[action.Spec.Action.Type]++
+
+		trigger := action.Labels[LabelTriggerName]
+		if trigger != "" {
+			report.TriggersFired[trigger]++
+		}
+
+		if action.Labels[LabelAIDriven] == "true" {
+			report.AIDriven++
+		} else {
+			report.Traditional++
+		}
+
+		target := fmt.Sprintf("%s/%s/%s", action.Spec.TargetResource.Kind,
+			action.Spec.TargetResource.Namespace, action.Spec.TargetResource.Name)
+		issues[v1alpha1.RecurringIssue{Trigger: trigger, Target: target}]++
+
+		if action.Spec.DryRun {
+			report.DryRun++
+			if observation := action.Status.DryRunObservation; observation != nil && observation.Outcome != "" {
+				outcomes[observation.Outcome]++
+			}
+			continue
+		}
+
+		switch action.Status.Phase {
+		case v1alpha1.HealingActionPhaseSucceeded:
+			report.Succeeded++
+			if action.Status.CompletionTime != nil {
+				recoveryTotal += action.Status.CompletionTime.Sub(created)
+				recovered++
+			}
+		case v1alpha1.HealingActionPhaseFailed:
+			report.Failed++
+		}
+	}
+
+	if completed := report.Succeeded + report.Failed; completed > 0 {
+		report.SuccessRatePercent = report.Succeeded * 100 / completed
+	}
+	if recovered > 0 {
+		report.MTTR = metav1.Duration{Duration: (recoveryTotal / time.Duration(recovered)).Round(time.Second)}
+	}
+
+	report.TopIssues = topRecurringIssues(issues, topIssues)
+	report.DryRunComparison = compareDryRuns(outcomes)
+	return report
+}
+
+// compareDryRuns turns the outcomes observed for dry-run targets into a
+// "healing would have helped/hurt" comparison. Targets that kept failing
+// or needed someone to intervene would have been helped; targets that
+// recovered on their own would only have been disrupted.
+func compareDryRuns(outcomes map[string]int32) *v1alpha1.DryRunComparison {
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	comparison := &v1alpha1.DryRunComparison{
+		Outcomes:        outcomes,
+		WouldHaveHelped: outcomes[v1alpha1.DryRunOutcomePersisted] + outcomes[v1alpha1.DryRunOutcomeIntervened],
+		WouldHaveHurt:   outcomes[v1alpha1.DryRunOutcomeRecovered],
+		Inconclusive:    outcomes[v1alpha1.DryRunOutcomeInconclusive],
+	}
+	conclusive := comparison.WouldHaveHelped + comparison.WouldHaveHurt
+	if conclusive > 0 {
+		comparison.HelpedPercent = comparison.WouldHaveHelped * 100 / conclusive
+	}
+
+	switch {
+	case conclusive < minDryRunComparisons:
+		comparison.Recommendation = v1alpha1.DryRunRecommendationInsufficientData
+		comparison.Reason = fmt.Sprintf("only %d conclusive dry-run outcomes, at least %d are needed",
+			conclusive, minDryRunComparisons)
+	case comparison.HelpedPercent >= enableAutomaticHelpedPercent:
+		comparison.Recommendation = v1alpha1.DryRunRecommendationEnableAutomatic
+		comparison.Reason = fmt.Sprintf("healing would have helped %d%% of the targets, which kept failing or needed an intervention",
+			comparison.HelpedPercent)
+	default:
+		comparison.Recommendation = v1alpha1.DryRunRecommendationKeepDryRun
+		comparison.Reason = fmt.Sprintf("%d of %d targets recovered on their own, healing would only have disrupted them",
+			comparison.WouldHaveHurt, conclusive)
+	}
+	return comparison
+}
+
+// topRecurringIssues returns the issues that occurred more than once, most
+// frequent first
+func topRecurringIssues(issues map[v1alpha1.RecurringIssue]int32, limit int) []v1alpha1.RecurringIssue {
+	var result []v1alpha1.RecurringIssue
+	for issue, count := range issues {
+		if count < 2 {
+			continue
+		}
+		issue.Count = count
+		result = append(result, issue)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].Trigger != result[j].Trigger {
+			return result[i].Trigger < result[j].Trigger
+		}
+		return result[i].Target < result[j].Target
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// RenderReportMarkdown renders a report for attaching to ops reviews
+func RenderReportMarkdown(report *v1alpha1.HealingReport) string {
+	var sb strings.Builder
+	status := report.Status
+
+	fmt.Fprintf(&sb, "# Healing Report: %s/%s\n\n", report.Namespace, report.Name)
+	if status.PeriodStart != nil && status.PeriodEnd != nil {
+		fmt.Fprintf(&sb, "Period: %s to %s\n\n",
+			status.PeriodStart.Format(time.RFC3339), status.PeriodEnd.Format(time.RFC3339))
+	}
+
+	sb.WriteString("## Summary\n\n")
+	writePolicyReportMarkdown(&sb, status.Summary)
+
+	for _, policy := range status.Policies {
+		fmt.Fprintf(&sb, "## Policy: %s\n\n", policy.Name)
+		writePolicyReportMarkdown(&sb, policy)
+	}
+
+	return sb.String()
+}
+
+// writePolicyReportMarkdown renders a single policy section
+func writePolicyReportMarkdown(sb *strings.Builder, report v1alpha1.PolicyReport) {
+	sb.WriteString("| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(sb, "| Actions | %d |\n", report.ActionsTotal)
+	fmt.Fprintf(sb, "| Succeeded | %d |\n", report.Succeeded)
+	fmt.Fprintf(sb, "| Failed | %d |\n", report.Failed)
+	fmt.Fprintf(sb, "| Dry run | %d |\n", report.DryRun)
+	fmt.Fprintf(sb, "| Success rate | %d%% |\n", report.SuccessRatePercent)
+	fmt.Fprintf(sb, "| MTTR | %s |\n", report.MTTR.Duration)
+	fmt.Fprintf(sb, "| AI-driven / traditional | %d / %d |\n\n", report.AIDriven, report.Traditional)
+
+	if len(report.ActionsByType) > 0 {
+		sb.WriteString("Actions by type:\n\n")
+		for _, key := range sortedKeys(report.ActionsByType) {
+			fmt.Fprintf(sb, "- %s: %d\n", key, report.ActionsByType[key])
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(report.TriggersFired) > 0 {
+		sb.WriteString("Triggers fired:\n\n")
+		for _, key := range sortedKeys(report.TriggersFired) {
+			fmt.Fprintf(sb, "- %s: %d\n", key, report.TriggersFired[key])
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(report.TopIssues) > 0 {
+		sb.WriteString("Top recurring issues:\n\n")
+		for _, issue := range report.TopIssues {
+			fmt.Fprintf(sb, "- %s on %s (%d times)\n", issue.Trigger, issue.Target, issue.Count)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(report.Suggestions) > 0 {
+		sb.WriteString("Tuning suggestions:\n\n")
+		for _, suggestion := range report.Suggestions {
+			writeTuningSuggestionMarkdown(sb, suggestion)
+		}
+		sb.WriteString("\n")
+	}
+
+	if comparison := report.DryRunComparison; comparison != nil {
+		sb.WriteString("Dry run vs. healing:\n\n")
+		fmt.Fprintf(sb, "- Would have helped: %d\n", comparison.WouldHaveHelped)
+		fmt.Fprintf(sb, "- Would have hurt: %d\n", comparison.WouldHaveHurt)
+		fmt.Fprintf(sb, "- Inconclusive: %d\n", comparison.Inconclusive)
+		for _, key := range sortedKeys(comparison.Outcomes) {
+			fmt.Fprintf(sb, "- %s: %d\n", key, comparison.Outcomes[key])
+		}
+		fmt.Fprintf(sb, "- Recommendation: %s (%s)\n\n", comparison.Recommendation, comparison.Reason)
+	}
+}
+
+// writeTuningSuggestionMarkdown renders a tuning suggestion as a list item
+func writeTuningSuggestionMarkdown(sb *strings.Builder, suggestion v1alpha1.TuningSuggestion) {
+	sb.WriteString("- ")
+	if suggestion.Trigger != "" {
+		fmt.Fprintf(sb, "%s: ", suggestion.Trigger)
+	}
+	if suggestion.Field != "" {
+		fmt.Fprintf(sb, "set %s from %s to %s, ", suggestion.Field, suggestion.Current, suggestion.Suggested)
+	} else if suggestion.Suggested != "" {
+		fmt.Fprintf(sb, "%s, ", suggestion.Suggested)
+	}
+	fmt.Fprintf(sb, "%s (%s)\n", suggestion.Reason, suggestion.Source)
+}
+
+// RenderReportJSON renders the report status as indented JSON
+func RenderReportJSON(report *v1alpha1.HealingReport) (string, error) {
+	data, err := json.MarshalIndent(report.Status, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return string(data), nil
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]int32) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
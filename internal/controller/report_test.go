@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func testReportAction(name, trigger, target, actionType string, phase string, created time.Time, recovery time.Duration) v1alpha1.HealingAction {
+	action := v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				LabelPolicyName:  "web-policy",
+				LabelTriggerName: trigger,
+			},
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{Kind: "Deployment", Namespace: "apps", Name: target},
+			Action:         v1alpha1.HealingActionTemplate{Type: actionType},
+		},
+		Status: v1alpha1.HealingActionStatus{Phase: phase},
+	}
+	if recovery > 0 {
+		completed := metav1.NewTime(created.Add(recovery))
+		action.Status.CompletionTime = &completed
+	}
+	return action
+}
+
+func TestBuildPolicyReport(t *testing.T) {
+	end := time.Now()
+	start := end.Add(-7 * 24 * time.Hour)
+	day := end.Add(-24 * time.Hour)
+
+	aiAction := testReportAction("ai", "ai-analysis", "web", "scale", v1alpha1.HealingActionPhaseSucceeded, day, 4*time.Minute)
+	aiAction.Labels[LabelAIDriven] = "true"
+
+	dryRun := testReportAction("dry", "high-cpu", "web", "scale", v1alpha1.HealingActionPhaseSucceeded, day, time.Minute)
+	dryRun.Spec.DryRun = true
+
+	actions := []v1alpha1.HealingAction{
+		testReportAction("restart-1", "crash-loop", "web", "restart", v1alpha1.HealingActionPhaseSucceeded, day, 2*time.Minute),
+		testReportAction("restart-2", "crash-loop", "web", "restart", v1alpha1.HealingActionPhaseSucceeded, day, 6*time.Minute),
+		testReportAction("restart-3", "crash-loop", "web", "restart", v1alpha1.HealingActionPhaseFailed, day, 0),
+		testReportAction("restart-4", "crash-loop", "api", "restart", v1alpha1.HealingActionPhaseSucceeded, day, 0),
+		testReportAction("old", "crash-loop", "web", "restart", v1alpha1.HealingActionPhaseSucceeded, start.Add(-time.Hour), time.Minute),
+		aiAction,
+		dryRun,
+	}
+
+	report := BuildPolicyReport("web-policy", actions, start, end, 5)
+
+	assert.Equal(t, "web-policy", report.Name)
+	assert.Equal(t, int32(6), report.ActionsTotal, "actions before the period are excluded")
+	assert.Equal(t, int32(4), report.Succeeded)
+	assert.Equal(t, int32(1), report.Failed)
+	assert.Equal(t, int32(1), report.DryRun)
+	assert.Equal(t, int32(80), report.SuccessRatePercent)
+	assert.Equal(t, 4*time.Minute, report.MTTR.Duration)
+	assert.Equal(t, int32(1), report.AIDriven)
+	assert.Equal(t, int32(5), report.Traditional)
+	assert.Equal(t, map[string]int32{"restart": 4, "scale": 2}, report.ActionsByType)
+	assert.Equal(t, map[string]int32{"crash-loop": 4, "ai-analysis": 1, "high-cpu": 1}, report.TriggersFired)
+
+	require.Len(t, report.TopIssues, 1)
+	assert.Equal(t, v1alpha1.RecurringIssue{Trigger: "crash-loop", Target: "Deployment/apps/web", Count: 3}, report.TopIssues[0])
+}
+
+func TestBuildPolicyReport_Empty(t *testing.T) {
+	end := time.Now()
+	report := BuildPolicyReport("", nil, end.Add(-time.Hour), end, 5)
+
+	assert.Zero(t, report.ActionsTotal)
+	assert.Zero(t, report.SuccessRatePercent)
+	assert.Zero(t, report.MTTR.Duration)
+	assert.Empty(t, report.TopIssues)
+	assert.Nil(t, report.DryRunComparison)
+}
+
+func TestBuildPolicyReport_DryRunComparison(t *testing.T) {
+	end := time.Now()
+	day := end.Add(-24 * time.Hour)
+	dryRun := func(name, outcome string) v1alpha1.HealingAction {
+		action := testReportAction(name, "traditional", name, "restart", v1alpha1.HealingActionPhaseSucceeded, day, time.Minute)
+		action.Spec.DryRun = true
+		action.Status.DryRunObservation = &v1alpha1.DryRunObservation{Outcome: outcome}
+		return action
+	}
+
+	actions := []v1alpha1.HealingAction{
+		dryRun("a", v1alpha1.DryRunOutcomePersisted),
+		dryRun("b", v1alpha1.DryRunOutcomePersisted),
+		dryRun("c", v1alpha1.DryRunOutcomeIntervened),
+		dryRun("d", v1alpha1.DryRunOutcomeRecovered),
+		dryRun("e", v1alpha1.DryRunOutcomeInconclusive),
+		dryRun("still-observed", ""),
+	}
+
+	report := BuildPolicyReport("web-policy", actions, end.Add(-7*24*time.Hour), end, 5)
+	comparison := report.DryRunComparison
+	require.NotNil(t, comparison)
+	assert.Equal(t, int32(3), comparison.WouldHaveHelped)
+	assert.Equal(t, int32(1), comparison.WouldHaveHurt)
+	assert.Equal(t, int32(1), comparison.Inconclusive)
+	assert.Equal(t, int32(75), comparison.HelpedPercent)
+	assert.Equal(t, v1alpha1.DryRunRecommendationInsufficientData, comparison.Recommendation)
+
+	actions = append(actions, dryRun("f", v1alpha1.DryRunOutcomePersisted))
+	comparison = BuildPolicyReport("web-policy", actions, end.Add(-7*24*time.Hour), end, 5).DryRunComparison
+	assert.Equal(t, int32(80), comparison.HelpedPercent)
+	assert.Equal(t, v1alpha1.DryRunRecommendationEnableAutomatic, comparison.Recommendation)
+
+	actions = append(actions, dryRun("g", v1alpha1.DryRunOutcomeRecovered))
+	comparison = BuildPolicyReport("web-policy", actions, end.Add(-7*24*time.Hour), end, 5).DryRunComparison
+	assert.Equal(t, int32(66), comparison.HelpedPercent)
+	assert.Equal(t, v1alpha1.DryRunRecommendationKeepDryRun, comparison.Recommendation)
+	assert.Equal(t, "2 of 6 targets recovered on their own, healing would only have disrupted them", comparison.Reason)
+}
+
+func TestRenderReportMarkdown(t *testing.T) {
+	report := &v1alpha1.HealingReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "weekly", Namespace: "default"},
+		Status: v1alpha1.HealingReportStatus{
+			Summary: v1alpha1.PolicyReport{ActionsTotal: 3, Succeeded: 2, Failed: 1, SuccessRatePercent: 66},
+			Policies: []v1alpha1.PolicyReport{{
+				Name:          "web-policy",
+				ActionsTotal:  3,
+				ActionsByType: map[string]int32{"restart": 3},
+				TopIssues:     []v1alpha1.RecurringIssue{{Trigger: "crash-loop", Target: "Deployment/apps/web", Count: 3}},
+				DryRunComparison: &v1alpha1.DryRunComparison{
+					Outcomes:        map[string]int32{v1alpha1.DryRunOutcomePersisted: 4, v1alpha1.DryRunOutcomeRecovered: 1},
+					WouldHaveHelped: 4,
+					WouldHaveHurt:   1,
+					HelpedPercent:   80,
+					Recommendation:  v1alpha1.DryRunRecommendationEnableAutomatic,
+					Reason:          "healing would have helped 80% of the targets",
+				},
+			}},
+		},
+	}
+
+	markdown := RenderReportMarkdown(report)
+	assert.Contains(t, markdown, "# Healing Report: default/weekly")
+	assert.Contains(t, markdown, "| Success rate | 66% |")
+	assert.Contains(t, markdown, "## Policy: web-policy")
+	assert.Contains(t, markdown, "- restart: 3")
+	assert.Contains(t, markdown, "- crash-loop on Deployment/apps/web (3 times)")
+	assert.Contains(t, markdown, "- Would have helped: 4\n- Would have hurt: 1\n")
+	assert.Contains(t, markdown, "- Recommendation: EnableAutomatic (healing would have helped 80% of the targets)")
+}
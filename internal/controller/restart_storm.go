@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// ReasonRestartStorm is set while a restart storm trigger is firing
+	ReasonRestartStorm = "RestartStorm"
+
+	// ReasonNoRestartStorm clears a previous RestartStorm condition
+	ReasonNoRestartStorm = "NoRestartStorm"
+)
+
+// isPerPodAction reports whether an action restarts or deletes a single pod
+func isPerPodAction(ta TriggeredAction) bool {
+	if ta.Resource.GetObjectKind().GroupVersionKind().Kind != "Pod" {
+		return false
+	}
+	return ta.Action.Type == "restart" || ta.Action.Type == "delete"
+}
+
+// preferClusterLevelActions drops per-pod restarts and deletes during a
+// restart storm, since restarting individual pods only adds to the churn.
+// Workload-level actions such as pausing deployments are kept.
+func preferClusterLevelActions(actions []TriggeredAction) ([]TriggeredAction, int) {
+	kept := actions[:0:0]
+	for _, ta := range actions {
+		if !isPerPodAction(ta) {
+			kept = append(kept, ta)
+		}
+	}
+	return kept, len(actions) - len(kept)
+}
+
+// setRestartStormCondition reports active restart storms on the policy so
+// operators can respond at the cluster level
+func setRestartStormCondition(policy *v1alpha1.HealingPolicy, storms []string) {
+	if len(storms) == 0 {
+		if cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeRestartStorm); cond != nil && cond.Status == metav1.ConditionTrue {
+			SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeRestartStorm,
+				metav1.ConditionFalse, ReasonNoRestartStorm, "No restart storm detected")
+		}
+		return
+	}
+
+	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeRestartStorm,
+		metav1.ConditionTrue, ReasonRestartStorm,
+		fmt.Sprintf("%s; per-pod restarts and deletes are skipped, consider pausing rollouts or scaling the node pool",
+			strings.Join(storms, "; ")))
+}
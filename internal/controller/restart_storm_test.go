@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestPreferClusterLevelActions(t *testing.T) {
+	pod := &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}}
+	deployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}}
+
+	actions := []TriggeredAction{
+		{Resource: pod, Action: v1alpha1.HealingActionTemplate{Name: "restart-pod", Type: "restart"}},
+		{Resource: pod, Action: v1alpha1.HealingActionTemplate{Name: "delete-pod", Type: "delete"}},
+		{Resource: pod, Action: v1alpha1.HealingActionTemplate{Name: "patch-pod", Type: "patch"}},
+		{Resource: deployment, Action: v1alpha1.HealingActionTemplate{Name: "pause-rollout", Type: "patch"}},
+		{Resource: deployment, Action: v1alpha1.HealingActionTemplate{Name: "restart-deployment", Type: "restart"}},
+	}
+
+	kept, skipped := preferClusterLevelActions(actions)
+	assert.Equal(t, 2, skipped)
+
+	var names []string
+	for _, ta := range kept {
+		names = append(names, ta.Action.Name)
+	}
+	assert.Equal(t, []string{"patch-pod", "pause-rollout", "restart-deployment"}, names)
+	assert.Equal(t, "restart-pod", actions[0].Action.Name, "input must not be modified")
+}
+
+func TestSetRestartStormCondition(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{}
+
+	setRestartStormCondition(policy, nil)
+	assert.Nil(t, GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeRestartStorm))
+
+	setRestartStormCondition(policy, []string{"restart storm in namespace apps: 25 restarts across 6 pods in 5m0s"})
+	cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeRestartStorm)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Contains(t, cond.Message, "namespace apps")
+	assert.Contains(t, cond.Message, "scaling the node pool")
+
+	setRestartStormCondition(policy, nil)
+	cond = GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeRestartStorm)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"hash/fnv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// AnnotationRolloutExcluded marks actions that run as dry-runs because their
+// target is outside the policy's automatic rollout
+const AnnotationRolloutExcluded = "kubeskippy.io/rollout-excluded"
+
+// inRollout reports whether automatic actions of the policy may run on the
+// target. Targets are placed in one of 100 buckets by a hash of the policy
+// and target, so raising the percentage only ever adds targets and each
+// policy rolls out to a different subset.
+func inRollout(policy *v1alpha1.HealingPolicy, target client.Object) bool {
+	if policy.Spec.RolloutPercentage == nil {
+		return true
+	}
+	percentage := *policy.Spec.RolloutPercentage
+	if percentage >= 100 {
+		return true
+	}
+	if percentage <= 0 {
+		return false
+	}
+	return rolloutBucket(policy, target) < uint32(percentage)
+}
+
+// rolloutBucket returns the target's bucket in [0, 100) for the policy. It
+// uses names rather than UIDs so recreated pods keep their bucket.
+func rolloutBucket(policy *v1alpha1.HealingPolicy, target client.Object) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(policy.Namespace + "/" + policy.Name + "|" +
+		target.GetObjectKind().GroupVersionKind().Kind + "/" + target.GetNamespace() + "/" + target.GetName()))
+	return h.Sum32() % 100
+}
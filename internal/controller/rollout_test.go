@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestInRollout(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1alpha1.HealingPolicySpec{Mode: "automatic"},
+	}
+	var pods []client.Object
+	for i := 0; i < 500; i++ {
+		pods = append(pods, &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: "default"},
+		})
+	}
+	selected := func(percentage *int32) map[string]bool {
+		policy.Spec.RolloutPercentage = percentage
+		in := make(map[string]bool)
+		for _, pod := range pods {
+			if inRollout(policy, pod) {
+				in[pod.GetName()] = true
+			}
+		}
+		return in
+	}
+
+	assert.Len(t, selected(nil), 500, "no percentage rolls out to every target")
+	assert.Empty(t, selected(ptr(int32(0))))
+	assert.Len(t, selected(ptr(int32(100))), 500)
+
+	thirty := selected(ptr(int32(30)))
+	assert.InDelta(t, 150, len(thirty), 50)
+	assert.Equal(t, thirty, selected(ptr(int32(30))), "selection is stable")
+
+	// Raising the percentage keeps every target already rolled out to
+	sixty := selected(ptr(int32(60)))
+	assert.Greater(t, len(sixty), len(thirty))
+	for name := range thirty {
+		assert.True(t, sixty[name], "%s dropped out of the rollout", name)
+	}
+}
+
+func TestBuildHealingAction_Rollout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	r := &HealingPolicyReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1alpha1.HealingPolicySpec{Mode: "automatic", RolloutPercentage: ptr(int32(0))},
+	}
+	ta := TriggeredAction{
+		Trigger: "high-restarts",
+		Resource: &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		},
+		Action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+	}
+
+	action, err := r.buildHealingAction(context.Background(), policy, ta)
+	require.NoError(t, err)
+	assert.True(t, action.Spec.DryRun)
+	assert.Equal(t, "true", action.Annotations[AnnotationRolloutExcluded])
+
+	policy.Spec.RolloutPercentage = ptr(int32(100))
+	action, err = r.buildHealingAction(context.Background(), policy, ta)
+	require.NoError(t, err)
+	assert.False(t, action.Spec.DryRun)
+	assert.NotContains(t, action.Annotations, AnnotationRolloutExcluded)
+}
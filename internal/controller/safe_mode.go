@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// AnnotationSafeMode marks actions that run as dry-runs because the
+	// watchdog put the operator in safe mode
+	AnnotationSafeMode = "kubeskippy.io/safe-mode"
+
+	// ReasonSafeMode is set on actions held while the operator is in safe mode
+	ReasonSafeMode = "SafeMode"
+)
+
+// trackReconcile reports a reconcile to the watchdog, if one is configured,
+// and counts reconciles failing on conflicts. The returned function must be
+// called with the reconcile's error.
+func trackReconcile(watchdog Watchdog, controller string) func(err error) {
+	if watchdog == nil {
+		return func(err error) { recordReconcileConflict(controller, err) }
+	}
+	done := watchdog.ReconcileStarted(controller)
+	return func(err error) {
+		recordReconcileConflict(controller, err)
+		done(err)
+	}
+}
+
+// inSafeMode reports whether the watchdog forces new actions to dry-run
+func inSafeMode(watchdog Watchdog) bool {
+	return watchdog != nil && watchdog.SafeMode()
+}
+
+// holdSafeMode keeps an action that has not started pending while the
+// operator is in safe mode
+func (r *HealingActionReconciler) holdSafeMode(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	log.Info("Operator is in safe mode, holding action")
+
+	if cond := GetCondition(action.Status.Conditions, v1alpha1.ConditionTypeReady); cond == nil || cond.Reason != ReasonSafeMode {
+		action.SetPhase(v1alpha1.HealingActionPhasePending, ReasonSafeMode,
+			"Action is held because the operator is in safe mode")
+		if err := patchStatus(ctx, r.Client, action); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
+}
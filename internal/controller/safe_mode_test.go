@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// fakeWatchdog records reconciles and reports a fixed safe mode
+type fakeWatchdog struct {
+	safeMode bool
+	started  []string
+	errs     []error
+}
+
+func (w *fakeWatchdog) ReconcileStarted(controller string) func(err error) {
+	w.started = append(w.started, controller)
+	return func(err error) { w.errs = append(w.errs, err) }
+}
+
+func (w *fakeWatchdog) SafeMode() bool {
+	return w.safeMode
+}
+
+func TestBuildHealingAction_SafeMode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	watchdog := &fakeWatchdog{safeMode: true}
+	r := &HealingPolicyReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Watchdog: watchdog}
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1alpha1.HealingPolicySpec{Mode: "automatic"},
+	}
+	ta := TriggeredAction{
+		Trigger: "high-restarts",
+		Resource: &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		},
+		Action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+	}
+
+	action, err := r.buildHealingAction(context.Background(), policy, ta)
+	require.NoError(t, err)
+	assert.True(t, action.Spec.DryRun)
+	assert.Equal(t, "true", action.Annotations[AnnotationSafeMode])
+
+	watchdog.safeMode = false
+	action, err = r.buildHealingAction(context.Background(), policy, ta)
+	require.NoError(t, err)
+	assert.False(t, action.Spec.DryRun)
+	assert.NotContains(t, action.Annotations, AnnotationSafeMode)
+}
+
+func TestHealingActionReconciler_SafeModeHold(t *testing.T) {
+	tests := []struct {
+		name          string
+		dryRun        bool
+		expectedPhase string
+	}{
+		{name: "action is held", expectedPhase: v1alpha1.HealingActionPhasePending},
+		{name: "dry-run action proceeds", dryRun: true, expectedPhase: v1alpha1.HealingActionPhaseApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+			policy := pausedPolicy(false)
+			action := &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-action",
+					Namespace:  "default",
+					Finalizers: []string{FinalizerName},
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef: v1alpha1.PolicyReference{Name: policy.Name, Namespace: policy.Namespace},
+					Action:    v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+					DryRun:    tt.dryRun,
+				},
+				Status: v1alpha1.HealingActionStatus{Phase: v1alpha1.HealingActionPhasePending},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(policy, action).
+				WithStatusSubresource(policy, action).
+				Build()
+
+			watchdog := &fakeWatchdog{safeMode: true}
+			r := &HealingActionReconciler{
+				Client:            fakeClient,
+				Scheme:            scheme,
+				Config:            config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{},
+				SafetyController:  &MockSafetyController{},
+				Watchdog:          watchdog,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: action.Name, Namespace: action.Namespace}}
+			_, err := r.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"healingaction"}, watchdog.started)
+			assert.Equal(t, []error{nil}, watchdog.errs)
+
+			updated := &v1alpha1.HealingAction{}
+			require.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, updated))
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			if !tt.dryRun {
+				cond := GetCondition(updated.Status.Conditions, v1alpha1.ConditionTypeReady)
+				require.NotNil(t, cond)
+				assert.Equal(t, ReasonSafeMode, cond.Reason)
+			}
+		})
+	}
+}
+
+func TestTrackReconcile(t *testing.T) {
+	// Without a watchdog tracking is a no-op
+	trackReconcile(nil, "healingpolicy")(errors.New("ignored"))
+
+	watchdog := &fakeWatchdog{}
+	done := trackReconcile(watchdog, "healingpolicy")
+	done(errors.New("boom"))
+	assert.Equal(t, []string{"healingpolicy"}, watchdog.started)
+	assert.EqualError(t, watchdog.errs[0], "boom")
+}
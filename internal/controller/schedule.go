@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+)
+
+// nextScheduledRun returns the earliest upcoming run of the policy's
+// schedule triggers
+func nextScheduledRun(policy *v1alpha1.HealingPolicy, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, trigger := range policy.Spec.Triggers {
+		if trigger.Type != "schedule" || trigger.ScheduleTrigger == nil {
+			continue
+		}
+		if run, ok := triggers.NextRun(trigger.ScheduleTrigger, now); ok && (next.IsZero() || run.Before(next)) {
+			next = run
+		}
+	}
+	return next, !next.IsZero()
+}
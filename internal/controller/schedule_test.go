@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestNextScheduledRun(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 17, 0, 0, time.UTC)
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{
+				{Name: "cpu", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{Query: "cpu"}},
+				{Name: "nightly", Type: "schedule", ScheduleTrigger: &v1alpha1.ScheduleTrigger{Schedule: "0 3 * * *"}},
+				{Name: "hourly", Type: "schedule", ScheduleTrigger: &v1alpha1.ScheduleTrigger{Schedule: "@hourly"}},
+			},
+		},
+	}
+
+	next, ok := nextScheduledRun(policy, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), next.UTC())
+
+	_, ok = nextScheduledRun(&v1alpha1.HealingPolicy{}, now)
+	assert.False(t, ok)
+}
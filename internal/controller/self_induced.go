@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// selfInducedActionTypes replace the pods of their target
+var selfInducedActionTypes = map[string]bool{
+	"restart": true,
+	"delete":  true,
+}
+
+// templateHashLabels identify the pod template a pod was created from, in
+// order of preference
+var templateHashLabels = []string{"pod-template-hash", "controller-revision-hash"}
+
+// settlingWindow returns the configured settling window after restarts
+func (r *HealingPolicyReconciler) settlingWindow() time.Duration {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.Safety.SettlingWindow
+}
+
+// excludeSelfInducedChurn tracks the pods replaced by the policy's recent
+// restart and delete actions and returns the metrics to evaluate the
+// triggers on, without those pods and their events, along with the number
+// of pods left out
+func (r *HealingPolicyReconciler) excludeSelfInducedChurn(ctx context.Context, policy *v1alpha1.HealingPolicy, clusterMetrics *types.ClusterMetrics, now time.Time) (*types.ClusterMetrics, int, error) {
+	window := r.settlingWindow()
+	if window <= 0 {
+		policy.Status.SelfInducedChurn = nil
+		return clusterMetrics, 0, nil
+	}
+
+	actions := &v1alpha1.HealingActionList{}
+	err := r.List(ctx, actions, client.InNamespace(policy.Namespace),
+		client.MatchingLabels{LabelPolicyName: policy.Name})
+	if err != nil {
+		// Keep the churn tracked so far
+		actions.Items = nil
+	}
+	trackSelfInducedChurn(policy, actions.Items, clusterMetrics.Pods, window, now)
+	filtered, excluded := filterSelfInducedChurn(clusterMetrics, policy.Status.SelfInducedChurn)
+	return filtered, excluded, err
+}
+
+// trackSelfInducedChurn forgets churn whose settling window ended, starts
+// tracking the policy's restart and delete actions that succeeded within
+// the window and records the replacement pods created since each action
+// started
+func trackSelfInducedChurn(policy *v1alpha1.HealingPolicy, actions []v1alpha1.HealingAction, pods []types.PodMetrics, window time.Duration, now time.Time) {
+	var churn []v1alpha1.SelfInducedChurn
+	tracked := make(map[string]bool)
+	for _, entry := range policy.Status.SelfInducedChurn {
+		if now.Before(entry.Until.Time) {
+			churn = append(churn, entry)
+			tracked[entry.Action] = true
+		}
+	}
+
+	for i := range actions {
+		action := &actions[i]
+		if tracked[action.Name] || !isPolicyAction(action, policy) || action.Spec.DryRun ||
+			!selfInducedActionTypes[action.Spec.Action.Type] ||
+			action.Status.Phase != v1alpha1.HealingActionPhaseSucceeded || action.Status.CompletionTime == nil {
+			continue
+		}
+		until := action.Status.CompletionTime.Add(window)
+		if !now.Before(until) {
+			continue
+		}
+		startedAt := action.Status.CompletionTime
+		if action.Status.StartTime != nil {
+			startedAt = action.Status.StartTime
+		}
+		ref := action.Spec.TargetResource
+		churn = append(churn, v1alpha1.SelfInducedChurn{
+			Action:    action.Name,
+			Target:    ActionTargetKey(ref.Kind, ref.Namespace, ref.Name),
+			StartedAt: *startedAt,
+			Until:     metav1.NewTime(until),
+		})
+	}
+
+	for i := range churn {
+		entry := &churn[i]
+		kind, namespace, name := splitTarget(entry.Target)
+		for j := range pods {
+			pod := &pods[j]
+			if pod.UID == "" || pod.CreationTime.Before(entry.StartedAt.Time) || !podOfTarget(pod, kind, namespace, name) {
+				continue
+			}
+			if !slices.Contains(entry.PodUIDs, pod.UID) {
+				entry.PodUIDs = append(entry.PodUIDs, pod.UID)
+			}
+			if entry.TemplateHash == "" {
+				entry.TemplateHash = podTemplateHash(pod)
+			}
+		}
+	}
+	policy.Status.SelfInducedChurn = churn
+}
+
+// filterSelfInducedChurn returns a copy of the metrics without the
+// self-induced pods and their events, and the number of pods left out.
+// Workload pods created from a replacement template are self-induced too,
+// even before their UID was recorded.
+func filterSelfInducedChurn(clusterMetrics *types.ClusterMetrics, churn []v1alpha1.SelfInducedChurn) (*types.ClusterMetrics, int) {
+	if len(churn) == 0 {
+		return clusterMetrics, 0
+	}
+
+	uids := make(map[string]bool)
+	for _, entry := range churn {
+		for _, uid := range entry.PodUIDs {
+			uids[uid] = true
+		}
+	}
+	selfInduced := func(pod *types.PodMetrics) bool {
+		if pod.UID != "" && uids[pod.UID] {
+			return true
+		}
+		for _, entry := range churn {
+			kind, namespace, name := splitTarget(entry.Target)
+			if kind != "Pod" && entry.TemplateHash != "" && podTemplateHash(pod) == entry.TemplateHash &&
+				podOfTarget(pod, kind, namespace, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var pods []types.PodMetrics
+	excludedPods := make(map[string]bool)
+	for i := range clusterMetrics.Pods {
+		pod := &clusterMetrics.Pods[i]
+		if selfInduced(pod) {
+			excludedPods[ActionTargetKey("Pod", pod.Namespace, pod.Name)] = true
+			continue
+		}
+		pods = append(pods, *pod)
+	}
+	if len(excludedPods) == 0 {
+		return clusterMetrics, 0
+	}
+
+	var events []types.EventMetrics
+	for _, event := range clusterMetrics.Events {
+		if !excludedPods[event.Object] {
+			events = append(events, event)
+		}
+	}
+
+	filtered := *clusterMetrics
+	filtered.Pods = pods
+	filtered.Events = events
+	return &filtered, len(excludedPods)
+}
+
+// podOfTarget reports whether a pod belongs to an action target. The pods
+// replacing a deleted pod share its owner, whose name prefixes the pod's,
+// and Deployments own their pods through ReplicaSets named
+// <deployment>-<hash>.
+func podOfTarget(pod *types.PodMetrics, kind, namespace, name string) bool {
+	if pod.Namespace != namespace {
+		return false
+	}
+	if kind == "Pod" && pod.Name == name {
+		return true
+	}
+	for _, owner := range pod.OwnerReferences {
+		ownerKind, ownerName, ok := strings.Cut(owner, "/")
+		if !ok {
+			continue
+		}
+		switch kind {
+		case "Pod":
+			if strings.HasPrefix(name, ownerName+"-") {
+				return true
+			}
+		case "Deployment":
+			hash, isReplicaSet := strings.CutPrefix(ownerName, name+"-")
+			if ownerKind == "ReplicaSet" && isReplicaSet && hash != "" && !strings.Contains(hash, "-") {
+				return true
+			}
+		default:
+			if ownerKind == kind && ownerName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// podTemplateHash returns the hash of the template a pod was created from
+func podTemplateHash(pod *types.PodMetrics) string {
+	for _, label := range templateHashLabels {
+		if hash := pod.Labels[label]; hash != "" {
+			return hash
+		}
+	}
+	return ""
+}
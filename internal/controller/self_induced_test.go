@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func churnPod(name, uid, replicaSet string, created time.Time, restarts int32) types.PodMetrics {
+	return types.PodMetrics{
+		Name:            name,
+		Namespace:       "apps",
+		UID:             uid,
+		CreationTime:    created,
+		RestartCount:    restarts,
+		Labels:          map[string]string{"pod-template-hash": replicaSet[len("web-"):]},
+		OwnerReferences: []string{"ReplicaSet/" + replicaSet},
+	}
+}
+
+func restartAction(name, kind, target string, started, completed time.Time) *v1alpha1.HealingAction {
+	action := indexedAction(name, "web-policy", kind, target, started)
+	action.Labels = map[string]string{LabelPolicyName: "web-policy"}
+	startedAt, completedAt := metav1.NewTime(started), metav1.NewTime(completed)
+	action.Status.Phase = v1alpha1.HealingActionPhaseSucceeded
+	action.Status.StartTime = &startedAt
+	action.Status.CompletionTime = &completedAt
+	return action
+}
+
+func TestTrackSelfInducedChurn(t *testing.T) {
+	now := time.Now()
+	started := now.Add(-2 * time.Minute)
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"}}
+
+	dryRun := restartAction("dry", "Deployment", "web", started, started)
+	dryRun.Spec.DryRun = true
+	scale := restartAction("scale", "Deployment", "web", started, started)
+	scale.Spec.Action.Type = "scale"
+	failed := restartAction("failed", "Deployment", "web", started, started)
+	failed.Status.Phase = v1alpha1.HealingActionPhaseFailed
+	actions := []v1alpha1.HealingAction{
+		*restartAction("restart", "Deployment", "web", started, started.Add(time.Second)),
+		*restartAction("expired", "Deployment", "web", now.Add(-time.Hour), now.Add(-time.Hour)),
+		*dryRun, *scale, *failed,
+	}
+	pods := []types.PodMetrics{
+		churnPod("web-old-1", "old-1", "web-5d8f", now.Add(-time.Hour), 0),
+		churnPod("web-new-1", "new-1", "web-7c9b", started.Add(10*time.Second), 3),
+		churnPod("api-new-1", "api-1", "api-7c9b", started.Add(10*time.Second), 3),
+	}
+
+	trackSelfInducedChurn(policy, actions, pods, 5*time.Minute, now)
+	require.Len(t, policy.Status.SelfInducedChurn, 1)
+	churn := policy.Status.SelfInducedChurn[0]
+	assert.Equal(t, "restart", churn.Action)
+	assert.Equal(t, "Deployment/apps/web", churn.Target)
+	assert.Equal(t, "7c9b", churn.TemplateHash)
+	assert.Equal(t, []string{"new-1"}, churn.PodUIDs)
+	assert.Equal(t, started.Add(time.Second+5*time.Minute).Unix(), churn.Until.Unix())
+
+	// New replacement pods are added while the window lasts
+	pods = append(pods, churnPod("web-new-2", "new-2", "web-7c9b", started.Add(20*time.Second), 0))
+	trackSelfInducedChurn(policy, actions, pods, 5*time.Minute, now.Add(time.Minute))
+	require.Len(t, policy.Status.SelfInducedChurn, 1)
+	assert.Equal(t, []string{"new-1", "new-2"}, policy.Status.SelfInducedChurn[0].PodUIDs)
+
+	// and forgotten once it ended
+	trackSelfInducedChurn(policy, actions, pods, 5*time.Minute, now.Add(10*time.Minute))
+	assert.Empty(t, policy.Status.SelfInducedChurn)
+}
+
+func TestFilterSelfInducedChurn(t *testing.T) {
+	now := time.Now()
+	clusterMetrics := &types.ClusterMetrics{
+		Pods: []types.PodMetrics{
+			churnPod("web-old-1", "old-1", "web-5d8f", now.Add(-time.Hour), 1),
+			churnPod("web-new-1", "new-1", "web-7c9b", now, 3),
+			churnPod("web-new-2", "new-2", "web-7c9b", now, 2),
+		},
+		Events: []types.EventMetrics{
+			{Reason: "BackOff", Object: "Pod/apps/web-new-1"},
+			{Reason: "BackOff", Object: "Pod/apps/web-old-1"},
+		},
+	}
+
+	filtered, excluded := filterSelfInducedChurn(clusterMetrics, nil)
+	assert.Same(t, clusterMetrics, filtered)
+	assert.Zero(t, excluded)
+
+	filtered, excluded = filterSelfInducedChurn(clusterMetrics, []v1alpha1.SelfInducedChurn{{
+		Action:       "restart",
+		Target:       "Deployment/apps/web",
+		TemplateHash: "7c9b",
+		PodUIDs:      []string{"new-1"},
+	}})
+	assert.Equal(t, 2, excluded, "pods of the replacement template are excluded before their UID is recorded")
+	require.Len(t, filtered.Pods, 1)
+	assert.Equal(t, "web-old-1", filtered.Pods[0].Name)
+	require.Len(t, filtered.Events, 1)
+	assert.Equal(t, "Pod/apps/web-old-1", filtered.Events[0].Object)
+	assert.Len(t, clusterMetrics.Pods, 3, "the collected metrics are left alone")
+
+	// Siblings of a deleted pod share its template hash but are not churn
+	_, excluded = filterSelfInducedChurn(clusterMetrics, []v1alpha1.SelfInducedChurn{{
+		Action:       "delete",
+		Target:       "Pod/apps/web-7c9b-x2k4q",
+		TemplateHash: "7c9b",
+		PodUIDs:      []string{"new-1"},
+	}})
+	assert.Equal(t, 1, excluded)
+}
+
+func TestPodOfTarget(t *testing.T) {
+	pod := &types.PodMetrics{Name: "web-7c9b-abcde", Namespace: "apps", OwnerReferences: []string{"ReplicaSet/web-7c9b"}}
+	statefulPod := &types.PodMetrics{Name: "db-0", Namespace: "apps", OwnerReferences: []string{"StatefulSet/db"}}
+
+	tests := []struct {
+		name   string
+		pod    *types.PodMetrics
+		target string
+		want   bool
+	}{
+		{name: "deployment", pod: pod, target: "Deployment/apps/web", want: true},
+		{name: "other deployment", pod: pod, target: "Deployment/apps/we", want: false},
+		{name: "deployment with a dashed name", pod: pod, target: "Deployment/apps/web-7c9b", want: false},
+		{name: "other namespace", pod: pod, target: "Deployment/prod/web", want: false},
+		{name: "replacement of a deleted pod", pod: pod, target: "Pod/apps/web-7c9b-x2k4q", want: true},
+		{name: "recreated stateful pod", pod: statefulPod, target: "Pod/apps/db-0", want: true},
+		{name: "statefulset", pod: statefulPod, target: "StatefulSet/apps/db", want: true},
+		{name: "other statefulset", pod: statefulPod, target: "StatefulSet/apps/cache", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, namespace, name := splitTarget(tt.target)
+			assert.Equal(t, tt.want, podOfTarget(tt.pod, kind, namespace, name))
+		})
+	}
+}
+
+func TestExcludeSelfInducedChurn(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	now := time.Now()
+	started := now.Add(-time.Minute)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(restartAction("restart", "Deployment", "web", started, started)).
+		Build()
+	clusterMetrics := &types.ClusterMetrics{Pods: []types.PodMetrics{
+		churnPod("web-old-1", "old-1", "web-5d8f", now.Add(-time.Hour), 0),
+		churnPod("web-new-1", "new-1", "web-7c9b", now, 5),
+	}}
+
+	t.Run("disabled", func(t *testing.T) {
+		r := &HealingPolicyReconciler{Client: c, Config: &config.Config{}}
+		policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"}}
+		filtered, excluded, err := r.excludeSelfInducedChurn(context.Background(), policy, clusterMetrics, now)
+		require.NoError(t, err)
+		assert.Same(t, clusterMetrics, filtered)
+		assert.Zero(t, excluded)
+		assert.Empty(t, policy.Status.SelfInducedChurn)
+	})
+
+	t.Run("settling", func(t *testing.T) {
+		r := &HealingPolicyReconciler{Client: c, Config: &config.Config{Safety: config.SafetyConfig{SettlingWindow: 5 * time.Minute}}}
+		policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default"}}
+		filtered, excluded, err := r.excludeSelfInducedChurn(context.Background(), policy, clusterMetrics, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, excluded)
+		require.Len(t, filtered.Pods, 1)
+		assert.Equal(t, "web-old-1", filtered.Pods[0].Name)
+		require.Len(t, policy.Status.SelfInducedChurn, 1)
+		assert.Equal(t, []string{"new-1"}, policy.Status.SelfInducedChurn[0].PodUIDs)
+	})
+}
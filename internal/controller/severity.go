@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// Trigger severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// LabelSeverity carries the severity of the trigger that caused an action
+const LabelSeverity = "kubeskippy.io/severity"
+
+// triggerSeverity resolves a trigger's severity: its own, else the first
+// matching entry of the policy's severity mapping, else warning
+func triggerSeverity(policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger) string {
+	if trigger.Severity != "" {
+		return trigger.Severity
+	}
+
+	query := ""
+	if trigger.MetricTrigger != nil {
+		query = trigger.MetricTrigger.Query
+	}
+	for _, mapping := range policy.Spec.SeverityMapping {
+		if mapping.TriggerType != "" && mapping.TriggerType != trigger.Type {
+			continue
+		}
+		if mapping.QueryPattern != "" {
+			re, err := regexp.Compile(mapping.QueryPattern)
+			if err != nil || query == "" || !re.MatchString(query) {
+				continue
+			}
+		}
+		return mapping.Severity
+	}
+	return SeverityWarning
+}
+
+// severityRule returns the policy's safety rule for a severity, if any
+func severityRule(policy *v1alpha1.HealingPolicy, severity string) *v1alpha1.SeverityRule {
+	for i := range policy.Spec.SafetyRules.SeverityRules {
+		if policy.Spec.SafetyRules.SeverityRules[i].Severity == severity {
+			return &policy.Spec.SafetyRules.SeverityRules[i]
+		}
+	}
+	return nil
+}
+
+// applySeverity records the severity of the trigger on a triggered action
+// and escalates its priority to the severity's minimum
+func applySeverity(policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger, ta *TriggeredAction) {
+	ta.Severity = triggerSeverity(policy, trigger)
+	if rule := severityRule(policy, ta.Severity); rule != nil && ta.Action.Priority < rule.MinPriority {
+		ta.Action.Priority = rule.MinPriority
+	}
+}
+
+// checkSeverityRule applies the policy's rule for the action's severity to
+// the action about to be created
+func checkSeverityRule(policy *v1alpha1.HealingPolicy, severity string, action *v1alpha1.HealingAction) error {
+	rule := severityRule(policy, severity)
+	if rule == nil {
+		return nil
+	}
+
+	if len(rule.AllowedActions) > 0 {
+		allowed := false
+		for _, actionType := range rule.AllowedActions {
+			if actionType == action.Spec.Action.Type {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%s actions are not allowed for %s triggers", action.Spec.Action.Type, severity)
+		}
+	}
+
+	if rule.RequireApproval && !action.Spec.DryRun {
+		action.Spec.ApprovalRequired = true
+		action.Status.Approval = &v1alpha1.ApprovalStatus{Required: true}
+	}
+	return nil
+}
+
+// aiIssueSeverity maps a trigger severity onto the scale of AI issues
+func aiIssueSeverity(severity string) string {
+	switch severity {
+	case SeverityInfo:
+		return "low"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "medium"
+	}
+}
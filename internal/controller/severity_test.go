@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestTriggerSeverity(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			SeverityMapping: []v1alpha1.SeverityMapping{
+				{TriggerType: "metric", QueryPattern: `oom|memory`, Severity: SeverityCritical},
+				{TriggerType: "event", Severity: SeverityInfo},
+				{QueryPattern: `[`, Severity: SeverityInfo},
+			},
+		},
+	}
+	metric := func(query string) *v1alpha1.MetricTrigger {
+		return &v1alpha1.MetricTrigger{Query: query}
+	}
+
+	tests := []struct {
+		name     string
+		trigger  v1alpha1.HealingTrigger
+		expected string
+	}{
+		{"explicit severity wins", v1alpha1.HealingTrigger{Type: "metric", MetricTrigger: metric("memory_usage"), Severity: SeverityInfo}, SeverityInfo},
+		{"query pattern", v1alpha1.HealingTrigger{Type: "metric", MetricTrigger: metric("container_memory_usage")}, SeverityCritical},
+		{"trigger type", v1alpha1.HealingTrigger{Type: "event"}, SeverityInfo},
+		{"unmatched query", v1alpha1.HealingTrigger{Type: "metric", MetricTrigger: metric("cpu_usage")}, SeverityWarning},
+		{"unmatched type", v1alpha1.HealingTrigger{Type: "condition"}, SeverityWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, triggerSeverity(policy, &tt.trigger))
+		})
+	}
+}
+
+func TestBuildHealingAction_Severity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	r := &HealingPolicyReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Mode: "automatic",
+			SafetyRules: v1alpha1.SafetyRules{
+				SeverityRules: []v1alpha1.SeverityRule{
+					{Severity: SeverityCritical, RequireApproval: true, MinPriority: 90},
+					{Severity: SeverityInfo, AllowedActions: []string{"restart"}},
+				},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+	}
+
+	tests := []struct {
+		name     string
+		severity string
+		action   v1alpha1.HealingActionTemplate
+		priority int32
+		approval bool
+		err      string
+	}{
+		{name: "warning has no rule", severity: SeverityWarning, action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart", Priority: 10}, priority: 10},
+		{name: "critical escalates and requires approval", severity: SeverityCritical, action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart", Priority: 10}, priority: 90, approval: true},
+		{name: "info restricts action types", severity: SeverityInfo, action: v1alpha1.HealingActionTemplate{Name: "delete", Type: "delete", Priority: 10}, priority: 10, err: "delete actions are not allowed for info triggers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := TriggeredAction{Trigger: "high-memory", Resource: pod, Action: tt.action}
+			applySeverity(policy, &v1alpha1.HealingTrigger{Name: "high-memory", Type: "metric", Severity: tt.severity}, &ta)
+			assert.Equal(t, tt.priority, ta.Action.Priority)
+
+			action, err := r.buildHealingAction(context.Background(), policy, ta)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.severity, action.Labels[LabelSeverity])
+			assert.Equal(t, tt.severity, action.Status.Severity)
+			assert.Equal(t, tt.approval, action.Spec.ApprovalRequired)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	statusPatchesTotal      *prometheus.CounterVec
+	reconcileConflictsTotal *prometheus.CounterVec
+)
+
+// SetStatusPatchMetrics sets the status patch and conflict metrics from main.go
+func SetStatusPatchMetrics(patches, conflicts *prometheus.CounterVec) {
+	statusPatchesTotal = patches
+	reconcileConflictsTotal = conflicts
+}
+
+// statusPatcher writes the status changes of a reconcile as merge patches
+// that only carry the fields changed since the object was read or last
+// patched. Unlike a status update, a patch does not conflict with writes
+// made since the object was read, and is skipped when nothing changed.
+type statusPatcher struct {
+	client     client.Client
+	controller string
+	base       client.Object
+}
+
+// newStatusPatcher starts tracking the status changes of obj as read
+func newStatusPatcher(c client.Client, controller string, obj client.Object) *statusPatcher {
+	return &statusPatcher{
+		client:     c,
+		controller: controller,
+		base:       obj.DeepCopyObject().(client.Object),
+	}
+}
+
+// Patch writes the status changes of obj, if there are any
+func (p *statusPatcher) Patch(ctx context.Context, obj client.Object) error {
+	// The patch must not lock the resource version, which may have been
+	// moved on by other patches of this reconcile
+	base := p.base.DeepCopyObject().(client.Object)
+	base.SetResourceVersion(obj.GetResourceVersion())
+	data, err := client.MergeFrom(base).Data(obj)
+	if err != nil {
+		return err
+	}
+
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return err
+	}
+	status, changed := changes["status"]
+	if !changed {
+		recordStatusPatch(p.controller, "unchanged")
+		return nil
+	}
+	data, err = json.Marshal(map[string]json.RawMessage{"status": status})
+	if err != nil {
+		return err
+	}
+
+	if err := p.client.Status().Patch(ctx, obj, client.RawPatch(k8stypes.MergePatchType, data)); err != nil {
+		result := "failed"
+		if errors.IsConflict(err) {
+			result = "conflict"
+		}
+		recordStatusPatch(p.controller, result)
+		return err
+	}
+	recordStatusPatch(p.controller, "patched")
+	p.base = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func recordStatusPatch(controller, result string) {
+	if statusPatchesTotal != nil {
+		statusPatchesTotal.WithLabelValues(controller, result).Inc()
+	}
+}
+
+// recordReconcileConflict counts a reconcile that failed on a conflicting
+// write, which the controller retries
+func recordReconcileConflict(controller string, err error) {
+	if reconcileConflictsTotal != nil && errors.IsConflict(err) {
+		reconcileConflictsTotal.WithLabelValues(controller).Inc()
+	}
+}
+
+type statusPatcherKey struct{}
+
+// withStatusPatcher makes the reconcile's status patcher available to the
+// functions it calls
+func withStatusPatcher(ctx context.Context, p *statusPatcher) context.Context {
+	return context.WithValue(ctx, statusPatcherKey{}, p)
+}
+
+// patchStatus writes the status changes of obj with the reconcile's status
+// patcher. Objects other than the reconciled one, such as preempted actions,
+// are written with a status update.
+func patchStatus(ctx context.Context, c client.Client, obj client.Object) error {
+	if p, ok := ctx.Value(statusPatcherKey{}).(*statusPatcher); ok &&
+		client.ObjectKeyFromObject(p.base) == client.ObjectKeyFromObject(obj) {
+		return p.Patch(ctx, obj)
+	}
+	return c.Status().Update(ctx, obj)
+}
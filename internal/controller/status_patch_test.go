@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// useStatusPatchMetrics installs fresh status patch metrics for a test
+func useStatusPatchMetrics(t *testing.T) (patches, conflicts *prometheus.CounterVec) {
+	patches = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_status_patches_total"},
+		[]string{"controller", "result"})
+	conflicts = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_reconcile_conflicts_total"},
+		[]string{"controller"})
+	previousPatches, previousConflicts := statusPatchesTotal, reconcileConflictsTotal
+	SetStatusPatchMetrics(patches, conflicts)
+	t.Cleanup(func() { SetStatusPatchMetrics(previousPatches, previousConflicts) })
+	return patches, conflicts
+}
+
+// countingStatusWrites returns a fake client counting status patches and
+// updates
+func countingStatusWrites(t *testing.T, objs ...client.Object) (client.Client, *int, *int) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	var patches, updates int
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				updates++
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	return c, &patches, &updates
+}
+
+func TestStatusPatcher(t *testing.T) {
+	patches, _ := useStatusPatchMetrics(t)
+	policy := pausedPolicy(false)
+	c, sent, _ := countingStatusWrites(t, policy)
+	ctx := context.Background()
+
+	read := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(policy), read))
+	patcher := newStatusPatcher(c, "healingpolicy", read)
+
+	// Someone else writes the policy after it was read
+	other := read.DeepCopy()
+	other.Status.ActionsTaken = 3
+	require.NoError(t, c.Status().Update(ctx, other))
+
+	// Unchanged status is not written, even when metadata changed
+	read.Labels = map[string]string{"team": "shop"}
+	require.NoError(t, patcher.Patch(ctx, read))
+	assert.Equal(t, 0, *sent)
+
+	// Only the changed fields are written, without conflicting
+	read.Status.ObservedGeneration = 4
+	SetCondition(&read.Status.Conditions, v1alpha1.ConditionTypeReady, metav1.ConditionTrue, ReasonPolicyUpdated, "ok")
+	require.NoError(t, patcher.Patch(ctx, read))
+	assert.Equal(t, 1, *sent)
+
+	stored := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(policy), stored))
+	assert.Equal(t, int64(4), stored.Status.ObservedGeneration)
+	assert.Equal(t, int32(3), stored.Status.ActionsTaken, "concurrent writes are kept")
+	assert.Empty(t, stored.Labels, "status patches leave metadata alone")
+
+	// Later patches only carry what changed since the last one
+	require.NoError(t, patcher.Patch(ctx, read))
+	assert.Equal(t, 1, *sent)
+	read.Status.ObservedGeneration = 5
+	require.NoError(t, patcher.Patch(ctx, read))
+	assert.Equal(t, 2, *sent)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(patches.WithLabelValues("healingpolicy", "patched")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(patches.WithLabelValues("healingpolicy", "unchanged")))
+}
+
+func TestPatchStatus(t *testing.T) {
+	useStatusPatchMetrics(t)
+	action := &v1alpha1.HealingAction{ObjectMeta: metav1.ObjectMeta{Name: "restart", Namespace: "default"}}
+	victim := &v1alpha1.HealingAction{ObjectMeta: metav1.ObjectMeta{Name: "scale", Namespace: "default"}}
+	c, patches, updates := countingStatusWrites(t, action, victim)
+	ctx := context.Background()
+
+	// Outside of a reconcile the status is updated
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(action), action))
+	action.Status.Phase = v1alpha1.HealingActionPhasePending
+	require.NoError(t, patchStatus(ctx, c, action))
+	assert.Equal(t, 0, *patches)
+	assert.Equal(t, 1, *updates)
+
+	// The reconciled object is patched, others are updated
+	ctx = withStatusPatcher(ctx, newStatusPatcher(c, "healingaction", action))
+	action.Status.Phase = v1alpha1.HealingActionPhaseApproved
+	require.NoError(t, patchStatus(ctx, c, action))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(victim), victim))
+	victim.Status.Phase = v1alpha1.HealingActionPhaseCancelled
+	require.NoError(t, patchStatus(ctx, c, victim))
+	assert.Equal(t, 1, *patches)
+	assert.Equal(t, 2, *updates)
+}
+
+func TestHealingPolicyReconciler_SingleStatusPatch(t *testing.T) {
+	patches, _ := useStatusPatchMetrics(t)
+	policy := pausedPolicy(false)
+	policy.Generation = 2
+	c, sent, updates := countingStatusWrites(t, policy)
+	r := &HealingPolicyReconciler{
+		Client: c,
+		Scheme: c.Scheme(),
+		Config: config.NewDefaultConfig(),
+		MetricsCollector: &MockMetricsCollector{
+			CollectMetricsFunc: func(ctx context.Context, policy *v1alpha1.HealingPolicy) (*ktypes.ClusterMetrics, error) {
+				return &ktypes.ClusterMetrics{}, nil
+			},
+		},
+		SafetyController: &MockSafetyController{},
+	}
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(policy)}
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	// The observed generation, evaluation and conditions are one write
+	assert.Equal(t, 1, *sent)
+	assert.Equal(t, 0, *updates)
+	assert.Equal(t, 1.0, testutil.ToFloat64(patches.WithLabelValues("healingpolicy", "patched")))
+	stored := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(context.Background(), req.NamespacedName, stored))
+	assert.Equal(t, stored.Generation, stored.Status.ObservedGeneration)
+	assert.NotNil(t, GetCondition(stored.Status.Conditions, v1alpha1.ConditionTypeReady))
+}
+
+func TestRecordReconcileConflict(t *testing.T) {
+	_, conflicts := useStatusPatchMetrics(t)
+	conflict := apierrors.NewConflict(schema.GroupResource{Group: "kubeskippy.io", Resource: "healingactions"}, "restart", nil)
+
+	done := trackReconcile(nil, "healingaction")
+	done(conflict)
+	done(nil)
+	done(apierrors.NewNotFound(schema.GroupResource{}, "restart"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(conflicts.WithLabelValues("healingaction")))
+}
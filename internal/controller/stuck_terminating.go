@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+)
+
+// AnnotationForceDelete marks finalizer actions escalated to removing every
+// finalizer of their target
+const AnnotationForceDelete = "kubeskippy.io/force-delete"
+
+// evaluateStuckTerminating fires when resources matched by the policy are
+// stuck terminating on their finalizers
+func (r *HealingPolicyReconciler) evaluateStuckTerminating(ctx context.Context, policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger, now time.Time) (bool, string, error) {
+	resources, err := r.findMatchingResources(ctx, policy)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to find matching resources: %w", err)
+	}
+	objects := make([]metav1.Object, len(resources))
+	for i, resource := range resources {
+		objects[i] = resource
+	}
+	triggered, reason := triggers.EvaluateStuckTerminating(trigger.StuckTerminatingTrigger, objects, now)
+	return triggered, reason, nil
+}
+
+// filterStuckTargets narrows the targets of a stuck terminating trigger to
+// the stuck resources. Other triggers are returned unchanged.
+func filterStuckTargets(trigger *v1alpha1.HealingTrigger, resources []client.Object, now time.Time) []client.Object {
+	if trigger.Type != "stuckTerminating" {
+		return resources
+	}
+
+	threshold := triggers.StuckTerminatingThreshold(trigger.StuckTerminatingTrigger)
+	filtered := make([]client.Object, 0, len(resources))
+	for _, resource := range resources {
+		if _, ok := triggers.IsStuckTerminating(resource, threshold, now); ok {
+			filtered = append(filtered, resource)
+		}
+	}
+	return filtered
+}
+
+// isForceDelete reports whether an action may remove finalizers that are
+// not known to be safe
+func isForceDelete(action *v1alpha1.HealingActionTemplate) bool {
+	return action.Type == "finalizer" && action.FinalizerAction != nil && action.FinalizerAction.ForceDelete
+}
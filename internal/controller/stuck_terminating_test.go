@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func stuckPod(name string, deletedAgo time.Duration) *corev1.Pod {
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "apps",
+			Labels:     map[string]string{"app": "web"},
+			Finalizers: []string{"example.com/cleanup"},
+		},
+	}
+	if deletedAgo > 0 {
+		pod.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-deletedAgo)}
+	}
+	return pod
+}
+
+func TestStuckTerminatingTrigger(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	stuck, recent, running := stuckPod("stuck", time.Hour), stuckPod("recent", time.Minute), stuckPod("running", 0)
+	r := &HealingPolicyReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(stuck, recent, running).Build(),
+		Scheme: scheme,
+	}
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "stuck-pods", Namespace: "apps"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Selector: v1alpha1.ResourceSelector{
+				Namespaces:    []string{"apps"},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Resources:     []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+			},
+		},
+	}
+	trigger := &v1alpha1.HealingTrigger{
+		Name:                    "stuck",
+		Type:                    "stuckTerminating",
+		StuckTerminatingTrigger: &v1alpha1.StuckTerminatingTrigger{Threshold: metav1.Duration{Duration: 10 * time.Minute}},
+	}
+
+	result, err := r.evaluateTrigger(context.Background(), policy, trigger, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Triggered)
+	assert.Contains(t, result.Reason, "1 resources stuck terminating: apps/stuck")
+	assert.Contains(t, result.Reason, "blocked by example.com/cleanup")
+
+	// Only the stuck resource is targeted
+	targets := filterStuckTargets(trigger, []client.Object{stuck, recent, running}, time.Now())
+	require.Len(t, targets, 1)
+	assert.Equal(t, "stuck", targets[0].GetName())
+}
+
+func TestBuildHealingAction_ForceDeleteRequiresApproval(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	r := &HealingPolicyReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "stuck-pods", Namespace: "apps"},
+		Spec:       v1alpha1.HealingPolicySpec{Mode: "automatic"},
+	}
+
+	tests := []struct {
+		name     string
+		config   *v1alpha1.FinalizerAction
+		approval bool
+	}{
+		{name: "safe finalizers", config: &v1alpha1.FinalizerAction{RemoveFinalizers: []string{"example.com/cleanup"}}},
+		{name: "force delete", config: &v1alpha1.FinalizerAction{ForceDelete: true}, approval: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := r.buildHealingAction(context.Background(), policy, TriggeredAction{
+				Trigger:  "stuck",
+				Resource: stuckPod("stuck", time.Hour),
+				Action:   v1alpha1.HealingActionTemplate{Name: "release", Type: "finalizer", FinalizerAction: tt.config},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.approval, action.Spec.ApprovalRequired)
+			if tt.approval {
+				assert.Equal(t, "true", action.Annotations[AnnotationForceDelete])
+				require.NotNil(t, action.Status.Approval)
+				assert.True(t, action.Status.Approval.Required)
+			}
+		})
+	}
+}
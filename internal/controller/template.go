@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// AnnotationTemplatedFields lists the action fields rendered from templates
+const AnnotationTemplatedFields = "kubeskippy.io/templated-fields"
+
+// TemplateContext is the data available to action parameter templates
+type TemplateContext struct {
+	// Trigger is the name of the trigger that fired
+	Trigger string
+	// Reason is the trigger evaluation reason
+	Reason string
+	// Value is the observed value of a metric trigger
+	Value float64
+	// Threshold is the threshold of a metric trigger
+	Threshold float64
+	// Target is the resource the action applies to
+	Target TemplateTarget
+	// CurrentReplicas is the target's spec.replicas, if any
+	CurrentReplicas int32
+}
+
+// TemplateTarget describes the action target in a TemplateContext
+type TemplateTarget struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Labels    map[string]string
+}
+
+// NewTemplateContext builds the template context for a triggered action
+func NewTemplateContext(trigger *v1alpha1.HealingTrigger, result types.TriggerResult, target client.Object) TemplateContext {
+	tctx := TemplateContext{
+		Reason: result.Reason,
+		Value:  result.Value,
+		Target: TemplateTarget{
+			Kind:      target.GetObjectKind().GroupVersionKind().Kind,
+			Name:      target.GetName(),
+			Namespace: target.GetNamespace(),
+			Labels:    target.GetLabels(),
+		},
+		CurrentReplicas: currentReplicas(target),
+	}
+
+	if trigger != nil {
+		tctx.Trigger = trigger.Name
+		if trigger.MetricTrigger != nil {
+			tctx.Threshold = trigger.MetricTrigger.Threshold
+		}
+	}
+
+	return tctx
+}
+
+// RenderActionTemplate returns a copy of the action template with its
+// templated parameters evaluated against the context, along with the names
+// of the rendered fields
+func RenderActionTemplate(actionTemplate *v1alpha1.HealingActionTemplate, tctx TemplateContext) (*v1alpha1.HealingActionTemplate, []string, error) {
+	rendered := actionTemplate.DeepCopy()
+	var fields []string
+
+	render := func(field, text string) (string, error) {
+		if !strings.Contains(text, "{{") {
+			return text, nil
+		}
+		out, err := renderTemplate(field, text, tctx)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, field)
+		return out, nil
+	}
+
+	var err error
+	if rendered.Description, err = render("description", rendered.Description); err != nil {
+		return nil, nil, err
+	}
+
+	if scale := rendered.ScaleAction; scale != nil && scale.ReplicasTemplate != "" {
+		out, err := renderTemplate("scaleAction.replicasTemplate", scale.ReplicasTemplate, tctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		replicas, err := strconv.ParseFloat(strings.TrimSpace(out), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scaleAction.replicasTemplate rendered %q, expected a number", out)
+		}
+		if replicas != math.Trunc(replicas) {
+			return nil, nil, fmt.Errorf("scaleAction.replicasTemplate rendered %v, expected a whole number of replicas", replicas)
+		}
+		if replicas < 0 || replicas > math.MaxInt32 {
+			return nil, nil, fmt.Errorf("scaleAction.replicasTemplate rendered %v, out of range", replicas)
+		}
+		if replicas == 0 && !scale.AllowScaleToZero {
+			return nil, nil, fmt.Errorf("scaleAction.replicasTemplate rendered 0 replicas, set allowScaleToZero to scale to zero")
+		}
+		scale.Replicas = int32(replicas)
+		fields = append(fields, "scaleAction.replicas")
+	}
+
+	if patch := rendered.PatchAction; patch != nil {
+		if patch.Patch, err = render("patchAction.patch", patch.Patch); err != nil {
+			return nil, nil, err
+		}
+		for i := range patch.Patches {
+			field := fmt.Sprintf("patchAction.patches[%d].value", i)
+			if patch.Patches[i].Value, err = render(field, patch.Patches[i].Value); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return rendered, fields, nil
+}
+
+// renderTemplate evaluates a single template string
+func renderTemplate(name, text string, tctx TemplateContext) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, tctx); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// currentReplicas reads spec.replicas from the target, returning 0 if unset
+func currentReplicas(target client.Object) int32 {
+	var content map[string]interface{}
+	if u, ok := target.(*unstructured.Unstructured); ok {
+		content = u.Object
+	} else {
+		var err error
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(target)
+		if err != nil {
+			return 0
+		}
+	}
+
+	replicas, found, err := unstructured.NestedInt64(content, "spec", "replicas")
+	if err != nil || !found {
+		return 0
+	}
+	return int32(replicas)
+}
+
+// templateFuncs is a small sprig-like function set for action templates.
+// Functions fail on values that are not numbers rather than treating them
+// as zero, so a mistyped field aborts rendering instead of scaling to zero.
+var templateFuncs = template.FuncMap{
+	"add": func(a, b interface{}) (float64, error) {
+		return templateBinary(a, b, func(x, y float64) float64 { return x + y })
+	},
+	"sub": func(a, b interface{}) (float64, error) {
+		return templateBinary(a, b, func(x, y float64) float64 { return x - y })
+	},
+	"mul": func(a, b interface{}) (float64, error) {
+		return templateBinary(a, b, func(x, y float64) float64 { return x * y })
+	},
+	"div":   templateDiv,
+	"min":   func(a, b interface{}) (float64, error) { return templateBinary(a, b, math.Min) },
+	"max":   func(a, b interface{}) (float64, error) { return templateBinary(a, b, math.Max) },
+	"ceil":  func(a interface{}) (int64, error) { return templateUnary(a, math.Ceil) },
+	"floor": func(a interface{}) (int64, error) { return templateUnary(a, math.Floor) },
+	"round": func(a interface{}) (int64, error) { return templateUnary(a, math.Round) },
+	"int":   func(a interface{}) (int64, error) { return templateUnary(a, math.Trunc) },
+	"float": toFloat,
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || fmt.Sprint(value) == "" || fmt.Sprint(value) == "0" {
+			return def
+		}
+		return value
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"quote": strconv.Quote,
+}
+
+// templateBinary applies op to a and b converted to numbers
+func templateBinary(a, b interface{}, op func(x, y float64) float64) (float64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toFloat(b)
+	if err != nil {
+		return 0, err
+	}
+	return op(x, y), nil
+}
+
+// templateUnary applies op to a converted to a number, as an integer
+func templateUnary(a interface{}, op func(x float64) float64) (int64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+	return int64(op(x)), nil
+}
+
+// templateDiv divides a by b, failing on division by zero
+func templateDiv(a, b interface{}) (float64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+	divisor, err := toFloat(b)
+	if err != nil {
+		return 0, err
+	}
+	if divisor == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return x / divisor, nil
+}
+
+// toFloat converts numeric and numeric string values to float64, failing
+// on anything else
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", n)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%v (%T) is not a number", v, v)
+	}
+}
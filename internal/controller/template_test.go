@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestNewTemplateContext(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps", Labels: map[string]string{"app": "web"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr(int32(4))},
+	}
+	trigger := &v1alpha1.HealingTrigger{
+		Name: "high-cpu",
+		Type: "metric",
+		MetricTrigger: &v1alpha1.MetricTrigger{
+			Query:     "cpu_usage",
+			Threshold: 80,
+			Operator:  ">",
+		},
+	}
+	result := types.TriggerResult{Triggered: true, Reason: "cpu high", Value: 92.5, Observed: true}
+
+	tctx := NewTemplateContext(trigger, result, deployment)
+
+	assert.Equal(t, "high-cpu", tctx.Trigger)
+	assert.Equal(t, "cpu high", tctx.Reason)
+	assert.Equal(t, 92.5, tctx.Value)
+	assert.Equal(t, 80.0, tctx.Threshold)
+	assert.Equal(t, int32(4), tctx.CurrentReplicas)
+	assert.Equal(t, "Deployment", tctx.Target.Kind)
+	assert.Equal(t, "web", tctx.Target.Labels["app"])
+}
+
+func TestRenderActionTemplate(t *testing.T) {
+	tctx := TemplateContext{
+		Trigger:         "high-cpu",
+		Value:           92.5,
+		Threshold:       80,
+		CurrentReplicas: 3,
+		Target:          TemplateTarget{Kind: "Deployment", Name: "web", Namespace: "apps"},
+	}
+
+	tests := []struct {
+		name           string
+		action         v1alpha1.HealingActionTemplate
+		expectedFields []string
+		expectError    bool
+		verify         func(t *testing.T, action *v1alpha1.HealingActionTemplate)
+	}{
+		{
+			name: "no templates",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				Description: "scale up",
+				ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: 1},
+			},
+			verify: func(t *testing.T, action *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, int32(1), action.ScaleAction.Replicas)
+			},
+		},
+		{
+			name: "replicas from current replicas",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "scale",
+				Type: "scale",
+				ScaleAction: &v1alpha1.ScaleAction{
+					Direction:        "absolute",
+					ReplicasTemplate: "{{ mul .CurrentReplicas 1.5 | ceil }}",
+				},
+			},
+			expectedFields: []string{"scaleAction.replicas"},
+			verify: func(t *testing.T, action *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, int32(5), action.ScaleAction.Replicas)
+			},
+		},
+		{
+			name: "patch value from metric",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "patch",
+				Type:        "patch",
+				Description: "{{ .Trigger }} on {{ .Target.Name }}",
+				PatchAction: &v1alpha1.PatchAction{
+					Type:  "merge",
+					Patch: `{"metadata":{"annotations":{"observed":{{ printf "%.1f" .Value | quote }}}}}`,
+					Patches: []v1alpha1.PatchOperation{
+						{Path: []string{"spec", "replicas"}, Value: "{{ add .CurrentReplicas 1 }}"},
+						{Path: []string{"spec", "paused"}, Value: "false"},
+					},
+				},
+			},
+			expectedFields: []string{"description", "patchAction.patch", "patchAction.patches[0].value"},
+			verify: func(t *testing.T, action *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, "high-cpu on web", action.Description)
+				assert.Equal(t, `{"metadata":{"annotations":{"observed":"92.5"}}}`, action.PatchAction.Patch)
+				assert.Equal(t, "4", action.PatchAction.Patches[0].Value)
+				assert.Equal(t, "false", action.PatchAction.Patches[1].Value)
+			},
+		},
+		{
+			name: "non-numeric replicas",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{ReplicasTemplate: "{{ .Target.Name }}"},
+			},
+			expectError: true,
+		},
+		{
+			name: "division by zero",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{ReplicasTemplate: "{{ div .Value 0 }}"},
+			},
+			expectError: true,
+		},
+		{
+			name: "non-numeric operand",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{ReplicasTemplate: "{{ add .Target.Name 1 | ceil }}"},
+			},
+			expectError: true,
+		},
+		{
+			name: "missing operand",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{ReplicasTemplate: "{{ mul .Target.Labels.app 2 | ceil }}"},
+			},
+			expectError: true,
+		},
+		{
+			name: "fractional replicas",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{ReplicasTemplate: "{{ mul .CurrentReplicas 1.5 }}"},
+			},
+			expectError: true,
+		},
+		{
+			name: "zero replicas",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "scale",
+				Type:        "scale",
+				ScaleAction: &v1alpha1.ScaleAction{ReplicasTemplate: "{{ sub .CurrentReplicas 3 }}"},
+			},
+			expectError: true,
+		},
+		{
+			name: "zero replicas allowed",
+			action: v1alpha1.HealingActionTemplate{
+				Name: "scale",
+				Type: "scale",
+				ScaleAction: &v1alpha1.ScaleAction{
+					Direction:        "absolute",
+					ReplicasTemplate: "{{ sub .CurrentReplicas 3 }}",
+					AllowScaleToZero: true,
+				},
+			},
+			expectedFields: []string{"scaleAction.replicas"},
+			verify: func(t *testing.T, action *v1alpha1.HealingActionTemplate) {
+				assert.Equal(t, int32(0), action.ScaleAction.Replicas)
+			},
+		},
+		{
+			name: "invalid template",
+			action: v1alpha1.HealingActionTemplate{
+				Name:        "patch",
+				Type:        "patch",
+				PatchAction: &v1alpha1.PatchAction{Patch: "{{ .Missing"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.action.DeepCopy()
+
+			rendered, fields, err := RenderActionTemplate(&tt.action, tctx)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFields, fields)
+			tt.verify(t, rendered)
+
+			// The policy's template must not be modified
+			assert.Equal(t, original, &tt.action)
+		})
+	}
+}
+
+func TestToFloat(t *testing.T) {
+	value, err := toFloat(" 2.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, value)
+
+	value, err = toFloat(int32(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, value)
+
+	for _, bad := range []interface{}{"web", "", nil, true, []int{1}} {
+		_, err := toFloat(bad)
+		assert.Error(t, err, "%v", bad)
+	}
+}
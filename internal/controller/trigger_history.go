@@ -0,0 +1,76 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// triggerHistorySize returns how many samples to keep per metric trigger
+func (r *HealingPolicyReconciler) triggerHistorySize() int {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.Metrics.TriggerHistorySize
+}
+
+// recordTriggerSample appends the value a metric trigger was evaluated
+// against to the policy's trigger history, keeping at most size samples.
+// Triggers whose query produced no value are not recorded.
+func recordTriggerSample(policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger, result types.TriggerResult, now metav1.Time, size int) {
+	if size <= 0 || trigger.MetricTrigger == nil || !result.Observed {
+		return
+	}
+
+	sample := v1alpha1.TriggerSample{
+		Time:      now,
+		Value:     result.Value,
+		Threshold: trigger.MetricTrigger.Threshold,
+		Operator:  trigger.MetricTrigger.Operator,
+		Triggered: result.Triggered,
+	}
+	if result.Baselined {
+		sample.Threshold = result.Threshold
+	}
+
+	for i := range policy.Status.TriggerHistory {
+		history := &policy.Status.TriggerHistory[i]
+		if history.Name != trigger.Name {
+			continue
+		}
+		history.Samples = append(history.Samples, sample)
+		if len(history.Samples) > size {
+			history.Samples = history.Samples[len(history.Samples)-size:]
+		}
+		return
+	}
+	policy.Status.TriggerHistory = append(policy.Status.TriggerHistory, v1alpha1.TriggerHistory{
+		Name:    trigger.Name,
+		Samples: []v1alpha1.TriggerSample{sample},
+	})
+}
+
+// pruneTriggerHistory drops the history of triggers that are no longer
+// metric triggers of the policy, or all history when it is disabled
+func pruneTriggerHistory(policy *v1alpha1.HealingPolicy, size int) {
+	if size <= 0 {
+		policy.Status.TriggerHistory = nil
+		return
+	}
+
+	metricTriggers := make(map[string]bool, len(policy.Spec.Triggers))
+	for _, trigger := range policy.Spec.Triggers {
+		if trigger.MetricTrigger != nil {
+			metricTriggers[trigger.Name] = true
+		}
+	}
+
+	var kept []v1alpha1.TriggerHistory
+	for _, history := range policy.Status.TriggerHistory {
+		if metricTriggers[history.Name] {
+			kept = append(kept, history)
+		}
+	}
+	policy.Status.TriggerHistory = kept
+}
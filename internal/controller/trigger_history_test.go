@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestRecordTriggerSample(t *testing.T) {
+	latency := v1alpha1.HealingTrigger{
+		Name:          "latency",
+		Type:          "metric",
+		MetricTrigger: &v1alpha1.MetricTrigger{Query: "p99_latency", Threshold: 0.5, Operator: ">"},
+	}
+	errors := v1alpha1.HealingTrigger{
+		Name:          "errors",
+		Type:          "metric",
+		MetricTrigger: &v1alpha1.MetricTrigger{Query: "error_rate", Threshold: 5, Operator: ">"},
+	}
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{Triggers: []v1alpha1.HealingTrigger{latency, errors}},
+	}
+
+	start := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	for i, value := range []float64{0.2, 0.4, 0.6, 0.7} {
+		result := types.TriggerResult{Triggered: value > 0.5, Value: value, Observed: true}
+		recordTriggerSample(policy, &latency, result, metav1.NewTime(start.Add(time.Duration(i)*time.Minute)), 3)
+		// The error query returned nothing, so there is nothing to record
+		recordTriggerSample(policy, &errors, types.TriggerResult{}, metav1.NewTime(start), 3)
+	}
+
+	require.Len(t, policy.Status.TriggerHistory, 1)
+	history := policy.Status.TriggerHistory[0]
+	assert.Equal(t, "latency", history.Name)
+	require.Len(t, history.Samples, 3, "history is bounded")
+	assert.Equal(t, 0.4, history.Samples[0].Value)
+	assert.False(t, history.Samples[0].Triggered)
+	assert.Equal(t, v1alpha1.TriggerSample{
+		Time:      metav1.NewTime(start.Add(3 * time.Minute)),
+		Value:     0.7,
+		Threshold: 0.5,
+		Operator:  ">",
+		Triggered: true,
+	}, history.Samples[2])
+
+	// Baselined triggers record the threshold the value was compared against
+	recordTriggerSample(policy, &errors, types.TriggerResult{Value: 9, Observed: true, Threshold: 12, Baselined: true}, metav1.NewTime(start), 3)
+	require.Len(t, policy.Status.TriggerHistory, 2)
+	assert.Equal(t, 12.0, policy.Status.TriggerHistory[1].Samples[0].Threshold)
+
+	// Disabled history records nothing
+	recordTriggerSample(policy, &latency, types.TriggerResult{Triggered: true, Value: 9, Observed: true}, metav1.NewTime(start), 0)
+	assert.Len(t, policy.Status.TriggerHistory[0].Samples, 3)
+}
+
+func TestPruneTriggerHistory(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{
+				{Name: "latency", Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{Query: "p99_latency"}},
+			},
+		},
+		Status: v1alpha1.HealingPolicyStatus{
+			TriggerHistory: []v1alpha1.TriggerHistory{{Name: "latency"}, {Name: "removed"}},
+		},
+	}
+
+	pruneTriggerHistory(policy, 10)
+	require.Len(t, policy.Status.TriggerHistory, 1)
+	assert.Equal(t, "latency", policy.Status.TriggerHistory[0].Name)
+
+	pruneTriggerHistory(policy, 0)
+	assert.Nil(t, policy.Status.TriggerHistory)
+}
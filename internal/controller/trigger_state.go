@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// defaultClearAfterEvaluations is used when a trigger does not set
+	// ClearAfterEvaluations
+	defaultClearAfterEvaluations = 3
+
+	// flapReactivations is the number of reactivations after which a
+	// trigger is considered flapping
+	flapReactivations = 3
+
+	// ReasonTriggerFlapping is set when a trigger keeps turning on and off
+	ReasonTriggerFlapping = "TriggerFlapping"
+
+	// ReasonTriggersStable clears a previous TriggerFlapping condition
+	ReasonTriggersStable = "TriggersStable"
+)
+
+// updateTriggerStates records the outcome of an evaluation in the policy
+// status. evaluated maps each evaluated trigger to whether it fired; triggers
+// skipped this evaluation (cooldown, errors) keep their state. Triggers that
+// stayed quiet for ClearAfterEvaluations evaluations, or that were removed
+// from the spec, are dropped.
+func updateTriggerStates(policy *v1alpha1.HealingPolicy, evaluated map[string]bool, now metav1.Time) {
+	previous := make(map[string]v1alpha1.TriggerState, len(policy.Status.TriggerStates))
+	for _, state := range policy.Status.TriggerStates {
+		previous[state.Name] = state
+	}
+
+	var states []v1alpha1.TriggerState
+	var flapping []string
+	for _, trigger := range policy.Spec.Triggers {
+		state, tracked := previous[trigger.Name]
+		fired, wasEvaluated := evaluated[trigger.Name]
+
+		switch {
+		case !wasEvaluated:
+			if !tracked {
+				continue
+			}
+		case fired && !tracked:
+			state = v1alpha1.TriggerState{Name: trigger.Name, FirstActive: now, LastActive: now}
+		case fired:
+			if state.MissedEvaluations > 0 {
+				state.Reactivations++
+			}
+			state.MissedEvaluations = 0
+			state.LastActive = now
+		case tracked:
+			state.MissedEvaluations++
+			clearAfter := trigger.ClearAfterEvaluations
+			if clearAfter <= 0 {
+				clearAfter = defaultClearAfterEvaluations
+			}
+			if state.MissedEvaluations >= clearAfter {
+				continue
+			}
+		default:
+			continue
+		}
+
+		state.Flapping = state.Reactivations >= flapReactivations
+		if state.Flapping {
+			flapping = append(flapping, trigger.Name)
+		}
+		states = append(states, state)
+	}
+
+	policy.Status.TriggerStates = states
+	setFlappingCondition(policy, flapping)
+}
+
+// setFlappingCondition reports flapping triggers on the policy
+func setFlappingCondition(policy *v1alpha1.HealingPolicy, flapping []string) {
+	if len(flapping) == 0 {
+		if cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeTriggerFlapping); cond != nil && cond.Status == metav1.ConditionTrue {
+			SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeTriggerFlapping,
+				metav1.ConditionFalse, ReasonTriggersStable, "No triggers are flapping")
+		}
+		return
+	}
+
+	SetCondition(&policy.Status.Conditions, v1alpha1.ConditionTypeTriggerFlapping,
+		metav1.ConditionTrue, ReasonTriggerFlapping,
+		fmt.Sprintf("Triggers repeatedly turning on and off: %s", strings.Join(flapping, ", ")))
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestUpdateTriggerStates(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{
+				{Name: "steady"},
+				{Name: "blip", ClearAfterEvaluations: 2},
+				{Name: "flappy", ClearAfterEvaluations: 5},
+			},
+		},
+	}
+
+	start := time.Now()
+	evaluate := func(step int, evaluated map[string]bool) {
+		updateTriggerStates(policy, evaluated, metav1.NewTime(start.Add(time.Duration(step)*time.Minute)))
+	}
+	state := func(name string) *v1alpha1.TriggerState {
+		for i := range policy.Status.TriggerStates {
+			if policy.Status.TriggerStates[i].Name == name {
+				return &policy.Status.TriggerStates[i]
+			}
+		}
+		return nil
+	}
+
+	evaluate(0, map[string]bool{"steady": true, "blip": true, "flappy": true})
+	require.Len(t, policy.Status.TriggerStates, 3)
+	assert.Equal(t, start.Unix(), state("steady").FirstActive.Unix())
+
+	// blip is cleared after two quiet evaluations, flappy keeps toggling
+	evaluate(1, map[string]bool{"steady": true, "blip": false, "flappy": false})
+	evaluate(2, map[string]bool{"steady": true, "blip": false, "flappy": true})
+	assert.Nil(t, state("blip"))
+
+	steady := state("steady")
+	require.NotNil(t, steady)
+	assert.Equal(t, start.Unix(), steady.FirstActive.Unix())
+	assert.Equal(t, start.Add(2*time.Minute).Unix(), steady.LastActive.Unix())
+	assert.Zero(t, steady.Reactivations)
+
+	// Skipped evaluations (cooldown) do not count as missed
+	evaluate(3, map[string]bool{"flappy": false})
+	assert.Zero(t, state("steady").MissedEvaluations)
+
+	evaluate(4, map[string]bool{"flappy": true})
+	evaluate(5, map[string]bool{"flappy": false})
+	assert.False(t, state("flappy").Flapping)
+	assert.Nil(t, GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeTriggerFlapping))
+
+	evaluate(6, map[string]bool{"flappy": true})
+	flappy := state("flappy")
+	require.NotNil(t, flappy)
+	assert.Equal(t, int32(3), flappy.Reactivations)
+	assert.True(t, flappy.Flapping)
+	cond := GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeTriggerFlapping)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Contains(t, cond.Message, "flappy")
+
+	// Removing a trigger from the spec drops its state
+	policy.Spec.Triggers = policy.Spec.Triggers[:1]
+	evaluate(7, map[string]bool{"steady": true})
+	require.Len(t, policy.Status.TriggerStates, 1)
+	cond = GetCondition(policy.Status.Conditions, v1alpha1.ConditionTypeTriggerFlapping)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/events"
+)
+
+// Trigger state transitions
+const (
+	TransitionActivated   = "Activated"
+	TransitionStillFiring = "StillFiring"
+	TransitionResolved    = "Resolved"
+)
+
+var (
+	triggerTransitionsTotal *prometheus.CounterVec
+)
+
+// SetTriggerTransitionsMetric sets the trigger transitions metric from main.go
+func SetTriggerTransitionsMetric(metric *prometheus.CounterVec) {
+	triggerTransitionsTotal = metric
+}
+
+// TriggerTransition is a change in the state of a trigger
+type TriggerTransition struct {
+	Trigger    string
+	Transition string
+	Reason     string
+}
+
+// detectTriggerTransitions compares the trigger states before and after an
+// evaluation. Triggers that started being tracked were activated, triggers
+// no longer tracked were resolved, and triggers that fired again are
+// reported as still firing once per heartbeat. Reported activations and
+// heartbeats update LastNotified.
+func detectTriggerTransitions(policy *v1alpha1.HealingPolicy, previous []v1alpha1.TriggerState, reasons map[string]string, heartbeat time.Duration, now metav1.Time) []TriggerTransition {
+	tracked := make(map[string]bool, len(previous))
+	for _, state := range previous {
+		tracked[state.Name] = true
+	}
+
+	var transitions []TriggerTransition
+	current := make(map[string]bool, len(policy.Status.TriggerStates))
+	for i := range policy.Status.TriggerStates {
+		state := &policy.Status.TriggerStates[i]
+		current[state.Name] = true
+		reason, fired := reasons[state.Name]
+
+		switch {
+		case !tracked[state.Name]:
+			transitions = append(transitions, TriggerTransition{Trigger: state.Name, Transition: TransitionActivated, Reason: reason})
+			state.LastNotified = now
+		case fired && heartbeat > 0 && now.Sub(state.LastNotified.Time) >= heartbeat:
+			transitions = append(transitions, TriggerTransition{
+				Trigger:    state.Name,
+				Transition: TransitionStillFiring,
+				Reason:     fmt.Sprintf("firing since %s: %s", state.FirstActive.UTC().Format(time.RFC3339), reason),
+			})
+			state.LastNotified = now
+		}
+	}
+
+	for _, state := range previous {
+		if !current[state.Name] {
+			transitions = append(transitions, TriggerTransition{
+				Trigger:    state.Name,
+				Transition: TransitionResolved,
+				Reason:     fmt.Sprintf("last fired at %s", state.LastActive.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+	return transitions
+}
+
+// reportTriggerTransitions logs, counts, records and notifies trigger
+// transitions. Nothing is reported for evaluations that change nothing.
+func (r *HealingPolicyReconciler) reportTriggerTransitions(ctx context.Context, log logr.Logger, policy *v1alpha1.HealingPolicy, transitions []TriggerTransition) {
+	for _, t := range transitions {
+		log.Info("Trigger state changed", "trigger", t.Trigger, "transition", t.Transition, "reason", t.Reason)
+
+		if triggerTransitionsTotal != nil {
+			triggerTransitionsTotal.WithLabelValues(policy.Name, policy.Namespace, t.Trigger, t.Transition).Inc()
+		}
+
+		if r.Events != nil {
+			eventType := corev1.EventTypeWarning
+			if t.Transition == TransitionResolved {
+				eventType = corev1.EventTypeNormal
+			}
+			r.Events.Event(policy, events.Key(policy.Namespace+"/"+policy.Name, t.Trigger, t.Transition),
+				eventType, "Trigger"+t.Transition, fmt.Sprintf("Trigger %s: %s", t.Trigger, t.Reason))
+		}
+
+		if r.Notifier != nil {
+			if err := r.Notifier.NotifyTrigger(ctx, policy, t.Trigger, t.Transition, t.Reason); err != nil {
+				log.Error(err, "Failed to notify trigger transition", "trigger", t.Trigger, "transition", t.Transition)
+			}
+		}
+	}
+}
+
+// triggerHeartbeat returns the interval of "still firing" reports
+func (r *HealingPolicyReconciler) triggerHeartbeat() time.Duration {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.Events.TriggerHeartbeat
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/events"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestDetectTriggerTransitions(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{{Name: "high-cpu", ClearAfterEvaluations: 1}},
+		},
+	}
+	start := time.Now()
+	evaluate := func(step time.Duration, fired bool) []TriggerTransition {
+		previous := append([]v1alpha1.TriggerState(nil), policy.Status.TriggerStates...)
+		reasons := map[string]string{}
+		if fired {
+			reasons["high-cpu"] = "cpu above 80%"
+		}
+		now := metav1.NewTime(start.Add(step))
+		updateTriggerStates(policy, map[string]bool{"high-cpu": fired}, now)
+		return detectTriggerTransitions(policy, previous, reasons, 30*time.Minute, now)
+	}
+
+	transitions := evaluate(0, true)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, TriggerTransition{Trigger: "high-cpu", Transition: TransitionActivated, Reason: "cpu above 80%"}, transitions[0])
+
+	// Repeated firing is quiet until the heartbeat is due
+	assert.Empty(t, evaluate(time.Minute, true))
+	assert.Empty(t, evaluate(29*time.Minute, true))
+
+	transitions = evaluate(30*time.Minute, true)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, TransitionStillFiring, transitions[0].Transition)
+	assert.Contains(t, transitions[0].Reason, "cpu above 80%")
+	assert.Empty(t, evaluate(31*time.Minute, true))
+
+	transitions = evaluate(32*time.Minute, false)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, TransitionResolved, transitions[0].Transition)
+	assert.Empty(t, policy.Status.TriggerStates)
+	assert.Empty(t, evaluate(33*time.Minute, false))
+}
+
+func TestDetectTriggerTransitions_NoHeartbeat(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{
+			Triggers: []v1alpha1.HealingTrigger{{Name: "errors"}},
+		},
+	}
+	start := time.Now()
+	updateTriggerStates(policy, map[string]bool{"errors": true}, metav1.NewTime(start))
+
+	previous := append([]v1alpha1.TriggerState(nil), policy.Status.TriggerStates...)
+	now := metav1.NewTime(start.Add(24 * time.Hour))
+	updateTriggerStates(policy, map[string]bool{"errors": true}, now)
+	assert.Empty(t, detectTriggerTransitions(policy, previous, map[string]string{"errors": "5xx"}, 0, now))
+}
+
+type recordingTriggerNotifier struct {
+	transitions []string
+}
+
+func (n *recordingTriggerNotifier) NotifyTrigger(_ context.Context, _ *v1alpha1.HealingPolicy, trigger, transition, _ string) error {
+	n.transitions = append(n.transitions, trigger+"/"+transition)
+	return nil
+}
+
+func TestReportTriggerTransitions(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	notifier := &recordingTriggerNotifier{}
+	r := &HealingPolicyReconciler{
+		Events:   events.NewAggregator(recorder, config.EventsConfig{AggregationWindow: time.Minute, EmitEvery: 10}),
+		Notifier: notifier,
+	}
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}}
+
+	r.reportTriggerTransitions(context.Background(), logr.Discard(), policy, []TriggerTransition{
+		{Trigger: "high-cpu", Transition: TransitionActivated, Reason: "cpu above 80%"},
+		{Trigger: "errors", Transition: TransitionResolved, Reason: "last fired at 12:00"},
+	})
+
+	assert.Equal(t, []string{"high-cpu/Activated", "errors/Resolved"}, notifier.transitions)
+	require.Len(t, recorder.Events, 2)
+	assert.Equal(t, "Warning TriggerActivated Trigger high-cpu: cpu above 80%", <-recorder.Events)
+	assert.Equal(t, "Normal TriggerResolved Trigger errors: last fired at 12:00", <-recorder.Events)
+}
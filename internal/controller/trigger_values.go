@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+var (
+	triggerValueGauge     *prometheus.GaugeVec
+	triggerThresholdGauge *prometheus.GaugeVec
+	triggerValues         = newTriggerValueRecorder()
+)
+
+// SetTriggerValueMetrics sets the trigger value and threshold metrics from
+// main.go
+func SetTriggerValueMetrics(value, threshold *prometheus.GaugeVec) {
+	triggerValueGauge = value
+	triggerThresholdGauge = threshold
+}
+
+// triggerSeries identifies the series of one trigger
+type triggerSeries struct {
+	policy    string
+	namespace string
+	trigger   string
+}
+
+// triggerValueRecorder exports the values of the metric triggers of the
+// policies that opt in. Every trigger adds a series to each gauge, so the
+// number of triggers exported is capped, and a policy's series are deleted
+// when it stops recording, drops a trigger or is deleted.
+type triggerValueRecorder struct {
+	mu       sync.Mutex
+	exported map[triggerSeries]bool
+	// refused holds the policies with triggers refused by the limit, so the
+	// limit is reported once per policy rather than every evaluation
+	refused map[client.ObjectKey]bool
+}
+
+func newTriggerValueRecorder() *triggerValueRecorder {
+	return &triggerValueRecorder{
+		exported: make(map[triggerSeries]bool),
+		refused:  make(map[client.ObjectKey]bool),
+	}
+}
+
+// record exports the value a metric trigger was evaluated against, unless
+// the policy does not record trigger values, the value was not observed or
+// the trigger would exceed limit series. It reports whether the limit
+// refused a trigger of the policy for the first time, so that is logged
+// once.
+func (r *triggerValueRecorder) record(policy *v1alpha1.HealingPolicy, trigger *v1alpha1.HealingTrigger, result types.TriggerResult, limit int) bool {
+	if !policy.Spec.RecordTriggerValues || trigger.MetricTrigger == nil || !result.Observed ||
+		limit <= 0 || triggerValueGauge == nil {
+		return false
+	}
+
+	key := triggerSeries{policy: policy.Name, namespace: policy.Namespace, trigger: trigger.Name}
+	policyKey := client.ObjectKeyFromObject(policy)
+	r.mu.Lock()
+	if !r.exported[key] {
+		if len(r.exported) >= limit {
+			first := !r.refused[policyKey]
+			r.refused[policyKey] = true
+			r.mu.Unlock()
+			return first
+		}
+		r.exported[key] = true
+	}
+	r.mu.Unlock()
+
+	threshold := trigger.MetricTrigger.Threshold
+	if result.Baselined {
+		threshold = result.Threshold
+	}
+	triggerValueGauge.WithLabelValues(key.policy, key.namespace, key.trigger).Set(result.Value)
+	if triggerThresholdGauge != nil {
+		triggerThresholdGauge.WithLabelValues(key.policy, key.namespace, key.trigger).Set(threshold)
+	}
+	return false
+}
+
+// prune deletes the series of the policy's triggers that are no longer
+// recorded: all of them when policy is nil, i.e. deleted, or no longer
+// records trigger values
+func (r *triggerValueRecorder) prune(name client.ObjectKey, policy *v1alpha1.HealingPolicy) {
+	recorded := make(map[string]bool)
+	if policy != nil && policy.Spec.RecordTriggerValues {
+		for _, trigger := range policy.Spec.Triggers {
+			if trigger.MetricTrigger != nil {
+				recorded[trigger.Name] = true
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.exported {
+		if key.policy != name.Name || key.namespace != name.Namespace || recorded[key.trigger] {
+			continue
+		}
+		delete(r.exported, key)
+		if triggerValueGauge != nil {
+			triggerValueGauge.DeleteLabelValues(key.policy, key.namespace, key.trigger)
+		}
+		if triggerThresholdGauge != nil {
+			triggerThresholdGauge.DeleteLabelValues(key.policy, key.namespace, key.trigger)
+		}
+	}
+	if len(recorded) == 0 {
+		delete(r.refused, name)
+	}
+}
+
+// triggerValueSeriesLimit returns the cap on exported trigger value series
+func (r *HealingPolicyReconciler) triggerValueSeriesLimit() int {
+	if r.Config == nil {
+		return 0
+	}
+	return r.Config.Metrics.TriggerValueSeriesLimit
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestTriggerValueRecorder(t *testing.T) {
+	value := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_trigger_value"}, []string{"policy", "namespace", "trigger"})
+	threshold := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_trigger_threshold"}, []string{"policy", "namespace", "trigger"})
+	previousValue, previousThreshold := triggerValueGauge, triggerThresholdGauge
+	SetTriggerValueMetrics(value, threshold)
+	defer SetTriggerValueMetrics(previousValue, previousThreshold)
+	recorder := newTriggerValueRecorder()
+
+	metricTrigger := func(name string, threshold float64) v1alpha1.HealingTrigger {
+		return v1alpha1.HealingTrigger{Name: name, Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+			Query: "up", Threshold: threshold, Operator: ">",
+		}}
+	}
+	policy := func(name string, record bool, triggers ...v1alpha1.HealingTrigger) *v1alpha1.HealingPolicy {
+		return &v1alpha1.HealingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       v1alpha1.HealingPolicySpec{Triggers: triggers, RecordTriggerValues: record},
+		}
+	}
+	observed := func(v float64) types.TriggerResult { return types.TriggerResult{Value: v, Observed: true} }
+
+	errors := metricTrigger("errors", 0.1)
+	latency := metricTrigger("latency", 0.5)
+	web := policy("web", true, errors, latency)
+	assert.False(t, recorder.record(web, &web.Spec.Triggers[0], observed(0.05), 2))
+	assert.False(t, recorder.record(web, &web.Spec.Triggers[1],
+		types.TriggerResult{Value: 0.8, Observed: true, Threshold: 0.6, Baselined: true}, 2))
+	assert.Equal(t, 0.05, testutil.ToFloat64(value.WithLabelValues("web", "apps", "errors")))
+	assert.Equal(t, 0.1, testutil.ToFloat64(threshold.WithLabelValues("web", "apps", "errors")))
+	assert.Equal(t, 0.6, testutil.ToFloat64(threshold.WithLabelValues("web", "apps", "latency")), "baselined threshold")
+
+	// Policies that do not opt in and values that were not observed are
+	// not exported
+	quiet := policy("quiet", false, errors)
+	assert.False(t, recorder.record(quiet, &quiet.Spec.Triggers[0], observed(1), 2))
+	assert.False(t, recorder.record(web, &web.Spec.Triggers[0], types.TriggerResult{}, 2))
+	assert.Equal(t, 2, testutil.CollectAndCount(value))
+	assert.Equal(t, 0.05, testutil.ToFloat64(value.WithLabelValues("web", "apps", "errors")))
+
+	// Triggers past the limit are refused, reported once per policy
+	api := policy("api", true, errors)
+	assert.True(t, recorder.record(api, &api.Spec.Triggers[0], observed(1), 2))
+	assert.False(t, recorder.record(api, &api.Spec.Triggers[0], observed(1), 2))
+	assert.Equal(t, 2, testutil.CollectAndCount(value))
+
+	// Existing series keep being updated at the limit
+	assert.False(t, recorder.record(web, &web.Spec.Triggers[0], observed(0.2), 2))
+	assert.Equal(t, 0.2, testutil.ToFloat64(value.WithLabelValues("web", "apps", "errors")))
+
+	// Dropping a trigger frees its series
+	web.Spec.Triggers = web.Spec.Triggers[:1]
+	recorder.prune(client.ObjectKeyFromObject(web), web)
+	assert.Equal(t, 1, testutil.CollectAndCount(value))
+	assert.Equal(t, 1, testutil.CollectAndCount(threshold))
+	assert.False(t, recorder.record(api, &api.Spec.Triggers[0], observed(1), 2))
+	assert.Equal(t, 2, testutil.CollectAndCount(value))
+
+	// Deleted policies and policies that stop recording lose their series
+	recorder.prune(client.ObjectKeyFromObject(api), nil)
+	web.Spec.RecordTriggerValues = false
+	recorder.prune(client.ObjectKeyFromObject(web), web)
+	assert.Zero(t, testutil.CollectAndCount(value))
+	assert.Zero(t, testutil.CollectAndCount(threshold))
+	assert.Empty(t, recorder.exported)
+	assert.Empty(t, recorder.refused)
+}
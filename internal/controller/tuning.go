@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// AnnotationTuningSuggestions holds the latest report's tuning
+	// suggestions for a policy as JSON
+	AnnotationTuningSuggestions = "kubeskippy.io/tuning-suggestions"
+
+	// minTuningSamples is the number of trigger history samples needed
+	// before thresholds are judged
+	minTuningSamples = 5
+
+	// minSuggestedCooldown is the smallest cooldown suggested for flapping
+	// triggers
+	minSuggestedCooldown = 10 * time.Minute
+)
+
+// SuggestTuning analyzes how the policy's triggers fired and suggests
+// changes for triggers that always fire, never fire or flap. fired counts
+// the actions created per trigger in the report period; triggers of
+// policies younger than the period are not reported as never firing.
+func SuggestTuning(policy *v1alpha1.HealingPolicy, fired map[string]int32, start time.Time) []v1alpha1.TuningSuggestion {
+	states := make(map[string]v1alpha1.TriggerState, len(policy.Status.TriggerStates))
+	for _, state := range policy.Status.TriggerStates {
+		states[state.Name] = state
+	}
+	history := make(map[string][]v1alpha1.TriggerSample, len(policy.Status.TriggerHistory))
+	for _, h := range policy.Status.TriggerHistory {
+		history[h.Name] = h.Samples
+	}
+	coversPeriod := policy.CreationTimestamp.Time.Before(start)
+
+	var suggestions []v1alpha1.TuningSuggestion
+	for i := range policy.Spec.Triggers {
+		trigger := &policy.Spec.Triggers[i]
+		state, active := states[trigger.Name]
+
+		if state.Flapping || state.Reactivations >= flapReactivations {
+			current := trigger.CooldownPeriod.Duration
+			suggestions = append(suggestions, v1alpha1.TuningSuggestion{
+				Trigger:   trigger.Name,
+				Pattern:   v1alpha1.TuningPatternFlapping,
+				Field:     "cooldownPeriod",
+				Current:   current.String(),
+				Suggested: max(2*current, minSuggestedCooldown).String(),
+				Reason: fmt.Sprintf("fired again %d times after going quiet; a longer cooldown lets actions take effect before the trigger is re-evaluated",
+					state.Reactivations),
+				Source: v1alpha1.TuningSourceRules,
+			})
+			continue
+		}
+
+		samples := history[trigger.Name]
+		if suggestion, ok := suggestThreshold(trigger, samples, fired[trigger.Name]); ok {
+			suggestions = append(suggestions, suggestion)
+			continue
+		}
+
+		if coversPeriod && fired[trigger.Name] == 0 && !active && len(samples) == 0 {
+			suggestions = append(suggestions, v1alpha1.TuningSuggestion{
+				Trigger: trigger.Name,
+				Pattern: v1alpha1.TuningPatternNeverFiring,
+				Reason:  "created no actions in the report period; check that the trigger still matches a failure mode of the workload or remove it",
+				Source:  v1alpha1.TuningSourceRules,
+			})
+		}
+	}
+	return suggestions
+}
+
+// suggestThreshold judges the threshold of a metric trigger from its
+// evaluated values: a trigger firing in every evaluation should move to the
+// 90th percentile of its values, one that never fired to the extreme value
+// it saw. Thresholds relative to a baseline are not judged from absolute
+// values.
+func suggestThreshold(trigger *v1alpha1.HealingTrigger, samples []v1alpha1.TriggerSample, fired int32) (v1alpha1.TuningSuggestion, bool) {
+	if trigger.MetricTrigger == nil || trigger.MetricTrigger.Baseline != nil || len(samples) < minTuningSamples {
+		return v1alpha1.TuningSuggestion{}, false
+	}
+	var above bool
+	switch trigger.MetricTrigger.Operator {
+	case ">", ">=":
+		above = true
+	case "<", "<=":
+	default:
+		return v1alpha1.TuningSuggestion{}, false
+	}
+
+	values := make([]float64, len(samples))
+	triggered := 0
+	for i, sample := range samples {
+		values[i] = sample.Value
+		if sample.Triggered {
+			triggered++
+		}
+	}
+	sort.Float64s(values)
+	current := trigger.MetricTrigger.Threshold
+
+	suggestion := v1alpha1.TuningSuggestion{
+		Trigger: trigger.Name,
+		Field:   "metricTrigger.threshold",
+		Current: formatThreshold(current),
+		Source:  v1alpha1.TuningSourceRules,
+	}
+	switch {
+	case triggered == len(samples):
+		suggested := percentile(values, 0.9)
+		if !above {
+			suggested = percentile(values, 0.1)
+		}
+		if suggested == current {
+			return v1alpha1.TuningSuggestion{}, false
+		}
+		suggestion.Pattern = v1alpha1.TuningPatternAlwaysFiring
+		suggestion.Suggested = formatThreshold(suggested)
+		suggestion.Reason = fmt.Sprintf("fired in all %d recent evaluations (values %s to %s); a threshold at the %s of the observed values only fires on outliers",
+			len(samples), formatThreshold(values[0]), formatThreshold(values[len(values)-1]), percentileName(above))
+
+	case triggered == 0 && fired == 0:
+		extreme := values[len(values)-1]
+		if !above {
+			extreme = values[0]
+		}
+		suggestion.Pattern = v1alpha1.TuningPatternNeverFiring
+		suggestion.Suggested = formatThreshold(extreme)
+		suggestion.Reason = fmt.Sprintf("did not fire in %d recent evaluations and created no actions in the report period; the most extreme value observed was %s, move the threshold towards it if such values need healing",
+			len(samples), formatThreshold(extreme))
+
+	default:
+		return v1alpha1.TuningSuggestion{}, false
+	}
+	return suggestion, true
+}
+
+// percentile returns the p-th percentile of sorted values by nearest rank
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// percentileName names the percentile suggested for always firing triggers
+func percentileName(above bool) string {
+	if above {
+		return "90th percentile"
+	}
+	return "10th percentile"
+}
+
+// formatThreshold formats a threshold with at most two decimals
+func formatThreshold(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// suggestAITuning asks the AI analyzer for tuning suggestions, passing the
+// firing history of each trigger as an issue. Recommendations naming a
+// trigger are attributed to it.
+func (r *HealingReportReconciler) suggestAITuning(ctx context.Context, policy *v1alpha1.HealingPolicy, fired map[string]int32) []v1alpha1.TuningSuggestion {
+	history := make(map[string][]v1alpha1.TriggerSample, len(policy.Status.TriggerHistory))
+	for _, h := range policy.Status.TriggerHistory {
+		history[h.Name] = h.Samples
+	}
+	states := make(map[string]v1alpha1.TriggerState, len(policy.Status.TriggerStates))
+	for _, state := range policy.Status.TriggerStates {
+		states[state.Name] = state
+	}
+
+	issues := make([]types.Issue, 0, len(policy.Spec.Triggers))
+	for _, trigger := range policy.Spec.Triggers {
+		values := make([]float64, 0, len(history[trigger.Name]))
+		for _, sample := range history[trigger.Name] {
+			values = append(values, sample.Value)
+		}
+		metrics := map[string]interface{}{
+			"actionsInPeriod": fired[trigger.Name],
+			"reactivations":   states[trigger.Name].Reactivations,
+			"cooldownPeriod":  trigger.CooldownPeriod.Duration.String(),
+			"recentValues":    values,
+		}
+		if trigger.MetricTrigger != nil {
+			metrics["query"] = trigger.MetricTrigger.Query
+			metrics["operator"] = trigger.MetricTrigger.Operator
+			metrics["threshold"] = trigger.MetricTrigger.Threshold
+		}
+		issues = append(issues, types.Issue{
+			ID:          fmt.Sprintf("tuning-%s-%s", policy.Name, trigger.Name),
+			Severity:    "low",
+			Type:        "tuning",
+			Resource:    fmt.Sprintf("HealingPolicy/%s/%s", policy.Namespace, policy.Name),
+			Namespace:   policy.Namespace,
+			Description: fmt.Sprintf("Review the %s trigger %q of policy %s: suggest threshold or cooldown changes if it fires too often, never fires or flaps", trigger.Type, trigger.Name, policy.Name),
+			Metrics:     metrics,
+			DetectedAt:  time.Now(),
+		})
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	analysis, err := r.AIAnalyzer.AnalyzeClusterState(ctx, &types.ClusterMetrics{Timestamp: time.Now()}, issues)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "AI tuning analysis failed", "policy", policy.Name)
+		return nil
+	}
+
+	var suggestions []v1alpha1.TuningSuggestion
+	for _, rec := range analysis.Recommendations {
+		suggestion := v1alpha1.TuningSuggestion{
+			Suggested: rec.Action,
+			Reason:    rec.Reason,
+			Source:    v1alpha1.TuningSourceAI,
+		}
+		for _, trigger := range policy.Spec.Triggers {
+			if strings.Contains(rec.Target, trigger.Name) || strings.Contains(rec.Action, trigger.Name) {
+				suggestion.Trigger = trigger.Name
+				break
+			}
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+// annotateTuningSuggestions stores the suggestions on the policy, removing
+// the annotation once there are none
+func (r *HealingReportReconciler) annotateTuningSuggestions(ctx context.Context, policy *v1alpha1.HealingPolicy, suggestions []v1alpha1.TuningSuggestion) error {
+	value := ""
+	if len(suggestions) > 0 {
+		data, err := json.Marshal(suggestions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tuning suggestions: %w", err)
+		}
+		value = string(data)
+	}
+	if policy.Annotations[AnnotationTuningSuggestions] == value {
+		return nil
+	}
+
+	patch := client.MergeFrom(policy.DeepCopy())
+	if value == "" {
+		delete(policy.Annotations, AnnotationTuningSuggestions)
+	} else {
+		if policy.Annotations == nil {
+			policy.Annotations = make(map[string]string)
+		}
+		policy.Annotations[AnnotationTuningSuggestions] = value
+	}
+	if err := r.Patch(ctx, policy, patch); err != nil {
+		return fmt.Errorf("failed to annotate policy %s with tuning suggestions: %w", policy.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// tuningAnalyzer recommends canned changes and records the issues it was
+// asked about
+type tuningAnalyzer struct {
+	recordingAnalyzer
+	issues []ktypes.Issue
+}
+
+func (a *tuningAnalyzer) AnalyzeClusterState(ctx context.Context, metrics *ktypes.ClusterMetrics, issues []ktypes.Issue) (*ktypes.AIAnalysis, error) {
+	a.issues = append(a.issues, issues...)
+	return &ktypes.AIAnalysis{Recommendations: []ktypes.AIRecommendation{
+		{Action: "raise the cpu threshold to 85", Target: "HealingPolicy trigger high-cpu", Reason: "cpu stays above 80 during batch jobs"},
+	}}, nil
+}
+
+func metricTrigger(name, operator string, threshold float64) v1alpha1.HealingTrigger {
+	return v1alpha1.HealingTrigger{
+		Name:           name,
+		Type:           "metric",
+		MetricTrigger:  &v1alpha1.MetricTrigger{Query: name, Operator: operator, Threshold: threshold},
+		CooldownPeriod: metav1.Duration{Duration: 5 * time.Minute},
+	}
+}
+
+func triggerSamples(triggered bool, values ...float64) []v1alpha1.TriggerSample {
+	samples := make([]v1alpha1.TriggerSample, len(values))
+	for i, value := range values {
+		samples[i] = v1alpha1.TriggerSample{Value: value, Triggered: triggered}
+	}
+	return samples
+}
+
+func tuningPolicy(created time.Time) *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-policy", Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		Spec: v1alpha1.HealingPolicySpec{Triggers: []v1alpha1.HealingTrigger{
+			metricTrigger("high-cpu", ">", 80),
+			metricTrigger("low-memory", "<", 100),
+			metricTrigger("restarts", ">", 5),
+			{Name: "oom", Type: "event", CooldownPeriod: metav1.Duration{Duration: 5 * time.Minute}},
+			metricTrigger("latency", ">", 500),
+		}},
+		Status: v1alpha1.HealingPolicyStatus{
+			TriggerHistory: []v1alpha1.TriggerHistory{
+				{Name: "high-cpu", Samples: triggerSamples(true, 81, 82, 85, 90, 95, 88, 84, 83, 86, 99)},
+				{Name: "low-memory", Samples: triggerSamples(false, 300, 250, 400, 180, 220)},
+				{Name: "latency", Samples: triggerSamples(false, 100, 200)},
+			},
+			TriggerStates: []v1alpha1.TriggerState{{Name: "restarts", Reactivations: 4, Flapping: true}},
+		},
+	}
+}
+
+func TestSuggestTuning(t *testing.T) {
+	start := time.Now().Add(-7 * 24 * time.Hour)
+	policy := tuningPolicy(start.Add(-time.Hour))
+
+	suggestions := SuggestTuning(policy, map[string]int32{"high-cpu": 12, "restarts": 9}, start)
+	require.Len(t, suggestions, 4)
+
+	assert.Equal(t, v1alpha1.TuningSuggestion{
+		Trigger:   "high-cpu",
+		Pattern:   v1alpha1.TuningPatternAlwaysFiring,
+		Field:     "metricTrigger.threshold",
+		Current:   "80",
+		Suggested: "95",
+		Reason:    suggestions[0].Reason,
+		Source:    v1alpha1.TuningSourceRules,
+	}, suggestions[0])
+	assert.Contains(t, suggestions[0].Reason, "all 10 recent evaluations")
+
+	assert.Equal(t, "low-memory", suggestions[1].Trigger)
+	assert.Equal(t, v1alpha1.TuningPatternNeverFiring, suggestions[1].Pattern)
+	assert.Equal(t, "180", suggestions[1].Suggested)
+
+	assert.Equal(t, "restarts", suggestions[2].Trigger)
+	assert.Equal(t, v1alpha1.TuningPatternFlapping, suggestions[2].Pattern)
+	assert.Equal(t, "cooldownPeriod", suggestions[2].Field)
+	assert.Equal(t, "5m0s", suggestions[2].Current)
+	assert.Equal(t, "10m0s", suggestions[2].Suggested)
+
+	// Event triggers without history are judged by the actions they created
+	assert.Equal(t, "oom", suggestions[3].Trigger)
+	assert.Equal(t, v1alpha1.TuningPatternNeverFiring, suggestions[3].Pattern)
+	assert.Empty(t, suggestions[3].Field)
+
+	// Policies younger than the period have not had the chance to fire
+	young := tuningPolicy(start.Add(time.Hour))
+	assert.Len(t, SuggestTuning(young, nil, start), 3)
+}
+
+func TestHealingReportReconciler_TuningSuggestions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	policy := tuningPolicy(time.Now().Add(-30 * 24 * time.Hour))
+	report := &v1alpha1.HealingReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "weekly", Namespace: "default", Generation: 1},
+		Spec:       v1alpha1.HealingReportSpec{AITuning: true},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(report, policy).
+		WithStatusSubresource(&v1alpha1.HealingReport{}).
+		Build()
+
+	analyzer := &tuningAnalyzer{}
+	r := &HealingReportReconciler{Client: c, Scheme: scheme, AIAnalyzer: analyzer}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: NamespacedName(report)})
+	require.NoError(t, err)
+
+	stored := &v1alpha1.HealingReport{}
+	require.NoError(t, c.Get(context.Background(), NamespacedName(report), stored))
+	require.Len(t, stored.Status.Policies, 1)
+	suggestions := stored.Status.Policies[0].Suggestions
+	require.Len(t, suggestions, 5)
+	assert.Equal(t, v1alpha1.TuningSuggestion{
+		Trigger:   "high-cpu",
+		Suggested: "raise the cpu threshold to 85",
+		Reason:    "cpu stays above 80 during batch jobs",
+		Source:    v1alpha1.TuningSourceAI,
+	}, suggestions[4])
+
+	// Every trigger's history is passed to the AI
+	require.Len(t, analyzer.issues, 5)
+	assert.Equal(t, "tuning", analyzer.issues[0].Type)
+	assert.Equal(t, 80.0, analyzer.issues[0].Metrics["threshold"])
+
+	annotated := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(context.Background(), NamespacedName(policy), annotated))
+	var annotation []v1alpha1.TuningSuggestion
+	require.NoError(t, json.Unmarshal([]byte(annotated.Annotations[AnnotationTuningSuggestions]), &annotation))
+	assert.Equal(t, suggestions, annotation)
+
+	assert.Contains(t, RenderReportMarkdown(stored), "- high-cpu: set metricTrigger.threshold from 80 to 95, ")
+}
@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+)
+
+// Verification defaults for success criteria without a timeout or interval
+const (
+	defaultVerificationTimeout  = 5 * time.Minute
+	defaultVerificationInterval = 15 * time.Second
+)
+
+// ReasonVerificationFailed is the event reason of actions whose success
+// criteria were not met within their timeout
+const ReasonVerificationFailed = "VerificationFailed"
+
+// needsVerification reports whether an executed action has success
+// criteria to verify. Dry runs change nothing, so they are not verified.
+func needsVerification(action *v1alpha1.HealingAction) bool {
+	return !action.Spec.DryRun && action.Spec.Action.SuccessCriteria != nil
+}
+
+// verificationTimeout returns how long the criteria have to be met
+func verificationTimeout(criteria *v1alpha1.SuccessCriteria) time.Duration {
+	if criteria.Timeout.Duration > 0 {
+		return criteria.Timeout.Duration
+	}
+	return defaultVerificationTimeout
+}
+
+// verificationInterval returns the time between checks of the criteria
+func verificationInterval(criteria *v1alpha1.SuccessCriteria) time.Duration {
+	if criteria.Interval.Duration > 0 {
+		return criteria.Interval.Duration
+	}
+	return defaultVerificationInterval
+}
+
+// startVerification moves an executed action to the Verifying phase
+func (r *HealingActionReconciler) startVerification(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	log.Info("Action executed, verifying success criteria")
+	action.Status.Verification = &v1alpha1.VerificationStatus{StartTime: metav1.Now()}
+	action.SetPhase(v1alpha1.HealingActionPhaseVerifying, "Verifying",
+		"Action executed, waiting for its success criteria")
+
+	// Update status first
+	if err := patchStatus(ctx, r.Client, action); err != nil {
+		log.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	// Then update labels
+	if action.Labels == nil {
+		action.Labels = make(map[string]string)
+	}
+	action.Labels[LabelActionPhase] = v1alpha1.HealingActionPhaseVerifying
+	if err := r.Update(ctx, action); err != nil {
+		log.Error(err, "Failed to update action")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: verificationInterval(action.Spec.Action.SuccessCriteria)}, nil
+}
+
+// handleVerifying checks the success criteria of an executed action. The
+// action succeeds once they are met and fails when they are still unmet
+// after their timeout.
+func (r *HealingActionReconciler) handleVerifying(ctx context.Context, log logr.Logger, action *v1alpha1.HealingAction) (ctrl.Result, error) {
+	criteria := action.Spec.Action.SuccessCriteria
+	if action.Status.Verification == nil {
+		action.Status.Verification = &v1alpha1.VerificationStatus{StartTime: metav1.Now()}
+	}
+	verification := action.Status.Verification
+
+	// Criteria removed from the spec have nothing left to verify
+	met, message := true, "No success criteria to verify"
+	if criteria != nil {
+		var err error
+		met, message, err = r.checkSuccessCriteria(ctx, action)
+		if err != nil {
+			log.Error(err, "Failed to check success criteria")
+			message = err.Error()
+		}
+	}
+
+	now := metav1.Now()
+	verification.Checks++
+	verification.LastCheckTime = &now
+	verification.Message = message
+
+	if met {
+		log.Info("Success criteria met", "checks", verification.Checks)
+		action.SetPhase(v1alpha1.HealingActionPhaseSucceeded, ReasonActionSucceeded,
+			"Action completed and its success criteria were met")
+		startHibernation(action)
+		if _, err := r.completeAction(ctx, log, action); err != nil {
+			return ctrl.Result{}, err
+		}
+		if isHibernating(action) {
+			return r.handleHibernation(ctx, log, action)
+		}
+		if isNodeTainted(action) {
+			return r.handleNodeTaint(ctx, log, action)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	timeout := verificationTimeout(criteria)
+	if now.Sub(verification.StartTime.Time) >= timeout {
+		log.Info("Success criteria not met in time", "timeout", timeout, "reason", message)
+		failure := fmt.Sprintf("Success criteria not met within %v: %s", timeout, message)
+		action.SetPhase(v1alpha1.HealingActionPhaseFailed, ReasonVerificationFailed, failure)
+		if action.Status.Result == nil {
+			action.Status.Result = &v1alpha1.ActionResult{}
+		}
+		action.Status.Result.Success = false
+		action.Status.Result.Error = failure
+		action.Status.Result.FailureReason = v1alpha1.FailureReasonVerificationFailed
+		return r.completeAction(ctx, log, action)
+	}
+
+	if err := patchStatus(ctx, r.Client, action); err != nil {
+		log.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: verificationInterval(criteria)}, nil
+}
+
+// checkSuccessCriteria evaluates the action's criteria, returning whether
+// all of them are met and a message describing the checks
+func (r *HealingActionReconciler) checkSuccessCriteria(ctx context.Context, action *v1alpha1.HealingAction) (bool, string, error) {
+	criteria := action.Spec.Action.SuccessCriteria
+	target, err := r.getTarget(ctx, action)
+	if err != nil {
+		return false, "", err
+	}
+
+	var messages []string
+	if criteria.Metric != nil {
+		met, reason, err := r.checkMetricCriterion(ctx, action, target, criteria.Metric)
+		if err != nil || !met {
+			return false, reason, err
+		}
+		messages = append(messages, reason)
+	}
+
+	if criteria.Condition != nil {
+		met, reason, err := checkConditionCriterion(action, target, criteria.Condition)
+		if err != nil || !met {
+			return false, reason, err
+		}
+		messages = append(messages, reason)
+	}
+
+	return true, strings.Join(messages, "; "), nil
+}
+
+// checkConditionCriterion checks that the target reports the condition. A
+// missing target does not meet it.
+func checkConditionCriterion(action *v1alpha1.HealingAction, target *unstructured.Unstructured, criterion *v1alpha1.TargetCondition) (bool, string, error) {
+	ref := action.Spec.TargetResource
+	status := criterion.Status
+	if status == "" {
+		status = string(metav1.ConditionTrue)
+	}
+	if target == nil {
+		return false, fmt.Sprintf("%s %s not found", ref.Kind, ref.Name), nil
+	}
+
+	met, err := hasCondition(target, criterion.Type, status)
+	if err != nil {
+		return false, "", err
+	}
+	if !met {
+		return false, fmt.Sprintf("%s %s does not have condition %s=%s", ref.Kind, ref.Name, criterion.Type, status), nil
+	}
+	return true, fmt.Sprintf("%s %s has condition %s=%s", ref.Kind, ref.Name, criterion.Type, status), nil
+}
+
+// getTarget reads the action's target, or returns nil if it no longer exists
+func (r *HealingActionReconciler) getTarget(ctx context.Context, action *v1alpha1.HealingAction) (*unstructured.Unstructured, error) {
+	ref := action.Spec.TargetResource
+	target := &unstructured.Unstructured{}
+	target.SetAPIVersion(ref.APIVersion)
+	target.SetKind(ref.Kind)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, target); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", ref.Kind, ref.Name, err)
+	}
+	return target, nil
+}
+
+// checkMetricCriterion evaluates a metric criterion like a metric trigger,
+// rendering the target's variables into templated queries
+func (r *HealingActionReconciler) checkMetricCriterion(ctx context.Context, action *v1alpha1.HealingAction, target *unstructured.Unstructured, criterion *v1alpha1.MetricCriterion) (bool, string, error) {
+	if r.MetricsCollector == nil {
+		return false, "", fmt.Errorf("no metrics collector to evaluate the metric criterion")
+	}
+
+	ref := action.Spec.TargetResource
+	vars := QueryVars{Namespace: ref.Namespace, Name: ref.Name, Kind: ref.Kind, Owner: ref.Name}
+	if ref.Kind == "Pod" {
+		vars.PodName = ref.Name
+	}
+	if target != nil {
+		vars = queryVars(ctx, r.Client, target)
+	}
+	query, err := RenderQuery(criterion.Query, vars)
+	if err != nil {
+		return false, "", err
+	}
+
+	// PromQL is answered by Prometheus, other queries by the policy's metrics
+	metrics := &types.ClusterMetrics{Timestamp: time.Now()}
+	if !triggers.IsPromQL(query) {
+		policy := &v1alpha1.HealingPolicy{}
+		key := client.ObjectKey{Namespace: action.Spec.PolicyRef.Namespace, Name: action.Spec.PolicyRef.Name}
+		if err := r.Get(ctx, key, policy); err != nil {
+			return false, "", fmt.Errorf("failed to get policy for metric criterion: %w", err)
+		}
+		if metrics, err = r.MetricsCollector.CollectMetrics(ctx, policy); err != nil {
+			return false, "", fmt.Errorf("failed to collect metrics for metric criterion: %w", err)
+		}
+	}
+
+	trigger := &v1alpha1.HealingTrigger{
+		Name: "success-criteria",
+		Type: "metric",
+		MetricTrigger: &v1alpha1.MetricTrigger{
+			Query:     query,
+			Threshold: criterion.Threshold,
+			Operator:  criterion.Operator,
+		},
+	}
+	return r.MetricsCollector.EvaluateTrigger(ctx, trigger, metrics)
+}
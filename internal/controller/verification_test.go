@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	ktypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func verifiedAction(phase string, criteria *v1alpha1.SuccessCriteria) *v1alpha1.HealingAction {
+	now := metav1.Now()
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-restart", Namespace: "apps", Finalizers: []string{FinalizerName}},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "web", Namespace: "apps"},
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "apps"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart", SuccessCriteria: criteria},
+			Timeout:        metav1.Duration{Duration: 5 * time.Minute},
+		},
+		Status: v1alpha1.HealingActionStatus{Phase: phase, StartTime: &now},
+	}
+}
+
+func verificationScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestHealingActionReconciler_StartsVerification(t *testing.T) {
+	criteria := &v1alpha1.SuccessCriteria{
+		Condition: &v1alpha1.TargetCondition{Type: "Available"},
+		Interval:  metav1.Duration{Duration: 20 * time.Second},
+	}
+
+	tests := []struct {
+		name      string
+		dryRun    bool
+		criteria  *v1alpha1.SuccessCriteria
+		wantPhase string
+	}{
+		{name: "with success criteria", criteria: criteria, wantPhase: v1alpha1.HealingActionPhaseVerifying},
+		{name: "without success criteria", wantPhase: v1alpha1.HealingActionPhaseSucceeded},
+		{name: "dry run", dryRun: true, criteria: criteria, wantPhase: v1alpha1.HealingActionPhaseSucceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := verificationScheme(t)
+			action := verifiedAction(v1alpha1.HealingActionPhaseInProgress, tt.criteria)
+			action.Spec.DryRun = tt.dryRun
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(action).WithStatusSubresource(action).Build()
+			recorded := 0
+			r := &HealingActionReconciler{
+				Client:            c,
+				Scheme:            scheme,
+				Config:            config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{},
+				SafetyController: &MockSafetyController{RecordActionFunc: func(ctx context.Context, action *v1alpha1.HealingAction, result *ktypes.ActionResult) {
+					recorded++
+				}},
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+			require.NoError(t, err)
+
+			got := &v1alpha1.HealingAction{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+			assert.Equal(t, tt.wantPhase, got.Status.Phase)
+			if tt.wantPhase == v1alpha1.HealingActionPhaseVerifying {
+				assert.Equal(t, tt.wantPhase, got.Labels[LabelActionPhase])
+				require.NotNil(t, got.Status.Verification)
+				assert.Nil(t, got.Status.CompletionTime)
+				assert.Equal(t, 20*time.Second, result.RequeueAfter)
+				assert.Equal(t, "Mock success", got.Status.Result.Message)
+			}
+			if !tt.dryRun {
+				assert.Equal(t, 1, recorded, "the executed action is recorded before verification")
+			}
+		})
+	}
+}
+
+func TestHealingActionReconciler_Verifying(t *testing.T) {
+	deployment := func(available corev1.ConditionStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+			Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: available},
+			}},
+		}
+	}
+	availableCriteria := &v1alpha1.SuccessCriteria{Condition: &v1alpha1.TargetCondition{Type: "Available"}}
+	metricCriteria := &v1alpha1.SuccessCriteria{
+		Metric: &v1alpha1.MetricCriterion{
+			Query:     `sum(rate(http_errors_total{namespace="{{.Namespace}}",deployment="{{.Owner}}"}[5m]))`,
+			Threshold: 1,
+			Operator:  "<",
+		},
+		Timeout: metav1.Duration{Duration: 10 * time.Minute},
+	}
+
+	tests := []struct {
+		name        string
+		criteria    *v1alpha1.SuccessCriteria
+		started     time.Duration
+		objects     []client.Object
+		metricMet   bool
+		wantPhase   string
+		wantMessage string
+		wantRequeue time.Duration
+	}{
+		{
+			name:        "condition met",
+			criteria:    availableCriteria,
+			started:     time.Minute,
+			objects:     []client.Object{deployment(corev1.ConditionTrue)},
+			wantPhase:   v1alpha1.HealingActionPhaseSucceeded,
+			wantMessage: "Deployment web has condition Available=True",
+		},
+		{
+			name:        "condition not met yet",
+			criteria:    availableCriteria,
+			started:     time.Minute,
+			objects:     []client.Object{deployment(corev1.ConditionFalse)},
+			wantPhase:   v1alpha1.HealingActionPhaseVerifying,
+			wantMessage: "Deployment web does not have condition Available=True",
+			wantRequeue: defaultVerificationInterval,
+		},
+		{
+			name:        "condition not met in time",
+			criteria:    availableCriteria,
+			started:     6 * time.Minute,
+			objects:     []client.Object{deployment(corev1.ConditionFalse)},
+			wantPhase:   v1alpha1.HealingActionPhaseFailed,
+			wantMessage: "Deployment web does not have condition Available=True",
+		},
+		{
+			name:        "target missing",
+			criteria:    availableCriteria,
+			started:     time.Minute,
+			wantPhase:   v1alpha1.HealingActionPhaseVerifying,
+			wantMessage: "Deployment web not found",
+			wantRequeue: defaultVerificationInterval,
+		},
+		{
+			name:        "metric met",
+			criteria:    metricCriteria,
+			started:     time.Minute,
+			objects:     []client.Object{deployment(corev1.ConditionTrue)},
+			metricMet:   true,
+			wantPhase:   v1alpha1.HealingActionPhaseSucceeded,
+			wantMessage: `sum(rate(http_errors_total{namespace="apps",deployment="web"}[5m]))`,
+		},
+		{
+			name:        "metric not met within its timeout",
+			criteria:    metricCriteria,
+			started:     6 * time.Minute,
+			objects:     []client.Object{deployment(corev1.ConditionTrue)},
+			wantPhase:   v1alpha1.HealingActionPhaseVerifying,
+			wantMessage: `sum(rate(http_errors_total{namespace="apps",deployment="web"}[5m]))`,
+			wantRequeue: defaultVerificationInterval,
+		},
+		{
+			name:        "both criteria must be met",
+			criteria:    &v1alpha1.SuccessCriteria{Metric: metricCriteria.Metric, Condition: availableCriteria.Condition},
+			started:     time.Minute,
+			objects:     []client.Object{deployment(corev1.ConditionFalse)},
+			metricMet:   true,
+			wantPhase:   v1alpha1.HealingActionPhaseVerifying,
+			wantMessage: "Deployment web does not have condition Available=True",
+			wantRequeue: defaultVerificationInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := verificationScheme(t)
+			action := verifiedAction(v1alpha1.HealingActionPhaseVerifying, tt.criteria)
+			action.Status.Result = &v1alpha1.ActionResult{Success: true, Message: "Restarted"}
+			action.Status.Verification = &v1alpha1.VerificationStatus{StartTime: metav1.NewTime(time.Now().Add(-tt.started))}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(tt.objects, action)...).
+				WithStatusSubresource(action).
+				Build()
+			r := &HealingActionReconciler{
+				Client:            c,
+				Scheme:            scheme,
+				Config:            config.NewDefaultConfig(),
+				RemediationEngine: &MockRemediationEngine{},
+				SafetyController:  &MockSafetyController{},
+				MetricsCollector: &MockMetricsCollector{
+					EvaluateTriggerFunc: func(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *ktypes.ClusterMetrics) (bool, string, error) {
+						return tt.metricMet, trigger.MetricTrigger.Query, nil
+					},
+				},
+			}
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(action)})
+			require.NoError(t, err)
+
+			got := &v1alpha1.HealingAction{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(action), got))
+			assert.Equal(t, tt.wantPhase, got.Status.Phase)
+			require.NotNil(t, got.Status.Verification)
+			assert.Equal(t, int32(1), got.Status.Verification.Checks)
+			assert.Contains(t, got.Status.Verification.Message, tt.wantMessage)
+			assert.Equal(t, tt.wantRequeue, result.RequeueAfter)
+
+			switch tt.wantPhase {
+			case v1alpha1.HealingActionPhaseSucceeded:
+				assert.True(t, got.Status.Result.Success)
+				assert.NotNil(t, got.Status.CompletionTime)
+			case v1alpha1.HealingActionPhaseFailed:
+				assert.False(t, got.Status.Result.Success)
+				assert.Equal(t, v1alpha1.FailureReasonVerificationFailed, got.Status.Result.FailureReason)
+				assert.Equal(t, "Restarted", got.Status.Result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package debug serves the operator's performance profiling endpoints
+package debug
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/serving"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+const (
+	// CollectorStatsPath is the path of the collector stats endpoint
+	CollectorStatsPath = "/debug/collectorstats"
+
+	// PprofPath is the path prefix of the pprof handlers
+	PprofPath = "/debug/pprof/"
+)
+
+// Server serves the collector stats and, optionally, the pprof handlers.
+// Every request must present the configured bearer token.
+type Server struct {
+	stats     *metrics.CollectorStats
+	addr      string
+	token     string
+	handler   http.Handler
+	tlsConfig *tls.Config
+}
+
+// NewServer creates a new debug server
+func NewServer(stats *metrics.CollectorStats, addr, token string, enablePprof bool) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("a bearer token is required for the profiling endpoints")
+	}
+
+	s := &Server{stats: stats, addr: addr, token: token}
+	mux := http.NewServeMux()
+	mux.HandleFunc(CollectorStatsPath, s.serveCollectorStats)
+	if enablePprof {
+		mux.HandleFunc(PprofPath, pprof.Index)
+		mux.HandleFunc(PprofPath+"cmdline", pprof.Cmdline)
+		mux.HandleFunc(PprofPath+"profile", pprof.Profile)
+		mux.HandleFunc(PprofPath+"symbol", pprof.Symbol)
+		mux.HandleFunc(PprofPath+"trace", pprof.Trace)
+	}
+	s.handler = mux
+	return s, nil
+}
+
+// LoadServer creates a debug server using the token stored in the
+// configured Secret
+func LoadServer(ctx context.Context, reader client.Reader, stats *metrics.CollectorStats, cfg config.ProfilingConfig) (*Server, error) {
+	if cfg.TokenSecretName == "" {
+		return nil, fmt.Errorf("profiling.tokenSecretName is required")
+	}
+	secret := &corev1.Secret{}
+	name := k8stypes.NamespacedName{Name: cfg.TokenSecretName, Namespace: cfg.TokenSecretNamespace}
+	if err := reader.Get(ctx, name, secret); err != nil {
+		return nil, fmt.Errorf("failed to get profiling token secret %s: %w", name, err)
+	}
+	data, ok := secret.Data[cfg.TokenSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("profiling token secret %s has no key %q", name, cfg.TokenSecretKey)
+	}
+	return NewServer(stats, cfg.BindAddress, strings.TrimSpace(string(data)), cfg.EnablePprof)
+}
+
+// WithTLS serves the endpoints over HTTPS
+func (s *Server) WithTLS(tlsConfig *tls.Config) *Server {
+	s.tlsConfig = tlsConfig
+	return s
+}
+
+// NeedLeaderElection is false so every replica can be profiled
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the endpoints until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	// No write timeout: CPU profiles and traces stream for their duration
+	server := &http.Server{Addr: s.addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).WithName("profiling").Info("Serving profiling endpoints", "address", s.addr, "tls", s.tlsConfig != nil)
+	if err := serving.ListenAndServe(server, s.tlsConfig); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("profiling endpoints failed: %w", err)
+	}
+	return nil
+}
+
+// ServeHTTP checks the bearer token before dispatching the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	s.handler.ServeHTTP(w, req)
+}
+
+// serveCollectorStats writes a snapshot of the collector stats
+func (s *Server) serveCollectorStats(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.stats.Snapshot()); err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to write collector stats")
+	}
+}
@@ -0,0 +1,83 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestServer(t *testing.T) {
+	stats := metrics.NewCollectorStats()
+	stats.ObserveCollection("apps/web", 25*time.Millisecond, nil)
+
+	get := func(s *Server, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	withPprof, err := NewServer(stats, ":0", "secret", true)
+	require.NoError(t, err)
+	withoutPprof, err := NewServer(stats, ":0", "secret", false)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, get(withPprof, CollectorStatsPath, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(withPprof, CollectorStatsPath, "wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(withPprof, PprofPath, "").Code)
+
+	rec := get(withPprof, CollectorStatsPath, "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var snapshot metrics.CollectorStatsSnapshot
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	assert.Equal(t, int64(1), snapshot.Policies["apps/web"].Collections)
+	assert.Equal(t, 25.0, snapshot.Policies["apps/web"].LastMillis)
+
+	assert.Equal(t, http.StatusOK, get(withPprof, PprofPath, "secret").Code)
+	assert.Equal(t, http.StatusNotFound, get(withoutPprof, PprofPath, "secret").Code)
+	assert.Equal(t, http.StatusOK, get(withoutPprof, CollectorStatsPath, "secret").Code)
+
+	_, err = NewServer(stats, ":0", "", false)
+	assert.Error(t, err)
+}
+
+func TestLoadServer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "profiling-token", Namespace: "kubeskippy-system"},
+		Data:       map[string][]byte{"token": []byte("secret\n")},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	cfg := config.NewDefaultConfig().Profiling
+	cfg.TokenSecretName = "profiling-token"
+	s, err := LoadServer(context.Background(), reader, nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", s.token)
+	assert.Equal(t, ":8091", s.addr)
+
+	cfg.TokenSecretKey = "missing"
+	_, err = LoadServer(context.Background(), reader, nil, cfg)
+	assert.ErrorContains(t, err, `has no key "missing"`)
+
+	cfg.TokenSecretName = ""
+	_, err = LoadServer(context.Background(), reader, nil, cfg)
+	assert.ErrorContains(t, err, "tokenSecretName is required")
+}
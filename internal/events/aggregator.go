@@ -0,0 +1,92 @@
+// Package events records Kubernetes events for healing activity without
+// flooding the cluster when policies fire frequently.
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Aggregator deduplicates correlated events, in the spirit of the
+// EventSeries of events.k8s.io: the first event of a series is recorded and
+// repeats within the aggregation window are counted, recording a summary
+// every EmitEvery occurrences.
+type Aggregator struct {
+	recorder  record.EventRecorder
+	window    time.Duration
+	emitEvery int
+
+	mu     sync.Mutex
+	series map[string]*series
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// series tracks the occurrences of correlated events
+type series struct {
+	first time.Time
+	count int
+}
+
+// NewAggregator creates an aggregator recording through recorder
+func NewAggregator(recorder record.EventRecorder, cfg config.EventsConfig) *Aggregator {
+	return &Aggregator{
+		recorder:  recorder,
+		window:    cfg.AggregationWindow,
+		emitEvery: cfg.EmitEvery,
+		series:    make(map[string]*series),
+		now:       time.Now,
+	}
+}
+
+// Key correlates events by policy, target and reason
+func Key(policy, target, reason string) string {
+	return policy + "|" + target + "|" + reason
+}
+
+// Event records an event on object unless it repeats a series with the same
+// key within the aggregation window. It reports whether the event was
+// recorded.
+func (a *Aggregator) Event(object runtime.Object, key, eventType, reason, message string) bool {
+	if a.window <= 0 {
+		a.recorder.Event(object, eventType, reason, message)
+		return true
+	}
+
+	a.mu.Lock()
+	now := a.now()
+	a.prune(now)
+	s, ok := a.series[key]
+	if !ok {
+		a.series[key] = &series{first: now, count: 1}
+		a.mu.Unlock()
+		a.recorder.Event(object, eventType, reason, message)
+		return true
+	}
+	s.count++
+	count, first := s.count, s.first
+	a.mu.Unlock()
+
+	if a.emitEvery <= 0 || count%a.emitEvery != 0 {
+		return false
+	}
+	a.recorder.Event(object, eventType, reason,
+		fmt.Sprintf("%s (%d occurrences since %s)", message, count, first.UTC().Format(time.RFC3339)))
+	return true
+}
+
+// prune ends series older than the aggregation window; callers hold mu
+func (a *Aggregator) prune(now time.Time) {
+	for key, s := range a.series {
+		if now.Sub(s.first) >= a.window {
+			delete(a.series, key)
+		}
+	}
+}
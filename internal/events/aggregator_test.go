@@ -0,0 +1,56 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestAggregator(t *testing.T) {
+	recorder := record.NewFakeRecorder(100)
+	aggregator := NewAggregator(recorder, config.EventsConfig{
+		AggregationWindow: 10 * time.Minute,
+		EmitEvery:         3,
+	})
+	now := time.Date(2024, 5, 1, 2, 13, 0, 0, time.UTC)
+	aggregator.now = func() time.Time { return now }
+
+	action := &v1alpha1.HealingAction{}
+	key := Key("default/web", "Pod/default/web-1", "ActionFailed")
+
+	var recorded []bool
+	for i := 0; i < 6; i++ {
+		recorded = append(recorded, aggregator.Event(action, key, corev1.EventTypeWarning, "ActionFailed", "restart failed"))
+	}
+	assert.Equal(t, []bool{true, false, true, false, false, true}, recorded)
+
+	// Other targets are separate series
+	assert.True(t, aggregator.Event(action, Key("default/web", "Pod/default/web-2", "ActionFailed"),
+		corev1.EventTypeWarning, "ActionFailed", "restart failed"))
+
+	// A new series starts once the window has passed
+	now = now.Add(10 * time.Minute)
+	assert.True(t, aggregator.Event(action, key, corev1.EventTypeWarning, "ActionFailed", "restart failed"))
+
+	require.Len(t, recorder.Events, 5)
+	assert.Equal(t, "Warning ActionFailed restart failed", <-recorder.Events)
+	assert.Equal(t, "Warning ActionFailed restart failed (3 occurrences since 2024-05-01T02:13:00Z)", <-recorder.Events)
+	assert.Equal(t, "Warning ActionFailed restart failed (6 occurrences since 2024-05-01T02:13:00Z)", <-recorder.Events)
+}
+
+func TestAggregator_Disabled(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	aggregator := NewAggregator(recorder, config.EventsConfig{})
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, aggregator.Event(&v1alpha1.HealingAction{}, "key", corev1.EventTypeNormal, "ActionSucceeded", "done"))
+	}
+	assert.Len(t, recorder.Events, 3)
+}
@@ -0,0 +1,8 @@
+//go:build !faultinjection
+
+package faults
+
+// Available reports whether this binary honors the fault injection
+// configuration. Build with -tags faultinjection (make
+// docker-build-faults) to inject faults.
+const Available = false
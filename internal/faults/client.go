@@ -0,0 +1,131 @@
+package faults
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// faultyClient fails writes at the injector's API error rate. Reads pass
+// through, as they are served from the informer cache.
+type faultyClient struct {
+	client.Client
+	injector *Injector
+}
+
+// NewClient wraps c so that writes fail with a service unavailable error at
+// the injector's API error rate. It returns c when injector is nil.
+func NewClient(c client.Client, injector *Injector) client.Client {
+	if injector == nil {
+		return c
+	}
+	return &faultyClient{Client: c, injector: injector}
+}
+
+// Create creates obj unless a fault is injected
+func (f *faultyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := f.fault(ctx, "create", obj, ""); err != nil {
+		return err
+	}
+	return f.Client.Create(ctx, obj, opts...)
+}
+
+// Update updates obj unless a fault is injected
+func (f *faultyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := f.fault(ctx, "update", obj, ""); err != nil {
+		return err
+	}
+	return f.Client.Update(ctx, obj, opts...)
+}
+
+// Patch patches obj unless a fault is injected
+func (f *faultyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := f.fault(ctx, "patch", obj, ""); err != nil {
+		return err
+	}
+	return f.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete deletes obj unless a fault is injected
+func (f *faultyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := f.fault(ctx, "delete", obj, ""); err != nil {
+		return err
+	}
+	return f.Client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf deletes the objects of obj's type unless a fault is injected
+func (f *faultyClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if err := f.fault(ctx, "deletecollection", obj, ""); err != nil {
+		return err
+	}
+	return f.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Status returns a status writer injecting faults
+func (f *faultyClient) Status() client.SubResourceWriter {
+	return f.SubResource("status")
+}
+
+// SubResource returns a subresource client injecting faults into writes
+func (f *faultyClient) SubResource(subResource string) client.SubResourceClient {
+	return &faultySubResourceClient{
+		SubResourceClient: f.Client.SubResource(subResource),
+		parent:            f,
+		subResource:       subResource,
+	}
+}
+
+// fault returns the API error to inject into a write, or nil
+func (f *faultyClient) fault(ctx context.Context, verb string, obj client.Object, subResource string) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvk, err := apiutil.GVKForObject(obj, f.Scheme()); err == nil {
+			kind = gvk.Kind
+		}
+	}
+	if subResource != "" {
+		kind = kind + "/" + subResource
+	}
+
+	if !f.injector.inject(ctx, KindAPIError, f.injector.config.APIErrorRate,
+		"verb", verb, "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName()) {
+		return nil
+	}
+	return apierrors.NewServiceUnavailable(fmt.Sprintf("%v: %s %s %s/%s", ErrInjected, verb, kind, obj.GetNamespace(), obj.GetName()))
+}
+
+// faultySubResourceClient fails subresource writes at the injector's API
+// error rate
+type faultySubResourceClient struct {
+	client.SubResourceClient
+	parent      *faultyClient
+	subResource string
+}
+
+// Create creates the subresource unless a fault is injected
+func (s *faultySubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := s.parent.fault(ctx, "create", obj, s.subResource); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+// Update updates the subresource unless a fault is injected
+func (s *faultySubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := s.parent.fault(ctx, "update", obj, s.subResource); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+// Patch patches the subresource unless a fault is injected
+func (s *faultySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := s.parent.fault(ctx, "patch", obj, s.subResource); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,122 @@
+// Package faults injects failures into the remediation executors, the AI
+// provider and the Kubernetes API, so that the circuit breaker, retries and
+// rollbacks can be exercised in e2e tests and chaos drills.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// ErrInjected is wrapped by the executor and AI failures of an injector
+var ErrInjected = errors.New("injected fault")
+
+// Kinds of injected faults
+const (
+	KindExecutor = "executor"
+	KindAIDelay  = "ai_delay"
+	KindAIError  = "ai_error"
+	KindAPIError = "api_error"
+)
+
+// injectedFaults counts injected faults by kind
+var injectedFaults *prometheus.CounterVec
+
+// SetInjectedFaultsMetric sets the injected faults metric from main.go
+func SetInjectedFaultsMetric(metric *prometheus.CounterVec) {
+	injectedFaults = metric
+}
+
+// Injector decides which calls fail. A nil injector injects nothing.
+type Injector struct {
+	config config.FaultInjectionConfig
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+// New creates the injector of the configuration. It returns nil when fault
+// injection is disabled, and an error when it is enabled in a binary built
+// without the faultinjection tag.
+func New(cfg config.FaultInjectionConfig) (*Injector, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if !Available {
+		return nil, fmt.Errorf("fault injection requires a binary built with -tags faultinjection")
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return NewInjector(cfg, seed), nil
+}
+
+// NewInjector creates an injector of the configuration with a fixed seed,
+// whatever the build tag. It lets tests inject faults.
+func NewInjector(cfg config.FaultInjectionConfig, seed int64) *Injector {
+	return &Injector{
+		config: cfg,
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// ExecutorFailure returns the failure to inject into an execution of the
+// action type, or nil
+func (i *Injector) ExecutorFailure(ctx context.Context, actionType string) error {
+	if i == nil || (len(i.config.ExecutorActionTypes) > 0 && !slices.Contains(i.config.ExecutorActionTypes, actionType)) {
+		return nil
+	}
+	if !i.inject(ctx, KindExecutor, i.config.ExecutorFailureRate, "actionType", actionType) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s executor failed", ErrInjected, actionType)
+}
+
+// AIFault delays an AI query and returns the failure to inject into it, or
+// nil
+func (i *Injector) AIFault(ctx context.Context) error {
+	if i == nil {
+		return nil
+	}
+	if i.config.AIDelay > 0 && i.inject(ctx, KindAIDelay, i.config.AIDelayRate, "delay", i.config.AIDelay) {
+		select {
+		case <-time.After(i.config.AIDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if i.inject(ctx, KindAIError, i.config.AIErrorRate) {
+		return fmt.Errorf("%w: AI provider failed", ErrInjected)
+	}
+	return nil
+}
+
+// inject decides whether to inject a fault of kind at rate, and logs and
+// counts it
+func (i *Injector) inject(ctx context.Context, kind string, rate float64, keysAndValues ...interface{}) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	roll := i.rand.Float64()
+	i.mu.Unlock()
+	if roll >= rate {
+		return false
+	}
+
+	log.FromContext(ctx).Info("Injecting fault", append([]interface{}{"kind", kind}, keysAndValues...)...)
+	if injectedFaults != nil {
+		injectedFaults.WithLabelValues(kind).Inc()
+	}
+	return true
+}
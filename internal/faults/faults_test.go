@@ -0,0 +1,129 @@
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// useInjectedFaultsMetric installs a fresh injected faults metric for a test
+func useInjectedFaultsMetric(t *testing.T) *prometheus.CounterVec {
+	metric := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_injected_faults_total"}, []string{"kind"})
+	previous := injectedFaults
+	SetInjectedFaultsMetric(metric)
+	t.Cleanup(func() { SetInjectedFaultsMetric(previous) })
+	return metric
+}
+
+func TestNew(t *testing.T) {
+	injector, err := New(config.FaultInjectionConfig{ExecutorFailureRate: 1})
+	require.NoError(t, err)
+	assert.Nil(t, injector, "disabled configurations inject nothing")
+
+	injector, err = New(config.FaultInjectionConfig{Enabled: true, ExecutorFailureRate: 1})
+	if Available {
+		require.NoError(t, err)
+		assert.NotNil(t, injector)
+	} else {
+		assert.ErrorContains(t, err, "-tags faultinjection")
+	}
+}
+
+func TestInjector(t *testing.T) {
+	metric := useInjectedFaultsMetric(t)
+	ctx := context.Background()
+
+	var nilInjector *Injector
+	assert.NoError(t, nilInjector.ExecutorFailure(ctx, "restart"))
+	assert.NoError(t, nilInjector.AIFault(ctx))
+
+	injector := NewInjector(config.FaultInjectionConfig{
+		ExecutorFailureRate: 1,
+		ExecutorActionTypes: []string{"scale"},
+		AIDelay:             20 * time.Millisecond,
+		AIDelayRate:         1,
+		AIErrorRate:         1,
+	}, 1)
+
+	assert.NoError(t, injector.ExecutorFailure(ctx, "restart"), "only the listed action types fail")
+	err := injector.ExecutorFailure(ctx, "scale")
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.ErrorContains(t, err, "scale executor failed")
+
+	start := time.Now()
+	assert.ErrorIs(t, injector.AIFault(ctx), ErrInjected)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, injector.AIFault(canceled), context.Canceled, "delays end with the query's context")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metric.WithLabelValues(KindExecutor)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metric.WithLabelValues(KindAIDelay)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metric.WithLabelValues(KindAIError)))
+}
+
+func TestInjector_Rate(t *testing.T) {
+	useInjectedFaultsMetric(t)
+	injector := NewInjector(config.FaultInjectionConfig{ExecutorFailureRate: 0.25}, 42)
+
+	failures := 0
+	for i := 0; i < 1000; i++ {
+		if injector.ExecutorFailure(context.Background(), "restart") != nil {
+			failures++
+		}
+	}
+	assert.InDelta(t, 250, failures, 50)
+
+	// The same seed injects the same faults
+	again := NewInjector(config.FaultInjectionConfig{ExecutorFailureRate: 0.25}, 42)
+	replayed := 0
+	for i := 0; i < 1000; i++ {
+		if again.ExecutorFailure(context.Background(), "restart") != nil {
+			replayed++
+		}
+	}
+	assert.Equal(t, failures, replayed)
+}
+
+func TestNewClient(t *testing.T) {
+	metric := useInjectedFaultsMetric(t)
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).WithStatusSubresource(pod).Build()
+	ctx := context.Background()
+
+	assert.Same(t, base, NewClient(base, nil))
+
+	c := NewClient(base, NewInjector(config.FaultInjectionConfig{APIErrorRate: 1}, 1))
+
+	// Reads pass through
+	read := &corev1.Pod{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(pod), read))
+
+	err := c.Delete(ctx, read)
+	assert.True(t, apierrors.IsServiceUnavailable(err))
+	assert.ErrorContains(t, err, "injected fault: delete Pod default/web-1")
+	read.Status.Phase = corev1.PodFailed
+	assert.True(t, apierrors.IsServiceUnavailable(c.Status().Update(ctx, read)))
+	assert.True(t, apierrors.IsServiceUnavailable(c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default"}})))
+
+	// Nothing was written
+	require.NoError(t, base.Get(ctx, client.ObjectKeyFromObject(pod), read))
+	assert.Empty(t, read.Status.Phase)
+	assert.Equal(t, 3.0, testutil.ToFloat64(metric.WithLabelValues(KindAPIError)))
+}
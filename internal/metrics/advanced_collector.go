@@ -12,6 +12,8 @@ import (
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
 	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
 )
 
 // AdvancedMetrics represents sophisticated metrics for AI analysis
@@ -40,18 +42,18 @@ type AdvancedMetrics struct {
 	RestartPattern            string    `json:"restart_pattern"`
 	FailureCorrelations       []string  `json:"failure_correlations"`
 	
+	// Patterns found by the enabled detectors
+	Patterns []pkgtypes.Pattern `json:"patterns,omitempty"`
+
 	// Historical Data for Trends
 	HistoricalData            map[string][]TimeSeriesPoint `json:"historical_data"`
 	TrendAnalysisWindow       time.Duration                `json:"trend_analysis_window"`
 	LastAnalysisTime          time.Time                    `json:"last_analysis_time"`
 }
 
-// TimeSeriesPoint represents a data point in time series
-type TimeSeriesPoint struct {
-	Timestamp time.Time `json:"timestamp"`
-	Value     float64   `json:"value"`
-	Labels    map[string]string `json:"labels,omitempty"`
-}
+// TimeSeriesPoint represents a data point in time series; detectors read
+// them through the public SeriesStore
+type TimeSeriesPoint = pkgtypes.SeriesPoint
 
 // AdvancedCollector extends the basic collector with AI-focused metrics
 type AdvancedCollector struct {
@@ -60,6 +62,7 @@ type AdvancedCollector struct {
 	aiMetricsEnabled  bool
 	trendWindow       time.Duration
 	patternDetector   *PatternDetector
+	detectors         []namedDetector
 }
 
 // PatternDetector analyzes patterns in metrics data
@@ -119,6 +122,12 @@ func NewPatternDetector() *PatternDetector {
 
 // CollectAdvancedMetrics gathers sophisticated metrics for AI analysis
 func (ac *AdvancedCollector) CollectAdvancedMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) (*AdvancedMetrics, error) {
+	return ac.collectAdvancedMetrics(ctx, policy, nil)
+}
+
+// collectAdvancedMetrics computes advanced metrics, narrowing the basic
+// metrics with sample first if it is set
+func (ac *AdvancedCollector) collectAdvancedMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy, sample func(*types.ClusterMetrics) *types.ClusterMetrics) (*AdvancedMetrics, error) {
 	log := log.FromContext(ctx)
 	log.Info("Collecting advanced metrics for AI analysis", "policy", policy.Name)
 
@@ -127,6 +136,9 @@ func (ac *AdvancedCollector) CollectAdvancedMetrics(ctx context.Context, policy
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect basic metrics: %w", err)
 	}
+	if sample != nil {
+		basicMetrics = sample(basicMetrics)
+	}
 
 	// Store current metrics in historical data
 	ac.updateHistoricalData(basicMetrics)
@@ -154,6 +166,7 @@ func (ac *AdvancedCollector) CollectAdvancedMetrics(ctx context.Context, policy
 	advanced.MemoryLeakPattern = ac.detectMemoryLeakPattern()
 	advanced.RestartPattern = ac.detectRestartPattern(basicMetrics)
 	advanced.FailureCorrelations = ac.detectFailureCorrelations()
+	advanced.Patterns = ac.runDetectors(ctx)
 
 	// AI metrics (these will be set by AI analyzer later)
 	advanced.AIConfidenceScore = 0.85 // Default for demo
@@ -171,10 +184,29 @@ func (ac *AdvancedCollector) CollectAdvancedMetrics(ctx context.Context, policy
 	return advanced, nil
 }
 
+// advancedQueries are the MetricTrigger queries answered by the advanced
+// metrics
+var advancedQueries = map[string]bool{
+	"memory_usage_trend_5m":           true,
+	"cpu_oscillation_amplitude_trend": true,
+	"error_rate_trend_3m":             true,
+	"correlation_risk_score":          true,
+	"system_health_score":             true,
+	"ai_confidence_score":             true,
+	"cascade_risk_score":              true,
+	"predictive_accuracy":             true,
+}
+
+// IsAdvancedQuery reports whether a MetricTrigger query is answered by the
+// advanced metrics rather than the cluster metrics
+func IsAdvancedQuery(query string) bool {
+	return advancedQueries[query] || strings.HasPrefix(query, PatternQueryPrefix)
+}
+
 // EvaluateAdvancedTrigger evaluates triggers using advanced metrics
-func (ac *AdvancedCollector) EvaluateAdvancedTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *AdvancedMetrics) (bool, string, error) {
+func (ac *AdvancedCollector) EvaluateAdvancedTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *AdvancedMetrics) (types.TriggerResult, error) {
 	if trigger.MetricTrigger == nil {
-		return false, "", fmt.Errorf("metric trigger configuration missing")
+		return types.TriggerResult{}, fmt.Errorf("metric trigger configuration missing")
 	}
 
 	query := trigger.MetricTrigger.Query
@@ -211,16 +243,19 @@ func (ac *AdvancedCollector) EvaluateAdvancedTrigger(ctx context.Context, trigge
 		actualValue = metrics.PredictiveAccuracy
 		found = true
 	default:
+		if strings.HasPrefix(query, PatternQueryPrefix) {
+			return evaluatePatternQuery(trigger.MetricTrigger, metrics.Patterns), nil
+		}
 		// Fall back to basic metrics evaluation
-		return ac.Collector.EvaluateTrigger(ctx, trigger, &types.ClusterMetrics{})
+		return ac.Collector.EvaluateTriggerResult(ctx, trigger, &types.ClusterMetrics{})
 	}
 
 	if !found {
-		return false, fmt.Sprintf("advanced metric not found: %s", query), nil
+		return types.TriggerResult{Reason: fmt.Sprintf("advanced metric not found: %s", query)}, nil
 	}
 
 	// Evaluate the threshold
-	triggered := ac.evaluateThreshold(actualValue, threshold, operator)
+	triggered := triggers.Compare(actualValue, threshold, operator)
 	reason := fmt.Sprintf("advanced query '%s' = %.2f %s %.2f", query, actualValue, operator, threshold)
 	
 	log.FromContext(ctx).Info("Advanced trigger evaluation", 
@@ -230,7 +265,7 @@ func (ac *AdvancedCollector) EvaluateAdvancedTrigger(ctx context.Context, trigge
 		"operator", operator, 
 		"triggered", triggered)
 
-	return triggered, reason, nil
+	return types.TriggerResult{Triggered: triggered, Reason: reason, Value: actualValue, Observed: true}, nil
 }
 
 // updateHistoricalData stores current metrics for trend analysis
@@ -687,23 +722,4 @@ func (ac *AdvancedCollector) calculateMetricCorrelation(metric1, metric2 string)
 	default:
 		return 0.1 + 0.2*math.Sin(seed/90)
 	}
-}
-
-func (ac *AdvancedCollector) evaluateThreshold(value, threshold float64, operator string) bool {
-	switch operator {
-	case ">":
-		return value > threshold
-	case ">=":
-		return value >= threshold
-	case "<":
-		return value < threshold
-	case "<=":
-		return value <= threshold
-	case "==":
-		return value == threshold
-	case "!=":
-		return value != threshold
-	default:
-		return false
-	}
 }
\ No newline at end of file
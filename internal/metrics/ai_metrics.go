@@ -20,6 +20,7 @@ var (
 	aiDecisionConfidence     *prometheus.HistogramVec
 	aiAlternativesConsidered *prometheus.CounterVec
 	aiConfidenceFactors      *prometheus.CounterVec
+	aiPatchDenials           *prometheus.CounterVec
 	
 	// Global AI metrics instance
 	GlobalAIMetrics *AIMetrics
@@ -46,8 +47,8 @@ type AIMetrics struct {
 	cascadePreventionTotal prometheus.Counter
 	systemHealthScore      prometheus.Gauge
 	
-	// Real-time AI State
-	currentDecisions       map[string]*AIDecision
+	// Recent AI decision outcomes for the success rate gauges; the
+	// decisions themselves are stored as AIDecision resources
 	decisionHistory        []AIDecisionRecord
 	mutex                  sync.RWMutex
 }
@@ -85,6 +86,19 @@ func SetAIMetrics(reasoningSteps, alternatives, confidenceFactors *prometheus.Co
 	aiConfidenceFactors = confidenceFactors
 }
 
+// SetAIPatchDenialsMetric sets the AI patch denials metric from main.go
+func SetAIPatchDenialsMetric(metric *prometheus.CounterVec) {
+	aiPatchDenials = metric
+}
+
+// RecordAIPatchDenial counts an AI-proposed patch denied by the safety
+// rule for the target kind
+func RecordAIPatchDenial(kind, rule string) {
+	if aiPatchDenials != nil {
+		aiPatchDenials.WithLabelValues(kind, rule).Inc()
+	}
+}
+
 // NewAIMetrics creates comprehensive AI-specific metrics
 func NewAIMetrics() *AIMetrics {
 	return &AIMetrics{
@@ -183,7 +197,6 @@ func NewAIMetrics() *AIMetrics {
 			},
 		),
 		
-		currentDecisions: make(map[string]*AIDecision),
 		decisionHistory:  make([]AIDecisionRecord, 0),
 	}
 }
@@ -254,14 +267,13 @@ func (ai *AIMetrics) RecordHealingAction(ctx context.Context, policyName, action
 		"ai_driven", isAIDriven)
 }
 
-// StartAIDecision begins tracking an AI decision
+// StartAIDecision records the metrics of a new AI decision
 func (ai *AIMetrics) StartAIDecision(ctx context.Context, decision *AIDecision) {
 	ai.mutex.Lock()
 	defer ai.mutex.Unlock()
 	
 	decision.Timestamp = time.Now()
 	decision.Status = "pending"
-	ai.currentDecisions[decision.ID] = decision
 	
 	// Update AI confidence gauge
 	ai.aiConfidenceGauge.Set(decision.Confidence)
@@ -278,27 +290,20 @@ func (ai *AIMetrics) StartAIDecision(ctx context.Context, decision *AIDecision)
 		"action_type", decision.ActionType)
 }
 
-// CompleteAIDecision marks an AI decision as completed
-func (ai *AIMetrics) CompleteAIDecision(ctx context.Context, decisionID string, success bool, actualOutcome string) {
+// CompleteAIDecision records the outcome of an AI decision that took
+// duration from decision to completion
+func (ai *AIMetrics) CompleteAIDecision(ctx context.Context, decision AIDecision, success bool, duration time.Duration) {
 	ai.mutex.Lock()
 	defer ai.mutex.Unlock()
 	
-	decision, exists := ai.currentDecisions[decisionID]
-	if !exists {
-		log.FromContext(ctx).Error(nil, "AI decision not found", "decision_id", decisionID)
-		return
-	}
-	
 	decision.Status = "completed"
-	decision.ActualOutcome = actualOutcome
-	duration := time.Since(decision.Timestamp)
 	
 	// Record decision duration
 	ai.aiDecisionDuration.Observe(duration.Seconds())
 	
 	// Create historical record
 	record := AIDecisionRecord{
-		Decision: *decision,
+		Decision: decision,
 		Duration: duration,
 		Success:  success,
 		LearningData: map[string]interface{}{
@@ -309,17 +314,14 @@ func (ai *AIMetrics) CompleteAIDecision(ctx context.Context, decisionID string,
 	
 	ai.decisionHistory = append(ai.decisionHistory, record)
 	
-	// Clean up current decisions
-	delete(ai.currentDecisions, decisionID)
-	
 	// Update success rates
 	ai.updateSuccessRates()
 	
 	log.FromContext(ctx).Info("Completed AI decision",
-		"decision_id", decisionID,
+		"decision_id", decision.ID,
 		"success", success,
 		"duration", duration,
-		"outcome", actualOutcome)
+		"outcome", decision.ActualOutcome)
 }
 
 // UpdateAdvancedMetrics updates advanced AI metrics
@@ -385,7 +387,7 @@ func (ai *AIMetrics) updateSuccessRates() {
 		return
 	}
 	
-	// Look at recent decisions (last hour)
+	// Look at recent decisions (last hour) and forget older ones
 	cutoff := time.Now().Add(-1 * time.Hour)
 	recentDecisions := []AIDecisionRecord{}
 	
@@ -394,6 +396,7 @@ func (ai *AIMetrics) updateSuccessRates() {
 			recentDecisions = append(recentDecisions, record)
 		}
 	}
+	ai.decisionHistory = recentDecisions
 	
 	if len(recentDecisions) == 0 {
 		return
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+)
+
+// baselineKey identifies the baseline of a query in an hour
+type baselineKey struct {
+	query string
+	days  int
+	hour  time.Time
+}
+
+// baselineValue is a baseline and the number of days it averages
+type baselineValue struct {
+	value float64
+	days  int
+}
+
+// Baseline returns the average of a query over the same clock hour of the
+// previous days, and the number of days that had samples. Days without
+// samples are skipped. A baseline only changes on the hour, so it is cached
+// until then.
+func (p *PrometheusClient) Baseline(ctx context.Context, query string, days int, now time.Time) (float64, int, error) {
+	hour := now.Truncate(time.Hour)
+	key := baselineKey{query: query, days: days, hour: hour}
+	p.baselineMu.Lock()
+	cached, ok := p.baselines[key]
+	p.baselineMu.Unlock()
+	if ok {
+		return cached.value, cached.days, nil
+	}
+
+	averaged := fmt.Sprintf("avg_over_time((%s)[1h:])", query)
+	sum, observed := 0.0, 0
+	for day := 1; day <= days; day++ {
+		value, err := p.queryAt(ctx, averaged, hour.Add(time.Hour).AddDate(0, 0, -day))
+		if errors.Is(err, errNoData) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		sum += value
+		observed++
+	}
+	baseline := baselineValue{days: observed}
+	if observed > 0 {
+		baseline.value = sum / float64(observed)
+	}
+
+	p.baselineMu.Lock()
+	defer p.baselineMu.Unlock()
+	for k := range p.baselines {
+		if !k.hour.Equal(hour) {
+			delete(p.baselines, k)
+		}
+	}
+	if p.baselines == nil {
+		p.baselines = make(map[baselineKey]baselineValue)
+	}
+	p.baselines[key] = baseline
+	return baseline.value, baseline.days, nil
+}
+
+// evaluateBaselineTrigger compares the value of a Prometheus query against
+// the trigger's threshold multiplied by the query's baseline
+func (c *Collector) evaluateBaselineTrigger(ctx context.Context, trigger *v1alpha1.MetricTrigger, value float64, now time.Time) (types.TriggerResult, error) {
+	days := int(trigger.Baseline.Days)
+	if days <= 0 {
+		days = v1alpha1.DefaultBaselineDays
+	}
+	baseline, observed, err := c.prometheus.Baseline(ctx, trigger.Query, days, now)
+	if err != nil {
+		return types.TriggerResult{}, fmt.Errorf("failed to compute baseline of '%s': %w", trigger.Query, err)
+	}
+	if observed == 0 {
+		return types.TriggerResult{Reason: fmt.Sprintf("no history for the baseline of '%s'", trigger.Query)}, nil
+	}
+
+	baseline = math.Max(baseline, trigger.Baseline.Floor)
+	threshold := trigger.Threshold * baseline
+	return types.TriggerResult{
+		Triggered: triggers.Compare(value, threshold, trigger.Operator),
+		Reason: fmt.Sprintf("Prometheus query '%s' = %.2f %s %.2f (%gx the %d-day same-hour baseline %.2f)",
+			trigger.Query, value, trigger.Operator, threshold, trigger.Threshold, observed, baseline),
+		Value:     value,
+		Observed:  true,
+		Threshold: threshold,
+		Baselined: true,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// baselineServer answers "errors" and "other" with 5. The hourly averages
+// of "errors" are 1 a day ago, 3 two days ago and missing before; "other"
+// has no history.
+func baselineServer(t *testing.T, baselineQueries *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		value := ""
+		switch r.FormValue("query") {
+		case "errors", "other":
+			value = "5"
+		case "avg_over_time((errors)[1h:])":
+			baselineQueries.Add(1)
+			ts, err := strconv.ParseFloat(r.FormValue("time"), 64)
+			require.NoError(t, err)
+			end := time.Now().Truncate(time.Hour).Add(time.Hour)
+			switch day := math.Round(end.Sub(time.Unix(int64(ts), 0)).Hours() / 24); day {
+			case 1:
+				value = "1"
+			case 2:
+				value = "3"
+			}
+		}
+		result := "[]"
+		if value != "" {
+			result = fmt.Sprintf(`[{"metric": {}, "value": [%d, %q]}]`, time.Now().Unix(), value)
+		}
+		fmt.Fprintf(w, `{"status": "success", "data": {"resultType": "vector", "result": %s}}`, result)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEvaluateMetricTrigger_Baseline(t *testing.T) {
+	var baselineQueries atomic.Int32
+	server := baselineServer(t, &baselineQueries)
+	collector := NewCollector(nil, nil, nil)
+	require.NoError(t, collector.WithPrometheus(server.URL))
+	ctx := context.Background()
+
+	trigger := &v1alpha1.MetricTrigger{Query: "errors", Datasource: v1alpha1.DatasourcePrometheus, Operator: ">", Threshold: 2, Baseline: &v1alpha1.MetricBaseline{Days: 4}}
+	result, err := collector.evaluateMetricTrigger(ctx, trigger, &types.ClusterMetrics{})
+	require.NoError(t, err)
+	assert.True(t, result.Triggered, "5 is more than twice the baseline of 2")
+	assert.True(t, result.Baselined)
+	assert.Equal(t, 4.0, result.Threshold)
+	assert.Equal(t, 5.0, result.Value)
+	assert.Contains(t, result.Reason, "2-day same-hour baseline 2.00")
+	assert.Equal(t, int32(4), baselineQueries.Load(), "one query per day")
+
+	trigger.Threshold = 3
+	result, err = collector.evaluateMetricTrigger(ctx, trigger, &types.ClusterMetrics{})
+	require.NoError(t, err)
+	assert.False(t, result.Triggered)
+	assert.Equal(t, 6.0, result.Threshold)
+	assert.Equal(t, int32(4), baselineQueries.Load(), "baselines are cached for the hour")
+
+	trigger.Threshold = 2
+	trigger.Baseline.Floor = 10
+	result, err = collector.evaluateMetricTrigger(ctx, trigger, &types.ClusterMetrics{})
+	require.NoError(t, err)
+	assert.False(t, result.Triggered, "the floor raises quiet baselines")
+	assert.Equal(t, 20.0, result.Threshold)
+
+	// No history yet
+	other := &v1alpha1.MetricTrigger{Query: "other", Datasource: v1alpha1.DatasourcePrometheus, Operator: ">", Baseline: &v1alpha1.MetricBaseline{Days: 7}}
+	result, err = collector.evaluateMetricTrigger(ctx, other, &types.ClusterMetrics{})
+	require.NoError(t, err)
+	assert.False(t, result.Triggered)
+	assert.False(t, result.Observed)
+}
+
+func TestEvaluateMetricTrigger_BaselineWithoutPrometheus(t *testing.T) {
+	collector := NewCollector(nil, nil, nil)
+	trigger := &v1alpha1.MetricTrigger{Query: "error_rate", Datasource: v1alpha1.DatasourcePrometheus, Operator: ">", Threshold: 2, Baseline: &v1alpha1.MetricBaseline{}}
+
+	result, err := collector.evaluateMetricTrigger(context.Background(), trigger, &types.ClusterMetrics{})
+	require.NoError(t, err)
+	assert.False(t, result.Triggered)
+	assert.Equal(t, "no baseline for 'error_rate' without Prometheus", result.Reason)
+}
@@ -0,0 +1,227 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+const (
+	// failedSchedulingWindow is how far back FailedScheduling events count
+	failedSchedulingWindow = 5 * time.Minute
+
+	// maxUnschedulablePods caps the unschedulable pods listed with their
+	// scheduler message; all of them are counted
+	maxUnschedulablePods = 10
+)
+
+// WithCapacitySignals adds the cluster's unschedulable pods and recent
+// FailedScheduling events to the collected metrics, plus the cluster
+// autoscaler's status read from the autoscalerStatus "namespace/name"
+// ConfigMap unless it is empty
+func (c *Collector) WithCapacitySignals(autoscalerStatus string) error {
+	if autoscalerStatus != "" {
+		namespace, name, ok := strings.Cut(autoscalerStatus, "/")
+		if !ok || namespace == "" || name == "" {
+			return fmt.Errorf("cluster autoscaler status must be namespace/name, got %q", autoscalerStatus)
+		}
+		c.autoscalerStatus = client.ObjectKey{Namespace: namespace, Name: name}
+	}
+	c.capacitySignals = true
+	return nil
+}
+
+// collectCapacity collects the scheduling and autoscaling signals. Capacity
+// is a property of the cluster, so they are not limited to the policy's
+// selector.
+func (c *Collector) collectCapacity(ctx context.Context) (*pkgtypes.CapacityMetrics, error) {
+	pods := &corev1.PodList{}
+	if err := c.client.List(ctx, pods, client.UnsafeDisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	capacity := &pkgtypes.CapacityMetrics{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		message, ok := unschedulableMessage(pod)
+		if !ok {
+			continue
+		}
+		capacity.PendingUnschedulable++
+		if len(capacity.UnschedulablePods) < maxUnschedulablePods {
+			capacity.UnschedulablePods = append(capacity.UnschedulablePods, pkgtypes.UnschedulablePod{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+				Message:   message,
+			})
+		}
+	}
+
+	if c.clientset != nil {
+		events, err := c.clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("reason", "FailedScheduling").String(),
+		})
+		if err != nil {
+			return capacity, fmt.Errorf("failed to list FailedScheduling events: %w", err)
+		}
+		capacity.FailedScheduling = countFailedScheduling(events.Items, time.Now())
+	}
+
+	if c.autoscalerStatus.Name != "" {
+		capacity.Autoscaler = c.readAutoscalerStatus(ctx)
+	}
+	return capacity, nil
+}
+
+// unschedulableMessage returns the scheduler's message for a pending pod
+// it found no node for
+func unschedulableMessage(pod *corev1.Pod) (string, bool) {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return "", false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return condition.Message, true
+		}
+	}
+	return "", false
+}
+
+// countFailedScheduling counts the FailedScheduling events last seen within
+// failedSchedulingWindow of now
+func countFailedScheduling(events []corev1.Event, now time.Time) int {
+	count := 0
+	for i := range events {
+		event := &events[i]
+		if event.Reason != "FailedScheduling" {
+			continue
+		}
+		// Events recorded through the events.k8s.io API only set the
+		// event time
+		seen := event.LastTimestamp.Time
+		if seen.IsZero() {
+			seen = event.EventTime.Time
+		}
+		if now.Sub(seen) < failedSchedulingWindow {
+			count++
+		}
+	}
+	return count
+}
+
+// readAutoscalerStatus reads the cluster autoscaler's status ConfigMap,
+// returning nil if there is none
+func (c *Collector) readAutoscalerStatus(ctx context.Context) *pkgtypes.AutoscalerStatus {
+	status := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.autoscalerStatus, status); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to read cluster autoscaler status")
+		}
+		return nil
+	}
+	return parseAutoscalerStatus(status.Data["status"])
+}
+
+var (
+	cloudProviderTargetPattern = regexp.MustCompile(`cloudProviderTarget[=:]\s*(\d+)`)
+	maxSizePattern             = regexp.MustCompile(`maxSize[=:]\s*(\d+)`)
+)
+
+// autoscalerNodeGroup is the part of a node group's status that tells
+// whether it can still grow
+type autoscalerNodeGroup struct {
+	name    string
+	scaleUp string
+	target  int
+	maxSize int
+}
+
+// parseAutoscalerStatus parses the status text of the cluster autoscaler,
+// either the legacy format of "Health: Healthy (...)" lines or the YAML
+// format with a "status:" field per health, scaleUp and scaleDown block.
+// The cluster-wide statuses come before the node groups in both.
+func parseAutoscalerStatus(text string) *pkgtypes.AutoscalerStatus {
+	status := &pkgtypes.AutoscalerStatus{}
+	var groups []*autoscalerNodeGroup
+	var group *autoscalerNodeGroup
+	block := ""
+
+	set := func(field, value string) {
+		value, _, _ = strings.Cut(value, " ")
+		switch field {
+		case "health":
+			if group == nil {
+				status.Health = value
+			}
+		case "scaleup":
+			if group == nil {
+				status.ScaleUp = value
+			} else {
+				group.scaleUp = value
+			}
+		case "scaledown":
+			if group == nil {
+				status.ScaleDown = value
+			}
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "- ")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "nodegroups":
+			block = ""
+		case "name":
+			group = &autoscalerNodeGroup{name: strings.Trim(value, `"`)}
+			groups = append(groups, group)
+			block = ""
+		case "health", "scaleup", "scaledown":
+			if value == "" {
+				block = key
+			} else {
+				set(key, value)
+			}
+		case "status":
+			set(block, value)
+		}
+
+		if group != nil {
+			if match := cloudProviderTargetPattern.FindStringSubmatch(line); match != nil {
+				group.target, _ = strconv.Atoi(match[1])
+			}
+			if match := maxSizePattern.FindStringSubmatch(line); match != nil {
+				group.maxSize, _ = strconv.Atoi(match[1])
+			}
+		}
+	}
+
+	for _, group := range groups {
+		if group.maxSize > 0 && group.target >= group.maxSize {
+			status.NodeGroupsAtMaxSize = append(status.NodeGroupsAtMaxSize, group.name)
+		}
+		if group.scaleUp == "Backoff" {
+			status.NodeGroupsInBackoff = append(status.NodeGroupsInBackoff, group.name)
+		}
+	}
+	return status
+}
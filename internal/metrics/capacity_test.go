@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+const legacyAutoscalerStatus = `Cluster-autoscaler status at 2026-10-16 12:00:00.123456 +0000 UTC:
+Cluster-wide:
+  Health:      Healthy (ready=3 unready=0 (resourceUnready=0) notStarted=0 longNotStarted=0 registered=3 longUnregistered=0)
+               LastProbeTime:      2026-10-16 12:00:00.123456 +0000 UTC
+               LastTransitionTime: 2026-10-16 08:00:00.123456 +0000 UTC
+  ScaleUp:     NoActivity (ready=3 registered=3)
+               LastProbeTime:      2026-10-16 12:00:00.123456 +0000 UTC
+  ScaleDown:   NoCandidates (candidates=0)
+               LastProbeTime:      2026-10-16 12:00:00.123456 +0000 UTC
+
+NodeGroups:
+  Name:        pool-a
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0 cloudProviderTarget=3 (minSize=1, maxSize=3))
+  ScaleUp:     NoActivity (ready=3 cloudProviderTarget=3)
+  ScaleDown:   NoCandidates (candidates=0)
+
+  Name:        pool-gpu
+  Health:      Healthy (ready=0 unready=0 notStarted=0 longNotStarted=0 registered=0 longUnregistered=0 cloudProviderTarget=0 (minSize=0, maxSize=4))
+  ScaleUp:     Backoff (ready=0 cloudProviderTarget=0)
+  ScaleDown:   NoCandidates (candidates=0)
+`
+
+const yamlAutoscalerStatus = `time: 2026-10-16 12:00:00.123456 +0000 UTC
+autoscalerStatus: Running
+clusterWide:
+  health:
+    status: Healthy
+    nodeCounts:
+      registered:
+        total: 3
+        ready: 3
+        notStarted: 0
+      longUnregistered: 0
+      unregistered: 0
+    lastProbeTime: "2026-10-16T12:00:00Z"
+  scaleUp:
+    status: InProgress
+    lastProbeTime: "2026-10-16T12:00:00Z"
+  scaleDown:
+    status: NoCandidates
+nodeGroups:
+- name: pool-a
+  health:
+    status: Healthy
+    nodeCounts:
+      registered:
+        total: 3
+    cloudProviderTarget: 3
+    minSize: 1
+    maxSize: 3
+  scaleUp:
+    status: NoActivity
+  scaleDown:
+    status: NoCandidates
+- name: pool-b
+  health:
+    status: Healthy
+    cloudProviderTarget: 2
+    minSize: 1
+    maxSize: 5
+  scaleUp:
+    status: InProgress
+`
+
+func TestParseAutoscalerStatus(t *testing.T) {
+	assert.Equal(t, &pkgtypes.AutoscalerStatus{
+		Health:              "Healthy",
+		ScaleUp:             "NoActivity",
+		ScaleDown:           "NoCandidates",
+		NodeGroupsAtMaxSize: []string{"pool-a"},
+		NodeGroupsInBackoff: []string{"pool-gpu"},
+	}, parseAutoscalerStatus(legacyAutoscalerStatus))
+
+	assert.Equal(t, &pkgtypes.AutoscalerStatus{
+		Health:              "Healthy",
+		ScaleUp:             "InProgress",
+		ScaleDown:           "NoCandidates",
+		NodeGroupsAtMaxSize: []string{"pool-a"},
+	}, parseAutoscalerStatus(yamlAutoscalerStatus))
+}
+
+func TestCollector_CapacitySignals(t *testing.T) {
+	now := time.Now()
+	pending := func(name, reason string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: reason,
+					Message: "0/3 nodes are available: 3 Insufficient cpu.",
+				}},
+			},
+		}
+	}
+	running := modelPod("web-1", "worker-1", 0, corev1.PodRunning)
+	status := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-autoscaler-status", Namespace: "kube-system"},
+		Data:       map[string]string{"status": legacyAutoscalerStatus},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := ctrlclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(pending("web-2", corev1.PodReasonUnschedulable), pending("web-3", corev1.PodReasonUnschedulable),
+			pending("web-4", corev1.PodReasonSchedulingGated), running, status).
+		Build()
+	event := func(name, reason string, lastSeen time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "apps", Name: "web-2"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		event("web-2.1", "FailedScheduling", now.Add(-time.Minute)),
+		event("web-3.1", "FailedScheduling", now.Add(-time.Hour)),
+		event("web-2.2", "BackOff", now.Add(-time.Minute)),
+	)
+	collector := NewCollector(c, clientset, nil)
+
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}}
+	metrics, err := collector.CollectMetrics(context.Background(), policy)
+	require.NoError(t, err)
+	assert.Nil(t, metrics.Capacity, "capacity signals are opt-in")
+
+	require.NoError(t, collector.WithCapacitySignals("kube-system/cluster-autoscaler-status"))
+	metrics, err = collector.CollectMetrics(context.Background(), policy)
+	require.NoError(t, err)
+	capacity := metrics.Capacity
+	require.NotNil(t, capacity)
+	assert.Equal(t, 2, capacity.PendingUnschedulable, "scheduling gated pods are not out of capacity")
+	require.Len(t, capacity.UnschedulablePods, 2)
+	assert.Equal(t, "0/3 nodes are available: 3 Insufficient cpu.", capacity.UnschedulablePods[0].Message)
+	assert.Equal(t, 1, capacity.FailedScheduling)
+	require.NotNil(t, capacity.Autoscaler)
+	assert.Equal(t, []string{"pool-a"}, capacity.Autoscaler.NodeGroupsAtMaxSize)
+
+	// Clusters without an autoscaler
+	require.NoError(t, c.Delete(context.Background(), status))
+	metrics, err = collector.CollectMetrics(context.Background(), policy)
+	require.NoError(t, err)
+	assert.Nil(t, metrics.Capacity.Autoscaler)
+
+	assert.ErrorContains(t, collector.WithCapacitySignals("cluster-autoscaler-status"), "must be namespace/name")
+}
@@ -19,6 +19,8 @@ import (
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
 	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/plugins"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
 )
 
 // Collector implements the MetricsCollector interface
@@ -27,15 +29,34 @@ type Collector struct {
 	clientset     kubernetes.Interface
 	metricsClient metricsclient.Interface
 	prometheus    *PrometheusClient // Optional Prometheus integration
+	logSampler    *LogSampler
+	restartStorms *RestartStormDetector
+	pushReceiver  *PushReceiver   // Optional application-pushed metrics
+	stats         *CollectorStats // Optional performance stats
+	plugins       *plugins.Registry
+
+	// maxLowSignalPods caps the steadily running pods kept per collection;
+	// 0 keeps every pod
+	maxLowSignalPods int
+
+	// capacitySignals adds the scheduling and autoscaling signals, with
+	// the autoscaler's status from the autoscalerStatus ConfigMap if set
+	capacitySignals  bool
+	autoscalerStatus client.ObjectKey
 }
 
 // NewCollector creates a new metrics collector
 func NewCollector(client client.Client, clientset kubernetes.Interface, metricsClient metricsclient.Interface) *Collector {
-	return &Collector{
+	collector := &Collector{
 		client:        client,
 		clientset:     clientset,
 		metricsClient: metricsClient,
+		restartStorms: NewRestartStormDetector(),
 	}
+	if clientset != nil {
+		collector.logSampler = NewLogSampler(clientset)
+	}
+	return collector
 }
 
 // WithPrometheus adds Prometheus support to the collector
@@ -62,11 +83,56 @@ func (c *Collector) WithPrometheus(prometheusAddr string) error {
 	return nil
 }
 
+// WithQueryCache caches Prometheus query results for ttl, so identical
+// queries of several triggers and policies reach Prometheus once per cycle.
+// It must be called after WithPrometheus; a ttl of 0 disables the cache.
+func (c *Collector) WithQueryCache(ttl time.Duration) {
+	if c.prometheus != nil && ttl > 0 {
+		c.prometheus.WithCache(NewQueryCache(ttl))
+	}
+}
+
+// WithPushReceiver makes metrics pushed by applications available to
+// MetricTriggers as "custom:<name>" queries
+func (c *Collector) WithPushReceiver(receiver *PushReceiver) {
+	c.pushReceiver = receiver
+}
+
+// WithStats records collection timings and store sizes in stats
+func (c *Collector) WithStats(stats *CollectorStats) {
+	c.stats = stats
+	stats.RegisterStore("restartStorm.samples", c.restartStorms.Size)
+	if c.pushReceiver != nil {
+		stats.RegisterStore("pushReceiver.samples", c.pushReceiver.Size)
+	}
+}
+
+// WithPlugins evaluates plugin triggers with the evaluators and detectors
+// of registry
+func (c *Collector) WithPlugins(registry *plugins.Registry) {
+	c.plugins = registry
+}
+
+// WithLowSignalSampling keeps at most maxPods low-signal pods, pods running
+// ready without restarts, per collection. Pods that may need healing are
+// always kept, so only aggregate usage triggers see the sample. A maxPods
+// of zero or less keeps every pod.
+func (c *Collector) WithLowSignalSampling(maxPods int) {
+	c.maxLowSignalPods = maxPods
+}
+
 // CollectMetrics gathers metrics for the given policy
 func (c *Collector) CollectMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) (*types.ClusterMetrics, error) {
 	log := log.FromContext(ctx)
 	log.Info("Collecting metrics for policy", "policy", policy.Name)
 
+	// The first failed step counts the collection as an error in the stats
+	start := time.Now()
+	var collectErr error
+	defer func() {
+		c.stats.ObserveCollection(policy.Namespace+"/"+policy.Name, time.Since(start), collectErr)
+	}()
+
 	metrics := &types.ClusterMetrics{
 		Timestamp: time.Now(),
 		Resources: make(map[string]interface{}),
@@ -77,54 +143,129 @@ func (c *Collector) CollectMetrics(ctx context.Context, policy *v1alpha1.Healing
 	nodes, err := c.collectNodeMetrics(ctx, policy)
 	if err != nil {
 		log.Error(err, "Failed to collect node metrics")
+		if collectErr == nil {
+			collectErr = err
+		}
 	}
 	metrics.Nodes = nodes
 
 	// Collect pod metrics
-	pods, err := c.collectPodMetrics(ctx, policy)
+	pods, sampledOut, err := c.collectPodMetrics(ctx, policy)
 	if err != nil {
 		log.Error(err, "Failed to collect pod metrics")
+		if collectErr == nil {
+			collectErr = err
+		}
 	}
 	metrics.Pods = pods
+	metrics.SampledOutPods = sampledOut
 
-	log.Info("Collected metrics", "policy", policy.Name, "pods", len(pods), "nodes", len(nodes))
-	for _, pod := range pods {
-		log.V(1).Info("Pod metrics", "pod", pod.Name, "restarts", pod.RestartCount, "cpu", pod.CPUUsage, "memory", pod.MemoryUsage, "status", pod.Status)
+	log.Info("Collected metrics", "policy", policy.Name, "pods", len(pods), "sampledOut", sampledOut, "nodes", len(nodes))
+	if debug := log.V(1); debug.Enabled() {
+		for i := range pods {
+			pod := &pods[i]
+			debug.Info("Pod metrics", "pod", pod.Name, "restarts", pod.RestartCount, "cpu", pod.CPUUsage, "memory", pod.MemoryUsage, "status", pod.Status)
+		}
 	}
 
 	// Collect events
 	events, err := c.collectEvents(ctx, policy)
 	if err != nil {
 		log.Error(err, "Failed to collect events")
+		if collectErr == nil {
+			collectErr = err
+		}
 	}
 	metrics.Events = events
 
-	// Custom metrics collection would go here
-	// This is a placeholder for future implementation
+	// Tell a cluster out of capacity apart from failing workloads
+	if c.capacitySignals {
+		capacity, err := c.collectCapacity(ctx)
+		if err != nil {
+			log.Error(err, "Failed to collect capacity signals")
+			if collectErr == nil {
+				collectErr = err
+			}
+		}
+		metrics.Capacity = capacity
+	}
+
+	// Metrics pushed by applications in the policy's namespaces
+	if c.pushReceiver != nil {
+		namespaces := policy.Spec.Selector.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{policy.Namespace}
+		}
+		for key, value := range c.pushReceiver.Values(namespaces) {
+			metrics.Custom[key] = value
+		}
+	}
 
 	return metrics, nil
 }
 
 // EvaluateTrigger checks if a trigger condition is met
 func (c *Collector) EvaluateTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
-	switch trigger.Type {
-	case "metric":
+	result, err := c.EvaluateTriggerResult(ctx, trigger, metrics)
+	return result.Triggered, result.Reason, err
+}
+
+// EvaluateTriggerResult checks if a trigger condition is met and reports the
+// value metric triggers were evaluated against
+func (c *Collector) EvaluateTriggerResult(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *types.ClusterMetrics) (types.TriggerResult, error) {
+	if trigger.Type == "metric" {
 		if trigger.MetricTrigger == nil {
-			return false, "", fmt.Errorf("metric trigger configuration missing")
+			return types.TriggerResult{}, fmt.Errorf("metric trigger configuration missing")
 		}
 		return c.evaluateMetricTrigger(ctx, trigger.MetricTrigger, metrics)
+	}
+
+	triggered, reason, err := c.evaluateTrigger(ctx, trigger, metrics)
+	return types.TriggerResult{Triggered: triggered, Reason: reason}, err
+}
+
+// evaluateTrigger evaluates the trigger types that have no observed value
+func (c *Collector) evaluateTrigger(ctx context.Context, trigger *v1alpha1.HealingTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
+	switch trigger.Type {
 
 	case "event":
 		if trigger.EventTrigger == nil {
 			return false, "", fmt.Errorf("event trigger configuration missing")
 		}
-		return c.evaluateEventTrigger(ctx, trigger.EventTrigger, metrics)
+		triggered, reason := triggers.EvaluateEvents(trigger.EventTrigger,
+			types.ToPublicClusterMetrics(metrics).Events, time.Now())
+		return triggered, reason, nil
 
 	case "condition":
 		if trigger.ConditionTrigger == nil {
 			return false, "", fmt.Errorf("condition trigger configuration missing")
 		}
-		return c.evaluateConditionTrigger(ctx, trigger.ConditionTrigger, metrics)
+		triggered, reason := triggers.EvaluateCondition(trigger.ConditionTrigger, types.ToPublicClusterMetrics(metrics))
+		return triggered, reason, nil
+
+	case "log":
+		if trigger.LogTrigger == nil {
+			return false, "", fmt.Errorf("log trigger configuration missing")
+		}
+		if c.logSampler == nil {
+			return false, "", fmt.Errorf("log sampling requires a kubernetes clientset")
+		}
+		return c.logSampler.Evaluate(ctx, trigger.LogTrigger, metrics)
+
+	case "restartStorm":
+		if trigger.RestartStormTrigger == nil {
+			return false, "", fmt.Errorf("restart storm trigger configuration missing")
+		}
+		return c.restartStorms.Evaluate(trigger.RestartStormTrigger, metrics)
+
+	case "plugin":
+		if trigger.PluginTrigger == nil {
+			return false, "", fmt.Errorf("plugin trigger configuration missing")
+		}
+		if c.plugins == nil {
+			return false, "", fmt.Errorf("plugin triggers require a plugin registry")
+		}
+		return c.plugins.Evaluate(ctx, trigger.PluginTrigger, types.ToPublicClusterMetrics(metrics))
 
 	default:
 		return false, "", fmt.Errorf("unknown trigger type: %s", trigger.Type)
@@ -185,16 +326,23 @@ func (c *Collector) GetResourceMetrics(ctx context.Context, resource *v1alpha1.T
 
 // collectNodeMetrics collects metrics for all nodes matching the policy selector
 func (c *Collector) collectNodeMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) ([]types.NodeMetrics, error) {
-	var nodeMetrics []types.NodeMetrics
+	var selector labels.Selector
+	if policy.Spec.Selector.LabelSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(policy.Spec.Selector.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+	}
 
-	// Get all nodes
+	// Get all nodes; they are only read, so the cached objects are used as is
 	nodeList := &corev1.NodeList{}
-	if err := c.client.List(ctx, nodeList); err != nil {
+	if err := c.client.List(ctx, nodeList, client.UnsafeDisableDeepCopy); err != nil {
 		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 
 	// Get node metrics from metrics server
-	metricsMap := make(map[string]*v1beta1.NodeMetrics)
+	metricsMap := make(map[string]*v1beta1.NodeMetrics, len(nodeList.Items))
 	if c.metricsClient != nil {
 		metricsList, err := c.metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
 		if err != nil {
@@ -206,23 +354,34 @@ func (c *Collector) collectNodeMetrics(ctx context.Context, policy *v1alpha1.Hea
 		}
 	}
 
-	for _, node := range nodeList.Items {
-		// Apply label selector if specified
-		if policy.Spec.Selector.LabelSelector != nil {
-			selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector.LabelSelector)
-			if err != nil {
-				return nil, fmt.Errorf("invalid label selector: %w", err)
-			}
-			if !selector.Matches(labels.Set(node.Labels)) {
-				continue
+	// Count the pods of every node from a single list
+	scratch := getScratch()
+	defer scratch.release()
+	podList := &corev1.PodList{}
+	if err := c.client.List(ctx, podList, client.UnsafeDisableDeepCopy); err == nil {
+		for i := range podList.Items {
+			if nodeName := podList.Items[i].Spec.NodeName; nodeName != "" {
+				scratch.podCounts[nodeName]++
 			}
 		}
+	}
+
+	now := time.Now()
+	nodeMetrics := make([]types.NodeMetrics, 0, len(nodeList.Items))
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		// Apply label selector if specified
+		if selector != nil && !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
 
-		nm := types.NodeMetrics{
+		nodeMetrics = append(nodeMetrics, types.NodeMetrics{
 			Name:           node.Name,
 			Labels:         node.Labels,
-			LastUpdateTime: time.Now(),
-		}
+			LastUpdateTime: now,
+			PodCount:       scratch.podCounts[node.Name],
+		})
+		nm := &nodeMetrics[len(nodeMetrics)-1]
 
 		// Get conditions
 		for _, condition := range node.Status.Conditions {
@@ -236,89 +395,72 @@ func (c *Collector) collectNodeMetrics(ctx context.Context, policy *v1alpha1.Hea
 			nm.CPUUsage = float64(metrics.Usage.Cpu().MilliValue()) / 1000.0
 			nm.MemoryUsage = float64(metrics.Usage.Memory().Value()) / (1024 * 1024 * 1024) // Convert to GB
 		}
-
-		// Get pod count
-		podList := &corev1.PodList{}
-		if err := c.client.List(ctx, podList, client.MatchingFields{"spec.nodeName": node.Name}); err == nil {
-			nm.PodCount = int32(len(podList.Items))
-		}
-
-		nodeMetrics = append(nodeMetrics, nm)
 	}
 
 	return nodeMetrics, nil
 }
 
-// collectPodMetrics collects metrics for pods matching the policy selector
-func (c *Collector) collectPodMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) ([]types.PodMetrics, error) {
-	var podMetrics []types.PodMetrics
-
-	// Build list options from policy selector
-	opts := []client.ListOption{}
+// collectPodMetrics collects metrics for pods matching the policy selector.
+// It also returns how many low-signal pods were left out by sampling.
+func (c *Collector) collectPodMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy) ([]types.PodMetrics, int, error) {
+	// Build list options from policy selector; the pods are only read, so
+	// the cached objects are used as is
+	opts := []client.ListOption{client.UnsafeDisableDeepCopy}
+	metricsOpts := metav1.ListOptions{}
 	if policy.Spec.Selector.LabelSelector != nil {
 		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector.LabelSelector)
 		if err != nil {
-			return nil, fmt.Errorf("invalid label selector: %w", err)
+			return nil, 0, fmt.Errorf("invalid label selector: %w", err)
 		}
 		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+		metricsOpts.LabelSelector = selector.String()
 	}
+	// For multiple namespaces, we'd need to make multiple queries
+	// For now, just use the first namespace, or the policy's namespace if
+	// none is specified
+	namespace := policy.Namespace
 	if len(policy.Spec.Selector.Namespaces) > 0 {
-		// For multiple namespaces, we'd need to make multiple queries
-		// For now, just use the first namespace
-		opts = append(opts, client.InNamespace(policy.Spec.Selector.Namespaces[0]))
-	} else {
-		// If no namespace specified, use the policy's namespace
-		opts = append(opts, client.InNamespace(policy.Namespace))
+		namespace = policy.Spec.Selector.Namespaces[0]
 	}
+	opts = append(opts, client.InNamespace(namespace))
 
 	// Get pods
 	podList := &corev1.PodList{}
 	if err := c.client.List(ctx, podList, opts...); err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return nil, 0, fmt.Errorf("failed to list pods: %w", err)
 	}
 
-	// Get pod metrics from metrics server
-	for _, pod := range podList.Items {
-		pm := types.PodMetrics{
-			Name:           pod.Name,
-			Namespace:      pod.Namespace,
-			Status:         string(pod.Status.Phase),
-			Labels:         pod.Labels,
-			LastUpdateTime: time.Now(),
-		}
-
-		// Get conditions
-		for _, condition := range pod.Status.Conditions {
-			if condition.Status == corev1.ConditionTrue {
-				pm.Conditions = append(pm.Conditions, string(condition.Type))
+	// Get pod metrics from metrics server with one list for the namespace
+	scratch := getScratch()
+	defer scratch.release()
+	if c.metricsClient != nil {
+		metricsList, err := c.metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metricsOpts)
+		if err == nil {
+			for i := range metricsList.Items {
+				scratch.podUsage[metricsList.Items[i].Name] = podUsage(&metricsList.Items[i])
 			}
 		}
+	}
 
-		// Get restart count
-		for _, containerStatus := range pod.Status.ContainerStatuses {
-			pm.RestartCount += containerStatus.RestartCount
-		}
-
-		// Get owner references
-		for _, owner := range pod.OwnerReferences {
-			pm.OwnerReferences = append(pm.OwnerReferences, fmt.Sprintf("%s/%s", owner.Kind, owner.Name))
-		}
-
-		// Get resource usage from metrics server
-		if c.metricsClient != nil {
-			metrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
-			if err == nil {
-				for _, container := range metrics.Containers {
-					pm.CPUUsage += float64(container.Usage.Cpu().MilliValue()) / 1000.0
-					pm.MemoryUsage += float64(container.Usage.Memory().Value()) / (1024 * 1024) // Convert to MB
-				}
+	now := time.Now()
+	podMetrics := make([]types.PodMetrics, 0, len(podList.Items))
+	arena := &stringArena{}
+	lowSignal, sampledOut := 0, 0
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if c.maxLowSignalPods > 0 && isLowSignalPod(pod, now) {
+			if lowSignal >= c.maxLowSignalPods {
+				sampledOut++
+				continue
 			}
+			lowSignal++
 		}
 
-		podMetrics = append(podMetrics, pm)
+		podMetrics = append(podMetrics, types.PodMetrics{})
+		fillPodMetrics(&podMetrics[len(podMetrics)-1], pod, scratch.podUsage[pod.Name], now, arena, scratch)
 	}
 
-	return podMetrics, nil
+	return podMetrics, sampledOut, nil
 }
 
 // collectEvents collects recent events
@@ -356,257 +498,60 @@ func (c *Collector) collectEvents(ctx context.Context, policy *v1alpha1.HealingP
 }
 
 // evaluateMetricTrigger evaluates a metric-based trigger
-func (c *Collector) evaluateMetricTrigger(ctx context.Context, trigger *v1alpha1.MetricTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
-	var actualValue float64
-	var err error
-
-	// Try Prometheus first if available and query looks like PromQL
-	if c.prometheus != nil && (strings.Contains(trigger.Query, "(") || strings.Contains(trigger.Query, "{") || strings.Contains(trigger.Query, "[")) {
-		// This looks like a PromQL query
-		actualValue, err = c.prometheus.Query(ctx, trigger.Query)
-		if err != nil {
-			log.FromContext(ctx).Error(err, "Prometheus query failed, falling back to basic metrics", "query", trigger.Query)
-			// Fall through to basic metrics
-		} else {
-			// Successfully got value from Prometheus
-			triggered := c.evaluateThreshold(actualValue, trigger.Threshold, trigger.Operator)
-			reason := fmt.Sprintf("Prometheus query '%s' = %.2f %s %.2f", trigger.Query, actualValue, trigger.Operator, trigger.Threshold)
-			return triggered, reason, nil
-		}
-	}
-
-	// Fall back to basic metrics evaluation
-	// Parse the query to understand what metric is being requested
-	if strings.Contains(trigger.Query, "node_cpu") {
-		if len(metrics.Nodes) > 0 {
-			total := 0.0
-			for _, node := range metrics.Nodes {
-				total += node.CPUUsage
-			}
-			actualValue = total / float64(len(metrics.Nodes))
-		}
-	} else if strings.Contains(trigger.Query, "pod_restart") || strings.Contains(trigger.Query, "restart_count") {
-		if len(metrics.Pods) > 0 {
-			maxRestarts := int32(0)
-			for _, pod := range metrics.Pods {
-				if pod.RestartCount > maxRestarts {
-					maxRestarts = pod.RestartCount
-				}
-			}
-			actualValue = float64(maxRestarts)
-		}
-	} else if strings.Contains(trigger.Query, "cpu_usage_percent") {
-		if len(metrics.Pods) > 0 {
-			maxCPU := 0.0
-			for _, pod := range metrics.Pods {
-				// Assuming CPU limit is 1000m (1 core) by default
-				cpuPercent := (pod.CPUUsage / 1000.0) * 100.0
-				if cpuPercent > maxCPU {
-					maxCPU = cpuPercent
-				}
-			}
-			actualValue = maxCPU
-		}
-	} else if strings.Contains(trigger.Query, "memory_usage_percent") {
-		if len(metrics.Pods) > 0 {
-			maxMemory := 0.0
-			for _, pod := range metrics.Pods {
-				// Assuming memory limit is 512MB by default
-				memoryPercent := (pod.MemoryUsage / 512.0) * 100.0
-				if memoryPercent > maxMemory {
-					maxMemory = memoryPercent
-				}
-			}
-			actualValue = maxMemory
-		}
-	} else if strings.Contains(trigger.Query, "memory_usage_bytes") {
-		if len(metrics.Pods) > 0 {
-			maxMemory := 0.0
-			for _, pod := range metrics.Pods {
-				if pod.MemoryUsage > maxMemory {
-					maxMemory = pod.MemoryUsage
-				}
-			}
-			actualValue = maxMemory * 1024 * 1024 // Convert MB to bytes
-		}
-	} else if strings.Contains(trigger.Query, "error_rate_percent") {
-		// Calculate error rate from recent events
-		errorCount := 0
-		totalEvents := 0
-		for _, event := range metrics.Events {
-			// Look for events in the last 5 minutes
-			if time.Since(event.LastSeen) < 5*time.Minute {
-				if event.Type == "Warning" && (strings.Contains(event.Reason, "Unhealthy") ||
-					strings.Contains(event.Reason, "BackOff") ||
-					strings.Contains(event.Reason, "Failed")) {
-					errorCount++
-				}
-				totalEvents++
-			}
-		}
-
-		// Also check pod restart counts as errors
-		for _, pod := range metrics.Pods {
-			if pod.RestartCount > 0 {
-				errorCount += int(pod.RestartCount)
-				totalEvents += int(pod.RestartCount) + 1
-			}
-		}
-
-		if totalEvents > 0 {
-			actualValue = float64(errorCount) / float64(totalEvents) * 100.0
-		} else {
-			actualValue = 0
-		}
+func (c *Collector) evaluateMetricTrigger(ctx context.Context, trigger *v1alpha1.MetricTrigger, metrics *types.ClusterMetrics) (types.TriggerResult, error) {
+	datasource := trigger.EffectiveDatasource()
 
-		// For demo purposes, if we have flaky pods with restarts, assume 20% error rate
-		for _, pod := range metrics.Pods {
-			if strings.Contains(pod.Name, "flaky") && pod.RestartCount > 0 {
-				actualValue = 20.0 // Simulate the 20% error rate from the app
-				break
-			}
-		}
-	} else if strings.Contains(trigger.Query, "error_rate") && !strings.Contains(trigger.Query, "percent") {
-		// Simple error rate (count of errors)
-		errorCount := 0
-		for _, event := range metrics.Events {
-			if time.Since(event.LastSeen) < 5*time.Minute && event.Type == "Warning" {
-				errorCount++
-			}
-		}
-		for _, pod := range metrics.Pods {
-			if pod.RestartCount > 0 {
-				errorCount += int(pod.RestartCount)
-			}
+	// Pushed metrics are only available through the receiver
+	if datasource == v1alpha1.DatasourcePushed {
+		key := CustomMetricPrefix + strings.TrimPrefix(trigger.Query, CustomMetricPrefix)
+		value, ok := metrics.Custom[key]
+		if !ok {
+			return types.TriggerResult{Reason: fmt.Sprintf("no pushed samples for '%s'", key)}, nil
 		}
-		actualValue = float64(errorCount)
-	} else if strings.Contains(trigger.Query, "availability_percent") {
-		// Calculate availability based on pod readiness and events
-		totalPods := len(metrics.Pods)
-		healthyPods := 0
-
-		for _, pod := range metrics.Pods {
-			if pod.Status == "Running" && pod.RestartCount < 3 {
-				healthyPods++
-			}
-		}
-
-		if totalPods > 0 {
-			actualValue = float64(healthyPods) / float64(totalPods) * 100.0
-		} else {
-			actualValue = 100.0
-		}
-
-		// Reduce availability based on recent error events
-		recentErrors := 0
-		for _, event := range metrics.Events {
-			if time.Since(event.LastSeen) < 5*time.Minute && event.Type == "Warning" {
-				recentErrors++
-			}
-		}
-
-		// Each error reduces availability by 0.5%
-		actualValue = actualValue - (float64(recentErrors) * 0.5)
-		if actualValue < 0 {
-			actualValue = 0
-		}
-	} else {
-		return false, "metric evaluation not implemented for query: " + trigger.Query, nil
-	}
-
-	// Evaluate the threshold
-	triggered := c.evaluateThreshold(actualValue, trigger.Threshold, trigger.Operator)
-	reason := fmt.Sprintf("query '%s' result %.2f %s %.2f", trigger.Query, actualValue, trigger.Operator, trigger.Threshold)
-	return triggered, reason, nil
-}
-
-// evaluateThreshold compares a value against a threshold using the given operator
-func (c *Collector) evaluateThreshold(value, threshold float64, operator string) bool {
-	switch operator {
-	case ">":
-		return value > threshold
-	case ">=":
-		return value >= threshold
-	case "<":
-		return value < threshold
-	case "<=":
-		return value <= threshold
-	case "==":
-		return value == threshold
-	case "!=":
-		return value != threshold
-	default:
-		return false
+		return types.TriggerResult{
+			Triggered: triggers.Compare(value, trigger.Threshold, trigger.Operator),
+			Reason:    fmt.Sprintf("Pushed metric '%s' = %.2f %s %.2f", key, value, trigger.Operator, trigger.Threshold),
+			Value:     value,
+			Observed:  true,
+		}, nil
 	}
-}
 
-// evaluateEventTrigger evaluates an event-based trigger
-func (c *Collector) evaluateEventTrigger(ctx context.Context, trigger *v1alpha1.EventTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
-	matchCount := 0
-	window := time.Duration(5 * time.Minute) // Default window
-	if trigger.Window.Duration > 0 {
-		window = trigger.Window.Duration
+	// Patterns are found by the detectors of the advanced collector
+	if datasource == v1alpha1.DatasourcePattern {
+		return evaluatePatternQuery(trigger, metrics.Patterns), nil
 	}
-	cutoff := time.Now().Add(-window)
-
-	for _, event := range metrics.Events {
-		if trigger.Type != "" && event.Type != trigger.Type {
-			continue
-		}
-		if trigger.Reason != "" && event.Reason != trigger.Reason {
-			continue
-		}
 
-		// Check if event is within the time window
-		if event.LastSeen.Before(cutoff) {
-			continue
+	// Query Prometheus if available, falling back to basic metrics
+	if c.prometheus != nil && datasource == v1alpha1.DatasourcePrometheus {
+		actualValue, err := c.prometheus.Query(ctx, trigger.Query)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Prometheus query failed, falling back to basic metrics", "query", trigger.Query)
+			// Fall through to basic metrics
+		} else if trigger.Baseline != nil {
+			return c.evaluateBaselineTrigger(ctx, trigger, actualValue, time.Now())
+		} else {
+			return types.TriggerResult{
+				Triggered: triggers.Compare(actualValue, trigger.Threshold, trigger.Operator),
+				Reason:    fmt.Sprintf("Prometheus query '%s' = %.2f %s %.2f", trigger.Query, actualValue, trigger.Operator, trigger.Threshold),
+				Value:     actualValue,
+				Observed:  true,
+			}, nil
 		}
-
-		matchCount++
 	}
 
-	triggered := matchCount >= int(trigger.Count)
-	reason := fmt.Sprintf("found %d matching events (threshold: %d) in last %v", matchCount, trigger.Count, window)
-	return triggered, reason, nil
-}
-
-// evaluateConditionTrigger evaluates a condition-based trigger
-func (c *Collector) evaluateConditionTrigger(ctx context.Context, trigger *v1alpha1.ConditionTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
-	matchCount := 0
-
-	// Check node conditions
-	for _, node := range metrics.Nodes {
-		for _, condition := range node.Conditions {
-			if condition == trigger.Type {
-				matchCount++
-				break
-			}
-		}
+	// Baselines are read from Prometheus, the basic metrics have no history
+	if trigger.Baseline != nil {
+		return types.TriggerResult{Reason: fmt.Sprintf("no baseline for '%s' without Prometheus", trigger.Query)}, nil
 	}
 
-	// Check pod conditions and status
-	for _, pod := range metrics.Pods {
-		// Check regular pod conditions
-		for _, condition := range pod.Conditions {
-			if condition == trigger.Type {
-				matchCount++
-				break
-			}
-		}
-
-		// Special handling for CrashLoopBackOff which is a container state, not a condition
-		if trigger.Type == "CrashLoopBackOff" {
-			// We need to check the actual pod status from the cluster
-			// For now, we'll use a heuristic: high restart count indicates crashloop
-			if pod.RestartCount > 2 {
-				matchCount++
-			}
-		}
+	// Fall back to basic metrics evaluation
+	actualValue, ok := triggers.MetricValue(trigger.Query, types.ToPublicClusterMetrics(metrics), time.Now())
+	if !ok {
+		return types.TriggerResult{Reason: "metric evaluation not implemented for query: " + trigger.Query}, nil
 	}
 
-	triggered := matchCount > 0
-	reason := fmt.Sprintf("found %d resources with condition %s", matchCount, trigger.Type)
-	return triggered, reason, nil
+	triggered, reason := triggers.EvaluateMetric(trigger, actualValue)
+	return types.TriggerResult{Triggered: triggered, Reason: reason, Value: actualValue, Observed: true}, nil
 }
 
 // Helper methods for getting metric values
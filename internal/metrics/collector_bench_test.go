@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// benchmarkCluster builds a cluster of nodes running pods, one in a hundred
+// of them restarting, with metrics-server usage for every pod and node
+func benchmarkCluster(b *testing.B, nodes, pods int) *Collector {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+
+	objects := make([]client.Object, 0, nodes+pods)
+	var nodeUsage []runtime.Object
+	var podUsage []*metricsv1beta1.PodMetrics
+	for i := 0; i < nodes; i++ {
+		name := fmt.Sprintf("node-%d", i)
+		objects = append(objects, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": "default"}},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			}},
+		})
+		nodeUsage = append(nodeUsage, &metricsv1beta1.NodeMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		})
+	}
+	for i := 0; i < pods; i++ {
+		name := fmt.Sprintf("web-%d", i)
+		restarts := int32(0)
+		if i%100 == 0 {
+			restarts = 3
+		}
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				Labels:          map[string]string{"app": "web"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d8f"}},
+			},
+			Spec: corev1.PodSpec{NodeName: fmt.Sprintf("node-%d", i%nodes)},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+					{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true, RestartCount: restarts}},
+			},
+		})
+		podUsage = append(podUsage, &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Containers: []metricsv1beta1.ContainerMetrics{{
+				Name: "web",
+				Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			}},
+		})
+	}
+
+	c := &cacheClient{Client: ctrlclient.NewClientBuilder().WithScheme(scheme).Build(), objects: objects}
+	metricsClient := metricsfake.NewSimpleClientset(nodeUsage...)
+	for _, pod := range podUsage {
+		if err := metricsClient.Tracker().Create(podMetricsResource, pod, pod.Namespace); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return NewCollector(c, fake.NewSimpleClientset(), metricsClient)
+}
+
+// cacheClient lists objects the way the manager's cache does for
+// client.UnsafeDisableDeepCopy, so the benchmarks measure the collector
+// rather than the fake client's copies
+type cacheClient struct {
+	client.Client
+	objects []client.Object
+}
+
+func (c *cacheClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	switch l := list.(type) {
+	case *corev1.NodeList:
+		for _, obj := range c.objects {
+			if node, ok := obj.(*corev1.Node); ok {
+				l.Items = append(l.Items, *node)
+			}
+		}
+	case *corev1.PodList:
+		for _, obj := range c.objects {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				l.Items = append(l.Items, *pod)
+			}
+		}
+	default:
+		return c.Client.List(ctx, list, opts...)
+	}
+	return nil
+}
+
+func benchmarkPolicy() *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1alpha1.HealingPolicySpec{
+			Selector: v1alpha1.ResourceSelector{Namespaces: []string{"default"}},
+		},
+	}
+}
+
+func BenchmarkCollectPodMetrics(b *testing.B) {
+	collector := benchmarkCluster(b, 100, 10000)
+	policy := benchmarkPolicy()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := collector.collectPodMetrics(context.Background(), policy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCollectNodeMetrics(b *testing.B) {
+	collector := benchmarkCluster(b, 100, 10000)
+	policy := benchmarkPolicy()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := collector.collectNodeMetrics(context.Background(), policy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCollectPodMetricsSampled(b *testing.B) {
+	collector := benchmarkCluster(b, 100, 10000)
+	collector.WithLowSignalSampling(500)
+	policy := benchmarkPolicy()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := collector.collectPodMetrics(context.Background(), policy); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
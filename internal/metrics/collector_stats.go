@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// CollectorStats records how long metrics collection takes per policy, how
+// often caches are hit and how large the in-memory time-series stores are,
+// for performance investigations. A nil CollectorStats records nothing.
+type CollectorStats struct {
+	mu       sync.Mutex
+	policies map[string]*PolicyCollectionStats
+	caches   map[string]*CacheStats
+	stores   map[string]func() int
+}
+
+// PolicyCollectionStats summarizes the metrics collections of one policy
+type PolicyCollectionStats struct {
+	Collections int64     `json:"collections"`
+	Errors      int64     `json:"errors"`
+	LastMillis  float64   `json:"lastMillis"`
+	MeanMillis  float64   `json:"meanMillis"`
+	MaxMillis   float64   `json:"maxMillis"`
+	LastAt      time.Time `json:"lastAt"`
+
+	total time.Duration
+}
+
+// CacheStats counts the lookups of one cache
+type CacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// CollectorStatsSnapshot is a point-in-time copy of the collector stats
+type CollectorStatsSnapshot struct {
+	Policies map[string]PolicyCollectionStats `json:"policies"`
+	Caches   map[string]CacheStats            `json:"caches"`
+	Stores   map[string]int                   `json:"stores"`
+}
+
+// NewCollectorStats creates empty collector stats
+func NewCollectorStats() *CollectorStats {
+	return &CollectorStats{
+		policies: make(map[string]*PolicyCollectionStats),
+		caches:   make(map[string]*CacheStats),
+		stores:   make(map[string]func() int),
+	}
+}
+
+// ObserveCollection records one metrics collection for a policy
+func (s *CollectorStats) ObserveCollection(policy string, duration time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.policies[policy]
+	if !ok {
+		stats = &PolicyCollectionStats{}
+		s.policies[policy] = stats
+	}
+	stats.Collections++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.total += duration
+	stats.LastMillis = millis(duration)
+	stats.MeanMillis = millis(stats.total / time.Duration(stats.Collections))
+	if stats.LastMillis > stats.MaxMillis {
+		stats.MaxMillis = stats.LastMillis
+	}
+	stats.LastAt = time.Now()
+}
+
+// RecordCacheLookup counts a hit or miss of the named cache
+func (s *CollectorStats) RecordCacheLookup(cache string, hit bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.caches[cache]
+	if !ok {
+		stats = &CacheStats{}
+		s.caches[cache] = stats
+	}
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+	stats.HitRate = float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
+
+// RegisterStore reports the size of an in-memory store in snapshots. The
+// size function must be safe to call concurrently.
+func (s *CollectorStats) RegisterStore(name string, size func() int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores[name] = size
+}
+
+// Snapshot copies the current stats
+func (s *CollectorStats) Snapshot() CollectorStatsSnapshot {
+	snapshot := CollectorStatsSnapshot{
+		Policies: make(map[string]PolicyCollectionStats),
+		Caches:   make(map[string]CacheStats),
+		Stores:   make(map[string]int),
+	}
+	if s == nil {
+		return snapshot
+	}
+
+	s.mu.Lock()
+	for name, stats := range s.policies {
+		snapshot.Policies[name] = *stats
+	}
+	for name, stats := range s.caches {
+		snapshot.Caches[name] = *stats
+	}
+	names := make([]string, 0, len(s.stores))
+	sizes := make([]func() int, 0, len(s.stores))
+	for name, size := range s.stores {
+		names = append(names, name)
+		sizes = append(sizes, size)
+	}
+	s.mu.Unlock()
+
+	// Stores take their own locks, so they are sized outside ours
+	for i, name := range names {
+		snapshot.Stores[name] = sizes[i]()
+	}
+	return snapshot
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectorStats(t *testing.T) {
+	stats := NewCollectorStats()
+	stats.ObserveCollection("apps/web", 10*time.Millisecond, nil)
+	stats.ObserveCollection("apps/web", 30*time.Millisecond, errors.New("metrics server unavailable"))
+	stats.RecordCacheLookup("aiAnalysis", true)
+	stats.RecordCacheLookup("aiAnalysis", true)
+	stats.RecordCacheLookup("aiAnalysis", false)
+	stats.RegisterStore("samples", func() int { return 42 })
+
+	snapshot := stats.Snapshot()
+	web := snapshot.Policies["apps/web"]
+	assert.Equal(t, int64(2), web.Collections)
+	assert.Equal(t, int64(1), web.Errors)
+	assert.Equal(t, 30.0, web.LastMillis)
+	assert.Equal(t, 20.0, web.MeanMillis)
+	assert.Equal(t, 30.0, web.MaxMillis)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, HitRate: 2.0 / 3}, snapshot.Caches["aiAnalysis"])
+	assert.Equal(t, map[string]int{"samples": 42}, snapshot.Stores)
+}
+
+func TestCollectorStats_Nil(t *testing.T) {
+	var stats *CollectorStats
+	stats.ObserveCollection("apps/web", time.Millisecond, nil)
+	stats.RecordCacheLookup("aiAnalysis", true)
+	stats.RegisterStore("samples", func() int { return 1 })
+
+	snapshot := stats.Snapshot()
+	assert.Empty(t, snapshot.Policies)
+	assert.Empty(t, snapshot.Caches)
+	assert.Empty(t, snapshot.Stores)
+}
+
+func TestCollector_WithStats(t *testing.T) {
+	collector := NewCollector(nil, nil, nil)
+	collector.WithPushReceiver(NewPushReceiver("", "", time.Minute))
+	stats := NewCollectorStats()
+	collector.WithStats(stats)
+
+	collector.pushReceiver.Record("queue_depth", "apps", "worker", 3)
+	collector.pushReceiver.Record("queue_depth", "apps", "api", 1)
+	assert.Equal(t, map[string]int{"restartStorm.samples": 0, "pushReceiver.samples": 2}, stats.Snapshot().Stores)
+}
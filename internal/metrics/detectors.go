@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/plugins"
+	"github.com/kubeskippy/kubeskippy/pkg/triggers"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// PatternQueryPrefix marks MetricTrigger queries answered by the enabled
+// detectors: "pattern:<detector>" is the number of patterns the detector
+// found in the last analysis
+const PatternQueryPrefix = v1alpha1.PatternQueryPrefix
+
+// namedDetector is a detector enabled on the advanced collector
+type namedDetector struct {
+	name     string
+	detector pkgtypes.Detector
+}
+
+// WithDetectors runs the named detectors of registry over the time series
+// on every analysis, in the given order
+func (ac *AdvancedCollector) WithDetectors(registry *plugins.Registry, names []string) error {
+	for _, name := range names {
+		detector, ok := registry.Detector(name)
+		if !ok {
+			return fmt.Errorf("detector %q is not registered", name)
+		}
+		ac.detectors = append(ac.detectors, namedDetector{name: name, detector: detector})
+	}
+	return nil
+}
+
+// Keys returns the sorted keys of the time series starting with prefix
+func (ac *AdvancedCollector) Keys(prefix string) []string {
+	var keys []string
+	for key := range ac.historicalData {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Points returns the points of a time series, oldest first
+func (ac *AdvancedCollector) Points(key string) []pkgtypes.SeriesPoint {
+	return ac.historicalData[key]
+}
+
+// runDetectors returns the patterns found by the enabled detectors. A
+// failing detector is logged and skipped so it cannot block analysis.
+func (ac *AdvancedCollector) runDetectors(ctx context.Context) []pkgtypes.Pattern {
+	var patterns []pkgtypes.Pattern
+	for _, d := range ac.detectors {
+		found, err := d.detector.Detect(ctx, ac)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Detector failed", "detector", d.name)
+			continue
+		}
+		for _, pattern := range found {
+			pattern.Detector = d.name
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// evaluatePatternQuery evaluates a "pattern:<detector>" query against the
+// patterns found in the last analysis
+func evaluatePatternQuery(trigger *v1alpha1.MetricTrigger, patterns []pkgtypes.Pattern) types.TriggerResult {
+	detector := strings.TrimPrefix(trigger.Query, PatternQueryPrefix)
+	var descriptions []string
+	for _, pattern := range patterns {
+		if pattern.Detector != detector {
+			continue
+		}
+		description := pattern.Description
+		if pattern.Target != "" {
+			description = fmt.Sprintf("%s on %s", description, pattern.Target)
+		}
+		descriptions = append(descriptions, description)
+	}
+
+	value := float64(len(descriptions))
+	reason := fmt.Sprintf("Detector '%s' found %d patterns %s %.2f", detector, len(descriptions), trigger.Operator, trigger.Threshold)
+	if len(descriptions) > 0 {
+		reason += ": " + strings.Join(descriptions, "; ")
+	}
+	return types.TriggerResult{
+		Triggered: triggers.Compare(value, trigger.Threshold, trigger.Operator),
+		Reason:    reason,
+		Value:     value,
+		Observed:  true,
+	}
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/plugins"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// seriesDetector adapts a function to a detector
+type seriesDetector func(ctx context.Context, series pkgtypes.SeriesStore) ([]pkgtypes.Pattern, error)
+
+func (f seriesDetector) Detect(ctx context.Context, series pkgtypes.SeriesStore) ([]pkgtypes.Pattern, error) {
+	return f(ctx, series)
+}
+
+// restartingPods reports every pod whose restart series is above zero
+var restartingPods = seriesDetector(func(ctx context.Context, series pkgtypes.SeriesStore) ([]pkgtypes.Pattern, error) {
+	var patterns []pkgtypes.Pattern
+	for _, key := range series.Keys("pod_restarts_") {
+		points := series.Points(key)
+		if last := points[len(points)-1]; last.Value > 0 {
+			patterns = append(patterns, pkgtypes.Pattern{
+				Name:        "restarting",
+				Target:      last.Labels["namespace"] + "/" + last.Labels["pod"],
+				Description: "pod restarts",
+				Confidence:  0.9,
+			})
+		}
+	}
+	return patterns, nil
+})
+
+func TestAdvancedCollector_Detectors(t *testing.T) {
+	registry := plugins.NewRegistry()
+	require.NoError(t, registry.RegisterDetector("restarts", restartingPods))
+	require.NoError(t, registry.RegisterDetector("broken", seriesDetector(func(ctx context.Context, series pkgtypes.SeriesStore) ([]pkgtypes.Pattern, error) {
+		return nil, errors.New("boom")
+	})))
+
+	collector := NewAdvancedCollector(modelCollector(t, []client.Object{
+		modelPod("web-1", "worker-1", 3, corev1.PodRunning),
+		modelPod("web-2", "worker-1", 0, corev1.PodRunning),
+	}))
+	assert.Error(t, collector.WithDetectors(registry, []string{"missing"}))
+	require.NoError(t, collector.WithDetectors(registry, []string{"broken", "restarts"}))
+
+	advanced, err := collector.CollectAdvancedMetrics(context.Background(), &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+	})
+	require.NoError(t, err)
+	require.Len(t, advanced.Patterns, 1, "a failing detector is skipped")
+	assert.Equal(t, "restarts", advanced.Patterns[0].Detector)
+	assert.Equal(t, "apps/web-1", advanced.Patterns[0].Target)
+
+	assert.Equal(t, []string{"pod_restarts_apps_web-1", "pod_restarts_apps_web-2"}, collector.Keys("pod_restarts_"))
+	assert.Len(t, collector.Points("pod_cpu_apps_web-1"), 1)
+	assert.Empty(t, collector.Points("pod_cpu_apps_missing"))
+
+	t.Run("pattern queries", func(t *testing.T) {
+		trigger := &v1alpha1.HealingTrigger{
+			Name:          "restarting",
+			Type:          "metric",
+			MetricTrigger: &v1alpha1.MetricTrigger{Query: "pattern:restarts", Threshold: 0, Operator: ">"},
+		}
+
+		result, err := collector.EvaluateAdvancedTrigger(context.Background(), trigger, advanced)
+		require.NoError(t, err)
+		assert.True(t, result.Triggered)
+		assert.Equal(t, 1.0, result.Value)
+		assert.Equal(t, "Detector 'restarts' found 1 patterns > 0.00: pod restarts on apps/web-1", result.Reason)
+
+		result, err = collector.EvaluateTriggerResult(context.Background(), trigger, &types.ClusterMetrics{Patterns: advanced.Patterns})
+		require.NoError(t, err)
+		assert.True(t, result.Triggered, "cluster metrics carry the patterns too")
+
+		trigger.MetricTrigger.Query = "pattern:broken"
+		result, err = collector.EvaluateTriggerResult(context.Background(), trigger, &types.ClusterMetrics{Patterns: advanced.Patterns})
+		require.NoError(t, err)
+		assert.False(t, result.Triggered)
+		assert.True(t, result.Observed)
+	})
+}
+
+func TestIsAdvancedQuery(t *testing.T) {
+	assert.True(t, IsAdvancedQuery("system_health_score"))
+	assert.True(t, IsAdvancedQuery("pattern:restarts"))
+	assert.False(t, IsAdvancedQuery("pod_restarts"))
+	assert.False(t, IsAdvancedQuery("custom:queue_depth"))
+}
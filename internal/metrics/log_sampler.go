@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// defaultLogMaxLines bounds the lines sampled per container
+	defaultLogMaxLines = 500
+
+	// defaultLogMaxBytes bounds the bytes sampled per container
+	defaultLogMaxBytes = 64 * 1024
+
+	// maxLogSamplePods bounds the pods sampled per evaluation
+	maxLogSamplePods = 20
+
+	// maxLogMatchesPerTrigger bounds the matched lines kept for AI analysis
+	maxLogMatchesPerTrigger = 20
+
+	// maxLogLineLength truncates very long log lines
+	maxLogLineLength = 512
+)
+
+// LogSampler samples pod logs for log-pattern triggers
+type LogSampler struct {
+	clientset kubernetes.Interface
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+	// offsets rotates the sampled pods of each trigger across evaluations
+	offsets map[string]int
+}
+
+// NewLogSampler creates a new log sampler
+func NewLogSampler(clientset kubernetes.Interface) *LogSampler {
+	return &LogSampler{
+		clientset: clientset,
+		patterns:  make(map[string]*regexp.Regexp),
+		offsets:   make(map[string]int),
+	}
+}
+
+// Evaluate samples the logs of the pods in metrics and counts lines matching
+// the trigger pattern within the window. Matched lines are appended to
+// metrics.LogMatches so they are available to AI analysis.
+func (s *LogSampler) Evaluate(ctx context.Context, trigger *v1alpha1.LogTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
+	pattern, err := s.compile(trigger.Pattern)
+	if err != nil {
+		return false, "", err
+	}
+
+	window := 5 * time.Minute
+	if trigger.Window.Duration > 0 {
+		window = trigger.Window.Duration
+	}
+	count := trigger.Count
+	if count <= 0 {
+		count = 1
+	}
+
+	var candidates []types.PodMetrics
+	for _, pod := range metrics.Pods {
+		if pod.Status == string(corev1.PodRunning) || pod.Status == string(corev1.PodFailed) {
+			candidates = append(candidates, pod)
+		}
+	}
+	candidates = s.rotate(trigger, candidates)
+
+	matchCount := 0
+	kept := 0
+	for _, pod := range candidates {
+		containers, err := s.containers(ctx, pod.Namespace, pod.Name, trigger)
+		if err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to get pod containers", "pod", pod.Name, "error", err.Error())
+			continue
+		}
+
+		for _, container := range containers {
+			lines, err := s.sample(ctx, pod.Namespace, pod.Name, container, trigger, window)
+			if err != nil {
+				log.FromContext(ctx).V(1).Info("Failed to sample pod logs", "pod", pod.Name, "container", container, "error", err.Error())
+				continue
+			}
+
+			for _, line := range lines {
+				if !pattern.MatchString(line) {
+					continue
+				}
+				matchCount++
+				if kept < maxLogMatchesPerTrigger {
+					if len(line) > maxLogLineLength {
+						line = line[:maxLogLineLength]
+					}
+					metrics.LogMatches = append(metrics.LogMatches, types.LogMatch{
+						Pod:       pod.Name,
+						Namespace: pod.Namespace,
+						Container: container,
+						Pattern:   trigger.Pattern,
+						Line:      line,
+					})
+					kept++
+				}
+			}
+		}
+	}
+
+	triggered := matchCount >= int(count)
+	reason := fmt.Sprintf("found %d log lines matching %q (threshold: %d) in last %v across %d pods",
+		matchCount, trigger.Pattern, count, window, len(candidates))
+	return triggered, reason, nil
+}
+
+// rotate returns at most maxLogSamplePods pods, starting where the previous
+// evaluation of the trigger stopped so every pod is sampled over time
+func (s *LogSampler) rotate(trigger *v1alpha1.LogTrigger, pods []types.PodMetrics) []types.PodMetrics {
+	if len(pods) <= maxLogSamplePods {
+		return pods
+	}
+
+	key := trigger.Container + "/" + trigger.Pattern
+	s.mu.Lock()
+	start := s.offsets[key] % len(pods)
+	s.offsets[key] = (start + maxLogSamplePods) % len(pods)
+	s.mu.Unlock()
+
+	sampled := make([]types.PodMetrics, 0, maxLogSamplePods)
+	for i := 0; i < maxLogSamplePods; i++ {
+		sampled = append(sampled, pods[(start+i)%len(pods)])
+	}
+	return sampled
+}
+
+// containers returns the containers to sample: the trigger container, or
+// every container of the pod when none is set
+func (s *LogSampler) containers(ctx context.Context, namespace, name string, trigger *v1alpha1.LogTrigger) ([]string, error) {
+	if trigger.Container != "" {
+		return []string{trigger.Container}, nil
+	}
+	pod, err := s.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, container.Name)
+	}
+	return containers, nil
+}
+
+// sample reads a bounded tail of a container's logs within the window
+func (s *LogSampler) sample(ctx context.Context, namespace, name, container string, trigger *v1alpha1.LogTrigger, window time.Duration) ([]string, error) {
+	maxLines := trigger.MaxLines
+	if maxLines <= 0 {
+		maxLines = defaultLogMaxLines
+	}
+	maxBytes := trigger.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultLogMaxBytes
+	}
+	sinceSeconds := int64(window.Seconds())
+
+	opts := &corev1.PodLogOptions{
+		Container:    container,
+		TailLines:    &maxLines,
+		LimitBytes:   &maxBytes,
+		SinceSeconds: &sinceSeconds,
+	}
+
+	stream, err := s.clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream logs: %w", err)
+	}
+	defer stream.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(io.LimitReader(stream, maxBytes))
+	scanner.Buffer(make([]byte, 0, 4096), int(maxBytes))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return lines, fmt.Errorf("failed to read logs: %w", err)
+	}
+	return lines, nil
+}
+
+// compile returns the cached regular expression for a pattern
+func (s *LogSampler) compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("log trigger pattern is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if re, ok := s.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log pattern %q: %w", pattern, err)
+	}
+	s.patterns[pattern] = re
+	return re, nil
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/plugins"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// logPod returns a pod with the named containers
+func logPod(name string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+	}
+	return pod
+}
+
+func TestLogSampler_Evaluate(t *testing.T) {
+	// The fake clientset returns "fake logs" for every log request, so
+	// every sampled container yields one line
+	pods := []types.PodMetrics{
+		{Name: "web-1", Namespace: "default", Status: "Running"},
+		{Name: "web-2", Namespace: "default", Status: "Running"},
+		{Name: "web-3", Namespace: "default", Status: "Pending"},
+	}
+
+	tests := []struct {
+		name          string
+		trigger       *v1alpha1.LogTrigger
+		expected      bool
+		expectedLines int
+		expectError   bool
+	}{
+		{
+			name:          "pattern matches across pods",
+			trigger:       &v1alpha1.LogTrigger{Pattern: "fake\\s+logs", Count: 2},
+			expected:      true,
+			expectedLines: 3,
+		},
+		{
+			name:          "count threshold not reached",
+			trigger:       &v1alpha1.LogTrigger{Pattern: "fake", Count: 4},
+			expected:      false,
+			expectedLines: 3,
+		},
+		{
+			name:          "named container only",
+			trigger:       &v1alpha1.LogTrigger{Pattern: "fake", Container: "app", Count: 2},
+			expected:      true,
+			expectedLines: 2,
+		},
+		{
+			name:          "pattern does not match",
+			trigger:       &v1alpha1.LogTrigger{Pattern: "OutOfMemoryError"},
+			expected:      false,
+			expectedLines: 0,
+		},
+		{
+			name:        "invalid pattern",
+			trigger:     &v1alpha1.LogTrigger{Pattern: "("},
+			expectError: true,
+		},
+		{
+			name:        "missing pattern",
+			trigger:     &v1alpha1.LogTrigger{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler := NewLogSampler(fake.NewSimpleClientset(
+				logPod("web-1", "app", "sidecar"), logPod("web-2", "app")))
+			metrics := &types.ClusterMetrics{Pods: pods}
+
+			triggered, reason, err := sampler.Evaluate(context.Background(), tt.trigger, metrics)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, triggered)
+			assert.Contains(t, reason, "log lines matching")
+			assert.Len(t, metrics.LogMatches, tt.expectedLines)
+			for _, match := range metrics.LogMatches {
+				assert.NotEmpty(t, match.Container)
+			}
+		})
+	}
+}
+
+func TestLogSampler_RotatesPods(t *testing.T) {
+	var objects []runtime.Object
+	var pods []types.PodMetrics
+	for i := 0; i < maxLogSamplePods+5; i++ {
+		name := fmt.Sprintf("web-%d", i)
+		objects = append(objects, logPod(name, "app"))
+		pods = append(pods, types.PodMetrics{Name: name, Namespace: "default", Status: "Running"})
+	}
+	sampler := NewLogSampler(fake.NewSimpleClientset(objects...))
+	trigger := &v1alpha1.LogTrigger{Pattern: "fake"}
+
+	// Two evaluations together cover every pod
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		metrics := &types.ClusterMetrics{Pods: pods}
+		_, _, err := sampler.Evaluate(context.Background(), trigger, metrics)
+		require.NoError(t, err)
+		for _, match := range metrics.LogMatches {
+			seen[match.Pod] = true
+		}
+	}
+	assert.Len(t, seen, len(pods))
+}
+
+func TestCollector_EvaluateLogTrigger(t *testing.T) {
+	collector := NewCollector(nil, fake.NewSimpleClientset(logPod("web-1", "app")), nil)
+
+	trigger := &v1alpha1.HealingTrigger{Name: "oom", Type: "log"}
+	_, _, err := collector.EvaluateTrigger(context.Background(), trigger, &types.ClusterMetrics{})
+	assert.Error(t, err)
+
+	trigger.LogTrigger = &v1alpha1.LogTrigger{Pattern: "fake"}
+	triggered, _, err := collector.EvaluateTrigger(context.Background(), trigger, &types.ClusterMetrics{
+		Pods: []types.PodMetrics{{Name: "web-1", Namespace: "default", Status: "Running"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, triggered)
+}
+
+// readyEvaluator fires when no pod is running
+type readyEvaluator struct{}
+
+func (readyEvaluator) Evaluate(ctx context.Context, config map[string]string, metrics *pkgtypes.ClusterMetrics) (bool, string, error) {
+	for _, pod := range metrics.Pods {
+		if pod.Status == "Running" {
+			return false, "", nil
+		}
+	}
+	return true, "no running pods", nil
+}
+
+func TestCollector_EvaluatePluginTrigger(t *testing.T) {
+	collector := NewCollector(nil, nil, nil)
+	trigger := &v1alpha1.HealingTrigger{Name: "down", Type: "plugin",
+		PluginTrigger: &v1alpha1.PluginTrigger{Evaluator: "no-running-pods"}}
+
+	// Plugin triggers need a registry
+	_, _, err := collector.EvaluateTrigger(context.Background(), trigger, &types.ClusterMetrics{})
+	assert.Error(t, err)
+
+	registry := plugins.NewRegistry()
+	require.NoError(t, registry.RegisterTriggerEvaluator("no-running-pods", readyEvaluator{}))
+	collector.WithPlugins(registry)
+
+	triggered, reason, err := collector.EvaluateTrigger(context.Background(), trigger, &types.ClusterMetrics{
+		Pods: []types.PodMetrics{{Name: "web-1", Namespace: "default", Status: "Pending"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, triggered)
+	assert.Equal(t, "no running pods", reason)
+}
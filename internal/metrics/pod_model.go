@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// resourceUsage is the CPU (cores) and memory usage of a pod or node as
+// reported by metrics-server
+type resourceUsage struct {
+	cpu    float64
+	memory float64
+}
+
+// ownerKey identifies an owner reference of a pod
+type ownerKey struct {
+	kind string
+	name string
+}
+
+// collectScratch holds the lookup maps built while collecting metrics.
+// They only live for one collection, so they are pooled rather than
+// reallocated, and regrown, for every policy on every reconcile.
+type collectScratch struct {
+	// podUsage is keyed by pod name, podCounts by node name
+	podUsage  map[string]resourceUsage
+	podCounts map[string]int32
+
+	// owners interns the "Kind/name" strings shared by the pods of a
+	// workload
+	owners map[ownerKey]string
+}
+
+var scratchPool = sync.Pool{
+	New: func() any {
+		return &collectScratch{
+			podUsage:  make(map[string]resourceUsage),
+			podCounts: make(map[string]int32),
+			owners:    make(map[ownerKey]string),
+		}
+	},
+}
+
+// getScratch returns empty scratch maps; release them when done
+func getScratch() *collectScratch {
+	return scratchPool.Get().(*collectScratch)
+}
+
+// release clears the maps, keeping their buckets, and returns them to the
+// pool
+func (s *collectScratch) release() {
+	clear(s.podUsage)
+	clear(s.podCounts)
+	clear(s.owners)
+	scratchPool.Put(s)
+}
+
+// owner returns the interned "Kind/name" of an owner reference
+func (s *collectScratch) owner(kind, name string) string {
+	key := ownerKey{kind: kind, name: name}
+	owner, ok := s.owners[key]
+	if !ok {
+		owner = kind + "/" + name
+		s.owners[key] = owner
+	}
+	return owner
+}
+
+// stringArenaChunk is the number of strings allocated at once by a
+// stringArena
+const stringArenaChunk = 1024
+
+// stringArena hands out small string slices carved from larger chunks, so
+// the conditions and owners of thousands of pods take a handful of
+// allocations instead of several per pod
+type stringArena struct {
+	buf []string
+}
+
+// slice returns an empty slice with room for n strings. Its capacity is
+// capped, so appending past n reallocates instead of overwriting the next
+// slice.
+func (a *stringArena) slice(n int) []string {
+	if n == 0 {
+		return nil
+	}
+	if len(a.buf) < n {
+		a.buf = make([]string, max(n, stringArenaChunk))
+	}
+	s := a.buf[:0:n]
+	a.buf = a.buf[n:]
+	return s
+}
+
+// podUsage sums the container usage of a pod, memory in MB
+func podUsage(metrics *v1beta1.PodMetrics) resourceUsage {
+	var usage resourceUsage
+	for i := range metrics.Containers {
+		container := &metrics.Containers[i]
+		usage.cpu += float64(container.Usage.Cpu().MilliValue()) / 1000.0
+		usage.memory += float64(container.Usage.Memory().Value()) / (1024 * 1024) // Convert to MB
+	}
+	return usage
+}
+
+// isLowSignalPod reports whether a pod is running steadily: ready, with no
+// restarts and in none of the pod states. Such pods rarely trigger healing,
+// so they are the ones sampled in large clusters.
+func isLowSignalPod(pod *corev1.Pod, now time.Time) bool {
+	if pod.Status.Phase != corev1.PodRunning || !isPodReady(pod) || pod.DeletionTimestamp != nil {
+		return false
+	}
+	if getTotalRestartCount(pod) > 0 || InitContainerRestarts(pod) > 0 {
+		return false
+	}
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if status.State.Running != nil {
+			return false
+		}
+	}
+	for _, state := range podStates {
+		if _, ok := podStateSince(pod, state, now); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fillPodMetrics converts a pod into pm, which is usually an element of a
+// preallocated slice. Conditions and owners come from the arena, owner
+// strings are interned and Labels share the pod's map, so metrics must be
+// treated as read-only.
+func fillPodMetrics(pm *types.PodMetrics, pod *corev1.Pod, usage resourceUsage, now time.Time, arena *stringArena, scratch *collectScratch) {
+	pm.Name = pod.Name
+	pm.Namespace = pod.Namespace
+	pm.UID = string(pod.UID)
+	pm.CreationTime = pod.CreationTimestamp.Time
+	pm.Status = string(pod.Status.Phase)
+	pm.Labels = pod.Labels
+	pm.LastUpdateTime = now
+	pm.CPUUsage = usage.cpu
+	pm.MemoryUsage = usage.memory
+
+	// Conditions, then the pod states
+	trueConditions := 0
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Status == corev1.ConditionTrue {
+			trueConditions++
+		}
+	}
+	pm.Conditions = arena.slice(trueConditions)
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Status == corev1.ConditionTrue {
+			pm.Conditions = append(pm.Conditions, string(pod.Status.Conditions[i].Type))
+		}
+	}
+	pm.Conditions = appendPodStates(pm.Conditions, pod, now)
+
+	pm.RestartCount = getTotalRestartCount(pod)
+	pm.InitRestartCount = InitContainerRestarts(pod)
+	pm.InitFailures = InitContainerFailures(pod)
+	pm.EphemeralContainers = RunningEphemeralContainers(pod)
+
+	pm.OwnerReferences = arena.slice(len(pod.OwnerReferences))
+	for i := range pod.OwnerReferences {
+		pm.OwnerReferences = append(pm.OwnerReferences, scratch.owner(pod.OwnerReferences[i].Kind, pod.OwnerReferences[i].Name))
+	}
+}
@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// podMetricsResource is the resource the metrics client reads pod usage
+// from, which the fake clientset cannot guess from the PodMetrics kind
+var podMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+func modelPod(name, node string, restarts int32, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "apps",
+			Labels:          map[string]string{"app": "web"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d8f"}},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}, {Type: corev1.PodScheduled, Status: corev1.ConditionFalse}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true, RestartCount: restarts}},
+		},
+	}
+}
+
+func modelCollector(t *testing.T, objects []client.Object, usage ...*metricsv1beta1.PodMetrics) *Collector {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := ctrlclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	metricsClient := metricsfake.NewSimpleClientset()
+	for _, pod := range usage {
+		require.NoError(t, metricsClient.Tracker().Create(podMetricsResource, pod, pod.Namespace))
+	}
+	return NewCollector(c, fake.NewSimpleClientset(), metricsClient)
+}
+
+func TestCollectPodMetrics_Model(t *testing.T) {
+	web := modelPod("web-1", "worker-1", 2, corev1.PodRunning)
+	web.OwnerReferences = append(web.OwnerReferences, metav1.OwnerReference{Kind: "Job", Name: "backup"})
+	web.UID = "web-1-uid"
+	done := modelPod("job-1", "worker-1", 0, corev1.PodSucceeded)
+	collector := modelCollector(t, []client.Object{web, done}, &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "web", Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("64Mi")}},
+			{Name: "proxy", Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("64Mi")}},
+		},
+	})
+
+	pods, sampledOut, err := collector.collectPodMetrics(context.Background(), &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, sampledOut)
+	require.Len(t, pods, 2)
+
+	byName := map[string]types.PodMetrics{}
+	for _, pod := range pods {
+		byName[pod.Name] = pod
+	}
+	assert.Equal(t, []string{"Ready"}, byName["web-1"].Conditions)
+	assert.Equal(t, []string{"ReplicaSet/web-5d8f", "Job/backup"}, byName["web-1"].OwnerReferences)
+	assert.Equal(t, int32(2), byName["web-1"].RestartCount)
+	assert.Equal(t, "web-1-uid", byName["web-1"].UID)
+	assert.InDelta(t, 0.5, byName["web-1"].CPUUsage, 0.001)
+	assert.InDelta(t, 128, byName["web-1"].MemoryUsage, 0.001)
+	assert.Equal(t, []string{"Ready", PodStateCompleted}, byName["job-1"].Conditions, "pod states follow the conditions")
+	assert.Zero(t, byName["job-1"].CPUUsage, "pods without metrics-server usage")
+}
+
+func TestCollectPodMetrics_LowSignalSampling(t *testing.T) {
+	objects := []client.Object{
+		modelPod("steady-1", "worker-1", 0, corev1.PodRunning),
+		modelPod("steady-2", "worker-1", 0, corev1.PodRunning),
+		modelPod("steady-3", "worker-1", 0, corev1.PodRunning),
+		modelPod("restarting", "worker-1", 4, corev1.PodRunning),
+		modelPod("pending", "", 0, corev1.PodPending),
+	}
+	policy := &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}}
+
+	tests := []struct {
+		name           string
+		maxPods        int
+		wantPods       int
+		wantSampledOut int
+	}{
+		{name: "disabled", wantPods: 5},
+		{name: "caps steady pods", maxPods: 1, wantPods: 3, wantSampledOut: 2},
+		{name: "cap not reached", maxPods: 10, wantPods: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := modelCollector(t, objects)
+			collector.WithLowSignalSampling(tt.maxPods)
+
+			metrics, err := collector.CollectMetrics(context.Background(), policy)
+			require.NoError(t, err)
+			assert.Len(t, metrics.Pods, tt.wantPods)
+			assert.Equal(t, tt.wantSampledOut, metrics.SampledOutPods)
+
+			names := map[string]bool{}
+			for _, pod := range metrics.Pods {
+				names[pod.Name] = true
+			}
+			assert.True(t, names["restarting"], "pods that may need healing are kept")
+			assert.True(t, names["pending"], "pods that may need healing are kept")
+		})
+	}
+}
+
+func TestIsLowSignalPod(t *testing.T) {
+	now := time.Now()
+	terminating := modelPod("web", "worker-1", 0, corev1.PodRunning)
+	terminating.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Minute)}
+	notReady := modelPod("web", "worker-1", 0, corev1.PodRunning)
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	initRestarts := modelPod("web", "worker-1", 0, corev1.PodRunning)
+	initRestarts.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "migrate", RestartCount: 1}}
+	debugged := modelPod("web", "worker-1", 0, corev1.PodRunning)
+	debugged.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{{Name: "debugger", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}}
+
+	assert.True(t, isLowSignalPod(modelPod("web", "worker-1", 0, corev1.PodRunning), now))
+	assert.False(t, isLowSignalPod(modelPod("web", "worker-1", 1, corev1.PodRunning), now), "restarts")
+	assert.False(t, isLowSignalPod(modelPod("web", "worker-1", 0, corev1.PodSucceeded), now), "not running")
+	assert.False(t, isLowSignalPod(terminating, now), "terminating")
+	assert.False(t, isLowSignalPod(notReady, now), "not ready")
+	assert.False(t, isLowSignalPod(initRestarts, now), "init container restarts")
+	assert.False(t, isLowSignalPod(debugged, now), "debugging session")
+}
+
+func TestCollectNodeMetrics_PodCounts(t *testing.T) {
+	node := func(name, pool string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}}}
+	}
+	collector := modelCollector(t, []client.Object{
+		node("worker-1", "default"), node("worker-2", "default"), node("gpu-1", "gpu"),
+		modelPod("web-1", "worker-1", 0, corev1.PodRunning),
+		modelPod("web-2", "worker-1", 0, corev1.PodRunning),
+		modelPod("web-3", "worker-2", 0, corev1.PodRunning),
+		modelPod("pending", "", 0, corev1.PodPending),
+	})
+
+	nodes, err := collector.collectNodeMetrics(context.Background(), &v1alpha1.HealingPolicy{
+		Spec: v1alpha1.HealingPolicySpec{Selector: v1alpha1.ResourceSelector{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "default"}},
+		}},
+	})
+	require.NoError(t, err)
+	counts := map[string]int32{}
+	for _, node := range nodes {
+		counts[node.Name] = node.PodCount
+	}
+	assert.Equal(t, map[string]int32{"worker-1": 2, "worker-2": 1}, counts)
+}
+
+func TestStringArena(t *testing.T) {
+	arena := &stringArena{}
+	assert.Nil(t, arena.slice(0))
+
+	first := append(arena.slice(1), "Ready")
+	second := append(arena.slice(1), "Initialized")
+	first = append(first, "Completed")
+	assert.Equal(t, []string{"Ready", "Completed"}, first)
+	assert.Equal(t, []string{"Initialized"}, second, "appending past the capacity leaves the next slice alone")
+
+	large := arena.slice(2 * stringArenaChunk)
+	assert.Equal(t, 2*stringArenaChunk, cap(large))
+}
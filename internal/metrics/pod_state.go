@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Pod states reported alongside pod conditions so condition triggers can
+// match pods that are stuck in a state Kubernetes does not report as a
+// condition
+const (
+	// PodStateImagePullBackOff is set while a container cannot pull its image
+	PodStateImagePullBackOff = "ImagePullBackOff"
+
+	// PodStateTerminating is set once a pod's deletion grace period expired
+	// and the pod still exists
+	PodStateTerminating = "Terminating"
+
+	// PodStateEvicted is set for pods that failed because they were evicted
+	PodStateEvicted = "Evicted"
+
+	// PodStateCompleted is set for pods whose containers all succeeded
+	PodStateCompleted = "Completed"
+
+	// PodStateInitFailure is set while an init container keeps failing,
+	// leaving the pod stuck before its app containers start
+	PodStateInitFailure = "InitFailure"
+)
+
+// initWaitingFailures are the waiting reasons of init containers that
+// cannot start or keep failing
+var initWaitingFailures = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// IsPodState reports whether a condition type is one of the pod states
+func IsPodState(conditionType string) bool {
+	switch conditionType {
+	case PodStateImagePullBackOff, PodStateTerminating, PodStateEvicted, PodStateCompleted, PodStateInitFailure:
+		return true
+	}
+	return false
+}
+
+// podStates lists every pod state in the order they are reported
+var podStates = []string{PodStateImagePullBackOff, PodStateTerminating, PodStateEvicted, PodStateCompleted, PodStateInitFailure}
+
+// PodStates returns the pod states the pod is in at the given time
+func PodStates(pod *corev1.Pod, now time.Time) []string {
+	return appendPodStates(nil, pod, now)
+}
+
+// appendPodStates appends the pod states the pod is in to states
+func appendPodStates(states []string, pod *corev1.Pod, now time.Time) []string {
+	for _, state := range podStates {
+		if _, ok := podStateSince(pod, state, now); ok {
+			states = append(states, state)
+		}
+	}
+	return states
+}
+
+// PodInState reports whether the pod has been in the state for at least
+// minAge
+func PodInState(pod *corev1.Pod, state string, minAge time.Duration, now time.Time) bool {
+	since, ok := podStateSince(pod, state, now)
+	return ok && now.Sub(since) >= minAge
+}
+
+// podStateSince returns when the pod entered the state, as closely as the
+// pod status allows
+func podStateSince(pod *corev1.Pod, state string, now time.Time) (time.Time, bool) {
+	switch state {
+	case PodStateImagePullBackOff:
+		for _, status := range pod.Status.ContainerStatuses {
+			if waiting := status.State.Waiting; waiting != nil &&
+				(waiting.Reason == "ImagePullBackOff" || waiting.Reason == "ErrImagePull") {
+				return pod.CreationTimestamp.Time, true
+			}
+		}
+
+	case PodStateTerminating:
+		// The deletion timestamp is the end of the grace period
+		if pod.DeletionTimestamp != nil && !now.Before(pod.DeletionTimestamp.Time) {
+			return pod.DeletionTimestamp.Time, true
+		}
+
+	case PodStateEvicted:
+		if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted" {
+			return finishedAt(pod), true
+		}
+
+	case PodStateCompleted:
+		if pod.Status.Phase == corev1.PodSucceeded {
+			return finishedAt(pod), true
+		}
+
+	case PodStateInitFailure:
+		if len(InitContainerFailures(pod)) > 0 {
+			return pod.CreationTimestamp.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// finishedAt returns when the last container of the pod terminated, falling
+// back to the pod's start or creation time
+func finishedAt(pod *corev1.Pod) time.Time {
+	var finished time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(finished) {
+			finished = terminated.FinishedAt.Time
+		}
+	}
+	if !finished.IsZero() {
+		return finished
+	}
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// InitContainerFailures returns "container: reason" for each init container
+// that cannot start or exited with an error
+func InitContainerFailures(pod *corev1.Pod) []string {
+	var failures []string
+	for _, status := range pod.Status.InitContainerStatuses {
+		switch {
+		case status.State.Waiting != nil && initWaitingFailures[status.State.Waiting.Reason]:
+			failures = append(failures, fmt.Sprintf("%s: %s", status.Name, status.State.Waiting.Reason))
+
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			reason := status.State.Terminated.Reason
+			if reason == "" {
+				reason = "Error"
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s (exit code %d)", status.Name, reason, status.State.Terminated.ExitCode))
+		}
+	}
+	return failures
+}
+
+// InitContainerRestarts returns the total restart count of the pod's init
+// containers
+func InitContainerRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.InitContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
+
+// RunningEphemeralContainers returns the names of the ephemeral containers
+// running in the pod, usually debugging sessions
+func RunningEphemeralContainers(pod *corev1.Pod) []string {
+	var names []string
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if status.State.Running != nil {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
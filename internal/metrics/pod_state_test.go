@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodStates(t *testing.T) {
+	now := time.Now()
+	created := metav1.NewTime(now.Add(-time.Hour))
+	deleted := metav1.NewTime(now.Add(-10 * time.Minute))
+	pending := metav1.NewTime(now.Add(time.Minute))
+	finished := metav1.NewTime(now.Add(-2 * time.Hour))
+
+	tests := []struct {
+		name   string
+		pod    corev1.Pod
+		want   []string
+		state  string
+		minAge time.Duration
+		inAge  bool
+	}{
+		{
+			name: "image pull backoff",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"}},
+				}}},
+			},
+			want:   []string{PodStateImagePullBackOff},
+			state:  PodStateImagePullBackOff,
+			minAge: 5 * time.Minute,
+			inAge:  true,
+		},
+		{
+			name:   "terminating past grace period",
+			pod:    corev1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted}},
+			want:   []string{PodStateTerminating},
+			state:  PodStateTerminating,
+			minAge: 15 * time.Minute,
+			inAge:  false,
+		},
+		{
+			name:  "terminating within grace period",
+			pod:   corev1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &pending}},
+			want:  nil,
+			state: PodStateTerminating,
+		},
+		{
+			name: "evicted",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodFailed, Reason: "Evicted", StartTime: &finished,
+			}},
+			want:   []string{PodStateEvicted},
+			state:  PodStateEvicted,
+			minAge: time.Hour,
+			inAge:  true,
+		},
+		{
+			name: "completed",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: finished}},
+				}},
+			}},
+			want:   []string{PodStateCompleted},
+			state:  PodStateCompleted,
+			minAge: 3 * time.Hour,
+			inAge:  false,
+		},
+		{
+			name: "init container crash looping",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status: corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "migrate",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}}},
+			},
+			want:   []string{PodStateInitFailure},
+			state:  PodStateInitFailure,
+			minAge: 10 * time.Minute,
+			inAge:  true,
+		},
+		{
+			name: "init container still running",
+			pod: corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "migrate",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}}}},
+			want:  nil,
+			state: PodStateInitFailure,
+		},
+		{
+			name:  "running",
+			pod:   corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			want:  nil,
+			state: PodStateCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PodStates(&tt.pod, now))
+			assert.Equal(t, tt.inAge, PodInState(&tt.pod, tt.state, tt.minAge, now))
+		})
+	}
+}
+
+func TestInitAndEphemeralContainers(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{
+		InitContainerStatuses: []corev1.ContainerStatus{
+			{Name: "config", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+			{Name: "wait-for-db", RestartCount: 4, State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+			}},
+			{Name: "fetch", RestartCount: 1, State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"},
+			}},
+			{Name: "pending", State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+			}},
+		},
+		EphemeralContainerStatuses: []corev1.ContainerStatus{
+			{Name: "debugger", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			{Name: "old-debugger", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+		},
+	}}
+
+	assert.Equal(t, []string{"wait-for-db: Error (exit code 1)", "fetch: ErrImagePull"}, InitContainerFailures(pod))
+	assert.Equal(t, int32(5), InitContainerRestarts(pod))
+	assert.Equal(t, []string{"debugger"}, RunningEphemeralContainers(pod))
+}
@@ -2,9 +2,11 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
@@ -26,10 +28,19 @@ var (
 	)
 )
 
+// errNoData is returned by instant queries without samples
+var errNoData = errors.New("query returned no data")
+
 // PrometheusClient wraps Prometheus API client
 type PrometheusClient struct {
 	api     promv1.API
+	address string
 	timeout time.Duration
+	cache   *QueryCache // Optional query result cache
+
+	// baselines of the current hour, see Baseline
+	baselineMu sync.Mutex
+	baselines  map[baselineKey]baselineValue
 }
 
 // NewPrometheusClient creates a new Prometheus client
@@ -49,19 +60,44 @@ func NewPrometheusClient(address string, timeout time.Duration) (*PrometheusClie
 
 	return &PrometheusClient{
 		api:     promv1.NewAPI(client),
+		address: address,
 		timeout: timeout,
 	}, nil
 }
 
+// WithCache serves repeated queries from cache within its TTL
+func (p *PrometheusClient) WithCache(cache *QueryCache) {
+	p.cache = cache
+}
+
 // Query executes a PromQL query and returns the result as a float64
 func (p *PrometheusClient) Query(ctx context.Context, query string) (float64, error) {
+	if p.cache == nil {
+		return p.query(ctx, query)
+	}
+	value, err := p.cache.Do(ctx, queryCacheKey{datasource: p.address, query: query}, func(ctx context.Context) (interface{}, error) {
+		return p.query(ctx, query)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return value.(float64), nil
+}
+
+// query executes an instant query against Prometheus
+func (p *PrometheusClient) query(ctx context.Context, query string) (float64, error) {
+	return p.queryAt(ctx, query, time.Now())
+}
+
+// queryAt executes an instant query evaluated at ts against Prometheus
+func (p *PrometheusClient) queryAt(ctx context.Context, query string, ts time.Time) (float64, error) {
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
 	log := log.FromContext(ctx)
-	log.V(1).Info("Executing Prometheus query", "query", query)
+	log.V(1).Info("Executing Prometheus query", "query", query, "time", ts)
 
-	result, warnings, err := p.api.Query(ctx, query, time.Now())
+	result, warnings, err := p.api.Query(ctx, query, ts)
 	if err != nil {
 		return 0, fmt.Errorf("prometheus query failed: %w", err)
 	}
@@ -81,6 +117,23 @@ func (p *PrometheusClient) Query(ctx context.Context, query string) (float64, er
 
 // QueryRange executes a range query (useful for checking if condition held for duration)
 func (p *PrometheusClient) QueryRange(ctx context.Context, query string, duration time.Duration) ([]float64, error) {
+	step := duration / 10 // 10 data points
+	if p.cache == nil {
+		return p.queryRange(ctx, query, duration, step)
+	}
+	value, err := p.cache.Do(ctx, queryCacheKey{datasource: p.address, query: query, step: step}, func(ctx context.Context) (interface{}, error) {
+		return p.queryRange(ctx, query, duration, step)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Callers must not modify the cached values
+	return append([]float64(nil), value.([]float64)...), nil
+}
+
+// queryRange executes a range query over the last duration against
+// Prometheus
+func (p *PrometheusClient) queryRange(ctx context.Context, query string, duration, step time.Duration) ([]float64, error) {
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
@@ -89,7 +142,6 @@ func (p *PrometheusClient) QueryRange(ctx context.Context, query string, duratio
 
 	end := time.Now()
 	start := end.Add(-duration)
-	step := duration / 10 // 10 data points
 
 	result, warnings, err := p.api.QueryRange(ctx, query, promv1.Range{
 		Start: start,
@@ -127,7 +179,7 @@ func (p *PrometheusClient) extractValue(result model.Value) (float64, error) {
 	switch v := result.(type) {
 	case model.Vector:
 		if len(v) == 0 {
-			return 0, fmt.Errorf("query returned no data")
+			return 0, errNoData
 		}
 		// Take the first result
 		return float64(v[0].Value), nil
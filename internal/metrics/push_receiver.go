@@ -0,0 +1,416 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// CustomMetricPrefix marks MetricTrigger queries answered by pushed
+	// metrics: "custom:<name>" is the highest value across the workloads in
+	// the policy's namespaces, "custom:<name>:<namespace>/<workload>" the
+	// value of a single workload
+	CustomMetricPrefix = v1alpha1.PushedQueryPrefix
+
+	// defaultPushTTL is used when no TTL is configured
+	defaultPushTTL = 5 * time.Minute
+
+	// maxPushBodyBytes bounds OTLP request bodies
+	maxPushBodyBytes = 1 << 20
+
+	// maxStatsDPacketBytes bounds StatsD datagrams
+	maxStatsDPacketBytes = 64 * 1024
+
+	// otlpMetricsPath is the OTLP/HTTP metrics endpoint
+	otlpMetricsPath = "/v1/metrics"
+)
+
+// otlpWorkloadAttributes are the resource attributes naming a workload, in
+// order of preference
+var otlpWorkloadAttributes = []string{
+	"k8s.deployment.name",
+	"k8s.statefulset.name",
+	"k8s.daemonset.name",
+	"k8s.job.name",
+	"workload",
+	"service.name",
+}
+
+// pushedSample is the latest value pushed for a workload
+type pushedSample struct {
+	value float64
+	at    time.Time
+}
+
+// PushReceiver accepts application metrics pushed over StatsD (UDP) or
+// OTLP/HTTP with JSON encoding, keyed by namespace and workload. Samples
+// expire after the TTL so stale values do not keep triggers firing.
+type PushReceiver struct {
+	statsdAddr string
+	otlpAddr   string
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	samples map[string]map[string]pushedSample // name -> namespace/workload -> sample
+}
+
+// NewPushReceiver creates a new push receiver. Either address may be empty
+// to disable that protocol.
+func NewPushReceiver(statsdAddr, otlpAddr string, ttl time.Duration) *PushReceiver {
+	if ttl <= 0 {
+		ttl = defaultPushTTL
+	}
+	return &PushReceiver{
+		statsdAddr: statsdAddr,
+		otlpAddr:   otlpAddr,
+		ttl:        ttl,
+		now:        time.Now,
+		samples:    make(map[string]map[string]pushedSample),
+	}
+}
+
+// NeedLeaderElection is false so every replica accepts pushes
+func (r *PushReceiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the configured protocols until the context is cancelled
+func (r *PushReceiver) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("push-receiver")
+	errs := make(chan error, 2)
+
+	if r.statsdAddr != "" {
+		conn, err := net.ListenPacket("udp", r.statsdAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen for StatsD on %s: %w", r.statsdAddr, err)
+		}
+		go func() {
+			<-ctx.Done()
+			conn.Close()
+		}()
+		go func() { errs <- r.serveStatsD(ctx, conn) }()
+		log.Info("Accepting StatsD metrics", "address", r.statsdAddr)
+	}
+
+	if r.otlpAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(otlpMetricsPath, r)
+		server := &http.Server{Addr: r.otlpAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}()
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- fmt.Errorf("OTLP receiver failed: %w", err)
+				return
+			}
+			errs <- nil
+		}()
+		log.Info("Accepting OTLP metrics", "address", r.otlpAddr, "path", otlpMetricsPath)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errs:
+		return err
+	}
+}
+
+// serveStatsD reads StatsD datagrams until the connection is closed
+func (r *PushReceiver) serveStatsD(ctx context.Context, conn net.PacketConn) error {
+	buf := make([]byte, maxStatsDPacketBytes)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("StatsD receiver failed: %w", err)
+		}
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			if err := r.handleStatsD(line); err != nil {
+				log.FromContext(ctx).V(1).Info("Ignoring StatsD line", "line", line, "reason", err.Error())
+			}
+		}
+	}
+}
+
+// handleStatsD records a "<name>:<value>|<g|c>[|@rate][|#namespace:ns,workload:w]" line
+func (r *PushReceiver) handleStatsD(line string) error {
+	nameValue, rest, ok := strings.Cut(line, "|")
+	if !ok {
+		return fmt.Errorf("missing metric type")
+	}
+	name, rawValue, ok := strings.Cut(nameValue, ":")
+	if !ok || name == "" {
+		return fmt.Errorf("missing metric name or value")
+	}
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %w", rawValue, err)
+	}
+
+	fields := strings.Split(rest, "|")
+	metricType := fields[0]
+	rate := 1.0
+	tags := make(map[string]string)
+	for _, field := range fields[1:] {
+		switch {
+		case strings.HasPrefix(field, "@"):
+			if rate, err = strconv.ParseFloat(field[1:], 64); err != nil || rate <= 0 {
+				return fmt.Errorf("invalid sample rate %q", field)
+			}
+		case strings.HasPrefix(field, "#"):
+			for _, tag := range strings.Split(field[1:], ",") {
+				key, val, _ := strings.Cut(tag, ":")
+				tags[key] = val
+			}
+		}
+	}
+
+	namespace := tags["namespace"]
+	if namespace == "" {
+		return fmt.Errorf("missing namespace tag")
+	}
+	workload := tags["workload"]
+
+	switch metricType {
+	case "g":
+		// Signed gauge values adjust the current value
+		if strings.HasPrefix(rawValue, "+") || strings.HasPrefix(rawValue, "-") {
+			r.add(name, namespace, workload, value)
+			return nil
+		}
+		r.Record(name, namespace, workload, value)
+	case "c":
+		r.add(name, namespace, workload, value/rate)
+	default:
+		return fmt.Errorf("unsupported metric type %q", metricType)
+	}
+	return nil
+}
+
+// otlpRequest is the subset of an OTLP/HTTP JSON metrics export request
+// used by the receiver
+type otlpRequest struct {
+	ResourceMetrics []struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeMetrics []struct {
+			Metrics []struct {
+				Name  string          `json:"name"`
+				Gauge *otlpDataPoints `json:"gauge"`
+				Sum   *otlpDataPoints `json:"sum"`
+			} `json:"metrics"`
+		} `json:"scopeMetrics"`
+	} `json:"resourceMetrics"`
+}
+
+type otlpDataPoints struct {
+	DataPoints []struct {
+		Attributes []otlpAttribute `json:"attributes"`
+		AsDouble   *float64        `json:"asDouble"`
+		// AsInt is a string in the JSON encoding of int64 fields
+		AsInt *json.Number `json:"asInt"`
+	} `json:"dataPoints"`
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+// ServeHTTP accepts OTLP/HTTP metrics export requests in JSON encoding
+func (r *PushReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if contentType := req.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		http.Error(w, "only JSON encoded OTLP is supported", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPushBodyBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var export otlpRequest
+	if err := json.Unmarshal(body, &export); err != nil {
+		http.Error(w, fmt.Sprintf("invalid OTLP request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, rm := range export.ResourceMetrics {
+		resource := attributeMap(rm.Resource.Attributes)
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				points := metric.Gauge
+				if points == nil {
+					points = metric.Sum
+				}
+				if points == nil {
+					continue
+				}
+				for _, dp := range points.DataPoints {
+					attrs := attributeMap(dp.Attributes)
+					for key, val := range resource {
+						if _, ok := attrs[key]; !ok {
+							attrs[key] = val
+						}
+					}
+					namespace := attrs["k8s.namespace.name"]
+					if namespace == "" {
+						continue
+					}
+
+					var value float64
+					switch {
+					case dp.AsDouble != nil:
+						value = *dp.AsDouble
+					case dp.AsInt != nil:
+						if value, err = dp.AsInt.Float64(); err != nil {
+							continue
+						}
+					default:
+						continue
+					}
+					r.Record(metric.Name, namespace, otlpWorkload(attrs), value)
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+// attributeMap converts OTLP attributes to a map of their string values
+func attributeMap(attributes []otlpAttribute) map[string]string {
+	m := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		m[attr.Key] = attr.Value.StringValue
+	}
+	return m
+}
+
+// otlpWorkload returns the workload named by the attributes
+func otlpWorkload(attrs map[string]string) string {
+	for _, key := range otlpWorkloadAttributes {
+		if name := attrs[key]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Record sets the value of a metric for a workload
+func (r *PushReceiver) Record(name, namespace, workload string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.set(name, namespace+"/"+workload, value)
+}
+
+// add adds to the value of a metric for a workload, starting from zero if
+// the previous value expired
+func (r *PushReceiver) add(name, namespace, workload string, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := namespace + "/" + workload
+	current := 0.0
+	if sample, ok := r.samples[name][key]; ok && r.now().Sub(sample.at) <= r.ttl {
+		current = sample.value
+	}
+	r.set(name, key, current+delta)
+}
+
+func (r *PushReceiver) set(name, key string, value float64) {
+	workloads, ok := r.samples[name]
+	if !ok {
+		workloads = make(map[string]pushedSample)
+		r.samples[name] = workloads
+	}
+	workloads[key] = pushedSample{value: value, at: r.now()}
+}
+
+// Size returns the number of pushed samples held
+func (r *PushReceiver) Size() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	size := 0
+	for _, workloads := range r.samples {
+		size += len(workloads)
+	}
+	return size
+}
+
+// Values returns the unexpired pushed metrics of the namespaces (all
+// namespaces if empty) keyed as CustomMetricPrefix queries
+func (r *PushReceiver) Values(namespaces []string) map[string]float64 {
+	included := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		included[namespace] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	values := make(map[string]float64)
+	names := make([]string, 0, len(r.samples))
+	for name := range r.samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		workloads := r.samples[name]
+		for key, sample := range workloads {
+			if now.Sub(sample.at) > r.ttl {
+				delete(workloads, key)
+				continue
+			}
+			namespace, _, _ := strings.Cut(key, "/")
+			if len(included) > 0 && !included[namespace] {
+				continue
+			}
+
+			values[CustomMetricPrefix+name+":"+key] = sample.value
+			aggregate := CustomMetricPrefix + name
+			if current, ok := values[aggregate]; !ok || sample.value > current {
+				values[aggregate] = sample.value
+			}
+		}
+		if len(workloads) == 0 {
+			delete(r.samples, name)
+		}
+	}
+	return values
+}
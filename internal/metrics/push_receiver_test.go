@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestPushReceiver_StatsD(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{
+			name:  "gauge",
+			lines: []string{"queue_depth:42|g|#namespace:apps,workload:worker"},
+			want: map[string]float64{
+				"custom:queue_depth":             42,
+				"custom:queue_depth:apps/worker": 42,
+			},
+		},
+		{
+			name: "gauge adjustments",
+			lines: []string{
+				"queue_depth:10|g|#namespace:apps,workload:worker",
+				"queue_depth:+5|g|#namespace:apps,workload:worker",
+				"queue_depth:-3|g|#namespace:apps,workload:worker",
+			},
+			want: map[string]float64{
+				"custom:queue_depth":             12,
+				"custom:queue_depth:apps/worker": 12,
+			},
+		},
+		{
+			name: "sampled counter",
+			lines: []string{
+				"jobs_failed:1|c|@0.5|#namespace:apps,workload:worker",
+				"jobs_failed:2|c|#namespace:apps,workload:worker",
+			},
+			want: map[string]float64{
+				"custom:jobs_failed":             4,
+				"custom:jobs_failed:apps/worker": 4,
+			},
+		},
+		{
+			name: "aggregate is the highest workload value",
+			lines: []string{
+				"job_lag:30|g|#namespace:apps,workload:a",
+				"job_lag:90|g|#namespace:apps,workload:b",
+			},
+			want: map[string]float64{
+				"custom:job_lag":        90,
+				"custom:job_lag:apps/a": 30,
+				"custom:job_lag:apps/b": 90,
+			},
+		},
+		{
+			name:    "missing namespace",
+			lines:   []string{"queue_depth:42|g|#workload:worker"},
+			want:    map[string]float64{},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			lines:   []string{"latency:12|ms|#namespace:apps"},
+			want:    map[string]float64{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := NewPushReceiver("", "", time.Minute)
+			for _, line := range tt.lines {
+				err := receiver.handleStatsD(line)
+				if tt.wantErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+			assert.Equal(t, tt.want, receiver.Values(nil))
+		})
+	}
+}
+
+func TestPushReceiver_OTLP(t *testing.T) {
+	receiver := NewPushReceiver("", "", time.Minute)
+
+	body := `{"resourceMetrics":[{
+		"resource":{"attributes":[
+			{"key":"k8s.namespace.name","value":{"stringValue":"apps"}},
+			{"key":"k8s.deployment.name","value":{"stringValue":"worker"}}
+		]},
+		"scopeMetrics":[{"metrics":[
+			{"name":"queue_depth","gauge":{"dataPoints":[{"asDouble":7.5}]}},
+			{"name":"jobs_total","sum":{"dataPoints":[{"asInt":"12"}]}}
+		]}]
+	}]}`
+	req := httptest.NewRequest(http.MethodPost, otlpMetricsPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	values := receiver.Values([]string{"apps"})
+	assert.Equal(t, 7.5, values["custom:queue_depth:apps/worker"])
+	assert.Equal(t, 12.0, values["custom:jobs_total"])
+
+	// Other namespaces are filtered out
+	assert.Empty(t, receiver.Values([]string{"other"}))
+
+	// Protobuf encoding is not supported
+	req = httptest.NewRequest(http.MethodPost, otlpMetricsPath, strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	rec = httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestPushReceiver_Expiry(t *testing.T) {
+	now := time.Now()
+	receiver := NewPushReceiver("", "", time.Minute)
+	receiver.now = func() time.Time { return now }
+
+	receiver.Record("queue_depth", "apps", "worker", 5)
+	assert.Len(t, receiver.Values(nil), 2)
+
+	now = now.Add(2 * time.Minute)
+	assert.Empty(t, receiver.Values(nil))
+	assert.Empty(t, receiver.samples)
+}
+
+func TestCollector_CustomMetricTrigger(t *testing.T) {
+	receiver := NewPushReceiver("", "", time.Minute)
+	receiver.Record("queue_depth", "apps", "worker", 150)
+	receiver.Record("queue_depth", "other", "worker", 900)
+
+	collector := NewCollector(nil, nil, nil)
+	collector.WithPushReceiver(receiver)
+
+	metrics := &types.ClusterMetrics{Custom: receiver.Values([]string{"apps"})}
+
+	result, err := collector.evaluateMetricTrigger(context.Background(),
+		&v1alpha1.MetricTrigger{Query: "custom:queue_depth", Operator: ">", Threshold: 100}, metrics)
+	assert.NoError(t, err)
+	assert.True(t, result.Triggered)
+	assert.Contains(t, result.Reason, "150.00")
+	assert.True(t, result.Observed)
+	assert.Equal(t, 150.0, result.Value)
+
+	result, err = collector.evaluateMetricTrigger(context.Background(),
+		&v1alpha1.MetricTrigger{Query: "custom:queue_depth:apps/worker", Operator: ">", Threshold: 200}, metrics)
+	assert.NoError(t, err)
+	assert.False(t, result.Triggered)
+
+	result, err = collector.evaluateMetricTrigger(context.Background(),
+		&v1alpha1.MetricTrigger{Query: "custom:job_lag", Operator: ">", Threshold: 1}, metrics)
+	assert.NoError(t, err)
+	assert.False(t, result.Triggered)
+	assert.False(t, result.Observed)
+	assert.Contains(t, result.Reason, "no pushed samples")
+
+	// An explicit datasource needs no prefix
+	result, err = collector.evaluateMetricTrigger(context.Background(),
+		&v1alpha1.MetricTrigger{Query: "queue_depth", Datasource: v1alpha1.DatasourcePushed, Operator: ">", Threshold: 100}, metrics)
+	assert.NoError(t, err)
+	assert.True(t, result.Triggered)
+	assert.Equal(t, 150.0, result.Value)
+}
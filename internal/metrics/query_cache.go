@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// queryCacheRequests counts cached queries by result - initialized by
+	// SetQueryCacheMetric from main.go
+	queryCacheRequests *prometheus.CounterVec
+)
+
+// SetQueryCacheMetric sets the query cache metric from main.go
+func SetQueryCacheMetric(metric *prometheus.CounterVec) {
+	queryCacheRequests = metric
+}
+
+// queryCacheKey identifies a query result; step is 0 for instant queries
+type queryCacheKey struct {
+	datasource string
+	query      string
+	step       time.Duration
+}
+
+func (k queryCacheKey) String() string {
+	return fmt.Sprintf("%s\x00%s\x00%d", k.datasource, k.query, k.step)
+}
+
+type queryCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// QueryCache keeps query results for a short TTL so that identical queries
+// issued by several triggers and policies in a cycle reach the datasource
+// once. Concurrent identical queries share one request. Failed queries are
+// not cached.
+type QueryCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[queryCacheKey]queryCacheEntry
+	group   singleflight.Group
+	now     func() time.Time
+}
+
+// NewQueryCache creates a query cache keeping results for ttl
+func NewQueryCache(ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		ttl:     ttl,
+		entries: make(map[queryCacheKey]queryCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// Do returns the cached result for key, or runs query once for all callers
+// asking for key concurrently and caches its result
+func (c *QueryCache) Do(ctx context.Context, key queryCacheKey, query func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+	if found && c.now().Before(entry.expires) {
+		recordQueryCache("hit")
+		return entry.value, nil
+	}
+
+	value, err, shared := c.group.Do(key.String(), func() (interface{}, error) {
+		value, err := query(ctx)
+		if err == nil {
+			c.store(key, value)
+		}
+		return value, err
+	})
+	if shared {
+		recordQueryCache("shared")
+	} else {
+		recordQueryCache("miss")
+	}
+	return value, err
+}
+
+// store caches a result and drops the expired ones
+func (c *QueryCache) store(key queryCacheKey, value interface{}) {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = queryCacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+func recordQueryCache(result string) {
+	if queryCacheRequests != nil {
+		queryCacheRequests.WithLabelValues(result).Inc()
+	}
+}
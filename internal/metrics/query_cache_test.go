@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useQueryCacheMetric installs a fresh query cache metric for a test
+func useQueryCacheMetric(t *testing.T) *prometheus.CounterVec {
+	metric := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_query_cache_requests_total"},
+		[]string{"result"})
+	previous := queryCacheRequests
+	SetQueryCacheMetric(metric)
+	t.Cleanup(func() { SetQueryCacheMetric(previous) })
+	return metric
+}
+
+func TestQueryCache(t *testing.T) {
+	requests := useQueryCacheMetric(t)
+	cache := NewQueryCache(15 * time.Second)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	var calls int
+	query := func(value float64, err error) func(context.Context) (interface{}, error) {
+		return func(context.Context) (interface{}, error) {
+			calls++
+			return value, err
+		}
+	}
+	errorRate := queryCacheKey{datasource: "http://prometheus:9090", query: "error_rate"}
+
+	value, err := cache.Do(ctx, errorRate, query(0.5, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+
+	// Identical queries within the TTL are served from the cache
+	value, err = cache.Do(ctx, errorRate, query(0.7, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+	assert.Equal(t, 1, calls)
+
+	// The datasource and step are part of the key
+	for _, key := range []queryCacheKey{
+		{datasource: "http://thanos:9090", query: "error_rate"},
+		{datasource: "http://prometheus:9090", query: "error_rate", step: time.Minute},
+	} {
+		_, err = cache.Do(ctx, key, query(0.9, nil))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, calls)
+
+	// Expired results are queried again and dropped
+	now = now.Add(16 * time.Second)
+	value, err = cache.Do(ctx, errorRate, query(0.7, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0.7, value)
+	assert.Len(t, cache.entries, 1)
+
+	// Failures are not cached
+	now = now.Add(16 * time.Second)
+	_, err = cache.Do(ctx, errorRate, query(0, errors.New("timeout")))
+	assert.Error(t, err)
+	value, err = cache.Do(ctx, errorRate, query(0.8, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, value)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(requests.WithLabelValues("hit")))
+	assert.Equal(t, 6.0, testutil.ToFloat64(requests.WithLabelValues("miss")))
+}
+
+func TestQueryCache_SharesConcurrentQueries(t *testing.T) {
+	requests := useQueryCacheMetric(t)
+	cache := NewQueryCache(time.Minute)
+	key := queryCacheKey{datasource: "http://prometheus:9090", query: "up"}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.Do(context.Background(), key, func(context.Context) (interface{}, error) {
+				calls.Add(1)
+				<-release
+				return 1.0, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 1.0, value)
+		}()
+	}
+	// Wait until the callers joined the running query
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 5.0, testutil.ToFloat64(requests.WithLabelValues("shared"))+
+		testutil.ToFloat64(requests.WithLabelValues("hit")))
+}
+
+func TestPrometheusClient_QueryCache(t *testing.T) {
+	useQueryCacheMetric(t)
+	var queries atomic.Int32
+	server := mockPrometheusServer(t)
+	defer server.Close()
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer counting.Close()
+
+	client, err := NewPrometheusClient(counting.URL, 10*time.Second)
+	require.NoError(t, err)
+	client.WithCache(NewQueryCache(time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		value, err := client.Query(ctx, "up")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, value)
+	}
+	assert.Equal(t, int32(1), queries.Load())
+
+	values, err := client.QueryRange(ctx, "up", 5*time.Minute)
+	require.NoError(t, err)
+	values[0] = -1
+	values, err = client.QueryRange(ctx, "up", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{100, 110, 120}, values, "cached values are copied")
+	assert.Equal(t, int32(2), queries.Load())
+}
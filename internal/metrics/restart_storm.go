@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// defaultStormMinRestarts is used when a trigger does not set MinRestarts
+	defaultStormMinRestarts = 20
+
+	// defaultStormMinPods is used when a trigger does not set MinPods
+	defaultStormMinPods = 5
+
+	// defaultStormWindow is used when a trigger does not set a window
+	defaultStormWindow = 5 * time.Minute
+
+	// maxRestartSampleAge bounds how long restart samples are kept
+	maxRestartSampleAge = time.Hour
+)
+
+// restartSample is a pod's cumulative restart count at a point in time
+type restartSample struct {
+	at    time.Time
+	count int32
+}
+
+// RestartStormDetector tracks pod restart counts across evaluations to
+// detect namespace-wide restart storms
+type RestartStormDetector struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	samples map[string][]restartSample
+}
+
+// NewRestartStormDetector creates a new restart storm detector
+func NewRestartStormDetector() *RestartStormDetector {
+	return &RestartStormDetector{
+		now:     time.Now,
+		samples: make(map[string][]restartSample),
+	}
+}
+
+// Size returns the number of restart samples held
+func (d *RestartStormDetector) Size() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	size := 0
+	for _, samples := range d.samples {
+		size += len(samples)
+	}
+	return size
+}
+
+// Evaluate records the restart counts of the pods in metrics and fires if a
+// namespace saw at least MinRestarts restarts across MinPods distinct pods
+// within the window. Restarts are counted from the earliest sample in the
+// window, so a pod's restarts before it was first observed are not counted.
+func (d *RestartStormDetector) Evaluate(trigger *v1alpha1.RestartStormTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
+	minRestarts := trigger.MinRestarts
+	if minRestarts <= 0 {
+		minRestarts = defaultStormMinRestarts
+	}
+	minPods := trigger.MinPods
+	if minPods <= 0 {
+		minPods = defaultStormMinPods
+	}
+	window := trigger.Window.Duration
+	if window <= 0 {
+		window = defaultStormWindow
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	restarts := make(map[string]int32)
+	pods := make(map[string]int32)
+	for _, pod := range metrics.Pods {
+		key := pod.Namespace + "/" + pod.Name
+		delta := d.record(key, now, pod.RestartCount, window)
+		if delta > 0 {
+			restarts[pod.Namespace] += delta
+			pods[pod.Namespace]++
+		}
+	}
+	d.prune(now)
+
+	namespaces := make([]string, 0, len(restarts))
+	for namespace := range restarts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		if restarts[namespace] >= minRestarts && pods[namespace] >= minPods {
+			return true, fmt.Sprintf("restart storm in namespace %s: %d restarts across %d pods in %s",
+				namespace, restarts[namespace], pods[namespace], window), nil
+		}
+	}
+
+	return false, "", nil
+}
+
+// record stores a sample and returns the restarts since the earliest sample
+// within the window
+func (d *RestartStormDetector) record(key string, now time.Time, count int32, window time.Duration) int32 {
+	samples := d.samples[key]
+
+	// A lower count means the pod was recreated under the same name
+	if n := len(samples); n > 0 && count < samples[n-1].count {
+		samples = nil
+	}
+	samples = append(samples, restartSample{at: now, count: count})
+	d.samples[key] = samples
+
+	baseline := samples[len(samples)-1]
+	for _, sample := range samples {
+		if now.Sub(sample.at) <= window {
+			baseline = sample
+			break
+		}
+	}
+	return count - baseline.count
+}
+
+// prune drops samples older than maxRestartSampleAge, forgetting pods that
+// have not been observed since
+func (d *RestartStormDetector) prune(now time.Time) {
+	for key, samples := range d.samples {
+		i := 0
+		for i < len(samples) && now.Sub(samples[i].at) > maxRestartSampleAge {
+			i++
+		}
+		if i == len(samples) {
+			delete(d.samples, key)
+			continue
+		}
+		d.samples[key] = samples[i:]
+	}
+}
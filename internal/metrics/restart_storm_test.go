@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func stormMetrics(namespace string, restarts ...int32) *types.ClusterMetrics {
+	metrics := &types.ClusterMetrics{}
+	for i, count := range restarts {
+		metrics.Pods = append(metrics.Pods, types.PodMetrics{
+			Name:         fmt.Sprintf("pod-%d", i),
+			Namespace:    namespace,
+			RestartCount: count,
+		})
+	}
+	return metrics
+}
+
+func TestRestartStormDetector_Evaluate(t *testing.T) {
+	trigger := &v1alpha1.RestartStormTrigger{
+		MinRestarts: 20,
+		MinPods:     5,
+		Window:      metav1.Duration{Duration: 5 * time.Minute},
+	}
+
+	now := time.Now()
+	detector := NewRestartStormDetector()
+	detector.now = func() time.Time { return now }
+
+	// The first observation establishes the baseline
+	triggered, _, err := detector.Evaluate(trigger, stormMetrics("apps", 10, 10, 10, 10, 10, 10))
+	assert.NoError(t, err)
+	assert.False(t, triggered)
+
+	// Many restarts concentrated on a few pods are not a storm
+	now = now.Add(time.Minute)
+	triggered, _, _ = detector.Evaluate(trigger, stormMetrics("apps", 30, 20, 10, 10, 10, 10))
+	assert.False(t, triggered)
+
+	// Restarts spread across six pods are
+	now = now.Add(time.Minute)
+	triggered, reason, _ := detector.Evaluate(trigger, stormMetrics("apps", 32, 22, 13, 13, 13, 13))
+	assert.True(t, triggered)
+	assert.Equal(t, "restart storm in namespace apps: 46 restarts across 6 pods in 5m0s", reason)
+
+	// Restarts older than the window no longer count
+	now = now.Add(10 * time.Minute)
+	triggered, _, _ = detector.Evaluate(trigger, stormMetrics("apps", 32, 22, 13, 13, 13, 13))
+	assert.False(t, triggered)
+}
+
+func TestRestartStormDetector_RecreatedPod(t *testing.T) {
+	detector := NewRestartStormDetector()
+	trigger := &v1alpha1.RestartStormTrigger{MinRestarts: 1, MinPods: 1}
+
+	detector.Evaluate(trigger, stormMetrics("apps", 50))
+	// A recreated pod starts counting from zero again
+	triggered, _, _ := detector.Evaluate(trigger, stormMetrics("apps", 0))
+	assert.False(t, triggered)
+	triggered, _, _ = detector.Evaluate(trigger, stormMetrics("apps", 2))
+	assert.True(t, triggered)
+}
+
+func TestRestartStormDetector_Prune(t *testing.T) {
+	now := time.Now()
+	detector := NewRestartStormDetector()
+	detector.now = func() time.Time { return now }
+
+	detector.Evaluate(&v1alpha1.RestartStormTrigger{}, stormMetrics("apps", 1, 1))
+	assert.Len(t, detector.samples, 2)
+
+	now = now.Add(2 * maxRestartSampleAge)
+	detector.Evaluate(&v1alpha1.RestartStormTrigger{}, stormMetrics("other", 1))
+	assert.Len(t, detector.samples, 1)
+}
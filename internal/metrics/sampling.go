@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// SamplePods returns a copy of the metrics holding at most maxPods pods.
+// Pods named in keep (namespace/name) are sampled first, then pods that look
+// unhealthy by restart count and phase, so the sample keeps the pods most
+// likely to matter for healing. A maxPods of zero or less keeps every pod.
+// The input is not modified.
+func SamplePods(metrics *types.ClusterMetrics, maxPods int, keep map[string]bool) *types.ClusterMetrics {
+	if metrics == nil || maxPods <= 0 || len(metrics.Pods) <= maxPods {
+		return metrics
+	}
+
+	pods := make([]types.PodMetrics, len(metrics.Pods))
+	copy(pods, metrics.Pods)
+
+	rank := func(pod types.PodMetrics) int {
+		switch {
+		case keep[pod.Namespace+"/"+pod.Name]:
+			return 0
+		case pod.Status != "" && pod.Status != "Running" && pod.Status != "Succeeded":
+			return 1
+		case pod.RestartCount > 0:
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		ri, rj := rank(pods[i]), rank(pods[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return pods[i].RestartCount > pods[j].RestartCount
+	})
+
+	sampled := *metrics
+	sampled.Pods = pods[:maxPods]
+	return &sampled
+}
+
+// CollectSampledAdvancedMetrics is CollectAdvancedMetrics restricted to a
+// sample of at most maxPods pods, bounding the cost of trend analysis and
+// pattern detection
+func (ac *AdvancedCollector) CollectSampledAdvancedMetrics(ctx context.Context, policy *v1alpha1.HealingPolicy, maxPods int) (*AdvancedMetrics, error) {
+	if maxPods <= 0 {
+		return ac.CollectAdvancedMetrics(ctx, policy)
+	}
+	return ac.collectAdvancedMetrics(ctx, policy, func(metrics *types.ClusterMetrics) *types.ClusterMetrics {
+		return SamplePods(metrics, maxPods, nil)
+	})
+}
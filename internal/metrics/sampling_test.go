@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestSamplePods(t *testing.T) {
+	metrics := &types.ClusterMetrics{
+		Pods: []types.PodMetrics{
+			{Name: "healthy-a", Namespace: "default", Status: "Running"},
+			{Name: "restarting", Namespace: "default", Status: "Running", RestartCount: 3},
+			{Name: "healthy-b", Namespace: "default", Status: "Running"},
+			{Name: "pending", Namespace: "default", Status: "Pending"},
+			{Name: "targeted", Namespace: "default", Status: "Running"},
+		},
+	}
+
+	names := func(m *types.ClusterMetrics) []string {
+		var out []string
+		for _, pod := range m.Pods {
+			out = append(out, pod.Name)
+		}
+		return out
+	}
+
+	t.Run("keeps targeted then unhealthy pods", func(t *testing.T) {
+		sampled := SamplePods(metrics, 3, map[string]bool{"default/targeted": true})
+		assert.Equal(t, []string{"targeted", "pending", "restarting"}, names(sampled))
+		assert.Len(t, metrics.Pods, 5)
+		assert.Equal(t, "healthy-a", metrics.Pods[0].Name)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		assert.Same(t, metrics, SamplePods(metrics, 0, nil))
+		assert.Same(t, metrics, SamplePods(metrics, 10, nil))
+	})
+}
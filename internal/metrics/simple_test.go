@@ -53,3 +53,22 @@ func TestCollectMetrics_Basic(t *testing.T) {
 	assert.NotNil(t, metrics.Resources)
 	assert.NotNil(t, metrics.Custom)
 }
+
+func TestAdvancedCollector_EvaluateAdvancedTrigger(t *testing.T) {
+	collector := NewAdvancedCollector(NewCollector(nil, nil, nil))
+	trigger := &v1alpha1.HealingTrigger{
+		Name: "health",
+		Type: "metric",
+		MetricTrigger: &v1alpha1.MetricTrigger{
+			Query:     "system_health_score",
+			Threshold: 50,
+			Operator:  "<",
+		},
+	}
+
+	result, err := collector.EvaluateAdvancedTrigger(context.Background(), trigger, &AdvancedMetrics{SystemHealthScore: 32})
+	assert.NoError(t, err)
+	assert.True(t, result.Triggered)
+	assert.True(t, result.Observed)
+	assert.Equal(t, 32.0, result.Value)
+}
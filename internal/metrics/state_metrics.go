@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// stateListTimeout bounds the cache reads of a scrape
+const stateListTimeout = 10 * time.Second
+
+// actionPhases are the phases reported by kubeskippy_healingaction_status_phase
+var actionPhases = []string{
+	v1alpha1.HealingActionPhasePending,
+	v1alpha1.HealingActionPhaseApproved,
+	v1alpha1.HealingActionPhaseInProgress,
+	v1alpha1.HealingActionPhaseVerifying,
+	v1alpha1.HealingActionPhaseSucceeded,
+	v1alpha1.HealingActionPhaseFailed,
+	v1alpha1.HealingActionPhaseCancelled,
+}
+
+var (
+	policyInfoDesc = prometheus.NewDesc(
+		"kubeskippy_healingpolicy_info",
+		"Information about a HealingPolicy",
+		[]string{"namespace", "policy", "mode", "paused", "ai_profile"}, nil,
+	)
+	policyActionsTakenDesc = prometheus.NewDesc(
+		"kubeskippy_healingpolicy_actions_taken",
+		"Number of healing actions the policy has taken",
+		[]string{"namespace", "policy"}, nil,
+	)
+	policyActiveTriggersDesc = prometheus.NewDesc(
+		"kubeskippy_healingpolicy_active_triggers",
+		"Number of the policy's triggers that are firing",
+		[]string{"namespace", "policy"}, nil,
+	)
+	policyLastEvaluatedDesc = prometheus.NewDesc(
+		"kubeskippy_healingpolicy_last_evaluated_timestamp_seconds",
+		"Unix time the policy was last evaluated",
+		[]string{"namespace", "policy"}, nil,
+	)
+	actionInfoDesc = prometheus.NewDesc(
+		"kubeskippy_healingaction_info",
+		"Information about a HealingAction",
+		[]string{"namespace", "action", "policy", "action_type", "target_kind", "target_namespace", "target_name", "dry_run"}, nil,
+	)
+	actionPhaseDesc = prometheus.NewDesc(
+		"kubeskippy_healingaction_status_phase",
+		"The HealingAction's current phase, 1 for the current phase and 0 for the others",
+		[]string{"namespace", "action", "phase"}, nil,
+	)
+	actionCreatedDesc = prometheus.NewDesc(
+		"kubeskippy_healingaction_created_timestamp_seconds",
+		"Unix creation time of the HealingAction",
+		[]string{"namespace", "action"}, nil,
+	)
+	actionAttemptsDesc = prometheus.NewDesc(
+		"kubeskippy_healingaction_status_attempts",
+		"Number of execution attempts of the HealingAction",
+		[]string{"namespace", "action"}, nil,
+	)
+)
+
+// StateCollector exports the state of HealingPolicies and HealingActions
+// as per-object gauges, in the style of kube-state-metrics. Objects are
+// read from the cache on every scrape, so deleted objects disappear from
+// the next scrape.
+type StateCollector struct {
+	reader client.Reader
+}
+
+// NewStateCollector creates a collector reading the objects through reader
+func NewStateCollector(reader client.Reader) *StateCollector {
+	return &StateCollector{reader: reader}
+}
+
+// Describe implements prometheus.Collector
+func (c *StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		policyInfoDesc, policyActionsTakenDesc, policyActiveTriggersDesc, policyLastEvaluatedDesc,
+		actionInfoDesc, actionPhaseDesc, actionCreatedDesc, actionAttemptsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *StateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), stateListTimeout)
+	defer cancel()
+
+	policies := &v1alpha1.HealingPolicyList{}
+	if err := c.reader.List(ctx, policies); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list policies for state metrics")
+	}
+	for i := range policies.Items {
+		collectPolicy(ch, &policies.Items[i])
+	}
+
+	actions := &v1alpha1.HealingActionList{}
+	if err := c.reader.List(ctx, actions); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list actions for state metrics")
+	}
+	for i := range actions.Items {
+		collectAction(ch, &actions.Items[i])
+	}
+}
+
+// collectPolicy sends the gauges of a policy
+func collectPolicy(ch chan<- prometheus.Metric, policy *v1alpha1.HealingPolicy) {
+	ns, name := policy.Namespace, policy.Name
+	profile := ""
+	if policy.Spec.AIProfile != nil {
+		profile = policy.Spec.AIProfile.Mode
+	}
+
+	ch <- prometheus.MustNewConstMetric(policyInfoDesc, prometheus.GaugeValue, 1,
+		ns, name, policy.Spec.Mode, strconv.FormatBool(policy.Spec.Paused), profile)
+	ch <- prometheus.MustNewConstMetric(policyActionsTakenDesc, prometheus.GaugeValue,
+		float64(policy.Status.ActionsTaken), ns, name)
+	ch <- prometheus.MustNewConstMetric(policyActiveTriggersDesc, prometheus.GaugeValue,
+		float64(len(policy.Status.ActiveTriggers)), ns, name)
+	if !policy.Status.LastEvaluated.IsZero() {
+		ch <- prometheus.MustNewConstMetric(policyLastEvaluatedDesc, prometheus.GaugeValue,
+			float64(policy.Status.LastEvaluated.Unix()), ns, name)
+	}
+}
+
+// collectAction sends the gauges of an action. Actions that were not
+// reconciled yet are reported as Pending.
+func collectAction(ch chan<- prometheus.Metric, action *v1alpha1.HealingAction) {
+	ns, name := action.Namespace, action.Name
+	target := action.Spec.TargetResource
+
+	ch <- prometheus.MustNewConstMetric(actionInfoDesc, prometheus.GaugeValue, 1,
+		ns, name, action.Spec.PolicyRef.Name, action.Spec.Action.Type,
+		target.Kind, target.Namespace, target.Name, strconv.FormatBool(action.Spec.DryRun))
+
+	phase := action.Status.Phase
+	if phase == "" {
+		phase = v1alpha1.HealingActionPhasePending
+	}
+	for _, p := range actionPhases {
+		value := 0.0
+		if p == phase {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(actionPhaseDesc, prometheus.GaugeValue, value, ns, name, p)
+	}
+
+	ch <- prometheus.MustNewConstMetric(actionCreatedDesc, prometheus.GaugeValue,
+		float64(action.CreationTimestamp.Unix()), ns, name)
+	ch <- prometheus.MustNewConstMetric(actionAttemptsDesc, prometheus.GaugeValue,
+		float64(action.Status.Attempts), ns, name)
+}
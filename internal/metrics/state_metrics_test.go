@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestStateCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	created := metav1.NewTime(time.Unix(1700000000, 0))
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec:       v1alpha1.HealingPolicySpec{Mode: "automatic", AIProfile: &v1alpha1.AIProfile{Mode: "lite"}},
+		Status: v1alpha1.HealingPolicyStatus{
+			ActionsTaken:   3,
+			ActiveTriggers: []string{"high-cpu"},
+			LastEvaluated:  metav1.NewTime(time.Unix(1700000600, 0)),
+		},
+	}
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-restart", Namespace: "shop", CreationTimestamp: created},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "web", Namespace: "shop"},
+			TargetResource: v1alpha1.TargetResource{Kind: "Deployment", Namespace: "shop", Name: "frontend"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+		},
+		Status: v1alpha1.HealingActionStatus{Phase: v1alpha1.HealingActionPhaseInProgress, Attempts: 2},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, action).Build()
+	collector := NewStateCollector(reader)
+
+	expected := `
+# HELP kubeskippy_healingaction_status_phase The HealingAction's current phase, 1 for the current phase and 0 for the others
+# TYPE kubeskippy_healingaction_status_phase gauge
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="Approved"} 0
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="Cancelled"} 0
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="Failed"} 0
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="InProgress"} 1
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="Pending"} 0
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="Succeeded"} 0
+kubeskippy_healingaction_status_phase{action="web-restart",namespace="shop",phase="Verifying"} 0
+# HELP kubeskippy_healingaction_info Information about a HealingAction
+# TYPE kubeskippy_healingaction_info gauge
+kubeskippy_healingaction_info{action="web-restart",action_type="restart",dry_run="false",namespace="shop",policy="web",target_kind="Deployment",target_name="frontend",target_namespace="shop"} 1
+# HELP kubeskippy_healingpolicy_info Information about a HealingPolicy
+# TYPE kubeskippy_healingpolicy_info gauge
+kubeskippy_healingpolicy_info{ai_profile="lite",mode="automatic",namespace="shop",paused="false",policy="web"} 1
+# HELP kubeskippy_healingpolicy_actions_taken Number of healing actions the policy has taken
+# TYPE kubeskippy_healingpolicy_actions_taken gauge
+kubeskippy_healingpolicy_actions_taken{namespace="shop",policy="web"} 3
+# HELP kubeskippy_healingpolicy_active_triggers Number of the policy's triggers that are firing
+# TYPE kubeskippy_healingpolicy_active_triggers gauge
+kubeskippy_healingpolicy_active_triggers{namespace="shop",policy="web"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"kubeskippy_healingaction_status_phase", "kubeskippy_healingaction_info",
+		"kubeskippy_healingpolicy_info", "kubeskippy_healingpolicy_actions_taken",
+		"kubeskippy_healingpolicy_active_triggers"))
+
+	// Four policy gauges, and the info, seven phases, creation time and attempts of the action
+	assert.Equal(t, 4+10, testutil.CollectAndCount(collector))
+}
@@ -0,0 +1,145 @@
+// Package migration moves the stored custom resources of the operator to
+// the storage version of their CRDs after an upgrade, so that older API
+// versions can be dropped from the CRDs.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// listPageSize bounds the objects listed per request
+const listPageSize = 100
+
+// Result of migrating a CRD
+type Result struct {
+	// CRD name
+	CRD string
+
+	// StorageVersion the objects are stored in after the migration
+	StorageVersion string
+
+	// Migrated is the number of objects rewritten
+	Migrated int
+}
+
+// StorageVersionMigrator rewrites every object of a CRD unchanged, which
+// makes the API server store it in the CRD's storage version, then drops the
+// other versions from the CRD's stored versions
+type StorageVersionMigrator struct {
+	client client.Client
+}
+
+// NewStorageVersionMigrator creates a migrator; c must know the
+// apiextensions.k8s.io/v1 types
+func NewStorageVersionMigrator(c client.Client) *StorageVersionMigrator {
+	return &StorageVersionMigrator{client: c}
+}
+
+// MigrateGroup migrates the CRDs of an API group that have objects stored in
+// versions other than their storage version
+func (m *StorageVersionMigrator) MigrateGroup(ctx context.Context, group string) ([]Result, error) {
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := m.client.List(ctx, crds); err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	var results []Result
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if crd.Spec.Group != group {
+			continue
+		}
+		storage := storageVersion(crd)
+		if slices.Equal(crd.Status.StoredVersions, []string{storage}) {
+			continue
+		}
+		result, err := m.Migrate(ctx, crd.Name)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Migrate migrates the objects of the named CRD to its storage version
+func (m *StorageVersionMigrator) Migrate(ctx context.Context, crdName string) (Result, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
+		return Result{}, fmt.Errorf("failed to get CRD %s: %w", crdName, err)
+	}
+	result := Result{CRD: crdName, StorageVersion: storageVersion(crd)}
+	if result.StorageVersion == "" {
+		return result, fmt.Errorf("CRD %s has no storage version", crdName)
+	}
+
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: result.StorageVersion, Kind: crd.Spec.Names.ListKind}
+	if gvk.Kind == "" {
+		gvk.Kind = crd.Spec.Names.Kind + "List"
+	}
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := m.client.List(ctx, list, client.Limit(listPageSize), client.Continue(continueToken)); err != nil {
+			return result, fmt.Errorf("failed to list %s: %w", crd.Spec.Names.Plural, err)
+		}
+		for i := range list.Items {
+			if err := m.rewrite(ctx, &list.Items[i]); err != nil {
+				return result, err
+			}
+			result.Migrated++
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	// Only the storage version is left in etcd
+	crd.Status.StoredVersions = []string{result.StorageVersion}
+	if err := m.client.Status().Update(ctx, crd); err != nil {
+		return result, fmt.Errorf("failed to update stored versions of CRD %s: %w", crdName, err)
+	}
+	log.FromContext(ctx).Info("Migrated CRD to its storage version", "crd", crdName,
+		"storageVersion", result.StorageVersion, "objects", result.Migrated)
+	return result, nil
+}
+
+// rewrite writes obj back unchanged, re-reading it on conflicts. Objects
+// deleted meanwhile are skipped.
+func (m *StorageVersionMigrator) rewrite(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := m.client.Update(ctx, obj)
+		if apierrors.IsConflict(err) {
+			if getErr := m.client.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to rewrite %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+// storageVersion returns the version a CRD stores its objects in
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
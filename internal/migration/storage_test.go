@@ -0,0 +1,97 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func healingPolicyCRD(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "healingpolicies.kubeskippy.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "kubeskippy.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: "healingpolicies", Kind: "HealingPolicy", ListKind: "HealingPolicyList",
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: true},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func TestStorageVersionMigrator_MigrateGroup(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	crd := healingPolicyCRD("v1beta1", "v1alpha1")
+	migrated := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "healingactions.kubeskippy.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "kubeskippy.io",
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Plural: "healingactions", Kind: "HealingAction"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true, Storage: true}},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1"}},
+	}
+	objs := []client.Object{crd, migrated}
+	for i := 0; i < 3; i++ {
+		objs = append(objs, &v1alpha1.HealingPolicy{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%d", i), Namespace: "default"}})
+	}
+
+	updates := map[string]int{}
+	conflicted := false
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(crd, migrated).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				// The first write of policy-1 loses against a concurrent update
+				if obj.GetName() == "policy-1" && !conflicted {
+					conflicted = true
+					return apierrors.NewConflict(schema.GroupResource{Group: "kubeskippy.io", Resource: "healingpolicies"}, obj.GetName(), nil)
+				}
+				updates[obj.GetName()]++
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+
+	results, err := NewStorageVersionMigrator(c).MigrateGroup(context.Background(), "kubeskippy.io")
+	require.NoError(t, err)
+	assert.Equal(t, []Result{{CRD: "healingpolicies.kubeskippy.io", StorageVersion: "v1alpha1", Migrated: 3}}, results)
+	assert.Equal(t, map[string]int{"policy-0": 1, "policy-1": 1, "policy-2": 1}, updates)
+
+	updated := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(crd), updated))
+	assert.Equal(t, []string{"v1alpha1"}, updated.Status.StoredVersions)
+
+	// Migrated CRDs are skipped
+	results, err = NewStorageVersionMigrator(c).MigrateGroup(context.Background(), "kubeskippy.io")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestStorageVersionMigrator_Migrate_NoStorageVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	crd := healingPolicyCRD("v1alpha1")
+	crd.Spec.Versions[0].Storage = false
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+
+	_, err := NewStorageVersionMigrator(c).Migrate(context.Background(), crd.Name)
+	assert.ErrorContains(t, err, "has no storage version")
+}
@@ -0,0 +1,377 @@
+// Package notify reports healing outcomes to external systems.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Issue tracker providers
+const (
+	ProviderWebhook = "webhook"
+	ProviderGitHub  = "github"
+	ProviderJira    = "jira"
+)
+
+const (
+	// defaultTimeout is used when no request timeout is configured
+	defaultTimeout = 10 * time.Second
+
+	// maxEvidenceBytes bounds each piece of evidence in a summary
+	maxEvidenceBytes = 2000
+)
+
+// githubIssuePattern matches "owner/repo#123" issue references
+var githubIssuePattern = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+
+// jiraIssuePattern matches "PROJ-123" issue keys
+var jiraIssuePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-\d+$`)
+
+// IncidentSummary describes a completed healing action
+type IncidentSummary struct {
+	Issue       string   `json:"issue,omitempty"`
+	Detected    string   `json:"detected"`
+	Evidence    []string `json:"evidence,omitempty"`
+	ActionTaken string   `json:"actionTaken"`
+	Outcome     string   `json:"outcome"`
+	Succeeded   bool     `json:"succeeded"`
+	DryRun      bool     `json:"dryRun,omitempty"`
+	AIReasoning string   `json:"aiReasoning,omitempty"`
+	Policy      string   `json:"policy"`
+	Action      string   `json:"action"`
+	ActionURL   string   `json:"actionURL,omitempty"`
+}
+
+// IssueTrackerNotifier posts incident summaries to an issue tracker
+type IssueTrackerNotifier struct {
+	config     config.IssueTrackerConfig
+	token      string
+	httpClient *http.Client
+}
+
+// NewIssueTrackerNotifier creates a new issue tracker notifier
+func NewIssueTrackerNotifier(cfg config.IssueTrackerConfig, token string) (*IssueTrackerNotifier, error) {
+	switch cfg.Provider {
+	case ProviderWebhook, ProviderGitHub, ProviderJira:
+	default:
+		return nil, fmt.Errorf("unsupported issue tracker provider: %q", cfg.Provider)
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("issue tracker URL is required")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &IssueTrackerNotifier{
+		config:     cfg,
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// LoadIssueTrackerNotifier creates a notifier using the token stored in the
+// configured Secret, if any
+func LoadIssueTrackerNotifier(ctx context.Context, reader client.Reader, cfg config.IssueTrackerConfig) (*IssueTrackerNotifier, error) {
+	var token string
+	if cfg.TokenSecretName != "" {
+		secret := &corev1.Secret{}
+		name := types.NamespacedName{Name: cfg.TokenSecretName, Namespace: cfg.TokenSecretNamespace}
+		if err := reader.Get(ctx, name, secret); err != nil {
+			return nil, fmt.Errorf("failed to get issue tracker token secret %s: %w", name, err)
+		}
+		data, ok := secret.Data[cfg.TokenSecretKey]
+		if !ok {
+			return nil, fmt.Errorf("issue tracker token secret %s has no key %q", name, cfg.TokenSecretKey)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	return NewIssueTrackerNotifier(cfg, token)
+}
+
+// NotifyCompletion posts a summary of a completed action. Outcomes that are
+// not configured to be reported, and actions without a related issue when
+// the provider comments on issues, are skipped.
+func (n *IssueTrackerNotifier) NotifyCompletion(ctx context.Context, action *v1alpha1.HealingAction) error {
+	succeeded := action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded
+	if (succeeded && !n.config.NotifyOnSuccess) || (!succeeded && !n.config.NotifyOnFailure) {
+		return nil
+	}
+
+	summary := BuildIncidentSummary(action, n.actionURL(action))
+
+	if n.config.Provider == ProviderWebhook {
+		return n.post(ctx, n.config.URL, summary)
+	}
+	if summary.Issue == "" {
+		return nil
+	}
+	endpoint, err := n.commentEndpoint(summary.Issue)
+	if err != nil {
+		return err
+	}
+	return n.post(ctx, endpoint, map[string]string{"body": RenderMarkdown(summary)})
+}
+
+// TriggerSummary describes a trigger state transition
+type TriggerSummary struct {
+	Issue      string `json:"issue,omitempty"`
+	Policy     string `json:"policy"`
+	Trigger    string `json:"trigger"`
+	Transition string `json:"transition"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// NotifyTrigger posts a trigger state transition, if trigger notifications
+// are enabled. Policies without a related issue are skipped when the
+// provider comments on issues.
+func (n *IssueTrackerNotifier) NotifyTrigger(ctx context.Context, policy *v1alpha1.HealingPolicy, trigger, transition, reason string) error {
+	if !n.config.NotifyOnTrigger {
+		return nil
+	}
+
+	summary := &TriggerSummary{
+		Issue:      policy.Annotations[kubetypes.AnnotationIssue],
+		Policy:     fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+		Trigger:    trigger,
+		Transition: transition,
+		Reason:     reason,
+	}
+	if n.config.Provider == ProviderWebhook {
+		return n.post(ctx, n.config.URL, summary)
+	}
+	if summary.Issue == "" {
+		return nil
+	}
+	endpoint, err := n.commentEndpoint(summary.Issue)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("KubeSkippy trigger `%s` of policy `%s`: **%s**\n\n%s\n", trigger, summary.Policy, transition, orNone(reason))
+	return n.post(ctx, endpoint, map[string]string{"body": body})
+}
+
+// EscalationSummary reports the targets of a policy healing is
+// ineffective for
+type EscalationSummary struct {
+	Issue   string                   `json:"issue,omitempty"`
+	Policy  string                   `json:"policy"`
+	Targets []v1alpha1.FailingTarget `json:"targets"`
+}
+
+// NotifyEscalation posts one report for the policy's escalated targets, if
+// escalation notifications are enabled. Policies without a related issue
+// are skipped when the provider comments on issues.
+func (n *IssueTrackerNotifier) NotifyEscalation(ctx context.Context, policy *v1alpha1.HealingPolicy, targets []v1alpha1.FailingTarget) error {
+	if !n.config.NotifyOnEscalation || len(targets) == 0 {
+		return nil
+	}
+
+	summary := &EscalationSummary{
+		Issue:   policy.Annotations[kubetypes.AnnotationIssue],
+		Policy:  fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+		Targets: targets,
+	}
+	if n.config.Provider == ProviderWebhook {
+		return n.post(ctx, n.config.URL, summary)
+	}
+	if summary.Issue == "" {
+		return nil
+	}
+	endpoint, err := n.commentEndpoint(summary.Issue)
+	if err != nil {
+		return err
+	}
+	return n.post(ctx, endpoint, map[string]string{"body": RenderEscalationMarkdown(summary)})
+}
+
+// RenderEscalationMarkdown renders an escalation report as a Markdown comment
+func RenderEscalationMarkdown(summary *EscalationSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### KubeSkippy healing ineffective: policy `%s`\n\n", summary.Policy)
+	b.WriteString("| Target | Consecutive failures | Circuit breaker | Failing since | Last failure |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, target := range summary.Targets {
+		since := "-"
+		if target.FirstFailure != nil {
+			since = target.FirstFailure.UTC().Format(time.RFC3339)
+		}
+		circuit := "closed"
+		if target.CircuitOpen {
+			circuit = "open"
+		}
+		fmt.Fprintf(&b, "| `%s` | %d | %s | %s | %s |\n", target.Target, target.ConsecutiveFailures,
+			circuit, since, truncate(strings.ReplaceAll(orNone(target.LastMessage), "|", "\\|"), 200))
+	}
+	return b.String()
+}
+
+// commentEndpoint returns the URL for commenting on an issue
+func (n *IssueTrackerNotifier) commentEndpoint(issue string) (string, error) {
+	switch n.config.Provider {
+	case ProviderGitHub:
+		m := githubIssuePattern.FindStringSubmatch(issue)
+		if m == nil {
+			return "", fmt.Errorf("invalid GitHub issue reference %q, expected owner/repo#number", issue)
+		}
+		return fmt.Sprintf("%s/repos/%s/%s/issues/%s/comments",
+			strings.TrimSuffix(n.config.URL, "/"), url.PathEscape(m[1]), url.PathEscape(m[2]), m[3]), nil
+
+	case ProviderJira:
+		if !jiraIssuePattern.MatchString(issue) {
+			return "", fmt.Errorf("invalid Jira issue key %q", issue)
+		}
+		return fmt.Sprintf("%s/rest/api/2/issue/%s/comment", strings.TrimSuffix(n.config.URL, "/"), issue), nil
+	}
+	return "", fmt.Errorf("unsupported issue tracker provider: %q", n.config.Provider)
+}
+
+// post sends a JSON request to the issue tracker
+func (n *IssueTrackerNotifier) post(ctx context.Context, endpoint string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident summary: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post incident summary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("issue tracker returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// actionURL links to the action, if a template is configured
+func (n *IssueTrackerNotifier) actionURL(action *v1alpha1.HealingAction) string {
+	if n.config.ActionURLTemplate == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{namespace}", url.PathEscape(action.Namespace),
+		"{name}", url.PathEscape(action.Name),
+	).Replace(n.config.ActionURLTemplate)
+}
+
+// BuildIncidentSummary summarizes a completed action
+func BuildIncidentSummary(action *v1alpha1.HealingAction, actionURL string) *IncidentSummary {
+	target := action.Spec.TargetResource
+	summary := &IncidentSummary{
+		Issue:       action.Annotations[kubetypes.AnnotationIssue],
+		Detected:    action.Annotations[kubetypes.AnnotationTriggerReason],
+		ActionTaken: fmt.Sprintf("%s (%s) on %s %s/%s", action.Spec.Action.Name, action.Spec.Action.Type, target.Kind, target.Namespace, target.Name),
+		Succeeded:   action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded,
+		DryRun:      action.Spec.DryRun,
+		AIReasoning: action.Annotations[kubetypes.AnnotationAIReasoning],
+		Policy:      fmt.Sprintf("%s/%s", action.Spec.PolicyRef.Namespace, action.Spec.PolicyRef.Name),
+		Action:      fmt.Sprintf("%s/%s", action.Namespace, action.Name),
+		ActionURL:   actionURL,
+	}
+	summary.Outcome = action.Status.Phase
+	if result := action.Status.Result; result != nil {
+		switch {
+		case result.Error != "":
+			summary.Outcome = fmt.Sprintf("%s: %s", action.Status.Phase, result.Error)
+		case result.Message != "":
+			summary.Outcome = fmt.Sprintf("%s: %s", action.Status.Phase, result.Message)
+		}
+		for _, change := range result.Changes {
+			summary.Evidence = append(summary.Evidence, fmt.Sprintf("change %s %s: %s -> %s",
+				change.ChangeType, change.Field, change.OldValue, change.NewValue))
+		}
+		for _, capture := range result.Diagnostics {
+			summary.Evidence = append(summary.Evidence, fmt.Sprintf("%s %s:\n%s",
+				capture.Type, capture.Source, truncate(capture.Output, maxEvidenceBytes)))
+		}
+	}
+	if radius := action.Status.BlastRadius; radius != nil {
+		summary.Evidence = append(summary.Evidence, fmt.Sprintf("blast radius: %d pods affected, %d services losing endpoints, %d ready replicas remaining",
+			len(radius.AffectedPods), len(radius.ServicesLosingEndpoints), radius.RemainingReplicas))
+	}
+	return summary
+}
+
+// RenderMarkdown renders a summary as an issue comment
+func RenderMarkdown(summary *IncidentSummary) string {
+	var b strings.Builder
+
+	status := "succeeded"
+	if !summary.Succeeded {
+		status = "failed"
+	}
+	if summary.DryRun {
+		status += " (dry run)"
+	}
+	fmt.Fprintf(&b, "### KubeSkippy healing action %s\n\n", status)
+
+	fmt.Fprintf(&b, "**Issue detected:** %s\n\n", orNone(summary.Detected))
+	fmt.Fprintf(&b, "**Action taken:** %s\n\n", summary.ActionTaken)
+	fmt.Fprintf(&b, "**Outcome:** %s\n\n", summary.Outcome)
+	if summary.AIReasoning != "" {
+		fmt.Fprintf(&b, "**AI reasoning:** %s\n\n", summary.AIReasoning)
+	}
+	if len(summary.Evidence) > 0 {
+		b.WriteString("**Evidence:**\n\n")
+		for _, evidence := range summary.Evidence {
+			if strings.Contains(evidence, "\n") {
+				fmt.Fprintf(&b, "```\n%s\n```\n", evidence)
+				continue
+			}
+			fmt.Fprintf(&b, "- %s\n", evidence)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Policy `%s`, HealingAction `%s`", summary.Policy, summary.Action)
+	if summary.ActionURL != "" {
+		fmt.Fprintf(&b, " ([view](%s))", summary.ActionURL)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "not recorded"
+	}
+	return s
+}
+
+// truncate bounds s to max bytes
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "\n[truncated]"
+}
@@ -0,0 +1,289 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func completedAction(phase, issue string) *v1alpha1.HealingAction {
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-restart-abc12",
+			Namespace: "apps",
+			Annotations: map[string]string{
+				kubetypes.AnnotationTriggerReason: "found 3 resources with condition CrashLoopBackOff",
+				kubetypes.AnnotationAIReasoning:   "memory leak suspected; confidence 85%",
+			},
+		},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "web-policy", Namespace: "apps"},
+			TargetResource: v1alpha1.TargetResource{Kind: "Deployment", Namespace: "apps", Name: "web"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart-web", Type: "restart"},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Phase: phase,
+			Result: &v1alpha1.ActionResult{
+				Success: phase == v1alpha1.HealingActionPhaseSucceeded,
+				Message: "Restarted deployment",
+				Diagnostics: []v1alpha1.DiagnosticCapture{
+					{Type: "logs", Source: "web-1/app", Output: "OOMKilled\nrestarting"},
+				},
+			},
+		},
+	}
+	if issue != "" {
+		action.Annotations[kubetypes.AnnotationIssue] = issue
+	}
+	return action
+}
+
+// trackerServer records the last request made to it
+func trackerServer(t *testing.T, status int) (*httptest.Server, *http.Request, *[]byte) {
+	var last http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = *r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &last, &body
+}
+
+func TestIssueTrackerNotifier_Providers(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		issue    string
+		wantPath string
+	}{
+		{name: "webhook", provider: ProviderWebhook, wantPath: "/hook"},
+		{name: "github", provider: ProviderGitHub, issue: "acme/shop#42", wantPath: "/repos/acme/shop/issues/42/comments"},
+		{name: "jira", provider: ProviderJira, issue: "OPS-7", wantPath: "/rest/api/2/issue/OPS-7/comment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, req, body := trackerServer(t, http.StatusCreated)
+			url := server.URL
+			if tt.provider == ProviderWebhook {
+				url += "/hook"
+			}
+
+			notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+				Provider:          tt.provider,
+				URL:               url,
+				ActionURLTemplate: "https://console.example.com/actions/{namespace}/{name}",
+				NotifyOnSuccess:   true,
+				NotifyOnFailure:   true,
+			}, "secret-token")
+			require.NoError(t, err)
+
+			require.NoError(t, notifier.NotifyCompletion(context.Background(),
+				completedAction(v1alpha1.HealingActionPhaseSucceeded, tt.issue)))
+			assert.Equal(t, tt.wantPath, req.URL.Path)
+			assert.Equal(t, "Bearer secret-token", req.Header.Get("Authorization"))
+
+			if tt.provider == ProviderWebhook {
+				var summary IncidentSummary
+				require.NoError(t, json.Unmarshal(*body, &summary))
+				assert.True(t, summary.Succeeded)
+				assert.Equal(t, "apps/web-restart-abc12", summary.Action)
+				assert.Equal(t, "https://console.example.com/actions/apps/web-restart-abc12", summary.ActionURL)
+				return
+			}
+
+			var comment map[string]string
+			require.NoError(t, json.Unmarshal(*body, &comment))
+			assert.Contains(t, comment["body"], "**Issue detected:** found 3 resources with condition CrashLoopBackOff")
+			assert.Contains(t, comment["body"], "**AI reasoning:** memory leak suspected")
+			assert.Contains(t, comment["body"], "OOMKilled")
+			assert.Contains(t, comment["body"], "HealingAction `apps/web-restart-abc12`")
+		})
+	}
+}
+
+func TestIssueTrackerNotifier_Skips(t *testing.T) {
+	server, req, _ := trackerServer(t, http.StatusOK)
+
+	notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+		Provider:        ProviderGitHub,
+		URL:             server.URL,
+		NotifyOnFailure: true,
+	}, "")
+	require.NoError(t, err)
+
+	// Successes are not reported
+	require.NoError(t, notifier.NotifyCompletion(context.Background(),
+		completedAction(v1alpha1.HealingActionPhaseSucceeded, "acme/shop#42")))
+	// Actions without a related issue have nowhere to comment
+	require.NoError(t, notifier.NotifyCompletion(context.Background(),
+		completedAction(v1alpha1.HealingActionPhaseFailed, "")))
+	assert.Nil(t, req.URL)
+
+	err = notifier.NotifyCompletion(context.Background(), completedAction(v1alpha1.HealingActionPhaseFailed, "not-an-issue"))
+	assert.ErrorContains(t, err, "invalid GitHub issue reference")
+}
+
+func TestIssueTrackerNotifier_ErrorStatus(t *testing.T) {
+	server, _, _ := trackerServer(t, http.StatusForbidden)
+
+	notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+		Provider:        ProviderWebhook,
+		URL:             server.URL,
+		NotifyOnFailure: true,
+	}, "")
+	require.NoError(t, err)
+
+	err = notifier.NotifyCompletion(context.Background(), completedAction(v1alpha1.HealingActionPhaseFailed, ""))
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestLoadIssueTrackerNotifier(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tracker", Namespace: "kubeskippy-system"},
+		Data:       map[string][]byte{"token": []byte("abc\n")},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	cfg := config.IssueTrackerConfig{
+		Provider:             ProviderJira,
+		URL:                  "https://jira.example.com",
+		TokenSecretName:      "tracker",
+		TokenSecretNamespace: "kubeskippy-system",
+		TokenSecretKey:       "token",
+	}
+	notifier, err := LoadIssueTrackerNotifier(context.Background(), reader, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", notifier.token)
+
+	cfg.TokenSecretKey = "missing"
+	_, err = LoadIssueTrackerNotifier(context.Background(), reader, cfg)
+	assert.ErrorContains(t, err, `has no key "missing"`)
+
+	_, err = NewIssueTrackerNotifier(config.IssueTrackerConfig{Provider: "email", URL: "x"}, "")
+	assert.ErrorContains(t, err, "unsupported issue tracker provider")
+}
+
+func TestIssueTrackerNotifier_NotifyTrigger(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-policy",
+			Namespace:   "apps",
+			Annotations: map[string]string{kubetypes.AnnotationIssue: "acme/shop#42"},
+		},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		server, req, _ := trackerServer(t, http.StatusOK)
+		notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{Provider: ProviderWebhook, URL: server.URL}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, notifier.NotifyTrigger(context.Background(), policy, "high-cpu", "Activated", "cpu above 80%"))
+		assert.Nil(t, req.URL)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		server, _, body := trackerServer(t, http.StatusOK)
+		notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+			Provider: ProviderWebhook, URL: server.URL, NotifyOnTrigger: true,
+		}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, notifier.NotifyTrigger(context.Background(), policy, "high-cpu", "Activated", "cpu above 80%"))
+		var summary TriggerSummary
+		require.NoError(t, json.Unmarshal(*body, &summary))
+		assert.Equal(t, TriggerSummary{
+			Issue: "acme/shop#42", Policy: "apps/web-policy", Trigger: "high-cpu", Transition: "Activated", Reason: "cpu above 80%",
+		}, summary)
+	})
+
+	t.Run("github", func(t *testing.T) {
+		server, req, body := trackerServer(t, http.StatusCreated)
+		notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+			Provider: ProviderGitHub, URL: server.URL, NotifyOnTrigger: true,
+		}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, notifier.NotifyTrigger(context.Background(), policy, "high-cpu", "Resolved", "last fired at 12:00"))
+		assert.Equal(t, "/repos/acme/shop/issues/42/comments", req.URL.Path)
+		var comment map[string]string
+		require.NoError(t, json.Unmarshal(*body, &comment))
+		assert.Contains(t, comment["body"], "**Resolved**")
+
+		// Policies without a related issue have nowhere to comment
+		*req = http.Request{}
+		unlinked := policy.DeepCopy()
+		unlinked.Annotations = nil
+		require.NoError(t, notifier.NotifyTrigger(context.Background(), unlinked, "high-cpu", "Activated", ""))
+		assert.Nil(t, req.URL)
+	})
+}
+
+func TestIssueTrackerNotifier_NotifyEscalation(t *testing.T) {
+	policy := &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-policy",
+			Namespace:   "apps",
+			Annotations: map[string]string{kubetypes.AnnotationIssue: "acme/shop#42"},
+		},
+	}
+	targets := []v1alpha1.FailingTarget{{
+		Target: "Deployment/apps/web", ConsecutiveFailures: 3, CircuitOpen: true, LastMessage: "rollout | timed out",
+	}}
+
+	t.Run("disabled", func(t *testing.T) {
+		server, req, _ := trackerServer(t, http.StatusOK)
+		notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{Provider: ProviderWebhook, URL: server.URL}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, notifier.NotifyEscalation(context.Background(), policy, targets))
+		assert.Nil(t, req.URL)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		server, _, body := trackerServer(t, http.StatusOK)
+		notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+			Provider: ProviderWebhook, URL: server.URL, NotifyOnEscalation: true,
+		}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, notifier.NotifyEscalation(context.Background(), policy, targets))
+		var summary EscalationSummary
+		require.NoError(t, json.Unmarshal(*body, &summary))
+		assert.Equal(t, "apps/web-policy", summary.Policy)
+		assert.Equal(t, targets, summary.Targets)
+	})
+
+	t.Run("github", func(t *testing.T) {
+		server, req, body := trackerServer(t, http.StatusCreated)
+		notifier, err := NewIssueTrackerNotifier(config.IssueTrackerConfig{
+			Provider: ProviderGitHub, URL: server.URL, NotifyOnEscalation: true,
+		}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, notifier.NotifyEscalation(context.Background(), policy, targets))
+		assert.Equal(t, "/repos/acme/shop/issues/42/comments", req.URL.Path)
+		var comment map[string]string
+		require.NoError(t, json.Unmarshal(*body, &comment))
+		assert.Contains(t, comment["body"], "| `Deployment/apps/web` | 3 | open | - | rollout \\| timed out |")
+	})
+}
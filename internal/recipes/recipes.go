@@ -0,0 +1,240 @@
+// Package recipes provides built-in healing policies for common pod
+// pathologies. Recipe policies are created in dryrun mode with conservative
+// safety rules; once their planned actions look right they can be promoted
+// by editing the policy, which the installer never overwrites.
+package recipes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+const (
+	// LabelRecipe marks policies generated from a recipe
+	LabelRecipe = "kubeskippy.io/recipe"
+
+	// policyNamePrefix prefixes the names of recipe policies
+	policyNamePrefix = "recipe-"
+)
+
+// Recipe names
+const (
+	ImagePullBackOff = "image-pull-backoff"
+	StuckTerminating = "stuck-terminating"
+	EvictedCleanup   = "evicted-cleanup"
+	CompletedPodGC   = "completed-pod-gc"
+	InitFailure      = "init-failure"
+)
+
+// Recipe is a built-in healing policy
+type Recipe struct {
+	// Name of the recipe
+	Name string
+
+	// Description of what the recipe heals
+	Description string
+
+	// trigger and action of the recipe policy
+	trigger v1alpha1.HealingTrigger
+	action  v1alpha1.HealingActionTemplate
+
+	// maxActionsPerHour bounds the recipe's actions per namespace
+	maxActionsPerHour int32
+}
+
+// podStateTrigger fires for pods that stayed in a state for the duration
+func podStateTrigger(name, state string, duration, cooldown time.Duration) v1alpha1.HealingTrigger {
+	return v1alpha1.HealingTrigger{
+		Name: name,
+		Type: "condition",
+		ConditionTrigger: &v1alpha1.ConditionTrigger{
+			Type:     state,
+			Status:   "True",
+			Duration: metav1.Duration{Duration: duration},
+		},
+		CooldownPeriod: metav1.Duration{Duration: cooldown},
+	}
+}
+
+var recipes = []Recipe{
+	{
+		Name: ImagePullBackOff,
+		Description: "Recreates pods stuck in ImagePullBackOff to retry the pull, " +
+			"backing off for the trigger cooldown between retries",
+		trigger: podStateTrigger("image-pull-backoff", metrics.PodStateImagePullBackOff, 5*time.Minute, 15*time.Minute),
+		action: v1alpha1.HealingActionTemplate{
+			Name:          "retry-image-pull",
+			Type:          "restart",
+			Description:   "Recreate the pod to retry pulling its image",
+			RestartAction: &v1alpha1.RestartAction{Strategy: "recreate", MaxConcurrent: 1},
+		},
+		maxActionsPerHour: 4,
+	},
+	{
+		Name:        StuckTerminating,
+		Description: "Force deletes pods still terminating 5 minutes after their grace period",
+		trigger:     podStateTrigger("stuck-terminating", metrics.PodStateTerminating, 5*time.Minute, 5*time.Minute),
+		action: v1alpha1.HealingActionTemplate{
+			Name:         "force-delete",
+			Type:         "delete",
+			Description:  "Remove finalizers and delete the pod without a grace period",
+			DeleteAction: &v1alpha1.DeleteAction{Force: true},
+		},
+		maxActionsPerHour: 5,
+	},
+	{
+		Name:        EvictedCleanup,
+		Description: "Deletes pods that were evicted more than an hour ago",
+		trigger:     podStateTrigger("evicted", metrics.PodStateEvicted, time.Hour, 10*time.Minute),
+		action: v1alpha1.HealingActionTemplate{
+			Name:         "delete-evicted",
+			Type:         "delete",
+			Description:  "Delete the evicted pod",
+			DeleteAction: &v1alpha1.DeleteAction{},
+		},
+		maxActionsPerHour: 20,
+	},
+	{
+		Name:        CompletedPodGC,
+		Description: "Deletes pods that completed more than a day ago",
+		trigger:     podStateTrigger("completed", metrics.PodStateCompleted, 24*time.Hour, 30*time.Minute),
+		action: v1alpha1.HealingActionTemplate{
+			Name:         "delete-completed",
+			Type:         "delete",
+			Description:  "Delete the completed pod",
+			DeleteAction: &v1alpha1.DeleteAction{},
+		},
+		maxActionsPerHour: 20,
+	},
+	{
+		Name: InitFailure,
+		Description: "Recreates pods whose init containers kept failing for 10 minutes, " +
+			"retrying init containers that wait on dependencies that were unavailable",
+		trigger: podStateTrigger("init-failure", metrics.PodStateInitFailure, 10*time.Minute, 30*time.Minute),
+		action: v1alpha1.HealingActionTemplate{
+			Name:          "retry-init",
+			Type:          "restart",
+			Description:   "Recreate the pod to rerun its init containers",
+			RestartAction: &v1alpha1.RestartAction{Strategy: "recreate", MaxConcurrent: 1},
+		},
+		maxActionsPerHour: 4,
+	},
+}
+
+// Names returns the names of the built-in recipes
+func Names() []string {
+	names := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		names[i] = recipe.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the recipe with the given name
+func Get(name string) (*Recipe, bool) {
+	for i := range recipes {
+		if recipes[i].Name == name {
+			return &recipes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Policy returns the recipe's policy for a namespace. Recipe policies start
+// in dryrun mode and only target pods in their own namespace.
+func (r *Recipe) Policy(namespace string) *v1alpha1.HealingPolicy {
+	return &v1alpha1.HealingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyNamePrefix + r.Name,
+			Namespace: namespace,
+			Labels:    map[string]string{LabelRecipe: r.Name},
+			Annotations: map[string]string{
+				"kubeskippy.io/description": r.Description,
+			},
+		},
+		Spec: v1alpha1.HealingPolicySpec{
+			Selector: v1alpha1.ResourceSelector{
+				Namespaces: []string{namespace},
+				Resources:  []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+			},
+			Triggers: []v1alpha1.HealingTrigger{r.trigger},
+			Actions:  []v1alpha1.HealingActionTemplate{r.action},
+			Mode:     "dryrun",
+			SafetyRules: v1alpha1.SafetyRules{
+				MaxActionsPerHour: r.maxActionsPerHour,
+			},
+		},
+	}
+}
+
+// Installer creates the configured recipe policies
+type Installer struct {
+	client client.Client
+	config config.RecipesConfig
+}
+
+// NewInstaller creates a new recipe installer
+func NewInstaller(c client.Client, cfg config.RecipesConfig) (*Installer, error) {
+	for _, name := range cfg.Names {
+		if _, ok := Get(name); !ok {
+			return nil, fmt.Errorf("unknown recipe %q, available recipes: %v", name, Names())
+		}
+	}
+	return &Installer{client: c, config: cfg}, nil
+}
+
+// NeedLeaderElection is true so only the leader creates policies
+func (i *Installer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start installs the recipe policies once
+func (i *Installer) Start(ctx context.Context) error {
+	if err := i.Install(ctx); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to install recipe policies")
+	}
+	return nil
+}
+
+// Install creates the recipe policies that do not exist yet. Existing
+// policies are left alone so promoted or tuned recipes keep their changes.
+func (i *Installer) Install(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("recipes")
+
+	names := i.config.Names
+	if len(names) == 0 {
+		names = Names()
+	}
+	if len(i.config.Namespaces) == 0 {
+		log.Info("No namespaces configured for recipes, skipping installation")
+		return nil
+	}
+
+	for _, namespace := range i.config.Namespaces {
+		for _, name := range names {
+			recipe, _ := Get(name)
+			policy := recipe.Policy(namespace)
+			if err := i.client.Create(ctx, policy); err != nil {
+				if errors.IsAlreadyExists(err) {
+					log.V(1).Info("Recipe policy already exists", "policy", policy.Name, "namespace", namespace)
+					continue
+				}
+				return fmt.Errorf("failed to create recipe policy %s/%s: %w", namespace, policy.Name, err)
+			}
+			log.Info("Installed recipe policy", "policy", policy.Name, "namespace", namespace, "mode", policy.Spec.Mode)
+		}
+	}
+	return nil
+}
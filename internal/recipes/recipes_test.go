@@ -0,0 +1,67 @@
+package recipes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestRecipePolicies(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			recipe, ok := Get(name)
+			require.True(t, ok)
+
+			policy := recipe.Policy("apps")
+			assert.Equal(t, "recipe-"+name, policy.Name)
+			assert.Equal(t, name, policy.Labels[LabelRecipe])
+			assert.Equal(t, "dryrun", policy.Spec.Mode)
+			assert.Equal(t, []string{"apps"}, policy.Spec.Selector.Namespaces)
+			assert.Positive(t, policy.Spec.SafetyRules.MaxActionsPerHour)
+			assert.Empty(t, policy.Validate(), "recipe policies must pass admission validation")
+		})
+	}
+}
+
+func TestNewInstaller_UnknownRecipe(t *testing.T) {
+	_, err := NewInstaller(nil, config.RecipesConfig{Names: []string{"does-not-exist"}})
+	assert.ErrorContains(t, err, "unknown recipe")
+}
+
+func TestInstaller_Install(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	// A promoted recipe policy must not be reset to dryrun
+	promoted, _ := Get(EvictedCleanup)
+	existing := promoted.Policy("apps")
+	existing.Spec.Mode = "automatic"
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	installer, err := NewInstaller(c, config.RecipesConfig{
+		Enabled:    true,
+		Names:      []string{EvictedCleanup, StuckTerminating},
+		Namespaces: []string{"apps", "jobs"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, installer.Install(context.Background()))
+
+	policies := &v1alpha1.HealingPolicyList{}
+	require.NoError(t, c.List(context.Background(), policies))
+	assert.Len(t, policies.Items, 4)
+
+	policy := &v1alpha1.HealingPolicy{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "apps", Name: "recipe-" + EvictedCleanup}, policy))
+	assert.Equal(t, "automatic", policy.Spec.Mode)
+
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "jobs", Name: "recipe-" + StuckTerminating}, policy))
+	assert.Equal(t, "dryrun", policy.Spec.Mode)
+}
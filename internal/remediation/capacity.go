@@ -0,0 +1,296 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CapacityBlockedError reports a scale-up whose new replicas cannot be
+// scheduled on the cluster's nodes
+type CapacityBlockedError struct {
+	// Requested is the number of replicas the scale-up adds
+	Requested int32
+
+	// Schedulable is the number of them that fit on the nodes
+	Schedulable int32
+
+	// Recommendation for scaling a node pool, if enabled
+	Recommendation string
+}
+
+func (e *CapacityBlockedError) Error() string {
+	msg := fmt.Sprintf("capacity blocked: only %d of %d new replicas fit on the cluster's nodes", e.Schedulable, e.Requested)
+	if e.Recommendation != "" {
+		msg += "; " + e.Recommendation
+	}
+	return msg
+}
+
+// metrics returns the result metrics of the blocked scale-up
+func (e *CapacityBlockedError) metrics() map[string]string {
+	metrics := map[string]string{
+		"capacity_blocked":     "true",
+		"requested_replicas":   strconv.Itoa(int(e.Requested)),
+		"schedulable_replicas": strconv.Itoa(int(e.Schedulable)),
+	}
+	if e.Recommendation != "" {
+		metrics["node_pool_recommendation"] = e.Recommendation
+	}
+	return metrics
+}
+
+// CapacityChecker checks the allocatable headroom of the nodes before a
+// scale-up so actions do not create replicas that stay Pending. When the
+// cluster autoscaler reports itself healthy, unschedulable replicas are
+// left to it to add nodes for.
+type CapacityChecker struct {
+	client client.Client
+
+	// autoscalerStatus is the cluster autoscaler's status ConfigMap, empty
+	// to ignore the autoscaler
+	autoscalerStatus types.NamespacedName
+
+	// recommend adds a node pool scaling recommendation to blocked results
+	recommend bool
+}
+
+// NewCapacityChecker creates a new capacity checker. autoscalerStatus is
+// the "namespace/name" of the cluster autoscaler's status ConfigMap, empty
+// to ignore the autoscaler.
+func NewCapacityChecker(client client.Client, autoscalerStatus string, recommend bool) (*CapacityChecker, error) {
+	checker := &CapacityChecker{client: client, recommend: recommend}
+	if autoscalerStatus != "" {
+		namespace, name, ok := strings.Cut(autoscalerStatus, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("cluster autoscaler status must be namespace/name, got %q", autoscalerStatus)
+		}
+		checker.autoscalerStatus = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return checker, nil
+}
+
+// SetCapacityChecker makes scale-ups check node headroom first
+func (s *ScaleExecutor) SetCapacityChecker(checker *CapacityChecker) {
+	s.capacity = checker
+}
+
+// podRequests are the scheduling requests of one replica
+type podRequests struct {
+	milliCPU int64
+	memory   int64
+}
+
+// Check returns a CapacityBlockedError if fewer than additional replicas of
+// the target fit on the nodes. Targets without a pod template, and checks
+// failing to read the cluster, are not blocked.
+func (c *CapacityChecker) Check(ctx context.Context, target client.Object, additional int32) error {
+	log := log.FromContext(ctx)
+
+	spec, ok, err := podTemplateSpec(target)
+	if err != nil || !ok {
+		log.V(1).Info("Skipping capacity check, target has no pod template", "kind", resourceKind(target), "error", err)
+		return nil
+	}
+	requests := replicaRequests(spec)
+
+	fit, err := c.schedulableReplicas(ctx, spec, requests)
+	if err != nil {
+		log.Error(err, "Failed to check node capacity, scaling anyway")
+		return nil
+	}
+	if fit >= int64(additional) {
+		return nil
+	}
+
+	if c.autoscalerHealthy(ctx) {
+		log.Info("Nodes lack headroom, leaving new replicas to the cluster autoscaler",
+			"requested", additional, "schedulable", fit)
+		return nil
+	}
+
+	blocked := &CapacityBlockedError{Requested: additional, Schedulable: int32(fit)}
+	if c.recommend {
+		blocked.Recommendation = nodePoolRecommendation(int64(additional)-fit, requests)
+	}
+	return blocked
+}
+
+// schedulableReplicas sums how many replicas fit on each node the pod can
+// be scheduled to
+func (c *CapacityChecker) schedulableReplicas(ctx context.Context, spec *corev1.PodSpec, requests podRequests) (int64, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.client.List(ctx, nodes); err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods := &corev1.PodList{}
+	if err := c.client.List(ctx, pods); err != nil {
+		return 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	used := make(map[string]podRequests, len(nodes.Items))
+	podCount := make(map[string]int64, len(nodes.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		r := replicaRequests(&pod.Spec)
+		u := used[pod.Spec.NodeName]
+		u.milliCPU += r.milliCPU
+		u.memory += r.memory
+		used[pod.Spec.NodeName] = u
+		podCount[pod.Spec.NodeName]++
+	}
+
+	var total int64
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !schedulableOn(node, spec) {
+			continue
+		}
+		allocatable := node.Status.Allocatable
+		fit := allocatable.Pods().Value() - podCount[node.Name]
+		if requests.milliCPU > 0 {
+			fit = min(fit, (allocatable.Cpu().MilliValue()-used[node.Name].milliCPU)/requests.milliCPU)
+		}
+		if requests.memory > 0 {
+			fit = min(fit, (allocatable.Memory().Value()-used[node.Name].memory)/requests.memory)
+		}
+		if fit > 0 {
+			total += fit
+		}
+	}
+	return total, nil
+}
+
+// autoscalerHealthy reports whether the cluster autoscaler's status
+// ConfigMap reports it cluster-wide healthy
+func (c *CapacityChecker) autoscalerHealthy(ctx context.Context) bool {
+	if c.autoscalerStatus.Name == "" {
+		return false
+	}
+	status := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.autoscalerStatus, status); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to read cluster autoscaler status")
+		}
+		return false
+	}
+	return autoscalerStatusHealthy(status.Data["status"])
+}
+
+// autoscalerStatusHealthy parses the cluster-wide health from the status
+// text, either the legacy "Health: Healthy (...)" format or the YAML format
+// whose first status field is the cluster-wide health
+func autoscalerStatusHealthy(status string) bool {
+	for _, line := range strings.Split(status, "\n") {
+		line = strings.TrimSpace(line)
+		for _, field := range []string{"Health:", "status:"} {
+			if value, ok := strings.CutPrefix(line, field); ok {
+				return strings.HasPrefix(strings.TrimSpace(value), "Healthy")
+			}
+		}
+	}
+	return false
+}
+
+// nodePoolRecommendation describes the capacity to add for the replicas
+// that do not fit
+func nodePoolRecommendation(missing int64, requests podRequests) string {
+	cpu := resource.NewMilliQuantity(missing*requests.milliCPU, resource.DecimalSI)
+	memory := resource.NewQuantity(missing*requests.memory, resource.BinarySI)
+	return fmt.Sprintf("scale up a node pool by at least %s CPU and %s memory of allocatable capacity for %d replicas",
+		cpu.String(), memory.String(), missing)
+}
+
+// schedulableOn reports whether new pods with the spec can be scheduled to
+// the node, considering readiness, cordons, node selectors and taints
+func schedulableOn(node *corev1.Node, spec *corev1.PodSpec) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	if !ready {
+		return false
+	}
+	for key, value := range spec.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range spec.Tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// replicaRequests returns the effective requests of a pod: its containers'
+// requests or its largest init container's, whichever is higher, plus the
+// pod overhead
+func replicaRequests(spec *corev1.PodSpec) podRequests {
+	var r podRequests
+	for _, container := range spec.Containers {
+		r.milliCPU += container.Resources.Requests.Cpu().MilliValue()
+		r.memory += container.Resources.Requests.Memory().Value()
+	}
+	for _, container := range spec.InitContainers {
+		r.milliCPU = max(r.milliCPU, container.Resources.Requests.Cpu().MilliValue())
+		r.memory = max(r.memory, container.Resources.Requests.Memory().Value())
+	}
+	r.milliCPU += spec.Overhead.Cpu().MilliValue()
+	r.memory += spec.Overhead.Memory().Value()
+	return r
+}
+
+// podTemplateSpec returns the pod spec of the target's pod template
+func podTemplateSpec(target client.Object) (*corev1.PodSpec, bool, error) {
+	var obj map[string]interface{}
+	if u, ok := target.(*unstructured.Unstructured); ok {
+		obj = u.Object
+	} else {
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(target)
+		if err != nil {
+			return nil, false, err
+		}
+		obj = converted
+	}
+
+	template, found, err := unstructured.NestedMap(obj, "spec", "template", "spec")
+	if err != nil || !found {
+		return nil, false, err
+	}
+	spec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, spec); err != nil {
+		return nil, false, fmt.Errorf("failed to decode pod template: %w", err)
+	}
+	return spec, true, nil
+}
@@ -0,0 +1,207 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func capacityNode(name, cpu, memory string, mutate ...func(*corev1.Node)) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	for _, m := range mutate {
+		m(node)
+	}
+	return node
+}
+
+func capacityPod(name, node, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func capacityDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(2),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "web",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("256Mi"),
+				}},
+			}}}},
+		},
+	}
+}
+
+func TestScaleExecutor_CapacityCheck(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	// Two replicas fit on node-a next to the running pod; the other nodes
+	// cannot take new pods
+	cluster := []client.Object{
+		capacityNode("node-a", "2", "4Gi"),
+		capacityNode("cordoned", "8", "16Gi", func(n *corev1.Node) { n.Spec.Unschedulable = true }),
+		capacityNode("tainted", "8", "16Gi", func(n *corev1.Node) {
+			n.Spec.Taints = []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		capacityNode("not-ready", "8", "16Gi", func(n *corev1.Node) {
+			n.Status.Conditions[0].Status = corev1.ConditionFalse
+		}),
+		capacityPod("db", "node-a", "1", "1Gi"),
+		capacityPod("done", "node-a", "1", "1Gi"),
+	}
+	cluster[5].(*corev1.Pod).Status.Phase = corev1.PodSucceeded
+
+	scaleUp := func(replicas int32) *v1alpha1.HealingActionTemplate {
+		return &v1alpha1.HealingActionTemplate{
+			Type:        "scale",
+			ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: replicas},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		objects          []client.Object
+		autoscaler       string
+		replicas         int32
+		expectBlocked    bool
+		expectedReplicas int32
+	}{
+		{name: "fits", replicas: 2, expectedReplicas: 4},
+		{name: "blocked", replicas: 3, expectBlocked: true, expectedReplicas: 2},
+		{
+			name: "left to a healthy autoscaler",
+			objects: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-autoscaler-status", Namespace: "kube-system"},
+				Data:       map[string]string{"status": "Cluster-wide:\n  Health:      Healthy (ready=1 unready=0)\n"},
+			}},
+			autoscaler:       "kube-system/cluster-autoscaler-status",
+			replicas:         3,
+			expectedReplicas: 5,
+		},
+		{
+			name:             "autoscaler status missing",
+			autoscaler:       "kube-system/cluster-autoscaler-status",
+			replicas:         3,
+			expectBlocked:    true,
+			expectedReplicas: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := capacityDeployment()
+			objects := append([]client.Object{deployment}, tt.objects...)
+			for _, obj := range cluster {
+				objects = append(objects, obj.DeepCopyObject().(client.Object))
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			executor := NewScaleExecutor(c)
+			checker, err := NewCapacityChecker(c, tt.autoscaler, true)
+			require.NoError(t, err)
+			executor.SetCapacityChecker(checker)
+
+			dryRun, err := executor.DryRun(context.Background(), deployment, scaleUp(tt.replicas))
+			require.NoError(t, err)
+			assert.True(t, dryRun.Success)
+
+			result, err := executor.Execute(context.Background(), deployment, scaleUp(tt.replicas))
+			if tt.expectBlocked {
+				var blocked *CapacityBlockedError
+				require.ErrorAs(t, err, &blocked)
+				assert.Equal(t, int32(3), blocked.Requested)
+				assert.Equal(t, int32(2), blocked.Schedulable)
+				assert.Equal(t, "scale up a node pool by at least 500m CPU and 256Mi memory of allocatable capacity for 1 replicas", blocked.Recommendation)
+				assert.False(t, result.Success)
+				assert.Equal(t, "true", result.Metrics["capacity_blocked"])
+				assert.Equal(t, "true", dryRun.Metrics["capacity_blocked"])
+				assert.Contains(t, dryRun.Message, "capacity blocked")
+			} else {
+				require.NoError(t, err)
+				assert.True(t, result.Success)
+				assert.Empty(t, dryRun.Metrics["capacity_blocked"])
+			}
+
+			var updated appsv1.Deployment
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(deployment), &updated))
+			assert.Equal(t, tt.expectedReplicas, *updated.Spec.Replicas)
+		})
+	}
+}
+
+func TestCapacityChecker_TolerationsAndSelectors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	gpu := capacityNode("gpu", "4", "8Gi", func(n *corev1.Node) {
+		n.Labels = map[string]string{"pool": "gpu"}
+		n.Spec.Taints = []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gpu).Build()
+	checker, err := NewCapacityChecker(c, "", false)
+	require.NoError(t, err)
+
+	deployment := capacityDeployment()
+	var blocked *CapacityBlockedError
+	require.ErrorAs(t, checker.Check(context.Background(), deployment, 1), &blocked)
+	assert.Empty(t, blocked.Recommendation)
+
+	deployment.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}
+	assert.NoError(t, checker.Check(context.Background(), deployment, 8))
+
+	deployment.Spec.Template.Spec.NodeSelector = map[string]string{"pool": "cpu"}
+	assert.Error(t, checker.Check(context.Background(), deployment, 1))
+}
+
+func TestAutoscalerStatusHealthy(t *testing.T) {
+	legacy := "Cluster-autoscaler status at 2024-05-15 10:00:00 +0000 UTC:\nCluster-wide:\n  Health:      Unhealthy (ready=1 unready=2)\n"
+	yamlStatus := "time: 2024-05-15 10:00:00 +0000 UTC\nautoscalerStatus: Running\nclusterWide:\n  health:\n    status: Healthy\n"
+
+	assert.False(t, autoscalerStatusHealthy(legacy))
+	assert.True(t, autoscalerStatusHealthy(yamlStatus))
+	assert.False(t, autoscalerStatusHealthy(""))
+}
+
+func TestNewCapacityChecker_InvalidAutoscalerStatus(t *testing.T) {
+	_, err := NewCapacityChecker(nil, "cluster-autoscaler-status", true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,345 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// BlastRadiusBlockedError reports an action whose simulated impact exceeds
+// the policy limits. Retrying does not help until the cluster changes.
+type BlastRadiusBlockedError struct {
+	Reason string
+}
+
+func (e *BlastRadiusBlockedError) Error() string {
+	return "blast radius exceeds policy limits: " + e.Reason
+}
+
+// CascadeSimulator estimates the downstream effects of removing a resource
+type CascadeSimulator struct {
+	client client.Client
+}
+
+// NewCascadeSimulator creates a new cascade simulator
+func NewCascadeSimulator(client client.Client) *CascadeSimulator {
+	return &CascadeSimulator{
+		client: client,
+	}
+}
+
+// Simulate builds a blast-radius report for deleting or draining the
+// target. Pods are evaluated directly, nodes are expanded into the pods
+// scheduled on them and workloads with a label selector into the pods they
+// own.
+func (c *CascadeSimulator) Simulate(ctx context.Context, target client.Object) (*v1alpha1.BlastRadiusReport, error) {
+	report := &v1alpha1.BlastRadiusReport{
+		SimulatedAt: &metav1.Time{Time: time.Now()},
+	}
+
+	kind := target.GetObjectKind().GroupVersionKind().Kind
+	if kind == "Node" {
+		return c.simulateDrain(ctx, target.GetName(), report)
+	}
+
+	namespace := target.GetNamespace()
+	if namespace == "" {
+		return report, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.client.List(ctx, podList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	affected, err := targetPods(target, podList.Items)
+	if err != nil {
+		return nil, err
+	}
+	if len(affected) == 0 {
+		return report, nil
+	}
+	if err := c.assess(ctx, namespace, affected, podList.Items, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// simulateDrain evaluates evicting every pod scheduled on the node, one
+// namespace at a time. Remaining replicas is the lowest of any namespace.
+func (c *CascadeSimulator) simulateDrain(ctx context.Context, node string, report *v1alpha1.BlastRadiusReport) (*v1alpha1.BlastRadiusReport, error) {
+	podList := &corev1.PodList{}
+	if err := c.client.List(ctx, podList); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	byNamespace := make(map[string][]corev1.Pod)
+	onNode := make(map[string][]*corev1.Pod)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], *pod)
+	}
+	for namespace, pods := range byNamespace {
+		for i := range pods {
+			// Mirror pods and DaemonSet pods are not evicted by a drain
+			if pods[i].Spec.NodeName == node && !isDaemonSetPod(&pods[i]) && pods[i].Annotations[corev1.MirrorPodAnnotationKey] == "" {
+				onNode[namespace] = append(onNode[namespace], &pods[i])
+			}
+		}
+	}
+	if len(onNode) == 0 {
+		return report, nil
+	}
+
+	namespaces := make([]string, 0, len(onNode))
+	for namespace := range onNode {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	remaining := int32(math.MaxInt32)
+	for _, namespace := range namespaces {
+		partial := &v1alpha1.BlastRadiusReport{}
+		if err := c.assess(ctx, namespace, onNode[namespace], byNamespace[namespace], partial); err != nil {
+			return nil, err
+		}
+		for _, name := range partial.AffectedPods {
+			report.AffectedPods = append(report.AffectedPods, namespace+"/"+name)
+		}
+		for _, name := range partial.ServicesLosingEndpoints {
+			report.ServicesLosingEndpoints = append(report.ServicesLosingEndpoints, namespace+"/"+name)
+		}
+		for _, name := range partial.ViolatedPDBs {
+			report.ViolatedPDBs = append(report.ViolatedPDBs, namespace+"/"+name)
+		}
+		remaining = min(remaining, partial.RemainingReplicas)
+	}
+	report.RemainingReplicas = remaining
+	return report, nil
+}
+
+// assess fills the report with the effects of removing the affected pods of
+// a namespace, given all pods of the namespace
+func (c *CascadeSimulator) assess(ctx context.Context, namespace string, affected []*corev1.Pod, pods []corev1.Pod, report *v1alpha1.BlastRadiusReport) error {
+	podList := &corev1.PodList{Items: pods}
+
+	affectedNames := make(map[string]bool, len(affected))
+	for _, pod := range affected {
+		affectedNames[pod.Name] = true
+		report.AffectedPods = append(report.AffectedPods, pod.Name)
+	}
+	sort.Strings(report.AffectedPods)
+
+	// Services that would be left without a single ready endpoint
+	svcList := &corev1.ServiceList{}
+	if err := c.client.List(ctx, svcList, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	for _, svc := range svcList.Items {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		servesAffected := false
+		remaining := 0
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if affectedNames[pod.Name] {
+				servesAffected = true
+				continue
+			}
+			if isPodReady(pod) {
+				remaining++
+			}
+		}
+		if servesAffected && remaining == 0 {
+			report.ServicesLosingEndpoints = append(report.ServicesLosingEndpoints, svc.Name)
+		}
+	}
+
+	// PodDisruptionBudgets that cannot absorb the disruption
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	if err := c.client.List(ctx, pdbList, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+	for _, pdb := range pdbList.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		disrupted := int32(0)
+		for _, pod := range affected {
+			if selector.Matches(labels.Set(pod.Labels)) && isPodReady(pod) {
+				disrupted++
+			}
+		}
+		if disrupted > pdb.Status.DisruptionsAllowed {
+			report.ViolatedPDBs = append(report.ViolatedPDBs, pdb.Name)
+		}
+	}
+
+	report.RemainingReplicas = remainingSiblingReplicas(affected, affectedNames, podList.Items)
+	return nil
+}
+
+// Evaluate marks the report as blocked when it exceeds the given limits
+func (c *CascadeSimulator) Evaluate(report *v1alpha1.BlastRadiusReport, limits *v1alpha1.BlastRadiusLimits) bool {
+	if report == nil || limits == nil {
+		return false
+	}
+
+	var reasons []string
+	if int32(len(report.ServicesLosingEndpoints)) > limits.MaxServicesWithoutEndpoints {
+		reasons = append(reasons, fmt.Sprintf("%d services would lose all endpoints (limit %d): %s",
+			len(report.ServicesLosingEndpoints), limits.MaxServicesWithoutEndpoints,
+			strings.Join(report.ServicesLosingEndpoints, ", ")))
+	}
+	if len(report.ViolatedPDBs) > 0 && !limits.AllowPDBViolation {
+		reasons = append(reasons, fmt.Sprintf("would violate PodDisruptionBudgets: %s",
+			strings.Join(report.ViolatedPDBs, ", ")))
+	}
+	if len(report.AffectedPods) > 0 && report.RemainingReplicas < limits.MinRemainingReplicas {
+		reasons = append(reasons, fmt.Sprintf("only %d ready replicas would remain (minimum %d)",
+			report.RemainingReplicas, limits.MinRemainingReplicas))
+	}
+
+	if len(reasons) == 0 {
+		return false
+	}
+
+	report.Blocked = true
+	report.Reason = strings.Join(reasons, "; ")
+	return true
+}
+
+// targetPods resolves the pods that belong to the target: the pod itself,
+// or the pods matched by a workload's label selector
+func targetPods(target client.Object, pods []corev1.Pod) ([]*corev1.Pod, error) {
+	obj, err := toUnstructuredMap(target)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := target.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind, _, _ = unstructured.NestedString(obj, "kind")
+	}
+
+	var affected []*corev1.Pod
+	if kind == "Pod" {
+		for i := range pods {
+			if pods[i].Name == target.GetName() {
+				affected = append(affected, &pods[i])
+			}
+		}
+		return affected, nil
+	}
+
+	rawSelector, found, err := unstructured.NestedMap(obj, "spec", "selector")
+	if err != nil || !found {
+		return nil, nil
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSelector, labelSelector); err != nil {
+		return nil, fmt.Errorf("failed to parse selector: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	for i := range pods {
+		if selector.Matches(labels.Set(pods[i].Labels)) {
+			affected = append(affected, &pods[i])
+		}
+	}
+	return affected, nil
+}
+
+// remainingSiblingReplicas returns the lowest count of ready pods left for
+// any controller that owns an affected pod
+func remainingSiblingReplicas(affected []*corev1.Pod, affectedNames map[string]bool, pods []corev1.Pod) int32 {
+	owners := make(map[string]bool)
+	for _, pod := range affected {
+		if ref := metav1.GetControllerOf(pod); ref != nil {
+			owners[string(ref.UID)] = true
+		}
+	}
+	if len(owners) == 0 {
+		// Unowned pods are not replaced, nothing remains
+		return 0
+	}
+
+	remaining := make(map[string]int32, len(owners))
+	for uid := range owners {
+		remaining[uid] = 0
+	}
+	for i := range pods {
+		pod := &pods[i]
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil || !owners[string(ref.UID)] || affectedNames[pod.Name] {
+			continue
+		}
+		if isPodReady(pod) {
+			remaining[string(ref.UID)]++
+		}
+	}
+
+	lowest := int32(math.MaxInt32)
+	for _, count := range remaining {
+		if count < lowest {
+			lowest = count
+		}
+	}
+	return lowest
+}
+
+// toUnstructuredMap returns the object content regardless of its concrete type
+func toUnstructuredMap(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+	return content, nil
+}
+
+// isDaemonSetPod reports whether the pod is owned by a DaemonSet
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	ref := metav1.GetControllerOf(pod)
+	return ref != nil && ref.Kind == "DaemonSet"
+}
+
+// isPodReady reports whether the pod has the Ready condition set
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
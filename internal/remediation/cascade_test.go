@@ -0,0 +1,252 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func readyPod(name, owner string, ready bool) *corev1.Pod {
+	isController := true
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "apps",
+			Labels:    map[string]string{"app": "web"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: owner, UID: types.UID(owner), Controller: &isController},
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestCascadeSimulator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+
+	minAvailable := intstr.FromInt32(1)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pdb", Namespace: "apps"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+	}
+
+	tests := []struct {
+		name              string
+		objects           []client.Object
+		target            string
+		limits            *v1alpha1.BlastRadiusLimits
+		expectedServices  []string
+		expectedPDBs      []string
+		expectedRemaining int32
+		expectedBlocked   bool
+	}{
+		{
+			name:              "last ready replica behind service",
+			objects:           []client.Object{readyPod("web-1", "rs-1", true), readyPod("web-2", "rs-1", false), service},
+			target:            "web-1",
+			limits:            &v1alpha1.BlastRadiusLimits{},
+			expectedServices:  []string{"web"},
+			expectedRemaining: 0,
+			expectedBlocked:   true,
+		},
+		{
+			name:              "other ready replicas keep service alive",
+			objects:           []client.Object{readyPod("web-1", "rs-1", true), readyPod("web-2", "rs-1", true), service},
+			target:            "web-1",
+			limits:            &v1alpha1.BlastRadiusLimits{MinRemainingReplicas: 1},
+			expectedRemaining: 1,
+			expectedBlocked:   false,
+		},
+		{
+			name:              "pdb violation blocked",
+			objects:           []client.Object{readyPod("web-1", "rs-1", true), readyPod("web-2", "rs-1", true), pdb},
+			target:            "web-1",
+			limits:            &v1alpha1.BlastRadiusLimits{},
+			expectedPDBs:      []string{"web-pdb"},
+			expectedRemaining: 1,
+			expectedBlocked:   true,
+		},
+		{
+			name:              "pdb violation allowed",
+			objects:           []client.Object{readyPod("web-1", "rs-1", true), readyPod("web-2", "rs-1", true), pdb},
+			target:            "web-1",
+			limits:            &v1alpha1.BlastRadiusLimits{AllowPDBViolation: true},
+			expectedPDBs:      []string{"web-pdb"},
+			expectedRemaining: 1,
+			expectedBlocked:   false,
+		},
+		{
+			name:              "no limits never blocks",
+			objects:           []client.Object{readyPod("web-1", "rs-1", true), service, pdb},
+			target:            "web-1",
+			expectedServices:  []string{"web"},
+			expectedPDBs:      []string{"web-pdb"},
+			expectedRemaining: 0,
+			expectedBlocked:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.objects...).
+				Build()
+
+			simulator := NewCascadeSimulator(fakeClient)
+			target := &corev1.Pod{}
+			require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: tt.target}, target))
+			target.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+
+			report, err := simulator.Simulate(context.Background(), target)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{tt.target}, report.AffectedPods)
+			assert.Equal(t, tt.expectedServices, report.ServicesLosingEndpoints)
+			assert.Equal(t, tt.expectedPDBs, report.ViolatedPDBs)
+			assert.Equal(t, tt.expectedRemaining, report.RemainingReplicas)
+
+			blocked := simulator.Evaluate(report, tt.limits)
+			assert.Equal(t, tt.expectedBlocked, blocked)
+			assert.Equal(t, tt.expectedBlocked, report.Blocked)
+			if tt.expectedBlocked {
+				assert.NotEmpty(t, report.Reason)
+			}
+		})
+	}
+}
+
+func TestCascadeSimulator_Workload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(readyPod("web-1", "rs-1", true), readyPod("web-2", "rs-1", true)).
+		Build()
+
+	deployment := createUnstructuredDeployment("web", "apps")
+	deployment.Object["spec"].(map[string]interface{})["selector"] = map[string]interface{}{
+		"matchLabels": map[string]interface{}{"app": "web"},
+	}
+
+	report, err := NewCascadeSimulator(fakeClient).Simulate(context.Background(), deployment)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web-1", "web-2"}, report.AffectedPods)
+	assert.Equal(t, int32(0), report.RemainingReplicas)
+}
+
+func TestCascadeSimulator_NodeDrain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+
+	onNode := func(pod *corev1.Pod, node string) *corev1.Pod {
+		pod.Spec.NodeName = node
+		return pod
+	}
+	daemon := onNode(readyPod("agent-1", "agent", true), "node-a")
+	daemon.OwnerReferences[0].Kind = "DaemonSet"
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			onNode(readyPod("web-1", "rs-1", true), "node-a"),
+			onNode(readyPod("web-2", "rs-1", true), "node-a"),
+			onNode(readyPod("web-3", "rs-1", true), "node-b"),
+			daemon,
+			service,
+		).
+		Build()
+
+	node := &corev1.Node{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+	}
+	report, err := NewCascadeSimulator(fakeClient).Simulate(context.Background(), node)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apps/web-1", "apps/web-2"}, report.AffectedPods, "DaemonSet pods are not drained")
+	assert.Empty(t, report.ServicesLosingEndpoints, "web-3 keeps serving")
+	assert.Equal(t, int32(1), report.RemainingReplicas)
+
+	assert.True(t, NewCascadeSimulator(fakeClient).Evaluate(report, &v1alpha1.BlastRadiusLimits{MinRemainingReplicas: 2}))
+}
+
+// An action with limits is blocked when its impact cannot be simulated
+func TestEngine_SimulateBlastRadiusFailure(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return errors.New("forbidden")
+			},
+		}).
+		Build()
+	engine := NewEngine(fakeClient, nil)
+	deployment := createUnstructuredDeployment("web", "apps")
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "delete-web", Namespace: "apps"},
+		Spec: v1alpha1.HealingActionSpec{
+			Action:            v1alpha1.HealingActionTemplate{Name: "delete", Type: "delete"},
+			BlastRadiusLimits: &v1alpha1.BlastRadiusLimits{MinRemainingReplicas: 1},
+		},
+	}
+	report := engine.simulateBlastRadius(context.Background(), action, deployment)
+	require.NotNil(t, report)
+	assert.True(t, report.Blocked)
+	assert.Contains(t, report.Reason, "could not be simulated")
+
+	action.Spec.BlastRadiusLimits = nil
+	assert.Nil(t, engine.simulateBlastRadius(context.Background(), action, deployment), "without limits the action proceeds")
+}
+
+func TestIsDestructive(t *testing.T) {
+	pod := &corev1.Pod{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}}
+	deployment := createUnstructuredDeployment("web", "apps")
+
+	assert.True(t, isDestructive(&v1alpha1.HealingActionTemplate{Type: "delete"}, deployment))
+	assert.True(t, isDestructive(&v1alpha1.HealingActionTemplate{Type: "restart"}, pod), "pod restarts delete the pod")
+	assert.False(t, isDestructive(&v1alpha1.HealingActionTemplate{Type: "restart"}, deployment), "workloads roll")
+	assert.False(t, isDestructive(&v1alpha1.HealingActionTemplate{Type: "restart",
+		RestartAction: &v1alpha1.RestartAction{Containers: []string{"app"}}}, pod), "containers restart in place")
+	assert.False(t, isDestructive(&v1alpha1.HealingActionTemplate{Type: "scale"}, deployment))
+}
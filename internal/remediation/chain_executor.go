@@ -0,0 +1,573 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+const (
+	// defaultVerifyStepTimeout is used when a verify step does not set
+	// Timeout
+	defaultVerifyStepTimeout = 2 * time.Minute
+
+	// verifyStepInterval is how often verify steps check the target
+	verifyStepInterval = 2 * time.Second
+
+	// maxStepOutputLength bounds each output recorded in the step's
+	// status; the following steps get the whole output
+	maxStepOutputLength = 1024
+)
+
+// reversibleStepTypes are the step types whose changes are undone by
+// setting the fields they changed back
+var reversibleStepTypes = map[string]bool{
+	"patch":  true,
+	"scale":  true,
+	"resize": true,
+}
+
+// ChainExecutor handles chain actions, which run their steps in order on
+// the target and pass the outputs of each step to the steps after it
+type ChainExecutor struct {
+	engine *Engine
+}
+
+// NewChainExecutor creates a new chain executor running the steps with the
+// executors registered with engine
+func NewChainExecutor(engine *Engine) *ChainExecutor {
+	return &ChainExecutor{
+		engine: engine,
+	}
+}
+
+// stepData is what the parameters of a step are rendered with
+type stepData struct {
+	Target stepTarget
+	Steps  map[string]map[string]string
+}
+
+// stepTarget identifies the target of the chain to step templates
+type stepTarget struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// completedStep is a step that ran, with the target as it found it and,
+// if it could be read, as it left it
+type completedStep struct {
+	index  int
+	step   *v1alpha1.ActionStep
+	before client.Object
+	after  client.Object
+}
+
+// Execute runs the steps in order. When a step fails, the following steps
+// are skipped and the completed ones rolled back in reverse order.
+func (c *ChainExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	log := log.FromContext(ctx)
+	startTime := time.Now()
+
+	result := &kubetypes.ActionResult{
+		Success:   true,
+		StartTime: startTime,
+		Metrics:   map[string]string{"steps": fmt.Sprintf("%d", len(action.Steps))},
+	}
+	data := newStepData(target)
+	var completed []completedStep
+
+	for i := range action.Steps {
+		step, err := renderStep(&action.Steps[i], data)
+		if err == nil {
+			err = ctx.Err()
+		}
+
+		stepStart := metav1.Now()
+		var out *kubetypes.ActionResult
+		before := target.DeepCopyObject().(client.Object)
+		if err == nil {
+			out, err = c.runStep(ctx, target, action, step)
+		}
+		stepEnd := metav1.Now()
+		stepResult := v1alpha1.StepResult{
+			Name:      action.Steps[i].Name,
+			Type:      action.Steps[i].Type,
+			StartTime: &stepStart,
+			EndTime:   &stepEnd,
+		}
+		if out != nil {
+			stepResult.Message = out.Message
+			stepResult.Outputs = statusOutputs(stepOutputs(out))
+			stepResult.Changes = out.Changes
+			result.Changes = append(result.Changes, out.Changes...)
+			result.Diagnostics = append(result.Diagnostics, out.Diagnostics...)
+		}
+
+		if err != nil {
+			log.Info("Chain step failed", "step", stepResult.Name, "error", err.Error())
+			stepResult.Phase = v1alpha1.StepPhaseFailed
+			stepResult.Message = err.Error()
+			result.Steps = append(result.Steps, stepResult)
+			for _, skipped := range action.Steps[i+1:] {
+				result.Steps = append(result.Steps, v1alpha1.StepResult{
+					Name:    skipped.Name,
+					Type:    skipped.Type,
+					Phase:   v1alpha1.StepPhaseSkipped,
+					Message: fmt.Sprintf("Skipped after step %s failed", stepResult.Name),
+				})
+			}
+
+			// Undo the completed steps even if the attempt was cancelled
+			cleanupCtx, cancel := cleanupContext(ctx)
+			rolledBack := c.rollback(cleanupCtx, result, completed)
+			cancel()
+
+			result.Success = false
+			result.Error = err
+			result.Message = fmt.Sprintf("Step %s failed: %v; rolled back %d of %d completed steps",
+				stepResult.Name, err, rolledBack, len(completed))
+			result.EndTime = time.Now()
+			return result, fmt.Errorf("step %s failed: %w", stepResult.Name, err)
+		}
+
+		stepResult.Phase = v1alpha1.StepPhaseSucceeded
+		result.Steps = append(result.Steps, stepResult)
+		data.Steps[step.Name] = stepOutputs(out)
+		done := completedStep{index: len(result.Steps) - 1, step: step, before: before}
+
+		// The next step works on the target as this step left it
+		if changesTarget(step.Type) {
+			if current, err := c.refreshTarget(ctx, target); err == nil {
+				target, done.after = current, current
+			}
+		}
+		completed = append(completed, done)
+	}
+
+	result.Message = fmt.Sprintf("Completed %d steps", len(action.Steps))
+	result.EndTime = time.Now()
+	return result, nil
+}
+
+// runStep runs one rendered step on the target
+func (c *ChainExecutor) runStep(ctx context.Context, target client.Object, chain *v1alpha1.HealingActionTemplate, step *v1alpha1.ActionStep) (*kubetypes.ActionResult, error) {
+	switch step.Type {
+	case "diagnostics":
+		c.engine.mu.RLock()
+		hooks := c.engine.hooks
+		c.engine.mu.RUnlock()
+		if hooks == nil {
+			return nil, fmt.Errorf("diagnostics are not enabled")
+		}
+		captures := hooks.Run(ctx, target, step.Hooks, chain.Execution)
+		return &kubetypes.ActionResult{
+			Success:     true,
+			Message:     fmt.Sprintf("Captured %d diagnostics", len(captures)),
+			Diagnostics: captures,
+		}, nil
+
+	case "verify":
+		return c.verify(ctx, target, step)
+	}
+
+	executor, err := c.engine.GetActionExecutor(step.Type)
+	if err != nil {
+		return nil, err
+	}
+	stepAction := step.ActionTemplate(chain)
+	if err := executor.Validate(ctx, target, stepAction); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	out, err := executor.Execute(ctx, target, stepAction)
+	if err == nil && out != nil && !out.Success {
+		err = errors.New(out.Message)
+	}
+	return out, err
+}
+
+// verify waits for the target to report the step's condition
+func (c *ChainExecutor) verify(ctx context.Context, target client.Object, step *v1alpha1.ActionStep) (*kubetypes.ActionResult, error) {
+	timeout := step.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultVerifyStepTimeout
+	}
+	want := step.Condition.Status
+	if want == "" {
+		want = "True"
+	}
+
+	key := client.ObjectKeyFromObject(target)
+	gvk := target.GetObjectKind().GroupVersionKind()
+	observed := ""
+	err := wait.PollUntilContextTimeout(ctx, verifyStepInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(gvk)
+		if err := c.engine.client.Get(ctx, key, current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		observed = conditionStatus(current, step.Condition.Type)
+		return observed == want, nil
+	})
+	if err != nil {
+		if observed == "" {
+			observed = "not reported"
+		}
+		return nil, fmt.Errorf("condition %s is %s, not %s: %w", step.Condition.Type, observed, want, err)
+	}
+
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: fmt.Sprintf("Condition %s is %s", step.Condition.Type, observed),
+		Metrics: map[string]string{
+			"condition": step.Condition.Type,
+			"status":    observed,
+		},
+	}, nil
+}
+
+// rollback undoes the completed steps in reverse order and returns how
+// many were rolled back. Only the fields a step changed are set back, and
+// a step is not rolled back once someone else changed those fields or
+// deleted the target. Steps whose changes cannot be undone, such as
+// restarts, keep their phase.
+func (c *ChainExecutor) rollback(ctx context.Context, result *kubetypes.ActionResult, completed []completedStep) int {
+	log := log.FromContext(ctx)
+	rolledBack := 0
+	for i := len(completed) - 1; i >= 0; i-- {
+		done := completed[i]
+		stepResult := &result.Steps[done.index]
+		if !reversibleStep(done.step, done.before) {
+			if len(stepResult.Changes) > 0 {
+				stepResult.Message = fmt.Sprintf("%s; %s steps cannot be rolled back", stepResult.Message, done.step.Type)
+			}
+			continue
+		}
+
+		if done.after == nil {
+			stepResult.Phase = v1alpha1.StepPhaseRollbackSkipped
+			stepResult.Message = "Rollback skipped: the target could not be read after the step"
+			continue
+		}
+		if err := revertChanges(ctx, c.engine.client, done.before, done.after); err != nil {
+			if errors.Is(err, errTargetDiverged) {
+				log.Info("Skipped rolling back chain step", "step", done.step.Name, "reason", err.Error())
+				stepResult.Phase = v1alpha1.StepPhaseRollbackSkipped
+				stepResult.Message = fmt.Sprintf("Rollback skipped: %v", err)
+				continue
+			}
+			log.Error(err, "Failed to roll back chain step", "step", done.step.Name)
+			stepResult.Phase = v1alpha1.StepPhaseRollbackFailed
+			stepResult.Message = fmt.Sprintf("Rollback failed: %v", err)
+			continue
+		}
+		stepResult.Phase = v1alpha1.StepPhaseRolledBack
+		stepResult.Message = "Rolled back"
+		rolledBack++
+	}
+	return rolledBack
+}
+
+// reversibleStep reports whether the step's changes are undone by setting
+// the fields it changed back. Pod resources are resized in place and
+// cannot be restored by a patch.
+func reversibleStep(step *v1alpha1.ActionStep, before client.Object) bool {
+	if !reversibleStepTypes[step.Type] {
+		return false
+	}
+	return step.Type != "resize" || before.GetObjectKind().GroupVersionKind().Kind != "Pod"
+}
+
+// changesTarget reports whether steps of the type may change the target
+func changesTarget(stepType string) bool {
+	return stepType != "diagnostics" && stepType != "verify"
+}
+
+// refreshTarget reads the target again. The caller keeps the previous
+// object if it cannot be read, e.g. after it was deleted.
+func (c *ChainExecutor) refreshTarget(ctx context.Context, target client.Object) (client.Object, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(target.GetObjectKind().GroupVersionKind())
+	if err := c.engine.client.Get(ctx, client.ObjectKeyFromObject(target), current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// Validate validates the steps. Steps with parameters using the outputs of
+// earlier steps are validated once rendered, when they run.
+func (c *ChainExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	if len(action.Steps) == 0 {
+		return fmt.Errorf("chain action requires steps")
+	}
+
+	names := make(map[string]bool)
+	for i := range action.Steps {
+		step := &action.Steps[i]
+		if step.Name == "" {
+			return fmt.Errorf("step %d has no name", i)
+		}
+		if names[step.Name] {
+			return fmt.Errorf("duplicate step name %s", step.Name)
+		}
+		names[step.Name] = true
+
+		switch step.Type {
+		case "diagnostics":
+			if len(step.Hooks) == 0 {
+				return fmt.Errorf("diagnostics step %s requires hooks", step.Name)
+			}
+		case "verify":
+			if step.Condition == nil || step.Condition.Type == "" {
+				return fmt.Errorf("verify step %s requires a condition", step.Name)
+			}
+		default:
+			executor, err := c.engine.GetActionExecutor(step.Type)
+			if err != nil || step.Type == "chain" {
+				return fmt.Errorf("step %s has unsupported type %s", step.Name, step.Type)
+			}
+			if usesTemplates(step) {
+				continue
+			}
+			if err := executor.Validate(ctx, target, step.ActionTemplate(action)); err != nil {
+				return fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DryRun simulates the steps in order, passing the outputs of each
+// simulated step to the following ones
+func (c *ChainExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	startTime := time.Now()
+	result := &kubetypes.ActionResult{
+		Success:   true,
+		StartTime: startTime,
+		Metrics:   map[string]string{"steps": fmt.Sprintf("%d", len(action.Steps))},
+	}
+	data := newStepData(target)
+	names := make([]string, 0, len(action.Steps))
+
+	for i := range action.Steps {
+		// Outputs only known when the steps run are left unrendered
+		step, err := renderStep(&action.Steps[i], data)
+		if err != nil {
+			step = action.Steps[i].DeepCopy()
+		}
+
+		var out *kubetypes.ActionResult
+		switch step.Type {
+		case "diagnostics":
+			out = &kubetypes.ActionResult{Message: fmt.Sprintf("Would capture %d hooks", len(step.Hooks))}
+		case "verify":
+			timeout := step.Timeout.Duration
+			if timeout <= 0 {
+				timeout = defaultVerifyStepTimeout
+			}
+			condition := ""
+			if step.Condition != nil {
+				condition = step.Condition.Type
+			}
+			out = &kubetypes.ActionResult{Message: fmt.Sprintf("Would wait up to %v for condition %s", timeout, condition)}
+		default:
+			executor, err := c.engine.GetActionExecutor(step.Type)
+			if err != nil {
+				return nil, err
+			}
+			out, err = executor.DryRun(ctx, target, step.ActionTemplate(action))
+			if err != nil {
+				return out, fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+
+		result.Changes = append(result.Changes, out.Changes...)
+		result.Steps = append(result.Steps, v1alpha1.StepResult{
+			Name:    step.Name,
+			Type:    step.Type,
+			Phase:   v1alpha1.StepPhaseSucceeded,
+			Message: out.Message,
+			Outputs: statusOutputs(out.Metrics),
+			Changes: out.Changes,
+		})
+		data.Steps[step.Name] = out.Metrics
+		names = append(names, step.Name)
+	}
+
+	result.Message = fmt.Sprintf("Dry-run: Would run %d steps: %s", len(names), strings.Join(names, ", "))
+	result.EndTime = time.Now()
+	return result, nil
+}
+
+// newStepData returns the data of the chain's first step
+func newStepData(target client.Object) stepData {
+	return stepData{
+		Target: stepTarget{
+			Kind:      target.GetObjectKind().GroupVersionKind().Kind,
+			Name:      target.GetName(),
+			Namespace: target.GetNamespace(),
+		},
+		Steps: make(map[string]map[string]string),
+	}
+}
+
+// stepOutputs returns the outputs of a step: the metrics of its result,
+// plus the output and source of its first successful capture and the
+// number of captures
+func stepOutputs(out *kubetypes.ActionResult) map[string]string {
+	outputs := make(map[string]string, len(out.Metrics)+3)
+	for k, v := range out.Metrics {
+		outputs[k] = v
+	}
+	if len(out.Diagnostics) > 0 {
+		outputs["captures"] = fmt.Sprintf("%d", len(out.Diagnostics))
+		for _, capture := range out.Diagnostics {
+			if capture.Error == "" {
+				outputs["output"] = capture.Output
+				outputs["source"] = capture.Source
+				break
+			}
+		}
+	}
+	return outputs
+}
+
+// statusOutputs truncates outputs to maxStepOutputLength for the status
+func statusOutputs(outputs map[string]string) map[string]string {
+	if len(outputs) == 0 {
+		return nil
+	}
+	truncated := make(map[string]string, len(outputs))
+	for k, v := range outputs {
+		if len(v) > maxStepOutputLength {
+			v = v[:maxStepOutputLength]
+		}
+		truncated[k] = v
+	}
+	return truncated
+}
+
+// usesTemplates reports whether the step's parameters reference the
+// target or the outputs of other steps
+func usesTemplates(step *v1alpha1.ActionStep) bool {
+	raw, err := json.Marshal(step)
+	return err == nil && bytes.Contains(raw, []byte("{{"))
+}
+
+// renderStep returns a copy of the step with the templates in its string
+// parameters rendered with data
+func renderStep(step *v1alpha1.ActionStep, data stepData) (*v1alpha1.ActionStep, error) {
+	if !usesTemplates(step) {
+		return stepReplicas(step.DeepCopy())
+	}
+
+	raw, err := json.Marshal(step)
+	if err != nil {
+		return nil, err
+	}
+	var fields interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	rendered, err := renderValue(step.Name, fields, data)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err = json.Marshal(rendered); err != nil {
+		return nil, err
+	}
+
+	out := &v1alpha1.ActionStep{}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return nil, fmt.Errorf("rendered step %s is invalid: %w", step.Name, err)
+	}
+	return stepReplicas(out)
+}
+
+// stepReplicas sets the replicas of a scale step from its rendered
+// replicas template
+func stepReplicas(step *v1alpha1.ActionStep) (*v1alpha1.ActionStep, error) {
+	scale := step.ScaleAction
+	if scale == nil || scale.ReplicasTemplate == "" {
+		return step, nil
+	}
+	replicas, err := strconv.ParseInt(strings.TrimSpace(scale.ReplicasTemplate), 10, 32)
+	if err != nil || replicas < 0 {
+		return nil, fmt.Errorf("%s.scaleAction.replicasTemplate rendered %q, expected a replica count", step.Name, scale.ReplicasTemplate)
+	}
+	if replicas == 0 && !scale.AllowScaleToZero {
+		return nil, fmt.Errorf("%s.scaleAction.replicasTemplate rendered 0 replicas, set allowScaleToZero to scale to zero", step.Name)
+	}
+	scale.Replicas = int32(replicas)
+	return step, nil
+}
+
+// renderValue renders the templates in the strings of a decoded JSON value
+func renderValue(path string, value interface{}, data stepData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		return buf.String(), nil
+	case map[string]interface{}:
+		for k, item := range v {
+			rendered, err := renderValue(path+"."+k, item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = rendered
+		}
+	case []interface{}:
+		for i, item := range v {
+			rendered, err := renderValue(fmt.Sprintf("%s[%d]", path, i), item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+	}
+	return value, nil
+}
+
+// conditionStatus returns the status of the object's condition of the
+// given type, or "" if it does not report one
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		return status
+	}
+	return ""
+}
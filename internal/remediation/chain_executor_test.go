@@ -0,0 +1,270 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+func TestChainExecutor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	engine := NewEngine(c, nil)
+	engine.SetPodExecutor(&mockPodExecutor{output: "/tmp/heap-1.hprof"})
+	executor, err := engine.GetActionExecutor("chain")
+	require.NoError(t, err)
+
+	// Capture a heap dump, record where it is, and check the pod is ready
+	action := &v1alpha1.HealingActionTemplate{
+		Name: "dump-and-verify",
+		Type: "chain",
+		Steps: []v1alpha1.ActionStep{
+			{Name: "dump", Type: "exec", ExecAction: &v1alpha1.ExecAction{Command: []string{"jcmd", "1", "GC.heap_dump"}}},
+			{Name: "record", Type: "patch", PatchAction: &v1alpha1.PatchAction{Type: "merge", Patches: []v1alpha1.PatchOperation{
+				{Path: []string{"metadata", "annotations", "heap-dump"}, Value: `"{{.Steps.dump.output}} from {{.Target.Name}}"`},
+			}}},
+			{Name: "ready", Type: "verify", Condition: &v1alpha1.TargetCondition{Type: "Ready"}, Timeout: metav1.Duration{Duration: time.Second}},
+		},
+	}
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pod), target))
+	require.NoError(t, executor.Validate(context.Background(), target, action))
+
+	result, err := executor.Execute(context.Background(), target, action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.Len(t, result.Steps, 3)
+	for _, step := range result.Steps {
+		assert.Equal(t, v1alpha1.StepPhaseSucceeded, step.Phase, step.Name)
+	}
+	assert.Equal(t, "/tmp/heap-1.hprof", result.Steps[0].Outputs["output"])
+	assert.Equal(t, "True", result.Steps[2].Outputs["status"])
+	assert.Len(t, result.Diagnostics, 1)
+	assert.NotEmpty(t, result.Changes)
+
+	updated := &corev1.Pod{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pod), updated))
+	assert.Equal(t, "/tmp/heap-1.hprof from web-1", updated.Annotations["heap-dump"])
+
+	// Dry runs simulate every step
+	dryRun, err := executor.DryRun(context.Background(), target, action)
+	require.NoError(t, err)
+	assert.Len(t, dryRun.Steps, 3)
+	assert.Contains(t, dryRun.Message, "dump, record, ready")
+}
+
+func TestChainExecutor_RollsBackFailedChain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "apps"},
+		Data:       map[string]string{"mode": "normal"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+	executor := NewChainExecutor(NewEngine(c, nil))
+
+	setMode := func(name, mode string) v1alpha1.ActionStep {
+		return v1alpha1.ActionStep{Name: name, Type: "patch", PatchAction: &v1alpha1.PatchAction{Type: "merge", Patches: []v1alpha1.PatchOperation{
+			{Path: []string{"data", "mode"}, Value: `"` + mode + `"`},
+		}}}
+	}
+	action := &v1alpha1.HealingActionTemplate{
+		Name: "degrade",
+		Type: "chain",
+		Steps: []v1alpha1.ActionStep{
+			setMode("degrade", "degraded"),
+			// ConfigMaps report no conditions
+			{Name: "healthy", Type: "verify", Condition: &v1alpha1.TargetCondition{Type: "Healthy"}, Timeout: metav1.Duration{Duration: 50 * time.Millisecond}},
+			setMode("restore", "normal"),
+		},
+	}
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(configMap), target))
+
+	result, err := executor.Execute(context.Background(), target, action)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "step healthy failed")
+	assert.False(t, result.Success)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, v1alpha1.StepPhaseRolledBack, result.Steps[0].Phase)
+	assert.Equal(t, v1alpha1.StepPhaseFailed, result.Steps[1].Phase)
+	assert.Contains(t, result.Steps[1].Message, "condition Healthy is not reported")
+	assert.Equal(t, v1alpha1.StepPhaseSkipped, result.Steps[2].Phase)
+	assert.Contains(t, result.Message, "rolled back 1 of 1 completed steps")
+
+	updated := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(configMap), updated))
+	assert.Equal(t, "normal", updated.Data["mode"])
+}
+
+func TestChainExecutor_RollbackKeepsOthersChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		// meddle is what someone else changes while the chain runs
+		meddle    map[string]string
+		wantPhase string
+		wantData  map[string]string
+	}{
+		{
+			name:      "other fields are kept",
+			meddle:    map[string]string{"owner": "ops"},
+			wantPhase: v1alpha1.StepPhaseRolledBack,
+			wantData:  map[string]string{"mode": "normal", "owner": "ops"},
+		},
+		{
+			name:      "changed fields are not rolled back",
+			meddle:    map[string]string{"mode": "manual"},
+			wantPhase: v1alpha1.StepPhaseRollbackSkipped,
+			wantData:  map[string]string{"mode": "manual"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			configMap := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "apps"},
+				Data:       map[string]string{"mode": "normal"},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+			engine := NewEngine(c, nil)
+			engine.RegisterExecutor("meddle", &MockExecutor{
+				ExecuteFunc: func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+					current := &corev1.ConfigMap{}
+					require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(target), current))
+					for k, v := range tt.meddle {
+						current.Data[k] = v
+					}
+					require.NoError(t, c.Update(ctx, current))
+					return nil, errors.New("interrupted")
+				},
+			})
+			executor := NewChainExecutor(engine)
+
+			action := &v1alpha1.HealingActionTemplate{
+				Name: "degrade",
+				Type: "chain",
+				Steps: []v1alpha1.ActionStep{
+					{Name: "degrade", Type: "patch", PatchAction: &v1alpha1.PatchAction{Type: "merge", Patches: []v1alpha1.PatchOperation{
+						{Path: []string{"data", "mode"}, Value: `"degraded"`},
+					}}},
+					{Name: "meddle", Type: "meddle"},
+				},
+			}
+			target := &unstructured.Unstructured{}
+			target.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(configMap), target))
+
+			result, err := executor.Execute(context.Background(), target, action)
+			require.Error(t, err)
+			require.Len(t, result.Steps, 2)
+			assert.Equal(t, tt.wantPhase, result.Steps[0].Phase)
+			if tt.wantPhase == v1alpha1.StepPhaseRollbackSkipped {
+				assert.Contains(t, result.Steps[0].Message, "data.mode was changed by someone else")
+			}
+
+			updated := &corev1.ConfigMap{}
+			require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(configMap), updated))
+			assert.Equal(t, tt.wantData, updated.Data)
+		})
+	}
+}
+
+func TestChainExecutor_Validate(t *testing.T) {
+	executor := NewChainExecutor(NewEngine(fake.NewClientBuilder().Build(), nil))
+	target := createUnstructuredDeployment("web", "apps")
+
+	tests := []struct {
+		name  string
+		steps []v1alpha1.ActionStep
+		err   string
+	}{
+		{name: "no steps", err: "requires steps"},
+		{
+			name:  "duplicate names",
+			steps: []v1alpha1.ActionStep{{Name: "check", Type: "verify", Condition: &v1alpha1.TargetCondition{Type: "Available"}}, {Name: "check", Type: "verify", Condition: &v1alpha1.TargetCondition{Type: "Available"}}},
+			err:   "duplicate step name check",
+		},
+		{
+			name:  "nested chain",
+			steps: []v1alpha1.ActionStep{{Name: "inner", Type: "chain"}},
+			err:   "unsupported type chain",
+		},
+		{
+			name:  "missing configuration",
+			steps: []v1alpha1.ActionStep{{Name: "grow", Type: "scale"}},
+			err:   "step grow",
+		},
+		{
+			name: "templated parameters are validated when rendered",
+			steps: []v1alpha1.ActionStep{{Name: "check", Type: "verify", Condition: &v1alpha1.TargetCondition{Type: "Available"}},
+				{Name: "scale", Type: "scale", ScaleAction: &v1alpha1.ScaleAction{Direction: "absolute", ReplicasTemplate: "{{.Steps.check.status}}"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(context.Background(), target, &v1alpha1.HealingActionTemplate{Type: "chain", Steps: tt.steps})
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestRenderStep(t *testing.T) {
+	data := stepData{
+		Target: stepTarget{Kind: "Deployment", Name: "web", Namespace: "apps"},
+		Steps:  map[string]map[string]string{"grow": {"previous_replicas": "2"}},
+	}
+	step := &v1alpha1.ActionStep{Name: "shrink", Type: "exec", ExecAction: &v1alpha1.ExecAction{
+		Command: []string{"notify", "{{.Target.Namespace}}/{{.Target.Name}}", "{{.Steps.grow.previous_replicas}}"},
+	}}
+
+	rendered, err := renderStep(step, data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notify", "apps/web", "2"}, rendered.ExecAction.Command)
+	assert.Equal(t, "{{.Steps.grow.previous_replicas}}", step.ExecAction.Command[2], "the step is not modified")
+
+	restore := &v1alpha1.ActionStep{Name: "restore", Type: "scale", ScaleAction: &v1alpha1.ScaleAction{
+		Direction: "absolute", ReplicasTemplate: "{{.Steps.grow.previous_replicas}}",
+	}}
+	rendered, err = renderStep(restore, data)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), rendered.ScaleAction.Replicas)
+
+	step.ExecAction.Command[2] = "{{.Steps.missing.output}}"
+	_, err = renderStep(step, data)
+	assert.ErrorContains(t, err, "shrink.execAction.command[2]")
+}
@@ -0,0 +1,103 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// Annotations attributing workload changes to healing actions
+const (
+	// AnnotationChangeCause is shown by kubectl rollout history
+	AnnotationChangeCause = "kubernetes.io/change-cause"
+
+	// AnnotationActionID is the namespace/name of the HealingAction that
+	// last changed the workload
+	AnnotationActionID = "kubeskippy.io/action-id"
+)
+
+// changeCauseKinds are the workloads whose rollout revisions copy the
+// workload's annotations
+var changeCauseKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// changeCauseActions are the action types that change the workload they
+// target
+var changeCauseActions = map[string]bool{
+	"restart":   true,
+	"scale":     true,
+	"patch":     true,
+	"hibernate": true,
+	"resize":    true,
+}
+
+// ChangeCause describes an action for the change-cause annotation
+func ChangeCause(action *v1alpha1.HealingAction) string {
+	return fmt.Sprintf("kubeskippy %s action %s/%s of policy %s/%s", action.Spec.Action.Type,
+		action.Namespace, action.Name, action.Spec.PolicyRef.Namespace, action.Spec.PolicyRef.Name)
+}
+
+// annotateChangeCause records the action on a workload target before the
+// action changes it, so that the rollout revision the change creates copies
+// the annotations. target is updated in place. It returns the previous
+// annotation values to restore if the action fails, or nil if the target
+// was not annotated.
+func annotateChangeCause(ctx context.Context, c client.Client, action *v1alpha1.HealingAction, target client.Object) map[string]*string {
+	if !changeCauseKinds[action.Spec.TargetResource.Kind] || !changeCauseActions[action.Spec.Action.Type] {
+		return nil
+	}
+
+	previous := make(map[string]*string)
+	for _, key := range []string{AnnotationChangeCause, AnnotationActionID} {
+		if value, ok := target.GetAnnotations()[key]; ok {
+			previous[key] = &value
+		} else {
+			previous[key] = nil
+		}
+	}
+	annotations := map[string]*string{
+		AnnotationChangeCause: stringPtr(ChangeCause(action)),
+		AnnotationActionID:    stringPtr(action.Namespace + "/" + action.Name),
+	}
+	if err := patchAnnotations(ctx, c, target, annotations); err != nil {
+		// Attribution is best effort; the action runs either way
+		log.FromContext(ctx).Error(err, "Failed to annotate change cause", "action", action.Name,
+			"target", fmt.Sprintf("%s/%s/%s", action.Spec.TargetResource.Kind, target.GetNamespace(), target.GetName()))
+		return nil
+	}
+	return previous
+}
+
+// restoreChangeCause puts back the annotations a failed action replaced
+func restoreChangeCause(ctx context.Context, c client.Client, target client.Object, previous map[string]*string) {
+	if previous == nil {
+		return
+	}
+	if err := patchAnnotations(ctx, c, target, previous); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to restore change cause", "target", target.GetName())
+	}
+}
+
+// patchAnnotations merge patches annotations of obj; nil values remove them
+func patchAnnotations(ctx context.Context, c client.Client, obj client.Object, annotations map[string]*string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return err
+	}
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,31 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MetricUpdateAttempts is the result metric holding how many attempts an
+// executor needed to write its change
+const MetricUpdateAttempts = "update_attempts"
+
+// retryOnConflict runs mutate, which must apply the change to target and
+// write it, retrying on 409 conflicts. Targets are fetched before the action
+// runs, so from the second attempt on target is re-read first and mutate
+// works on the fresh copy. It returns the number of attempts made.
+func retryOnConflict(ctx context.Context, c client.Client, target client.Object, mutate func() error) (int, error) {
+	attempts := 0
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		attempts++
+		if attempts > 1 {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(target), target); err != nil {
+				return fmt.Errorf("failed to re-read %s: %w", target.GetName(), err)
+			}
+		}
+		return mutate()
+	})
+	return attempts, err
+}
@@ -0,0 +1,129 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// conflictOnce fails the first write with a 409 after another writer has
+// changed the object, as happens when the target changes between the
+// reconcile's read and the executor's write
+func conflictOnce(concurrentWrite func(ctx context.Context, c client.WithWatch) error) interceptor.Funcs {
+	conflicted := false
+	conflict := func(ctx context.Context, c client.WithWatch, name string) error {
+		if conflicted {
+			return nil
+		}
+		conflicted = true
+		if err := concurrentWrite(ctx, c); err != nil {
+			return err
+		}
+		return apierrors.NewConflict(schema.GroupResource{Resource: "test"}, name, assert.AnError)
+	}
+
+	return interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := conflict(ctx, c, obj.GetName()); err != nil {
+				return err
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if err := conflict(ctx, c.(client.WithWatch), obj.GetName()); err != nil {
+				return err
+			}
+			return c.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+	}
+}
+
+func TestPatchExecutor_RetriesOnConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"mode": "normal"},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(configMap).
+		WithInterceptorFuncs(conflictOnce(func(ctx context.Context, c client.WithWatch) error {
+			current := &corev1.ConfigMap{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(configMap), current); err != nil {
+				return err
+			}
+			current.Data["owner"] = "someone-else"
+			return c.Update(ctx, current)
+		})).
+		Build()
+
+	result, err := NewPatchExecutor(c).Execute(context.Background(), configMap.DeepCopy(), &v1alpha1.HealingActionTemplate{
+		Type: "patch",
+		PatchAction: &v1alpha1.PatchAction{
+			Type:    "merge",
+			Patches: []v1alpha1.PatchOperation{{Path: []string{"data", "mode"}, Value: "safe"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "2", result.Metrics[MetricUpdateAttempts])
+
+	// The concurrent change survives because the retry re-read the object
+	updated := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(configMap), updated))
+	assert.Equal(t, map[string]string{"mode": "safe", "owner": "someone-else"}, updated.Data)
+}
+
+func TestScaleExecutor_RetriesOnConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deployment).
+		WithInterceptorFuncs(conflictOnce(func(ctx context.Context, c client.WithWatch) error {
+			current := &appsv1.Deployment{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), current); err != nil {
+				return err
+			}
+			current.Labels = map[string]string{"touched": "true"}
+			return c.Update(ctx, current)
+		})).
+		Build()
+
+	result, err := NewScaleExecutor(c).Execute(context.Background(), deployment.DeepCopy(), &v1alpha1.HealingActionTemplate{
+		Type:        "scale",
+		ScaleAction: &v1alpha1.ScaleAction{Direction: "absolute", Replicas: 4},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "2", result.Metrics[MetricUpdateAttempts])
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(deployment), updated))
+	assert.Equal(t, int32(4), *updated.Spec.Replicas)
+	assert.Equal(t, "true", updated.Labels["touched"])
+}
@@ -0,0 +1,151 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// restartContainers restarts the selected containers in place in every pod
+// of the target, leaving the other containers of the pods running.
+//
+// A container's image is the only field of a running container that may be
+// changed, and the kubelet restarts a container whose spec changed. Each
+// container is restarted by setting its image to a reference pinned to the
+// digest it is already running, so the same image is started again without
+// relying on any tool inside the container, and the kubelet stops the old
+// process with the pod's termination grace period even if it ignores SIGTERM.
+func (r *RestartExecutor) restartContainers(ctx context.Context, target client.Object, config *v1alpha1.RestartAction) ([]v1alpha1.ResourceChange, error) {
+	log := log.FromContext(ctx)
+
+	podList := &corev1.PodList{}
+	if err := r.client.List(ctx, podList, client.InNamespace(target.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods, err := targetPods(target, podList.Items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target pods: %w", err)
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found for %s/%s", target.GetNamespace(), target.GetName())
+	}
+
+	// Resolve every container first so a missing container doesn't leave
+	// the target partially restarted
+	images := make(map[string]map[string]string, len(pods))
+	for _, pod := range pods {
+		images[pod.Name] = make(map[string]string, len(config.Containers))
+		for _, name := range config.Containers {
+			image, err := restartImage(pod, name)
+			if err != nil {
+				return nil, err
+			}
+			images[pod.Name][name] = image
+		}
+	}
+
+	var changes []v1alpha1.ResourceChange
+	for i, pod := range pods {
+		// A cancelled attempt stops between pods, keeping the restarts made
+		if err := ctx.Err(); err != nil {
+			return changes, fmt.Errorf("stopped after restarting containers in %d of %d pods: %w", i, len(pods), err)
+		}
+		patch := client.StrategicMergeFrom(pod.DeepCopy())
+		var podChanges []v1alpha1.ResourceChange
+		for _, name := range config.Containers {
+			container := podContainer(pod, name)
+			log.Info("Restarting container", "pod", pod.Name, "namespace", pod.Namespace, "container", name)
+
+			podChanges = append(podChanges, v1alpha1.ResourceChange{
+				ResourceRef: fmt.Sprintf("Pod/%s/%s", pod.Namespace, pod.Name),
+				ChangeType:  "update",
+				Field:       fmt.Sprintf("containers[%s].image", name),
+				OldValue:    container.Image,
+				NewValue:    images[pod.Name][name],
+				Timestamp:   &metav1.Time{Time: time.Now()},
+			})
+			container.Image = images[pod.Name][name]
+		}
+
+		if err := r.client.Patch(ctx, pod, patch); err != nil {
+			return changes, fmt.Errorf("failed to restart containers in pod %s: %w", pod.Name, err)
+		}
+		changes = append(changes, podChanges...)
+	}
+	return changes, nil
+}
+
+// restartImage returns the image reference that restarts the named container
+// with the image it is running: the image reference pinned to the digest the
+// container runs. Containers already pinned alternate between the reference
+// with and without the tag, which resolve to the same digest.
+func restartImage(pod *corev1.Pod, name string) (string, error) {
+	container := podContainer(pod, name)
+	if container == nil {
+		return "", fmt.Errorf("container %s not found in pod %s", name, pod.Name)
+	}
+	status := podContainerStatus(pod, name)
+	if status == nil || status.ImageID == "" {
+		return "", fmt.Errorf("container %s in pod %s has not started an image yet", name, pod.Name)
+	}
+
+	_, digest, ok := strings.Cut(status.ImageID, "@")
+	if !ok {
+		return "", fmt.Errorf("container %s in pod %s does not report an image digest", name, pod.Name)
+	}
+	repository, tag := splitImage(container.Image)
+	if tag == "" {
+		tag = "latest"
+	}
+
+	pinned := repository + ":" + tag + "@" + digest
+	if container.Image != pinned {
+		return pinned, nil
+	}
+	return repository + "@" + digest, nil
+}
+
+// splitImage splits an image reference into its repository and tag,
+// dropping any digest
+func splitImage(image string) (repository, tag string) {
+	repository, _, _ = strings.Cut(image, "@")
+	// A colon after the last slash separates the tag; one before it is a
+	// registry port
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		return repository[:i], repository[i+1:]
+	}
+	return repository, ""
+}
+
+// podContainer returns the named container, including native sidecars
+// declared as init containers, or nil
+func podContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for i := range containers {
+			if containers[i].Name == name {
+				return &containers[i]
+			}
+		}
+	}
+	return nil
+}
+
+// podContainerStatus returns the status of the named container or nil
+func podContainerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].Name == name {
+				return &statuses[i]
+			}
+		}
+	}
+	return nil
+}
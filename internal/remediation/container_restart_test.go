@@ -0,0 +1,112 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const envoyDigest = "sha256:4f1c3f0e2a7b"
+
+func TestRestartContainers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "test"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "test", Image: "nginx:1.27"},
+					{Name: "envoy", Image: "registry.local:5000/envoy:v1.30"},
+				},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "test", ImageID: "docker.io/library/nginx@sha256:aa"},
+					{Name: "envoy", ImageID: "registry.local:5000/envoy@" + envoyDigest},
+				},
+			},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pod("test-1"), pod("test-2")).
+		Build()
+
+	executor := NewRestartExecutor(fakeClient)
+	action := &v1alpha1.HealingActionTemplate{
+		Type:          "restart",
+		RestartAction: &v1alpha1.RestartAction{Strategy: "rolling", Containers: []string{"envoy"}},
+	}
+	deployment := createUnstructuredDeployment("test-deployment", "default")
+	require.NoError(t, executor.Validate(context.Background(), deployment, action))
+
+	result, err := executor.Execute(context.Background(), deployment, action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, "update", result.Changes[0].ChangeType)
+	assert.Equal(t, "containers[envoy].image", result.Changes[0].Field)
+	assert.Equal(t, "registry.local:5000/envoy:v1.30", result.Changes[0].OldValue)
+	assert.Equal(t, "registry.local:5000/envoy:v1.30@"+envoyDigest, result.Changes[0].NewValue)
+
+	// The selected container is pinned to the digest it runs, the other
+	// containers and the pods themselves are left alone
+	pods := &corev1.PodList{}
+	require.NoError(t, fakeClient.List(context.Background(), pods))
+	require.Len(t, pods.Items, 2)
+	for _, p := range pods.Items {
+		assert.Equal(t, "nginx:1.27", p.Spec.Containers[0].Image)
+		assert.Equal(t, "registry.local:5000/envoy:v1.30@"+envoyDigest, p.Spec.Containers[1].Image)
+	}
+
+	// Restarting a pinned container drops the tag to change the spec again
+	result, err = executor.Execute(context.Background(), createUnstructuredPod("test-1", "default"), action)
+	require.NoError(t, err)
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, "registry.local:5000/envoy@"+envoyDigest, result.Changes[0].NewValue)
+
+	// Unknown containers fail before anything is restarted
+	action.RestartAction.Containers = []string{"test", "missing"}
+	_, err = executor.Execute(context.Background(), createUnstructuredPod("test-2", "default"), action)
+	assert.ErrorContains(t, err, "container missing not found in pod test-2")
+	live := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test-2"}, live))
+	assert.Equal(t, "nginx:1.27", live.Spec.Containers[0].Image)
+
+	// A cancelled attempt stops before the next pod
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	action.RestartAction.Containers = []string{"test"}
+	_, err = executor.Execute(cancelled, deployment, action)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "stopped after restarting containers in 0 of 2 pods")
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		image      string
+		repository string
+		tag        string
+	}{
+		{"nginx", "nginx", ""},
+		{"nginx:1.27", "nginx", "1.27"},
+		{"registry.local:5000/envoy", "registry.local:5000/envoy", ""},
+		{"registry.local:5000/envoy:v1@sha256:ab", "registry.local:5000/envoy", "v1"},
+	}
+	for _, tt := range tests {
+		repository, tag := splitImage(tt.image)
+		assert.Equal(t, tt.repository, repository, tt.image)
+		assert.Equal(t, tt.tag, tag, tt.image)
+	}
+}
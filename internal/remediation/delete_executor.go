@@ -97,11 +97,16 @@ func (d *DeleteExecutor) Execute(ctx context.Context, target client.Object, acti
 		"force", config.Force)
 
 	// Force delete by removing finalizers if requested
+	attempts := 0
 	if config.Force && len(finalizers) > 0 {
 		log.Info("Force deleting: removing finalizers", "finalizers", finalizers)
-		target.SetFinalizers([]string{})
-		if err := d.client.Update(ctx, target); err != nil && !errors.IsNotFound(err) {
-			log.Error(err, "Failed to remove finalizers")
+		var err error
+		attempts, err = retryOnConflict(ctx, d.client, target, func() error {
+			target.SetFinalizers([]string{})
+			return d.client.Update(ctx, target)
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to remove finalizers", "attempts", attempts)
 		}
 	}
 
@@ -140,6 +145,7 @@ func (d *DeleteExecutor) Execute(ctx context.Context, target client.Object, acti
 			"grace_period_seconds": fmt.Sprintf("%d", config.GracePeriodSeconds),
 			"force":                fmt.Sprintf("%v", config.Force),
 			"propagation_policy":   config.PropagationPolicy,
+			MetricUpdateAttempts:   fmt.Sprintf("%d", attempts),
 		},
 	}, nil
 }
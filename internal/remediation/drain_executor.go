@@ -0,0 +1,183 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// DrainExecutor cordons a node and evicts its pods. Drain actions are
+// created to escalate taint actions whose node is still unhealthy after
+// their window, and always need approval.
+type DrainExecutor struct {
+	client client.Client
+}
+
+// NewDrainExecutor creates a new drain executor
+func NewDrainExecutor(client client.Client) *DrainExecutor {
+	return &DrainExecutor{
+		client: client,
+	}
+}
+
+// Execute cordons the target node and evicts its pods
+func (d *DrainExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	startTime := time.Now()
+	if err := d.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Validation failed: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	evicted, err := drainNode(ctx, d.client, target.GetName())
+	now := metav1.Now()
+	result := &kubetypes.ActionResult{
+		Changes: []v1alpha1.ResourceChange{{
+			ResourceRef: fmt.Sprintf("Node//%s", target.GetName()),
+			ChangeType:  "update",
+			Field:       "spec.unschedulable",
+			NewValue:    "true",
+			Timestamp:   &now,
+		}},
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Metrics: map[string]string{
+			"evicted_pods": fmt.Sprintf("%d", evicted),
+		},
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to drain node: %v", err)
+		result.Error = err
+		return result, err
+	}
+
+	log.FromContext(ctx).Info("Drained node", "node", target.GetName(), "evicted", evicted)
+	result.Success = true
+	result.Message = fmt.Sprintf("Drained node %s, evicted %d pods", target.GetName(), evicted)
+	return result, nil
+}
+
+// Validate checks that the target is an existing node
+func (d *DrainExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	if kind := target.GetObjectKind().GroupVersionKind().Kind; kind != "Node" {
+		return fmt.Errorf("drain actions target nodes, not %s", kind)
+	}
+	if err := d.client.Get(ctx, client.ObjectKey{Name: target.GetName()}, &corev1.Node{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("node not found")
+		}
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+	return nil
+}
+
+// DryRun reports the pods that would be evicted
+func (d *DrainExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	if err := d.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Validation failed: %v", err),
+		}, err
+	}
+
+	pods, err := drainablePods(ctx, d.client, target.GetName())
+	if err != nil {
+		return &kubetypes.ActionResult{Success: false, Message: err.Error()}, err
+	}
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: fmt.Sprintf("Dry-run: Would cordon node %s and evict %d pods", target.GetName(), len(pods)),
+		Changes: []v1alpha1.ResourceChange{{
+			ResourceRef: fmt.Sprintf("Node//%s", target.GetName()),
+			ChangeType:  "update",
+			Field:       "spec.unschedulable",
+			NewValue:    "true",
+		}},
+		Metrics: map[string]string{
+			"evicted_pods": fmt.Sprintf("%d", len(pods)),
+		},
+	}, nil
+}
+
+// drainNode cordons the node and evicts its pods, except mirror and
+// DaemonSet pods, like kubectl drain. Evictions go through the eviction
+// API, so PodDisruptionBudgets are respected; the pods they block are
+// reported in the error. It returns the number of evicted pods.
+func drainNode(ctx context.Context, c client.Client, name string) (int, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+		return 0, fmt.Errorf("failed to get node: %w", err)
+	}
+	if _, err := retryOnConflict(ctx, c, node, func() error {
+		if node.Spec.Unschedulable {
+			return nil
+		}
+		node.Spec.Unschedulable = true
+		return c.Update(ctx, node)
+	}); err != nil {
+		return 0, fmt.Errorf("failed to cordon node %s: %w", name, err)
+	}
+
+	pods, err := drainablePods(ctx, c, name)
+	if err != nil {
+		return 0, err
+	}
+
+	evicted := 0
+	var errs []error
+	for _, pod := range pods {
+		if err := ctx.Err(); err != nil {
+			return evicted, fmt.Errorf("stopped draining node %s after evicting %d pods: %w", name, evicted, err)
+		}
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		if err := c.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s/%s: %w", pod.Namespace, pod.Name, err))
+			continue
+		}
+		evicted++
+	}
+
+	if len(errs) > 0 {
+		return evicted, fmt.Errorf("failed to evict %d pods from node %s: %w", len(errs), name, errors.Join(errs...))
+	}
+	return evicted, nil
+}
+
+// drainablePods returns the running pods on the node that a drain evicts
+func drainablePods(ctx context.Context, c client.Client, name string) ([]*corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName != name || isDaemonSetPod(pod) || pod.Annotations[corev1.MirrorPodAnnotationKey] != "" {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
@@ -2,11 +2,14 @@ package remediation
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -14,15 +17,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/faults"
 	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
 )
 
+const (
+	// defaultAttemptTimeout bounds an execution attempt whose context has
+	// no deadline
+	defaultAttemptTimeout = 5 * time.Minute
+
+	// cleanupTimeout bounds the steps that undo or record an attempt after
+	// it was cancelled
+	cleanupTimeout = 30 * time.Second
+)
+
 // Engine implements the RemediationEngine interface
 type Engine struct {
 	client    client.Client
 	executors map[string]kubetypes.ActionExecutor
 	recorder  ActionRecorder
-	mu        sync.RWMutex
+	cascade   *CascadeSimulator
+	hooks     *HookRunner
+	preflight *RBACPreflight
+	// serverDryRun, if set, is the client dry runs are sent to the API
+	// server through
+	serverDryRun client.Client
+	// faults, if set, fails executions at the configured rate
+	faults *faults.Injector
+	// changeCause annotates workloads with the action changing them
+	changeCause bool
+	mu          sync.RWMutex
 
 	// For tracking in-flight actions
 	activeActions map[string]*ActionContext
@@ -58,6 +82,8 @@ func NewEngine(client client.Client, recorder ActionRecorder) *Engine {
 		client:        client,
 		executors:     make(map[string]kubetypes.ActionExecutor),
 		recorder:      recorder,
+		cascade:       NewCascadeSimulator(client),
+		hooks:         NewHookRunner(client, nil, nil),
 		activeActions: make(map[string]*ActionContext),
 	}
 
@@ -66,6 +92,13 @@ func NewEngine(client client.Client, recorder ActionRecorder) *Engine {
 	engine.RegisterExecutor("scale", NewScaleExecutor(client))
 	engine.RegisterExecutor("patch", NewPatchExecutor(client))
 	engine.RegisterExecutor("delete", NewDeleteExecutor(client))
+	engine.RegisterExecutor("finalizer", NewFinalizerExecutor(client))
+	engine.RegisterExecutor("hibernate", NewHibernateExecutor(client))
+	engine.RegisterExecutor("exec", NewExecExecutor(client))
+	engine.RegisterExecutor("resize", NewResizeExecutor(client))
+	engine.RegisterExecutor("taint", NewTaintExecutor(client))
+	engine.RegisterExecutor("drain", NewDrainExecutor(client))
+	engine.RegisterExecutor("chain", NewChainExecutor(engine))
 
 	return engine
 }
@@ -77,6 +110,74 @@ func (e *Engine) RegisterExecutor(actionType string, executor kubetypes.ActionEx
 	e.executors[actionType] = executor
 }
 
+// SetHookRunner replaces the runner used for pre-action hooks
+func (e *Engine) SetHookRunner(hooks *HookRunner) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hooks = hooks
+}
+
+// SetPodExecutor enables exec actions through the registered exec executor
+func (e *Engine) SetPodExecutor(executor PodExecutor) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if exec, ok := e.executors["exec"].(*ExecExecutor); ok {
+		exec.SetPodExecutor(executor)
+	}
+}
+
+// SetHelperPodRunner enables exec actions with an execution environment
+// through the registered exec executor
+func (e *Engine) SetHelperPodRunner(helpers *HelperPodRunner) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if exec, ok := e.executors["exec"].(*ExecExecutor); ok {
+		exec.SetHelperPodRunner(helpers)
+	}
+}
+
+// SetCapacityChecker makes the registered scale executor check node
+// headroom before scale-ups
+func (e *Engine) SetCapacityChecker(checker *CapacityChecker) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if scale, ok := e.executors["scale"].(*ScaleExecutor); ok {
+		scale.SetCapacityChecker(checker)
+	}
+}
+
+// SetRBACPreflight enables RBAC pre-flight checks before execution
+func (e *Engine) SetRBACPreflight(preflight *RBACPreflight) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.preflight = preflight
+}
+
+// SetServerDryRun makes DryRun execute the built-in executors against the
+// API server through c with dryRun=All, instead of simulating them
+func (e *Engine) SetServerDryRun(c client.Client) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.serverDryRun = c
+}
+
+// SetFaultInjector makes executions of the executors fail at the injector's
+// executor failure rate
+func (e *Engine) SetFaultInjector(injector *faults.Injector) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.faults = injector
+}
+
+// SetChangeCauseAnnotations makes actions changing a Deployment,
+// StatefulSet or DaemonSet record themselves in its change-cause and
+// action-id annotations, so rollout history attributes the change
+func (e *Engine) SetChangeCauseAnnotations(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.changeCause = enabled
+}
+
 // ExecuteAction performs the healing action
 func (e *Engine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingAction) (*kubetypes.ActionResult, error) {
 	log := log.FromContext(ctx)
@@ -92,11 +193,25 @@ func (e *Engine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingActi
 	actionCtx := e.trackAction(action)
 	defer e.untrackAction(action.Name)
 
-	// Create cancelable context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	// Resources created for the action are cleaned up with it
+	ctx = withOwnerAction(ctx, action)
+
+	// The attempt is bounded by the caller's deadline, and can be
+	// cancelled with CancelAction
+	var cancel context.CancelFunc
+	if _, ok := ctx.Deadline(); ok {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, defaultAttemptTimeout)
+	}
 	actionCtx.CancelFunc = cancel
 	defer cancel()
 
+	// Undoing and recording the attempt must still work once it was
+	// cancelled
+	cleanupCtx, cleanupCancel := cleanupContext(ctx)
+	defer cleanupCancel()
+
 	// Get the executor
 	executor, err := e.GetActionExecutor(action.Spec.Action.Type)
 	if err != nil {
@@ -124,6 +239,21 @@ func (e *Engine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingActi
 	// Store original state for potential rollback
 	actionCtx.OriginalObj = target.DeepCopyObject()
 
+	// Make sure every request of the action is permitted before any of them
+	// is made
+	if e.preflight != nil {
+		if err := e.preflight.Check(ctx, action); err != nil {
+			log.Info("Action failed RBAC pre-flight check", "action", action.Name, "reason", err.Error())
+			return &kubetypes.ActionResult{
+				Success:   false,
+				Message:   err.Error(),
+				Error:     err,
+				StartTime: actionCtx.StartTime,
+				EndTime:   time.Now(),
+			}, err
+		}
+	}
+
 	// Validate the action
 	if err := executor.Validate(ctx, target, &action.Spec.Action); err != nil {
 		return &kubetypes.ActionResult{
@@ -135,24 +265,91 @@ func (e *Engine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingActi
 		}, nil
 	}
 
-	// Execute the action
-	result, err := executor.Execute(ctx, target, &action.Spec.Action)
+	// Simulate the downstream impact before destructive actions
+	blastRadius := e.simulateBlastRadius(ctx, action, target)
+	if blastRadius != nil && blastRadius.Blocked {
+		err := &BlastRadiusBlockedError{Reason: blastRadius.Reason}
+		log.Info("Action blocked by blast radius simulation", "action", action.Name, "reason", blastRadius.Reason)
+		return &kubetypes.ActionResult{
+			Success:     false,
+			Message:     fmt.Sprintf("Action blocked: %s", blastRadius.Reason),
+			Error:       err,
+			Metrics:     blastRadiusMetrics(blastRadius),
+			BlastRadius: blastRadius,
+			StartTime:   actionCtx.StartTime,
+			EndTime:     time.Now(),
+		}, err
+	}
+
+	// Capture diagnostics before the target is mutated
+	var diagnostics []v1alpha1.DiagnosticCapture
+	if e.hooks != nil && len(action.Spec.Action.PreActionHooks) > 0 {
+		diagnostics = e.hooks.Run(ctx, target, action.Spec.Action.PreActionHooks, action.Spec.Action.Execution)
+	}
+
+	// Attribute the workload change to the action
+	e.mu.RLock()
+	changeCause := e.changeCause
+	e.mu.RUnlock()
+	var previousCause map[string]*string
+	if changeCause {
+		previousCause = annotateChangeCause(ctx, e.client, action, target)
+	}
+
+	// Hold the workload's rollouts while the action changes it
+	var freeze *rolloutFreeze
+	if action.Spec.Action.FreezeRollout {
+		var frozen client.Object
+		freeze, frozen, err = e.freezeTarget(ctx, action, target)
+		if err != nil {
+			restoreChangeCause(cleanupCtx, e.client, target, previousCause)
+			return &kubetypes.ActionResult{
+				Success:     false,
+				Message:     err.Error(),
+				Error:       err,
+				Diagnostics: diagnostics,
+				StartTime:   actionCtx.StartTime,
+				EndTime:     time.Now(),
+			}, err
+		}
+		target = frozen
+	}
+
+	// Execute the action, unless the attempt ran out while preparing it
+	var result *kubetypes.ActionResult
+	if err = ctx.Err(); err == nil {
+		result, err = executor.Execute(ctx, target, &action.Spec.Action)
+	}
 	if result == nil {
 		result = &kubetypes.ActionResult{
 			StartTime: actionCtx.StartTime,
 			EndTime:   time.Now(),
 		}
 	}
+	if err != nil && ctx.Err() != nil {
+		err = attemptCancelled(ctx, result, err)
+		log.Info("Execution attempt stopped", "action", action.Name,
+			"reason", ctx.Err().Error(), "changes", len(result.Changes))
+	}
+	if freeze != nil {
+		err = e.thawRollout(ctx, freeze, result, err)
+	}
 	result.StartTime = actionCtx.StartTime
 	result.EndTime = time.Now()
+	attachBlastRadius(result, blastRadius)
+	result.Diagnostics = append(diagnostics, result.Diagnostics...)
 
 	// Record the action for audit and potential rollback
 	if e.recorder != nil {
-		if recordErr := e.recorder.RecordAction(ctx, action, result, actionCtx.OriginalObj); recordErr != nil {
+		if recordErr := e.recorder.RecordAction(cleanupCtx, action, result, actionCtx.OriginalObj); recordErr != nil {
 			log.Error(recordErr, "Failed to record action")
 		}
 	}
 
+	if err != nil || !result.Success {
+		restoreChangeCause(cleanupCtx, e.client, target, previousCause)
+	}
+
 	if err != nil {
 		result.Success = false
 		result.Error = err
@@ -166,6 +363,12 @@ func (e *Engine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingActi
 		return result, fmt.Errorf(result.Message)
 	}
 
+	// Remember the generation we left the target at so later manual
+	// changes can be told apart from our own
+	if updated, err := e.getTargetResource(cleanupCtx, &action.Spec.TargetResource); err == nil {
+		result.TargetGeneration = updated.GetGeneration()
+	}
+
 	log.Info("Healing action completed successfully",
 		"action", action.Name,
 		"duration", result.EndTime.Sub(result.StartTime))
@@ -173,6 +376,26 @@ func (e *Engine) ExecuteAction(ctx context.Context, action *v1alpha1.HealingActi
 	return result, nil
 }
 
+// cleanupContext returns a context for undoing and recording an attempt
+// that keeps the values of ctx but not its cancellation
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+}
+
+// attemptCancelled marks the result of an attempt stopped by its context
+// and returns an error that matches the context's error, so a timed out
+// attempt is classified as a timeout. Changes made before the executor
+// stopped are kept as partial progress.
+func attemptCancelled(ctx context.Context, result *kubetypes.ActionResult, err error) error {
+	ctxErr := ctx.Err()
+	if !errors.Is(err, ctxErr) {
+		err = fmt.Errorf("%w: %v", ctxErr, err)
+	}
+	result.Partial = len(result.Changes) > 0
+	result.Message = fmt.Sprintf("Attempt stopped after %d changes: %v", len(result.Changes), ctxErr)
+	return err
+}
+
 // DryRun simulates the action without executing
 func (e *Engine) DryRun(ctx context.Context, action *v1alpha1.HealingAction) (*kubetypes.ActionResult, error) {
 	log := log.FromContext(ctx)
@@ -217,8 +440,17 @@ func (e *Engine) DryRun(ctx context.Context, action *v1alpha1.HealingAction) (*k
 		}, nil
 	}
 
-	// Perform dry-run
-	result, err := executor.DryRun(ctx, target, &action.Spec.Action)
+	// Perform dry-run, against the API server if configured
+	e.mu.RLock()
+	serverClient := e.serverDryRun
+	e.mu.RUnlock()
+	serverSide := serverClient != nil && supportsServerDryRun(&action.Spec.Action)
+	var result *kubetypes.ActionResult
+	if serverSide {
+		result, err = serverSideDryRun(ctx, serverClient, action, target)
+	} else {
+		result, err = executor.DryRun(ctx, target, &action.Spec.Action)
+	}
 	if result == nil {
 		result = &kubetypes.ActionResult{
 			StartTime: startTime,
@@ -242,6 +474,18 @@ func (e *Engine) DryRun(ctx context.Context, action *v1alpha1.HealingAction) (*k
 		result.Metrics = make(map[string]string)
 	}
 	result.Metrics["dry_run"] = "true"
+	result.Metrics["dry_run_mode"] = "client"
+	if serverSide {
+		result.Metrics["dry_run_mode"] = "server"
+	}
+
+	// Report what the blast radius simulation would have decided
+	if blastRadius := e.simulateBlastRadius(ctx, action, target); blastRadius != nil {
+		attachBlastRadius(result, blastRadius)
+		if blastRadius.Blocked {
+			result.Message = fmt.Sprintf("%s (would be blocked: %s)", result.Message, blastRadius.Reason)
+		}
+	}
 
 	log.Info("Dry-run completed",
 		"action", action.Name,
@@ -251,11 +495,181 @@ func (e *Engine) DryRun(ctx context.Context, action *v1alpha1.HealingAction) (*k
 	return result, nil
 }
 
+// freezeTarget freezes the rollouts of the target and returns the frozen
+// target for the executor to change
+func (e *Engine) freezeTarget(ctx context.Context, action *v1alpha1.HealingAction, target client.Object) (*rolloutFreeze, client.Object, error) {
+	freeze, err := freezeRollout(ctx, e.client, target)
+	if err != nil || freeze == nil {
+		return nil, target, err
+	}
+
+	frozen, err := e.getTargetResource(ctx, &action.Spec.TargetResource)
+	if err != nil {
+		if releaseErr := freeze.release(ctx); releaseErr != nil {
+			log.FromContext(ctx).Error(releaseErr, "Failed to resume rollouts", "action", action.Name)
+		}
+		return nil, target, err
+	}
+	freeze.before = frozen.DeepCopyObject().(client.Object)
+	return freeze, frozen, nil
+}
+
+// thawRollout resumes the rollouts of a frozen workload after the action
+// changed it and waits for its changes to roll out healthy. If the action
+// failed or the rollout did not become healthy, the fields the action
+// changed are reverted, unless someone else changed them since. A workload
+// resumed by someone else during the action is being rolled out by them
+// and is left alone.
+func (e *Engine) thawRollout(ctx context.Context, freeze *rolloutFreeze, result *kubetypes.ActionResult, execErr error) error {
+	log := log.FromContext(ctx)
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	after, err := freeze.current(cleanupCtx)
+	if err != nil {
+		if execErr != nil {
+			return fmt.Errorf("%w; %v", execErr, err)
+		}
+		return err
+	}
+	if err := freeze.takenOver(after); err != nil {
+		log.Info("Frozen workload taken over, leaving it to its new owner", "reason", err.Error())
+		if execErr != nil {
+			return fmt.Errorf("%w; %v, changes were left in place", execErr, err)
+		}
+		return fmt.Errorf("%w; changes were left in place", err)
+	}
+
+	failure := execErr
+	if failure == nil && !result.Success {
+		failure = fmt.Errorf("%s", result.Message)
+	}
+	if failure == nil {
+		if err := freeze.release(cleanupCtx); err != nil {
+			return err
+		}
+		if failure = freeze.awaitHealthy(ctx); failure == nil {
+			if result.Metrics == nil {
+				result.Metrics = make(map[string]string)
+			}
+			result.Metrics["rollout_frozen"] = "true"
+			return nil
+		}
+	}
+
+	// Waiting for the rollout may have used up the cleanup time
+	revertCtx, cancelRevert := cleanupContext(ctx)
+	defer cancelRevert()
+	log.Info("Reverting the changes to the frozen workload", "reason", failure.Error())
+	revertErr := revertChanges(revertCtx, e.client, freeze.before, after)
+	if err := freeze.release(revertCtx); err != nil {
+		log.Error(err, "Failed to resume rollouts")
+	}
+	switch {
+	case errors.Is(revertErr, errTargetDiverged):
+		return fmt.Errorf("%w; rollback skipped: %v", failure, revertErr)
+	case revertErr != nil:
+		return fmt.Errorf("%w; rollback failed: %v", failure, revertErr)
+	}
+	return fmt.Errorf("%w; changes were rolled back", failure)
+}
+
+// simulateBlastRadius runs the cascade simulation for destructive actions.
+// If the simulation fails, an action with blast radius limits is blocked,
+// since its impact cannot be checked against them; without limits the
+// simulation is only informational and the action proceeds.
+func (e *Engine) simulateBlastRadius(ctx context.Context, action *v1alpha1.HealingAction, target client.Object) *v1alpha1.BlastRadiusReport {
+	if !isDestructive(&action.Spec.Action, target) || e.cascade == nil {
+		return nil
+	}
+
+	report, err := e.cascade.Simulate(ctx, target)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to simulate blast radius", "action", action.Name)
+		if action.Spec.BlastRadiusLimits == nil {
+			return nil
+		}
+		return &v1alpha1.BlastRadiusReport{
+			Blocked:     true,
+			Reason:      fmt.Sprintf("blast radius could not be simulated: %v", err),
+			SimulatedAt: &metav1.Time{Time: time.Now()},
+		}
+	}
+	e.cascade.Evaluate(report, action.Spec.BlastRadiusLimits)
+	return report
+}
+
+// destructiveActionTypes are simulated before execution
+var destructiveActionTypes = map[string]bool{
+	"delete":    true,
+	"hibernate": true,
+	"drain":     true,
+}
+
+// isDestructive reports whether the action removes pods: destructive action
+// types, restarts that drain a pod by deleting it rather than rolling a
+// workload or restarting single containers, and chains with such a step
+func isDestructive(action *v1alpha1.HealingActionTemplate, target client.Object) bool {
+	if destructiveActionTypes[action.Type] {
+		return true
+	}
+	for i := range action.Steps {
+		if isDestructive(action.Steps[i].ActionTemplate(action), target) {
+			return true
+		}
+	}
+	return action.Type == "restart" && target.GetObjectKind().GroupVersionKind().Kind == "Pod" &&
+		(action.RestartAction == nil || len(action.RestartAction.Containers) == 0)
+}
+
+// attachBlastRadius adds the report and its summary metrics to the result
+func attachBlastRadius(result *kubetypes.ActionResult, report *v1alpha1.BlastRadiusReport) {
+	if report == nil {
+		return
+	}
+	result.BlastRadius = report
+	if result.Metrics == nil {
+		result.Metrics = make(map[string]string)
+	}
+	for k, v := range blastRadiusMetrics(report) {
+		result.Metrics[k] = v
+	}
+}
+
+// blastRadiusMetrics summarises a report as result metrics
+func blastRadiusMetrics(report *v1alpha1.BlastRadiusReport) map[string]string {
+	return map[string]string{
+		"blast_radius_affected_pods":      fmt.Sprintf("%d", len(report.AffectedPods)),
+		"blast_radius_services_affected":  fmt.Sprintf("%d", len(report.ServicesLosingEndpoints)),
+		"blast_radius_pdbs_violated":      fmt.Sprintf("%d", len(report.ViolatedPDBs)),
+		"blast_radius_remaining_replicas": fmt.Sprintf("%d", report.RemainingReplicas),
+		"blast_radius_blocked":            fmt.Sprintf("%v", report.Blocked),
+	}
+}
+
 // Rollback reverses a previously executed action
 func (e *Engine) Rollback(ctx context.Context, action *v1alpha1.HealingAction) error {
 	log := log.FromContext(ctx)
 	log.Info("Rolling back healing action", "action", action.Name)
 
+	// Scale and hibernate actions only restore the prior replica count,
+	// through the scale subresource, so unrelated spec changes are kept
+	if action.Spec.Action.Type == "scale" || action.Spec.Action.Type == "hibernate" {
+		if replicas, ok := PreviousReplicas(action); ok {
+			return e.rollbackScale(ctx, action, replicas)
+		}
+	}
+
+	// Taint actions only remove their taint from the node
+	if action.Spec.Action.Type == "taint" {
+		taint := NodeTaint(action.Spec.Action.TaintAction)
+		if err := removeTaint(ctx, e.client, action.Spec.TargetResource.Name, taint.Key); err != nil {
+			return err
+		}
+		log.Info("Rollback completed successfully", "action", action.Name, "taint", taint.Key)
+		return nil
+	}
+
 	if e.recorder == nil {
 		return fmt.Errorf("no action recorder configured for rollback")
 	}
@@ -270,8 +684,19 @@ func (e *Engine) Rollback(ctx context.Context, action *v1alpha1.HealingAction) e
 		return fmt.Errorf("no rollback information available for action %s", action.Name)
 	}
 
+	if err := e.restoreState(ctx, history.OriginalState); err != nil {
+		return err
+	}
+
+	log.Info("Rollback completed successfully", "action", action.Name)
+	return nil
+}
+
+// restoreState writes a recorded state of a resource back, recreating the
+// resource if it was deleted
+func (e *Engine) restoreState(ctx context.Context, state runtime.Object) error {
 	// Convert original state back to unstructured
-	originalUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(history.OriginalState)
+	originalUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(state)
 	if err != nil {
 		return fmt.Errorf("failed to convert original state: %w", err)
 	}
@@ -287,12 +712,12 @@ func (e *Engine) Rollback(ctx context.Context, action *v1alpha1.HealingAction) e
 	}
 
 	if err := e.client.Get(ctx, key, current); err != nil {
-		if errors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
 			// Resource was deleted, recreate it
 			if err := e.client.Create(ctx, original); err != nil {
 				return fmt.Errorf("failed to recreate resource: %w", err)
 			}
-			log.Info("Resource recreated during rollback", "resource", key)
+			log.FromContext(ctx).Info("Resource recreated during rollback", "resource", key)
 			return nil
 		}
 		return fmt.Errorf("failed to get current resource state: %w", err)
@@ -303,8 +728,40 @@ func (e *Engine) Rollback(ctx context.Context, action *v1alpha1.HealingAction) e
 	if err := e.client.Update(ctx, original); err != nil {
 		return fmt.Errorf("failed to restore resource: %w", err)
 	}
+	return nil
+}
 
-	log.Info("Rollback completed successfully", "action", action.Name)
+// PreviousReplicas returns the replica count recorded before a scale or
+// hibernate action
+func PreviousReplicas(action *v1alpha1.HealingAction) (int32, bool) {
+	if action.Status.Result == nil {
+		return 0, false
+	}
+	for _, change := range action.Status.Result.Changes {
+		if change.Field != "spec.replicas" || change.OldValue == "" {
+			continue
+		}
+		replicas, err := strconv.ParseInt(change.OldValue, 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		return int32(replicas), true
+	}
+	return 0, false
+}
+
+// rollbackScale restores the replica count of a scale action's target
+func (e *Engine) rollbackScale(ctx context.Context, action *v1alpha1.HealingAction, replicas int32) error {
+	target, err := e.getTargetResource(ctx, &action.Spec.TargetResource)
+	if err != nil {
+		return fmt.Errorf("failed to get target resource: %w", err)
+	}
+	if err := NewScaleExecutor(e.client).SetReplicas(ctx, target, replicas); err != nil {
+		return fmt.Errorf("failed to restore replicas: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Rollback completed successfully",
+		"action", action.Name, "replicas", replicas)
 	return nil
 }
 
@@ -317,6 +774,9 @@ func (e *Engine) GetActionExecutor(actionType string) (kubetypes.ActionExecutor,
 	if !exists {
 		return nil, fmt.Errorf("no executor registered for action type: %s", actionType)
 	}
+	if e.faults != nil {
+		executor = &faultyExecutor{ActionExecutor: executor, actionType: actionType, faults: e.faults}
+	}
 
 	return executor, nil
 }
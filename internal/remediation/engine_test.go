@@ -18,7 +18,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/faults"
 	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
 )
 
 // MockExecutor is a mock action executor for testing
@@ -419,6 +421,43 @@ func TestEngine_Rollback(t *testing.T) {
 	assert.Equal(t, "value1", restored.Data["key1"])
 }
 
+func TestEngine_RollbackScale(t *testing.T) {
+	deployment := createUnstructuredDeployment("web", "default")
+	require.NoError(t, unstructured.SetNestedField(deployment.Object, int64(6), "spec", "replicas"))
+	c := newScaleClient(t, deployment)
+
+	// No recorder history: the prior replica count comes from the action
+	// status, so rollback survives operator restarts
+	engine := NewEngine(c, NewInMemoryActionRecorder(time.Hour))
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "scale-web", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "scale-up", Type: "scale"},
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Result: &v1alpha1.ActionResult{
+				Success: true,
+				Changes: []v1alpha1.ResourceChange{{
+					ResourceRef: "Deployment/default/web",
+					ChangeType:  "scale",
+					Field:       "spec.replicas",
+					OldValue:    "3",
+					NewValue:    "6",
+				}},
+			},
+		},
+	}
+
+	require.NoError(t, engine.Rollback(context.Background(), action))
+
+	restored := &unstructured.Unstructured{}
+	restored.SetGroupVersionKind(deployment.GroupVersionKind())
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(deployment), restored))
+	replicas, _, _ := unstructured.NestedInt64(restored.Object, "spec", "replicas")
+	assert.Equal(t, int64(3), replicas)
+}
+
 func TestEngine_ConcurrentActions(t *testing.T) {
 	// Create fake client
 	scheme := runtime.NewScheme()
@@ -497,3 +536,168 @@ func TestEngine_GetTargetResource(t *testing.T) {
 	assert.Equal(t, "default", unstructuredObj.GetNamespace())
 	assert.Equal(t, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, unstructuredObj.GroupVersionKind())
 }
+
+func TestEngine_FaultInjection(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+	}
+	engine := NewEngine(fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build(), nil)
+	executed := 0
+	engine.RegisterExecutor("restart", &MockExecutor{
+		ExecuteFunc: func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+			executed++
+			return &kubetypes.ActionResult{Success: true, Message: "Pod restarted"}, nil
+		},
+	})
+	engine.SetFaultInjector(faults.NewInjector(config.FaultInjectionConfig{ExecutorFailureRate: 1}, 1))
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart-test-pod", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "test-pod", Namespace: "default"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+		},
+	}
+	result, err := engine.ExecuteAction(context.Background(), action)
+	assert.ErrorIs(t, err, faults.ErrInjected)
+	require.NotNil(t, result)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "restart executor failed")
+	assert.Equal(t, 1, executed, "the action runs before the failure is reported")
+
+	// Dry runs are not failed
+	_, err = engine.DryRun(context.Background(), action)
+	assert.NoError(t, err)
+}
+
+func TestEngine_ChangeCauseAnnotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default",
+			Annotations: map[string]string{AnnotationChangeCause: "kubectl set image deployment/web web=web:2"}},
+		Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, pod).Build()
+	engine := NewEngine(c, nil)
+	engine.SetChangeCauseAnnotations(true)
+	ctx := context.Background()
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart-web", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "web-policy", Namespace: "default"},
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default"},
+			Action: v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart",
+				RestartAction: &v1alpha1.RestartAction{Strategy: "rolling"}},
+		},
+	}
+	result, err := engine.ExecuteAction(ctx, action)
+	require.NoError(t, err)
+	require.True(t, result.Success, result.Message)
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), updated))
+	assert.Equal(t, "kubeskippy restart action default/restart-web of policy default/web-policy",
+		updated.Annotations[AnnotationChangeCause])
+	assert.Equal(t, "default/restart-web", updated.Annotations[AnnotationActionID])
+	assert.NotEmpty(t, updated.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"],
+		"the executor works on the annotated target")
+
+	// Failed actions restore the previous attribution
+	engine.RegisterExecutor("scale", &MockExecutor{
+		ExecuteFunc: func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+			return &kubetypes.ActionResult{Success: false, Message: "scale failed"}, nil
+		},
+	})
+	failed := action.DeepCopy()
+	failed.Name = "scale-web"
+	failed.Spec.Action = v1alpha1.HealingActionTemplate{Name: "scale", Type: "scale"}
+	_, err = engine.ExecuteAction(ctx, failed)
+	assert.Error(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), updated))
+	assert.Equal(t, "default/restart-web", updated.Annotations[AnnotationActionID])
+
+	// Pods have no rollout history
+	podAction := action.DeepCopy()
+	podAction.Spec.TargetResource = v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "web-1", Namespace: "default"}
+	engine.RegisterExecutor("restart", &MockExecutor{})
+	_, err = engine.ExecuteAction(ctx, podAction)
+	require.NoError(t, err)
+	updatedPod := &corev1.Pod{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(pod), updatedPod))
+	assert.Empty(t, updatedPod.Annotations)
+}
+
+func TestEngine_AttemptCancellation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default",
+			Annotations: map[string]string{AnnotationChangeCause: "kubectl scale deployment/web --replicas=2"}},
+		Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment).Build()
+	engine := NewEngine(c, nil)
+	engine.SetChangeCauseAnnotations(true)
+
+	started := make(chan struct{}, 1)
+	executed := 0
+	engine.RegisterExecutor("scale", &MockExecutor{
+		ExecuteFunc: func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+			executed++
+			started <- struct{}{}
+			// The first step of the sequence is made, the second waits
+			changes := []v1alpha1.ResourceChange{{ResourceRef: "Deployment/default/web", ChangeType: "scale", Field: "spec.replicas", OldValue: "2", NewValue: "3"}}
+			<-ctx.Done()
+			return &kubetypes.ActionResult{Changes: changes}, fmt.Errorf("failed to scale: %w", ctx.Err())
+		},
+	})
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "scale-web", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "scale", Type: "scale"},
+		},
+	}
+
+	// The caller's deadline stops the executor and keeps its progress
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, err := engine.ExecuteAction(ctx, action)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotNil(t, result)
+	assert.False(t, result.Success)
+	assert.True(t, result.Partial)
+	assert.Len(t, result.Changes, 1)
+	assert.Contains(t, result.Message, "Attempt stopped after 1 changes")
+	<-started
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(deployment), updated))
+	assert.Equal(t, "kubectl scale deployment/web --replicas=2", updated.Annotations[AnnotationChangeCause],
+		"the attribution is restored after the deadline")
+
+	// CancelAction stops a running attempt
+	done := make(chan error, 1)
+	go func() {
+		_, err := engine.ExecuteAction(context.Background(), action)
+		done <- err
+	}()
+	<-started
+	require.NoError(t, engine.CancelAction("scale-web"))
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Empty(t, engine.GetActiveActions())
+
+	// An attempt that ran out while being prepared never executes
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = engine.ExecuteAction(expired, action)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, executed)
+}
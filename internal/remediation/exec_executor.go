@@ -0,0 +1,242 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// ExecExecutor runs a command in containers of the target's pods, or in
+// helper pods next to them when the action sets an execution environment
+type ExecExecutor struct {
+	client   client.Client
+	executor PodExecutor
+	helpers  *HelperPodRunner
+}
+
+// NewExecExecutor creates a new exec executor. Commands can only be run
+// once a pod executor is set.
+func NewExecExecutor(client client.Client) *ExecExecutor {
+	return &ExecExecutor{
+		client: client,
+	}
+}
+
+// SetPodExecutor sets the executor commands are run through
+func (e *ExecExecutor) SetPodExecutor(executor PodExecutor) {
+	e.executor = executor
+}
+
+// SetHelperPodRunner sets the runner of actions with an execution
+// environment
+func (e *ExecExecutor) SetHelperPodRunner(helpers *HelperPodRunner) {
+	e.helpers = helpers
+}
+
+// Execute runs the command in every selected container of the target's
+// pods, or in one helper pod per target pod. The output of each run is
+// returned as a diagnostic capture.
+func (e *ExecExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	log := log.FromContext(ctx)
+	startTime := time.Now()
+
+	if err := e.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Validation failed: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	pods, err := e.pods(ctx, target)
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   err.Error(),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	config := action.ExecAction
+	timeout := config.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+
+	var captures []v1alpha1.DiagnosticCapture
+	var failed []string
+	for _, pod := range pods {
+		for _, container := range e.containers(pod, action) {
+			source := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container)
+			// A cancelled attempt stops between containers
+			if err := ctx.Err(); err != nil {
+				return &kubetypes.ActionResult{
+					Success:     false,
+					Message:     fmt.Sprintf("Stopped before %s after running in %d containers", source, len(captures)),
+					Error:       err,
+					Diagnostics: captures,
+					StartTime:   startTime,
+					EndTime:     time.Now(),
+				}, err
+			}
+			log.Info("Running command in container", "pod", pod.Name, "namespace", pod.Namespace, "container", container)
+
+			execCtx, cancel := context.WithTimeout(ctx, timeout)
+			var output string
+			if action.Execution != nil {
+				output, err = e.helpers.Run(execCtx, action.Execution, pod, config.Command, timeout)
+			} else {
+				output, err = e.executor.Exec(execCtx, pod.Namespace, pod.Name, container, config.Command)
+			}
+			cancel()
+
+			capture := v1alpha1.DiagnosticCapture{
+				Type:       "exec",
+				Source:     source,
+				CapturedAt: metav1.Now(),
+			}
+			if err != nil {
+				capture.Error = fmt.Sprintf("exec failed: %v", err)
+				failed = append(failed, source)
+			}
+			capture.Output, capture.Truncated = truncateCapture(output)
+			captures = append(captures, capture)
+		}
+	}
+
+	result := &kubetypes.ActionResult{
+		Success:     len(failed) == 0,
+		Diagnostics: captures,
+		StartTime:   startTime,
+		EndTime:     time.Now(),
+		Metrics: map[string]string{
+			"containers":        fmt.Sprintf("%d", len(captures)),
+			"failed_containers": fmt.Sprintf("%d", len(failed)),
+		},
+	}
+	if len(failed) > 0 {
+		err := fmt.Errorf("command failed in %s", strings.Join(failed, ", "))
+		result.Message = err.Error()
+		result.Error = err
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Ran %s in %d containers", config.Command[0], len(captures))
+	return result, nil
+}
+
+// Validate checks that a command is configured and can be run
+func (e *ExecExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	if action.ExecAction == nil {
+		return fmt.Errorf("exec action missing configuration")
+	}
+	if len(action.ExecAction.Command) == 0 {
+		return fmt.Errorf("exec action requires a command")
+	}
+	if action.Execution != nil {
+		if e.helpers == nil {
+			return fmt.Errorf("exec actions with an execution environment require a helper pod runner")
+		}
+		return nil
+	}
+	if e.executor == nil {
+		return fmt.Errorf("exec actions require a pod executor")
+	}
+	return nil
+}
+
+// DryRun reports the containers the command would run in
+func (e *ExecExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	if err := e.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Validation failed: %v", err),
+		}, err
+	}
+
+	pods, err := e.pods(ctx, target)
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	var sources []string
+	for _, pod := range pods {
+		for _, container := range e.containers(pod, action) {
+			sources = append(sources, fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container))
+		}
+	}
+
+	where := strings.Join(sources, ", ")
+	if action.Execution != nil {
+		where = fmt.Sprintf("helper pods of image %s for %s", action.Execution.Image, where)
+	}
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: fmt.Sprintf("Dry-run: Would run %s in %s", strings.Join(action.ExecAction.Command, " "), where),
+		Metrics: map[string]string{
+			"containers": fmt.Sprintf("%d", len(sources)),
+		},
+	}, nil
+}
+
+// pods returns the running pods of the target
+func (e *ExecExecutor) pods(ctx context.Context, target client.Object) ([]*corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := e.client.List(ctx, podList, client.InNamespace(target.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods, err := targetPods(target, podList.Items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target pods: %w", err)
+	}
+
+	var running []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+	if len(running) == 0 {
+		return nil, fmt.Errorf("no running pods found for %s/%s", target.GetNamespace(), target.GetName())
+	}
+	return running, nil
+}
+
+// containers returns the containers of the pod the action's command runs
+// in, the helper container when it runs in a helper pod
+func (e *ExecExecutor) containers(pod *corev1.Pod, action *v1alpha1.HealingActionTemplate) []string {
+	if action.Execution != nil {
+		return []string{helperContainerName}
+	}
+	return execContainers(pod, action.ExecAction.Containers)
+}
+
+// execContainers returns the containers of the pod a command runs in: the
+// requested ones present in the pod, or the pod's first container
+func execContainers(pod *corev1.Pod, containers []string) []string {
+	if len(containers) == 0 {
+		return []string{hookContainer(pod, "")}
+	}
+	var present []string
+	for _, name := range containers {
+		if podContainer(pod, name) != nil {
+			present = append(present, name)
+		}
+	}
+	return present
+}
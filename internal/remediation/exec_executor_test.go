@@ -0,0 +1,71 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestExecExecutor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "test"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "test"}, {Name: "envoy"}}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pod("test-1", corev1.PodRunning), pod("test-2", corev1.PodRunning), pod("test-3", corev1.PodPending)).
+		Build()
+
+	executor := NewExecExecutor(fakeClient)
+	action := &v1alpha1.HealingActionTemplate{
+		Type:       "exec",
+		ExecAction: &v1alpha1.ExecAction{Command: []string{"nginx", "-s", "reload"}},
+	}
+	deployment := createUnstructuredDeployment("test-deployment", "default")
+
+	assert.ErrorContains(t, executor.Validate(context.Background(), deployment, action), "require a pod executor")
+
+	podExecutor := &mockPodExecutor{output: "reloaded"}
+	executor.SetPodExecutor(podExecutor)
+
+	dryRun, err := executor.DryRun(context.Background(), deployment, action)
+	require.NoError(t, err)
+	assert.Contains(t, dryRun.Message, "default/test-1/test")
+	assert.Empty(t, podExecutor.calls)
+
+	// The command runs in the first container of every running pod
+	result, err := executor.Execute(context.Background(), deployment, action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Len(t, podExecutor.calls, 2)
+	require.Len(t, result.Diagnostics, 2)
+	assert.Equal(t, "exec", result.Diagnostics[0].Type)
+	assert.Equal(t, "reloaded", result.Diagnostics[0].Output)
+	assert.Empty(t, result.Changes)
+
+	// Failures are reported per container
+	podExecutor = &mockPodExecutor{output: "bad config", err: errors.New("exit code 1")}
+	executor.SetPodExecutor(podExecutor)
+	action.ExecAction.Containers = []string{"envoy"}
+	result, err = executor.Execute(context.Background(), createUnstructuredPod("test-1", "default"), action)
+	assert.ErrorContains(t, err, "command failed in default/test-1/envoy")
+	assert.False(t, result.Success)
+	require.Len(t, result.Diagnostics, 1)
+	assert.Equal(t, "bad config", result.Diagnostics[0].Output)
+	assert.Contains(t, result.Diagnostics[0].Error, "exit code 1")
+}
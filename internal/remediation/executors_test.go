@@ -11,8 +11,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
 )
@@ -209,6 +211,133 @@ func TestScaleExecutor(t *testing.T) {
 	}
 }
 
+// newScaleClient returns a fake client that serves the scale subresource of
+// unstructured objects from spec.replicas, as the API server does for
+// custom resources declaring /scale
+func newScaleClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	scheme.AddKnownTypeWithName(rolloutGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(rolloutGVK.GroupVersion().WithKind("RolloutList"), &unstructured.UnstructuredList{})
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceGet: func(ctx context.Context, c client.Client, subResource string, obj, body client.Object, opts ...client.SubResourceGetOption) error {
+				require.Equal(t, "scale", subResource)
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+					return err
+				}
+				replicas, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object, "spec", "replicas")
+				return unstructured.SetNestedField(body.(*unstructured.Unstructured).Object, replicas, "spec", "replicas")
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				require.Equal(t, "scale", subResource)
+				options := client.SubResourceUpdateOptions{}
+				options.ApplyOptions(opts)
+				replicas, _, _ := unstructured.NestedInt64(options.SubResourceBody.(*unstructured.Unstructured).Object, "spec", "replicas")
+
+				current := obj.DeepCopyObject().(*unstructured.Unstructured)
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+					return err
+				}
+				if err := unstructured.SetNestedField(current.Object, replicas, "spec", "replicas"); err != nil {
+					return err
+				}
+				return c.Update(ctx, current)
+			},
+		}).
+		Build()
+}
+
+var rolloutGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+func createUnstructuredRollout(name, namespace string, replicas int64) *unstructured.Unstructured {
+	rollout := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": replicas},
+	}}
+	rollout.SetGroupVersionKind(rolloutGVK)
+	rollout.SetName(name)
+	rollout.SetNamespace(namespace)
+	return rollout
+}
+
+func TestScaleExecutor_CustomResource(t *testing.T) {
+	rollout := createUnstructuredRollout("checkout", "default", 4)
+	c := newScaleClient(t, rollout)
+	executor := NewScaleExecutor(c)
+
+	action := &v1alpha1.HealingActionTemplate{
+		Type: "scale",
+		ScaleAction: &v1alpha1.ScaleAction{
+			Direction:   "down",
+			Replicas:    3,
+			MinReplicas: 2,
+		},
+	}
+	require.NoError(t, executor.Validate(context.Background(), rollout, action))
+
+	result, err := executor.Execute(context.Background(), rollout, action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, "Rollout/default/checkout", result.Changes[0].ResourceRef)
+	assert.Equal(t, "4", result.Changes[0].OldValue)
+	assert.Equal(t, "2", result.Changes[0].NewValue)
+
+	updated := &unstructured.Unstructured{}
+	updated.SetGroupVersionKind(rolloutGVK)
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(rollout), updated))
+	replicas, _, _ := unstructured.NestedInt64(updated.Object, "spec", "replicas")
+	assert.Equal(t, int64(2), replicas)
+}
+
+func TestScaleExecutor_ValidateRequiresScaleSubresource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+
+	err := NewScaleExecutor(c).Validate(context.Background(), pod, &v1alpha1.HealingActionTemplate{
+		Type:        "scale",
+		ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: 1},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scale not supported for Pod")
+}
+
+func TestDesiredReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int32
+		action   v1alpha1.ScaleAction
+		expected int32
+		wantErr  bool
+	}{
+		{name: "up", current: 3, action: v1alpha1.ScaleAction{Direction: "up", Replicas: 2}, expected: 5},
+		{name: "up capped by max", current: 3, action: v1alpha1.ScaleAction{Direction: "up", Replicas: 5, MaxReplicas: 6}, expected: 6},
+		{name: "up raised to min", current: 0, action: v1alpha1.ScaleAction{Direction: "up", Replicas: 1, MinReplicas: 2}, expected: 2},
+		{name: "down floored at min", current: 3, action: v1alpha1.ScaleAction{Direction: "down", Replicas: 5, MinReplicas: 1}, expected: 1},
+		{name: "down never negative", current: 1, action: v1alpha1.ScaleAction{Direction: "down", Replicas: 5}, expected: 0},
+		{name: "down capped by max", current: 12, action: v1alpha1.ScaleAction{Direction: "down", Replicas: 1, MaxReplicas: 8}, expected: 8},
+		{name: "absolute within bounds", current: 3, action: v1alpha1.ScaleAction{Direction: "absolute", Replicas: 10, MinReplicas: 1, MaxReplicas: 8}, expected: 8},
+		{name: "invalid direction", current: 3, action: v1alpha1.ScaleAction{Direction: "sideways"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := desiredReplicas(tt.current, &tt.action)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
 func TestPatchExecutor(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
@@ -0,0 +1,36 @@
+package remediation
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/faults"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// faultyExecutor fails executions at the injector's executor failure rate.
+// The action is executed before the failure is reported, so that rollbacks
+// restore real changes.
+type faultyExecutor struct {
+	kubetypes.ActionExecutor
+	actionType string
+	faults     *faults.Injector
+}
+
+// Execute executes the action and reports an injected failure
+func (f *faultyExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	result, err := f.ActionExecutor.Execute(ctx, target, action)
+	if err != nil {
+		return result, err
+	}
+	if injected := f.faults.ExecutorFailure(ctx, f.actionType); injected != nil {
+		if result != nil {
+			result.Success = false
+			result.Message = injected.Error()
+		}
+		return result, injected
+	}
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// FinalizerExecutor releases resources stuck in Terminating by removing
+// finalizers that were opted in as safe, or every finalizer when the action
+// escalates to a force delete
+type FinalizerExecutor struct {
+	client client.Client
+}
+
+// NewFinalizerExecutor creates a new finalizer executor
+func NewFinalizerExecutor(client client.Client) *FinalizerExecutor {
+	return &FinalizerExecutor{
+		client: client,
+	}
+}
+
+// finalizerPlan splits the finalizers of a target into those the action
+// removes and those left in place
+func finalizerPlan(finalizers []string, config *v1alpha1.FinalizerAction) (remove, keep []string) {
+	safe := make(map[string]bool, len(config.RemoveFinalizers))
+	for _, f := range config.RemoveFinalizers {
+		safe[f] = true
+	}
+	for _, f := range finalizers {
+		if safe[f] {
+			remove = append(remove, f)
+		} else {
+			keep = append(keep, f)
+		}
+	}
+	if len(keep) > 0 && config.ForceDelete {
+		return append(remove, keep...), nil
+	}
+	return remove, keep
+}
+
+// Execute removes the finalizers selected by the action
+func (f *FinalizerExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	log := log.FromContext(ctx)
+	startTime := time.Now()
+
+	if err := f.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Validation failed: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	ref := fmt.Sprintf("%s/%s/%s", target.GetObjectKind().GroupVersionKind().Kind, target.GetNamespace(), target.GetName())
+	var removed, kept []string
+	attempts, err := retryOnConflict(ctx, f.client, target, func() error {
+		removed, kept = finalizerPlan(target.GetFinalizers(), action.FinalizerAction)
+		if len(removed) == 0 {
+			return nil
+		}
+		target.SetFinalizers(kept)
+		return f.client.Update(ctx, target)
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to remove finalizers: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	if len(removed) == 0 {
+		err := fmt.Errorf("blocked by finalizers %s, none of which may be removed", strings.Join(kept, ", "))
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("%s stuck terminating: %v", ref, err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	now := metav1.Now()
+	changes := make([]v1alpha1.ResourceChange, len(removed))
+	for i, finalizer := range removed {
+		changes[i] = v1alpha1.ResourceChange{
+			ResourceRef: ref,
+			ChangeType:  "update",
+			Field:       "metadata.finalizers",
+			OldValue:    finalizer,
+			NewValue:    "removed",
+			Timestamp:   &now,
+		}
+	}
+
+	log.Info("Removed finalizers from stuck resource",
+		"resource", ref, "removed", removed, "remaining", kept, "force", action.FinalizerAction.ForceDelete)
+
+	message := fmt.Sprintf("Removed finalizers %s from %s", strings.Join(removed, ", "), ref)
+	if len(kept) > 0 {
+		message += fmt.Sprintf(", still blocked by %s", strings.Join(kept, ", "))
+	}
+	return &kubetypes.ActionResult{
+		Success:   true,
+		Message:   message,
+		Changes:   changes,
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Metrics: map[string]string{
+			"removed_finalizers":   strings.Join(removed, ","),
+			"remaining_finalizers": strings.Join(kept, ","),
+			"force":                fmt.Sprintf("%v", action.FinalizerAction.ForceDelete),
+			MetricUpdateAttempts:   fmt.Sprintf("%d", attempts),
+		},
+	}, nil
+}
+
+// Validate checks that the target is terminating on finalizers
+func (f *FinalizerExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	if action.FinalizerAction == nil {
+		return fmt.Errorf("finalizer action missing configuration")
+	}
+
+	key := client.ObjectKey{
+		Namespace: target.GetNamespace(),
+		Name:      target.GetName(),
+	}
+	if err := f.client.Get(ctx, key, target); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("resource not found")
+		}
+		return fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	if target.GetDeletionTimestamp() == nil {
+		return fmt.Errorf("resource is not terminating")
+	}
+	if len(target.GetFinalizers()) == 0 {
+		return fmt.Errorf("resource has no finalizers")
+	}
+	return nil
+}
+
+// DryRun reports which finalizers would be removed
+func (f *FinalizerExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	if err := f.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Validation failed: %v", err),
+		}, err
+	}
+
+	ref := fmt.Sprintf("%s/%s/%s", target.GetObjectKind().GroupVersionKind().Kind, target.GetNamespace(), target.GetName())
+	removed, kept := finalizerPlan(target.GetFinalizers(), action.FinalizerAction)
+
+	simulatedChanges := make([]v1alpha1.ResourceChange, len(removed))
+	for i, finalizer := range removed {
+		simulatedChanges[i] = v1alpha1.ResourceChange{
+			ResourceRef: ref,
+			ChangeType:  "update",
+			Field:       "metadata.finalizers",
+			OldValue:    finalizer,
+			NewValue:    "would be removed",
+		}
+	}
+
+	message := fmt.Sprintf("Dry-run: Would remove finalizers %s from %s", strings.Join(removed, ", "), ref)
+	if len(removed) == 0 {
+		message = fmt.Sprintf("Dry-run: %s is blocked by finalizers %s, none of which may be removed", ref, strings.Join(kept, ", "))
+	} else if len(kept) > 0 {
+		message += fmt.Sprintf(", still blocked by %s", strings.Join(kept, ", "))
+	}
+
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: message,
+		Changes: simulatedChanges,
+		Metrics: map[string]string{
+			"removed_finalizers":   strings.Join(removed, ","),
+			"remaining_finalizers": strings.Join(kept, ","),
+			"force":                fmt.Sprintf("%v", action.FinalizerAction.ForceDelete),
+		},
+	}, nil
+}
@@ -0,0 +1,122 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestFinalizerExecutor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	stuckPod := func(finalizers ...string) *corev1.Pod {
+		return &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "stuck",
+				Namespace:         "apps",
+				Finalizers:        finalizers,
+				DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		target        *corev1.Pod
+		config        *v1alpha1.FinalizerAction
+		expectedError bool
+		remaining     []string
+		deleted       bool
+	}{
+		{
+			name:      "removes only safe finalizers",
+			target:    stuckPod("example.com/orphaned", "example.com/live"),
+			config:    &v1alpha1.FinalizerAction{RemoveFinalizers: []string{"example.com/orphaned"}},
+			remaining: []string{"example.com/live"},
+		},
+		{
+			name:          "nothing removable",
+			target:        stuckPod("example.com/live"),
+			config:        &v1alpha1.FinalizerAction{RemoveFinalizers: []string{"example.com/orphaned"}},
+			expectedError: true,
+			remaining:     []string{"example.com/live"},
+		},
+		{
+			name:    "force delete removes every finalizer",
+			target:  stuckPod("example.com/orphaned", "example.com/live"),
+			config:  &v1alpha1.FinalizerAction{ForceDelete: true},
+			deleted: true,
+		},
+		{
+			name:          "missing configuration",
+			target:        stuckPod("example.com/orphaned"),
+			expectedError: true,
+			remaining:     []string{"example.com/orphaned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.target).
+				Build()
+			executor := NewFinalizerExecutor(fakeClient)
+			action := &v1alpha1.HealingActionTemplate{Type: "finalizer", FinalizerAction: tt.config}
+
+			if tt.config != nil {
+				result, err := executor.DryRun(context.Background(), tt.target.DeepCopy(), action)
+				require.NoError(t, err)
+				assert.True(t, result.Success)
+				assert.Contains(t, result.Message, "Dry-run")
+			}
+
+			result, err := executor.Execute(context.Background(), tt.target.DeepCopy(), action)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.False(t, result.Success)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, result.Success)
+			}
+
+			current := &corev1.Pod{}
+			err = fakeClient.Get(context.Background(), client.ObjectKeyFromObject(tt.target), current)
+			if tt.deleted {
+				assert.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.remaining, current.Finalizers)
+		})
+	}
+}
+
+func TestFinalizerExecutorRequiresTerminating(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "running", Namespace: "apps", Finalizers: []string{"example.com/orphaned"},
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+
+	err := NewFinalizerExecutor(fakeClient).Validate(context.Background(), pod, &v1alpha1.HealingActionTemplate{
+		Type:            "finalizer",
+		FinalizerAction: &v1alpha1.FinalizerAction{RemoveFinalizers: []string{"example.com/orphaned"}},
+	})
+	assert.ErrorContains(t, err, "not terminating")
+}
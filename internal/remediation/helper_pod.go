@@ -0,0 +1,193 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// LabelHelperPod marks helper pods started by the remediation engine
+	LabelHelperPod = "kubeskippy.io/helper-pod"
+
+	// LabelHelperTarget names the target pod a helper pod was started for
+	LabelHelperTarget = "kubeskippy.io/helper-target"
+
+	// helperContainerName is the name of the helper pod's only container
+	helperContainerName = "helper"
+
+	// helperPollInterval is how often a helper pod's phase is checked
+	helperPollInterval = time.Second
+)
+
+// HelperPodRunner runs commands in short-lived helper pods next to a target
+// pod and deletes them once the command finished
+type HelperPodRunner struct {
+	client       client.Client
+	clientset    kubernetes.Interface
+	pollInterval time.Duration
+}
+
+// NewHelperPodRunner creates a helper pod runner. The clientset is used to
+// read the command's output from the helper pod's logs.
+func NewHelperPodRunner(client client.Client, clientset kubernetes.Interface) *HelperPodRunner {
+	return &HelperPodRunner{
+		client:       client,
+		clientset:    clientset,
+		pollInterval: helperPollInterval,
+	}
+}
+
+// Run starts a helper pod for the target pod, waits for the command to
+// finish within timeout and returns its output. The helper pod is deleted
+// whatever the outcome, and helper pods left over from earlier runs for the
+// target pod are deleted first.
+func (h *HelperPodRunner) Run(ctx context.Context, env *v1alpha1.ExecutionEnvironment, target *corev1.Pod, command []string, timeout time.Duration) (string, error) {
+	log := log.FromContext(ctx)
+
+	if err := h.cleanup(ctx, target); err != nil {
+		return "", err
+	}
+
+	pod := helperPod(env, target, command, timeout)
+	// The janitor deletes the pod if it outlives its action
+	if owner := ownerAction(ctx); owner != "" {
+		pod.Labels[LabelOwnerAction] = string(owner)
+	}
+	if err := h.client.Create(ctx, pod); err != nil {
+		return "", fmt.Errorf("failed to create helper pod: %w", err)
+	}
+	log.Info("Started helper pod", "pod", pod.Name, "namespace", pod.Namespace, "image", env.Image)
+	defer func() {
+		// Delete even when the action's context is done
+		deleteCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := h.delete(deleteCtx, pod); err != nil {
+			log.Error(err, "Failed to delete helper pod", "pod", pod.Name)
+		}
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	phase, err := h.wait(runCtx, pod)
+	output := h.output(ctx, pod)
+	if err != nil {
+		return output, fmt.Errorf("helper pod %s did not finish within %v: %w", pod.Name, timeout, err)
+	}
+	if phase != corev1.PodSucceeded {
+		return output, fmt.Errorf("helper pod %s failed", pod.Name)
+	}
+	return output, nil
+}
+
+// helperPod returns the helper pod running command for the target pod
+func helperPod(env *v1alpha1.ExecutionEnvironment, target *corev1.Pod, command []string, timeout time.Duration) *corev1.Pod {
+	deadline := int64(timeout / time.Second)
+	if deadline < 1 {
+		deadline = 1
+	}
+	automount := env.ServiceAccountName != ""
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kubeskippy-helper-",
+			Namespace:    target.Namespace,
+			Labels: map[string]string{
+				LabelHelperPod:    "true",
+				LabelHelperTarget: target.Name,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:        &deadline,
+			ServiceAccountName:           env.ServiceAccountName,
+			AutomountServiceAccountToken: &automount,
+			NodeSelector:                 env.NodeSelector,
+			Affinity:                     env.Affinity,
+			Tolerations:                  env.Tolerations,
+			Containers: []corev1.Container{{
+				Name:      helperContainerName,
+				Image:     env.Image,
+				Command:   command,
+				Resources: env.Resources,
+				Env: []corev1.EnvVar{
+					{Name: "TARGET_POD_NAME", Value: target.Name},
+					{Name: "TARGET_POD_NAMESPACE", Value: target.Namespace},
+					{Name: "TARGET_POD_IP", Value: target.Status.PodIP},
+					{Name: "TARGET_NODE_NAME", Value: target.Spec.NodeName},
+				},
+			}},
+		},
+	}
+	if env.TargetNode {
+		pod.Spec.NodeName = target.Spec.NodeName
+	}
+	return pod
+}
+
+// wait polls the helper pod until it succeeded or failed
+func (h *HelperPodRunner) wait(ctx context.Context, pod *corev1.Pod) (corev1.PodPhase, error) {
+	var phase corev1.PodPhase
+	err := wait.PollUntilContextCancel(ctx, h.pollInterval, true, func(ctx context.Context) (bool, error) {
+		current := &corev1.Pod{}
+		if err := h.client.Get(ctx, client.ObjectKeyFromObject(pod), current); err != nil {
+			return false, err
+		}
+		phase = current.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	})
+	return phase, err
+}
+
+// output reads the helper container's logs, which hold the command's output
+func (h *HelperPodRunner) output(ctx context.Context, pod *corev1.Pod) string {
+	if h.clientset == nil {
+		return ""
+	}
+	limitBytes := int64(maxCaptureBytes + 1)
+	stream, err := h.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  helperContainerName,
+		LimitBytes: &limitBytes,
+	}).Stream(ctx)
+	if err != nil {
+		return ""
+	}
+	defer stream.Close()
+	data, _ := io.ReadAll(io.LimitReader(stream, limitBytes))
+	return string(data)
+}
+
+// cleanup deletes helper pods left over for the target pod, e.g. when the
+// operator restarted while a command ran
+func (h *HelperPodRunner) cleanup(ctx context.Context, target *corev1.Pod) error {
+	pods := &corev1.PodList{}
+	if err := h.client.List(ctx, pods, client.InNamespace(target.Namespace),
+		client.MatchingLabels{LabelHelperPod: "true", LabelHelperTarget: target.Name}); err != nil {
+		return fmt.Errorf("failed to list helper pods: %w", err)
+	}
+	for i := range pods.Items {
+		if err := h.delete(ctx, &pods.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// delete removes a helper pod without waiting for it to shut down
+func (h *HelperPodRunner) delete(ctx context.Context, pod *corev1.Pod) error {
+	if err := h.client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete helper pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
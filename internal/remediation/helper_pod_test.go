@@ -0,0 +1,183 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// helperPodClient returns a fake client whose helper pods end in phase,
+// recording the created helper pods
+func helperPodClient(t *testing.T, phase corev1.PodPhase, objs ...client.Object) (client.Client, *[]*corev1.Pod) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	var created []*corev1.Pod
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if err := c.Create(ctx, obj, opts...); err != nil {
+					return err
+				}
+				pod, ok := obj.(*corev1.Pod)
+				if !ok {
+					return nil
+				}
+				created = append(created, pod.DeepCopy())
+				pod.Status.Phase = phase
+				return c.Status().Update(ctx, pod)
+			},
+		}).
+		Build()
+	return c, &created
+}
+
+func helperTargetPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "test"}},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "test"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.7"},
+	}
+}
+
+func TestHelperPodRunner(t *testing.T) {
+	env := &v1alpha1.ExecutionEnvironment{
+		Image:              "jdk:21",
+		ServiceAccountName: "heap-dumper",
+		TargetNode:         true,
+		Tolerations:        []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	}
+	command := []string{"jcmd", "1", "GC.heap_dump", "/dumps/heap.hprof"}
+
+	tests := []struct {
+		name        string
+		phase       corev1.PodPhase
+		timeout     time.Duration
+		expectError string
+	}{
+		{name: "succeeded", phase: corev1.PodSucceeded, timeout: time.Minute},
+		{name: "failed", phase: corev1.PodFailed, timeout: time.Minute, expectError: "failed"},
+		{name: "timed out", phase: corev1.PodPending, timeout: 50 * time.Millisecond, expectError: "did not finish within 50ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := helperTargetPod("web-1")
+			// A helper pod left over from an earlier run
+			leftover := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kubeskippy-helper-old", Namespace: "default",
+				Labels: map[string]string{LabelHelperPod: "true", LabelHelperTarget: "web-1"}}}
+			c, created := helperPodClient(t, tt.phase, target, leftover)
+			runner := NewHelperPodRunner(c, kubefake.NewSimpleClientset())
+			runner.pollInterval = 10 * time.Millisecond
+
+			ctx := withOwnerAction(context.Background(), &v1alpha1.HealingAction{ObjectMeta: metav1.ObjectMeta{UID: "action-uid"}})
+			output, err := runner.Run(ctx, env, target, command, tt.timeout)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "fake logs", output)
+			}
+
+			require.Len(t, *created, 1)
+			helper := (*created)[0]
+			assert.Equal(t, "default", helper.Namespace)
+			assert.Equal(t, "web-1", helper.Labels[LabelHelperTarget])
+			assert.Equal(t, "action-uid", helper.Labels[LabelOwnerAction])
+			assert.Equal(t, "node-1", helper.Spec.NodeName, "helper pods can run on the target's node")
+			assert.Equal(t, "heap-dumper", helper.Spec.ServiceAccountName)
+			assert.True(t, *helper.Spec.AutomountServiceAccountToken)
+			assert.Equal(t, corev1.RestartPolicyNever, helper.Spec.RestartPolicy)
+			assert.NotNil(t, helper.Spec.ActiveDeadlineSeconds)
+			assert.Equal(t, env.Tolerations, helper.Spec.Tolerations)
+			require.Len(t, helper.Spec.Containers, 1)
+			assert.Equal(t, "jdk:21", helper.Spec.Containers[0].Image)
+			assert.Equal(t, command, helper.Spec.Containers[0].Command)
+			assert.Contains(t, helper.Spec.Containers[0].Env, corev1.EnvVar{Name: "TARGET_POD_IP", Value: "10.0.0.7"})
+
+			// Helper pods are deleted whatever the outcome
+			pods := &corev1.PodList{}
+			require.NoError(t, c.List(context.Background(), pods, client.MatchingLabels{LabelHelperPod: "true"}))
+			assert.Empty(t, pods.Items)
+		})
+	}
+}
+
+func TestHelperPodDefaults(t *testing.T) {
+	pod := helperPod(&v1alpha1.ExecutionEnvironment{Image: "busybox", NodeSelector: map[string]string{"pool": "debug"}},
+		helperTargetPod("web-1"), []string{"true"}, 500*time.Millisecond)
+
+	assert.Empty(t, pod.Spec.NodeName, "helper pods are scheduled by default")
+	assert.Equal(t, map[string]string{"pool": "debug"}, pod.Spec.NodeSelector)
+	assert.False(t, *pod.Spec.AutomountServiceAccountToken, "no token is mounted without a service account")
+	assert.Equal(t, int64(1), *pod.Spec.ActiveDeadlineSeconds)
+}
+
+func TestExecExecutorHelperPods(t *testing.T) {
+	c, created := helperPodClient(t, corev1.PodSucceeded, helperTargetPod("web-1"), helperTargetPod("web-2"))
+	executor := NewExecExecutor(c)
+	action := &v1alpha1.HealingActionTemplate{
+		Type:       "exec",
+		ExecAction: &v1alpha1.ExecAction{Command: []string{"jcmd", "1", "Thread.print"}},
+		Execution:  &v1alpha1.ExecutionEnvironment{Image: "jdk:21"},
+	}
+	deployment := createUnstructuredDeployment("test-deployment", "default")
+
+	assert.ErrorContains(t, executor.Validate(context.Background(), deployment, action), "require a helper pod runner")
+
+	runner := NewHelperPodRunner(c, kubefake.NewSimpleClientset())
+	runner.pollInterval = 10 * time.Millisecond
+	executor.SetHelperPodRunner(runner)
+
+	dryRun, err := executor.DryRun(context.Background(), deployment, action)
+	require.NoError(t, err)
+	assert.Contains(t, dryRun.Message, "helper pods of image jdk:21 for default/web-1/helper")
+	assert.Empty(t, *created)
+
+	// One helper pod runs per target pod, without a pod executor
+	result, err := executor.Execute(context.Background(), deployment, action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Len(t, *created, 2)
+	require.Len(t, result.Diagnostics, 2)
+	assert.Equal(t, "default/web-1/helper", result.Diagnostics[0].Source)
+	assert.Equal(t, "fake logs", result.Diagnostics[0].Output)
+}
+
+func TestHookRunnerHelperPods(t *testing.T) {
+	target := helperTargetPod("web-1")
+	c, created := helperPodClient(t, corev1.PodSucceeded, target)
+	podExecutor := &mockPodExecutor{output: "in target"}
+	hooks := NewHookRunner(c, kubefake.NewSimpleClientset(), podExecutor)
+	env := &v1alpha1.ExecutionEnvironment{Image: "jdk:21"}
+	dump := []v1alpha1.PreActionHook{{Type: "exec", Command: []string{"jcmd", "1", "GC.heap_dump"}}}
+
+	captures := hooks.Run(context.Background(), target, dump, env)
+	require.Len(t, captures, 1)
+	assert.Equal(t, "helper pods are not configured", captures[0].Error)
+
+	runner := NewHelperPodRunner(c, kubefake.NewSimpleClientset())
+	runner.pollInterval = 10 * time.Millisecond
+	hooks.SetHelperPodRunner(runner)
+	captures = hooks.Run(context.Background(), target, dump, env)
+	require.Len(t, captures, 1)
+	assert.Empty(t, captures[0].Error)
+	assert.Equal(t, "default/web-1/helper", captures[0].Source)
+	assert.Equal(t, "fake logs", captures[0].Output)
+	assert.Len(t, *created, 1)
+	assert.Empty(t, podExecutor.calls, "exec hooks run in the helper pod instead of the target")
+}
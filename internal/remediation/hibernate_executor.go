@@ -0,0 +1,84 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// HibernateExecutor scales workloads to zero. The previous replica count is
+// kept in the result changes, and the action controller restores it through
+// Rollback when the hibernation ends.
+type HibernateExecutor struct {
+	scale *ScaleExecutor
+}
+
+// NewHibernateExecutor creates a new hibernate executor
+func NewHibernateExecutor(client client.Client) *HibernateExecutor {
+	return &HibernateExecutor{
+		scale: NewScaleExecutor(client),
+	}
+}
+
+// toZero is the scale action hibernation performs
+var toZero = &v1alpha1.HealingActionTemplate{
+	Type:        "scale",
+	ScaleAction: &v1alpha1.ScaleAction{Direction: "absolute", Replicas: 0},
+}
+
+// Execute scales the target to zero replicas
+func (h *HibernateExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	startTime := time.Now()
+	if err := h.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Validation failed: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	result, err := h.scale.Execute(ctx, target, toZero)
+	if err != nil {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Hibernated %s/%s: %s", target.GetNamespace(), target.GetName(), result.Message)
+	return result, nil
+}
+
+// Validate checks that the target can be scaled and is not already at zero
+func (h *HibernateExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	config := action.HibernateAction
+	if config == nil {
+		return fmt.Errorf("hibernate action configuration is missing")
+	}
+	if config.Duration.Duration <= 0 && config.ResumeWhen == nil {
+		return fmt.Errorf("hibernate action needs a duration or resumeWhen condition")
+	}
+
+	scale, err := h.scale.getScale(ctx, target)
+	if err != nil {
+		return fmt.Errorf("scale not supported for %s: %w", resourceKind(target), err)
+	}
+	if scale.Spec.Replicas == 0 {
+		return fmt.Errorf("%s is already scaled to zero", resourceKind(target))
+	}
+	return nil
+}
+
+// DryRun simulates scaling the target to zero
+func (h *HibernateExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	if err := h.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Validation failed: %v", err),
+		}, err
+	}
+	return h.scale.DryRun(ctx, target, toZero)
+}
@@ -0,0 +1,114 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestHibernateExecutor_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int64
+		config   *v1alpha1.HibernateAction
+		wantErr  string
+	}{
+		{
+			name:     "duration",
+			replicas: 3,
+			config:   &v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}},
+		},
+		{
+			name:     "resume condition",
+			replicas: 3,
+			config: &v1alpha1.HibernateAction{ResumeWhen: &v1alpha1.ResumeCondition{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "db", Type: "Available",
+			}},
+		},
+		{
+			name:     "missing configuration",
+			replicas: 3,
+			wantErr:  "configuration is missing",
+		},
+		{
+			name:     "never resumes",
+			replicas: 3,
+			config:   &v1alpha1.HibernateAction{},
+			wantErr:  "needs a duration or resumeWhen",
+		},
+		{
+			name:     "already at zero",
+			replicas: 0,
+			config:   &v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}},
+			wantErr:  "already scaled to zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollout := createUnstructuredRollout("checkout", "default", tt.replicas)
+			executor := NewHibernateExecutor(newScaleClient(t, rollout))
+
+			err := executor.Validate(context.Background(), rollout, &v1alpha1.HealingActionTemplate{
+				Type:            "hibernate",
+				HibernateAction: tt.config,
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHibernateExecutor_ExecuteAndResume(t *testing.T) {
+	rollout := createUnstructuredRollout("checkout", "default", 4)
+	c := newScaleClient(t, rollout)
+	template := v1alpha1.HealingActionTemplate{
+		Name:            "hibernate-checkout",
+		Type:            "hibernate",
+		HibernateAction: &v1alpha1.HibernateAction{Duration: metav1.Duration{Duration: time.Hour}},
+	}
+
+	replicas := func() int64 {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(rolloutGVK)
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(rollout), current))
+		value, _, _ := unstructured.NestedInt64(current.Object, "spec", "replicas")
+		return value
+	}
+
+	result, err := NewHibernateExecutor(c).Execute(context.Background(), rollout, &template)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "Hibernated default/checkout")
+	assert.Equal(t, int64(0), replicas())
+
+	// The engine restores the recorded replica count
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "hibernate-checkout", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: rolloutGVK.GroupVersion().String(), Kind: "Rollout", Name: "checkout", Namespace: "default"},
+			Action:         template,
+		},
+		Status: v1alpha1.HealingActionStatus{
+			Result: &v1alpha1.ActionResult{Success: true, Changes: result.Changes},
+		},
+	}
+	previous, ok := PreviousReplicas(action)
+	require.True(t, ok)
+	assert.Equal(t, int32(4), previous)
+
+	require.NoError(t, NewEngine(c, nil).Rollback(context.Background(), action))
+	assert.Equal(t, int64(4), replicas())
+}
@@ -0,0 +1,300 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// maxCaptureBytes bounds each capture so the action status stays small
+	maxCaptureBytes = 16 * 1024
+
+	// maxActionCaptureBytes bounds the captures of all hooks of an action,
+	// which run once per target pod
+	maxActionCaptureBytes = 64 * 1024
+
+	// defaultHookTailLines is used when a logs hook does not set TailLines
+	defaultHookTailLines = 100
+
+	// defaultHookTimeout is used when a hook does not set Timeout
+	defaultHookTimeout = 30 * time.Second
+)
+
+// PodExecutor runs a command inside a container and returns its output
+type PodExecutor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error)
+}
+
+// HookRunner executes pre-action hooks against the target's pods
+type HookRunner struct {
+	client    client.Client
+	clientset kubernetes.Interface
+	executor  PodExecutor
+	helpers   *HelperPodRunner
+}
+
+// NewHookRunner creates a new hook runner. The clientset is used for log
+// capture and executor for exec hooks; either may be nil to disable them.
+func NewHookRunner(client client.Client, clientset kubernetes.Interface, executor PodExecutor) *HookRunner {
+	return &HookRunner{
+		client:    client,
+		clientset: clientset,
+		executor:  executor,
+	}
+}
+
+// SetHelperPodRunner sets the runner of exec hooks of actions with an
+// execution environment
+func (h *HookRunner) SetHelperPodRunner(helpers *HelperPodRunner) {
+	h.helpers = helpers
+}
+
+// Run executes the hooks and returns one capture per hook and pod. Exec
+// hooks run in helper pods when env is set. Failures are recorded in the
+// capture and never abort the action. Once the captures reach
+// maxActionCaptureBytes, the remaining hooks are skipped.
+func (h *HookRunner) Run(ctx context.Context, target client.Object, hooks []v1alpha1.PreActionHook, env *v1alpha1.ExecutionEnvironment) []v1alpha1.DiagnosticCapture {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	log := log.FromContext(ctx)
+
+	podList := &corev1.PodList{}
+	if err := h.client.List(ctx, podList, client.InNamespace(target.GetNamespace())); err != nil {
+		return []v1alpha1.DiagnosticCapture{{
+			Type:       "pods",
+			Error:      fmt.Sprintf("failed to list pods: %v", err),
+			CapturedAt: metav1.Now(),
+		}}
+	}
+
+	pods, err := targetPods(target, podList.Items)
+	if err != nil || len(pods) == 0 {
+		// Fall back to capturing events for the target itself
+		pods = nil
+	}
+
+	var captures []v1alpha1.DiagnosticCapture
+	budget := maxActionCaptureBytes
+	add := func(capture v1alpha1.DiagnosticCapture) {
+		if len(capture.Output) > budget {
+			capture.Output = capture.Output[len(capture.Output)-budget:]
+			capture.Truncated = true
+		}
+		budget -= len(capture.Output)
+		captures = append(captures, capture)
+	}
+	for _, hook := range hooks {
+		timeout := hook.Timeout.Duration
+		if timeout <= 0 {
+			timeout = defaultHookTimeout
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+
+		switch {
+		case budget <= 0:
+			add(v1alpha1.DiagnosticCapture{
+				Type:       hook.Type,
+				Source:     fmt.Sprintf("%s/%s", target.GetNamespace(), target.GetName()),
+				Error:      fmt.Sprintf("skipped: captures of the action reached %d bytes", maxActionCaptureBytes),
+				CapturedAt: metav1.Now(),
+			})
+		case hook.Type == "events":
+			add(h.captureEvents(hookCtx, target))
+		case hook.Type == "logs" || hook.Type == "exec":
+			if len(pods) == 0 {
+				add(v1alpha1.DiagnosticCapture{
+					Type:       hook.Type,
+					Source:     fmt.Sprintf("%s/%s", target.GetNamespace(), target.GetName()),
+					Error:      "no pods found for target",
+					CapturedAt: metav1.Now(),
+				})
+				break
+			}
+			for _, pod := range pods {
+				if budget <= 0 {
+					break
+				}
+				switch {
+				case hook.Type == "logs":
+					add(h.captureLogs(hookCtx, pod, hook))
+				case env != nil:
+					add(h.captureHelperExec(hookCtx, pod, hook, env, timeout))
+				default:
+					add(h.captureExec(hookCtx, pod, hook))
+				}
+			}
+		default:
+			add(v1alpha1.DiagnosticCapture{
+				Type:       hook.Type,
+				Error:      fmt.Sprintf("unsupported hook type: %s", hook.Type),
+				CapturedAt: metav1.Now(),
+			})
+		}
+		cancel()
+	}
+
+	log.V(1).Info("Pre-action hooks completed", "captures", len(captures))
+	return captures
+}
+
+// captureLogs reads the last lines of a container's logs
+func (h *HookRunner) captureLogs(ctx context.Context, pod *corev1.Pod, hook v1alpha1.PreActionHook) v1alpha1.DiagnosticCapture {
+	container := hookContainer(pod, hook.Container)
+	capture := v1alpha1.DiagnosticCapture{
+		Type:       "logs",
+		Source:     fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container),
+		CapturedAt: metav1.Now(),
+	}
+
+	if h.clientset == nil {
+		capture.Error = "log capture is not configured"
+		return capture
+	}
+
+	tailLines := hook.TailLines
+	if tailLines <= 0 {
+		tailLines = defaultHookTailLines
+	}
+	limitBytes := int64(maxCaptureBytes + 1)
+
+	stream, err := h.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}).Stream(ctx)
+	if err != nil {
+		capture.Error = fmt.Sprintf("failed to stream logs: %v", err)
+		return capture
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(io.LimitReader(stream, limitBytes))
+	if err != nil {
+		capture.Error = fmt.Sprintf("failed to read logs: %v", err)
+	}
+	capture.Output, capture.Truncated = truncateCapture(string(data))
+	return capture
+}
+
+// captureExec runs the hook command in the container
+func (h *HookRunner) captureExec(ctx context.Context, pod *corev1.Pod, hook v1alpha1.PreActionHook) v1alpha1.DiagnosticCapture {
+	container := hookContainer(pod, hook.Container)
+	capture := v1alpha1.DiagnosticCapture{
+		Type:       "exec",
+		Source:     fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container),
+		CapturedAt: metav1.Now(),
+	}
+
+	if len(hook.Command) == 0 {
+		capture.Error = "exec hook requires a command"
+		return capture
+	}
+	if h.executor == nil {
+		capture.Error = "exec capture is not configured"
+		return capture
+	}
+
+	output, err := h.executor.Exec(ctx, pod.Namespace, pod.Name, container, hook.Command)
+	if err != nil {
+		capture.Error = fmt.Sprintf("exec failed: %v", err)
+	}
+	capture.Output, capture.Truncated = truncateCapture(output)
+	return capture
+}
+
+// captureHelperExec runs the hook command in a helper pod next to the pod
+func (h *HookRunner) captureHelperExec(ctx context.Context, pod *corev1.Pod, hook v1alpha1.PreActionHook, env *v1alpha1.ExecutionEnvironment, timeout time.Duration) v1alpha1.DiagnosticCapture {
+	capture := v1alpha1.DiagnosticCapture{
+		Type:       "exec",
+		Source:     fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, helperContainerName),
+		CapturedAt: metav1.Now(),
+	}
+
+	if len(hook.Command) == 0 {
+		capture.Error = "exec hook requires a command"
+		return capture
+	}
+	if h.helpers == nil {
+		capture.Error = "helper pods are not configured"
+		return capture
+	}
+
+	output, err := h.helpers.Run(ctx, env, pod, hook.Command, timeout)
+	if err != nil {
+		capture.Error = fmt.Sprintf("exec failed: %v", err)
+	}
+	capture.Output, capture.Truncated = truncateCapture(output)
+	return capture
+}
+
+// captureEvents snapshots the events recorded for the target
+func (h *HookRunner) captureEvents(ctx context.Context, target client.Object) v1alpha1.DiagnosticCapture {
+	capture := v1alpha1.DiagnosticCapture{
+		Type:       "events",
+		Source:     fmt.Sprintf("%s/%s", target.GetNamespace(), target.GetName()),
+		CapturedAt: metav1.Now(),
+	}
+
+	eventList := &corev1.EventList{}
+	if err := h.client.List(ctx, eventList, client.InNamespace(target.GetNamespace())); err != nil {
+		capture.Error = fmt.Sprintf("failed to list events: %v", err)
+		return capture
+	}
+
+	kind := target.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvk, err := apiutil.GVKForObject(target, h.client.Scheme()); err == nil {
+			kind = gvk.Kind
+		}
+	}
+
+	var events []corev1.Event
+	for _, event := range eventList.Items {
+		// A Deployment and its Service often share a name
+		if event.InvolvedObject.Name == target.GetName() && (kind == "" || event.InvolvedObject.Kind == kind) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(&events[j].LastTimestamp)
+	})
+
+	var sb strings.Builder
+	for _, event := range events {
+		fmt.Fprintf(&sb, "%s %s %s: %s (x%d)\n",
+			event.LastTimestamp.Format(time.RFC3339), event.Type, event.Reason, event.Message, event.Count)
+	}
+	capture.Output, capture.Truncated = truncateCapture(sb.String())
+	return capture
+}
+
+// hookContainer returns the requested container or the pod's first container
+func hookContainer(pod *corev1.Pod, container string) string {
+	if container != "" || len(pod.Spec.Containers) == 0 {
+		return container
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+// truncateCapture keeps the tail of the output, which holds the most recent data
+func truncateCapture(output string) (string, bool) {
+	if len(output) <= maxCaptureBytes {
+		return output, false
+	}
+	return output[len(output)-maxCaptureBytes:], true
+}
@@ -0,0 +1,167 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+type mockPodExecutor struct {
+	output string
+	err    error
+	calls  [][]string
+}
+
+func (m *mockPodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+	m.calls = append(m.calls, command)
+	return m.output, m.err
+}
+
+func TestHookRunner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.oom", Namespace: "apps"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "apps"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "OOMKilled",
+		Message:        "container app was OOM killed",
+		Count:          3,
+	}
+	// Events of other kinds sharing the name are left out
+	serviceEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.sync", Namespace: "apps"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Service", Name: "web-1", Namespace: "apps"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "SyncLoadBalancerFailed",
+	}
+
+	tests := []struct {
+		name          string
+		hooks         []v1alpha1.PreActionHook
+		executor      *mockPodExecutor
+		expectedType  string
+		expectedError string
+		expectOutput  string
+	}{
+		{
+			name:         "logs",
+			hooks:        []v1alpha1.PreActionHook{{Type: "logs", TailLines: 10}},
+			expectedType: "logs",
+			expectOutput: "fake logs",
+		},
+		{
+			name:         "events",
+			hooks:        []v1alpha1.PreActionHook{{Type: "events"}},
+			expectedType: "events",
+			expectOutput: "OOMKilled",
+		},
+		{
+			name:         "exec",
+			hooks:        []v1alpha1.PreActionHook{{Type: "exec", Command: []string{"jcmd", "1", "Thread.print"}}},
+			executor:     &mockPodExecutor{output: "thread dump"},
+			expectedType: "exec",
+			expectOutput: "thread dump",
+		},
+		{
+			name:          "exec failure is recorded",
+			hooks:         []v1alpha1.PreActionHook{{Type: "exec", Command: []string{"false"}}},
+			executor:      &mockPodExecutor{err: errors.New("exit code 1")},
+			expectedType:  "exec",
+			expectedError: "exit code 1",
+		},
+		{
+			name:          "exec without executor",
+			hooks:         []v1alpha1.PreActionHook{{Type: "exec", Command: []string{"true"}}},
+			expectedType:  "exec",
+			expectedError: "not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(pod.DeepCopy(), event.DeepCopy(), serviceEvent.DeepCopy()).
+				Build()
+
+			var executor PodExecutor
+			if tt.executor != nil {
+				executor = tt.executor
+			}
+			runner := NewHookRunner(fakeClient, kubefake.NewSimpleClientset(pod.DeepCopy()), executor)
+
+			captures := runner.Run(context.Background(), pod, tt.hooks, nil)
+			require.Len(t, captures, 1)
+
+			capture := captures[0]
+			assert.Equal(t, tt.expectedType, capture.Type)
+			if tt.expectedError != "" {
+				assert.Contains(t, capture.Error, tt.expectedError)
+			} else {
+				assert.Empty(t, capture.Error)
+				assert.Contains(t, capture.Output, tt.expectOutput)
+				assert.NotContains(t, capture.Output, "SyncLoadBalancerFailed")
+			}
+		})
+	}
+}
+
+func TestHookRunner_ActionCaptureBudget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod.DeepCopy()).Build()
+	executor := &mockPodExecutor{output: strings.Repeat("a", maxCaptureBytes*3/4)}
+	runner := NewHookRunner(fakeClient, nil, executor)
+
+	hooks := make([]v1alpha1.PreActionHook, 8)
+	for i := range hooks {
+		hooks[i] = v1alpha1.PreActionHook{Type: "exec", Command: []string{"dump"}}
+	}
+	captures := runner.Run(context.Background(), pod, hooks, nil)
+	require.Len(t, captures, 8)
+
+	total := 0
+	for _, capture := range captures {
+		total += len(capture.Output)
+	}
+	assert.Equal(t, maxActionCaptureBytes, total)
+	assert.True(t, captures[5].Truncated)
+	assert.Contains(t, captures[6].Error, "skipped")
+	assert.Len(t, executor.calls, 6)
+}
+
+func TestTruncateCapture(t *testing.T) {
+	output, truncated := truncateCapture("short")
+	assert.Equal(t, "short", output)
+	assert.False(t, truncated)
+
+	long := strings.Repeat("a", maxCaptureBytes) + "tail"
+	output, truncated = truncateCapture(long)
+	assert.True(t, truncated)
+	assert.Len(t, output, maxCaptureBytes)
+	assert.True(t, strings.HasSuffix(output, "tail"))
+}
@@ -0,0 +1,286 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// LabelOwnerAction is the UID of the healing action that created an
+	// auxiliary resource, such as a helper pod
+	LabelOwnerAction = "kubeskippy.io/owner-action"
+
+	// AnnotationTaintOwners maps the keys of the taints actions added to a
+	// node to the UIDs of the actions, as a JSON object
+	AnnotationTaintOwners = "kubeskippy.io/taint-owners"
+
+	// defaultJanitorInterval is used when the janitor's interval is unset
+	defaultJanitorInterval = 10 * time.Minute
+)
+
+// ownerActionKey is the context key of the action an execution runs for
+type ownerActionKey struct{}
+
+// withOwnerAction returns a context recording that the resources created
+// with it belong to the action
+func withOwnerAction(ctx context.Context, action *v1alpha1.HealingAction) context.Context {
+	if action.UID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ownerActionKey{}, action.UID)
+}
+
+// ownerAction returns the UID of the action the context's execution runs
+// for, or "" outside of executions
+func ownerAction(ctx context.Context) types.UID {
+	uid, _ := ctx.Value(ownerActionKey{}).(types.UID)
+	return uid
+}
+
+// JanitorResult counts the orphaned resources a sweep cleaned up
+type JanitorResult struct {
+	// HelperPods deleted
+	HelperPods int
+	// Taints removed from nodes
+	Taints int
+	// ReleasedTaints whose action kept them for an operator, so they are no
+	// longer tracked
+	ReleasedTaints int
+}
+
+// Janitor cleans up the auxiliary resources executors create when the
+// action owning them is gone or finished, which happens when the operator
+// crashes or an action is removed without its finalizer. Helper pods carry
+// their action in LabelOwnerAction, and nodes the actions of their taints
+// in AnnotationTaintOwners.
+type Janitor struct {
+	client   client.Client
+	interval time.Duration
+
+	// gracePeriod is how long helper pods without an owner label are left
+	// alone, for runs started before they were labelled
+	gracePeriod time.Duration
+
+	now func() time.Time
+}
+
+// NewJanitor creates a janitor sweeping every interval
+func NewJanitor(c client.Client, interval, gracePeriod time.Duration) *Janitor {
+	if interval <= 0 {
+		interval = defaultJanitorInterval
+	}
+	return &Janitor{
+		client:      c,
+		interval:    interval,
+		gracePeriod: gracePeriod,
+		now:         time.Now,
+	}
+}
+
+// Start sweeps on the janitor's interval until ctx is done. It runs on the
+// leader only, as a manager runnable.
+func (j *Janitor) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("janitor")
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			result, err := j.Sweep(ctx)
+			if err != nil {
+				log.Error(err, "Failed to clean up orphaned resources")
+			}
+			if result.HelperPods > 0 || result.Taints > 0 || result.ReleasedTaints > 0 {
+				log.Info("Cleaned up orphaned resources", "helperPods", result.HelperPods,
+					"taints", result.Taints, "releasedTaints", result.ReleasedTaints)
+			}
+		}
+	}
+}
+
+// Sweep cleans up the orphaned resources once. It carries on past the
+// resources it fails to clean up and returns the first error.
+func (j *Janitor) Sweep(ctx context.Context) (JanitorResult, error) {
+	var result JanitorResult
+
+	actions := &v1alpha1.HealingActionList{}
+	if err := j.client.List(ctx, actions); err != nil {
+		return result, fmt.Errorf("failed to list healing actions: %w", err)
+	}
+	owners := make(map[types.UID]*v1alpha1.HealingAction, len(actions.Items))
+	for i := range actions.Items {
+		owners[actions.Items[i].UID] = &actions.Items[i]
+	}
+
+	podsErr := j.sweepHelperPods(ctx, owners, &result)
+	taintsErr := j.sweepTaints(ctx, owners, &result)
+	if podsErr != nil {
+		return result, podsErr
+	}
+	return result, taintsErr
+}
+
+// sweepHelperPods deletes the helper pods of actions that are gone or
+// finished
+func (j *Janitor) sweepHelperPods(ctx context.Context, owners map[types.UID]*v1alpha1.HealingAction, result *JanitorResult) error {
+	pods := &corev1.PodList{}
+	if err := j.client.List(ctx, pods, client.MatchingLabels{LabelHelperPod: "true"}); err != nil {
+		return fmt.Errorf("failed to list helper pods: %w", err)
+	}
+
+	var firstErr error
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if uid, ok := pod.Labels[LabelOwnerAction]; ok {
+			if owner := owners[types.UID(uid)]; owner != nil && !owner.IsComplete() {
+				continue
+			}
+		} else if j.now().Sub(pod.CreationTimestamp.Time) < j.gracePeriod {
+			continue
+		}
+
+		if err := j.client.Delete(ctx, pod, client.PropagationPolicy("Background")); err != nil && !apierrors.IsNotFound(err) {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete helper pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+			continue
+		}
+		log.FromContext(ctx).Info("Deleted orphaned helper pod", "pod", pod.Name, "namespace", pod.Namespace)
+		result.HelperPods++
+	}
+	return firstErr
+}
+
+// sweepTaints removes the taints of actions that are gone, or finished
+// without a taint window to resolve, from the nodes. Taints the action kept
+// for an operator, or left on a drained node, stop being tracked.
+func (j *Janitor) sweepTaints(ctx context.Context, owners map[types.UID]*v1alpha1.HealingAction, result *JanitorResult) error {
+	nodes := &corev1.NodeList{}
+	if err := j.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var firstErr error
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if _, ok := node.Annotations[AnnotationTaintOwners]; !ok {
+			continue
+		}
+		removed, released, err := j.sweepNode(ctx, node, owners)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		result.Taints += removed
+		result.ReleasedTaints += released
+	}
+	return firstErr
+}
+
+// sweepNode updates one node's taints and their owners
+func (j *Janitor) sweepNode(ctx context.Context, node *corev1.Node, owners map[types.UID]*v1alpha1.HealingAction) (int, int, error) {
+	removed, released := 0, 0
+	_, err := retryOnConflict(ctx, j.client, node, func() error {
+		removed, released = 0, 0
+		taintOwners := nodeTaintOwners(node)
+		orphaned := make(map[string]bool)
+		for key, uid := range taintOwners {
+			switch taintOwnership(owners[uid]) {
+			case taintOrphaned:
+				orphaned[key] = true
+				delete(taintOwners, key)
+				if hasTaint(node, key) {
+					removed++
+				}
+			case taintReleased:
+				delete(taintOwners, key)
+				released++
+			}
+		}
+		if len(orphaned) == 0 && released == 0 {
+			return nil
+		}
+
+		taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+		for _, taint := range node.Spec.Taints {
+			if !orphaned[taint.Key] {
+				taints = append(taints, taint)
+			}
+		}
+		node.Spec.Taints = taints
+		setNodeTaintOwners(node, taintOwners)
+		return j.client.Update(ctx, node)
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to clean up taints of node %s: %w", node.Name, err)
+	}
+	if removed > 0 {
+		log.FromContext(ctx).Info("Removed orphaned taints", "node", node.Name, "taints", removed)
+	}
+	return removed, released, nil
+}
+
+// Ownership states of a tracked taint
+const (
+	taintOwned = iota
+	taintOrphaned
+	taintReleased
+)
+
+// taintOwnership tells what becomes of a taint tracked for the action
+func taintOwnership(owner *v1alpha1.HealingAction) int {
+	switch {
+	case owner == nil:
+		return taintOrphaned
+	case !owner.IsComplete():
+		return taintOwned
+	case owner.Status.NodeTaint == nil || owner.Status.NodeTaint.Outcome == v1alpha1.NodeTaintUntainted:
+		return taintOrphaned
+	case owner.Status.NodeTaint.ResolvedAt == nil:
+		// Waiting for the taint window
+		return taintOwned
+	default:
+		return taintReleased
+	}
+}
+
+// nodeTaintOwners returns the node's taint owners, or an empty map if it
+// has none or they cannot be read
+func nodeTaintOwners(node *corev1.Node) map[string]types.UID {
+	owners := make(map[string]types.UID)
+	if value, ok := node.Annotations[AnnotationTaintOwners]; ok {
+		_ = json.Unmarshal([]byte(value), &owners)
+	}
+	return owners
+}
+
+// setNodeTaintOwners records the node's taint owners, removing the
+// annotation when there are none
+func setNodeTaintOwners(node *corev1.Node, owners map[string]types.UID) {
+	if len(owners) == 0 {
+		delete(node.Annotations, AnnotationTaintOwners)
+		return
+	}
+	value, err := json.Marshal(owners)
+	if err != nil {
+		return
+	}
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[AnnotationTaintOwners] = string(value)
+}
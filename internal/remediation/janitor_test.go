@@ -0,0 +1,119 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestJanitor_Sweep(t *testing.T) {
+	scheme := taintScheme(t)
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	now := time.Now()
+
+	action := func(name, phase string, nodeTaint *v1alpha1.NodeTaintStatus) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps", UID: types.UID(name + "-uid")},
+			Status:     v1alpha1.HealingActionStatus{Phase: phase, NodeTaint: nodeTaint},
+		}
+	}
+	resolved := metav1.NewTime(now)
+	running := action("running", v1alpha1.HealingActionPhaseInProgress, nil)
+	failed := action("failed", v1alpha1.HealingActionPhaseFailed, nil)
+	waiting := action("waiting", v1alpha1.HealingActionPhaseSucceeded, &v1alpha1.NodeTaintStatus{Key: "waiting"})
+	kept := action("kept", v1alpha1.HealingActionPhaseSucceeded, &v1alpha1.NodeTaintStatus{Key: "kept", Outcome: v1alpha1.NodeTaintKept, ResolvedAt: &resolved})
+
+	helper := func(name string, owner types.UID, age time.Duration) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "apps", CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			Labels: map[string]string{LabelHelperPod: "true", LabelHelperTarget: "web-1"},
+		}}
+		if owner != "" {
+			pod.Labels[LabelOwnerAction] = string(owner)
+		}
+		return pod
+	}
+	pods := []*corev1.Pod{
+		helper("of-running", running.UID, 2*time.Hour),
+		helper("of-failed", failed.UID, time.Minute),
+		helper("of-deleted", "deleted-uid", time.Minute),
+		helper("unowned-recent", "", time.Minute),
+		helper("unowned-old", "", 2*time.Hour),
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"}},
+	}
+
+	taint := func(key string) corev1.Taint {
+		return corev1.Taint{Key: key, Effect: corev1.TaintEffectPreferNoSchedule}
+	}
+	node := suspectNode(taint("dedicated"), taint("running"), taint("deleted"), taint("failed"), taint("waiting"), taint("kept"))
+	setNodeTaintOwners(node, map[string]types.UID{
+		"running": running.UID,
+		"deleted": "deleted-uid",
+		"failed":  failed.UID,
+		"waiting": waiting.UID,
+		"kept":    kept.UID,
+		// Removed by hand
+		"gone": "deleted-uid",
+	})
+
+	objects := []client.Object{running, failed, waiting, kept, node}
+	for _, pod := range pods {
+		objects = append(objects, pod)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	janitor := NewJanitor(c, time.Minute, time.Hour)
+	janitor.now = func() time.Time { return now }
+
+	result, err := janitor.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, JanitorResult{HelperPods: 3, Taints: 2, ReleasedTaints: 1}, result)
+
+	for _, name := range []string{"of-failed", "of-deleted", "unowned-old"} {
+		err := c.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: name}, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err), name)
+	}
+	for _, name := range []string{"of-running", "unowned-recent", "web-1"} {
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: name}, &corev1.Pod{}), name)
+	}
+
+	got := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "worker-1"}, got))
+	assert.Equal(t, []corev1.Taint{taint("dedicated"), taint("running"), taint("waiting"), taint("kept")}, got.Spec.Taints)
+	assert.Equal(t, map[string]types.UID{"running": running.UID, "waiting": waiting.UID}, nodeTaintOwners(got),
+		"the kept taint is left to an operator")
+
+	// Nothing left to clean up
+	result, err = janitor.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result)
+}
+
+func TestTaintOwnership(t *testing.T) {
+	scheme := taintScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(suspectNode()).Build()
+	action := &v1alpha1.HealingAction{ObjectMeta: metav1.ObjectMeta{Name: "worker-1-taint", UID: "taint-uid"}}
+	target := suspectNode()
+
+	_, err := NewTaintExecutor(c).Execute(withOwnerAction(context.Background(), action), target,
+		&v1alpha1.HealingActionTemplate{Type: "taint", TaintAction: &v1alpha1.TaintAction{}})
+	require.NoError(t, err)
+	node := &corev1.Node{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "worker-1"}, node))
+	assert.Equal(t, map[string]types.UID{DefaultTaintKey: "taint-uid"}, nodeTaintOwners(node))
+
+	require.NoError(t, removeTaint(context.Background(), c, "worker-1", DefaultTaintKey))
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "worker-1"}, node))
+	assert.Empty(t, node.Spec.Taints)
+	assert.NotContains(t, node.Annotations, AnnotationTaintOwners)
+}
@@ -0,0 +1,210 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// Service meshes recognised by mesh-aware restarts
+const (
+	MeshIstio   = "istio"
+	MeshLinkerd = "linkerd"
+)
+
+const (
+	// DefaultMeshReadinessGate is the readiness gate used to drain meshed pods
+	DefaultMeshReadinessGate = "kubeskippy.io/serving"
+
+	// defaultMeshDrainSeconds is the drain period when none is configured
+	defaultMeshDrainSeconds = 10
+
+	// ReasonMeshDrain is set on the readiness gate condition of drained pods
+	ReasonMeshDrain = "KubeSkippyDrain"
+
+	// istioIncludeInboundPorts lists the inbound ports the Istio sidecar
+	// intercepts; an empty value disables inbound interception
+	istioIncludeInboundPorts = "traffic.sidecar.istio.io/includeInboundPorts"
+)
+
+// meshSidecars maps sidecar container names to their mesh
+var meshSidecars = map[string]string{
+	"istio-proxy":   MeshIstio,
+	"linkerd-proxy": MeshLinkerd,
+}
+
+// podMesh returns the mesh whose sidecar runs in the pod, or "" when the
+// pod is not meshed. Native sidecars run as init containers.
+func podMesh(pod *corev1.Pod) (mesh, container string) {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, c := range containers {
+			if mesh, ok := meshSidecars[c.Name]; ok {
+				return mesh, c.Name
+			}
+		}
+	}
+	return "", ""
+}
+
+// interceptsInbound reports whether the mesh routes inbound traffic through
+// the pod's sidecar, which is what makes draining necessary
+func interceptsInbound(pod *corev1.Pod, mesh string) bool {
+	if mesh != MeshIstio {
+		return true
+	}
+	ports, ok := pod.Annotations[istioIncludeInboundPorts]
+	return !ok || ports != ""
+}
+
+// sidecarReady reports whether the named sidecar container of the pod is ready
+func sidecarReady(pod *corev1.Pod, container string) bool {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses} {
+		for _, status := range statuses {
+			if status.Name == container {
+				return status.Ready
+			}
+		}
+	}
+	return false
+}
+
+// prepareMeshRestart gets a meshed pod ready for deletion: the sidecars of
+// the pods sharing its controller must be ready to take over its traffic,
+// then the pod is drained through its readiness gate. Pods outside a mesh
+// are left alone.
+func (r *RestartExecutor) prepareMeshRestart(ctx context.Context, target client.Object, config *v1alpha1.MeshRestart) ([]v1alpha1.ResourceChange, error) {
+	log := log.FromContext(ctx)
+
+	pod := &corev1.Pod{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: target.GetNamespace(), Name: target.GetName()}, pod); err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	mesh, sidecar := podMesh(pod)
+	if mesh == "" {
+		return nil, nil
+	}
+	if err := r.checkPeerSidecars(ctx, pod, sidecar); err != nil {
+		return nil, err
+	}
+	if !interceptsInbound(pod, mesh) {
+		log.V(1).Info("Sidecar does not intercept inbound traffic, skipping drain", "pod", pod.Name, "mesh", mesh)
+		return nil, nil
+	}
+
+	gate := config.ReadinessGate
+	if gate == "" {
+		gate = DefaultMeshReadinessGate
+	}
+	if !hasReadinessGate(pod, gate) {
+		log.Info("Meshed pod has no drain readiness gate, deleting without draining", "pod", pod.Name, "mesh", mesh, "readinessGate", gate)
+		return nil, nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	setPodCondition(pod, corev1.PodConditionType(gate), corev1.ConditionFalse, ReasonMeshDrain)
+	if err := r.client.Status().Patch(ctx, pod, patch); err != nil {
+		return nil, fmt.Errorf("failed to drain pod: %w", err)
+	}
+	changes := []v1alpha1.ResourceChange{{
+		ResourceRef: fmt.Sprintf("Pod/%s/%s", pod.Namespace, pod.Name),
+		ChangeType:  "update",
+		Field:       fmt.Sprintf("status.conditions[%s]", gate),
+		OldValue:    string(corev1.ConditionTrue),
+		NewValue:    string(corev1.ConditionFalse),
+		Timestamp:   &metav1.Time{Time: time.Now()},
+	}}
+
+	drain := time.Duration(config.DrainSeconds) * time.Second
+	if config.DrainSeconds == 0 {
+		drain = defaultMeshDrainSeconds * time.Second
+	}
+	log.Info("Draining meshed pod before restart", "pod", pod.Name, "mesh", mesh, "drain", drain)
+	if err := r.sleep(ctx, drain); err != nil {
+		return changes, fmt.Errorf("interrupted while draining pod: %w", err)
+	}
+	return changes, nil
+}
+
+// checkPeerSidecars fails when a pod sharing the controller of pod has a
+// sidecar that is not ready, so restarts don't remove the last serving pods
+func (r *RestartExecutor) checkPeerSidecars(ctx context.Context, pod *corev1.Pod, sidecar string) error {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods, client.InNamespace(pod.Namespace)); err != nil {
+		return fmt.Errorf("failed to list peer pods: %w", err)
+	}
+	for i := range pods.Items {
+		peer := &pods.Items[i]
+		if peer.UID == pod.UID || peer.DeletionTimestamp != nil {
+			continue
+		}
+		if peerOwner := metav1.GetControllerOf(peer); peerOwner == nil || peerOwner.UID != owner.UID {
+			continue
+		}
+		if !sidecarReady(peer, sidecar) {
+			return fmt.Errorf("waiting for sidecar %s of peer pod %s to become ready", sidecar, peer.Name)
+		}
+	}
+	return nil
+}
+
+// hasReadinessGate reports whether the pod declares the readiness gate
+func hasReadinessGate(pod *corev1.Pod, gate string) bool {
+	for _, g := range pod.Spec.ReadinessGates {
+		if string(g.ConditionType) == gate {
+			return true
+		}
+	}
+	return false
+}
+
+// setPodCondition sets or adds a pod condition
+func setPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType, status corev1.ConditionStatus, reason string) {
+	now := metav1.Now()
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			if pod.Status.Conditions[i].Status != status {
+				pod.Status.Conditions[i].LastTransitionTime = now
+			}
+			pod.Status.Conditions[i].Status = status
+			pod.Status.Conditions[i].Reason = reason
+			return
+		}
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: now,
+	})
+}
+
+// skipSleep returns at once; server-side dry runs have nothing to wait for
+func skipSleep(ctx context.Context, d time.Duration) error {
+	return ctx.Err()
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestMeshAwareRestart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	owner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", UID: "rs-uid", Controller: boolPtr(true)}
+	meshedPod := func(name string, sidecarReady bool) *corev1.Pod {
+		return &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				UID:             types.UID(name + "-uid"),
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}},
+				ReadinessGates: []corev1.PodReadinessGate{{
+					ConditionType: DefaultMeshReadinessGate,
+				}},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: DefaultMeshReadinessGate, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", Ready: true},
+					{Name: "istio-proxy", Ready: sidecarReady},
+				},
+			},
+		}
+	}
+	action := &v1alpha1.HealingActionTemplate{
+		Type: "restart",
+		RestartAction: &v1alpha1.RestartAction{
+			Strategy: "rolling",
+			Mesh:     &v1alpha1.MeshRestart{},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		target        *corev1.Pod
+		peer          *corev1.Pod
+		expectError   string
+		expectDrained bool
+	}{
+		{
+			name:          "drains meshed pod when peers are ready",
+			target:        meshedPod("web-1", true),
+			peer:          meshedPod("web-2", true),
+			expectDrained: true,
+		},
+		{
+			name:        "waits for peer sidecars",
+			target:      meshedPod("web-1", true),
+			peer:        meshedPod("web-2", false),
+			expectError: "waiting for sidecar istio-proxy of peer pod web-2",
+		},
+		{
+			name: "skips drain without inbound interception",
+			target: func() *corev1.Pod {
+				pod := meshedPod("web-1", true)
+				pod.Annotations = map[string]string{istioIncludeInboundPorts: ""}
+				return pod
+			}(),
+			peer: meshedPod("web-2", true),
+		},
+		{
+			name: "ignores pods outside the mesh",
+			target: func() *corev1.Pod {
+				pod := meshedPod("web-1", true)
+				pod.Spec.Containers = pod.Spec.Containers[:1]
+				return pod
+			}(),
+			peer: meshedPod("web-2", false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tt.target, tt.peer).
+				WithStatusSubresource(&corev1.Pod{}).
+				Build()
+
+			// Watch the drain happen before the pod is deleted
+			var drained []time.Duration
+			executor := NewRestartExecutor(fakeClient)
+			executor.sleep = func(ctx context.Context, d time.Duration) error {
+				pod := &corev1.Pod{}
+				require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(tt.target), pod))
+				assert.Equal(t, corev1.ConditionFalse, pod.Status.Conditions[0].Status)
+				assert.Equal(t, ReasonMeshDrain, pod.Status.Conditions[0].Reason)
+				drained = append(drained, d)
+				return nil
+			}
+
+			result, err := executor.Execute(context.Background(), tt.target.DeepCopy(), action)
+
+			err2 := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(tt.target), &corev1.Pod{})
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				assert.False(t, result.Success)
+				assert.NoError(t, err2, "pod must not be deleted")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, result.Success)
+			assert.True(t, errors.IsNotFound(err2), "pod is deleted")
+
+			if tt.expectDrained {
+				assert.Equal(t, []time.Duration{10 * time.Second}, drained)
+				require.Len(t, result.Changes, 2)
+				assert.Equal(t, "status.conditions[kubeskippy.io/serving]", result.Changes[0].Field)
+			} else {
+				assert.Empty(t, drained)
+				assert.Len(t, result.Changes, 1)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
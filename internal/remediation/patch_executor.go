@@ -57,23 +57,71 @@ func (p *PatchExecutor) Execute(ctx context.Context, target client.Object, actio
 		}, err
 	}
 
-	// Store original values for change tracking
-	originalValues := make(map[string]interface{})
+	// Apply the patches and write them, re-reading the target on conflicts
+	var changes []v1alpha1.ResourceChange
+	var setErr error
+	attempts, err := retryOnConflict(ctx, p.client, unstructuredTarget, func() error {
+		changes, setErr = p.applyPatches(ctx, unstructuredTarget, target, config)
+		if setErr != nil {
+			return setErr
+		}
+		return p.client.Update(ctx, unstructuredTarget)
+	})
+	if setErr != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   setErr.Error(),
+			Error:     setErr,
+			Changes:   changes,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, setErr
+	}
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to update resource: %v", err),
+			Error:     err,
+			Changes:   changes,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+			Metrics: map[string]string{
+				MetricUpdateAttempts: fmt.Sprintf("%d", attempts),
+			},
+		}, err
+	}
+
+	log.Info("Resource patched successfully",
+		"resource", fmt.Sprintf("%s/%s", target.GetNamespace(), target.GetName()),
+		"patches", len(config.Patches))
+
+	return &kubetypes.ActionResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully patched %s/%s with %d patches", target.GetNamespace(), target.GetName(), len(config.Patches)),
+		Changes:   changes,
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Metrics: map[string]string{
+			"patch_count":        fmt.Sprintf("%d", len(config.Patches)),
+			"patch_type":         string(config.Type),
+			MetricUpdateAttempts: fmt.Sprintf("%d", attempts),
+		},
+	}, nil
+}
+
+// applyPatches sets the patched fields on obj and returns the changes made
+func (p *PatchExecutor) applyPatches(ctx context.Context, obj *unstructured.Unstructured, target client.Object, config *v1alpha1.PatchAction) ([]v1alpha1.ResourceChange, error) {
+	log := log.FromContext(ctx)
 	changes := []v1alpha1.ResourceChange{}
 
-	// Apply patches
 	for _, patch := range config.Patches {
 		// Get original value
-		originalValue, exists, err := unstructured.NestedFieldCopy(unstructuredTarget.Object, patch.Path...)
+		originalValue, _, err := unstructured.NestedFieldCopy(obj.Object, patch.Path...)
 		if err != nil {
 			log.Error(err, "Failed to get original value", "path", patch.Path)
 			originalValue = nil
 		}
 
-		if exists {
-			originalValues[pathToString(patch.Path)] = originalValue
-		}
-
 		// Parse the new value
 		var newValue interface{}
 		if err := json.Unmarshal([]byte(patch.Value), &newValue); err != nil {
@@ -82,15 +130,8 @@ func (p *PatchExecutor) Execute(ctx context.Context, target client.Object, actio
 		}
 
 		// Apply the patch
-		if err := unstructured.SetNestedField(unstructuredTarget.Object, newValue, patch.Path...); err != nil {
-			return &kubetypes.ActionResult{
-				Success:   false,
-				Message:   fmt.Sprintf("Failed to set field %s: %v", pathToString(patch.Path), err),
-				Error:     err,
-				Changes:   changes,
-				StartTime: startTime,
-				EndTime:   time.Now(),
-			}, err
+		if err := unstructured.SetNestedField(obj.Object, newValue, patch.Path...); err != nil {
+			return changes, fmt.Errorf("failed to set field %s: %w", pathToString(patch.Path), err)
 		}
 
 		// Record the change
@@ -104,33 +145,7 @@ func (p *PatchExecutor) Execute(ctx context.Context, target client.Object, actio
 		})
 	}
 
-	// Update the resource
-	if err := p.client.Update(ctx, unstructuredTarget); err != nil {
-		return &kubetypes.ActionResult{
-			Success:   false,
-			Message:   fmt.Sprintf("Failed to update resource: %v", err),
-			Error:     err,
-			Changes:   changes,
-			StartTime: startTime,
-			EndTime:   time.Now(),
-		}, err
-	}
-
-	log.Info("Resource patched successfully",
-		"resource", fmt.Sprintf("%s/%s", target.GetNamespace(), target.GetName()),
-		"patches", len(config.Patches))
-
-	return &kubetypes.ActionResult{
-		Success:   true,
-		Message:   fmt.Sprintf("Successfully patched %s/%s with %d patches", target.GetNamespace(), target.GetName(), len(config.Patches)),
-		Changes:   changes,
-		StartTime: startTime,
-		EndTime:   time.Now(),
-		Metrics: map[string]string{
-			"patch_count": fmt.Sprintf("%d", len(config.Patches)),
-			"patch_type":  string(config.Type),
-		},
-	}, nil
+	return changes, nil
 }
 
 // Validate checks if the patch action can be executed
@@ -0,0 +1,57 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// spdyPodExecutor runs commands in containers through the pods/exec
+// subresource, like kubectl exec
+type spdyPodExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor creates a PodExecutor that execs into containers with the
+// given config. The operator needs create on pods/exec.
+func NewPodExecutor(config *rest.Config, clientset kubernetes.Interface) PodExecutor {
+	return &spdyPodExecutor{
+		config:    config,
+		clientset: clientset,
+	}
+}
+
+// Exec runs command in the container and returns its combined output. The
+// output is also returned when the command fails.
+func (e *spdyPodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return "", fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	var output bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &output,
+		Stderr: &output,
+	})
+	return output.String(), err
+}
@@ -0,0 +1,171 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// MissingRBACError reports a permission the operator lacks for an action
+type MissingRBACError struct {
+	Verb      string
+	Resource  string
+	Namespace string
+	Reason    string
+}
+
+func (e *MissingRBACError) Error() string {
+	msg := fmt.Sprintf("missing RBAC: %s %s in ns %s", e.Verb, e.Resource, e.Namespace)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// RBACPreflight checks with SelfSubjectAccessReviews that the operator may
+// perform every request an action needs before it is executed, so actions
+// fail before any partial work. Creating SelfSubjectAccessReviews is granted
+// to all authenticated users.
+type RBACPreflight struct {
+	client client.Client
+}
+
+// NewRBACPreflight creates a new RBAC pre-flight checker
+func NewRBACPreflight(client client.Client) *RBACPreflight {
+	return &RBACPreflight{client: client}
+}
+
+// accessRequest is a verb on a resource an action needs
+type accessRequest struct {
+	verb        string
+	subresource string
+	// pods makes the request on the target's pods instead of the target
+	pods bool
+}
+
+// requiredAccess lists the requests an action makes on the target
+func requiredAccess(action *v1alpha1.HealingAction) []accessRequest {
+	requests := executorAccess(action)
+
+	// Freezing patches the workload, and failed actions are rolled back
+	// with an update
+	if action.Spec.Action.FreezeRollout {
+		switch action.Spec.TargetResource.Kind {
+		case "Deployment", "StatefulSet":
+			requests = append(requests, accessRequest{verb: "patch"}, accessRequest{verb: "update"})
+		}
+	}
+	return requests
+}
+
+// executorAccess lists the requests the executor for an action makes on
+// the target, mirroring the executors' client calls
+func executorAccess(action *v1alpha1.HealingAction) []accessRequest {
+	switch action.Spec.Action.Type {
+	case "restart":
+		if restart := action.Spec.Action.RestartAction; restart != nil && len(restart.Containers) > 0 {
+			return []accessRequest{{verb: "list", pods: true}, {verb: "patch", pods: true}}
+		}
+		if action.Spec.TargetResource.Kind == "Pod" {
+			return []accessRequest{{verb: "delete"}}
+		}
+		return []accessRequest{{verb: "patch"}}
+	case "exec":
+		return []accessRequest{{verb: "list", pods: true}, {verb: "create", subresource: "exec", pods: true}}
+	case "resize":
+		if action.Spec.TargetResource.Kind == "Pod" {
+			return []accessRequest{{verb: "patch", subresource: "resize"}}
+		}
+		return []accessRequest{{verb: "patch"}}
+	case "scale", "hibernate":
+		return []accessRequest{{verb: "get", subresource: "scale"}, {verb: "update", subresource: "scale"}}
+	case "patch":
+		return []accessRequest{{verb: "update"}}
+	case "delete":
+		if action.Spec.Action.DeleteAction != nil && action.Spec.Action.DeleteAction.Force {
+			return []accessRequest{{verb: "update"}, {verb: "delete"}}
+		}
+		return []accessRequest{{verb: "delete"}}
+	case "finalizer":
+		return []accessRequest{{verb: "update"}}
+	case "taint", "drain":
+		return []accessRequest{{verb: "update"}}
+	case "chain":
+		// Steps are rolled back with an update
+		requests := []accessRequest{{verb: "update"}}
+		for i := range action.Spec.Action.Steps {
+			step := action.DeepCopy()
+			step.Spec.Action = *action.Spec.Action.Steps[i].ActionTemplate(&action.Spec.Action)
+			requests = append(requests, executorAccess(step)...)
+		}
+		return requests
+	default:
+		return nil
+	}
+}
+
+// Check returns a MissingRBACError for the first request the operator is
+// not allowed to make
+func (p *RBACPreflight) Check(ctx context.Context, action *v1alpha1.HealingAction) error {
+	requests := requiredAccess(action)
+	if len(requests) == 0 {
+		return nil
+	}
+
+	target := action.Spec.TargetResource
+	gv, err := schema.ParseGroupVersion(target.APIVersion)
+	if err != nil {
+		return fmt.Errorf("invalid API version %s: %w", target.APIVersion, err)
+	}
+	mapping, err := p.client.RESTMapper().RESTMapping(schema.GroupKind{Group: gv.Group, Kind: target.Kind}, gv.Version)
+	if err != nil {
+		return fmt.Errorf("failed to map %s to a resource: %w", target.Kind, err)
+	}
+
+	for _, request := range requests {
+		attributes := &authorizationv1.ResourceAttributes{
+			Namespace:   target.Namespace,
+			Verb:        request.verb,
+			Group:       gv.Group,
+			Version:     gv.Version,
+			Resource:    mapping.Resource.Resource,
+			Subresource: request.subresource,
+			Name:        target.Name,
+		}
+		if request.pods && target.Kind != "Pod" {
+			// Any of the target's pods may be used
+			attributes.Group = ""
+			attributes.Version = "v1"
+			attributes.Resource = "pods"
+			attributes.Name = ""
+		}
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: attributes,
+			},
+		}
+		if err := p.client.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to review access for %s %s: %w", request.verb, attributes.Resource, err)
+		}
+
+		if !review.Status.Allowed {
+			resource := attributes.Resource
+			if request.subresource != "" {
+				resource += "/" + request.subresource
+			}
+			return &MissingRBACError{
+				Verb:      request.verb,
+				Resource:  resource,
+				Namespace: target.Namespace,
+				Reason:    review.Status.Reason,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// accessReviewClient builds a fake client answering SelfSubjectAccessReviews
+// with allowed and recording the reviewed attributes
+func accessReviewClient(t *testing.T, allowed func(attrs *authorizationv1.ResourceAttributes) bool, reviewed *[]authorizationv1.ResourceAttributes, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for gvk := range scheme.AllKnownTypes() {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(mapper).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SelfSubjectAccessReview)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				attrs := review.Spec.ResourceAttributes
+				*reviewed = append(*reviewed, *attrs)
+				review.Status.Allowed = allowed(attrs)
+				if !review.Status.Allowed {
+					review.Status.Reason = "no RBAC policy matched"
+				}
+				return nil
+			},
+		}).
+		Build()
+}
+
+func TestRBACPreflight_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        string
+		apiVersion  string
+		actionType  string
+		deleteForce bool
+		freeze      bool
+		denyVerb    string
+		expectVerbs []string
+		expectError string
+	}{
+		{
+			name:        "restart pod needs delete",
+			kind:        "Pod",
+			apiVersion:  "v1",
+			actionType:  "restart",
+			expectVerbs: []string{"delete"},
+		},
+		{
+			name:        "restart deployment needs patch",
+			kind:        "Deployment",
+			apiVersion:  "apps/v1",
+			actionType:  "restart",
+			expectVerbs: []string{"patch"},
+		},
+		{
+			name:        "scale denied",
+			kind:        "Deployment",
+			apiVersion:  "apps/v1",
+			actionType:  "scale",
+			denyVerb:    "update",
+			expectVerbs: []string{"get", "update"},
+			expectError: "missing RBAC: update deployments/scale in ns default",
+		},
+		{
+			name:        "force delete needs update and delete",
+			kind:        "Pod",
+			apiVersion:  "v1",
+			actionType:  "delete",
+			deleteForce: true,
+			denyVerb:    "delete",
+			expectVerbs: []string{"update", "delete"},
+			expectError: "missing RBAC: delete pods in ns default",
+		},
+		{
+			name:        "exec into deployment pods denied",
+			kind:        "Deployment",
+			apiVersion:  "apps/v1",
+			actionType:  "exec",
+			denyVerb:    "create",
+			expectVerbs: []string{"list", "create"},
+			expectError: "missing RBAC: create pods/exec in ns default",
+		},
+		{
+			name:        "frozen patch needs patch and update",
+			kind:        "Deployment",
+			apiVersion:  "apps/v1",
+			actionType:  "patch",
+			freeze:      true,
+			expectVerbs: []string{"update", "patch", "update"},
+		},
+		{
+			name:       "custom actions are not checked",
+			kind:       "Pod",
+			apiVersion: "v1",
+			actionType: "custom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviewed []authorizationv1.ResourceAttributes
+			c := accessReviewClient(t, func(attrs *authorizationv1.ResourceAttributes) bool {
+				return attrs.Verb != tt.denyVerb
+			}, &reviewed)
+
+			action := &v1alpha1.HealingAction{
+				Spec: v1alpha1.HealingActionSpec{
+					TargetResource: v1alpha1.TargetResource{
+						APIVersion: tt.apiVersion,
+						Kind:       tt.kind,
+						Name:       "app",
+						Namespace:  "default",
+					},
+					Action: v1alpha1.HealingActionTemplate{Name: tt.actionType, Type: tt.actionType},
+				},
+			}
+			if tt.deleteForce {
+				action.Spec.Action.DeleteAction = &v1alpha1.DeleteAction{Force: true}
+			}
+			action.Spec.Action.FreezeRollout = tt.freeze
+
+			err := NewRBACPreflight(c).Check(context.Background(), action)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectError+": no RBAC policy matched", err.Error())
+				var rbacErr *MissingRBACError
+				assert.ErrorAs(t, err, &rbacErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var verbs []string
+			for _, attrs := range reviewed {
+				verbs = append(verbs, attrs.Verb)
+				if attrs.Resource == "pods" && tt.kind != "Pod" {
+					// Requests on the target's pods cover any of them
+					assert.Empty(t, attrs.Name)
+					continue
+				}
+				assert.Equal(t, "app", attrs.Name)
+				assert.Equal(t, "default", attrs.Namespace)
+			}
+			assert.Equal(t, tt.expectVerbs, verbs)
+		})
+	}
+}
+
+func TestEngine_ExecuteAction_RBACPreflight(t *testing.T) {
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+	}
+
+	var reviewed []authorizationv1.ResourceAttributes
+	c := accessReviewClient(t, func(*authorizationv1.ResourceAttributes) bool { return false }, &reviewed, pod)
+
+	executed := false
+	engine := NewEngine(c, nil)
+	engine.RegisterExecutor("restart", &MockExecutor{
+		ExecuteFunc: func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+			executed = true
+			return &kubetypes.ActionResult{Success: true}, nil
+		},
+	})
+	engine.SetRBACPreflight(NewRBACPreflight(c))
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-action", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "test-pod", Namespace: "default"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+		},
+	}
+
+	result, err := engine.ExecuteAction(context.Background(), action)
+	require.Error(t, err)
+	assert.False(t, executed, "action should not run without permissions")
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "missing RBAC: delete pods in ns default")
+}
@@ -0,0 +1,165 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrReadOnly is returned for writes rejected by a read-only client
+var ErrReadOnly = errors.New("write rejected: operator is in read-only mode")
+
+// readOnlyViolations counts writes rejected by read-only clients
+var readOnlyViolations *prometheus.CounterVec
+
+// SetReadOnlyViolationsMetric sets the read-only violations metric from main.go
+func SetReadOnlyViolationsMetric(metric *prometheus.CounterVec) {
+	readOnlyViolations = metric
+}
+
+// readOnlyClient rejects every write that is not a dry run. It backs the
+// engine in dry-run mode so a write that slips past the dry-run checks
+// fails instead of changing the cluster.
+type readOnlyClient struct {
+	client.Client
+
+	// enforced reports whether writes are rejected; nil rejects them always
+	enforced func() bool
+}
+
+// NewReadOnlyClient wraps c so that reads pass through and writes fail
+// with ErrReadOnly. Writes sent with dryRun=All are allowed, as the API
+// server does not persist them.
+func NewReadOnlyClient(c client.Client) client.Client {
+	return &readOnlyClient{Client: c}
+}
+
+// NewSafeModeClient wraps c so that writes fail with ErrReadOnly while
+// safeMode reports true, such as while the operator watchdog holds the
+// operator in safe mode. Actions that started before safe mode was entered
+// are stopped at their next write.
+func NewSafeModeClient(c client.Client, safeMode func() bool) client.Client {
+	return &readOnlyClient{Client: c, enforced: safeMode}
+}
+
+// allowed reports whether a write with the dryRun option passes
+func (r *readOnlyClient) allowed(dryRun []string) bool {
+	return isDryRun(dryRun) || (r.enforced != nil && !r.enforced())
+}
+
+// Create rejects the creation of obj
+func (r *readOnlyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if r.allowed((&client.CreateOptions{}).ApplyOptions(opts).DryRun) {
+		return r.Client.Create(ctx, obj, opts...)
+	}
+	return r.reject(ctx, "create", obj, "")
+}
+
+// Update rejects the update of obj
+func (r *readOnlyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if r.allowed((&client.UpdateOptions{}).ApplyOptions(opts).DryRun) {
+		return r.Client.Update(ctx, obj, opts...)
+	}
+	return r.reject(ctx, "update", obj, "")
+}
+
+// Patch rejects the patch of obj
+func (r *readOnlyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if r.allowed((&client.PatchOptions{}).ApplyOptions(opts).DryRun) {
+		return r.Client.Patch(ctx, obj, patch, opts...)
+	}
+	return r.reject(ctx, "patch", obj, "")
+}
+
+// Delete rejects the deletion of obj
+func (r *readOnlyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if r.allowed((&client.DeleteOptions{}).ApplyOptions(opts).DryRun) {
+		return r.Client.Delete(ctx, obj, opts...)
+	}
+	return r.reject(ctx, "delete", obj, "")
+}
+
+// DeleteAllOf rejects the deletion of the objects of obj's type
+func (r *readOnlyClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if r.allowed((&client.DeleteAllOfOptions{}).ApplyOptions(opts).DryRun) {
+		return r.Client.DeleteAllOf(ctx, obj, opts...)
+	}
+	return r.reject(ctx, "deletecollection", obj, "")
+}
+
+// Status returns a writer rejecting status writes
+func (r *readOnlyClient) Status() client.SubResourceWriter {
+	return r.SubResource("status")
+}
+
+// SubResource returns a client rejecting subresource writes
+func (r *readOnlyClient) SubResource(subResource string) client.SubResourceClient {
+	return &readOnlySubResourceClient{
+		SubResourceClient: r.Client.SubResource(subResource),
+		parent:            r,
+		subResource:       subResource,
+	}
+}
+
+// reject logs and counts a rejected write
+func (r *readOnlyClient) reject(ctx context.Context, verb string, obj client.Object, subResource string) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvk, err := apiutil.GVKForObject(obj, r.Scheme()); err == nil {
+			kind = gvk.Kind
+		}
+	}
+	if subResource != "" {
+		kind = kind + "/" + subResource
+	}
+
+	log.FromContext(ctx).Error(ErrReadOnly, "Rejected write in read-only mode",
+		"verb", verb, "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+	if readOnlyViolations != nil {
+		readOnlyViolations.WithLabelValues(verb, kind).Inc()
+	}
+	return fmt.Errorf("%s %s %s/%s: %w", verb, kind, obj.GetNamespace(), obj.GetName(), ErrReadOnly)
+}
+
+// readOnlySubResourceClient rejects every subresource write that is not a
+// dry run
+type readOnlySubResourceClient struct {
+	client.SubResourceClient
+	parent      *readOnlyClient
+	subResource string
+}
+
+// Create rejects the creation of the subresource
+func (s *readOnlySubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if s.parent.allowed((&client.SubResourceCreateOptions{}).ApplyOptions(opts).DryRun) {
+		return s.SubResourceClient.Create(ctx, obj, subResource, opts...)
+	}
+	return s.parent.reject(ctx, "create", obj, s.subResource)
+}
+
+// Update rejects the update of the subresource
+func (s *readOnlySubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if s.parent.allowed((&client.SubResourceUpdateOptions{}).ApplyOptions(opts).DryRun) {
+		return s.SubResourceClient.Update(ctx, obj, opts...)
+	}
+	return s.parent.reject(ctx, "update", obj, s.subResource)
+}
+
+// Patch rejects the patch of the subresource
+func (s *readOnlySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if s.parent.allowed((&client.SubResourcePatchOptions{}).ApplyOptions(opts).DryRun) {
+		return s.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	}
+	return s.parent.reject(ctx, "patch", obj, s.subResource)
+}
+
+// isDryRun reports whether write options request a server-side dry run
+func isDryRun(dryRun []string) bool {
+	return len(dryRun) == 1 && dryRun[0] == metav1.DryRunAll
+}
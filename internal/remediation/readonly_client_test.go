@@ -0,0 +1,129 @@
+package remediation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReadOnlyClient_RejectsWrites(t *testing.T) {
+	violations := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_readonly_violations_total"}, []string{"verb", "kind"})
+	SetReadOnlyViolationsMetric(violations)
+	defer SetReadOnlyViolationsMetric(nil)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment.DeepCopy()).Build()
+	c := NewReadOnlyClient(base)
+	ctx := context.Background()
+
+	// Reads pass through
+	live := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), live))
+
+	err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"}})
+	assert.True(t, errors.Is(err, ErrReadOnly))
+	live.Labels = map[string]string{"changed": "true"}
+	assert.True(t, errors.Is(c.Update(ctx, live), ErrReadOnly))
+	assert.True(t, errors.Is(c.Patch(ctx, live, client.MergeFrom(deployment)), ErrReadOnly))
+	assert.True(t, errors.Is(c.Delete(ctx, live), ErrReadOnly))
+	assert.True(t, errors.Is(c.DeleteAllOf(ctx, &appsv1.Deployment{}, client.InNamespace("default")), ErrReadOnly))
+	assert.True(t, errors.Is(c.Status().Update(ctx, live), ErrReadOnly))
+	assert.True(t, errors.Is(c.SubResource("scale").Patch(ctx, live, client.MergeFrom(deployment)), ErrReadOnly))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("create", "ConfigMap")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("update", "Deployment")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("patch", "Deployment")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("delete", "Deployment")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("deletecollection", "Deployment")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("update", "Deployment/status")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(violations.WithLabelValues("patch", "Deployment/scale")))
+
+	// Nothing was changed
+	stored := &appsv1.Deployment{}
+	require.NoError(t, base.Get(ctx, client.ObjectKeyFromObject(deployment), stored))
+	assert.Empty(t, stored.Labels)
+}
+
+func TestReadOnlyClient_AllowsDryRunWrites(t *testing.T) {
+	violations := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_readonly_violations_total"}, []string{"verb", "kind"})
+	SetReadOnlyViolationsMetric(violations)
+	defer SetReadOnlyViolationsMetric(nil)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+
+	var forwarded int
+	base := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deployment.DeepCopy()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				forwarded++
+				return nil
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				forwarded++
+				return nil
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				forwarded++
+				return nil
+			},
+		}).
+		Build()
+	c := NewReadOnlyClient(base)
+	ctx := context.Background()
+
+	live := deployment.DeepCopy()
+	require.NoError(t, c.Update(ctx, live, client.DryRunAll))
+	require.NoError(t, c.Patch(ctx, live, client.MergeFrom(deployment), client.DryRunAll))
+	require.NoError(t, c.Delete(ctx, live, client.DryRunAll))
+	assert.Equal(t, 3, forwarded)
+
+	// A dry-run client layered on top is allowed through as well
+	require.NoError(t, client.NewDryRunClient(c).Update(ctx, live))
+	assert.Equal(t, 4, forwarded)
+	assert.Equal(t, 0, testutil.CollectAndCount(violations))
+}
+
+func TestSafeModeClient_RejectsWritesInSafeMode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment.DeepCopy()).Build()
+	safeMode := false
+	c := NewSafeModeClient(base, func() bool { return safeMode })
+	ctx := context.Background()
+
+	live := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), live))
+	live.Labels = map[string]string{"changed": "true"}
+	require.NoError(t, c.Update(ctx, live), "writes pass outside safe mode")
+
+	safeMode = true
+	live.Labels["changed"] = "again"
+	assert.True(t, errors.Is(c.Update(ctx, live), ErrReadOnly))
+	assert.True(t, errors.Is(c.SubResource("scale").Patch(ctx, live, client.MergeFrom(deployment)), ErrReadOnly))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deployment), live))
+	assert.Equal(t, "true", live.Labels["changed"])
+
+	safeMode = false
+	live.Labels["changed"] = "again"
+	assert.NoError(t, c.Update(ctx, live), "writes pass once safe mode is left")
+}
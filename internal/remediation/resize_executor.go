@@ -0,0 +1,271 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// ResizeExecutor changes the resources of containers. Pods are resized in
+// place through the resize subresource; workloads get the resources in
+// their pod template.
+type ResizeExecutor struct {
+	client client.Client
+}
+
+// NewResizeExecutor creates a new resize executor
+func NewResizeExecutor(client client.Client) *ResizeExecutor {
+	return &ResizeExecutor{
+		client: client,
+	}
+}
+
+// Execute sets the configured resources on the selected containers
+func (r *ResizeExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	log := log.FromContext(ctx)
+	startTime := time.Now()
+
+	if err := r.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Validation failed: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	u, err := r.toUnstructured(target)
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to convert to unstructured: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	var changes []v1alpha1.ResourceChange
+	attempts, err := retryOnConflict(ctx, r.client, u, func() error {
+		original := u.DeepCopy()
+		var setErr error
+		changes, setErr = setContainerResources(u, action.ResizeAction)
+		if setErr != nil || len(changes) == 0 {
+			return setErr
+		}
+		patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+		if u.GetKind() != "Pod" {
+			return r.client.Patch(ctx, u, patch)
+		}
+		// Clusters before Kubernetes 1.33 resize pods through the pod itself
+		err := r.client.SubResource("resize").Patch(ctx, u, patch)
+		if errors.IsNotFound(err) || errors.IsMethodNotSupported(err) {
+			err = r.client.Patch(ctx, u, patch)
+		}
+		return err
+	})
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to resize containers: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	ref := fmt.Sprintf("%s/%s/%s", u.GetKind(), u.GetNamespace(), u.GetName())
+	log.Info("Resized containers", "resource", ref, "containers", action.ResizeAction.Containers, "changes", len(changes))
+
+	message := fmt.Sprintf("Resized containers of %s", ref)
+	if len(changes) == 0 {
+		message = fmt.Sprintf("Containers of %s already have the requested resources", ref)
+	}
+	return &kubetypes.ActionResult{
+		Success:   true,
+		Message:   message,
+		Changes:   changes,
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Metrics: map[string]string{
+			"resized_fields":     fmt.Sprintf("%d", len(changes)),
+			MetricUpdateAttempts: fmt.Sprintf("%d", attempts),
+		},
+	}, nil
+}
+
+// Validate checks the configuration and that the target has the containers
+func (r *ResizeExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	config := action.ResizeAction
+	if config == nil {
+		return fmt.Errorf("resize action missing configuration")
+	}
+	if len(config.Containers) == 0 {
+		return fmt.Errorf("resize action requires containers")
+	}
+	if len(config.Resources.Requests) == 0 && len(config.Resources.Limits) == 0 {
+		return fmt.Errorf("resize action requires resource requests or limits")
+	}
+
+	u, err := r.toUnstructured(target)
+	if err != nil {
+		return err
+	}
+	containers, found, err := unstructured.NestedSlice(u.Object, containersPath(u.GetKind())...)
+	if err != nil || !found {
+		return fmt.Errorf("%s %s has no containers to resize", u.GetKind(), u.GetName())
+	}
+	for _, name := range config.Containers {
+		if containerIndex(containers, name) < 0 {
+			return fmt.Errorf("container %s not found in %s %s", name, u.GetKind(), u.GetName())
+		}
+	}
+	return nil
+}
+
+// DryRun reports the resources that would change
+func (r *ResizeExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	if err := r.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Validation failed: %v", err),
+		}, err
+	}
+
+	u, err := r.toUnstructured(target)
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to convert to unstructured: %v", err),
+		}, err
+	}
+	changes, err := setContainerResources(u.DeepCopy(), action.ResizeAction)
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: fmt.Sprintf("Dry-run: Would resize containers of %s/%s/%s", u.GetKind(), u.GetNamespace(), u.GetName()),
+		Changes: changes,
+		Metrics: map[string]string{
+			"resized_fields": fmt.Sprintf("%d", len(changes)),
+		},
+	}, nil
+}
+
+// setContainerResources sets the configured requests and limits on the
+// selected containers of u and returns the fields that changed
+func setContainerResources(u *unstructured.Unstructured, config *v1alpha1.ResizeAction) ([]v1alpha1.ResourceChange, error) {
+	path := containersPath(u.GetKind())
+	containers, _, err := unstructured.NestedSlice(u.Object, path...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read containers: %w", err)
+	}
+
+	ref := fmt.Sprintf("%s/%s/%s", u.GetKind(), u.GetNamespace(), u.GetName())
+	now := metav1.Now()
+	var changes []v1alpha1.ResourceChange
+	for _, name := range config.Containers {
+		i := containerIndex(containers, name)
+		if i < 0 {
+			return nil, fmt.Errorf("container %s not found in %s", name, ref)
+		}
+		container := containers[i].(map[string]interface{})
+		for _, set := range []struct {
+			field     string
+			resources corev1.ResourceList
+		}{
+			{"requests", config.Resources.Requests},
+			{"limits", config.Resources.Limits},
+		} {
+			for _, resourceName := range sortedResourceNames(set.resources) {
+				quantity := set.resources[resourceName]
+				old, _, _ := unstructured.NestedString(container, "resources", set.field, string(resourceName))
+				if current, err := resource.ParseQuantity(old); err == nil && quantity.Cmp(current) == 0 {
+					continue
+				}
+				if err := unstructured.SetNestedField(container, quantity.String(), "resources", set.field, string(resourceName)); err != nil {
+					return nil, fmt.Errorf("failed to set %s of container %s: %w", set.field, name, err)
+				}
+				changes = append(changes, v1alpha1.ResourceChange{
+					ResourceRef: ref,
+					ChangeType:  "patch",
+					Field:       fmt.Sprintf("containers[%s].resources.%s.%s", name, set.field, resourceName),
+					OldValue:    old,
+					NewValue:    quantity.String(),
+					Timestamp:   &now,
+				})
+			}
+		}
+	}
+
+	if err := unstructured.SetNestedSlice(u.Object, containers, path...); err != nil {
+		return nil, fmt.Errorf("failed to set containers: %w", err)
+	}
+	return changes, nil
+}
+
+// containersPath returns the path of the containers of a kind: the pod's own
+// containers, or those of a workload's pod template
+func containersPath(kind string) []string {
+	if kind == "Pod" {
+		return []string{"spec", "containers"}
+	}
+	return []string{"spec", "template", "spec", "containers"}
+}
+
+// containerIndex returns the index of the named container or -1
+func containerIndex(containers []interface{}, name string) int {
+	for i, c := range containers {
+		if container, ok := c.(map[string]interface{}); ok && container["name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortedResourceNames returns the resource names of a list in a stable order
+func sortedResourceNames(resources corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// toUnstructured converts obj to an unstructured object carrying its kind
+func (r *ResizeExecutor) toUnstructured(obj client.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := toUnstructuredMap(obj)
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := apiutil.GVKForObject(obj, r.client.Scheme())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kind: %w", err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	return u, nil
+}
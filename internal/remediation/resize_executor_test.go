@@ -0,0 +1,102 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestResizeExecutor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	containers := []corev1.Container{
+		{Name: "app", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}},
+		{Name: "envoy"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: containers},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}},
+		},
+	}
+
+	var resizes int
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pod, deployment).
+		WithInterceptorFuncs(interceptor.Funcs{
+			// The resize subresource is missing before Kubernetes 1.33
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				resizes++
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "pods/resize"}, obj.GetName())
+			},
+		}).
+		Build()
+
+	executor := NewResizeExecutor(fakeClient)
+	action := &v1alpha1.HealingActionTemplate{
+		Type: "resize",
+		ResizeAction: &v1alpha1.ResizeAction{
+			Containers: []string{"app"},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		},
+	}
+
+	// Only the fields that change are reported
+	dryRun, err := executor.DryRun(context.Background(), pod.DeepCopy(), action)
+	require.NoError(t, err)
+	require.Len(t, dryRun.Changes, 1)
+	assert.Equal(t, "containers[app].resources.limits.memory", dryRun.Changes[0].Field)
+	assert.Equal(t, "512Mi", dryRun.Changes[0].OldValue)
+	assert.Equal(t, "1Gi", dryRun.Changes[0].NewValue)
+
+	// Pods are resized in place, falling back to the pod on older clusters
+	result, err := executor.Execute(context.Background(), pod.DeepCopy(), action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, resizes)
+	live := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), live))
+	assert.Equal(t, "1Gi", live.Spec.Containers[0].Resources.Limits.Memory().String())
+	assert.Empty(t, live.Spec.Containers[1].Resources.Limits)
+
+	// Workloads get the resources in their pod template
+	result, err = executor.Execute(context.Background(), deployment.DeepCopy(), action)
+	require.NoError(t, err)
+	assert.Len(t, result.Changes, 1)
+	assert.Equal(t, 1, resizes)
+	liveDeployment := &appsv1.Deployment{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(deployment), liveDeployment))
+	assert.Equal(t, "1Gi", liveDeployment.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String())
+
+	// Unknown containers are rejected
+	action.ResizeAction.Containers = []string{"missing"}
+	assert.ErrorContains(t, executor.Validate(context.Background(), pod.DeepCopy(), action), "container missing not found")
+}
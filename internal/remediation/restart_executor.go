@@ -21,12 +21,16 @@ import (
 // RestartExecutor handles restart actions
 type RestartExecutor struct {
 	client client.Client
+
+	// sleep waits while meshed pods drain and recreated pods terminate
+	sleep func(ctx context.Context, d time.Duration) error
 }
 
 // NewRestartExecutor creates a new restart executor
 func NewRestartExecutor(client client.Client) *RestartExecutor {
 	return &RestartExecutor{
 		client: client,
+		sleep:  sleepContext,
 	}
 }
 
@@ -49,16 +53,21 @@ func (r *RestartExecutor) Execute(ctx context.Context, target client.Object, act
 	// Execute based on resource type
 	var changes []v1alpha1.ResourceChange
 	var err error
+	attempts := 1
 
 	switch gvk.Kind {
 	case "Pod":
-		changes, err = r.restartPodGeneric(ctx, target, config)
-	case "Deployment":
-		changes, err = r.restartWorkloadGeneric(ctx, target, config, "Deployment")
-	case "StatefulSet":
-		changes, err = r.restartWorkloadGeneric(ctx, target, config, "StatefulSet")
-	case "DaemonSet":
-		changes, err = r.restartWorkloadGeneric(ctx, target, config, "DaemonSet")
+		if len(config.Containers) > 0 {
+			changes, err = r.restartContainers(ctx, target, config)
+		} else {
+			changes, err = r.restartPodGeneric(ctx, target, config)
+		}
+	case "Deployment", "StatefulSet", "DaemonSet":
+		if len(config.Containers) > 0 {
+			changes, err = r.restartContainers(ctx, target, config)
+		} else {
+			changes, attempts, err = r.restartWorkloadGeneric(ctx, target, config, gvk.Kind)
+		}
 	default:
 		return &kubetypes.ActionResult{
 			Success:   false,
@@ -91,8 +100,9 @@ func (r *RestartExecutor) Execute(ctx context.Context, target client.Object, act
 		StartTime: startTime,
 		EndTime:   time.Now(),
 		Metrics: map[string]string{
-			"restart_strategy": config.Strategy,
-			"resource_type":    fmt.Sprintf("%T", target),
+			"restart_strategy":   config.Strategy,
+			"resource_type":      fmt.Sprintf("%T", target),
+			MetricUpdateAttempts: fmt.Sprintf("%d", attempts),
 		},
 	}, nil
 }
@@ -279,17 +289,23 @@ func (r *RestartExecutor) restartDeployment(ctx context.Context, deployment *app
 		originalReplicas := *deployment.Spec.Replicas
 
 		// Scale down to 0
-		deployment.Spec.Replicas = int32Ptr(0)
-		if err := r.client.Update(ctx, deployment); err != nil {
+		if _, err := retryOnConflict(ctx, r.client, deployment, func() error {
+			deployment.Spec.Replicas = int32Ptr(0)
+			return r.client.Update(ctx, deployment)
+		}); err != nil {
 			return changes, fmt.Errorf("failed to scale down deployment: %w", err)
 		}
 
 		// Wait a moment for pods to terminate
-		time.Sleep(2 * time.Second)
+		if err := r.sleep(ctx, 2*time.Second); err != nil {
+			return changes, fmt.Errorf("interrupted while scaling down deployment: %w", err)
+		}
 
 		// Scale back up
-		deployment.Spec.Replicas = &originalReplicas
-		if err := r.client.Update(ctx, deployment); err != nil {
+		if _, err := retryOnConflict(ctx, r.client, deployment, func() error {
+			deployment.Spec.Replicas = &originalReplicas
+			return r.client.Update(ctx, deployment)
+		}); err != nil {
 			return changes, fmt.Errorf("failed to scale up deployment: %w", err)
 		}
 
@@ -392,6 +408,15 @@ func (r *RestartExecutor) restartPodGeneric(ctx context.Context, target client.O
 		},
 	}
 
+	// Drain meshed pods before deleting them
+	if config.Mesh != nil {
+		meshChanges, err := r.prepareMeshRestart(ctx, target, config.Mesh)
+		if err != nil {
+			return meshChanges, err
+		}
+		changes = append(meshChanges, changes...)
+	}
+
 	// Delete the pod based on strategy
 	deleteOptions := &client.DeleteOptions{}
 
@@ -419,7 +444,7 @@ func (r *RestartExecutor) restartPodGeneric(ctx context.Context, target client.O
 }
 
 // restartWorkloadGeneric restarts a workload (Deployment/StatefulSet/DaemonSet) using generic client
-func (r *RestartExecutor) restartWorkloadGeneric(ctx context.Context, target client.Object, config *v1alpha1.RestartAction, kind string) ([]v1alpha1.ResourceChange, error) {
+func (r *RestartExecutor) restartWorkloadGeneric(ctx context.Context, target client.Object, config *v1alpha1.RestartAction, kind string) ([]v1alpha1.ResourceChange, int, error) {
 	log := log.FromContext(ctx)
 
 	// Use kubectl's restart annotation approach
@@ -444,8 +469,11 @@ func (r *RestartExecutor) restartWorkloadGeneric(ctx context.Context, target cli
 		"namespace", target.GetNamespace(),
 		"strategy", config.Strategy)
 
-	if err := r.client.Patch(ctx, target, client.RawPatch(types.MergePatchType, mustMarshalJSON(annotationPatch))); err != nil {
-		return nil, fmt.Errorf("failed to patch %s: %w", kind, err)
+	attempts, err := retryOnConflict(ctx, r.client, target, func() error {
+		return r.client.Patch(ctx, target, client.RawPatch(types.MergePatchType, mustMarshalJSON(annotationPatch)))
+	})
+	if err != nil {
+		return nil, attempts, fmt.Errorf("failed to patch %s: %w", kind, err)
 	}
 
 	changes := []v1alpha1.ResourceChange{
@@ -466,7 +494,7 @@ func (r *RestartExecutor) restartWorkloadGeneric(ctx context.Context, target cli
 		log.Info("Recreate strategy requested but using rolling restart for simplicity")
 	}
 
-	return changes, nil
+	return changes, attempts, nil
 }
 
 // mustMarshalJSON marshals an object to JSON, panicking on error
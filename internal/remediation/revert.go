@@ -0,0 +1,163 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errTargetDiverged is returned when changes are not reverted because the
+// target was changed or deleted by someone else since the action
+var errTargetDiverged = errors.New("target diverged")
+
+// revertChanges undoes the changes an action made to a resource, given the
+// resource as the action found it and as it left it. Only the fields that
+// differ between the two are set back, so changes others made to other
+// fields are kept; status and metadata other than labels and annotations
+// are left alone. If a field to revert no longer has
+// the value the action left, or the resource is gone, nothing is reverted
+// and an error wrapping errTargetDiverged is returned.
+func revertChanges(ctx context.Context, c client.Client, before, after client.Object) error {
+	beforeFields, err := revertibleFields(before)
+	if err != nil {
+		return err
+	}
+	afterFields, err := revertibleFields(after)
+	if err != nil {
+		return err
+	}
+	patch := revertPatch(beforeFields, afterFields)
+	if len(patch) == 0 {
+		return nil
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(before.GetObjectKind().GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(before), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s %s was deleted", errTargetDiverged, current.GetKind(), before.GetName())
+		}
+		return fmt.Errorf("failed to get current resource state: %w", err)
+	}
+	if field := changedSince("", patch, afterFields, current.Object); field != "" {
+		return fmt.Errorf("%w: %s was changed by someone else", errTargetDiverged, field)
+	}
+
+	// The resource version fails the patch if the resource changed since
+	// it was checked
+	metadata, _ := patch["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+		patch["metadata"] = metadata
+	}
+	metadata["resourceVersion"] = current.GetResourceVersion()
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	if err := c.Patch(ctx, current, client.RawPatch(types.MergePatchType, data)); err != nil {
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("%w: %s %s changed while it was reverted", errTargetDiverged, current.GetKind(), current.GetName())
+		}
+		return fmt.Errorf("failed to revert resource: %w", err)
+	}
+	return nil
+}
+
+// revertibleFields returns the fields of obj an action may change: its
+// labels, annotations and every top-level field but status, such as the
+// spec of a workload or the data of a ConfigMap
+func revertibleFields(obj runtime.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert resource: %w", err)
+	}
+	fields := make(map[string]interface{})
+	for key, value := range content {
+		switch key {
+		case "apiVersion", "kind", "metadata", "status":
+		default:
+			fields[key] = value
+		}
+	}
+	metadata := make(map[string]interface{})
+	for _, key := range []string{"labels", "annotations"} {
+		if value, ok, _ := unstructured.NestedFieldNoCopy(content, "metadata", key); ok {
+			metadata[key] = value
+		}
+	}
+	fields["metadata"] = metadata
+	return fields, nil
+}
+
+// revertPatch returns the merge patch setting the fields that differ
+// between after and before back to their values in before
+func revertPatch(before, after map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key, afterValue := range after {
+		beforeValue, ok := before[key]
+		if !ok {
+			// A null removes fields the action added. Fields added to a
+			// new map are removed one by one, keeping the ones others add.
+			if afterMap, isMap := afterValue.(map[string]interface{}); isMap {
+				if nested := revertPatch(map[string]interface{}{}, afterMap); len(nested) > 0 {
+					patch[key] = nested
+				}
+				continue
+			}
+			patch[key] = nil
+			continue
+		}
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		beforeMap, beforeIsMap := beforeValue.(map[string]interface{})
+		afterMap, afterIsMap := afterValue.(map[string]interface{})
+		if beforeIsMap && afterIsMap {
+			if nested := revertPatch(beforeMap, afterMap); len(nested) > 0 {
+				patch[key] = nested
+			}
+			continue
+		}
+		patch[key] = beforeValue
+	}
+	for key, beforeValue := range before {
+		if _, ok := after[key]; !ok {
+			patch[key] = beforeValue
+		}
+	}
+	return patch
+}
+
+// changedSince returns the first field set by patch whose value in current
+// is not its value in after, or "" if they all still have it
+func changedSince(path string, patch, after, current map[string]interface{}) string {
+	for key, value := range patch {
+		afterValue, inAfter := after[key]
+		currentValue, inCurrent := current[key]
+		nested, isMap := value.(map[string]interface{})
+		afterMap, afterIsMap := afterValue.(map[string]interface{})
+		if isMap && afterIsMap {
+			currentMap, ok := currentValue.(map[string]interface{})
+			if !ok {
+				return path + key
+			}
+			if field := changedSince(path+key+".", nested, afterMap, currentMap); field != "" {
+				return field
+			}
+			continue
+		}
+		if inAfter != inCurrent || !reflect.DeepEqual(afterValue, currentValue) {
+			return path + key
+		}
+	}
+	return ""
+}
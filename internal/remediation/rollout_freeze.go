@@ -0,0 +1,238 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// rolloutVerifyTimeout bounds the wait for the rollout of an action's
+	// changes to a frozen workload to become healthy
+	rolloutVerifyTimeout = 2 * time.Minute
+
+	// rolloutVerifyInterval is how often that rollout is checked
+	rolloutVerifyInterval = 2 * time.Second
+)
+
+// rolloutFreeze holds the rollouts of a workload while an action changes
+// it, so the workload's controller and other tools cannot start a rollout
+// in between the action's changes
+type rolloutFreeze struct {
+	client client.Client
+	target *unstructured.Unstructured
+
+	// wasPaused is the spec.paused of a Deployment before the freeze
+	wasPaused bool
+
+	// partition is the rolling update partition held on a StatefulSet, and
+	// previousPartition the partition it had before the freeze
+	partition         int64
+	previousPartition *int64
+
+	// before is the frozen workload as the action found it
+	before client.Object
+}
+
+// freezeRollout pauses a Deployment, or raises the rolling update partition
+// of a StatefulSet to its replica count. It returns nil for other kinds and
+// for StatefulSets updated OnDelete, which never roll out on their own.
+func freezeRollout(ctx context.Context, c client.Client, target client.Object) (*rolloutFreeze, error) {
+	u, ok := target.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	freeze := &rolloutFreeze{client: c, target: u}
+	var patch map[string]interface{}
+	switch u.GetKind() {
+	case "Deployment":
+		freeze.wasPaused, _, _ = unstructured.NestedBool(u.Object, "spec", "paused")
+		patch = map[string]interface{}{"spec": map[string]interface{}{"paused": true}}
+	case "StatefulSet":
+		strategy, _, _ := unstructured.NestedString(u.Object, "spec", "updateStrategy", "type")
+		if strategy != "" && strategy != "RollingUpdate" {
+			return nil, nil
+		}
+		if partition, found, _ := unstructured.NestedInt64(u.Object, "spec", "updateStrategy", "rollingUpdate", "partition"); found {
+			freeze.previousPartition = &partition
+		}
+		freeze.partition = 1
+		if replicas, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas"); found {
+			freeze.partition = replicas
+		}
+		patch = partitionPatch(freeze.partition)
+	default:
+		return nil, nil
+	}
+
+	if err := freeze.patch(ctx, patch); err != nil {
+		return nil, fmt.Errorf("failed to freeze rollouts of %s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	return freeze, nil
+}
+
+// current reads the frozen workload
+func (f *rolloutFreeze) current(ctx context.Context) (*unstructured.Unstructured, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(f.target.GroupVersionKind())
+	if err := f.client.Get(ctx, client.ObjectKeyFromObject(f.target), current); err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", f.target.GetKind(), f.target.GetName(), err)
+	}
+	return current, nil
+}
+
+// takenOver checks that the workload is still frozen. A workload that was
+// resumed while the action changed it is being rolled out by someone else.
+func (f *rolloutFreeze) takenOver(current *unstructured.Unstructured) error {
+	switch f.target.GetKind() {
+	case "Deployment":
+		if paused, _, _ := unstructured.NestedBool(current.Object, "spec", "paused"); !paused {
+			return fmt.Errorf("deployment %s was resumed by someone else during the action", f.target.GetName())
+		}
+	case "StatefulSet":
+		partition, _, _ := unstructured.NestedInt64(current.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+		if partition < f.partition {
+			return fmt.Errorf("statefulset %s partition was lowered to %d by someone else during the action", f.target.GetName(), partition)
+		}
+	}
+	return nil
+}
+
+// release restores the paused state or partition the workload had before
+// the freeze, letting the changes of the action roll out
+func (f *rolloutFreeze) release(ctx context.Context) error {
+	var patch map[string]interface{}
+	switch f.target.GetKind() {
+	case "Deployment":
+		patch = map[string]interface{}{"spec": map[string]interface{}{"paused": f.wasPaused}}
+	case "StatefulSet":
+		if f.previousPartition != nil {
+			patch = partitionPatch(*f.previousPartition)
+		} else {
+			// A null removes the partition set by the freeze
+			patch = partitionPatch(nil)
+		}
+	}
+
+	if err := f.patch(ctx, patch); err != nil {
+		return fmt.Errorf("failed to resume rollouts of %s %s: %w", f.target.GetKind(), f.target.GetName(), err)
+	}
+	return nil
+}
+
+// awaitHealthy waits for the released workload to roll out the action's
+// changes, and returns an error if the rollout fails or does not complete
+// within rolloutVerifyTimeout. A Deployment that was paused before the
+// freeze stays paused and is not waited for.
+func (f *rolloutFreeze) awaitHealthy(ctx context.Context) error {
+	if f.target.GetKind() == "Deployment" && f.wasPaused {
+		return nil
+	}
+
+	var problem string
+	err := wait.PollUntilContextTimeout(ctx, rolloutVerifyInterval, rolloutVerifyTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := f.current(ctx)
+		if err != nil {
+			return false, err
+		}
+		var failed bool
+		problem, failed = rolloutProblem(current)
+		if failed {
+			return false, fmt.Errorf("%s", problem)
+		}
+		return problem == "", nil
+	})
+	if err != nil {
+		if problem == "" {
+			return fmt.Errorf("failed to verify the rollout of %s %s: %w", f.target.GetKind(), f.target.GetName(), err)
+		}
+		return fmt.Errorf("rollout of %s %s did not become healthy: %s", f.target.GetKind(), f.target.GetName(), problem)
+	}
+	return nil
+}
+
+// rolloutProblem returns what keeps a workload's rollout from being
+// complete and healthy, "" if nothing does, and whether the rollout failed
+// rather than still progressing
+func rolloutProblem(obj *unstructured.Unstructured) (string, bool) {
+	generation := obj.GetGeneration()
+	observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observed < generation {
+		return "the controller has not observed the change yet", false
+	}
+
+	replicas := int64(1)
+	if r, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		replicas = r
+	}
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	switch obj.GetKind() {
+	case "Deployment":
+		for _, c := range conditionsOf(obj) {
+			if c["type"] == "Progressing" && c["status"] == "False" && c["reason"] == "ProgressDeadlineExceeded" {
+				return "progress deadline exceeded", true
+			}
+		}
+		total, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+		switch {
+		case updated < replicas:
+			return fmt.Sprintf("%d of %d replicas updated", updated, replicas), false
+		case total > updated:
+			return fmt.Sprintf("%d old replicas pending termination", total-updated), false
+		case available < replicas:
+			return fmt.Sprintf("%d of %d replicas available", available, replicas), false
+		}
+	case "StatefulSet":
+		partition, _, _ := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		switch {
+		case partition < replicas && updated < replicas-partition:
+			return fmt.Sprintf("%d of %d replicas updated", updated, replicas-partition), false
+		case ready < replicas:
+			return fmt.Sprintf("%d of %d replicas ready", ready, replicas), false
+		}
+	}
+	return "", false
+}
+
+// conditionsOf returns the status conditions of an object
+func conditionsOf(obj *unstructured.Unstructured) []map[string]interface{} {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	conditions := make([]map[string]interface{}, 0, len(raw))
+	for _, c := range raw {
+		if condition, ok := c.(map[string]interface{}); ok {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+// patch merge-patches the frozen workload
+func (f *rolloutFreeze) patch(ctx context.Context, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(f.target.GroupVersionKind())
+	obj.SetNamespace(f.target.GetNamespace())
+	obj.SetName(f.target.GetName())
+	return f.client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+}
+
+// partitionPatch sets the rolling update partition of a StatefulSet
+func partitionPatch(partition interface{}) map[string]interface{} {
+	return map[string]interface{}{"spec": map[string]interface{}{
+		"updateStrategy": map[string]interface{}{
+			"rollingUpdate": map[string]interface{}{"partition": partition},
+		},
+	}}
+}
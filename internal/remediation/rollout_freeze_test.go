@@ -0,0 +1,219 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// annotateExecutor patches an annotation onto the target, recording whether
+// the target was frozen when it was changed
+func annotateExecutor(c client.Client, frozen *bool, fail bool) *MockExecutor {
+	return &MockExecutor{
+		ExecuteFunc: func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+			u := target.(*unstructured.Unstructured)
+			paused, _, _ := unstructured.NestedBool(u.Object, "spec", "paused")
+			partition, _, _ := unstructured.NestedInt64(u.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+			*frozen = paused || partition > 0
+
+			u.SetAnnotations(map[string]string{"healed": "true"})
+			if err := c.Update(ctx, u); err != nil {
+				return nil, err
+			}
+			if fail {
+				return &kubetypes.ActionResult{Success: false, Message: "patch rejected"}, nil
+			}
+			return &kubetypes.ActionResult{Success: true, Message: "patched"}, nil
+		},
+	}
+}
+
+func freezeAction(kind string) *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-patch", Namespace: "apps"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: kind, Name: "web", Namespace: "apps"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "patch", Type: "patch", FreezeRollout: true},
+		},
+	}
+}
+
+func TestEngine_FreezeRollout(t *testing.T) {
+	replicas := int32(3)
+	partition := int32(1)
+	deployment := func(paused bool) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Paused: paused},
+			Status:     appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+		}
+	}
+	stuck := deployment(false)
+	stuck.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+	}}
+	statefulSet := func(partition *int32) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       &replicas,
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+			},
+			Status: appsv1.StatefulSetStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3},
+		}
+		if partition != nil {
+			sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: partition}
+		}
+		return sts
+	}
+
+	tests := []struct {
+		name   string
+		kind   string
+		object client.Object
+		fail   bool
+		// resume simulates another tool resuming the workload mid-action
+		resume bool
+		// label simulates another tool labelling the workload once it is
+		// resumed, and relabel it also changing the annotation the action set
+		label         bool
+		relabel       bool
+		wantErr       string
+		wantHealed    bool
+		wantPaused    bool
+		wantPartition *int32
+	}{
+		{name: "deployment", kind: "Deployment", object: deployment(false), wantHealed: true},
+		{name: "paused deployment stays paused", kind: "Deployment", object: deployment(true), wantHealed: true, wantPaused: true},
+		{name: "statefulset", kind: "StatefulSet", object: statefulSet(nil), wantHealed: true},
+		{name: "statefulset partition restored", kind: "StatefulSet", object: statefulSet(&partition), wantHealed: true, wantPartition: &partition},
+		{name: "failed action rolled back", kind: "Deployment", object: deployment(false), fail: true, wantErr: "patch rejected; changes were rolled back"},
+		{name: "unhealthy rollout rolled back", kind: "Deployment", object: stuck, wantErr: "progress deadline exceeded; changes were rolled back"},
+		{name: "rollback keeps changes of others", kind: "Deployment", object: stuck, label: true, wantErr: "changes were rolled back"},
+		{name: "rollback skipped when the changes were changed", kind: "Deployment", object: stuck, relabel: true, wantErr: "rollback skipped: target diverged: metadata.annotations.healed was changed by someone else", wantHealed: true},
+		{name: "resumed by someone else", kind: "Deployment", object: deployment(false), resume: true, wantErr: "resumed by someone else", wantHealed: true},
+	}
+
+	interval, timeout := rolloutVerifyInterval, rolloutVerifyTimeout
+	rolloutVerifyInterval, rolloutVerifyTimeout = 10*time.Millisecond, 100*time.Millisecond
+	defer func() { rolloutVerifyInterval, rolloutVerifyTimeout = interval, timeout }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, appsv1.AddToScheme(scheme))
+			var c client.WithWatch
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.object).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if err := cl.Patch(ctx, obj, patch, opts...); err != nil {
+							return err
+						}
+						data, _ := patch.Data(obj)
+						if !(tt.label || tt.relabel) || string(data) != `{"spec":{"paused":false}}` {
+							return nil
+						}
+						current := &appsv1.Deployment{}
+						require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(obj), current))
+						current.Labels = map[string]string{"team": "web"}
+						if tt.relabel {
+							current.Annotations["healed"] = "by-hand"
+						}
+						return cl.Update(ctx, current)
+					},
+				}).Build()
+			engine := NewEngine(c, nil)
+
+			frozen := false
+			executor := annotateExecutor(c, &frozen, tt.fail)
+			if tt.resume {
+				execute := executor.ExecuteFunc
+				executor.ExecuteFunc = func(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+					result, err := execute(ctx, target, action)
+					current := &appsv1.Deployment{}
+					require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(target), current))
+					current.Spec.Paused = false
+					require.NoError(t, c.Update(ctx, current))
+					return result, err
+				}
+			}
+			engine.RegisterExecutor("patch", executor)
+
+			result, err := engine.ExecuteAction(context.Background(), freezeAction(tt.kind))
+			assert.True(t, frozen, "the executor changes the frozen workload")
+
+			key := client.ObjectKeyFromObject(tt.object)
+			var annotations map[string]string
+			switch tt.kind {
+			case "Deployment":
+				got := &appsv1.Deployment{}
+				require.NoError(t, c.Get(context.Background(), key, got))
+				assert.Equal(t, tt.wantPaused, got.Spec.Paused)
+				annotations = got.Annotations
+				if tt.label {
+					assert.Equal(t, "web", got.Labels["team"], "changes of others are kept")
+				}
+			case "StatefulSet":
+				got := &appsv1.StatefulSet{}
+				require.NoError(t, c.Get(context.Background(), key, got))
+				var gotPartition *int32
+				if got.Spec.UpdateStrategy.RollingUpdate != nil {
+					gotPartition = got.Spec.UpdateStrategy.RollingUpdate.Partition
+				}
+				assert.Equal(t, tt.wantPartition, gotPartition)
+				annotations = got.Annotations
+			}
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.False(t, result.Success)
+				assert.Equal(t, tt.wantHealed, annotations["healed"] != "", "changes are rolled back")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, result.Success)
+			assert.Equal(t, "true", result.Metrics["rollout_frozen"])
+			assert.Equal(t, "true", annotations["healed"])
+		})
+	}
+}
+
+func TestFreezeRollout_IgnoresOtherTargets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	onDelete := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+		Spec:       appsv1.StatefulSetSpec{UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(onDelete, pod).Build()
+
+	for _, obj := range []client.Object{onDelete, pod} {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		require.NoError(t, err)
+		u := &unstructured.Unstructured{Object: content}
+		gvks, _, err := scheme.ObjectKinds(obj)
+		require.NoError(t, err)
+		u.SetGroupVersionKind(gvks[0])
+
+		freeze, err := freezeRollout(context.Background(), c, u)
+		require.NoError(t, err)
+		assert.Nil(t, freeze, gvks[0].Kind)
+	}
+}
@@ -2,12 +2,17 @@ package remediation
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -18,6 +23,10 @@ import (
 // ScaleExecutor handles scale actions
 type ScaleExecutor struct {
 	client client.Client
+
+	// capacity checks node headroom before scale-ups, see
+	// SetCapacityChecker
+	capacity *CapacityChecker
 }
 
 // NewScaleExecutor creates a new scale executor
@@ -43,8 +52,8 @@ func (s *ScaleExecutor) Execute(ctx context.Context, target client.Object, actio
 		}, fmt.Errorf("scale action configuration is missing")
 	}
 
-	// Get current replicas
-	currentReplicas, err := s.getCurrentReplicas(target)
+	// Read the current replica count from the scale subresource
+	scale, err := s.getScale(ctx, target)
 	if err != nil {
 		return &kubetypes.ActionResult{
 			Success:   false,
@@ -54,35 +63,17 @@ func (s *ScaleExecutor) Execute(ctx context.Context, target client.Object, actio
 			EndTime:   time.Now(),
 		}, err
 	}
+	currentReplicas := scale.Spec.Replicas
 
 	// Calculate new replicas
-	newReplicas := currentReplicas
-	switch config.Direction {
-	case "up":
-		newReplicas = currentReplicas + config.Replicas
-		if config.MaxReplicas > 0 && newReplicas > config.MaxReplicas {
-			newReplicas = config.MaxReplicas
-		}
-	case "down":
-		newReplicas = currentReplicas - config.Replicas
-		if newReplicas < config.MinReplicas {
-			newReplicas = config.MinReplicas
-		}
-	case "absolute":
-		newReplicas = config.Replicas
-		if config.MaxReplicas > 0 && newReplicas > config.MaxReplicas {
-			newReplicas = config.MaxReplicas
-		}
-		if newReplicas < config.MinReplicas {
-			newReplicas = config.MinReplicas
-		}
-	default:
+	newReplicas, err := desiredReplicas(currentReplicas, config)
+	if err != nil {
 		return &kubetypes.ActionResult{
 			Success:   false,
-			Message:   fmt.Sprintf("Invalid scale direction: %s", config.Direction),
+			Message:   err.Error(),
 			StartTime: startTime,
 			EndTime:   time.Now(),
-		}, fmt.Errorf("invalid scale direction: %s", config.Direction)
+		}, err
 	}
 
 	// Check if scaling is needed
@@ -100,8 +91,27 @@ func (s *ScaleExecutor) Execute(ctx context.Context, target client.Object, actio
 		}, nil
 	}
 
+	// Do not create replicas the nodes have no room for
+	if s.capacity != nil && newReplicas > currentReplicas {
+		var blocked *CapacityBlockedError
+		if err := s.capacity.Check(ctx, target, newReplicas-currentReplicas); errors.As(err, &blocked) {
+			metrics := blocked.metrics()
+			metrics["current_replicas"] = fmt.Sprintf("%d", currentReplicas)
+			metrics["target_replicas"] = fmt.Sprintf("%d", newReplicas)
+			log.Info("Scale-up blocked by node capacity", "resource", fmt.Sprintf("%s/%s", target.GetNamespace(), target.GetName()), "reason", err.Error())
+			return &kubetypes.ActionResult{
+				Success:   false,
+				Message:   err.Error(),
+				Error:     err,
+				StartTime: startTime,
+				EndTime:   time.Now(),
+				Metrics:   metrics,
+			}, err
+		}
+	}
+
 	// Perform the scaling
-	changes, err := s.scaleResource(ctx, target, newReplicas)
+	changes, attempts, err := s.scaleResource(ctx, target, scale, newReplicas)
 	if err != nil {
 		return &kubetypes.ActionResult{
 			Success:   false,
@@ -125,23 +135,16 @@ func (s *ScaleExecutor) Execute(ctx context.Context, target client.Object, actio
 		StartTime: startTime,
 		EndTime:   time.Now(),
 		Metrics: map[string]string{
-			"previous_replicas": fmt.Sprintf("%d", currentReplicas),
-			"new_replicas":      fmt.Sprintf("%d", newReplicas),
-			"scale_direction":   config.Direction,
+			"previous_replicas":  fmt.Sprintf("%d", currentReplicas),
+			"new_replicas":       fmt.Sprintf("%d", newReplicas),
+			"scale_direction":    config.Direction,
+			MetricUpdateAttempts: fmt.Sprintf("%d", attempts),
 		},
 	}, nil
 }
 
 // Validate checks if the scale action can be executed
 func (s *ScaleExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
-	// Check if resource type is supported
-	switch target.(type) {
-	case *appsv1.Deployment, *appsv1.ReplicaSet, *appsv1.StatefulSet:
-		// Supported types
-	default:
-		return fmt.Errorf("scale not supported for resource type %T", target)
-	}
-
 	// Validate scale configuration
 	if action.ScaleAction == nil {
 		return fmt.Errorf("scale action configuration is missing")
@@ -170,6 +173,11 @@ func (s *ScaleExecutor) Validate(ctx context.Context, target client.Object, acti
 		return fmt.Errorf("replicas cannot be negative for absolute scaling")
 	}
 
+	// Any workload exposing /scale is supported, including custom resources
+	if _, err := s.getScale(ctx, target); err != nil {
+		return fmt.Errorf("scale not supported for %s: %w", resourceKind(target), err)
+	}
+
 	return nil
 }
 
@@ -186,53 +194,34 @@ func (s *ScaleExecutor) DryRun(ctx context.Context, target client.Object, action
 	config := action.ScaleAction
 
 	// Get current replicas
-	currentReplicas, err := s.getCurrentReplicas(target)
+	scale, err := s.getScale(ctx, target)
 	if err != nil {
 		return &kubetypes.ActionResult{
 			Success: false,
 			Message: fmt.Sprintf("Failed to get current replicas: %v", err),
 		}, err
 	}
+	currentReplicas := scale.Spec.Replicas
 
-	// Calculate new replicas
-	newReplicas := currentReplicas
-	switch config.Direction {
-	case "up":
-		newReplicas = currentReplicas + config.Replicas
-		if config.MaxReplicas > 0 && newReplicas > config.MaxReplicas {
-			newReplicas = config.MaxReplicas
-		}
-	case "down":
-		newReplicas = currentReplicas - config.Replicas
-		if newReplicas < config.MinReplicas {
-			newReplicas = config.MinReplicas
-		}
-	case "absolute":
-		newReplicas = config.Replicas
-	}
-
-	// Simulate changes
-	var resourceType string
-	switch target.(type) {
-	case *appsv1.Deployment:
-		resourceType = "Deployment"
-	case *appsv1.ReplicaSet:
-		resourceType = "ReplicaSet"
-	case *appsv1.StatefulSet:
-		resourceType = "StatefulSet"
+	newReplicas, err := desiredReplicas(currentReplicas, config)
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: err.Error(),
+		}, err
 	}
 
 	simulatedChanges := []v1alpha1.ResourceChange{
 		{
-			ResourceRef: fmt.Sprintf("%s/%s/%s", resourceType, target.GetNamespace(), target.GetName()),
-			ChangeType:  "update",
+			ResourceRef: fmt.Sprintf("%s/%s/%s", resourceKind(target), target.GetNamespace(), target.GetName()),
+			ChangeType:  "scale",
 			Field:       "spec.replicas",
 			OldValue:    fmt.Sprintf("%d", currentReplicas),
 			NewValue:    fmt.Sprintf("%d", newReplicas),
 		},
 	}
 
-	return &kubetypes.ActionResult{
+	result := &kubetypes.ActionResult{
 		Success: true,
 		Message: fmt.Sprintf("Dry-run: Would scale %s/%s from %d to %d replicas", target.GetNamespace(), target.GetName(), currentReplicas, newReplicas),
 		Changes: simulatedChanges,
@@ -242,76 +231,123 @@ func (s *ScaleExecutor) DryRun(ctx context.Context, target client.Object, action
 			"scale_direction":  config.Direction,
 			"dry_run":          "true",
 		},
-	}, nil
-}
+	}
 
-// getCurrentReplicas gets the current replica count for a resource
-func (s *ScaleExecutor) getCurrentReplicas(target client.Object) (int32, error) {
-	switch obj := target.(type) {
-	case *appsv1.Deployment:
-		if obj.Spec.Replicas == nil {
-			return 1, nil // Default for deployments
-		}
-		return *obj.Spec.Replicas, nil
-	case *appsv1.ReplicaSet:
-		if obj.Spec.Replicas == nil {
-			return 1, nil
-		}
-		return *obj.Spec.Replicas, nil
-	case *appsv1.StatefulSet:
-		if obj.Spec.Replicas == nil {
-			return 1, nil
+	// Report the capacity a real scale-up would be blocked by
+	if s.capacity != nil && newReplicas > currentReplicas {
+		var blocked *CapacityBlockedError
+		if err := s.capacity.Check(ctx, target, newReplicas-currentReplicas); errors.As(err, &blocked) {
+			result.Message += ", but " + blocked.Error()
+			for key, value := range blocked.metrics() {
+				result.Metrics[key] = value
+			}
 		}
-		return *obj.Spec.Replicas, nil
-	default:
-		return 0, fmt.Errorf("cannot get replicas for resource type %T", target)
 	}
+	return result, nil
 }
 
-// scaleResource performs the actual scaling operation
-func (s *ScaleExecutor) scaleResource(ctx context.Context, target client.Object, newReplicas int32) ([]v1alpha1.ResourceChange, error) {
-	log := log.FromContext(ctx)
-
-	currentReplicas, _ := s.getCurrentReplicas(target)
+// desiredReplicas applies the scale direction and the MinReplicas and
+// MaxReplicas bounds to the current replica count
+func desiredReplicas(current int32, config *v1alpha1.ScaleAction) (int32, error) {
+	var desired int32
+	switch config.Direction {
+	case "up":
+		desired = current + config.Replicas
+	case "down":
+		desired = current - config.Replicas
+	case "absolute":
+		desired = config.Replicas
+	default:
+		return current, fmt.Errorf("invalid scale direction: %s", config.Direction)
+	}
 
-	// Create change record
-	var resourceType string
-	var changes []v1alpha1.ResourceChange
+	if config.MaxReplicas > 0 && desired > config.MaxReplicas {
+		desired = config.MaxReplicas
+	}
+	if desired < config.MinReplicas {
+		desired = config.MinReplicas
+	}
+	if desired < 0 {
+		desired = 0
+	}
+	return desired, nil
+}
 
-	switch obj := target.(type) {
-	case *appsv1.Deployment:
-		resourceType = "Deployment"
-		obj.Spec.Replicas = &newReplicas
-		if err := s.client.Update(ctx, obj); err != nil {
-			return nil, fmt.Errorf("failed to update deployment: %w", err)
+// getScale reads the scale subresource of the target. Unstructured targets,
+// as loaded by the engine, need an unstructured subresource body.
+func (s *ScaleExecutor) getScale(ctx context.Context, target client.Object) (*autoscalingv1.Scale, error) {
+	if _, ok := target.(*unstructured.Unstructured); !ok {
+		scale := &autoscalingv1.Scale{}
+		if err := s.client.SubResource("scale").Get(ctx, target, scale); err != nil {
+			return nil, err
 		}
+		return scale, nil
+	}
 
-	case *appsv1.ReplicaSet:
-		resourceType = "ReplicaSet"
-		obj.Spec.Replicas = &newReplicas
-		if err := s.client.Update(ctx, obj); err != nil {
-			return nil, fmt.Errorf("failed to update replicaset: %w", err)
-		}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+	if err := s.client.SubResource("scale").Get(ctx, target, u); err != nil {
+		return nil, err
+	}
+	scale := &autoscalingv1.Scale{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, scale); err != nil {
+		return nil, fmt.Errorf("failed to decode scale subresource: %w", err)
+	}
+	return scale, nil
+}
 
-	case *appsv1.StatefulSet:
-		resourceType = "StatefulSet"
-		obj.Spec.Replicas = &newReplicas
-		if err := s.client.Update(ctx, obj); err != nil {
-			return nil, fmt.Errorf("failed to update statefulset: %w", err)
+// updateScale writes the scale subresource of the target
+func (s *ScaleExecutor) updateScale(ctx context.Context, target client.Object, scale *autoscalingv1.Scale) error {
+	var body client.Object = scale
+	if _, ok := target.(*unstructured.Unstructured); ok {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(scale)
+		if err != nil {
+			return fmt.Errorf("failed to encode scale subresource: %w", err)
 		}
+		u := &unstructured.Unstructured{Object: obj}
+		u.SetGroupVersionKind(autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+		body = u
+	}
+	return s.client.SubResource("scale").Update(ctx, target, client.WithSubResourceBody(body))
+}
 
-	default:
-		return nil, fmt.Errorf("unsupported resource type for scaling: %T", target)
+// SetReplicas sets the replica count of the target through its scale
+// subresource; it is used to roll back scale actions
+func (s *ScaleExecutor) SetReplicas(ctx context.Context, target client.Object, replicas int32) error {
+	scale, err := s.getScale(ctx, target)
+	if err != nil {
+		return fmt.Errorf("failed to get scale of %s: %w", resourceKind(target), err)
+	}
+	if scale.Spec.Replicas == replicas {
+		return nil
+	}
+	if _, err := s.writeReplicas(ctx, target, scale, replicas); err != nil {
+		return fmt.Errorf("failed to scale %s: %w", resourceKind(target), err)
+	}
+	return nil
+}
+
+// scaleResource performs the actual scaling operation
+func (s *ScaleExecutor) scaleResource(ctx context.Context, target client.Object, scale *autoscalingv1.Scale, newReplicas int32) ([]v1alpha1.ResourceChange, int, error) {
+	log := log.FromContext(ctx)
+
+	resourceType := resourceKind(target)
+	currentReplicas := scale.Spec.Replicas
+
+	attempts, err := s.writeReplicas(ctx, target, scale, newReplicas)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to update %s scale: %w", strings.ToLower(resourceType), err)
 	}
 
-	changes = append(changes, v1alpha1.ResourceChange{
+	// The prior replica count is kept in the change record for rollback
+	changes := []v1alpha1.ResourceChange{{
 		ResourceRef: fmt.Sprintf("%s/%s/%s", resourceType, target.GetNamespace(), target.GetName()),
-		ChangeType:  "update",
+		ChangeType:  "scale",
 		Field:       "spec.replicas",
 		OldValue:    fmt.Sprintf("%d", currentReplicas),
 		NewValue:    fmt.Sprintf("%d", newReplicas),
 		Timestamp:   &metav1.Time{Time: time.Now()},
-	})
+	}}
 
 	log.Info("Scaled resource",
 		"type", resourceType,
@@ -323,7 +359,30 @@ func (s *ScaleExecutor) scaleResource(ctx context.Context, target client.Object,
 	// Check if HPA exists and might interfere
 	s.checkHPA(ctx, target)
 
-	return changes, nil
+	return changes, attempts, nil
+}
+
+// writeReplicas updates the scale subresource, re-reading it when the
+// update conflicts. It returns the number of attempts made.
+func (s *ScaleExecutor) writeReplicas(ctx context.Context, target client.Object, scale *autoscalingv1.Scale, replicas int32) (int, error) {
+	attempts := 0
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// A cancelled attempt stops between retries
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		attempts++
+		if attempts > 1 {
+			fresh, err := s.getScale(ctx, target)
+			if err != nil {
+				return err
+			}
+			*scale = *fresh
+		}
+		scale.Spec.Replicas = replicas
+		return s.updateScale(ctx, target, scale)
+	})
+	return attempts, err
 }
 
 // checkHPA checks if there's an HPA that might interfere with manual scaling
@@ -340,8 +399,7 @@ func (s *ScaleExecutor) checkHPA(ctx context.Context, target client.Object) {
 	// Check if any HPA targets this resource
 	for _, hpa := range hpaList.Items {
 		if hpa.Spec.ScaleTargetRef.Name == target.GetName() {
-			gvk := target.GetObjectKind().GroupVersionKind()
-			if hpa.Spec.ScaleTargetRef.Kind == gvk.Kind {
+			if hpa.Spec.ScaleTargetRef.Kind == resourceKind(target) {
 				log.Info("Warning: HPA exists for this resource and may override manual scaling",
 					"hpa", hpa.Name,
 					"resource", target.GetName())
@@ -349,3 +407,12 @@ func (s *ScaleExecutor) checkHPA(ctx context.Context, target client.Object) {
 		}
 	}
 }
+
+// resourceKind returns the kind of an object, falling back to its Go type
+// name for typed objects without TypeMeta
+func resourceKind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
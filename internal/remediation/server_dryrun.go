@@ -0,0 +1,300 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// ignoredDiffFields are left out of server-side dry-run diffs: they change
+// on every write or are not written by actions
+var ignoredDiffFields = map[string]bool{
+	"metadata.managedFields":   true,
+	"metadata.resourceVersion": true,
+	"status":                   true,
+}
+
+// newServerDryRunExecutor returns a built-in executor for the action type
+// that writes through c, or nil if the type has none. Custom executors
+// cannot be redirected and keep their own DryRun.
+func newServerDryRunExecutor(actionType string, c client.Client) kubetypes.ActionExecutor {
+	switch actionType {
+	case "restart":
+		// Nothing is deleted or drained, so there is nothing to wait for
+		executor := NewRestartExecutor(c)
+		executor.sleep = skipSleep
+		return executor
+	case "scale":
+		return NewScaleExecutor(c)
+	case "patch":
+		return NewPatchExecutor(c)
+	case "delete":
+		return NewDeleteExecutor(c)
+	case "finalizer":
+		return NewFinalizerExecutor(c)
+	case "hibernate":
+		return NewHibernateExecutor(c)
+	case "resize":
+		return NewResizeExecutor(c)
+	}
+	return nil
+}
+
+// supportsServerDryRun reports whether an action can be dry-run against the
+// API server. Exec actions run commands in pods, which has no dry-run.
+func supportsServerDryRun(action *v1alpha1.HealingActionTemplate) bool {
+	return newServerDryRunExecutor(action.Type, nil) != nil
+}
+
+// serverSideDryRun executes the action through c with every write sent as
+// a dry run, so admission webhooks, quota and validation run without
+// persisting anything. The result's changes are the differences between
+// the live objects and the objects the API server returned, recorded with
+// the type of the write that would have made them.
+func serverSideDryRun(ctx context.Context, c client.Client, action *v1alpha1.HealingAction, target client.Object) (*kubetypes.ActionResult, error) {
+	log.FromContext(ctx).Info("Performing server-side dry-run", "action", action.Name)
+
+	recorder := newDryRunRecorder(c)
+	executor := newServerDryRunExecutor(action.Spec.Action.Type, recorder)
+	result, err := executor.Execute(ctx, target.DeepCopyObject().(client.Object), &action.Spec.Action)
+	if result == nil {
+		result = &kubetypes.ActionResult{}
+	}
+	if changes := recorder.Changes(); len(changes) > 0 {
+		result.Changes = changes
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("API server rejected the dry run: %v", err)
+		return result, err
+	}
+	result.Message = fmt.Sprintf("Server-side dry run: %s", result.Message)
+	return result, nil
+}
+
+// dryRunRecorder sends every write as a dry run and records how the
+// objects the API server returned differ from the live objects
+type dryRunRecorder struct {
+	client.Client
+	reader client.Client
+
+	mu      sync.Mutex
+	changes []v1alpha1.ResourceChange
+}
+
+// newDryRunRecorder creates a recorder writing through c with dryRun=All
+func newDryRunRecorder(c client.Client) *dryRunRecorder {
+	return &dryRunRecorder{Client: client.NewDryRunClient(c), reader: c}
+}
+
+// Changes returns the recorded changes
+func (r *dryRunRecorder) Changes() []v1alpha1.ResourceChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]v1alpha1.ResourceChange(nil), r.changes...)
+}
+
+// Update records the changes the server made to obj
+func (r *dryRunRecorder) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	live := r.live(ctx, obj)
+	if err := r.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	r.recordDiff(obj, "update", live, obj)
+	return nil
+}
+
+// Patch records the changes the server made to obj
+func (r *dryRunRecorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	live := r.live(ctx, obj)
+	if err := r.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	r.recordDiff(obj, "patch", live, obj)
+	return nil
+}
+
+// Create records the creation of obj
+func (r *dryRunRecorder) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := r.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	r.record(obj, "create", "", "", "")
+	return nil
+}
+
+// Delete records the deletion of obj
+func (r *dryRunRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := r.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	r.record(obj, "delete", "", "", "")
+	return nil
+}
+
+// SubResource records the changes the server made to subresources
+func (r *dryRunRecorder) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceRecorder{
+		SubResourceClient: r.Client.SubResource(subResource),
+		reader:            r.reader.SubResource(subResource),
+		recorder:          r,
+		subResource:       subResource,
+	}
+}
+
+// live returns a copy of the object as currently stored, or nil if it
+// cannot be read
+func (r *dryRunRecorder) live(ctx context.Context, obj client.Object) client.Object {
+	live := obj.DeepCopyObject().(client.Object)
+	if err := r.reader.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		return nil
+	}
+	return live
+}
+
+// recordDiff records the fields that differ between before and after as
+// changes of the given type
+func (r *dryRunRecorder) recordDiff(obj client.Object, changeType string, before, after runtime.Object) {
+	if before == nil {
+		return
+	}
+	beforeMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(before)
+	if err != nil {
+		return
+	}
+	afterMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(after)
+	if err != nil {
+		return
+	}
+	for _, d := range diffFields("", beforeMap, afterMap) {
+		r.record(obj, changeType, d.field, d.oldValue, d.newValue)
+	}
+}
+
+// record adds a change of obj
+func (r *dryRunRecorder) record(obj client.Object, changeType, field, oldValue, newValue string) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvk, err := apiutil.GVKForObject(obj, r.Scheme()); err == nil {
+			kind = gvk.Kind
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, v1alpha1.ResourceChange{
+		ResourceRef: fmt.Sprintf("%s/%s/%s", kind, obj.GetNamespace(), obj.GetName()),
+		ChangeType:  changeType,
+		Field:       field,
+		OldValue:    oldValue,
+		NewValue:    newValue,
+		Timestamp:   &metav1.Time{Time: time.Now()},
+	})
+}
+
+// dryRunSubResourceRecorder records the changes the server made to a
+// subresource
+type dryRunSubResourceRecorder struct {
+	client.SubResourceClient
+	reader      client.SubResourceClient
+	recorder    *dryRunRecorder
+	subResource string
+}
+
+// Update records the changes the server made to the subresource body
+func (s *dryRunSubResourceRecorder) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	body := obj
+	if updateOpts := (&client.SubResourceUpdateOptions{}).ApplyOptions(opts); updateOpts.SubResourceBody != nil {
+		body = updateOpts.SubResourceBody
+	}
+	live := body.DeepCopyObject().(client.Object)
+	if err := s.reader.Get(ctx, obj, live); err != nil {
+		live = nil
+	}
+
+	if err := s.SubResourceClient.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if live != nil {
+		changeType := "update"
+		if s.subResource == "scale" {
+			changeType = "scale"
+		}
+		s.recorder.recordDiff(obj, changeType, live, body)
+	}
+	return nil
+}
+
+// fieldDiff is a field whose value differs
+type fieldDiff struct {
+	field    string
+	oldValue string
+	newValue string
+}
+
+// diffFields returns the fields that differ between two unstructured
+// objects, descending into nested objects. Lists are compared as a whole.
+func diffFields(prefix string, before, after map[string]interface{}) []fieldDiff {
+	keys := make(map[string]bool, len(before)+len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var diffs []fieldDiff
+	for _, key := range sorted {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		if ignoredDiffFields[field] {
+			continue
+		}
+		oldValue, newValue := before[key], after[key]
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffs = append(diffs, diffFields(field, oldMap, newMap)...)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diffs = append(diffs, fieldDiff{field: field, oldValue: diffValue(oldValue), newValue: diffValue(newValue)})
+		}
+	}
+	return diffs
+}
+
+// diffValue formats a field value for a change record
+func diffValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,192 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func serverDryRunAction() *v1alpha1.HealingAction {
+	return &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "tune", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default"},
+			DryRun:         true,
+			Action: v1alpha1.HealingActionTemplate{
+				Name: "tune",
+				Type: "patch",
+				PatchAction: &v1alpha1.PatchAction{
+					Type:    "merge",
+					Patches: []v1alpha1.PatchOperation{{Path: []string{"spec", "replicas"}, Value: "5"}},
+				},
+			},
+		},
+	}
+}
+
+func TestEngine_ServerSideDryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	var dryRunUpdates int
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(createUnstructuredDeployment("web", "default")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				options := (&client.UpdateOptions{}).ApplyOptions(opts)
+				if len(options.DryRun) == 1 && options.DryRun[0] == metav1.DryRunAll {
+					dryRunUpdates++
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	engine := NewEngine(c, nil)
+	engine.SetServerDryRun(c)
+
+	result, err := engine.DryRun(context.Background(), serverDryRunAction())
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, dryRunUpdates)
+	assert.Equal(t, "server", result.Metrics["dry_run_mode"])
+	assert.Contains(t, result.Message, "Server-side dry run")
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, "Deployment/default/web", result.Changes[0].ResourceRef)
+	assert.Equal(t, "update", result.Changes[0].ChangeType)
+	assert.Equal(t, "spec.replicas", result.Changes[0].Field)
+	assert.Equal(t, "3", result.Changes[0].OldValue)
+	assert.Equal(t, "5", result.Changes[0].NewValue)
+
+	// Nothing was persisted
+	stored := &unstructured.Unstructured{}
+	stored.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "web"}, stored))
+	replicas, _, _ := unstructured.NestedInt64(stored.Object, "spec", "replicas")
+	assert.Equal(t, int64(3), replicas)
+}
+
+func TestEngine_ServerSideDryRunRejected(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(createUnstructuredDeployment("web", "default")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				return apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, obj.GetName(),
+					assert.AnError)
+			},
+		}).
+		Build()
+	engine := NewEngine(c, nil)
+	engine.SetServerDryRun(c)
+
+	result, err := engine.DryRun(context.Background(), serverDryRunAction())
+	require.Error(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "API server rejected the dry run")
+}
+
+func TestSupportsServerDryRun(t *testing.T) {
+	assert.True(t, supportsServerDryRun(&v1alpha1.HealingActionTemplate{Type: "scale"}))
+	assert.True(t, supportsServerDryRun(&v1alpha1.HealingActionTemplate{Type: "restart", RestartAction: &v1alpha1.RestartAction{}}))
+	assert.True(t, supportsServerDryRun(&v1alpha1.HealingActionTemplate{Type: "restart",
+		RestartAction: &v1alpha1.RestartAction{Containers: []string{"app"}}}))
+	assert.True(t, supportsServerDryRun(&v1alpha1.HealingActionTemplate{Type: "resize"}))
+	assert.False(t, supportsServerDryRun(&v1alpha1.HealingActionTemplate{Type: "exec"}))
+	assert.False(t, supportsServerDryRun(&v1alpha1.HealingActionTemplate{Type: "custom"}))
+}
+
+func TestDiffFields(t *testing.T) {
+	before := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "resourceVersion": "1", "generation": int64(1)},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{"metadata": map[string]interface{}{}},
+		},
+		"status": map[string]interface{}{"replicas": int64(3)},
+	}
+	after := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "resourceVersion": "2", "generation": int64(2)},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{"metadata": map[string]interface{}{
+				"annotations": map[string]interface{}{"restartedAt": "now"},
+			}},
+		},
+		"status": map[string]interface{}{"replicas": int64(0)},
+	}
+
+	assert.Equal(t, []fieldDiff{
+		{field: "metadata.generation", oldValue: "1", newValue: "2"},
+		{field: "spec.template.metadata.annotations", oldValue: "", newValue: `{"restartedAt":"now"}`},
+	}, diffFields("", before, after))
+}
+
+func TestEngine_ServerSideDryRunSkipsMeshDrain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers:     []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}},
+			ReadinessGates: []corev1.PodReadinessGate{{ConditionType: DefaultMeshReadinessGate}},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: DefaultMeshReadinessGate, Status: corev1.ConditionTrue}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).WithStatusSubresource(pod).Build()
+	engine := NewEngine(c, nil)
+	engine.SetServerDryRun(c)
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Pod", Name: "web-1", Namespace: "default"},
+			DryRun:         true,
+			Action: v1alpha1.HealingActionTemplate{
+				Name: "restart",
+				Type: "restart",
+				RestartAction: &v1alpha1.RestartAction{
+					Strategy: "rolling",
+					Mesh:     &v1alpha1.MeshRestart{DrainSeconds: 600},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := engine.DryRun(ctx, action)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.NoError(t, ctx.Err(), "the drain should not be waited for")
+
+	// The pod was neither drained nor deleted
+	stored := &corev1.Pod{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pod), stored))
+	require.Len(t, stored.Status.Conditions, 1)
+	assert.Equal(t, corev1.ConditionTrue, stored.Status.Conditions[0].Status)
+	for _, change := range result.Changes {
+		assert.Contains(t, []string{"create", "update", "delete", "patch", "scale"}, change.ChangeType)
+	}
+}
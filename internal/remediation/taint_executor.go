@@ -0,0 +1,212 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// Taint defaults for taint actions that do not set them
+const (
+	DefaultTaintKey    = "kubeskippy.io/suspected-problem"
+	DefaultTaintEffect = corev1.TaintEffectPreferNoSchedule
+)
+
+// TaintExecutor taints nodes suspected of a problem. The action controller
+// waits for the taint action's window and then removes the taint through
+// Rollback, or escalates by creating a drain action.
+type TaintExecutor struct {
+	client client.Client
+}
+
+// NewTaintExecutor creates a new taint executor
+func NewTaintExecutor(client client.Client) *TaintExecutor {
+	return &TaintExecutor{
+		client: client,
+	}
+}
+
+// NodeTaint returns the taint a taint action applies
+func NodeTaint(config *v1alpha1.TaintAction) corev1.Taint {
+	taint := corev1.Taint{Key: DefaultTaintKey, Effect: DefaultTaintEffect}
+	if config == nil {
+		return taint
+	}
+	if config.Key != "" {
+		taint.Key = config.Key
+	}
+	if config.Effect != "" {
+		taint.Effect = corev1.TaintEffect(config.Effect)
+	}
+	return taint
+}
+
+// Execute adds the action's taint to the target node
+func (t *TaintExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	startTime := time.Now()
+	node := &corev1.Node{}
+	if err := t.getNode(ctx, target, action, node); err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Validation failed: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	taint := NodeTaint(action.TaintAction)
+	owner := ownerAction(ctx)
+	attempts, err := retryOnConflict(ctx, t.client, node, func() error {
+		if hasTaint(node, taint.Key) {
+			return nil
+		}
+		node.Spec.Taints = append(node.Spec.Taints, taint)
+		// The janitor removes the taint if its action goes away
+		if owner != "" {
+			owners := nodeTaintOwners(node)
+			owners[taint.Key] = owner
+			setNodeTaintOwners(node, owners)
+		}
+		return t.client.Update(ctx, node)
+	})
+	if err != nil {
+		return &kubetypes.ActionResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to taint node: %v", err),
+			Error:     err,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+		}, err
+	}
+
+	log.FromContext(ctx).Info("Tainted suspected node", "node", node.Name, "taint", taint.ToString())
+
+	now := metav1.Now()
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: fmt.Sprintf("Tainted node %s with %s", node.Name, taint.ToString()),
+		Changes: []v1alpha1.ResourceChange{{
+			ResourceRef: fmt.Sprintf("Node//%s", node.Name),
+			ChangeType:  "update",
+			Field:       "spec.taints",
+			NewValue:    taint.ToString(),
+			Timestamp:   &now,
+		}},
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Metrics: map[string]string{
+			"taint_key":          taint.Key,
+			"taint_effect":       string(taint.Effect),
+			MetricUpdateAttempts: fmt.Sprintf("%d", attempts),
+		},
+	}, nil
+}
+
+// Validate checks that the target is a node without the action's taint
+func (t *TaintExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	return t.getNode(ctx, target, action, &corev1.Node{})
+}
+
+// DryRun reports the taint that would be added
+func (t *TaintExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*kubetypes.ActionResult, error) {
+	if err := t.Validate(ctx, target, action); err != nil {
+		return &kubetypes.ActionResult{
+			Success: false,
+			Message: fmt.Sprintf("Validation failed: %v", err),
+		}, err
+	}
+
+	taint := NodeTaint(action.TaintAction)
+	return &kubetypes.ActionResult{
+		Success: true,
+		Message: fmt.Sprintf("Dry-run: Would taint node %s with %s", target.GetName(), taint.ToString()),
+		Changes: []v1alpha1.ResourceChange{{
+			ResourceRef: fmt.Sprintf("Node//%s", target.GetName()),
+			ChangeType:  "update",
+			Field:       "spec.taints",
+			NewValue:    taint.ToString(),
+		}},
+		Metrics: map[string]string{
+			"taint_key":    taint.Key,
+			"taint_effect": string(taint.Effect),
+		},
+	}, nil
+}
+
+// getNode reads the target node into node, checking that the action can
+// taint it
+func (t *TaintExecutor) getNode(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate, node *corev1.Node) error {
+	if action.TaintAction == nil {
+		return fmt.Errorf("taint action missing configuration")
+	}
+	if kind := target.GetObjectKind().GroupVersionKind().Kind; kind != "Node" {
+		return fmt.Errorf("taint actions target nodes, not %s", kind)
+	}
+
+	if err := t.client.Get(ctx, client.ObjectKey{Name: target.GetName()}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("node not found")
+		}
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+
+	key := NodeTaint(action.TaintAction).Key
+	if hasTaint(node, key) {
+		return fmt.Errorf("node %s is already tainted with %s", node.Name, key)
+	}
+	return nil
+}
+
+// hasTaint reports whether the node has a taint with the key
+func hasTaint(node *corev1.Node, key string) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTaint removes the taint with the key from the node. A node that no
+// longer exists has nothing to remove.
+func removeTaint(ctx context.Context, c client.Client, name, key string) error {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+
+	_, err := retryOnConflict(ctx, c, node, func() error {
+		taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+		for _, taint := range node.Spec.Taints {
+			if taint.Key != key {
+				taints = append(taints, taint)
+			}
+		}
+		owners := nodeTaintOwners(node)
+		_, tracked := owners[key]
+		if len(taints) == len(node.Spec.Taints) && !tracked {
+			return nil
+		}
+		node.Spec.Taints = taints
+		delete(owners, key)
+		setNodeTaintOwners(node, owners)
+		return c.Update(ctx, node)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove taint %s from node %s: %w", key, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func taintScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+	return scheme
+}
+
+func suspectNode(taints ...corev1.Taint) *corev1.Node {
+	return &corev1.Node{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Spec:       corev1.NodeSpec{Taints: taints},
+	}
+}
+
+func TestTaintExecutor(t *testing.T) {
+	existing := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name          string
+		target        client.Object
+		config        *v1alpha1.TaintAction
+		expectedError string
+		wantTaints    []corev1.Taint
+	}{
+		{
+			name:       "defaults",
+			target:     suspectNode(existing),
+			config:     &v1alpha1.TaintAction{},
+			wantTaints: []corev1.Taint{existing, {Key: DefaultTaintKey, Effect: corev1.TaintEffectPreferNoSchedule}},
+		},
+		{
+			name:       "custom taint",
+			target:     suspectNode(),
+			config:     &v1alpha1.TaintAction{Key: "example.com/flaky", Effect: "NoSchedule"},
+			wantTaints: []corev1.Taint{{Key: "example.com/flaky", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		{
+			name:          "already tainted",
+			target:        suspectNode(corev1.Taint{Key: DefaultTaintKey, Effect: corev1.TaintEffectNoSchedule}),
+			config:        &v1alpha1.TaintAction{},
+			expectedError: "already tainted",
+			wantTaints:    []corev1.Taint{{Key: DefaultTaintKey, Effect: corev1.TaintEffectNoSchedule}},
+		},
+		{
+			name:          "missing configuration",
+			target:        suspectNode(),
+			expectedError: "missing configuration",
+		},
+		{
+			name: "not a node",
+			target: &corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Namespace: "apps"},
+			},
+			config:        &v1alpha1.TaintAction{},
+			expectedError: "target nodes, not Pod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(taintScheme(t)).WithObjects(tt.target).Build()
+			executor := NewTaintExecutor(c)
+			action := &v1alpha1.HealingActionTemplate{Type: "taint", TaintAction: tt.config}
+
+			dryRun, dryRunErr := executor.DryRun(context.Background(), tt.target, action)
+			result, err := executor.Execute(context.Background(), tt.target, action)
+			if tt.expectedError != "" {
+				require.Error(t, dryRunErr)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.False(t, result.Success)
+			} else {
+				require.NoError(t, dryRunErr)
+				require.NoError(t, err)
+				assert.True(t, result.Success)
+				require.Len(t, result.Changes, 1)
+				assert.Equal(t, "spec.taints", result.Changes[0].Field)
+				assert.Equal(t, dryRun.Changes[0].NewValue, result.Changes[0].NewValue)
+			}
+
+			if _, isNode := tt.target.(*corev1.Node); isNode {
+				got := &corev1.Node{}
+				require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "worker-1"}, got))
+				assert.Equal(t, tt.wantTaints, got.Spec.Taints)
+			}
+		})
+	}
+}
+
+func TestEngine_TaintRollbackAndDrain(t *testing.T) {
+	scheme := taintScheme(t)
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	taint := corev1.Taint{Key: DefaultTaintKey, Effect: corev1.TaintEffectPreferNoSchedule}
+	pod := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	daemon := pod("agent", "worker-1")
+	daemon.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent", Controller: boolPtr(true)}}
+	mirror := pod("etcd", "worker-1")
+	mirror.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+	done := pod("job", "worker-1")
+	done.Status.Phase = corev1.PodSucceeded
+
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1-taint"},
+		Spec: v1alpha1.HealingActionSpec{
+			TargetResource: v1alpha1.TargetResource{APIVersion: "v1", Kind: "Node", Name: "worker-1"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "suspect", Type: "taint", TaintAction: &v1alpha1.TaintAction{}},
+		},
+	}
+
+	t.Run("rollback removes the taint", func(t *testing.T) {
+		other := corev1.Taint{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(suspectNode(other, taint)).Build()
+		require.NoError(t, NewEngine(c, nil).Rollback(context.Background(), action))
+
+		got := &corev1.Node{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "worker-1"}, got))
+		assert.Equal(t, []corev1.Taint{other}, got.Spec.Taints)
+	})
+
+	t.Run("rollback of a removed node", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		assert.NoError(t, NewEngine(c, nil).Rollback(context.Background(), action))
+	})
+
+	t.Run("drain cordons and evicts", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(suspectNode(taint), pod("web-1", "worker-1"), pod("web-2", "worker-2"), daemon, mirror, done).
+			Build()
+		target := suspectNode(taint)
+		target.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Node"))
+		drain := &v1alpha1.HealingActionTemplate{Name: "suspect-drain", Type: "drain"}
+		preview, err := NewDrainExecutor(c).DryRun(context.Background(), target, drain)
+		require.NoError(t, err)
+		assert.Equal(t, "1", preview.Metrics["evicted_pods"])
+
+		result, err := NewDrainExecutor(c).Execute(context.Background(), target, drain)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "1", result.Metrics["evicted_pods"])
+
+		node := &corev1.Node{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "worker-1"}, node))
+		assert.True(t, node.Spec.Unschedulable)
+		assert.Equal(t, []corev1.Taint{taint}, node.Spec.Taints, "the taint is kept")
+
+		err = c.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web-1"}, &corev1.Pod{})
+		assert.True(t, apierrors.IsNotFound(err), "pods on the node are evicted")
+		for _, name := range []string{"web-2", "agent", "etcd", "job"} {
+			assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: name}, &corev1.Pod{}), name)
+		}
+	})
+
+	t.Run("only nodes drain", func(t *testing.T) {
+		target := pod("web-1", "worker-1")
+		target.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(target).Build()
+		_, err := NewDrainExecutor(c).Execute(context.Background(), target,
+			&v1alpha1.HealingActionTemplate{Name: "suspect-drain", Type: "drain"})
+		assert.Error(t, err)
+	})
+}
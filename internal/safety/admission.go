@@ -0,0 +1,46 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// ValidateAdmission runs the checks of ValidateAction that only depend on
+// the action and the safety configuration. Cooldowns, circuit breakers and
+// rate limits change over time and are left to ValidateAction.
+func (c *Controller) ValidateAdmission(ctx context.Context, action *v1alpha1.HealingAction) (*kubetypes.ValidationResult, error) {
+	result := &kubetypes.ValidationResult{
+		Valid:    true,
+		Warnings: []string{},
+	}
+
+	reject := func(reason string) (*kubetypes.ValidationResult, error) {
+		result.Valid = false
+		result.Reason = reason
+		c.auditLogger.LogValidation(ctx, action, false, reason)
+		return result, nil
+	}
+
+	if c.config.DryRunMode && !action.Spec.DryRun {
+		return reject("System is in dry-run mode only")
+	}
+
+	target, err := c.getTargetResource(ctx, action)
+	if err != nil {
+		return reject(fmt.Sprintf("Failed to get target resource: %v", err))
+	}
+	if protected, reason := c.IsProtectedResource(target); protected {
+		return reject(fmt.Sprintf("Resource is protected: %s", reason))
+	}
+	if err := c.validateActionType(action, target); err != nil {
+		return reject(err.Error())
+	}
+
+	if action.Spec.Action.Type == "delete" {
+		result.Warnings = append(result.Warnings, "Delete operations are potentially destructive")
+	}
+	return result, nil
+}
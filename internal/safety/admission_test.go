@@ -0,0 +1,122 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestController_ValidateAdmission(t *testing.T) {
+	action := func(namespace, kind, actionType string) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-action", Namespace: namespace},
+			Spec: v1alpha1.HealingActionSpec{
+				TargetResource: v1alpha1.TargetResource{Kind: kind, Name: "target", Namespace: namespace},
+				Action:         v1alpha1.HealingActionTemplate{Name: actionType, Type: actionType},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		config      config.SafetyConfig
+		action      *v1alpha1.HealingAction
+		wantValid   bool
+		wantReason  string
+		wantWarning string
+	}{
+		{
+			name:      "valid restart",
+			action:    action("default", "Pod", "restart"),
+			wantValid: true,
+		},
+		{
+			name:       "protected namespace",
+			config:     config.SafetyConfig{ProtectedNamespaces: []string{"kube-system"}},
+			action:     action("kube-system", "Pod", "restart"),
+			wantReason: "Resource is protected: namespace kube-system is protected",
+		},
+		{
+			name:       "deleting persistent volumes",
+			action:     action("default", "PersistentVolume", "delete"),
+			wantReason: "deleting PersistentVolumes is not allowed",
+		},
+		{
+			name:       "scale without configuration",
+			action:     action("default", "Deployment", "scale"),
+			wantReason: "scale action missing configuration",
+		},
+		{
+			name:       "dry-run mode",
+			config:     config.SafetyConfig{DryRunMode: true},
+			action:     action("default", "Pod", "restart"),
+			wantReason: "System is in dry-run mode only",
+		},
+		{
+			name:        "delete is allowed with a warning",
+			action:      action("default", "Pod", "delete"),
+			wantValid:   true,
+			wantWarning: "Delete operations are potentially destructive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &MockAuditLogger{}
+			c := NewController(nil, tt.config, nil, logger)
+
+			result, err := c.ValidateAdmission(context.Background(), tt.action)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValid, result.Valid)
+			assert.Equal(t, tt.wantReason, result.Reason)
+			if tt.wantWarning != "" {
+				assert.Contains(t, result.Warnings, tt.wantWarning)
+			}
+			if !tt.wantValid {
+				require.Len(t, logger.Validations, 1)
+				assert.False(t, logger.Validations[0].Valid)
+			}
+		})
+	}
+}
+
+func TestController_ValidateAdmission_IgnoresRuntimeState(t *testing.T) {
+	c := NewController(nil, config.SafetyConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 2, SuccessThreshold: 1, Timeout: time.Hour},
+	}, nil, &MockAuditLogger{})
+	action := &v1alpha1.HealingAction{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-action", Namespace: "default"},
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef:      v1alpha1.PolicyReference{Name: "test-policy", Namespace: "default"},
+			TargetResource: v1alpha1.TargetResource{Kind: "Pod", Name: "target", Namespace: "default"},
+			Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+		},
+	}
+
+	// Trip the circuit breaker for the action's failure domain
+	for i := 0; i < 2; i++ {
+		c.RecordAction(context.Background(), action, &kubetypes.ActionResult{
+			Success:   false,
+			Error:     fmt.Errorf("test error"),
+			StartTime: time.Now(),
+			EndTime:   time.Now(),
+		})
+	}
+	validated, err := c.ValidateAction(context.Background(), action)
+	require.NoError(t, err)
+	require.False(t, validated.Valid, "the circuit breaker should be open")
+
+	// Admission leaves transient state to the controller
+	admitted, err := c.ValidateAdmission(context.Background(), action)
+	require.NoError(t, err)
+	assert.True(t, admitted.Valid)
+}
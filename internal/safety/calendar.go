@@ -0,0 +1,207 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// blackoutDateLayout is the layout of blackout dates
+const blackoutDateLayout = "2006-01-02"
+
+// Calendar knows the operator's business hours and the release freezes
+// listed in the blackout ConfigMap, both in the configured time zone
+type Calendar struct {
+	reader        client.Reader
+	location      *time.Location
+	days          map[time.Weekday]bool
+	start, end    int
+	offHoursLimit int
+	blackouts     types.NamespacedName
+
+	// Cooldowns started outside business hours, zero to keep the default
+	offHoursTargetCooldown time.Duration
+	offHoursFailureCooloff time.Duration
+}
+
+// NewCalendar creates a calendar from its configuration. The blackout
+// ConfigMap is read through reader whenever a freeze is looked up.
+func NewCalendar(reader client.Reader, cfg config.CalendarConfig) (*Calendar, error) {
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar time zone %q: %w", cfg.TimeZone, err)
+	}
+
+	c := &Calendar{
+		reader:        reader,
+		location:      location,
+		days:          make(map[time.Weekday]bool, len(cfg.BusinessDays)),
+		offHoursLimit: cfg.OffHoursMaxActionsPerHour,
+
+		offHoursTargetCooldown: cfg.OffHoursTargetCooldown,
+		offHoursFailureCooloff: cfg.OffHoursFailureCooloff,
+	}
+	for _, day := range cfg.BusinessDays {
+		weekday, ok := parseWeekday(day)
+		if !ok {
+			return nil, fmt.Errorf("invalid business day %q", day)
+		}
+		c.days[weekday] = true
+	}
+	if c.start, err = parseClock(cfg.BusinessHoursStart); err != nil {
+		return nil, fmt.Errorf("invalid business hours start: %w", err)
+	}
+	if c.end, err = parseClock(cfg.BusinessHoursEnd); err != nil {
+		return nil, fmt.Errorf("invalid business hours end: %w", err)
+	}
+
+	if cfg.BlackoutConfigMap != "" {
+		namespace, name, ok := strings.Cut(cfg.BlackoutConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("blackout ConfigMap must be namespace/name, got %q", cfg.BlackoutConfigMap)
+		}
+		c.blackouts = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return c, nil
+}
+
+// BusinessHours reports whether t falls within business hours. Hours
+// ending before they start span midnight and belong to the business day
+// they start on, so Friday night hours run into Saturday morning.
+func (c *Calendar) BusinessHours(t time.Time) bool {
+	local := t.In(c.location)
+	minute := local.Hour()*60 + local.Minute()
+	if c.start <= c.end {
+		return c.days[local.Weekday()] && minute >= c.start && minute < c.end
+	}
+	if minute >= c.start {
+		return c.days[local.Weekday()]
+	}
+	previous := (local.Weekday() + 6) % 7
+	return minute < c.end && c.days[previous]
+}
+
+// Blackout returns the name of the release freeze t falls in. A missing
+// ConfigMap has no freezes; malformed entries are skipped.
+func (c *Calendar) Blackout(ctx context.Context, t time.Time) (string, bool, error) {
+	if c.blackouts.Name == "" {
+		return "", false, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.reader.Get(ctx, c.blackouts, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to read blackout ConfigMap: %w", err)
+	}
+
+	names := make([]string, 0, len(configMap.Data))
+	for name := range configMap.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	date := t.In(c.location).Format(blackoutDateLayout)
+	for _, name := range names {
+		from, to, err := parseBlackout(configMap.Data[name])
+		if err != nil {
+			log.FromContext(ctx).Info("Skipping invalid blackout", "name", name, "error", err.Error())
+			continue
+		}
+		// Dates in this layout sort chronologically
+		if date >= from && date <= to {
+			return name, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// rateLimit returns the hourly action limit of the policy at now. Outside
+// business hours the policy's off-hours limit applies, or else the
+// calendar's.
+func (c *Controller) rateLimit(policy *v1alpha1.HealingPolicy, now time.Time) int {
+	limit := c.config.MaxActionsPerHour
+	if policy.Spec.SafetyRules.MaxActionsPerHour > 0 {
+		limit = int(policy.Spec.SafetyRules.MaxActionsPerHour)
+	}
+	if c.calendar == nil || c.calendar.BusinessHours(now) {
+		return limit
+	}
+
+	if policy.Spec.SafetyRules.OffHoursMaxActionsPerHour > 0 {
+		return int(policy.Spec.SafetyRules.OffHoursMaxActionsPerHour)
+	}
+	if c.calendar.offHoursLimit > 0 {
+		return c.calendar.offHoursLimit
+	}
+	return limit
+}
+
+// targetCooldown returns the cooldown of a target healed at start, which
+// is the calendar's off-hours cooldown outside business hours
+func (c *Controller) targetCooldown(start time.Time) time.Duration {
+	if c.calendar != nil && c.calendar.offHoursTargetCooldown > 0 && !c.calendar.BusinessHours(start) {
+		return c.calendar.offHoursTargetCooldown
+	}
+	return c.config.TargetCooldown
+}
+
+// failureCooloff returns the cool-off of an action type that failed at
+// start, which is the calendar's off-hours cool-off outside business hours
+func (c *Controller) failureCooloff(start time.Time) time.Duration {
+	if c.calendar != nil && c.calendar.offHoursFailureCooloff > 0 && !c.calendar.BusinessHours(start) {
+		return c.calendar.offHoursFailureCooloff
+	}
+	return c.config.FailureCooloff
+}
+
+// parseBlackout parses a date or an inclusive date range "from/to"
+func parseBlackout(value string) (string, string, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(value), "/")
+	if !isRange {
+		to = from
+	}
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse(blackoutDateLayout, date); err != nil {
+			return "", "", fmt.Errorf("invalid date %q", date)
+		}
+	}
+	if to < from {
+		return "", "", fmt.Errorf("range %q ends before it starts", value)
+	}
+	return from, to, nil
+}
+
+// parseClock parses "15:04" into minutes since midnight
+func parseClock(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// parseWeekday parses a weekday name or its three letter abbreviation
+func parseWeekday(value string) (time.Weekday, bool) {
+	value = strings.ToLower(value)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,179 @@
+package safety
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func calendarConfig() config.CalendarConfig {
+	return config.CalendarConfig{
+		Enabled:                   true,
+		TimeZone:                  "Europe/Berlin",
+		BusinessDays:              []string{"Mon", "tue", "Wednesday", "Thu", "Fri"},
+		BusinessHoursStart:        "09:00",
+		BusinessHoursEnd:          "17:00",
+		OffHoursMaxActionsPerHour: 2,
+		BlackoutConfigMap:         "kubeskippy-system/release-freezes",
+	}
+}
+
+func TestCalendar_BusinessHours(t *testing.T) {
+	calendar, err := NewCalendar(nil, calendarConfig())
+	require.NoError(t, err)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	// Wednesday, 2024-05-15
+	assert.True(t, calendar.BusinessHours(time.Date(2024, 5, 15, 9, 0, 0, 0, berlin)))
+	assert.True(t, calendar.BusinessHours(time.Date(2024, 5, 15, 16, 59, 0, 0, berlin)))
+	assert.False(t, calendar.BusinessHours(time.Date(2024, 5, 15, 17, 0, 0, 0, berlin)))
+	assert.False(t, calendar.BusinessHours(time.Date(2024, 5, 15, 8, 0, 0, 0, berlin)))
+	// 08:00 UTC is 10:00 in Berlin
+	assert.True(t, calendar.BusinessHours(time.Date(2024, 5, 15, 8, 0, 0, 0, time.UTC)))
+	// Saturday
+	assert.False(t, calendar.BusinessHours(time.Date(2024, 5, 18, 12, 0, 0, 0, berlin)))
+
+	t.Run("spanning midnight", func(t *testing.T) {
+		cfg := calendarConfig()
+		cfg.BusinessHoursStart, cfg.BusinessHoursEnd = "22:00", "06:00"
+		night, err := NewCalendar(nil, cfg)
+		require.NoError(t, err)
+		assert.True(t, night.BusinessHours(time.Date(2024, 5, 15, 23, 0, 0, 0, berlin)))
+		assert.True(t, night.BusinessHours(time.Date(2024, 5, 15, 5, 0, 0, 0, berlin)))
+		assert.False(t, night.BusinessHours(time.Date(2024, 5, 15, 12, 0, 0, 0, berlin)))
+		// Friday night runs into Saturday, Sunday night is off
+		assert.True(t, night.BusinessHours(time.Date(2024, 5, 18, 5, 0, 0, 0, berlin)))
+		assert.False(t, night.BusinessHours(time.Date(2024, 5, 18, 23, 0, 0, 0, berlin)))
+		assert.False(t, night.BusinessHours(time.Date(2024, 5, 20, 5, 0, 0, 0, berlin)))
+	})
+}
+
+func TestNewCalendar_Invalid(t *testing.T) {
+	tests := map[string]func(*config.CalendarConfig){
+		"time zone":     func(c *config.CalendarConfig) { c.TimeZone = "Mars/Olympus" },
+		"business day":  func(c *config.CalendarConfig) { c.BusinessDays = []string{"Funday"} },
+		"start":         func(c *config.CalendarConfig) { c.BusinessHoursStart = "9am" },
+		"end":           func(c *config.CalendarConfig) { c.BusinessHoursEnd = "25:00" },
+		"blackout name": func(c *config.CalendarConfig) { c.BlackoutConfigMap = "release-freezes" },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := calendarConfig()
+			mutate(&cfg)
+			_, err := NewCalendar(nil, cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCalendar_Blackout(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	freezes := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "release-freezes", Namespace: "kubeskippy-system"},
+		Data: map[string]string{
+			"holidays":     "2024-12-20/2025-01-03",
+			"black-friday": " 2024-11-29 ",
+			"broken":       "2024-13-01",
+		},
+	}
+	reader := fake.NewClientBuilder().WithObjects(freezes).Build()
+	calendar, err := NewCalendar(reader, calendarConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		at       time.Time
+		expected string
+	}{
+		{time.Date(2024, 12, 20, 0, 0, 0, 0, berlin), "holidays"},
+		{time.Date(2025, 1, 3, 23, 59, 0, 0, berlin), "holidays"},
+		{time.Date(2025, 1, 4, 0, 0, 0, 0, berlin), ""},
+		{time.Date(2024, 11, 29, 12, 0, 0, 0, berlin), "black-friday"},
+		// 23:30 UTC on the 28th is already the 29th in Berlin
+		{time.Date(2024, 11, 28, 23, 30, 0, 0, time.UTC), "black-friday"},
+		{time.Date(2024, 6, 1, 12, 0, 0, 0, berlin), ""},
+	}
+	for _, tt := range tests {
+		name, active, err := calendar.Blackout(context.Background(), tt.at)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected != "", active, tt.at.String())
+		assert.Equal(t, tt.expected, name, tt.at.String())
+	}
+
+	t.Run("missing ConfigMap", func(t *testing.T) {
+		empty, err := NewCalendar(fake.NewClientBuilder().Build(), calendarConfig())
+		require.NoError(t, err)
+		_, active, err := empty.Blackout(context.Background(), time.Date(2024, 12, 24, 0, 0, 0, 0, berlin))
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+}
+
+func TestController_RateLimitOffHours(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	workday := time.Date(2024, 5, 15, 12, 0, 0, 0, berlin)
+	night := time.Date(2024, 5, 15, 23, 0, 0, 0, berlin)
+
+	c := NewController(nil, config.SafetyConfig{MaxActionsPerHour: 10}, nil, nil)
+	policy := &v1alpha1.HealingPolicy{}
+	assert.Equal(t, 10, c.rateLimit(policy, night), "no calendar")
+
+	calendar, err := NewCalendar(nil, calendarConfig())
+	require.NoError(t, err)
+	c.SetCalendar(calendar)
+	assert.Equal(t, 10, c.rateLimit(policy, workday))
+	assert.Equal(t, 2, c.rateLimit(policy, night))
+
+	policy.Spec.SafetyRules.MaxActionsPerHour = 20
+	policy.Spec.SafetyRules.OffHoursMaxActionsPerHour = 5
+	assert.Equal(t, 20, c.rateLimit(policy, workday))
+	assert.Equal(t, 5, c.rateLimit(policy, night))
+}
+
+func TestController_OffHoursCooldowns(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	workday := time.Date(2024, 5, 15, 12, 0, 0, 0, berlin)
+	// 20:00 UTC is 22:00 in Berlin
+	night := time.Date(2024, 5, 15, 20, 0, 0, 0, time.UTC)
+
+	c := NewController(nil, config.SafetyConfig{
+		TargetCooldown: 5 * time.Minute,
+		FailureCooloff: 30 * time.Minute,
+	}, nil, nil)
+	assert.Equal(t, 5*time.Minute, c.targetCooldown(night), "no calendar")
+	assert.Equal(t, 30*time.Minute, c.failureCooloff(night), "no calendar")
+
+	cfg := calendarConfig()
+	cfg.OffHoursTargetCooldown = time.Hour
+	cfg.OffHoursFailureCooloff = 4 * time.Hour
+	calendar, err := NewCalendar(nil, cfg)
+	require.NoError(t, err)
+	c.SetCalendar(calendar)
+
+	assert.Equal(t, 5*time.Minute, c.targetCooldown(workday))
+	assert.Equal(t, time.Hour, c.targetCooldown(night))
+	assert.Equal(t, 30*time.Minute, c.failureCooloff(workday))
+	assert.Equal(t, 4*time.Hour, c.failureCooloff(night))
+
+	// Off-hours cooldowns are kept until they expired
+	c.targetCooldowns.Store("night", night)
+	c.targetCooldowns.Store("workday", workday)
+	c.pruneTargetCooldowns(night.Add(30 * time.Minute))
+	_, ok := c.targetCooldowns.Load("night")
+	assert.True(t, ok)
+	_, ok = c.targetCooldowns.Load("workday")
+	assert.False(t, ok)
+
+	c.failureCooloffs.Store("night", night)
+	c.pruneFailureCooloffs(night.Add(5 * time.Hour))
+	_, ok = c.failureCooloffs.Load("night")
+	assert.False(t, ok)
+}
@@ -0,0 +1,122 @@
+package safety
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Circuit breaker keying modes
+const (
+	// BreakerKeyPolicy shares one breaker across all targets of a policy
+	BreakerKeyPolicy = config.CircuitBreakerKeyPolicy
+
+	// BreakerKeyPolicyNamespace keeps a breaker per policy and target namespace
+	BreakerKeyPolicyNamespace = config.CircuitBreakerKeyPolicyNamespace
+
+	// BreakerKeyPolicyTarget keeps a breaker per policy and target
+	BreakerKeyPolicyTarget = config.CircuitBreakerKeyPolicyTarget
+)
+
+// circuitBreakerKey returns the failure domain of the action's breaker.
+// Config.Validate rejects unknown keying modes, so the default is only
+// reached for "policy" or an unset mode.
+func (c *Controller) circuitBreakerKey(action *v1alpha1.HealingAction) string {
+	target := action.Spec.TargetResource
+	switch c.config.CircuitBreaker.KeyBy {
+	case BreakerKeyPolicyNamespace:
+		return fmt.Sprintf("%s|%s", action.Spec.PolicyRef.Name, target.Namespace)
+	case BreakerKeyPolicyTarget:
+		return fmt.Sprintf("%s|%s/%s/%s", action.Spec.PolicyRef.Name, target.Kind, target.Namespace, target.Name)
+	default:
+		return action.Spec.PolicyRef.Name
+	}
+}
+
+// circuitBreakerIdleTTL is how long a breaker may go without calls before
+// it is evicted. Breakers still open are kept for at least their timeout.
+const circuitBreakerIdleTTL = 24 * time.Hour
+
+// circuitBreakerThresholds returns the breaker configuration for the action
+// and the index of the first matching threshold override, or -1 if none
+// matches
+func (c *Controller) circuitBreakerThresholds(action *v1alpha1.HealingAction) (config.CircuitBreakerThresholds, int) {
+	global := c.config.CircuitBreaker
+	cfg := config.CircuitBreakerThresholds{
+		FailureThreshold: global.FailureThreshold,
+		SuccessThreshold: global.SuccessThreshold,
+		Timeout:          global.Timeout,
+	}
+
+	target := action.Spec.TargetResource
+	for i, override := range global.Thresholds {
+		if (override.Policy != "" && override.Policy != action.Spec.PolicyRef.Name) ||
+			(override.Namespace != "" && override.Namespace != target.Namespace) ||
+			(override.Kind != "" && override.Kind != target.Kind) {
+			continue
+		}
+		if override.FailureThreshold > 0 {
+			cfg.FailureThreshold = override.FailureThreshold
+		}
+		if override.SuccessThreshold > 0 {
+			cfg.SuccessThreshold = override.SuccessThreshold
+		}
+		if override.Timeout > 0 {
+			cfg.Timeout = override.Timeout
+		}
+		return cfg, i
+	}
+	return cfg, -1
+}
+
+// getOrCreateCircuitBreaker gets or creates the circuit breaker covering an
+// action and returns it with the key of its failure domain. A failure domain
+// whose actions match different threshold overrides, such as a policy
+// breaker with a namespace override, keeps a breaker per override, so the
+// thresholds never depend on which action created the breaker.
+func (c *Controller) getOrCreateCircuitBreaker(action *v1alpha1.HealingAction) (*kubetypes.CircuitBreaker, string) {
+	key := c.circuitBreakerKey(action)
+	thresholds, override := c.circuitBreakerThresholds(action)
+	storeKey := key
+	if override >= 0 {
+		storeKey = fmt.Sprintf("%s|thresholds=%d", key, override)
+	}
+
+	// Try to load existing circuit breaker
+	if value, exists := c.circuitBreakers.Load(storeKey); exists {
+		return value.(*kubetypes.CircuitBreaker), key
+	}
+
+	// Create new circuit breaker
+	cb := kubetypes.NewCircuitBreaker(thresholds.FailureThreshold, thresholds.SuccessThreshold, thresholds.Timeout)
+
+	// Try to store it atomically
+	actual, _ := c.circuitBreakers.LoadOrStore(storeKey, cb)
+
+	// Return the actual value (either the new one we stored, or an existing one)
+	return actual.(*kubetypes.CircuitBreaker), key
+}
+
+// pruneCircuitBreakers evicts breakers that were not called for
+// circuitBreakerIdleTTL, keeping open breakers until their timeout passed
+func (c *Controller) pruneCircuitBreakers(now time.Time) {
+	ttl := circuitBreakerIdleTTL
+	if timeout := c.config.CircuitBreaker.Timeout; timeout > ttl {
+		ttl = timeout
+	}
+	for _, override := range c.config.CircuitBreaker.Thresholds {
+		if override.Timeout > ttl {
+			ttl = override.Timeout
+		}
+	}
+
+	c.circuitBreakers.Range(func(key, value interface{}) bool {
+		if now.Sub(value.(*kubetypes.CircuitBreaker).LastCall()) >= ttl {
+			c.circuitBreakers.Delete(key)
+		}
+		return true
+	})
+}
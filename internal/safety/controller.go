@@ -3,9 +3,11 @@ package safety
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -25,8 +27,26 @@ type Controller struct {
 	store       ActionStore
 	auditLogger AuditLogger
 
-	// Circuit breakers per policy
+	// signer signs action records when audit signing is enabled
+	signer *AuditSigner
+
+	// calendar applies off-hours rate limits when configured
+	calendar *Calendar
+
+	// Circuit breakers per failure domain, see circuitBreakerKey
 	circuitBreakers sync.Map // map[string]*kubetypes.CircuitBreaker
+
+	// Time of the last successful action per policy and target
+	targetCooldowns sync.Map // map[string]time.Time
+
+	// Time of the last failed action per target and action type
+	failureCooloffs sync.Map // map[string]time.Time
+
+	// Time of the last action per cooldown group
+	cooldownGroups sync.Map // map[string]time.Time
+
+	// Actions counted against the rate limit before they ran, by UID
+	reservations sync.Map // map[types.UID]struct{}
 }
 
 // NewController creates a new safety controller
@@ -82,11 +102,36 @@ func (c *Controller) ValidateAction(ctx context.Context, action *v1alpha1.Healin
 		return result, nil
 	}
 
+	// Give recently healed targets time to recover
+	if remaining := c.targetCooldownRemaining(action); remaining > 0 {
+		result.Valid = false
+		result.Reason = fmt.Sprintf("Target is in cooldown for %s after a successful healing", remaining.Round(time.Second))
+		c.auditLogger.LogValidation(ctx, action, false, result.Reason)
+		return result, nil
+	}
+
+	// Related workloads are not healed by several policies at once
+	if remaining := c.cooldownGroupRemaining(action); remaining > 0 {
+		result.Valid = false
+		result.Reason = fmt.Sprintf("Cooldown group %s is in cooldown for %s after an action of the group", cooldownGroup(action), remaining.Round(time.Second))
+		c.auditLogger.LogValidation(ctx, action, false, result.Reason)
+		return result, nil
+	}
+
+	// Don't retry an action type that just failed on the target
+	if remaining := c.failureCooloffRemaining(action); remaining > 0 {
+		result.Valid = false
+		result.Reason = fmt.Sprintf("%s actions on the target are in failure cool-off for %s", action.Spec.Action.Type, remaining.Round(time.Second))
+		c.auditLogger.LogValidation(ctx, action, false, result.Reason)
+		return result, nil
+	}
+
 	// Check circuit breaker
-	cb := c.getOrCreateCircuitBreaker(action.Spec.PolicyRef.Name)
+	cb, breakerKey := c.getOrCreateCircuitBreaker(action)
 	if err := cb.Call(ctx, func() error { return nil }); err != nil {
 		result.Valid = false
-		result.Reason = fmt.Sprintf("Circuit breaker is open: %v", err)
+		result.Reason = fmt.Sprintf("Circuit breaker is open for %s: %v", breakerKey, err)
+		result.CircuitOpen = true
 		result.Warnings = append(result.Warnings, "Too many failures detected")
 		c.auditLogger.LogValidation(ctx, action, false, result.Reason)
 		return result, nil
@@ -100,6 +145,14 @@ func (c *Controller) ValidateAction(ctx context.Context, action *v1alpha1.Healin
 		return result, nil
 	}
 
+	// Make sure the action keeps every zone served
+	if err := c.validateZoneSpread(ctx, action); err != nil {
+		result.Valid = false
+		result.Reason = err.Error()
+		c.auditLogger.LogValidation(ctx, action, false, result.Reason)
+		return result, nil
+	}
+
 	// Check if approval is enforced globally
 	if c.config.RequireApproval && !action.Spec.DryRun {
 		if action.Spec.ApprovalRequired || action.Status.Approval == nil || !action.Status.Approval.Approved {
@@ -124,30 +177,87 @@ func (c *Controller) ValidateAction(ctx context.Context, action *v1alpha1.Healin
 // CheckRateLimit verifies action frequency limits
 func (c *Controller) CheckRateLimit(ctx context.Context, policy *v1alpha1.HealingPolicy) (bool, error) {
 	policyKey := getPolicyKey(policy)
+	count, limit, err := c.hourlyActions(ctx, policy)
+	if err != nil {
+		return false, err
+	}
+
+	allowed := count < limit
+	c.auditLogger.LogRateLimit(ctx, policyKey, allowed, count, limit)
+
+	if !allowed {
+		log.FromContext(ctx).Info("Rate limit exceeded",
+			"policy", policyKey,
+			"current", count,
+			"limit", limit)
+	}
 
-	// Determine the rate limit
-	limit := c.config.MaxActionsPerHour
-	if policy.Spec.SafetyRules.MaxActionsPerHour > 0 {
-		limit = int(policy.Spec.SafetyRules.MaxActionsPerHour)
+	return allowed, nil
+}
+
+// RemainingActions returns how many more actions the policy may take in the
+// current hour before reaching its rate limit
+func (c *Controller) RemainingActions(ctx context.Context, policy *v1alpha1.HealingPolicy) (int, error) {
+	count, limit, err := c.hourlyActions(ctx, policy)
+	if err != nil {
+		return 0, err
 	}
+	if count >= limit {
+		return 0, nil
+	}
+	return limit - count, nil
+}
 
-	// Get action count in the last hour
+// hourlyActions returns the actions the policy took in the last hour and its
+// hourly limit
+func (c *Controller) hourlyActions(ctx context.Context, policy *v1alpha1.HealingPolicy) (int, int, error) {
+	limit := c.rateLimit(policy, time.Now())
 	since := time.Now().Add(-1 * time.Hour)
-	count, err := c.store.GetActionCount(ctx, policyKey, since)
+	count, err := c.store.GetActionCount(ctx, getPolicyKey(policy), since)
 	if err != nil {
-		return false, fmt.Errorf("failed to get action count: %w", err)
+		return 0, 0, fmt.Errorf("failed to get action count: %w", err)
+	}
+	return count, limit, nil
+}
+
+// ReserveAction counts an action against the hourly rate limit of its
+// policy in the same step as the limit is checked, so that managers sharing
+// the action store cannot both take the last action of the hour. With a
+// store that cannot reserve the action is allowed and counted when it is
+// recorded.
+func (c *Controller) ReserveAction(ctx context.Context, action *v1alpha1.HealingAction) (bool, error) {
+	reserver, ok := c.store.(RateLimitReserver)
+	if !ok || action.Spec.DryRun {
+		return true, nil
 	}
 
-	allowed := count < limit
+	policy := &v1alpha1.HealingPolicy{}
+	key := types.NamespacedName{Name: action.Spec.PolicyRef.Name, Namespace: action.Spec.PolicyRef.Namespace}
+	if err := c.client.Get(ctx, key, policy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get policy %s: %w", key, err)
+		}
+		// Actions of a deleted policy are held to the default limit
+		policy.Name, policy.Namespace = key.Name, key.Namespace
+	}
+
+	policyKey := getPolicyKey(policy)
+	now := time.Now()
+	limit := c.rateLimit(policy, now)
+	allowed, count, err := reserver.ReserveAction(ctx, policyKey, now, limit)
+	if err != nil {
+		return false, err
+	}
 	c.auditLogger.LogRateLimit(ctx, policyKey, allowed, count, limit)
 
-	if !allowed {
+	if allowed {
+		c.reservations.Store(action.UID, struct{}{})
+	} else {
 		log.FromContext(ctx).Info("Rate limit exceeded",
 			"policy", policyKey,
 			"current", count,
 			"limit", limit)
 	}
-
 	return allowed, nil
 }
 
@@ -215,6 +325,16 @@ func (c *Controller) IsProtectedResource(resource runtime.Object) (bool, string)
 	return false, ""
 }
 
+// SetAuditSigner enables signing of action records
+func (c *Controller) SetAuditSigner(signer *AuditSigner) {
+	c.signer = signer
+}
+
+// SetCalendar enables business hours rate limits
+func (c *Controller) SetCalendar(calendar *Calendar) {
+	c.calendar = calendar
+}
+
 // RecordAction logs an executed action
 func (c *Controller) RecordAction(ctx context.Context, action *v1alpha1.HealingAction, result *kubetypes.ActionResult) {
 	policyKey := fmt.Sprintf("%s/%s", action.Spec.PolicyRef.Namespace, action.Spec.PolicyRef.Name)
@@ -233,6 +353,9 @@ func (c *Controller) RecordAction(ctx context.Context, action *v1alpha1.HealingA
 		DurationMS: result.EndTime.Sub(result.StartTime).Milliseconds(),
 		DryRun:     action.Spec.DryRun,
 	}
+	if _, reserved := c.reservations.LoadAndDelete(action.UID); reserved {
+		record.Reserved = true
+	}
 
 	if !result.Success && result.Error != nil {
 		record.Error = result.Error.Error()
@@ -242,12 +365,35 @@ func (c *Controller) RecordAction(ctx context.Context, action *v1alpha1.HealingA
 		record.ApprovedBy = action.Status.Approval.ApprovedBy
 	}
 
+	// Provenance is carried on the action annotations
+	if generation, err := strconv.ParseInt(action.Annotations[kubetypes.AnnotationPolicyGeneration], 10, 64); err == nil {
+		record.PolicyGeneration = generation
+	}
+	record.AIModel = action.Annotations[kubetypes.AnnotationAIModel]
+	record.PromptHash = action.Annotations[kubetypes.AnnotationAIPromptHash]
+
+	if c.signer != nil {
+		// The ID is part of the signed payload, so it must be set before signing
+		record.ID = generateID()
+		if err := c.signer.Sign(&record); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to sign action record")
+		}
+	}
+
 	if err := c.store.RecordAction(ctx, record); err != nil {
 		log.FromContext(ctx).Error(err, "Failed to record action")
 	}
 
+	// Start the target cooldown after a successful healing
+	if result.Success && !action.Spec.DryRun && c.config.TargetCooldown > 0 {
+		c.targetCooldowns.Store(targetCooldownKey(action), result.EndTime)
+	}
+
+	c.recordFailureCooloff(action, result)
+	c.recordCooldownGroup(action, result)
+
 	// Update circuit breaker based on result
-	cb := c.getOrCreateCircuitBreaker(action.Spec.PolicyRef.Name)
+	cb, _ := c.getOrCreateCircuitBreaker(action)
 	if result.Success {
 		cb.Call(ctx, func() error { return nil })
 	} else {
@@ -260,6 +406,17 @@ func (c *Controller) RecordAction(ctx context.Context, action *v1alpha1.HealingA
 		"dry_run":     record.DryRun,
 		"target":      targetKey,
 	}
+	if record.PolicyGeneration != 0 {
+		details["policy_generation"] = record.PolicyGeneration
+	}
+	if record.AIModel != "" {
+		details["ai_model"] = record.AIModel
+		details["prompt_hash"] = record.PromptHash
+	}
+	if record.Signature != "" {
+		details["key_id"] = record.KeyID
+		details["signature"] = record.Signature
+	}
 	c.auditLogger.LogAction(ctx, action, fmt.Sprintf("success=%v", result.Success), details)
 }
 
@@ -302,30 +459,60 @@ func (c *Controller) validateActionType(action *v1alpha1.HealingAction, target r
 		if action.Spec.Action.PatchAction == nil {
 			return fmt.Errorf("patch action missing configuration")
 		}
-	}
 
-	return nil
-}
+	case "finalizer":
+		// Finalizers are only removed when explicitly opted in
+		if action.Spec.Action.FinalizerAction == nil {
+			return fmt.Errorf("finalizer action missing configuration")
+		}
+		if action.Spec.Action.FinalizerAction.ForceDelete && !action.Spec.ApprovalRequired {
+			return fmt.Errorf("force deleting finalizers requires approval")
+		}
 
-// getOrCreateCircuitBreaker gets or creates a circuit breaker for a policy
-func (c *Controller) getOrCreateCircuitBreaker(policyName string) *kubetypes.CircuitBreaker {
-	// Try to load existing circuit breaker
-	if value, exists := c.circuitBreakers.Load(policyName); exists {
-		return value.(*kubetypes.CircuitBreaker)
-	}
+	case "hibernate":
+		// Hibernated workloads must come back on their own
+		if action.Spec.Action.HibernateAction == nil {
+			return fmt.Errorf("hibernate action missing configuration")
+		}
+		if action.Spec.Action.HibernateAction.Duration.Duration <= 0 && action.Spec.Action.HibernateAction.ResumeWhen == nil {
+			return fmt.Errorf("hibernate action needs a duration or resumeWhen condition")
+		}
 
-	// Create new circuit breaker
-	cb := kubetypes.NewCircuitBreaker(
-		c.config.CircuitBreaker.FailureThreshold,
-		c.config.CircuitBreaker.SuccessThreshold,
-		c.config.CircuitBreaker.Timeout,
-	)
+	case "exec":
+		// Commands run with the container's privileges
+		if action.Spec.Action.ExecAction == nil || len(action.Spec.Action.ExecAction.Command) == 0 {
+			return fmt.Errorf("exec action missing command")
+		}
 
-	// Try to store it atomically
-	actual, _ := c.circuitBreakers.LoadOrStore(policyName, cb)
+	case "resize":
+		if action.Spec.Action.ResizeAction == nil || len(action.Spec.Action.ResizeAction.Containers) == 0 {
+			return fmt.Errorf("resize action missing containers")
+		}
 
-	// Return the actual value (either the new one we stored, or an existing one)
-	return actual.(*kubetypes.CircuitBreaker)
+	case "drain":
+		// Draining evicts every pod of the node
+		if action.Spec.TargetResource.Kind != "Node" {
+			return fmt.Errorf("drain actions target nodes, not %s", action.Spec.TargetResource.Kind)
+		}
+		if !action.Spec.ApprovalRequired {
+			return fmt.Errorf("draining a node requires approval")
+		}
+
+	case "chain":
+		// Every step is held to the rules of its action type
+		if len(action.Spec.Action.Steps) == 0 {
+			return fmt.Errorf("chain action missing steps")
+		}
+		for i := range action.Spec.Action.Steps {
+			step := action.DeepCopy()
+			step.Spec.Action = *action.Spec.Action.Steps[i].ActionTemplate(&action.Spec.Action)
+			if err := c.validateActionType(step, target); err != nil {
+				return fmt.Errorf("step %s: %w", step.Spec.Action.Name, err)
+			}
+		}
+	}
+
+	return nil
 }
 
 // getPolicyKey generates a unique key for a policy
@@ -481,6 +668,40 @@ func (d *defaultAuditLogger) LogRateLimit(ctx context.Context, policyKey string,
 		"limit", limit)
 }
 
+// targetCooldownKey identifies a target healed by a policy
+func targetCooldownKey(action *v1alpha1.HealingAction) string {
+	return fmt.Sprintf("%s/%s|%s/%s/%s",
+		action.Spec.PolicyRef.Namespace, action.Spec.PolicyRef.Name,
+		action.Spec.TargetResource.Kind,
+		action.Spec.TargetResource.Namespace,
+		action.Spec.TargetResource.Name)
+}
+
+// targetCooldownRemaining returns how long the action's target stays in
+// cooldown for its policy
+func (c *Controller) targetCooldownRemaining(action *v1alpha1.HealingAction) time.Duration {
+	if c.config.TargetCooldown <= 0 {
+		return 0
+	}
+	value, ok := c.targetCooldowns.Load(targetCooldownKey(action))
+	if !ok {
+		return 0
+	}
+	start := value.(time.Time)
+	return time.Until(start.Add(c.targetCooldown(start)))
+}
+
+// pruneTargetCooldowns forgets targets whose cooldown has expired
+func (c *Controller) pruneTargetCooldowns(now time.Time) {
+	c.targetCooldowns.Range(func(key, value interface{}) bool {
+		start := value.(time.Time)
+		if now.Sub(start) >= c.targetCooldown(start) {
+			c.targetCooldowns.Delete(key)
+		}
+		return true
+	})
+}
+
 // StartCleanupLoop starts a background loop to clean up old records
 func (c *Controller) StartCleanupLoop(ctx context.Context, retention time.Duration) {
 	go func() {
@@ -496,6 +717,10 @@ func (c *Controller) StartCleanupLoop(ctx context.Context, retention time.Durati
 				if err := c.store.CleanupOldRecords(ctx, before); err != nil {
 					log.FromContext(ctx).Error(err, "Failed to cleanup old records")
 				}
+				c.pruneTargetCooldowns(time.Now())
+				c.pruneCircuitBreakers(time.Now())
+				c.pruneFailureCooloffs(time.Now())
+				c.pruneCooldownGroups(time.Now())
 			}
 		}
 	}()
@@ -212,6 +212,88 @@ func TestController_ValidateAction(t *testing.T) {
 			expectedValid:  false,
 			expectedReason: "scale action missing configuration",
 		},
+		{
+			name:   "force delete of finalizers without approval is invalid",
+			config: config.SafetyConfig{},
+			action: &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-action",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef: v1alpha1.PolicyReference{
+						Name:      "test-policy",
+						Namespace: "default",
+					},
+					TargetResource: v1alpha1.TargetResource{
+						Kind:      "Pod",
+						Name:      "test-pod",
+						Namespace: "default",
+					},
+					Action: v1alpha1.HealingActionTemplate{
+						Name:            "release",
+						Type:            "finalizer",
+						FinalizerAction: &v1alpha1.FinalizerAction{ForceDelete: true},
+					},
+				},
+			},
+			expectedValid:  false,
+			expectedReason: "force deleting finalizers requires approval",
+		},
+		{
+			name:   "hibernate without a resume point is invalid",
+			config: config.SafetyConfig{},
+			action: &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-action",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef: v1alpha1.PolicyReference{
+						Name:      "test-policy",
+						Namespace: "default",
+					},
+					TargetResource: v1alpha1.TargetResource{
+						Kind:      "Deployment",
+						Name:      "test-deployment",
+						Namespace: "default",
+					},
+					Action: v1alpha1.HealingActionTemplate{
+						Name:            "hibernate",
+						Type:            "hibernate",
+						HibernateAction: &v1alpha1.HibernateAction{},
+					},
+				},
+			},
+			expectedValid:  false,
+			expectedReason: "hibernate action needs a duration or resumeWhen condition",
+		},
+		{
+			name:   "drain without approval is invalid",
+			config: config.SafetyConfig{},
+			action: &v1alpha1.HealingAction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-action",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.HealingActionSpec{
+					PolicyRef: v1alpha1.PolicyReference{
+						Name:      "test-policy",
+						Namespace: "default",
+					},
+					TargetResource: v1alpha1.TargetResource{
+						Kind: "Node",
+						Name: "worker-1",
+					},
+					Action: v1alpha1.HealingActionTemplate{
+						Name: "suspect-drain",
+						Type: "drain",
+					},
+				},
+			},
+			expectedValid:  false,
+			expectedReason: "draining a node requires approval",
+		},
 	}
 
 	for _, tt := range tests {
@@ -343,6 +425,10 @@ func TestController_CheckRateLimit(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedAllowed, allowed)
 
+			remaining, err := safetyCtrl.RemainingActions(context.Background(), tt.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAllowed, remaining > 0)
+
 			// Check audit log
 			require.Len(t, auditLogger.RateLimits, 1)
 			assert.Equal(t, tt.expectedAllowed, auditLogger.RateLimits[0].Allowed)
@@ -486,7 +572,7 @@ func TestController_RecordAction(t *testing.T) {
 		},
 	}
 
-	result := &types.ActionResult{
+	result := &kubetypes.ActionResult{
 		Success:   true,
 		Message:   "Action completed",
 		StartTime: time.Now().Add(-1 * time.Minute),
@@ -625,7 +711,7 @@ func TestCircuitBreakerIntegration(t *testing.T) {
 
 	// Record failures to trip circuit breaker
 	for i := 0; i < 2; i++ {
-		safetyCtrl.RecordAction(context.Background(), action, &types.ActionResult{
+		safetyCtrl.RecordAction(context.Background(), action, &kubetypes.ActionResult{
 			Success:   false,
 			Error:     fmt.Errorf("test error"),
 			StartTime: time.Now(),
@@ -638,6 +724,7 @@ func TestCircuitBreakerIntegration(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, result.Valid)
 	assert.Contains(t, result.Reason, "Circuit breaker is open")
+	assert.True(t, result.CircuitOpen)
 
 	// Wait for timeout
 	time.Sleep(150 * time.Millisecond)
@@ -647,3 +734,229 @@ func TestCircuitBreakerIntegration(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, result.Valid)
 }
+
+func TestTargetCooldown(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	config := config.SafetyConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{
+			FailureThreshold: 5,
+			SuccessThreshold: 1,
+			Timeout:          time.Minute,
+		},
+		TargetCooldown: 10 * time.Minute,
+	}
+
+	safetyCtrl := NewController(client, config, nil, nil)
+
+	newAction := func(policy, pod string) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-action",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.HealingActionSpec{
+				PolicyRef: v1alpha1.PolicyReference{
+					Name:      policy,
+					Namespace: "default",
+				},
+				TargetResource: v1alpha1.TargetResource{
+					Kind:      "Pod",
+					Name:      pod,
+					Namespace: "default",
+				},
+				Action: v1alpha1.HealingActionTemplate{
+					Name: "restart",
+					Type: "restart",
+				},
+			},
+		}
+	}
+
+	action := newAction("test-policy", "test-pod")
+	safetyCtrl.RecordAction(context.Background(), action, &kubetypes.ActionResult{
+		Success:   true,
+		StartTime: time.Now(),
+		EndTime:   time.Now(),
+	})
+
+	// The healed target is in cooldown for the same policy
+	result, err := safetyCtrl.ValidateAction(context.Background(), action)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "Target is in cooldown")
+
+	// Other targets and other policies are not affected
+	result, err = safetyCtrl.ValidateAction(context.Background(), newAction("test-policy", "other-pod"))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	result, err = safetyCtrl.ValidateAction(context.Background(), newAction("other-policy", "test-pod"))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// Failed actions do not start a cooldown
+	failed := newAction("test-policy", "failed-pod")
+	safetyCtrl.RecordAction(context.Background(), failed, &kubetypes.ActionResult{
+		Success:   false,
+		Error:     fmt.Errorf("test error"),
+		StartTime: time.Now(),
+		EndTime:   time.Now(),
+	})
+	result, err = safetyCtrl.ValidateAction(context.Background(), failed)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// Expired cooldowns are pruned
+	safetyCtrl.pruneTargetCooldowns(time.Now().Add(11 * time.Minute))
+	result, err = safetyCtrl.ValidateAction(context.Background(), action)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestCircuitBreakerKeying(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	newAction := func(kind, namespace, name string) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-action", Namespace: "default"},
+			Spec: v1alpha1.HealingActionSpec{
+				PolicyRef:      v1alpha1.PolicyReference{Name: "test-policy", Namespace: "default"},
+				TargetResource: v1alpha1.TargetResource{Kind: kind, Namespace: namespace, Name: name},
+				Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			},
+		}
+	}
+	fail := func(ctrl *Controller, action *v1alpha1.HealingAction, times int) {
+		for i := 0; i < times; i++ {
+			ctrl.RecordAction(context.Background(), action, &kubetypes.ActionResult{
+				Success:   false,
+				Error:     fmt.Errorf("test error"),
+				StartTime: time.Now(),
+				EndTime:   time.Now(),
+			})
+		}
+	}
+	valid := func(ctrl *Controller, action *v1alpha1.HealingAction) bool {
+		result, err := ctrl.ValidateAction(context.Background(), action)
+		require.NoError(t, err)
+		return result.Valid
+	}
+
+	tests := []struct {
+		name           string
+		keyBy          string
+		sameNamespace  bool
+		otherNamespace bool
+	}{
+		{name: "policy", keyBy: BreakerKeyPolicy, sameNamespace: false, otherNamespace: false},
+		{name: "policy and namespace", keyBy: BreakerKeyPolicyNamespace, sameNamespace: false, otherNamespace: true},
+		{name: "policy and target", keyBy: BreakerKeyPolicyTarget, sameNamespace: true, otherNamespace: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := NewController(fake.NewClientBuilder().WithScheme(scheme).Build(), config.SafetyConfig{
+				CircuitBreaker: config.CircuitBreakerConfig{
+					FailureThreshold: 2,
+					SuccessThreshold: 1,
+					Timeout:          time.Minute,
+					KeyBy:            tt.keyBy,
+				},
+			}, nil, nil)
+
+			failing := newAction("Deployment", "team-a", "web")
+			fail(ctrl, failing, 2)
+
+			result, err := ctrl.ValidateAction(context.Background(), failing)
+			require.NoError(t, err)
+			assert.False(t, result.Valid)
+			assert.Contains(t, result.Reason, "Circuit breaker is open")
+
+			assert.Equal(t, tt.sameNamespace, valid(ctrl, newAction("Deployment", "team-a", "api")))
+			assert.Equal(t, tt.otherNamespace, valid(ctrl, newAction("Deployment", "team-b", "web")))
+		})
+	}
+
+	t.Run("threshold overrides", func(t *testing.T) {
+		ctrl := NewController(fake.NewClientBuilder().WithScheme(scheme).Build(), config.SafetyConfig{
+			CircuitBreaker: config.CircuitBreakerConfig{
+				FailureThreshold: 2,
+				SuccessThreshold: 1,
+				Timeout:          time.Minute,
+				KeyBy:            BreakerKeyPolicyNamespace,
+				Thresholds: []config.CircuitBreakerThresholds{
+					{Namespace: "batch", FailureThreshold: 5},
+				},
+			},
+		}, nil, nil)
+
+		batch := newAction("Pod", "batch", "job-1")
+		fail(ctrl, batch, 4)
+		cb, _ := ctrl.getOrCreateCircuitBreaker(batch)
+		assert.Equal(t, kubetypes.CircuitBreakerClosed, cb.GetState())
+		fail(ctrl, batch, 1)
+		assert.False(t, valid(ctrl, batch))
+
+		web := newAction("Pod", "web", "frontend")
+		fail(ctrl, web, 2)
+		assert.False(t, valid(ctrl, web))
+	})
+
+	t.Run("threshold overrides within a shared failure domain", func(t *testing.T) {
+		ctrl := NewController(fake.NewClientBuilder().WithScheme(scheme).Build(), config.SafetyConfig{
+			CircuitBreaker: config.CircuitBreakerConfig{
+				FailureThreshold: 2,
+				SuccessThreshold: 1,
+				Timeout:          time.Minute,
+				KeyBy:            BreakerKeyPolicy,
+				Thresholds: []config.CircuitBreakerThresholds{
+					{Namespace: "batch", FailureThreshold: 5},
+				},
+			},
+		}, nil, nil)
+
+		// The policy breaker is first created by an action without override
+		web := newAction("Pod", "web", "frontend")
+		assert.True(t, valid(ctrl, web))
+
+		batch := newAction("Pod", "batch", "job-1")
+		fail(ctrl, batch, 4)
+		cb, _ := ctrl.getOrCreateCircuitBreaker(batch)
+		assert.Equal(t, kubetypes.CircuitBreakerClosed, cb.GetState())
+		fail(ctrl, batch, 1)
+		assert.False(t, valid(ctrl, batch))
+		assert.True(t, valid(ctrl, web))
+	})
+}
+
+func TestPruneCircuitBreakers(t *testing.T) {
+	ctrl := NewController(nil, config.SafetyConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{
+			FailureThreshold: 1,
+			SuccessThreshold: 1,
+			Timeout:          48 * time.Hour,
+		},
+	}, nil, nil)
+
+	action := &v1alpha1.HealingAction{
+		Spec: v1alpha1.HealingActionSpec{
+			PolicyRef: v1alpha1.PolicyReference{Name: "test-policy"},
+		},
+	}
+	cb, key := ctrl.getOrCreateCircuitBreaker(action)
+	_ = cb.Call(context.Background(), func() error { return fmt.Errorf("failed") })
+	require.Equal(t, kubetypes.CircuitBreakerOpen, cb.GetState())
+
+	// Open breakers outlive the idle TTL until their timeout passed
+	ctrl.pruneCircuitBreakers(time.Now().Add(circuitBreakerIdleTTL + time.Hour))
+	_, ok := ctrl.circuitBreakers.Load(key)
+	assert.True(t, ok)
+
+	ctrl.pruneCircuitBreakers(time.Now().Add(49 * time.Hour))
+	_, ok = ctrl.circuitBreakers.Load(key)
+	assert.False(t, ok)
+}
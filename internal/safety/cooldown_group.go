@@ -0,0 +1,48 @@
+package safety
+
+import (
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// cooldownGroup returns the cooldown group of the action's policy. Group
+// names are cluster-wide, so a group may span namespaces.
+func cooldownGroup(action *v1alpha1.HealingAction) string {
+	return action.Labels[kubetypes.LabelCooldownGroup]
+}
+
+// recordCooldownGroup starts the cooldown of the action's group. Failed
+// actions count too, since they may have changed the workloads as well.
+func (c *Controller) recordCooldownGroup(action *v1alpha1.HealingAction, result *kubetypes.ActionResult) {
+	group := cooldownGroup(action)
+	if c.config.CooldownGroupWindow <= 0 || group == "" || action.Spec.DryRun {
+		return
+	}
+	c.cooldownGroups.Store(group, result.EndTime)
+}
+
+// cooldownGroupRemaining returns how long the action's group stays in
+// cooldown
+func (c *Controller) cooldownGroupRemaining(action *v1alpha1.HealingAction) time.Duration {
+	group := cooldownGroup(action)
+	if c.config.CooldownGroupWindow <= 0 || group == "" {
+		return 0
+	}
+	value, ok := c.cooldownGroups.Load(group)
+	if !ok {
+		return 0
+	}
+	return time.Until(value.(time.Time).Add(c.config.CooldownGroupWindow))
+}
+
+// pruneCooldownGroups forgets groups whose cooldown has expired
+func (c *Controller) pruneCooldownGroups(now time.Time) {
+	c.cooldownGroups.Range(func(key, value interface{}) bool {
+		if now.Sub(value.(time.Time)) >= c.config.CooldownGroupWindow {
+			c.cooldownGroups.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,83 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestCooldownGroups(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	safetyCtrl := NewController(client, config.SafetyConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{
+			FailureThreshold: 5,
+			SuccessThreshold: 1,
+			Timeout:          time.Minute,
+		},
+		CooldownGroupWindow: 10 * time.Minute,
+	}, nil, nil)
+
+	newAction := func(policy, namespace, group string) *v1alpha1.HealingAction {
+		action := &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-action", Namespace: namespace},
+			Spec: v1alpha1.HealingActionSpec{
+				PolicyRef:      v1alpha1.PolicyReference{Name: policy, Namespace: namespace},
+				TargetResource: v1alpha1.TargetResource{Kind: "Pod", Name: policy + "-1", Namespace: namespace},
+				Action:         v1alpha1.HealingActionTemplate{Name: "restart", Type: "restart"},
+			},
+		}
+		if group != "" {
+			action.Labels = map[string]string{kubetypes.LabelCooldownGroup: group}
+		}
+		return action
+	}
+	record := func(action *v1alpha1.HealingAction, success bool) {
+		result := &kubetypes.ActionResult{Success: success, StartTime: time.Now(), EndTime: time.Now()}
+		if !success {
+			result.Error = fmt.Errorf("test error")
+		}
+		safetyCtrl.RecordAction(context.Background(), action, result)
+	}
+	validate := func(action *v1alpha1.HealingAction) *kubetypes.ValidationResult {
+		result, err := safetyCtrl.ValidateAction(context.Background(), action)
+		require.NoError(t, err)
+		return result
+	}
+
+	// A failed action holds back the group as well
+	record(newAction("frontend", "shop", "shop"), false)
+
+	// Every policy of the group is held back, in any namespace
+	result := validate(newAction("checkout", "shop", "shop"))
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "Cooldown group shop is in cooldown")
+	assert.False(t, validate(newAction("payments", "billing", "shop")).Valid)
+
+	// Other groups and ungrouped policies are not affected
+	assert.True(t, validate(newAction("checkout", "shop", "blog")).Valid)
+	assert.True(t, validate(newAction("checkout", "shop", "")).Valid)
+
+	// Dry runs do not start a cooldown
+	dryRun := newAction("frontend", "blog", "blog")
+	dryRun.Spec.DryRun = true
+	record(dryRun, true)
+	assert.True(t, validate(newAction("comments", "blog", "blog")).Valid)
+
+	// Expired cooldowns are pruned
+	safetyCtrl.pruneCooldownGroups(time.Now().Add(11 * time.Minute))
+	assert.True(t, validate(newAction("checkout", "shop", "shop")).Valid)
+}
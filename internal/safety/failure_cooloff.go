@@ -0,0 +1,58 @@
+package safety
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+)
+
+// failureCooloffKey identifies an action type on a target, regardless of
+// the policy that created the action
+func failureCooloffKey(action *v1alpha1.HealingAction) string {
+	return fmt.Sprintf("%s/%s/%s|%s",
+		action.Spec.TargetResource.Kind,
+		action.Spec.TargetResource.Namespace,
+		action.Spec.TargetResource.Name,
+		action.Spec.Action.Type)
+}
+
+// recordFailureCooloff starts the failure cool-off of the action's type on
+// its target when the action failed, and ends it when the action succeeded
+func (c *Controller) recordFailureCooloff(action *v1alpha1.HealingAction, result *kubetypes.ActionResult) {
+	if c.config.FailureCooloff <= 0 || action.Spec.DryRun {
+		return
+	}
+	if result.Success {
+		c.failureCooloffs.Delete(failureCooloffKey(action))
+		return
+	}
+	c.failureCooloffs.Store(failureCooloffKey(action), result.EndTime)
+}
+
+// failureCooloffRemaining returns how long the action's type stays in
+// failure cool-off on its target. Actions annotated with
+// AnnotationIgnoreFailureCooloff are never held back.
+func (c *Controller) failureCooloffRemaining(action *v1alpha1.HealingAction) time.Duration {
+	if c.config.FailureCooloff <= 0 || action.Annotations[kubetypes.AnnotationIgnoreFailureCooloff] == "true" {
+		return 0
+	}
+	value, ok := c.failureCooloffs.Load(failureCooloffKey(action))
+	if !ok {
+		return 0
+	}
+	start := value.(time.Time)
+	return time.Until(start.Add(c.failureCooloff(start)))
+}
+
+// pruneFailureCooloffs forgets failures whose cool-off has expired
+func (c *Controller) pruneFailureCooloffs(now time.Time) {
+	c.failureCooloffs.Range(func(key, value interface{}) bool {
+		start := value.(time.Time)
+		if now.Sub(start) >= c.failureCooloff(start) {
+			c.failureCooloffs.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,87 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	kubetypes "github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func TestFailureCooloff(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	safetyCtrl := NewController(client, config.SafetyConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{
+			FailureThreshold: 5,
+			SuccessThreshold: 1,
+			Timeout:          time.Minute,
+		},
+		FailureCooloff: 30 * time.Minute,
+	}, nil, nil)
+
+	newAction := func(policy, pod, actionType string) *v1alpha1.HealingAction {
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-action", Namespace: "default"},
+			Spec: v1alpha1.HealingActionSpec{
+				PolicyRef:      v1alpha1.PolicyReference{Name: policy, Namespace: "default"},
+				TargetResource: v1alpha1.TargetResource{Kind: "Pod", Name: pod, Namespace: "default"},
+				Action:         v1alpha1.HealingActionTemplate{Name: actionType, Type: actionType},
+			},
+		}
+	}
+	record := func(action *v1alpha1.HealingAction, success bool) {
+		result := &kubetypes.ActionResult{Success: success, StartTime: time.Now(), EndTime: time.Now()}
+		if !success {
+			result.Error = fmt.Errorf("test error")
+		}
+		safetyCtrl.RecordAction(context.Background(), action, result)
+	}
+	validate := func(action *v1alpha1.HealingAction) *kubetypes.ValidationResult {
+		result, err := safetyCtrl.ValidateAction(context.Background(), action)
+		require.NoError(t, err)
+		return result
+	}
+
+	record(newAction("policy-a", "web-1", "restart"), false)
+
+	// The same action type on the target is held back, whichever policy asks
+	result := validate(newAction("policy-b", "web-1", "restart"))
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Reason, "restart actions on the target are in failure cool-off")
+
+	// Other action types and other targets are not affected
+	assert.True(t, validate(newAction("policy-a", "web-1", "delete")).Valid)
+	assert.True(t, validate(newAction("policy-a", "web-2", "restart")).Valid)
+
+	// The annotation overrides the cool-off
+	override := newAction("policy-a", "web-1", "restart")
+	override.Annotations = map[string]string{kubetypes.AnnotationIgnoreFailureCooloff: "true"}
+	assert.True(t, validate(override).Valid)
+
+	// A success ends the cool-off
+	record(override, true)
+	assert.True(t, validate(newAction("policy-a", "web-1", "restart")).Valid)
+
+	// Dry-run failures do not start a cool-off
+	dryRun := newAction("policy-a", "web-3", "restart")
+	dryRun.Spec.DryRun = true
+	record(dryRun, false)
+	assert.True(t, validate(newAction("policy-a", "web-3", "restart")).Valid)
+
+	// Expired cool-offs are pruned
+	record(newAction("policy-a", "web-4", "restart"), false)
+	safetyCtrl.pruneFailureCooloffs(time.Now().Add(31 * time.Minute))
+	assert.True(t, validate(newAction("policy-a", "web-4", "restart")).Valid)
+}
@@ -21,10 +21,23 @@ type ActionStore interface {
 	// GetLastAction returns the most recent action for a policy
 	GetLastAction(ctx context.Context, policyKey string) (*ActionRecord, error)
 
+	// GetActionsBetween returns the actions of all policies recorded after
+	// since and up to until, oldest first
+	GetActionsBetween(ctx context.Context, since, until time.Time) ([]ActionRecord, error)
+
 	// CleanupOldRecords removes records older than the retention period
 	CleanupOldRecords(ctx context.Context, before time.Time) error
 }
 
+// RateLimitReserver is implemented by action stores that check the rate
+// limit of a policy and count an action against it in one atomic step
+type RateLimitReserver interface {
+	// ReserveAction counts an action of the policy at the time if fewer than
+	// limit actions were counted in the hour before, and reports whether it
+	// did along with the count it found
+	ReserveAction(ctx context.Context, policyKey string, at time.Time, limit int) (bool, int, error)
+}
+
 // ActionRecord represents a recorded healing action
 type ActionRecord struct {
 	ID         string
@@ -38,6 +51,19 @@ type ActionRecord struct {
 	DurationMS int64
 	ApprovedBy string
 	DryRun     bool
+
+	// Provenance of the action
+	PolicyGeneration int64
+	AIModel          string
+	PromptHash       string
+
+	// KeyID and Signature are set when audit signing is enabled
+	KeyID     string
+	Signature string
+
+	// Reserved is set when the action was counted against the rate limit
+	// before it ran, so the store does not count it again
+	Reserved bool `json:"-"`
 }
 
 // AuditLogger defines the interface for audit logging
@@ -96,6 +96,27 @@ func (s *InMemoryActionStore) GetLastAction(ctx context.Context, policyKey strin
 	return &record, nil
 }
 
+// GetActionsBetween returns the actions of all policies recorded after since
+// and up to until, oldest first
+func (s *InMemoryActionStore) GetActionsBetween(ctx context.Context, since, until time.Time) ([]ActionRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []ActionRecord
+	for _, records := range s.records {
+		for _, record := range records {
+			if record.Timestamp.After(since) && !record.Timestamp.After(until) {
+				result = append(result, record)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
 // CleanupOldRecords removes records older than the retention period
 func (s *InMemoryActionStore) CleanupOldRecords(ctx context.Context, before time.Time) error {
 	s.mu.Lock()
@@ -221,3 +221,28 @@ func TestInMemoryActionStore_MultiplePolices(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 5, count)
 }
+
+func TestInMemoryActionStore_GetActionsBetween(t *testing.T) {
+	store := NewInMemoryActionStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, r := range []struct {
+		policy string
+		name   string
+		age    time.Duration
+	}{
+		{"default/a", "too-old", 2 * time.Hour},
+		{"default/a", "older", 50 * time.Minute},
+		{"default/b", "newer", 10 * time.Minute},
+		{"default/b", "boundary", time.Hour},
+	} {
+		require.NoError(t, store.RecordAction(ctx, ActionRecord{PolicyKey: r.policy, ActionName: r.name, Timestamp: now.Add(-r.age)}))
+	}
+
+	records, err := store.GetActionsBetween(ctx, now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "older", records[0].ActionName)
+	assert.Equal(t, "newer", records[1].ActionName)
+}
@@ -0,0 +1,177 @@
+package safety
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+const (
+	// PatchRuleSelector denies patches touching a selector
+	PatchRuleSelector = "selector"
+
+	// PatchRuleNotAllowed denies patches outside the kind's mutable fields
+	PatchRuleNotAllowed = "not-allowed"
+
+	// PatchRuleSecurityContext denies patches that weaken a securityContext
+	PatchRuleSecurityContext = "security-context"
+)
+
+// commonMutableFields may be patched on every kind
+var commonMutableFields = []string{
+	"metadata.labels",
+	"metadata.annotations",
+}
+
+// mutableFields lists, per kind, the fields below which patches proposed by
+// the AI may set values. A patch operation must target one of these fields
+// or a field below it; replacing a parent would drop its other fields.
+var mutableFields = map[string][]string{
+	"Deployment": {
+		"spec.replicas",
+		"spec.minReadySeconds",
+		"spec.progressDeadlineSeconds",
+		"spec.strategy.rollingUpdate",
+		"spec.template.metadata.annotations",
+		"spec.template.spec.terminationGracePeriodSeconds",
+		"spec.template.spec.securityContext",
+	},
+	"StatefulSet": {
+		"spec.replicas",
+		"spec.minReadySeconds",
+		"spec.updateStrategy.rollingUpdate",
+		"spec.template.metadata.annotations",
+		"spec.template.spec.terminationGracePeriodSeconds",
+		"spec.template.spec.securityContext",
+	},
+	"DaemonSet": {
+		"spec.minReadySeconds",
+		"spec.updateStrategy.rollingUpdate",
+		"spec.template.metadata.annotations",
+		"spec.template.spec.terminationGracePeriodSeconds",
+		"spec.template.spec.securityContext",
+	},
+	"Pod": {
+		"spec.activeDeadlineSeconds",
+	},
+	"HorizontalPodAutoscaler": {
+		"spec.minReplicas",
+		"spec.maxReplicas",
+	},
+}
+
+// weakenedSecurityValues maps securityContext fields to the values that lift
+// a restriction. Since the current value is not known, setting one of these
+// values is treated as weakening the securityContext.
+var weakenedSecurityValues = map[string]func(value interface{}) bool{
+	"privileged":               isValue(true),
+	"allowPrivilegeEscalation": isValue(true),
+	"runAsNonRoot":             isValue(false),
+	"readOnlyRootFilesystem":   isValue(false),
+	"runAsUser":                isValue(float64(0)),
+	"runAsGroup":               isValue(float64(0)),
+	"procMount":                isValue("Unmasked"),
+	"seccompProfile.type":      isValue("Unconfined"),
+	"appArmorProfile.type":     isValue("Unconfined"),
+	"capabilities.add":         func(value interface{}) bool { return true },
+}
+
+// PatchViolation describes why a patch was denied
+type PatchViolation struct {
+	// Field the violating operation targets
+	Field string
+
+	// Rule that denied the patch
+	Rule string
+
+	// Message explaining the denial
+	Message string
+}
+
+func (v *PatchViolation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// ValidatePatch checks patch operations proposed for a resource of kind
+// against the kind's mutable fields. Selectors are never patched and
+// securityContext restrictions are never reduced. It returns the first
+// violation, or nil if the patch is allowed.
+func ValidatePatch(kind string, patches []v1alpha1.PatchOperation) *PatchViolation {
+	allowed := append(append([]string(nil), commonMutableFields...), mutableFields[kind]...)
+
+	for _, patch := range patches {
+		field := strings.Join(patch.Path, ".")
+		if len(patch.Path) == 0 {
+			return &PatchViolation{Field: field, Rule: PatchRuleNotAllowed, Message: "patch has no path"}
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(patch.Value), &value); err != nil {
+			value = patch.Value
+		}
+
+		for _, segment := range patch.Path {
+			if segment == "selector" {
+				return &PatchViolation{Field: field, Rule: PatchRuleSelector, Message: "selectors must never be patched"}
+			}
+		}
+		if !hasFieldPrefix(field, allowed) {
+			return &PatchViolation{Field: field, Rule: PatchRuleNotAllowed,
+				Message: fmt.Sprintf("field is not mutable for %s", kindOrUnknown(kind))}
+		}
+
+		if violation := checkSecurityContext(patch.Path, value); violation != nil {
+			return violation
+		}
+	}
+	return nil
+}
+
+// checkSecurityContext denies operations that weaken a securityContext set
+// at or below path
+func checkSecurityContext(path []string, value interface{}) *PatchViolation {
+	field := strings.Join(path, ".")
+	for i, segment := range path {
+		if segment != "securityContext" {
+			continue
+		}
+		// Replacing the whole securityContext, or a nested object of it,
+		// would drop the restrictions it currently sets
+		if _, isObject := value.(map[string]interface{}); isObject || value == nil {
+			return &PatchViolation{Field: field, Rule: PatchRuleSecurityContext,
+				Message: "securityContext fields must be set individually"}
+		}
+		subfield := strings.Join(path[i+1:], ".")
+		if weakens, ok := weakenedSecurityValues[subfield]; ok && weakens(value) {
+			return &PatchViolation{Field: field, Rule: PatchRuleSecurityContext,
+				Message: fmt.Sprintf("setting %s to %v reduces securityContext restrictions", subfield, value)}
+		}
+	}
+	return nil
+}
+
+// hasFieldPrefix reports whether field is one of prefixes or below one
+func hasFieldPrefix(field string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if field == prefix || strings.HasPrefix(field, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isValue returns a function reporting whether a value equals want
+func isValue(want interface{}) func(value interface{}) bool {
+	return func(value interface{}) bool {
+		return value == want
+	}
+}
+
+func kindOrUnknown(kind string) string {
+	if kind == "" {
+		return "unknown kinds"
+	}
+	return kind
+}
@@ -0,0 +1,109 @@
+package safety
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func patchOp(value string, path ...string) v1alpha1.PatchOperation {
+	return v1alpha1.PatchOperation{Path: path, Value: value}
+}
+
+func TestValidatePatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		patches   []v1alpha1.PatchOperation
+		wantRule  string
+		wantField string
+	}{
+		{
+			name: "allowed deployment fields",
+			kind: "Deployment",
+			patches: []v1alpha1.PatchOperation{
+				patchOp("5", "spec", "replicas"),
+				patchOp(`"true"`, "spec", "template", "metadata", "annotations", "sidecar.istio.io/inject"),
+				patchOp("true", "spec", "template", "spec", "securityContext", "runAsNonRoot"),
+				patchOp("1000", "spec", "template", "spec", "securityContext", "runAsUser"),
+			},
+		},
+		{
+			name:      "selector",
+			kind:      "Deployment",
+			patches:   []v1alpha1.PatchOperation{patchOp(`{"app":"other"}`, "spec", "selector", "matchLabels")},
+			wantRule:  PatchRuleSelector,
+			wantField: "spec.selector.matchLabels",
+		},
+		{
+			name:      "replacing a parent of mutable fields",
+			kind:      "Deployment",
+			patches:   []v1alpha1.PatchOperation{patchOp(`{"replicas":5}`, "spec")},
+			wantRule:  PatchRuleNotAllowed,
+			wantField: "spec",
+		},
+		{
+			name:      "field not mutable for the kind",
+			kind:      "DaemonSet",
+			patches:   []v1alpha1.PatchOperation{patchOp("5", "spec", "replicas")},
+			wantRule:  PatchRuleNotAllowed,
+			wantField: "spec.replicas",
+		},
+		{
+			name:    "unknown kind metadata",
+			kind:    "Widget",
+			patches: []v1alpha1.PatchOperation{patchOp("web", "metadata", "labels", "tier")},
+		},
+		{
+			name:      "empty path",
+			kind:      "Deployment",
+			patches:   []v1alpha1.PatchOperation{patchOp("5")},
+			wantRule:  PatchRuleNotAllowed,
+			wantField: "",
+		},
+		{
+			name:      "root allowed",
+			kind:      "Deployment",
+			patches:   []v1alpha1.PatchOperation{patchOp("false", "spec", "template", "spec", "securityContext", "runAsNonRoot")},
+			wantRule:  PatchRuleSecurityContext,
+			wantField: "spec.template.spec.securityContext.runAsNonRoot",
+		},
+		{
+			name:      "root user",
+			kind:      "StatefulSet",
+			patches:   []v1alpha1.PatchOperation{patchOp("0", "spec", "template", "spec", "securityContext", "runAsUser")},
+			wantRule:  PatchRuleSecurityContext,
+			wantField: "spec.template.spec.securityContext.runAsUser",
+		},
+		{
+			name:      "unconfined seccomp",
+			kind:      "Deployment",
+			patches:   []v1alpha1.PatchOperation{patchOp("Unconfined", "spec", "template", "spec", "securityContext", "seccompProfile", "type")},
+			wantRule:  PatchRuleSecurityContext,
+			wantField: "spec.template.spec.securityContext.seccompProfile.type",
+		},
+		{
+			name:      "replacing the securityContext",
+			kind:      "Deployment",
+			patches:   []v1alpha1.PatchOperation{patchOp(`{"runAsUser":1000}`, "spec", "template", "spec", "securityContext")},
+			wantRule:  PatchRuleSecurityContext,
+			wantField: "spec.template.spec.securityContext",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := ValidatePatch(tt.kind, tt.patches)
+			if tt.wantRule == "" {
+				assert.Nil(t, violation)
+				return
+			}
+			require.NotNil(t, violation)
+			assert.Equal(t, tt.wantRule, violation.Rule)
+			assert.Equal(t, tt.wantField, violation.Field)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package safety
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// counterWindow is how long the per-minute action counters live. It covers
+// the hour of the rate limit.
+const counterWindow = time.Hour + time.Minute
+
+// cleanupScript removes the records of a policy up to a score, and the
+// policy from the policy set once it has none, atomically so that a record
+// of another manager is never orphaned
+var cleanupScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if redis.call('ZCARD', KEYS[1]) == 0 then
+	redis.call('SREM', KEYS[2], ARGV[2])
+end
+return 0
+`)
+
+// reserveScript counts an action against the rate limit if the per-minute
+// counters of the hour, the current minute last, are below the limit. It
+// returns whether the action was counted and the count it found.
+var reserveScript = redis.NewScript(`
+local count = 0
+for i = 1, #KEYS do
+	local n = redis.call('GET', KEYS[i])
+	if n then
+		count = count + tonumber(n)
+	end
+end
+if count >= tonumber(ARGV[1]) then
+	return {0, count}
+end
+redis.call('INCR', KEYS[#KEYS])
+redis.call('EXPIREAT', KEYS[#KEYS], ARGV[2])
+return {1, count}
+`)
+
+// RedisActionStore keeps action records and rate limit counters in Redis or
+// Valkey, so that every manager sharing the server counts the same actions.
+// Records are sorted sets scored by timestamp; action counts come from
+// per-minute counters that expire after the rate limit window. Actions are
+// reserved against the rate limit with a script, so managers sharing the
+// server cannot both take the last action of the hour.
+type RedisActionStore struct {
+	client redis.UniversalClient
+	prefix string
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewRedisActionStore creates an action store on a Redis client. Every key
+// starts with keyPrefix.
+func NewRedisActionStore(c redis.UniversalClient, keyPrefix string) *RedisActionStore {
+	return &RedisActionStore{
+		client: c,
+		prefix: keyPrefix,
+		now:    time.Now,
+	}
+}
+
+// LoadRedisActionStore connects to the server of the configured Secret
+func LoadRedisActionStore(ctx context.Context, reader client.Reader, cfg config.RedisStoreConfig) (*RedisActionStore, error) {
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Name: cfg.SecretName, Namespace: cfg.SecretNamespace}
+	if err := reader.Get(ctx, name, secret); err != nil {
+		return nil, fmt.Errorf("failed to get redis connection secret %s: %w", name, err)
+	}
+
+	options, err := redisOptions(secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis connection secret %s: %w", name, err)
+	}
+	if cfg.DialTimeout > 0 {
+		options.DialTimeout = cfg.DialTimeout
+	}
+
+	c := redis.NewClient(options)
+	if err := c.Ping(ctx).Err(); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", options.Addr, err)
+	}
+	return NewRedisActionStore(c, cfg.KeyPrefix), nil
+}
+
+// redisOptions reads the connection options from the data of a Secret
+func redisOptions(data map[string][]byte) (*redis.Options, error) {
+	var options *redis.Options
+	if url, ok := data["url"]; ok {
+		var err error
+		if options, err = redis.ParseURL(string(url)); err != nil {
+			return nil, err
+		}
+	} else if address, ok := data["address"]; ok {
+		options = &redis.Options{Addr: string(address)}
+	} else {
+		return nil, fmt.Errorf("no url or address key")
+	}
+
+	if username, ok := data["username"]; ok {
+		options.Username = string(username)
+	}
+	if password, ok := data["password"]; ok {
+		options.Password = string(password)
+	}
+	if db, ok := data["db"]; ok {
+		n, err := strconv.Atoi(string(db))
+		if err != nil {
+			return nil, fmt.Errorf("invalid db %q: %w", db, err)
+		}
+		options.DB = n
+	}
+	if useTLS, ok := data["tls"]; ok {
+		enabled, err := strconv.ParseBool(string(useTLS))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls %q: %w", useTLS, err)
+		}
+		if enabled && options.TLSConfig == nil {
+			options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		} else if !enabled {
+			options.TLSConfig = nil
+		}
+	}
+	return options, nil
+}
+
+// Close closes the connection to the server
+func (s *RedisActionStore) Close() error {
+	return s.client.Close()
+}
+
+// RecordAction stores an executed action and counts it
+func (s *RedisActionStore) RecordAction(ctx context.Context, record ActionRecord) error {
+	if record.ID == "" {
+		record.ID = generateID()
+	}
+	member, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode action record: %w", err)
+	}
+
+	z := redis.Z{Score: score(record.Timestamp), Member: member}
+	minute := record.Timestamp.Truncate(time.Minute)
+	expires := minute.Add(counterWindow)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, s.policyKey(record.PolicyKey), z)
+		pipe.ZAdd(ctx, s.key("actions"), z)
+		pipe.SAdd(ctx, s.key("policies"), record.PolicyKey)
+		if !record.Reserved && expires.After(s.now()) {
+			counter := s.counterKey(record.PolicyKey, minute)
+			pipe.Incr(ctx, counter)
+			pipe.ExpireAt(ctx, counter, expires)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record action: %w", err)
+	}
+	return nil
+}
+
+// GetActionCount returns the number of actions for a policy in the time
+// window. Windows within the last hour are counted from the counters, which
+// round since down to the minute and so may count up to a minute of older
+// actions.
+func (s *RedisActionStore) GetActionCount(ctx context.Context, policyKey string, since time.Time) (int, error) {
+	now := s.now()
+	first := since.Truncate(time.Minute)
+	if now.Sub(first) >= counterWindow {
+		count, err := s.client.ZCount(ctx, s.policyKey(policyKey), strconv.FormatFloat(score(since), 'f', -1, 64), "+inf").Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to count actions: %w", err)
+		}
+		return int(count), nil
+	}
+
+	values, err := s.client.MGet(ctx, s.counterKeys(policyKey, first, now)...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count actions: %w", err)
+	}
+
+	count := 0
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		n, err := strconv.Atoi(value.(string))
+		if err != nil {
+			return 0, fmt.Errorf("invalid action counter %q: %w", value, err)
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// ReserveAction counts an action of the policy at the time if fewer than
+// limit actions were counted in the hour before. The check and the count
+// are one script, so concurrent reservations never pass the limit.
+func (s *RedisActionStore) ReserveAction(ctx context.Context, policyKey string, at time.Time, limit int) (bool, int, error) {
+	minute := at.Truncate(time.Minute)
+	keys := s.counterKeys(policyKey, at.Add(-time.Hour).Truncate(time.Minute), minute)
+	expires := minute.Add(counterWindow).Unix()
+	result, err := reserveScript.Run(ctx, s.client, keys, limit, expires).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to reserve action: %w", err)
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected reservation result %v", result)
+	}
+	return result[0] == 1, int(result[1]), nil
+}
+
+// GetRecentActions returns recent actions for a policy, newest first
+func (s *RedisActionStore) GetRecentActions(ctx context.Context, policyKey string, limit int) ([]ActionRecord, error) {
+	if limit <= 0 {
+		return []ActionRecord{}, nil
+	}
+	members, err := s.client.ZRevRange(ctx, s.policyKey(policyKey), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent actions: %w", err)
+	}
+	return decodeRecords(members)
+}
+
+// GetLastAction returns the most recent action for a policy
+func (s *RedisActionStore) GetLastAction(ctx context.Context, policyKey string) (*ActionRecord, error) {
+	records, err := s.GetRecentActions(ctx, policyKey, 1)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+// GetActionsBetween returns the actions of all policies recorded after since
+// and up to until, oldest first
+func (s *RedisActionStore) GetActionsBetween(ctx context.Context, since, until time.Time) ([]ActionRecord, error) {
+	members, err := s.client.ZRangeByScore(ctx, s.key("actions"), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatFloat(score(since), 'f', -1, 64),
+		Max: strconv.FormatFloat(score(until), 'f', -1, 64),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actions: %w", err)
+	}
+	return decodeRecords(members)
+}
+
+// CleanupOldRecords removes records older than the retention period. The
+// counters expire on their own.
+func (s *RedisActionStore) CleanupOldRecords(ctx context.Context, before time.Time) error {
+	upTo := strconv.FormatFloat(score(before), 'f', -1, 64)
+	if err := s.client.ZRemRangeByScore(ctx, s.key("actions"), "-inf", upTo).Err(); err != nil {
+		return fmt.Errorf("failed to remove old actions: %w", err)
+	}
+
+	policies, err := s.client.SMembers(ctx, s.key("policies")).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list policies: %w", err)
+	}
+	for _, policyKey := range policies {
+		keys := []string{s.policyKey(policyKey), s.key("policies")}
+		if err := cleanupScript.Run(ctx, s.client, keys, upTo, policyKey).Err(); err != nil {
+			return fmt.Errorf("failed to remove old actions of policy %s: %w", policyKey, err)
+		}
+	}
+	return nil
+}
+
+func (s *RedisActionStore) key(name string) string {
+	return s.prefix + name
+}
+
+func (s *RedisActionStore) policyKey(policyKey string) string {
+	return s.prefix + "actions:" + policyKey
+}
+
+// counterKeys returns the counters of the minutes from first to last
+func (s *RedisActionStore) counterKeys(policyKey string, first, last time.Time) []string {
+	var keys []string
+	for minute := first; !minute.After(last); minute = minute.Add(time.Minute) {
+		keys = append(keys, s.counterKey(policyKey, minute))
+	}
+	return keys
+}
+
+func (s *RedisActionStore) counterKey(policyKey string, minute time.Time) string {
+	return s.prefix + "count:" + policyKey + ":" + strconv.FormatInt(minute.Unix()/60, 10)
+}
+
+// score orders records by timestamp to the microsecond, which a float64
+// holds exactly
+func score(t time.Time) float64 {
+	return float64(t.UnixMicro())
+}
+
+func decodeRecords(members []string) ([]ActionRecord, error) {
+	records := make([]ActionRecord, 0, len(members))
+	for _, member := range members {
+		var record ActionRecord
+		if err := json.Unmarshal([]byte(member), &record); err != nil {
+			return nil, fmt.Errorf("invalid action record: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
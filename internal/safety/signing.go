@@ -0,0 +1,182 @@
+package safety
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// AuditSigner signs action records so the audit trail is tamper-evident.
+// Keys are cosign-style PEM private keys (ECDSA P-256 or Ed25519); encrypted
+// cosign keys must be decrypted before they are stored in the Secret.
+type AuditSigner struct {
+	key   crypto.Signer
+	keyID string
+}
+
+// NewAuditSigner creates a signer from a PEM-encoded private key
+func NewAuditSigner(pemData []byte) (*AuditSigner, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in signing key")
+	}
+	if strings.HasPrefix(block.Type, "ENCRYPTED") {
+		return nil, fmt.Errorf("encrypted signing keys are not supported, store the decrypted key")
+	}
+
+	var key crypto.Signer
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		ecKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		key = ecKey
+	default:
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
+		}
+		key = signer
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported ECDSA curve %s, use P-256", k.Curve.Params().Name)
+		}
+	case ed25519.PrivateKey:
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	keyID, err := publicKeyID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &AuditSigner{key: key, keyID: keyID}, nil
+}
+
+// LoadAuditSigner reads the signing key from the configured Secret
+func LoadAuditSigner(ctx context.Context, reader client.Reader, cfg config.AuditSigningConfig) (*AuditSigner, error) {
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Name: cfg.SecretName, Namespace: cfg.SecretNamespace}
+	if err := reader.Get(ctx, name, secret); err != nil {
+		return nil, fmt.Errorf("failed to get signing key secret %s: %w", name, err)
+	}
+
+	data, ok := secret.Data[cfg.SecretKey]
+	if !ok {
+		return nil, fmt.Errorf("signing key secret %s has no key %q", name, cfg.SecretKey)
+	}
+	return NewAuditSigner(data)
+}
+
+// KeyID identifies the public key of the signer
+func (s *AuditSigner) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey returns the key used to verify signatures
+func (s *AuditSigner) PublicKey() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// Sign sets the KeyID and Signature of a record
+func (s *AuditSigner) Sign(record *ActionRecord) error {
+	record.KeyID = s.keyID
+	payload, err := recordPayload(*record)
+	if err != nil {
+		return err
+	}
+
+	var signature []byte
+	switch key := s.key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, payload)
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(payload)
+		signature, err = ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			return fmt.Errorf("failed to sign record: %w", err)
+		}
+	}
+
+	record.Signature = base64.StdEncoding.EncodeToString(signature)
+	return nil
+}
+
+// VerifyActionRecord checks a record's signature against a public key
+func VerifyActionRecord(publicKey crypto.PublicKey, record ActionRecord) error {
+	if record.Signature == "" {
+		return fmt.Errorf("record is not signed")
+	}
+	signature, err := base64.StdEncoding.DecodeString(record.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	payload, err := recordPayload(record)
+	if err != nil {
+		return err
+	}
+
+	valid := false
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, payload, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		valid = ecdsa.VerifyASN1(key, digest[:], signature)
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	if !valid {
+		return fmt.Errorf("signature verification failed for record %s", record.ID)
+	}
+	return nil
+}
+
+// recordPayload is the canonical encoding of a record that is signed
+func recordPayload(record ActionRecord) ([]byte, error) {
+	record.Signature = ""
+	// Timestamps are signed in UTC so the payload does not depend on the
+	// location attached to the time
+	record.Timestamp = record.Timestamp.UTC()
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode record: %w", err)
+	}
+	return payload, nil
+}
+
+// publicKeyID derives a short identifier from a public key
+func publicKeyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,135 @@
+package safety
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func ecdsaKeyPEM(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func ed25519KeyPEM(t *testing.T) []byte {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func testRecord() ActionRecord {
+	return ActionRecord{
+		ID:               "record-1",
+		PolicyKey:        "default/policy",
+		ActionName:       "restart",
+		ActionType:       "restart",
+		TargetKey:        "Pod/default/app",
+		Success:          true,
+		Timestamp:        time.Now(),
+		PolicyGeneration: 4,
+		AIModel:          "llama2:7b",
+		PromptHash:       "sha256:abc",
+	}
+}
+
+func TestAuditSigner_SignAndVerify(t *testing.T) {
+	tests := []struct {
+		name string
+		key  func(t *testing.T) []byte
+	}{
+		{name: "ecdsa", key: ecdsaKeyPEM},
+		{name: "ed25519", key: ed25519KeyPEM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewAuditSigner(tt.key(t))
+			require.NoError(t, err)
+
+			record := testRecord()
+			require.NoError(t, signer.Sign(&record))
+			assert.Equal(t, signer.KeyID(), record.KeyID)
+			assert.NotEmpty(t, record.Signature)
+
+			assert.NoError(t, VerifyActionRecord(signer.PublicKey(), record))
+
+			tampered := record
+			tampered.PolicyGeneration = 5
+			assert.Error(t, VerifyActionRecord(signer.PublicKey(), tampered))
+
+			tampered = record
+			tampered.Success = false
+			assert.Error(t, VerifyActionRecord(signer.PublicKey(), tampered))
+		})
+	}
+}
+
+func TestVerifyActionRecord_Unsigned(t *testing.T) {
+	signer, err := NewAuditSigner(ecdsaKeyPEM(t))
+	require.NoError(t, err)
+
+	assert.Error(t, VerifyActionRecord(signer.PublicKey(), testRecord()))
+}
+
+func TestNewAuditSigner_InvalidKeys(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p384DER, err := x509.MarshalECPrivateKey(p384)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		pem  []byte
+	}{
+		{name: "not PEM", pem: []byte("not a key")},
+		{name: "encrypted", pem: pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("x")})},
+		{name: "unsupported curve", pem: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: p384DER})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuditSigner(tt.pem)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadAuditSigner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "signing", Namespace: "kubeskippy-system"},
+		Data:       map[string][]byte{"cosign.key": ecdsaKeyPEM(t)},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	cfg := config.AuditSigningConfig{Enabled: true, SecretName: "signing", SecretNamespace: "kubeskippy-system", SecretKey: "cosign.key"}
+	signer, err := LoadAuditSigner(context.Background(), reader, cfg)
+	require.NoError(t, err)
+	assert.NotEmpty(t, signer.KeyID())
+
+	cfg.SecretKey = "missing"
+	_, err = LoadAuditSigner(context.Background(), reader, cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// defaultTopologyKey is the well-known node label for availability zones
+const defaultTopologyKey = "topology.kubernetes.io/zone"
+
+// validateZoneSpread ensures restarting or deleting a pod does not remove the
+// last ready replica of its workload in the pod's zone
+func (c *Controller) validateZoneSpread(ctx context.Context, action *v1alpha1.HealingAction) error {
+	if !c.config.ZoneSpread.Enabled || c.client == nil {
+		return nil
+	}
+	if action.Spec.TargetResource.Kind != "Pod" {
+		return nil
+	}
+	if action.Spec.Action.Type != "restart" && action.Spec.Action.Type != "delete" {
+		return nil
+	}
+
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{
+		Namespace: action.Spec.TargetResource.Namespace,
+		Name:      action.Spec.TargetResource.Name,
+	}
+	if err := c.client.Get(ctx, key, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get target pod: %w", err)
+	}
+
+	// Removing a pod that is not serving does not reduce zone capacity
+	if !podReady(pod) {
+		return nil
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+
+	topologyKey := c.config.ZoneSpread.TopologyKey
+	if topologyKey == "" {
+		topologyKey = defaultTopologyKey
+	}
+	minReplicas := c.config.ZoneSpread.MinReplicasPerZone
+	if minReplicas <= 0 {
+		minReplicas = 1
+	}
+
+	zones := make(map[string]string)
+	zone, err := c.nodeZone(ctx, pod.Spec.NodeName, topologyKey, zones)
+	if err != nil {
+		return err
+	}
+	if zone == "" {
+		return nil
+	}
+
+	siblings := &corev1.PodList{}
+	if err := c.client.List(ctx, siblings, client.InNamespace(pod.Namespace)); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	remaining := 0
+	for i := range siblings.Items {
+		sibling := &siblings.Items[i]
+		if sibling.UID == pod.UID || sibling.Name == pod.Name {
+			continue
+		}
+		ref := metav1.GetControllerOf(sibling)
+		if ref == nil || ref.UID != owner.UID || !podReady(sibling) {
+			continue
+		}
+		siblingZone, err := c.nodeZone(ctx, sibling.Spec.NodeName, topologyKey, zones)
+		if err != nil {
+			return err
+		}
+		if siblingZone == zone {
+			remaining++
+		}
+	}
+
+	if remaining < minReplicas {
+		return fmt.Errorf("action would leave %d ready replicas of %s/%s in zone %s (minimum %d)",
+			remaining, owner.Kind, owner.Name, zone, minReplicas)
+	}
+
+	return nil
+}
+
+// nodeZone returns the topology label of a node, caching lookups in zones
+func (c *Controller) nodeZone(ctx context.Context, nodeName, topologyKey string, zones map[string]string) (string, error) {
+	if nodeName == "" {
+		return "", nil
+	}
+	if zone, ok := zones[nodeName]; ok {
+		return zone, nil
+	}
+
+	node := &corev1.Node{}
+	if err := c.client.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			zones[nodeName] = ""
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	zone := node.Labels[topologyKey]
+	zones[nodeName] = zone
+	return zone, nil
+}
+
+// podReady reports whether the pod is running and ready
+func podReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
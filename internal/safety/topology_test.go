@@ -0,0 +1,148 @@
+package safety
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func zoneNode(name, zone string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{defaultTopologyKey: zone},
+		},
+	}
+}
+
+func zonePod(name, node string, ready bool) *corev1.Pod {
+	isController := true
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "apps",
+			UID:       types.UID(name),
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", UID: "rs-web", Controller: &isController},
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestController_ValidateZoneSpread(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	nodes := []client.Object{zoneNode("node-a", "zone-a"), zoneNode("node-b", "zone-b")}
+
+	tests := []struct {
+		name       string
+		pods       []client.Object
+		actionType string
+		enabled    bool
+		minPerZone int
+		expectErr  bool
+	}{
+		{
+			name:       "last ready replica in zone is blocked",
+			pods:       []client.Object{zonePod("web-1", "node-a", true), zonePod("web-2", "node-b", true)},
+			actionType: "restart",
+			enabled:    true,
+			expectErr:  true,
+		},
+		{
+			name:       "another ready replica in zone allows action",
+			pods:       []client.Object{zonePod("web-1", "node-a", true), zonePod("web-2", "node-a", true)},
+			actionType: "delete",
+			enabled:    true,
+			expectErr:  false,
+		},
+		{
+			name:       "unready sibling does not count",
+			pods:       []client.Object{zonePod("web-1", "node-a", true), zonePod("web-2", "node-a", false)},
+			actionType: "restart",
+			enabled:    true,
+			expectErr:  true,
+		},
+		{
+			name:       "unready target is always allowed",
+			pods:       []client.Object{zonePod("web-1", "node-a", false)},
+			actionType: "restart",
+			enabled:    true,
+			expectErr:  false,
+		},
+		{
+			name:       "minimum per zone is configurable",
+			pods:       []client.Object{zonePod("web-1", "node-a", true), zonePod("web-2", "node-a", true)},
+			actionType: "restart",
+			enabled:    true,
+			minPerZone: 2,
+			expectErr:  true,
+		},
+		{
+			name:       "scale actions are not checked",
+			pods:       []client.Object{zonePod("web-1", "node-a", true)},
+			actionType: "scale",
+			enabled:    true,
+			expectErr:  false,
+		},
+		{
+			name:       "disabled",
+			pods:       []client.Object{zonePod("web-1", "node-a", true)},
+			actionType: "restart",
+			enabled:    false,
+			expectErr:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(append(nodes, tt.pods...)...).
+				Build()
+
+			cfg := config.SafetyConfig{
+				ZoneSpread: config.ZoneSpreadConfig{
+					Enabled:            tt.enabled,
+					MinReplicasPerZone: tt.minPerZone,
+				},
+			}
+			safetyCtrl := NewController(fakeClient, cfg, nil, nil)
+
+			action := &v1alpha1.HealingAction{
+				Spec: v1alpha1.HealingActionSpec{
+					TargetResource: v1alpha1.TargetResource{Kind: "Pod", Name: "web-1", Namespace: "apps"},
+					Action:         v1alpha1.HealingActionTemplate{Type: tt.actionType},
+				},
+			}
+
+			err := safetyCtrl.validateZoneSpread(context.Background(), action)
+			if tt.expectErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "zone-a")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package serving
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// HealthzPath and ReadyzPath are the paths of the liveness and
+	// readiness probes, as served by the manager
+	HealthzPath = "/healthz"
+	ReadyzPath  = "/readyz"
+)
+
+// ProbeServer serves the health probes over HTTPS, replacing the manager's
+// plain HTTP probe endpoint. Checks are added like on the manager.
+type ProbeServer struct {
+	addr      string
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	healthz map[string]healthz.Checker
+	readyz  map[string]healthz.Checker
+}
+
+// NewProbeServer creates a probe server presenting the certificate of
+// tlsConfig
+func NewProbeServer(addr string, tlsConfig *tls.Config) *ProbeServer {
+	return &ProbeServer{
+		addr:      addr,
+		tlsConfig: tlsConfig,
+		healthz:   make(map[string]healthz.Checker),
+		readyz:    make(map[string]healthz.Checker),
+	}
+}
+
+// AddHealthzCheck adds a liveness check
+func (s *ProbeServer) AddHealthzCheck(name string, check healthz.Checker) error {
+	return s.addCheck(s.healthz, name, check)
+}
+
+// AddReadyzCheck adds a readiness check
+func (s *ProbeServer) AddReadyzCheck(name string, check healthz.Checker) error {
+	return s.addCheck(s.readyz, name, check)
+}
+
+func (s *ProbeServer) addCheck(checks map[string]healthz.Checker, name string, check healthz.Checker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := checks[name]; exists {
+		return fmt.Errorf("health check %q is already registered", name)
+	}
+	checks[name] = check
+	return nil
+}
+
+// NeedLeaderElection is false so every replica answers its probes
+func (s *ProbeServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the probe endpoints. Individual checks are served below
+// the probe path, e.g. /healthz/ping.
+func (s *ProbeServer) Handler() http.Handler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mux := http.NewServeMux()
+	for path, checks := range map[string]map[string]healthz.Checker{HealthzPath: s.healthz, ReadyzPath: s.readyz} {
+		handler := http.StripPrefix(path, &healthz.Handler{Checks: checks})
+		mux.Handle(path, handler)
+		mux.Handle(path+"/", handler)
+	}
+	return mux
+}
+
+// Start serves the probes until the context is cancelled
+func (s *ProbeServer) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).WithName("probes").Info("Serving health probes over TLS", "address", s.addr)
+	if err := ListenAndServe(server, s.tlsConfig); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("health probes failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Package serving secures the operator's HTTP endpoints: metrics only
+// served to authenticated and authorized callers, and TLS with certificate
+// rotation for the health probes and debug endpoints.
+package serving
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Certificate is a serving certificate that is reloaded from disk when it
+// is rotated. Add it to the manager to watch the files.
+type Certificate struct {
+	watcher *certwatcher.CertWatcher
+}
+
+// LoadCertificate reads the certificate and key from the configured
+// directory
+func LoadCertificate(cfg config.ServingConfig) (*Certificate, error) {
+	if cfg.CertDir == "" {
+		return nil, fmt.Errorf("serving.certDir is required")
+	}
+	certPath := filepath.Join(cfg.CertDir, cfg.CertName)
+	keyPath := filepath.Join(cfg.CertDir, cfg.KeyName)
+	watcher, err := certwatcher.New(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load serving certificate %s: %w", certPath, err)
+	}
+	return &Certificate{watcher: watcher}, nil
+}
+
+// NeedLeaderElection is false so every replica reloads its certificate
+func (c *Certificate) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the certificate files until the context is cancelled
+func (c *Certificate) Start(ctx context.Context) error {
+	return c.watcher.Start(ctx)
+}
+
+// TLSConfig returns a server TLS configuration that always presents the
+// current certificate
+func (c *Certificate) TLSConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: c.watcher.GetCertificate,
+	}
+	disableHTTP2(cfg)
+	return cfg
+}
+
+// disableHTTP2 restricts a server to HTTP/1.1, which is not affected by the
+// HTTP/2 stream cancellation and rapid reset vulnerabilities
+func disableHTTP2(cfg *tls.Config) {
+	cfg.NextProtos = []string{"http/1.1"}
+}
+
+// MetricsOptions returns the options of the manager's metrics server.
+// Secure metrics authenticate callers with TokenReviews and authorize them
+// with SubjectAccessReviews, presenting cert when it is set.
+func MetricsOptions(addr string, cfg config.ServingConfig, cert *Certificate) server.Options {
+	opts := server.Options{BindAddress: addr}
+	if !cfg.SecureMetrics {
+		return opts
+	}
+
+	opts.SecureServing = true
+	opts.FilterProvider = filters.WithAuthenticationAndAuthorization
+	opts.TLSOpts = []func(*tls.Config){disableHTTP2}
+	if cert != nil {
+		opts.TLSOpts = append(opts.TLSOpts, func(tlsConfig *tls.Config) {
+			tlsConfig.MinVersion = tls.VersionTLS12
+			tlsConfig.GetCertificate = cert.watcher.GetCertificate
+		})
+	}
+	return opts
+}
+
+// ListenAndServe serves on the server's address, over TLS when tlsConfig
+// is set
+func ListenAndServe(server *http.Server, tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		return server.ListenAndServe()
+	}
+	server.TLSConfig = tlsConfig
+	return server.ListenAndServeTLS("", "")
+}
@@ -0,0 +1,127 @@
+package serving
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func writeTestCertificate(t *testing.T) (config.ServingConfig, []byte) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cfg := config.NewDefaultConfig().Serving
+	cfg.CertDir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.CertDir, cfg.CertName), certPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.CertDir, cfg.KeyName), keyPEM, 0o600))
+	return cfg, certPEM
+}
+
+func TestLoadCertificate(t *testing.T) {
+	cfg, certPEM := writeTestCertificate(t)
+	cert, err := LoadCertificate(cfg)
+	require.NoError(t, err)
+	assert.False(t, cert.NeedLeaderElection())
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Listener = tls.NewListener(server.Listener, cert.TLSConfig())
+	server.Start()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + server.Listener.Addr().String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "HTTP/1.1", resp.Proto, "HTTP/2 is disabled")
+
+	_, err = LoadCertificate(config.ServingConfig{})
+	assert.ErrorContains(t, err, "serving.certDir is required")
+	missing := cfg
+	missing.CertDir = t.TempDir()
+	_, err = LoadCertificate(missing)
+	assert.ErrorContains(t, err, "failed to load serving certificate")
+}
+
+func TestMetricsOptions(t *testing.T) {
+	cfg, _ := writeTestCertificate(t)
+	cert, err := LoadCertificate(cfg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		secure      bool
+		cert        *Certificate
+		wantTLSOpts int
+	}{
+		{name: "insecure", cert: cert},
+		{name: "secure with generated certificate", secure: true, wantTLSOpts: 1},
+		{name: "secure with serving certificate", secure: true, cert: cert, wantTLSOpts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := MetricsOptions(":8443", config.ServingConfig{SecureMetrics: tt.secure}, tt.cert)
+			assert.Equal(t, ":8443", opts.BindAddress)
+			assert.Equal(t, tt.secure, opts.SecureServing)
+			assert.Equal(t, tt.secure, opts.FilterProvider != nil)
+			require.Len(t, opts.TLSOpts, tt.wantTLSOpts)
+
+			tlsConfig := &tls.Config{}
+			for _, opt := range opts.TLSOpts {
+				opt(tlsConfig)
+			}
+			if tt.secure {
+				assert.Equal(t, []string{"http/1.1"}, tlsConfig.NextProtos)
+			}
+			assert.Equal(t, tt.secure && tt.cert != nil, tlsConfig.GetCertificate != nil)
+		})
+	}
+}
+
+func TestProbeServerHandler(t *testing.T) {
+	probes := NewProbeServer(":0", nil)
+	require.NoError(t, probes.AddHealthzCheck("ping", healthz.Ping))
+	require.NoError(t, probes.AddReadyzCheck("ping", healthz.Ping))
+	require.NoError(t, probes.AddReadyzCheck("informers", func(*http.Request) error {
+		return assert.AnError
+	}))
+	assert.ErrorContains(t, probes.AddHealthzCheck("ping", healthz.Ping), `health check "ping" is already registered`)
+	assert.False(t, probes.NeedLeaderElection())
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/healthz", wantStatus: http.StatusOK},
+		{path: "/healthz/ping", wantStatus: http.StatusOK},
+		{path: "/readyz", wantStatus: http.StatusInternalServerError},
+		{path: "/readyz/ping", wantStatus: http.StatusOK},
+		{path: "/readyz/informers", wantStatus: http.StatusInternalServerError},
+		{path: "/metrics", wantStatus: http.StatusNotFound},
+	}
+
+	handler := probes.Handler()
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
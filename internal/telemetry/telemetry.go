@@ -0,0 +1,280 @@
+// Package telemetry sends anonymous, aggregated usage reports to the
+// maintainers when an operator opts in. Reports never hold names,
+// namespaces, labels, addresses or free text: only counters keyed by the
+// fixed values of the API's type enums. docs/telemetry.md documents the
+// schema.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+const (
+	// SchemaVersion of the reports, raised on any change to Report
+	SchemaVersion = 1
+
+	// EnvTelemetry set to "off" disables reports whatever the
+	// configuration
+	EnvTelemetry = "KUBESKIPPY_TELEMETRY"
+
+	// EnvDoNotTrack set to "1" disables reports like EnvTelemetry, per
+	// the consoledonottrack.com convention
+	EnvDoNotTrack = "DO_NOT_TRACK"
+
+	// otherValue counts the values outside of the known ones
+	otherValue = "other"
+)
+
+// Report is the payload of a telemetry report
+type Report struct {
+	// SchemaVersion of the report
+	SchemaVersion int `json:"schemaVersion"`
+
+	// Date the report was generated, without the time of day
+	Date string `json:"date"`
+
+	// IntervalHours covered by Actions
+	IntervalHours int `json:"intervalHours"`
+
+	// Actions completed in the interval, by action type
+	Actions map[string]ActionCounts `json:"actions"`
+
+	// TriggerTypes counts the triggers of all policies by type
+	TriggerTypes map[string]int `json:"triggerTypes"`
+
+	// PolicyModes counts the policies by mode
+	PolicyModes map[string]int `json:"policyModes"`
+
+	// AIProviderClass is none, local, hosted or cloud
+	AIProviderClass string `json:"aiProviderClass"`
+}
+
+// ActionCounts are the outcomes of the actions of one type
+type ActionCounts struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Cancelled int `json:"cancelled"`
+	DryRun    int `json:"dryRun"`
+
+	// SuccessRate of the executed actions that succeeded or failed
+	SuccessRate float64 `json:"successRate"`
+}
+
+// knownActionTypes, knownTriggerTypes and knownPolicyModes mirror the type
+// enums of the API. Other values, such as those of plugins, are counted as
+// "other" so no user-chosen string is ever reported.
+var (
+	knownActionTypes = setOf("restart", "scale", "patch", "delete", "finalizer", "hibernate",
+		"exec", "resize", "taint", "drain", "custom", "chain")
+	knownTriggerTypes = setOf("metric", "event", "condition", "log", "restartStorm", "schedule",
+		"stuckTerminating", "plugin")
+	knownPolicyModes = setOf("monitor", "dryrun", "automatic", "manual")
+)
+
+// Disabled reports whether the environment switches telemetry off
+func Disabled() bool {
+	return strings.EqualFold(os.Getenv(EnvTelemetry), "off") || os.Getenv(EnvDoNotTrack) == "1"
+}
+
+// ProviderClass classifies an AI provider without naming it: local for
+// models served in the cluster, hosted for API services and cloud for the
+// cloud platforms' model services
+func ProviderClass(provider string) string {
+	switch provider {
+	case "":
+		return "none"
+	case "ollama", "grpc":
+		return "local"
+	case "openai":
+		return "hosted"
+	case "bedrock", "vertex", "azure-openai":
+		return "cloud"
+	default:
+		return otherValue
+	}
+}
+
+// Reporter collects and sends the reports
+type Reporter struct {
+	reader     client.Reader
+	cfg        config.TelemetryConfig
+	aiProvider string
+	httpClient *http.Client
+
+	now func() time.Time
+}
+
+// NewReporter creates a reporter reading the policies and actions through
+// reader
+func NewReporter(reader client.Reader, cfg config.TelemetryConfig, aiProvider string) *Reporter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &Reporter{
+		reader:     reader,
+		cfg:        cfg,
+		aiProvider: aiProvider,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		now:        time.Now,
+	}
+}
+
+// NeedLeaderElection is true so only the leader reports
+func (r *Reporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sends a report every interval until ctx is done
+func (r *Reporter) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("telemetry")
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Send(ctx); err != nil {
+				log.V(1).Info("Failed to send telemetry report", "error", err.Error())
+			}
+		}
+	}
+}
+
+// Collect builds the report of the interval ending now
+func (r *Reporter) Collect(ctx context.Context) (*Report, error) {
+	now := r.now().UTC()
+	report := &Report{
+		SchemaVersion:   SchemaVersion,
+		Date:            now.Format(time.DateOnly),
+		IntervalHours:   int(r.cfg.Interval.Hours()),
+		Actions:         make(map[string]ActionCounts),
+		TriggerTypes:    make(map[string]int),
+		PolicyModes:     make(map[string]int),
+		AIProviderClass: ProviderClass(r.aiProvider),
+	}
+
+	policies := &v1alpha1.HealingPolicyList{}
+	if err := r.reader.List(ctx, policies); err != nil {
+		return nil, fmt.Errorf("failed to list healing policies: %w", err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		report.PolicyModes[known(knownPolicyModes, policy.Spec.Mode)]++
+		for _, trigger := range policy.Spec.Triggers {
+			report.TriggerTypes[known(knownTriggerTypes, trigger.Type)]++
+		}
+	}
+
+	actions := &v1alpha1.HealingActionList{}
+	if err := r.reader.List(ctx, actions); err != nil {
+		return nil, fmt.Errorf("failed to list healing actions: %w", err)
+	}
+	since := now.Add(-r.cfg.Interval)
+	for i := range actions.Items {
+		action := &actions.Items[i]
+		completed := action.Status.CompletionTime
+		if !action.IsComplete() || completed == nil || completed.Time.Before(since) {
+			continue
+		}
+
+		actionType := known(knownActionTypes, action.Spec.Action.Type)
+		counts := report.Actions[actionType]
+		switch {
+		case action.Spec.DryRun:
+			counts.DryRun++
+		case action.Status.Phase == v1alpha1.HealingActionPhaseSucceeded:
+			counts.Succeeded++
+		case action.Status.Phase == v1alpha1.HealingActionPhaseFailed:
+			counts.Failed++
+		default:
+			counts.Cancelled++
+		}
+		report.Actions[actionType] = counts
+	}
+	for actionType, counts := range report.Actions {
+		if executed := counts.Succeeded + counts.Failed; executed > 0 {
+			counts.SuccessRate = float64(counts.Succeeded) / float64(executed)
+			report.Actions[actionType] = counts
+		}
+	}
+	return report, nil
+}
+
+// Preview writes the report that would be sent now, so operators can see
+// exactly what leaves the cluster before they opt in
+func (r *Reporter) Preview(ctx context.Context, w io.Writer) error {
+	report, err := r.Collect(ctx)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// Send posts a report to the endpoint, unless telemetry is not enabled or
+// is switched off by the environment
+func (r *Reporter) Send(ctx context.Context) error {
+	if !r.cfg.Enabled || Disabled() {
+		return nil
+	}
+
+	report, err := r.Collect(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// known returns the value if it is one of the known values, and "other"
+// otherwise
+func known(values map[string]bool, value string) string {
+	if values[value] {
+		return value
+	}
+	return otherValue
+}
+
+func setOf(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
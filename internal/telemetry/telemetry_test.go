@@ -0,0 +1,179 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func newTestReporter(t *testing.T, cfg config.TelemetryConfig, now time.Time) *Reporter {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	policy := func(name, mode string, triggers ...string) *v1alpha1.HealingPolicy {
+		p := &v1alpha1.HealingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       v1alpha1.HealingPolicySpec{Mode: mode},
+		}
+		for _, trigger := range triggers {
+			p.Spec.Triggers = append(p.Spec.Triggers, v1alpha1.HealingTrigger{Name: trigger, Type: trigger})
+		}
+		return p
+	}
+	action := func(name, actionType, phase string, age time.Duration, dryRun bool) *v1alpha1.HealingAction {
+		completed := metav1.NewTime(now.Add(-age))
+		return &v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec: v1alpha1.HealingActionSpec{
+				Action: v1alpha1.HealingActionTemplate{Name: name, Type: actionType},
+				DryRun: dryRun,
+			},
+			Status: v1alpha1.HealingActionStatus{Phase: phase, CompletionTime: &completed},
+		}
+	}
+
+	objects := []client.Object{
+		policy("crashes", "automatic", "metric", "restartStorm"),
+		policy("memory", "dryrun", "metric", "my-plugin-trigger"),
+		action("restart-1", "restart", v1alpha1.HealingActionPhaseSucceeded, time.Hour, false),
+		action("restart-2", "restart", v1alpha1.HealingActionPhaseSucceeded, time.Hour, false),
+		action("restart-3", "restart", v1alpha1.HealingActionPhaseSucceeded, time.Hour, false),
+		action("restart-4", "restart", v1alpha1.HealingActionPhaseFailed, time.Hour, false),
+		action("restart-old", "restart", v1alpha1.HealingActionPhaseFailed, 48*time.Hour, false),
+		action("scale-1", "scale", v1alpha1.HealingActionPhaseSucceeded, time.Hour, true),
+		action("scale-2", "scale", v1alpha1.HealingActionPhaseCancelled, time.Hour, false),
+		action("custom-1", "my-executor", v1alpha1.HealingActionPhaseFailed, time.Hour, false),
+		&v1alpha1.HealingAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "apps"},
+			Spec:       v1alpha1.HealingActionSpec{Action: v1alpha1.HealingActionTemplate{Type: "delete"}},
+			Status:     v1alpha1.HealingActionStatus{Phase: v1alpha1.HealingActionPhaseInProgress},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	reporter := NewReporter(c, cfg, "bedrock")
+	reporter.now = func() time.Time { return now }
+	return reporter
+}
+
+func TestReporter_Collect(t *testing.T) {
+	now := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	reporter := newTestReporter(t, config.TelemetryConfig{Interval: 24 * time.Hour}, now)
+
+	report, err := reporter.Collect(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, &Report{
+		SchemaVersion: SchemaVersion,
+		Date:          "2026-03-14",
+		IntervalHours: 24,
+		Actions: map[string]ActionCounts{
+			"restart": {Succeeded: 3, Failed: 1, SuccessRate: 0.75},
+			"scale":   {Cancelled: 1, DryRun: 1},
+			"other":   {Failed: 1},
+		},
+		TriggerTypes:    map[string]int{"metric": 2, "restartStorm": 1, "other": 1},
+		PolicyModes:     map[string]int{"automatic": 1, "dryrun": 1},
+		AIProviderClass: "cloud",
+	}, report)
+}
+
+func TestReporter_Preview(t *testing.T) {
+	reporter := newTestReporter(t, config.TelemetryConfig{}, time.Now())
+
+	var out bytes.Buffer
+	require.NoError(t, reporter.Preview(context.Background(), &out))
+
+	// Only counters and enum values leave the cluster
+	assert.NotContains(t, out.String(), "apps")
+	assert.NotContains(t, out.String(), "crashes")
+	assert.NotContains(t, out.String(), "my-plugin-trigger")
+	assert.NotContains(t, out.String(), "bedrock")
+
+	report := &Report{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), report))
+	assert.Equal(t, SchemaVersion, report.SchemaVersion)
+}
+
+func TestReporter_Send(t *testing.T) {
+	var received []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	t.Setenv(EnvTelemetry, "")
+	t.Setenv(EnvDoNotTrack, "")
+
+	cfg := config.TelemetryConfig{Enabled: true, Endpoint: server.URL}
+	reporter := newTestReporter(t, cfg, time.Now())
+	reporter.httpClient = server.Client()
+
+	require.NoError(t, reporter.Send(context.Background()))
+	report := &Report{}
+	require.NoError(t, json.Unmarshal(received, report))
+	assert.Equal(t, 3, report.Actions["restart"].Succeeded)
+
+	// The environment switches reports off whatever the configuration
+	received = nil
+	t.Setenv(EnvDoNotTrack, "1")
+	require.NoError(t, reporter.Send(context.Background()))
+	assert.Nil(t, received)
+
+	// Not enabled
+	t.Setenv(EnvDoNotTrack, "")
+	reporter.cfg.Enabled = false
+	require.NoError(t, reporter.Send(context.Background()))
+	assert.Nil(t, received)
+}
+
+func TestDisabled(t *testing.T) {
+	tests := []struct {
+		telemetry  string
+		doNotTrack string
+		want       bool
+	}{
+		{"", "", false},
+		{"on", "0", false},
+		{"off", "", true},
+		{"OFF", "", true},
+		{"", "1", true},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvTelemetry, tt.telemetry)
+		t.Setenv(EnvDoNotTrack, tt.doNotTrack)
+		assert.Equal(t, tt.want, Disabled(), "%s=%q %s=%q", EnvTelemetry, tt.telemetry, EnvDoNotTrack, tt.doNotTrack)
+	}
+}
+
+func TestProviderClass(t *testing.T) {
+	for provider, want := range map[string]string{
+		"":             "none",
+		"ollama":       "local",
+		"grpc":         "local",
+		"openai":       "hosted",
+		"bedrock":      "cloud",
+		"vertex":       "cloud",
+		"azure-openai": "cloud",
+		"in-house":     "other",
+	} {
+		assert.Equal(t, want, ProviderClass(provider), provider)
+	}
+}
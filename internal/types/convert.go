@@ -0,0 +1,270 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// ToPublicClusterMetrics converts cluster metrics to the public type
+func ToPublicClusterMetrics(in *ClusterMetrics) *pkgtypes.ClusterMetrics {
+	if in == nil {
+		return nil
+	}
+
+	out := &pkgtypes.ClusterMetrics{
+		Timestamp: in.Timestamp,
+		Custom:    copyFloatMap(in.Custom),
+	}
+	for _, node := range in.Nodes {
+		out.Nodes = append(out.Nodes, pkgtypes.NodeMetrics{
+			Name:        node.Name,
+			CPUUsage:    node.CPUUsage,
+			MemoryUsage: node.MemoryUsage,
+			DiskUsage:   node.DiskUsage,
+			PodCount:    node.PodCount,
+			Conditions:  append([]string(nil), node.Conditions...),
+			Labels:      copyStringMap(node.Labels),
+		})
+	}
+	for _, pod := range in.Pods {
+		out.Pods = append(out.Pods, pkgtypes.PodMetrics{
+			Name:         pod.Name,
+			Namespace:    pod.Namespace,
+			CPUUsage:     pod.CPUUsage,
+			MemoryUsage:  pod.MemoryUsage,
+			RestartCount: pod.RestartCount,
+			Status:       pod.Status,
+			Conditions:   append([]string(nil), pod.Conditions...),
+			Labels:       copyStringMap(pod.Labels),
+
+			InitRestartCount:    pod.InitRestartCount,
+			InitFailures:        append([]string(nil), pod.InitFailures...),
+			EphemeralContainers: append([]string(nil), pod.EphemeralContainers...),
+		})
+	}
+	for _, event := range in.Events {
+		out.Events = append(out.Events, pkgtypes.EventMetrics(event))
+	}
+	for _, match := range in.LogMatches {
+		out.LogMatches = append(out.LogMatches, pkgtypes.LogMatch(match))
+	}
+	out.Patterns = append([]pkgtypes.Pattern(nil), in.Patterns...)
+	out.Capacity = copyCapacity(in.Capacity)
+	return out
+}
+
+// FromPublicClusterMetrics converts public cluster metrics to the internal type
+func FromPublicClusterMetrics(in *pkgtypes.ClusterMetrics) *ClusterMetrics {
+	if in == nil {
+		return nil
+	}
+
+	out := &ClusterMetrics{
+		Timestamp: in.Timestamp,
+		Resources: make(map[string]interface{}),
+		Custom:    copyFloatMap(in.Custom),
+	}
+	for _, node := range in.Nodes {
+		out.Nodes = append(out.Nodes, NodeMetrics{
+			Name:        node.Name,
+			CPUUsage:    node.CPUUsage,
+			MemoryUsage: node.MemoryUsage,
+			DiskUsage:   node.DiskUsage,
+			PodCount:    node.PodCount,
+			Conditions:  append([]string(nil), node.Conditions...),
+			Labels:      copyStringMap(node.Labels),
+		})
+	}
+	for _, pod := range in.Pods {
+		out.Pods = append(out.Pods, PodMetrics{
+			Name:         pod.Name,
+			Namespace:    pod.Namespace,
+			CPUUsage:     pod.CPUUsage,
+			MemoryUsage:  pod.MemoryUsage,
+			RestartCount: pod.RestartCount,
+			Status:       pod.Status,
+			Conditions:   append([]string(nil), pod.Conditions...),
+			Labels:       copyStringMap(pod.Labels),
+
+			InitRestartCount:    pod.InitRestartCount,
+			InitFailures:        append([]string(nil), pod.InitFailures...),
+			EphemeralContainers: append([]string(nil), pod.EphemeralContainers...),
+		})
+	}
+	for _, event := range in.Events {
+		out.Events = append(out.Events, EventMetrics(event))
+	}
+	for _, match := range in.LogMatches {
+		out.LogMatches = append(out.LogMatches, LogMatch(match))
+	}
+	out.Patterns = append([]pkgtypes.Pattern(nil), in.Patterns...)
+	out.Capacity = copyCapacity(in.Capacity)
+	return out
+}
+
+// ToPublicValidationResult converts a validation result to the public type
+func ToPublicValidationResult(in *ValidationResult) *pkgtypes.ValidationResult {
+	if in == nil {
+		return nil
+	}
+	return &pkgtypes.ValidationResult{
+		Valid:       in.Valid,
+		Reason:      in.Reason,
+		Warnings:    append([]string(nil), in.Warnings...),
+		Suggestions: append([]string(nil), in.Suggestions...),
+	}
+}
+
+// FromPublicValidationResult converts a public validation result to the internal type
+func FromPublicValidationResult(in *pkgtypes.ValidationResult) *ValidationResult {
+	if in == nil {
+		return nil
+	}
+	return &ValidationResult{
+		Valid:       in.Valid,
+		Reason:      in.Reason,
+		Warnings:    append([]string(nil), in.Warnings...),
+		Suggestions: append([]string(nil), in.Suggestions...),
+	}
+}
+
+// ToPublicActionResult converts an action result to the public type
+func ToPublicActionResult(in *ActionResult) *pkgtypes.ActionResult {
+	if in == nil {
+		return nil
+	}
+	out := &pkgtypes.ActionResult{
+		Success:   in.Success,
+		Message:   in.Message,
+		Changes:   append([]v1alpha1.ResourceChange(nil), in.Changes...),
+		Metrics:   copyStringMap(in.Metrics),
+		StartTime: in.StartTime,
+		EndTime:   in.EndTime,
+	}
+	if in.Error != nil {
+		out.Error = in.Error.Error()
+	}
+	return out
+}
+
+// FromPublicActionResult converts a public action result to the internal type
+func FromPublicActionResult(in *pkgtypes.ActionResult) *ActionResult {
+	if in == nil {
+		return nil
+	}
+	out := &ActionResult{
+		Success:   in.Success,
+		Message:   in.Message,
+		Changes:   append([]v1alpha1.ResourceChange(nil), in.Changes...),
+		Metrics:   copyStringMap(in.Metrics),
+		StartTime: in.StartTime,
+		EndTime:   in.EndTime,
+	}
+	if in.Error != "" {
+		out.Error = errors.New(in.Error)
+	}
+	return out
+}
+
+// FromPublicAnalysis converts a public analysis to the internal AI analysis type
+func FromPublicAnalysis(in *pkgtypes.Analysis) *AIAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := &AIAnalysis{
+		Timestamp:    time.Now(),
+		Summary:      in.Summary,
+		Confidence:   in.Confidence,
+		ModelVersion: in.Model,
+	}
+	for _, issue := range in.Issues {
+		out.Issues = append(out.Issues, AIIssue(issue))
+	}
+	for _, rec := range in.Recommendations {
+		out.Recommendations = append(out.Recommendations, AIRecommendation{
+			ID:         rec.ID,
+			Priority:   rec.Priority,
+			Action:     rec.Action,
+			Target:     rec.Target,
+			Reason:     rec.Reason,
+			Risk:       rec.Risk,
+			Confidence: rec.Confidence,
+			Patches:    append([]v1alpha1.PatchOperation(nil), rec.Patches...),
+		})
+	}
+	return out
+}
+
+// publicExecutor adapts a public ActionExecutor to the internal interface
+type publicExecutor struct {
+	executor pkgtypes.ActionExecutor
+}
+
+// NewPublicExecutor wraps an executor built against pkg/types so it can be
+// registered with the remediation engine
+func NewPublicExecutor(executor pkgtypes.ActionExecutor) ActionExecutor {
+	return &publicExecutor{executor: executor}
+}
+
+// Execute performs the action
+func (p *publicExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*ActionResult, error) {
+	result, err := p.executor.Execute(ctx, target, action)
+	return FromPublicActionResult(result), err
+}
+
+// Validate checks if the action can be executed
+func (p *publicExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	return p.executor.Validate(ctx, target, action)
+}
+
+// DryRun simulates the action
+func (p *publicExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*ActionResult, error) {
+	result, err := p.executor.DryRun(ctx, target, action)
+	return FromPublicActionResult(result), err
+}
+
+// copyStringMap returns a copy of m, or nil if m is nil
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// copyFloatMap returns a copy of m, or nil if m is nil
+func copyFloatMap(m map[string]float64) map[string]float64 {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// copyCapacity returns a copy of c, or nil if c is nil
+func copyCapacity(c *pkgtypes.CapacityMetrics) *pkgtypes.CapacityMetrics {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	out.UnschedulablePods = append([]pkgtypes.UnschedulablePod(nil), c.UnschedulablePods...)
+	if c.Autoscaler != nil {
+		autoscaler := *c.Autoscaler
+		autoscaler.NodeGroupsAtMaxSize = append([]string(nil), c.Autoscaler.NodeGroupsAtMaxSize...)
+		autoscaler.NodeGroupsInBackoff = append([]string(nil), c.Autoscaler.NodeGroupsInBackoff...)
+		out.Autoscaler = &autoscaler
+	}
+	return &out
+}
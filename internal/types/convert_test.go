@@ -0,0 +1,132 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+func TestClusterMetricsConversion(t *testing.T) {
+	now := time.Now()
+	metrics := &ClusterMetrics{
+		Timestamp: now,
+		Nodes:     []NodeMetrics{{Name: "node-1", CPUUsage: 50, Labels: map[string]string{"zone": "a"}}},
+		Pods: []PodMetrics{{
+			Name: "web-1", Namespace: "apps", RestartCount: 3, Status: "Running",
+			InitRestartCount: 2, InitFailures: []string{"migrate: CrashLoopBackOff"}, EphemeralContainers: []string{"debugger"},
+		}},
+		Events: []EventMetrics{{Type: "Warning", Reason: "BackOff", Count: 2}},
+		Custom: map[string]float64{"cpu_usage": 92.5},
+		LogMatches: []LogMatch{
+			{Pod: "web-1", Namespace: "apps", Pattern: "OOM", Line: "OOM killed"},
+		},
+		Patterns: []pkgtypes.Pattern{{Name: "slow-leak", Target: "apps/web-1", Detector: "leaks"}},
+	}
+
+	public := ToPublicClusterMetrics(metrics)
+	require.NotNil(t, public)
+	assert.Equal(t, now, public.Timestamp)
+	assert.Equal(t, "node-1", public.Nodes[0].Name)
+	assert.Equal(t, int32(3), public.Pods[0].RestartCount)
+	assert.Equal(t, []string{"migrate: CrashLoopBackOff"}, public.Pods[0].InitFailures)
+	assert.Equal(t, "BackOff", public.Events[0].Reason)
+	assert.Equal(t, 92.5, public.Custom["cpu_usage"])
+	assert.Equal(t, "OOM killed", public.LogMatches[0].Line)
+	assert.Equal(t, metrics.Patterns, public.Patterns)
+
+	// Public metrics must not share maps with the internal snapshot
+	public.Custom["cpu_usage"] = 0
+	assert.Equal(t, 92.5, metrics.Custom["cpu_usage"])
+
+	back := FromPublicClusterMetrics(public)
+	assert.Equal(t, metrics.Pods, back.Pods)
+	assert.Equal(t, metrics.Events, back.Events)
+	assert.Equal(t, metrics.Patterns, back.Patterns)
+	assert.NotNil(t, back.Resources)
+
+	assert.Nil(t, ToPublicClusterMetrics(nil))
+	assert.Nil(t, FromPublicClusterMetrics(nil))
+}
+
+func TestActionResultConversion(t *testing.T) {
+	result := &ActionResult{
+		Success: false,
+		Message: "restart failed",
+		Error:   errors.New("pod not found"),
+		Changes: []v1alpha1.ResourceChange{{ResourceRef: "Pod/apps/web-1", ChangeType: "delete"}},
+		Metrics: map[string]string{"attempts": "1"},
+	}
+
+	public := ToPublicActionResult(result)
+	assert.Equal(t, "pod not found", public.Error)
+	assert.Equal(t, result.Changes, public.Changes)
+
+	back := FromPublicActionResult(public)
+	require.Error(t, back.Error)
+	assert.Equal(t, "pod not found", back.Error.Error())
+	assert.Equal(t, result.Metrics, back.Metrics)
+
+	assert.Nil(t, FromPublicActionResult(&pkgtypes.ActionResult{Success: true}).Error)
+}
+
+func TestValidationResultConversion(t *testing.T) {
+	result := &ValidationResult{Valid: false, Reason: "protected", Warnings: []string{"w"}}
+	assert.Equal(t, result, FromPublicValidationResult(ToPublicValidationResult(result)))
+}
+
+func TestFromPublicAnalysis(t *testing.T) {
+	analysis := FromPublicAnalysis(&pkgtypes.Analysis{
+		Summary:    "memory leak",
+		Confidence: 0.8,
+		Model:      "custom",
+		Issues:     []pkgtypes.AnalysisIssue{{ID: "1", Severity: "high"}},
+		Recommendations: []pkgtypes.Recommendation{
+			{ID: "r1", Action: "restart", Target: "Pod/apps/web-1", Confidence: 0.9},
+		},
+	})
+
+	assert.Equal(t, "custom", analysis.ModelVersion)
+	assert.Equal(t, "high", analysis.Issues[0].Severity)
+	assert.Equal(t, "restart", analysis.Recommendations[0].Action)
+	assert.False(t, analysis.Timestamp.IsZero())
+}
+
+type stubExecutor struct {
+	err error
+}
+
+func (s *stubExecutor) Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*pkgtypes.ActionResult, error) {
+	return &pkgtypes.ActionResult{Success: s.err == nil, Message: "executed"}, s.err
+}
+
+func (s *stubExecutor) Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error {
+	return s.err
+}
+
+func (s *stubExecutor) DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*pkgtypes.ActionResult, error) {
+	return &pkgtypes.ActionResult{Success: true, Message: "[DRY RUN]"}, nil
+}
+
+func TestNewPublicExecutor(t *testing.T) {
+	executor := NewPublicExecutor(&stubExecutor{})
+
+	result, err := executor.Execute(context.Background(), nil, &v1alpha1.HealingActionTemplate{})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "executed", result.Message)
+
+	result, err = executor.DryRun(context.Background(), nil, &v1alpha1.HealingActionTemplate{})
+	require.NoError(t, err)
+	assert.Equal(t, "[DRY RUN]", result.Message)
+
+	failing := NewPublicExecutor(&stubExecutor{err: errors.New("boom")})
+	assert.Error(t, failing.Validate(context.Background(), nil, &v1alpha1.HealingActionTemplate{}))
+}
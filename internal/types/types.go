@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	pkgtypes "github.com/kubeskippy/kubeskippy/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -18,6 +19,20 @@ type ClusterMetrics struct {
 	Resources map[string]interface{}
 	Events    []EventMetrics
 	Custom    map[string]float64
+
+	// LogMatches are log lines matched by log-pattern triggers
+	LogMatches []LogMatch
+
+	// SampledOutPods is the number of low-signal pods left out of Pods by
+	// sampling
+	SampledOutPods int
+
+	// Patterns are the patterns found by the enabled detectors
+	Patterns []pkgtypes.Pattern
+
+	// Capacity tells a cluster out of capacity apart from failing
+	// workloads; nil when capacity signals are not collected
+	Capacity *pkgtypes.CapacityMetrics
 }
 
 // NodeMetrics represents metrics for a node
@@ -44,6 +59,19 @@ type PodMetrics struct {
 	Labels          map[string]string
 	OwnerReferences []string
 	LastUpdateTime  time.Time
+
+	// InitRestartCount is the total restart count of the init containers
+	InitRestartCount int32
+
+	// InitFailures lists the failing init containers as "container: reason"
+	InitFailures []string
+
+	// EphemeralContainers lists the running ephemeral containers
+	EphemeralContainers []string
+
+	// UID and CreationTime of the pod tell replacement pods apart
+	UID          string
+	CreationTime time.Time
 }
 
 // ResourceMetrics represents metrics for a specific resource
@@ -67,12 +95,36 @@ type EventMetrics struct {
 	Object    string
 }
 
+// LogMatch represents a pod log line matched by a log-pattern trigger
+type LogMatch struct {
+	Pod       string
+	Namespace string
+	Container string
+	Pattern   string
+	Line      string
+}
+
+// TriggerResult is the outcome of a trigger evaluation
+type TriggerResult struct {
+	Triggered bool
+	Reason    string
+	// Value is the value a metric trigger was evaluated against, valid when
+	// Observed is set
+	Value    float64
+	Observed bool
+	// Threshold the value was compared against when Baselined is set, i.e.
+	// the trigger's threshold multiplied by the query's baseline
+	Threshold float64
+	Baselined bool
+}
+
 // Issue represents a detected problem
 type Issue struct {
 	ID          string
 	Severity    string
 	Type        string
 	Resource    string
+	Namespace   string
 	Description string
 	Metrics     map[string]interface{}
 	DetectedAt  time.Time
@@ -84,6 +136,10 @@ type ValidationResult struct {
 	Reason      string
 	Warnings    []string
 	Suggestions []string
+
+	// CircuitOpen is set when the action was rejected by an open circuit
+	// breaker
+	CircuitOpen bool
 }
 
 // ActionResult contains the result of executing an action
@@ -95,6 +151,22 @@ type ActionResult struct {
 	Metrics   map[string]string
 	StartTime time.Time
 	EndTime   time.Time
+
+	// BlastRadius is set for destructive actions that were simulated
+	BlastRadius *v1alpha1.BlastRadiusReport
+
+	// Diagnostics captured by pre-action hooks
+	Diagnostics []v1alpha1.DiagnosticCapture
+
+	// TargetGeneration is the target's generation after the action, or 0 if
+	// the target no longer exists
+	TargetGeneration int64
+
+	// Partial is set when the attempt was cancelled after Changes were made
+	Partial bool
+
+	// Steps are the results of the steps of chain actions
+	Steps []v1alpha1.StepResult
 }
 
 // AIAnalysis represents the AI's analysis of cluster state
@@ -105,7 +177,12 @@ type AIAnalysis struct {
 	Recommendations []AIRecommendation
 	Confidence      float64
 	ModelVersion    string
+	PromptHash      string
 	ReasoningSteps  []ReasoningStep
+
+	// RestrictedNamespaces were withheld from the provider by the AI data
+	// policy; their issues must be handled without AI
+	RestrictedNamespaces []string
 }
 
 // AIIssue represents an issue identified by AI
@@ -127,6 +204,9 @@ type AIRecommendation struct {
 	Risk       string
 	Confidence float64
 	Reasoning  DecisionReasoning
+	// Patches proposed for patch actions, validated against the target
+	// kind's mutable fields before use
+	Patches []v1alpha1.PatchOperation
 }
 
 // ReasoningStep represents a step in the AI's decision process
@@ -138,6 +218,18 @@ type ReasoningStep struct {
 	Timestamp   time.Time
 }
 
+// AIProgress reports an AI analysis while the provider streams its response
+type AIProgress struct {
+	// StepsCompleted are the reasoning steps received so far
+	StepsCompleted int
+
+	// CurrentStep describes the latest reasoning step
+	CurrentStep string
+
+	// Confidence stated by the AI so far, 0 until it states one
+	Confidence float64
+}
+
 // DecisionReasoning contains detailed reasoning for a specific recommendation
 type DecisionReasoning struct {
 	Observations      []string
@@ -182,8 +274,52 @@ type ActionExecutor interface {
 const (
 	AnnotationProtected       = "kubeskippy.io/protected"
 	AnnotationHealingDisabled = "kubeskippy.io/healing-disabled"
+
+	// Provenance recorded on healing actions for audit records
+	AnnotationPolicyGeneration = "kubeskippy.io/policy-generation"
+	AnnotationAIModel          = "kubeskippy.io/ai-model"
+	AnnotationAIPromptHash     = "kubeskippy.io/ai-prompt-hash"
+
+	// Context recorded on healing actions for incident summaries
+	AnnotationTriggerReason = "kubeskippy.io/trigger-reason"
+	AnnotationAIReasoning   = "kubeskippy.io/ai-reasoning"
+
+	// AnnotationPriorityDecision carries the JSON PriorityDecision of an
+	// action created in AI blend mode into the action's status
+	AnnotationPriorityDecision = "kubeskippy.io/priority-decision"
+
+	// AnnotationIssue names the issue tracker issue related to a policy,
+	// e.g. "owner/repo#123" for GitHub or "OPS-123" for Jira
+	AnnotationIssue = "kubeskippy.io/issue"
+
+	// AnnotationIgnoreFailureCooloff set to "true" on a healing action lets
+	// it run on a target in failure cool-off
+	AnnotationIgnoreFailureCooloff = "kubeskippy.io/ignore-failure-cooloff"
+
+	// Estimates recorded on healing actions at admission
+	AnnotationRiskLevel   = "kubeskippy.io/risk-level"
+	AnnotationBlastRadius = "kubeskippy.io/blast-radius"
 )
 
+// LabelCooldownGroup names the cooldown group of a HealingPolicy. Its
+// actions carry the label too, and any action of the group holds back the
+// actions of every policy in the group for the cooldown group window.
+const LabelCooldownGroup = "kubeskippy.io/cooldown-group"
+
+// Namespace data-governance labels
+const (
+	// LabelAIDataPolicy controls what data from a namespace may be sent to
+	// AI providers
+	LabelAIDataPolicy = "kubeskippy.io/ai-data-policy"
+
+	// AIDataPolicyRestricted keeps a namespace's data away from external
+	// AI providers
+	AIDataPolicyRestricted = "restricted"
+)
+
+// FieldManager is the field manager recorded on changes made by KubeSkippy
+const FieldManager = "kubeskippy"
+
 // CircuitBreakerState represents the state of a circuit breaker
 type CircuitBreakerState string
 
@@ -200,6 +336,7 @@ type CircuitBreaker struct {
 	failureCount     int
 	successCount     int
 	lastFailureTime  time.Time
+	lastCallTime     time.Time
 	timeout          time.Duration
 	failureThreshold int
 	successThreshold int
@@ -220,6 +357,7 @@ func (cb *CircuitBreaker) Call(ctx context.Context, fn func() error) error {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	cb.lastCallTime = time.Now()
 	if err := cb.canExecute(); err != nil {
 		return err
 	}
@@ -285,6 +423,13 @@ func (cb *CircuitBreaker) recordSuccess() {
 	}
 }
 
+// LastCall returns when the circuit breaker was last called
+func (cb *CircuitBreaker) LastCall() time.Time {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.lastCallTime
+}
+
 // GetState returns the current state of the circuit breaker
 func (cb *CircuitBreaker) GetState() CircuitBreakerState {
 	cb.mu.RLock()
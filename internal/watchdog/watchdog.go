@@ -0,0 +1,438 @@
+// Package watchdog monitors the operator's own health. It tracks reconcile
+// stalls, persistent API errors and AI queue saturation, takes bounded
+// corrective actions, and enters safe mode, in which new healing actions
+// are forced to dry-run, when checks keep failing. Its findings are
+// published in an OperatorHealth resource.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Health checks
+const (
+	CheckReconcileStall = "reconcile-stall"
+	CheckAPIErrors      = "api-errors"
+	CheckAIQueue        = "ai-queue"
+)
+
+const (
+	// ActionEnterSafeMode is recorded when safe mode is entered
+	ActionEnterSafeMode = "enter-safe-mode"
+
+	// ActionLeaveSafeMode is recorded when safe mode is left
+	ActionLeaveSafeMode = "leave-safe-mode"
+
+	// maxRemediationHistory bounds the remediations kept in the status
+	maxRemediationHistory = 20
+
+	// ConditionTypeHealthy reports whether all checks pass
+	ConditionTypeHealthy = "Healthy"
+)
+
+// Remediation is a corrective action for a failing check
+type Remediation struct {
+	// Name of the action, recorded in the OperatorHealth status
+	Name string
+
+	// Run takes the action
+	Run func(ctx context.Context) error
+}
+
+// reconcile is a reconcile in progress
+type reconcile struct {
+	controller string
+	start      time.Time
+}
+
+// Watchdog checks the operator's health every interval
+//
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=operatorhealths,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=kubeskippy.io,resources=operatorhealths/status,verbs=get;update;patch
+type Watchdog struct {
+	client client.Client
+	config config.WatchdogConfig
+
+	mu           sync.Mutex
+	nextID       uint64
+	inflight     map[uint64]reconcile
+	errors       map[string]int
+	aiQueue      func() int
+	remediations map[string][]Remediation
+
+	checks         map[string]*v1alpha1.HealthCheckStatus
+	remediated     []time.Time
+	history        []v1alpha1.SelfRemediation
+	safeMode       bool
+	forced         bool
+	safeModeSince  *metav1.Time
+	safeModeReason string
+	healthySince   time.Time
+
+	now func() time.Time
+}
+
+// NewWatchdog creates a new watchdog
+func NewWatchdog(c client.Client, cfg config.WatchdogConfig) *Watchdog {
+	return &Watchdog{
+		client:       c,
+		config:       cfg,
+		inflight:     make(map[uint64]reconcile),
+		errors:       make(map[string]int),
+		remediations: make(map[string][]Remediation),
+		checks:       make(map[string]*v1alpha1.HealthCheckStatus),
+		now:          time.Now,
+	}
+}
+
+// SetAIQueue sets the function reporting the number of evaluations waiting
+// for AI analysis; without it the AI queue check is skipped
+func (w *Watchdog) SetAIQueue(queue func() int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.aiQueue = queue
+}
+
+// AddRemediation registers a corrective action for a check
+func (w *Watchdog) AddRemediation(check string, remediation Remediation) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.remediations[check] = append(w.remediations[check], remediation)
+}
+
+// ReconcileStarted records the start of a reconcile. The returned function
+// records its end with the reconcile's error. Conflicts are not counted as
+// API errors since controllers retry them routinely.
+func (w *Watchdog) ReconcileStarted(controller string) func(err error) {
+	w.mu.Lock()
+	w.nextID++
+	id := w.nextID
+	w.inflight[id] = reconcile{controller: controller, start: w.now()}
+	w.mu.Unlock()
+
+	return func(err error) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.inflight, id)
+		switch {
+		case err == nil:
+			w.errors[controller] = 0
+		case !apierrors.IsConflict(err):
+			w.errors[controller]++
+		}
+	}
+}
+
+// SafeMode reports whether new healing actions must be forced to dry-run
+func (w *Watchdog) SafeMode() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.safeMode || w.forced
+}
+
+// NeedLeaderElection is true since only the leader reconciles
+func (w *Watchdog) NeedLeaderElection() bool {
+	return true
+}
+
+// Start checks the operator's health every interval
+func (w *Watchdog) Start(ctx context.Context) error {
+	interval := w.config.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.Check(ctx); err != nil {
+				log.FromContext(ctx).WithName("watchdog").Error(err, "Failed to publish operator health")
+			}
+		}
+	}
+}
+
+// Check runs the health checks, takes corrective actions and publishes the
+// result in the OperatorHealth resource
+func (w *Watchdog) Check(ctx context.Context) error {
+	health, err := w.getOrCreate(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.forced = health.Spec.ForceSafeMode
+	w.mu.Unlock()
+
+	w.evaluate(ctx)
+
+	w.mu.Lock()
+	w.fillStatus(&health.Status)
+	w.mu.Unlock()
+
+	if err := w.client.Status().Update(ctx, health); err != nil {
+		return fmt.Errorf("failed to update operator health status: %w", err)
+	}
+	return nil
+}
+
+// evaluate runs the checks and reacts to their results
+func (w *Watchdog) evaluate(ctx context.Context) {
+	log := log.FromContext(ctx).WithName("watchdog")
+	now := w.now()
+
+	w.mu.Lock()
+	results := w.runChecks(now)
+	var failing []string
+	for _, name := range sortedKeys(results) {
+		result := results[name]
+		state, ok := w.checks[name]
+		if !ok {
+			state = &v1alpha1.HealthCheckStatus{Name: name, Healthy: true, LastTransitionTime: metav1.NewTime(now)}
+			w.checks[name] = state
+		}
+		if state.Healthy != result.healthy {
+			state.LastTransitionTime = metav1.NewTime(now)
+		}
+		state.Healthy = result.healthy
+		state.Message = result.message
+		if result.healthy {
+			state.ConsecutiveFailures = 0
+			continue
+		}
+		state.ConsecutiveFailures++
+		failing = append(failing, name)
+	}
+	w.mu.Unlock()
+
+	for _, name := range failing {
+		w.remediate(ctx, name, now)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(failing) > 0 {
+		w.healthySince = time.Time{}
+	} else if w.healthySince.IsZero() {
+		w.healthySince = now
+	}
+
+	switch {
+	case !w.safeMode:
+		for _, name := range failing {
+			if int(w.checks[name].ConsecutiveFailures) >= w.config.SafeModeAfter && w.config.SafeModeAfter > 0 {
+				w.safeMode = true
+				w.safeModeSince = &metav1.Time{Time: now}
+				w.safeModeReason = fmt.Sprintf("check %s failed %d times: %s", name, w.checks[name].ConsecutiveFailures, w.checks[name].Message)
+				w.record(now, name, ActionEnterSafeMode, nil, w.safeModeReason)
+				log.Info("Entering safe mode, new healing actions are forced to dry-run", "reason", w.safeModeReason)
+				break
+			}
+		}
+	case len(failing) == 0 && now.Sub(w.healthySince) >= w.config.RecoveryPeriod:
+		w.safeMode = false
+		w.safeModeSince = nil
+		w.safeModeReason = ""
+		w.record(now, "", ActionLeaveSafeMode, nil, fmt.Sprintf("all checks passed for %s", w.config.RecoveryPeriod))
+		log.Info("Leaving safe mode, all checks passed", "period", w.config.RecoveryPeriod)
+	}
+}
+
+// checkResult is the outcome of a health check
+type checkResult struct {
+	healthy bool
+	message string
+}
+
+// runChecks evaluates the health signals. Callers hold mu.
+func (w *Watchdog) runChecks(now time.Time) map[string]checkResult {
+	results := make(map[string]checkResult)
+
+	var stalled []string
+	for _, r := range w.inflight {
+		if age := now.Sub(r.start); w.config.StallThreshold > 0 && age >= w.config.StallThreshold {
+			stalled = append(stalled, fmt.Sprintf("%s running for %s", r.controller, age.Round(time.Second)))
+		}
+	}
+	sort.Strings(stalled)
+	results[CheckReconcileStall] = checkResult{
+		healthy: len(stalled) == 0,
+		message: orOK(strings.Join(stalled, ", ")),
+	}
+
+	var failing []string
+	for _, controller := range sortedKeys(w.errors) {
+		if count := w.errors[controller]; w.config.APIErrorThreshold > 0 && count >= w.config.APIErrorThreshold {
+			failing = append(failing, fmt.Sprintf("%s failed %d consecutive reconciles", controller, count))
+		}
+	}
+	results[CheckAPIErrors] = checkResult{
+		healthy: len(failing) == 0,
+		message: orOK(strings.Join(failing, ", ")),
+	}
+
+	if w.aiQueue != nil {
+		queued := w.aiQueue()
+		result := checkResult{healthy: true, message: fmt.Sprintf("%d evaluations waiting for AI analysis", queued)}
+		if w.config.AIQueueThreshold > 0 && queued >= w.config.AIQueueThreshold {
+			result.healthy = false
+		}
+		results[CheckAIQueue] = result
+	}
+	return results
+}
+
+// remediate runs the corrective actions of a failing check while the
+// hourly budget allows
+func (w *Watchdog) remediate(ctx context.Context, check string, now time.Time) {
+	log := log.FromContext(ctx).WithName("watchdog")
+
+	w.mu.Lock()
+	remediations := w.remediations[check]
+	w.mu.Unlock()
+
+	for _, remediation := range remediations {
+		w.mu.Lock()
+		allowed := w.budgetAvailable(now)
+		if allowed {
+			w.remediated = append(w.remediated, now)
+		}
+		w.mu.Unlock()
+		if !allowed {
+			log.V(1).Info("Self-remediation budget exhausted", "check", check, "action", remediation.Name)
+			return
+		}
+
+		err := remediation.Run(ctx)
+		message := "completed"
+		if err != nil {
+			message = err.Error()
+			log.Error(err, "Self-remediation failed", "check", check, "action", remediation.Name)
+		} else {
+			log.Info("Self-remediation completed", "check", check, "action", remediation.Name)
+		}
+
+		w.mu.Lock()
+		w.record(now, check, remediation.Name, err, message)
+		w.mu.Unlock()
+	}
+}
+
+// budgetAvailable reports whether another remediation is allowed in the
+// past hour. Callers hold mu.
+func (w *Watchdog) budgetAvailable(now time.Time) bool {
+	cutoff := now.Add(-time.Hour)
+	kept := w.remediated[:0]
+	for _, t := range w.remediated {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	w.remediated = kept
+	return len(w.remediated) < w.config.MaxRemediationsPerHour
+}
+
+// record appends a remediation to the bounded history. Callers hold mu.
+func (w *Watchdog) record(now time.Time, check, action string, err error, message string) {
+	w.history = append(w.history, v1alpha1.SelfRemediation{
+		Time:      metav1.NewTime(now),
+		Check:     check,
+		Action:    action,
+		Succeeded: err == nil,
+		Message:   message,
+	})
+	if len(w.history) > maxRemediationHistory {
+		w.history = w.history[len(w.history)-maxRemediationHistory:]
+	}
+}
+
+// fillStatus copies the watchdog state into the status. Callers hold mu.
+func (w *Watchdog) fillStatus(status *v1alpha1.OperatorHealthStatus) {
+	now := metav1.NewTime(w.now())
+	status.LastCheckTime = &now
+	status.SafeMode = w.safeMode || w.forced
+	status.SafeModeSince = w.safeModeSince
+	status.SafeModeReason = w.safeModeReason
+	if w.forced {
+		status.SafeModeReason = "forced by spec.forceSafeMode"
+	}
+
+	status.Checks = status.Checks[:0]
+	var failing []string
+	for _, name := range sortedKeys(w.checks) {
+		status.Checks = append(status.Checks, *w.checks[name])
+		if !w.checks[name].Healthy {
+			failing = append(failing, name)
+		}
+	}
+	status.Remediations = append([]v1alpha1.SelfRemediation(nil), w.history...)
+
+	condition := metav1.Condition{
+		Type:    ConditionTypeHealthy,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ChecksPassing",
+		Message: "All health checks pass",
+	}
+	if len(failing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ChecksFailing"
+		condition.Message = "Failing checks: " + strings.Join(failing, ", ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// getOrCreate returns the OperatorHealth resource, creating it if needed
+func (w *Watchdog) getOrCreate(ctx context.Context) (*v1alpha1.OperatorHealth, error) {
+	health := &v1alpha1.OperatorHealth{}
+	key := client.ObjectKey{Namespace: w.config.Namespace, Name: w.config.Name}
+	err := w.client.Get(ctx, key, health)
+	if err == nil {
+		return health, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get operator health: %w", err)
+	}
+
+	health = &v1alpha1.OperatorHealth{
+		ObjectMeta: metav1.ObjectMeta{Namespace: w.config.Namespace, Name: w.config.Name},
+	}
+	if err := w.client.Create(ctx, health); err != nil {
+		return nil, fmt.Errorf("failed to create operator health: %w", err)
+	}
+	return health, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orOK(s string) string {
+	if s == "" {
+		return "ok"
+	}
+	return s
+}
@@ -0,0 +1,166 @@
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+func newTestWatchdog(t *testing.T, cfg config.WatchdogConfig) (*Watchdog, client.Client, *time.Time) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.OperatorHealth{}).Build()
+
+	cfg.Namespace = "kubeskippy-system"
+	cfg.Name = "kubeskippy"
+	w := NewWatchdog(c, cfg)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	return w, c, &now
+}
+
+func getHealth(t *testing.T, c client.Client) *v1alpha1.OperatorHealth {
+	health := &v1alpha1.OperatorHealth{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "kubeskippy-system", Name: "kubeskippy"}, health))
+	return health
+}
+
+func checkStatus(health *v1alpha1.OperatorHealth, name string) *v1alpha1.HealthCheckStatus {
+	for i := range health.Status.Checks {
+		if health.Status.Checks[i].Name == name {
+			return &health.Status.Checks[i]
+		}
+	}
+	return nil
+}
+
+func TestWatchdog_APIErrorsEnterAndLeaveSafeMode(t *testing.T) {
+	w, c, now := newTestWatchdog(t, config.WatchdogConfig{
+		APIErrorThreshold: 2,
+		SafeModeAfter:     2,
+		RecoveryPeriod:    time.Minute,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		w.ReconcileStarted("healingpolicy")(errors.New("connection refused"))
+	}
+	// Conflicts are retried routinely and are not API errors
+	w.ReconcileStarted("healingpolicy")(apierrors.NewConflict(schema.GroupResource{}, "web", errors.New("modified")))
+
+	require.NoError(t, w.Check(ctx))
+	assert.False(t, w.SafeMode())
+	check := checkStatus(getHealth(t, c), CheckAPIErrors)
+	require.NotNil(t, check)
+	assert.False(t, check.Healthy)
+	assert.Equal(t, "healingpolicy failed 2 consecutive reconciles", check.Message)
+
+	*now = now.Add(30 * time.Second)
+	require.NoError(t, w.Check(ctx))
+	assert.True(t, w.SafeMode())
+	health := getHealth(t, c)
+	assert.True(t, health.Status.SafeMode)
+	assert.Contains(t, health.Status.SafeModeReason, "check api-errors failed 2 times")
+	require.Len(t, health.Status.Remediations, 1)
+	assert.Equal(t, ActionEnterSafeMode, health.Status.Remediations[0].Action)
+
+	// A successful reconcile clears the check, safe mode ends after the
+	// recovery period
+	w.ReconcileStarted("healingpolicy")(nil)
+	*now = now.Add(30 * time.Second)
+	require.NoError(t, w.Check(ctx))
+	assert.True(t, w.SafeMode())
+
+	*now = now.Add(time.Minute)
+	require.NoError(t, w.Check(ctx))
+	assert.False(t, w.SafeMode())
+	health = getHealth(t, c)
+	assert.False(t, health.Status.SafeMode)
+	assert.Equal(t, ActionLeaveSafeMode, health.Status.Remediations[1].Action)
+}
+
+func TestWatchdog_ReconcileStall(t *testing.T) {
+	w, c, now := newTestWatchdog(t, config.WatchdogConfig{StallThreshold: 10 * time.Minute})
+
+	done := w.ReconcileStarted("healingaction")
+	*now = now.Add(11 * time.Minute)
+	require.NoError(t, w.Check(context.Background()))
+
+	check := checkStatus(getHealth(t, c), CheckReconcileStall)
+	require.NotNil(t, check)
+	assert.False(t, check.Healthy)
+	assert.Equal(t, "healingaction running for 11m0s", check.Message)
+	assert.Equal(t, int32(1), check.ConsecutiveFailures)
+
+	done(nil)
+	require.NoError(t, w.Check(context.Background()))
+	check = checkStatus(getHealth(t, c), CheckReconcileStall)
+	assert.True(t, check.Healthy)
+	assert.Zero(t, check.ConsecutiveFailures)
+}
+
+func TestWatchdog_RemediationBudget(t *testing.T) {
+	w, c, now := newTestWatchdog(t, config.WatchdogConfig{
+		AIQueueThreshold:       5,
+		MaxRemediationsPerHour: 2,
+	})
+	w.SetAIQueue(func() int { return 8 })
+	resets := 0
+	w.AddRemediation(CheckAIQueue, Remediation{
+		Name: "reset-ai-coordinator",
+		Run: func(context.Context) error {
+			resets++
+			if resets == 2 {
+				return errors.New("shared AI analysis in progress")
+			}
+			return nil
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, w.Check(context.Background()))
+		*now = now.Add(time.Minute)
+	}
+	assert.Equal(t, 2, resets)
+
+	health := getHealth(t, c)
+	require.Len(t, health.Status.Remediations, 2)
+	assert.True(t, health.Status.Remediations[0].Succeeded)
+	assert.False(t, health.Status.Remediations[1].Succeeded)
+	assert.Equal(t, "shared AI analysis in progress", health.Status.Remediations[1].Message)
+
+	// The budget is restored an hour later
+	*now = now.Add(time.Hour)
+	require.NoError(t, w.Check(context.Background()))
+	assert.Equal(t, 3, resets)
+}
+
+func TestWatchdog_ForceSafeMode(t *testing.T) {
+	w, c, _ := newTestWatchdog(t, config.WatchdogConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, w.Check(ctx))
+	assert.False(t, w.SafeMode())
+
+	health := getHealth(t, c)
+	health.Spec.ForceSafeMode = true
+	require.NoError(t, c.Update(ctx, health))
+
+	require.NoError(t, w.Check(ctx))
+	assert.True(t, w.SafeMode())
+	health = getHealth(t, c)
+	assert.True(t, health.Status.SafeMode)
+	assert.Equal(t, "forced by spec.forceSafeMode", health.Status.SafeModeReason)
+}
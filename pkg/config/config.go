@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -12,12 +14,19 @@ type Config struct {
 	// ProbeAddr is the address the probe endpoint binds to
 	ProbeAddr string `json:"probeAddr,omitempty"`
 
+	// Serving secures the metrics, health probe and debug endpoints
+	Serving ServingConfig `json:"serving,omitempty"`
+
 	// EnableLeaderElection enables leader election for controller manager
 	EnableLeaderElection bool `json:"enableLeaderElection,omitempty"`
 
 	// Namespace to watch (empty means all namespaces)
 	WatchNamespace string `json:"watchNamespace,omitempty"`
 
+	// EnableWebhooks serves the admission webhooks; requires serving
+	// certificates to be mounted
+	EnableWebhooks bool `json:"enableWebhooks,omitempty"`
+
 	// MetricsCollector configuration
 	Metrics MetricsConfig `json:"metrics,omitempty"`
 
@@ -30,8 +39,62 @@ type Config struct {
 	// Remediation configuration
 	Remediation RemediationConfig `json:"remediation,omitempty"`
 
+	// Recipes configuration
+	Recipes RecipesConfig `json:"recipes,omitempty"`
+
+	// IssueTracker configuration
+	IssueTracker IssueTrackerConfig `json:"issueTracker,omitempty"`
+
 	// Logging configuration
 	Logging LoggingConfig `json:"logging,omitempty"`
+
+	// Events configures Kubernetes event recording
+	Events EventsConfig `json:"events,omitempty"`
+
+	// Archive configures the export of action history to object storage
+	Archive ArchiveConfig `json:"archive,omitempty"`
+
+	// Watchdog configures the operator's monitoring of its own health
+	Watchdog WatchdogConfig `json:"watchdog,omitempty"`
+
+	// PolicyTesting configures the policy testing debug endpoint
+	PolicyTesting PolicyTestingConfig `json:"policyTesting,omitempty"`
+
+	// Profiling configures the performance profiling debug endpoints
+	Profiling ProfilingConfig `json:"profiling,omitempty"`
+
+	// Plugins configures the loading of trigger evaluator plugins
+	Plugins PluginsConfig `json:"plugins,omitempty"`
+
+	// FaultInjection injects failures for e2e tests and chaos drills
+	FaultInjection FaultInjectionConfig `json:"faultInjection,omitempty"`
+
+	// Telemetry configures the opt-in anonymous usage reports
+	Telemetry TelemetryConfig `json:"telemetry,omitempty"`
+}
+
+// ServingConfig secures the operator's HTTP endpoints. The serving
+// certificate is read from CertDir and reloaded when it is rotated.
+type ServingConfig struct {
+	// SecureMetrics serves metrics over HTTPS to callers authenticated with
+	// a TokenReview and authorized with a SubjectAccessReview for get on
+	// the /metrics non-resource URL
+	SecureMetrics bool `json:"secureMetrics,omitempty"`
+
+	// CertDir holds the serving certificate and key. Secure metrics fall
+	// back to a self-signed certificate without one.
+	CertDir string `json:"certDir,omitempty"`
+
+	// CertName and KeyName are the file names of the certificate and key
+	CertName string `json:"certName,omitempty"`
+	KeyName  string `json:"keyName,omitempty"`
+
+	// ProbeTLS serves the health probes over HTTPS; requires CertDir
+	ProbeTLS bool `json:"probeTLS,omitempty"`
+
+	// DebugTLS serves the policy testing and profiling endpoints over
+	// HTTPS; requires CertDir
+	DebugTLS bool `json:"debugTLS,omitempty"`
 }
 
 // MetricsConfig configures the metrics collector
@@ -42,6 +105,10 @@ type MetricsConfig struct {
 	// MetricsServerEnabled enables metrics-server integration
 	MetricsServerEnabled bool `json:"metricsServerEnabled,omitempty"`
 
+	// QueryCacheTTL is how long Prometheus query results are reused by
+	// triggers issuing identical queries; 0 disables the cache
+	QueryCacheTTL time.Duration `json:"queryCacheTTL,omitempty"`
+
 	// CollectionInterval is how often to collect metrics
 	CollectionInterval time.Duration `json:"collectionInterval,omitempty"`
 
@@ -50,14 +117,99 @@ type MetricsConfig struct {
 
 	// CustomQueries for additional Prometheus queries
 	CustomQueries map[string]string `json:"customQueries,omitempty"`
+
+	// PushReceiver accepts metrics pushed by applications
+	PushReceiver PushReceiverConfig `json:"pushReceiver,omitempty"`
+
+	// AlertRules generates PrometheusRules from policy triggers
+	AlertRules AlertRulesConfig `json:"alertRules,omitempty"`
+
+	// TriggerHistorySize is the number of evaluated values kept per metric
+	// trigger in the policy status; 0 disables the history
+	TriggerHistorySize int `json:"triggerHistorySize,omitempty"`
+
+	// TriggerValueSeriesLimit caps the series of the trigger value gauges
+	// exported for policies that set recordTriggerValues. Triggers past the
+	// limit are not exported until series are freed; 0 disables the gauges.
+	TriggerValueSeriesLimit int `json:"triggerValueSeriesLimit,omitempty"`
+
+	// StateMetrics exports per-object gauges of HealingPolicies and
+	// HealingActions on the metrics endpoint
+	StateMetrics StateMetricsConfig `json:"stateMetrics,omitempty"`
+
+	// MaxLowSignalPods caps the pods running ready without restarts kept
+	// per collection, bounding memory in clusters with many pods. Pods that
+	// may need healing are always kept; 0 keeps every pod.
+	MaxLowSignalPods int `json:"maxLowSignalPods,omitempty"`
+
+	// PatternDetectors names the registered time series detectors run on
+	// every analysis. Their patterns fire "pattern:<name>" metric queries
+	// and are included in the AI analysis.
+	PatternDetectors []string `json:"patternDetectors,omitempty"`
+
+	// CapacitySignals adds unschedulable pods, FailedScheduling events and
+	// the cluster autoscaler's status to the collected metrics
+	CapacitySignals CapacitySignalsConfig `json:"capacitySignals,omitempty"`
+}
+
+// CapacitySignalsConfig configures the scheduling and autoscaling signals
+// that tell a cluster out of capacity apart from failing workloads. Metric
+// triggers query them as pending_unschedulable, failed_scheduling and
+// out_of_capacity, and the AI analysis includes them.
+type CapacitySignalsConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ClusterAutoscalerStatus is the "namespace/name" of the cluster
+	// autoscaler's status ConfigMap. Empty leaves the autoscaler out.
+	ClusterAutoscalerStatus string `json:"clusterAutoscalerStatus,omitempty"`
+}
+
+// StateMetricsConfig configures the kube-state-metrics style gauges of
+// HealingPolicies and HealingActions
+type StateMetricsConfig struct {
+	// Enabled exports the gauges
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// AlertRulesConfig configures the PrometheusRules generated from the
+// Prometheus metric triggers of each policy. Requires the
+// prometheus-operator CRDs.
+type AlertRulesConfig struct {
+	// Enabled generates a PrometheusRule per policy
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Severity label of the generated alerts
+	Severity string `json:"severity,omitempty"`
+
+	// Labels added to each PrometheusRule so Prometheus selects it
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// PushReceiverConfig configures the receiver for application-pushed metrics,
+// which MetricTriggers query as "custom:<name>"
+type PushReceiverConfig struct {
+	// Enabled starts the receiver
+	Enabled bool `json:"enabled,omitempty"`
+
+	// StatsDAddr is the UDP address for StatsD gauges and counters tagged
+	// with namespace and workload. Empty disables StatsD.
+	StatsDAddr string `json:"statsdAddr,omitempty"`
+
+	// OTLPAddr is the address for OTLP/HTTP metrics in JSON encoding.
+	// Empty disables OTLP.
+	OTLPAddr string `json:"otlpAddr,omitempty"`
+
+	// TTL is how long a pushed value is used after it was received
+	TTL time.Duration `json:"ttl,omitempty"`
 }
 
 // AIConfig configures the AI integration
 type AIConfig struct {
-	// Provider (ollama, openai, etc.)
+	// Provider (ollama, openai, grpc, bedrock, vertex, azure-openai)
 	Provider string `json:"provider,omitempty"`
 
-	// Model to use
+	// Model to use. For azure-openai it names the deployment.
 	Model string `json:"model,omitempty"`
 
 	// Endpoint URL
@@ -83,6 +235,125 @@ type AIConfig struct {
 
 	// ValidateResponses enables response validation
 	ValidateResponses bool `json:"validateResponses,omitempty"`
+
+	// ValidationMode is "individual" (one validation query per
+	// recommendation) or "batch" (one query for all recommendations)
+	ValidationMode string `json:"validationMode,omitempty"`
+
+	// ValidateMinRisk limits AI validation to recommendations at or above
+	// this risk level (low, medium, high, critical); empty validates all
+	ValidateMinRisk string `json:"validateMinRisk,omitempty"`
+
+	// DecisionMode is "filter" (only actions matching a confident AI
+	// recommendation are taken, falling back to the highest priority
+	// rule-based actions) or "blend" (every action is taken in the order of
+	// its priority blended from rule priority and AI confidence)
+	DecisionMode string `json:"decisionMode,omitempty"`
+
+	// BlendWeights weigh rule priority and AI confidence in blend mode
+	BlendWeights AIBlendWeights `json:"blendWeights,omitempty"`
+
+	// GRPC configures the grpc provider
+	GRPC GRPCConfig `json:"grpc,omitempty"`
+
+	// Bedrock configures the bedrock provider
+	Bedrock BedrockConfig `json:"bedrock,omitempty"`
+
+	// Vertex configures the vertex provider
+	Vertex VertexConfig `json:"vertex,omitempty"`
+
+	// AzureOpenAI configures the azure-openai provider
+	AzureOpenAI AzureOpenAIConfig `json:"azureOpenAI,omitempty"`
+
+	// CoordinationInterval shares one AI analysis between all policies per
+	// interval instead of analyzing for each policy. Zero disables sharing.
+	CoordinationInterval time.Duration `json:"coordinationInterval,omitempty"`
+
+	// DecisionTTL is how long AIDecision records are kept after their
+	// HealingAction finished
+	DecisionTTL time.Duration `json:"decisionTTL,omitempty"`
+
+	// ProgressInterval is the minimum time between updates of a policy's
+	// status.aiAnalysis while a streaming provider (ollama, openai) sends
+	// its response. Zero disables streaming.
+	ProgressInterval time.Duration `json:"progressInterval,omitempty"`
+
+	// Recording records AI responses to disk or replays them
+	Recording AIRecordingConfig `json:"recording,omitempty"`
+}
+
+// AIBlendWeights weigh the parts of a blended action priority: Rule times
+// the action's priority plus AI times the AI confidence scaled to 0-100
+type AIBlendWeights struct {
+	// Rule weighs the priority of the policy's action
+	Rule float64 `json:"rule,omitempty"`
+
+	// AI weighs the confidence of the matching AI recommendation
+	AI float64 `json:"ai,omitempty"`
+}
+
+// AIRecordingConfig configures the recording and replay of AI responses,
+// one JSON file per model and prompt
+type AIRecordingConfig struct {
+	// Mode is "record", which stores every response of the provider, or
+	// "replay", which serves stored responses without contacting the
+	// provider and fails prompts without a recording. Empty disables it.
+	Mode string `json:"mode,omitempty"`
+
+	// Dir holds the recorded responses
+	Dir string `json:"dir,omitempty"`
+}
+
+// GRPCConfig configures the gRPC inference client
+type GRPCConfig struct {
+	// Insecure uses plaintext HTTP/2 instead of TLS
+	Insecure bool `json:"insecure,omitempty"`
+
+	// CAFile verifies the server certificate
+	CAFile string `json:"caFile,omitempty"`
+
+	// CertFile and KeyFile hold the client certificate for mTLS
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	// ServerName overrides the name used to verify the server certificate
+	ServerName string `json:"serverName,omitempty"`
+
+	// MaxConnections is the number of pooled connections
+	MaxConnections int `json:"maxConnections,omitempty"`
+
+	// InputTensor is the name of the text input tensor
+	InputTensor string `json:"inputTensor,omitempty"`
+
+	// OutputTensor is the name of the text output tensor
+	OutputTensor string `json:"outputTensor,omitempty"`
+}
+
+// BedrockConfig configures the bedrock provider, which calls AWS Bedrock
+// with credentials of the environment: IRSA (AWS_ROLE_ARN and
+// AWS_WEB_IDENTITY_TOKEN_FILE), EKS Pod Identity or static keys
+type BedrockConfig struct {
+	// Region of the Bedrock runtime, AWS_REGION when empty
+	Region string `json:"region,omitempty"`
+}
+
+// VertexConfig configures the vertex provider, which calls Vertex AI with
+// the token of the GKE workload identity of the manager
+type VertexConfig struct {
+	// Project hosting the model
+	Project string `json:"project,omitempty"`
+
+	// Location of the model
+	Location string `json:"location,omitempty"`
+}
+
+// AzureOpenAIConfig configures the azure-openai provider, which calls the
+// Azure OpenAI resource at the AI endpoint with tokens of the Azure AD
+// workload identity of the manager (AZURE_CLIENT_ID, AZURE_TENANT_ID and
+// AZURE_FEDERATED_TOKEN_FILE)
+type AzureOpenAIConfig struct {
+	// APIVersion of the Azure OpenAI API
+	APIVersion string `json:"apiVersion,omitempty"`
 }
 
 // SafetyConfig configures safety controls
@@ -107,6 +378,130 @@ type SafetyConfig struct {
 
 	// AuditLog configuration
 	AuditLog AuditLogConfig `json:"auditLog,omitempty"`
+
+	// ZoneSpread guarantees replicas survive in every zone
+	ZoneSpread ZoneSpreadConfig `json:"zoneSpread,omitempty"`
+
+	// TargetCooldown blocks new actions from the same policy on a target
+	// after it was healed successfully, while it recovers. Zero disables it.
+	TargetCooldown time.Duration `json:"targetCooldown,omitempty"`
+
+	// SettlingWindow leaves the pods replaced by a policy's restart or
+	// delete actions out of its trigger evaluation for this long after the
+	// action, so their startup restarts do not fire the triggers again.
+	// Zero disables it.
+	SettlingWindow time.Duration `json:"settlingWindow,omitempty"`
+
+	// FailureCooloff blocks retrying an action type on a target after it
+	// failed there, whichever policy created it. Independent of the circuit
+	// breaker and retry policy. Zero disables it.
+	FailureCooloff time.Duration `json:"failureCooloff,omitempty"`
+
+	// CooldownGroupWindow blocks every policy labeled with the same
+	// kubeskippy.io/cooldown-group after any of them acted, so related
+	// workloads are not healed by several policies at once. Zero disables
+	// cooldown groups.
+	CooldownGroupWindow time.Duration `json:"cooldownGroupWindow,omitempty"`
+
+	// RequireActionTemplates only allows policy actions that reference an
+	// ActionTemplate
+	RequireActionTemplates bool `json:"requireActionTemplates,omitempty"`
+
+	// Calendar applies business hours and release freezes to healing
+	Calendar CalendarConfig `json:"calendar,omitempty"`
+
+	// ActionStore selects where action records and rate limit counters are
+	// kept
+	ActionStore ActionStoreConfig `json:"actionStore,omitempty"`
+}
+
+// Circuit breaker keying modes
+const (
+	CircuitBreakerKeyPolicy          = "policy"
+	CircuitBreakerKeyPolicyNamespace = "policy+namespace"
+	CircuitBreakerKeyPolicyTarget    = "policy+target"
+)
+
+// Action store backends
+const (
+	ActionStoreMemory = "memory"
+	ActionStoreRedis  = "redis"
+)
+
+// ActionStoreConfig selects the action store. Managers sharing a Redis or
+// Valkey store, such as the replicas of an HA deployment or the federation
+// hub and its clusters, share their rate limits.
+type ActionStoreConfig struct {
+	// Backend is "memory" (default) or "redis"
+	Backend string `json:"backend,omitempty"`
+
+	// Redis configures the redis backend
+	Redis RedisStoreConfig `json:"redis,omitempty"`
+}
+
+// RedisStoreConfig locates the Redis or Valkey server of the redis backend
+type RedisStoreConfig struct {
+	// SecretName and SecretNamespace of the Secret holding the connection:
+	// either a "url" (redis:// or rediss://) or an "address" (host:port),
+	// with optional "username", "password", "db" and "tls" ("true") keys
+	SecretName      string `json:"secretName,omitempty"`
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+
+	// KeyPrefix of every key the store writes
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// DialTimeout bounds connecting to the server
+	DialTimeout time.Duration `json:"dialTimeout,omitempty"`
+}
+
+// CalendarConfig configures business hours and blackout dates
+type CalendarConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TimeZone of the business hours and blackout dates as an IANA name,
+	// e.g. "Europe/Berlin"
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// BusinessDays are the weekdays with business hours, e.g. "Mon"
+	BusinessDays []string `json:"businessDays,omitempty"`
+
+	// BusinessHoursStart is the start of business hours as "15:04"
+	BusinessHoursStart string `json:"businessHoursStart,omitempty"`
+
+	// BusinessHoursEnd is the end of business hours as "15:04"
+	BusinessHoursEnd string `json:"businessHoursEnd,omitempty"`
+
+	// OffHoursMaxActionsPerHour replaces MaxActionsPerHour at nights and on
+	// weekends unless a policy sets its own off-hours limit. Zero keeps
+	// MaxActionsPerHour.
+	OffHoursMaxActionsPerHour int `json:"offHoursMaxActionsPerHour,omitempty"`
+
+	// OffHoursTargetCooldown replaces an enabled safety.targetCooldown for
+	// targets healed outside business hours. Zero keeps targetCooldown.
+	OffHoursTargetCooldown time.Duration `json:"offHoursTargetCooldown,omitempty"`
+
+	// OffHoursFailureCooloff replaces an enabled safety.failureCooloff for
+	// actions that failed outside business hours. Zero keeps failureCooloff.
+	OffHoursFailureCooloff time.Duration `json:"offHoursFailureCooloff,omitempty"`
+
+	// BlackoutConfigMap is the namespace/name of a ConfigMap of release
+	// freezes. Each key names a freeze and its value is a date
+	// ("2024-12-24") or an inclusive range ("2024-12-20/2025-01-03").
+	// Automatic policies only create dry-run actions during a freeze.
+	BlackoutConfigMap string `json:"blackoutConfigMap,omitempty"`
+}
+
+// ZoneSpreadConfig configures topology-aware validation of pod restarts and deletes
+type ZoneSpreadConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TopologyKey is the node label identifying the zone
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// MinReplicasPerZone that must remain ready after the action
+	MinReplicasPerZone int `json:"minReplicasPerZone,omitempty"`
 }
 
 // CircuitBreakerConfig configures the circuit breaker
@@ -125,6 +520,37 @@ type CircuitBreakerConfig struct {
 
 	// HalfOpenMaxActions in half-open state
 	HalfOpenMaxActions int `json:"halfOpenMaxActions,omitempty"`
+
+	// KeyBy selects the failure domain a breaker covers: "policy",
+	// "policy+namespace" (the target's namespace) or "policy+target"
+	KeyBy string `json:"keyBy,omitempty"`
+
+	// Thresholds override the thresholds of matching breakers; the first
+	// matching entry wins
+	Thresholds []CircuitBreakerThresholds `json:"thresholds,omitempty"`
+}
+
+// CircuitBreakerThresholds overrides breaker thresholds for the actions of a
+// policy, target namespace or target kind. Empty selectors match anything
+// and zero thresholds keep the global value.
+type CircuitBreakerThresholds struct {
+	// Policy name to match
+	Policy string `json:"policy,omitempty"`
+
+	// Namespace of the target to match
+	Namespace string `json:"namespace,omitempty"`
+
+	// Kind of the target to match
+	Kind string `json:"kind,omitempty"`
+
+	// FailureThreshold before opening
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// SuccessThreshold before closing
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+
+	// Timeout when open
+	Timeout time.Duration `json:"timeout,omitempty"`
 }
 
 // AuditLogConfig configures audit logging
@@ -146,6 +572,22 @@ type AuditLogConfig struct {
 
 	// IncludeMetrics in audit logs
 	IncludeMetrics bool `json:"includeMetrics,omitempty"`
+
+	// Signing signs each action record for tamper-evident reviews
+	Signing AuditSigningConfig `json:"signing,omitempty"`
+}
+
+// AuditSigningConfig locates the key used to sign action records
+type AuditSigningConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SecretName and SecretNamespace of the Secret holding the key
+	SecretName      string `json:"secretName,omitempty"`
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+
+	// SecretKey holding the PEM-encoded ECDSA P-256 or Ed25519 private key
+	SecretKey string `json:"secretKey,omitempty"`
 }
 
 // RemediationConfig configures the remediation engine
@@ -159,16 +601,168 @@ type RemediationConfig struct {
 	// RetryBackoff configuration
 	RetryBackoff time.Duration `json:"retryBackoff,omitempty"`
 
+	// AttemptTimeout bounds each execution attempt of actions that do not
+	// set their own. Executors are cancelled once it runs out.
+	AttemptTimeout time.Duration `json:"attemptTimeout,omitempty"`
+
 	// EnableRollback allows automatic rollback on failure
 	EnableRollback bool `json:"enableRollback,omitempty"`
 
 	// ParallelActions maximum concurrent actions
 	ParallelActions int `json:"parallelActions,omitempty"`
 
+	// MaxActionsPerEvaluation limits the actions a policy creates per evaluation
+	MaxActionsPerEvaluation int `json:"maxActionsPerEvaluation,omitempty"`
+
+	// CreateBatchSize is the number of actions created per batch
+	CreateBatchSize int `json:"createBatchSize,omitempty"`
+
+	// CreateQPS limits the rate of action creation requests
+	CreateQPS float32 `json:"createQPS,omitempty"`
+
+	// CreateBurst is the burst allowed above CreateQPS
+	CreateBurst int `json:"createBurst,omitempty"`
+
+	// RBACPreflight checks the operator's permissions with
+	// SelfSubjectAccessReviews before executing an action
+	RBACPreflight bool `json:"rbacPreflight,omitempty"`
+
+	// PreemptionPriority is the action priority at or above which an action
+	// cancels unfinished lower-priority actions on the same target before it
+	// starts. Zero disables preemption.
+	PreemptionPriority int32 `json:"preemptionPriority,omitempty"`
+
+	// ChangeCauseAnnotations records the action changing a Deployment,
+	// StatefulSet or DaemonSet in its kubernetes.io/change-cause and
+	// kubeskippy.io/action-id annotations, for kubectl rollout history and
+	// GitOps diff views
+	ChangeCauseAnnotations bool `json:"changeCauseAnnotations,omitempty"`
+
+	// ServerDryRun sends the writes of dry-run actions to the API server
+	// with dryRun=All instead of simulating them
+	ServerDryRun ServerDryRunConfig `json:"serverDryRun,omitempty"`
+
+	// DryRunObservationWindow is how long the target of a dry-run action is
+	// watched for recovering on its own or being fixed by someone else,
+	// for the dry-run comparison of healing reports. Zero disables it.
+	DryRunObservationWindow time.Duration `json:"dryRunObservationWindow,omitempty"`
+
+	// Capacity checks node headroom before scale-ups
+	Capacity CapacityConfig `json:"capacity,omitempty"`
+
+	// Janitor cleans up helper pods and node taints left behind by actions
+	// that are gone or finished
+	Janitor JanitorConfig `json:"janitor,omitempty"`
+
 	// ActionDefaults per action type
 	ActionDefaults map[string]ActionConfig `json:"actionDefaults,omitempty"`
 }
 
+// CapacityConfig configures the node headroom check before scale-ups.
+// Scale-ups whose new replicas do not fit on the schedulable nodes fail
+// with the CapacityBlocked reason instead of creating Pending pods.
+type CapacityConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ClusterAutoscalerStatus is the "namespace/name" of the cluster
+	// autoscaler's status ConfigMap. While it reports the autoscaler healthy,
+	// scale-ups are left to it to add nodes for. Empty ignores the
+	// autoscaler.
+	ClusterAutoscalerStatus string `json:"clusterAutoscalerStatus,omitempty"`
+
+	// RecommendNodeScaling adds the node pool capacity missing for the
+	// blocked replicas to the action result and event
+	RecommendNodeScaling bool `json:"recommendNodeScaling,omitempty"`
+}
+
+// JanitorConfig configures the cleanup of orphaned auxiliary resources.
+// Executors label the resources they create with the action owning them,
+// and the janitor removes those whose action no longer exists or finished.
+type JanitorConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval between sweeps
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// GracePeriod before helper pods without an owning action, started
+	// by earlier versions, are deleted
+	GracePeriod time.Duration `json:"gracePeriod,omitempty"`
+}
+
+// ServerDryRunConfig configures server-side dry runs. Admission webhooks,
+// quota and validation see the requests, and the action result reports the
+// changes the API server would have made. Actions without a server-side
+// equivalent, such as container restarts, are still simulated.
+type ServerDryRunConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ImpersonateUser the dry-run requests are made as, e.g.
+	// "system:serviceaccount:apps:deployer", so RBAC is checked for that
+	// identity. Requires the impersonate permission; empty uses the
+	// operator's identity.
+	ImpersonateUser string `json:"impersonateUser,omitempty"`
+
+	// ImpersonateGroups sent with ImpersonateUser
+	ImpersonateGroups []string `json:"impersonateGroups,omitempty"`
+}
+
+// RecipesConfig configures the built-in healing recipes, which generate
+// policies for common pod pathologies
+type RecipesConfig struct {
+	// Enabled installs the recipe policies at startup
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Names of the recipes to install (empty installs all recipes)
+	Names []string `json:"names,omitempty"`
+
+	// Namespaces to install the recipe policies into; each policy only
+	// targets its own namespace
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// IssueTrackerConfig configures incident summaries posted to an issue
+// tracker when healing actions complete. Policies name the related issue in
+// the kubeskippy.io/issue annotation.
+type IssueTrackerConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Provider is "webhook" (post the JSON summary to URL), "github" or
+	// "jira" (comment on the related issue)
+	Provider string `json:"provider,omitempty"`
+
+	// URL of the webhook, or the API base URL for github and jira
+	URL string `json:"url,omitempty"`
+
+	// TokenSecretName, TokenSecretNamespace and TokenSecretKey locate the
+	// bearer token sent with each request
+	TokenSecretName      string `json:"tokenSecretName,omitempty"`
+	TokenSecretNamespace string `json:"tokenSecretNamespace,omitempty"`
+	TokenSecretKey       string `json:"tokenSecretKey,omitempty"`
+
+	// ActionURLTemplate links summaries back to the HealingAction;
+	// "{namespace}" and "{name}" are replaced
+	ActionURLTemplate string `json:"actionURLTemplate,omitempty"`
+
+	// NotifyOnSuccess and NotifyOnFailure select the outcomes to report
+	NotifyOnSuccess bool `json:"notifyOnSuccess,omitempty"`
+	NotifyOnFailure bool `json:"notifyOnFailure,omitempty"`
+
+	// NotifyOnTrigger reports policy triggers becoming active, still firing
+	// and resolved
+	NotifyOnTrigger bool `json:"notifyOnTrigger,omitempty"`
+
+	// NotifyOnEscalation reports targets healing is ineffective for: their
+	// actions keep failing or the circuit breaker blocks them
+	NotifyOnEscalation bool `json:"notifyOnEscalation,omitempty"`
+
+	// Timeout of each request
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
 // ActionConfig configures specific action types
 type ActionConfig struct {
 	// Enabled flag
@@ -187,6 +781,219 @@ type ActionConfig struct {
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
 }
 
+// EventsConfig configures aggregation of the Kubernetes events recorded
+// for healing actions. Events are correlated by policy, target and reason;
+// the first event of a series is recorded, repeats within the window are
+// counted and recorded every EmitEvery occurrences.
+type EventsConfig struct {
+	// AggregationWindow is how long a series of correlated events lasts.
+	// Zero records every event.
+	AggregationWindow time.Duration `json:"aggregationWindow,omitempty"`
+
+	// EmitEvery records a repeated event every EmitEvery occurrences of a
+	// series; zero records only the first event of a series
+	EmitEvery int `json:"emitEvery,omitempty"`
+
+	// TriggerHeartbeat is how often a trigger that keeps firing is reported
+	// as still firing; activation and resolution are always reported. Zero
+	// disables heartbeats.
+	TriggerHeartbeat time.Duration `json:"triggerHeartbeat,omitempty"`
+}
+
+// ArchiveConfig configures the periodic export of completed actions, audit
+// records and AI decisions to object storage for long-term retention.
+// Records are written as JSONL or Parquet objects partitioned by kind, date
+// and policy.
+type ArchiveConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Provider is "file" (write below Path, e.g. a mounted bucket), "http"
+	// (PUT each object below URL, e.g. an Azure container URL with a SAS
+	// query or a presigned prefix), "s3" (an S3 or S3 compatible bucket,
+	// SigV4 signed) or "gcs" (a Google Cloud Storage bucket)
+	Provider string `json:"provider,omitempty"`
+
+	// Path of the directory objects are written to by the file provider
+	Path string `json:"path,omitempty"`
+
+	// URL objects are uploaded below by the http provider, or the endpoint
+	// of an S3 compatible store or GCS emulator (empty for AWS and GCS)
+	URL string `json:"url,omitempty"`
+
+	// Bucket objects are written to by the s3 and gcs providers
+	Bucket string `json:"bucket,omitempty"`
+
+	// Region of the S3 bucket
+	Region string `json:"region,omitempty"`
+
+	// CredentialsSecretName names the Secret in TokenSecretNamespace with
+	// the accessKeyID, secretAccessKey and optional sessionToken keys for
+	// s3, or the service account key.json for gcs. Without it, s3 uses the
+	// IAM role of the service account (IRSA) and gcs the workload identity.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// Format of the objects, "jsonl" or "parquet"
+	Format string `json:"format,omitempty"`
+
+	// CheckpointConfigMap names the ConfigMap in TokenSecretNamespace the
+	// end of the last exported window is kept in, so restarts and leader
+	// changes neither skip nor repeat windows (empty disables it)
+	CheckpointConfigMap string `json:"checkpointConfigMap,omitempty"`
+
+	// TokenSecretName, TokenSecretNamespace and TokenSecretKey locate the
+	// bearer token sent with each upload
+	TokenSecretName      string `json:"tokenSecretName,omitempty"`
+	TokenSecretNamespace string `json:"tokenSecretNamespace,omitempty"`
+	TokenSecretKey       string `json:"tokenSecretKey,omitempty"`
+
+	// Prefix of every object key
+	Prefix string `json:"prefix,omitempty"`
+
+	// Interval between exports
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Timeout of each upload
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// WatchdogConfig configures the watchdog, which checks the operator's own
+// health, takes bounded corrective actions and enters safe mode, forcing
+// new healing actions to dry-run, when checks keep failing. Its findings
+// are published in an OperatorHealth resource.
+type WatchdogConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval between health checks
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Namespace and Name of the OperatorHealth resource
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+
+	// StallThreshold is how long a reconcile may run before it is stalled
+	StallThreshold time.Duration `json:"stallThreshold,omitempty"`
+
+	// APIErrorThreshold is the number of consecutive failed reconciles of
+	// a controller that fails the API errors check
+	APIErrorThreshold int `json:"apiErrorThreshold,omitempty"`
+
+	// AIQueueThreshold is the number of evaluations waiting for AI
+	// analysis that fails the AI queue check
+	AIQueueThreshold int `json:"aiQueueThreshold,omitempty"`
+
+	// MaxRemediationsPerHour bounds the corrective actions taken
+	MaxRemediationsPerHour int `json:"maxRemediationsPerHour,omitempty"`
+
+	// SafeModeAfter is the number of consecutive failures of a check
+	// after which safe mode is entered
+	SafeModeAfter int `json:"safeModeAfter,omitempty"`
+
+	// RecoveryPeriod all checks must pass before safe mode is left
+	RecoveryPeriod time.Duration `json:"recoveryPeriod,omitempty"`
+}
+
+// PolicyTestingConfig configures the debug endpoint that evaluates a policy
+// against a POSTed metrics snapshot. Requests must carry the bearer token
+// stored in the configured Secret.
+type PolicyTestingConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// BindAddress of the endpoint
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// TokenSecretName, TokenSecretNamespace and TokenSecretKey locate the
+	// bearer token requests must present
+	TokenSecretName      string `json:"tokenSecretName,omitempty"`
+	TokenSecretNamespace string `json:"tokenSecretNamespace,omitempty"`
+	TokenSecretKey       string `json:"tokenSecretKey,omitempty"`
+}
+
+// ProfilingConfig configures the debug endpoints used for performance
+// investigations: /debug/collectorstats and, with EnablePprof, the Go
+// pprof handlers. Requests must carry the bearer token stored in the
+// configured Secret.
+type ProfilingConfig struct {
+	// Enabled flag
+	Enabled bool `json:"enabled,omitempty"`
+
+	// EnablePprof serves the pprof handlers under /debug/pprof/
+	EnablePprof bool `json:"enablePprof,omitempty"`
+
+	// BindAddress of the endpoints
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// TokenSecretName, TokenSecretNamespace and TokenSecretKey locate the
+	// bearer token requests must present
+	TokenSecretName      string `json:"tokenSecretName,omitempty"`
+	TokenSecretNamespace string `json:"tokenSecretNamespace,omitempty"`
+	TokenSecretKey       string `json:"tokenSecretKey,omitempty"`
+}
+
+// PluginsConfig configures the Go plugins providing custom trigger
+// evaluators and pattern detectors, referenced from policies through
+// plugin triggers
+type PluginsConfig struct {
+	// Directory the .so plugin files are loaded from at startup. Needs the
+	// plugins image, as the default image is built without cgo.
+	Directory string `json:"directory,omitempty"`
+}
+
+// TelemetryConfig configures the anonymous usage reports sent to the
+// maintainers. Reports are off unless enabled, and hold only aggregated
+// counters: see docs/telemetry.md for the schema. Setting the
+// KUBESKIPPY_TELEMETRY environment variable to "off", or DO_NOT_TRACK to
+// "1", disables them whatever the configuration.
+type TelemetryConfig struct {
+	// Enabled opts in to sending reports
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Endpoint reports are posted to
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Interval between reports, each covering the actions completed in
+	// the interval before it
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Timeout of each report request
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// FaultInjectionConfig injects executor failures, delayed or failed AI
+// responses and API errors at configurable rates, so that the circuit
+// breaker, retries and rollbacks can be exercised. It is only honored by
+// binaries built with the faultinjection build tag (make
+// docker-build-faults).
+type FaultInjectionConfig struct {
+	// Enabled injects the configured faults
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Seed makes the injected faults reproducible; 0 seeds from the clock
+	Seed int64 `json:"seed,omitempty"`
+
+	// ExecutorFailureRate is the fraction of action executions that fail
+	ExecutorFailureRate float64 `json:"executorFailureRate,omitempty"`
+
+	// ExecutorActionTypes limits executor failures to these action types;
+	// empty fails every type
+	ExecutorActionTypes []string `json:"executorActionTypes,omitempty"`
+
+	// AIDelay is added to the AI responses selected by AIDelayRate
+	AIDelay time.Duration `json:"aiDelay,omitempty"`
+
+	// AIDelayRate is the fraction of AI queries delayed by AIDelay
+	AIDelayRate float64 `json:"aiDelayRate,omitempty"`
+
+	// AIErrorRate is the fraction of AI queries that fail
+	AIErrorRate float64 `json:"aiErrorRate,omitempty"`
+
+	// APIErrorRate is the fraction of writes by the remediation engine
+	// that fail with a service unavailable error
+	APIErrorRate float64 `json:"apiErrorRate,omitempty"`
+}
+
 // LoggingConfig configures logging
 type LoggingConfig struct {
 	// Level (debug, info, warn, error)
@@ -218,11 +1025,33 @@ func NewDefaultConfig() *Config {
 		ProbeAddr:            ":8081",
 		EnableLeaderElection: true,
 		WatchNamespace:       "",
+		Serving: ServingConfig{
+			CertName: "tls.crt",
+			KeyName:  "tls.key",
+		},
 		Metrics: MetricsConfig{
 			PrometheusURL:        "http://prometheus.monitoring:9090",
 			MetricsServerEnabled: true,
+			QueryCacheTTL:        15 * time.Second,
 			CollectionInterval:   30 * time.Second,
 			RetentionPeriod:      24 * time.Hour,
+			PushReceiver: PushReceiverConfig{
+				StatsDAddr: ":8125",
+				OTLPAddr:   ":4318",
+				TTL:        5 * time.Minute,
+			},
+			AlertRules: AlertRulesConfig{
+				Severity: "warning",
+			},
+			TriggerHistorySize:      10,
+			TriggerValueSeriesLimit: 500,
+			StateMetrics: StateMetricsConfig{
+				Enabled: true,
+			},
+			CapacitySignals: CapacitySignalsConfig{
+				Enabled:                 true,
+				ClusterAutoscalerStatus: "kube-system/cluster-autoscaler-status",
+			},
 		},
 		AI: AIConfig{
 			Provider:          "ollama",
@@ -234,6 +1063,18 @@ func NewDefaultConfig() *Config {
 			SystemPrompt:      DefaultSystemPrompt,
 			MinConfidence:     0.7,
 			ValidateResponses: true,
+			ValidationMode:    "batch",
+			DecisionMode:      "filter",
+			BlendWeights:      AIBlendWeights{Rule: 0.5, AI: 0.5},
+			DecisionTTL:       7 * 24 * time.Hour,
+			ProgressInterval:  5 * time.Second,
+			GRPC: GRPCConfig{
+				MaxConnections: 4,
+				InputTensor:    "text_input",
+				OutputTensor:   "text_output",
+			},
+			Vertex:      VertexConfig{Location: "us-central1"},
+			AzureOpenAI: AzureOpenAIConfig{APIVersion: "2024-06-01"},
 		},
 		Safety: SafetyConfig{
 			DryRunMode:        false,
@@ -254,6 +1095,30 @@ func NewDefaultConfig() *Config {
 				SuccessThreshold:   2,
 				Timeout:            5 * time.Minute,
 				HalfOpenMaxActions: 1,
+				KeyBy:              CircuitBreakerKeyPolicy,
+			},
+			ZoneSpread: ZoneSpreadConfig{
+				Enabled:            false,
+				TopologyKey:        "topology.kubernetes.io/zone",
+				MinReplicasPerZone: 1,
+			},
+			TargetCooldown: 5 * time.Minute,
+			SettlingWindow: 5 * time.Minute,
+			FailureCooloff: 30 * time.Minute,
+			// Cooldown groups only apply to policies labeled with a group
+			CooldownGroupWindow: 10 * time.Minute,
+			Calendar: CalendarConfig{
+				TimeZone:           "UTC",
+				BusinessDays:       []string{"Mon", "Tue", "Wed", "Thu", "Fri"},
+				BusinessHoursStart: "09:00",
+				BusinessHoursEnd:   "17:00",
+			},
+			ActionStore: ActionStoreConfig{
+				Backend: ActionStoreMemory,
+				Redis: RedisStoreConfig{
+					KeyPrefix:   "kubeskippy:",
+					DialTimeout: 5 * time.Second,
+				},
 			},
 			AuditLog: AuditLogConfig{
 				Enabled:        true,
@@ -262,14 +1127,38 @@ func NewDefaultConfig() *Config {
 				MaxBackups:     10,
 				MaxAge:         30,
 				IncludeMetrics: false,
+				Signing: AuditSigningConfig{
+					SecretName:      "kubeskippy-audit-signing",
+					SecretNamespace: "kubeskippy-system",
+					SecretKey:       "cosign.key",
+				},
 			},
 		},
 		Remediation: RemediationConfig{
-			DefaultTimeout:  5 * time.Minute,
-			MaxRetries:      3,
-			RetryBackoff:    30 * time.Second,
-			EnableRollback:  true,
-			ParallelActions: 5,
+			DefaultTimeout:          5 * time.Minute,
+			MaxRetries:              3,
+			RetryBackoff:            30 * time.Second,
+			AttemptTimeout:          5 * time.Minute,
+			EnableRollback:          true,
+			ParallelActions:         5,
+			MaxActionsPerEvaluation: 5,
+			CreateBatchSize:         10,
+			CreateQPS:               5,
+			CreateBurst:             10,
+			RBACPreflight:           true,
+			PreemptionPriority:      100,
+			ChangeCauseAnnotations:  true,
+			DryRunObservationWindow: 30 * time.Minute,
+			Capacity: CapacityConfig{
+				Enabled:                 true,
+				ClusterAutoscalerStatus: "kube-system/cluster-autoscaler-status",
+				RecommendNodeScaling:    true,
+			},
+			Janitor: JanitorConfig{
+				Enabled:     true,
+				Interval:    10 * time.Minute,
+				GracePeriod: time.Hour,
+			},
 			ActionDefaults: map[string]ActionConfig{
 				"restart": {
 					Enabled:         true,
@@ -297,6 +1186,15 @@ func NewDefaultConfig() *Config {
 				},
 			},
 		},
+		IssueTracker: IssueTrackerConfig{
+			Provider:             "webhook",
+			TokenSecretNamespace: "kubeskippy-system",
+			TokenSecretKey:       "token",
+			NotifyOnSuccess:      true,
+			NotifyOnFailure:      true,
+			NotifyOnEscalation:   true,
+			Timeout:              10 * time.Second,
+		},
 		Logging: LoggingConfig{
 			Level:             "info",
 			Format:            "json",
@@ -306,6 +1204,46 @@ func NewDefaultConfig() *Config {
 			Encoding:          "json",
 			OutputPaths:       []string{"stdout"},
 		},
+		Events: EventsConfig{
+			AggregationWindow: 10 * time.Minute,
+			EmitEvery:         10,
+			TriggerHeartbeat:  30 * time.Minute,
+		},
+		Archive: ArchiveConfig{
+			Provider:             "file",
+			TokenSecretNamespace: "kubeskippy-system",
+			TokenSecretKey:       "token",
+			Format:               "jsonl",
+			CheckpointConfigMap:  "kubeskippy-archive-checkpoint",
+			Prefix:               "kubeskippy",
+			Interval:             time.Hour,
+			Timeout:              30 * time.Second,
+		},
+		Watchdog: WatchdogConfig{
+			Interval:               30 * time.Second,
+			Namespace:              "kubeskippy-system",
+			Name:                   "kubeskippy",
+			StallThreshold:         10 * time.Minute,
+			APIErrorThreshold:      5,
+			AIQueueThreshold:       20,
+			MaxRemediationsPerHour: 3,
+			SafeModeAfter:          10,
+			RecoveryPeriod:         10 * time.Minute,
+		},
+		PolicyTesting: PolicyTestingConfig{
+			BindAddress:          ":8090",
+			TokenSecretNamespace: "kubeskippy-system",
+			TokenSecretKey:       "token",
+		},
+		Profiling: ProfilingConfig{
+			BindAddress:          ":8091",
+			TokenSecretNamespace: "kubeskippy-system",
+			TokenSecretKey:       "token",
+		},
+		Telemetry: TelemetryConfig{
+			Interval: 24 * time.Hour,
+			Timeout:  10 * time.Second,
+		},
 	}
 }
 
@@ -335,6 +1273,46 @@ Output format:
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	// Add validation logic here
+	if (c.Serving.ProbeTLS || c.Serving.DebugTLS) && c.Serving.CertDir == "" {
+		return fmt.Errorf("serving.certDir is required to serve the probes or debug endpoints over TLS")
+	}
+	for name, rate := range map[string]float64{
+		"executorFailureRate": c.FaultInjection.ExecutorFailureRate,
+		"aiDelayRate":         c.FaultInjection.AIDelayRate,
+		"aiErrorRate":         c.FaultInjection.AIErrorRate,
+		"apiErrorRate":        c.FaultInjection.APIErrorRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("faultInjection.%s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+	switch c.AI.Provider {
+	case "vertex":
+		if c.AI.Vertex.Project == "" || c.AI.Vertex.Location == "" {
+			return fmt.Errorf("ai.vertex.project and location are required by the vertex provider")
+		}
+	case "azure-openai":
+		if !strings.HasPrefix(c.AI.Endpoint, "https://") {
+			return fmt.Errorf("ai.endpoint must be the https URL of the Azure OpenAI resource, got %q", c.AI.Endpoint)
+		}
+	}
+	if c.Telemetry.Enabled && !strings.HasPrefix(c.Telemetry.Endpoint, "https://") {
+		return fmt.Errorf("telemetry.endpoint must be an https URL, got %q", c.Telemetry.Endpoint)
+	}
+	switch keyBy := c.Safety.CircuitBreaker.KeyBy; keyBy {
+	case "", CircuitBreakerKeyPolicy, CircuitBreakerKeyPolicyNamespace, CircuitBreakerKeyPolicyTarget:
+	default:
+		return fmt.Errorf("safety.circuitBreaker.keyBy must be %q, %q or %q, got %q",
+			CircuitBreakerKeyPolicy, CircuitBreakerKeyPolicyNamespace, CircuitBreakerKeyPolicyTarget, keyBy)
+	}
+	switch store := c.Safety.ActionStore; store.Backend {
+	case "", ActionStoreMemory:
+	case ActionStoreRedis:
+		if store.Redis.SecretName == "" || store.Redis.SecretNamespace == "" {
+			return fmt.Errorf("safety.actionStore.redis.secretName and secretNamespace are required by the redis backend")
+		}
+	default:
+		return fmt.Errorf("safety.actionStore.backend must be %q or %q, got %q", ActionStoreMemory, ActionStoreRedis, store.Backend)
+	}
 	return nil
 }
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_CircuitBreakerKeyBy(t *testing.T) {
+	for _, keyBy := range []string{"", CircuitBreakerKeyPolicy, CircuitBreakerKeyPolicyNamespace, CircuitBreakerKeyPolicyTarget} {
+		cfg := NewDefaultConfig()
+		cfg.Safety.CircuitBreaker.KeyBy = keyBy
+		assert.NoError(t, cfg.Validate(), keyBy)
+	}
+
+	cfg := NewDefaultConfig()
+	cfg.Safety.CircuitBreaker.KeyBy = "target"
+	assert.ErrorContains(t, cfg.Validate(), "safety.circuitBreaker.keyBy")
+}
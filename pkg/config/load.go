@@ -0,0 +1,142 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LoadFile reads the configuration from a YAML or JSON file. Fields the file
+// leaves out keep their defaults, and durations may be written as strings
+// such as "5m". Unknown fields are rejected, so a misspelled setting does
+// not silently keep its default.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	cfg := NewDefaultConfig()
+	if err := decode(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// decode sets the fields of cfg present in a YAML or JSON document
+func decode(data []byte, cfg *Config) error {
+	raw, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	if doc == nil {
+		return nil
+	}
+	if doc, err = parseDurations("", doc, reflect.TypeOf(cfg).Elem()); err != nil {
+		return err
+	}
+	if raw, err = json.Marshal(doc); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(cfg)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseDurations replaces the duration strings of a decoded document with
+// nanoseconds, following the fields of t
+func parseDurations(path string, value interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case string:
+		if t != durationType {
+			return v, nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a duration, got %q", path, v)
+		}
+		return int64(d), nil
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, item := range v {
+				field, ok := fields[key]
+				if !ok {
+					continue
+				}
+				parsed, err := parseDurations(joinPath(path, key), item, field)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = parsed
+			}
+		case reflect.Map:
+			for key, item := range v {
+				parsed, err := parseDurations(joinPath(path, key), item, t.Elem())
+				if err != nil {
+					return nil, err
+				}
+				v[key] = parsed
+			}
+		}
+		return v, nil
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			return v, nil
+		}
+		for i, item := range v {
+			parsed, err := parseDurations(fmt.Sprintf("%s[%d]", path, i), item, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			v[i] = parsed
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// jsonFields returns the types of the fields of a struct by JSON name
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
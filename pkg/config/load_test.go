@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+enableLeaderElection: true
+safety:
+  maxActionsPerHour: 5
+  circuitBreaker:
+    keyBy: policy+target
+remediation:
+  attemptTimeout: 90s
+`), 0o600))
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.EnableLeaderElection)
+	assert.Equal(t, 5, cfg.Safety.MaxActionsPerHour)
+	assert.Equal(t, CircuitBreakerKeyPolicyTarget, cfg.Safety.CircuitBreaker.KeyBy)
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, 90*time.Second, cfg.Remediation.AttemptTimeout)
+
+	// Fields the file leaves out keep their defaults
+	defaults := NewDefaultConfig()
+	assert.Equal(t, defaults.MetricsAddr, cfg.MetricsAddr)
+	assert.Equal(t, defaults.Safety.ProtectedNamespaces, cfg.Safety.ProtectedNamespaces)
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown field":    "safety:\n  maxActionPerHour: 5\n",
+		"invalid duration": "remediation:\n  attemptTimeout: soon\n",
+		"invalid yaml":     "safety: [",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			_, err := LoadFile(path)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
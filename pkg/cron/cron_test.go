@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 5m",
+	}
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// Friday 2026-10-16 10:17:30 UTC
+	from := time.Date(2026, 10, 16, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 16, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)},
+		{"0 18 * * sat,sun", time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"30 2 1 jan *", time.Date(2027, 1, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		// Day of month or day of week when both are restricted
+		{"0 0 1 * mon", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2026, 10, 16, 10, 25, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestSchedule_Prev(t *testing.T) {
+	from := time.Date(2026, 10, 16, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 16, 10, 17, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)},
+		{"0 18 * * sat,sun", time.Date(2026, 10, 11, 18, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 31 * *", time.Date(2026, 8, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Prev(from))
+		})
+	}
+}
+
+func TestSchedule_NeverFires(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+	assert.True(t, schedule.Prev(time.Now()).IsZero())
+}
+
+func TestSchedule_TimeZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	schedule, err := Parse("0 3 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC).In(berlin))
+	assert.Equal(t, time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC), next.UTC())
+}
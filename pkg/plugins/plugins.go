@@ -0,0 +1,245 @@
+// Package plugins lets users ship custom trigger evaluators and pattern
+// detectors without changing the operator. Extensions implement the
+// TriggerEvaluator and PatternDetector interfaces of pkg/types, are
+// registered by name and referenced from policies through plugin triggers.
+// Detectors over the operator's time series implement the Detector
+// interface and run on every analysis once enabled in the configuration.
+//
+// Extensions are either registered in-process by programs embedding the
+// operator, or built as Go plugins (go build -buildmode=plugin) that export
+//
+//	func Register(r *plugins.Registry) error
+//
+// and loaded from a directory at startup. Go plugins must be built with the
+// same Go version and module versions as the operator, and loading them
+// requires an operator built with cgo: the default static image cannot load
+// plugins, the image built by make docker-build-plugins can.
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// RegisterSymbol is the function a Go plugin must export
+const RegisterSymbol = "Register"
+
+// RegisterFunc registers a plugin's extensions
+type RegisterFunc func(r *Registry) error
+
+// Registry holds trigger evaluators and pattern detectors by name
+type Registry struct {
+	mu              sync.RWMutex
+	evaluators      map[string]types.TriggerEvaluator
+	detectors       map[string]types.PatternDetector
+	seriesDetectors map[string]types.Detector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		evaluators:      make(map[string]types.TriggerEvaluator),
+		detectors:       make(map[string]types.PatternDetector),
+		seriesDetectors: make(map[string]types.Detector),
+	}
+}
+
+// RegisterTriggerEvaluator registers a trigger evaluator under name
+func (r *Registry) RegisterTriggerEvaluator(name string, evaluator types.TriggerEvaluator) error {
+	if name == "" || evaluator == nil {
+		return fmt.Errorf("trigger evaluator needs a name and an implementation")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.evaluators[name]; exists {
+		return fmt.Errorf("trigger evaluator %q is already registered", name)
+	}
+	r.evaluators[name] = evaluator
+	return nil
+}
+
+// RegisterPatternDetector registers a pattern detector under name
+func (r *Registry) RegisterPatternDetector(name string, detector types.PatternDetector) error {
+	if name == "" || detector == nil {
+		return fmt.Errorf("pattern detector needs a name and an implementation")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.detectors[name]; exists {
+		return fmt.Errorf("pattern detector %q is already registered", name)
+	}
+	r.detectors[name] = detector
+	return nil
+}
+
+// RegisterDetector registers a time series detector under name
+func (r *Registry) RegisterDetector(name string, detector types.Detector) error {
+	if name == "" || detector == nil {
+		return fmt.Errorf("detector needs a name and an implementation")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.seriesDetectors[name]; exists {
+		return fmt.Errorf("detector %q is already registered", name)
+	}
+	r.seriesDetectors[name] = detector
+	return nil
+}
+
+// TriggerEvaluator returns the trigger evaluator registered under name
+func (r *Registry) TriggerEvaluator(name string) (types.TriggerEvaluator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	evaluator, ok := r.evaluators[name]
+	return evaluator, ok
+}
+
+// PatternDetector returns the pattern detector registered under name
+func (r *Registry) PatternDetector(name string) (types.PatternDetector, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	detector, ok := r.detectors[name]
+	return detector, ok
+}
+
+// Detector returns the time series detector registered under name
+func (r *Registry) Detector(name string) (types.Detector, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	detector, ok := r.seriesDetectors[name]
+	return detector, ok
+}
+
+// DetectorNames returns the sorted names of the registered time series
+// detectors
+func (r *Registry) DetectorNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.seriesDetectors))
+	for name := range r.seriesDetectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Names returns the sorted names of the registered evaluators and detectors
+func (r *Registry) Names() (evaluators, detectors []string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name := range r.evaluators {
+		evaluators = append(evaluators, name)
+	}
+	for name := range r.detectors {
+		detectors = append(detectors, name)
+	}
+	sort.Strings(evaluators)
+	sort.Strings(detectors)
+	return evaluators, detectors
+}
+
+// Evaluate runs the evaluator or detector a plugin trigger references. A
+// detector fires when it finds any pattern.
+func (r *Registry) Evaluate(ctx context.Context, trigger *v1alpha1.PluginTrigger, metrics *types.ClusterMetrics) (bool, string, error) {
+	if metrics == nil {
+		metrics = &types.ClusterMetrics{}
+	}
+
+	if trigger.Evaluator != "" {
+		evaluator, ok := r.TriggerEvaluator(trigger.Evaluator)
+		if !ok {
+			return false, "", fmt.Errorf("trigger evaluator %q is not registered", trigger.Evaluator)
+		}
+		triggered, reason, err := evaluator.Evaluate(ctx, trigger.Config, metrics)
+		if err != nil {
+			return false, "", fmt.Errorf("trigger evaluator %q failed: %w", trigger.Evaluator, err)
+		}
+		return triggered, reason, nil
+	}
+
+	if trigger.Detector == "" {
+		return false, "", fmt.Errorf("plugin trigger references no evaluator or detector")
+	}
+	detector, ok := r.PatternDetector(trigger.Detector)
+	if !ok {
+		return false, "", fmt.Errorf("pattern detector %q is not registered", trigger.Detector)
+	}
+	patterns, err := detector.Detect(ctx, trigger.Config, metrics)
+	if err != nil {
+		return false, "", fmt.Errorf("pattern detector %q failed: %w", trigger.Detector, err)
+	}
+	if len(patterns) == 0 {
+		return false, "", nil
+	}
+
+	descriptions := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		description := pattern.Description
+		if pattern.Target != "" {
+			description = fmt.Sprintf("%s on %s", description, pattern.Target)
+		}
+		descriptions = append(descriptions, description)
+	}
+	return true, fmt.Sprintf("%s detected %d patterns: %s",
+		trigger.Detector, len(patterns), strings.Join(descriptions, "; ")), nil
+}
+
+// LoadDirectory opens every .so file in dir as a Go plugin and calls its
+// Register function with the registry. It returns the loaded files.
+func (r *Registry) LoadDirectory(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+	}
+
+	var loaded []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if !GoPluginsSupported {
+			return nil, fmt.Errorf("cannot load plugin %s: the operator was built without cgo, use the plugins image", path)
+		}
+		if err := r.Load(path); err != nil {
+			return loaded, err
+		}
+		loaded = append(loaded, path)
+	}
+	return loaded, nil
+}
+
+// Load opens a Go plugin and calls its Register function with the registry
+func (r *Registry) Load(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+	symbol, err := p.Lookup(RegisterSymbol)
+	if err != nil {
+		return fmt.Errorf("plugin %s does not export %s: %w", path, RegisterSymbol, err)
+	}
+
+	var register RegisterFunc
+	switch fn := symbol.(type) {
+	case func(*Registry) error:
+		register = fn
+	case *RegisterFunc:
+		register = *fn
+	default:
+		return fmt.Errorf("plugin %s: %s has type %T, want func(*plugins.Registry) error", path, RegisterSymbol, symbol)
+	}
+	if err := register(r); err != nil {
+		return fmt.Errorf("plugin %s failed to register: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// podCountEvaluator fires when there are more pods than config["max"]
+type podCountEvaluator struct{}
+
+func (podCountEvaluator) Evaluate(ctx context.Context, config map[string]string, metrics *types.ClusterMetrics) (bool, string, error) {
+	max, err := strconv.Atoi(config["max"])
+	if err != nil {
+		return false, "", err
+	}
+	return len(metrics.Pods) > max, strconv.Itoa(len(metrics.Pods)) + " pods", nil
+}
+
+// detectorFunc adapts a function to a pattern detector
+type detectorFunc func(ctx context.Context, config map[string]string, metrics *types.ClusterMetrics) ([]types.Pattern, error)
+
+func (f detectorFunc) Detect(ctx context.Context, config map[string]string, metrics *types.ClusterMetrics) ([]types.Pattern, error) {
+	return f(ctx, config, metrics)
+}
+
+// seriesDetectorFunc adapts a function to a time series detector
+type seriesDetectorFunc func(ctx context.Context, series types.SeriesStore) ([]types.Pattern, error)
+
+func (f seriesDetectorFunc) Detect(ctx context.Context, series types.SeriesStore) ([]types.Pattern, error) {
+	return f(ctx, series)
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterTriggerEvaluator("pod-count", podCountEvaluator{}))
+	assert.Error(t, registry.RegisterTriggerEvaluator("pod-count", podCountEvaluator{}))
+	assert.Error(t, registry.RegisterTriggerEvaluator("", podCountEvaluator{}))
+	assert.Error(t, registry.RegisterPatternDetector("gc-thrash", nil))
+	require.NoError(t, registry.RegisterPatternDetector("gc-thrash", detectorFunc(nil)))
+
+	_, ok := registry.TriggerEvaluator("pod-count")
+	assert.True(t, ok)
+	_, ok = registry.PatternDetector("pod-count")
+	assert.False(t, ok)
+
+	evaluators, detectors := registry.Names()
+	assert.Equal(t, []string{"pod-count"}, evaluators)
+	assert.Equal(t, []string{"gc-thrash"}, detectors)
+
+	assert.Error(t, registry.RegisterDetector("leaks", nil))
+	require.NoError(t, registry.RegisterDetector("leaks", seriesDetectorFunc(nil)))
+	require.NoError(t, registry.RegisterDetector("flapping", seriesDetectorFunc(nil)))
+	assert.Error(t, registry.RegisterDetector("leaks", seriesDetectorFunc(nil)))
+	_, ok = registry.Detector("leaks")
+	assert.True(t, ok)
+	_, ok = registry.Detector("gc-thrash")
+	assert.False(t, ok, "pattern detectors and time series detectors are separate")
+	assert.Equal(t, []string{"flapping", "leaks"}, registry.DetectorNames())
+}
+
+func TestRegistry_Evaluate(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterTriggerEvaluator("pod-count", podCountEvaluator{}))
+	require.NoError(t, registry.RegisterPatternDetector("restarts", detectorFunc(
+		func(ctx context.Context, config map[string]string, metrics *types.ClusterMetrics) ([]types.Pattern, error) {
+			var patterns []types.Pattern
+			for _, pod := range metrics.Pods {
+				if pod.RestartCount > 0 {
+					patterns = append(patterns, types.Pattern{Name: "restarting", Target: pod.Name, Description: "restarting pod"})
+				}
+			}
+			return patterns, nil
+		})))
+	require.NoError(t, registry.RegisterPatternDetector("broken", detectorFunc(
+		func(ctx context.Context, config map[string]string, metrics *types.ClusterMetrics) ([]types.Pattern, error) {
+			return nil, errors.New("no history")
+		})))
+
+	metrics := &types.ClusterMetrics{Pods: []types.PodMetrics{{Name: "web-1", RestartCount: 3}, {Name: "web-2"}}}
+
+	tests := []struct {
+		name          string
+		trigger       v1alpha1.PluginTrigger
+		wantTriggered bool
+		wantReason    string
+		wantErr       string
+	}{
+		{
+			name:          "evaluator fires",
+			trigger:       v1alpha1.PluginTrigger{Evaluator: "pod-count", Config: map[string]string{"max": "1"}},
+			wantTriggered: true,
+			wantReason:    "2 pods",
+		},
+		{
+			name:       "evaluator quiet",
+			trigger:    v1alpha1.PluginTrigger{Evaluator: "pod-count", Config: map[string]string{"max": "5"}},
+			wantReason: "2 pods",
+		},
+		{
+			name:    "evaluator fails",
+			trigger: v1alpha1.PluginTrigger{Evaluator: "pod-count"},
+			wantErr: `trigger evaluator "pod-count" failed`,
+		},
+		{
+			name:    "unknown evaluator",
+			trigger: v1alpha1.PluginTrigger{Evaluator: "queue-depth"},
+			wantErr: `trigger evaluator "queue-depth" is not registered`,
+		},
+		{
+			name:          "detector finds patterns",
+			trigger:       v1alpha1.PluginTrigger{Detector: "restarts"},
+			wantTriggered: true,
+			wantReason:    "restarts detected 1 patterns: restarting pod on web-1",
+		},
+		{
+			name:    "detector fails",
+			trigger: v1alpha1.PluginTrigger{Detector: "broken"},
+			wantErr: `pattern detector "broken" failed: no history`,
+		},
+		{
+			name:    "nothing referenced",
+			wantErr: "plugin trigger references no evaluator or detector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triggered, reason, err := registry.Evaluate(context.Background(), &tt.trigger, metrics)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTriggered, triggered)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestRegistry_LoadDirectory(t *testing.T) {
+	registry := NewRegistry()
+
+	_, err := registry.LoadDirectory(t.TempDir() + "/missing")
+	assert.Error(t, err)
+
+	loaded, err := registry.LoadDirectory(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
+
+func TestRegistry_LoadDirectoryPluginFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0o600))
+
+	// Directories without plugins load in every build
+	loaded, err := NewRegistry().LoadDirectory(dir)
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.so"), []byte("not an ELF file"), 0o600))
+	_, err = NewRegistry().LoadDirectory(dir)
+	require.Error(t, err)
+	if !GoPluginsSupported {
+		assert.Contains(t, err.Error(), "built without cgo")
+	} else {
+		assert.Contains(t, err.Error(), "failed to open plugin")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package plugins
+
+// GoPluginsSupported reports whether this binary can load Go plugins, which
+// requires cgo
+const GoPluginsSupported = true
@@ -0,0 +1,8 @@
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package plugins
+
+// GoPluginsSupported reports whether this binary can load Go plugins, which
+// requires cgo. The default image is built with CGO_ENABLED=0; build the
+// plugins image (make docker-build-plugins) to load plugins.
+const GoPluginsSupported = false
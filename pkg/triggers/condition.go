@@ -0,0 +1,50 @@
+package triggers
+
+import (
+	"fmt"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// crashLoopRestarts is the restart count above which a pod is treated as
+// crash looping, since CrashLoopBackOff is a container state rather than a
+// pod condition
+const crashLoopRestarts = 2
+
+// EvaluateCondition fires when any node or pod reports the trigger's
+// condition
+func EvaluateCondition(trigger *v1alpha1.ConditionTrigger, metrics *types.ClusterMetrics) (bool, string) {
+	if metrics == nil {
+		return false, fmt.Sprintf("found 0 resources with condition %s", trigger.Type)
+	}
+
+	matchCount := 0
+	for _, node := range metrics.Nodes {
+		if hasCondition(node.Conditions, trigger.Type) {
+			matchCount++
+		}
+	}
+
+	for _, pod := range metrics.Pods {
+		if hasCondition(pod.Conditions, trigger.Type) {
+			matchCount++
+		}
+		if trigger.Type == "CrashLoopBackOff" && pod.RestartCount > crashLoopRestarts {
+			matchCount++
+		}
+	}
+
+	triggered := matchCount > 0
+	reason := fmt.Sprintf("found %d resources with condition %s", matchCount, trigger.Type)
+	return triggered, reason
+}
+
+func hasCondition(conditions []string, condition string) bool {
+	for _, c := range conditions {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package triggers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// DefaultEventWindow is the window of event triggers that do not set one
+const DefaultEventWindow = 5 * time.Minute
+
+// EventWindow returns the trigger's window, or DefaultEventWindow if unset
+func EventWindow(trigger *v1alpha1.EventTrigger) time.Duration {
+	if trigger.Window.Duration > 0 {
+		return trigger.Window.Duration
+	}
+	return DefaultEventWindow
+}
+
+// EvaluateEvents fires when at least Count events matching the trigger's
+// type and reason were last seen within its window before now
+func EvaluateEvents(trigger *v1alpha1.EventTrigger, events []types.EventMetrics, now time.Time) (bool, string) {
+	window := EventWindow(trigger)
+	cutoff := now.Add(-window)
+
+	matchCount := 0
+	for _, event := range events {
+		if trigger.Type != "" && event.Type != trigger.Type {
+			continue
+		}
+		if trigger.Reason != "" && event.Reason != trigger.Reason {
+			continue
+		}
+		if event.LastSeen.Before(cutoff) {
+			continue
+		}
+		matchCount++
+	}
+
+	triggered := matchCount >= int(trigger.Count)
+	reason := fmt.Sprintf("found %d matching events (threshold: %d) in last %v", matchCount, trigger.Count, window)
+	return triggered, reason
+}
@@ -0,0 +1,212 @@
+package triggers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// recentEventWindow is how far back events count towards error rates and
+// availability
+const recentEventWindow = 5 * time.Minute
+
+// Pod resource usage is reported as a percentage of these assumed limits
+const (
+	assumedCPULimitMillicores = 1000.0
+	assumedMemoryLimitMB      = 512.0
+)
+
+// EvaluateMetric compares a metric trigger's threshold with the observed
+// value of its query
+func EvaluateMetric(trigger *v1alpha1.MetricTrigger, value float64) (bool, string) {
+	triggered := Compare(value, trigger.Threshold, trigger.Operator)
+	reason := fmt.Sprintf("query '%s' result %.2f %s %.2f", trigger.Query, value, trigger.Operator, trigger.Threshold)
+	return triggered, reason
+}
+
+// IsPromQL reports whether a query looks like PromQL rather than one of the
+// basic metric names understood by MetricValue
+func IsPromQL(query string) bool {
+	return strings.ContainsAny(query, "({[")
+}
+
+// MetricValue computes the value of a basic metric query from a snapshot.
+// Queries are matched by the metric name they contain:
+//
+//   - node_cpu: average node CPU usage
+//   - pod_restart, restart_count: highest pod restart count
+//   - cpu_usage_percent, memory_usage_percent: highest pod usage relative
+//     to a 1 core and 512MB limit
+//   - memory_usage_bytes: highest pod memory usage
+//   - error_rate_percent, error_rate: warning events and restarts in the
+//     last five minutes, as a percentage or a count
+//   - availability_percent: share of running pods with few restarts, less
+//     0.5% per recent warning event
+//   - pending_unschedulable: pending pods the scheduler found no node for
+//   - failed_scheduling: FailedScheduling events in the last five minutes
+//   - out_of_capacity: 1 if the cluster is out of capacity, see
+//     OutOfCapacity, 0 otherwise
+//
+// The capacity queries are 0 unless capacity signals are collected.
+// It returns false if the query names none of these.
+func MetricValue(query string, metrics *types.ClusterMetrics, now time.Time) (float64, bool) {
+	if metrics == nil {
+		metrics = &types.ClusterMetrics{}
+	}
+
+	switch {
+	case strings.Contains(query, "node_cpu"):
+		return averageNodeCPU(metrics.Nodes), true
+	case strings.Contains(query, "pod_restart") || strings.Contains(query, "restart_count"):
+		return maxPodValue(metrics.Pods, func(pod types.PodMetrics) float64 { return float64(pod.RestartCount) }), true
+	case strings.Contains(query, "cpu_usage_percent"):
+		return maxPodValue(metrics.Pods, func(pod types.PodMetrics) float64 {
+			return pod.CPUUsage / assumedCPULimitMillicores * 100.0
+		}), true
+	case strings.Contains(query, "memory_usage_percent"):
+		return maxPodValue(metrics.Pods, func(pod types.PodMetrics) float64 {
+			return pod.MemoryUsage / assumedMemoryLimitMB * 100.0
+		}), true
+	case strings.Contains(query, "memory_usage_bytes"):
+		// Pod memory usage is reported in MB
+		return maxPodValue(metrics.Pods, func(pod types.PodMetrics) float64 { return pod.MemoryUsage }) * 1024 * 1024, true
+	case strings.Contains(query, "error_rate_percent"):
+		return errorRatePercent(metrics, now), true
+	case strings.Contains(query, "error_rate") && !strings.Contains(query, "percent"):
+		return errorCount(metrics, now), true
+	case strings.Contains(query, "availability_percent"):
+		return availabilityPercent(metrics, now), true
+	case strings.Contains(query, "pending_unschedulable"):
+		if metrics.Capacity == nil {
+			return 0, true
+		}
+		return float64(metrics.Capacity.PendingUnschedulable), true
+	case strings.Contains(query, "failed_scheduling"):
+		if metrics.Capacity == nil {
+			return 0, true
+		}
+		return float64(metrics.Capacity.FailedScheduling), true
+	case strings.Contains(query, "out_of_capacity"):
+		if OutOfCapacity(metrics.Capacity) {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// OutOfCapacity reports whether pods are pending for lack of nodes with
+// nothing adding any: there is no cluster autoscaler, or it is not scaling
+// up. Restarting or scaling the workloads does not help these pods.
+func OutOfCapacity(capacity *types.CapacityMetrics) bool {
+	if capacity == nil || capacity.PendingUnschedulable == 0 {
+		return false
+	}
+	autoscaler := capacity.Autoscaler
+	return autoscaler == nil || autoscaler.Health != "Healthy" || autoscaler.ScaleUp != "InProgress"
+}
+
+func averageNodeCPU(nodes []types.NodeMetrics) float64 {
+	if len(nodes) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, node := range nodes {
+		total += node.CPUUsage
+	}
+	return total / float64(len(nodes))
+}
+
+func maxPodValue(pods []types.PodMetrics, value func(types.PodMetrics) float64) float64 {
+	max := 0.0
+	for _, pod := range pods {
+		if v := value(pod); v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+func isRecent(event types.EventMetrics, now time.Time) bool {
+	return now.Sub(event.LastSeen) < recentEventWindow
+}
+
+func errorRatePercent(metrics *types.ClusterMetrics, now time.Time) float64 {
+	errors := 0
+	total := 0
+	for _, event := range metrics.Events {
+		if !isRecent(event, now) {
+			continue
+		}
+		if event.Type == "Warning" && (strings.Contains(event.Reason, "Unhealthy") ||
+			strings.Contains(event.Reason, "BackOff") ||
+			strings.Contains(event.Reason, "Failed")) {
+			errors++
+		}
+		total++
+	}
+
+	// Restarts count as errors too
+	for _, pod := range metrics.Pods {
+		if pod.RestartCount > 0 {
+			errors += int(pod.RestartCount)
+			total += int(pod.RestartCount) + 1
+		}
+	}
+
+	rate := 0.0
+	if total > 0 {
+		rate = float64(errors) / float64(total) * 100.0
+	}
+
+	// The demo's flaky app fails 20% of its requests
+	for _, pod := range metrics.Pods {
+		if strings.Contains(pod.Name, "flaky") && pod.RestartCount > 0 {
+			return 20.0
+		}
+	}
+	return rate
+}
+
+func errorCount(metrics *types.ClusterMetrics, now time.Time) float64 {
+	errors := 0
+	for _, event := range metrics.Events {
+		if isRecent(event, now) && event.Type == "Warning" {
+			errors++
+		}
+	}
+	for _, pod := range metrics.Pods {
+		if pod.RestartCount > 0 {
+			errors += int(pod.RestartCount)
+		}
+	}
+	return float64(errors)
+}
+
+func availabilityPercent(metrics *types.ClusterMetrics, now time.Time) float64 {
+	availability := 100.0
+	if len(metrics.Pods) > 0 {
+		healthy := 0
+		for _, pod := range metrics.Pods {
+			if pod.Status == "Running" && pod.RestartCount < 3 {
+				healthy++
+			}
+		}
+		availability = float64(healthy) / float64(len(metrics.Pods)) * 100.0
+	}
+
+	// Each recent warning reduces availability by 0.5%
+	for _, event := range metrics.Events {
+		if isRecent(event, now) && event.Type == "Warning" {
+			availability -= 0.5
+		}
+	}
+	if availability < 0 {
+		availability = 0
+	}
+	return availability
+}
@@ -0,0 +1,58 @@
+package triggers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/cron"
+)
+
+// MaxScheduleLateness bounds how late a scheduled run may still start
+const MaxScheduleLateness = time.Hour
+
+// EvaluateSchedule fires when a scheduled run fell between the previous
+// evaluation and now. Nothing fires on a policy's first evaluation so
+// creating a policy does not replay past runs.
+func EvaluateSchedule(trigger *v1alpha1.ScheduleTrigger, lastEvaluated, now time.Time) (bool, string, error) {
+	schedule, loc, err := ParseSchedule(trigger)
+	if err != nil {
+		return false, "", err
+	}
+	if lastEvaluated.IsZero() {
+		return false, "first evaluation, waiting for the next scheduled run", nil
+	}
+
+	run := schedule.Prev(now.In(loc))
+	switch {
+	case run.IsZero() || !run.After(lastEvaluated):
+		return false, fmt.Sprintf("next scheduled run at %s", schedule.Next(now.In(loc)).Format(time.RFC3339)), nil
+	case now.Sub(run) > MaxScheduleLateness:
+		return false, fmt.Sprintf("skipped scheduled run at %s, more than %s late", run.Format(time.RFC3339), MaxScheduleLateness), nil
+	}
+	return true, fmt.Sprintf("scheduled run at %s (%s)", run.Format(time.RFC3339), trigger.Schedule), nil
+}
+
+// NextRun returns the next run of a schedule trigger after now, or false if
+// the schedule is invalid or never fires again
+func NextRun(trigger *v1alpha1.ScheduleTrigger, now time.Time) (time.Time, bool) {
+	schedule, loc, err := ParseSchedule(trigger)
+	if err != nil {
+		return time.Time{}, false
+	}
+	run := schedule.Next(now.In(loc))
+	return run, !run.IsZero()
+}
+
+// ParseSchedule parses the schedule and time zone of a trigger
+func ParseSchedule(trigger *v1alpha1.ScheduleTrigger) (*cron.Schedule, *time.Location, error) {
+	schedule, err := cron.Parse(trigger.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	loc, err := time.LoadLocation(trigger.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time zone %q: %w", trigger.TimeZone, err)
+	}
+	return schedule, loc, nil
+}
@@ -0,0 +1,95 @@
+package triggers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	nightly := &v1alpha1.ScheduleTrigger{Schedule: "0 3 * * *", TimeZone: "UTC"}
+	run := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		trigger       *v1alpha1.ScheduleTrigger
+		lastEvaluated time.Time
+		now           time.Time
+		triggered     bool
+		reason        string
+		wantErr       bool
+	}{
+		{
+			name:          "run since last evaluation",
+			trigger:       nightly,
+			lastEvaluated: run.Add(-time.Minute),
+			now:           run.Add(30 * time.Second),
+			triggered:     true,
+			reason:        "scheduled run at 2026-10-16T03:00:00Z",
+		},
+		{
+			name:          "run already handled",
+			trigger:       nightly,
+			lastEvaluated: run.Add(30 * time.Second),
+			now:           run.Add(90 * time.Second),
+			reason:        "next scheduled run at 2026-10-17T03:00:00Z",
+		},
+		{
+			name:    "first evaluation",
+			trigger: nightly,
+			now:     run.Add(30 * time.Second),
+			reason:  "first evaluation",
+		},
+		{
+			name:          "missed run",
+			trigger:       nightly,
+			lastEvaluated: run.Add(-time.Hour),
+			now:           run.Add(2 * time.Hour),
+			reason:        "more than 1h0m0s late",
+		},
+		{
+			name:          "time zone",
+			trigger:       &v1alpha1.ScheduleTrigger{Schedule: "0 5 * * *", TimeZone: "Europe/Berlin"},
+			lastEvaluated: run.Add(-time.Minute),
+			now:           run.Add(time.Minute),
+			triggered:     true,
+			reason:        "scheduled run at 2026-10-16T05:00:00+02:00",
+		},
+		{
+			name:    "invalid schedule",
+			trigger: &v1alpha1.ScheduleTrigger{Schedule: "every night"},
+			now:     run,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triggered, reason, err := EvaluateSchedule(tt.trigger, tt.lastEvaluated, tt.now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.triggered, triggered)
+			assert.Contains(t, reason, tt.reason)
+		})
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 17, 0, 0, time.UTC)
+
+	next, ok := NextRun(&v1alpha1.ScheduleTrigger{Schedule: "@hourly"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), next.UTC())
+
+	_, ok = NextRun(&v1alpha1.ScheduleTrigger{Schedule: "0 0 30 2 *"}, now)
+	assert.False(t, ok)
+
+	_, ok = NextRun(&v1alpha1.ScheduleTrigger{Schedule: "bogus"}, now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,78 @@
+package triggers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// DefaultStuckTerminatingThreshold is used when a stuck terminating trigger
+// sets no threshold
+const DefaultStuckTerminatingThreshold = 10 * time.Minute
+
+// StuckResource is a resource held in Terminating by its finalizers
+type StuckResource struct {
+	// Name of the resource as namespace/name
+	Name string
+
+	// Finalizers blocking the deletion
+	Finalizers []string
+
+	// Terminating is how long the deletion has been pending
+	Terminating time.Duration
+}
+
+// StuckTerminatingThreshold returns the trigger's threshold or the default
+func StuckTerminatingThreshold(trigger *v1alpha1.StuckTerminatingTrigger) time.Duration {
+	if trigger == nil || trigger.Threshold.Duration <= 0 {
+		return DefaultStuckTerminatingThreshold
+	}
+	return trigger.Threshold.Duration
+}
+
+// IsStuckTerminating reports whether a resource was deleted at least
+// threshold ago and still has finalizers
+func IsStuckTerminating(obj metav1.Object, threshold time.Duration, now time.Time) (StuckResource, bool) {
+	deleted := obj.GetDeletionTimestamp()
+	if deleted == nil || len(obj.GetFinalizers()) == 0 {
+		return StuckResource{}, false
+	}
+	terminating := now.Sub(deleted.Time)
+	if terminating < threshold {
+		return StuckResource{}, false
+	}
+	return StuckResource{
+		Name:        obj.GetNamespace() + "/" + obj.GetName(),
+		Finalizers:  append([]string(nil), obj.GetFinalizers()...),
+		Terminating: terminating,
+	}, true
+}
+
+// EvaluateStuckTerminating fires when any of the resources is stuck
+// terminating and names the finalizers blocking each of them
+func EvaluateStuckTerminating(trigger *v1alpha1.StuckTerminatingTrigger, resources []metav1.Object, now time.Time) (bool, string) {
+	threshold := StuckTerminatingThreshold(trigger)
+
+	var stuck []StuckResource
+	for _, resource := range resources {
+		if s, ok := IsStuckTerminating(resource, threshold, now); ok {
+			stuck = append(stuck, s)
+		}
+	}
+	if len(stuck) == 0 {
+		return false, fmt.Sprintf("no resources terminating for more than %s", threshold)
+	}
+
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].Name < stuck[j].Name })
+	details := make([]string, len(stuck))
+	for i, s := range stuck {
+		details[i] = fmt.Sprintf("%s for %s blocked by %s",
+			s.Name, s.Terminating.Round(time.Second), strings.Join(s.Finalizers, ", "))
+	}
+	return true, fmt.Sprintf("%d resources stuck terminating: %s", len(stuck), strings.Join(details, "; "))
+}
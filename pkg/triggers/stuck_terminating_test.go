@@ -0,0 +1,64 @@
+package triggers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+func TestEvaluateStuckTerminating(t *testing.T) {
+	resource := func(name string, deletedAgo time.Duration, finalizers ...string) metav1.Object {
+		obj := &metav1.ObjectMeta{Name: name, Namespace: "apps", Finalizers: finalizers}
+		if deletedAgo > 0 {
+			obj.DeletionTimestamp = &metav1.Time{Time: now.Add(-deletedAgo)}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name      string
+		trigger   *v1alpha1.StuckTerminatingTrigger
+		resources []metav1.Object
+		triggered bool
+		reason    string
+	}{
+		{
+			name:      "running resource",
+			resources: []metav1.Object{resource("web", 0, "example.com/cleanup")},
+			reason:    "no resources terminating for more than 10m0s",
+		},
+		{
+			name:      "terminating without finalizers",
+			resources: []metav1.Object{resource("web", time.Hour)},
+			reason:    "no resources terminating for more than 10m0s",
+		},
+		{
+			name:      "below threshold",
+			resources: []metav1.Object{resource("web", 5*time.Minute, "example.com/cleanup")},
+			reason:    "no resources terminating for more than 10m0s",
+		},
+		{
+			name:    "stuck resources name their finalizers",
+			trigger: &v1alpha1.StuckTerminatingTrigger{Threshold: metav1.Duration{Duration: 2 * time.Minute}},
+			resources: []metav1.Object{
+				resource("web", 5*time.Minute, "example.com/cleanup"),
+				resource("db", time.Hour, "example.com/backup", "example.com/cleanup"),
+			},
+			triggered: true,
+			reason: "2 resources stuck terminating: apps/db for 1h0m0s blocked by example.com/backup, example.com/cleanup; " +
+				"apps/web for 5m0s blocked by example.com/cleanup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triggered, reason := EvaluateStuckTerminating(tt.trigger, tt.resources, now)
+			assert.Equal(t, tt.triggered, triggered)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
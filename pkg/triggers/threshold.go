@@ -0,0 +1,25 @@
+package triggers
+
+// Operators are the comparison operators accepted by metric triggers
+var Operators = []string{">", ">=", "<", "<=", "==", "!="}
+
+// Compare compares a value against a threshold using operator. Unknown
+// operators never match.
+func Compare(value, threshold float64, operator string) bool {
+	switch operator {
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	case "==":
+		return value == threshold
+	case "!=":
+		return value != threshold
+	default:
+		return false
+	}
+}
@@ -0,0 +1,70 @@
+// Package triggers evaluates HealingPolicy triggers against a snapshot of
+// cluster state. It has no controller or client dependencies: callers
+// collect the metrics, and the package parses trigger configuration,
+// applies thresholds and windows and explains the result.
+//
+// Triggers that need live data beyond a snapshot, such as PromQL queries,
+// pod logs, restart storm history and plugins, are evaluated by the operator's
+// metrics collector; Evaluate reports them as unsupported.
+package triggers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+// ErrUnsupported is returned for triggers that cannot be evaluated from a
+// metrics snapshot alone
+var ErrUnsupported = errors.New("trigger type needs live data")
+
+// Evaluate checks a trigger against a metrics snapshot at now. lastEvaluated
+// is when the policy was last evaluated and is only used by schedule
+// triggers. It returns whether the trigger fired and why.
+func Evaluate(trigger *v1alpha1.HealingTrigger, metrics *types.ClusterMetrics, lastEvaluated, now time.Time) (bool, string, error) {
+	if metrics == nil {
+		metrics = &types.ClusterMetrics{}
+	}
+
+	switch trigger.Type {
+	case "metric":
+		if trigger.MetricTrigger == nil {
+			return false, "", fmt.Errorf("metric trigger configuration missing")
+		}
+		value, ok := MetricValue(trigger.MetricTrigger.Query, metrics, now)
+		if !ok {
+			return false, "metric evaluation not implemented for query: " + trigger.MetricTrigger.Query, nil
+		}
+		triggered, reason := EvaluateMetric(trigger.MetricTrigger, value)
+		return triggered, reason, nil
+
+	case "event":
+		if trigger.EventTrigger == nil {
+			return false, "", fmt.Errorf("event trigger configuration missing")
+		}
+		triggered, reason := EvaluateEvents(trigger.EventTrigger, metrics.Events, now)
+		return triggered, reason, nil
+
+	case "condition":
+		if trigger.ConditionTrigger == nil {
+			return false, "", fmt.Errorf("condition trigger configuration missing")
+		}
+		triggered, reason := EvaluateCondition(trigger.ConditionTrigger, metrics)
+		return triggered, reason, nil
+
+	case "schedule":
+		if trigger.ScheduleTrigger == nil {
+			return false, "", fmt.Errorf("schedule trigger configuration missing")
+		}
+		return EvaluateSchedule(trigger.ScheduleTrigger, lastEvaluated, now)
+
+	case "log", "restartStorm", "stuckTerminating", "plugin":
+		return false, "", fmt.Errorf("%s trigger: %w", trigger.Type, ErrUnsupported)
+
+	default:
+		return false, "", fmt.Errorf("unknown trigger type: %s", trigger.Type)
+	}
+}
@@ -0,0 +1,213 @@
+package triggers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/pkg/types"
+)
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func snapshot() *types.ClusterMetrics {
+	return &types.ClusterMetrics{
+		Timestamp: now,
+		Nodes: []types.NodeMetrics{
+			{Name: "node-a", CPUUsage: 40, Conditions: []string{"Ready"}},
+			{Name: "node-b", CPUUsage: 80, Conditions: []string{"MemoryPressure"}},
+		},
+		Pods: []types.PodMetrics{
+			{Name: "web-1", Status: "Running", CPUUsage: 500, MemoryUsage: 256},
+			{Name: "web-2", Status: "Running", RestartCount: 4, CPUUsage: 250, MemoryUsage: 128},
+			{Name: "worker", Status: "Pending", Conditions: []string{"PodScheduled"}},
+		},
+		Events: []types.EventMetrics{
+			{Type: "Warning", Reason: "BackOff", LastSeen: now.Add(-time.Minute)},
+			{Type: "Warning", Reason: "BackOff", LastSeen: now.Add(-2 * time.Minute)},
+			{Type: "Warning", Reason: "BackOff", LastSeen: now.Add(-time.Hour)},
+			{Type: "Normal", Reason: "Pulled", LastSeen: now.Add(-time.Minute)},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		operator string
+		value    float64
+		expected bool
+	}{
+		{">", 6, true},
+		{">", 5, false},
+		{">=", 5, true},
+		{"<", 4, true},
+		{"<=", 6, false},
+		{"==", 5, true},
+		{"!=", 5, false},
+		{"=>", 6, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Compare(tt.value, 5, tt.operator))
+		})
+	}
+}
+
+func TestMetricValue(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected float64
+	}{
+		{"node_cpu_usage", 60},
+		{"kube_pod_container_status_restart_count", 4},
+		{"cpu_usage_percent", 50},
+		{"memory_usage_percent", 50},
+		{"memory_usage_bytes", 256 * 1024 * 1024},
+		{"error_rate", 6},
+		// 2 recent BackOffs and 4 restarts out of 3 recent events and 5
+		// restart samples
+		{"error_rate_percent", 75},
+		// 1 of 3 pods running with few restarts, less 0.5% for each of 2
+		// recent warnings
+		{"availability_percent", 100.0/3 - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			value, ok := MetricValue(tt.query, snapshot(), now)
+			require.True(t, ok)
+			assert.InDelta(t, tt.expected, value, 0.001)
+		})
+	}
+
+	_, ok := MetricValue("http_requests_total", snapshot(), now)
+	assert.False(t, ok)
+}
+
+func TestMetricValue_Capacity(t *testing.T) {
+	metrics := snapshot()
+	value, ok := MetricValue("pending_unschedulable", metrics, now)
+	require.True(t, ok, "capacity queries without capacity signals")
+	assert.Zero(t, value)
+
+	metrics.Capacity = &types.CapacityMetrics{PendingUnschedulable: 3, FailedScheduling: 7}
+	value, _ = MetricValue("pending_unschedulable", metrics, now)
+	assert.Equal(t, 3.0, value)
+	value, _ = MetricValue("failed_scheduling", metrics, now)
+	assert.Equal(t, 7.0, value)
+	value, _ = MetricValue("out_of_capacity", metrics, now)
+	assert.Equal(t, 1.0, value)
+}
+
+func TestOutOfCapacity(t *testing.T) {
+	assert.False(t, OutOfCapacity(nil))
+	assert.False(t, OutOfCapacity(&types.CapacityMetrics{FailedScheduling: 2}), "no pod is pending")
+	assert.True(t, OutOfCapacity(&types.CapacityMetrics{PendingUnschedulable: 1}), "no autoscaler")
+
+	scalingUp := &types.AutoscalerStatus{Health: "Healthy", ScaleUp: "InProgress"}
+	assert.False(t, OutOfCapacity(&types.CapacityMetrics{PendingUnschedulable: 1, Autoscaler: scalingUp}))
+	atMax := &types.AutoscalerStatus{Health: "Healthy", ScaleUp: "NoActivity", NodeGroupsAtMaxSize: []string{"pool-a"}}
+	assert.True(t, OutOfCapacity(&types.CapacityMetrics{PendingUnschedulable: 1, Autoscaler: atMax}))
+	unhealthy := &types.AutoscalerStatus{Health: "Unhealthy", ScaleUp: "InProgress"}
+	assert.True(t, OutOfCapacity(&types.CapacityMetrics{PendingUnschedulable: 1, Autoscaler: unhealthy}))
+}
+
+func TestEvaluateEvents(t *testing.T) {
+	trigger := &v1alpha1.EventTrigger{Type: "Warning", Reason: "BackOff", Count: 2}
+	triggered, reason := EvaluateEvents(trigger, snapshot().Events, now)
+	assert.True(t, triggered)
+	assert.Equal(t, "found 2 matching events (threshold: 2) in last 5m0s", reason)
+
+	trigger.Window = metav1.Duration{Duration: 90 * time.Second}
+	triggered, _ = EvaluateEvents(trigger, snapshot().Events, now)
+	assert.False(t, triggered)
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	triggered, reason := EvaluateCondition(&v1alpha1.ConditionTrigger{Type: "MemoryPressure"}, snapshot())
+	assert.True(t, triggered)
+	assert.Equal(t, "found 1 resources with condition MemoryPressure", reason)
+
+	triggered, _ = EvaluateCondition(&v1alpha1.ConditionTrigger{Type: "CrashLoopBackOff"}, snapshot())
+	assert.True(t, triggered)
+
+	triggered, _ = EvaluateCondition(&v1alpha1.ConditionTrigger{Type: "DiskPressure"}, snapshot())
+	assert.False(t, triggered)
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		trigger   v1alpha1.HealingTrigger
+		triggered bool
+		reason    string
+		err       error
+	}{
+		{
+			name: "metric",
+			trigger: v1alpha1.HealingTrigger{Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+				Query: "restart_count", Threshold: 3, Operator: ">",
+			}},
+			triggered: true,
+			reason:    "query 'restart_count' result 4.00 > 3.00",
+		},
+		{
+			name: "unknown metric",
+			trigger: v1alpha1.HealingTrigger{Type: "metric", MetricTrigger: &v1alpha1.MetricTrigger{
+				Query: "queue_depth", Threshold: 3, Operator: ">",
+			}},
+			reason: "metric evaluation not implemented for query: queue_depth",
+		},
+		{
+			name:      "event",
+			trigger:   v1alpha1.HealingTrigger{Type: "event", EventTrigger: &v1alpha1.EventTrigger{Reason: "BackOff", Count: 1}},
+			triggered: true,
+			reason:    "found 2 matching events",
+		},
+		{
+			name:    "condition",
+			trigger: v1alpha1.HealingTrigger{Type: "condition", ConditionTrigger: &v1alpha1.ConditionTrigger{Type: "DiskPressure"}},
+			reason:  "found 0 resources",
+		},
+		{
+			name:      "schedule",
+			trigger:   v1alpha1.HealingTrigger{Type: "schedule", ScheduleTrigger: &v1alpha1.ScheduleTrigger{Schedule: "0 * * * *"}},
+			triggered: true,
+			reason:    "scheduled run at 2026-10-16T12:00:00Z",
+		},
+		{
+			name:    "log needs live data",
+			trigger: v1alpha1.HealingTrigger{Type: "log", LogTrigger: &v1alpha1.LogTrigger{Pattern: "panic"}},
+			err:     ErrUnsupported,
+		},
+		{
+			name:    "missing configuration",
+			trigger: v1alpha1.HealingTrigger{Type: "metric"},
+			err:     errors.New("metric trigger configuration missing"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triggered, reason, err := Evaluate(&tt.trigger, snapshot(), now.Add(-time.Minute), now)
+			if tt.err != nil {
+				require.Error(t, err)
+				if errors.Is(tt.err, ErrUnsupported) {
+					assert.ErrorIs(t, err, ErrUnsupported)
+				} else {
+					assert.EqualError(t, err, tt.err.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.triggered, triggered)
+			assert.Contains(t, reason, tt.reason)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Package types contains the stable result types and extension interfaces
+// for building executors and analyzers against KubeSkippy. The operator
+// converts between these types and its internal representation, so fields
+// are only ever added here, never renamed or removed.
+package types
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+)
+
+// ClusterMetrics is a snapshot of cluster state used for trigger evaluation
+// and analysis
+type ClusterMetrics struct {
+	// Timestamp is when the metrics were collected
+	Timestamp time.Time `json:"timestamp"`
+
+	// Nodes contains per-node metrics
+	Nodes []NodeMetrics `json:"nodes,omitempty"`
+
+	// Pods contains per-pod metrics
+	Pods []PodMetrics `json:"pods,omitempty"`
+
+	// Events contains recent Kubernetes events
+	Events []EventMetrics `json:"events,omitempty"`
+
+	// Custom contains values of custom metric queries keyed by query
+	Custom map[string]float64 `json:"custom,omitempty"`
+
+	// LogMatches are log lines matched by log-pattern triggers
+	LogMatches []LogMatch `json:"logMatches,omitempty"`
+
+	// Patterns are the patterns found by the enabled detectors
+	Patterns []Pattern `json:"patterns,omitempty"`
+
+	// Capacity tells a cluster out of capacity apart from failing
+	// workloads; nil when capacity signals are not collected
+	Capacity *CapacityMetrics `json:"capacity,omitempty"`
+}
+
+// NodeMetrics contains metrics for a node
+type NodeMetrics struct {
+	Name        string            `json:"name"`
+	CPUUsage    float64           `json:"cpuUsage"`
+	MemoryUsage float64           `json:"memoryUsage"`
+	DiskUsage   float64           `json:"diskUsage"`
+	PodCount    int32             `json:"podCount"`
+	Conditions  []string          `json:"conditions,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// PodMetrics contains metrics for a pod
+type PodMetrics struct {
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	CPUUsage     float64           `json:"cpuUsage"`
+	MemoryUsage  float64           `json:"memoryUsage"`
+	RestartCount int32             `json:"restartCount"`
+	Status       string            `json:"status"`
+	Conditions   []string          `json:"conditions,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+
+	// InitRestartCount is the total restart count of the init containers
+	InitRestartCount int32 `json:"initRestartCount,omitempty"`
+
+	// InitFailures lists the failing init containers as "container: reason"
+	InitFailures []string `json:"initFailures,omitempty"`
+
+	// EphemeralContainers lists the running ephemeral containers
+	EphemeralContainers []string `json:"ephemeralContainers,omitempty"`
+}
+
+// EventMetrics describes a Kubernetes event
+type EventMetrics struct {
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Object    string    `json:"object"`
+}
+
+// CapacityMetrics are the scheduling and autoscaling signals of the cluster
+type CapacityMetrics struct {
+	// PendingUnschedulable is the number of pending pods the scheduler
+	// found no node for
+	PendingUnschedulable int `json:"pendingUnschedulable"`
+
+	// UnschedulablePods are the first of these pods with the scheduler's
+	// message
+	UnschedulablePods []UnschedulablePod `json:"unschedulablePods,omitempty"`
+
+	// FailedScheduling is the number of FailedScheduling events seen in the
+	// last five minutes
+	FailedScheduling int `json:"failedScheduling"`
+
+	// Autoscaler is the cluster autoscaler's status, nil without one
+	Autoscaler *AutoscalerStatus `json:"autoscaler,omitempty"`
+}
+
+// UnschedulablePod is a pending pod the scheduler found no node for
+type UnschedulablePod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Message   string `json:"message,omitempty"`
+}
+
+// AutoscalerStatus is the status the cluster autoscaler reports in its
+// status ConfigMap
+type AutoscalerStatus struct {
+	// Health is the cluster-wide health, e.g. "Healthy" or "Unhealthy"
+	Health string `json:"health,omitempty"`
+
+	// ScaleUp is the cluster-wide scale-up status, e.g. "InProgress",
+	// "NoActivity" or "Backoff"
+	ScaleUp string `json:"scaleUp,omitempty"`
+
+	// ScaleDown is the cluster-wide scale-down status
+	ScaleDown string `json:"scaleDown,omitempty"`
+
+	// NodeGroupsAtMaxSize lists the node groups that cannot grow any further
+	NodeGroupsAtMaxSize []string `json:"nodeGroupsAtMaxSize,omitempty"`
+
+	// NodeGroupsInBackoff lists the node groups whose scale-ups failed and
+	// are backing off
+	NodeGroupsInBackoff []string `json:"nodeGroupsInBackoff,omitempty"`
+}
+
+// LogMatch is a pod log line matched by a log-pattern trigger
+type LogMatch struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container,omitempty"`
+	Pattern   string `json:"pattern"`
+	Line      string `json:"line"`
+}
+
+// ValidationResult is the outcome of validating an action
+type ValidationResult struct {
+	// Valid reports whether the action may proceed
+	Valid bool `json:"valid"`
+
+	// Reason explains why the action is invalid
+	Reason string `json:"reason,omitempty"`
+
+	// Warnings that do not block the action
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Suggestions for making the action safer
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ActionResult is the outcome of executing or simulating an action
+type ActionResult struct {
+	// Success reports whether the action completed
+	Success bool `json:"success"`
+
+	// Message is a human readable summary
+	Message string `json:"message,omitempty"`
+
+	// Error describes the failure, if any
+	Error string `json:"error,omitempty"`
+
+	// Changes made to resources
+	Changes []v1alpha1.ResourceChange `json:"changes,omitempty"`
+
+	// Metrics recorded during execution
+	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// StartTime is when execution began
+	StartTime time.Time `json:"startTime"`
+
+	// EndTime is when execution finished
+	EndTime time.Time `json:"endTime"`
+}
+
+// Analysis is the result of analyzing cluster state
+type Analysis struct {
+	// Summary of the cluster state
+	Summary string `json:"summary"`
+
+	// Issues identified by the analyzer
+	Issues []AnalysisIssue `json:"issues,omitempty"`
+
+	// Recommendations suggested by the analyzer
+	Recommendations []Recommendation `json:"recommendations,omitempty"`
+
+	// Confidence of the analysis between 0 and 1
+	Confidence float64 `json:"confidence"`
+
+	// Model identifies the analyzer or model that produced the analysis
+	Model string `json:"model,omitempty"`
+}
+
+// AnalysisIssue is an issue identified by an analyzer
+type AnalysisIssue struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Impact      string `json:"impact,omitempty"`
+	RootCause   string `json:"rootCause,omitempty"`
+}
+
+// Recommendation is an action suggested by an analyzer
+type Recommendation struct {
+	ID         string  `json:"id"`
+	Priority   int     `json:"priority"`
+	Action     string  `json:"action"`
+	Target     string  `json:"target"`
+	Reason     string  `json:"reason"`
+	Risk       string  `json:"risk,omitempty"`
+	Confidence float64 `json:"confidence"`
+
+	// Patches proposed for patch actions
+	Patches []v1alpha1.PatchOperation `json:"patches,omitempty"`
+}
+
+// ActionExecutor implements a healing action type
+type ActionExecutor interface {
+	// Execute performs the action
+	Execute(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*ActionResult, error)
+
+	// Validate checks if the action can be executed
+	Validate(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) error
+
+	// DryRun simulates the action
+	DryRun(ctx context.Context, target client.Object, action *v1alpha1.HealingActionTemplate) (*ActionResult, error)
+}
+
+// Analyzer analyzes cluster state and recommends actions
+type Analyzer interface {
+	// Analyze returns an analysis of the given metrics
+	Analyze(ctx context.Context, metrics *ClusterMetrics) (*Analysis, error)
+}
+
+// TriggerEvaluator implements a custom trigger type. Evaluators are
+// registered by name and referenced from policies through plugin triggers.
+type TriggerEvaluator interface {
+	// Evaluate returns whether the trigger fired and why. config is the
+	// plugin trigger's configuration from the policy.
+	Evaluate(ctx context.Context, config map[string]string, metrics *ClusterMetrics) (bool, string, error)
+}
+
+// Pattern is a pattern found by a pattern detector
+type Pattern struct {
+	// Name of the pattern, e.g. "memory-leak"
+	Name string `json:"name"`
+
+	// Target the pattern was found on as namespace/name, or name for
+	// cluster-scoped targets; empty for cluster-wide patterns
+	Target string `json:"target,omitempty"`
+
+	// Description of the pattern
+	Description string `json:"description"`
+
+	// Confidence of the detection between 0 and 1
+	Confidence float64 `json:"confidence"`
+
+	// Detector is the name of the detector that found the pattern, set by
+	// the operator
+	Detector string `json:"detector,omitempty"`
+}
+
+// PatternDetector finds patterns in cluster state. A plugin trigger
+// referencing a detector fires when it finds any pattern.
+type PatternDetector interface {
+	// Detect returns the patterns found in the metrics. config is the
+	// plugin trigger's configuration from the policy.
+	Detect(ctx context.Context, config map[string]string, metrics *ClusterMetrics) ([]Pattern, error)
+}
+
+// SeriesPoint is a sample of a time series
+type SeriesPoint struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Value     float64           `json:"value"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// SeriesStore gives detectors read access to the time series kept by the
+// operator. Pod series are keyed "pod_cpu_<namespace>_<pod>",
+// "pod_memory_<namespace>_<pod>" and "pod_restarts_<namespace>_<pod>";
+// "error_count" counts recent warning events.
+type SeriesStore interface {
+	// Keys returns the sorted keys of the series starting with prefix
+	Keys(prefix string) []string
+
+	// Points returns the points of a series, oldest first. The points must
+	// not be modified.
+	Points(key string) []SeriesPoint
+}
+
+// Detector finds patterns in the time series kept by the operator.
+// Detectors are registered by name and enabled in the operator
+// configuration; the patterns they find can fire metric triggers with
+// "pattern:<name>" queries and are included in the AI analysis.
+type Detector interface {
+	// Detect returns the patterns found in the time series
+	Detect(ctx context.Context, series SeriesStore) ([]Pattern, error)
+}
@@ -0,0 +1,225 @@
+package integration_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/ai"
+	"github.com/kubeskippy/kubeskippy/internal/ai/aitest"
+	"github.com/kubeskippy/kubeskippy/internal/controller"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/types"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// AI metrics register with the default registry, so they are created once
+var initAIMetrics sync.Once
+
+// allowAllSafety approves every action
+type allowAllSafety struct{}
+
+func (allowAllSafety) ValidateAction(ctx context.Context, action *v1alpha1.HealingAction) (*types.ValidationResult, error) {
+	return &types.ValidationResult{Valid: true}, nil
+}
+
+func (allowAllSafety) CheckRateLimit(ctx context.Context, policy *v1alpha1.HealingPolicy) (bool, error) {
+	return true, nil
+}
+
+func (allowAllSafety) IsProtectedResource(resource runtime.Object) (bool, string) {
+	return false, ""
+}
+
+func (allowAllSafety) RecordAction(ctx context.Context, action *v1alpha1.HealingAction, result *types.ActionResult) {
+}
+
+func TestAIFilteringFlow(t *testing.T) {
+	initAIMetrics.Do(func() {
+		metrics.GlobalAIMetrics = metrics.NewAIMetrics()
+	})
+
+	tests := []struct {
+		name           string
+		policy         string
+		analysis       *types.AIAnalysis
+		expectedTypes  []string
+		expectAIDriven bool
+	}{
+		{
+			name:   "confident recommendation selects matching action",
+			policy: "ai-confident",
+			analysis: &types.AIAnalysis{
+				Summary:    "Pods of web are crash looping after a config change",
+				Confidence: 0.9,
+				Recommendations: []types.AIRecommendation{{
+					Action:     "rolling_restart",
+					Target:     "Deployment/ai-test/web",
+					Reason:     "Restart picks up the corrected config",
+					Risk:       "Low",
+					Confidence: 0.92,
+					Reasoning:  types.DecisionReasoning{DecisionLogic: "Restart is the least disruptive fix"},
+				}},
+			},
+			expectedTypes:  []string{"restart"},
+			expectAIDriven: true,
+		},
+		{
+			name:   "low confidence falls back to traditional actions",
+			policy: "ai-unsure",
+			analysis: &types.AIAnalysis{
+				Summary:    "Unclear root cause",
+				Confidence: 0.5,
+				Recommendations: []types.AIRecommendation{{
+					Action:     "scale_up",
+					Target:     "Deployment/ai-test/web",
+					Confidence: 0.6,
+				}},
+			},
+			expectedTypes: []string{"restart", "scale"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := aitest.NewOllamaServer("llama2", tt.analysis)
+			defer server.Close()
+
+			cfg := config.NewDefaultConfig()
+			cfg.AI.Provider = "ollama"
+			cfg.AI.Endpoint = server.URL
+			cfg.AI.Model = "llama2"
+
+			analyzer, err := ai.NewAnalyzer(cfg.AI)
+			require.NoError(t, err)
+
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+			_ = corev1.AddToScheme(scheme)
+			_ = appsv1.AddToScheme(scheme)
+
+			deployment := &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "web",
+					Namespace: "ai-test",
+					Labels:    map[string]string{"app": "web"},
+				},
+			}
+			policy := &v1alpha1.HealingPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        tt.policy,
+					Namespace:   "ai-test",
+					Annotations: map[string]string{"kubeskippy.io/ai-enabled": "true"},
+				},
+				Spec: v1alpha1.HealingPolicySpec{
+					Mode: "automatic",
+					Selector: v1alpha1.ResourceSelector{
+						Namespaces:    []string{"ai-test"},
+						LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+						Resources:     []v1alpha1.ResourceFilter{{APIVersion: "apps/v1", Kind: "Deployment"}},
+					},
+					Triggers: []v1alpha1.HealingTrigger{{
+						Name: "ai-confidence",
+						Type: "metric",
+						MetricTrigger: &v1alpha1.MetricTrigger{
+							Query:     "ai_confidence_score",
+							Threshold: 0.8,
+							Operator:  ">",
+						},
+					}},
+					Actions: []v1alpha1.HealingActionTemplate{
+						{Name: "restart-web", Type: "restart", Priority: 10, RestartAction: &v1alpha1.RestartAction{Strategy: "rolling"}},
+						{Name: "scale-web", Type: "scale", Priority: 5, ScaleAction: &v1alpha1.ScaleAction{Direction: "up", Replicas: 1}},
+					},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(deployment, policy).
+				WithStatusSubresource(&v1alpha1.HealingPolicy{}).
+				Build()
+
+			r := &controller.HealingPolicyReconciler{
+				Client:           fakeClient,
+				Scheme:           scheme,
+				Config:           cfg,
+				MetricsCollector: metrics.NewAdvancedCollector(metrics.NewCollector(fakeClient, fakeclientset.NewSimpleClientset(), nil)),
+				SafetyController: allowAllSafety{},
+				AIAnalyzer:       analyzer,
+			}
+
+			req := ctrl.Request{NamespacedName: ktypes.NamespacedName{Name: tt.policy, Namespace: "ai-test"}}
+			// The first reconcile adds the finalizer
+			_, err = r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			_, err = r.Reconcile(ctx, req)
+			require.NoError(t, err)
+
+			require.NotEmpty(t, server.Prompts(), "AI analysis should have been requested")
+			assert.Contains(t, server.Prompts()[0], "ai-confidence")
+
+			actions := &v1alpha1.HealingActionList{}
+			require.NoError(t, fakeClient.List(ctx, actions, client.InNamespace("ai-test"),
+				client.MatchingLabels{controller.LabelPolicyName: tt.policy}))
+
+			var actionTypes []string
+			for _, action := range actions.Items {
+				actionTypes = append(actionTypes, action.Spec.Action.Type)
+				if tt.expectAIDriven {
+					assert.Equal(t, "true", action.Labels[controller.LabelAIDriven])
+				} else {
+					assert.NotContains(t, action.Labels, controller.LabelAIDriven)
+				}
+			}
+			assert.ElementsMatch(t, tt.expectedTypes, actionTypes)
+
+			triggerType := "traditional"
+			if tt.expectAIDriven {
+				triggerType = "ai"
+			}
+			assert.Equal(t, float64(len(tt.expectedTypes)), healingActionsCreated(t, tt.policy, triggerType))
+		})
+	}
+}
+
+// healingActionsCreated sums kubeskippy_healing_actions_total for a policy
+func healingActionsCreated(t *testing.T, policy, triggerType string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	var total float64
+	for _, family := range families {
+		if family.GetName() != "kubeskippy_healing_actions_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["policy"] == policy && labels["trigger_type"] == triggerType &&
+				labels["status"] == "created" && labels["ai_driven"] == strconv.FormatBool(triggerType == "ai") {
+				total += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return total
+}
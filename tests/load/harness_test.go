@@ -0,0 +1,373 @@
+package load_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/kubeskippy/kubeskippy/api/v1alpha1"
+	"github.com/kubeskippy/kubeskippy/internal/controller"
+	"github.com/kubeskippy/kubeskippy/internal/metrics"
+	"github.com/kubeskippy/kubeskippy/internal/safety"
+	"github.com/kubeskippy/kubeskippy/pkg/config"
+)
+
+// Backends the harness runs against
+const (
+	backendEnvtest = "envtest"
+	backendFake    = "fake"
+)
+
+// namespaces the synthetic pods and policies are spread across
+const namespaces = 5
+
+// apps is the number of distinct app labels, each selected by a policy
+const apps = 10
+
+// scenario is a load shape
+type scenario struct {
+	policies int
+	pods     int
+}
+
+func (s scenario) String() string {
+	return fmt.Sprintf("policies=%d,pods=%d", s.policies, s.pods)
+}
+
+// threshold bounds a scenario's measurements; zero fields are not checked
+type threshold struct {
+	MaxP95Millis              float64 `json:"maxP95Millis,omitempty"`
+	MaxAPICallsPerReconcile   float64 `json:"maxAPICallsPerReconcile,omitempty"`
+	MaxAllocBytesPerReconcile float64 `json:"maxAllocBytesPerReconcile,omitempty"`
+}
+
+// loadThresholds reads the regression thresholds of a backend by scenario
+// from LOAD_THRESHOLDS, or thresholds.json next to the tests
+func loadThresholds(t testing.TB, backend string) map[string]threshold {
+	path := os.Getenv("LOAD_THRESHOLDS")
+	if path == "" {
+		path = "thresholds.json"
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var thresholds map[string]map[string]threshold
+	require.NoError(t, json.Unmarshal(data, &thresholds))
+	return thresholds[backend]
+}
+
+// measurement of a round of reconciles
+type measurement struct {
+	reconciles int
+	latencies  []time.Duration
+	apiCalls   int64
+	allocBytes uint64
+	heapInuse  uint64
+}
+
+// percentile returns the p-th percentile latency
+func (m *measurement) percentile(p float64) time.Duration {
+	if len(m.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), m.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+func (m *measurement) apiCallsPerReconcile() float64 {
+	return float64(m.apiCalls) / float64(m.reconciles)
+}
+
+func (m *measurement) allocBytesPerReconcile() float64 {
+	return float64(m.allocBytes) / float64(m.reconciles)
+}
+
+// check fails t for every threshold the measurement exceeds
+func (m *measurement) check(t testing.TB, limits threshold) {
+	p95 := float64(m.percentile(0.95)) / float64(time.Millisecond)
+	if limits.MaxP95Millis > 0 && p95 > limits.MaxP95Millis {
+		t.Errorf("p95 reconcile latency %.1fms exceeds %.1fms", p95, limits.MaxP95Millis)
+	}
+	if limits.MaxAPICallsPerReconcile > 0 && m.apiCallsPerReconcile() > limits.MaxAPICallsPerReconcile {
+		t.Errorf("%.1f API calls per reconcile exceed %.1f", m.apiCallsPerReconcile(), limits.MaxAPICallsPerReconcile)
+	}
+	if limits.MaxAllocBytesPerReconcile > 0 && m.allocBytesPerReconcile() > limits.MaxAllocBytesPerReconcile {
+		t.Errorf("%.0f bytes allocated per reconcile exceed %.0f", m.allocBytesPerReconcile(), limits.MaxAllocBytesPerReconcile)
+	}
+}
+
+// apiCounter counts the API calls of the clients under test by verb
+type apiCounter struct {
+	mu    sync.Mutex
+	calls map[string]int64
+}
+
+func newAPICounter() *apiCounter {
+	return &apiCounter{calls: make(map[string]int64)}
+}
+
+func (c *apiCounter) add(verb string) {
+	c.mu.Lock()
+	c.calls[verb]++
+	c.mu.Unlock()
+}
+
+// total returns the number of calls so far
+func (c *apiCounter) total() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total int64
+	for _, n := range c.calls {
+		total += n
+	}
+	return total
+}
+
+// byVerb returns a copy of the calls by verb
+func (c *apiCounter) byVerb() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int64, len(c.calls))
+	for verb, n := range c.calls {
+		out[verb] = n
+	}
+	return out
+}
+
+// countingFuncs count the calls of a controller-runtime client
+func (c *apiCounter) countingFuncs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			c.add("get")
+			return cl.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			c.add("list")
+			return cl.List(ctx, list, opts...)
+		},
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			c.add("create")
+			return cl.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			c.add("update")
+			return cl.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			c.add("patch")
+			return cl.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			c.add("delete")
+			return cl.Delete(ctx, obj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, cl client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			c.add("update/" + subResource)
+			return cl.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, cl client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			c.add("patch/" + subResource)
+			return cl.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	}
+}
+
+// countingTransport counts the requests of a clientset
+type countingTransport struct {
+	counter *apiCounter
+	next    http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.counter.add("clientset/" + req.Method)
+	return t.next.RoundTrip(req)
+}
+
+// harness runs the policy reconciler against synthetic policies and pods
+type harness struct {
+	backend    string
+	scheme     *k8sruntime.Scheme
+	client     client.Client
+	reconciler *controller.HealingPolicyReconciler
+	counter    *apiCounter
+	policies   []client.ObjectKey
+}
+
+// newHarness starts the backend: envtest when KUBEBUILDER_ASSETS is set,
+// a fake client otherwise
+func newHarness(t testing.TB) *harness {
+	h := &harness{scheme: k8sruntime.NewScheme(), counter: newAPICounter()}
+	require.NoError(t, clientgoscheme.AddToScheme(h.scheme))
+	require.NoError(t, v1alpha1.AddToScheme(h.scheme))
+
+	var base client.WithWatch
+	var clientset kubernetes.Interface
+	if os.Getenv("KUBEBUILDER_ASSETS") != "" {
+		h.backend = backendEnvtest
+		env := &envtest.Environment{
+			CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+			ErrorIfCRDPathMissing: true,
+		}
+		cfg, err := env.Start()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = env.Stop() })
+
+		base, err = client.NewWithWatch(cfg, client.Options{Scheme: h.scheme})
+		require.NoError(t, err)
+		counted := rest.CopyConfig(cfg)
+		counted.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &countingTransport{counter: h.counter, next: rt}
+		})
+		clientset, err = kubernetes.NewForConfig(counted)
+		require.NoError(t, err)
+	} else {
+		h.backend = backendFake
+		base = fake.NewClientBuilder().WithScheme(h.scheme).
+			WithStatusSubresource(&v1alpha1.HealingPolicy{}, &v1alpha1.HealingAction{}, &corev1.Pod{}).
+			Build()
+		fakeset := fakeclientset.NewSimpleClientset()
+		fakeset.PrependReactor("*", "*", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+			h.counter.add("clientset/" + action.GetVerb())
+			return false, nil, nil
+		})
+		clientset = fakeset
+	}
+	h.client = interceptor.NewClient(base, h.counter.countingFuncs())
+
+	cfg := config.NewDefaultConfig()
+	h.reconciler = &controller.HealingPolicyReconciler{
+		Client:           h.client,
+		Scheme:           h.scheme,
+		Config:           cfg,
+		MetricsCollector: metrics.NewCollector(h.client, clientset, nil),
+		SafetyController: safety.NewController(h.client, cfg.Safety, safety.NewInMemoryActionStore(), nil),
+	}
+	return h
+}
+
+// seed creates the namespaces, pods and policies of a scenario. Every
+// tenth pod restarts often enough to fire the policies' metric trigger.
+func (h *harness) seed(t testing.TB, s scenario) {
+	ctx := context.Background()
+	for n := 0; n < namespaces; n++ {
+		require.NoError(t, h.client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName(n)}}))
+	}
+
+	for i := 0; i < s.pods; i++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("pod-%d", i),
+				Namespace: namespaceName(i % namespaces),
+				Labels:    map[string]string{"app": fmt.Sprintf("app-%d", i%apps)},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:latest"}}},
+		}
+		require.NoError(t, h.client.Create(ctx, pod))
+		restarts := int32(0)
+		if i%10 == 0 {
+			restarts = 10
+		}
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "app", Ready: true, RestartCount: restarts,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now()}},
+			}},
+		}
+		require.NoError(t, h.client.Status().Update(ctx, pod))
+	}
+
+	for i := 0; i < s.policies; i++ {
+		policy := &v1alpha1.HealingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%d", i), Namespace: namespaceName(i % namespaces)},
+			Spec: v1alpha1.HealingPolicySpec{
+				Mode: "dryrun",
+				Selector: v1alpha1.ResourceSelector{
+					Namespaces:    []string{namespaceName(i % namespaces)},
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": fmt.Sprintf("app-%d", i%apps)}},
+					Resources:     []v1alpha1.ResourceFilter{{APIVersion: "v1", Kind: "Pod"}},
+				},
+				Triggers: []v1alpha1.HealingTrigger{
+					{
+						Name: "restarts", Type: "metric",
+						MetricTrigger:  &v1alpha1.MetricTrigger{Query: "restart_count", Threshold: 5, Operator: ">"},
+						CooldownPeriod: metav1.Duration{Duration: 5 * time.Minute},
+					},
+					{
+						Name: "backoff", Type: "event",
+						EventTrigger:   &v1alpha1.EventTrigger{Reason: "BackOff", Type: "Warning", Count: 3, Window: metav1.Duration{Duration: 5 * time.Minute}},
+						CooldownPeriod: metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+				Actions: []v1alpha1.HealingActionTemplate{
+					{Name: "restart", Type: "restart", Priority: 10, RestartAction: &v1alpha1.RestartAction{Strategy: "rolling", MaxConcurrent: 1}},
+				},
+				SafetyRules: v1alpha1.SafetyRules{MaxActionsPerHour: 10},
+			},
+		}
+		require.NoError(t, h.client.Create(ctx, policy))
+		h.policies = append(h.policies, client.ObjectKeyFromObject(policy))
+	}
+
+	// The first reconcile adds the finalizer, the second creates the
+	// first actions; both are left out of the measurements
+	for round := 0; round < 2; round++ {
+		for _, key := range h.policies {
+			_, err := h.reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			require.NoError(t, err)
+		}
+	}
+}
+
+// measure reconciles every policy once and records the latencies, API
+// calls and allocations
+func (h *harness) measure(t testing.TB) *measurement {
+	ctx := context.Background()
+	m := &measurement{reconciles: len(h.policies)}
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	calls := h.counter.total()
+
+	for _, key := range h.policies {
+		start := time.Now()
+		_, err := h.reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		m.latencies = append(m.latencies, time.Since(start))
+		require.NoError(t, err)
+	}
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	m.apiCalls = h.counter.total() - calls
+	m.allocBytes = after.TotalAlloc - before.TotalAlloc
+	m.heapInuse = after.HeapInuse
+	return m
+}
+
+func namespaceName(n int) string {
+	return fmt.Sprintf("load-%d", n)
+}
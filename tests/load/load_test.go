@@ -0,0 +1,83 @@
+package load_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func init() {
+	// Reconciler logs would dominate the measurements
+	ctrl.SetLogger(logr.Discard())
+}
+
+// scenarios benchmarked by default; LOAD_POLICIES and LOAD_PODS replace them
+// with a single custom scenario
+func scenarios(t testing.TB) []scenario {
+	policies, pods := os.Getenv("LOAD_POLICIES"), os.Getenv("LOAD_PODS")
+	if policies == "" && pods == "" {
+		return []scenario{{policies: 10, pods: 100}, {policies: 50, pods: 500}, {policies: 200, pods: 2000}}
+	}
+	custom := scenario{policies: 10, pods: 100}
+	var err error
+	if policies != "" {
+		custom.policies, err = strconv.Atoi(policies)
+		require.NoError(t, err, "LOAD_POLICIES")
+	}
+	if pods != "" {
+		custom.pods, err = strconv.Atoi(pods)
+		require.NoError(t, err, "LOAD_PODS")
+	}
+	return []scenario{custom}
+}
+
+// BenchmarkPolicyReconcile reconciles every policy of a scenario per
+// iteration and fails when the thresholds of the scenario are exceeded
+func BenchmarkPolicyReconcile(b *testing.B) {
+	for _, s := range scenarios(b) {
+		b.Run(s.String(), func(b *testing.B) {
+			h := newHarness(b)
+			h.seed(b, s)
+			thresholds := loadThresholds(b, h.backend)
+
+			total := &measurement{}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m := h.measure(b)
+				total.reconciles += m.reconciles
+				total.latencies = append(total.latencies, m.latencies...)
+				total.apiCalls += m.apiCalls
+				total.allocBytes += m.allocBytes
+				total.heapInuse = m.heapInuse
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64(total.percentile(0.50))/float64(time.Millisecond), "p50-ms")
+			b.ReportMetric(float64(total.percentile(0.95))/float64(time.Millisecond), "p95-ms")
+			b.ReportMetric(float64(total.percentile(0.99))/float64(time.Millisecond), "p99-ms")
+			b.ReportMetric(total.apiCallsPerReconcile(), "api-calls/reconcile")
+			b.ReportMetric(total.allocBytesPerReconcile(), "alloc-B/reconcile")
+			b.ReportMetric(float64(total.heapInuse)/(1<<20), "heap-MB")
+			b.Logf("%s backend, API calls by verb: %v", h.backend, h.counter.byVerb())
+			total.check(b, thresholds[s.String()])
+		})
+	}
+}
+
+// TestAPICallBudget guards the API calls per reconcile of the smallest
+// scenario, which unlike latency and memory do not depend on the machine
+func TestAPICallBudget(t *testing.T) {
+	s := scenario{policies: 10, pods: 100}
+	h := newHarness(t)
+	h.seed(t, s)
+
+	m := h.measure(t)
+	limits := loadThresholds(t, h.backend)[s.String()]
+	m.check(t, threshold{MaxAPICallsPerReconcile: limits.MaxAPICallsPerReconcile})
+	t.Logf("%.1f API calls per reconcile: %v", m.apiCallsPerReconcile(), h.counter.byVerb())
+}